@@ -10,8 +10,10 @@ import (
 	"syscall"
 	"time"
 
+	"telecom-platform/internal/audit"
 	"telecom-platform/internal/auth"
 	"telecom-platform/internal/config"
+	"telecom-platform/internal/httpapi"
 	"telecom-platform/pkg/logger"
 	"telecom-platform/pkg/utils"
 
@@ -59,7 +61,29 @@ func main() {
 
 	// Gin router
 	r := gin.New()
+	// Only honor X-Forwarded-For from configured proxies; nil trusts none (Gin's
+	// default trusts every peer, which lets callers spoof their IP).
+	if err := r.SetTrustedProxies(cfg.App.TrustedProxies); err != nil {
+		log.Error("trusted proxies invalid", "err", err)
+		os.Exit(1)
+	}
+	ipResolver, err := utils.NewClientIPResolver(cfg.App.TrustedProxies)
+	if err != nil {
+		log.Error("trusted proxies invalid", "err", err)
+		os.Exit(1)
+	}
+	// Audit events go to the audit_events table (see audit.PostgresRepo).
+	auditSvc := audit.NewService(audit.PostgresRepo{DB: db})
+
+	// Optional source-IP allowlist for /v1/admin and /v1/noc (ADMIN_ALLOWED_CIDRS);
+	// denials are audited.
+	adminIPs, err := utils.NewIPAllowlist(cfg.App.AdminAllowedCIDRs)
+	if err != nil {
+		log.Error("admin allowlist invalid", "err", err)
+		os.Exit(1)
+	}
 	r.Use(gin.Recovery())
+	r.Use(httpapi.ClientIPMiddleware(ipResolver))
 	r.Use(logger.Middleware(log))
 
 	// Attach shared deps to context (no globals)
@@ -69,10 +93,8 @@ func main() {
 		c.Next()
 	})
 
-	// Route groups
-	registerPublicRoutes(r) // webhooks, health
-	registerAuthRoutes(r, authManager)
-	registerProtectedRoutes(r, auth.RequireAccessToken(authManager))
+	// Route groups (public webhooks and health, then the authenticated /v1 API)
+	registerRoutes(r, rdb, auth.RequireAccessToken(authManager), httpapi.RequireAllowedIP(adminIPs, auditSvc))
 
 	srv := &http.Server{
 		Addr:              cfg.HTTPAddr(),
@@ -83,9 +105,78 @@ func main() {
 		IdleTimeout:       60 * time.Second,
 	}
 
+	// Native TLS for deployments without a fronting proxy (TLS_MODE=files|acme).
+	if cfg.TLS.Enabled() {
+		tlsCfg := utils.TLSServerConfig{
+			MinVersion:                cfg.TLS.MinVersion,
+			CipherPolicy:              cfg.TLS.CipherPolicy,
+			HTTP2Disabled:             cfg.TLS.HTTP2Disabled,
+			HTTP2MaxConcurrentStreams: cfg.TLS.HTTP2MaxConcurrentStreams,
+		}
+		if cfg.TLS.Mode == "acme" {
+			tlsCfg.ACMEDomains = cfg.TLS.ACMEDomains
+			tlsCfg.ACMECacheDir = cfg.TLS.ACMECacheDir
+			tlsCfg.ACMEEmail = cfg.TLS.ACMEEmail
+		} else {
+			tlsCfg.CertFile = cfg.TLS.CertFile
+			tlsCfg.KeyFile = cfg.TLS.KeyFile
+		}
+		reloader, err := utils.ConfigureTLSServer(srv, tlsCfg)
+		if err != nil {
+			log.Error("tls init failed", "err", err)
+			os.Exit(1)
+		}
+		if reloader != nil {
+			go reloadOnHUP(rootCtx, log, reloader, "api")
+		}
+	}
+
+	// Internal mTLS listener for service callers (INTERNAL_ADDR); see auth.RequireClientCert.
+	var internalSrv *http.Server
+	if cfg.Internal.Enabled() {
+		ir := gin.New()
+		ir.Use(gin.Recovery())
+		ir.Use(logger.Middleware(log))
+		registerInternalRoutes(ir, auth.RequireClientCert(cfg.Internal.Identities))
+
+		internalSrv = &http.Server{
+			Addr:              cfg.Internal.Addr,
+			Handler:           ir,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+		}
+		reloader, err := utils.ConfigureMTLSServer(internalSrv, utils.TLSServerConfig{
+			CertFile:     cfg.Internal.CertFile,
+			KeyFile:      cfg.Internal.KeyFile,
+			MinVersion:   cfg.TLS.MinVersion,
+			CipherPolicy: cfg.TLS.CipherPolicy,
+		}, cfg.Internal.ClientCAFile)
+		if err != nil {
+			log.Error("internal mtls init failed", "err", err)
+			os.Exit(1)
+		}
+		go reloadOnHUP(rootCtx, log, reloader, "internal")
+
+		go func() {
+			log.Info("internal listener started", "addr", internalSrv.Addr)
+			if err := internalSrv.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Error("internal server failed", "err", err)
+				stop()
+			}
+		}()
+	}
+
 	go func() {
-		log.Info("api listening", "addr", srv.Addr, "env", cfg.App.Env)
-		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Info("api listening", "addr", srv.Addr, "env", cfg.App.Env, "tls", cfg.TLS.Mode)
+		var err error
+		if cfg.TLS.Enabled() {
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Error("http server failed", "err", err)
 			stop()
 		}
@@ -100,6 +191,30 @@ func main() {
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Error("http shutdown failed", "err", err)
 	}
+	if internalSrv != nil {
+		if err := internalSrv.Shutdown(shutdownCtx); err != nil {
+			log.Error("internal shutdown failed", "err", err)
+		}
+	}
 
 	_ = logger.ShutdownFlush(shutdownCtx, 2*time.Second)
 }
+
+// reloadOnHUP re-reads the listener's certificate pair on SIGHUP until ctx ends.
+func reloadOnHUP(ctx context.Context, log *slog.Logger, r *utils.CertReloader, listener string) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if err := r.Reload(); err != nil {
+				log.Error("tls cert reload failed; keeping previous certificate", "listener", listener, "err", err)
+				continue
+			}
+			log.Info("tls cert reloaded", "listener", listener)
+		}
+	}
+}
@@ -2,32 +2,63 @@ package main
 
 import (
 	"errors"
+	"time"
+
 	"telecom-platform/internal/auth"
 	"telecom-platform/internal/httpapi"
+	"telecom-platform/internal/ratelimit"
 	"telecom-platform/internal/rbac"
+	"telecom-platform/internal/replay"
 	"telecom-platform/internal/routing"
+	"telecom-platform/internal/scim"
 	"telecom-platform/internal/telephony"
 	"telecom-platform/internal/wallet"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 )
 
 // registerRoutes wires HTTP routes to handlers.
 // Keep this file free of business logic. Handlers should delegate to internal modules.
-func registerRoutes(r *gin.Engine, authMW gin.HandlerFunc) {
+// rdb backs the public routes' shared rate limit counters and replay nonces.
+// privilegedNetMW (optional) restricts /v1/admin and /v1/noc by source IP.
+func registerRoutes(r *gin.Engine, rdb *redis.Client, authMW, privilegedNetMW gin.HandlerFunc) {
+	// Body size caps: JSON by default, larger streamed uploads on file routes.
+	r.Use(httpapi.BodyLimits(httpapi.MaxJSONBodyBytes, map[string]int64{
+		"POST /v1/calls/import":      httpapi.MaxUploadBodyBytes,
+		"POST /v1/numbers/documents": httpapi.MaxUploadBodyBytes,
+	}))
+
 	// public
 	r.GET("/healthz", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	// System announcements (public, polled by dashboards).
+	r.GET("/system/announcements", httpapi.Handlers{}.ActiveAnnouncements)
+
 	// Provider webhooks (public).
 	// NOTE: This endpoint should be protected by Twilio signature validation in production.
 	{
 		re := routing.NewRoutingEngine(nil, nil, nil)
-		router := routing.NewEngineAdapter(re, routing.AdapterOptions{})
-		twilioProvider := telephony.NewTwilioProvider(router)
+		// TODO: routing.RedisConcurrencySlots once a Redis client reaches route wiring;
+		// in-memory slots only hold for a single API instance.
+		re.Concurrency = routing.NewMemoryConcurrencySlots()
+		// TODO: persistent wallet assignments and pricing.Service once storage DI lands;
+		// until then calls carry no estimate and the balance check is skipped.
+		walletCtx := &routing.WalletContextResolver{Wallets: routing.NewMemoryWalletAssignmentStore()}
+		router := routing.NewEngineAdapter(re, routing.AdapterOptions{
+			WalletContextResolver: walletCtx.Resolve,
+			// Re-dial up to two more destinations when the selected one is busy or does not answer.
+			FailoverAlternates: 2,
+		})
+		// TODO: size from cfg.Twilio.RESTRatePerSecond/RESTBurst once config reaches route wiring.
+		twilioProvider := telephony.NewTwilioProvider(router).
+			WithRESTLimiter("", telephony.NewRESTLimiter(telephony.RESTLimitConfig{}))
 		h := telephony.TwilioWebhookHandler{
-			Provider: twilioProvider,
+			Provider:       twilioProvider,
+			DialStatusPath: "/webhooks/twilio/dial-status",
+			Concurrency:    re.Concurrency,
 			WorkspaceIDResolver: func(c *gin.Context, toNumber string) (string, error) {
 				// TODO: Resolve workspace_id by looking up the dialed number in storage.
 				// Kept as a function injection to avoid persistence assumptions here.
@@ -35,11 +66,50 @@ func registerRoutes(r *gin.Engine, authMW gin.HandlerFunc) {
 			},
 		}
 		r.POST("/webhooks/twilio/voice", h.HandleInboundCall)
+		r.POST("/webhooks/twilio/dial-status", h.HandleDialStatus)
+		// Status and recording callbacks configured on numbers by ConfigureNumber.
+		r.POST("/webhooks/twilio/status", h.HandleCallStatus)
+		// Caller ID verification results (numbers.Service as recorder once DI lands).
+		r.POST("/webhooks/twilio/caller-id-verification", telephony.TwilioCallerIDHandler{}.HandleVerification)
+		r.POST("/webhooks/twilio/recording", func(c *gin.Context) {
+			c.AbortWithStatusJSON(501, gin.H{"error": "twilio recording callback handler not wired"})
+		})
+		r.POST("/webhooks/twilio/sms", func(c *gin.Context) {
+			// telephony.TwilioSMSHandler with a messaging.Service responder once DI lands.
+			c.AbortWithStatusJSON(501, gin.H{"error": "twilio sms handler not wired (requires messaging service DI)"})
+		})
+	}
+
+	// Visit tracking for the number-insertion script (public). The pool's
+	// publishable site key stands in for a token, so each client IP is held to
+	// a per-minute budget per site key, and each request must carry a fresh
+	// timestamp+nonce so a captured request cannot be replayed into extra
+	// sessions (see httpapi.StartPublicTrackingSession).
+	{
+		limiter := ratelimit.Limiter{Counter: ratelimit.RedisCounter{Client: rdb}, Limit: 30, Window: time.Minute}
+		perSite := func(c *gin.Context) string {
+			return "tracking:" + c.Param("site_key") + ":" + ratelimit.ClientIP(c)
+		}
+		guard := replay.Guard{Nonces: replay.RedisNonceStore{Client: rdb}}
+		siteScope := func(c *gin.Context) string {
+			return "tracking:" + c.Param("site_key")
+		}
+		r.POST("/tracking/:site_key/sessions", limiter.Middleware(perSite), guard.Middleware(siteScope), func(c *gin.Context) {
+			c.AbortWithStatusJSON(501, gin.H{"error": "tracking handler not wired (requires tracking service DI)"})
+		})
 	}
 
+	// SCIM 2.0 provisioning (authenticated by per-workspace SCIM bearer tokens, not user JWTs).
+	// Rejects every request until a TokenStore and scim.Service are injected.
+	scimGroup := r.Group("/scim/v2")
+	scimGroup.Use(scim.RequireToken(nil))
+	scim.Handler{}.Register(scimGroup)
+
 	// protected API group
 	v1 := r.Group("/v1")
 	v1.Use(authMW)
+	v1.Use(auth.RequireWriteScope())
+	v1.Use(rbac.RequireSafeMethods())
 	{
 		h := httpapi.Handlers{
 			// Auth manager is already used by authMW; login uses the same manager but is wired in main.
@@ -57,6 +127,14 @@ func registerRoutes(r *gin.Engine, authMW gin.HandlerFunc) {
 			c.JSON(200, gin.H{"user_id": uid, "workspace_id": wid, "role": role})
 		})
 
+		// Concrete actions the current token can perform (RBAC self-description).
+		v1.GET("/me/permissions", rbac.RequireWorkspace(), h.MyPermissions)
+
+		// Mixed sub-requests replayed through this router (each item is authorized on its own).
+		// TODO: Redis-backed BatchResultStore so replays survive restarts and span instances.
+		batch := httpapi.Handlers{Batch: &httpapi.BatchRunner{Handler: r, Results: httpapi.NewMemoryBatchResults()}}
+		v1.POST("/batch", rbac.RequireWorkspace(), batch.RunBatch)
+
 		// AUTH routes (token issuance).
 		// NOTE: This is a placeholder login route; real credential validation is not implemented.
 		authGroup := v1.Group("/auth")
@@ -73,6 +151,22 @@ func registerRoutes(r *gin.Engine, authMW gin.HandlerFunc) {
 			wallets.GET("/:wallet_id/balance", func(c *gin.Context) {
 				c.AbortWithStatusJSON(501, gin.H{"error": "wallet handler not wired (requires wallet service DI)"})
 			})
+			notWired := func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "wallet handler not wired (requires wallet service DI)"})
+			}
+			wallets.GET("", notWired)
+			manage := rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleSuperAdmin)
+			wallets.POST("", manage, notWired)
+			wallets.POST("/:wallet_id/disable", manage, notWired)
+			wallets.POST("/:wallet_id/enable", manage, notWired)
+			wallets.POST("/:wallet_id/close", manage, notWired)
+			wallets.GET("/:wallet_id/auto-topup", notWired)
+			wallets.PUT("/:wallet_id/auto-topup", manage, notWired)
+			wallets.PUT("/:wallet_id/spend-caps", manage, notWired)
+			// Balance as of a past time, for reporting and disputes.
+			wallets.GET("/:wallet_id/balance/at", rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleFinance, rbac.RoleReadOnly, rbac.RoleSuperAdmin), notWired)
+			// Ledger history for reconciliation (cursor-paginated).
+			wallets.GET("/:wallet_id/ledger", rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleFinance, rbac.RoleReadOnly, rbac.RoleSuperAdmin), notWired)
 		}
 
 		// CALLS routes
@@ -84,23 +178,376 @@ func registerRoutes(r *gin.Engine, authMW gin.HandlerFunc) {
 				// Placeholder only; actual call orchestration belongs to internal/calls.
 				c.JSON(200, gin.H{"status": "queued"})
 			})
+			// Historical CDR backfill (reporting only, never billed).
+			calls.POST("/import", rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleSuperAdmin), func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "call import handler not wired (requires calls importer DI)"})
+			})
+			// Hold music for the remote party; hold periods count toward the call's hold time.
+			calls.POST("/:call_id/hold", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "calls handler not wired (requires calls service DI)"})
+			})
+			calls.POST("/:call_id/resume", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "calls handler not wired (requires calls service DI)"})
+			})
+			// Supervisor listen/whisper/barge via conference bridging; every action is audited.
+			monitorNotWired := func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "call monitor handler not wired (requires call monitor service DI)"})
+			}
+			calls.POST("/:call_id/monitor", rbac.RequirePermission(rbac.PermCallsMonitor), monitorNotWired)
+			calls.PATCH("/:call_id/monitor/:session_id", rbac.RequirePermission(rbac.PermCallsMonitor), monitorNotWired)
+			calls.DELETE("/:call_id/monitor/:session_id", rbac.RequirePermission(rbac.PermCallsMonitor), monitorNotWired)
+		}
+
+		// JOBS routes (status of async operations; GET /:job_id?wait=30s long-polls)
+		jobsGroup := v1.Group("/jobs")
+		jobsGroup.Use(rbac.RequireWorkspace())
+		jobsGroup.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleAnalyst, rbac.RoleFinance, rbac.RoleReadOnly, rbac.RoleSuperAdmin))
+		{
+			notWired := func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "jobs handler not wired (requires jobs service DI)"})
+			}
+			jobsGroup.GET("", notWired)
+			jobsGroup.GET("/:job_id", notWired)
+		}
+
+		// CALLBACKS routes (callback tasks captured by IVR when no agent was available)
+		callbacksGroup := v1.Group("/callbacks")
+		callbacksGroup.Use(rbac.RequireWorkspace())
+		callbacksGroup.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleAgent, rbac.RoleSuperAdmin))
+		{
+			notWired := func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "callbacks handler not wired (requires callbacks service DI)"})
+			}
+			callbacksGroup.GET("", notWired)
+			callbacksGroup.POST("/:callback_id/cancel", notWired)
+		}
+
+		// MESSAGING routes (SMS keyword replies, opt-out list, inbound forwarding, paced sends)
+		messagingGroup := v1.Group("/messaging")
+		messagingGroup.Use(rbac.RequireWorkspace())
+		messagingGroup.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleSuperAdmin))
+		{
+			notWired := func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "messaging handler not wired (requires messaging service DI)"})
+			}
+			messagingGroup.GET("/keywords", notWired)
+			messagingGroup.PUT("/keywords", notWired)
+			messagingGroup.DELETE("/keywords/:rule_id", notWired)
+			messagingGroup.GET("/opt-outs", notWired)
+			messagingGroup.GET("/config", notWired)
+			messagingGroup.PUT("/config", notWired)
+			messagingGroup.POST("/messages", notWired)
+			messagingGroup.GET("/messages/:message_id", notWired)
+			messagingGroup.GET("/queue", notWired)
+		}
+
+		// WEBHOOK signing routes (per-workspace signing secrets for tenant-facing webhooks)
+		webhooksGroup := v1.Group("/webhooks")
+		webhooksGroup.Use(rbac.RequireWorkspace())
+		webhooksGroup.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleSuperAdmin))
+		{
+			notWired := func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "webhooks handler not wired (requires webhooks service DI)"})
+			}
+			webhooksGroup.GET("/signing-keys", notWired)
+			webhooksGroup.POST("/signing-keys/rotate", notWired)
+			webhooksGroup.POST("/test", notWired)
+			webhooksGroup.GET("/verification", notWired)
+		}
+
+		// CONVERSION routes (offline conversion forwarding to Google Ads / Meta, with delivery logs)
+		conversionsGroup := v1.Group("/conversions")
+		conversionsGroup.Use(rbac.RequireWorkspace())
+		conversionsGroup.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleSuperAdmin))
+		{
+			notWired := func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "conversions handler not wired (requires conversions service DI)"})
+			}
+			conversionsGroup.GET("/integrations", notWired)
+			conversionsGroup.POST("/integrations", notWired)
+			conversionsGroup.GET("/integrations/:integration_id", notWired)
+			conversionsGroup.PUT("/integrations/:integration_id", notWired)
+			conversionsGroup.DELETE("/integrations/:integration_id", notWired)
+			conversionsGroup.GET("/deliveries", notWired)
+			conversionsGroup.POST("/deliveries/:delivery_id/retry", notWired)
+		}
+
+		// TRACKING routes (visit sessions with UTM/click ids for dynamic number insertion)
+		trackingGroup := v1.Group("/tracking")
+		trackingGroup.Use(rbac.RequireWorkspace())
+		{
+			trackingGroup.POST("/sessions", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "tracking handler not wired (requires tracking service DI)"})
+			})
+		}
+
+		// REPORTS routes (tenant dashboard and marketing attribution reports)
+		reports := v1.Group("/reports")
+		reports.Use(rbac.RequireWorkspace())
+		reports.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleAnalyst, rbac.RoleFinance, rbac.RoleReadOnly, rbac.RoleSuperAdmin))
+		{
+			notWired := func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "reporting handler not wired (requires reporting service DI)"})
+			}
+			reports.GET("/attribution", notWired)
+			// Today's aggregates, served from the cache warmed by the worker.
+			reports.GET("/dashboard", notWired)
+			// Talk time per agent (hold time excluded when configured).
+			reports.GET("/agent-talk-time", notWired)
+			// Live per-campaign contact-center stats from Redis; /stream pushes them every second (SSE).
+			reports.GET("/wallboard", notWired)
+			reports.GET("/wallboard/stream", notWired)
+			// Dialer abandonment per campaign per day against the 3% threshold.
+			reports.GET("/abandonment", notWired)
+		}
+
+		// NUMBER POOL routes (rotating sender / tracking number sets; see internal/numbers/pools.go)
+		pools := v1.Group("/number-pools")
+		pools.Use(rbac.RequireWorkspace())
+		pools.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleSuperAdmin))
+		{
+			notWired := func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "numbers handler not wired (requires numbers service DI)"})
+			}
+			pools.GET("", notWired)
+			pools.POST("", notWired)
+			pools.GET("/:pool_id", notWired)
+			pools.PUT("/:pool_id", notWired)
+			pools.DELETE("/:pool_id", notWired)
+			// Local presence preview: the caller ID a caller_id pool picks for ?to=.
+			pools.GET("/:pool_id/caller-id", notWired)
+		}
+
+		// CALLER ID routes (external numbers verified for outbound caller ID; see
+		// internal/numbers/caller_id_verification.go)
+		callerIDs := v1.Group("/caller-ids")
+		callerIDs.Use(rbac.RequireWorkspace())
+		callerIDs.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleSuperAdmin))
+		{
+			notWired := func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "numbers handler not wired (requires numbers service DI)"})
+			}
+			callerIDs.GET("", notWired)
+			callerIDs.POST("", notWired)
+			callerIDs.DELETE("/:caller_id", notWired)
+		}
+
+		// SIP TRUNK routes (transport and SRTP policy; secure trunks are verified
+		// before enabling, see internal/telephony/sip_trunk.go)
+		sipTrunks := v1.Group("/sip-trunks")
+		sipTrunks.Use(rbac.RequireWorkspace())
+		sipTrunks.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleSuperAdmin))
+		{
+			notWired := func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "sip trunk handler not wired (requires trunk store DI)"})
+			}
+			sipTrunks.GET("", notWired)
+			sipTrunks.PUT("/:trunk_id", notWired)
+			sipTrunks.POST("/:trunk_id/enable", notWired)
+			sipTrunks.POST("/:trunk_id/disable", notWired)
+		}
+
+		// DIAL PLAN routes (destination normalization per country; emergency numbers
+		// are rejected, see internal/telephony/dialplan.go)
+		dialPlan := v1.Group("/dial-plan")
+		dialPlan.Use(rbac.RequireWorkspace())
+		{
+			notWired := func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "dial plan handler not wired (requires dial plan settings DI)"})
+			}
+			dialPlan.GET("", rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleSuperAdmin), notWired)
+			dialPlan.PUT("", rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleSuperAdmin), notWired)
+			dialPlan.POST("/normalize", rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleAgent, rbac.RoleSuperAdmin), notWired)
+		}
+
+		// BLOCKLIST routes (callers rejected before routing; see internal/routing/blocklist.go)
+		blocklist := v1.Group("/blocklist")
+		blocklist.Use(rbac.RequireWorkspace())
+		blocklist.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleSuperAdmin))
+		{
+			notWired := func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "blocklist handler not wired (requires blocklist store DI)"})
+			}
+			blocklist.GET("", notWired)
+			blocklist.POST("", notWired)
+			blocklist.DELETE("/:entry_id", notWired)
+		}
+
+		// ROUTING DECISION routes (recorded Route outcomes for troubleshooting; see
+		// internal/routing/decision_log.go)
+		routingGroup := v1.Group("/routing")
+		routingGroup.Use(rbac.RequireWorkspace())
+		routingGroup.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleNetworkOperator, rbac.RoleSuperAdmin))
+		{
+			routingGroup.GET("/decisions", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "routing handler not wired (requires routing engine DI)"})
+			})
+		}
+
+		// LOOKUPS routes (billed number intelligence; see internal/lookups)
+		lookupsGroup := v1.Group("/lookups")
+		lookupsGroup.Use(rbac.RequireWorkspace())
+		lookupsGroup.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleAgent, rbac.RoleSuperAdmin))
+		{
+			lookupsGroup.GET("/:number", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "lookups handler not wired (requires lookups service DI)"})
+			})
 		}
 
 		// CAMPAIGNS routes
 		campaigns := v1.Group("/campaigns")
 		campaigns.Use(rbac.RequireWorkspace())
-		campaigns.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleAnalyst, rbac.RoleSuperAdmin))
+		campaigns.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleAnalyst, rbac.RoleReadOnly, rbac.RoleSuperAdmin))
 		{
 			campaigns.GET("/", func(c *gin.Context) {
 				c.AbortWithStatusJSON(501, gin.H{"error": "campaigns not implemented"})
 			})
 		}
 
+		// NUMBERS routes
+		// Purchases are checked against the workspace purchase policy and regulatory requirements.
+		nums := v1.Group("/numbers")
+		nums.Use(rbac.RequireWorkspace())
+		nums.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleSuperAdmin))
+		{
+			nums.GET("/requirements", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "numbers handler not wired (requires numbers service DI)"})
+			})
+			nums.GET("", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "numbers handler not wired (requires numbers service DI)"})
+			})
+			nums.PUT("/:number/assignment", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "numbers handler not wired (requires numbers service DI)"})
+			})
+			nums.POST("/import", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "numbers handler not wired (requires numbers service DI)"})
+			})
+			nums.POST("/:number/webhooks", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "numbers handler not wired (requires numbers service DI)"})
+			})
+			// E911 addresses for US voice numbers (see internal/numbers/emergency_address.go).
+			nums.GET("/emergency-readiness", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "numbers handler not wired (requires numbers service DI)"})
+			})
+			nums.GET("/:number/emergency-address", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "numbers handler not wired (requires numbers service DI)"})
+			})
+			nums.PUT("/:number/emergency-address", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "numbers handler not wired (requires numbers service DI)"})
+			})
+			// Per-number forwarding for numbers used without a campaign.
+			nums.GET("/:number/forwarding", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "forwarding handler not wired (requires forwarding service DI)"})
+			})
+			nums.PUT("/:number/forwarding", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "forwarding handler not wired (requires forwarding service DI)"})
+			})
+			nums.DELETE("/:number/forwarding", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "forwarding handler not wired (requires forwarding service DI)"})
+			})
+			nums.GET("/search", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "numbers handler not wired (requires numbers service DI)"})
+			})
+			nums.POST("/purchase", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "numbers handler not wired (requires numbers service DI)"})
+			})
+			nums.PUT("/policy", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "numbers handler not wired (requires numbers service DI)"})
+			})
+			// Regulatory documents for countries that need KYC.
+			nums.POST("/documents", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "compliance handler not wired (requires compliance service DI)"})
+			})
+		}
+
+		// CONTRACTS routes (committed-use status for the tenant).
+		contractsGroup := v1.Group("/contracts")
+		contractsGroup.Use(rbac.RequireWorkspace())
+		contractsGroup.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleFinance, rbac.RoleReadOnly, rbac.RoleSuperAdmin))
+		{
+			contractsGroup.GET("", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "contracts handler not wired (requires contracts service DI)"})
+			})
+		}
+
+		// AUDIT EXPORT routes (opt-in tenant SIEM streaming).
+		auditExport := v1.Group("/audit/export")
+		auditExport.Use(rbac.RequireWorkspace())
+		auditExport.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleSuperAdmin))
+		{
+			auditExport.GET("", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "audit export handler not wired (requires audit export store DI)"})
+			})
+			auditExport.PUT("", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "audit export handler not wired (requires audit export store DI)"})
+			})
+		}
+
+		// SYSTEM routes (platform-wide; super_admin only).
+		system := v1.Group("/system")
+		system.Use(rbac.RequireWorkspace())
+		system.Use(rbac.RequireAnyRole(rbac.RoleSuperAdmin))
+		{
+			system.GET("/announcements", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "announcements handler not wired (requires announcements service DI)"})
+			})
+			system.POST("/announcements", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "announcements handler not wired (requires announcements service DI)"})
+			})
+			system.PUT("/announcements/:announcement_id", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "announcements handler not wired (requires announcements service DI)"})
+			})
+			system.DELETE("/announcements/:announcement_id", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "announcements handler not wired (requires announcements service DI)"})
+			})
+
+			// Committed-use contracts are negotiated by the platform, not self-served.
+			system.POST("/contracts", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "contracts handler not wired (requires contracts service DI)"})
+			})
+			system.POST("/contracts/:contract_id/cancel", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "contracts handler not wired (requires contracts service DI)"})
+			})
+
+			// Internal oversight of the hidden override capability.
+			system.GET("/reports/override-usage", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "reporting handler not wired (requires reporting service DI)"})
+			})
+			// Platform revenue and other system ledger accounts (double-entry legs).
+			system.GET("/reports/system-accounts", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "wallet admin handler not wired (requires wallet service DI)"})
+			})
+		}
+
+		// NOC routes (network operator console).
+		// Explicitly listed for the hidden network_operator role; kept separate from /admin.
+		nocGroup := v1.Group("/noc")
+		if privilegedNetMW != nil {
+			nocGroup.Use(privilegedNetMW)
+		}
+		// Platform-wide controls: no workspace, platform roles only.
+		nocGroup.Use(rbac.RequirePlatformRole(rbac.RoleNetworkOperator, rbac.RoleSuperAdmin))
+		{
+			notWired := func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "noc handler not wired (requires noc service DI)"})
+			}
+			nocGroup.GET("/calls/live", notWired)
+			nocGroup.GET("/trunks", notWired)
+			nocGroup.GET("/emergency-stop", notWired)
+			nocGroup.POST("/emergency-stop", notWired)
+			nocGroup.DELETE("/emergency-stop", notWired)
+			nocGroup.GET("/reroutes", notWired)
+			nocGroup.POST("/reroutes", notWired)
+			nocGroup.DELETE("/reroutes/:reroute_id", notWired)
+		}
 
 		// ADMIN routes
 		// Only owner/super_admin can access admin endpoints by default.
 		// Hidden network_operator is intentionally NOT included unless explicitly desired.
 		admin := v1.Group("/admin")
+		if privilegedNetMW != nil {
+			admin.Use(privilegedNetMW)
+		}
 		admin.Use(rbac.RequireWorkspace())
 		admin.Use(rbac.RequireAnyRole(rbac.RoleOwner, rbac.RoleSuperAdmin))
 		{
@@ -108,12 +555,124 @@ func registerRoutes(r *gin.Engine, authMW gin.HandlerFunc) {
 				c.JSON(200, gin.H{"status": "ok"})
 			})
 
+			// Platform-wide caller blocklist (applies to every workspace).
+			blocklistNotWired := func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "blocklist handler not wired (requires blocklist store DI)"})
+			}
+			admin.GET("/blocklist", rbac.RequireAnyRole(rbac.RoleSuperAdmin), blocklistNotWired)
+			admin.POST("/blocklist", rbac.RequireAnyRole(rbac.RoleSuperAdmin), blocklistNotWired)
+			admin.DELETE("/blocklist/:entry_id", rbac.RequireAnyRole(rbac.RoleSuperAdmin), blocklistNotWired)
+
 			// Admin wallet credit (placeholder wiring until DI is added).
 			admin.POST("/wallets/manual-credit", func(c *gin.Context) {
 				// Avoid constructing wallet service with nil dependencies.
 				_ = wallet.ErrInvalidArgument
 				c.AbortWithStatusJSON(501, gin.H{"error": "wallet admin handler not wired (requires wallet service DI)"})
 			})
+			// Overdraft is a platform risk decision, so tenant owners cannot raise their own limit.
+			admin.POST("/wallets/:wallet_id/credit-limit", rbac.RequireAnyRole(rbac.RoleSuperAdmin), func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "wallet admin handler not wired (requires wallet service DI)"})
+			})
+
+			// Freezes block every money movement; lifting one is reserved to platform admins too.
+			admin.POST("/wallets/:wallet_id/freeze", rbac.RequireAnyRole(rbac.RoleSuperAdmin), func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "wallet admin handler not wired (requires wallet service DI)"})
+			})
+			admin.POST("/wallets/:wallet_id/unfreeze", rbac.RequireAnyRole(rbac.RoleSuperAdmin), func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "wallet admin handler not wired (requires wallet service DI)"})
+			})
+			// Refunds/reversals move money out of revenue, so tenant owners cannot post them.
+			admin.POST("/wallets/:wallet_id/ledger/:ledger_id/reverse", rbac.RequireAnyRole(rbac.RoleSuperAdmin), func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "wallet admin handler not wired (requires wallet service DI)"})
+			})
+
+			// Four-eyes approval queue for high-risk actions (large credits, freezes, overrides).
+			admin.GET("/approvals", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "approvals handler not wired (requires approvals service DI)"})
+			})
+			admin.POST("/approvals/:approval_id/approve", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "approvals handler not wired (requires approvals service DI)"})
+			})
+			admin.POST("/approvals/:approval_id/reject", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "approvals handler not wired (requires approvals service DI)"})
+			})
+
+			// Workspace termination: suspend -> grace period -> staged purge (run by the worker).
+			admin.GET("/workspace/termination", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "workspace handler not wired (requires workspaces service DI)"})
+			})
+			admin.POST("/workspace/termination", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "workspace handler not wired (requires workspaces service DI)"})
+			})
+			admin.DELETE("/workspace/termination", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "workspace handler not wired (requires workspaces service DI)"})
+			})
+
+			// Legal holds: held calls/recordings are skipped by purges and block workspace purge.
+			admin.GET("/legal-holds", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "legal hold handler not wired (requires legal hold service DI)"})
+			})
+			admin.POST("/legal-holds", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "legal hold handler not wired (requires legal hold service DI)"})
+			})
+			admin.POST("/legal-holds/:hold_id/release", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "legal hold handler not wired (requires legal hold service DI)"})
+			})
+
+			// What-if re-pricing of a historical period before publishing a rate deck change.
+			admin.POST("/pricing/simulate", rbac.RequireAnyRole(rbac.RoleSuperAdmin), func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "pricing handler not wired (requires pricing service DI)"})
+			})
+
+			// Side-effect-free routing trace for troubleshooting misrouted calls.
+			// super_admin only: the trace reveals silent overrides.
+			admin.POST("/routing/dry-run", rbac.RequireAnyRole(rbac.RoleSuperAdmin), func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "routing handler not wired (requires routing engine DI)"})
+			})
+
+			// Tamper-evidence checks for audit_events and wallet_ledger hash chains.
+			admin.GET("/audit/verify", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "audit verify handler not wired (requires audit service DI)"})
+			})
+			admin.GET("/wallets/:wallet_id/ledger/verify", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "wallet admin handler not wired (requires wallet service DI)"})
+			})
+			// Money operation metrics and SLO burn rates (Prometheus text format).
+			admin.GET("/metrics/wallet", rbac.RequireAnyRole(rbac.RoleSuperAdmin), func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "wallet admin handler not wired (requires wallet service DI)"})
+			})
+			// Latest provider config drift report (numbers, webhooks, recording); platform-wide.
+			admin.GET("/numbers/drift", rbac.RequireAnyRole(rbac.RoleSuperAdmin), func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "numbers handler not wired (requires numbers service DI)"})
+			})
+			// Ledger search across the workspace's wallets for support investigations.
+			admin.GET("/ledger/search", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "wallet admin handler not wired (requires wallet service DI)"})
+			})
+			// Daily/monthly spend cap utilization (caps are set via PUT /v1/wallets/:wallet_id/spend-caps).
+			admin.GET("/wallets/:wallet_id/spend-caps", func(c *gin.Context) {
+				c.AbortWithStatusJSON(501, gin.H{"error": "wallet admin handler not wired (requires wallet service DI)"})
+			})
 		}
 	}
 }
+
+// registerInternalRoutes wires the mTLS listener for internal services (worker,
+// media controller). certMW authenticates the client certificate.
+func registerInternalRoutes(r *gin.Engine, certMW gin.HandlerFunc) {
+	r.GET("/healthz", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "ok"})
+	})
+
+	internal := r.Group("/internal/v1")
+	internal.Use(certMW)
+	{
+		// Identity resolved from the client certificate (for rollout checks).
+		internal.GET("/whoami", func(c *gin.Context) {
+			uid, _ := auth.UserID(c.Request.Context())
+			wid, _ := auth.WorkspaceID(c.Request.Context())
+			role, _ := auth.Role(c.Request.Context())
+			c.JSON(200, gin.H{"user_id": uid, "workspace_id": wid, "role": role})
+		})
+	}
+}
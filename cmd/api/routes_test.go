@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The permission matrix is what /v1/me/permissions reports to frontends, so
+// each read route's role list must grant exactly the roles the matrix does.
+func TestRoutes_ReadRolesMatchPermissionMatrix(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	asRole := func(c *gin.Context) {
+		ctx := auth.WithIdentity(c.Request.Context(), "u", "w", c.GetHeader("X-Test-Role"))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+	registerRoutes(r, nil, asRole, nil)
+
+	routes := []struct {
+		path string
+		perm rbac.Permission
+	}{
+		{"/v1/campaigns/", rbac.PermCampaignsRead},
+		{"/v1/contracts", rbac.PermContractsRead},
+		{"/v1/jobs", rbac.PermJobsRead},
+		{"/v1/jobs/job-1", rbac.PermJobsRead},
+		{"/v1/reports/dashboard", rbac.PermReportsRead},
+		{"/v1/reports/attribution", rbac.PermReportsRead},
+		{"/v1/lookups/+15550001", rbac.PermLookupsRun},
+	}
+	roles := []string{rbac.RoleOwner, rbac.RoleAgent, rbac.RoleAnalyst, rbac.RoleFinance, rbac.RoleReadOnly, rbac.RoleNetworkOperator, rbac.RoleSuperAdmin}
+	for _, rt := range routes {
+		for _, role := range roles {
+			req := httptest.NewRequest(http.MethodGet, rt.path, nil)
+			req.Header.Set("X-Test-Role", role)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			allowed := w.Code != http.StatusForbidden
+			if allowed != rbac.Can(role, rt.perm) {
+				t.Fatalf("GET %s as %s: route allowed=%v (status %d), matrix grants %s=%v", rt.path, role, allowed, w.Code, rt.perm, rbac.Can(role, rt.perm))
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"telecom-platform/internal/config"
+	"telecom-platform/internal/events"
+	"telecom-platform/pkg/logger"
+	"telecom-platform/pkg/utils"
+)
+
+// worker consumes the call event bus. Each concern is its own consumer group so
+// billing, notifications and analytics progress (and fail) independently.
+func main() {
+	rootCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("config load failed", "err", err)
+		os.Exit(1)
+	}
+
+	log := logger.New(cfg.App.Env)
+	slog.SetDefault(log)
+
+	rdb, err := utils.OpenRedis(rootCtx, utils.RedisConfig{Addr: cfg.RedisAddr()})
+	if err != nil {
+		log.Error("redis init failed", "err", err)
+		os.Exit(1)
+	}
+	defer rdb.Close()
+
+	name, _ := os.Hostname()
+	if name == "" {
+		name = "worker"
+	}
+	streams := events.RedisStreams{Client: rdb}
+
+	consumers := []events.Consumer{
+		{Group: "billing", Handler: handleBilling},
+		{Group: "notifications", Handler: handleNotifications},
+		{Group: "analytics", Handler: handleAnalytics},
+		{Group: "conversions", Handler: handleConversions},
+	}
+
+	// TODO: run reporting.DashboardWarmer{Cache: reporting.RedisDashboardCache{Client: rdb}}
+	// every minute once a reporting repository and active-workspace source are wired.
+
+	var wg sync.WaitGroup
+	for _, c := range consumers {
+		c.Client = streams
+		c.Stream = events.DefaultStream
+		c.Name = name
+		c.Block = 5 * time.Second
+		c.ClaimMinIdle = time.Minute
+		if err := c.Start(rootCtx, "$"); err != nil {
+			log.Error("consumer group init failed", "group", c.Group, "err", err)
+			os.Exit(1)
+		}
+		wg.Add(1)
+		go func(c events.Consumer) {
+			defer wg.Done()
+			c.Run(logger.With(rootCtx, log.With("group", c.Group)))
+		}(c)
+	}
+
+	log.Info("worker started", "consumer", name, "stream", events.DefaultStream)
+	<-rootCtx.Done()
+	log.Info("shutdown initiated")
+	wg.Wait()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = logger.ShutdownFlush(shutdownCtx, 2*time.Second)
+}
+
+// Handlers must be idempotent: delivery is at-least-once.
+
+func handleBilling(ctx context.Context, e events.Envelope) error {
+	if e.Type != events.TypeCallCompleted {
+		return nil
+	}
+	p, err := e.CallPayload()
+	if err != nil {
+		return err
+	}
+	// Usage charging is keyed by the event id (wallet idempotency key) once wired to wallet.Service.
+	logger.From(ctx).Info("billing: call completed", "workspace_id", e.WorkspaceID, "provider_call_id", p.ProviderCallID, "duration", p.DurationSeconds)
+	return nil
+}
+
+func handleNotifications(ctx context.Context, e events.Envelope) error {
+	if e.Type != events.TypeCallFailed {
+		return nil
+	}
+	logger.From(ctx).Info("notifications: call failed", "workspace_id", e.WorkspaceID, "event_id", e.ID)
+	return nil
+}
+
+func handleConversions(ctx context.Context, e events.Envelope) error {
+	if e.Type != events.TypeCallCompleted {
+		return nil
+	}
+	p, err := e.CallPayload()
+	if err != nil {
+		return err
+	}
+	// Hands the call to conversions.Service.RecordCall (deduplicated per integration and call) once wired.
+	logger.From(ctx).Debug("conversions: call completed", "workspace_id", e.WorkspaceID, "call_id", p.CallID, "duration", p.DurationSeconds)
+	return nil
+}
+
+func handleAnalytics(ctx context.Context, e events.Envelope) error {
+	logger.From(ctx).Debug("analytics: event", "workspace_id", e.WorkspaceID, "type", e.Type)
+	return nil
+}
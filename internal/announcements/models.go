@@ -0,0 +1,50 @@
+package announcements
+
+import "time"
+
+// Announcement is a system-wide banner shown on dashboards (maintenance windows, incidents, etc.).
+//
+// Announcements are platform-level, not tenant-scoped: they are managed by super_admin only
+// and served unauthenticated, so they must never contain tenant data.
+type Announcement struct {
+	ID       string   `json:"id" db:"id"`
+	Message  string   `json:"message" db:"message"`
+	Severity Severity `json:"severity" db:"severity"`
+
+	// StartsAt is inclusive. EndsAt is exclusive; nil means until removed.
+	StartsAt time.Time  `json:"starts_at" db:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at,omitempty" db:"ends_at"`
+
+	CreatedByUserID string `json:"-" db:"created_by_user_id"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+func (s Severity) valid() bool {
+	switch s {
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+		return true
+	default:
+		return false
+	}
+}
+
+// ActiveAt reports whether the announcement should be displayed at t.
+func (a Announcement) ActiveAt(t time.Time) bool {
+	if t.Before(a.StartsAt) {
+		return false
+	}
+	if a.EndsAt != nil && !t.Before(*a.EndsAt) {
+		return false
+	}
+	return true
+}
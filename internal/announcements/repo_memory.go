@@ -0,0 +1,61 @@
+package announcements
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryRepo is a simple in-memory repository useful for tests.
+// It is not intended for production use.
+type MemoryRepo struct {
+	mu    sync.Mutex
+	items map[string]Announcement
+}
+
+func NewMemoryRepo() *MemoryRepo { return &MemoryRepo{items: map[string]Announcement{}} }
+
+func (r *MemoryRepo) Create(ctx context.Context, a Announcement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items[a.ID] = a
+	return nil
+}
+
+func (r *MemoryRepo) Update(ctx context.Context, a Announcement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[a.ID]; !ok {
+		return ErrNotFound
+	}
+	r.items[a.ID] = a
+	return nil
+}
+
+func (r *MemoryRepo) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.items, id)
+	return nil
+}
+
+func (r *MemoryRepo) Get(ctx context.Context, id string) (Announcement, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a, ok := r.items[id]
+	return a, ok, nil
+}
+
+func (r *MemoryRepo) List(ctx context.Context) ([]Announcement, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Announcement, 0, len(r.items))
+	for _, a := range r.items {
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartsAt.Before(out[j].StartsAt) })
+	return out, nil
+}
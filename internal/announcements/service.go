@@ -0,0 +1,171 @@
+package announcements
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Service manages system announcements.
+//
+// Rules:
+// - Mutations are restricted to super_admin at the HTTP layer (RBAC), not here.
+// - Active() is cheap and safe to call from an unauthenticated, frequently polled endpoint.
+// - When maintenance mode is on (APP_MAINTENANCE), a synthetic banner is always included.
+type Service struct {
+	repo  Repository
+	clock func() time.Time
+
+	// Maintenance mirrors config.AppConfig.Maintenance.
+	Maintenance bool
+}
+
+// Repository persists announcements.
+type Repository interface {
+	Create(ctx context.Context, a Announcement) error
+	Update(ctx context.Context, a Announcement) error
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (Announcement, bool, error)
+	List(ctx context.Context) ([]Announcement, error)
+}
+
+func NewService(repo Repository, maintenance bool) *Service {
+	return &Service{repo: repo, clock: time.Now, Maintenance: maintenance}
+}
+
+var (
+	ErrInvalidArgument = errors.New("announcements: invalid argument")
+	ErrNotFound        = errors.New("announcements: not found")
+)
+
+// maintenanceAnnouncementID identifies the synthetic maintenance banner.
+const maintenanceAnnouncementID = "maintenance"
+
+type UpsertRequest struct {
+	Message  string     `json:"message"`
+	Severity Severity   `json:"severity"`
+	StartsAt time.Time  `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+}
+
+func (r UpsertRequest) validate() error {
+	if strings.TrimSpace(r.Message) == "" || !r.Severity.valid() || r.StartsAt.IsZero() {
+		return ErrInvalidArgument
+	}
+	if r.EndsAt != nil && !r.EndsAt.After(r.StartsAt) {
+		return ErrInvalidArgument
+	}
+	return nil
+}
+
+func (s *Service) Create(ctx context.Context, actorUserID string, req UpsertRequest) (Announcement, error) {
+	if err := req.validate(); err != nil {
+		return Announcement{}, err
+	}
+	if s.repo == nil {
+		return Announcement{}, errors.New("announcements: repository not configured")
+	}
+	now := s.clock().UTC()
+	a := Announcement{
+		ID:              uuid.NewString(),
+		Message:         strings.TrimSpace(req.Message),
+		Severity:        req.Severity,
+		StartsAt:        req.StartsAt.UTC(),
+		EndsAt:          req.EndsAt,
+		CreatedByUserID: actorUserID,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.repo.Create(ctx, a); err != nil {
+		return Announcement{}, err
+	}
+	return a, nil
+}
+
+func (s *Service) Update(ctx context.Context, id string, req UpsertRequest) (Announcement, error) {
+	if id == "" {
+		return Announcement{}, ErrInvalidArgument
+	}
+	if err := req.validate(); err != nil {
+		return Announcement{}, err
+	}
+	if s.repo == nil {
+		return Announcement{}, errors.New("announcements: repository not configured")
+	}
+	a, ok, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return Announcement{}, err
+	}
+	if !ok {
+		return Announcement{}, ErrNotFound
+	}
+	a.Message = strings.TrimSpace(req.Message)
+	a.Severity = req.Severity
+	a.StartsAt = req.StartsAt.UTC()
+	a.EndsAt = req.EndsAt
+	a.UpdatedAt = s.clock().UTC()
+	if err := s.repo.Update(ctx, a); err != nil {
+		return Announcement{}, err
+	}
+	return a, nil
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrInvalidArgument
+	}
+	if s.repo == nil {
+		return errors.New("announcements: repository not configured")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// List returns all announcements (including past/future) for the admin UI.
+func (s *Service) List(ctx context.Context) ([]Announcement, error) {
+	if s.repo == nil {
+		return nil, errors.New("announcements: repository not configured")
+	}
+	return s.repo.List(ctx)
+}
+
+// Active returns announcements to display now, most severe first.
+func (s *Service) Active(ctx context.Context) ([]Announcement, error) {
+	now := s.clock().UTC()
+	out := make([]Announcement, 0)
+	if s.Maintenance {
+		out = append(out, Announcement{
+			ID:       maintenanceAnnouncementID,
+			Message:  "The platform is in maintenance mode. Changes are temporarily disabled.",
+			Severity: SeverityWarning,
+			StartsAt: now,
+		})
+	}
+	if s.repo != nil {
+		all, err := s.repo.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range all {
+			if a.ActiveAt(now) {
+				out = append(out, a)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return severityRank(out[i].Severity) > severityRank(out[j].Severity) })
+	return out, nil
+}
+
+func severityRank(s Severity) int {
+	switch s {
+	case SeverityCritical:
+		return 2
+	case SeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,40 @@
+package announcements
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestActive_FiltersWindowAndAddsMaintenance(t *testing.T) {
+	now := time.Unix(1700000000, 0).UTC()
+	svc := NewService(NewMemoryRepo(), true)
+	svc.clock = func() time.Time { return now }
+	ctx := context.Background()
+
+	end := now.Add(time.Hour)
+	if _, err := svc.Create(ctx, "admin", UpsertRequest{Message: "Carrier incident", Severity: SeverityCritical, StartsAt: now.Add(-time.Minute), EndsAt: &end}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := svc.Create(ctx, "admin", UpsertRequest{Message: "Future", Severity: SeverityInfo, StartsAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	got, err := svc.Active(ctx)
+	if err != nil {
+		t.Fatalf("active: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 active (incident + maintenance), got %d", len(got))
+	}
+	if got[0].Severity != SeverityCritical || got[1].ID != maintenanceAnnouncementID {
+		t.Fatalf("unexpected order: %+v", got)
+	}
+}
+
+func TestCreate_ValidatesInput(t *testing.T) {
+	svc := NewService(NewMemoryRepo(), false)
+	if _, err := svc.Create(context.Background(), "admin", UpsertRequest{Message: "x", Severity: "loud", StartsAt: time.Now()}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package approvals
+
+import (
+	"context"
+	"encoding/json"
+
+	"telecom-platform/internal/wallet"
+)
+
+// ManualCreditPayload is the Payload for ActionManualCredit.
+type ManualCreditPayload struct {
+	WalletID       string `json:"wallet_id"`
+	AmountMinor    int64  `json:"amount_minor"`
+	Currency       string `json:"currency"`
+	Reason         string `json:"reason"`
+	IdempotencyKey string `json:"idempotency_key"`
+	Metadata       string `json:"metadata,omitempty"`
+}
+
+// ManualCreditExecutor posts an approved manual credit.
+// The ledger/admin action records the requester as the acting admin; the approver
+// is captured in the approval audit trail.
+func ManualCreditExecutor(w *wallet.Service) Executor {
+	return func(ctx context.Context, r Request) (string, error) {
+		var p ManualCreditPayload
+		if err := json.Unmarshal([]byte(r.Payload), &p); err != nil {
+			return "", ErrInvalidArgument
+		}
+		_, entry, _, err := w.AdminManualCredit(ctx, r.WorkspaceID, p.WalletID, r.RequestedBy, r.RequestedRole, wallet.AdminCreditRequest{
+			AmountMinor:    p.AmountMinor,
+			Currency:       p.Currency,
+			Reason:         p.Reason,
+			IdempotencyKey: p.IdempotencyKey,
+			Metadata:       p.Metadata,
+		})
+		if err != nil {
+			return "", err
+		}
+		return entry.ID, nil
+	}
+}
+
+// WalletFreezePayload is the Payload for ActionWalletFreeze.
+type WalletFreezePayload struct {
+	WalletID string `json:"wallet_id"`
+	Reason   string `json:"reason"`
+}
+
+// WalletFreezeExecutor freezes the wallet once the freeze is approved. As with
+// manual credits, the requester is recorded as the acting admin.
+func WalletFreezeExecutor(w *wallet.Service) Executor {
+	return func(ctx context.Context, r Request) (string, error) {
+		var p WalletFreezePayload
+		if err := json.Unmarshal([]byte(r.Payload), &p); err != nil {
+			return "", ErrInvalidArgument
+		}
+		frozen, err := w.FreezeWallet(ctx, r.WorkspaceID, p.WalletID, r.RequestedBy, r.RequestedRole, wallet.FreezeWalletRequest{Reason: p.Reason})
+		if err != nil {
+			return "", err
+		}
+		return frozen.ID, nil
+	}
+}
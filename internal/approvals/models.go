@@ -0,0 +1,65 @@
+package approvals
+
+import "time"
+
+// ActionType identifies a high-risk operation that may require a second approver.
+type ActionType string
+
+const (
+	ActionManualCredit ActionType = "wallet_manual_credit"
+	ActionWalletFreeze ActionType = "wallet_freeze"
+)
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved" // approved and executed successfully
+	StatusFailed   Status = "failed"   // approved but execution failed
+	StatusRejected Status = "rejected"
+	StatusExpired  Status = "expired"
+)
+
+// Request is a pending high-risk action awaiting a second admin.
+//
+// Invariants:
+// - workspace_id required.
+// - ApprovedBy/RejectedBy must differ from RequestedBy (four-eyes).
+// - Payload is immutable after creation; the approver approves exactly what was requested.
+type Request struct {
+	ID          string     `json:"id" db:"id"`
+	WorkspaceID string     `json:"workspace_id" db:"workspace_id"`
+	Action      ActionType `json:"action" db:"action"`
+
+	// Payload is the JSON-encoded action input passed to the executor on approval.
+	Payload string `json:"payload" db:"payload"`
+	// AmountMinor is set for money actions to make review easier.
+	AmountMinor int64  `json:"amount_minor,omitempty" db:"amount_minor"`
+	Currency    string `json:"currency,omitempty" db:"currency"`
+	Reason      string `json:"reason" db:"reason"`
+
+	RequestedBy   string `json:"requested_by" db:"requested_by"`
+	RequestedRole string `json:"requested_role" db:"requested_role"`
+
+	Status       Status `json:"status" db:"status"`
+	DecidedBy    string `json:"decided_by,omitempty" db:"decided_by"`
+	DecidedRole  string `json:"decided_role,omitempty" db:"decided_role"`
+	DecisionNote string `json:"decision_note,omitempty" db:"decision_note"`
+
+	// Result is the executor output (e.g. the ledger id); Error holds the execution failure.
+	Result string `json:"result,omitempty" db:"result"`
+	Error  string `json:"error,omitempty" db:"error"`
+
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	DecidedAt *time.Time `json:"decided_at,omitempty" db:"decided_at"`
+}
+
+// SubmitRequest is the input for queueing an action.
+type SubmitRequest struct {
+	Action      ActionType `json:"action"`
+	Payload     string     `json:"payload"`
+	AmountMinor int64      `json:"amount_minor,omitempty"`
+	Currency    string     `json:"currency,omitempty"`
+	Reason      string     `json:"reason"`
+}
@@ -0,0 +1,86 @@
+package approvals
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryRepo is a simple in-memory Repository for tests and early development.
+// It is not intended for production use.
+type MemoryRepo struct {
+	mu       sync.Mutex
+	requests map[string]Request
+}
+
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{requests: map[string]Request{}}
+}
+
+func (r *MemoryRepo) Create(ctx context.Context, req Request) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.requests[req.ID]; ok {
+		return ErrInvalidArgument
+	}
+	r.requests[req.ID] = req
+	return nil
+}
+
+func (r *MemoryRepo) Get(ctx context.Context, workspaceID, id string) (Request, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	req, ok := r.requests[id]
+	if !ok || req.WorkspaceID != workspaceID {
+		return Request{}, false, nil
+	}
+	return req, true, nil
+}
+
+func (r *MemoryRepo) UpdateIfPending(ctx context.Context, req Request) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cur, ok := r.requests[req.ID]
+	if !ok || cur.WorkspaceID != req.WorkspaceID || cur.Status != StatusPending {
+		return false, nil
+	}
+	r.requests[req.ID] = req
+	return true, nil
+}
+
+func (r *MemoryRepo) Update(ctx context.Context, req Request) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cur, ok := r.requests[req.ID]
+	if !ok || cur.WorkspaceID != req.WorkspaceID {
+		return ErrNotFound
+	}
+	r.requests[req.ID] = req
+	return nil
+}
+
+func (r *MemoryRepo) ListPending(ctx context.Context, workspaceID string) ([]Request, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Request, 0)
+	for _, req := range r.requests {
+		if req.WorkspaceID == workspaceID && req.Status == StatusPending {
+			out = append(out, req)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (r *MemoryRepo) ListExpired(ctx context.Context, now time.Time) ([]Request, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Request, 0)
+	for _, req := range r.requests {
+		if req.Status == StatusPending && !now.Before(req.ExpiresAt) {
+			out = append(out, req)
+		}
+	}
+	return out, nil
+}
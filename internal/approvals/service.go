@@ -0,0 +1,264 @@
+package approvals
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"telecom-platform/internal/audit"
+
+	"github.com/google/uuid"
+)
+
+// Service implements the four-eyes approval queue for high-risk admin actions.
+//
+// Flow:
+// - An admin submits an action; it is stored as pending with an expiry.
+// - A different admin approves (the action executes immediately) or rejects it.
+// - Pending requests past ExpiresAt can no longer be approved and are swept to expired.
+// - Every transition is audited with both actors.
+type Service struct {
+	repo  Repository
+	audit *audit.Service
+	clock func() time.Time
+
+	// TTL is how long a request stays approvable.
+	TTL time.Duration
+	// ManualCreditThresholdMinor: manual credits at or above this amount need approval.
+	ManualCreditThresholdMinor int64
+
+	executors map[ActionType]Executor
+}
+
+// Executor performs an approved action and returns a short result (e.g. a created id).
+type Executor func(ctx context.Context, r Request) (string, error)
+
+// Repository persists approval requests. Implementations must enforce workspace filtering.
+type Repository interface {
+	Create(ctx context.Context, r Request) error
+	Get(ctx context.Context, workspaceID, id string) (Request, bool, error)
+	// UpdateIfPending stores r only if the stored row is still pending (guards double approval).
+	UpdateIfPending(ctx context.Context, r Request) (bool, error)
+	Update(ctx context.Context, r Request) error
+	ListPending(ctx context.Context, workspaceID string) ([]Request, error)
+	ListExpired(ctx context.Context, now time.Time) ([]Request, error)
+}
+
+func NewService(repo Repository, auditSvc *audit.Service) *Service {
+	return &Service{
+		repo:                       repo,
+		audit:                      auditSvc,
+		clock:                      time.Now,
+		TTL:                        24 * time.Hour,
+		ManualCreditThresholdMinor: 100_000,
+		executors:                  map[ActionType]Executor{},
+	}
+}
+
+var (
+	ErrInvalidArgument = errors.New("approvals: invalid argument")
+	ErrNotFound        = errors.New("approvals: not found")
+	ErrNotPending      = errors.New("approvals: request is not pending")
+	ErrExpired         = errors.New("approvals: request expired")
+	ErrSelfApproval    = errors.New("approvals: requester cannot decide their own request")
+	ErrNoExecutor      = errors.New("approvals: no executor registered for action")
+)
+
+// Register sets the executor for an action type.
+func (s *Service) Register(action ActionType, exec Executor) {
+	s.executors[action] = exec
+}
+
+// RequiresApproval reports whether an action must go through the queue.
+// Freezes always do; manual credits only above the threshold.
+func (s *Service) RequiresApproval(action ActionType, amountMinor int64) bool {
+	switch action {
+	case ActionManualCredit:
+		return amountMinor >= s.ManualCreditThresholdMinor
+	case ActionWalletFreeze:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Service) Submit(ctx context.Context, workspaceID, actorUserID, actorRole string, req SubmitRequest) (Request, error) {
+	if workspaceID == "" || actorUserID == "" || actorRole == "" {
+		return Request{}, ErrInvalidArgument
+	}
+	if req.Action == "" || req.Reason == "" {
+		return Request{}, ErrInvalidArgument
+	}
+	if _, ok := s.executors[req.Action]; !ok {
+		return Request{}, ErrNoExecutor
+	}
+
+	now := s.clock().UTC()
+	r := Request{
+		ID:            uuid.NewString(),
+		WorkspaceID:   workspaceID,
+		Action:        req.Action,
+		Payload:       req.Payload,
+		AmountMinor:   req.AmountMinor,
+		Currency:      req.Currency,
+		Reason:        req.Reason,
+		RequestedBy:   actorUserID,
+		RequestedRole: actorRole,
+		Status:        StatusPending,
+		ExpiresAt:     now.Add(s.TTL),
+		CreatedAt:     now,
+	}
+	if err := s.repo.Create(ctx, r); err != nil {
+		return Request{}, err
+	}
+	s.log(ctx, r, actorUserID, actorRole, "approval requested: "+string(r.Action))
+	return r, nil
+}
+
+func (s *Service) Get(ctx context.Context, workspaceID, id string) (Request, error) {
+	r, ok, err := s.repo.Get(ctx, workspaceID, id)
+	if err != nil {
+		return Request{}, err
+	}
+	if !ok {
+		return Request{}, ErrNotFound
+	}
+	return r, nil
+}
+
+func (s *Service) ListPending(ctx context.Context, workspaceID string) ([]Request, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	return s.repo.ListPending(ctx, workspaceID)
+}
+
+// Approve records the second admin's approval and executes the action.
+// The returned request carries the execution result or error.
+func (s *Service) Approve(ctx context.Context, workspaceID, id, actorUserID, actorRole, note string) (Request, error) {
+	r, err := s.decidable(ctx, workspaceID, id, actorUserID, actorRole)
+	if err != nil {
+		return Request{}, err
+	}
+	exec, ok := s.executors[r.Action]
+	if !ok {
+		return Request{}, ErrNoExecutor
+	}
+
+	now := s.clock().UTC()
+	r.DecidedBy = actorUserID
+	r.DecidedRole = actorRole
+	r.DecisionNote = note
+	r.DecidedAt = &now
+
+	// Claim the request before executing so two approvers cannot both run it.
+	r.Status = StatusApproved
+	if ok, err := s.repo.UpdateIfPending(ctx, r); err != nil {
+		return Request{}, err
+	} else if !ok {
+		return Request{}, ErrNotPending
+	}
+
+	result, execErr := exec(ctx, r)
+	if execErr != nil {
+		r.Status = StatusFailed
+		r.Error = execErr.Error()
+	} else {
+		r.Result = result
+	}
+	if err := s.repo.Update(ctx, r); err != nil {
+		return Request{}, err
+	}
+
+	msg := fmt.Sprintf("approval granted: %s (requested by %s)", r.Action, r.RequestedBy)
+	if execErr != nil {
+		msg = fmt.Sprintf("approval granted but execution failed: %s (requested by %s)", r.Action, r.RequestedBy)
+	}
+	s.log(ctx, r, actorUserID, actorRole, msg)
+	return r, nil
+}
+
+func (s *Service) Reject(ctx context.Context, workspaceID, id, actorUserID, actorRole, note string) (Request, error) {
+	r, err := s.decidable(ctx, workspaceID, id, actorUserID, actorRole)
+	if err != nil {
+		return Request{}, err
+	}
+	now := s.clock().UTC()
+	r.Status = StatusRejected
+	r.DecidedBy = actorUserID
+	r.DecidedRole = actorRole
+	r.DecisionNote = note
+	r.DecidedAt = &now
+	if ok, err := s.repo.UpdateIfPending(ctx, r); err != nil {
+		return Request{}, err
+	} else if !ok {
+		return Request{}, ErrNotPending
+	}
+	s.log(ctx, r, actorUserID, actorRole, fmt.Sprintf("approval rejected: %s (requested by %s)", r.Action, r.RequestedBy))
+	return r, nil
+}
+
+// ExpirePending marks pending requests past their expiry as expired. Returns the count.
+func (s *Service) ExpirePending(ctx context.Context) (int, error) {
+	now := s.clock().UTC()
+	due, err := s.repo.ListExpired(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, r := range due {
+		r.Status = StatusExpired
+		r.DecidedAt = &now
+		ok, err := s.repo.UpdateIfPending(ctx, r)
+		if err != nil {
+			return n, err
+		}
+		if ok {
+			n++
+			s.log(ctx, r, "system", "", "approval expired: "+string(r.Action))
+		}
+	}
+	return n, nil
+}
+
+func (s *Service) decidable(ctx context.Context, workspaceID, id, actorUserID, actorRole string) (Request, error) {
+	if workspaceID == "" || id == "" || actorUserID == "" || actorRole == "" {
+		return Request{}, ErrInvalidArgument
+	}
+	r, err := s.Get(ctx, workspaceID, id)
+	if err != nil {
+		return Request{}, err
+	}
+	if r.Status != StatusPending {
+		return Request{}, ErrNotPending
+	}
+	if !s.clock().Before(r.ExpiresAt) {
+		return Request{}, ErrExpired
+	}
+	if r.RequestedBy == actorUserID {
+		return Request{}, ErrSelfApproval
+	}
+	return r, nil
+}
+
+func (s *Service) log(ctx context.Context, r Request, actorUserID, actorRole, message string) {
+	if s.audit == nil {
+		return
+	}
+	meta, _ := json.Marshal(map[string]string{
+		"approval_id":  r.ID,
+		"requested_by": r.RequestedBy,
+		"decided_by":   r.DecidedBy,
+		"status":       string(r.Status),
+	})
+	_ = s.audit.Append(ctx, audit.Event{
+		WorkspaceID: r.WorkspaceID,
+		Type:        audit.EventTypeApproval,
+		ActorUserID: actorUserID,
+		ActorRole:   actorRole,
+		Message:     message,
+		Metadata:    string(meta),
+	})
+}
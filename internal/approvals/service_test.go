@@ -0,0 +1,71 @@
+package approvals
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/audit"
+)
+
+func TestService_FourEyesApproveExecutesOnce(t *testing.T) {
+	ctx := context.Background()
+	auditRepo := audit.NewMemoryRepo()
+	svc := NewService(NewMemoryRepo(), audit.NewService(auditRepo))
+
+	runs := 0
+	svc.Register(ActionWalletFreeze, func(ctx context.Context, r Request) (string, error) {
+		runs++
+		return "frozen", nil
+	})
+
+	r, err := svc.Submit(ctx, "w", "alice", "owner", SubmitRequest{Action: ActionWalletFreeze, Payload: `{"wallet_id":"x"}`, Reason: "fraud"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if _, err := svc.Approve(ctx, "w", r.ID, "alice", "owner", ""); err != ErrSelfApproval {
+		t.Fatalf("expected ErrSelfApproval, got %v", err)
+	}
+
+	got, err := svc.Approve(ctx, "w", r.ID, "bob", "super_admin", "ok")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got.Status != StatusApproved || got.Result != "frozen" || runs != 1 {
+		t.Fatalf("expected executed once, got %+v runs=%d", got, runs)
+	}
+	if _, err := svc.Approve(ctx, "w", r.ID, "carol", "owner", ""); err != ErrNotPending {
+		t.Fatalf("expected ErrNotPending, got %v", err)
+	}
+
+	evs := auditRepo.Events()
+	if len(evs) != 2 || evs[0].ActorUserID != "alice" || evs[1].ActorUserID != "bob" {
+		t.Fatalf("expected both actors audited, got %+v", evs)
+	}
+	if strings.Contains(evs[0].Metadata, "requested_role") {
+		t.Fatalf("expected requester role kept out of audit metadata, got %s", evs[0].Metadata)
+	}
+}
+
+func TestService_ExpiredRequestsCannotBeApproved(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0).UTC()
+	svc := NewService(NewMemoryRepo(), nil)
+	svc.clock = func() time.Time { return now }
+	svc.TTL = time.Hour
+	svc.Register(ActionManualCredit, func(ctx context.Context, r Request) (string, error) { return "", nil })
+
+	if svc.RequiresApproval(ActionManualCredit, svc.ManualCreditThresholdMinor-1) {
+		t.Fatalf("expected small credit to bypass approval")
+	}
+
+	r, _ := svc.Submit(ctx, "w", "alice", "owner", SubmitRequest{Action: ActionManualCredit, AmountMinor: 500000, Reason: "goodwill"})
+	now = now.Add(2 * time.Hour)
+	if _, err := svc.Approve(ctx, "w", r.ID, "bob", "owner", ""); err != ErrExpired {
+		t.Fatalf("expected ErrExpired, got %v", err)
+	}
+	if n, err := svc.ExpirePending(ctx); err != nil || n != 1 {
+		t.Fatalf("expected 1 expired, got %d (%v)", n, err)
+	}
+}
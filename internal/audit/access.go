@@ -0,0 +1,132 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"sync"
+)
+
+// Read-access auditing.
+//
+// Mutations are audited unconditionally by the services that perform them. Reads
+// of sensitive data (recording playback, transcripts, ledger exports) are audited
+// separately as EventTypeDataAccess so SOC2 reviews can answer "who looked at what
+// and when" without mixing reads into the change history.
+//
+// Read volume can be far higher than write volume, so logging is opt-in per
+// resource with a sample rate:
+// - No policy (or SampleRate 0) for a resource: reads are not logged.
+// - SampleRate 1: every read is logged.
+// - In between: reads are logged with that probability; the rate is stored on the
+//   event so counts can be scaled back up.
+// Roles in AlwaysLogRoles (e.g. super_admin, support) bypass sampling.
+
+// AccessResource names a class of sensitive data.
+type AccessResource string
+
+const (
+	AccessRecording    AccessResource = "recording"
+	AccessTranscript   AccessResource = "transcript"
+	AccessLedgerExport AccessResource = "ledger_export"
+)
+
+// AccessPolicy controls logging for one resource.
+type AccessPolicy struct {
+	// SampleRate is the fraction of reads logged, in [0, 1].
+	SampleRate float64
+}
+
+// Access describes one read.
+type Access struct {
+	WorkspaceID string
+	ActorUserID string
+	ActorRole   string
+	IPAddress   string
+
+	Resource   AccessResource
+	ResourceID string
+	// Action is what was done with the data, e.g. "play", "download", "list".
+	Action string
+
+	// Optional targets, copied onto the event for filtering.
+	CallID   string
+	WalletID string
+}
+
+// AccessLogger records sampled read-access events. A nil *AccessLogger logs nothing.
+type AccessLogger struct {
+	audit *Service
+
+	Policies       map[AccessResource]AccessPolicy
+	AlwaysLogRoles []string
+
+	mu   sync.Mutex
+	rand func() float64
+}
+
+func NewAccessLogger(svc *Service, policies map[AccessResource]AccessPolicy) *AccessLogger {
+	return &AccessLogger{audit: svc, Policies: policies, rand: rand.Float64}
+}
+
+// LogAccess records a as an EventTypeDataAccess event when the resource's policy
+// selects it. It reports whether an event was written. Like all audit calls it is
+// best-effort: callers should not fail the read when it returns an error.
+func (l *AccessLogger) LogAccess(ctx context.Context, a Access) (bool, error) {
+	if l == nil || l.audit == nil || a.WorkspaceID == "" || a.Resource == "" {
+		return false, nil
+	}
+	rate, forced := l.sampleRate(a)
+	if rate <= 0 {
+		return false, nil
+	}
+	if !forced && rate < 1 && l.sample() >= rate {
+		return false, nil
+	}
+	if forced {
+		rate = 1
+	}
+
+	md, _ := json.Marshal(map[string]any{
+		"resource":    a.Resource,
+		"resource_id": a.ResourceID,
+		"action":      a.Action,
+		"sample_rate": rate,
+	})
+	err := l.audit.Append(ctx, Event{
+		WorkspaceID: a.WorkspaceID,
+		Type:        EventTypeDataAccess,
+		ActorUserID: a.ActorUserID,
+		ActorRole:   a.ActorRole,
+		IPAddress:   a.IPAddress,
+		CallID:      a.CallID,
+		WalletID:    a.WalletID,
+		Message:     a.Action + " " + string(a.Resource),
+		Metadata:    string(md),
+	})
+	return err == nil, err
+}
+
+// sampleRate returns the resource's rate and whether the actor bypasses sampling.
+// A forced actor is still only logged when the resource has a policy.
+func (l *AccessLogger) sampleRate(a Access) (float64, bool) {
+	p, ok := l.Policies[a.Resource]
+	if !ok || p.SampleRate <= 0 {
+		return 0, false
+	}
+	for _, r := range l.AlwaysLogRoles {
+		if r != "" && r == a.ActorRole {
+			return 1, true
+		}
+	}
+	return p.SampleRate, false
+}
+
+func (l *AccessLogger) sample() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rand == nil {
+		l.rand = rand.Float64
+	}
+	return l.rand()
+}
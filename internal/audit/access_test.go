@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestAccessLogger_PolicyAndSampling(t *testing.T) {
+	repo := NewMemoryRepo()
+	l := NewAccessLogger(NewService(repo), map[AccessResource]AccessPolicy{
+		AccessRecording:  {SampleRate: 1},
+		AccessTranscript: {SampleRate: 0.25},
+	})
+	l.AlwaysLogRoles = []string{"super_admin"}
+	draws := []float64{0.5, 0.1}
+	l.rand = func() float64 { d := draws[0]; draws = draws[1:]; return d }
+	ctx := context.Background()
+
+	if ok, err := l.LogAccess(ctx, Access{WorkspaceID: "ws", ActorUserID: "u1", Resource: AccessRecording, ResourceID: "r1", Action: "play", CallID: "c1"}); !ok || err != nil {
+		t.Fatalf("expected recording read logged, got %v %v", ok, err)
+	}
+	if ok, _ := l.LogAccess(ctx, Access{WorkspaceID: "ws", Resource: AccessLedgerExport, Action: "list"}); ok {
+		t.Fatalf("resource without policy must not be logged")
+	}
+	if ok, _ := l.LogAccess(ctx, Access{WorkspaceID: "ws", Resource: AccessTranscript, Action: "view"}); ok {
+		t.Fatalf("draw 0.5 must be sampled out at rate 0.25")
+	}
+	if ok, _ := l.LogAccess(ctx, Access{WorkspaceID: "ws", Resource: AccessTranscript, Action: "view"}); !ok {
+		t.Fatalf("draw 0.1 must be sampled in at rate 0.25")
+	}
+	// Always-logged roles skip the draw (draws is empty: a draw would panic).
+	if ok, _ := l.LogAccess(ctx, Access{WorkspaceID: "ws", ActorRole: "super_admin", Resource: AccessTranscript, Action: "view"}); !ok {
+		t.Fatalf("super_admin reads must always be logged")
+	}
+
+	events := repo.Events()
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	e := events[0]
+	if e.Type != EventTypeDataAccess || e.ActorUserID != "u1" || e.CallID != "c1" || e.Message != "play recording" {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	var md map[string]any
+	if err := json.Unmarshal([]byte(events[1].Metadata), &md); err != nil {
+		t.Fatal(err)
+	}
+	if md["sample_rate"] != 0.25 || md["resource"] != "transcript" {
+		t.Fatalf("unexpected metadata: %v", md)
+	}
+	if err := json.Unmarshal([]byte(events[2].Metadata), &md); err != nil || md["sample_rate"] != 1.0 {
+		t.Fatalf("forced read should record sample_rate 1, got %v", md)
+	}
+
+	var nilLogger *AccessLogger
+	if ok, err := nilLogger.LogAccess(ctx, Access{WorkspaceID: "ws", Resource: AccessRecording}); ok || err != nil {
+		t.Fatalf("nil logger must be a no-op")
+	}
+}
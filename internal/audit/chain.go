@@ -0,0 +1,116 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"telecom-platform/pkg/utils"
+)
+
+// Tamper evidence.
+//
+// Each workspace's audit events form a hash chain: event N stores the hash of
+// event N-1 (PrevHash) and Hash = ChainHash(PrevHash, contents). Any retroactive
+// UPDATE, DELETE or INSERT breaks the chain from that point on.
+//
+// The repository links events on Append (it must read the chain head and insert
+// atomically, e.g. under a per-workspace advisory lock in Postgres).
+
+// ChainReader is implemented by repositories that can return a workspace chain in seq order.
+type ChainReader interface {
+	ListChain(ctx context.Context, workspaceID string) ([]Event, error)
+}
+
+// ChainVerification is the outcome of a verification run.
+type ChainVerification struct {
+	WorkspaceID string `json:"workspace_id"`
+	Checked     int    `json:"checked"`
+	Valid       bool   `json:"valid"`
+	// BrokenAtSeq is the first seq whose link or hash does not match (0 when valid).
+	BrokenAtSeq int64     `json:"broken_at_seq,omitempty"`
+	BrokenID    string    `json:"broken_id,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	VerifiedAt  time.Time `json:"verified_at"`
+}
+
+var ErrChainUnsupported = errors.New("audit: repository does not support chain verification")
+
+// EventHash computes the chain hash for e given the previous hash.
+// Field order is part of the stored format; do not reorder.
+func EventHash(prev string, e Event) string {
+	return utils.ChainHash(prev,
+		e.ID,
+		e.WorkspaceID,
+		string(e.Type),
+		e.ActorUserID,
+		e.ActorRole,
+		e.IPAddress,
+		e.WalletID,
+		e.CampaignID,
+		e.CallID,
+		e.OverrideID,
+		e.Message,
+		e.Metadata,
+		e.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+		strconv.FormatInt(e.ChainSeq, 10),
+	)
+}
+
+// LinkEvent sets chain fields on e so it follows head (the zero Event for an empty chain).
+// CreatedAt is truncated to the microsecond Postgres stores, so the hash still
+// matches once the row is read back.
+func LinkEvent(head Event, e Event) Event {
+	e.CreatedAt = e.CreatedAt.Truncate(time.Microsecond)
+	e.ChainSeq = head.ChainSeq + 1
+	e.PrevHash = head.Hash
+	e.Hash = EventHash(e.PrevHash, e)
+	return e
+}
+
+// VerifyEvents checks a workspace chain given in seq order.
+func VerifyEvents(events []Event) ChainVerification {
+	out := ChainVerification{Valid: true}
+	prev := Event{}
+	for _, e := range events {
+		out.Checked++
+		reason := ""
+		switch {
+		case e.ChainSeq != prev.ChainSeq+1:
+			reason = "sequence gap"
+		case e.PrevHash != prev.Hash:
+			reason = "previous hash mismatch"
+		case e.Hash != EventHash(e.PrevHash, e):
+			reason = "row contents modified"
+		}
+		if reason != "" {
+			out.Valid = false
+			out.BrokenAtSeq = e.ChainSeq
+			out.BrokenID = e.ID
+			out.Reason = reason
+			return out
+		}
+		prev = e
+	}
+	return out
+}
+
+// VerifyChain re-hashes a workspace's audit chain.
+func (s *Service) VerifyChain(ctx context.Context, workspaceID string) (ChainVerification, error) {
+	if workspaceID == "" {
+		return ChainVerification{}, ErrInvalidEvent
+	}
+	reader, ok := s.repo.(ChainReader)
+	if !ok {
+		return ChainVerification{}, ErrChainUnsupported
+	}
+	events, err := reader.ListChain(ctx, workspaceID)
+	if err != nil {
+		return ChainVerification{}, err
+	}
+	out := VerifyEvents(events)
+	out.WorkspaceID = workspaceID
+	out.VerifiedAt = s.clock().UTC()
+	return out, nil
+}
@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"telecom-platform/pkg/logger"
+)
+
+// WorkspaceLister returns the workspaces whose chains should be verified.
+type WorkspaceLister interface {
+	ListWorkspaceIDs(ctx context.Context) ([]string, error)
+}
+
+// ChainCheck verifies an additional chain (e.g. wallet ledgers) for a workspace.
+// It returns a non-empty reason when tampering is detected.
+type ChainCheck func(ctx context.Context, workspaceID string) (reason string, err error)
+
+// ChainVerifier periodically re-hashes audit chains (and any extra chains) and
+// records an admin_action audit event when a break is found.
+type ChainVerifier struct {
+	Audit      *Service
+	Workspaces WorkspaceLister
+	Extra      []ChainCheck
+}
+
+// RunOnce verifies every workspace once and returns the number of broken chains.
+func (v ChainVerifier) RunOnce(ctx context.Context) (int, error) {
+	ids, err := v.Workspaces.ListWorkspaceIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	log := logger.From(ctx)
+	broken := 0
+	for _, workspaceID := range ids {
+		res, err := v.Audit.VerifyChain(ctx, workspaceID)
+		if err != nil {
+			return broken, err
+		}
+		if !res.Valid {
+			broken++
+			log.Error("audit chain broken", "workspace_id", workspaceID, "seq", res.BrokenAtSeq, "reason", res.Reason)
+			_ = v.Audit.LogAdminAction(ctx, workspaceID, "system", "", "", "audit chain verification failed: "+res.Reason, "", "")
+		}
+		for _, check := range v.Extra {
+			reason, err := check(ctx, workspaceID)
+			if err != nil {
+				return broken, err
+			}
+			if reason != "" {
+				broken++
+				log.Error("chain verification failed", "workspace_id", workspaceID, "reason", reason)
+				_ = v.Audit.LogAdminAction(ctx, workspaceID, "system", "", "", "chain verification failed: "+reason, "", "")
+			}
+		}
+	}
+	return broken, nil
+}
+
+// Run verifies on every tick until ctx is canceled.
+func (v ChainVerifier) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := v.RunOnce(ctx); err != nil {
+				logger.From(ctx).Error("chain verification run failed", "err", err)
+			}
+		}
+	}
+}
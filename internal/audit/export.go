@@ -0,0 +1,424 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Tenant SIEM export.
+//
+// Workspaces may opt in to receiving a copy of their audit events at an HTTPS
+// endpoint (Splunk HEC, Datadog, a custom collector) or an S3 bucket.
+//
+// Rules:
+// - Opt-in per workspace; nothing is exported without an enabled ExportConfig.
+// - Internal-only events (routing overrides) are never exported.
+// - Exported records use ExportRecord (schema_version 1), not Event; actor_role is
+//   omitted because it may contain hidden roles.
+// - Export never blocks Append: events go through a bounded queue and are dropped
+//   (and counted) when the queue is full.
+// - Sink failures are retried with exponential backoff up to MaxAttempts.
+
+// ExportSchemaVersion is the version of the ExportRecord JSON schema.
+const ExportSchemaVersion = 1
+
+type SinkKind string
+
+const (
+	SinkWebhook SinkKind = "webhook"
+	SinkS3      SinkKind = "s3"
+)
+
+// ExportConfig is the per-workspace SIEM export setting.
+type ExportConfig struct {
+	WorkspaceID string   `json:"workspace_id" db:"workspace_id"`
+	Enabled     bool     `json:"enabled" db:"enabled"`
+	Sink        SinkKind `json:"sink" db:"sink"`
+
+	// Webhook sink: HTTPS URL; payloads are signed with HMAC-SHA256 using Secret.
+	EndpointURL string `json:"endpoint_url,omitempty" db:"endpoint_url"`
+	Secret      string `json:"-" db:"secret"`
+
+	// S3 sink.
+	Bucket string `json:"bucket,omitempty" db:"bucket"`
+	Prefix string `json:"prefix,omitempty" db:"prefix"`
+
+	// EventTypes selects which types to export; empty means all exportable types.
+	EventTypes []EventType `json:"event_types,omitempty" db:"event_types"`
+
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ExportRecord is the documented JSON schema delivered to tenant sinks.
+//
+//	{
+//	  "schema_version": 1,
+//	  "id": "uuid",
+//	  "workspace_id": "uuid",
+//	  "type": "admin_action",
+//	  "actor_user_id": "uuid",
+//	  "ip_address": "203.0.113.7",
+//	  "wallet_id": "", "campaign_id": "", "call_id": "",
+//	  "message": "…",
+//	  "metadata": "{…}",
+//	  "created_at": "2024-01-01T00:00:00Z"
+//	}
+type ExportRecord struct {
+	SchemaVersion int       `json:"schema_version"`
+	ID            string    `json:"id"`
+	WorkspaceID   string    `json:"workspace_id"`
+	Type          EventType `json:"type"`
+	ActorUserID   string    `json:"actor_user_id,omitempty"`
+	IPAddress     string    `json:"ip_address,omitempty"`
+	WalletID      string    `json:"wallet_id,omitempty"`
+	CampaignID    string    `json:"campaign_id,omitempty"`
+	CallID        string    `json:"call_id,omitempty"`
+	Message       string    `json:"message,omitempty"`
+	Metadata      string    `json:"metadata,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ExportConfigStore persists per-workspace export settings.
+type ExportConfigStore interface {
+	GetExportConfig(ctx context.Context, workspaceID string) (ExportConfig, bool, error)
+	SetExportConfig(ctx context.Context, cfg ExportConfig) error
+}
+
+// Sink delivers a batch of records for one workspace.
+type Sink interface {
+	Send(ctx context.Context, cfg ExportConfig, records []ExportRecord) error
+}
+
+var ErrInvalidExportConfig = errors.New("audit: invalid export config")
+
+// exportableTypes are the event types a tenant may receive in its SIEM. It is
+// an allowlist: platform-side events (admin actions, overrides, call monitoring,
+// NOC controls) and any type added later stay internal until listed here.
+var exportableTypes = map[EventType]bool{
+	EventTypeRoutingSchedule: true,
+	EventTypeServiceToken:    true,
+	EventTypeProvisioning:    true,
+	EventTypeApproval:        true,
+	EventTypeLegalHold:       true,
+	EventTypeDataAccess:      true,
+	EventTypeAccessDenied:    true,
+}
+
+// IsExportable reports whether an event type may leave the platform.
+func IsExportable(t EventType) bool {
+	return exportableTypes[t]
+}
+
+// ValidateExportConfig checks a config before it is stored.
+func ValidateExportConfig(cfg ExportConfig) error {
+	if cfg.WorkspaceID == "" {
+		return ErrInvalidExportConfig
+	}
+	for _, t := range cfg.EventTypes {
+		if !IsExportable(t) {
+			return ErrInvalidExportConfig
+		}
+	}
+	if !cfg.Enabled {
+		return nil
+	}
+	switch cfg.Sink {
+	case SinkWebhook:
+		u, err := url.Parse(cfg.EndpointURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return ErrInvalidExportConfig
+		}
+		if cfg.Secret == "" {
+			return ErrInvalidExportConfig
+		}
+	case SinkS3:
+		if cfg.Bucket == "" {
+			return ErrInvalidExportConfig
+		}
+	default:
+		return ErrInvalidExportConfig
+	}
+	return nil
+}
+
+func (cfg ExportConfig) wants(t EventType) bool {
+	if !IsExportable(t) {
+		return false
+	}
+	if len(cfg.EventTypes) == 0 {
+		return true
+	}
+	for _, x := range cfg.EventTypes {
+		if x == t {
+			return true
+		}
+	}
+	return false
+}
+
+func toExportRecord(e Event) ExportRecord {
+	return ExportRecord{
+		SchemaVersion: ExportSchemaVersion,
+		ID:            e.ID,
+		WorkspaceID:   e.WorkspaceID,
+		Type:          e.Type,
+		ActorUserID:   e.ActorUserID,
+		IPAddress:     e.IPAddress,
+		WalletID:      e.WalletID,
+		CampaignID:    e.CampaignID,
+		CallID:        e.CallID,
+		Message:       e.Message,
+		Metadata:      e.Metadata,
+		CreatedAt:     e.CreatedAt.UTC(),
+	}
+}
+
+// Exporter fans audit events out to tenant sinks.
+type Exporter struct {
+	Configs ExportConfigStore
+	Sinks   map[SinkKind]Sink
+
+	BatchSize     int
+	FlushInterval time.Duration
+	MaxAttempts   int
+	// Backoff is the first retry delay; it doubles on each attempt.
+	Backoff time.Duration
+
+	queue   chan Event
+	dropped atomic.Int64
+}
+
+func NewExporter(configs ExportConfigStore, sinks map[SinkKind]Sink, queueSize int) *Exporter {
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+	return &Exporter{
+		Configs:       configs,
+		Sinks:         sinks,
+		BatchSize:     100,
+		FlushInterval: 5 * time.Second,
+		MaxAttempts:   5,
+		Backoff:       500 * time.Millisecond,
+		queue:         make(chan Event, queueSize),
+	}
+}
+
+// Enqueue offers an event for export without blocking.
+// It returns false (and counts a drop) when the queue is full.
+func (x *Exporter) Enqueue(e Event) bool {
+	if !IsExportable(e.Type) {
+		return true
+	}
+	select {
+	case x.queue <- e:
+		return true
+	default:
+		x.dropped.Add(1)
+		return false
+	}
+}
+
+// Dropped returns the number of events dropped due to backpressure.
+func (x *Exporter) Dropped() int64 { return x.dropped.Load() }
+
+// Run drains the queue until ctx is canceled, flushing on BatchSize or FlushInterval.
+func (x *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(x.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, x.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		_ = x.Flush(ctx, batch)
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-x.queue:
+			batch = append(batch, e)
+			if len(batch) >= x.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// Flush delivers events grouped by workspace. Workspaces without an enabled
+// config are skipped. The first delivery error is returned after all
+// workspaces have been attempted.
+func (x *Exporter) Flush(ctx context.Context, events []Event) error {
+	byWorkspace := map[string][]Event{}
+	order := make([]string, 0)
+	for _, e := range events {
+		if _, ok := byWorkspace[e.WorkspaceID]; !ok {
+			order = append(order, e.WorkspaceID)
+		}
+		byWorkspace[e.WorkspaceID] = append(byWorkspace[e.WorkspaceID], e)
+	}
+
+	var firstErr error
+	for _, workspaceID := range order {
+		cfg, ok, err := x.Configs.GetExportConfig(ctx, workspaceID)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if !ok || !cfg.Enabled {
+			continue
+		}
+		sink, ok := x.Sinks[cfg.Sink]
+		if !ok {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("audit: no sink for %q", cfg.Sink)
+			}
+			continue
+		}
+
+		records := make([]ExportRecord, 0, len(byWorkspace[workspaceID]))
+		for _, e := range byWorkspace[workspaceID] {
+			if cfg.wants(e.Type) {
+				records = append(records, toExportRecord(e))
+			}
+		}
+		if len(records) == 0 {
+			continue
+		}
+		if err := x.sendWithRetry(ctx, sink, cfg, records); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (x *Exporter) sendWithRetry(ctx context.Context, sink Sink, cfg ExportConfig, records []ExportRecord) error {
+	attempts := x.MaxAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+	delay := x.Backoff
+	var err error
+	for i := 0; i < attempts; i++ {
+		if err = sink.Send(ctx, cfg, records); err == nil {
+			return nil
+		}
+		if i == attempts-1 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	return err
+}
+
+// ExportingRepo wraps a Repository and offers each appended event to an Exporter.
+type ExportingRepo struct {
+	Repository
+	Exporter *Exporter
+}
+
+func (r ExportingRepo) Append(ctx context.Context, e Event) error {
+	if err := r.Repository.Append(ctx, e); err != nil {
+		return err
+	}
+	if r.Exporter != nil {
+		r.Exporter.Enqueue(e)
+	}
+	return nil
+}
+
+// ListChain passes chain reads through so verification works on a wrapped repository.
+func (r ExportingRepo) ListChain(ctx context.Context, workspaceID string) ([]Event, error) {
+	reader, ok := r.Repository.(ChainReader)
+	if !ok {
+		return nil, ErrChainUnsupported
+	}
+	return reader.ListChain(ctx, workspaceID)
+}
+
+// WebhookSink POSTs batches as a JSON array.
+// The X-Audit-Signature header carries hex(HMAC-SHA256(secret, body)).
+type WebhookSink struct {
+	Client *http.Client
+}
+
+func (s WebhookSink) Send(ctx context.Context, cfg ExportConfig, records []ExportRecord) error {
+	body, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.EndpointURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, []byte(cfg.Secret))
+	mac.Write(body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Audit-Signature", hex.EncodeToString(mac.Sum(nil)))
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("audit: siem endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ObjectWriter is the minimal S3-compatible put used by S3Sink.
+type ObjectWriter interface {
+	PutObject(ctx context.Context, bucket, key string, body []byte, contentType string) error
+}
+
+// S3Sink writes each batch as a newline-delimited JSON object.
+// Keys: <prefix>/<workspace_id>/YYYY/MM/DD/<first_event_id>.ndjson
+type S3Sink struct {
+	Objects ObjectWriter
+}
+
+func (s S3Sink) Send(ctx context.Context, cfg ExportConfig, records []ExportRecord) error {
+	if s.Objects == nil {
+		return errors.New("audit: object writer not configured")
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	first := records[0]
+	key := fmt.Sprintf("%s/%s/%s.ndjson", cfg.WorkspaceID, first.CreatedAt.Format("2006/01/02"), first.ID)
+	if p := strings.Trim(cfg.Prefix, "/"); p != "" {
+		key = p + "/" + key
+	}
+	return s.Objects.PutObject(ctx, cfg.Bucket, key, buf.Bytes(), "application/x-ndjson")
+}
@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type flakySink struct {
+	failures int
+	calls    int
+	got      []ExportRecord
+}
+
+func (s *flakySink) Send(ctx context.Context, cfg ExportConfig, records []ExportRecord) error {
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("unavailable")
+	}
+	s.got = append(s.got, records...)
+	return nil
+}
+
+func TestExporter_FiltersInternalEventsAndRetries(t *testing.T) {
+	configs := NewMemoryExportConfigStore()
+	_ = configs.SetExportConfig(context.Background(), ExportConfig{WorkspaceID: "w", Enabled: true, Sink: SinkS3, Bucket: "b"})
+
+	sink := &flakySink{failures: 2}
+	x := NewExporter(configs, map[SinkKind]Sink{SinkS3: sink}, 10)
+	x.Backoff = 0
+
+	events := []Event{
+		{ID: "1", WorkspaceID: "w", Type: EventTypeApproval},
+		{ID: "2", WorkspaceID: "w", Type: EventTypeOverride},
+		{ID: "3", WorkspaceID: "not-opted-in", Type: EventTypeApproval},
+		{ID: "4", WorkspaceID: "w", Type: EventTypeAdminAction, ActorRole: "network_operator"},
+		{ID: "5", WorkspaceID: "w", Type: EventTypeCallMonitor},
+	}
+	if err := x.Flush(context.Background(), events); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if sink.calls != 3 {
+		t.Fatalf("expected 2 retries before success, got %d calls", sink.calls)
+	}
+	if len(sink.got) != 1 || sink.got[0].ID != "1" || sink.got[0].SchemaVersion != ExportSchemaVersion {
+		t.Fatalf("expected only the approval exported, got %+v", sink.got)
+	}
+}
+
+func TestExporter_EnqueueDropsWhenFull(t *testing.T) {
+	x := NewExporter(NewMemoryExportConfigStore(), nil, 1)
+	if !x.Enqueue(Event{WorkspaceID: "w", Type: EventTypeApproval}) {
+		t.Fatalf("expected first enqueue to succeed")
+	}
+	if x.Enqueue(Event{WorkspaceID: "w", Type: EventTypeApproval}) {
+		t.Fatalf("expected backpressure drop")
+	}
+	if x.Dropped() != 1 {
+		t.Fatalf("expected 1 dropped, got %d", x.Dropped())
+	}
+}
+
+func TestValidateExportConfig_RejectsPlainHTTPAndOverrides(t *testing.T) {
+	if err := ValidateExportConfig(ExportConfig{WorkspaceID: "w", Enabled: true, Sink: SinkWebhook, EndpointURL: "http://siem.example.com", Secret: "s"}); err == nil {
+		t.Fatalf("expected plain http rejected")
+	}
+	if err := ValidateExportConfig(ExportConfig{WorkspaceID: "w", EventTypes: []EventType{EventTypeOverride}}); err == nil {
+		t.Fatalf("expected override export rejected")
+	}
+	for _, typ := range []EventType{EventTypeAdminAction, EventTypeCallMonitor, EventTypeNOCAction, "new_internal_type"} {
+		if err := ValidateExportConfig(ExportConfig{WorkspaceID: "w", EventTypes: []EventType{typ}}); err == nil {
+			t.Fatalf("expected %s export rejected", typ)
+		}
+	}
+}
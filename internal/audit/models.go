@@ -13,6 +13,7 @@ import "time"
 // - Table audit_events with an INSERT-only policy.
 // - Optional: trigger to prevent UPDATE/DELETE.
 // - Optional: partition by time for retention.
+// - UNIQUE (workspace_id, chain_seq) so chain links cannot be duplicated or reordered.
 
 type Event struct {
 	ID          string   `json:"id" db:"id"`
@@ -43,11 +44,33 @@ type Event struct {
 	Metadata string `json:"metadata,omitempty" db:"metadata"`
 
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// Tamper evidence (see chain.go). Set by the repository on append.
+	// ChainSeq is per workspace, starting at 1; Hash covers PrevHash + row contents.
+	ChainSeq int64  `json:"chain_seq" db:"chain_seq"`
+	PrevHash string `json:"prev_hash,omitempty" db:"prev_hash"`
+	Hash     string `json:"hash" db:"hash"`
 }
 
 type EventType string
 
 const (
-	EventTypeAdminAction EventType = "admin_action"
-	EventTypeOverride    EventType = "routing_override"
+	EventTypeAdminAction     EventType = "admin_action"
+	EventTypeOverride        EventType = "routing_override"
+	EventTypeRoutingSchedule EventType = "routing_schedule"
+	EventTypeServiceToken    EventType = "service_token"
+	EventTypeProvisioning    EventType = "provisioning"
+	EventTypeApproval        EventType = "approval"
+	EventTypeLegalHold       EventType = "legal_hold"
+	// EventTypeDataAccess records reads of sensitive data (see access.go).
+	EventTypeDataAccess EventType = "data_access"
+	// EventTypeAccessDenied records privileged requests refused by network policy
+	// (e.g. the admin IP allowlist).
+	EventTypeAccessDenied EventType = "access_denied"
+	// EventTypeCallMonitor records supervisors joining, switching mode on and
+	// leaving live calls (listen/whisper/barge).
+	EventTypeCallMonitor EventType = "call_monitor"
+	// EventTypeNOCAction records network operator console controls (emergency
+	// stops, reroutes). Always logged under PlatformScope.
+	EventTypeNOCAction EventType = "noc_action"
 )
@@ -18,10 +18,28 @@ func NewMemoryRepo() *MemoryRepo { return &MemoryRepo{} }
 func (r *MemoryRepo) Append(ctx context.Context, e Event) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.events = append(r.events, e)
+	head := Event{}
+	for _, x := range r.events {
+		if x.WorkspaceID == e.WorkspaceID {
+			head = x
+		}
+	}
+	r.events = append(r.events, LinkEvent(head, e))
 	return nil
 }
 
+func (r *MemoryRepo) ListChain(ctx context.Context, workspaceID string) ([]Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Event, 0)
+	for _, e := range r.events {
+		if e.WorkspaceID == workspaceID {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
 func (r *MemoryRepo) Events() []Event {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -29,3 +47,27 @@ func (r *MemoryRepo) Events() []Event {
 	copy(out, r.events)
 	return out
 }
+
+// MemoryExportConfigStore is an in-memory ExportConfigStore for tests.
+type MemoryExportConfigStore struct {
+	mu      sync.Mutex
+	configs map[string]ExportConfig
+}
+
+func NewMemoryExportConfigStore() *MemoryExportConfigStore {
+	return &MemoryExportConfigStore{configs: map[string]ExportConfig{}}
+}
+
+func (s *MemoryExportConfigStore) GetExportConfig(ctx context.Context, workspaceID string) (ExportConfig, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg, ok := s.configs[workspaceID]
+	return cfg, ok, nil
+}
+
+func (s *MemoryExportConfigStore) SetExportConfig(ctx context.Context, cfg ExportConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configs[cfg.WorkspaceID] = cfg
+	return nil
+}
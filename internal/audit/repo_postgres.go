@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"telecom-platform/pkg/utils"
+)
+
+// PostgresRepo stores events in the audit_events table:
+//
+//	audit_events (id uuid PRIMARY KEY, workspace_id, type, actor_user_id, actor_role,
+//	  ip_address, wallet_id, campaign_id, call_id, override_id, message, metadata
+//	  text NOT NULL DEFAULT '', created_at timestamptz, chain_seq bigint,
+//	  prev_hash text NOT NULL DEFAULT '', hash text)
+//	UNIQUE (workspace_id, chain_seq)
+//
+// Append reads the workspace chain head and inserts under a per-workspace
+// advisory lock, so concurrent appends link in order.
+type PostgresRepo struct {
+	DB *sql.DB
+}
+
+var (
+	_ Repository  = PostgresRepo{}
+	_ ChainReader = PostgresRepo{}
+)
+
+// auditWriteBudget keeps best-effort audit writes from stalling the request.
+var auditWriteBudget = utils.QueryBudget{StatementTimeout: time.Second, SlowThreshold: 100 * time.Millisecond}
+
+func (r PostgresRepo) Append(ctx context.Context, e Event) error {
+	if r.DB == nil {
+		return errors.New("audit: db is nil")
+	}
+	ctx = utils.WithQuery(ctx, "audit", "Append", auditWriteBudget)
+	return utils.WithTx(ctx, r.DB, &sql.TxOptions{}, func(ctx context.Context, tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('audit:' || $1))`, e.WorkspaceID); err != nil {
+			return err
+		}
+		var head Event
+		err := tx.QueryRowContext(ctx, `
+SELECT chain_seq, hash FROM audit_events
+WHERE workspace_id = $1
+ORDER BY chain_seq DESC
+LIMIT 1`, e.WorkspaceID).Scan(&head.ChainSeq, &head.Hash)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		e = LinkEvent(head, e)
+		_, err = tx.ExecContext(ctx, `
+INSERT INTO audit_events (id, workspace_id, type, actor_user_id, actor_role, ip_address, wallet_id,
+                          campaign_id, call_id, override_id, message, metadata, created_at,
+                          chain_seq, prev_hash, hash)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`,
+			e.ID, e.WorkspaceID, string(e.Type), e.ActorUserID, e.ActorRole, e.IPAddress, e.WalletID,
+			e.CampaignID, e.CallID, e.OverrideID, e.Message, e.Metadata, e.CreatedAt,
+			e.ChainSeq, e.PrevHash, e.Hash)
+		return err
+	})
+}
+
+func (r PostgresRepo) ListChain(ctx context.Context, workspaceID string) (out []Event, err error) {
+	if r.DB == nil {
+		return nil, errors.New("audit: db is nil")
+	}
+	ctx, done := utils.TrackQuery(utils.WithQuery(ctx, "audit", "ListChain", utils.ReportBudget))
+	defer func() { done(err) }()
+	rows, err := r.DB.QueryContext(ctx, `
+SELECT id, workspace_id, type, actor_user_id, actor_role, ip_address, wallet_id, campaign_id,
+       call_id, override_id, message, metadata, created_at, chain_seq, prev_hash, hash
+FROM audit_events
+WHERE workspace_id = $1
+ORDER BY chain_seq`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e Event
+		var typ string
+		if err := rows.Scan(&e.ID, &e.WorkspaceID, &typ, &e.ActorUserID, &e.ActorRole, &e.IPAddress, &e.WalletID,
+			&e.CampaignID, &e.CallID, &e.OverrideID, &e.Message, &e.Metadata, &e.CreatedAt,
+			&e.ChainSeq, &e.PrevHash, &e.Hash); err != nil {
+			return nil, err
+		}
+		e.Type = EventType(typ)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"time"
 
+	"telecom-platform/pkg/utils"
+
 	"github.com/google/uuid"
 )
 
@@ -12,6 +14,8 @@ import (
 //
 // It MUST be append-only.
 // No Update/Delete methods are provided by design.
+// Append MUST link the event into the workspace hash chain (see LinkEvent) atomically
+// with the insert.
 
 type Repository interface {
 	Append(ctx context.Context, e Event) error
@@ -52,6 +56,10 @@ func (s *Service) Append(ctx context.Context, e Event) error {
 	if e.CreatedAt.IsZero() {
 		e.CreatedAt = now
 	}
+	if e.IPAddress == "" {
+		// Fall back to the client IP resolved by the HTTP layer (trusted-proxy aware).
+		e.IPAddress = utils.ClientIP(ctx)
+	}
 	return s.repo.Append(ctx, e)
 }
 
@@ -69,6 +77,23 @@ func (s *Service) LogAdminAction(ctx context.Context, workspaceID, actorUserID,
 	})
 }
 
+// PlatformScope is the WorkspaceID of platform-wide events (e.g. NOC controls).
+// It is not a tenant workspace, so the events never appear in a tenant's audit
+// log or SIEM export; they form their own hash chain.
+const PlatformScope = "_platform"
+
+// LogPlatformAction records a platform-wide action under PlatformScope.
+func (s *Service) LogPlatformAction(ctx context.Context, t EventType, actorUserID, actorRole, message, metadata string) error {
+	return s.Append(ctx, Event{
+		WorkspaceID: PlatformScope,
+		Type:        t,
+		ActorUserID: actorUserID,
+		ActorRole:   actorRole,
+		Message:     message,
+		Metadata:    metadata,
+	})
+}
+
 // LogOverride records an internal override usage.
 func (s *Service) LogOverride(ctx context.Context, workspaceID, actorUserID, actorRole, ip, campaignID, callID, overrideID, connectTo, metadata string) error {
 	return s.Append(ctx, Event{
@@ -3,6 +3,9 @@ package audit
 import (
 	"context"
 	"testing"
+	"time"
+
+	"telecom-platform/pkg/utils"
 )
 
 func TestService_AppendRequiresWorkspaceAndType(t *testing.T) {
@@ -36,3 +39,64 @@ func TestService_AppendsImmutableEvents(t *testing.T) {
 		t.Fatalf("expected admin_action")
 	}
 }
+
+func TestService_FallsBackToContextClientIP(t *testing.T) {
+	repo := NewMemoryRepo()
+	svc := NewService(repo)
+
+	ctx := utils.WithClientIP(context.Background(), "198.51.100.7")
+	if err := svc.LogAdminAction(ctx, "w", "u", "owner", "", "did something", "", ""); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if ip := repo.Events()[0].IPAddress; ip != "198.51.100.7" {
+		t.Fatalf("expected resolved client ip, got %q", ip)
+	}
+}
+
+func TestVerifyChain_DetectsRetroactiveModification(t *testing.T) {
+	repo := NewMemoryRepo()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	for _, msg := range []string{"a", "b", "c"} {
+		if err := svc.LogAdminAction(ctx, "w", "u", "owner", "", msg, "", ""); err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+	}
+	_ = svc.LogAdminAction(ctx, "other", "u", "owner", "", "x", "", "")
+
+	res, err := svc.VerifyChain(ctx, "w")
+	if err != nil || !res.Valid || res.Checked != 3 {
+		t.Fatalf("expected valid chain of 3, got %+v (%v)", res, err)
+	}
+
+	repo.mu.Lock()
+	repo.events[1].Message = "tampered"
+	repo.mu.Unlock()
+
+	res, _ = svc.VerifyChain(ctx, "w")
+	if res.Valid || res.BrokenAtSeq != 2 {
+		t.Fatalf("expected break at seq 2, got %+v", res)
+	}
+}
+
+func TestVerifyEvents_SurvivesStoredTimestampPrecision(t *testing.T) {
+	const pgTimestamptz = "2006-01-02 15:04:05.999999-07"
+	created := time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC)
+	var stored []Event
+	head := Event{}
+	for _, msg := range []string{"a", "b"} {
+		head = LinkEvent(head, Event{ID: msg, WorkspaceID: "w", Type: EventTypeAdminAction, Message: msg, CreatedAt: created})
+		// Round-trip through timestamptz text, as Postgres returns it.
+		e := head
+		back, err := time.Parse(pgTimestamptz, e.CreatedAt.In(time.FixedZone("", 2*3600)).Format(pgTimestamptz))
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.CreatedAt = back
+		stored = append(stored, e)
+	}
+	if res := VerifyEvents(stored); !res.Valid {
+		t.Fatalf("expected chain read back from storage to verify, got %+v", res)
+	}
+}
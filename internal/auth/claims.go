@@ -19,4 +19,27 @@ type Claims struct {
 	WorkspaceID string    `json:"workspace_id"`
 	Role        string    `json:"role"`
 	TokenType   TokenType `json:"token_type"`
+
+	// Optional extensions. Absent values keep pre-extension behavior.
+	// Plan is the workspace plan tier at issuance (e.g., "free", "pro", "enterprise").
+	Plan string `json:"plan,omitempty"`
+	// Features lists feature flags enabled for the workspace at issuance.
+	Features []string `json:"features,omitempty"`
+	// Scopes narrows what the token may do. Empty means unrestricted (full access for the role).
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// Token scopes. A token with scopes must include ScopeWrite to call mutating endpoints.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+func isKnownScope(s string) bool {
+	switch s {
+	case ScopeRead, ScopeWrite:
+		return true
+	default:
+		return false
+	}
 }
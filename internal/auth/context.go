@@ -11,6 +11,9 @@ const (
 	ctxUserID ctxKey = iota
 	ctxWorkspaceID
 	ctxRole
+	ctxPlan
+	ctxFeatures
+	ctxScopes
 )
 
 func WithIdentity(ctx context.Context, userID, workspaceID, role string) context.Context {
@@ -43,3 +46,39 @@ func Role(ctx context.Context) (string, error) {
 	}
 	return "", errors.New("role not in context")
 }
+
+// WithTokenExtensions stores the optional plan/features/scopes claims in context.
+func WithTokenExtensions(ctx context.Context, plan string, features, scopes []string) context.Context {
+	ctx = context.WithValue(ctx, ctxPlan, plan)
+	ctx = context.WithValue(ctx, ctxFeatures, features)
+	ctx = context.WithValue(ctx, ctxScopes, scopes)
+	return ctx
+}
+
+// Plan returns the workspace plan tier embedded in the token ("" if absent).
+func Plan(ctx context.Context) string {
+	s, _ := ctx.Value(ctxPlan).(string)
+	return s
+}
+
+// HasFeature reports whether the token lists feature f as enabled.
+func HasFeature(ctx context.Context, f string) bool {
+	fs, _ := ctx.Value(ctxFeatures).([]string)
+	for _, x := range fs {
+		if x == f {
+			return true
+		}
+	}
+	return false
+}
+
+// Scopes returns the token scopes. Empty means unrestricted.
+func Scopes(ctx context.Context) []string {
+	s, _ := ctx.Value(ctxScopes).([]string)
+	return s
+}
+
+// HasScope reports whether the token in context allows scope. Tokens without scopes are unrestricted.
+func HasScope(ctx context.Context, scope string) bool {
+	return Claims{Scopes: Scopes(ctx)}.HasScope(scope)
+}
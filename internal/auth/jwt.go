@@ -39,7 +39,27 @@ type TokenPair struct {
 
 /* ===================== ISSUE TOKENS ===================== */
 
+// TokenOptions carries optional claims embedded at issuance.
+type TokenOptions struct {
+	Plan     string
+	Features []string
+	Scopes   []string
+}
+
 func (m *Manager) IssuePair(now time.Time, userID, workspaceID, role string) (TokenPair, error) {
+	return m.IssuePairWithOptions(now, userID, workspaceID, role, TokenOptions{})
+}
+
+// IssuePairWithOptions issues a token pair embedding plan, features and scopes.
+// Refresh tokens keep the scopes (so a refresh can never widen access) but not plan/features,
+// which are re-resolved on refresh.
+func (m *Manager) IssuePairWithOptions(now time.Time, userID, workspaceID, role string, opts TokenOptions) (TokenPair, error) {
+	for _, s := range opts.Scopes {
+		if !isKnownScope(s) {
+			return TokenPair{}, errors.New("unknown scope: " + s)
+		}
+	}
+
 	access, err := m.issue(
 		now,
 		TokenTypeAccess,
@@ -47,6 +67,7 @@ func (m *Manager) IssuePair(now time.Time, userID, workspaceID, role string) (To
 		workspaceID,
 		role,
 		m.accessTTL,
+		opts,
 	)
 	if err != nil {
 		return TokenPair{}, err
@@ -59,6 +80,7 @@ func (m *Manager) IssuePair(now time.Time, userID, workspaceID, role string) (To
 		workspaceID,
 		"", // refresh tokens DO NOT carry role
 		m.refreshTTL,
+		TokenOptions{Scopes: opts.Scopes},
 	)
 	if err != nil {
 		return TokenPair{}, err
@@ -124,6 +146,12 @@ func (m *Manager) Verify(tokenString string, expected TokenType, now time.Time)
 		return Claims{}, errors.New("role missing in access token")
 	}
 
+	for _, s := range claims.Scopes {
+		if !isKnownScope(s) {
+			return Claims{}, errors.New("unknown scope in token")
+		}
+	}
+
 	return claims, nil
 }
 
@@ -136,6 +164,7 @@ func (m *Manager) issue(
 	workspaceID,
 	role string,
 	ttl time.Duration,
+	opts TokenOptions,
 ) (string, error) {
 
 	jti := uuid.NewString()
@@ -152,12 +181,28 @@ func (m *Manager) issue(
 		WorkspaceID: workspaceID,
 		Role:        role,
 		TokenType:   tokenType,
+		Plan:        opts.Plan,
+		Features:    opts.Features,
+		Scopes:      opts.Scopes,
 	}
 
 	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return t.SignedString(m.secret)
 }
 
+// HasScope reports whether the claims allow scope. Tokens without scopes are unrestricted.
+func (c Claims) HasScope(scope string) bool {
+	if len(c.Scopes) == 0 {
+		return true
+	}
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 func audienceOrNil(aud string) jwt.ClaimStrings {
 	if aud == "" {
 		return nil
@@ -1,9 +1,11 @@
 package auth
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"telecom-platform/internal/audit"
 	"telecom-platform/internal/config"
 )
 
@@ -48,3 +50,68 @@ func TestVerifyRejectsWrongTokenType(t *testing.T) {
 		t.Fatalf("expected token_type mismatch")
 	}
 }
+
+func TestIssuePairWithOptions_EmbedsScopesAndPlan(t *testing.T) {
+	m, _ := NewManager(config.AuthConfig{JWTSecret: "secret", AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour})
+	now := time.Now()
+	p, err := m.IssuePairWithOptions(now, "u", "w", "analyst", TokenOptions{Plan: "pro", Features: []string{"recording"}, Scopes: []string{ScopeRead}})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	claims, err := m.Verify(p.AccessToken, TokenTypeAccess, now)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if claims.Plan != "pro" || len(claims.Features) != 1 {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+	if claims.HasScope(ScopeWrite) || !claims.HasScope(ScopeRead) {
+		t.Fatalf("expected read-only token, got scopes %v", claims.Scopes)
+	}
+
+	refresh, err := m.Verify(p.RefreshToken, TokenTypeRefresh, now)
+	if err != nil {
+		t.Fatalf("verify refresh: %v", err)
+	}
+	if refresh.HasScope(ScopeWrite) {
+		t.Fatalf("expected refresh token to keep read-only scope")
+	}
+
+	if _, err := m.IssuePairWithOptions(now, "u", "w", "analyst", TokenOptions{Scopes: []string{"admin"}}); err == nil {
+		t.Fatalf("expected unknown scope rejected")
+	}
+}
+
+func TestServiceToken_MintVerifyAndAudit(t *testing.T) {
+	repo := audit.NewMemoryRepo()
+	m, err := NewServiceTokenManager(config.AuthConfig{JWTSecret: "user-secret", ServiceTokenSecret: "svc-secret", ServiceTokenTTL: 5 * time.Minute}, audit.NewService(repo))
+	if err != nil {
+		t.Fatalf("manager: %v", err)
+	}
+	now := time.Now()
+	tok, err := m.Mint(context.Background(), now, ServiceTokenRequest{Service: "worker", WorkspaceID: "w", IssuedBy: "u", TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+	claims, err := m.Verify(tok, now)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if claims.Service != "worker" || claims.WorkspaceID != "w" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+	if ttl := claims.ExpiresAt.Sub(claims.IssuedAt.Time); ttl != 5*time.Minute {
+		t.Fatalf("expected ttl capped at 5m, got %v", ttl)
+	}
+	if _, err := m.Verify(tok, now.Add(6*time.Minute)); err == nil {
+		t.Fatalf("expected expired service token")
+	}
+	if len(repo.Events()) != 1 {
+		t.Fatalf("expected mint to be audited")
+	}
+
+	users, _ := NewManager(config.AuthConfig{JWTSecret: "user-secret", AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour})
+	if _, err := users.Verify(tok, TokenTypeAccess, now); err == nil {
+		t.Fatalf("expected service token rejected by user token path")
+	}
+}
@@ -29,6 +29,7 @@ func RequireAccessToken(m *Manager) gin.HandlerFunc {
 		}
 
 		ctx := WithIdentity(c.Request.Context(), claims.UserID, claims.WorkspaceID, claims.Role)
+		ctx = WithTokenExtensions(ctx, claims.Plan, claims.Features, claims.Scopes)
 		c.Request = c.Request.WithContext(ctx)
 
 		// Also store on gin context for handler convenience.
@@ -39,3 +40,21 @@ func RequireAccessToken(m *Manager) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequireWriteScope rejects mutating requests (POST/PUT/PATCH/DELETE) made with a token
+// whose scopes do not include ScopeWrite (e.g., read-only tokens).
+// Must run after RequireAccessToken.
+func RequireWriteScope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if !HasScope(c.Request.Context(), ScopeWrite) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "token scope does not allow writes"})
+			return
+		}
+		c.Next()
+	}
+}
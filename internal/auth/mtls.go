@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"telecom-platform/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Mutual-TLS service callers.
+//
+// On the internal listener (see utils.ConfigureMTLSServer) internal services
+// authenticate with a client certificate instead of a shared JWT secret. The
+// certificate's SPIFFE ID (spiffe://trust-domain/path URI SAN) is mapped to a
+// service name by configuration; unknown IDs are rejected. The caller gets the
+// same identity as a service token ("service:<name>", ServiceRole). A trusted
+// service acts across workspaces, so the workspace comes from WorkspaceHeader.
+
+// WorkspaceHeader names the workspace an mTLS service caller acts in.
+const WorkspaceHeader = "X-Workspace-ID"
+
+// RequireClientCert authenticates internal callers by verified client
+// certificate. identities maps SPIFFE IDs to service names; if services is
+// non-empty, only the listed service names are accepted.
+func RequireClientCert(identities map[string]string, services ...string) gin.HandlerFunc {
+	allowed := make(map[string]struct{}, len(services))
+	for _, s := range services {
+		allowed[s] = struct{}{}
+	}
+	return func(c *gin.Context) {
+		// VerifiedChains is only set when the handshake verified the client chain.
+		st := c.Request.TLS
+		if st == nil || len(st.VerifiedChains) == 0 || len(st.PeerCertificates) == 0 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "client certificate required"})
+			return
+		}
+		id, ok := utils.SPIFFEID(st.PeerCertificates[0])
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "client certificate has no spiffe id"})
+			return
+		}
+		service, ok := identities[id]
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		if len(allowed) > 0 {
+			if _, ok := allowed[service]; !ok {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+				return
+			}
+		}
+
+		workspaceID := strings.TrimSpace(c.GetHeader(WorkspaceHeader))
+		ctx := WithIdentity(c.Request.Context(), "service:"+service, workspaceID, ServiceRole)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Set("user_id", "service:"+service)
+		c.Set("workspace_id", workspaceID)
+		c.Set("role", ServiceRole)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireClientCert(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/x", RequireClientCert(map[string]string{
+		"spiffe://telecom.internal/worker": "worker",
+		"spiffe://telecom.internal/media":  "media_controller",
+	}, "worker"), func(c *gin.Context) {
+		uid, _ := UserID(c.Request.Context())
+		wid, _ := WorkspaceID(c.Request.Context())
+		role, _ := Role(c.Request.Context())
+		c.String(200, uid+"|"+wid+"|"+role)
+	})
+
+	do := func(id string, verified bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/x", nil)
+		req.Header.Set(WorkspaceHeader, "ws-1")
+		if id != "" {
+			u, _ := url.Parse(id)
+			cert := &x509.Certificate{URIs: []*url.URL{u}}
+			req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+			if verified {
+				req.TLS.VerifiedChains = [][]*x509.Certificate{{cert}}
+			}
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do("spiffe://telecom.internal/worker", true); w.Code != 200 || w.Body.String() != "service:worker|ws-1|"+ServiceRole {
+		t.Fatalf("expected worker identity, got %d %q", w.Code, w.Body.String())
+	}
+	if w := do("", false); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without certificate, got %d", w.Code)
+	}
+	if w := do("spiffe://telecom.internal/worker", false); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for unverified chain, got %d", w.Code)
+	}
+	if w := do("spiffe://other.domain/worker", true); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for unknown id, got %d", w.Code)
+	}
+	if w := do("spiffe://telecom.internal/media", true); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for service not allowed on route, got %d", w.Code)
+	}
+}
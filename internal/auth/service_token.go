@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/audit"
+	"telecom-platform/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Service (delegation) tokens.
+//
+// Background jobs, webhooks and internal services get narrowly-scoped, short-lived tokens
+// instead of reusing user JWTs:
+// - signed with a separate secret (INTERNAL_TOKEN_SECRET)
+// - bound to exactly one workspace and one named service
+// - TTL capped (default 5 minutes)
+// - every mint is audited
+
+const TokenTypeService TokenType = "service"
+
+// ServiceRole is the role injected into context for service callers.
+const ServiceRole = "service"
+
+// ServiceClaims is the claims shape of service tokens.
+type ServiceClaims struct {
+	jwt.RegisteredClaims
+
+	Service     string    `json:"service"`
+	WorkspaceID string    `json:"workspace_id"`
+	Scopes      []string  `json:"scopes,omitempty"`
+	TokenType   TokenType `json:"token_type"`
+
+	// IssuedBy records the principal that requested the token (user id or service name).
+	IssuedBy string `json:"issued_by"`
+}
+
+type ServiceTokenManager struct {
+	secret []byte
+	issuer string
+	maxTTL time.Duration
+	audit  *audit.Service
+}
+
+func NewServiceTokenManager(cfg config.AuthConfig, auditSvc *audit.Service) (*ServiceTokenManager, error) {
+	if cfg.ServiceTokenSecret == "" {
+		return nil, errors.New("INTERNAL_TOKEN_SECRET is required")
+	}
+	if cfg.ServiceTokenSecret == cfg.JWTSecret {
+		return nil, errors.New("INTERNAL_TOKEN_SECRET must differ from JWT_SECRET")
+	}
+	ttl := cfg.ServiceTokenTTL
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+	return &ServiceTokenManager{secret: []byte(cfg.ServiceTokenSecret), issuer: cfg.JWTIssuer, maxTTL: ttl, audit: auditSvc}, nil
+}
+
+type ServiceTokenRequest struct {
+	Service     string
+	WorkspaceID string
+	Scopes      []string
+	// TTL is optional; it is capped at the configured maximum.
+	TTL time.Duration
+
+	IssuedBy string
+}
+
+// Mint issues a service token and audits the issuance.
+func (m *ServiceTokenManager) Mint(ctx context.Context, now time.Time, req ServiceTokenRequest) (string, error) {
+	if req.Service == "" || req.WorkspaceID == "" || req.IssuedBy == "" {
+		return "", errors.New("service, workspace_id and issued_by required")
+	}
+	for _, s := range req.Scopes {
+		if !isKnownScope(s) {
+			return "", errors.New("unknown scope: " + s)
+		}
+	}
+	ttl := req.TTL
+	if ttl <= 0 || ttl > m.maxTTL {
+		ttl = m.maxTTL
+	}
+
+	claims := ServiceClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.issuer,
+			Subject:   req.Service,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			ID:        uuid.NewString(),
+		},
+		Service:     req.Service,
+		WorkspaceID: req.WorkspaceID,
+		Scopes:      req.Scopes,
+		TokenType:   TokenTypeService,
+		IssuedBy:    req.IssuedBy,
+	}
+	tok, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", err
+	}
+
+	if m.audit != nil {
+		meta, _ := json.Marshal(map[string]any{"jti": claims.ID, "ttl_seconds": int(ttl / time.Second)})
+		// Best-effort; do not block job dispatch on audit failures.
+		_ = m.audit.Append(ctx, audit.Event{
+			WorkspaceID: req.WorkspaceID,
+			Type:        audit.EventTypeServiceToken,
+			ActorUserID: req.IssuedBy,
+			Message:     "service token minted for " + req.Service,
+			Metadata:    string(meta),
+		})
+	}
+	return tok, nil
+}
+
+// Verify validates a service token. The TTL cap is re-checked so a token minted under a
+// looser config cannot outlive the current policy.
+func (m *ServiceTokenManager) Verify(tokenString string, now time.Time) (ServiceClaims, error) {
+	var claims ServiceClaims
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithTimeFunc(func() time.Time { return now }),
+		jwt.WithLeeway(5*time.Second),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+	)
+	if _, err := parser.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (any, error) {
+		return m.secret, nil
+	}); err != nil {
+		return ServiceClaims{}, err
+	}
+	if claims.TokenType != TokenTypeService {
+		return ServiceClaims{}, errors.New("token_type mismatch")
+	}
+	if claims.Service == "" || claims.WorkspaceID == "" {
+		return ServiceClaims{}, errors.New("service token missing service or workspace_id")
+	}
+	if claims.IssuedAt == nil || claims.ExpiresAt.Sub(claims.IssuedAt.Time) > m.maxTTL {
+		return ServiceClaims{}, errors.New("service token ttl exceeds policy")
+	}
+	for _, s := range claims.Scopes {
+		if !isKnownScope(s) {
+			return ServiceClaims{}, errors.New("unknown scope in token")
+		}
+	}
+	return claims, nil
+}
+
+// RequireServiceToken authenticates internal callers with a service token.
+// If services is non-empty, only the listed service names are accepted.
+func RequireServiceToken(m *ServiceTokenManager, services ...string) gin.HandlerFunc {
+	allowed := make(map[string]struct{}, len(services))
+	for _, s := range services {
+		allowed[s] = struct{}{}
+	}
+	return func(c *gin.Context) {
+		raw := strings.TrimSpace(c.GetHeader(authorizationHeader))
+		if raw == "" || !strings.HasPrefix(raw, bearerPrefix) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing bearer token"})
+			return
+		}
+		claims, err := m.Verify(strings.TrimPrefix(raw, bearerPrefix), time.Now())
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+		if len(allowed) > 0 {
+			if _, ok := allowed[claims.Service]; !ok {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+				return
+			}
+		}
+
+		ctx := WithIdentity(c.Request.Context(), "service:"+claims.Service, claims.WorkspaceID, ServiceRole)
+		ctx = WithTokenExtensions(ctx, "", nil, claims.Scopes)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Set("user_id", "service:"+claims.Service)
+		c.Set("workspace_id", claims.WorkspaceID)
+		c.Set("role", ServiceRole)
+
+		c.Next()
+	}
+}
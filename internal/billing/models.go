@@ -0,0 +1,89 @@
+package billing
+
+import (
+	"time"
+
+	"telecom-platform/internal/pricing"
+)
+
+// ActiveCall is a connected call being billed incrementally.
+type ActiveCall struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	CallID      string `json:"call_id" db:"call_id"`
+	WalletID    string `json:"wallet_id" db:"wallet_id"`
+
+	// ProviderCallID is needed to instruct the provider to end the call.
+	ProviderCallID string `json:"provider_call_id,omitempty" db:"provider_call_id"`
+
+	// Quote is the rate locked at connect time.
+	Quote pricing.RateQuote `json:"quote" db:"quote"`
+
+	ConnectedAt time.Time `json:"connected_at" db:"connected_at"`
+
+	// BilledSeconds/BilledMinor track what has already been captured from the wallet.
+	BilledSeconds int   `json:"billed_seconds" db:"billed_seconds"`
+	BilledMinor   int64 `json:"billed_minor" db:"billed_minor"`
+	Increments    int   `json:"increments" db:"increments"`
+
+	// Pending is an increment being captured: recorded before its debit and cleared
+	// once the debit is recorded or refused.
+	Pending *Increment `json:"pending,omitempty" db:"pending"`
+
+	// FundsExhausted is set when an increment could not be captured.
+	FundsExhausted bool `json:"funds_exhausted" db:"funds_exhausted"`
+
+	// CutoffAt is set once the watchdog has asked the provider to hang up.
+	CutoffAt *time.Time `json:"cutoff_at,omitempty" db:"cutoff_at"`
+
+	// CampaignID, PacedAt and PacingReservedMinor carry the routing decision's
+	// campaign pacing reservation, reconciled to the billed total on completion.
+	CampaignID          string     `json:"campaign_id,omitempty" db:"campaign_id"`
+	PacedAt             *time.Time `json:"paced_at,omitempty" db:"paced_at"`
+	PacingReservedMinor int64      `json:"pacing_reserved_minor,omitempty" db:"pacing_reserved_minor"`
+}
+
+// Increment is one captured (or failed) partial charge.
+type Increment struct {
+	WorkspaceID string `json:"workspace_id"`
+	CallID      string `json:"call_id"`
+	Seq         int    `json:"seq"`
+
+	UpToSeconds int   `json:"up_to_seconds"`
+	AmountMinor int64 `json:"amount_minor"`
+
+	Err error `json:"-"`
+}
+
+// WatchdogPolicy configures mid-call balance cutoff for a workspace.
+type WatchdogPolicy struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+
+	// Disabled turns off cutoff entirely (calls are billed post-call regardless).
+	Disabled bool `json:"disabled" db:"disabled"`
+
+	// GraceMinor is how far below zero (balance minus unbilled accrued cost) a wallet
+	// may go before calls are cut off.
+	GraceMinor int64 `json:"grace_minor" db:"grace_minor"`
+
+	// GraceSeconds keeps brand-new calls alive for a short while even if funds are short.
+	GraceSeconds int `json:"grace_seconds" db:"grace_seconds"`
+
+	// Announcement is spoken before hangup; empty uses DefaultCutoffAnnouncement.
+	Announcement string `json:"announcement,omitempty" db:"announcement"`
+}
+
+// DefaultCutoffAnnouncement is played when a policy does not set one.
+const DefaultCutoffAnnouncement = "We're sorry, this call must end because the account balance has run out. Goodbye."
+
+// Cutoff records one watchdog hangup.
+type Cutoff struct {
+	WorkspaceID    string `json:"workspace_id"`
+	CallID         string `json:"call_id"`
+	WalletID       string `json:"wallet_id"`
+	ProviderCallID string `json:"provider_call_id"`
+
+	// RemainingMinor is balance minus unbilled accrued cost at decision time.
+	RemainingMinor int64 `json:"remaining_minor"`
+
+	Err error `json:"-"`
+}
@@ -0,0 +1,74 @@
+package billing
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryStore is an in-memory ActiveCallStore useful for tests.
+// It is not intended for production use.
+
+type MemoryStore struct {
+	mu    sync.Mutex
+	calls map[string]ActiveCall
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{calls: map[string]ActiveCall{}}
+}
+
+func (s *MemoryStore) Put(ctx context.Context, c ActiveCall) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[c.WorkspaceID+"|"+c.CallID] = c
+	return nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, workspaceID, callID string) (ActiveCall, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.calls[workspaceID+"|"+callID]
+	return c, ok, nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, workspaceID, callID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.calls, workspaceID+"|"+callID)
+	return nil
+}
+
+func (s *MemoryStore) List(ctx context.Context) ([]ActiveCall, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]ActiveCall, 0, len(s.calls))
+	for _, c := range s.calls {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CallID < out[j].CallID })
+	return out, nil
+}
+
+// MemoryPolicyStore is an in-memory WatchdogPolicyStore useful for tests.
+type MemoryPolicyStore struct {
+	mu       sync.Mutex
+	policies map[string]WatchdogPolicy
+}
+
+func NewMemoryPolicyStore() *MemoryPolicyStore {
+	return &MemoryPolicyStore{policies: map[string]WatchdogPolicy{}}
+}
+
+func (s *MemoryPolicyStore) SetWatchdogPolicy(p WatchdogPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policies[p.WorkspaceID] = p
+}
+
+func (s *MemoryPolicyStore) GetWatchdogPolicy(ctx context.Context, workspaceID string) (WatchdogPolicy, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.policies[workspaceID]
+	return p, ok, nil
+}
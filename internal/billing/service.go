@@ -0,0 +1,247 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"telecom-platform/internal/wallet"
+	"telecom-platform/pkg/logger"
+)
+
+// Incremental (long-call) billing.
+//
+// Short calls are billed once, on completion. Calls that run past Threshold are
+// billed in partial captures every Interval while still connected, so an hours-long
+// call cannot drive a wallet deeply negative before the post-call debit lands.
+//
+// Every capture is a regular wallet debit with a deterministic idempotency key
+// ("call:<id>:inc:<n>" / "call:<id>:final"), so retries and worker restarts never
+// double-charge. An increment is recorded as Pending before it is debited; after
+// a restart between the debit and recording it, the same request is replayed
+// (the wallet returns the original entry) rather than recomputed under a new
+// amount. The final debit only captures the remainder.
+
+var (
+	ErrInvalidArgument = errors.New("billing: invalid argument")
+	ErrNotFound        = errors.New("billing: active call not found")
+	// ErrCannotTerminate means the watchdog has no way to end a call it should cut off.
+	ErrCannotTerminate = errors.New("billing: call cannot be terminated")
+)
+
+// Debiter is the wallet capability billing needs. *wallet.Service implements it.
+type Debiter interface {
+	Debit(ctx context.Context, workspaceID, walletID string, req wallet.DebitRequest) (wallet.WalletLedger, wallet.Balance, error)
+}
+
+// ActiveCallStore persists in-flight billing state.
+// Implementations must enforce workspace filtering.
+type ActiveCallStore interface {
+	Put(ctx context.Context, c ActiveCall) error
+	Get(ctx context.Context, workspaceID, callID string) (ActiveCall, bool, error)
+	Delete(ctx context.Context, workspaceID, callID string) error
+	List(ctx context.Context) ([]ActiveCall, error)
+}
+
+// PacingRecorder adjusts a campaign's daily spend from the estimate reserved at
+// routing time to the actual cost.
+type PacingRecorder interface {
+	RecordActual(ctx context.Context, workspaceID, campaignID string, startedAt time.Time, estMinor, actualMinor int64) error
+}
+
+// Service posts incremental and final call debits.
+type Service struct {
+	wallet Debiter
+	calls  ActiveCallStore
+
+	// Threshold is how long a call runs before increments start (default 10m).
+	Threshold time.Duration
+	// Interval is the capture period once past Threshold (default 5m).
+	Interval time.Duration
+
+	// Pacing reconciles campaign pacing reservations to the billed total
+	// (optional; routing.Pacer implements it).
+	Pacing PacingRecorder
+
+	clock func() time.Time
+}
+
+func NewService(w Debiter, store ActiveCallStore) *Service {
+	return &Service{wallet: w, calls: store, Threshold: 10 * time.Minute, Interval: 5 * time.Minute, clock: time.Now}
+}
+
+// StartCall registers a connected call for incremental billing.
+func (s *Service) StartCall(ctx context.Context, c ActiveCall) error {
+	if c.WorkspaceID == "" || c.CallID == "" || c.WalletID == "" || c.Quote.Currency == "" {
+		return ErrInvalidArgument
+	}
+	if c.ConnectedAt.IsZero() {
+		c.ConnectedAt = s.clock().UTC()
+	}
+	if _, ok, err := s.calls.Get(ctx, c.WorkspaceID, c.CallID); err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+	return s.calls.Put(ctx, c)
+}
+
+// Tick captures due increments for every active call and returns what was attempted.
+func (s *Service) Tick(ctx context.Context) ([]Increment, error) {
+	active, err := s.calls.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := s.clock().UTC()
+	var out []Increment
+	for _, c := range active {
+		inc, ok, err := s.captureDue(ctx, c, now)
+		if err != nil {
+			return out, err
+		}
+		if ok {
+			out = append(out, inc)
+		}
+	}
+	return out, nil
+}
+
+func (s *Service) captureDue(ctx context.Context, c ActiveCall, now time.Time) (Increment, bool, error) {
+	if c.Pending != nil {
+		// Debited (or attempted) but not recorded: replay the same request.
+		return s.capture(ctx, &c, *c.Pending)
+	}
+	elapsed := now.Sub(c.ConnectedAt)
+	if elapsed < s.Threshold || s.Interval <= 0 {
+		return Increment{}, false, nil
+	}
+	step := int(s.Interval / time.Second)
+	upTo := int(elapsed/time.Second) / step * step
+	if upTo <= c.BilledSeconds {
+		return Increment{}, false, nil
+	}
+
+	amount := c.Quote.CostMinor(upTo) - c.BilledMinor
+	inc := Increment{WorkspaceID: c.WorkspaceID, CallID: c.CallID, Seq: c.Increments + 1, UpToSeconds: upTo, AmountMinor: amount}
+	if amount <= 0 {
+		c.BilledSeconds = upTo
+		return inc, true, s.calls.Put(ctx, c)
+	}
+	c.Pending = &inc
+	if err := s.calls.Put(ctx, c); err != nil {
+		return inc, true, err
+	}
+	return s.capture(ctx, &c, inc)
+}
+
+// capture debits inc, which must already be recorded as c.Pending. A transient
+// failure leaves it pending so the identical request is retried; a debit refused
+// for lack of funds never landed, so it is dropped and recomputed next time.
+func (s *Service) capture(ctx context.Context, c *ActiveCall, inc Increment) (Increment, bool, error) {
+	meta, _ := json.Marshal(map[string]any{"billing": "incremental", "up_to_seconds": inc.UpToSeconds})
+	_, _, err := s.wallet.Debit(ctx, c.WorkspaceID, c.WalletID, wallet.DebitRequest{
+		AmountMinor:    inc.AmountMinor,
+		Currency:       c.Quote.Currency,
+		ExternalRef:    "call:" + c.CallID,
+		IdempotencyKey: fmt.Sprintf("call:%s:inc:%d", c.CallID, inc.Seq),
+		Metadata:       string(meta),
+	})
+	if err != nil {
+		inc.Err = err
+		if fundsRefused(err) {
+			c.Pending = nil
+			c.FundsExhausted = true
+			return inc, true, s.calls.Put(ctx, *c)
+		}
+		return inc, true, nil
+	}
+	c.Pending = nil
+	c.BilledSeconds = inc.UpToSeconds
+	c.BilledMinor += inc.AmountMinor
+	c.Increments = inc.Seq
+	c.FundsExhausted = false
+	return inc, true, s.calls.Put(ctx, *c)
+}
+
+// fundsRefused reports whether a debit was refused for lack of funds. A spend cap
+// stops the call the same way an empty wallet does.
+func fundsRefused(err error) bool {
+	return errors.Is(err, wallet.ErrInsufficientFunds) || errors.Is(err, wallet.ErrSpendCapExceeded)
+}
+
+// CompleteCall posts the remainder for a finished call and forgets it.
+// It returns the total billed for the call (increments + final).
+func (s *Service) CompleteCall(ctx context.Context, workspaceID, callID string, durationSeconds int) (int64, error) {
+	if workspaceID == "" || callID == "" || durationSeconds < 0 {
+		return 0, ErrInvalidArgument
+	}
+	c, ok, err := s.calls.Get(ctx, workspaceID, callID)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrNotFound
+	}
+
+	if c.Pending != nil {
+		// Settle an unrecorded increment first so the remainder does not charge it again.
+		inc, _, err := s.capture(ctx, &c, *c.Pending)
+		if err != nil {
+			return c.BilledMinor, err
+		}
+		if inc.Err != nil && !fundsRefused(inc.Err) {
+			return c.BilledMinor, inc.Err
+		}
+	}
+
+	total := c.Quote.CostMinor(durationSeconds)
+	if rest := total - c.BilledMinor; rest > 0 {
+		meta, _ := json.Marshal(map[string]any{"billing": "final", "duration_seconds": durationSeconds})
+		_, _, err := s.wallet.Debit(ctx, c.WorkspaceID, c.WalletID, wallet.DebitRequest{
+			AmountMinor:    rest,
+			Currency:       c.Quote.Currency,
+			ExternalRef:    "call:" + c.CallID,
+			IdempotencyKey: fmt.Sprintf("call:%s:final", c.CallID),
+			Metadata:       string(meta),
+		})
+		if err != nil {
+			return c.BilledMinor, err
+		}
+		c.BilledMinor = total
+	}
+	if err := s.calls.Delete(ctx, workspaceID, callID); err != nil {
+		return c.BilledMinor, err
+	}
+	// Reconciled after the delete so a retried completion cannot adjust twice;
+	// the charge stands even if pacing lags.
+	if s.Pacing != nil && c.PacedAt != nil {
+		if err := s.Pacing.RecordActual(ctx, c.WorkspaceID, c.CampaignID, *c.PacedAt, c.PacingReservedMinor, c.BilledMinor); err != nil {
+			logger.From(ctx).Warn("pacing reconcile failed", "workspace_id", c.WorkspaceID, "call_id", c.CallID, "err", err)
+		}
+	}
+	return c.BilledMinor, nil
+}
+
+// Run ticks until ctx is canceled.
+func (s *Service) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			incs, err := s.Tick(ctx)
+			if err != nil {
+				logger.From(ctx).Error("incremental billing tick failed", "err", err)
+			}
+			for _, inc := range incs {
+				if inc.Err != nil {
+					logger.From(ctx).Warn("incremental capture failed", "workspace_id", inc.WorkspaceID, "call_id", inc.CallID, "seq", inc.Seq, "err", inc.Err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/pricing"
+	"telecom-platform/internal/wallet"
+)
+
+type stubWallet struct {
+	balance int64
+	keys    map[string]int64
+}
+
+func (w *stubWallet) Debit(ctx context.Context, workspaceID, walletID string, req wallet.DebitRequest) (wallet.WalletLedger, wallet.Balance, error) {
+	if _, ok := w.keys[req.IdempotencyKey]; ok {
+		return wallet.WalletLedger{}, wallet.Balance{BalanceMinor: w.balance}, nil
+	}
+	if w.balance < req.AmountMinor {
+		return wallet.WalletLedger{}, wallet.Balance{}, wallet.ErrInsufficientFunds
+	}
+	w.balance -= req.AmountMinor
+	w.keys[req.IdempotencyKey] = req.AmountMinor
+	return wallet.WalletLedger{}, wallet.Balance{BalanceMinor: w.balance}, nil
+}
+
+func TestIncrementalBilling_CapturesDuringLongCall(t *testing.T) {
+	w := &stubWallet{balance: 1000, keys: map[string]int64{}}
+	store := NewMemoryStore()
+	svc := NewService(w, store)
+	t0 := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := t0
+	svc.clock = func() time.Time { return now }
+
+	q := pricing.RateQuote{WorkspaceID: "w", Currency: "USD", RatePerMinuteMinor: 10, BillingIncrementSeconds: 60}
+	if err := svc.StartCall(context.Background(), ActiveCall{WorkspaceID: "w", CallID: "c1", WalletID: "wa", Quote: q}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	now = t0.Add(9 * time.Minute)
+	if incs, _ := svc.Tick(context.Background()); len(incs) != 0 {
+		t.Fatalf("expected no capture before threshold, got %+v", incs)
+	}
+
+	now = t0.Add(12 * time.Minute)
+	incs, err := svc.Tick(context.Background())
+	if err != nil || len(incs) != 1 || incs[0].AmountMinor != 100 || incs[0].UpToSeconds != 600 {
+		t.Fatalf("unexpected first capture: %+v err=%v", incs, err)
+	}
+	// Same window again is a no-op.
+	if incs, _ := svc.Tick(context.Background()); len(incs) != 0 {
+		t.Fatalf("expected no duplicate capture, got %+v", incs)
+	}
+
+	now = t0.Add(16 * time.Minute)
+	incs, _ = svc.Tick(context.Background())
+	if len(incs) != 1 || incs[0].AmountMinor != 50 || incs[0].Seq != 2 {
+		t.Fatalf("unexpected second capture: %+v", incs)
+	}
+
+	total, err := svc.CompleteCall(context.Background(), "w", "c1", 17*60+5)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if total != 180 || w.balance != 820 {
+		t.Fatalf("expected 180 billed in total, got %d (balance %d)", total, w.balance)
+	}
+	if _, ok, _ := store.Get(context.Background(), "w", "c1"); ok {
+		t.Fatalf("expected call to be removed after completion")
+	}
+}
+
+func TestIncrementalBilling_FlagsExhaustedFunds(t *testing.T) {
+	w := &stubWallet{balance: 50, keys: map[string]int64{}}
+	store := NewMemoryStore()
+	svc := NewService(w, store)
+	t0 := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	svc.clock = func() time.Time { return t0.Add(11 * time.Minute) }
+
+	q := pricing.RateQuote{WorkspaceID: "w", Currency: "USD", RatePerMinuteMinor: 10, BillingIncrementSeconds: 60}
+	_ = svc.StartCall(context.Background(), ActiveCall{WorkspaceID: "w", CallID: "c1", WalletID: "wa", Quote: q, ConnectedAt: t0})
+
+	incs, err := svc.Tick(context.Background())
+	if err != nil || len(incs) != 1 || incs[0].Err == nil {
+		t.Fatalf("expected failed capture, got %+v err=%v", incs, err)
+	}
+	c, _, _ := store.Get(context.Background(), "w", "c1")
+	if !c.FundsExhausted || c.BilledMinor != 0 {
+		t.Fatalf("expected exhausted flag without billing, got %+v", c)
+	}
+}
+
+// crashingStore fails the Put that records a completed increment, as if the
+// worker died between the debit and saving the call.
+type crashingStore struct {
+	*MemoryStore
+	crash bool
+}
+
+func (s *crashingStore) Put(ctx context.Context, c ActiveCall) error {
+	if s.crash && c.Pending == nil && c.Increments > 0 {
+		s.crash = false
+		return errors.New("store unavailable")
+	}
+	return s.MemoryStore.Put(ctx, c)
+}
+
+func TestIncrementalBilling_ReplaysUnrecordedIncrement(t *testing.T) {
+	w := &stubWallet{balance: 1000, keys: map[string]int64{}}
+	store := &crashingStore{MemoryStore: NewMemoryStore(), crash: true}
+	svc := NewService(w, store)
+	t0 := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := t0.Add(12 * time.Minute)
+	svc.clock = func() time.Time { return now }
+
+	q := pricing.RateQuote{WorkspaceID: "w", Currency: "USD", RatePerMinuteMinor: 10, BillingIncrementSeconds: 60}
+	_ = svc.StartCall(context.Background(), ActiveCall{WorkspaceID: "w", CallID: "c1", WalletID: "wa", Quote: q, ConnectedAt: t0})
+	if _, err := svc.Tick(context.Background()); err == nil {
+		t.Fatalf("expected the recording put to fail")
+	}
+
+	// The next window replays the same increment instead of charging it again.
+	now = t0.Add(16 * time.Minute)
+	incs, err := svc.Tick(context.Background())
+	if err != nil || len(incs) != 1 || incs[0].Seq != 1 || incs[0].AmountMinor != 100 || incs[0].Err != nil {
+		t.Fatalf("expected replay of increment 1, got %+v err=%v", incs, err)
+	}
+	if incs, _ := svc.Tick(context.Background()); len(incs) != 1 || incs[0].Seq != 2 || incs[0].AmountMinor != 50 {
+		t.Fatalf("expected increment 2 after replay, got %+v", incs)
+	}
+	total, err := svc.CompleteCall(context.Background(), "w", "c1", 17*60+5)
+	if err != nil || total != 180 || w.balance != 820 {
+		t.Fatalf("expected 180 billed once, got %d (balance %d) err=%v", total, w.balance, err)
+	}
+}
+
+type recordingPacing []int64
+
+func (p *recordingPacing) RecordActual(ctx context.Context, workspaceID, campaignID string, startedAt time.Time, estMinor, actualMinor int64) error {
+	*p = append(*p, estMinor, actualMinor)
+	return nil
+}
+
+func TestCompleteCall_ReconcilesPacingOnce(t *testing.T) {
+	w := &stubWallet{balance: 1000, keys: map[string]int64{}}
+	svc := NewService(w, NewMemoryStore())
+	pacing := &recordingPacing{}
+	svc.Pacing = pacing
+	pacedAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	q := pricing.RateQuote{WorkspaceID: "w", Currency: "USD", RatePerMinuteMinor: 10, BillingIncrementSeconds: 60}
+	_ = svc.StartCall(context.Background(), ActiveCall{WorkspaceID: "w", CallID: "c1", WalletID: "wa", Quote: q, CampaignID: "camp", PacedAt: &pacedAt, PacingReservedMinor: 40})
+
+	if total, err := svc.CompleteCall(context.Background(), "w", "c1", 125); err != nil || total != 30 {
+		t.Fatalf("expected 30 billed, got %d err=%v", total, err)
+	}
+	if _, err := svc.CompleteCall(context.Background(), "w", "c1", 125); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected retried completion to find nothing, got %v", err)
+	}
+	if len(*pacing) != 2 || (*pacing)[0] != 40 || (*pacing)[1] != 30 {
+		t.Fatalf("expected one reconcile from 40 to 30, got %v", *pacing)
+	}
+}
+
+type stubBalance struct{ minor, held, creditLimit int64 }
+
+func (b stubBalance) GetBalance(ctx context.Context, workspaceID, walletID string) (wallet.Balance, error) {
+	return wallet.Balance{WorkspaceID: workspaceID, WalletID: walletID, Currency: "USD", BalanceMinor: b.minor, HeldMinor: b.held, CreditLimitMinor: b.creditLimit}, nil
+}
+
+type recordingTerminator struct{ hungUp []string }
+
+func (r *recordingTerminator) HangupCall(ctx context.Context, workspaceID, providerCallID, announcement string) error {
+	r.hungUp = append(r.hungUp, providerCallID+"|"+announcement)
+	return nil
+}
+
+func TestWatchdog_CutsOffWhenAccruedCostExceedsBalancePlusGrace(t *testing.T) {
+	store := NewMemoryStore()
+	policies := NewMemoryPolicyStore()
+	policies.SetWatchdogPolicy(WatchdogPolicy{WorkspaceID: "w", GraceMinor: 20, Announcement: "bye"})
+	term := &recordingTerminator{}
+	t0 := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := t0
+	wd := Watchdog{Calls: store, Wallet: stubBalance{minor: 100}, Policies: policies, Terminator: term, Now: func() time.Time { return now }}
+
+	q := pricing.RateQuote{WorkspaceID: "w", Currency: "USD", RatePerMinuteMinor: 10, BillingIncrementSeconds: 60}
+	_ = store.Put(context.Background(), ActiveCall{WorkspaceID: "w", CallID: "c1", WalletID: "wa", ProviderCallID: "p1", Quote: q, ConnectedAt: t0})
+	_ = store.Put(context.Background(), ActiveCall{WorkspaceID: "w", CallID: "c2", WalletID: "wa", ProviderCallID: "p2", Quote: q, ConnectedAt: t0})
+
+	// 5 minutes x 2 calls = 100 accrued: at zero, within grace.
+	now = t0.Add(5 * time.Minute)
+	if cos, err := wd.RunOnce(context.Background()); err != nil || len(cos) != 0 {
+		t.Fatalf("expected no cutoff within grace, got %+v err=%v", cos, err)
+	}
+
+	// 7 minutes x 2 = 140 accrued: -40 < -20.
+	now = t0.Add(7 * time.Minute)
+	cos, err := wd.RunOnce(context.Background())
+	if err != nil || len(cos) != 2 || cos[0].RemainingMinor != -40 {
+		t.Fatalf("expected both calls cut off, got %+v err=%v", cos, err)
+	}
+	if len(term.hungUp) != 2 || term.hungUp[0] != "p1|bye" {
+		t.Fatalf("unexpected hangups: %v", term.hungUp)
+	}
+
+	// Already cut off calls are not hung up twice.
+	if cos, _ := wd.RunOnce(context.Background()); len(cos) != 0 {
+		t.Fatalf("expected no repeat cutoffs, got %+v", cos)
+	}
+}
+
+func TestWatchdog_UsesSpendableBalance(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	q := pricing.RateQuote{WorkspaceID: "w", Currency: "USD", RatePerMinuteMinor: 10, BillingIncrementSeconds: 60}
+	run := func(b stubBalance) []Cutoff {
+		store := NewMemoryStore()
+		_ = store.Put(context.Background(), ActiveCall{WorkspaceID: "w", CallID: "c1", WalletID: "wa", ProviderCallID: "p1", Quote: q, ConnectedAt: t0})
+		wd := Watchdog{Calls: store, Wallet: b, Terminator: &recordingTerminator{}, Now: func() time.Time { return t0.Add(10 * time.Minute) }}
+		cos, err := wd.RunOnce(context.Background())
+		if err != nil {
+			t.Fatalf("RunOnce: %v", err)
+		}
+		return cos
+	}
+	// 100 accrued against a zero balance with a 200 credit line: still covered.
+	if cos := run(stubBalance{minor: 0, creditLimit: 200}); len(cos) != 0 {
+		t.Fatalf("expected credit line to cover the call, got %+v", cos)
+	}
+	// 150 balance but 100 held elsewhere: only 50 spendable.
+	if cos := run(stubBalance{minor: 150, held: 100}); len(cos) != 1 || cos[0].RemainingMinor != -50 {
+		t.Fatalf("expected held funds excluded, got %+v", cos)
+	}
+}
+
+func TestWatchdog_LeavesCallLiveWhenItCannotHangUp(t *testing.T) {
+	store := NewMemoryStore()
+	t0 := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	wd := Watchdog{Calls: store, Wallet: stubBalance{minor: 0}, Terminator: &recordingTerminator{}, Now: func() time.Time { return t0.Add(time.Hour) }}
+	q := pricing.RateQuote{WorkspaceID: "w", Currency: "USD", RatePerMinuteMinor: 10, BillingIncrementSeconds: 60}
+	_ = store.Put(context.Background(), ActiveCall{WorkspaceID: "w", CallID: "c1", WalletID: "wa", Quote: q, ConnectedAt: t0})
+
+	cos, err := wd.RunOnce(context.Background())
+	if err != nil || len(cos) != 1 || !errors.Is(cos[0].Err, ErrCannotTerminate) {
+		t.Fatalf("expected ErrCannotTerminate for call without provider id, got %+v err=%v", cos, err)
+	}
+	wd.Terminator = nil
+	_ = store.Put(context.Background(), ActiveCall{WorkspaceID: "w", CallID: "c1", WalletID: "wa", ProviderCallID: "p1", Quote: q, ConnectedAt: t0})
+	cos, err = wd.RunOnce(context.Background())
+	if err != nil || len(cos) != 1 || !errors.Is(cos[0].Err, ErrCannotTerminate) {
+		t.Fatalf("expected ErrCannotTerminate without terminator, got %+v err=%v", cos, err)
+	}
+	if c, _, _ := store.Get(context.Background(), "w", "c1"); c.CutoffAt != nil {
+		t.Fatalf("expected CutoffAt unset, got %v", c.CutoffAt)
+	}
+}
+
+func TestWatchdog_DisabledPolicySkips(t *testing.T) {
+	store := NewMemoryStore()
+	policies := NewMemoryPolicyStore()
+	policies.SetWatchdogPolicy(WatchdogPolicy{WorkspaceID: "w", Disabled: true})
+	t0 := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	wd := Watchdog{Calls: store, Wallet: stubBalance{minor: 0}, Policies: policies, Terminator: &recordingTerminator{}, Now: func() time.Time { return t0.Add(time.Hour) }}
+	q := pricing.RateQuote{WorkspaceID: "w", Currency: "USD", RatePerMinuteMinor: 10, BillingIncrementSeconds: 60}
+	_ = store.Put(context.Background(), ActiveCall{WorkspaceID: "w", CallID: "c1", WalletID: "wa", ProviderCallID: "p1", Quote: q, ConnectedAt: t0})
+
+	if cos, err := wd.RunOnce(context.Background()); err != nil || len(cos) != 0 {
+		t.Fatalf("expected no cutoff when disabled, got %+v err=%v", cos, err)
+	}
+}
@@ -0,0 +1,160 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"telecom-platform/internal/wallet"
+	"telecom-platform/pkg/logger"
+)
+
+// Mid-call balance exhaustion cutoff.
+//
+// The watchdog periodically compares, per wallet, the spendable balance (available plus credit line) against the
+// cost accrued by that wallet's live calls but not yet captured. When the remainder
+// falls below -GraceMinor, each of those calls is ended with a polite announcement.
+// Calls younger than GraceSeconds are left alone. A call is only marked cut off
+// once the hangup succeeds; failed or impossible hangups are retried next run.
+
+// CallTerminator ends a live call at the provider.
+// telephony.CallTerminator implementations satisfy it.
+type CallTerminator interface {
+	HangupCall(ctx context.Context, workspaceID, providerCallID, announcement string) error
+}
+
+// WatchdogPolicyStore loads per-workspace cutoff policies.
+// ok=false means the default policy (enabled, no grace) applies.
+type WatchdogPolicyStore interface {
+	GetWatchdogPolicy(ctx context.Context, workspaceID string) (WatchdogPolicy, bool, error)
+}
+
+// Watchdog cuts off calls whose wallet can no longer cover them.
+type Watchdog struct {
+	Calls      ActiveCallStore
+	Wallet     wallet.BalanceService
+	Policies   WatchdogPolicyStore
+	Terminator CallTerminator
+
+	Now func() time.Time
+}
+
+// RunOnce evaluates every live call once and returns the cutoffs issued.
+func (w Watchdog) RunOnce(ctx context.Context) ([]Cutoff, error) {
+	now := time.Now().UTC()
+	if w.Now != nil {
+		now = w.Now().UTC()
+	}
+	active, err := w.Calls.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group by wallet: concurrent calls draw on the same balance.
+	type walletKey struct{ workspaceID, walletID string }
+	byWallet := map[walletKey][]ActiveCall{}
+	var order []walletKey
+	for _, c := range active {
+		if c.CutoffAt != nil {
+			continue
+		}
+		k := walletKey{c.WorkspaceID, c.WalletID}
+		if _, ok := byWallet[k]; !ok {
+			order = append(order, k)
+		}
+		byWallet[k] = append(byWallet[k], c)
+	}
+
+	var out []Cutoff
+	for _, k := range order {
+		policy, err := w.policy(ctx, k.workspaceID)
+		if err != nil {
+			return out, err
+		}
+		if policy.Disabled {
+			continue
+		}
+		bal, err := w.Wallet.GetBalance(ctx, k.workspaceID, k.walletID)
+		if err != nil {
+			return out, err
+		}
+
+		// Holds are already committed elsewhere; a credit line extends what calls may draw.
+		remaining := bal.Spendable()
+		for _, c := range byWallet[k] {
+			elapsed := int(now.Sub(c.ConnectedAt) / time.Second)
+			if accrued := c.Quote.CostMinor(elapsed) - c.BilledMinor; accrued > 0 {
+				remaining -= accrued
+			}
+		}
+		if remaining >= -policy.GraceMinor {
+			continue
+		}
+
+		announcement := policy.Announcement
+		if announcement == "" {
+			announcement = DefaultCutoffAnnouncement
+		}
+		for _, c := range byWallet[k] {
+			if now.Sub(c.ConnectedAt) < time.Duration(policy.GraceSeconds)*time.Second {
+				continue
+			}
+			co := Cutoff{WorkspaceID: c.WorkspaceID, CallID: c.CallID, WalletID: c.WalletID, ProviderCallID: c.ProviderCallID, RemainingMinor: remaining}
+			switch {
+			case w.Terminator == nil:
+				co.Err = fmt.Errorf("%w: no terminator configured", ErrCannotTerminate)
+			case c.ProviderCallID == "":
+				co.Err = fmt.Errorf("%w: call has no provider call id", ErrCannotTerminate)
+			default:
+				co.Err = w.Terminator.HangupCall(ctx, c.WorkspaceID, c.ProviderCallID, announcement)
+			}
+			if co.Err == nil {
+				at := now
+				c.CutoffAt = &at
+				if err := w.Calls.Put(ctx, c); err != nil {
+					return out, err
+				}
+			}
+			out = append(out, co)
+		}
+	}
+	return out, nil
+}
+
+func (w Watchdog) policy(ctx context.Context, workspaceID string) (WatchdogPolicy, error) {
+	if w.Policies == nil {
+		return WatchdogPolicy{WorkspaceID: workspaceID}, nil
+	}
+	p, ok, err := w.Policies.GetWatchdogPolicy(ctx, workspaceID)
+	if err != nil {
+		return WatchdogPolicy{}, err
+	}
+	if !ok {
+		return WatchdogPolicy{WorkspaceID: workspaceID}, nil
+	}
+	return p, nil
+}
+
+// Run evaluates on every tick until ctx is canceled.
+func (w Watchdog) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cutoffs, err := w.RunOnce(ctx)
+			if err != nil {
+				logger.From(ctx).Error("balance watchdog run failed", "err", err)
+			}
+			for _, co := range cutoffs {
+				if co.Err != nil {
+					logger.From(ctx).Error("balance cutoff hangup failed", "workspace_id", co.WorkspaceID, "call_id", co.CallID, "err", co.Err)
+					continue
+				}
+				logger.From(ctx).Info("call cut off: funds exhausted", "workspace_id", co.WorkspaceID, "call_id", co.CallID, "remaining_minor", co.RemainingMinor)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package callbacks
+
+import "time"
+
+// Status is the lifecycle of a callback task.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusDialing   Status = "dialing"
+	StatusCompleted Status = "completed" // call originated
+	StatusFailed    Status = "failed"    // gave up after MaxAttempts
+	StatusCanceled  Status = "canceled"
+	StatusSkipped   Status = "skipped" // number failed lead screening
+)
+
+// Callback is a caller's request to be called back later, captured by IVR when
+// routing could not connect (closed hours, overflow, no available destination).
+type Callback struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	CampaignID  string `json:"campaign_id" db:"campaign_id"`
+
+	// Number is the E.164 number to call back.
+	Number string `json:"number" db:"number"`
+	// PreferredAt is the earliest time the dialer may originate the callback.
+	PreferredAt time.Time `json:"preferred_at" db:"preferred_at"`
+
+	// Reason is the routing reason that triggered the offer (e.g. "campaign_closed").
+	Reason string `json:"reason,omitempty" db:"reason"`
+	// SourceProviderCallID links the callback to the inbound call that requested it.
+	SourceProviderCallID string `json:"source_provider_call_id,omitempty" db:"source_provider_call_id"`
+
+	Status    Status `json:"status" db:"status"`
+	Attempts  int    `json:"attempts" db:"attempts"`
+	LastError string `json:"last_error,omitempty" db:"last_error"`
+
+	// ProviderCallID is the originated outbound call (set once dialed).
+	ProviderCallID string `json:"provider_call_id,omitempty" db:"provider_call_id"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ListFilter narrows ListCallbacks. Empty fields match everything.
+type ListFilter struct {
+	Status     Status
+	CampaignID string
+}
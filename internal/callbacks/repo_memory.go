@@ -0,0 +1,92 @@
+package callbacks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryRepo is a simple in-memory Repository useful for tests.
+// It is not intended for production use.
+
+type MemoryRepo struct {
+	mu    sync.Mutex
+	items map[string]Callback
+}
+
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{items: map[string]Callback{}}
+}
+
+func (r *MemoryRepo) Create(ctx context.Context, cb Callback) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items[cb.ID] = cb
+	return nil
+}
+
+func (r *MemoryRepo) Update(ctx context.Context, cb Callback) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cur, ok := r.items[cb.ID]
+	if !ok || cur.WorkspaceID != cb.WorkspaceID {
+		return ErrNotFound
+	}
+	r.items[cb.ID] = cb
+	return nil
+}
+
+func (r *MemoryRepo) Get(ctx context.Context, workspaceID, callbackID string) (Callback, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cb, ok := r.items[callbackID]
+	if !ok || cb.WorkspaceID != workspaceID {
+		return Callback{}, false, nil
+	}
+	return cb, true, nil
+}
+
+func (r *MemoryRepo) List(ctx context.Context, workspaceID string, f ListFilter) ([]Callback, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Callback
+	for _, cb := range r.items {
+		if cb.WorkspaceID != workspaceID {
+			continue
+		}
+		if f.Status != "" && cb.Status != f.Status {
+			continue
+		}
+		if f.CampaignID != "" && cb.CampaignID != f.CampaignID {
+			continue
+		}
+		out = append(out, cb)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].PreferredAt.Before(out[j].PreferredAt) })
+	return out, nil
+}
+
+func (r *MemoryRepo) ListDue(ctx context.Context, now time.Time) ([]Callback, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Callback
+	for _, cb := range r.items {
+		if cb.Status == StatusPending && !cb.PreferredAt.After(now) {
+			out = append(out, cb)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].PreferredAt.Before(out[j].PreferredAt) })
+	return out, nil
+}
+
+func (r *MemoryRepo) HasOpen(ctx context.Context, workspaceID, number string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, cb := range r.items {
+		if cb.WorkspaceID == workspaceID && cb.Number == number && (cb.Status == StatusPending || cb.Status == StatusDialing) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,226 @@
+package callbacks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/audit"
+	"telecom-platform/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Callback requests.
+//
+// Flow:
+// - Routing offers a callback when it cannot connect and the caller accepts via IVR;
+//   the routing layer calls RequestCallback with the caller's number and preferred time.
+// - The dialer worker calls RunOnce, which originates due callbacks through the
+//   Originator. Failed originations are retried with RetryBackoff up to MaxAttempts.
+// - Pending callbacks are visible (and cancelable) through the API.
+
+// Originator places the outbound callback call (dialer/provider adapter).
+type Originator interface {
+	Originate(ctx context.Context, workspaceID, campaignID, to string) (providerCallID string, err error)
+}
+
+// Screener decides whether a number is worth dialing (e.g. lookups.Service, which
+// skips invalid numbers).
+type Screener interface {
+	Screen(ctx context.Context, workspaceID, number string) (skip bool, reason string, err error)
+}
+
+// Repository persists callbacks. Implementations must enforce workspace filtering.
+type Repository interface {
+	Create(ctx context.Context, cb Callback) error
+	Update(ctx context.Context, cb Callback) error
+	Get(ctx context.Context, workspaceID, callbackID string) (Callback, bool, error)
+	List(ctx context.Context, workspaceID string, f ListFilter) ([]Callback, error)
+	// ListDue returns pending callbacks with PreferredAt <= now across all workspaces.
+	ListDue(ctx context.Context, now time.Time) ([]Callback, error)
+	// HasOpen reports whether number already has a pending/dialing callback in the workspace.
+	HasOpen(ctx context.Context, workspaceID, number string) (bool, error)
+}
+
+var (
+	ErrInvalidArgument = errors.New("callbacks: invalid argument")
+	ErrNotFound        = errors.New("callbacks: callback not found")
+	ErrDuplicate       = errors.New("callbacks: callback already pending for number")
+	ErrNotCancelable   = errors.New("callbacks: callback is no longer pending")
+)
+
+type Service struct {
+	repo       Repository
+	originator Originator
+	audit      *audit.Service
+	clock      func() time.Time
+
+	MaxAttempts  int
+	RetryBackoff time.Duration
+
+	// Screener is optional. Screening errors are logged and the callback is dialed anyway.
+	Screener Screener
+}
+
+func NewService(repo Repository, originator Originator, auditSvc *audit.Service) *Service {
+	return &Service{repo: repo, originator: originator, audit: auditSvc, clock: time.Now, MaxAttempts: 3, RetryBackoff: 15 * time.Minute}
+}
+
+// RequestCallback creates a pending callback. A number with an open callback in the
+// workspace is rejected with ErrDuplicate so repeat callers are not dialed twice.
+func (s *Service) RequestCallback(ctx context.Context, workspaceID, campaignID, number string, preferredAt time.Time, reason, sourceProviderCallID string) (Callback, error) {
+	number = strings.TrimSpace(number)
+	if workspaceID == "" || number == "" || !strings.HasPrefix(number, "+") {
+		return Callback{}, ErrInvalidArgument
+	}
+	open, err := s.repo.HasOpen(ctx, workspaceID, number)
+	if err != nil {
+		return Callback{}, err
+	}
+	if open {
+		return Callback{}, ErrDuplicate
+	}
+	now := s.clock().UTC()
+	if preferredAt.IsZero() || preferredAt.Before(now) {
+		preferredAt = now
+	}
+	cb := Callback{
+		ID:                   uuid.NewString(),
+		WorkspaceID:          workspaceID,
+		CampaignID:           campaignID,
+		Number:               number,
+		PreferredAt:          preferredAt.UTC(),
+		Reason:               reason,
+		SourceProviderCallID: sourceProviderCallID,
+		Status:               StatusPending,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	if err := s.repo.Create(ctx, cb); err != nil {
+		return Callback{}, err
+	}
+	return cb, nil
+}
+
+// ListCallbacks returns the workspace's callbacks (e.g. Status=pending for the queue view).
+func (s *Service) ListCallbacks(ctx context.Context, workspaceID string, f ListFilter) ([]Callback, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	return s.repo.List(ctx, workspaceID, f)
+}
+
+// Cancel cancels a pending callback.
+func (s *Service) Cancel(ctx context.Context, workspaceID, callbackID, actorUserID, actorRole string) (Callback, error) {
+	if workspaceID == "" || callbackID == "" {
+		return Callback{}, ErrInvalidArgument
+	}
+	cb, ok, err := s.repo.Get(ctx, workspaceID, callbackID)
+	if err != nil {
+		return Callback{}, err
+	}
+	if !ok {
+		return Callback{}, ErrNotFound
+	}
+	if cb.Status != StatusPending {
+		return Callback{}, ErrNotCancelable
+	}
+	cb.Status = StatusCanceled
+	cb.UpdatedAt = s.clock().UTC()
+	if err := s.repo.Update(ctx, cb); err != nil {
+		return Callback{}, err
+	}
+	if s.audit != nil {
+		meta, _ := json.Marshal(map[string]string{"callback_id": cb.ID})
+		_ = s.audit.LogAdminAction(ctx, workspaceID, actorUserID, actorRole, "", "callback canceled", "", string(meta))
+	}
+	return cb, nil
+}
+
+// RunOnce originates due callbacks and returns how many were dialed.
+func (s *Service) RunOnce(ctx context.Context) (int, error) {
+	if s.originator == nil {
+		return 0, errors.New("callbacks: originator not configured")
+	}
+	now := s.clock().UTC()
+	due, err := s.repo.ListDue(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	dialed := 0
+	for _, cb := range due {
+		cb.Status = StatusDialing
+		cb.Attempts++
+		cb.UpdatedAt = now
+		if err := s.repo.Update(ctx, cb); err != nil {
+			logger.From(ctx).Error("callback claim failed", "callback_id", cb.ID, "err", err)
+			continue
+		}
+
+		if s.skip(ctx, &cb) {
+			continue
+		}
+
+		pcid, err := s.originator.Originate(ctx, cb.WorkspaceID, cb.CampaignID, cb.Number)
+		if err != nil {
+			cb.LastError = err.Error()
+			if cb.Attempts >= s.MaxAttempts {
+				cb.Status = StatusFailed
+			} else {
+				cb.Status = StatusPending
+				cb.PreferredAt = now.Add(s.RetryBackoff)
+			}
+		} else {
+			cb.Status = StatusCompleted
+			cb.ProviderCallID = pcid
+			cb.LastError = ""
+			dialed++
+		}
+		cb.UpdatedAt = s.clock().UTC()
+		if err := s.repo.Update(ctx, cb); err != nil {
+			logger.From(ctx).Error("callback update failed", "callback_id", cb.ID, "err", err)
+		}
+	}
+	return dialed, nil
+}
+
+// skip screens cb and, when it should not be dialed, records it as skipped.
+func (s *Service) skip(ctx context.Context, cb *Callback) bool {
+	if s.Screener == nil {
+		return false
+	}
+	skip, reason, err := s.Screener.Screen(ctx, cb.WorkspaceID, cb.Number)
+	if err != nil {
+		logger.From(ctx).Error("callback screening failed", "callback_id", cb.ID, "err", err)
+		return false
+	}
+	if !skip {
+		return false
+	}
+	cb.Status = StatusSkipped
+	cb.LastError = reason
+	cb.UpdatedAt = s.clock().UTC()
+	if err := s.repo.Update(ctx, *cb); err != nil {
+		logger.From(ctx).Error("callback update failed", "callback_id", cb.ID, "err", err)
+	}
+	return true
+}
+
+// Run calls RunOnce every interval until ctx is canceled.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.RunOnce(ctx); err != nil {
+				logger.From(ctx).Error("callback dispatch failed", "err", err)
+			}
+		}
+	}
+}
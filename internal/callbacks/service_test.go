@@ -0,0 +1,109 @@
+package callbacks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type stubOriginator struct {
+	fail  int
+	calls []string
+}
+
+func (o *stubOriginator) Originate(ctx context.Context, workspaceID, campaignID, to string) (string, error) {
+	o.calls = append(o.calls, to)
+	if o.fail > 0 {
+		o.fail--
+		return "", errors.New("carrier busy")
+	}
+	return "CA-out", nil
+}
+
+func TestService_DispatchesDueCallbacksWithRetry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+	orig := &stubOriginator{fail: 1}
+	svc := NewService(NewMemoryRepo(), orig, nil)
+	svc.clock = func() time.Time { return now }
+
+	cb, err := svc.RequestCallback(ctx, "w", "c", "+15551230000", now.Add(time.Hour), "campaign_closed", "CA-in")
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if _, err := svc.RequestCallback(ctx, "w", "c", "+15551230000", time.Time{}, "", ""); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("expected duplicate, got %v", err)
+	}
+
+	if n, _ := svc.RunOnce(ctx); n != 0 || len(orig.calls) != 0 {
+		t.Fatalf("callback dialed before preferred time")
+	}
+
+	now = now.Add(time.Hour)
+	if n, _ := svc.RunOnce(ctx); n != 0 {
+		t.Fatalf("expected first attempt to fail")
+	}
+	pending, _ := svc.ListCallbacks(ctx, "w", ListFilter{Status: StatusPending})
+	if len(pending) != 1 || pending[0].Attempts != 1 || !pending[0].PreferredAt.Equal(now.Add(svc.RetryBackoff)) {
+		t.Fatalf("expected retry scheduled, got %+v", pending)
+	}
+
+	now = now.Add(svc.RetryBackoff)
+	if n, _ := svc.RunOnce(ctx); n != 1 {
+		t.Fatalf("expected callback dialed")
+	}
+	done, _ := svc.ListCallbacks(ctx, "w", ListFilter{Status: StatusCompleted})
+	if len(done) != 1 || done[0].ID != cb.ID || done[0].ProviderCallID != "CA-out" {
+		t.Fatalf("unexpected completed callbacks: %+v", done)
+	}
+}
+
+func TestService_CancelOnlyPending(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryRepo(), &stubOriginator{}, nil)
+	cb, _ := svc.RequestCallback(ctx, "w", "c", "+15551230000", time.Time{}, "", "")
+
+	if _, err := svc.Cancel(ctx, "other", cb.ID, "u", "owner"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected workspace isolation, got %v", err)
+	}
+	if _, err := svc.Cancel(ctx, "w", cb.ID, "u", "owner"); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if _, err := svc.Cancel(ctx, "w", cb.ID, "u", "owner"); !errors.Is(err, ErrNotCancelable) {
+		t.Fatalf("expected not cancelable, got %v", err)
+	}
+	if n, _ := svc.RunOnce(ctx); n != 0 {
+		t.Fatalf("canceled callback must not be dialed")
+	}
+}
+
+type stubScreener struct{ invalid map[string]bool }
+
+func (s stubScreener) Screen(ctx context.Context, workspaceID, number string) (bool, string, error) {
+	if s.invalid[number] {
+		return true, "invalid number", nil
+	}
+	return false, "", nil
+}
+
+func TestService_SkipsScreenedNumbers(t *testing.T) {
+	ctx := context.Background()
+	orig := &stubOriginator{}
+	svc := NewService(NewMemoryRepo(), orig, nil)
+	svc.Screener = stubScreener{invalid: map[string]bool{"+15550000000": true}}
+
+	if _, err := svc.RequestCallback(ctx, "w", "c", "+15550000000", time.Time{}, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.RequestCallback(ctx, "w", "c", "+15551230000", time.Time{}, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := svc.RunOnce(ctx); n != 1 || len(orig.calls) != 1 || orig.calls[0] != "+15551230000" {
+		t.Fatalf("expected only the valid number dialed, got %d %v", n, orig.calls)
+	}
+	skipped, _ := svc.ListCallbacks(ctx, "w", ListFilter{Status: StatusSkipped})
+	if len(skipped) != 1 || skipped[0].Number != "+15550000000" || skipped[0].LastError != "invalid number" {
+		t.Fatalf("unexpected skipped callbacks: %+v", skipped)
+	}
+}
@@ -0,0 +1,171 @@
+package calls
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Call hold and resume.
+//
+// An agent puts the remote party of a live call on hold music and takes it off
+// again through the provider. Each hold is a HoldPeriod on the call's timeline;
+// on resume its length is added to Call.HoldSeconds, so talk-time reports can
+// leave hold time out (Call.TalkSeconds).
+//
+// Not to be confused with legal holds (legal_hold.go), which preserve data.
+
+// HoldController drives hold on the live call. telephony.CallHolder providers
+// implement it.
+type HoldController interface {
+	HoldCall(ctx context.Context, workspaceID, providerCallID, musicURL string) error
+	ResumeCall(ctx context.Context, workspaceID, providerCallID string) error
+}
+
+// HoldPeriod is one stretch of a call spent on hold.
+type HoldPeriod struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	CallID      string `json:"call_id" db:"call_id"`
+
+	StartedAt time.Time `json:"started_at" db:"started_at"`
+	StartedBy string    `json:"started_by" db:"started_by"`
+
+	// EndedAt is nil while the call is still on hold.
+	EndedAt *time.Time `json:"ended_at,omitempty" db:"ended_at"`
+	EndedBy string     `json:"ended_by,omitempty" db:"ended_by"`
+}
+
+// Seconds is the period's length, up to now while it is still open.
+func (p HoldPeriod) Seconds(now time.Time) int {
+	end := now
+	if p.EndedAt != nil {
+		end = *p.EndedAt
+	}
+	if d := end.Sub(p.StartedAt); d > 0 {
+		return int(d / time.Second)
+	}
+	return 0
+}
+
+// CallStore reads live calls and records their hold time. Implementations must
+// enforce workspace filtering.
+type CallStore interface {
+	GetCall(ctx context.Context, workspaceID, callID string) (Call, bool, error)
+	AddHoldSeconds(ctx context.Context, workspaceID, callID string, seconds int) error
+}
+
+// HoldPeriodStore persists the hold timeline. Implementations must enforce
+// workspace filtering.
+type HoldPeriodStore interface {
+	CreateHoldPeriod(ctx context.Context, p HoldPeriod) error
+	UpdateHoldPeriod(ctx context.Context, p HoldPeriod) error
+	// OpenHoldPeriod returns the call's unfinished period, if any.
+	OpenHoldPeriod(ctx context.Context, workspaceID, callID string) (HoldPeriod, bool, error)
+	// ListHoldPeriods returns the call's periods, oldest first.
+	ListHoldPeriods(ctx context.Context, workspaceID, callID string) ([]HoldPeriod, error)
+}
+
+var (
+	ErrInvalidCallHold = errors.New("calls: invalid hold request")
+	ErrCallNotFound    = errors.New("calls: call not found")
+	ErrCallNotLive     = errors.New("calls: call is not in progress")
+	ErrCallOnHold      = errors.New("calls: call is already on hold")
+	ErrCallNotOnHold   = errors.New("calls: call is not on hold")
+)
+
+type CallHoldService struct {
+	provider HoldController
+	calls    CallStore
+	periods  HoldPeriodStore
+	clock    func() time.Time
+
+	// MusicURL is the hold music; empty uses the provider's default.
+	MusicURL string
+}
+
+func NewCallHoldService(provider HoldController, calls CallStore, periods HoldPeriodStore) *CallHoldService {
+	return &CallHoldService{provider: provider, calls: calls, periods: periods, clock: time.Now}
+}
+
+// Hold puts the remote party of a live call on hold music.
+func (s *CallHoldService) Hold(ctx context.Context, workspaceID, callID, actorUserID string) (HoldPeriod, error) {
+	c, err := s.liveCall(ctx, workspaceID, callID, actorUserID)
+	if err != nil {
+		return HoldPeriod{}, err
+	}
+	if _, open, err := s.periods.OpenHoldPeriod(ctx, workspaceID, callID); err != nil {
+		return HoldPeriod{}, err
+	} else if open {
+		return HoldPeriod{}, ErrCallOnHold
+	}
+	if err := s.provider.HoldCall(ctx, workspaceID, c.ProviderCallID, s.MusicURL); err != nil {
+		return HoldPeriod{}, err
+	}
+	p := HoldPeriod{
+		ID:          uuid.NewString(),
+		WorkspaceID: workspaceID,
+		CallID:      callID,
+		StartedAt:   s.clock().UTC(),
+		StartedBy:   actorUserID,
+	}
+	if err := s.periods.CreateHoldPeriod(ctx, p); err != nil {
+		return HoldPeriod{}, err
+	}
+	return p, nil
+}
+
+// Resume takes the call off hold and adds the period to the call's hold time.
+func (s *CallHoldService) Resume(ctx context.Context, workspaceID, callID, actorUserID string) (HoldPeriod, error) {
+	c, err := s.liveCall(ctx, workspaceID, callID, actorUserID)
+	if err != nil {
+		return HoldPeriod{}, err
+	}
+	p, open, err := s.periods.OpenHoldPeriod(ctx, workspaceID, callID)
+	if err != nil {
+		return HoldPeriod{}, err
+	}
+	if !open {
+		return HoldPeriod{}, ErrCallNotOnHold
+	}
+	if err := s.provider.ResumeCall(ctx, workspaceID, c.ProviderCallID); err != nil {
+		return HoldPeriod{}, err
+	}
+	now := s.clock().UTC()
+	p.EndedAt = &now
+	p.EndedBy = actorUserID
+	if err := s.periods.UpdateHoldPeriod(ctx, p); err != nil {
+		return HoldPeriod{}, err
+	}
+	if err := s.calls.AddHoldSeconds(ctx, workspaceID, callID, p.Seconds(now)); err != nil {
+		return HoldPeriod{}, err
+	}
+	return p, nil
+}
+
+// Timeline lists the call's hold periods, oldest first.
+func (s *CallHoldService) Timeline(ctx context.Context, workspaceID, callID string) ([]HoldPeriod, error) {
+	if workspaceID == "" || callID == "" {
+		return nil, ErrInvalidCallHold
+	}
+	return s.periods.ListHoldPeriods(ctx, workspaceID, callID)
+}
+
+func (s *CallHoldService) liveCall(ctx context.Context, workspaceID, callID, actorUserID string) (Call, error) {
+	if workspaceID == "" || callID == "" || actorUserID == "" {
+		return Call{}, ErrInvalidCallHold
+	}
+	c, ok, err := s.calls.GetCall(ctx, workspaceID, callID)
+	if err != nil {
+		return Call{}, err
+	}
+	if !ok {
+		return Call{}, ErrCallNotFound
+	}
+	if c.Status != CallStatusInProgress || c.ProviderCallID == "" {
+		return Call{}, ErrCallNotLive
+	}
+	return c, nil
+}
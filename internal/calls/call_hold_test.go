@@ -0,0 +1,90 @@
+package calls
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type stubHoldController struct {
+	held    map[string]bool
+	failing error
+}
+
+func (s *stubHoldController) HoldCall(ctx context.Context, workspaceID, providerCallID, musicURL string) error {
+	if s.failing != nil {
+		return s.failing
+	}
+	s.held[providerCallID] = true
+	return nil
+}
+
+func (s *stubHoldController) ResumeCall(ctx context.Context, workspaceID, providerCallID string) error {
+	s.held[providerCallID] = false
+	return nil
+}
+
+func TestCallHold_HoldResumeTracksHoldTime(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryCallStore(
+		Call{CallID: "c1", WorkspaceID: "w", ProviderCallID: "CA1", Status: CallStatusInProgress},
+		Call{CallID: "c2", WorkspaceID: "w", ProviderCallID: "CA2", Status: CallStatusCompleted},
+	)
+	provider := &stubHoldController{held: map[string]bool{}}
+	svc := NewCallHoldService(provider, store, store)
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	svc.clock = func() time.Time { return now }
+
+	if _, err := svc.Resume(ctx, "w", "c1", "agent"); !errors.Is(err, ErrCallNotOnHold) {
+		t.Fatalf("expected ErrCallNotOnHold, got %v", err)
+	}
+	if _, err := svc.Hold(ctx, "w", "c2", "agent"); !errors.Is(err, ErrCallNotLive) {
+		t.Fatalf("expected ended call to be rejected, got %v", err)
+	}
+	if _, err := svc.Hold(ctx, "other", "c1", "agent"); !errors.Is(err, ErrCallNotFound) {
+		t.Fatalf("calls must be workspace scoped, got %v", err)
+	}
+
+	for i, d := range []time.Duration{45 * time.Second, 30 * time.Second} {
+		if _, err := svc.Hold(ctx, "w", "c1", "agent"); err != nil {
+			t.Fatalf("hold %d: %v", i, err)
+		}
+		if !provider.held["CA1"] {
+			t.Fatalf("provider not asked to hold")
+		}
+		if _, err := svc.Hold(ctx, "w", "c1", "agent"); !errors.Is(err, ErrCallOnHold) {
+			t.Fatalf("expected ErrCallOnHold, got %v", err)
+		}
+		now = now.Add(d)
+		p, err := svc.Resume(ctx, "w", "c1", "agent2")
+		if err != nil || p.EndedAt == nil || p.EndedBy != "agent2" || p.Seconds(now) != int(d/time.Second) {
+			t.Fatalf("resume %d: %+v err=%v", i, p, err)
+		}
+		now = now.Add(time.Minute)
+	}
+	if provider.held["CA1"] {
+		t.Fatalf("provider not asked to resume")
+	}
+	c, _, _ := store.GetCall(ctx, "w", "c1")
+	if c.HoldSeconds != 75 {
+		t.Fatalf("expected 75s on hold, got %d", c.HoldSeconds)
+	}
+	timeline, _ := svc.Timeline(ctx, "w", "c1")
+	if len(timeline) != 2 {
+		t.Fatalf("expected 2 hold periods, got %+v", timeline)
+	}
+
+	c.DurationSeconds = 300
+	if c.TalkSeconds(false) != 300 || c.TalkSeconds(true) != 225 {
+		t.Fatalf("unexpected talk time %d/%d", c.TalkSeconds(false), c.TalkSeconds(true))
+	}
+
+	provider.failing = errors.New("provider down")
+	if _, err := svc.Hold(ctx, "w", "c1", "agent"); err == nil {
+		t.Fatalf("expected provider failure")
+	}
+	if _, open, _ := store.OpenHoldPeriod(ctx, "w", "c1"); open {
+		t.Fatalf("a failed provider hold must not open a period")
+	}
+}
@@ -0,0 +1,318 @@
+package calls
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"telecom-platform/internal/audit"
+
+	"github.com/google/uuid"
+)
+
+// Supervisor live monitoring.
+//
+// A manager joins a live call as an extra participant of the call's conference:
+// listen (muted), whisper (heard by the agent only) or barge (heard by both
+// parties). The mode can be switched without leaving.
+//
+// Every join, mode change and leave is audited, and the audit is mandatory: if
+// the event cannot be written, the action is not taken. Workspaces can ask for
+// the caller to be told (MonitorDisclosure) before the supervisor is connected.
+
+type MonitorMode string
+
+const (
+	MonitorModeListen  MonitorMode = "listen"
+	MonitorModeWhisper MonitorMode = "whisper"
+	MonitorModeBarge   MonitorMode = "barge"
+)
+
+func (m MonitorMode) Valid() bool {
+	switch m {
+	case MonitorModeListen, MonitorModeWhisper, MonitorModeBarge:
+		return true
+	}
+	return false
+}
+
+// MonitorBridge bridges a supervisor into the live call through a conference.
+// telephony.CallMonitor providers implement it.
+type MonitorBridge interface {
+	// JoinMonitor moves the call into a conference (if it is not in one yet),
+	// dials supervisorTarget into it in mode and returns the supervisor's leg.
+	JoinMonitor(ctx context.Context, workspaceID, providerCallID, supervisorTarget, mode string) (legID string, err error)
+	SetMonitorMode(ctx context.Context, workspaceID, providerCallID, legID, mode string) error
+	LeaveMonitor(ctx context.Context, workspaceID, providerCallID, legID string) error
+	// AnnounceToCaller plays message to the remote party only.
+	AnnounceToCaller(ctx context.Context, workspaceID, providerCallID, message string) error
+}
+
+// MonitorDisclosure is a workspace's caller disclosure setting. When Enabled,
+// Message is played to the caller before the supervisor joins in one of Modes
+// (barge only when empty).
+type MonitorDisclosure struct {
+	Enabled bool          `json:"enabled"`
+	Message string        `json:"message,omitempty"`
+	Modes   []MonitorMode `json:"modes,omitempty"`
+}
+
+const DefaultMonitorDisclosureMessage = "A supervisor is joining this call."
+
+func (d MonitorDisclosure) applies(mode MonitorMode) bool {
+	if !d.Enabled {
+		return false
+	}
+	if len(d.Modes) == 0 {
+		return mode == MonitorModeBarge
+	}
+	for _, m := range d.Modes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+func (d MonitorDisclosure) message() string {
+	if d.Message == "" {
+		return DefaultMonitorDisclosureMessage
+	}
+	return d.Message
+}
+
+// MonitorDisclosureStore reads disclosure settings; no row means disabled.
+type MonitorDisclosureStore interface {
+	GetMonitorDisclosure(ctx context.Context, workspaceID string) (MonitorDisclosure, bool, error)
+}
+
+// MonitorSession is one supervisor's presence on a call.
+type MonitorSession struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	CallID      string `json:"call_id" db:"call_id"`
+
+	SupervisorUserID string      `json:"supervisor_user_id" db:"supervisor_user_id"`
+	Mode             MonitorMode `json:"mode" db:"mode"`
+	// ProviderLegID is the supervisor's conference participant.
+	ProviderLegID string `json:"provider_leg_id" db:"provider_leg_id"`
+	// Disclosed is true when the caller was told about the supervisor.
+	Disclosed bool `json:"disclosed" db:"disclosed"`
+
+	StartedAt time.Time  `json:"started_at" db:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty" db:"ended_at"`
+}
+
+// MonitorSessionStore persists sessions. Implementations must enforce
+// workspace filtering.
+type MonitorSessionStore interface {
+	CreateMonitorSession(ctx context.Context, s MonitorSession) error
+	UpdateMonitorSession(ctx context.Context, s MonitorSession) error
+	GetMonitorSession(ctx context.Context, workspaceID, sessionID string) (MonitorSession, bool, error)
+	// OpenMonitorSession returns the supervisor's unfinished session on the call, if any.
+	OpenMonitorSession(ctx context.Context, workspaceID, callID, supervisorUserID string) (MonitorSession, bool, error)
+}
+
+var (
+	ErrInvalidMonitor         = errors.New("calls: invalid monitor request")
+	ErrMonitorSessionNotFound = errors.New("calls: monitor session not found")
+	ErrAlreadyMonitoring      = errors.New("calls: supervisor is already on this call")
+	ErrMonitorEnded           = errors.New("calls: monitor session has ended")
+	ErrMonitorAuditFailed     = errors.New("calls: monitor audit failed")
+)
+
+// MonitorRequest starts a session. SupervisorTarget is the number or SIP URI
+// the supervisor is reached at.
+type MonitorRequest struct {
+	Mode             MonitorMode `json:"mode"`
+	SupervisorTarget string      `json:"supervisor_target"`
+}
+
+type CallMonitorService struct {
+	bridge      MonitorBridge
+	calls       CallStore
+	sessions    MonitorSessionStore
+	disclosures MonitorDisclosureStore
+	audit       *audit.Service
+	clock       func() time.Time
+}
+
+// NewCallMonitorService requires an audit service; disclosures may be nil
+// (never disclose).
+func NewCallMonitorService(bridge MonitorBridge, calls CallStore, sessions MonitorSessionStore, disclosures MonitorDisclosureStore, auditSvc *audit.Service) *CallMonitorService {
+	return &CallMonitorService{bridge: bridge, calls: calls, sessions: sessions, disclosures: disclosures, audit: auditSvc, clock: time.Now}
+}
+
+// Start joins the supervisor to a live call.
+func (s *CallMonitorService) Start(ctx context.Context, workspaceID, callID, actorUserID, actorRole string, req MonitorRequest) (MonitorSession, error) {
+	if workspaceID == "" || callID == "" || actorUserID == "" || !req.Mode.Valid() || req.SupervisorTarget == "" {
+		return MonitorSession{}, ErrInvalidMonitor
+	}
+	c, ok, err := s.calls.GetCall(ctx, workspaceID, callID)
+	if err != nil {
+		return MonitorSession{}, err
+	}
+	if !ok {
+		return MonitorSession{}, ErrCallNotFound
+	}
+	if c.Status != CallStatusInProgress || c.ProviderCallID == "" {
+		return MonitorSession{}, ErrCallNotLive
+	}
+	if _, open, err := s.sessions.OpenMonitorSession(ctx, workspaceID, callID, actorUserID); err != nil {
+		return MonitorSession{}, err
+	} else if open {
+		return MonitorSession{}, ErrAlreadyMonitoring
+	}
+
+	sess := MonitorSession{
+		ID:               uuid.NewString(),
+		WorkspaceID:      workspaceID,
+		CallID:           callID,
+		SupervisorUserID: actorUserID,
+		Mode:             req.Mode,
+		StartedAt:        s.clock().UTC(),
+	}
+	disclosure, err := s.disclosure(ctx, workspaceID)
+	if err != nil {
+		return MonitorSession{}, err
+	}
+	sess.Disclosed = disclosure.applies(req.Mode)
+
+	if err := s.log(ctx, sess, actorRole, "supervisor joined call"); err != nil {
+		return MonitorSession{}, err
+	}
+	if sess.Disclosed {
+		if err := s.bridge.AnnounceToCaller(ctx, workspaceID, c.ProviderCallID, disclosure.message()); err != nil {
+			return MonitorSession{}, err
+		}
+	}
+	if sess.ProviderLegID, err = s.bridge.JoinMonitor(ctx, workspaceID, c.ProviderCallID, req.SupervisorTarget, string(req.Mode)); err != nil {
+		return MonitorSession{}, err
+	}
+	if err := s.sessions.CreateMonitorSession(ctx, sess); err != nil {
+		return MonitorSession{}, err
+	}
+	return sess, nil
+}
+
+// ChangeMode switches an open session between listen, whisper and barge.
+// Switching into a disclosed mode announces to the caller first, once.
+func (s *CallMonitorService) ChangeMode(ctx context.Context, workspaceID, callID, sessionID, actorUserID, actorRole string, mode MonitorMode) (MonitorSession, error) {
+	if !mode.Valid() {
+		return MonitorSession{}, ErrInvalidMonitor
+	}
+	sess, c, err := s.openSession(ctx, workspaceID, callID, sessionID, actorUserID)
+	if err != nil {
+		return MonitorSession{}, err
+	}
+	if sess.Mode == mode {
+		return sess, nil
+	}
+	prev := sess.Mode
+	sess.Mode = mode
+	disclosure, err := s.disclosure(ctx, workspaceID)
+	if err != nil {
+		return MonitorSession{}, err
+	}
+	announce := !sess.Disclosed && disclosure.applies(mode)
+	sess.Disclosed = sess.Disclosed || announce
+
+	if err := s.log(ctx, sess, actorRole, fmt.Sprintf("supervisor switched from %s to %s", prev, mode)); err != nil {
+		return MonitorSession{}, err
+	}
+	if announce {
+		if err := s.bridge.AnnounceToCaller(ctx, workspaceID, c.ProviderCallID, disclosure.message()); err != nil {
+			return MonitorSession{}, err
+		}
+	}
+	if err := s.bridge.SetMonitorMode(ctx, workspaceID, c.ProviderCallID, sess.ProviderLegID, string(mode)); err != nil {
+		return MonitorSession{}, err
+	}
+	if err := s.sessions.UpdateMonitorSession(ctx, sess); err != nil {
+		return MonitorSession{}, err
+	}
+	return sess, nil
+}
+
+// Stop removes the supervisor from the call.
+func (s *CallMonitorService) Stop(ctx context.Context, workspaceID, callID, sessionID, actorUserID, actorRole string) (MonitorSession, error) {
+	sess, c, err := s.openSession(ctx, workspaceID, callID, sessionID, actorUserID)
+	if err != nil {
+		return MonitorSession{}, err
+	}
+	now := s.clock().UTC()
+	sess.EndedAt = &now
+	if err := s.log(ctx, sess, actorRole, "supervisor left call"); err != nil {
+		return MonitorSession{}, err
+	}
+	if err := s.bridge.LeaveMonitor(ctx, workspaceID, c.ProviderCallID, sess.ProviderLegID); err != nil {
+		return MonitorSession{}, err
+	}
+	if err := s.sessions.UpdateMonitorSession(ctx, sess); err != nil {
+		return MonitorSession{}, err
+	}
+	return sess, nil
+}
+
+// openSession loads the actor's own open session on callID and the call.
+// Another supervisor's session is reported as not found.
+func (s *CallMonitorService) openSession(ctx context.Context, workspaceID, callID, sessionID, actorUserID string) (MonitorSession, Call, error) {
+	if workspaceID == "" || sessionID == "" || actorUserID == "" {
+		return MonitorSession{}, Call{}, ErrInvalidMonitor
+	}
+	sess, ok, err := s.sessions.GetMonitorSession(ctx, workspaceID, sessionID)
+	if err != nil {
+		return MonitorSession{}, Call{}, err
+	}
+	if !ok || sess.CallID != callID || sess.SupervisorUserID != actorUserID {
+		return MonitorSession{}, Call{}, ErrMonitorSessionNotFound
+	}
+	if sess.EndedAt != nil {
+		return MonitorSession{}, Call{}, ErrMonitorEnded
+	}
+	c, ok, err := s.calls.GetCall(ctx, workspaceID, sess.CallID)
+	if err != nil {
+		return MonitorSession{}, Call{}, err
+	}
+	if !ok {
+		return MonitorSession{}, Call{}, ErrCallNotFound
+	}
+	return sess, c, nil
+}
+
+func (s *CallMonitorService) disclosure(ctx context.Context, workspaceID string) (MonitorDisclosure, error) {
+	if s.disclosures == nil {
+		return MonitorDisclosure{}, nil
+	}
+	d, _, err := s.disclosures.GetMonitorDisclosure(ctx, workspaceID)
+	return d, err
+}
+
+// log writes the mandatory audit event; unlike most audit calls a failure
+// blocks the action.
+func (s *CallMonitorService) log(ctx context.Context, sess MonitorSession, actorRole, message string) error {
+	if s.audit == nil {
+		return ErrMonitorAuditFailed
+	}
+	meta, _ := json.Marshal(map[string]any{
+		"monitor_session_id": sess.ID,
+		"mode":               sess.Mode,
+		"disclosed":          sess.Disclosed,
+		"ended":              sess.EndedAt != nil,
+	})
+	err := s.audit.Append(ctx, audit.Event{
+		WorkspaceID: sess.WorkspaceID,
+		Type:        audit.EventTypeCallMonitor,
+		ActorUserID: sess.SupervisorUserID,
+		ActorRole:   actorRole,
+		CallID:      sess.CallID,
+		Message:     message,
+		Metadata:    string(meta),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMonitorAuditFailed, err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package calls
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"telecom-platform/internal/audit"
+)
+
+type stubMonitorBridge struct {
+	modes     map[string]string
+	announced []string
+}
+
+func (s *stubMonitorBridge) JoinMonitor(ctx context.Context, workspaceID, providerCallID, supervisorTarget, mode string) (string, error) {
+	leg := "leg-" + supervisorTarget
+	s.modes[leg] = mode
+	return leg, nil
+}
+
+func (s *stubMonitorBridge) SetMonitorMode(ctx context.Context, workspaceID, providerCallID, legID, mode string) error {
+	s.modes[legID] = mode
+	return nil
+}
+
+func (s *stubMonitorBridge) LeaveMonitor(ctx context.Context, workspaceID, providerCallID, legID string) error {
+	delete(s.modes, legID)
+	return nil
+}
+
+func (s *stubMonitorBridge) AnnounceToCaller(ctx context.Context, workspaceID, providerCallID, message string) error {
+	s.announced = append(s.announced, message)
+	return nil
+}
+
+func TestCallMonitor_ListenBargeLeaveIsAudited(t *testing.T) {
+	ctx := context.Background()
+	calls := NewMemoryCallStore(Call{CallID: "c1", WorkspaceID: "w", ProviderCallID: "CA1", Status: CallStatusInProgress})
+	monitors := NewMemoryMonitorStore()
+	monitors.Disclosures["w"] = MonitorDisclosure{Enabled: true}
+	bridge := &stubMonitorBridge{modes: map[string]string{}}
+	auditRepo := audit.NewMemoryRepo()
+	svc := NewCallMonitorService(bridge, calls, monitors, monitors, audit.NewService(auditRepo))
+
+	if _, err := svc.Start(ctx, "w", "c1", "mgr", "owner", MonitorRequest{Mode: "spy", SupervisorTarget: "+1"}); !errors.Is(err, ErrInvalidMonitor) {
+		t.Fatalf("expected invalid mode, got %v", err)
+	}
+	if _, err := svc.Start(ctx, "other", "c1", "mgr", "owner", MonitorRequest{Mode: MonitorModeListen, SupervisorTarget: "+1"}); !errors.Is(err, ErrCallNotFound) {
+		t.Fatalf("calls must be workspace scoped, got %v", err)
+	}
+
+	sess, err := svc.Start(ctx, "w", "c1", "mgr", "owner", MonitorRequest{Mode: MonitorModeListen, SupervisorTarget: "+1"})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if bridge.modes[sess.ProviderLegID] != "listen" || sess.Disclosed || len(bridge.announced) != 0 {
+		t.Fatalf("expected silent listen leg, got %+v announced=%v", sess, bridge.announced)
+	}
+	if _, err := svc.Start(ctx, "w", "c1", "mgr", "owner", MonitorRequest{Mode: MonitorModeBarge, SupervisorTarget: "+1"}); !errors.Is(err, ErrAlreadyMonitoring) {
+		t.Fatalf("expected ErrAlreadyMonitoring, got %v", err)
+	}
+	if _, err := svc.ChangeMode(ctx, "w", "c1", sess.ID, "mgr2", "owner", MonitorModeBarge); !errors.Is(err, ErrMonitorSessionNotFound) {
+		t.Fatalf("another supervisor's session must not be controllable, got %v", err)
+	}
+
+	sess, err = svc.ChangeMode(ctx, "w", "c1", sess.ID, "mgr", "owner", MonitorModeBarge)
+	if err != nil {
+		t.Fatalf("barge: %v", err)
+	}
+	if bridge.modes[sess.ProviderLegID] != "barge" || !sess.Disclosed || len(bridge.announced) != 1 || bridge.announced[0] != DefaultMonitorDisclosureMessage {
+		t.Fatalf("expected disclosed barge, got %+v announced=%v", sess, bridge.announced)
+	}
+	if _, err := svc.ChangeMode(ctx, "w", "c1", sess.ID, "mgr", "owner", MonitorModeWhisper); err != nil {
+		t.Fatalf("whisper: %v", err)
+	}
+	if _, err := svc.ChangeMode(ctx, "w", "c1", sess.ID, "mgr", "owner", MonitorModeBarge); err != nil || len(bridge.announced) != 1 {
+		t.Fatalf("expected a single disclosure per session, got %v announced=%v", err, bridge.announced)
+	}
+
+	if sess, err = svc.Stop(ctx, "w", "c1", sess.ID, "mgr", "owner"); err != nil || sess.EndedAt == nil {
+		t.Fatalf("stop: %+v %v", sess, err)
+	}
+	if _, ok := bridge.modes[sess.ProviderLegID]; ok {
+		t.Fatalf("expected supervisor leg removed")
+	}
+	if _, err := svc.Stop(ctx, "w", "c1", sess.ID, "mgr", "owner"); !errors.Is(err, ErrMonitorEnded) {
+		t.Fatalf("expected ErrMonitorEnded, got %v", err)
+	}
+
+	// join, barge, whisper, barge, leave
+	events := auditRepo.Events()
+	if len(events) != 5 {
+		t.Fatalf("expected 5 audit events, got %d", len(events))
+	}
+	for _, e := range events {
+		if e.Type != audit.EventTypeCallMonitor || e.CallID != "c1" || e.ActorUserID != "mgr" {
+			t.Fatalf("unexpected audit event %+v", e)
+		}
+	}
+}
+
+func TestCallMonitor_RefusedWithoutAudit(t *testing.T) {
+	ctx := context.Background()
+	calls := NewMemoryCallStore(Call{CallID: "c1", WorkspaceID: "w", ProviderCallID: "CA1", Status: CallStatusInProgress})
+	monitors := NewMemoryMonitorStore()
+	bridge := &stubMonitorBridge{modes: map[string]string{}}
+	svc := NewCallMonitorService(bridge, calls, monitors, nil, audit.NewService(nil))
+
+	if _, err := svc.Start(ctx, "w", "c1", "mgr", "owner", MonitorRequest{Mode: MonitorModeListen, SupervisorTarget: "+1"}); !errors.Is(err, ErrMonitorAuditFailed) {
+		t.Fatalf("expected ErrMonitorAuditFailed, got %v", err)
+	}
+	if len(bridge.modes) != 0 || len(monitors.Sessions) != 0 {
+		t.Fatalf("supervisor must not be joined without an audit record")
+	}
+}
@@ -0,0 +1,253 @@
+package calls
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Historical call backfill.
+//
+// Tenants migrating from another provider/platform upload CDR exports so their
+// reporting history carries over.
+//
+// Rules:
+// - Imported rows are marked Imported=true with the batch id; they are never billed
+//   (no wallet ledger entries, no call events on the bus).
+// - Rows are deduplicated per workspace by ExternalID, so re-uploading a file is safe.
+// - Bad rows are reported back and skipped; one bad row does not fail the batch.
+
+type ImportFormat string
+
+const (
+	// ImportFormatGeneric columns: call_id,from,to,status,duration_seconds,started_at[,campaign_id]
+	ImportFormatGeneric ImportFormat = "generic"
+	// ImportFormatTwilio is the Twilio console call log export.
+	ImportFormatTwilio ImportFormat = "twilio"
+)
+
+// MaxImportRows bounds a single upload.
+const MaxImportRows = 100_000
+
+// ImportStore persists imported calls.
+type ImportStore interface {
+	// InsertImported inserts calls whose (workspace_id, external_id) is not already present
+	// and returns how many were inserted.
+	InsertImported(ctx context.Context, workspaceID string, rows []Call) (int, error)
+	CreateImportBatch(ctx context.Context, b ImportBatch) error
+}
+
+// ImportBatch records one upload for support/audit.
+type ImportBatch struct {
+	ID          string       `json:"id" db:"id"`
+	WorkspaceID string       `json:"workspace_id" db:"workspace_id"`
+	Format      ImportFormat `json:"format" db:"format"`
+	CreatedBy   string       `json:"created_by" db:"created_by"`
+
+	TotalRows  int `json:"total_rows" db:"total_rows"`
+	Inserted   int `json:"inserted" db:"inserted"`
+	Duplicates int `json:"duplicates" db:"duplicates"`
+	Rejected   int `json:"rejected" db:"rejected"`
+
+	Errors []RowError `json:"errors,omitempty" db:"-"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// RowError describes a rejected input row (1-based, header excluded).
+type RowError struct {
+	Row    int    `json:"row"`
+	Reason string `json:"reason"`
+}
+
+var (
+	ErrInvalidImport  = errors.New("calls: invalid import")
+	ErrImportTooLarge = errors.New("calls: import exceeds row limit")
+)
+
+// Importer runs backfill imports.
+type Importer struct {
+	Store ImportStore
+	Now   func() time.Time
+}
+
+func NewImporter(store ImportStore) *Importer {
+	return &Importer{Store: store, Now: time.Now}
+}
+
+// ImportCSV parses r in the given format and stores valid rows for workspaceID.
+func (im *Importer) ImportCSV(ctx context.Context, workspaceID, actorUserID string, format ImportFormat, r io.Reader) (ImportBatch, error) {
+	if workspaceID == "" || actorUserID == "" {
+		return ImportBatch{}, ErrInvalidImport
+	}
+	batch := ImportBatch{ID: uuid.NewString(), WorkspaceID: workspaceID, Format: format, CreatedBy: actorUserID, CreatedAt: im.Now().UTC()}
+
+	rows, rowErrs, err := ParseCSV(r, format)
+	if err != nil {
+		return ImportBatch{}, err
+	}
+	batch.TotalRows = len(rows) + len(rowErrs)
+	batch.Rejected = len(rowErrs)
+	batch.Errors = rowErrs
+
+	for i := range rows {
+		rows[i].CallID = uuid.NewString()
+		rows[i].WorkspaceID = workspaceID
+		rows[i].Imported = true
+		rows[i].ImportBatchID = batch.ID
+		rows[i].UpdatedAt = batch.CreatedAt
+	}
+	inserted, err := im.Store.InsertImported(ctx, workspaceID, rows)
+	if err != nil {
+		return ImportBatch{}, err
+	}
+	batch.Inserted = inserted
+	batch.Duplicates = len(rows) - inserted
+
+	if err := im.Store.CreateImportBatch(ctx, batch); err != nil {
+		return ImportBatch{}, err
+	}
+	return batch, nil
+}
+
+type columnMap struct {
+	externalID, from, to, status, duration, startedAt, campaignID string
+	timeLayouts                                                   []string
+	statusMap                                                     map[string]CallStatus
+}
+
+var importFormats = map[ImportFormat]columnMap{
+	ImportFormatGeneric: {
+		externalID:  "call_id",
+		from:        "from",
+		to:          "to",
+		status:      "status",
+		duration:    "duration_seconds",
+		startedAt:   "started_at",
+		campaignID:  "campaign_id",
+		timeLayouts: []string{time.RFC3339},
+	},
+	// Twilio start times must carry a numeric offset (or UTC): time.Parse treats an
+	// unknown zone abbreviation as a zero-offset zone, which would silently skew it.
+	ImportFormatTwilio: {
+		externalID:  "sid",
+		from:        "from",
+		to:          "to",
+		status:      "status",
+		duration:    "duration",
+		startedAt:   "start time",
+		timeLayouts: []string{time.RFC3339, "2006-01-02 15:04:05 -0700", "2006-01-02 15:04:05 UTC", "Mon, 02 Jan 2006 15:04:05 -0700"},
+		statusMap:   map[string]CallStatus{"no-answer": CallStatusNoAnswer, "in-progress": CallStatusInProgress},
+	},
+}
+
+// ParseCSV converts an export into Call rows. Rows that cannot be parsed are
+// returned as RowErrors instead of failing the whole file.
+func ParseCSV(r io.Reader, format ImportFormat) ([]Call, []RowError, error) {
+	cm, ok := importFormats[format]
+	if !ok {
+		return nil, nil, ErrInvalidImport
+	}
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, ErrInvalidImport
+	}
+	idx := map[string]int{}
+	for i, h := range header {
+		idx[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, required := range []string{cm.externalID, cm.from, cm.to, cm.status, cm.startedAt} {
+		if _, ok := idx[required]; !ok {
+			return nil, nil, fmt.Errorf("%w: missing column %q", ErrInvalidImport, required)
+		}
+	}
+	get := func(rec []string, col string) string {
+		i, ok := idx[col]
+		if !ok || i >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[i])
+	}
+
+	out := make([]Call, 0)
+	var rowErrs []RowError
+	for n := 1; ; n++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if n > MaxImportRows {
+			return nil, nil, ErrImportTooLarge
+		}
+		if err != nil {
+			var pe *csv.ParseError
+			if !errors.As(err, &pe) {
+				// I/O failure on the upload stream (e.g. body size limit); not a row problem.
+				return nil, nil, err
+			}
+			rowErrs = append(rowErrs, RowError{Row: n, Reason: "malformed csv"})
+			continue
+		}
+
+		c := Call{ExternalID: get(rec, cm.externalID), From: get(rec, cm.from), To: get(rec, cm.to), CampaignID: get(rec, cm.campaignID)}
+		if c.ExternalID == "" {
+			rowErrs = append(rowErrs, RowError{Row: n, Reason: "missing call id"})
+			continue
+		}
+		status, ok := parseImportStatus(get(rec, cm.status), cm.statusMap)
+		if !ok {
+			rowErrs = append(rowErrs, RowError{Row: n, Reason: "unknown status"})
+			continue
+		}
+		c.Status = status
+		if d := get(rec, cm.duration); d != "" {
+			secs, err := strconv.Atoi(d)
+			if err != nil || secs < 0 {
+				rowErrs = append(rowErrs, RowError{Row: n, Reason: "invalid duration"})
+				continue
+			}
+			c.DurationSeconds = secs
+		}
+		started, ok := parseImportTime(get(rec, cm.startedAt), cm.timeLayouts)
+		if !ok {
+			rowErrs = append(rowErrs, RowError{Row: n, Reason: "invalid start time"})
+			continue
+		}
+		c.CreatedAt = started
+		out = append(out, c)
+	}
+	return out, rowErrs, nil
+}
+
+func parseImportStatus(v string, aliases map[string]CallStatus) (CallStatus, bool) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if s, ok := aliases[v]; ok {
+		return s, true
+	}
+	switch s := CallStatus(v); s {
+	case CallStatusQueued, CallStatusRinging, CallStatusInProgress, CallStatusCompleted,
+		CallStatusFailed, CallStatusNoAnswer, CallStatusBusy, CallStatusCanceled:
+		return s, true
+	}
+	return "", false
+}
+
+func parseImportTime(v string, layouts []string) (time.Time, bool) {
+	for _, l := range layouts {
+		if t, err := time.Parse(l, v); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
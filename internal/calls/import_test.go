@@ -0,0 +1,63 @@
+package calls
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImporter_TwilioExportDedupAndRowErrors(t *testing.T) {
+	csvData := `Sid,From,To,Status,Duration,Start Time
+CA1,+15550001,+15550002,completed,61,2023-11-14T10:00:00Z
+CA2,+15550001,+15550003,no-answer,0,2023-11-14T11:00:00Z
+CA3,+15550001,+15550004,exploded,5,2023-11-14T12:00:00Z
+CA4,+15550001,+15550005,busy,x,2023-11-14T13:00:00Z
+`
+	store := NewMemoryImportStore()
+	im := NewImporter(store)
+
+	b, err := im.ImportCSV(context.Background(), "w", "u", ImportFormatTwilio, strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if b.TotalRows != 4 || b.Inserted != 2 || b.Rejected != 2 {
+		t.Fatalf("unexpected batch %+v", b)
+	}
+	if b.Errors[0].Row != 3 || b.Errors[1].Reason != "invalid duration" {
+		t.Fatalf("unexpected row errors %+v", b.Errors)
+	}
+	for _, c := range store.Calls {
+		if !c.Imported || c.WorkspaceID != "w" || c.ImportBatchID != b.ID {
+			t.Fatalf("expected imported marker on %+v", c)
+		}
+	}
+	if store.Calls[1].Status != CallStatusNoAnswer {
+		t.Fatalf("expected twilio status mapped, got %q", store.Calls[1].Status)
+	}
+
+	// Re-upload is idempotent.
+	b2, err := im.ImportCSV(context.Background(), "w", "u", ImportFormatTwilio, strings.NewReader(csvData))
+	if err != nil || b2.Inserted != 0 || b2.Duplicates != 2 {
+		t.Fatalf("expected duplicates skipped, got %+v (%v)", b2, err)
+	}
+}
+
+func TestParseCSV_RequiresColumns(t *testing.T) {
+	if _, _, err := ParseCSV(strings.NewReader("call_id,from\n1,+1\n"), ImportFormatGeneric); err == nil {
+		t.Fatalf("expected missing column error")
+	}
+}
+
+func TestParseImportTime_TwilioZones(t *testing.T) {
+	layouts := importFormats[ImportFormatTwilio].timeLayouts
+	want := time.Date(2023, 11, 14, 15, 0, 0, 0, time.UTC)
+	for _, v := range []string{"2023-11-14 10:00:00 -0500", "2023-11-14 15:00:00 UTC", "Tue, 14 Nov 2023 10:00:00 -0500"} {
+		if got, ok := parseImportTime(v, layouts); !ok || !got.Equal(want) {
+			t.Fatalf("parse %q: got %v ok=%v", v, got, ok)
+		}
+	}
+	if _, ok := parseImportTime("2023-11-14 10:00:00 EST", layouts); ok {
+		t.Fatalf("expected zone abbreviation to be rejected")
+	}
+}
@@ -0,0 +1,201 @@
+package calls
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/audit"
+
+	"github.com/google/uuid"
+)
+
+// Legal holds.
+//
+// An admin places a hold on a call (the call row and its recording) or on the
+// recording alone when litigation or an investigation requires preservation.
+// While any hold is active:
+// - retention and purge jobs must skip the held item (ask IsHeld before deleting);
+// - workspace termination will not start purging (see workspaces.Service.Holds).
+// Holds are never deleted; Release records who lifted it and why. Every change is audited.
+
+type LegalHoldScope string
+
+const (
+	// LegalHoldScopeCall preserves the call row and its recording.
+	LegalHoldScopeCall LegalHoldScope = "call"
+	// LegalHoldScopeRecording preserves only the recording; the call may be anonymized.
+	LegalHoldScopeRecording LegalHoldScope = "recording"
+)
+
+type LegalHold struct {
+	ID          string         `json:"id" db:"id"`
+	WorkspaceID string         `json:"workspace_id" db:"workspace_id"`
+	CallID      string         `json:"call_id" db:"call_id"`
+	Scope       LegalHoldScope `json:"scope" db:"scope"`
+
+	Reason  string `json:"reason" db:"reason"`
+	CaseRef string `json:"case_ref,omitempty" db:"case_ref"`
+
+	PlacedBy     string    `json:"placed_by" db:"placed_by"`
+	PlacedByRole string    `json:"placed_by_role" db:"placed_by_role"`
+	PlacedAt     time.Time `json:"placed_at" db:"placed_at"`
+
+	ReleasedBy    string     `json:"released_by,omitempty" db:"released_by"`
+	ReleaseReason string     `json:"release_reason,omitempty" db:"release_reason"`
+	ReleasedAt    *time.Time `json:"released_at,omitempty" db:"released_at"`
+}
+
+// Active reports whether the hold still blocks deletion.
+func (h LegalHold) Active() bool { return h.ReleasedAt == nil }
+
+// Covers reports whether h protects the given scope of its call.
+func (h LegalHold) Covers(scope LegalHoldScope) bool {
+	return h.Active() && (h.Scope == LegalHoldScopeCall || h.Scope == scope)
+}
+
+type PlaceLegalHoldRequest struct {
+	CallID  string         `json:"call_id"`
+	Scope   LegalHoldScope `json:"scope"`
+	Reason  string         `json:"reason"`
+	CaseRef string         `json:"case_ref,omitempty"`
+}
+
+// LegalHoldStore persists holds. Implementations must enforce workspace filtering.
+type LegalHoldStore interface {
+	Create(ctx context.Context, h LegalHold) error
+	Update(ctx context.Context, h LegalHold) error
+	Get(ctx context.Context, workspaceID, holdID string) (LegalHold, bool, error)
+	// ListActive returns unreleased holds; callID narrows to one call when set.
+	ListActive(ctx context.Context, workspaceID, callID string) ([]LegalHold, error)
+}
+
+var (
+	ErrInvalidLegalHold  = errors.New("calls: invalid legal hold")
+	ErrLegalHoldNotFound = errors.New("calls: legal hold not found")
+	ErrLegalHoldReleased = errors.New("calls: legal hold already released")
+)
+
+type LegalHoldService struct {
+	store LegalHoldStore
+	audit *audit.Service
+	clock func() time.Time
+}
+
+func NewLegalHoldService(store LegalHoldStore, auditSvc *audit.Service) *LegalHoldService {
+	return &LegalHoldService{store: store, audit: auditSvc, clock: time.Now}
+}
+
+// Place puts a call (or its recording) under legal hold. A reason is required.
+func (s *LegalHoldService) Place(ctx context.Context, workspaceID, actorUserID, actorRole string, req PlaceLegalHoldRequest) (LegalHold, error) {
+	req.CallID = strings.TrimSpace(req.CallID)
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Scope == "" {
+		req.Scope = LegalHoldScopeCall
+	}
+	if workspaceID == "" || actorUserID == "" || req.CallID == "" || req.Reason == "" {
+		return LegalHold{}, ErrInvalidLegalHold
+	}
+	if req.Scope != LegalHoldScopeCall && req.Scope != LegalHoldScopeRecording {
+		return LegalHold{}, fmt.Errorf("%w: unknown scope %q", ErrInvalidLegalHold, req.Scope)
+	}
+	h := LegalHold{
+		ID:           uuid.NewString(),
+		WorkspaceID:  workspaceID,
+		CallID:       req.CallID,
+		Scope:        req.Scope,
+		Reason:       req.Reason,
+		CaseRef:      req.CaseRef,
+		PlacedBy:     actorUserID,
+		PlacedByRole: actorRole,
+		PlacedAt:     s.clock().UTC(),
+	}
+	if err := s.store.Create(ctx, h); err != nil {
+		return LegalHold{}, err
+	}
+	s.log(ctx, h, actorUserID, actorRole, "legal hold placed")
+	return h, nil
+}
+
+// Release lifts a hold. The hold row is kept for the record.
+func (s *LegalHoldService) Release(ctx context.Context, workspaceID, holdID, actorUserID, actorRole, reason string) (LegalHold, error) {
+	reason = strings.TrimSpace(reason)
+	if workspaceID == "" || holdID == "" || actorUserID == "" || reason == "" {
+		return LegalHold{}, ErrInvalidLegalHold
+	}
+	h, ok, err := s.store.Get(ctx, workspaceID, holdID)
+	if err != nil {
+		return LegalHold{}, err
+	}
+	if !ok {
+		return LegalHold{}, ErrLegalHoldNotFound
+	}
+	if !h.Active() {
+		return LegalHold{}, ErrLegalHoldReleased
+	}
+	now := s.clock().UTC()
+	h.ReleasedAt = &now
+	h.ReleasedBy = actorUserID
+	h.ReleaseReason = reason
+	if err := s.store.Update(ctx, h); err != nil {
+		return LegalHold{}, err
+	}
+	s.log(ctx, h, actorUserID, actorRole, "legal hold released")
+	return h, nil
+}
+
+// IsHeld reports whether deleting scope of callID is blocked.
+func (s *LegalHoldService) IsHeld(ctx context.Context, workspaceID, callID string, scope LegalHoldScope) (bool, error) {
+	if workspaceID == "" || callID == "" {
+		return false, ErrInvalidLegalHold
+	}
+	holds, err := s.store.ListActive(ctx, workspaceID, callID)
+	if err != nil {
+		return false, err
+	}
+	for _, h := range holds {
+		if h.Covers(scope) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Report lists the workspace's active holds (items currently preserved).
+func (s *LegalHoldService) Report(ctx context.Context, workspaceID string) ([]LegalHold, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidLegalHold
+	}
+	return s.store.ListActive(ctx, workspaceID, "")
+}
+
+// CountActiveLegalHolds lets workspace termination refuse to purge held data.
+func (s *LegalHoldService) CountActiveLegalHolds(ctx context.Context, workspaceID string) (int, error) {
+	holds, err := s.Report(ctx, workspaceID)
+	return len(holds), err
+}
+
+func (s *LegalHoldService) log(ctx context.Context, h LegalHold, actorUserID, actorRole, message string) {
+	if s.audit == nil {
+		return
+	}
+	meta, _ := json.Marshal(map[string]any{
+		"legal_hold_id": h.ID,
+		"scope":         h.Scope,
+		"reason":        h.Reason,
+		"case_ref":      h.CaseRef,
+		"active":        h.Active(),
+	})
+	_ = s.audit.Append(ctx, audit.Event{
+		WorkspaceID: h.WorkspaceID,
+		Type:        audit.EventTypeLegalHold,
+		ActorUserID: actorUserID,
+		ActorRole:   actorRole,
+		CallID:      h.CallID,
+		Message:     message,
+		Metadata:    string(meta),
+	})
+}
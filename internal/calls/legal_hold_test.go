@@ -0,0 +1,67 @@
+package calls
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/audit"
+)
+
+func TestLegalHold_PlaceReleaseAndScope(t *testing.T) {
+	ctx := context.Background()
+	auditRepo := audit.NewMemoryRepo()
+	svc := NewLegalHoldService(NewMemoryLegalHoldStore(), audit.NewService(auditRepo))
+	now := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	svc.clock = func() time.Time { return now }
+
+	if _, err := svc.Place(ctx, "w", "admin", "owner", PlaceLegalHoldRequest{CallID: "c1"}); !errors.Is(err, ErrInvalidLegalHold) {
+		t.Fatalf("expected reason to be required, got %v", err)
+	}
+	if _, err := svc.Place(ctx, "w", "admin", "owner", PlaceLegalHoldRequest{CallID: "c1", Scope: "everything", Reason: "r"}); !errors.Is(err, ErrInvalidLegalHold) {
+		t.Fatalf("expected unknown scope to be rejected, got %v", err)
+	}
+
+	rec, err := svc.Place(ctx, "w", "admin", "owner", PlaceLegalHoldRequest{CallID: "c1", Scope: LegalHoldScopeRecording, Reason: "subpoena", CaseRef: "2025-CV-12"})
+	if err != nil {
+		t.Fatalf("place: %v", err)
+	}
+	if held, _ := svc.IsHeld(ctx, "w", "c1", LegalHoldScopeRecording); !held {
+		t.Fatalf("expected recording to be held")
+	}
+	if held, _ := svc.IsHeld(ctx, "w", "c1", LegalHoldScopeCall); held {
+		t.Fatalf("a recording hold must not protect the call row")
+	}
+	if held, _ := svc.IsHeld(ctx, "other", "c1", LegalHoldScopeRecording); held {
+		t.Fatalf("holds must be workspace scoped")
+	}
+
+	call, _ := svc.Place(ctx, "w", "admin", "owner", PlaceLegalHoldRequest{CallID: "c2", Reason: "investigation"})
+	if held, _ := svc.IsHeld(ctx, "w", "c2", LegalHoldScopeRecording); !held {
+		t.Fatalf("a call hold must also protect the recording")
+	}
+	if n, _ := svc.CountActiveLegalHolds(ctx, "w"); n != 2 {
+		t.Fatalf("expected 2 active holds, got %d", n)
+	}
+
+	if _, err := svc.Release(ctx, "w", rec.ID, "admin", "owner", ""); !errors.Is(err, ErrInvalidLegalHold) {
+		t.Fatalf("expected release reason to be required, got %v", err)
+	}
+	released, err := svc.Release(ctx, "w", rec.ID, "admin2", "owner", "case closed")
+	if err != nil || released.Active() || released.ReleasedBy != "admin2" {
+		t.Fatalf("release: %+v %v", released, err)
+	}
+	if _, err := svc.Release(ctx, "w", rec.ID, "admin2", "owner", "again"); !errors.Is(err, ErrLegalHoldReleased) {
+		t.Fatalf("expected ErrLegalHoldReleased, got %v", err)
+	}
+	report, _ := svc.Report(ctx, "w")
+	if len(report) != 1 || report[0].ID != call.ID {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	events := auditRepo.Events()
+	if len(events) != 3 || events[0].Type != audit.EventTypeLegalHold {
+		t.Fatalf("expected 3 legal hold audit events, got %+v", events)
+	}
+}
@@ -29,10 +29,55 @@ type Call struct {
 
 	RecordingURL string `json:"recording_url,omitempty" db:"recording_url"`
 
+	// ProviderCallID is the provider's id for the live call (e.g. Twilio CallSid),
+	// used to control the call mid-flight (hold, hangup).
+	ProviderCallID string `json:"provider_call_id,omitempty" db:"provider_call_id"`
+	// AgentUserID is the user who handled the call, if any.
+	AgentUserID string `json:"agent_user_id,omitempty" db:"agent_user_id"`
+	// HoldSeconds is the time the remote party spent on hold (see call_hold.go).
+	HoldSeconds int `json:"hold_seconds,omitempty" db:"hold_seconds"`
+
+	// Imported marks historical calls backfilled from another system.
+	// Imported calls count in reporting but are never billed.
+	Imported      bool   `json:"imported,omitempty" db:"imported"`
+	ImportBatchID string `json:"import_batch_id,omitempty" db:"import_batch_id"`
+	// ExternalID is the source system's call id (dedup key for imports).
+	ExternalID string `json:"external_id,omitempty" db:"external_id"`
+
+	// Locked rate quote, captured when the call connects.
+	// Completion is billed against it even if effective-dated pricing flips mid-call.
+	RateID                      string     `json:"rate_id,omitempty" db:"rate_id"`
+	RatePerMinuteMinor          int64      `json:"rate_per_minute_minor,omitempty" db:"rate_per_minute_minor"`
+	RateCurrency                string     `json:"rate_currency,omitempty" db:"rate_currency"`
+	RateBillingIncrementSeconds int        `json:"rate_billing_increment_seconds,omitempty" db:"rate_billing_increment_seconds"`
+	RateMinimumBillableSeconds  int        `json:"rate_minimum_billable_seconds,omitempty" db:"rate_minimum_billable_seconds"`
+	RateBand                    string     `json:"rate_band,omitempty" db:"rate_band"`
+	RateLockedAt                *time.Time `json:"rate_locked_at,omitempty" db:"rate_locked_at"`
+
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// Anonymized returns the call with personal data (phone numbers, recording link) removed.
+// Durations, statuses and rate fields are kept so billing history still reconciles.
+func (c Call) Anonymized() Call {
+	c.From = ""
+	c.To = ""
+	c.RecordingURL = ""
+	return c
+}
+
+// TalkSeconds is the call duration, less hold time when excludeHold is set.
+func (c Call) TalkSeconds(excludeHold bool) int {
+	if !excludeHold {
+		return c.DurationSeconds
+	}
+	if t := c.DurationSeconds - c.HoldSeconds; t > 0 {
+		return t
+	}
+	return 0
+}
+
 type CallStatus string
 
 const (
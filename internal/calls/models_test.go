@@ -23,3 +23,13 @@ func TestCallStatusValuesAreNonEmpty(t *testing.T) {
 func TestCall_FieldsCompile(t *testing.T) {
 	_ = Call{}
 }
+
+func TestCall_AnonymizedKeepsBillingFields(t *testing.T) {
+	c := Call{CallID: "c", WorkspaceID: "w", From: "+1", To: "+2", RecordingURL: "s3://x", DurationSeconds: 30, RateID: "r"}.Anonymized()
+	if c.From != "" || c.To != "" || c.RecordingURL != "" {
+		t.Fatalf("expected personal data removed: %+v", c)
+	}
+	if c.DurationSeconds != 30 || c.RateID != "r" {
+		t.Fatalf("expected billing fields kept: %+v", c)
+	}
+}
@@ -0,0 +1,234 @@
+package calls
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryImportStore is a simple in-memory ImportStore for tests.
+// It is not intended for production use.
+type MemoryImportStore struct {
+	mu      sync.Mutex
+	Calls   []Call
+	Batches []ImportBatch
+}
+
+func NewMemoryImportStore() *MemoryImportStore { return &MemoryImportStore{} }
+
+func (s *MemoryImportStore) InsertImported(ctx context.Context, workspaceID string, rows []Call) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seen := map[string]bool{}
+	for _, c := range s.Calls {
+		if c.WorkspaceID == workspaceID && c.ExternalID != "" {
+			seen[c.ExternalID] = true
+		}
+	}
+	n := 0
+	for _, c := range rows {
+		if seen[c.ExternalID] {
+			continue
+		}
+		seen[c.ExternalID] = true
+		s.Calls = append(s.Calls, c)
+		n++
+	}
+	return n, nil
+}
+
+func (s *MemoryImportStore) CreateImportBatch(ctx context.Context, b ImportBatch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Batches = append(s.Batches, b)
+	return nil
+}
+
+// MemoryLegalHoldStore is a simple in-memory LegalHoldStore for tests.
+// It is not intended for production use.
+type MemoryLegalHoldStore struct {
+	mu    sync.Mutex
+	holds []LegalHold
+}
+
+func NewMemoryLegalHoldStore() *MemoryLegalHoldStore { return &MemoryLegalHoldStore{} }
+
+func (s *MemoryLegalHoldStore) Create(ctx context.Context, h LegalHold) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.holds = append(s.holds, h)
+	return nil
+}
+
+func (s *MemoryLegalHoldStore) Update(ctx context.Context, h LegalHold) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, cur := range s.holds {
+		if cur.ID == h.ID && cur.WorkspaceID == h.WorkspaceID {
+			s.holds[i] = h
+			return nil
+		}
+	}
+	return ErrLegalHoldNotFound
+}
+
+func (s *MemoryLegalHoldStore) Get(ctx context.Context, workspaceID, holdID string) (LegalHold, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, h := range s.holds {
+		if h.ID == holdID && h.WorkspaceID == workspaceID {
+			return h, true, nil
+		}
+	}
+	return LegalHold{}, false, nil
+}
+
+func (s *MemoryLegalHoldStore) ListActive(ctx context.Context, workspaceID, callID string) ([]LegalHold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []LegalHold
+	for _, h := range s.holds {
+		if h.WorkspaceID != workspaceID || !h.Active() {
+			continue
+		}
+		if callID != "" && h.CallID != callID {
+			continue
+		}
+		out = append(out, h)
+	}
+	return out, nil
+}
+
+// MemoryCallStore is a simple in-memory CallStore and HoldPeriodStore for tests.
+// It is not intended for production use.
+type MemoryCallStore struct {
+	mu      sync.Mutex
+	Calls   []Call
+	Periods []HoldPeriod
+}
+
+func NewMemoryCallStore(calls ...Call) *MemoryCallStore { return &MemoryCallStore{Calls: calls} }
+
+func (s *MemoryCallStore) GetCall(ctx context.Context, workspaceID, callID string) (Call, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.Calls {
+		if c.WorkspaceID == workspaceID && c.CallID == callID {
+			return c, true, nil
+		}
+	}
+	return Call{}, false, nil
+}
+
+func (s *MemoryCallStore) AddHoldSeconds(ctx context.Context, workspaceID, callID string, seconds int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, c := range s.Calls {
+		if c.WorkspaceID == workspaceID && c.CallID == callID {
+			s.Calls[i].HoldSeconds += seconds
+			return nil
+		}
+	}
+	return ErrCallNotFound
+}
+
+func (s *MemoryCallStore) CreateHoldPeriod(ctx context.Context, p HoldPeriod) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Periods = append(s.Periods, p)
+	return nil
+}
+
+func (s *MemoryCallStore) UpdateHoldPeriod(ctx context.Context, p HoldPeriod) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, cur := range s.Periods {
+		if cur.ID == p.ID && cur.WorkspaceID == p.WorkspaceID {
+			s.Periods[i] = p
+			return nil
+		}
+	}
+	return ErrCallNotOnHold
+}
+
+func (s *MemoryCallStore) OpenHoldPeriod(ctx context.Context, workspaceID, callID string) (HoldPeriod, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.Periods {
+		if p.WorkspaceID == workspaceID && p.CallID == callID && p.EndedAt == nil {
+			return p, true, nil
+		}
+	}
+	return HoldPeriod{}, false, nil
+}
+
+func (s *MemoryCallStore) ListHoldPeriods(ctx context.Context, workspaceID, callID string) ([]HoldPeriod, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []HoldPeriod
+	for _, p := range s.Periods {
+		if p.WorkspaceID == workspaceID && p.CallID == callID {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+// MemoryMonitorStore is a simple in-memory MonitorSessionStore and
+// MonitorDisclosureStore for tests. It is not intended for production use.
+type MemoryMonitorStore struct {
+	mu          sync.Mutex
+	Sessions    []MonitorSession
+	Disclosures map[string]MonitorDisclosure
+}
+
+func NewMemoryMonitorStore() *MemoryMonitorStore {
+	return &MemoryMonitorStore{Disclosures: map[string]MonitorDisclosure{}}
+}
+
+func (s *MemoryMonitorStore) CreateMonitorSession(ctx context.Context, sess MonitorSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Sessions = append(s.Sessions, sess)
+	return nil
+}
+
+func (s *MemoryMonitorStore) UpdateMonitorSession(ctx context.Context, sess MonitorSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, cur := range s.Sessions {
+		if cur.WorkspaceID == sess.WorkspaceID && cur.ID == sess.ID {
+			s.Sessions[i] = sess
+			return nil
+		}
+	}
+	return ErrMonitorSessionNotFound
+}
+
+func (s *MemoryMonitorStore) GetMonitorSession(ctx context.Context, workspaceID, sessionID string) (MonitorSession, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sess := range s.Sessions {
+		if sess.WorkspaceID == workspaceID && sess.ID == sessionID {
+			return sess, true, nil
+		}
+	}
+	return MonitorSession{}, false, nil
+}
+
+func (s *MemoryMonitorStore) OpenMonitorSession(ctx context.Context, workspaceID, callID, supervisorUserID string) (MonitorSession, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sess := range s.Sessions {
+		if sess.WorkspaceID == workspaceID && sess.CallID == callID && sess.SupervisorUserID == supervisorUserID && sess.EndedAt == nil {
+			return sess, true, nil
+		}
+	}
+	return MonitorSession{}, false, nil
+}
+
+func (s *MemoryMonitorStore) GetMonitorDisclosure(ctx context.Context, workspaceID string) (MonitorDisclosure, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.Disclosures[workspaceID]
+	return d, ok, nil
+}
@@ -0,0 +1,73 @@
+package compliance
+
+import "time"
+
+// Document is a regulatory identity/KYC document uploaded by a workspace.
+//
+// Multi-tenant invariant: workspace_id required.
+// The file itself lives in object storage; only the object key is stored here.
+type Document struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+
+	Type DocumentType `json:"type" db:"type"`
+
+	// CountryISO2 is the country the document is valid for (regulations are per country).
+	CountryISO2 string `json:"country_iso2" db:"country_iso2"`
+
+	FileName    string `json:"file_name" db:"file_name"`
+	ContentType string `json:"content_type" db:"content_type"`
+	SizeBytes   int64  `json:"size_bytes" db:"size_bytes"`
+
+	// StorageKey is the object storage key. Never expose signed URLs in this model.
+	StorageKey string `json:"-" db:"storage_key"`
+
+	Status DocumentStatus `json:"status" db:"status"`
+	// StatusReason is the provider/reviewer reason for rejection (optional).
+	StatusReason string `json:"status_reason,omitempty" db:"status_reason"`
+
+	// ProviderSubmissionID is the provider-side reference once submitted.
+	ProviderSubmissionID string `json:"provider_submission_id,omitempty" db:"provider_submission_id"`
+
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type DocumentType string
+
+const (
+	DocumentTypeIdentity             DocumentType = "identity"
+	DocumentTypeProofOfAddress       DocumentType = "proof_of_address"
+	DocumentTypeBusinessRegistration DocumentType = "business_registration"
+)
+
+type DocumentStatus string
+
+const (
+	DocumentStatusUploaded  DocumentStatus = "uploaded"
+	DocumentStatusSubmitted DocumentStatus = "submitted"
+	DocumentStatusApproved  DocumentStatus = "approved"
+	DocumentStatusRejected  DocumentStatus = "rejected"
+)
+
+// Bundle groups documents that together satisfy a country's regulatory requirement.
+// Numbers purchased under a bundle are linked to it so they can be re-verified on expiry.
+type Bundle struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+
+	CountryISO2 string `json:"country_iso2" db:"country_iso2"`
+	NumberType  string `json:"number_type" db:"number_type"`
+
+	DocumentIDs []string `json:"document_ids" db:"document_ids"`
+
+	// Numbers are E.164 numbers purchased using this bundle.
+	Numbers []string `json:"numbers,omitempty" db:"numbers"`
+
+	Status DocumentStatus `json:"status" db:"status"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
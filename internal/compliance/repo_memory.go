@@ -0,0 +1,101 @@
+package compliance
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+)
+
+// MemoryRepo is a simple in-memory Repository and ObjectStorage for tests.
+// It is not intended for production use.
+type MemoryRepo struct {
+	mu sync.Mutex
+
+	documents map[string]Document
+	bundles   map[string]Bundle
+	objects   map[string][]byte
+}
+
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{documents: map[string]Document{}, bundles: map[string]Bundle{}, objects: map[string][]byte{}}
+}
+
+func (r *MemoryRepo) CreateDocument(ctx context.Context, d Document) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.documents[d.ID] = d
+	return nil
+}
+
+func (r *MemoryRepo) GetDocument(ctx context.Context, workspaceID, documentID string) (Document, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.documents[documentID]
+	if !ok || d.WorkspaceID != workspaceID {
+		return Document{}, false, nil
+	}
+	return d, true, nil
+}
+
+func (r *MemoryRepo) UpdateDocument(ctx context.Context, d Document) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cur, ok := r.documents[d.ID]
+	if !ok || cur.WorkspaceID != d.WorkspaceID {
+		return ErrNotFound
+	}
+	r.documents[d.ID] = d
+	return nil
+}
+
+func (r *MemoryRepo) ListDocuments(ctx context.Context, workspaceID string) ([]Document, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Document, 0)
+	for _, d := range r.documents {
+		if d.WorkspaceID == workspaceID {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func (r *MemoryRepo) CreateBundle(ctx context.Context, b Bundle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bundles[b.ID] = b
+	return nil
+}
+
+func (r *MemoryRepo) GetBundle(ctx context.Context, workspaceID, bundleID string) (Bundle, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.bundles[bundleID]
+	if !ok || b.WorkspaceID != workspaceID {
+		return Bundle{}, false, nil
+	}
+	return b, true, nil
+}
+
+func (r *MemoryRepo) UpdateBundle(ctx context.Context, b Bundle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cur, ok := r.bundles[b.ID]
+	if !ok || cur.WorkspaceID != b.WorkspaceID {
+		return ErrNotFound
+	}
+	r.bundles[b.ID] = b
+	return nil
+}
+
+func (r *MemoryRepo) Put(ctx context.Context, key, contentType string, body io.Reader, sizeBytes int64) error {
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, io.LimitReader(body, sizeBytes)); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.objects[key] = buf.Bytes()
+	return nil
+}
@@ -0,0 +1,276 @@
+package compliance
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Service manages regulatory documents and bundles.
+//
+// Rules:
+// - Files go to object storage; metadata goes to the repository.
+// - Provider submission is a hook; the provider's verdict is applied via SetStatus.
+// - A bundle is usable for purchases only when it and all its documents are approved and unexpired.
+type Service struct {
+	repo      Repository
+	storage   ObjectStorage
+	submitter ProviderSubmitter
+	clock     func() time.Time
+}
+
+// Repository persists document and bundle metadata. Implementations must enforce workspace filtering.
+type Repository interface {
+	CreateDocument(ctx context.Context, d Document) error
+	GetDocument(ctx context.Context, workspaceID, documentID string) (Document, bool, error)
+	UpdateDocument(ctx context.Context, d Document) error
+	ListDocuments(ctx context.Context, workspaceID string) ([]Document, error)
+
+	CreateBundle(ctx context.Context, b Bundle) error
+	GetBundle(ctx context.Context, workspaceID, bundleID string) (Bundle, bool, error)
+	UpdateBundle(ctx context.Context, b Bundle) error
+}
+
+// ObjectStorage stores document files (S3/GCS/etc.).
+type ObjectStorage interface {
+	Put(ctx context.Context, key, contentType string, body io.Reader, sizeBytes int64) error
+}
+
+// ProviderSubmitter forwards a bundle to the telephony provider's regulatory API.
+// It returns the provider's submission reference.
+type ProviderSubmitter interface {
+	SubmitBundle(ctx context.Context, b Bundle, docs []Document) (submissionID string, err error)
+}
+
+func NewService(repo Repository, storage ObjectStorage, submitter ProviderSubmitter) *Service {
+	return &Service{repo: repo, storage: storage, submitter: submitter, clock: time.Now}
+}
+
+var (
+	ErrInvalidArgument = errors.New("compliance: invalid argument")
+	ErrNotFound        = errors.New("compliance: not found")
+)
+
+// maxDocumentBytes caps uploads to keep storage and provider limits sane.
+const maxDocumentBytes = 10 << 20
+
+type UploadRequest struct {
+	Type        DocumentType
+	CountryISO2 string
+	FileName    string
+	ContentType string
+	SizeBytes   int64
+	ExpiresAt   *time.Time
+	Body        io.Reader
+}
+
+// Upload stores the file and records document metadata.
+func (s *Service) Upload(ctx context.Context, workspaceID string, req UploadRequest) (Document, error) {
+	if workspaceID == "" || req.Type == "" || req.Body == nil || req.FileName == "" {
+		return Document{}, ErrInvalidArgument
+	}
+	if req.SizeBytes <= 0 || req.SizeBytes > maxDocumentBytes {
+		return Document{}, ErrInvalidArgument
+	}
+	if s.repo == nil || s.storage == nil {
+		return Document{}, errors.New("compliance: not configured")
+	}
+
+	now := s.clock().UTC()
+	id := uuid.NewString()
+	key := "compliance/" + workspaceID + "/" + id
+
+	if err := s.storage.Put(ctx, key, req.ContentType, req.Body, req.SizeBytes); err != nil {
+		return Document{}, err
+	}
+
+	d := Document{
+		ID:          id,
+		WorkspaceID: workspaceID,
+		Type:        req.Type,
+		CountryISO2: strings.ToUpper(strings.TrimSpace(req.CountryISO2)),
+		FileName:    req.FileName,
+		ContentType: req.ContentType,
+		SizeBytes:   req.SizeBytes,
+		StorageKey:  key,
+		Status:      DocumentStatusUploaded,
+		ExpiresAt:   req.ExpiresAt,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.CreateDocument(ctx, d); err != nil {
+		return Document{}, err
+	}
+	return d, nil
+}
+
+// CreateBundle groups uploaded documents for a country/number type.
+func (s *Service) CreateBundle(ctx context.Context, workspaceID, countryISO2, numberType string, documentIDs []string) (Bundle, error) {
+	if workspaceID == "" || countryISO2 == "" || numberType == "" || len(documentIDs) == 0 {
+		return Bundle{}, ErrInvalidArgument
+	}
+	if s.repo == nil {
+		return Bundle{}, errors.New("compliance: not configured")
+	}
+	for _, id := range documentIDs {
+		if _, ok, err := s.repo.GetDocument(ctx, workspaceID, id); err != nil {
+			return Bundle{}, err
+		} else if !ok {
+			return Bundle{}, ErrNotFound
+		}
+	}
+
+	now := s.clock().UTC()
+	b := Bundle{
+		ID:          uuid.NewString(),
+		WorkspaceID: workspaceID,
+		CountryISO2: strings.ToUpper(strings.TrimSpace(countryISO2)),
+		NumberType:  numberType,
+		DocumentIDs: documentIDs,
+		Status:      DocumentStatusUploaded,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.CreateBundle(ctx, b); err != nil {
+		return Bundle{}, err
+	}
+	return b, nil
+}
+
+// SubmitBundle sends the bundle to the provider and marks it and its documents as submitted.
+func (s *Service) SubmitBundle(ctx context.Context, workspaceID, bundleID string) (Bundle, error) {
+	if s.submitter == nil {
+		return Bundle{}, errors.New("compliance: provider submitter not configured")
+	}
+	b, docs, err := s.loadBundle(ctx, workspaceID, bundleID)
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	ref, err := s.submitter.SubmitBundle(ctx, b, docs)
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	now := s.clock().UTC()
+	for _, d := range docs {
+		d.Status = DocumentStatusSubmitted
+		d.ProviderSubmissionID = ref
+		d.UpdatedAt = now
+		if err := s.repo.UpdateDocument(ctx, d); err != nil {
+			return Bundle{}, err
+		}
+	}
+	b.Status = DocumentStatusSubmitted
+	b.UpdatedAt = now
+	if err := s.repo.UpdateBundle(ctx, b); err != nil {
+		return Bundle{}, err
+	}
+	return b, nil
+}
+
+// SetBundleStatus applies a provider/reviewer verdict to a bundle and its documents.
+func (s *Service) SetBundleStatus(ctx context.Context, workspaceID, bundleID string, status DocumentStatus, reason string) (Bundle, error) {
+	if status != DocumentStatusApproved && status != DocumentStatusRejected {
+		return Bundle{}, ErrInvalidArgument
+	}
+	b, docs, err := s.loadBundle(ctx, workspaceID, bundleID)
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	now := s.clock().UTC()
+	for _, d := range docs {
+		d.Status = status
+		d.StatusReason = reason
+		d.UpdatedAt = now
+		if err := s.repo.UpdateDocument(ctx, d); err != nil {
+			return Bundle{}, err
+		}
+	}
+	b.Status = status
+	b.UpdatedAt = now
+	if err := s.repo.UpdateBundle(ctx, b); err != nil {
+		return Bundle{}, err
+	}
+	return b, nil
+}
+
+// IsBundleApproved implements numbers.BundleChecker.
+// Purchases in regulated countries are blocked until this returns true.
+func (s *Service) IsBundleApproved(ctx context.Context, workspaceID, bundleID, countryISO2, numberType string) (bool, error) {
+	b, docs, err := s.loadBundle(ctx, workspaceID, bundleID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if b.Status != DocumentStatusApproved {
+		return false, nil
+	}
+	if !strings.EqualFold(b.CountryISO2, countryISO2) || b.NumberType != numberType {
+		return false, nil
+	}
+	now := s.clock().UTC()
+	for _, d := range docs {
+		if d.Status != DocumentStatusApproved {
+			return false, nil
+		}
+		if d.ExpiresAt != nil && !now.Before(*d.ExpiresAt) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// LinkNumber records that a number was purchased under a bundle.
+func (s *Service) LinkNumber(ctx context.Context, workspaceID, bundleID, number string) error {
+	if number == "" {
+		return ErrInvalidArgument
+	}
+	b, _, err := s.loadBundle(ctx, workspaceID, bundleID)
+	if err != nil {
+		return err
+	}
+	for _, n := range b.Numbers {
+		if n == number {
+			return nil
+		}
+	}
+	b.Numbers = append(b.Numbers, number)
+	b.UpdatedAt = s.clock().UTC()
+	return s.repo.UpdateBundle(ctx, b)
+}
+
+func (s *Service) loadBundle(ctx context.Context, workspaceID, bundleID string) (Bundle, []Document, error) {
+	if workspaceID == "" || bundleID == "" {
+		return Bundle{}, nil, ErrInvalidArgument
+	}
+	if s.repo == nil {
+		return Bundle{}, nil, errors.New("compliance: not configured")
+	}
+	b, ok, err := s.repo.GetBundle(ctx, workspaceID, bundleID)
+	if err != nil {
+		return Bundle{}, nil, err
+	}
+	if !ok {
+		return Bundle{}, nil, ErrNotFound
+	}
+	docs := make([]Document, 0, len(b.DocumentIDs))
+	for _, id := range b.DocumentIDs {
+		d, ok, err := s.repo.GetDocument(ctx, workspaceID, id)
+		if err != nil {
+			return Bundle{}, nil, err
+		}
+		if !ok {
+			return Bundle{}, nil, ErrNotFound
+		}
+		docs = append(docs, d)
+	}
+	return b, docs, nil
+}
@@ -0,0 +1,67 @@
+package compliance
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+type stubSubmitter struct{}
+
+func (stubSubmitter) SubmitBundle(ctx context.Context, b Bundle, docs []Document) (string, error) {
+	return "BU123", nil
+}
+
+func TestBundleApprovalGatesPurchase(t *testing.T) {
+	repo := NewMemoryRepo()
+	svc := NewService(repo, repo, stubSubmitter{})
+	now := time.Unix(1700000000, 0).UTC()
+	svc.clock = func() time.Time { return now }
+	ctx := context.Background()
+
+	doc, err := svc.Upload(ctx, "w", UploadRequest{Type: DocumentTypeProofOfAddress, CountryISO2: "de", FileName: "bill.pdf", ContentType: "application/pdf", SizeBytes: 3, Body: strings.NewReader("pdf")})
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	b, err := svc.CreateBundle(ctx, "w", "DE", "local", []string{doc.ID})
+	if err != nil {
+		t.Fatalf("bundle: %v", err)
+	}
+
+	if ok, _ := svc.IsBundleApproved(ctx, "w", b.ID, "DE", "local"); ok {
+		t.Fatalf("expected unapproved bundle to block purchase")
+	}
+	if _, err := svc.SubmitBundle(ctx, "w", b.ID); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if _, err := svc.SetBundleStatus(ctx, "w", b.ID, DocumentStatusApproved, ""); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if ok, _ := svc.IsBundleApproved(ctx, "w", b.ID, "DE", "local"); !ok {
+		t.Fatalf("expected approved bundle")
+	}
+	if ok, _ := svc.IsBundleApproved(ctx, "other", b.ID, "DE", "local"); ok {
+		t.Fatalf("expected workspace isolation")
+	}
+}
+
+func TestIsBundleApproved_ExpiredDocumentBlocks(t *testing.T) {
+	repo := NewMemoryRepo()
+	svc := NewService(repo, repo, nil)
+	now := time.Unix(1700000000, 0).UTC()
+	svc.clock = func() time.Time { return now }
+	ctx := context.Background()
+
+	exp := now.Add(time.Hour)
+	doc, _ := svc.Upload(ctx, "w", UploadRequest{Type: DocumentTypeIdentity, FileName: "id.png", SizeBytes: 1, ExpiresAt: &exp, Body: strings.NewReader("x")})
+	b, _ := svc.CreateBundle(ctx, "w", "DE", "local", []string{doc.ID})
+	if _, err := svc.SetBundleStatus(ctx, "w", b.ID, DocumentStatusApproved, ""); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+
+	now = exp
+	if ok, _ := svc.IsBundleApproved(ctx, "w", b.ID, "DE", "local"); ok {
+		t.Fatalf("expected expired document to block purchase")
+	}
+}
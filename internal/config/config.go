@@ -3,6 +3,8 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/netip"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -20,6 +22,9 @@ type Config struct {
 	Redis  RedisConfig
 	Auth   AuthConfig
 	Twilio TwilioConfig
+	TLS    TLSConfig
+	// Internal is the optional mTLS listener for internal service callers.
+	Internal InternalConfig
 }
 
 /* ===================== APP ===================== */
@@ -29,6 +34,18 @@ type AppConfig struct {
 	Port          int
 	Maintenance   bool // UI read-only / banner
 	EmergencyStop bool // HARD STOP all calls
+
+	// TrustedProxies lists load balancer/proxy IPs or CIDRs whose X-Forwarded-For
+	// entries are honored. Empty means no proxy is trusted and the socket peer is the client.
+	TrustedProxies []string
+
+	// AdminAllowedCIDRs restricts /v1/admin and /v1/noc to client IPs in these
+	// CIDRs (or bare IPs). Empty disables the allowlist.
+	AdminAllowedCIDRs []string
+
+	// PublicBaseURL is where providers reach this environment's webhooks
+	// (e.g. https://api.example.com). Numbers are configured to call back here.
+	PublicBaseURL string
 }
 
 /* ===================== DATABASE ===================== */
@@ -59,6 +76,11 @@ type AuthConfig struct {
 	JWTAudience      string
 	AccessTokenTTL  time.Duration
 	RefreshTokenTTL time.Duration
+
+	// ServiceTokenSecret signs short-lived internal service (delegation) tokens.
+	// Must differ from JWTSecret so user and service token paths never share a key.
+	ServiceTokenSecret string
+	ServiceTokenTTL    time.Duration
 }
 
 /* ===================== TWILIO ===================== */
@@ -67,8 +89,57 @@ type TwilioConfig struct {
 	AccountSID    string
 	AuthToken     string
 	WebhookSecret string
+
+	// Client-side REST pacing per account (see telephony.RESTLimiter).
+	RESTRatePerSecond float64
+	RESTBurst         int
+}
+
+/* ===================== TLS / HTTP2 ===================== */
+
+// TLSConfig enables native TLS for deployments without a fronting proxy.
+type TLSConfig struct {
+	Mode string // off (default), files, acme
+
+	// files mode; the pair is re-read on SIGHUP.
+	CertFile string
+	KeyFile  string
+
+	// acme mode (TLS-ALPN-01 on the serving port).
+	ACMEDomains  []string
+	ACMECacheDir string
+	ACMEEmail    string
+
+	MinVersion   string // 1.2 (default) or 1.3
+	CipherPolicy string // modern (default) or intermediate
+
+	HTTP2Disabled             bool
+	HTTP2MaxConcurrentStreams uint32
+}
+
+func (t TLSConfig) Enabled() bool { return t.Mode == "files" || t.Mode == "acme" }
+
+/* ===================== INTERNAL (mTLS) ===================== */
+
+// InternalConfig enables a dedicated listener for internal services (worker,
+// media controller) that authenticates callers by client certificate.
+type InternalConfig struct {
+	// Addr (e.g. ":9443") enables the listener; empty disables it.
+	Addr string
+
+	// Server pair, re-read on SIGHUP.
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is the PEM bundle client certificates must chain to.
+	ClientCAFile string
+
+	// Identities maps SPIFFE IDs (spiffe://trust-domain/path) to service names.
+	// Parsed from INTERNAL_MTLS_IDENTITIES as "id=service" pairs, comma-separated.
+	Identities map[string]string
 }
 
+func (i InternalConfig) Enabled() bool { return i.Addr != "" }
+
 /* ===================== LOAD ===================== */
 
 func Load() (Config, error) {
@@ -84,6 +155,19 @@ func Load() (Config, error) {
 
 	c.App.Maintenance = strings.ToLower(os.Getenv("APP_MAINTENANCE")) == "true"
 	c.App.EmergencyStop = strings.ToLower(os.Getenv("APP_EMERGENCY_STOP")) == "true"
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			c.App.TrustedProxies = append(c.App.TrustedProxies, p)
+		}
+	}
+
+	for _, p := range strings.Split(os.Getenv("ADMIN_ALLOWED_CIDRS"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			c.App.AdminAllowedCIDRs = append(c.App.AdminAllowedCIDRs, p)
+		}
+	}
+
+	c.App.PublicBaseURL = strings.TrimRight(strings.TrimSpace(os.Getenv("PUBLIC_BASE_URL")), "/")
 
 	/* ---- DB ---- */
 	c.DB.Host = strings.TrimSpace(os.Getenv("DB_HOST"))
@@ -114,10 +198,71 @@ func Load() (Config, error) {
 	c.Auth.RefreshTokenTTL, err = mustDuration("JWT_REFRESH_TTL")
 	parseErrs = append(parseErrs, err)
 
+	c.Auth.ServiceTokenSecret = os.Getenv("INTERNAL_TOKEN_SECRET")
+	c.Auth.ServiceTokenTTL, err = mustDuration("INTERNAL_TOKEN_TTL")
+	parseErrs = append(parseErrs, err)
+
 	/* ---- TWILIO ---- */
 	c.Twilio.AccountSID = strings.TrimSpace(os.Getenv("TWILIO_ACCOUNT_SID"))
 	c.Twilio.AuthToken = os.Getenv("TWILIO_AUTH_TOKEN")
 	c.Twilio.WebhookSecret = os.Getenv("TWILIO_WEBHOOK_SECRET")
+	if v := strings.TrimSpace(os.Getenv("TWILIO_REST_RPS")); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f <= 0 {
+			parseErrs = append(parseErrs, errors.New("TWILIO_REST_RPS must be a positive number"))
+		}
+		c.Twilio.RESTRatePerSecond = f
+	}
+	if v := strings.TrimSpace(os.Getenv("TWILIO_REST_BURST")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			parseErrs = append(parseErrs, errors.New("TWILIO_REST_BURST must be a positive integer"))
+		}
+		c.Twilio.RESTBurst = n
+	}
+
+	/* ---- TLS ---- */
+	c.TLS.Mode = strings.ToLower(strings.TrimSpace(os.Getenv("TLS_MODE")))
+	c.TLS.CertFile = strings.TrimSpace(os.Getenv("TLS_CERT_FILE"))
+	c.TLS.KeyFile = strings.TrimSpace(os.Getenv("TLS_KEY_FILE"))
+	for _, d := range strings.Split(os.Getenv("TLS_ACME_DOMAINS"), ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			c.TLS.ACMEDomains = append(c.TLS.ACMEDomains, d)
+		}
+	}
+	c.TLS.ACMECacheDir = strings.TrimSpace(os.Getenv("TLS_ACME_CACHE_DIR"))
+	c.TLS.ACMEEmail = strings.TrimSpace(os.Getenv("TLS_ACME_EMAIL"))
+	c.TLS.MinVersion = strings.TrimSpace(os.Getenv("TLS_MIN_VERSION"))
+	c.TLS.CipherPolicy = strings.ToLower(strings.TrimSpace(os.Getenv("TLS_CIPHER_POLICY")))
+	c.TLS.HTTP2Disabled = strings.ToLower(os.Getenv("HTTP2_DISABLED")) == "true"
+	if v := strings.TrimSpace(os.Getenv("HTTP2_MAX_CONCURRENT_STREAMS")); v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || n == 0 {
+			parseErrs = append(parseErrs, errors.New("HTTP2_MAX_CONCURRENT_STREAMS must be a positive integer"))
+		} else {
+			c.TLS.HTTP2MaxConcurrentStreams = uint32(n)
+		}
+	}
+
+	/* ---- INTERNAL (mTLS) ---- */
+	c.Internal.Addr = strings.TrimSpace(os.Getenv("INTERNAL_ADDR"))
+	c.Internal.CertFile = strings.TrimSpace(os.Getenv("INTERNAL_TLS_CERT_FILE"))
+	c.Internal.KeyFile = strings.TrimSpace(os.Getenv("INTERNAL_TLS_KEY_FILE"))
+	c.Internal.ClientCAFile = strings.TrimSpace(os.Getenv("INTERNAL_TLS_CLIENT_CA_FILE"))
+	for _, pair := range strings.Split(os.Getenv("INTERNAL_MTLS_IDENTITIES"), ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		id, service, ok := strings.Cut(pair, "=")
+		if !ok {
+			parseErrs = append(parseErrs, fmt.Errorf("INTERNAL_MTLS_IDENTITIES entry %q must be spiffe-id=service", pair))
+			continue
+		}
+		if c.Internal.Identities == nil {
+			c.Internal.Identities = map[string]string{}
+		}
+		c.Internal.Identities[strings.TrimSpace(id)] = strings.TrimSpace(service)
+	}
 
 	/* ---- APPLY DEFAULTS (NO SIDE EFFECTS IN VALIDATE) ---- */
 	if c.Auth.AccessTokenTTL == 0 {
@@ -126,9 +271,24 @@ func Load() (Config, error) {
 	if c.Auth.RefreshTokenTTL == 0 {
 		c.Auth.RefreshTokenTTL = 30 * 24 * time.Hour
 	}
+	if c.Auth.ServiceTokenTTL == 0 {
+		c.Auth.ServiceTokenTTL = 5 * time.Minute
+	}
 	if c.DB.SSLMode == "" && !c.IsProduction() {
 		c.DB.SSLMode = "disable"
 	}
+	if c.TLS.Mode == "" {
+		c.TLS.Mode = "off"
+	}
+	if c.TLS.MinVersion == "" {
+		c.TLS.MinVersion = "1.2"
+	}
+	if c.TLS.CipherPolicy == "" {
+		c.TLS.CipherPolicy = "modern"
+	}
+	if c.TLS.ACMECacheDir == "" {
+		c.TLS.ACMECacheDir = "/var/cache/telecom-platform/acme"
+	}
 
 	if err := joinErrors(parseErrs); err != nil {
 		return Config{}, err
@@ -155,6 +315,31 @@ func (c Config) Validate() error {
 	if c.App.Port <= 0 || c.App.Port > 65535 {
 		errs = append(errs, fmt.Errorf("APP_PORT must be valid"))
 	}
+	for _, p := range c.App.TrustedProxies {
+		if _, err := netip.ParsePrefix(p); err == nil {
+			continue
+		}
+		if _, err := netip.ParseAddr(p); err != nil {
+			errs = append(errs, fmt.Errorf("TRUSTED_PROXIES entry %q must be an IP or CIDR", p))
+		}
+	}
+	for _, p := range c.App.AdminAllowedCIDRs {
+		if _, err := netip.ParsePrefix(p); err == nil {
+			continue
+		}
+		if _, err := netip.ParseAddr(p); err != nil {
+			errs = append(errs, fmt.Errorf("ADMIN_ALLOWED_CIDRS entry %q must be an IP or CIDR", p))
+		}
+	}
+	if c.App.PublicBaseURL != "" {
+		u, err := url.Parse(c.App.PublicBaseURL)
+		switch {
+		case err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") || u.RawQuery != "" || u.Fragment != "":
+			errs = append(errs, errors.New("PUBLIC_BASE_URL must be an absolute http(s) URL without query or fragment"))
+		case u.Scheme != "https" && c.App.Env == "production":
+			errs = append(errs, errors.New("PUBLIC_BASE_URL must use https in production"))
+		}
+	}
 
 	/* ---- DB ---- */
 	if c.DB.Host == "" {
@@ -199,6 +384,12 @@ func (c Config) Validate() error {
 	if c.Auth.RefreshTokenTTL <= c.Auth.AccessTokenTTL {
 		errs = append(errs, errors.New("JWT_REFRESH_TTL must be greater than JWT_ACCESS_TTL"))
 	}
+	if c.Auth.ServiceTokenSecret != "" && c.Auth.ServiceTokenSecret == c.Auth.JWTSecret {
+		errs = append(errs, errors.New("INTERNAL_TOKEN_SECRET must differ from JWT_SECRET"))
+	}
+	if c.Auth.ServiceTokenTTL > 15*time.Minute {
+		errs = append(errs, errors.New("INTERNAL_TOKEN_TTL must be at most 15m"))
+	}
 
 	/* ---- TWILIO ---- */
 	if c.Twilio.AccountSID != "" || c.Twilio.AuthToken != "" {
@@ -209,6 +400,43 @@ func (c Config) Validate() error {
 		}
 	}
 
+	/* ---- TLS ---- */
+	switch c.TLS.Mode {
+	case "", "off":
+	case "files":
+		if c.TLS.CertFile == "" || c.TLS.KeyFile == "" {
+			errs = append(errs, errors.New("TLS_CERT_FILE and TLS_KEY_FILE are required when TLS_MODE=files"))
+		}
+	case "acme":
+		if len(c.TLS.ACMEDomains) == 0 {
+			errs = append(errs, errors.New("TLS_ACME_DOMAINS is required when TLS_MODE=acme"))
+		}
+	default:
+		errs = append(errs, errors.New("TLS_MODE must be off, files, or acme"))
+	}
+	if c.TLS.MinVersion != "" && c.TLS.MinVersion != "1.2" && c.TLS.MinVersion != "1.3" {
+		errs = append(errs, errors.New("TLS_MIN_VERSION must be 1.2 or 1.3"))
+	}
+	if c.TLS.CipherPolicy != "" && c.TLS.CipherPolicy != "modern" && c.TLS.CipherPolicy != "intermediate" {
+		errs = append(errs, errors.New("TLS_CIPHER_POLICY must be modern or intermediate"))
+	}
+
+	/* ---- INTERNAL (mTLS) ---- */
+	if c.Internal.Enabled() {
+		if c.Internal.CertFile == "" || c.Internal.KeyFile == "" || c.Internal.ClientCAFile == "" {
+			errs = append(errs, errors.New("INTERNAL_TLS_CERT_FILE, INTERNAL_TLS_KEY_FILE and INTERNAL_TLS_CLIENT_CA_FILE are required when INTERNAL_ADDR is set"))
+		}
+		if len(c.Internal.Identities) == 0 {
+			errs = append(errs, errors.New("INTERNAL_MTLS_IDENTITIES is required when INTERNAL_ADDR is set"))
+		}
+		for id, service := range c.Internal.Identities {
+			u, err := url.Parse(id)
+			if err != nil || u.Scheme != "spiffe" || u.Host == "" || service == "" {
+				errs = append(errs, fmt.Errorf("INTERNAL_MTLS_IDENTITIES entry %q must map a spiffe:// ID to a service name", id))
+			}
+		}
+	}
+
 	return joinErrors(errs)
 }
 
@@ -36,3 +36,101 @@ func TestValidate_LocalDefaultsSSLMode(t *testing.T) {
 		t.Fatalf("expected sslmode disable default, got %q", c.DB.SSLMode)
 	}
 }
+
+func TestValidate_TLSModeRequirements(t *testing.T) {
+	base := Config{
+		App:   AppConfig{Env: "local", Port: 8443},
+		DB:    DBConfig{Host: "localhost", Port: 5432, User: "postgres", Name: "telecom", SSLMode: "disable"},
+		Redis: RedisConfig{Host: "localhost", Port: 6379},
+		Auth:  AuthConfig{JWTSecret: "secret", AccessTokenTTL: 1, RefreshTokenTTL: 2},
+	}
+	c := base
+	c.TLS = TLSConfig{Mode: "files"}
+	if err := c.Validate(); err == nil {
+		t.Fatalf("expected error for files mode without cert/key")
+	}
+	c.TLS = TLSConfig{Mode: "acme", ACMEDomains: []string{"api.example.com"}, MinVersion: "1.3"}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("expected valid acme config, got %v", err)
+	}
+	c.TLS = TLSConfig{Mode: "files", CertFile: "c", KeyFile: "k", CipherPolicy: "legacy"}
+	if err := c.Validate(); err == nil {
+		t.Fatalf("expected error for unknown cipher policy")
+	}
+}
+
+func TestValidate_TrustedProxies(t *testing.T) {
+	c := Config{
+		App:   AppConfig{Env: "local", Port: 8080, TrustedProxies: []string{"10.0.0.0/8", "192.168.1.10"}},
+		DB:    DBConfig{Host: "localhost", Port: 5432, User: "postgres", Name: "telecom", SSLMode: "disable"},
+		Redis: RedisConfig{Host: "localhost", Port: 6379},
+		Auth:  AuthConfig{JWTSecret: "secret", AccessTokenTTL: 1, RefreshTokenTTL: 2},
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("expected valid proxies, got %v", err)
+	}
+	c.App.TrustedProxies = []string{"lb.internal"}
+	if err := c.Validate(); err == nil {
+		t.Fatalf("expected error for hostname proxy entry")
+	}
+}
+
+func TestValidate_AdminAllowedCIDRs(t *testing.T) {
+	c := Config{
+		App:   AppConfig{Env: "local", Port: 8080, AdminAllowedCIDRs: []string{"203.0.113.0/24", "198.51.100.7"}},
+		DB:    DBConfig{Host: "localhost", Port: 5432, User: "postgres", Name: "telecom", SSLMode: "disable"},
+		Redis: RedisConfig{Host: "localhost", Port: 6379},
+		Auth:  AuthConfig{JWTSecret: "secret", AccessTokenTTL: 1, RefreshTokenTTL: 2},
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("expected valid allowlist, got %v", err)
+	}
+	c.App.AdminAllowedCIDRs = []string{"office-vpn"}
+	if err := c.Validate(); err == nil {
+		t.Fatalf("expected error for hostname allowlist entry")
+	}
+}
+
+func TestValidate_PublicBaseURL(t *testing.T) {
+	c := Config{
+		App:   AppConfig{Env: "production", Port: 8080, PublicBaseURL: "http://api.example.com"},
+		DB:    DBConfig{Host: "localhost", Port: 5432, User: "postgres", Name: "telecom", SSLMode: "require"},
+		Redis: RedisConfig{Host: "localhost", Port: 6379},
+		Auth:  AuthConfig{JWTSecret: "secret", JWTIssuer: "api", JWTAudience: "app", AccessTokenTTL: 1, RefreshTokenTTL: 2},
+	}
+	if err := c.Validate(); err == nil {
+		t.Fatalf("expected https requirement in production")
+	}
+	c.App.PublicBaseURL = "https://api.example.com/base"
+	if err := c.Validate(); err != nil {
+		t.Fatalf("expected valid base url, got %v", err)
+	}
+	c.App.PublicBaseURL = "api.example.com"
+	if err := c.Validate(); err == nil {
+		t.Fatalf("expected error for relative base url")
+	}
+}
+
+func TestValidate_InternalMTLS(t *testing.T) {
+	c := Config{
+		App:   AppConfig{Env: "local", Port: 8080},
+		DB:    DBConfig{Host: "localhost", Port: 5432, User: "postgres", Name: "telecom", SSLMode: "disable"},
+		Redis: RedisConfig{Host: "localhost", Port: 6379},
+		Auth:  AuthConfig{JWTSecret: "secret", AccessTokenTTL: 1, RefreshTokenTTL: 2},
+		Internal: InternalConfig{
+			Addr: ":9443", CertFile: "c.pem", KeyFile: "k.pem", ClientCAFile: "ca.pem",
+			Identities: map[string]string{"spiffe://telecom.internal/worker": "worker"},
+		},
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("expected valid internal listener, got %v", err)
+	}
+	c.Internal.Identities = map[string]string{"worker": "worker"}
+	if err := c.Validate(); err == nil {
+		t.Fatalf("expected error for non-spiffe identity")
+	}
+	c.Internal.Identities = nil
+	if err := c.Validate(); err == nil {
+		t.Fatalf("expected error without identities")
+	}
+}
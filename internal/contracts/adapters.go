@@ -0,0 +1,117 @@
+package contracts
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"telecom-platform/internal/pricing"
+	"telecom-platform/internal/reporting"
+)
+
+// ReportingSpend implements SpendSource from the reporting spend summary (usage debits only).
+type ReportingSpend struct {
+	Reporting *reporting.Service
+}
+
+func (r ReportingSpend) UsageSpendMinor(ctx context.Context, workspaceID, currency string, from, to time.Time) (int64, error) {
+	out, err := r.Reporting.SpendSummary(ctx, reporting.SpendSummaryRequest{
+		WorkspaceID: workspaceID,
+		Currency:    currency,
+		Timezone:    "UTC",
+		Range:       reporting.TimeRange{From: from, To: to},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return out.UsageDebitMinor, nil
+}
+
+// DefaultCommitCheckTTL is how long OverageRates reuses a "commit not yet consumed"
+// answer before asking the SpendSource again.
+const DefaultCommitCheckTTL = time.Minute
+
+// OverageRates decorates a pricing.RateRepository so that, once a workspace has consumed
+// the month's commitment, the contract's overage rate replaces the list rate.
+//
+// Rating runs on every call, so the commitment check is cached per contract and month: once consumed it stays consumed for the month (usage spend only grows);
+// until then the answer is reused for CheckTTL.
+type OverageRates struct {
+	Base      pricing.RateRepository
+	Contracts *Service
+	// CheckTTL bounds how stale a "not consumed" answer may be; zero uses DefaultCommitCheckTTL.
+	CheckTTL time.Duration
+
+	mu     sync.Mutex
+	checks map[string]commitCheck
+	now    func() time.Time
+}
+
+type commitCheck struct {
+	consumed  bool
+	checkedAt time.Time
+}
+
+func NewOverageRates(base pricing.RateRepository, contracts *Service) *OverageRates {
+	return &OverageRates{Base: base, Contracts: contracts, checks: map[string]commitCheck{}, now: time.Now}
+}
+
+func (o *OverageRates) FindMinutePricing(ctx context.Context, workspaceID string, direction pricing.CallDirection, destination string, at time.Time) (pricing.MinutePricing, bool, error) {
+	mp, ok, err := o.Base.FindMinutePricing(ctx, workspaceID, direction, destination, at)
+	if err != nil || !ok || o.Contracts == nil {
+		return mp, ok, err
+	}
+	c, active, err := o.Contracts.ActiveContract(ctx, workspaceID, at)
+	if err != nil || !active || c.Currency != mp.Currency {
+		return mp, ok, err
+	}
+	for _, r := range c.OverageRates {
+		if r.Direction != direction || r.Destination != destination {
+			continue
+		}
+		consumed, err := o.commitConsumed(ctx, c, at)
+		if err != nil {
+			return pricing.MinutePricing{}, false, err
+		}
+		if consumed {
+			mp.RatePerMinuteMinor = r.RatePerMinuteMinor
+			mp.Bands = nil
+			mp.Tiers = nil
+		}
+		break
+	}
+	return mp, ok, nil
+}
+
+// commitConsumed reports whether spend in the month of `at` has reached the commitment.
+func (o *OverageRates) commitConsumed(ctx context.Context, c Contract, at time.Time) (bool, error) {
+	m := monthStart(at)
+	key := c.WorkspaceID + "|" + c.ID + "|" + m.Format("2006-01")
+	ttl := o.CheckTTL
+	if ttl <= 0 {
+		ttl = DefaultCommitCheckTTL
+	}
+	o.mu.Lock()
+	if o.checks == nil {
+		o.checks = map[string]commitCheck{}
+	}
+	now := time.Now()
+	if o.now != nil {
+		now = o.now()
+	}
+	prev, ok := o.checks[key]
+	o.mu.Unlock()
+	if ok && (prev.consumed || now.Sub(prev.checkedAt) < ttl) {
+		return prev.consumed, nil
+	}
+
+	spend, err := o.Contracts.spend.UsageSpendMinor(ctx, c.WorkspaceID, c.Currency, m, at)
+	if err != nil {
+		return false, err
+	}
+	consumed := spend >= c.MinimumMonthlyCommitMinor
+	o.mu.Lock()
+	o.checks[key] = commitCheck{consumed: consumed, checkedAt: now}
+	o.mu.Unlock()
+	return consumed, nil
+}
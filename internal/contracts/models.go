@@ -0,0 +1,132 @@
+package contracts
+
+import (
+	"time"
+
+	"telecom-platform/internal/pricing"
+)
+
+// Committed-use contracts are tenant-scoped (workspace_id required everywhere).
+// Amounts are expressed in minor units using int64.
+
+type Status string
+
+const (
+	StatusActive   Status = "active"
+	StatusExpired  Status = "expired"
+	StatusCanceled Status = "canceled"
+)
+
+// Contract is a minimum monthly spend commitment over a term.
+type Contract struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+
+	Currency string `json:"currency" db:"currency"`
+
+	// MinimumMonthlyCommitMinor is the spend the tenant pays for each calendar month (UTC)
+	// of the term, whether or not it is used.
+	MinimumMonthlyCommitMinor int64 `json:"minimum_monthly_commit_minor" db:"minimum_monthly_commit_minor"`
+
+	// TermStart is inclusive, TermEnd exclusive. Both should be month boundaries.
+	TermStart time.Time `json:"term_start" db:"term_start"`
+	TermEnd   time.Time `json:"term_end" db:"term_end"`
+
+	// OverageRates replace list per-minute rates once the month's commit is consumed.
+	// Store as JSONB in Postgres.
+	OverageRates []OverageRate `json:"overage_rates,omitempty" db:"overage_rates"`
+
+	Status Status `json:"status" db:"status"`
+
+	// CanceledAt ends the true-up window: months that closed before it are still owed.
+	CanceledAt *time.Time `json:"canceled_at,omitempty" db:"canceled_at"`
+
+	// SettledThrough is the start of the first month not yet trued up (zero: none
+	// yet). Months before it are posted or met their commitment.
+	SettledThrough time.Time `json:"settled_through,omitempty" db:"settled_through"`
+
+	CreatedByUserID string    `json:"created_by_user_id" db:"created_by_user_id"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// OverageRate is a contract-specific rate for one direction + destination.
+type OverageRate struct {
+	Direction          pricing.CallDirection `json:"direction"`
+	Destination        string                `json:"destination"`
+	RatePerMinuteMinor int64                 `json:"rate_per_minute_minor"`
+}
+
+// coversMonth reports whether the month starting at m lies inside the term.
+func (c Contract) coversMonth(m time.Time) bool {
+	return !m.Before(c.TermStart) && m.Before(c.TermEnd)
+}
+
+// trueUpEnd is the exclusive end of the months owed: TermEnd, or for a canceled
+// contract the start of the month it was canceled in (a partial month is not owed).
+func (c Contract) trueUpEnd() time.Time {
+	if c.Status == StatusCanceled && c.CanceledAt != nil {
+		if end := monthStart(*c.CanceledAt); end.Before(c.TermEnd) {
+			return end
+		}
+	}
+	return c.TermEnd
+}
+
+// settledFrom is the first month still to true up.
+func (c Contract) settledFrom() time.Time {
+	if c.SettledThrough.IsZero() {
+		return monthStart(c.TermStart)
+	}
+	return c.SettledThrough
+}
+
+// trueUpsDue reports whether any owed month is not yet settled.
+func (c Contract) trueUpsDue() bool {
+	return c.settledFrom().Before(c.trueUpEnd())
+}
+
+type TrueUpStatus string
+
+const (
+	TrueUpStatusPosted TrueUpStatus = "posted"
+	TrueUpStatusFailed TrueUpStatus = "failed"
+)
+
+// TrueUp is the charge for a month's unused commitment.
+type TrueUp struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	ContractID  string `json:"contract_id" db:"contract_id"`
+
+	// Period is the month, formatted YYYY-MM.
+	Period string `json:"period" db:"period"`
+
+	CommitMinor    int64  `json:"commit_minor" db:"commit_minor"`
+	SpendMinor     int64  `json:"spend_minor" db:"spend_minor"`
+	AmountMinor    int64  `json:"amount_minor" db:"amount_minor"`
+	Currency       string `json:"currency" db:"currency"`
+	WalletID       string `json:"wallet_id" db:"wallet_id"`
+	LedgerID       string `json:"ledger_id,omitempty" db:"ledger_id"`
+	IdempotencyKey string `json:"idempotency_key" db:"idempotency_key"`
+
+	Status TrueUpStatus `json:"status" db:"status"`
+	Error  string       `json:"error,omitempty" db:"error"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// PeriodStatus is the contract's standing for one month.
+type PeriodStatus struct {
+	Contract Contract `json:"contract"`
+
+	Period      string `json:"period"`
+	CommitMinor int64  `json:"commit_minor"`
+	SpendMinor  int64  `json:"spend_minor"`
+
+	// ShortfallMinor is the true-up owed if spend stays where it is.
+	ShortfallMinor int64 `json:"shortfall_minor"`
+	CommitMet      bool  `json:"commit_met"`
+
+	TrueUp *TrueUp `json:"true_up,omitempty"`
+}
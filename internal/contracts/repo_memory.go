@@ -0,0 +1,92 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// MemoryRepo is a simple in-memory Repository useful for tests.
+// It is not intended for production use.
+
+type MemoryRepo struct {
+	mu        sync.Mutex
+	contracts map[string]Contract
+	trueUps   map[string]TrueUp
+}
+
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{contracts: map[string]Contract{}, trueUps: map[string]TrueUp{}}
+}
+
+func (r *MemoryRepo) CreateContract(ctx context.Context, c Contract) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.contracts[c.ID]; ok {
+		return errors.New("contracts: contract already exists")
+	}
+	r.contracts[c.ID] = c
+	return nil
+}
+
+func (r *MemoryRepo) GetContract(ctx context.Context, workspaceID, id string) (Contract, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.contracts[id]
+	if !ok || c.WorkspaceID != workspaceID {
+		return Contract{}, false, nil
+	}
+	return c, true, nil
+}
+
+func (r *MemoryRepo) UpdateContract(ctx context.Context, c Contract) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cur, ok := r.contracts[c.ID]
+	if !ok || cur.WorkspaceID != c.WorkspaceID {
+		return ErrNotFound
+	}
+	r.contracts[c.ID] = c
+	return nil
+}
+
+func (r *MemoryRepo) ListContracts(ctx context.Context, workspaceID string) ([]Contract, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Contract
+	for _, c := range r.contracts {
+		if c.WorkspaceID == workspaceID {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].TermStart.Before(out[j].TermStart) })
+	return out, nil
+}
+
+func (r *MemoryRepo) ListContractsDue(ctx context.Context) ([]Contract, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Contract
+	for _, c := range r.contracts {
+		if c.trueUpsDue() {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (r *MemoryRepo) GetTrueUp(ctx context.Context, workspaceID, contractID, period string) (TrueUp, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.trueUps[workspaceID+"|"+contractID+"|"+period]
+	return t, ok, nil
+}
+
+func (r *MemoryRepo) SaveTrueUp(ctx context.Context, t TrueUp) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.trueUps[t.WorkspaceID+"|"+t.ContractID+"|"+t.Period] = t
+	return nil
+}
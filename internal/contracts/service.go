@@ -0,0 +1,317 @@
+package contracts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"telecom-platform/internal/audit"
+	"telecom-platform/internal/wallet"
+
+	"github.com/google/uuid"
+)
+
+// Service manages committed-use contracts and monthly true-ups.
+//
+// Invoicing flow:
+//   - Usage is billed as usual during the month (at overage rates once the commit is consumed).
+//   - After a month closes, RunTrueUps compares the month's usage spend to the commitment
+//     and debits the shortfall from the workspace wallet.
+//   - Every closed month of the term is owed, including months missed by earlier runs and,
+//     for canceled contracts, months that closed before the cancellation.
+//   - True-ups are idempotent per contract + month (wallet idempotency key and stored row).
+//   - Contracts whose term has ended are marked expired.
+type Service struct {
+	repo   Repository
+	spend  SpendSource
+	wallet Debiter
+	audit  *audit.Service
+	clock  func() time.Time
+}
+
+// Repository persists contracts and true-ups. Implementations must enforce workspace filtering.
+type Repository interface {
+	CreateContract(ctx context.Context, c Contract) error
+	GetContract(ctx context.Context, workspaceID, id string) (Contract, bool, error)
+	UpdateContract(ctx context.Context, c Contract) error
+	ListContracts(ctx context.Context, workspaceID string) ([]Contract, error)
+	// ListContractsDue returns contracts of any status, across all workspaces, with owed
+	// months not yet settled: SettledThrough (or the TermStart month when zero) before
+	// TermEnd, or before the cancellation month for canceled contracts.
+	ListContractsDue(ctx context.Context) ([]Contract, error)
+
+	GetTrueUp(ctx context.Context, workspaceID, contractID, period string) (TrueUp, bool, error)
+	SaveTrueUp(ctx context.Context, t TrueUp) error
+}
+
+// SpendSource reports usage spend (call charges, excluding top-ups and true-ups).
+type SpendSource interface {
+	UsageSpendMinor(ctx context.Context, workspaceID, currency string, from, to time.Time) (int64, error)
+}
+
+// Debiter posts the true-up charge. *wallet.Service implements it.
+type Debiter interface {
+	Debit(ctx context.Context, workspaceID, walletID string, req wallet.DebitRequest) (wallet.WalletLedger, wallet.Balance, error)
+}
+
+// WalletResolver picks the wallet a workspace is invoiced from.
+type WalletResolver func(ctx context.Context, workspaceID, currency string) (string, error)
+
+var (
+	ErrInvalidArgument = errors.New("contracts: invalid argument")
+	ErrNotFound        = errors.New("contracts: not found")
+	ErrOverlap         = errors.New("contracts: overlaps an active contract")
+)
+
+func NewService(repo Repository, spend SpendSource, w Debiter, auditSvc *audit.Service) *Service {
+	return &Service{repo: repo, spend: spend, wallet: w, audit: auditSvc, clock: time.Now}
+}
+
+// CreateContract stores a new active contract. Terms may not overlap another active contract.
+func (s *Service) CreateContract(ctx context.Context, actorUserID, actorRole string, c Contract) (Contract, error) {
+	if c.WorkspaceID == "" || actorUserID == "" || c.Currency == "" || c.MinimumMonthlyCommitMinor <= 0 {
+		return Contract{}, ErrInvalidArgument
+	}
+	if c.TermStart.IsZero() || !c.TermEnd.After(c.TermStart) {
+		return Contract{}, ErrInvalidArgument
+	}
+	for _, r := range c.OverageRates {
+		if r.Destination == "" || r.RatePerMinuteMinor < 0 {
+			return Contract{}, ErrInvalidArgument
+		}
+	}
+	existing, err := s.repo.ListContracts(ctx, c.WorkspaceID)
+	if err != nil {
+		return Contract{}, err
+	}
+	for _, e := range existing {
+		if e.Status == StatusActive && c.TermStart.Before(e.TermEnd) && e.TermStart.Before(c.TermEnd) {
+			return Contract{}, ErrOverlap
+		}
+	}
+
+	now := s.clock().UTC()
+	c.ID = uuid.NewString()
+	c.Status = StatusActive
+	c.TermStart = c.TermStart.UTC()
+	c.TermEnd = c.TermEnd.UTC()
+	c.CreatedByUserID = actorUserID
+	c.CreatedAt = now
+	c.UpdatedAt = now
+	if err := s.repo.CreateContract(ctx, c); err != nil {
+		return Contract{}, err
+	}
+	if s.audit != nil {
+		meta, _ := json.Marshal(map[string]string{"contract_id": c.ID})
+		_ = s.audit.LogAdminAction(ctx, c.WorkspaceID, actorUserID, actorRole, "",
+			fmt.Sprintf("contract created: commit %d %s/month", c.MinimumMonthlyCommitMinor, c.Currency), "",
+			string(meta))
+	}
+	return c, nil
+}
+
+// CancelContract stops true-ups from the month of cancellation on; earlier months are still owed.
+func (s *Service) CancelContract(ctx context.Context, workspaceID, contractID, actorUserID, actorRole string) (Contract, error) {
+	c, ok, err := s.repo.GetContract(ctx, workspaceID, contractID)
+	if err != nil {
+		return Contract{}, err
+	}
+	if !ok {
+		return Contract{}, ErrNotFound
+	}
+	now := s.clock().UTC()
+	c.Status = StatusCanceled
+	c.CanceledAt = &now
+	c.UpdatedAt = now
+	if err := s.repo.UpdateContract(ctx, c); err != nil {
+		return Contract{}, err
+	}
+	if s.audit != nil {
+		meta, _ := json.Marshal(map[string]string{"contract_id": c.ID})
+		_ = s.audit.LogAdminAction(ctx, workspaceID, actorUserID, actorRole, "", "contract canceled", "", string(meta))
+	}
+	return c, nil
+}
+
+// ActiveContract returns the workspace's contract covering `at`, if any.
+func (s *Service) ActiveContract(ctx context.Context, workspaceID string, at time.Time) (Contract, bool, error) {
+	if workspaceID == "" {
+		return Contract{}, false, ErrInvalidArgument
+	}
+	items, err := s.repo.ListContracts(ctx, workspaceID)
+	if err != nil {
+		return Contract{}, false, err
+	}
+	for _, c := range items {
+		if c.Status == StatusActive && !at.Before(c.TermStart) && at.Before(c.TermEnd) {
+			return c, true, nil
+		}
+	}
+	return Contract{}, false, nil
+}
+
+// Status returns spend vs commitment for the month containing `at` (service clock if zero).
+func (s *Service) Status(ctx context.Context, workspaceID string, at time.Time) (PeriodStatus, bool, error) {
+	if at.IsZero() {
+		at = s.clock().UTC()
+	}
+	c, ok, err := s.ActiveContract(ctx, workspaceID, at)
+	if err != nil || !ok {
+		return PeriodStatus{}, ok, err
+	}
+	ps, err := s.periodStatus(ctx, c, monthStart(at))
+	return ps, true, err
+}
+
+func (s *Service) periodStatus(ctx context.Context, c Contract, month time.Time) (PeriodStatus, error) {
+	spend, err := s.spend.UsageSpendMinor(ctx, c.WorkspaceID, c.Currency, month, month.AddDate(0, 1, 0))
+	if err != nil {
+		return PeriodStatus{}, err
+	}
+	period := month.Format("2006-01")
+	out := PeriodStatus{Contract: c, Period: period, CommitMinor: c.MinimumMonthlyCommitMinor, SpendMinor: spend}
+	if spend >= c.MinimumMonthlyCommitMinor {
+		out.CommitMet = true
+	} else {
+		out.ShortfallMinor = c.MinimumMonthlyCommitMinor - spend
+	}
+	if t, ok, err := s.repo.GetTrueUp(ctx, c.WorkspaceID, c.ID, period); err != nil {
+		return PeriodStatus{}, err
+	} else if ok {
+		out.TrueUp = &t
+	}
+	return out, nil
+}
+
+// RunTrueUps posts true-ups for every closed, unsettled month of every contract
+// due (see ListContractsDue). Already-posted months are skipped; failed ones are
+// retried and hold SettledThrough back until they post. Active contracts past
+// their term are marked expired.
+func (s *Service) RunTrueUps(ctx context.Context, resolveWallet WalletResolver) ([]TrueUp, error) {
+	if resolveWallet == nil || s.wallet == nil {
+		return nil, errors.New("contracts: wallet not configured")
+	}
+	now := s.clock().UTC()
+	closed := monthStart(now)
+
+	items, err := s.repo.ListContractsDue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []TrueUp
+	for _, c := range items {
+		end := c.trueUpEnd()
+		if closed.Before(end) {
+			end = closed
+		}
+		settled, blocked := c.settledFrom(), false
+		for m := settled; m.Before(end); m = m.AddDate(0, 1, 0) {
+			done := true
+			if c.coversMonth(m) {
+				t, posted, err := s.trueUpMonth(ctx, c, m, resolveWallet, now)
+				if err != nil {
+					return out, err
+				}
+				if t != nil {
+					out = append(out, *t)
+				}
+				done = posted
+			}
+			if !done {
+				blocked = true
+			}
+			if !blocked {
+				settled = m.AddDate(0, 1, 0)
+			}
+		}
+
+		changed := !settled.Equal(c.SettledThrough)
+		c.SettledThrough = settled
+		if c.Status == StatusActive && !now.Before(c.TermEnd) {
+			c.Status = StatusExpired
+			changed = true
+		}
+		if changed {
+			c.UpdatedAt = now
+			if err := s.repo.UpdateContract(ctx, c); err != nil {
+				return out, err
+			}
+		}
+	}
+	return out, nil
+}
+
+// trueUpMonth charges the shortfall for one closed month. It returns the true-up it
+// saved (nil when nothing was owed or it was already posted) and whether the month
+// is settled.
+func (s *Service) trueUpMonth(ctx context.Context, c Contract, month time.Time, resolveWallet WalletResolver, now time.Time) (*TrueUp, bool, error) {
+	ps, err := s.periodStatus(ctx, c, month)
+	if err != nil {
+		return nil, false, err
+	}
+	if ps.CommitMet || (ps.TrueUp != nil && ps.TrueUp.Status == TrueUpStatusPosted) {
+		return nil, true, nil
+	}
+
+	t := TrueUp{
+		ID:             uuid.NewString(),
+		WorkspaceID:    c.WorkspaceID,
+		ContractID:     c.ID,
+		Period:         ps.Period,
+		CommitMinor:    ps.CommitMinor,
+		SpendMinor:     ps.SpendMinor,
+		AmountMinor:    ps.ShortfallMinor,
+		Currency:       c.Currency,
+		IdempotencyKey: fmt.Sprintf("contract:%s:trueup:%s", c.ID, ps.Period),
+		CreatedAt:      now,
+	}
+	if ps.TrueUp != nil {
+		t.ID = ps.TrueUp.ID
+	}
+	walletID, err := resolveWallet(ctx, c.WorkspaceID, c.Currency)
+	if err == nil {
+		t.WalletID = walletID
+		// A struct keeps the key order of earlier true-ups: the metadata bytes are
+		// part of the debit's idempotency fingerprint.
+		meta, _ := json.Marshal(struct {
+			ContractID  string `json:"contract_id"`
+			Period      string `json:"period"`
+			CommitMinor int64  `json:"commit_minor"`
+			SpendMinor  int64  `json:"spend_minor"`
+		}{c.ID, t.Period, t.CommitMinor, t.SpendMinor})
+		var entry wallet.WalletLedger
+		entry, _, err = s.wallet.Debit(ctx, c.WorkspaceID, walletID, wallet.DebitRequest{
+			AmountMinor:    t.AmountMinor,
+			Currency:       c.Currency,
+			ExternalRef:    "contract_true_up",
+			IdempotencyKey: t.IdempotencyKey,
+			Metadata:       string(meta),
+		})
+		t.LedgerID = entry.ID
+	}
+	if err != nil {
+		t.Status = TrueUpStatusFailed
+		t.Error = err.Error()
+	} else {
+		t.Status = TrueUpStatusPosted
+	}
+	if err := s.repo.SaveTrueUp(ctx, t); err != nil {
+		return nil, false, err
+	}
+	return &t, t.Status == TrueUpStatusPosted, nil
+}
+
+// ListContracts returns all contracts for a workspace.
+func (s *Service) ListContracts(ctx context.Context, workspaceID string) ([]Contract, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	return s.repo.ListContracts(ctx, workspaceID)
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,177 @@
+package contracts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/pricing"
+	"telecom-platform/internal/wallet"
+)
+
+type stubSpend map[string]int64 // key: YYYY-MM of `from`
+
+func (s stubSpend) UsageSpendMinor(ctx context.Context, workspaceID, currency string, from, to time.Time) (int64, error) {
+	s["calls"]++
+	return s[from.Format("2006-01")], nil
+}
+
+type stubWallet struct {
+	debits   map[string]int64
+	metadata map[string]string // idempotency key -> metadata
+}
+
+func (w *stubWallet) Debit(ctx context.Context, workspaceID, walletID string, req wallet.DebitRequest) (wallet.WalletLedger, wallet.Balance, error) {
+	w.debits[req.IdempotencyKey] = req.AmountMinor
+	w.metadata[req.IdempotencyKey] = req.Metadata
+	return wallet.WalletLedger{ID: "l-" + req.IdempotencyKey}, wallet.Balance{}, nil
+}
+
+func newTestService(spend stubSpend, now time.Time) (*Service, *stubWallet) {
+	w := &stubWallet{debits: map[string]int64{}, metadata: map[string]string{}}
+	svc := NewService(NewMemoryRepo(), spend, w, nil)
+	svc.clock = func() time.Time { return now }
+	return svc, w
+}
+
+func TestContracts_TrueUpPostsShortfallOnce(t *testing.T) {
+	now := time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC)
+	svc, w := newTestService(stubSpend{"2024-01": 10000, "2024-02": 12000, "2024-03": 7000, "2024-04": 500}, now)
+	c, err := svc.CreateContract(context.Background(), "admin", "super_admin", Contract{
+		WorkspaceID: "w", Currency: "USD", MinimumMonthlyCommitMinor: 10000,
+		TermStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), TermEnd: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	resolve := func(ctx context.Context, workspaceID, currency string) (string, error) { return "wa", nil }
+	ups, err := svc.RunTrueUps(context.Background(), resolve)
+	if err != nil || len(ups) != 1 {
+		t.Fatalf("expected one true-up, got %+v err=%v", ups, err)
+	}
+	if ups[0].Period != "2024-03" || ups[0].AmountMinor != 3000 || ups[0].Status != TrueUpStatusPosted {
+		t.Fatalf("unexpected true-up: %+v", ups[0])
+	}
+	if w.debits["contract:"+c.ID+":trueup:2024-03"] != 3000 {
+		t.Fatalf("expected wallet debit, got %v", w.debits)
+	}
+	// The metadata bytes feed the debit's idempotency fingerprint, so their key
+	// order must not change between releases.
+	wantMeta := `{"contract_id":"` + c.ID + `","period":"2024-03","commit_minor":10000,"spend_minor":7000}`
+	if got := w.metadata["contract:"+c.ID+":trueup:2024-03"]; got != wantMeta {
+		t.Fatalf("expected metadata %s, got %s", wantMeta, got)
+	}
+
+	if ups, _ := svc.RunTrueUps(context.Background(), resolve); len(ups) != 0 {
+		t.Fatalf("expected no repeat true-up, got %+v", ups)
+	}
+
+	st, ok, err := svc.Status(context.Background(), "w", time.Time{})
+	if err != nil || !ok || st.Period != "2024-04" || st.ShortfallMinor != 9500 || st.CommitMet {
+		t.Fatalf("unexpected status: %+v ok=%v err=%v", st, ok, err)
+	}
+}
+
+func TestContracts_RejectsOverlap(t *testing.T) {
+	svc, _ := newTestService(stubSpend{}, time.Now())
+	base := Contract{WorkspaceID: "w", Currency: "USD", MinimumMonthlyCommitMinor: 1, TermStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), TermEnd: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)}
+	if _, err := svc.CreateContract(context.Background(), "a", "super_admin", base); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	base.TermStart = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	base.TermEnd = time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := svc.CreateContract(context.Background(), "a", "super_admin", base); err != ErrOverlap {
+		t.Fatalf("expected ErrOverlap, got %v", err)
+	}
+}
+
+func TestOverageRates_ApplyAfterCommitConsumed(t *testing.T) {
+	at := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	spend := stubSpend{"2024-03": 0}
+	svc, _ := newTestService(spend, at)
+	_, _ = svc.CreateContract(context.Background(), "a", "super_admin", Contract{
+		WorkspaceID: "w", Currency: "USD", MinimumMonthlyCommitMinor: 1000,
+		TermStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), TermEnd: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		OverageRates: []OverageRate{{Direction: pricing.CallDirectionOutbound, Destination: "US", RatePerMinuteMinor: 4}},
+	})
+	base := &pricing.MemoryRepo{Minute: []pricing.MinutePricing{{ID: "r", WorkspaceID: "w", Direction: pricing.CallDirectionOutbound, Destination: "US", Currency: "USD", RatePerMinuteMinor: 9, Status: pricing.PricingStatusActive}}}
+	rates := NewOverageRates(base, svc)
+	clock := at
+	rates.now = func() time.Time { return clock }
+
+	mp, _, _ := rates.FindMinutePricing(context.Background(), "w", pricing.CallDirectionOutbound, "US", at)
+	if mp.RatePerMinuteMinor != 9 {
+		t.Fatalf("expected list rate before commit consumed, got %d", mp.RatePerMinuteMinor)
+	}
+	spend["2024-03"] = 1000
+	mp, _, _ = rates.FindMinutePricing(context.Background(), "w", pricing.CallDirectionOutbound, "US", at)
+	if mp.RatePerMinuteMinor != 9 || spend["calls"] != 1 {
+		t.Fatalf("expected cached commit check within the TTL, got rate %d after %d spend lookups", mp.RatePerMinuteMinor, spend["calls"])
+	}
+	clock = clock.Add(DefaultCommitCheckTTL)
+	mp, _, _ = rates.FindMinutePricing(context.Background(), "w", pricing.CallDirectionOutbound, "US", at)
+	if mp.RatePerMinuteMinor != 4 {
+		t.Fatalf("expected overage rate, got %d", mp.RatePerMinuteMinor)
+	}
+	clock = clock.Add(time.Hour)
+	spend["2024-03"] = 0
+	mp, _, _ = rates.FindMinutePricing(context.Background(), "w", pricing.CallDirectionOutbound, "US", at)
+	if mp.RatePerMinuteMinor != 4 || spend["calls"] != 2 {
+		t.Fatalf("expected consumed commit to stay cached for the month, got rate %d after %d spend lookups", mp.RatePerMinuteMinor, spend["calls"])
+	}
+}
+
+func TestContracts_TrueUpCatchesUpMissedMonthsAndExpires(t *testing.T) {
+	now := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	svc, w := newTestService(stubSpend{"2024-02": 10000}, now)
+	c, err := svc.CreateContract(context.Background(), "admin", "super_admin", Contract{
+		WorkspaceID: "w", Currency: "USD", MinimumMonthlyCommitMinor: 10000,
+		TermStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), TermEnd: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	resolve := func(ctx context.Context, workspaceID, currency string) (string, error) { return "wa", nil }
+	ups, err := svc.RunTrueUps(context.Background(), resolve)
+	if err != nil || len(ups) != 2 || ups[0].Period != "2024-01" || ups[1].Period != "2024-03" {
+		t.Fatalf("expected true-ups for 2024-01 and 2024-03, got %+v err=%v", ups, err)
+	}
+	if len(w.debits) != 2 {
+		t.Fatalf("expected two debits, got %v", w.debits)
+	}
+	got, _, _ := svc.repo.GetContract(context.Background(), "w", c.ID)
+	if got.Status != StatusExpired || !got.SettledThrough.Equal(c.TermEnd) {
+		t.Fatalf("expected expired contract settled through term end, got %+v", got)
+	}
+	if ups, _ := svc.RunTrueUps(context.Background(), resolve); len(ups) != 0 {
+		t.Fatalf("expected nothing left to true up, got %+v", ups)
+	}
+}
+
+func TestContracts_CanceledContractTruesUpClosedMonths(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc, w := newTestService(stubSpend{}, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC))
+	c, err := svc.CreateContract(context.Background(), "admin", "super_admin", Contract{
+		WorkspaceID: "w", Currency: "USD", MinimumMonthlyCommitMinor: 10000,
+		TermStart: start, TermEnd: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if _, err := svc.CancelContract(context.Background(), "w", c.ID, "admin", "super_admin"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	svc.clock = func() time.Time { return time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC) }
+	resolve := func(ctx context.Context, workspaceID, currency string) (string, error) { return "wa", nil }
+	ups, err := svc.RunTrueUps(context.Background(), resolve)
+	if err != nil || len(ups) != 2 || ups[0].Period != "2024-01" || ups[1].Period != "2024-02" {
+		t.Fatalf("expected true-ups for the months before cancellation, got %+v err=%v", ups, err)
+	}
+	got, _, _ := svc.repo.GetContract(context.Background(), "w", c.ID)
+	if got.Status != StatusCanceled || len(w.debits) != 2 {
+		t.Fatalf("unexpected contract %+v debits %v", got, w.debits)
+	}
+}
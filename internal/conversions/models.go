@@ -0,0 +1,121 @@
+package conversions
+
+import "time"
+
+type Platform string
+
+const (
+	PlatformGoogleAds Platform = "google_ads"
+	PlatformMeta      Platform = "meta"
+)
+
+// Integration forwards a workspace's qualified calls to one ad platform.
+type Integration struct {
+	ID          string   `json:"id" db:"id"`
+	WorkspaceID string   `json:"workspace_id" db:"workspace_id"`
+	Platform    Platform `json:"platform" db:"platform"`
+	Name        string   `json:"name" db:"name"`
+	Enabled     bool     `json:"enabled" db:"enabled"`
+
+	// CampaignIDs limits forwarding to these campaigns; empty means all.
+	CampaignIDs []string `json:"campaign_ids" db:"campaign_ids"`
+	// MinDurationSeconds qualifies a call as a conversion.
+	MinDurationSeconds int `json:"min_duration_seconds" db:"min_duration_seconds"`
+
+	Mapping Mapping `json:"mapping" db:"mapping"`
+
+	// SealedCredentials is the platform Credentials JSON sealed with the
+	// workspace's data key (see internal/keyring). Never serialized.
+	SealedCredentials []byte `json:"-" db:"sealed_credentials"`
+	// CredentialsHint identifies the stored account without revealing secrets.
+	CredentialsHint string `json:"credentials_hint" db:"credentials_hint"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Mapping says how a call becomes a platform conversion.
+type Mapping struct {
+	// ConversionName is the Google Ads conversion action id or the Meta event name
+	// (e.g. "Lead").
+	ConversionName string `json:"conversion_name"`
+
+	// ValueMinor/Currency is the conversion value reported for every call, unless
+	// UseCallValue is set and the call carries its own value (e.g. a payout).
+	ValueMinor   int64  `json:"value_minor"`
+	Currency     string `json:"currency"`
+	UseCallValue bool   `json:"use_call_value"`
+}
+
+// Credentials are the tenant's platform credentials. Google Ads uses CustomerID,
+// DeveloperToken, AccessToken and optionally LoginCustomerID; Meta uses DatasetID
+// and AccessToken.
+type Credentials struct {
+	AccessToken     string `json:"access_token"`
+	CustomerID      string `json:"customer_id,omitempty"`
+	LoginCustomerID string `json:"login_customer_id,omitempty"`
+	DeveloperToken  string `json:"developer_token,omitempty"`
+	DatasetID       string `json:"dataset_id,omitempty"`
+}
+
+// IntegrationRequest creates or replaces an integration. Credentials are only
+// required on create; omitted on update, the stored ones are kept.
+type IntegrationRequest struct {
+	Platform           Platform     `json:"platform"`
+	Name               string       `json:"name"`
+	Enabled            bool         `json:"enabled"`
+	CampaignIDs        []string     `json:"campaign_ids"`
+	MinDurationSeconds int          `json:"min_duration_seconds"`
+	Mapping            Mapping      `json:"mapping"`
+	Credentials        *Credentials `json:"credentials,omitempty"`
+}
+
+// Call is a completed call offered for forwarding.
+type Call struct {
+	WorkspaceID     string    `json:"workspace_id"`
+	CallID          string    `json:"call_id"`
+	CampaignID      string    `json:"campaign_id"`
+	CallerNumber    string    `json:"caller_number"` // E.164
+	StartedAt       time.Time `json:"started_at"`
+	DurationSeconds int       `json:"duration_seconds"`
+
+	// ValueMinor/Currency is the call's own value, used with Mapping.UseCallValue.
+	ValueMinor int64  `json:"value_minor,omitempty"`
+	Currency   string `json:"currency,omitempty"`
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is the log entry for one conversion sent to one integration.
+type Delivery struct {
+	ID            string         `json:"id" db:"id"`
+	WorkspaceID   string         `json:"workspace_id" db:"workspace_id"`
+	IntegrationID string         `json:"integration_id" db:"integration_id"`
+	Platform      Platform       `json:"platform" db:"platform"`
+	CallID        string         `json:"call_id" db:"call_id"`
+	Call          Call           `json:"call" db:"call"`
+	Status        DeliveryStatus `json:"status" db:"status"`
+
+	Attempts      int       `json:"attempts" db:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError     string    `json:"last_error,omitempty" db:"last_error"`
+	// ExternalRef is the platform's acknowledgement (e.g. Meta's fbtrace_id).
+	ExternalRef string     `json:"external_ref,omitempty" db:"external_ref"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty" db:"delivered_at"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DeliveryFilter narrows ListDeliveries. Empty fields match everything.
+type DeliveryFilter struct {
+	IntegrationID string
+	Status        DeliveryStatus
+	Limit         int
+}
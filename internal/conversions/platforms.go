@@ -0,0 +1,192 @@
+package conversions
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/wallet"
+)
+
+// Uploader sends one conversion to an ad platform and returns the platform's
+// acknowledgement, if any.
+type Uploader interface {
+	Upload(ctx context.Context, creds Credentials, m Mapping, call Call) (externalRef string, err error)
+}
+
+// ErrRejected marks a conversion the platform refused (bad request, revoked
+// credentials). The worker does not retry it.
+var ErrRejected = errors.New("conversions: rejected by platform")
+
+// GoogleAdsUploader uploads call conversions through the Google Ads REST API
+// (customers/{id}:uploadCallConversions). Google matches the call to the ad click
+// by caller id and call start time, so no click id is needed.
+type GoogleAdsUploader struct {
+	Client     *http.Client
+	BaseURL    string // default https://googleads.googleapis.com
+	APIVersion string // default v17
+}
+
+func (u GoogleAdsUploader) Upload(ctx context.Context, creds Credentials, m Mapping, call Call) (string, error) {
+	customerID := strings.ReplaceAll(creds.CustomerID, "-", "")
+	conv := map[string]any{
+		"callerId":           call.CallerNumber,
+		"callStartDateTime":  googleTime(call.StartedAt),
+		"conversionAction":   "customers/" + customerID + "/conversionActions/" + m.ConversionName,
+		"conversionDateTime": googleTime(call.StartedAt.Add(time.Duration(call.DurationSeconds) * time.Second)),
+	}
+	if value, currency, ok := conversionValue(m, call); ok {
+		conv["conversionValue"] = value
+		conv["currencyCode"] = currency
+	}
+	body, err := json.Marshal(map[string]any{"conversions": []any{conv}, "partialFailure": true})
+	if err != nil {
+		return "", err
+	}
+	url := orDefault(u.BaseURL, "https://googleads.googleapis.com") + "/" + orDefault(u.APIVersion, "v17") +
+		"/customers/" + customerID + ":uploadCallConversions"
+	headers := map[string]string{
+		"Authorization":   "Bearer " + creds.AccessToken,
+		"developer-token": creds.DeveloperToken,
+	}
+	if creds.LoginCustomerID != "" {
+		headers["login-customer-id"] = strings.ReplaceAll(creds.LoginCustomerID, "-", "")
+	}
+	resp, err := post(ctx, u.Client, url, headers, body)
+	if err != nil {
+		return "", err
+	}
+	// With partialFailure the request succeeds and per-row errors are reported here.
+	var out struct {
+		PartialFailureError *struct {
+			Message string `json:"message"`
+		} `json:"partialFailureError"`
+	}
+	if err := json.Unmarshal(resp, &out); err == nil && out.PartialFailureError != nil && out.PartialFailureError.Message != "" {
+		return "", fmt.Errorf("%w: %s", ErrRejected, out.PartialFailureError.Message)
+	}
+	return "", nil
+}
+
+// MetaUploader sends phone_call events to the Meta Conversions API
+// ({dataset_id}/events). The caller's number is normalized and SHA-256 hashed as
+// Meta requires; the call id is the event_id so Meta drops duplicates.
+type MetaUploader struct {
+	Client     *http.Client
+	BaseURL    string // default https://graph.facebook.com
+	APIVersion string // default v19.0
+}
+
+func (u MetaUploader) Upload(ctx context.Context, creds Credentials, m Mapping, call Call) (string, error) {
+	event := map[string]any{
+		"event_name":    m.ConversionName,
+		"event_time":    call.StartedAt.Unix(),
+		"event_id":      call.CallID,
+		"action_source": "phone_call",
+		"user_data":     map[string]any{"ph": []string{hashPhone(call.CallerNumber)}},
+	}
+	if value, currency, ok := conversionValue(m, call); ok {
+		event["custom_data"] = map[string]any{"value": value, "currency": currency}
+	}
+	body, err := json.Marshal(map[string]any{"data": []any{event}})
+	if err != nil {
+		return "", err
+	}
+	url := orDefault(u.BaseURL, "https://graph.facebook.com") + "/" + orDefault(u.APIVersion, "v19.0") +
+		"/" + creds.DatasetID + "/events"
+	resp, err := post(ctx, u.Client, url, map[string]string{"Authorization": "Bearer " + creds.AccessToken}, body)
+	if err != nil {
+		return "", err
+	}
+	var out struct {
+		FBTraceID string `json:"fbtrace_id"`
+	}
+	_ = json.Unmarshal(resp, &out)
+	return out.FBTraceID, nil
+}
+
+// post sends a JSON body. 4xx responses other than 429 are ErrRejected.
+func post(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	out, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode/100 == 2:
+		return out, nil
+	case resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusTooManyRequests:
+		return nil, fmt.Errorf("%w: status %d: %s", ErrRejected, resp.StatusCode, truncate(string(out), 300))
+	default:
+		return nil, fmt.Errorf("conversions: platform returned %d", resp.StatusCode)
+	}
+}
+
+// conversionValue returns the value to report in major units.
+func conversionValue(m Mapping, call Call) (float64, string, bool) {
+	minor, currency := m.ValueMinor, m.Currency
+	if m.UseCallValue && call.ValueMinor > 0 && call.Currency != "" {
+		minor, currency = call.ValueMinor, call.Currency
+	}
+	if minor <= 0 || currency == "" {
+		return 0, "", false
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(wallet.MinorUnitExponent(currency))), nil)
+	v, _ := new(big.Rat).SetFrac(big.NewInt(minor), scale).Float64()
+	return v, currency, true
+}
+
+// hashPhone normalizes an E.164 number to digits only and hashes it (Meta's "ph" format).
+func hashPhone(e164 string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, e164)
+	sum := sha256.Sum256([]byte(digits))
+	return hex.EncodeToString(sum[:])
+}
+
+// googleTime is the "yyyy-mm-dd hh:mm:ss+00:00" format Google Ads expects.
+func googleTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05-07:00")
+}
+
+func orDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return strings.TrimRight(v, "/")
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
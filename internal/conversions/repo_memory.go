@@ -0,0 +1,126 @@
+package conversions
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a simple in-memory Store useful for tests.
+// It is not intended for production use.
+
+type MemoryStore struct {
+	mu           sync.Mutex
+	integrations map[string]Integration
+	deliveries   map[string]Delivery
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{integrations: map[string]Integration{}, deliveries: map[string]Delivery{}}
+}
+
+func (s *MemoryStore) PutIntegration(ctx context.Context, in Integration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	in.CampaignIDs = append([]string(nil), in.CampaignIDs...)
+	s.integrations[in.ID] = in
+	return nil
+}
+
+func (s *MemoryStore) GetIntegration(ctx context.Context, workspaceID, id string) (Integration, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	in, ok := s.integrations[id]
+	if !ok || in.WorkspaceID != workspaceID {
+		return Integration{}, false, nil
+	}
+	return in, true, nil
+}
+
+func (s *MemoryStore) ListIntegrations(ctx context.Context, workspaceID string) ([]Integration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Integration, 0)
+	for _, in := range s.integrations {
+		if in.WorkspaceID == workspaceID {
+			out = append(out, in)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (s *MemoryStore) DeleteIntegration(ctx context.Context, workspaceID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if in, ok := s.integrations[id]; ok && in.WorkspaceID == workspaceID {
+		delete(s.integrations, id)
+	}
+	return nil
+}
+
+func (s *MemoryStore) CreateDelivery(ctx context.Context, d Delivery) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.deliveries[d.ID]; ok {
+		return false, nil
+	}
+	s.deliveries[d.ID] = d
+	return true, nil
+}
+
+func (s *MemoryStore) UpdateDelivery(ctx context.Context, d Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cur, ok := s.deliveries[d.ID]; !ok || cur.WorkspaceID != d.WorkspaceID {
+		return ErrNotFound
+	}
+	s.deliveries[d.ID] = d
+	return nil
+}
+
+func (s *MemoryStore) GetDelivery(ctx context.Context, workspaceID, id string) (Delivery, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.deliveries[id]
+	if !ok || d.WorkspaceID != workspaceID {
+		return Delivery{}, false, nil
+	}
+	return d, true, nil
+}
+
+func (s *MemoryStore) ListDeliveries(ctx context.Context, workspaceID string, f DeliveryFilter) ([]Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Delivery, 0)
+	for _, d := range s.deliveries {
+		if d.WorkspaceID != workspaceID ||
+			(f.IntegrationID != "" && d.IntegrationID != f.IntegrationID) ||
+			(f.Status != "" && d.Status != f.Status) {
+			continue
+		}
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	if f.Limit > 0 && len(out) > f.Limit {
+		out = out[:f.Limit]
+	}
+	return out, nil
+}
+
+func (s *MemoryStore) DueDeliveries(ctx context.Context, now time.Time, limit int) ([]Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Delivery, 0)
+	for _, d := range s.deliveries {
+		if d.Status == DeliveryPending && !d.NextAttemptAt.After(now) {
+			out = append(out, d)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].NextAttemptAt.Before(out[j].NextAttemptAt) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
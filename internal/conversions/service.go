@@ -0,0 +1,321 @@
+package conversions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Offline conversion forwarding.
+//
+// Call-tracking advertisers want their ad platforms to learn which clicks turned
+// into phone calls. A workspace configures integrations (Google Ads call
+// conversions, Meta Conversions API) with its own platform credentials; every
+// completed call that qualifies (campaign filter, minimum duration) gets one
+// Delivery per integration, and DeliveryWorker sends it with retries.
+//
+// Credentials are sealed with the workspace's data key before they are stored and
+// are only opened by the worker. Deliveries are keyed by integration and call, so
+// recording the same call twice (at-least-once events) does not send it twice.
+
+// Store persists integrations and deliveries. Implementations must enforce
+// workspace filtering.
+type Store interface {
+	PutIntegration(ctx context.Context, in Integration) error
+	GetIntegration(ctx context.Context, workspaceID, id string) (Integration, bool, error)
+	ListIntegrations(ctx context.Context, workspaceID string) ([]Integration, error)
+	DeleteIntegration(ctx context.Context, workspaceID, id string) error
+
+	// CreateDelivery returns false when a delivery with the same id exists.
+	CreateDelivery(ctx context.Context, d Delivery) (bool, error)
+	UpdateDelivery(ctx context.Context, d Delivery) error
+	GetDelivery(ctx context.Context, workspaceID, id string) (Delivery, bool, error)
+	ListDeliveries(ctx context.Context, workspaceID string, f DeliveryFilter) ([]Delivery, error)
+	// DueDeliveries returns pending deliveries with NextAttemptAt <= now, across workspaces.
+	DueDeliveries(ctx context.Context, now time.Time, limit int) ([]Delivery, error)
+}
+
+// Sealer encrypts credentials per workspace; keyring.Service implements it.
+type Sealer interface {
+	Seal(ctx context.Context, workspaceID, objectKey string, plaintext []byte) ([]byte, error)
+	Open(ctx context.Context, workspaceID, objectKey string, sealed []byte) ([]byte, error)
+}
+
+var (
+	ErrInvalidArgument = errors.New("conversions: invalid argument")
+	ErrNotFound        = errors.New("conversions: not found")
+)
+
+type Service struct {
+	store  Store
+	sealer Sealer
+	clock  func() time.Time
+}
+
+func NewService(store Store, sealer Sealer) *Service {
+	return &Service{store: store, sealer: sealer, clock: time.Now}
+}
+
+// CreateIntegration stores a new integration with sealed credentials.
+func (s *Service) CreateIntegration(ctx context.Context, workspaceID string, req IntegrationRequest) (Integration, error) {
+	if workspaceID == "" || req.Credentials == nil {
+		return Integration{}, ErrInvalidArgument
+	}
+	now := s.clock().UTC()
+	in := Integration{ID: uuid.NewString(), WorkspaceID: workspaceID, CreatedAt: now}
+	return s.saveIntegration(ctx, in, req, now)
+}
+
+// UpdateIntegration replaces an integration's settings, keeping its credentials
+// unless new ones are given. The platform cannot change.
+func (s *Service) UpdateIntegration(ctx context.Context, workspaceID, id string, req IntegrationRequest) (Integration, error) {
+	in, err := s.GetIntegration(ctx, workspaceID, id)
+	if err != nil {
+		return Integration{}, err
+	}
+	if req.Platform != "" && req.Platform != in.Platform {
+		return Integration{}, fmt.Errorf("%w: platform cannot change", ErrInvalidArgument)
+	}
+	req.Platform = in.Platform
+	return s.saveIntegration(ctx, in, req, s.clock().UTC())
+}
+
+func (s *Service) saveIntegration(ctx context.Context, in Integration, req IntegrationRequest, now time.Time) (Integration, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Mapping.ConversionName = strings.TrimSpace(req.Mapping.ConversionName)
+	req.Mapping.Currency = strings.ToUpper(strings.TrimSpace(req.Mapping.Currency))
+	if req.Platform != PlatformGoogleAds && req.Platform != PlatformMeta {
+		return Integration{}, fmt.Errorf("%w: platform must be google_ads or meta", ErrInvalidArgument)
+	}
+	if req.Name == "" || req.Mapping.ConversionName == "" || req.MinDurationSeconds < 0 || req.Mapping.ValueMinor < 0 {
+		return Integration{}, ErrInvalidArgument
+	}
+	if req.Mapping.ValueMinor > 0 && len(req.Mapping.Currency) != 3 {
+		return Integration{}, fmt.Errorf("%w: currency required with a value", ErrInvalidArgument)
+	}
+	if req.Credentials != nil {
+		if err := validateCredentials(req.Platform, *req.Credentials); err != nil {
+			return Integration{}, err
+		}
+		raw, err := json.Marshal(req.Credentials)
+		if err != nil {
+			return Integration{}, err
+		}
+		sealed, err := s.sealer.Seal(ctx, in.WorkspaceID, credentialsKey(in.ID), raw)
+		if err != nil {
+			return Integration{}, err
+		}
+		in.SealedCredentials = sealed
+		in.CredentialsHint = credentialsHint(req.Platform, *req.Credentials)
+	}
+
+	in.Platform = req.Platform
+	in.Name = req.Name
+	in.Enabled = req.Enabled
+	in.CampaignIDs = append([]string(nil), req.CampaignIDs...)
+	in.MinDurationSeconds = req.MinDurationSeconds
+	in.Mapping = req.Mapping
+	in.UpdatedAt = now
+	if err := s.store.PutIntegration(ctx, in); err != nil {
+		return Integration{}, err
+	}
+	return in, nil
+}
+
+func (s *Service) GetIntegration(ctx context.Context, workspaceID, id string) (Integration, error) {
+	if workspaceID == "" || id == "" {
+		return Integration{}, ErrInvalidArgument
+	}
+	in, ok, err := s.store.GetIntegration(ctx, workspaceID, id)
+	if err != nil {
+		return Integration{}, err
+	}
+	if !ok {
+		return Integration{}, ErrNotFound
+	}
+	return in, nil
+}
+
+func (s *Service) ListIntegrations(ctx context.Context, workspaceID string) ([]Integration, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	return s.store.ListIntegrations(ctx, workspaceID)
+}
+
+// DeleteIntegration removes an integration. Its delivery log is kept.
+func (s *Service) DeleteIntegration(ctx context.Context, workspaceID, id string) error {
+	if _, err := s.GetIntegration(ctx, workspaceID, id); err != nil {
+		return err
+	}
+	return s.store.DeleteIntegration(ctx, workspaceID, id)
+}
+
+// RecordCall queues a delivery to every enabled integration the call qualifies
+// for and returns how many were queued. Re-recording a call is a no-op.
+func (s *Service) RecordCall(ctx context.Context, call Call) (int, error) {
+	if call.WorkspaceID == "" || call.CallID == "" || call.DurationSeconds < 0 {
+		return 0, ErrInvalidArgument
+	}
+	ins, err := s.store.ListIntegrations(ctx, call.WorkspaceID)
+	if err != nil {
+		return 0, err
+	}
+	now := s.clock().UTC()
+	n := 0
+	for _, in := range ins {
+		if !qualifies(in, call) {
+			continue
+		}
+		created, err := s.store.CreateDelivery(ctx, Delivery{
+			ID:            deliveryID(in.ID, call.CallID),
+			WorkspaceID:   call.WorkspaceID,
+			IntegrationID: in.ID,
+			Platform:      in.Platform,
+			CallID:        call.CallID,
+			Call:          call,
+			Status:        DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+		if err != nil {
+			return n, err
+		}
+		if created {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// ListDeliveries returns the workspace's delivery log, newest first.
+func (s *Service) ListDeliveries(ctx context.Context, workspaceID string, f DeliveryFilter) ([]Delivery, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	if f.Limit <= 0 || f.Limit > 200 {
+		f.Limit = 50
+	}
+	return s.store.ListDeliveries(ctx, workspaceID, f)
+}
+
+// RetryDelivery re-queues a failed delivery with a fresh attempt budget.
+func (s *Service) RetryDelivery(ctx context.Context, workspaceID, id string) (Delivery, error) {
+	if workspaceID == "" || id == "" {
+		return Delivery{}, ErrInvalidArgument
+	}
+	d, ok, err := s.store.GetDelivery(ctx, workspaceID, id)
+	if err != nil {
+		return Delivery{}, err
+	}
+	if !ok {
+		return Delivery{}, ErrNotFound
+	}
+	if d.Status != DeliveryFailed {
+		return Delivery{}, fmt.Errorf("%w: only failed deliveries can be retried", ErrInvalidArgument)
+	}
+	now := s.clock().UTC()
+	d.Status = DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	d.UpdatedAt = now
+	if err := s.store.UpdateDelivery(ctx, d); err != nil {
+		return Delivery{}, err
+	}
+	return d, nil
+}
+
+// ConvertedCalls reports which of callIDs were delivered to at least one of the
+// workspace's current integrations. Reporting uses it to count conversions per
+// source.
+func (s *Service) ConvertedCalls(ctx context.Context, workspaceID string, callIDs []string) (map[string]bool, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	ins, err := s.store.ListIntegrations(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]bool{}
+	for _, callID := range callIDs {
+		for _, in := range ins {
+			d, ok, err := s.store.GetDelivery(ctx, workspaceID, deliveryID(in.ID, callID))
+			if err != nil {
+				return nil, err
+			}
+			if ok && d.Status == DeliveryDelivered {
+				out[callID] = true
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+// credentials opens an integration's sealed credentials.
+func (s *Service) credentials(ctx context.Context, in Integration) (Credentials, error) {
+	raw, err := s.sealer.Open(ctx, in.WorkspaceID, credentialsKey(in.ID), in.SealedCredentials)
+	if err != nil {
+		return Credentials{}, err
+	}
+	var c Credentials
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return Credentials{}, err
+	}
+	return c, nil
+}
+
+func qualifies(in Integration, call Call) bool {
+	if !in.Enabled || call.DurationSeconds < in.MinDurationSeconds || call.CallerNumber == "" {
+		return false
+	}
+	if len(in.CampaignIDs) == 0 {
+		return true
+	}
+	for _, id := range in.CampaignIDs {
+		if id == call.CampaignID {
+			return true
+		}
+	}
+	return false
+}
+
+func validateCredentials(p Platform, c Credentials) error {
+	if c.AccessToken == "" {
+		return fmt.Errorf("%w: access_token required", ErrInvalidArgument)
+	}
+	switch p {
+	case PlatformGoogleAds:
+		if c.CustomerID == "" || c.DeveloperToken == "" {
+			return fmt.Errorf("%w: customer_id and developer_token required", ErrInvalidArgument)
+		}
+	case PlatformMeta:
+		if c.DatasetID == "" {
+			return fmt.Errorf("%w: dataset_id required", ErrInvalidArgument)
+		}
+	}
+	return nil
+}
+
+// credentialsHint names the ad account the credentials belong to.
+func credentialsHint(p Platform, c Credentials) string {
+	if p == PlatformGoogleAds {
+		return "customer " + c.CustomerID
+	}
+	return "dataset " + c.DatasetID
+}
+
+// credentialsKey binds sealed credentials to their integration.
+func credentialsKey(integrationID string) string {
+	return "conversions/integrations/" + integrationID
+}
+
+func deliveryID(integrationID, callID string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(integrationID+"|"+callID)).String()
+}
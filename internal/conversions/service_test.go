@@ -0,0 +1,153 @@
+package conversions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/keyring"
+)
+
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	master, err := keyring.NewLocalMasterKey("m1", bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatalf("master key: %v", err)
+	}
+	return NewService(NewMemoryStore(), keyring.NewService(keyring.NewMemoryStore(), master))
+}
+
+func metaRequest() IntegrationRequest {
+	return IntegrationRequest{
+		Platform:           PlatformMeta,
+		Name:               "Meta leads",
+		Enabled:            true,
+		CampaignIDs:        []string{"c1"},
+		MinDurationSeconds: 60,
+		Mapping:            Mapping{ConversionName: "Lead", ValueMinor: 2500, Currency: "usd"},
+		Credentials:        &Credentials{AccessToken: "secret-token", DatasetID: "123"},
+	}
+}
+
+func TestService_RecordCallQualifiesAndDedupes(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+	in, err := svc.CreateIntegration(ctx, "w1", metaRequest())
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if b, _ := json.Marshal(in); strings.Contains(string(b), "secret-token") || bytes.Contains(in.SealedCredentials, []byte("secret-token")) {
+		t.Fatalf("credentials leaked: %s", b)
+	}
+
+	short := Call{WorkspaceID: "w1", CallID: "a", CampaignID: "c1", CallerNumber: "+15550001111", DurationSeconds: 59}
+	other := Call{WorkspaceID: "w1", CallID: "b", CampaignID: "c2", CallerNumber: "+15550001111", DurationSeconds: 120}
+	good := Call{WorkspaceID: "w1", CallID: "c", CampaignID: "c1", CallerNumber: "+15550001111", DurationSeconds: 60}
+	for _, call := range []Call{short, other} {
+		if n, err := svc.RecordCall(ctx, call); err != nil || n != 0 {
+			t.Fatalf("call %s: expected not qualified, got %d %v", call.CallID, n, err)
+		}
+	}
+	if n, err := svc.RecordCall(ctx, good); err != nil || n != 1 {
+		t.Fatalf("expected one delivery, got %d %v", n, err)
+	}
+	if n, _ := svc.RecordCall(ctx, good); n != 0 {
+		t.Fatalf("expected redelivered event to be ignored, got %d", n)
+	}
+	if _, err := svc.UpdateIntegration(ctx, "w1", in.ID, IntegrationRequest{Platform: PlatformGoogleAds, Name: "x", Mapping: Mapping{ConversionName: "1"}}); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected platform change to be rejected, got %v", err)
+	}
+}
+
+func TestDeliveryWorker_SendsToMetaWithRetries(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	svc.clock = func() time.Time { return now }
+	if _, err := svc.CreateIntegration(ctx, "w1", metaRequest()); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	var got map[string]any
+	status := http.StatusInternalServerError
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v19.0/123/events" || r.Header.Get("Authorization") != "Bearer secret-token" {
+			t.Errorf("unexpected request %s %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"events_received":1,"fbtrace_id":"trace-1"}`))
+	}))
+	defer srv.Close()
+
+	w := DeliveryWorker{
+		Conversions:  svc,
+		Uploaders:    map[Platform]Uploader{PlatformMeta: MetaUploader{Client: srv.Client(), BaseURL: srv.URL}},
+		RetryBackoff: time.Minute,
+		Now:          func() time.Time { return now },
+	}
+	call := Call{WorkspaceID: "w1", CallID: "call-1", CampaignID: "c1", CallerNumber: "+1 555 000 1111", StartedAt: now, DurationSeconds: 90}
+	if _, err := svc.RecordCall(ctx, call); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	if n, _ := w.RunOnce(ctx); n != 0 {
+		t.Fatalf("expected transient failure, got %d delivered", n)
+	}
+	if n, _ := w.RunOnce(ctx); n != 0 {
+		t.Fatalf("expected backoff to hold the retry")
+	}
+	now = now.Add(time.Minute)
+	status = http.StatusOK
+	if n, err := w.RunOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("expected delivery after backoff, got %d %v", n, err)
+	}
+
+	list, _ := svc.ListDeliveries(ctx, "w1", DeliveryFilter{})
+	if len(list) != 1 || list[0].Status != DeliveryDelivered || list[0].Attempts != 2 || list[0].ExternalRef != "trace-1" {
+		t.Fatalf("unexpected delivery log: %+v", list)
+	}
+	if conv, err := svc.ConvertedCalls(ctx, "w1", []string{"call-1", "call-2"}); err != nil || len(conv) != 1 || !conv["call-1"] {
+		t.Fatalf("expected call-1 converted, got %v %v", conv, err)
+	}
+	event := got["data"].([]any)[0].(map[string]any)
+	if event["event_id"] != "call-1" || event["action_source"] != "phone_call" {
+		t.Fatalf("unexpected event: %v", event)
+	}
+	if ph := event["user_data"].(map[string]any)["ph"].([]any)[0]; ph != hashPhone("15550001111") {
+		t.Fatalf("expected hashed digits-only phone, got %v", ph)
+	}
+	if v := event["custom_data"].(map[string]any)["value"]; v != 25.0 {
+		t.Fatalf("expected value 25.00, got %v", v)
+	}
+}
+
+func TestDeliveryWorker_RejectedConversionFailsAndCanBeRetried(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+	if _, err := svc.CreateIntegration(ctx, "w1", metaRequest()); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	w := DeliveryWorker{Conversions: svc, Uploaders: map[Platform]Uploader{PlatformMeta: MetaUploader{Client: srv.Client(), BaseURL: srv.URL}}}
+
+	_, _ = svc.RecordCall(ctx, Call{WorkspaceID: "w1", CallID: "x", CampaignID: "c1", CallerNumber: "+15550001111", DurationSeconds: 61})
+	_, _ = w.RunOnce(ctx)
+	list, _ := svc.ListDeliveries(ctx, "w1", DeliveryFilter{Status: DeliveryFailed})
+	if len(list) != 1 || list[0].Attempts != 1 {
+		t.Fatalf("expected one failed delivery without retries, got %+v", list)
+	}
+	d, err := svc.RetryDelivery(ctx, "w1", list[0].ID)
+	if err != nil || d.Status != DeliveryPending {
+		t.Fatalf("retry: %+v %v", d, err)
+	}
+}
@@ -0,0 +1,140 @@
+package conversions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"telecom-platform/pkg/logger"
+)
+
+// DeliveryWorker sends due deliveries. Transient failures back off exponentially
+// from RetryBackoff; after MaxAttempts, or when the platform rejects the
+// conversion, the delivery is marked failed and can be retried from the API.
+// Run a single instance.
+type DeliveryWorker struct {
+	Conversions *Service
+	Uploaders   map[Platform]Uploader
+
+	BatchSize    int
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	Now          func() time.Time
+}
+
+// RunOnce sends one batch and returns how many deliveries succeeded.
+func (w DeliveryWorker) RunOnce(ctx context.Context) (int, error) {
+	due, err := w.Conversions.store.DueDeliveries(ctx, w.now(), w.batchSize())
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, d := range due {
+		if w.deliver(ctx, d) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (w DeliveryWorker) deliver(ctx context.Context, d Delivery) bool {
+	log := logger.From(ctx).With("workspace_id", d.WorkspaceID, "delivery_id", d.ID, "platform", string(d.Platform))
+	ref, err := w.upload(ctx, d)
+	now := w.now()
+	d.Attempts++
+	d.UpdatedAt = now
+	if err == nil {
+		d.Status = DeliveryDelivered
+		d.ExternalRef = ref
+		d.LastError = ""
+		d.DeliveredAt = &now
+	} else {
+		d.LastError = err.Error()
+		if errors.Is(err, ErrRejected) || d.Attempts >= w.maxAttempts() {
+			d.Status = DeliveryFailed
+			log.Error("conversion delivery failed", "attempts", d.Attempts, "err", err)
+		} else {
+			d.NextAttemptAt = now.Add(w.backoff(d.Attempts))
+		}
+	}
+	if uErr := w.Conversions.store.UpdateDelivery(ctx, d); uErr != nil {
+		log.Error("conversion delivery update failed", "err", uErr)
+		return false
+	}
+	return err == nil
+}
+
+func (w DeliveryWorker) upload(ctx context.Context, d Delivery) (string, error) {
+	in, ok, err := w.Conversions.store.GetIntegration(ctx, d.WorkspaceID, d.IntegrationID)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("%w: integration deleted", ErrRejected)
+	}
+	if !in.Enabled {
+		return "", fmt.Errorf("%w: integration disabled", ErrRejected)
+	}
+	up, ok := w.Uploaders[in.Platform]
+	if !ok {
+		return "", fmt.Errorf("conversions: no uploader for %s", in.Platform)
+	}
+	creds, err := w.Conversions.credentials(ctx, in)
+	if err != nil {
+		return "", err
+	}
+	return up.Upload(ctx, creds, in.Mapping, d.Call)
+}
+
+// Run sends due deliveries on every tick until ctx is canceled.
+func (w DeliveryWorker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.RunOnce(ctx); err != nil {
+				logger.From(ctx).Error("conversion delivery run failed", "err", err)
+			}
+		}
+	}
+}
+
+func (w DeliveryWorker) now() time.Time {
+	if w.Now != nil {
+		return w.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (w DeliveryWorker) batchSize() int {
+	if w.BatchSize > 0 {
+		return w.BatchSize
+	}
+	return 100
+}
+
+func (w DeliveryWorker) maxAttempts() int {
+	if w.MaxAttempts > 0 {
+		return w.MaxAttempts
+	}
+	return 8
+}
+
+// backoff doubles from RetryBackoff per attempt, capped at six hours.
+func (w DeliveryWorker) backoff(attempts int) time.Duration {
+	d := w.RetryBackoff
+	if d <= 0 {
+		d = time.Minute
+	}
+	for i := 1; i < attempts && d < 6*time.Hour; i++ {
+		d *= 2
+	}
+	if d > 6*time.Hour {
+		d = 6 * time.Hour
+	}
+	return d
+}
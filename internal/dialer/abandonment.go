@@ -0,0 +1,214 @@
+// Package dialer holds outbound dialer policy shared by the dialer workers.
+package dialer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"telecom-platform/pkg/logger"
+)
+
+// Abandoned-call compliance.
+//
+// A dialed call the callee answers but that is not connected to an agent within
+// AbandonWindow (2 seconds) counts as abandoned. Regulators cap the abandonment
+// rate at 3% of answered calls per campaign, so the tracker keeps daily counts per
+// campaign (local day of the workspace) and the dialer asks DialRatio how many
+// lines to dial per available agent: the campaign's ratio while the rate is low,
+// scaled down towards one line per agent as the rate approaches the threshold.
+
+const (
+	// AbandonWindow is how long an answered call may wait for an agent.
+	AbandonWindow = 2 * time.Second
+	// AbandonThreshold is the regulatory maximum abandonment rate.
+	AbandonThreshold = 0.03
+	// DefaultSlowdownAt is the rate at which the dialer starts slowing down.
+	DefaultSlowdownAt = 0.02
+	// DefaultMinAnswered is the sample below which rates are not acted on.
+	DefaultMinAnswered = 50
+)
+
+var ErrInvalidArgument = errors.New("dialer: invalid argument")
+
+// DailyAbandonment is one campaign's counts for one local day.
+type DailyAbandonment struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	CampaignID  string `json:"campaign_id" db:"campaign_id"`
+	// Day is the workspace-local date (YYYY-MM-DD).
+	Day       string `json:"day" db:"day"`
+	Answered  int    `json:"answered" db:"answered"`
+	Abandoned int    `json:"abandoned" db:"abandoned"`
+}
+
+// Rate is Abandoned/Answered (0 with no answered calls).
+func (d DailyAbandonment) Rate() float64 {
+	if d.Answered == 0 {
+		return 0
+	}
+	return float64(d.Abandoned) / float64(d.Answered)
+}
+
+// AbandonmentStore keeps the daily counts. Implementations must enforce
+// workspace filtering and increment atomically.
+type AbandonmentStore interface {
+	// Increment adds one answered call (and one abandoned if abandoned) and
+	// returns the day's counts afterwards.
+	Increment(ctx context.Context, workspaceID, campaignID, day string, abandoned bool) (DailyAbandonment, error)
+	Get(ctx context.Context, workspaceID, campaignID, day string) (DailyAbandonment, error)
+	// List returns the days in [fromDay, toDay], oldest first; campaignID "" means all.
+	List(ctx context.Context, workspaceID, campaignID, fromDay, toDay string) ([]DailyAbandonment, error)
+}
+
+// Timezones supplies the workspace timezone (IANA name, "" for UTC);
+// reporting.WorkspaceSettings implementations satisfy it.
+type Timezones interface {
+	WorkspaceTimezone(ctx context.Context, workspaceID string) (string, error)
+}
+
+// AnsweredCall is a dialer call the callee picked up.
+type AnsweredCall struct {
+	WorkspaceID string
+	CampaignID  string
+	CallID      string
+	AnsweredAt  time.Time
+	// ConnectedAt is when an agent was bridged; nil if never.
+	ConnectedAt *time.Time
+}
+
+// Abandoned reports whether the call waited longer than AbandonWindow for an agent.
+func (c AnsweredCall) Abandoned() bool {
+	return c.ConnectedAt == nil || c.ConnectedAt.Sub(c.AnsweredAt) > AbandonWindow
+}
+
+type AbandonmentTracker struct {
+	store     AbandonmentStore
+	timezones Timezones
+	clock     func() time.Time
+
+	// SlowdownAt and MinAnswered tune DialRatio; zero uses the defaults.
+	SlowdownAt  float64
+	MinAnswered int
+}
+
+// NewAbandonmentTracker builds a tracker; timezones may be nil (UTC days).
+func NewAbandonmentTracker(store AbandonmentStore, timezones Timezones) *AbandonmentTracker {
+	return &AbandonmentTracker{store: store, timezones: timezones, clock: time.Now}
+}
+
+// RecordAnswered counts an answered call against the local day it was answered.
+func (t *AbandonmentTracker) RecordAnswered(ctx context.Context, c AnsweredCall) (DailyAbandonment, error) {
+	if c.WorkspaceID == "" || c.CampaignID == "" || c.AnsweredAt.IsZero() {
+		return DailyAbandonment{}, ErrInvalidArgument
+	}
+	day, err := t.day(ctx, c.WorkspaceID, c.AnsweredAt)
+	if err != nil {
+		return DailyAbandonment{}, err
+	}
+	d, err := t.store.Increment(ctx, c.WorkspaceID, c.CampaignID, day, c.Abandoned())
+	if err != nil {
+		return DailyAbandonment{}, err
+	}
+	if c.Abandoned() && d.Answered >= t.minAnswered() && d.Rate() >= AbandonThreshold {
+		logger.From(ctx).Warn("dialer abandonment over threshold",
+			"workspace_id", c.WorkspaceID, "campaign_id", c.CampaignID, "day", day,
+			"answered", d.Answered, "abandoned", d.Abandoned, "call_id", c.CallID)
+	}
+	return d, nil
+}
+
+// DialRatio returns the lines to dial per available agent for the campaign now.
+// Below SlowdownAt it is base; from there it falls linearly to 1 at
+// AbandonThreshold and stays at 1 above it. Days with fewer than MinAnswered
+// answered calls use base.
+func (t *AbandonmentTracker) DialRatio(ctx context.Context, workspaceID, campaignID string, base float64) (float64, error) {
+	if workspaceID == "" || campaignID == "" || base <= 0 {
+		return 0, ErrInvalidArgument
+	}
+	if base <= 1 {
+		return base, nil
+	}
+	day, err := t.day(ctx, workspaceID, t.clock())
+	if err != nil {
+		return 0, err
+	}
+	d, err := t.store.Get(ctx, workspaceID, campaignID, day)
+	if err != nil {
+		return 0, err
+	}
+	return t.ratioFor(d, base), nil
+}
+
+func (t *AbandonmentTracker) ratioFor(d DailyAbandonment, base float64) float64 {
+	slowdown := t.SlowdownAt
+	if slowdown <= 0 || slowdown >= AbandonThreshold {
+		slowdown = DefaultSlowdownAt
+	}
+	rate := d.Rate()
+	switch {
+	case d.Answered < t.minAnswered(), rate < slowdown:
+		return base
+	case rate >= AbandonThreshold:
+		return 1
+	}
+	frac := (rate - slowdown) / (AbandonThreshold - slowdown)
+	return base - (base-1)*frac
+}
+
+// Report lists daily abandonment for [fromDay, toDay] (workspace-local dates),
+// flagging days at or over the threshold.
+func (t *AbandonmentTracker) Report(ctx context.Context, workspaceID, campaignID, fromDay, toDay string) ([]AbandonmentReportRow, error) {
+	from, err1 := time.Parse(dayLayout, fromDay)
+	to, err2 := time.Parse(dayLayout, toDay)
+	if workspaceID == "" || err1 != nil || err2 != nil || to.Before(from) || to.Sub(from) > maxReportDays*24*time.Hour {
+		return nil, ErrInvalidArgument
+	}
+	days, err := t.store.List(ctx, workspaceID, campaignID, fromDay, toDay)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]AbandonmentReportRow, 0, len(days))
+	for _, d := range days {
+		out = append(out, AbandonmentReportRow{
+			DailyAbandonment: d,
+			Rate:             d.Rate(),
+			OverThreshold:    d.Answered > 0 && d.Rate() >= AbandonThreshold,
+		})
+	}
+	return out, nil
+}
+
+// AbandonmentReportRow is a reported day.
+type AbandonmentReportRow struct {
+	DailyAbandonment
+	Rate          float64 `json:"rate"`
+	OverThreshold bool    `json:"over_threshold"`
+}
+
+const (
+	dayLayout     = "2006-01-02"
+	maxReportDays = 92
+)
+
+func (t *AbandonmentTracker) day(ctx context.Context, workspaceID string, at time.Time) (string, error) {
+	loc := time.UTC
+	if t.timezones != nil {
+		tz, err := t.timezones.WorkspaceTimezone(ctx, workspaceID)
+		if err != nil {
+			return "", err
+		}
+		if tz != "" {
+			if loc, err = time.LoadLocation(tz); err != nil {
+				return "", ErrInvalidArgument
+			}
+		}
+	}
+	return at.In(loc).Format(dayLayout), nil
+}
+
+func (t *AbandonmentTracker) minAnswered() int {
+	if t.MinAnswered > 0 {
+		return t.MinAnswered
+	}
+	return DefaultMinAnswered
+}
@@ -0,0 +1,88 @@
+package dialer
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+type stubTimezones map[string]string
+
+func (s stubTimezones) WorkspaceTimezone(ctx context.Context, workspaceID string) (string, error) {
+	return s[workspaceID], nil
+}
+
+func TestAnsweredCall_AbandonedAfterTwoSeconds(t *testing.T) {
+	at := time.Date(2025, 3, 1, 15, 0, 0, 0, time.UTC)
+	in2s, in3s := at.Add(2*time.Second), at.Add(3*time.Second)
+	cases := []struct {
+		connected *time.Time
+		want      bool
+	}{{&in2s, false}, {&in3s, true}, {nil, true}}
+	for _, tc := range cases {
+		if got := (AnsweredCall{AnsweredAt: at, ConnectedAt: tc.connected}).Abandoned(); got != tc.want {
+			t.Fatalf("connected=%v: expected abandoned=%v", tc.connected, tc.want)
+		}
+	}
+}
+
+func TestAbandonmentTracker_SlowsDownNearThreshold(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryAbandonmentStore()
+	tr := NewAbandonmentTracker(store, stubTimezones{"w": "America/New_York"})
+	now := time.Date(2025, 3, 2, 3, 0, 0, 0, time.UTC) // 2025-03-01 in New York
+	tr.clock = func() time.Time { return now }
+
+	record := func(n int, abandoned bool) {
+		for i := 0; i < n; i++ {
+			c := AnsweredCall{WorkspaceID: "w", CampaignID: "c", AnsweredAt: now}
+			if !abandoned {
+				connected := now.Add(time.Second)
+				c.ConnectedAt = &connected
+			}
+			if _, err := tr.RecordAnswered(ctx, c); err != nil {
+				t.Fatalf("record: %v", err)
+			}
+		}
+	}
+	ratio := func() float64 {
+		r, err := tr.DialRatio(ctx, "w", "c", 3)
+		if err != nil {
+			t.Fatalf("ratio: %v", err)
+		}
+		return r
+	}
+
+	record(40, false)
+	record(2, true) // 4.8% but below the minimum sample
+	if r := ratio(); r != 3 {
+		t.Fatalf("expected base ratio on a small sample, got %v", r)
+	}
+	record(58, false) // 2 / 100 = 2%: slowdown starts
+	if r := ratio(); r != 3 {
+		t.Fatalf("expected base ratio at 2%%, got %v", r)
+	}
+	record(1, true)
+	record(99, false) // 3 / 200 = 1.5%
+	record(2, true)   // 5 / 202 ≈ 2.48%
+	if r := ratio(); r >= 3 || r <= 1 {
+		t.Fatalf("expected slowed ratio between 1 and 3, got %v", r)
+	}
+	record(2, true) // 7 / 204 ≈ 3.4%
+	if r := ratio(); r != 1 {
+		t.Fatalf("expected one line per agent over the threshold, got %v", r)
+	}
+
+	rows, err := tr.Report(ctx, "w", "", "2025-03-01", "2025-03-01")
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("report: %+v err=%v", rows, err)
+	}
+	if rows[0].Answered != 204 || rows[0].Abandoned != 7 || !rows[0].OverThreshold || math.Abs(rows[0].Rate-7.0/204) > 1e-9 {
+		t.Fatalf("unexpected report row %+v", rows[0])
+	}
+	if _, err := tr.Report(ctx, "w", "", "2025-03-02", "2025-03-01"); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected invalid range, got %v", err)
+	}
+}
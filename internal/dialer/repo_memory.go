@@ -0,0 +1,62 @@
+package dialer
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryAbandonmentStore is a simple in-memory AbandonmentStore for tests.
+// It is not intended for production use.
+type MemoryAbandonmentStore struct {
+	mu   sync.Mutex
+	days map[string]DailyAbandonment
+}
+
+func NewMemoryAbandonmentStore() *MemoryAbandonmentStore {
+	return &MemoryAbandonmentStore{days: map[string]DailyAbandonment{}}
+}
+
+func (s *MemoryAbandonmentStore) Increment(ctx context.Context, workspaceID, campaignID, day string, abandoned bool) (DailyAbandonment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := workspaceID + "|" + campaignID + "|" + day
+	d, ok := s.days[k]
+	if !ok {
+		d = DailyAbandonment{WorkspaceID: workspaceID, CampaignID: campaignID, Day: day}
+	}
+	d.Answered++
+	if abandoned {
+		d.Abandoned++
+	}
+	s.days[k] = d
+	return d, nil
+}
+
+func (s *MemoryAbandonmentStore) Get(ctx context.Context, workspaceID, campaignID, day string) (DailyAbandonment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d, ok := s.days[workspaceID+"|"+campaignID+"|"+day]; ok {
+		return d, nil
+	}
+	return DailyAbandonment{WorkspaceID: workspaceID, CampaignID: campaignID, Day: day}, nil
+}
+
+func (s *MemoryAbandonmentStore) List(ctx context.Context, workspaceID, campaignID, fromDay, toDay string) ([]DailyAbandonment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []DailyAbandonment
+	for _, d := range s.days {
+		if d.WorkspaceID != workspaceID || (campaignID != "" && d.CampaignID != campaignID) || d.Day < fromDay || d.Day > toDay {
+			continue
+		}
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Day != out[j].Day {
+			return out[i].Day < out[j].Day
+		}
+		return out[i].CampaignID < out[j].CampaignID
+	})
+	return out, nil
+}
@@ -0,0 +1,221 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"telecom-platform/pkg/logger"
+)
+
+// Call event bus over Redis Streams.
+//
+// Delivery model:
+// - At-least-once. Handlers must be idempotent (key on Envelope.ID).
+// - Each worker concern (billing, notifications, analytics) is its own consumer group,
+//   so each sees every event independently.
+// - An entry is acked only after its handler succeeds; failures stay in the group's
+//   pending list and are reclaimed by any consumer after ClaimMinIdle (crash recovery).
+// - Entries with a schema version newer than this build are copied to ParkStream
+//   and acked, so they neither block the group nor get half-processed; a newer
+//   worker replays the parked stream.
+
+// DefaultStream is the stream carrying call lifecycle events.
+const DefaultStream = "events:calls"
+
+// Message is a raw stream entry.
+type Message struct {
+	ID     string
+	Values map[string]any
+}
+
+// StreamClient is the subset of Redis Streams used by the bus.
+// RedisStreams implements it over go-redis; MemoryStreams is for tests.
+type StreamClient interface {
+	Add(ctx context.Context, stream string, maxLen int64, values map[string]any) (string, error)
+	EnsureGroup(ctx context.Context, stream, group, start string) error
+	ReadGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]Message, error)
+	ClaimStale(ctx context.Context, stream, group, consumer string, minIdle time.Duration, count int64) ([]Message, error)
+	Ack(ctx context.Context, stream, group string, ids ...string) error
+	Range(ctx context.Context, stream, fromID string, count int64) ([]Message, error)
+	SetGroupOffset(ctx context.Context, stream, group, id string) error
+}
+
+// Publisher publishes envelopes to the bus.
+type Publisher interface {
+	Publish(ctx context.Context, e Envelope) (string, error)
+}
+
+// StreamPublisher publishes to a Redis stream, trimming it to roughly MaxLen entries.
+type StreamPublisher struct {
+	Client StreamClient
+	Stream string
+	MaxLen int64
+}
+
+func (p StreamPublisher) Publish(ctx context.Context, e Envelope) (string, error) {
+	if e.ID == "" || e.WorkspaceID == "" || e.Type == "" {
+		return "", ErrInvalidEvent
+	}
+	if e.SchemaVersion == 0 {
+		e.SchemaVersion = SchemaVersion
+	}
+	fields, err := encodeFields(e)
+	if err != nil {
+		return "", err
+	}
+	stream := p.Stream
+	if stream == "" {
+		stream = DefaultStream
+	}
+	return p.Client.Add(ctx, stream, p.MaxLen, fields)
+}
+
+// Handler processes one event. Returning an error leaves the entry pending.
+type Handler func(ctx context.Context, e Envelope) error
+
+// Consumer reads a stream as one member of a consumer group.
+type Consumer struct {
+	Client  StreamClient
+	Stream  string
+	Group   string
+	Name    string
+	Handler Handler
+
+	BatchSize    int64
+	Block        time.Duration
+	ClaimMinIdle time.Duration
+
+	// ParkStream receives entries too new for this build; empty means
+	// "<stream>:parked".
+	ParkStream string
+}
+
+// Start creates the group if needed. start is "$" for new events only or "0" for the full stream.
+func (c Consumer) Start(ctx context.Context, start string) error {
+	if c.Client == nil || c.Group == "" || c.Name == "" || c.Handler == nil {
+		return errors.New("events: consumer not configured")
+	}
+	if start == "" {
+		start = "$"
+	}
+	return c.Client.EnsureGroup(ctx, c.stream(), c.Group, start)
+}
+
+// Run polls until ctx is canceled: reclaim stale pending entries, then read new ones.
+func (c Consumer) Run(ctx context.Context) {
+	log := logger.From(ctx).With("stream", c.stream(), "group", c.Group, "consumer", c.Name)
+	for ctx.Err() == nil {
+		if _, err := c.Poll(ctx); err != nil && ctx.Err() == nil {
+			log.Error("event poll failed", "err", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+// Poll runs one reclaim + read cycle and returns the number of entries acked.
+func (c Consumer) Poll(ctx context.Context) (int, error) {
+	batch := c.BatchSize
+	if batch <= 0 {
+		batch = 50
+	}
+	minIdle := c.ClaimMinIdle
+	if minIdle <= 0 {
+		minIdle = time.Minute
+	}
+
+	acked := 0
+	stale, err := c.Client.ClaimStale(ctx, c.stream(), c.Group, c.Name, minIdle, batch)
+	if err != nil {
+		return 0, err
+	}
+	n, err := c.handle(ctx, stale)
+	acked += n
+	if err != nil {
+		return acked, err
+	}
+
+	fresh, err := c.Client.ReadGroup(ctx, c.stream(), c.Group, c.Name, batch, c.Block)
+	if err != nil {
+		return acked, err
+	}
+	n, err = c.handle(ctx, fresh)
+	return acked + n, err
+}
+
+func (c Consumer) handle(ctx context.Context, msgs []Message) (int, error) {
+	log := logger.From(ctx)
+	acked := 0
+	for _, m := range msgs {
+		e, err := decodeMessage(m)
+		switch {
+		case errors.Is(err, ErrUnsupportedVersion):
+			// Park for a newer worker build rather than redeliver it forever.
+			if _, perr := c.Client.Add(ctx, c.parkStream(), 0, m.Values); perr != nil {
+				return acked, perr
+			}
+			log.Warn("event schema too new; parked", "stream_id", m.ID, "park_stream", c.parkStream())
+		case err != nil:
+			// Poison entry: ack so it does not block the group forever.
+			log.Error("undecodable event acked and dropped", "stream_id", m.ID, "err", err)
+		default:
+			if herr := c.Handler(ctx, e); herr != nil {
+				log.Warn("event handler failed; will retry", "stream_id", m.ID, "type", e.Type, "err", herr)
+				continue
+			}
+		}
+		if err := c.Client.Ack(ctx, c.stream(), c.Group, m.ID); err != nil {
+			return acked, err
+		}
+		acked++
+	}
+	return acked, nil
+}
+
+// ResetOffset moves the group's last-delivered id so the next reads replay from id.
+// Use "0" to replay the whole retained stream.
+func (c Consumer) ResetOffset(ctx context.Context, id string) error {
+	return c.Client.SetGroupOffset(ctx, c.stream(), c.Group, id)
+}
+
+// Replay feeds entries after fromID (exclusive) to h without touching any group state.
+// Useful for rebuilding projections. Returns the last id processed.
+func Replay(ctx context.Context, client StreamClient, stream, fromID string, h Handler) (string, error) {
+	last := fromID
+	for {
+		msgs, err := client.Range(ctx, stream, "("+last, 500)
+		if err != nil {
+			return last, err
+		}
+		if len(msgs) == 0 {
+			return last, nil
+		}
+		for _, m := range msgs {
+			e, err := decodeMessage(m)
+			if err != nil {
+				return last, err
+			}
+			if err := h(ctx, e); err != nil {
+				return last, err
+			}
+			last = m.ID
+		}
+	}
+}
+
+func (c Consumer) stream() string {
+	if c.Stream == "" {
+		return DefaultStream
+	}
+	return c.Stream
+}
+
+func (c Consumer) parkStream() string {
+	if c.ParkStream == "" {
+		return c.stream() + ":parked"
+	}
+	return c.ParkStream
+}
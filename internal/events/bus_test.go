@@ -0,0 +1,116 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConsumer_RetriesFailedEntryViaClaim(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	streams := NewMemoryStreams()
+	streams.Now = func() time.Time { return now }
+
+	pub := StreamPublisher{Client: streams}
+	billing := Consumer{Client: streams, Group: "billing", Name: "w1", ClaimMinIdle: time.Minute}
+	fail := true
+	var seen []string
+	billing.Handler = func(ctx context.Context, e Envelope) error {
+		if fail {
+			return errors.New("db down")
+		}
+		p, _ := e.CallPayload()
+		seen = append(seen, p.ProviderCallID)
+		return nil
+	}
+	if err := billing.Start(ctx, "$"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	e, _ := NewCallEvent("e1", TypeCallCompleted, "w", now, CallPayload{ProviderCallID: "CA1", Status: "completed", DurationSeconds: 42})
+	if _, err := pub.Publish(ctx, e); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	if n, _ := billing.Poll(ctx); n != 0 || streams.Pending(DefaultStream, "billing") != 1 {
+		t.Fatalf("expected entry left pending after failure")
+	}
+
+	// A different consumer reclaims after the idle threshold (crash recovery).
+	fail = false
+	now = now.Add(2 * time.Minute)
+	billing.Name = "w2"
+	if n, err := billing.Poll(ctx); err != nil || n != 1 {
+		t.Fatalf("expected reclaimed entry acked, got %d (%v)", n, err)
+	}
+	if len(seen) != 1 || seen[0] != "CA1" || streams.Pending(DefaultStream, "billing") != 0 {
+		t.Fatalf("unexpected state seen=%v pending=%d", seen, streams.Pending(DefaultStream, "billing"))
+	}
+}
+
+func TestConsumer_GroupsAreIndependentAndReplay(t *testing.T) {
+	ctx := context.Background()
+	streams := NewMemoryStreams()
+	pub := StreamPublisher{Client: streams}
+
+	count := map[string]int{}
+	for _, g := range []string{"notifications", "analytics"} {
+		g := g
+		c := Consumer{Client: streams, Group: g, Name: "w1", Handler: func(ctx context.Context, e Envelope) error { count[g]++; return nil }}
+		_ = c.Start(ctx, "0")
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		e, _ := NewCallEvent(id, TypeCallInbound, "w", time.Now(), CallPayload{ProviderCallID: id})
+		_, _ = pub.Publish(ctx, e)
+	}
+	for _, g := range []string{"notifications", "analytics"} {
+		g := g
+		c := Consumer{Client: streams, Group: g, Name: "w1", Handler: func(ctx context.Context, e Envelope) error { count[g]++; return nil }}
+		if _, err := c.Poll(ctx); err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+	}
+	if count["notifications"] != 3 || count["analytics"] != 3 {
+		t.Fatalf("expected each group to see every event, got %v", count)
+	}
+
+	var replayed []string
+	last, err := Replay(ctx, streams, DefaultStream, "1-0", func(ctx context.Context, e Envelope) error {
+		replayed = append(replayed, e.ID)
+		return nil
+	})
+	if err != nil || len(replayed) != 2 || replayed[0] != "b" || last != "3-0" {
+		t.Fatalf("unexpected replay %v last=%s (%v)", replayed, last, err)
+	}
+}
+
+func TestDecode_RejectsNewerSchema(t *testing.T) {
+	_, err := decodeMessage(Message{ID: "1-0", Values: map[string]any{"v": "99", "data": "{}"}})
+	if !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("expected ErrUnsupportedVersion, got %v", err)
+	}
+}
+
+func TestConsumer_ParksNewerSchema(t *testing.T) {
+	ctx := context.Background()
+	streams := NewMemoryStreams()
+	handled := 0
+	c := Consumer{Client: streams, Group: "billing", Name: "w1", Handler: func(ctx context.Context, e Envelope) error { handled++; return nil }}
+	if err := c.Start(ctx, "0"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	_, _ = streams.Add(ctx, DefaultStream, 0, map[string]any{"v": "99", "type": "call.future", "workspace_id": "w", "data": "{}"})
+
+	if n, err := c.Poll(ctx); err != nil || n != 1 || handled != 0 {
+		t.Fatalf("expected entry acked without handling, got n=%d handled=%d err=%v", n, handled, err)
+	}
+	if p := streams.Pending(DefaultStream, "billing"); p != 0 {
+		t.Fatalf("expected nothing pending, got %d", p)
+	}
+	parked, err := streams.Range(ctx, DefaultStream+":parked", "0", 10)
+	if err != nil || len(parked) != 1 || parked[0].Values["v"] != "99" {
+		t.Fatalf("expected entry parked, got %+v err=%v", parked, err)
+	}
+}
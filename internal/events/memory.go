@@ -0,0 +1,185 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStreams is an in-memory StreamClient for tests. It models entries,
+// consumer groups, last-delivered offsets and pending entries; it does not block.
+// It is not intended for production use.
+type MemoryStreams struct {
+	mu      sync.Mutex
+	Now     func() time.Time
+	streams map[string]*memStream
+}
+
+type memStream struct {
+	entries []Message
+	seq     int64
+	groups  map[string]*memGroup
+}
+
+type memGroup struct {
+	lastDelivered int64
+	pending       map[string]memPending
+}
+
+type memPending struct {
+	consumer    string
+	deliveredAt time.Time
+}
+
+func NewMemoryStreams() *MemoryStreams {
+	return &MemoryStreams{Now: time.Now, streams: map[string]*memStream{}}
+}
+
+func (m *MemoryStreams) stream(name string) *memStream {
+	s, ok := m.streams[name]
+	if !ok {
+		s = &memStream{groups: map[string]*memGroup{}}
+		m.streams[name] = s
+	}
+	return s
+}
+
+func (m *MemoryStreams) Add(ctx context.Context, stream string, maxLen int64, values map[string]any) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.stream(stream)
+	s.seq++
+	id := strconv.FormatInt(s.seq, 10) + "-0"
+	s.entries = append(s.entries, Message{ID: id, Values: values})
+	if maxLen > 0 && int64(len(s.entries)) > maxLen {
+		s.entries = s.entries[int64(len(s.entries))-maxLen:]
+	}
+	return id, nil
+}
+
+func (m *MemoryStreams) EnsureGroup(ctx context.Context, stream, group, start string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.stream(stream)
+	if _, ok := s.groups[group]; ok {
+		return nil
+	}
+	last := int64(0)
+	if start == "$" {
+		last = s.seq
+	} else if start != "" {
+		last = seqOf(start)
+	}
+	s.groups[group] = &memGroup{lastDelivered: last, pending: map[string]memPending{}}
+	return nil
+}
+
+func (m *MemoryStreams) ReadGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.stream(stream)
+	g, ok := s.groups[group]
+	if !ok {
+		return nil, errors.New("NOGROUP")
+	}
+	out := make([]Message, 0)
+	for _, e := range s.entries {
+		if int64(len(out)) >= count {
+			break
+		}
+		if seqOf(e.ID) <= g.lastDelivered {
+			continue
+		}
+		g.lastDelivered = seqOf(e.ID)
+		g.pending[e.ID] = memPending{consumer: consumer, deliveredAt: m.Now()}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (m *MemoryStreams) ClaimStale(ctx context.Context, stream, group, consumer string, minIdle time.Duration, count int64) ([]Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.stream(stream)
+	g, ok := s.groups[group]
+	if !ok {
+		return nil, errors.New("NOGROUP")
+	}
+	now := m.Now()
+	out := make([]Message, 0)
+	for _, e := range s.entries {
+		if int64(len(out)) >= count {
+			break
+		}
+		p, ok := g.pending[e.ID]
+		if !ok || now.Sub(p.deliveredAt) < minIdle {
+			continue
+		}
+		g.pending[e.ID] = memPending{consumer: consumer, deliveredAt: now}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (m *MemoryStreams) Ack(ctx context.Context, stream, group string, ids ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g, ok := m.stream(stream).groups[group]
+	if !ok {
+		return errors.New("NOGROUP")
+	}
+	for _, id := range ids {
+		delete(g.pending, id)
+	}
+	return nil
+}
+
+// Pending returns the number of unacked entries for a group.
+func (m *MemoryStreams) Pending(stream, group string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g, ok := m.stream(stream).groups[group]
+	if !ok {
+		return 0
+	}
+	return len(g.pending)
+}
+
+func (m *MemoryStreams) Range(ctx context.Context, stream, fromID string, count int64) ([]Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	exclusive := strings.HasPrefix(fromID, "(")
+	from := seqOf(strings.TrimPrefix(fromID, "("))
+	out := make([]Message, 0)
+	for _, e := range m.stream(stream).entries {
+		if int64(len(out)) >= count {
+			break
+		}
+		n := seqOf(e.ID)
+		if n < from || (exclusive && n == from) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (m *MemoryStreams) SetGroupOffset(ctx context.Context, stream, group, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g, ok := m.stream(stream).groups[group]
+	if !ok {
+		return errors.New("NOGROUP")
+	}
+	g.lastDelivered = seqOf(id)
+	return nil
+}
+
+func seqOf(id string) int64 {
+	ms, _, _ := strings.Cut(id, "-")
+	n, _ := strconv.ParseInt(ms, 10, 64)
+	return n
+}
@@ -0,0 +1,109 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStreams implements StreamClient with go-redis.
+type RedisStreams struct {
+	Client *redis.Client
+}
+
+func (r RedisStreams) Add(ctx context.Context, stream string, maxLen int64, values map[string]any) (string, error) {
+	args := &redis.XAddArgs{Stream: stream, Values: values}
+	if maxLen > 0 {
+		args.MaxLen = maxLen
+		args.Approx = true
+	}
+	return r.Client.XAdd(ctx, args).Result()
+}
+
+func (r RedisStreams) EnsureGroup(ctx context.Context, stream, group, start string) error {
+	err := r.Client.XGroupCreateMkStream(ctx, stream, group, start).Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+func (r RedisStreams) ReadGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]Message, error) {
+	if block <= 0 {
+		block = 5 * time.Second
+	}
+	res, err := r.Client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Message, 0)
+	for _, s := range res {
+		out = append(out, toMessages(s.Messages)...)
+	}
+	return out, nil
+}
+
+// ClaimStale follows the XAUTOCLAIM cursor through the whole pending list, so
+// stale entries behind count recently delivered ones are still reached, and
+// stops once count entries are claimed or the cursor wraps to "0-0".
+func (r RedisStreams) ClaimStale(ctx context.Context, stream, group, consumer string, minIdle time.Duration, count int64) ([]Message, error) {
+	out := make([]Message, 0)
+	cursor := "0-0"
+	for {
+		msgs, next, err := r.Client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    group,
+			Consumer: consumer,
+			MinIdle:  minIdle,
+			Start:    cursor,
+			Count:    count - int64(len(out)),
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			return out, nil
+		}
+		if err != nil {
+			return out, err
+		}
+		out = append(out, toMessages(msgs)...)
+		if next == "0-0" || next == "" || int64(len(out)) >= count {
+			return out, nil
+		}
+		cursor = next
+	}
+}
+
+func (r RedisStreams) Ack(ctx context.Context, stream, group string, ids ...string) error {
+	return r.Client.XAck(ctx, stream, group, ids...).Err()
+}
+
+func (r RedisStreams) Range(ctx context.Context, stream, fromID string, count int64) ([]Message, error) {
+	msgs, err := r.Client.XRangeN(ctx, stream, fromID, "+", count).Result()
+	if err != nil {
+		return nil, err
+	}
+	return toMessages(msgs), nil
+}
+
+func (r RedisStreams) SetGroupOffset(ctx context.Context, stream, group, id string) error {
+	return r.Client.XGroupSetID(ctx, stream, group, id).Err()
+}
+
+func toMessages(in []redis.XMessage) []Message {
+	out := make([]Message, 0, len(in))
+	for _, m := range in {
+		out = append(out, Message{ID: m.ID, Values: m.Values})
+	}
+	return out
+}
@@ -0,0 +1,117 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Internal event schema shared by the api (publisher) and worker (consumers).
+//
+// Versioning rules:
+// - SchemaVersion is bumped only for breaking payload changes.
+// - Consumers must reject versions newer than they understand (ErrUnsupportedVersion)
+//   and park them (Consumer.ParkStream), so an older worker never half-processes a newer event.
+// - Adding optional payload fields is not a breaking change.
+
+// SchemaVersion is the current envelope/payload version produced by this build.
+const SchemaVersion = 1
+
+type Type string
+
+const (
+	TypeCallInbound   Type = "call.inbound"
+	TypeCallRinging   Type = "call.ringing"
+	TypeCallAnswered  Type = "call.answered"
+	TypeCallCompleted Type = "call.completed"
+	TypeCallFailed    Type = "call.failed"
+)
+
+// Envelope wraps every event on the bus.
+type Envelope struct {
+	SchemaVersion int       `json:"v"`
+	ID            string    `json:"id"`
+	Type          Type      `json:"type"`
+	WorkspaceID   string    `json:"workspace_id"`
+	OccurredAt    time.Time `json:"occurred_at"`
+
+	Payload json.RawMessage `json:"payload"`
+
+	// StreamID is the Redis Streams entry id; set on consume, never published.
+	StreamID string `json:"-"`
+}
+
+// CallPayload is the payload for all call.* events.
+type CallPayload struct {
+	CallID         string `json:"call_id,omitempty"`
+	ProviderCallID string `json:"provider_call_id"`
+	Provider       string `json:"provider"`
+	CampaignID     string `json:"campaign_id,omitempty"`
+
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	Status          string `json:"status"`
+	DurationSeconds int    `json:"duration_seconds,omitempty"`
+	FailureReason   string `json:"failure_reason,omitempty"`
+}
+
+var (
+	ErrInvalidEvent       = errors.New("events: invalid event")
+	ErrUnsupportedVersion = errors.New("events: unsupported schema version")
+)
+
+// NewCallEvent builds an envelope for a call lifecycle event.
+func NewCallEvent(id string, t Type, workspaceID string, occurredAt time.Time, p CallPayload) (Envelope, error) {
+	if id == "" || workspaceID == "" || t == "" {
+		return Envelope{}, ErrInvalidEvent
+	}
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return Envelope{}, err
+	}
+	return Envelope{SchemaVersion: SchemaVersion, ID: id, Type: t, WorkspaceID: workspaceID, OccurredAt: occurredAt.UTC(), Payload: raw}, nil
+}
+
+// CallPayload decodes the payload of a call.* event.
+func (e Envelope) CallPayload() (CallPayload, error) {
+	var p CallPayload
+	if err := json.Unmarshal(e.Payload, &p); err != nil {
+		return CallPayload{}, ErrInvalidEvent
+	}
+	return p, nil
+}
+
+// Stream fields. "type" and "workspace_id" are duplicated outside the JSON body so
+// operators can inspect streams (XRANGE) without decoding.
+func encodeFields(e Envelope) (map[string]any, error) {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"v":            strconv.Itoa(e.SchemaVersion),
+		"type":         string(e.Type),
+		"workspace_id": e.WorkspaceID,
+		"data":         string(body),
+	}, nil
+}
+
+func decodeMessage(m Message) (Envelope, error) {
+	v, _ := m.Values["v"].(string)
+	ver, err := strconv.Atoi(v)
+	if err != nil {
+		return Envelope{}, ErrInvalidEvent
+	}
+	if ver > SchemaVersion {
+		return Envelope{}, ErrUnsupportedVersion
+	}
+	data, _ := m.Values["data"].(string)
+	var e Envelope
+	if err := json.Unmarshal([]byte(data), &e); err != nil {
+		return Envelope{}, ErrInvalidEvent
+	}
+	e.StreamID = m.ID
+	return e, nil
+}
@@ -0,0 +1,55 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Wallet domain events. The wallet service writes them to its outbox in the same
+// transaction as the ledger entry; wallet.OutboxRelay publishes them to
+// WalletStream. The envelope ID is the ledger entry ID, so consumers can dedupe
+// redeliveries on it.
+
+// WalletStream is the stream carrying wallet events.
+const WalletStream = "events:wallet"
+
+const (
+	TypeWalletCredited   Type = "wallet.credited"
+	TypeWalletDebited    Type = "wallet.debited"
+	TypeWalletHoldPlaced Type = "wallet.hold_placed"
+)
+
+// WalletPayload is the payload for all wallet.* events. Amounts are positive;
+// the balances are the wallet's after the entry.
+type WalletPayload struct {
+	WalletID    string `json:"wallet_id"`
+	LedgerID    string `json:"ledger_id"`
+	HoldID      string `json:"hold_id,omitempty"`
+	AmountMinor int64  `json:"amount_minor"`
+	Currency    string `json:"currency"`
+	ExternalRef string `json:"external_ref,omitempty"`
+
+	BalanceMinor int64 `json:"balance_minor"`
+	HeldMinor    int64 `json:"held_minor"`
+}
+
+// NewWalletEvent builds an envelope for a wallet event.
+func NewWalletEvent(id string, t Type, workspaceID string, occurredAt time.Time, p WalletPayload) (Envelope, error) {
+	if id == "" || workspaceID == "" || t == "" || p.WalletID == "" {
+		return Envelope{}, ErrInvalidEvent
+	}
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return Envelope{}, err
+	}
+	return Envelope{SchemaVersion: SchemaVersion, ID: id, Type: t, WorkspaceID: workspaceID, OccurredAt: occurredAt.UTC(), Payload: raw}, nil
+}
+
+// WalletPayload decodes the payload of a wallet.* event.
+func (e Envelope) WalletPayload() (WalletPayload, error) {
+	var p WalletPayload
+	if err := json.Unmarshal(e.Payload, &p); err != nil {
+		return WalletPayload{}, ErrInvalidEvent
+	}
+	return p, nil
+}
@@ -0,0 +1,40 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/dialer"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Dialer abandonment compliance ---
+
+// AbandonmentReport returns each campaign's daily abandonment rate (answered
+// calls not connected to an agent within 2 seconds), flagging days at or over
+// the 3% threshold. RBAC: owner/analyst/finance/super_admin.
+//
+// Query: from_date, to_date (YYYY-MM-DD, workspace-local, max 92 days); campaign_id (optional).
+func (h Handlers) AbandonmentReport(c *gin.Context) {
+	if h.Abandonment == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "abandonment tracking not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	rows, err := h.Abandonment.Report(c.Request.Context(), workspaceID, c.Query("campaign_id"), c.Query("from_date"), c.Query("to_date"))
+	if err != nil {
+		if errors.Is(err, dialer.ErrInvalidArgument) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "from_date and to_date must be YYYY-MM-DD, in order, at most 92 days apart"})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "report failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"threshold": dialer.AbandonThreshold, "days": rows})
+}
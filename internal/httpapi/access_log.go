@@ -0,0 +1,25 @@
+package httpapi
+
+import (
+	"telecom-platform/internal/audit"
+	"telecom-platform/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Read-access audit ---
+
+// logAccess records a read of sensitive data for the authenticated caller. It is
+// best-effort and a no-op unless an AccessLogger with a policy for the resource is wired.
+func (h Handlers) logAccess(c *gin.Context, a audit.Access) {
+	if h.AccessLog == nil {
+		return
+	}
+	ctx := c.Request.Context()
+	if a.WorkspaceID == "" {
+		a.WorkspaceID, _ = auth.WorkspaceID(ctx)
+	}
+	a.ActorUserID, _ = auth.UserID(ctx)
+	a.ActorRole, _ = auth.Role(ctx)
+	_, _ = h.AccessLog.LogAccess(ctx, a)
+}
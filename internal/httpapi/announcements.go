@@ -0,0 +1,106 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/announcements"
+	"telecom-platform/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- System announcements ---
+
+// ActiveAnnouncements is the public, unauthenticated endpoint dashboards poll.
+func (h Handlers) ActiveAnnouncements(c *gin.Context) {
+	if h.Announcements == nil {
+		c.JSON(http.StatusOK, gin.H{"announcements": []announcements.Announcement{}})
+		return
+	}
+	items, err := h.Announcements.Active(c.Request.Context())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "announcements lookup failed"})
+		return
+	}
+	// Short cache keeps polling dashboards cheap.
+	c.Header("Cache-Control", "public, max-age=30")
+	c.JSON(http.StatusOK, gin.H{"announcements": items})
+}
+
+// ListAnnouncements returns all announcements. RBAC: super_admin.
+func (h Handlers) ListAnnouncements(c *gin.Context) {
+	if h.Announcements == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "announcements not configured"})
+		return
+	}
+	items, err := h.Announcements.List(c.Request.Context())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "announcements lookup failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"announcements": items})
+}
+
+// CreateAnnouncement creates an announcement. RBAC: super_admin.
+func (h Handlers) CreateAnnouncement(c *gin.Context) {
+	if h.Announcements == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "announcements not configured"})
+		return
+	}
+	var req announcements.UpsertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	uid, _ := auth.UserID(c.Request.Context())
+	a, err := h.Announcements.Create(c.Request.Context(), uid, req)
+	if err != nil {
+		writeAnnouncementError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, a)
+}
+
+// UpdateAnnouncement replaces an announcement. RBAC: super_admin.
+func (h Handlers) UpdateAnnouncement(c *gin.Context) {
+	if h.Announcements == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "announcements not configured"})
+		return
+	}
+	var req announcements.UpsertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	a, err := h.Announcements.Update(c.Request.Context(), c.Param("announcement_id"), req)
+	if err != nil {
+		writeAnnouncementError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, a)
+}
+
+// DeleteAnnouncement removes an announcement. RBAC: super_admin.
+func (h Handlers) DeleteAnnouncement(c *gin.Context) {
+	if h.Announcements == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "announcements not configured"})
+		return
+	}
+	if err := h.Announcements.Delete(c.Request.Context(), c.Param("announcement_id")); err != nil {
+		writeAnnouncementError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func writeAnnouncementError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, announcements.ErrInvalidArgument):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, announcements.ErrNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "announcement not found"})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "announcement update failed"})
+	}
+}
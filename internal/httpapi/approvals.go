@@ -0,0 +1,95 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/approvals"
+	"telecom-platform/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Approvals (four-eyes) ---
+
+type approvalDecisionRequest struct {
+	Note string `json:"note"`
+}
+
+// ListPendingApprovals returns the workspace approval queue. RBAC: owner/super_admin.
+func (h Handlers) ListPendingApprovals(c *gin.Context) {
+	if h.Approvals == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "approvals not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	items, err := h.Approvals.ListPending(c.Request.Context(), workspaceID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "approvals lookup failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"approvals": items})
+}
+
+// ApproveRequest approves and executes a pending action. RBAC: owner/super_admin, not the requester.
+func (h Handlers) ApproveRequest(c *gin.Context) {
+	h.decideApproval(c, true)
+}
+
+// RejectRequest rejects a pending action. RBAC: owner/super_admin, not the requester.
+func (h Handlers) RejectRequest(c *gin.Context) {
+	h.decideApproval(c, false)
+}
+
+func (h Handlers) decideApproval(c *gin.Context, approve bool) {
+	if h.Approvals == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "approvals not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	uid, _ := auth.UserID(c.Request.Context())
+	role, _ := auth.Role(c.Request.Context())
+
+	var req approvalDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			abortBodyError(c, err, "invalid json")
+			return
+		}
+	}
+
+	var out approvals.Request
+	if approve {
+		out, err = h.Approvals.Approve(c.Request.Context(), workspaceID, c.Param("approval_id"), uid, role, req.Note)
+	} else {
+		out, err = h.Approvals.Reject(c.Request.Context(), workspaceID, c.Param("approval_id"), uid, role, req.Note)
+	}
+	if err != nil {
+		writeApprovalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"approval": out})
+}
+
+func writeApprovalError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, approvals.ErrNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "approval not found"})
+	case errors.Is(err, approvals.ErrSelfApproval):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "a different admin must decide this request"})
+	case errors.Is(err, approvals.ErrNotPending), errors.Is(err, approvals.ErrExpired):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, approvals.ErrInvalidArgument):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "approval failed"})
+	}
+}
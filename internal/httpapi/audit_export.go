@@ -0,0 +1,83 @@
+package httpapi
+
+import (
+	"net/http"
+	"time"
+
+	"telecom-platform/internal/audit"
+	"telecom-platform/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Audit export (tenant SIEM) ---
+
+type auditExportRequest struct {
+	Enabled     bool              `json:"enabled"`
+	Sink        audit.SinkKind    `json:"sink"`
+	EndpointURL string            `json:"endpoint_url"`
+	Secret      string            `json:"secret"`
+	Bucket      string            `json:"bucket"`
+	Prefix      string            `json:"prefix"`
+	EventTypes  []audit.EventType `json:"event_types"`
+}
+
+// GetAuditExport returns the workspace SIEM export config. RBAC: owner/super_admin.
+func (h Handlers) GetAuditExport(c *gin.Context) {
+	if h.AuditExport == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "audit export not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	cfg, ok, err := h.AuditExport.GetExportConfig(c.Request.Context(), workspaceID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "audit export lookup failed"})
+		return
+	}
+	if !ok {
+		cfg = audit.ExportConfig{WorkspaceID: workspaceID}
+	}
+	c.JSON(http.StatusOK, gin.H{"config": cfg, "schema_version": audit.ExportSchemaVersion})
+}
+
+// SetAuditExport opts the workspace in or out of SIEM export. RBAC: owner/super_admin.
+func (h Handlers) SetAuditExport(c *gin.Context) {
+	if h.AuditExport == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "audit export not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	var req auditExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	cfg := audit.ExportConfig{
+		WorkspaceID: workspaceID,
+		Enabled:     req.Enabled,
+		Sink:        req.Sink,
+		EndpointURL: req.EndpointURL,
+		Secret:      req.Secret,
+		Bucket:      req.Bucket,
+		Prefix:      req.Prefix,
+		EventTypes:  req.EventTypes,
+		UpdatedAt:   time.Now().UTC(),
+	}
+	if err := audit.ValidateExportConfig(cfg); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid export config (https endpoint and secret, or bucket, required)"})
+		return
+	}
+	if err := h.AuditExport.SetExportConfig(c.Request.Context(), cfg); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "audit export update failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"config": cfg})
+}
@@ -0,0 +1,323 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"telecom-platform/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Batch ---
+//
+// POST /v1/batch runs up to MaxItems sub-requests against the regular /v1 routes,
+// so every item passes the same auth, RBAC and validation as a direct call. Items
+// run with bounded concurrency and each gets its own status; a failing item never
+// fails the batch. There is no cross-item transaction and no ordering guarantee:
+// dashboards use it for independent bulk edits (e.g. toggling 50 destinations).
+//
+// Items with an idempotency_key are remembered per workspace and user for ResultTTL,
+// so a retried batch replays finished items instead of executing them again. The key
+// is reserved before the item runs: a concurrent retry gets 409 instead of running
+// it twice. 5xx results are not remembered; retrying those is the point of a retry.
+// GET items take no key (reads are safe to repeat and must not replay stale data).
+
+const (
+	DefaultBatchMaxItems    = 50
+	DefaultBatchConcurrency = 8
+	DefaultBatchResultTTL   = 24 * time.Hour
+	// DefaultBatchPendingTTL bounds how long a reserved key blocks retries if the
+	// instance running the item dies before storing its result.
+	DefaultBatchPendingTTL = 5 * time.Minute
+)
+
+// batchForwardHeaders are copied from the batch request to every item so identity,
+// client IP and tracing stay intact.
+var batchForwardHeaders = []string{"Authorization", "X-Forwarded-For", "X-Real-IP", "X-Request-ID", "User-Agent"}
+
+// batchItemHeaders are the only per-item headers callers may set.
+var batchItemHeaders = map[string]bool{"If-Match": true, "Idempotency-Key": true}
+
+// BatchResultStore remembers item results by idempotency key.
+// Implementations must scope keys by workspace; keys arrive already scoped by user.
+type BatchResultStore interface {
+	// ReserveBatchResult atomically stores a pending entry (Status 0) for key unless
+	// one exists. It returns true when the caller reserved the key, otherwise the
+	// existing entry (pending or finished).
+	ReserveBatchResult(ctx context.Context, workspaceID, key, fingerprint string, ttl time.Duration) (StoredBatchResult, bool, error)
+	// PutBatchResult replaces the reservation with the finished result.
+	PutBatchResult(ctx context.Context, workspaceID, key string, res StoredBatchResult, ttl time.Duration) error
+	// DeleteBatchResult drops a reservation whose result is not remembered.
+	DeleteBatchResult(ctx context.Context, workspaceID, key string) error
+}
+
+// StoredBatchResult is an item result plus the fingerprint of the request that produced it.
+// Status 0 marks a reservation whose item is still running.
+type StoredBatchResult struct {
+	Fingerprint string          `json:"fingerprint"`
+	Status      int             `json:"status"`
+	Body        json.RawMessage `json:"body,omitempty"`
+}
+
+// BatchRunner executes batch items. Handler is the router serving /v1 (the gin engine).
+type BatchRunner struct {
+	Handler     http.Handler
+	Results     BatchResultStore // optional; without it idempotency keys are ignored
+	MaxItems    int
+	Concurrency int
+	ResultTTL   time.Duration
+}
+
+type batchRequest struct {
+	Requests []batchItem `json:"requests"`
+}
+
+type batchItem struct {
+	ID             string            `json:"id"`
+	Method         string            `json:"method"`
+	Path           string            `json:"path"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Body           json.RawMessage   `json:"body,omitempty"`
+	IdempotencyKey string            `json:"idempotency_key,omitempty"`
+}
+
+type batchItemResult struct {
+	ID       string          `json:"id"`
+	Status   int             `json:"status"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	Replayed bool            `json:"replayed,omitempty"`
+}
+
+// RunBatch executes sub-requests and returns per-item results in request order.
+func (h Handlers) RunBatch(c *gin.Context) {
+	if h.Batch == nil || h.Batch.Handler == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "batch not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	// Service tokens carry no user; their keys share the workspace-level scope.
+	userID, _ := auth.UserID(c.Request.Context())
+	var req batchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	if len(req.Requests) == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "requests required"})
+		return
+	}
+	if max := h.Batch.maxItems(); len(req.Requests) > max {
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "too many batch items", "max_items": max})
+		return
+	}
+	ids := map[string]bool{}
+	keys := map[string]bool{}
+	for i, it := range req.Requests {
+		if msg := validateBatchItem(it); msg != "" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": msg, "index": i})
+			return
+		}
+		if ids[it.ID] {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "duplicate item id", "index": i})
+			return
+		}
+		ids[it.ID] = true
+		if it.IdempotencyKey != "" {
+			if it.Method == http.MethodGet {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "idempotency_key not allowed on GET items", "index": i})
+				return
+			}
+			if keys[it.IdempotencyKey] {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "duplicate idempotency_key", "index": i})
+				return
+			}
+			keys[it.IdempotencyKey] = true
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": h.Batch.run(c.Request, workspaceID, userID, req.Requests)})
+}
+
+func validateBatchItem(it batchItem) string {
+	if it.ID == "" {
+		return "item id required"
+	}
+	switch it.Method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return "unsupported item method"
+	}
+	if !strings.HasPrefix(it.Path, "/v1/") || strings.Contains(it.Path, "..") {
+		return "item path must be under /v1/"
+	}
+	if p := strings.SplitN(it.Path, "?", 2)[0]; strings.TrimSuffix(p, "/") == "/v1/batch" {
+		return "nested batches are not allowed"
+	}
+	for k := range it.Headers {
+		if !batchItemHeaders[http.CanonicalHeaderKey(k)] {
+			return "item header not allowed: " + k
+		}
+	}
+	return ""
+}
+
+func (b *BatchRunner) maxItems() int {
+	if b.MaxItems > 0 {
+		return b.MaxItems
+	}
+	return DefaultBatchMaxItems
+}
+
+func (b *BatchRunner) run(parent *http.Request, workspaceID, userID string, items []batchItem) []batchItemResult {
+	conc := b.Concurrency
+	if conc <= 0 {
+		conc = DefaultBatchConcurrency
+	}
+	out := make([]batchItemResult, len(items))
+	sem := make(chan struct{}, conc)
+	var wg sync.WaitGroup
+	for i := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			out[i] = b.runItem(parent, workspaceID, userID, items[i])
+		}(i)
+	}
+	wg.Wait()
+	return out
+}
+
+func (b *BatchRunner) runItem(parent *http.Request, workspaceID, userID string, it batchItem) batchItemResult {
+	ctx := parent.Context()
+	fp := batchFingerprint(it)
+	remember := b.Results != nil && it.IdempotencyKey != ""
+	key := userID + "|" + it.IdempotencyKey
+	if remember {
+		prev, reserved, err := b.Results.ReserveBatchResult(ctx, workspaceID, key, fp, DefaultBatchPendingTTL)
+		if err != nil {
+			return batchError(it.ID, http.StatusServiceUnavailable, "idempotency lookup failed")
+		}
+		if !reserved {
+			switch {
+			case prev.Fingerprint != fp:
+				return batchError(it.ID, http.StatusUnprocessableEntity, "idempotency_key reused with a different request")
+			case prev.Status == 0:
+				return batchError(it.ID, http.StatusConflict, "a request with this idempotency_key is in progress")
+			}
+			return batchItemResult{ID: it.ID, Status: prev.Status, Body: prev.Body, Replayed: true}
+		}
+	}
+
+	var body []byte
+	if len(it.Body) > 0 && string(it.Body) != "null" {
+		body = it.Body
+	}
+	sub, err := http.NewRequestWithContext(ctx, it.Method, it.Path, bytes.NewReader(body))
+	if err != nil {
+		return batchError(it.ID, http.StatusBadRequest, "invalid item request")
+	}
+	sub.RemoteAddr = parent.RemoteAddr
+	for _, k := range batchForwardHeaders {
+		if v := parent.Header.Get(k); v != "" {
+			sub.Header.Set(k, v)
+		}
+	}
+	for k, v := range it.Headers {
+		sub.Header.Set(k, v)
+	}
+	if it.IdempotencyKey != "" {
+		sub.Header.Set("Idempotency-Key", it.IdempotencyKey)
+	}
+	if body != nil {
+		sub.Header.Set("Content-Type", "application/json")
+	}
+
+	rec := newBatchRecorder()
+	b.Handler.ServeHTTP(rec, sub)
+
+	res := batchItemResult{ID: it.ID, Status: rec.status, Body: rec.jsonBody()}
+	if remember {
+		// Best-effort: the result store is not the source of truth, so storage errors
+		// only mean a retry executes the item again (or waits out the pending TTL).
+		// The parent context may already be canceled; the reservation must still resolve.
+		sctx := context.WithoutCancel(ctx)
+		if res.Status >= 500 {
+			_ = b.Results.DeleteBatchResult(sctx, workspaceID, key)
+			return res
+		}
+		ttl := b.ResultTTL
+		if ttl <= 0 {
+			ttl = DefaultBatchResultTTL
+		}
+		_ = b.Results.PutBatchResult(sctx, workspaceID, key, StoredBatchResult{Fingerprint: fp, Status: res.Status, Body: res.Body}, ttl)
+	}
+	return res
+}
+
+func batchError(id string, status int, msg string) batchItemResult {
+	body, _ := json.Marshal(gin.H{"error": msg})
+	return batchItemResult{ID: id, Status: status, Body: body}
+}
+
+// batchFingerprint identifies what an idempotency key was first used for.
+func batchFingerprint(it batchItem) string {
+	ifMatch := ""
+	for k, v := range it.Headers {
+		if http.CanonicalHeaderKey(k) == "If-Match" {
+			ifMatch = v
+		}
+	}
+	h := sha256.New()
+	h.Write([]byte(it.Method + "\n" + it.Path + "\n" + ifMatch + "\n"))
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, it.Body); err == nil {
+		h.Write(compact.Bytes())
+	} else {
+		h.Write(it.Body)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// batchRecorder captures a sub-request response in memory.
+type batchRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBatchRecorder() *batchRecorder {
+	return &batchRecorder{header: http.Header{}, status: http.StatusOK}
+}
+
+func (r *batchRecorder) Header() http.Header { return r.header }
+
+func (r *batchRecorder) Write(p []byte) (int, error) { return r.body.Write(p) }
+
+func (r *batchRecorder) WriteHeader(status int) { r.status = status }
+
+// jsonBody returns the response as JSON; non-JSON bodies are wrapped as a string.
+func (r *batchRecorder) jsonBody() json.RawMessage {
+	b := bytes.TrimSpace(r.body.Bytes())
+	if len(b) == 0 {
+		return nil
+	}
+	if json.Valid(b) {
+		return json.RawMessage(append([]byte(nil), b...))
+	}
+	s, _ := json.Marshal(string(b))
+	return s
+}
@@ -0,0 +1,50 @@
+package httpapi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryBatchResults is a simple in-memory BatchResultStore. Entries are dropped
+// lazily once expired. It is not intended for production use (results are lost on
+// restart and not shared between instances); use a Redis-backed store there.
+type MemoryBatchResults struct {
+	mu      sync.Mutex
+	entries map[string]memoryBatchEntry
+	now     func() time.Time
+}
+
+type memoryBatchEntry struct {
+	res       StoredBatchResult
+	expiresAt time.Time
+}
+
+func NewMemoryBatchResults() *MemoryBatchResults {
+	return &MemoryBatchResults{entries: map[string]memoryBatchEntry{}, now: time.Now}
+}
+
+func (m *MemoryBatchResults) ReserveBatchResult(ctx context.Context, workspaceID, key, fingerprint string, ttl time.Duration) (StoredBatchResult, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k := workspaceID + "|" + key
+	if e, ok := m.entries[k]; ok && m.now().Before(e.expiresAt) {
+		return e.res, false, nil
+	}
+	m.entries[k] = memoryBatchEntry{res: StoredBatchResult{Fingerprint: fingerprint}, expiresAt: m.now().Add(ttl)}
+	return StoredBatchResult{}, true, nil
+}
+
+func (m *MemoryBatchResults) PutBatchResult(ctx context.Context, workspaceID, key string, res StoredBatchResult, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[workspaceID+"|"+key] = memoryBatchEntry{res: res, expiresAt: m.now().Add(ttl)}
+	return nil
+}
+
+func (m *MemoryBatchResults) DeleteBatchResult(ctx context.Context, workspaceID, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, workspaceID+"|"+key)
+	return nil
+}
@@ -0,0 +1,110 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/routing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Caller blocklist ---
+
+type addBlockEntryRequest struct {
+	Pattern string             `json:"pattern"`
+	Match   routing.BlockMatch `json:"match"`
+	Note    string             `json:"note,omitempty"`
+}
+
+// ListBlocklist returns the workspace blocklist. RBAC: owner/super_admin.
+func (h Handlers) ListBlocklist(c *gin.Context) { h.listBlocklist(c, false) }
+
+// AddBlockEntry blocks a caller number or prefix for the workspace. RBAC: owner/super_admin.
+func (h Handlers) AddBlockEntry(c *gin.Context) { h.addBlockEntry(c, false) }
+
+// DeleteBlockEntry removes a workspace blocklist entry. RBAC: owner/super_admin.
+func (h Handlers) DeleteBlockEntry(c *gin.Context) { h.deleteBlockEntry(c, false) }
+
+// ListGlobalBlocklist returns the platform-wide blocklist. RBAC: super_admin.
+func (h Handlers) ListGlobalBlocklist(c *gin.Context) { h.listBlocklist(c, true) }
+
+// AddGlobalBlockEntry blocks a caller for every workspace. RBAC: super_admin.
+func (h Handlers) AddGlobalBlockEntry(c *gin.Context) { h.addBlockEntry(c, true) }
+
+// DeleteGlobalBlockEntry removes a platform-wide entry. RBAC: super_admin.
+func (h Handlers) DeleteGlobalBlockEntry(c *gin.Context) { h.deleteBlockEntry(c, true) }
+
+func (h Handlers) listBlocklist(c *gin.Context, global bool) {
+	listID, ok := h.blocklistCaller(c, global)
+	if !ok {
+		return
+	}
+	out, err := h.Blocklist.List(c.Request.Context(), listID)
+	if err != nil {
+		abortBlocklistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"entries": out})
+}
+
+func (h Handlers) addBlockEntry(c *gin.Context, global bool) {
+	listID, ok := h.blocklistCaller(c, global)
+	if !ok {
+		return
+	}
+	var req addBlockEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	uid, _ := auth.UserID(c.Request.Context())
+	b, err := h.Blocklist.Add(c.Request.Context(), routing.BlockEntry{WorkspaceID: listID, Pattern: req.Pattern, Match: req.Match, Note: req.Note, CreatedBy: uid})
+	if err != nil {
+		abortBlocklistError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, b)
+}
+
+func (h Handlers) deleteBlockEntry(c *gin.Context, global bool) {
+	listID, ok := h.blocklistCaller(c, global)
+	if !ok {
+		return
+	}
+	if err := h.Blocklist.Remove(c.Request.Context(), listID, c.Param("entry_id")); err != nil {
+		abortBlocklistError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// blocklistCaller returns the list the request addresses: the caller's workspace,
+// or the global list.
+func (h Handlers) blocklistCaller(c *gin.Context, global bool) (string, bool) {
+	if h.Blocklist == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "blocklist not configured"})
+		return "", false
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return "", false
+	}
+	if global {
+		return routing.GlobalBlocklist, true
+	}
+	return workspaceID, true
+}
+
+func abortBlocklistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, routing.ErrInvalidBlockEntry):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "pattern must be an E.164 number (exact) or + and digits (prefix)"})
+	case errors.Is(err, routing.ErrBlockEntryNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "blocklist entry not found"})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "blocklist request failed"})
+	}
+}
@@ -0,0 +1,100 @@
+package httpapi
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Body size limits ---
+
+// Default request body limits. JSON APIs never need more than a megabyte; uploads
+// (rate decks, lead lists, audio assets, documents) are streamed and get a larger cap.
+const (
+	MaxJSONBodyBytes   int64 = 1 << 20
+	MaxUploadBodyBytes int64 = 64 << 20
+
+	// maxFormFieldBytes bounds each non-file field read while streaming multipart bodies.
+	maxFormFieldBytes int64 = 64 << 10
+)
+
+// BodyLimits enforces a maximum request body size per route. perRoute is keyed by
+// "METHOD /full/path" as registered in Gin (e.g. "POST /v1/calls/import"); other
+// routes get defaultMax.
+//
+// Requests that declare a larger Content-Length are rejected with 413 before any
+// byte is read. Chunked bodies are wrapped with http.MaxBytesReader so reads fail
+// once the limit is crossed; handlers map that to 413 via abortBodyError.
+func BodyLimits(defaultMax int64, perRoute map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := defaultMax
+		if n, ok := perRoute[c.Request.Method+" "+c.FullPath()]; ok {
+			limit = n
+		}
+		if limit <= 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > limit {
+			c.Header("Connection", "close")
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large", "max_bytes": limit})
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// bodyTooLarge reports whether err came from crossing the BodyLimits cap.
+func bodyTooLarge(err error) bool {
+	var mbe *http.MaxBytesError
+	return errors.As(err, &mbe)
+}
+
+// abortBodyError responds to a body read/bind failure: 413 when the limit was hit,
+// otherwise 400 with msg.
+func abortBodyError(c *gin.Context, err error, msg string) {
+	if bodyTooLarge(err) {
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+		return
+	}
+	c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": msg})
+}
+
+// streamFormFile reads a multipart body up to the file part named field without
+// buffering the file in memory or on disk. Text fields that precede the file are
+// returned in fields; clients must send them before the file. The caller reads the
+// returned part to completion (or until error) while the request is still open.
+func streamFormFile(c *gin.Context, field string) (*multipart.Part, map[string]string, error) {
+	mr, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, nil, err
+	}
+	fields := map[string]string{}
+	for {
+		p, err := mr.NextPart()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fields, http.ErrMissingFile
+			}
+			return nil, fields, err
+		}
+		if p.FormName() == field && p.FileName() != "" {
+			return p, fields, nil
+		}
+		if p.FileName() != "" {
+			continue // unrelated file part; skipped by NextPart
+		}
+		b, err := io.ReadAll(io.LimitReader(p, maxFormFieldBytes+1))
+		if err != nil {
+			return nil, fields, err
+		}
+		if int64(len(b)) > maxFormFieldBytes {
+			return nil, fields, errors.New("form field too large")
+		}
+		fields[p.FormName()] = string(b)
+	}
+}
@@ -0,0 +1,90 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/calls"
+	"telecom-platform/internal/reporting"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Call hold ---
+
+// HoldCall puts the remote party of a live call on hold music.
+// RBAC: owner/agent/super_admin.
+func (h Handlers) HoldCall(c *gin.Context) {
+	h.callHoldAction(c, (*calls.CallHoldService).Hold)
+}
+
+// ResumeCall takes a call off hold; the hold period is added to the call's hold time.
+// RBAC: owner/agent/super_admin.
+func (h Handlers) ResumeCall(c *gin.Context) {
+	h.callHoldAction(c, (*calls.CallHoldService).Resume)
+}
+
+func (h Handlers) callHoldAction(c *gin.Context, action func(*calls.CallHoldService, context.Context, string, string, string) (calls.HoldPeriod, error)) {
+	if h.CallHolds == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "call hold not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	uid, _ := auth.UserID(c.Request.Context())
+
+	period, err := action(h.CallHolds, c.Request.Context(), workspaceID, c.Param("call_id"), uid)
+	if err != nil {
+		switch {
+		case errors.Is(err, calls.ErrInvalidCallHold):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, calls.ErrCallNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "call not found"})
+		case errors.Is(err, calls.ErrCallNotLive), errors.Is(err, calls.ErrCallOnHold), errors.Is(err, calls.ErrCallNotOnHold):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.AbortWithStatusJSON(http.StatusBadGateway, gin.H{"error": "provider call control failed"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, period)
+}
+
+// --- Agent talk time ---
+
+// AgentTalkTimeReport returns talk time per agent, without hold time when the
+// reporting service is configured to exclude it. RBAC: owner/analyst/finance/super_admin.
+//
+// Query: from, to (RFC3339) or from_date, to_date (YYYY-MM-DD, local to timezone);
+// timezone, campaign_id (optional).
+func (h Handlers) AgentTalkTimeReport(c *gin.Context) {
+	if h.Reporting == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "reporting not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	rng, ok := reportRange(c)
+	if !ok {
+		return
+	}
+	out, err := h.Reporting.AgentTalkTime(c.Request.Context(), reporting.AgentTalkTimeRequest{
+		WorkspaceID: workspaceID,
+		Range:       rng,
+		CampaignID:  c.Query("campaign_id"),
+		Timezone:    c.Query("timezone"),
+	})
+	if err != nil {
+		abortReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
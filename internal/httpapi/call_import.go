@@ -0,0 +1,99 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/calls"
+	"telecom-platform/internal/jobs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Call history import ---
+
+// ImportCalls backfills historical calls from a CSV upload (field "file") with form
+// field format ("generic" or "twilio"). Imported calls are reporting-only and never billed.
+// The file is streamed into the importer; send format before the file (or as a query param).
+// With async=true (form field or query) the file is buffered and imported as a job:
+// the response is 202 with the job, whose result is the batch; notify_url is optional.
+// RBAC: owner/super_admin.
+func (h Handlers) ImportCalls(c *gin.Context) {
+	if h.CallImport == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "call import not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	uid, _ := auth.UserID(c.Request.Context())
+
+	part, fields, err := streamFormFile(c, "file")
+	if err != nil {
+		abortBodyError(c, err, "file required")
+		return
+	}
+	defer part.Close()
+
+	format := calls.ImportFormat(fields["format"])
+	if format == "" {
+		format = calls.ImportFormat(c.DefaultQuery("format", string(calls.ImportFormatGeneric)))
+	}
+	if fieldOrQuery(c, fields, "async") == "true" {
+		h.importCallsAsync(c, workspaceID, uid, format, fieldOrQuery(c, fields, "notify_url"), part)
+		return
+	}
+	batch, err := h.CallImport.ImportCSV(c.Request.Context(), workspaceID, uid, format, part)
+	if err != nil {
+		if bodyTooLarge(err) {
+			abortBodyError(c, err, "")
+			return
+		}
+		if errors.Is(err, calls.ErrInvalidImport) || errors.Is(err, calls.ErrImportTooLarge) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "call import failed"})
+		return
+	}
+	c.JSON(http.StatusCreated, batch)
+}
+
+func (h Handlers) importCallsAsync(c *gin.Context, workspaceID, uid string, format calls.ImportFormat, notifyURL string, part io.Reader) {
+	if h.Jobs == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "jobs not configured"})
+		return
+	}
+	// The request body is gone once we respond, so the upload (already bounded by
+	// the route's body limit) is read before the job starts.
+	data, err := io.ReadAll(part)
+	if err != nil {
+		abortBodyError(c, err, "file required")
+		return
+	}
+	j, err := h.Jobs.Submit(c.Request.Context(), workspaceID, uid, jobs.KindImport, notifyURL, func(ctx context.Context, progress func(int)) (any, error) {
+		return h.CallImport.ImportCSV(ctx, workspaceID, uid, format, bytes.NewReader(data))
+	})
+	if err != nil {
+		if errors.Is(err, jobs.ErrInvalidArgument) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "job submit failed"})
+		return
+	}
+	acceptedJob(c, j)
+}
+
+func fieldOrQuery(c *gin.Context, fields map[string]string, name string) string {
+	if v, ok := fields[name]; ok {
+		return v
+	}
+	return c.Query(name)
+}
@@ -0,0 +1,101 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/calls"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Supervisor live monitoring ---
+
+// StartCallMonitor joins the caller (a manager) to a live call in listen,
+// whisper or barge mode. RBAC: calls.monitor (owner/super_admin).
+func (h Handlers) StartCallMonitor(c *gin.Context) {
+	workspaceID, uid, role, ok := h.callMonitorCaller(c)
+	if !ok {
+		return
+	}
+	var req calls.MonitorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	sess, err := h.CallMonitors.Start(c.Request.Context(), workspaceID, c.Param("call_id"), uid, role, req)
+	if err != nil {
+		abortCallMonitorError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, sess)
+}
+
+// ChangeCallMonitorMode switches the caller's own session to another mode.
+// RBAC: calls.monitor (owner/super_admin).
+func (h Handlers) ChangeCallMonitorMode(c *gin.Context) {
+	workspaceID, uid, role, ok := h.callMonitorCaller(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Mode calls.MonitorMode `json:"mode"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	sess, err := h.CallMonitors.ChangeMode(c.Request.Context(), workspaceID, c.Param("call_id"), c.Param("session_id"), uid, role, req.Mode)
+	if err != nil {
+		abortCallMonitorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sess)
+}
+
+// StopCallMonitor removes the caller from the call. RBAC: calls.monitor (owner/super_admin).
+func (h Handlers) StopCallMonitor(c *gin.Context) {
+	workspaceID, uid, role, ok := h.callMonitorCaller(c)
+	if !ok {
+		return
+	}
+	sess, err := h.CallMonitors.Stop(c.Request.Context(), workspaceID, c.Param("call_id"), c.Param("session_id"), uid, role)
+	if err != nil {
+		abortCallMonitorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sess)
+}
+
+func (h Handlers) callMonitorCaller(c *gin.Context) (workspaceID, uid, role string, ok bool) {
+	if h.CallMonitors == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "call monitoring not configured"})
+		return "", "", "", false
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return "", "", "", false
+	}
+	uid, _ = auth.UserID(c.Request.Context())
+	role, _ = auth.Role(c.Request.Context())
+	return workspaceID, uid, role, true
+}
+
+func abortCallMonitorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, calls.ErrInvalidMonitor):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, calls.ErrCallNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "call not found"})
+	case errors.Is(err, calls.ErrMonitorSessionNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "monitor session not found"})
+	case errors.Is(err, calls.ErrCallNotLive), errors.Is(err, calls.ErrAlreadyMonitoring), errors.Is(err, calls.ErrMonitorEnded):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, calls.ErrMonitorAuditFailed):
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "audit unavailable; monitoring refused"})
+	default:
+		c.AbortWithStatusJSON(http.StatusBadGateway, gin.H{"error": "provider call control failed"})
+	}
+}
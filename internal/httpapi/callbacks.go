@@ -0,0 +1,72 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/callbacks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Callback requests ---
+
+// ListCallbacks returns the workspace's callback tasks.
+// Query: status (default pending; "all" for every status), campaign_id (optional).
+// RBAC: owner/agent/super_admin.
+func (h Handlers) ListCallbacks(c *gin.Context) {
+	if h.Callbacks == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "callbacks not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	f := callbacks.ListFilter{Status: callbacks.Status(c.DefaultQuery("status", string(callbacks.StatusPending))), CampaignID: c.Query("campaign_id")}
+	if f.Status == "all" {
+		f.Status = ""
+	}
+	items, err := h.Callbacks.ListCallbacks(c.Request.Context(), workspaceID, f)
+	if err != nil {
+		writeCallbackError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"callbacks": items})
+}
+
+// CancelCallback cancels a pending callback. RBAC: owner/agent/super_admin.
+func (h Handlers) CancelCallback(c *gin.Context) {
+	if h.Callbacks == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "callbacks not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	uid, _ := auth.UserID(c.Request.Context())
+	role, _ := auth.Role(c.Request.Context())
+	out, err := h.Callbacks.Cancel(c.Request.Context(), workspaceID, c.Param("callback_id"), uid, role)
+	if err != nil {
+		writeCallbackError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func writeCallbackError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, callbacks.ErrInvalidArgument):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, callbacks.ErrNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "callback not found"})
+	case errors.Is(err, callbacks.ErrNotCancelable):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "callback request failed"})
+	}
+}
@@ -0,0 +1,99 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/numbers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Verified caller IDs ---
+
+type startCallerIDRequest struct {
+	Number       string `json:"number"`
+	FriendlyName string `json:"friendly_name"`
+}
+
+// ListCallerIDs returns the workspace's external caller IDs and their
+// verification status. RBAC: owner/super_admin.
+func (h Handlers) ListCallerIDs(c *gin.Context) {
+	workspaceID, ok := h.callerIDCaller(c)
+	if !ok {
+		return
+	}
+	out, err := h.Numbers.ListVerifiedCallerIDs(c.Request.Context(), workspaceID)
+	if err != nil {
+		abortCallerIDError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"caller_ids": out})
+}
+
+// StartCallerIDVerification registers an external number as caller ID and has the
+// provider call it; the response's validation_code must be entered on that call.
+// RBAC: owner/super_admin.
+func (h Handlers) StartCallerIDVerification(c *gin.Context) {
+	workspaceID, ok := h.callerIDCaller(c)
+	if !ok {
+		return
+	}
+	var req startCallerIDRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	v, err := h.Numbers.StartCallerIDVerification(c.Request.Context(), workspaceID, req.Number, req.FriendlyName)
+	if err != nil {
+		abortCallerIDError(c, err)
+		return
+	}
+	status := http.StatusAccepted
+	if v.Status == numbers.CallerIDVerified {
+		status = http.StatusOK
+	}
+	c.JSON(status, v)
+}
+
+// DeleteCallerID unregisters an external caller ID. RBAC: owner/super_admin.
+func (h Handlers) DeleteCallerID(c *gin.Context) {
+	workspaceID, ok := h.callerIDCaller(c)
+	if !ok {
+		return
+	}
+	if err := h.Numbers.DeleteVerifiedCallerID(c.Request.Context(), workspaceID, c.Param("caller_id")); err != nil {
+		abortCallerIDError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h Handlers) callerIDCaller(c *gin.Context) (string, bool) {
+	if h.Numbers == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "numbers not configured"})
+		return "", false
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return "", false
+	}
+	return workspaceID, true
+}
+
+func abortCallerIDError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, numbers.ErrInvalidArgument):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, numbers.ErrCallerIDNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "caller id not found"})
+	case errors.Is(err, numbers.ErrVerificationPending):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "verification already in progress"})
+	case errors.Is(err, numbers.ErrVerificationUnsupported), errors.Is(err, numbers.ErrWebhooksUnsupported):
+		c.AbortWithStatusJSON(http.StatusNotImplemented, gin.H{"error": "caller id verification unavailable"})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "caller id request failed"})
+	}
+}
@@ -0,0 +1,72 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"telecom-platform/internal/audit"
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/wallet"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Tamper evidence ---
+
+// VerifyAuditChain re-hashes the workspace audit chain. RBAC: owner/super_admin.
+func (h Handlers) VerifyAuditChain(c *gin.Context) {
+	if h.Audit == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "audit not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	res, err := h.Audit.VerifyChain(c.Request.Context(), workspaceID)
+	if err != nil {
+		if errors.Is(err, audit.ErrChainUnsupported) {
+			c.AbortWithStatusJSON(http.StatusNotImplemented, gin.H{"error": "audit chain verification unsupported"})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "audit chain verification failed"})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// VerifyWalletLedgerChain re-hashes a wallet's ledger chain. RBAC: owner/super_admin.
+// Query: anchor_seq, anchor_hash (optional): a head_seq/head_hash from an earlier
+// verification that must still be in the chain.
+func (h Handlers) VerifyWalletLedgerChain(c *gin.Context) {
+	if h.Wallet == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	var anchor wallet.LedgerAnchor
+	if v := c.Query("anchor_seq"); v != "" {
+		seq, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "anchor_seq must be an integer"})
+			return
+		}
+		anchor = wallet.LedgerAnchor{Seq: seq, Hash: c.Query("anchor_hash")}
+	}
+	res, err := h.Wallet.VerifyLedgerChainAnchor(c.Request.Context(), workspaceID, c.Param("wallet_id"), anchor)
+	if err != nil {
+		if errors.Is(err, wallet.ErrInvalidArgument) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "wallet_id required; anchor_seq and anchor_hash go together"})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "ledger chain verification failed"})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,20 @@
+package httpapi
+
+import (
+	"telecom-platform/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Client IP ---
+
+// ClientIPMiddleware resolves the caller IP behind trusted proxies once per request
+// and stores it on the request context, where audit logging and rate limiting read it.
+// Register it before any handler that audits or throttles.
+func ClientIPMiddleware(r *utils.ClientIPResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := r.Resolve(c.Request.RemoteAddr, c.Request.Header.Values("X-Forwarded-For"))
+		c.Request = c.Request.WithContext(utils.WithClientIP(c.Request.Context(), ip))
+		c.Next()
+	}
+}
@@ -0,0 +1,69 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/compliance"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Compliance ---
+
+// UploadComplianceDocument accepts a multipart upload (field "file") plus form fields
+// type, country_iso2 and optional expires_at (RFC3339).
+func (h Handlers) UploadComplianceDocument(c *gin.Context) {
+	if h.Compliance == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "compliance not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		abortBodyError(c, err, "file required")
+		return
+	}
+	var expiresAt *time.Time
+	if v := c.PostForm("expires_at"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "expires_at invalid"})
+			return
+		}
+		expiresAt = &t
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "file unreadable"})
+		return
+	}
+	defer f.Close()
+
+	doc, err := h.Compliance.Upload(c.Request.Context(), workspaceID, compliance.UploadRequest{
+		Type:        compliance.DocumentType(c.PostForm("type")),
+		CountryISO2: c.PostForm("country_iso2"),
+		FileName:    fh.Filename,
+		ContentType: fh.Header.Get("Content-Type"),
+		SizeBytes:   fh.Size,
+		ExpiresAt:   expiresAt,
+		Body:        f,
+	})
+	if err != nil {
+		if errors.Is(err, compliance.ErrInvalidArgument) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "document upload failed"})
+		return
+	}
+	c.JSON(http.StatusCreated, doc)
+}
@@ -0,0 +1,111 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/contracts"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Committed-use contracts ---
+
+type createContractRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	Currency    string `json:"currency"`
+
+	MinimumMonthlyCommitMinor int64     `json:"minimum_monthly_commit_minor"`
+	TermStart                 time.Time `json:"term_start"`
+	TermEnd                   time.Time `json:"term_end"`
+
+	OverageRates []contracts.OverageRate `json:"overage_rates,omitempty"`
+}
+
+// ListContracts returns the caller's contracts and the current month's commit status.
+// RBAC: owner/finance/super_admin.
+func (h Handlers) ListContracts(c *gin.Context) {
+	if h.Contracts == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "contracts not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	items, err := h.Contracts.ListContracts(c.Request.Context(), workspaceID)
+	if err != nil {
+		writeContractError(c, err)
+		return
+	}
+	resp := gin.H{"contracts": items}
+	if st, ok, err := h.Contracts.Status(c.Request.Context(), workspaceID, time.Time{}); err != nil {
+		writeContractError(c, err)
+		return
+	} else if ok {
+		resp["current"] = st
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateContract records a contract for a target workspace. RBAC: super_admin.
+func (h Handlers) CreateContract(c *gin.Context) {
+	if h.Contracts == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "contracts not configured"})
+		return
+	}
+	uid, _ := auth.UserID(c.Request.Context())
+	role, _ := auth.Role(c.Request.Context())
+
+	var req createContractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	out, err := h.Contracts.CreateContract(c.Request.Context(), uid, role, contracts.Contract{
+		WorkspaceID:               req.WorkspaceID,
+		Currency:                  req.Currency,
+		MinimumMonthlyCommitMinor: req.MinimumMonthlyCommitMinor,
+		TermStart:                 req.TermStart,
+		TermEnd:                   req.TermEnd,
+		OverageRates:              req.OverageRates,
+	})
+	if err != nil {
+		writeContractError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// CancelContract stops future true-ups. RBAC: super_admin.
+// Query: workspace_id (target workspace, required).
+func (h Handlers) CancelContract(c *gin.Context) {
+	if h.Contracts == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "contracts not configured"})
+		return
+	}
+	uid, _ := auth.UserID(c.Request.Context())
+	role, _ := auth.Role(c.Request.Context())
+	out, err := h.Contracts.CancelContract(c.Request.Context(), c.Query("workspace_id"), c.Param("contract_id"), uid, role)
+	if err != nil {
+		writeContractError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func writeContractError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, contracts.ErrInvalidArgument):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, contracts.ErrNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "contract not found"})
+	case errors.Is(err, contracts.ErrOverlap):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "contracts request failed"})
+	}
+}
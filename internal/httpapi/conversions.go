@@ -0,0 +1,184 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/conversions"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Conversion forwarding ---
+
+// ListConversionIntegrations lists the workspace's ad platform integrations
+// (credentials are never returned). RBAC: owner/super_admin.
+func (h Handlers) ListConversionIntegrations(c *gin.Context) {
+	if h.Conversions == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "conversions not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	list, err := h.Conversions.ListIntegrations(c.Request.Context(), workspaceID)
+	if err != nil {
+		abortConversionsError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"integrations": list})
+}
+
+// CreateConversionIntegration stores a Google Ads or Meta integration with the
+// tenant's credentials. RBAC: owner/super_admin.
+func (h Handlers) CreateConversionIntegration(c *gin.Context) {
+	if h.Conversions == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "conversions not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	var req conversions.IntegrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	in, err := h.Conversions.CreateIntegration(c.Request.Context(), workspaceID, req)
+	if err != nil {
+		abortConversionsError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, in)
+}
+
+// GetConversionIntegration returns one integration. RBAC: owner/super_admin.
+func (h Handlers) GetConversionIntegration(c *gin.Context) {
+	if h.Conversions == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "conversions not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	in, err := h.Conversions.GetIntegration(c.Request.Context(), workspaceID, c.Param("integration_id"))
+	if err != nil {
+		abortConversionsError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, in)
+}
+
+// UpdateConversionIntegration replaces an integration's settings; credentials are
+// kept unless the body carries new ones. RBAC: owner/super_admin.
+func (h Handlers) UpdateConversionIntegration(c *gin.Context) {
+	if h.Conversions == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "conversions not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	var req conversions.IntegrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	in, err := h.Conversions.UpdateIntegration(c.Request.Context(), workspaceID, c.Param("integration_id"), req)
+	if err != nil {
+		abortConversionsError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, in)
+}
+
+// DeleteConversionIntegration removes an integration; its delivery log is kept.
+// RBAC: owner/super_admin.
+func (h Handlers) DeleteConversionIntegration(c *gin.Context) {
+	if h.Conversions == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "conversions not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	if err := h.Conversions.DeleteIntegration(c.Request.Context(), workspaceID, c.Param("integration_id")); err != nil {
+		abortConversionsError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListConversionDeliveries returns the delivery log, newest first.
+// Query: integration_id, status (pending|delivered|failed), limit. RBAC: owner/super_admin.
+func (h Handlers) ListConversionDeliveries(c *gin.Context) {
+	if h.Conversions == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "conversions not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	f := conversions.DeliveryFilter{
+		IntegrationID: c.Query("integration_id"),
+		Status:        conversions.DeliveryStatus(c.Query("status")),
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		f.Limit = n
+	}
+	list, err := h.Conversions.ListDeliveries(c.Request.Context(), workspaceID, f)
+	if err != nil {
+		abortConversionsError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": list})
+}
+
+// RetryConversionDelivery re-queues a failed delivery. RBAC: owner/super_admin.
+func (h Handlers) RetryConversionDelivery(c *gin.Context) {
+	if h.Conversions == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "conversions not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	d, err := h.Conversions.RetryDelivery(c.Request.Context(), workspaceID, c.Param("delivery_id"))
+	if err != nil {
+		abortConversionsError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, d)
+}
+
+func abortConversionsError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, conversions.ErrInvalidArgument):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, conversions.ErrNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "not found"})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "conversions request failed"})
+	}
+}
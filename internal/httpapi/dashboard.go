@@ -0,0 +1,38 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/reporting"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Dashboard ---
+
+// Dashboard returns today's headline aggregates (calls, spend, active campaigns),
+// served from the cache the worker warms every minute.
+// RBAC: owner/analyst/finance/super_admin.
+func (h Handlers) Dashboard(c *gin.Context) {
+	if h.Reporting == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "reporting not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	out, err := h.Reporting.Dashboard(c.Request.Context(), workspaceID)
+	if err != nil {
+		if errors.Is(err, reporting.ErrInvalidRequest) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid workspace timezone"})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "dashboard failed"})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,106 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/telephony"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Dial plan ---
+
+type putDialPlanRequest struct {
+	DefaultCountry string `json:"default_country"`
+}
+
+type normalizeDestinationRequest struct {
+	Number string `json:"number"`
+	// Country overrides the workspace default (ISO 3166-1 alpha-2).
+	Country string `json:"country"`
+}
+
+// GetDialPlan returns the workspace default destination country and the
+// countries with a dial plan. RBAC: owner/super_admin.
+func (h Handlers) GetDialPlan(c *gin.Context) {
+	workspaceID, ok := h.dialPlanCaller(c)
+	if !ok {
+		return
+	}
+	country, err := h.DialPlan.DefaultCountry(c.Request.Context(), workspaceID)
+	if err != nil {
+		abortDialPlanError(c, err)
+		return
+	}
+	countries := make([]string, 0, len(telephony.DialPlans))
+	for iso := range telephony.DialPlans {
+		countries = append(countries, iso)
+	}
+	sort.Strings(countries)
+	c.JSON(http.StatusOK, gin.H{"default_country": country, "countries": countries})
+}
+
+// PutDialPlan sets the workspace default destination country. RBAC: owner/super_admin.
+func (h Handlers) PutDialPlan(c *gin.Context) {
+	workspaceID, ok := h.dialPlanCaller(c)
+	if !ok {
+		return
+	}
+	var req putDialPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	if err := h.DialPlan.SetDefaultCountry(c.Request.Context(), workspaceID, req.DefaultCountry); err != nil {
+		abortDialPlanError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// NormalizeDestination returns the E.164 form a destination would be dialed as.
+// Emergency numbers are rejected with 422. RBAC: owner/agent/super_admin.
+func (h Handlers) NormalizeDestination(c *gin.Context) {
+	workspaceID, ok := h.dialPlanCaller(c)
+	if !ok {
+		return
+	}
+	var req normalizeDestinationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	e164, err := h.DialPlan.Normalize(c.Request.Context(), workspaceID, req.Number, req.Country)
+	if err != nil {
+		abortDialPlanError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"number": req.Number, "e164": e164})
+}
+
+func (h Handlers) dialPlanCaller(c *gin.Context) (string, bool) {
+	if h.DialPlan == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "dial plan not configured"})
+		return "", false
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return "", false
+	}
+	return workspaceID, true
+}
+
+func abortDialPlanError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, telephony.ErrEmergencyNumber):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "emergency numbers cannot be dialed via the api"})
+	case errors.Is(err, telephony.ErrInvalidDestination), errors.Is(err, telephony.ErrUnknownCountry):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "dial plan request failed"})
+	}
+}
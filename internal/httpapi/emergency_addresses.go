@@ -0,0 +1,78 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/numbers"
+	"telecom-platform/internal/telephony"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Emergency (E911) addresses ---
+
+// GetEmergencyAddress returns the number's registered emergency address.
+// RBAC: owner/super_admin.
+func (h Handlers) GetEmergencyAddress(c *gin.Context) {
+	workspaceID, ok := h.callerIDCaller(c)
+	if !ok {
+		return
+	}
+	a, err := h.Numbers.GetEmergencyAddress(c.Request.Context(), workspaceID, c.Param("number"))
+	if err != nil {
+		abortEmergencyAddressError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, a)
+}
+
+// PutEmergencyAddress registers the number's emergency address with the provider.
+// RBAC: owner/super_admin.
+func (h Handlers) PutEmergencyAddress(c *gin.Context) {
+	workspaceID, ok := h.callerIDCaller(c)
+	if !ok {
+		return
+	}
+	var req telephony.EmergencyAddress
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	a, err := h.Numbers.RegisterEmergencyAddress(c.Request.Context(), workspaceID, c.Param("number"), req)
+	if err != nil {
+		abortEmergencyAddressError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, a)
+}
+
+// GetEmergencyReadiness lists the workspace's US voice numbers without an
+// emergency address; ready is false while any remain. RBAC: owner/super_admin.
+func (h Handlers) GetEmergencyReadiness(c *gin.Context) {
+	workspaceID, ok := h.callerIDCaller(c)
+	if !ok {
+		return
+	}
+	r, err := h.Numbers.EmergencyReadiness(c.Request.Context(), workspaceID)
+	if err != nil {
+		abortEmergencyAddressError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
+
+func abortEmergencyAddressError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, numbers.ErrInvalidArgument):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, numbers.ErrNumberNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "number not found"})
+	case errors.Is(err, numbers.ErrEmergencyAddressMissing):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "no emergency address registered"})
+	case errors.Is(err, numbers.ErrEmergencyUnsupported):
+		c.AbortWithStatusJSON(http.StatusNotImplemented, gin.H{"error": "emergency address registration unavailable"})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "emergency address request failed"})
+	}
+}
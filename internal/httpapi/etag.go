@@ -0,0 +1,47 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Optimistic concurrency ---
+//
+// Versioned resources are returned with a strong ETag ("<version>"). Writes accept
+// If-Match with that ETag; a stale value makes the service return a version
+// conflict, which handlers map to 409. Without If-Match the write is unconditional,
+// so existing API clients keep last-write-wins.
+
+func setETag(c *gin.Context, version int64) {
+	if version > 0 {
+		c.Header("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+	}
+}
+
+// ifMatchVersion parses If-Match into a version. Absent or "*" returns 0 (no check).
+// ok=false means the header was malformed and a 400 has been written.
+func ifMatchVersion(c *gin.Context) (int64, bool) {
+	raw := strings.TrimSpace(c.GetHeader("If-Match"))
+	if raw == "" || raw == "*" {
+		return 0, true
+	}
+	// Weak validators are accepted: the version is the same either way.
+	raw = strings.TrimPrefix(raw, "W/")
+	unq, err := strconv.Unquote(raw)
+	if err == nil {
+		raw = unq
+	}
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || v <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid If-Match"})
+		return 0, false
+	}
+	return v, true
+}
+
+func abortVersionConflict(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "resource was modified by another request; reload and retry"})
+}
@@ -0,0 +1,120 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/routing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Number forwarding ---
+
+type setForwardingRequest struct {
+	ForwardTo  string                     `json:"forward_to"`
+	Schedule   []routing.ForwardingWindow `json:"schedule,omitempty"`
+	Timezone   string                     `json:"timezone,omitempty"`
+	FallbackTo string                     `json:"fallback_to,omitempty"`
+	Enabled    *bool                      `json:"enabled,omitempty"` // default true
+}
+
+// GetNumberForwarding returns the forwarding config of a number.
+func (h Handlers) GetNumberForwarding(c *gin.Context) {
+	if h.Forwarding == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "forwarding not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	out, err := h.Forwarding.Get(c.Request.Context(), workspaceID, c.Param("number"))
+	if err != nil {
+		writeForwardingError(c, err)
+		return
+	}
+	setETag(c, out.Version)
+	c.JSON(http.StatusOK, out)
+}
+
+// SetNumberForwarding forwards a number to a phone or SIP target, optionally on a schedule.
+// Honours If-Match. RBAC: owner/super_admin.
+func (h Handlers) SetNumberForwarding(c *gin.Context) {
+	if h.Forwarding == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "forwarding not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	uid, _ := auth.UserID(c.Request.Context())
+	role, _ := auth.Role(c.Request.Context())
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	var req setForwardingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	out, err := h.Forwarding.Set(c.Request.Context(), uid, role, routing.NumberForwarding{
+		WorkspaceID: workspaceID,
+		Number:      c.Param("number"),
+		ForwardTo:   req.ForwardTo,
+		Schedule:    req.Schedule,
+		Timezone:    req.Timezone,
+		FallbackTo:  req.FallbackTo,
+		Enabled:     enabled,
+		Version:     version,
+	})
+	if err != nil {
+		writeForwardingError(c, err)
+		return
+	}
+	setETag(c, out.Version)
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteNumberForwarding returns the number to campaign routing. RBAC: owner/super_admin.
+func (h Handlers) DeleteNumberForwarding(c *gin.Context) {
+	if h.Forwarding == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "forwarding not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	uid, _ := auth.UserID(c.Request.Context())
+	role, _ := auth.Role(c.Request.Context())
+	if err := h.Forwarding.Delete(c.Request.Context(), workspaceID, c.Param("number"), uid, role); err != nil {
+		writeForwardingError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func writeForwardingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, routing.ErrInvalidForwarding):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, routing.ErrForwardingNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "forwarding not found"})
+	case errors.Is(err, routing.ErrVersionConflict):
+		abortVersionConflict(c)
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "forwarding request failed"})
+	}
+}
@@ -1,12 +1,34 @@
 package httpapi
 
 import (
+	"encoding/json"
 	"net/http"
 	"time"
 
+	"telecom-platform/internal/announcements"
+	"telecom-platform/internal/approvals"
+	"telecom-platform/internal/audit"
 	"telecom-platform/internal/auth"
+	"telecom-platform/internal/callbacks"
+	"telecom-platform/internal/calls"
+	"telecom-platform/internal/compliance"
+	"telecom-platform/internal/contracts"
+	"telecom-platform/internal/conversions"
+	"telecom-platform/internal/dialer"
+	"telecom-platform/internal/jobs"
+	"telecom-platform/internal/lookups"
+	"telecom-platform/internal/messaging"
+	"telecom-platform/internal/noc"
+	"telecom-platform/internal/numbers"
+	"telecom-platform/internal/pricing"
 	"telecom-platform/internal/rbac"
+	"telecom-platform/internal/reporting"
+	"telecom-platform/internal/routing"
+	"telecom-platform/internal/telephony"
+	"telecom-platform/internal/tracking"
 	"telecom-platform/internal/wallet"
+	"telecom-platform/internal/webhooks"
+	"telecom-platform/internal/workspaces"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,8 +37,39 @@ import (
 // Keep these thin: parse/validate input, call internal services, return JSON.
 
 type Handlers struct {
-	Auth   *auth.Manager
-	Wallet *wallet.Service
+	Auth          *auth.Manager
+	Wallet        *wallet.Service
+	Numbers       *numbers.Service
+	NumberDrift   numbers.DriftReportStore
+	Compliance    *compliance.Service
+	Announcements *announcements.Service
+	AuditExport   audit.ExportConfigStore
+	Audit         *audit.Service
+	AccessLog     *audit.AccessLogger
+	Approvals     *approvals.Service
+	Reporting     *reporting.Service
+	NOC           *noc.Service
+	CallImport    *calls.Importer
+	LegalHolds    *calls.LegalHoldService
+	CallHolds     *calls.CallHoldService
+	CallMonitors  *calls.CallMonitorService
+	Abandonment   *dialer.AbandonmentTracker
+	Contracts     *contracts.Service
+	Pricing       *pricing.Service
+	Workspaces    *workspaces.Service
+	Callbacks     *callbacks.Service
+	Routing       *routing.RoutingEngine
+	Blocklist     *routing.Blocklist
+	Forwarding    *routing.ForwardingService
+	Batch         *BatchRunner
+	Jobs          *jobs.Service
+	Lookups       *lookups.Service
+	Messaging     *messaging.Service
+	Webhooks      *webhooks.Service
+	Conversions   *conversions.Service
+	Tracking      *tracking.Service
+	SIPTrunks     *telephony.SIPTrunkService
+	DialPlan      *telephony.DialPlanner
 }
 
 // --- Auth ---
@@ -37,7 +90,7 @@ func (h Handlers) Login(c *gin.Context) {
 	}
 	var req loginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+		abortBodyError(c, err, "invalid json")
 		return
 	}
 	if req.UserID == "" || req.WorkspaceID == "" || req.Role == "" {
@@ -104,13 +157,43 @@ func (h Handlers) AdminManualCredit(c *gin.Context) {
 
 	var req adminManualCreditRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+		abortBodyError(c, err, "invalid json")
 		return
 	}
 	if req.WalletID == "" {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "wallet_id required"})
 		return
 	}
+	// Reject bad metadata up front so it is not discovered only when an approval executes.
+	if _, err := wallet.NormalizeMetadata(wallet.LedgerEntryTypeCredit, req.Metadata); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Four-eyes: large credits are queued for a second admin instead of executing.
+	if h.Approvals != nil && h.Approvals.RequiresApproval(approvals.ActionManualCredit, req.AmountMinor) {
+		payload, _ := json.Marshal(approvals.ManualCreditPayload{
+			WalletID:       req.WalletID,
+			AmountMinor:    req.AmountMinor,
+			Currency:       req.Currency,
+			Reason:         req.Reason,
+			IdempotencyKey: req.IdempotencyKey,
+			Metadata:       req.Metadata,
+		})
+		ar, err := h.Approvals.Submit(c.Request.Context(), workspaceID, adminUserID, adminRole, approvals.SubmitRequest{
+			Action:      approvals.ActionManualCredit,
+			Payload:     string(payload),
+			AmountMinor: req.AmountMinor,
+			Currency:    req.Currency,
+			Reason:      req.Reason,
+		})
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"approval": ar})
+		return
+	}
 
 	_, _, bal, err := h.Wallet.AdminManualCredit(c.Request.Context(), workspaceID, req.WalletID, adminUserID, adminRole, wallet.AdminCreditRequest{
 		AmountMinor:    req.AmountMinor,
@@ -0,0 +1,97 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"telecom-platform/internal/reporting"
+	"telecom-platform/internal/wallet"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Internal compliance reports ---
+
+// OverrideUsageReport summarizes silent override usage across workspaces.
+// RBAC: super_admin only. Never expose this to tenant roles.
+//
+// Query: from, to (RFC3339) or from_date, to_date (YYYY-MM-DD, local to timezone);
+// timezone (IANA, optional; defaults to UTC); workspace_id (optional filter).
+func (h Handlers) OverrideUsageReport(c *gin.Context) {
+	if h.Reporting == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "reporting not configured"})
+		return
+	}
+	rng, ok := reportRange(c)
+	if !ok {
+		return
+	}
+	out, err := h.Reporting.OverrideUsage(c.Request.Context(), reporting.OverrideUsageRequest{
+		WorkspaceID: c.Query("workspace_id"),
+		Range:       rng,
+		Timezone:    c.Query("timezone"),
+	})
+	if err != nil {
+		abortReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// SystemAccountTotals nets the platform's system ledger accounts (revenue, funding,
+// adjustments, holds) per currency for [from, to). RBAC: super_admin only.
+//
+// Query: from, to (RFC3339).
+func (h Handlers) SystemAccountTotals(c *gin.Context) {
+	if h.Wallet == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet not configured"})
+		return
+	}
+	from, err1 := time.Parse(time.RFC3339, c.Query("from"))
+	to, err2 := time.Parse(time.RFC3339, c.Query("to"))
+	if err1 != nil || err2 != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "from and to must be RFC3339 timestamps"})
+		return
+	}
+	out, err := h.Wallet.SystemAccountTotals(c.Request.Context(), from, to)
+	if err != nil {
+		if errors.Is(err, wallet.ErrInvalidArgument) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid range"})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "report failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"accounts": out})
+}
+
+// reportRange parses either RFC3339 from/to or local from_date/to_date query params.
+// Date strings are resolved by the reporting service in the request/workspace timezone.
+func reportRange(c *gin.Context) (reporting.TimeRange, bool) {
+	if fd, td := c.Query("from_date"), c.Query("to_date"); fd != "" || td != "" {
+		return reporting.TimeRange{FromDate: fd, ToDate: td}, true
+	}
+	from, err1 := time.Parse(time.RFC3339, c.Query("from"))
+	to, err2 := time.Parse(time.RFC3339, c.Query("to"))
+	if err1 != nil || err2 != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "from and to must be RFC3339 timestamps (or use from_date/to_date)"})
+		return reporting.TimeRange{}, false
+	}
+	return reporting.TimeRange{From: from, To: to}, true
+}
+
+// abortReportError maps reporting errors, including the query cost guardrails, to
+// HTTP responses.
+func abortReportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, reporting.ErrTooManyReports):
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many reports in progress; retry shortly"})
+	case errors.Is(err, reporting.ErrRangeTooLarge):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, reporting.ErrInvalidRequest):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid range or timezone"})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "report failed"})
+	}
+}
@@ -0,0 +1,49 @@
+package httpapi
+
+import (
+	"encoding/json"
+
+	"telecom-platform/internal/audit"
+	"telecom-platform/internal/auth"
+	"telecom-platform/pkg/logger"
+	"telecom-platform/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAllowedIP rejects requests whose client IP (see ClientIPMiddleware) is
+// outside list with 403. A disabled list allows everything. Mount it on privileged
+// groups after authentication so denied attempts are audited with the caller's
+// workspace and role; auditor is optional and auditing is best-effort.
+func RequireAllowedIP(list *utils.IPAllowlist, auditor *audit.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !list.Enabled() {
+			c.Next()
+			return
+		}
+		ctx := c.Request.Context()
+		ip := utils.ClientIP(ctx)
+		if list.Allows(ip) {
+			c.Next()
+			return
+		}
+
+		workspaceID, _ := auth.WorkspaceID(ctx)
+		userID, _ := auth.UserID(ctx)
+		role, _ := auth.Role(ctx)
+		logger.From(ctx).Warn("privileged request from disallowed ip", "ip", ip, "path", c.FullPath(), "workspace_id", workspaceID, "user_id", userID)
+		if auditor != nil && workspaceID != "" {
+			meta, _ := json.Marshal(map[string]string{"method": c.Request.Method, "path": c.Request.URL.Path})
+			_ = auditor.Append(ctx, audit.Event{
+				WorkspaceID: workspaceID,
+				Type:        audit.EventTypeAccessDenied,
+				ActorUserID: userID,
+				ActorRole:   role,
+				IPAddress:   ip,
+				Message:     "source ip not in admin allowlist",
+				Metadata:    string(meta),
+			})
+		}
+		c.AbortWithStatusJSON(403, gin.H{"error": "source ip not allowed"})
+	}
+}
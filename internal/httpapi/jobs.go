@@ -0,0 +1,94 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/jobs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Jobs ---
+
+// GetJob returns an async job. ?wait=<duration> (e.g. 30s, or plain seconds)
+// long-polls until the job is terminal or the wait elapses; the response is the
+// current job either way, so clients just re-issue the request while it is running.
+// RBAC: owner/analyst/finance/super_admin.
+func (h Handlers) GetJob(c *gin.Context) {
+	if h.Jobs == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "jobs not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	wait, ok := parseWait(c.Query("wait"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid wait"})
+		return
+	}
+	j, err := h.Jobs.Wait(c.Request.Context(), workspaceID, c.Param("job_id"), wait)
+	if err != nil {
+		switch {
+		case errors.Is(err, jobs.ErrNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		case errors.Is(err, jobs.ErrInvalidArgument):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "job lookup failed"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, j)
+}
+
+// ListJobs lists the workspace's jobs, newest first. Filters: kind, status, limit.
+// RBAC: owner/analyst/finance/super_admin.
+func (h Handlers) ListJobs(c *gin.Context) {
+	if h.Jobs == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "jobs not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	list, err := h.Jobs.List(c.Request.Context(), workspaceID, jobs.ListFilter{
+		Kind:   jobs.Kind(c.Query("kind")),
+		Status: jobs.Status(c.Query("status")),
+		Limit:  limit,
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "job list failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"jobs": list})
+}
+
+// acceptedJob answers a submitted async operation with 202 and the job's location.
+func acceptedJob(c *gin.Context, j jobs.Job) {
+	c.Header("Location", "/v1/jobs/"+j.ID)
+	c.JSON(http.StatusAccepted, gin.H{"job": j})
+}
+
+func parseWait(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, true
+	}
+	if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+		return time.Duration(n) * time.Second, true
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, false
+	}
+	return d, true
+}
@@ -0,0 +1,106 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/calls"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Legal holds ---
+
+type releaseLegalHoldRequest struct {
+	Reason string `json:"reason"`
+}
+
+// PlaceLegalHold preserves a call (or only its recording) from purges.
+// RBAC: owner/super_admin.
+func (h Handlers) PlaceLegalHold(c *gin.Context) {
+	if h.LegalHolds == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "legal holds not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	uid, _ := auth.UserID(c.Request.Context())
+	role, _ := auth.Role(c.Request.Context())
+
+	var req calls.PlaceLegalHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	hold, err := h.LegalHolds.Place(c.Request.Context(), workspaceID, uid, role, req)
+	if err != nil {
+		abortLegalHoldError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, hold)
+}
+
+// ReleaseLegalHold lifts a hold; a reason is required and recorded.
+// RBAC: owner/super_admin.
+func (h Handlers) ReleaseLegalHold(c *gin.Context) {
+	if h.LegalHolds == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "legal holds not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	uid, _ := auth.UserID(c.Request.Context())
+	role, _ := auth.Role(c.Request.Context())
+
+	var req releaseLegalHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	hold, err := h.LegalHolds.Release(c.Request.Context(), workspaceID, c.Param("hold_id"), uid, role, req.Reason)
+	if err != nil {
+		abortLegalHoldError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, hold)
+}
+
+// ListLegalHolds reports the items currently under hold.
+// RBAC: owner/super_admin.
+func (h Handlers) ListLegalHolds(c *gin.Context) {
+	if h.LegalHolds == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "legal holds not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	holds, err := h.LegalHolds.Report(c.Request.Context(), workspaceID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "legal hold report failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"legal_holds": holds})
+}
+
+func abortLegalHoldError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, calls.ErrInvalidLegalHold):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, calls.ErrLegalHoldNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "legal hold not found"})
+	case errors.Is(err, calls.ErrLegalHoldReleased):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "legal hold update failed"})
+	}
+}
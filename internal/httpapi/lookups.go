@@ -0,0 +1,44 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/lookups"
+	"telecom-platform/internal/wallet"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Number lookups ---
+
+// LookupNumber returns line type, carrier, ported status and validity for an E.164
+// number. Uncached lookups are billed to the workspace's default wallet.
+// RBAC: owner/agent/super_admin.
+func (h Handlers) LookupNumber(c *gin.Context) {
+	if h.Lookups == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "lookups not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	r, err := h.Lookups.Lookup(c.Request.Context(), workspaceID, c.Param("number"))
+	if err != nil {
+		switch {
+		case errors.Is(err, lookups.ErrInvalidArgument):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "number must be E.164"})
+		case errors.Is(err, lookups.ErrNoWallet):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, wallet.ErrInsufficientFunds), errors.Is(err, wallet.ErrSpendCapExceeded), errors.Is(err, wallet.ErrWalletNotActive):
+			c.AbortWithStatusJSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+		default:
+			c.AbortWithStatusJSON(http.StatusBadGateway, gin.H{"error": "lookup failed"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
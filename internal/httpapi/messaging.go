@@ -0,0 +1,164 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/messaging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- SMS keywords and opt-outs ---
+
+type keywordRuleRequest struct {
+	Number  string `json:"number"`
+	Keyword string `json:"keyword"`
+	Reply   string `json:"reply"`
+}
+
+// ListKeywordRules returns the workspace's keyword auto-replies.
+// RBAC: owner/super_admin.
+func (h Handlers) ListKeywordRules(c *gin.Context) {
+	if h.Messaging == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "messaging not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	list, err := h.Messaging.ListRules(c.Request.Context(), workspaceID)
+	if err != nil {
+		abortMessagingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rules": list})
+}
+
+// PutKeywordRule creates or replaces a keyword reply. STOP/START/HELP keywords keep
+// their opt-out/opt-in behavior; only the confirmation text changes.
+// RBAC: owner/super_admin.
+func (h Handlers) PutKeywordRule(c *gin.Context) {
+	if h.Messaging == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "messaging not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	var req keywordRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	r, err := h.Messaging.PutRule(c.Request.Context(), workspaceID, req.Number, req.Keyword, req.Reply)
+	if err != nil {
+		abortMessagingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
+
+// DeleteKeywordRule removes a keyword reply. RBAC: owner/super_admin.
+func (h Handlers) DeleteKeywordRule(c *gin.Context) {
+	if h.Messaging == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "messaging not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	if err := h.Messaging.DeleteRule(c.Request.Context(), workspaceID, c.Param("rule_id")); err != nil {
+		abortMessagingError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListOptOuts returns recipients who texted STOP, with the message that opted them out.
+// There is deliberately no delete: only the recipient can opt back in (START).
+// RBAC: owner/super_admin.
+func (h Handlers) ListOptOuts(c *gin.Context) {
+	if h.Messaging == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "messaging not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	list, err := h.Messaging.ListOptOuts(c.Request.Context(), workspaceID)
+	if err != nil {
+		abortMessagingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"opt_outs": list})
+}
+
+// GetMessagingConfig returns where non-keyword inbound messages are forwarded.
+// RBAC: owner/super_admin.
+func (h Handlers) GetMessagingConfig(c *gin.Context) {
+	if h.Messaging == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "messaging not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	cfg, err := h.Messaging.GetConfig(c.Request.Context(), workspaceID)
+	if err != nil {
+		abortMessagingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cfg)
+}
+
+// PutMessagingConfig sets the forward URL. Body: {"forward_url":"https://..."}.
+// RBAC: owner/super_admin.
+func (h Handlers) PutMessagingConfig(c *gin.Context) {
+	if h.Messaging == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "messaging not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	var req struct {
+		ForwardURL string `json:"forward_url"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	cfg, err := h.Messaging.SetForwardURL(c.Request.Context(), workspaceID, req.ForwardURL)
+	if err != nil {
+		abortMessagingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cfg)
+}
+
+func abortMessagingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, messaging.ErrInvalidArgument):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, messaging.ErrNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "not found"})
+	case errors.Is(err, messaging.ErrOptedOut):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "recipient opted out"})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "messaging request failed"})
+	}
+}
@@ -0,0 +1,112 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/messaging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- SMS send queue ---
+
+// sendMessageRequest is messaging.SendRequest plus an optional number pool whose
+// active numbers, in rotation order, replace senders.
+type sendMessageRequest struct {
+	messaging.SendRequest
+	PoolID string `json:"pool_id"`
+}
+
+// SendMessage queues an outbound SMS paced to its sender's carrier limit. The
+// response carries the chosen sender and send_at (the ETA); poll GetMessage for
+// the outcome. RBAC: owner/super_admin.
+func (h Handlers) SendMessage(c *gin.Context) {
+	if h.Messaging == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "messaging not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	var req sendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	if req.PoolID != "" {
+		if h.Numbers == nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "numbers not configured"})
+			return
+		}
+		// Sticky pools keep one sender per recipient.
+		pooled, err := h.Numbers.RotatedNumbers(c.Request.Context(), workspaceID, req.PoolID, req.To)
+		if err != nil {
+			abortPoolError(c, err)
+			return
+		}
+		req.Senders = make([]messaging.Sender, 0, len(pooled))
+		for _, n := range pooled {
+			req.Senders = append(req.Senders, messaging.Sender{Number: n.Number, Class: senderClass(n.NumberType)})
+		}
+	}
+	m, err := h.Messaging.Enqueue(c.Request.Context(), workspaceID, req.SendRequest)
+	if err != nil {
+		abortMessagingError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, m)
+}
+
+// GetMessage returns a queued or finished outbound SMS. RBAC: owner/super_admin.
+func (h Handlers) GetMessage(c *gin.Context) {
+	if h.Messaging == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "messaging not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	m, err := h.Messaging.GetMessage(c.Request.Context(), workspaceID, c.Param("message_id"))
+	if err != nil {
+		abortMessagingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, m)
+}
+
+// GetMessageQueue reports queue depth and ETA per sending number.
+// RBAC: owner/super_admin.
+func (h Handlers) GetMessageQueue(c *gin.Context) {
+	if h.Messaging == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "messaging not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	stats, err := h.Messaging.QueueStats(c.Request.Context(), workspaceID)
+	if err != nil {
+		abortMessagingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"senders": stats})
+}
+
+// senderClass maps an inventory number type to its carrier throughput class.
+func senderClass(numberType string) messaging.SenderClass {
+	switch numberType {
+	case "toll_free":
+		return messaging.SenderTollFree
+	case "short_code":
+		return messaging.SenderShortCode
+	default:
+		return messaging.SenderLongCode
+	}
+}
@@ -0,0 +1,160 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/noc"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Network operator console ---
+//
+// RBAC: network_operator (hidden) and super_admin only, via
+// rbac.RequirePlatformRole: the controls are platform-wide, not per workspace.
+// These routes live in their own /v1/noc group and are never added to the
+// regular admin group.
+
+type emergencyStopRequest struct {
+	Provider string `json:"provider"`
+	Reason   string `json:"reason"`
+}
+
+func (h Handlers) NOCLiveCalls(c *gin.Context) {
+	if h.NOC == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "noc not configured"})
+		return
+	}
+	out, err := h.NOC.LiveCallCounts(c.Request.Context())
+	if err != nil {
+		writeNOCError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"providers": out})
+}
+
+func (h Handlers) NOCTrunkHealth(c *gin.Context) {
+	if h.NOC == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "noc not configured"})
+		return
+	}
+	out, err := h.NOC.TrunkHealth(c.Request.Context())
+	if err != nil {
+		writeNOCError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"trunks": out})
+}
+
+func (h Handlers) NOCEmergencyStops(c *gin.Context) {
+	if h.NOC == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "noc not configured"})
+		return
+	}
+	out, err := h.NOC.EmergencyStops(c.Request.Context())
+	if err != nil {
+		writeNOCError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"stops": out})
+}
+
+func (h Handlers) NOCEngageStop(c *gin.Context) {
+	if h.NOC == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "noc not configured"})
+		return
+	}
+	var req emergencyStopRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	uid, role := nocActor(c)
+	out, err := h.NOC.EngageStop(c.Request.Context(), uid, role, req.Provider, req.Reason)
+	if err != nil {
+		writeNOCError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h Handlers) NOCReleaseStop(c *gin.Context) {
+	if h.NOC == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "noc not configured"})
+		return
+	}
+	uid, role := nocActor(c)
+	if err := h.NOC.ReleaseStop(c.Request.Context(), uid, role, c.Query("provider")); err != nil {
+		writeNOCError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h Handlers) NOCListReroutes(c *gin.Context) {
+	if h.NOC == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "noc not configured"})
+		return
+	}
+	out, err := h.NOC.ActiveReroutes(c.Request.Context())
+	if err != nil {
+		writeNOCError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"reroutes": out})
+}
+
+func (h Handlers) NOCCreateReroute(c *gin.Context) {
+	if h.NOC == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "noc not configured"})
+		return
+	}
+	var req noc.RerouteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	uid, role := nocActor(c)
+	out, err := h.NOC.CreateReroute(c.Request.Context(), uid, role, req)
+	if err != nil {
+		writeNOCError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+func (h Handlers) NOCDeleteReroute(c *gin.Context) {
+	if h.NOC == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "noc not configured"})
+		return
+	}
+	uid, role := nocActor(c)
+	if err := h.NOC.DeleteReroute(c.Request.Context(), uid, role, c.Param("reroute_id")); err != nil {
+		writeNOCError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func nocActor(c *gin.Context) (userID, role string) {
+	userID, _ = auth.UserID(c.Request.Context())
+	role, _ = auth.Role(c.Request.Context())
+	return userID, role
+}
+
+func writeNOCError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, noc.ErrInvalidArgument):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, noc.ErrNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "not found"})
+	case errors.Is(err, noc.ErrNotConfigured):
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, noc.ErrNotAudited):
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "action applied but audit record failed"})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "noc request failed"})
+	}
+}
@@ -0,0 +1,179 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/numbers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Number pools ---
+
+// numberPoolResponse adds the pool's health so dashboards need one request.
+type numberPoolResponse struct {
+	numbers.NumberPool
+	Health numbers.PoolHealth `json:"health"`
+}
+
+// ListNumberPools returns the workspace's number pools. RBAC: owner/super_admin.
+func (h Handlers) ListNumberPools(c *gin.Context) {
+	if h.Numbers == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "numbers not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	pools, err := h.Numbers.ListPools(c.Request.Context(), workspaceID)
+	if err != nil {
+		abortPoolError(c, err)
+		return
+	}
+	out := make([]numberPoolResponse, 0, len(pools))
+	for _, p := range pools {
+		out = append(out, numberPoolResponse{NumberPool: p, Health: p.Health()})
+	}
+	c.JSON(http.StatusOK, gin.H{"pools": out})
+}
+
+// CreateNumberPool creates a messaging, tracking or caller_id pool from owned numbers.
+// RBAC: owner/super_admin.
+func (h Handlers) CreateNumberPool(c *gin.Context) {
+	if h.Numbers == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "numbers not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	var req numbers.PoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	p, err := h.Numbers.CreatePool(c.Request.Context(), workspaceID, req)
+	if err != nil {
+		abortPoolError(c, err)
+		return
+	}
+	setETag(c, p.Version)
+	c.JSON(http.StatusCreated, numberPoolResponse{NumberPool: p, Health: p.Health()})
+}
+
+// GetNumberPool returns a pool with its health. RBAC: owner/super_admin.
+func (h Handlers) GetNumberPool(c *gin.Context) {
+	if h.Numbers == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "numbers not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	p, err := h.Numbers.GetPool(c.Request.Context(), workspaceID, c.Param("pool_id"))
+	if err != nil {
+		abortPoolError(c, err)
+		return
+	}
+	setETag(c, p.Version)
+	c.JSON(http.StatusOK, numberPoolResponse{NumberPool: p, Health: p.Health()})
+}
+
+// UpdateNumberPool replaces a pool's settings and members. Honours If-Match.
+// RBAC: owner/super_admin.
+func (h Handlers) UpdateNumberPool(c *gin.Context) {
+	if h.Numbers == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "numbers not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	var req numbers.PoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	req.IfVersion = version
+	p, err := h.Numbers.UpdatePool(c.Request.Context(), workspaceID, c.Param("pool_id"), req)
+	if err != nil {
+		abortPoolError(c, err)
+		return
+	}
+	setETag(c, p.Version)
+	c.JSON(http.StatusOK, numberPoolResponse{NumberPool: p, Health: p.Health()})
+}
+
+// DeleteNumberPool removes a pool; its numbers stay owned. RBAC: owner/super_admin.
+func (h Handlers) DeleteNumberPool(c *gin.Context) {
+	if h.Numbers == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "numbers not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	if err := h.Numbers.DeletePool(c.Request.Context(), workspaceID, c.Param("pool_id")); err != nil {
+		abortPoolError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// PreviewCallerID shows which caller ID a caller_id pool would present to a
+// callee, and why (area_code, region, fallback or rotation). Nothing is recorded.
+// RBAC: owner/super_admin.
+//
+// Query: to (E.164, required).
+func (h Handlers) PreviewCallerID(c *gin.Context) {
+	if h.Numbers == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "numbers not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	rec, err := h.Numbers.SelectCallerID(c.Request.Context(), workspaceID, c.Param("pool_id"), c.Query("to"), "")
+	if err != nil {
+		abortPoolError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rec)
+}
+
+func abortPoolError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, numbers.ErrInvalidArgument):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, numbers.ErrPoolNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "pool not found"})
+	case errors.Is(err, numbers.ErrNumberNotFound), errors.Is(err, numbers.ErrCapabilityMismatch), errors.Is(err, numbers.ErrNumberInPool):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, numbers.ErrNoLocalNumber):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "pool has no local number for destination"})
+	case errors.Is(err, numbers.ErrPoolExhausted):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "pool has no active numbers"})
+	case errors.Is(err, numbers.ErrVersionConflict):
+		abortVersionConflict(c)
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "number pool request failed"})
+	}
+}
@@ -0,0 +1,300 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/numbers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Numbers ---
+
+// GetNumberRequirements surfaces provider regulatory-document requirements so clients
+// know which documents to upload before buying a number.
+func (h Handlers) GetNumberRequirements(c *gin.Context) {
+	if h.Numbers == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "numbers not configured"})
+		return
+	}
+	req, found, err := h.Numbers.Requirements(c.Request.Context(), c.Query("country"), c.Query("number_type"))
+	if err != nil {
+		if errors.Is(err, numbers.ErrInvalidArgument) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "country and number_type required"})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "requirements lookup failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"regulated": found && req.NeedsDocuments(), "requirement": req})
+}
+
+// SearchNumbers lists numbers available for purchase so tenants can pick a specific one.
+// Query: country, number_type (required); area_code, contains, capabilities (comma
+// separated), page_size, page_token.
+func (h Handlers) SearchNumbers(c *gin.Context) {
+	if h.Numbers == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "numbers not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	q := numbers.SearchQuery{
+		CountryISO2: c.Query("country"),
+		NumberType:  c.Query("number_type"),
+		AreaCode:    c.Query("area_code"),
+		Contains:    c.Query("contains"),
+		PageToken:   c.Query("page_token"),
+	}
+	if v := c.Query("capabilities"); v != "" {
+		q.Capabilities = strings.Split(v, ",")
+	}
+	if v := c.Query("page_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid page_size"})
+			return
+		}
+		q.PageSize = n
+	}
+
+	page, err := h.Numbers.SearchNumbers(c.Request.Context(), workspaceID, q)
+	if err != nil {
+		switch {
+		case errors.Is(err, numbers.ErrInvalidArgument), errors.Is(err, numbers.ErrInvalidPageToken):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, numbers.ErrPurchaseNotAllowed):
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, numbers.ErrSearchUnsupported):
+			c.AbortWithStatusJSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+		default:
+			c.AbortWithStatusJSON(http.StatusBadGateway, gin.H{"error": "number search failed"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// BuyNumber purchases a number subject to the workspace purchase policy.
+func (h Handlers) BuyNumber(c *gin.Context) {
+	if h.Numbers == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "numbers not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+
+	var req numbers.PurchaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+
+	res, err := h.Numbers.BuyNumber(c.Request.Context(), workspaceID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, numbers.ErrInvalidArgument):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, numbers.ErrPurchaseNotAllowed), errors.Is(err, numbers.ErrRegulatoryBundleRequired):
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.AbortWithStatusJSON(http.StatusBadGateway, gin.H{"error": "number purchase failed"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// ListNumbers returns the workspace's number inventory with capabilities and assignments.
+func (h Handlers) ListNumbers(c *gin.Context) {
+	if h.Numbers == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "numbers not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	items, err := h.Numbers.ListNumbers(c.Request.Context(), workspaceID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "number listing failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"numbers": items})
+}
+
+// AssignNumber attaches a number to a voice campaign and/or messaging profile after
+// checking its capabilities. Honours If-Match.
+func (h Handlers) AssignNumber(c *gin.Context) {
+	if h.Numbers == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "numbers not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	var req numbers.NumberAssignment
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	req.IfVersion = version
+	out, err := h.Numbers.AssignNumber(c.Request.Context(), workspaceID, c.Param("number"), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, numbers.ErrInvalidArgument):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, numbers.ErrNumberNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "number not found"})
+		case errors.Is(err, numbers.ErrCapabilityMismatch):
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, numbers.ErrVersionConflict):
+			abortVersionConflict(c)
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "number assignment failed"})
+		}
+		return
+	}
+	setETag(c, out.Version)
+	c.JSON(http.StatusOK, out)
+}
+
+// ImportNumber adds a number already owned at the provider to the inventory and
+// configures its webhooks.
+func (h Handlers) ImportNumber(c *gin.Context) {
+	if h.Numbers == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "numbers not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	var req numbers.ImportNumberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	out, err := h.Numbers.ImportNumber(c.Request.Context(), workspaceID, req)
+	if err != nil {
+		if errors.Is(err, numbers.ErrInvalidArgument) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, numbers.ErrVersionConflict) {
+			abortVersionConflict(c)
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "number import failed"})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// ConfigureNumberWebhooks points a number's provider callbacks at this environment.
+func (h Handlers) ConfigureNumberWebhooks(c *gin.Context) {
+	if h.Numbers == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "numbers not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	out, err := h.Numbers.ConfigureWebhooks(c.Request.Context(), workspaceID, c.Param("number"))
+	if err != nil {
+		switch {
+		case errors.Is(err, numbers.ErrInvalidArgument):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, numbers.ErrNumberNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "number not found"})
+		case errors.Is(err, numbers.ErrWebhooksUnsupported):
+			c.AbortWithStatusJSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+		case errors.Is(err, numbers.ErrVersionConflict):
+			abortVersionConflict(c)
+		default:
+			c.AbortWithStatusJSON(http.StatusBadGateway, gin.H{"error": "webhook configuration failed"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// GetNumberConfigDrift returns the latest provider config drift report
+// (platform-wide, see numbers.ConfigDriftJob). RBAC: super_admin.
+func (h Handlers) GetNumberConfigDrift(c *gin.Context) {
+	if h.NumberDrift == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "number drift reports not configured"})
+		return
+	}
+	rep, ok, err := h.NumberDrift.LatestDriftReport(c.Request.Context())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "drift report lookup failed"})
+		return
+	}
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "no drift report yet"})
+		return
+	}
+	c.JSON(http.StatusOK, rep)
+}
+
+// SetNumberPurchasePolicy replaces the workspace purchase policy.
+// Honours If-Match (or "version" in the body). RBAC: owner or super_admin.
+func (h Handlers) SetNumberPurchasePolicy(c *gin.Context) {
+	if h.Numbers == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "numbers not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	var p numbers.PurchasePolicy
+	if err := c.ShouldBindJSON(&p); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	// Never trust workspace_id from the body.
+	p.WorkspaceID = workspaceID
+	if version != 0 {
+		p.Version = version
+	}
+
+	out, err := h.Numbers.SetPolicy(c.Request.Context(), p)
+	if err != nil {
+		if errors.Is(err, numbers.ErrVersionConflict) {
+			abortVersionConflict(c)
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	setETag(c, out.Version)
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,31 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Permissions ---
+
+// MyPermissions returns the actions the current token can perform, derived from the
+// RBAC permission matrix, so frontends do not duplicate RBAC rules client-side.
+func (h Handlers) MyPermissions(c *gin.Context) {
+	role, err := auth.Role(c.Request.Context())
+	if err != nil || role == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "role required"})
+		return
+	}
+	wid, _ := auth.WorkspaceID(c.Request.Context())
+	perms := rbac.PermissionsFor(role)
+
+	// Hidden roles are never disclosed by name.
+	shownRole := role
+	if rbac.IsHiddenRole(role) {
+		shownRole = ""
+	}
+	c.JSON(http.StatusOK, gin.H{"workspace_id": wid, "role": shownRole, "permissions": perms})
+}
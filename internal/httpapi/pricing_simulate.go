@@ -0,0 +1,63 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/pricing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Pricing simulation ---
+
+type simulatePricingRequest struct {
+	// WorkspaceID defaults to the caller's workspace.
+	WorkspaceID string `json:"workspace_id,omitempty"`
+
+	From    time.Time            `json:"from"`
+	To      time.Time            `json:"to"`
+	Changes []pricing.RateChange `json:"changes"`
+}
+
+// SimulatePricing re-prices a historical period under a hypothetical rate change and
+// returns recomputed vs actual costs. Nothing is published. RBAC: super_admin.
+func (h Handlers) SimulatePricing(c *gin.Context) {
+	if h.Pricing == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "pricing not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	var req simulatePricingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	if req.WorkspaceID != "" {
+		workspaceID = req.WorkspaceID
+	}
+	out, err := h.Pricing.Simulate(c.Request.Context(), pricing.SimulationRequest{
+		WorkspaceID: workspaceID,
+		From:        req.From,
+		To:          req.To,
+		Changes:     req.Changes,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pricing.ErrInvalidPricingReq):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid simulation request"})
+		case errors.Is(err, pricing.ErrSimulationTooLarge):
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "simulation failed"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,64 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/routing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Routing decision log ---
+
+// ListRoutingDecisions returns the workspace's recorded routing decisions, newest
+// first. Filters: campaign_id, provider_call_id, action, reason, since and until
+// (RFC3339, until exclusive), limit. RBAC: owner/network_operator/super_admin.
+func (h Handlers) ListRoutingDecisions(c *gin.Context) {
+	if h.Routing == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "routing not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	f := routing.DecisionFilter{
+		CampaignID:     c.Query("campaign_id"),
+		ProviderCallID: c.Query("provider_call_id"),
+		Action:         routing.Action(c.Query("action")),
+		Reason:         c.Query("reason"),
+	}
+	for param, dst := range map[string]*time.Time{"since": &f.Since, "until": &f.Until} {
+		if v := c.Query(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid " + param})
+				return
+			}
+			*dst = t
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		f.Limit = n
+	}
+	out, err := h.Routing.ListDecisions(c.Request.Context(), workspaceID, f)
+	if err != nil {
+		if errors.Is(err, routing.ErrInvalidDecisionFilter) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "routing decisions failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"decisions": out})
+}
@@ -0,0 +1,87 @@
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/routing"
+	"telecom-platform/internal/telephony"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Routing dry run ---
+
+type routingDryRunRequest struct {
+	// WorkspaceID targets another workspace; defaults to the caller's.
+	WorkspaceID string `json:"workspace_id"`
+
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	Time time.Time `json:"time"` // evaluation time (RFC3339); defaults to now
+	Role string    `json:"role"` // simulated actor role
+
+	CampaignID     string `json:"campaign_id"`
+	WalletID       string `json:"wallet_id,omitempty"`
+	EstimatedMinor int64  `json:"estimated_minor,omitempty"`
+	Currency       string `json:"currency,omitempty"`
+
+	// Collected simulates IVR input already gathered (e.g. {"language":"2","zip":"94107"}).
+	Collected map[string]string `json:"collected,omitempty"`
+}
+
+// RoutingDryRun evaluates a synthetic inbound call and returns the decision trace
+// without reserving pacing budget, writing audit events or scheduling callbacks.
+// RBAC: super_admin.
+func (h Handlers) RoutingDryRun(c *gin.Context) {
+	if h.Routing == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "routing not configured"})
+		return
+	}
+	callerWorkspace, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || callerWorkspace == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+
+	var req routingDryRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	workspaceID := strings.TrimSpace(req.WorkspaceID)
+	if workspaceID == "" {
+		workspaceID = callerWorkspace
+	}
+	if strings.TrimSpace(req.From) == "" || strings.TrimSpace(req.To) == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "from and to required"})
+		return
+	}
+
+	at := req.Time
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	out, err := h.Routing.DryRun(c.Request.Context(), routing.RouteInput{
+		WorkspaceID:    workspaceID,
+		CampaignID:     req.CampaignID,
+		ActorRole:      req.Role,
+		WalletID:       req.WalletID,
+		EstimatedMinor: req.EstimatedMinor,
+		Currency:       req.Currency,
+		Inbound: telephony.InboundCallRequest{
+			WorkspaceID: workspaceID,
+			From:        req.From,
+			To:          req.To,
+			OccurredAt:  at,
+			Collected:   req.Collected,
+		},
+	}, at)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "routing dry run failed"})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
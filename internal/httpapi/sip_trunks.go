@@ -0,0 +1,120 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/telephony"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- SIP trunks ---
+
+type putSIPTrunkRequest struct {
+	Name      string                 `json:"name"`
+	Host      string                 `json:"host"`
+	Transport telephony.SIPTransport `json:"transport"`
+	SRTP      telephony.SRTPPolicy   `json:"srtp"`
+	// EmergencyPolicy is required: block or route.
+	EmergencyPolicy telephony.EmergencyPolicy `json:"emergency_policy"`
+}
+
+// ListSIPTrunks returns the workspace's SIP trunks. RBAC: owner/super_admin.
+func (h Handlers) ListSIPTrunks(c *gin.Context) {
+	workspaceID, ok := h.sipTrunkCaller(c)
+	if !ok {
+		return
+	}
+	out, err := h.SIPTrunks.ListTrunks(c.Request.Context(), workspaceID)
+	if err != nil {
+		abortSIPTrunkError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"trunks": out})
+}
+
+// PutSIPTrunk creates or updates a trunk's transport, SRTP and emergency call
+// settings. Changing transport or SRTP disables the trunk until it is enabled
+// again. RBAC: owner/super_admin.
+func (h Handlers) PutSIPTrunk(c *gin.Context) {
+	workspaceID, ok := h.sipTrunkCaller(c)
+	if !ok {
+		return
+	}
+	var req putSIPTrunkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	t, err := h.SIPTrunks.PutTrunk(c.Request.Context(), telephony.SIPTrunk{
+		ID:          c.Param("trunk_id"),
+		WorkspaceID: workspaceID,
+		Name:        req.Name,
+		Host:        req.Host,
+		Transport:   req.Transport,
+		SRTP:        req.SRTP,
+		Emergency:   req.EmergencyPolicy,
+	})
+	if err != nil {
+		abortSIPTrunkError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// EnableSIPTrunk verifies a TLS trunk's secure path and enables the trunk.
+// RBAC: owner/super_admin.
+func (h Handlers) EnableSIPTrunk(c *gin.Context) {
+	workspaceID, ok := h.sipTrunkCaller(c)
+	if !ok {
+		return
+	}
+	t, err := h.SIPTrunks.EnableTrunk(c.Request.Context(), workspaceID, c.Param("trunk_id"))
+	if err != nil {
+		abortSIPTrunkError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// DisableSIPTrunk stops using a trunk. RBAC: owner/super_admin.
+func (h Handlers) DisableSIPTrunk(c *gin.Context) {
+	workspaceID, ok := h.sipTrunkCaller(c)
+	if !ok {
+		return
+	}
+	t, err := h.SIPTrunks.DisableTrunk(c.Request.Context(), workspaceID, c.Param("trunk_id"))
+	if err != nil {
+		abortSIPTrunkError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+func (h Handlers) sipTrunkCaller(c *gin.Context) (string, bool) {
+	if h.SIPTrunks == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "sip trunks not configured"})
+		return "", false
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return "", false
+	}
+	return workspaceID, true
+}
+
+func abortSIPTrunkError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, telephony.ErrInvalidTrunk):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, telephony.ErrTrunkNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "sip trunk not found"})
+	case errors.Is(err, telephony.ErrSecurePathFailed):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "secure path check failed"})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "sip trunk request failed"})
+	}
+}
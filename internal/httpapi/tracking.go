@@ -0,0 +1,127 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/numbers"
+	"telecom-platform/internal/reporting"
+	"telecom-platform/internal/tracking"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Visit tracking and attribution ---
+
+// StartTrackingSession records a website visit with its UTM parameters and click
+// ids and returns the tracking number to display.
+// Body: {"pool_id":"...","visitor_id":"...","landing_url":"https://...?utm_source=...","referrer":"..."}.
+// For server-side integrations holding a workspace-scoped token; page scripts use
+// StartPublicTrackingSession.
+func (h Handlers) StartTrackingSession(c *gin.Context) {
+	if h.Tracking == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "tracking not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	var req tracking.StartSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	sess, err := h.Tracking.StartSession(c.Request.Context(), workspaceID, req)
+	if err != nil {
+		abortTrackingError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, sess)
+}
+
+// StartPublicTrackingSession is StartTrackingSession for the number-insertion
+// script. It is unauthenticated: the pool's publishable site key in the path
+// selects the workspace and pool, and any pool_id in the body is ignored.
+// Routes mount it behind per-client rate limits and replay protection, so the
+// script sends X-Request-Timestamp and a fresh X-Request-Nonce with each call.
+//
+// Only the session id, number and expiry are returned; the attribution stays
+// server-side.
+func (h Handlers) StartPublicTrackingSession(c *gin.Context) {
+	if h.Tracking == nil || h.Numbers == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "tracking not configured"})
+		return
+	}
+	pool, err := h.Numbers.PoolBySiteKey(c.Request.Context(), c.Param("site_key"))
+	if err != nil {
+		if errors.Is(err, numbers.ErrPoolNotFound) || errors.Is(err, numbers.ErrInvalidArgument) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "unknown site key"})
+			return
+		}
+		abortPoolError(c, err)
+		return
+	}
+	var req tracking.StartSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	req.PoolID = pool.ID
+	sess, err := h.Tracking.StartSession(c.Request.Context(), pool.WorkspaceID, req)
+	if err != nil {
+		abortTrackingError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"session_id":      sess.ID,
+		"tracking_number": sess.TrackingNumber,
+		"expires_at":      sess.ExpiresAt,
+	})
+}
+
+func abortTrackingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, tracking.ErrInvalidArgument):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "pool_id and visitor_id required; landing_url must be a valid url"})
+	case errors.Is(err, numbers.ErrPoolNotFound), errors.Is(err, numbers.ErrPoolExhausted), errors.Is(err, numbers.ErrInvalidArgument):
+		abortPoolError(c, err)
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "session start failed"})
+	}
+}
+
+// AttributionReport returns calls, conversions and usage spend per
+// source/medium/campaign. RBAC: owner/analyst/finance/super_admin.
+//
+// Query: from, to (RFC3339) or from_date, to_date (YYYY-MM-DD, local to timezone);
+// timezone, campaign_id, currency (optional).
+func (h Handlers) AttributionReport(c *gin.Context) {
+	if h.Reporting == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "reporting not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	rng, ok := reportRange(c)
+	if !ok {
+		return
+	}
+	out, err := h.Reporting.AttributionReport(c.Request.Context(), reporting.AttributionRequest{
+		WorkspaceID: workspaceID,
+		Range:       rng,
+		CampaignID:  c.Query("campaign_id"),
+		Currency:    c.Query("currency"),
+		Timezone:    c.Query("timezone"),
+	})
+	if err != nil {
+		abortReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,101 @@
+package httpapi
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/reporting"
+	"telecom-platform/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Wallboard ---
+
+// wallboardInterval is the stream's push rate, matching a 1-second wallboard refresh.
+const wallboardInterval = time.Second
+
+// Wallboard returns live per-campaign stats (callers waiting, longest wait,
+// agents available, calls in progress, today's conversions) from Redis.
+// RBAC: owner/analyst/finance/super_admin.
+//
+// Query: campaign_id (optional).
+func (h Handlers) Wallboard(c *gin.Context) {
+	workspaceID, ok := h.wallboardCaller(c)
+	if !ok {
+		return
+	}
+	out, err := h.Reporting.Wallboard(c.Request.Context(), workspaceID, c.Query("campaign_id"))
+	if err != nil {
+		abortWallboardError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, out)
+}
+
+// WallboardStream pushes the Wallboard snapshot as a server-sent "wallboard"
+// event every second until the client disconnects. A failed read is sent as an
+// "error" event and the stream keeps going. RBAC: owner/analyst/finance/super_admin.
+//
+// Query: campaign_id (optional).
+func (h Handlers) WallboardStream(c *gin.Context) {
+	workspaceID, ok := h.wallboardCaller(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	campaignID := c.Query("campaign_id")
+
+	// Fail fast on a bad request before committing to a stream.
+	first, err := h.Reporting.Wallboard(ctx, workspaceID, campaignID)
+	if err != nil {
+		abortWallboardError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("wallboard", first)
+
+	ticker := time.NewTicker(wallboardInterval)
+	defer ticker.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+		out, err := h.Reporting.Wallboard(ctx, workspaceID, campaignID)
+		if err != nil {
+			logger.From(ctx).Warn("wallboard read failed", "workspace_id", workspaceID, "err", err)
+			c.SSEvent("error", gin.H{"error": "wallboard unavailable"})
+			return true
+		}
+		c.SSEvent("wallboard", out)
+		return true
+	})
+}
+
+func (h Handlers) wallboardCaller(c *gin.Context) (string, bool) {
+	if h.Reporting == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "reporting not configured"})
+		return "", false
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return "", false
+	}
+	return workspaceID, true
+}
+
+func abortWallboardError(c *gin.Context, err error) {
+	if errors.Is(err, reporting.ErrInvalidRequest) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid workspace timezone"})
+		return
+	}
+	c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallboard failed"})
+}
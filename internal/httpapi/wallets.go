@@ -0,0 +1,531 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"telecom-platform/internal/approvals"
+	"telecom-platform/internal/audit"
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/wallet"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Wallet lifecycle ---
+
+// CreateWallet opens a wallet for the workspace. Body: {"currency":"USD"}.
+// RBAC: owner/super_admin.
+func (h Handlers) CreateWallet(c *gin.Context) {
+	if h.Wallet == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	var req wallet.CreateWalletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	w, err := h.Wallet.CreateWallet(c.Request.Context(), workspaceID, req)
+	if err != nil {
+		abortWalletError(c, err, "wallet create failed")
+		return
+	}
+	c.JSON(http.StatusCreated, w)
+}
+
+// ListWallets lists the workspace's wallets, including disabled and closed ones.
+func (h Handlers) ListWallets(c *gin.Context) {
+	if h.Wallet == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	list, err := h.Wallet.ListWallets(c.Request.Context(), workspaceID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet list failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"wallets": list})
+}
+
+// DisableWallet stops spending from a wallet; credits still land.
+// RBAC: owner/super_admin.
+func (h Handlers) DisableWallet(c *gin.Context) {
+	h.transitionWallet(c, (*wallet.Service).DisableWallet)
+}
+
+// EnableWallet re-activates a disabled wallet.
+// RBAC: owner/super_admin.
+func (h Handlers) EnableWallet(c *gin.Context) {
+	h.transitionWallet(c, (*wallet.Service).EnableWallet)
+}
+
+// CloseWallet permanently closes a wallet; 409 unless its balance is zero with nothing held.
+// RBAC: owner/super_admin.
+func (h Handlers) CloseWallet(c *gin.Context) {
+	h.transitionWallet(c, (*wallet.Service).CloseWallet)
+}
+
+func (h Handlers) transitionWallet(c *gin.Context, fn func(*wallet.Service, context.Context, string, string) (wallet.Wallet, error)) {
+	if h.Wallet == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	w, err := fn(h.Wallet, c.Request.Context(), workspaceID, c.Param("wallet_id"))
+	if err != nil {
+		abortWalletError(c, err, "wallet update failed")
+		return
+	}
+	c.JSON(http.StatusOK, w)
+}
+
+// --- Wallet credit limit ---
+
+// SetWalletCreditLimit sets how far below zero the wallet may go.
+// Body: {"credit_limit_minor":10000,"reason":"..."}; 0 disables overdraft.
+// RBAC: super_admin.
+func (h Handlers) SetWalletCreditLimit(c *gin.Context) {
+	if h.Wallet == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	adminUserID, _ := auth.UserID(c.Request.Context())
+	adminRole, _ := auth.Role(c.Request.Context())
+
+	var req wallet.SetCreditLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	w, err := h.Wallet.SetCreditLimit(c.Request.Context(), workspaceID, c.Param("wallet_id"), adminUserID, adminRole, req)
+	if err != nil {
+		abortWalletError(c, err, "credit limit update failed")
+		return
+	}
+	c.JSON(http.StatusOK, w)
+}
+
+// --- Wallet freezes ---
+
+// FreezeWallet blocks all money movement on the wallet until an admin unfreezes it.
+// With an approval queue configured the freeze is queued for a second admin
+// (202 with the approval). Body: {"reason":"..."}. RBAC: super_admin.
+func (h Handlers) FreezeWallet(c *gin.Context) {
+	h.freezeWallet(c, approvals.ActionWalletFreeze, (*wallet.Service).FreezeWallet, "wallet freeze failed")
+}
+
+// UnfreezeWallet lifts a freeze and restores the wallet's previous status.
+// Body: {"reason":"..."}. RBAC: super_admin.
+func (h Handlers) UnfreezeWallet(c *gin.Context) {
+	h.freezeWallet(c, "", (*wallet.Service).UnfreezeWallet, "wallet unfreeze failed")
+}
+
+func (h Handlers) freezeWallet(c *gin.Context, action approvals.ActionType, fn func(*wallet.Service, context.Context, string, string, string, string, wallet.FreezeWalletRequest) (wallet.Wallet, error), fallback string) {
+	if h.Wallet == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	adminUserID, _ := auth.UserID(c.Request.Context())
+	adminRole, _ := auth.Role(c.Request.Context())
+
+	var req wallet.FreezeWalletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	if action != "" && h.Approvals != nil && h.Approvals.RequiresApproval(action, 0) {
+		payload, _ := json.Marshal(approvals.WalletFreezePayload{WalletID: c.Param("wallet_id"), Reason: req.Reason})
+		ar, err := h.Approvals.Submit(c.Request.Context(), workspaceID, adminUserID, adminRole, approvals.SubmitRequest{
+			Action:  action,
+			Payload: string(payload),
+			Reason:  req.Reason,
+		})
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"approval": ar})
+		return
+	}
+	w, err := fn(h.Wallet, c.Request.Context(), workspaceID, c.Param("wallet_id"), adminUserID, adminRole, req)
+	if err != nil {
+		abortWalletError(c, err, fallback)
+		return
+	}
+	c.JSON(http.StatusOK, w)
+}
+
+// --- Wallet reversals ---
+
+// ReverseLedgerEntry refunds a debit or claws back a credit, fully or in part, by
+// posting a reversal linked to the original entry.
+// Body: {"amount_minor":500,"reason":"...","idempotency_key":"..."}; amount_minor 0
+// (or omitted) reverses the rest. RBAC: super_admin.
+func (h Handlers) ReverseLedgerEntry(c *gin.Context) {
+	if h.Wallet == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	adminUserID, _ := auth.UserID(c.Request.Context())
+	adminRole, _ := auth.Role(c.Request.Context())
+
+	var req wallet.ReverseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	_, entry, bal, err := h.Wallet.Reverse(c.Request.Context(), workspaceID, c.Param("wallet_id"), c.Param("ledger_id"), adminUserID, adminRole, req)
+	if err != nil {
+		abortWalletError(c, err, "reversal failed")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"reversal": entry, "balance": bal})
+}
+
+// --- Wallet spend caps ---
+
+// PutSpendCaps sets the wallet's daily and monthly spend caps (UTC periods).
+// Body: {"daily_cap_minor":5000,"monthly_cap_minor":100000}; 0 removes a cap.
+// RBAC: owner/super_admin.
+func (h Handlers) PutSpendCaps(c *gin.Context) {
+	if h.Wallet == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	var req wallet.SetSpendCapsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	caps, err := h.Wallet.SetSpendCaps(c.Request.Context(), workspaceID, c.Param("wallet_id"), req)
+	if err != nil {
+		abortWalletError(c, err, "spend cap update failed")
+		return
+	}
+	c.JSON(http.StatusOK, caps)
+}
+
+// GetSpendCapUtilization returns the wallet's caps against spend (and open holds)
+// in the current UTC day and month. RBAC: owner/super_admin (admin group).
+func (h Handlers) GetSpendCapUtilization(c *gin.Context) {
+	if h.Wallet == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	u, err := h.Wallet.SpendCapUtilization(c.Request.Context(), workspaceID, c.Param("wallet_id"))
+	if err != nil {
+		abortWalletError(c, err, "spend cap lookup failed")
+		return
+	}
+	c.JSON(http.StatusOK, u)
+}
+
+// --- Wallet auto top-up ---
+
+// GetAutoTopUp returns the wallet's auto top-up rule; 404 when none is configured.
+func (h Handlers) GetAutoTopUp(c *gin.Context) {
+	if h.Wallet == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	cfg, ok, err := h.Wallet.GetAutoTopUp(c.Request.Context(), workspaceID, c.Param("wallet_id"))
+	if err != nil {
+		abortWalletError(c, err, "auto top-up lookup failed")
+		return
+	}
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "auto top-up not configured"})
+		return
+	}
+	c.JSON(http.StatusOK, cfg)
+}
+
+// PutAutoTopUp replaces the wallet's auto top-up rule.
+// Body: {"enabled":true,"threshold_minor":500,"topup_amount_minor":2000,"payment_method_ref":"pm_..."}.
+// RBAC: owner/super_admin.
+func (h Handlers) PutAutoTopUp(c *gin.Context) {
+	if h.Wallet == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	var req struct {
+		Enabled          bool   `json:"enabled"`
+		ThresholdMinor   int64  `json:"threshold_minor"`
+		TopUpAmountMinor int64  `json:"topup_amount_minor"`
+		PaymentMethodRef string `json:"payment_method_ref"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	walletID := c.Param("wallet_id")
+	// Surface a 404 for unknown wallets instead of storing an orphan rule.
+	if _, err := h.Wallet.GetBalance(c.Request.Context(), workspaceID, walletID); err != nil {
+		abortWalletError(c, err, "auto top-up update failed")
+		return
+	}
+	cfg, err := h.Wallet.SetAutoTopUp(c.Request.Context(), wallet.AutoTopUpConfig{
+		WorkspaceID:      workspaceID,
+		WalletID:         walletID,
+		Enabled:          req.Enabled,
+		ThresholdMinor:   req.ThresholdMinor,
+		TopUpAmountMinor: req.TopUpAmountMinor,
+		PaymentMethodRef: req.PaymentMethodRef,
+	})
+	if err != nil {
+		abortWalletError(c, err, "auto top-up update failed")
+		return
+	}
+	c.JSON(http.StatusOK, cfg)
+}
+
+// --- Wallet balance history ---
+
+// GetWalletBalanceAt returns a wallet's balance as of a past time (entries created
+// before it). Query: at (RFC 3339, required). RBAC: owner/finance/read_only/super_admin.
+func (h Handlers) GetWalletBalanceAt(c *gin.Context) {
+	if h.Wallet == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	at, err := time.Parse(time.RFC3339, c.Query("at"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid at"})
+		return
+	}
+	bal, err := h.Wallet.BalanceAt(c.Request.Context(), workspaceID, c.Param("wallet_id"), at)
+	if err != nil {
+		abortWalletError(c, err, "balance lookup failed")
+		return
+	}
+	c.JSON(http.StatusOK, bal)
+}
+
+// --- Wallet metrics ---
+
+// WalletMetrics serves money operation metrics and SLO burn rates in the
+// Prometheus text format (platform-wide, not per workspace). RBAC: super_admin.
+func (h Handlers) WalletMetrics(c *gin.Context) {
+	if h.Wallet == nil || h.Wallet.Metrics == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet metrics not configured"})
+		return
+	}
+	var buf bytes.Buffer
+	if err := h.Wallet.Metrics.WritePrometheus(&buf); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet metrics failed"})
+		return
+	}
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", buf.Bytes())
+}
+
+// --- Wallet ledger ---
+
+// ListWalletLedger pages through a wallet's ledger, newest first.
+// Query: from/to (RFC 3339, [from, to)), type (comma-separated), external_ref,
+// metadata.<key>=<value>, limit (default 50, max 500), cursor (next_cursor of the previous page).
+// RBAC: owner/finance/read_only/super_admin.
+func (h Handlers) ListWalletLedger(c *gin.Context) {
+	if h.Wallet == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	q := wallet.LedgerQuery{
+		Types:       wallet.ParseLedgerTypes(c.Query("type")),
+		ExternalRef: c.Query("external_ref"),
+		Cursor:      c.Query("cursor"),
+	}
+	for name, dst := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+		if v := c.Query(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+				return
+			}
+			*dst = t.UTC()
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		q.Limit = n
+	}
+	q.Metadata, err = wallet.ParseMetadataFilter(c.Request.URL.Query())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.Wallet.ListLedger(c.Request.Context(), workspaceID, c.Param("wallet_id"), q)
+	if err != nil {
+		abortWalletError(c, err, "ledger lookup failed")
+		return
+	}
+	h.logAccess(c, audit.Access{
+		WorkspaceID: workspaceID,
+		Resource:    audit.AccessLedgerExport,
+		ResourceID:  c.Param("wallet_id"),
+		Action:      "list",
+		WalletID:    c.Param("wallet_id"),
+	})
+	c.JSON(http.StatusOK, page)
+}
+
+// --- Wallet ledger search ---
+
+// SearchWalletLedger searches the workspace's ledger for support investigations,
+// newest first. Query: wallet_id, from/to (RFC 3339, [from, to)), type
+// (comma-separated), external_ref_prefix, metadata.<key>=<value>, metadata_key
+// (comma-separated keys that must exist), min_amount/max_amount (minor units,
+// absolute), limit (default 50, max 500), cursor. RBAC: owner/super_admin.
+func (h Handlers) SearchWalletLedger(c *gin.Context) {
+	if h.Wallet == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "wallet not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	q := wallet.LedgerSearch{
+		WalletID:          c.Query("wallet_id"),
+		Types:             wallet.ParseLedgerTypes(c.Query("type")),
+		ExternalRefPrefix: c.Query("external_ref_prefix"),
+		MetadataKeys:      wallet.ParseMetadataKeys(c.Query("metadata_key")),
+		Cursor:            c.Query("cursor"),
+	}
+	for name, dst := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+		if v := c.Query(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+				return
+			}
+			*dst = t.UTC()
+		}
+	}
+	for name, dst := range map[string]*int64{"min_amount": &q.MinAmountMinor, "max_amount": &q.MaxAmountMinor} {
+		if v := c.Query(name); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n < 0 {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+				return
+			}
+			*dst = n
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		q.Limit = n
+	}
+	q.Metadata, err = wallet.ParseMetadataFilter(c.Request.URL.Query())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.Wallet.SearchLedger(c.Request.Context(), workspaceID, q)
+	if err != nil {
+		abortWalletError(c, err, "ledger search failed")
+		return
+	}
+	h.logAccess(c, audit.Access{
+		WorkspaceID: workspaceID,
+		Resource:    audit.AccessLedgerExport,
+		ResourceID:  q.WalletID,
+		Action:      "search",
+		WalletID:    q.WalletID,
+	})
+	c.JSON(http.StatusOK, page)
+}
+
+func abortWalletError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, wallet.ErrInvalidArgument), errors.Is(err, wallet.ErrInvalidAutoTopUp):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, wallet.ErrBalanceArchived):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, wallet.ErrNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "wallet not found"})
+	case errors.Is(err, wallet.ErrWalletNotEmpty), errors.Is(err, wallet.ErrWalletClosed), errors.Is(err, wallet.ErrWalletNotActive), errors.Is(err, wallet.ErrAlreadyReversed):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, wallet.ErrIdempotencyConflict):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, wallet.ErrInsufficientFunds):
+		c.AbortWithStatusJSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
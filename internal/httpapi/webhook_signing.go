@@ -0,0 +1,100 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/webhooks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Webhook signing keys ---
+
+// ListWebhookSigningKeys lists the workspace's signing keys (never their secrets).
+// RBAC: owner/super_admin.
+func (h Handlers) ListWebhookSigningKeys(c *gin.Context) {
+	if h.Webhooks == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "webhooks not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	keys, err := h.Webhooks.Keys(c.Request.Context(), workspaceID)
+	if err != nil {
+		abortWebhooksError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"keys": keys, "signature_header": webhooks.SignatureHeader})
+}
+
+// RotateWebhookSigningKey creates a new signing secret and returns it once. The
+// previous secret keeps signing for the grace period. RBAC: owner/super_admin.
+func (h Handlers) RotateWebhookSigningKey(c *gin.Context) {
+	if h.Webhooks == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "webhooks not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	k, err := h.Webhooks.Rotate(c.Request.Context(), workspaceID)
+	if err != nil {
+		abortWebhooksError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, k)
+}
+
+// SendWebhookTest posts a signed webhook.test event to the given https URL.
+// Body: {"url":"https://..."}. RBAC: owner/super_admin.
+func (h Handlers) SendWebhookTest(c *gin.Context) {
+	if h.Webhooks == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "webhooks not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	var req struct {
+		URL string `json:"url"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortBodyError(c, err, "invalid json")
+		return
+	}
+	res, err := h.Webhooks.SendTest(c.Request.Context(), workspaceID, req.URL)
+	if err != nil {
+		abortWebhooksError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetWebhookVerification returns receiver-side signature verification examples.
+func (h Handlers) GetWebhookVerification(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"signature_header": webhooks.SignatureHeader,
+		"scheme":           "t=<unix seconds>,v1=<hex HMAC-SHA256(secret, t + \".\" + raw body)>; accept when any v1 matches",
+		"examples":         webhooks.VerificationExamples(),
+	})
+}
+
+func abortWebhooksError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, webhooks.ErrInvalidArgument):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, webhooks.ErrNoSigningKey):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "rotate in a signing key first"})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "webhooks request failed"})
+	}
+}
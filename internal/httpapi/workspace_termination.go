@@ -0,0 +1,99 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/workspaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Workspace termination ---
+
+type requestTerminationRequest struct {
+	Reason string `json:"reason"`
+}
+
+// GetTermination returns the workspace's termination status / completion report.
+func (h Handlers) GetTermination(c *gin.Context) {
+	if h.Workspaces == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "workspaces not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	out, err := h.Workspaces.Status(c.Request.Context(), workspaceID)
+	if err != nil {
+		writeTerminationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// RequestTermination suspends the workspace and schedules the purge. RBAC: owner/super_admin.
+func (h Handlers) RequestTermination(c *gin.Context) {
+	if h.Workspaces == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "workspaces not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	uid, _ := auth.UserID(c.Request.Context())
+	role, _ := auth.Role(c.Request.Context())
+
+	var req requestTerminationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			abortBodyError(c, err, "invalid json")
+			return
+		}
+	}
+	out, err := h.Workspaces.RequestTermination(c.Request.Context(), workspaceID, uid, role, req.Reason)
+	if err != nil {
+		writeTerminationError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, out)
+}
+
+// CancelTermination restores the workspace during the grace period. RBAC: owner/super_admin.
+func (h Handlers) CancelTermination(c *gin.Context) {
+	if h.Workspaces == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "workspaces not configured"})
+		return
+	}
+	workspaceID, err := auth.WorkspaceID(c.Request.Context())
+	if err != nil || workspaceID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "workspace_id required"})
+		return
+	}
+	uid, _ := auth.UserID(c.Request.Context())
+	role, _ := auth.Role(c.Request.Context())
+	out, err := h.Workspaces.CancelTermination(c.Request.Context(), workspaceID, uid, role)
+	if err != nil {
+		writeTerminationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func writeTerminationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, workspaces.ErrInvalidArgument):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, workspaces.ErrNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "no termination in progress"})
+	case errors.Is(err, workspaces.ErrAlreadyRequested), errors.Is(err, workspaces.ErrGracePeriodOver):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "termination request failed"})
+	}
+}
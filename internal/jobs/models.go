@@ -0,0 +1,65 @@
+package jobs
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Kind names the async operation a job runs. Handlers pick the kind; the jobs
+// package only tracks lifecycle and delivery.
+type Kind string
+
+const (
+	KindExport         Kind = "export"
+	KindImport         Kind = "import"
+	KindPurge          Kind = "purge"
+	KindReconciliation Kind = "reconciliation"
+)
+
+// Status is the job lifecycle: queued -> running -> succeeded | failed.
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Terminal reports whether no further transitions will happen.
+func (s Status) Terminal() bool {
+	return s == StatusSucceeded || s == StatusFailed
+}
+
+// Job is one async operation as exposed by GET /v1/jobs/:job_id.
+type Job struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	Kind        Kind   `json:"kind" db:"kind"`
+	CreatedBy   string `json:"created_by" db:"created_by"`
+
+	Status Status `json:"status" db:"status"`
+	// Progress is 0-100 when the runner reports it.
+	Progress int `json:"progress" db:"progress"`
+	// Result is the kind-specific outcome (e.g. an import batch summary).
+	Result json.RawMessage `json:"result,omitempty" db:"result"`
+	Error  string          `json:"error,omitempty" db:"error"`
+
+	// NotifyURL receives a POST with the job once it is terminal.
+	NotifyURL       string     `json:"notify_url,omitempty" db:"notify_url"`
+	NotifyAttempts  int        `json:"notify_attempts,omitempty" db:"notify_attempts"`
+	NotifiedAt      *time.Time `json:"notified_at,omitempty" db:"notified_at"`
+	NotifyLastError string     `json:"notify_last_error,omitempty" db:"notify_last_error"`
+
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty" db:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+// ListFilter narrows List. Empty fields match everything.
+type ListFilter struct {
+	Kind   Kind
+	Status Status
+	Limit  int
+}
@@ -0,0 +1,70 @@
+package jobs
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryStore is a simple in-memory Store useful for tests and single-instance dev.
+// It is not intended for production use.
+
+type MemoryStore struct {
+	mu    sync.Mutex
+	items map[string]Job
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: map[string]Job{}}
+}
+
+func (s *MemoryStore) Create(ctx context.Context, j Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[j.ID] = j
+	return nil
+}
+
+func (s *MemoryStore) Update(ctx context.Context, j Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur, ok := s.items[j.ID]
+	if !ok || cur.WorkspaceID != j.WorkspaceID {
+		return ErrNotFound
+	}
+	s.items[j.ID] = j
+	return nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, workspaceID, jobID string) (Job, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.items[jobID]
+	if !ok || j.WorkspaceID != workspaceID {
+		return Job{}, false, nil
+	}
+	return j, true, nil
+}
+
+func (s *MemoryStore) List(ctx context.Context, workspaceID string, f ListFilter) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Job
+	for _, j := range s.items {
+		if j.WorkspaceID != workspaceID {
+			continue
+		}
+		if f.Kind != "" && j.Kind != f.Kind {
+			continue
+		}
+		if f.Status != "" && j.Status != f.Status {
+			continue
+		}
+		out = append(out, j)
+	}
+	sort.Slice(out, func(i, k int) bool { return out[i].CreatedAt.After(out[k].CreatedAt) })
+	if f.Limit > 0 && len(out) > f.Limit {
+		out = out[:f.Limit]
+	}
+	return out, nil
+}
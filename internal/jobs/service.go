@@ -0,0 +1,321 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"telecom-platform/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Async jobs.
+//
+// Long-running operations (exports, imports, purges, reconciliations) run as jobs
+// instead of tracking their own status:
+// - The feature handler calls Submit with a Func; the API answers 202 with the job.
+// - Clients poll GET /v1/jobs/:job_id, optionally long-polling with ?wait=30s,
+//   which returns as soon as the job is terminal (or when the wait elapses).
+// - When the job set a NotifyURL, the terminal job is POSTed there with retries.
+//
+// Jobs run in the submitting process. Waiters on other instances fall back to
+// re-reading the store every PollInterval, so long-poll works behind a load balancer.
+
+// Func performs the work. progress reports 0-100; the returned result is stored
+// as JSON on the job.
+type Func func(ctx context.Context, progress func(pct int)) (result any, err error)
+
+// Store persists jobs. Implementations must enforce workspace filtering.
+type Store interface {
+	Create(ctx context.Context, j Job) error
+	Update(ctx context.Context, j Job) error
+	Get(ctx context.Context, workspaceID, jobID string) (Job, bool, error)
+	List(ctx context.Context, workspaceID string, f ListFilter) ([]Job, error)
+}
+
+// Notifier delivers a terminal job to its NotifyURL.
+type Notifier interface {
+	NotifyJob(ctx context.Context, j Job) error
+}
+
+var (
+	ErrInvalidArgument = errors.New("jobs: invalid argument")
+	ErrNotFound        = errors.New("jobs: job not found")
+)
+
+type Service struct {
+	store Store
+	clock func() time.Time
+
+	mu      sync.Mutex
+	waiters map[string][]chan struct{}
+
+	// Notifier is optional; without it NotifyURL is recorded but never called.
+	Notifier      Notifier
+	NotifyRetries int
+	NotifyBackoff time.Duration
+
+	// PollInterval is how often a waiter re-reads the store. MaxWait caps ?wait.
+	PollInterval time.Duration
+	MaxWait      time.Duration
+}
+
+func NewService(store Store) *Service {
+	return &Service{
+		store:         store,
+		clock:         time.Now,
+		waiters:       map[string][]chan struct{}{},
+		NotifyRetries: 3,
+		NotifyBackoff: 5 * time.Second,
+		PollInterval:  time.Second,
+		MaxWait:       30 * time.Second,
+	}
+}
+
+// Submit records a queued job and starts fn in the background. The job outlives
+// ctx (typically the HTTP request) but keeps its values, e.g. the request logger.
+func (s *Service) Submit(ctx context.Context, workspaceID, actorUserID string, kind Kind, notifyURL string, fn Func) (Job, error) {
+	if workspaceID == "" || kind == "" || fn == nil {
+		return Job{}, ErrInvalidArgument
+	}
+	if notifyURL != "" {
+		u, err := url.Parse(notifyURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return Job{}, fmt.Errorf("%w: notify_url must be an https url", ErrInvalidArgument)
+		}
+	}
+	now := s.clock().UTC()
+	j := Job{
+		ID:          uuid.NewString(),
+		WorkspaceID: workspaceID,
+		Kind:        kind,
+		CreatedBy:   actorUserID,
+		Status:      StatusQueued,
+		NotifyURL:   notifyURL,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.store.Create(ctx, j); err != nil {
+		return Job{}, err
+	}
+	go s.run(context.WithoutCancel(ctx), j, fn)
+	return j, nil
+}
+
+// Get returns a job in the workspace.
+func (s *Service) Get(ctx context.Context, workspaceID, jobID string) (Job, error) {
+	if workspaceID == "" || jobID == "" {
+		return Job{}, ErrInvalidArgument
+	}
+	j, ok, err := s.store.Get(ctx, workspaceID, jobID)
+	if err != nil {
+		return Job{}, err
+	}
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return j, nil
+}
+
+// Wait long-polls a job: it returns once the job is terminal, wait elapses or ctx
+// is canceled, always with the latest known state. wait is capped at MaxWait.
+func (s *Service) Wait(ctx context.Context, workspaceID, jobID string, wait time.Duration) (Job, error) {
+	j, err := s.Get(ctx, workspaceID, jobID)
+	if err != nil || j.Status.Terminal() || wait <= 0 {
+		return j, err
+	}
+	if s.MaxWait > 0 && wait > s.MaxWait {
+		wait = s.MaxWait
+	}
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	poll := time.NewTicker(s.PollInterval)
+	defer poll.Stop()
+
+	for {
+		// Subscribe before re-reading so a finish in between is not missed.
+		ch := s.subscribe(jobID)
+		cur, err := s.Get(ctx, workspaceID, jobID)
+		if err != nil {
+			s.unsubscribe(jobID, ch)
+			return j, err
+		}
+		j = cur
+		if j.Status.Terminal() {
+			s.unsubscribe(jobID, ch)
+			return j, nil
+		}
+		select {
+		case <-ch:
+		case <-poll.C:
+		case <-deadline.C:
+			s.unsubscribe(jobID, ch)
+			return j, nil
+		case <-ctx.Done():
+			s.unsubscribe(jobID, ch)
+			return j, nil
+		}
+		s.unsubscribe(jobID, ch)
+	}
+}
+
+// List returns the workspace's jobs, newest first.
+func (s *Service) List(ctx context.Context, workspaceID string, f ListFilter) ([]Job, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	if f.Limit <= 0 || f.Limit > 200 {
+		f.Limit = 50
+	}
+	return s.store.List(ctx, workspaceID, f)
+}
+
+func (s *Service) run(ctx context.Context, j Job, fn Func) {
+	log := logger.From(ctx).With("job_id", j.ID, "kind", string(j.Kind))
+
+	started := s.clock().UTC()
+	j.Status = StatusRunning
+	j.StartedAt = &started
+	j.UpdatedAt = started
+	s.save(ctx, j)
+
+	var mu sync.Mutex
+	progress := func(pct int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if pct < 0 {
+			pct = 0
+		}
+		if pct > 100 {
+			pct = 100
+		}
+		if pct == j.Progress {
+			return
+		}
+		j.Progress = pct
+		j.UpdatedAt = s.clock().UTC()
+		s.save(ctx, j)
+	}
+
+	result, err := safeRun(ctx, fn, progress)
+
+	mu.Lock()
+	finished := s.clock().UTC()
+	j.FinishedAt = &finished
+	j.UpdatedAt = finished
+	if err != nil {
+		j.Status = StatusFailed
+		j.Error = err.Error()
+	} else {
+		j.Status = StatusSucceeded
+		j.Progress = 100
+		if result != nil {
+			if b, mErr := json.Marshal(result); mErr == nil {
+				j.Result = b
+			} else {
+				j.Status = StatusFailed
+				j.Error = "result encoding failed"
+			}
+		}
+	}
+	s.save(ctx, j)
+	done := j
+	mu.Unlock()
+
+	if err != nil {
+		log.Error("job failed", "err", err)
+	}
+	if done.NotifyURL != "" && s.Notifier != nil {
+		s.notify(ctx, done)
+	}
+}
+
+// safeRun turns a panicking job into a failed one instead of crashing the process.
+func safeRun(ctx context.Context, fn Func, progress func(int)) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("jobs: job panicked: %v", r)
+		}
+	}()
+	return fn(ctx, progress)
+}
+
+// notify delivers j with NotifyRetries attempts, NotifyBackoff apart, and records
+// the outcome on the job.
+func (s *Service) notify(ctx context.Context, j Job) {
+	attempts := s.NotifyRetries
+	if attempts <= 0 {
+		attempts = 1
+	}
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(s.NotifyBackoff):
+			}
+		}
+		err := s.Notifier.NotifyJob(ctx, j)
+		j.NotifyAttempts++
+		j.UpdatedAt = s.clock().UTC()
+		if err == nil {
+			at := j.UpdatedAt
+			j.NotifiedAt = &at
+			j.NotifyLastError = ""
+			s.save(ctx, j)
+			return
+		}
+		j.NotifyLastError = err.Error()
+		s.save(ctx, j)
+	}
+	logger.From(ctx).Error("job notification failed", "job_id", j.ID, "attempts", j.NotifyAttempts, "err", j.NotifyLastError)
+}
+
+// save persists j and wakes its waiters. Store errors are logged: the job keeps
+// running and the next save will try again.
+func (s *Service) save(ctx context.Context, j Job) {
+	if err := s.store.Update(ctx, j); err != nil {
+		logger.From(ctx).Error("job update failed", "job_id", j.ID, "err", err)
+	}
+	s.broadcast(j.ID)
+}
+
+func (s *Service) subscribe(jobID string) chan struct{} {
+	ch := make(chan struct{})
+	s.mu.Lock()
+	s.waiters[jobID] = append(s.waiters[jobID], ch)
+	s.mu.Unlock()
+	return ch
+}
+
+func (s *Service) unsubscribe(jobID string, ch chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := s.waiters[jobID]
+	for i, w := range list {
+		if w == ch {
+			list = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+	if len(list) == 0 {
+		delete(s.waiters, jobID)
+	} else {
+		s.waiters[jobID] = list
+	}
+}
+
+func (s *Service) broadcast(jobID string) {
+	s.mu.Lock()
+	list := s.waiters[jobID]
+	delete(s.waiters, jobID)
+	s.mu.Unlock()
+	for _, ch := range list {
+		close(ch)
+	}
+}
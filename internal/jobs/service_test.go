@@ -0,0 +1,136 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type stubNotifier struct {
+	mu   sync.Mutex
+	fail int
+	got  []Job
+	done chan struct{}
+}
+
+func (n *stubNotifier) NotifyJob(ctx context.Context, j Job) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.got = append(n.got, j)
+	if n.fail > 0 {
+		n.fail--
+		return errors.New("endpoint down")
+	}
+	close(n.done)
+	return nil
+}
+
+func TestService_WaitReturnsWhenJobFinishes(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore())
+	svc.PollInterval = time.Hour // must be woken by the finish, not by polling
+
+	release := make(chan struct{})
+	j, err := svc.Submit(ctx, "w", "u", KindImport, "", func(ctx context.Context, progress func(int)) (any, error) {
+		progress(40)
+		<-release
+		return map[string]int{"inserted": 3}, nil
+	})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if j.Status != StatusQueued {
+		t.Fatalf("expected queued job, got %s", j.Status)
+	}
+
+	got, err := svc.Wait(ctx, "w", j.ID, 20*time.Millisecond)
+	if err != nil || got.Status.Terminal() {
+		t.Fatalf("expected wait to time out on a running job, got %+v %v", got, err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	got, err = svc.Wait(ctx, "w", j.ID, 5*time.Second)
+	if err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if got.Status != StatusSucceeded || got.Progress != 100 || string(got.Result) != `{"inserted":3}` || got.FinishedAt == nil {
+		t.Fatalf("unexpected finished job: %+v", got)
+	}
+
+	if _, err := svc.Get(ctx, "other", j.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected workspace isolation, got %v", err)
+	}
+}
+
+func TestService_FailedJobAndPanic(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore())
+
+	j, _ := svc.Submit(ctx, "w", "u", KindPurge, "", func(ctx context.Context, progress func(int)) (any, error) {
+		return nil, errors.New("disk full")
+	})
+	got, _ := svc.Wait(ctx, "w", j.ID, 5*time.Second)
+	if got.Status != StatusFailed || got.Error != "disk full" {
+		t.Fatalf("expected failed job, got %+v", got)
+	}
+
+	j, _ = svc.Submit(ctx, "w", "u", KindPurge, "", func(ctx context.Context, progress func(int)) (any, error) {
+		panic("boom")
+	})
+	got, _ = svc.Wait(ctx, "w", j.ID, 5*time.Second)
+	if got.Status != StatusFailed {
+		t.Fatalf("expected panicking job to fail, got %+v", got)
+	}
+}
+
+func TestService_NotifiesWithRetry(t *testing.T) {
+	ctx := context.Background()
+	n := &stubNotifier{fail: 1, done: make(chan struct{})}
+	svc := NewService(NewMemoryStore())
+	svc.Notifier = n
+	svc.NotifyBackoff = time.Millisecond
+
+	if _, err := svc.Submit(ctx, "w", "u", KindExport, "http://insecure.example", func(ctx context.Context, progress func(int)) (any, error) {
+		return nil, nil
+	}); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected https notify_url to be required, got %v", err)
+	}
+
+	j, err := svc.Submit(ctx, "w", "u", KindExport, "https://tenant.example/hooks/jobs", func(ctx context.Context, progress func(int)) (any, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	select {
+	case <-n.done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("notification not delivered")
+	}
+
+	n.mu.Lock()
+	if len(n.got) != 2 || n.got[1].Status != StatusSucceeded {
+		t.Fatalf("expected a retried notification of the finished job, got %+v", n.got)
+	}
+	n.mu.Unlock()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, _ := svc.Get(ctx, "w", j.ID)
+		if got.NotifiedAt != nil {
+			if got.NotifyAttempts != 2 || got.NotifyLastError != "" {
+				t.Fatalf("unexpected notify bookkeeping: %+v", got)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("notification outcome not recorded")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
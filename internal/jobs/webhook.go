@@ -0,0 +1,71 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookNotifier POSTs the terminal job as JSON to its NotifyURL.
+// When Secret is set, X-Job-Signature carries hex(HMAC-SHA256(secret, body)).
+type WebhookNotifier struct {
+	Client *http.Client
+	Secret string
+
+	// Signer adds the workspace's X-Webhook-Signature (see internal/webhooks). Optional.
+	Signer PayloadSigner
+}
+
+// PayloadSigner signs a webhook body with the workspace's signing keys and returns
+// the header value ("" when the workspace has none).
+type PayloadSigner interface {
+	Sign(ctx context.Context, workspaceID string, body []byte) (string, error)
+}
+
+func (n WebhookNotifier) NotifyJob(ctx context.Context, j Job) error {
+	body, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.NotifyURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Job-ID", j.ID)
+	if n.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Job-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	if n.Signer != nil {
+		sig, err := n.Signer.Sign(ctx, j.WorkspaceID, body)
+		if err != nil {
+			return err
+		}
+		if sig != "" {
+			req.Header.Set("X-Webhook-Signature", sig)
+		}
+	}
+
+	client := n.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("jobs: notify endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
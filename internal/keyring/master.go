@@ -0,0 +1,67 @@
+package keyring
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+// MasterKey wraps data keys. Production deployments back it with a KMS key
+// (the DEK never leaves the process unwrapped; the master key never enters it).
+// ID must be stable: it is stored next to every key it wrapped.
+type MasterKey interface {
+	ID() string
+	Wrap(ctx context.Context, dek []byte) ([]byte, error)
+	Unwrap(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// LocalMasterKey wraps with AES-256-GCM using a key held in process memory.
+// Suitable for development and single-tenant installs without a KMS.
+type LocalMasterKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+func NewLocalMasterKey(id string, key []byte) (*LocalMasterKey, error) {
+	if id == "" || len(key) != 32 {
+		return nil, errors.New("keyring: local master key needs an id and 32 bytes")
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalMasterKey{id: id, aead: aead}, nil
+}
+
+func (m *LocalMasterKey) ID() string { return m.id }
+
+func (m *LocalMasterKey) Wrap(ctx context.Context, dek []byte) ([]byte, error) {
+	nonce := make([]byte, m.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return m.aead.Seal(nonce, nonce, dek, []byte(m.id)), nil
+}
+
+func (m *LocalMasterKey) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	n := m.aead.NonceSize()
+	if len(wrapped) < n {
+		return nil, ErrDecrypt
+	}
+	dek, err := m.aead.Open(nil, wrapped[:n], wrapped[n:], []byte(m.id))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return dek, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
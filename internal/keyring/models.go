@@ -0,0 +1,27 @@
+package keyring
+
+import "time"
+
+type KeyStatus string
+
+const (
+	// KeyStatusActive encrypts new objects. A workspace has at most one active key.
+	KeyStatusActive KeyStatus = "active"
+	// KeyStatusRetired only decrypts objects sealed before a rotation.
+	KeyStatusRetired KeyStatus = "retired"
+)
+
+// DataKey is a per-workspace data encryption key (DEK). Only the wrapped form is
+// stored; the plaintext key exists in memory while in use.
+type DataKey struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+
+	// WrappedKey is the DEK encrypted by the master key MasterKeyID.
+	WrappedKey  []byte `json:"-" db:"wrapped_key"`
+	MasterKeyID string `json:"master_key_id" db:"master_key_id"`
+
+	Status    KeyStatus  `json:"status" db:"status"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	RetiredAt *time.Time `json:"retired_at,omitempty" db:"retired_at"`
+}
@@ -0,0 +1,79 @@
+package keyring
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// MemoryStore is a simple in-memory Store useful for tests.
+// It is not intended for production use.
+
+type MemoryStore struct {
+	mu    sync.Mutex
+	items map[string]DataKey
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: map[string]DataKey{}}
+}
+
+func (s *MemoryStore) Create(ctx context.Context, k DataKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if k.Status == KeyStatusActive {
+		for _, cur := range s.items {
+			if cur.WorkspaceID == k.WorkspaceID && cur.Status == KeyStatusActive {
+				return errors.New("keyring: workspace already has an active key")
+			}
+		}
+	}
+	s.items[k.ID] = k
+	return nil
+}
+
+func (s *MemoryStore) Update(ctx context.Context, k DataKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur, ok := s.items[k.ID]
+	if !ok || cur.WorkspaceID != k.WorkspaceID {
+		return ErrUnknownKey
+	}
+	s.items[k.ID] = k
+	return nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, workspaceID, keyID string) (DataKey, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k, ok := s.items[keyID]
+	if !ok || k.WorkspaceID != workspaceID {
+		return DataKey{}, false, nil
+	}
+	return k, true, nil
+}
+
+func (s *MemoryStore) GetActive(ctx context.Context, workspaceID string) (DataKey, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, k := range s.items {
+		if k.WorkspaceID == workspaceID && k.Status == KeyStatusActive {
+			return k, true, nil
+		}
+	}
+	return DataKey{}, false, nil
+}
+
+func (s *MemoryStore) List(ctx context.Context, workspaceID string) ([]DataKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []DataKey
+	for _, k := range s.items {
+		if k.WorkspaceID == workspaceID {
+			out = append(out, k)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
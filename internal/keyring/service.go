@@ -0,0 +1,285 @@
+package keyring
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Per-workspace envelope encryption for stored media (recordings, transcripts).
+//
+// Each workspace has its own data key (DEK), stored only wrapped by the platform
+// master key (KMS). Objects are sealed with AES-256-GCM under the workspace's
+// active DEK; the envelope names the DEK so older objects stay readable after
+// rotation. The workspace id and object key are bound as associated data, so a
+// ciphertext copied to another tenant or object fails to open.
+//
+// Rotation:
+// - Rotate retires the active DEK and creates a new one. Existing objects are not
+//   re-encrypted; they open with the retired key.
+// - RewrapKeys re-wraps a workspace's DEKs under the current master key after a
+//   master key rotation. Old master keys must stay in Previous until that has run.
+//
+// The object store only ever sees ciphertext, so download links for sealed media
+// must resolve through the API (which calls Open), not a presigned bucket URL.
+
+// Store persists data keys. Implementations must enforce workspace filtering and
+// should back "one active key per workspace" with a unique constraint.
+type Store interface {
+	Create(ctx context.Context, k DataKey) error
+	Update(ctx context.Context, k DataKey) error
+	Get(ctx context.Context, workspaceID, keyID string) (DataKey, bool, error)
+	GetActive(ctx context.Context, workspaceID string) (DataKey, bool, error)
+	List(ctx context.Context, workspaceID string) ([]DataKey, error)
+}
+
+var (
+	ErrInvalidArgument = errors.New("keyring: invalid argument")
+	ErrUnknownKey      = errors.New("keyring: data key not found")
+	ErrDecrypt         = errors.New("keyring: decryption failed")
+)
+
+// envelopeVersion is the first byte of every sealed object.
+const envelopeVersion byte = 1
+
+type Service struct {
+	store  Store
+	master MasterKey
+	clock  func() time.Time
+
+	// Previous are retired master keys still needed to unwrap older DEKs.
+	Previous []MasterKey
+
+	mu    sync.Mutex
+	cache map[string][]byte // data key id -> plaintext DEK
+}
+
+func NewService(store Store, master MasterKey) *Service {
+	return &Service{store: store, master: master, clock: time.Now, cache: map[string][]byte{}}
+}
+
+// Seal encrypts plaintext for workspaceID. objectKey identifies the stored object
+// (e.g. the recording's storage key) and must be passed unchanged to Open.
+// The workspace's first DEK is created on demand.
+func (s *Service) Seal(ctx context.Context, workspaceID, objectKey string, plaintext []byte) ([]byte, error) {
+	if workspaceID == "" || objectKey == "" {
+		return nil, ErrInvalidArgument
+	}
+	k, dek, err := s.activeKey(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	// version | len(key id) | key id | nonce | ciphertext
+	out := make([]byte, 0, 3+len(k.ID)+len(nonce)+len(plaintext)+aead.Overhead())
+	out = append(out, envelopeVersion)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(k.ID)))
+	out = append(out, k.ID...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, associatedData(workspaceID, objectKey)), nil
+}
+
+// Open decrypts an envelope produced by Seal for the same workspace and object key.
+func (s *Service) Open(ctx context.Context, workspaceID, objectKey string, sealed []byte) ([]byte, error) {
+	if workspaceID == "" || objectKey == "" {
+		return nil, ErrInvalidArgument
+	}
+	if len(sealed) < 3 || sealed[0] != envelopeVersion {
+		return nil, ErrDecrypt
+	}
+	n := int(binary.BigEndian.Uint16(sealed[1:3]))
+	if len(sealed) < 3+n {
+		return nil, ErrDecrypt
+	}
+	keyID := string(sealed[3 : 3+n])
+	rest := sealed[3+n:]
+
+	k, ok, err := s.store.Get(ctx, workspaceID, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// Also the answer for another workspace's key: never reveal that it exists.
+		return nil, ErrDecrypt
+	}
+	dek, err := s.unwrap(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	plaintext, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], associatedData(workspaceID, objectKey))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+// Rotate makes a fresh DEK the workspace's active key and retires the previous one.
+func (s *Service) Rotate(ctx context.Context, workspaceID string) (DataKey, error) {
+	if workspaceID == "" {
+		return DataKey{}, ErrInvalidArgument
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock().UTC()
+	if cur, ok, err := s.store.GetActive(ctx, workspaceID); err != nil {
+		return DataKey{}, err
+	} else if ok {
+		cur.Status = KeyStatusRetired
+		cur.RetiredAt = &now
+		if err := s.store.Update(ctx, cur); err != nil {
+			return DataKey{}, err
+		}
+	}
+	k, _, err := s.createKeyLocked(ctx, workspaceID, now)
+	return k, err
+}
+
+// RewrapKeys re-wraps every DEK of the workspace that is not yet under the current
+// master key and returns how many were updated. Plaintext DEKs do not change, so
+// sealed objects are unaffected.
+func (s *Service) RewrapKeys(ctx context.Context, workspaceID string) (int, error) {
+	if workspaceID == "" {
+		return 0, ErrInvalidArgument
+	}
+	keys, err := s.store.List(ctx, workspaceID)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, k := range keys {
+		if k.MasterKeyID == s.master.ID() {
+			continue
+		}
+		dek, err := s.unwrap(ctx, k)
+		if err != nil {
+			return n, err
+		}
+		wrapped, err := s.master.Wrap(ctx, dek)
+		if err != nil {
+			return n, err
+		}
+		k.WrappedKey = wrapped
+		k.MasterKeyID = s.master.ID()
+		if err := s.store.Update(ctx, k); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Keys lists the workspace's data keys (wrapped material is never serialized).
+func (s *Service) Keys(ctx context.Context, workspaceID string) ([]DataKey, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	return s.store.List(ctx, workspaceID)
+}
+
+func (s *Service) activeKey(ctx context.Context, workspaceID string) (DataKey, []byte, error) {
+	k, ok, err := s.store.GetActive(ctx, workspaceID)
+	if err != nil {
+		return DataKey{}, nil, err
+	}
+	if ok {
+		dek, err := s.unwrap(ctx, k)
+		return k, dek, err
+	}
+
+	s.mu.Lock()
+	// Re-check under the lock so concurrent first writers share one key.
+	k, ok, err = s.store.GetActive(ctx, workspaceID)
+	if err != nil || ok {
+		s.mu.Unlock()
+		if err != nil {
+			return DataKey{}, nil, err
+		}
+		dek, err := s.unwrap(ctx, k)
+		return k, dek, err
+	}
+	k, dek, err := s.createKeyLocked(ctx, workspaceID, s.clock().UTC())
+	s.mu.Unlock()
+	return k, dek, err
+}
+
+func (s *Service) createKeyLocked(ctx context.Context, workspaceID string, now time.Time) (DataKey, []byte, error) {
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return DataKey{}, nil, err
+	}
+	wrapped, err := s.master.Wrap(ctx, dek)
+	if err != nil {
+		return DataKey{}, nil, err
+	}
+	k := DataKey{
+		ID:          uuid.NewString(),
+		WorkspaceID: workspaceID,
+		WrappedKey:  wrapped,
+		MasterKeyID: s.master.ID(),
+		Status:      KeyStatusActive,
+		CreatedAt:   now,
+	}
+	if err := s.store.Create(ctx, k); err != nil {
+		return DataKey{}, nil, err
+	}
+	s.cache[k.ID] = dek
+	return k, dek, nil
+}
+
+// unwrap returns the plaintext DEK for k, using the master key that wrapped it.
+func (s *Service) unwrap(ctx context.Context, k DataKey) ([]byte, error) {
+	s.mu.Lock()
+	dek, ok := s.cache[k.ID]
+	s.mu.Unlock()
+	if ok {
+		return dek, nil
+	}
+
+	master := s.master
+	if k.MasterKeyID != master.ID() {
+		master = nil
+		for _, m := range s.Previous {
+			if m.ID() == k.MasterKeyID {
+				master = m
+				break
+			}
+		}
+		if master == nil {
+			return nil, ErrUnknownKey
+		}
+	}
+	dek, err := master.Unwrap(ctx, k.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.cache[k.ID] = dek
+	s.mu.Unlock()
+	return dek, nil
+}
+
+func associatedData(workspaceID, objectKey string) []byte {
+	return []byte(workspaceID + "\x00" + objectKey)
+}
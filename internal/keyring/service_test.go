@@ -0,0 +1,97 @@
+package keyring
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func testMaster(t *testing.T, id string, b byte) *LocalMasterKey {
+	t.Helper()
+	m, err := NewLocalMasterKey(id, bytes.Repeat([]byte{b}, 32))
+	if err != nil {
+		t.Fatalf("master key: %v", err)
+	}
+	return m
+}
+
+func TestService_SealOpenIsolatesWorkspacesAndObjects(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore(), testMaster(t, "m1", 1))
+
+	sealed, err := svc.Seal(ctx, "w1", "recordings/CA1.wav", []byte("hello"))
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if bytes.Contains(sealed, []byte("hello")) {
+		t.Fatalf("plaintext leaked into envelope")
+	}
+	got, err := svc.Open(ctx, "w1", "recordings/CA1.wav", sealed)
+	if err != nil || string(got) != "hello" {
+		t.Fatalf("open: %q %v", got, err)
+	}
+
+	if _, err := svc.Open(ctx, "w2", "recordings/CA1.wav", sealed); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected other workspace to fail, got %v", err)
+	}
+	if _, err := svc.Open(ctx, "w1", "recordings/CA2.wav", sealed); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected moved object to fail, got %v", err)
+	}
+	sealed[len(sealed)-1] ^= 1
+	if _, err := svc.Open(ctx, "w1", "recordings/CA1.wav", sealed); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected tampered object to fail, got %v", err)
+	}
+
+	other, _ := svc.Seal(ctx, "w2", "t/1.json", []byte("x"))
+	k1, _ := svc.Keys(ctx, "w1")
+	k2, _ := svc.Keys(ctx, "w2")
+	if len(k1) != 1 || len(k2) != 1 || k1[0].ID == k2[0].ID || len(other) == 0 {
+		t.Fatalf("expected one key per workspace, got %v %v", k1, k2)
+	}
+}
+
+func TestService_RotateKeepsOldObjectsReadable(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore(), testMaster(t, "m1", 1))
+
+	before, _ := svc.Seal(ctx, "w", "r/1", []byte("old"))
+	nk, err := svc.Rotate(ctx, "w")
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	after, _ := svc.Seal(ctx, "w", "r/2", []byte("new"))
+
+	keys, _ := svc.Keys(ctx, "w")
+	if len(keys) != 2 || keys[0].Status != KeyStatusRetired || keys[1].ID != nk.ID || keys[1].Status != KeyStatusActive {
+		t.Fatalf("unexpected keys after rotation: %+v", keys)
+	}
+	if got, err := svc.Open(ctx, "w", "r/1", before); err != nil || string(got) != "old" {
+		t.Fatalf("old object: %q %v", got, err)
+	}
+	if got, err := svc.Open(ctx, "w", "r/2", after); err != nil || string(got) != "new" {
+		t.Fatalf("new object: %q %v", got, err)
+	}
+}
+
+func TestService_RewrapAfterMasterRotation(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	m1 := testMaster(t, "m1", 1)
+	sealed, _ := NewService(store, m1).Seal(ctx, "w", "r/1", []byte("data"))
+
+	// A fresh process with the new master key only can't unwrap until m1 is listed.
+	svc := NewService(store, testMaster(t, "m2", 2))
+	if _, err := svc.Open(ctx, "w", "r/1", sealed); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected unknown master key, got %v", err)
+	}
+	svc.Previous = []MasterKey{m1}
+	if n, err := svc.RewrapKeys(ctx, "w"); err != nil || n != 1 {
+		t.Fatalf("rewrap: %d %v", n, err)
+	}
+
+	fresh := NewService(store, testMaster(t, "m2", 2))
+	if got, err := fresh.Open(ctx, "w", "r/1", sealed); err != nil || string(got) != "data" {
+		t.Fatalf("open after rewrap: %q %v", got, err)
+	}
+}
@@ -0,0 +1,34 @@
+package lookups
+
+import "time"
+
+// LineType is the provider's classification of a number.
+type LineType string
+
+const (
+	LineTypeMobile   LineType = "mobile"
+	LineTypeLandline LineType = "landline"
+	LineTypeVoIP     LineType = "voip"
+	LineTypeTollFree LineType = "toll_free"
+	LineTypeUnknown  LineType = "unknown"
+)
+
+// Result is the normalized number intelligence for one E.164 number.
+type Result struct {
+	Number string `json:"number"`
+	// Valid is false when the number is unallocated or malformed for its country.
+	Valid       bool     `json:"valid"`
+	LineType    LineType `json:"line_type"`
+	CountryISO2 string   `json:"country_iso2,omitempty"`
+	Carrier     string   `json:"carrier,omitempty"`
+	// Ported reports whether the number moved away from its original carrier.
+	Ported bool `json:"ported"`
+
+	Provider   string    `json:"provider"`
+	LookedUpAt time.Time `json:"looked_up_at"`
+
+	// Cached is true when the result was served from cache (and not billed).
+	Cached bool `json:"cached"`
+	// ChargedMinor is what this call cost the wallet (0 for cached results).
+	ChargedMinor int64 `json:"charged_minor"`
+}
@@ -0,0 +1,218 @@
+package lookups
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"telecom-platform/internal/wallet"
+
+	"github.com/google/uuid"
+)
+
+// Number lookups (HLR / number intelligence).
+//
+// Lookup asks the provider for line type, carrier, ported status and validity,
+// normalized into Result. Provider lookups cost money, so:
+// - Results are cached per workspace and number for CacheTTL; cached answers are free.
+// - Each provider lookup is billed to the workspace's default wallet at PriceMinor
+//   (a debit with a per-lookup idempotency key). The provider is queried first so a
+//   failed lookup is never billed.
+//
+// Screen is the dialer hook: it reports whether a lead should be skipped because the
+// number is invalid (or of a line type the workspace does not dial).
+
+const (
+	DefaultCacheTTL  = 7 * 24 * time.Hour
+	maxCacheEntries  = 10000
+	lookupMetaSource = "number_lookup"
+)
+
+// Provider wraps a number-intelligence API. Implementations return Valid=false (not
+// an error) for numbers the provider reports as unallocated.
+type Provider interface {
+	Name() string
+	Lookup(ctx context.Context, e164 string) (Result, error)
+}
+
+// Biller debits lookup charges (implemented by wallet.Service).
+type Biller interface {
+	Debit(ctx context.Context, workspaceID, walletID string, req wallet.DebitRequest) (wallet.WalletLedger, wallet.Balance, error)
+}
+
+// WalletResolver finds the wallet that pays for lookups (e.g. the routing wallet
+// assignment store).
+type WalletResolver interface {
+	DefaultWallet(ctx context.Context, workspaceID string) (walletID string, ok bool, err error)
+}
+
+var (
+	ErrInvalidArgument = errors.New("lookups: invalid argument")
+	ErrNoWallet        = errors.New("lookups: workspace has no billing wallet")
+)
+
+type cacheEntry struct {
+	result Result
+	at     time.Time
+}
+
+type Service struct {
+	provider Provider
+	billing  Biller
+	wallets  WalletResolver
+	clock    func() time.Time
+
+	// PriceMinor/Currency is the charge per provider lookup. Zero disables billing.
+	PriceMinor int64
+	Currency   string
+	CacheTTL   time.Duration
+	// SkipLineTypes are line types Screen treats as undialable (e.g. landline for SMS).
+	SkipLineTypes []LineType
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+func NewService(provider Provider, billing Biller, wallets WalletResolver) *Service {
+	return &Service{
+		provider: provider,
+		billing:  billing,
+		wallets:  wallets,
+		clock:    time.Now,
+		CacheTTL: DefaultCacheTTL,
+		cache:    map[string]cacheEntry{},
+	}
+}
+
+// Lookup returns number intelligence for an E.164 number, from cache when fresh.
+func (s *Service) Lookup(ctx context.Context, workspaceID, number string) (Result, error) {
+	number = strings.TrimSpace(number)
+	if workspaceID == "" || !validE164(number) {
+		return Result{}, ErrInvalidArgument
+	}
+	now := s.clock().UTC()
+	key := workspaceID + "|" + number
+	if r, ok := s.cached(key, now); ok {
+		r.Cached = true
+		r.ChargedMinor = 0
+		return r, nil
+	}
+
+	walletID, err := s.billingWallet(ctx, workspaceID)
+	if err != nil {
+		return Result{}, err
+	}
+	r, err := s.provider.Lookup(ctx, number)
+	if err != nil {
+		return Result{}, fmt.Errorf("lookups: provider: %w", err)
+	}
+	r.Number = number
+	if r.LineType == "" {
+		r.LineType = LineTypeUnknown
+	}
+	if r.Provider == "" {
+		r.Provider = s.provider.Name()
+	}
+	if r.LookedUpAt.IsZero() {
+		r.LookedUpAt = now
+	}
+	r.Cached = false
+	r.ChargedMinor = 0
+
+	if walletID != "" {
+		lookupID := uuid.NewString()
+		meta, _ := json.Marshal(map[string]string{"lookup_id": lookupID, "note": lookupMetaSource})
+		if _, _, err := s.billing.Debit(ctx, workspaceID, walletID, wallet.DebitRequest{
+			AmountMinor:    s.PriceMinor,
+			Currency:       s.Currency,
+			ExternalRef:    "lookup:" + lookupID,
+			IdempotencyKey: "lookup:" + lookupID,
+			Metadata:       string(meta),
+		}); err != nil {
+			return Result{}, err
+		}
+		r.ChargedMinor = s.PriceMinor
+	}
+
+	s.store(key, r, now)
+	return r, nil
+}
+
+// Screen reports whether the dialer should skip number and why. Numbers the
+// provider cannot classify are dialed.
+func (s *Service) Screen(ctx context.Context, workspaceID, number string) (skip bool, reason string, err error) {
+	r, err := s.Lookup(ctx, workspaceID, number)
+	if err != nil {
+		return false, "", err
+	}
+	if !r.Valid {
+		return true, "invalid number", nil
+	}
+	for _, t := range s.SkipLineTypes {
+		if r.LineType == t {
+			return true, "line type " + string(t), nil
+		}
+	}
+	return false, "", nil
+}
+
+// billingWallet returns "" when lookups are free.
+func (s *Service) billingWallet(ctx context.Context, workspaceID string) (string, error) {
+	if s.PriceMinor <= 0 || s.billing == nil {
+		return "", nil
+	}
+	if s.wallets == nil {
+		return "", ErrNoWallet
+	}
+	walletID, ok, err := s.wallets.DefaultWallet(ctx, workspaceID)
+	if err != nil {
+		return "", err
+	}
+	if !ok || walletID == "" {
+		return "", ErrNoWallet
+	}
+	return walletID, nil
+}
+
+func (s *Service) cached(key string, now time.Time) (Result, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.cache[key]
+	if !ok || now.Sub(e.at) >= s.CacheTTL {
+		return Result{}, false
+	}
+	return e.result, true
+}
+
+func (s *Service) store(key string, r Result, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cache) >= maxCacheEntries {
+		// Drop expired entries first; if still full, start over rather than grow unbounded.
+		for k, e := range s.cache {
+			if now.Sub(e.at) >= s.CacheTTL {
+				delete(s.cache, k)
+			}
+		}
+		if len(s.cache) >= maxCacheEntries {
+			s.cache = map[string]cacheEntry{}
+		}
+	}
+	s.cache[key] = cacheEntry{result: r, at: now}
+}
+
+func validE164(n string) bool {
+	if len(n) < 8 || len(n) > 16 || n[0] != '+' || n[1] == '0' {
+		return false
+	}
+	for _, c := range n[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package lookups
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/wallet"
+)
+
+type stubProvider struct {
+	calls int
+	err   error
+	out   map[string]Result
+}
+
+func (p *stubProvider) Name() string { return "stub" }
+
+func (p *stubProvider) Lookup(ctx context.Context, e164 string) (Result, error) {
+	p.calls++
+	if p.err != nil {
+		return Result{}, p.err
+	}
+	return p.out[e164], nil
+}
+
+type stubBiller struct{ debits []wallet.DebitRequest }
+
+func (b *stubBiller) Debit(ctx context.Context, workspaceID, walletID string, req wallet.DebitRequest) (wallet.WalletLedger, wallet.Balance, error) {
+	if walletID != "wal-1" {
+		return wallet.WalletLedger{}, wallet.Balance{}, wallet.ErrNotFound
+	}
+	b.debits = append(b.debits, req)
+	return wallet.WalletLedger{}, wallet.Balance{}, nil
+}
+
+type stubWallets map[string]string
+
+func (w stubWallets) DefaultWallet(ctx context.Context, workspaceID string) (string, bool, error) {
+	id, ok := w[workspaceID]
+	return id, ok, nil
+}
+
+func newTestService() (*Service, *stubProvider, *stubBiller, *time.Time) {
+	p := &stubProvider{out: map[string]Result{
+		"+15551230000": {Valid: true, LineType: LineTypeMobile, Carrier: "Acme", Ported: true},
+		"+15550000000": {Valid: false},
+		"+15557770000": {Valid: true, LineType: LineTypeLandline},
+	}}
+	b := &stubBiller{}
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewService(p, b, stubWallets{"ws": "wal-1"})
+	svc.PriceMinor = 5
+	svc.Currency = "USD"
+	svc.clock = func() time.Time { return now }
+	return svc, p, b, &now
+}
+
+func TestLookup_CachesAndBillsOncePerProviderCall(t *testing.T) {
+	ctx := context.Background()
+	svc, p, b, now := newTestService()
+
+	r, err := svc.Lookup(ctx, "ws", "+15551230000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Valid || r.Carrier != "Acme" || !r.Ported || r.Provider != "stub" || r.Cached || r.ChargedMinor != 5 {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+	if len(b.debits) != 1 || b.debits[0].AmountMinor != 5 || b.debits[0].IdempotencyKey == "" {
+		t.Fatalf("expected one debit, got %+v", b.debits)
+	}
+
+	r, _ = svc.Lookup(ctx, "ws", "+15551230000")
+	if !r.Cached || r.ChargedMinor != 0 || p.calls != 1 || len(b.debits) != 1 {
+		t.Fatalf("expected free cached hit, got %+v calls=%d debits=%d", r, p.calls, len(b.debits))
+	}
+
+	*now = now.Add(svc.CacheTTL)
+	if r, _ := svc.Lookup(ctx, "ws", "+15551230000"); r.Cached || p.calls != 2 || len(b.debits) != 2 {
+		t.Fatalf("expected expired entry to be looked up again")
+	}
+}
+
+func TestLookup_ErrorsAreNotBilled(t *testing.T) {
+	ctx := context.Background()
+	svc, p, b, _ := newTestService()
+
+	for _, n := range []string{"", "15551230000", "+0123456789", "+1555abc0000"} {
+		if _, err := svc.Lookup(ctx, "ws", n); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("%q: expected ErrInvalidArgument, got %v", n, err)
+		}
+	}
+	if _, err := svc.Lookup(ctx, "other", "+15551230000"); !errors.Is(err, ErrNoWallet) {
+		t.Fatalf("expected ErrNoWallet, got %v", err)
+	}
+	p.err = errors.New("timeout")
+	if _, err := svc.Lookup(ctx, "ws", "+15551230000"); err == nil {
+		t.Fatalf("expected provider error")
+	}
+	if len(b.debits) != 0 {
+		t.Fatalf("failed lookups must not be billed, got %+v", b.debits)
+	}
+}
+
+func TestScreen_SkipsInvalidAndConfiguredLineTypes(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, _ := newTestService()
+	svc.SkipLineTypes = []LineType{LineTypeLandline}
+
+	cases := map[string]bool{"+15551230000": false, "+15550000000": true, "+15557770000": true}
+	for n, want := range cases {
+		skip, reason, err := svc.Screen(ctx, "ws", n)
+		if err != nil || skip != want || (skip && reason == "") {
+			t.Fatalf("%s: got skip=%v reason=%q err=%v", n, skip, reason, err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package messaging
+
+import "time"
+
+// KeywordAction is what an inbound keyword does besides replying.
+type KeywordAction string
+
+const (
+	ActionOptOut KeywordAction = "opt_out" // STOP family: add the sender to the opt-out list
+	ActionOptIn  KeywordAction = "opt_in"  // START family: remove the sender from it
+	ActionHelp   KeywordAction = "help"    // HELP/INFO: reply only
+	ActionReply  KeywordAction = "reply"   // tenant-defined keyword: reply only
+)
+
+// KeywordRule customizes the reply to a keyword. Number scopes the rule to one
+// receiving number; empty applies it to every number in the workspace.
+type KeywordRule struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	Number      string `json:"number,omitempty" db:"number"`
+
+	// Keyword is stored uppercase.
+	Keyword string        `json:"keyword" db:"keyword"`
+	Action  KeywordAction `json:"action" db:"-"`
+	Reply   string        `json:"reply" db:"reply"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// OptOut records that a recipient asked not to be messaged by the workspace.
+type OptOut struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	Number      string `json:"number" db:"number"`
+
+	// Keyword and ProviderMessageID are the evidence for the opt-out.
+	Keyword           string `json:"keyword" db:"keyword"`
+	ProviderMessageID string `json:"provider_message_id,omitempty" db:"provider_message_id"`
+	// ReceivedOn is the workspace number the STOP was sent to.
+	ReceivedOn string `json:"received_on" db:"received_on"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Config is the workspace's inbound messaging configuration.
+type Config struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	// ForwardURL receives inbound messages that are not keywords (https only).
+	ForwardURL string    `json:"forward_url" db:"forward_url"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Outcome describes how an inbound message was handled.
+type Outcome struct {
+	Keyword   string        `json:"keyword,omitempty"`
+	Action    KeywordAction `json:"action,omitempty"`
+	Reply     string        `json:"reply,omitempty"`
+	Forwarded bool          `json:"forwarded"`
+}
+
+// SenderClass is the kind of sending number. Carriers cap throughput per class
+// (e.g. about 1 message per second for a 10DLC long code).
+type SenderClass string
+
+const (
+	SenderLongCode  SenderClass = "long_code"
+	SenderTollFree  SenderClass = "toll_free"
+	SenderShortCode SenderClass = "short_code"
+)
+
+// Sender is a candidate sending number for an outbound message.
+type Sender struct {
+	Number string      `json:"number"`
+	Class  SenderClass `json:"class,omitempty"` // default long_code
+}
+
+// MessageStatus is the outbound lifecycle: queued -> sent | failed | skipped.
+type MessageStatus string
+
+const (
+	MessageQueued MessageStatus = "queued"
+	MessageSent   MessageStatus = "sent"
+	MessageFailed MessageStatus = "failed"
+	// MessageSkipped means the recipient opted out after the message was queued.
+	MessageSkipped MessageStatus = "skipped"
+)
+
+// OutboundMessage is one SMS in the send queue.
+type OutboundMessage struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+
+	From        string      `json:"from" db:"from_number"`
+	SenderClass SenderClass `json:"sender_class" db:"sender_class"`
+	To          string      `json:"to" db:"to_number"`
+	Body        string      `json:"body" db:"body"`
+
+	Status MessageStatus `json:"status" db:"status"`
+	// SendAt is the message's paced slot on From; while queued it is the ETA.
+	SendAt   time.Time `json:"send_at" db:"send_at"`
+	Attempts int       `json:"attempts" db:"attempts"`
+
+	ProviderMessageID string `json:"provider_message_id,omitempty" db:"provider_message_id"`
+	Error             string `json:"error,omitempty" db:"error"`
+
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	SentAt    *time.Time `json:"sent_at,omitempty" db:"sent_at"`
+}
+
+// SendRequest queues one outbound SMS.
+type SendRequest struct {
+	To   string `json:"to"`
+	Body string `json:"body"`
+	// Senders are the candidate numbers, preferred first (e.g. the numbers of a
+	// pool). The message goes out on whichever has the earliest free slot.
+	Senders []Sender `json:"senders"`
+}
+
+// SenderQueue is one sending number's backlog.
+type SenderQueue struct {
+	Number string      `json:"number"`
+	Class  SenderClass `json:"class"`
+	// MPS is the pacing rate applied to the number (messages per second).
+	MPS   float64 `json:"mps"`
+	Depth int     `json:"depth"`
+	// ETA is when the last queued message on the number is expected to go out.
+	ETA time.Time `json:"eta"`
+}
@@ -0,0 +1,326 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/telephony"
+	"telecom-platform/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Outbound send queue.
+//
+// Carriers filter traffic that exceeds a sending number's throughput (about 1
+// message per second on a long code), so bulk sends are paced instead of fired at
+// the provider:
+// - Enqueue gives each message a slot on a sending number: the number's last
+//   reserved slot plus 1/MPS, or now when it is idle.
+// - A request may name several candidate numbers (e.g. a pool). The message goes to
+//   the one with the earliest slot, so a backlog on the preferred number spills over
+//   to the others; ties keep the caller's preference order.
+// - SendWorker sends messages whose slot has come, re-checking the opt-out list
+//   first. A failed send is retried on the next free slot after RetryBackoff.
+//
+// Slots are assigned under a process-local lock; run a single API instance per
+// store or back LastSlot with a per-number lock.
+
+// DefaultSenderMPS are typical carrier limits in messages per second.
+var DefaultSenderMPS = map[SenderClass]float64{
+	SenderLongCode:  1,
+	SenderTollFree:  3,
+	SenderShortCode: 100,
+}
+
+const maxBodyLen = 1600
+
+// QueueStore persists outbound messages. Implementations must enforce workspace filtering.
+type QueueStore interface {
+	CreateMessage(ctx context.Context, m OutboundMessage) error
+	UpdateMessage(ctx context.Context, m OutboundMessage) error
+	GetMessage(ctx context.Context, workspaceID, messageID string) (OutboundMessage, bool, error)
+	// LastSlot returns the latest SendAt reserved on number by a queued or sent message.
+	LastSlot(ctx context.Context, workspaceID, number string) (time.Time, bool, error)
+	// DueMessages returns queued messages with SendAt <= now across all
+	// workspaces, earliest first.
+	DueMessages(ctx context.Context, now time.Time, limit int) ([]OutboundMessage, error)
+	ListQueued(ctx context.Context, workspaceID string) ([]OutboundMessage, error)
+}
+
+var (
+	ErrOptedOut = errors.New("messaging: recipient opted out")
+	// errQueueNotConfigured is returned when no QueueStore is set.
+	errQueueNotConfigured = errors.New("messaging: send queue not configured")
+)
+
+// Enqueue validates req and schedules it on the best candidate sender.
+func (s *Service) Enqueue(ctx context.Context, workspaceID string, req SendRequest) (OutboundMessage, error) {
+	req.To = strings.TrimSpace(req.To)
+	if workspaceID == "" || req.To == "" || strings.TrimSpace(req.Body) == "" || len(req.Body) > maxBodyLen || len(req.Senders) == 0 {
+		return OutboundMessage{}, ErrInvalidArgument
+	}
+	for i, snd := range req.Senders {
+		snd.Number = strings.TrimSpace(snd.Number)
+		if snd.Class == "" {
+			snd.Class = SenderLongCode
+		}
+		if snd.Number == "" || s.mps(snd.Class) <= 0 {
+			return OutboundMessage{}, ErrInvalidArgument
+		}
+		req.Senders[i] = snd
+	}
+	if s.Queue == nil {
+		return OutboundMessage{}, errQueueNotConfigured
+	}
+	opted, err := s.optOuts.IsOptedOut(ctx, workspaceID, req.To)
+	if err != nil {
+		return OutboundMessage{}, err
+	}
+	if opted {
+		return OutboundMessage{}, ErrOptedOut
+	}
+
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+	now := s.clock().UTC()
+	var best Sender
+	var bestSlot time.Time
+	for i, snd := range req.Senders {
+		slot, err := s.nextSlotLocked(ctx, workspaceID, snd, now)
+		if err != nil {
+			return OutboundMessage{}, err
+		}
+		if i == 0 || slot.Before(bestSlot) {
+			best, bestSlot = snd, slot
+		}
+	}
+	m := OutboundMessage{
+		ID:          uuid.NewString(),
+		WorkspaceID: workspaceID,
+		From:        best.Number,
+		SenderClass: best.Class,
+		To:          req.To,
+		Body:        req.Body,
+		Status:      MessageQueued,
+		SendAt:      bestSlot,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.Queue.CreateMessage(ctx, m); err != nil {
+		return OutboundMessage{}, err
+	}
+	return m, nil
+}
+
+// GetMessage returns a queued or finished outbound message.
+func (s *Service) GetMessage(ctx context.Context, workspaceID, messageID string) (OutboundMessage, error) {
+	if workspaceID == "" || messageID == "" {
+		return OutboundMessage{}, ErrInvalidArgument
+	}
+	if s.Queue == nil {
+		return OutboundMessage{}, errQueueNotConfigured
+	}
+	m, ok, err := s.Queue.GetMessage(ctx, workspaceID, messageID)
+	if err != nil {
+		return OutboundMessage{}, err
+	}
+	if !ok {
+		return OutboundMessage{}, ErrNotFound
+	}
+	return m, nil
+}
+
+// QueueStats reports queue depth and drain ETA per sending number, by number.
+func (s *Service) QueueStats(ctx context.Context, workspaceID string) ([]SenderQueue, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	if s.Queue == nil {
+		return nil, errQueueNotConfigured
+	}
+	list, err := s.Queue.ListQueued(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	byNumber := map[string]*SenderQueue{}
+	for _, m := range list {
+		q, ok := byNumber[m.From]
+		if !ok {
+			q = &SenderQueue{Number: m.From, Class: m.SenderClass, MPS: s.mps(m.SenderClass)}
+			byNumber[m.From] = q
+		}
+		q.Depth++
+		if m.SendAt.After(q.ETA) {
+			q.ETA = m.SendAt
+		}
+	}
+	out := make([]SenderQueue, 0, len(byNumber))
+	for _, q := range byNumber {
+		out = append(out, *q)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Number < out[j].Number })
+	return out, nil
+}
+
+// reschedule moves m to the first free slot on its number at or after earliest.
+func (s *Service) reschedule(ctx context.Context, m OutboundMessage, earliest time.Time) (OutboundMessage, error) {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+	slot, err := s.nextSlotLocked(ctx, m.WorkspaceID, Sender{Number: m.From, Class: m.SenderClass}, earliest)
+	if err != nil {
+		return m, err
+	}
+	m.SendAt = slot
+	m.UpdatedAt = s.clock().UTC()
+	return m, s.Queue.UpdateMessage(ctx, m)
+}
+
+func (s *Service) nextSlotLocked(ctx context.Context, workspaceID string, snd Sender, earliest time.Time) (time.Time, error) {
+	last, ok, err := s.Queue.LastSlot(ctx, workspaceID, snd.Number)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !ok {
+		return earliest, nil
+	}
+	interval := time.Duration(float64(time.Second) / s.mps(snd.Class))
+	if next := last.Add(interval); next.After(earliest) {
+		return next, nil
+	}
+	return earliest, nil
+}
+
+func (s *Service) mps(class SenderClass) float64 {
+	if v, ok := s.SenderMPS[class]; ok {
+		return v
+	}
+	return DefaultSenderMPS[class]
+}
+
+// SendWorker delivers due messages. Run a single instance per store.
+type SendWorker struct {
+	Messaging *Service
+	Sender    telephony.SMSSender
+
+	BatchSize    int
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	Now          func() time.Time
+}
+
+// RunOnce sends every due message once and returns how many were sent.
+func (w SendWorker) RunOnce(ctx context.Context) (int, error) {
+	s := w.Messaging
+	if s == nil || s.Queue == nil || w.Sender == nil {
+		return 0, errQueueNotConfigured
+	}
+	due, err := s.Queue.DueMessages(ctx, w.now(), w.batchSize())
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, m := range due {
+		ok, err := w.send(ctx, m)
+		if err != nil {
+			logger.From(ctx).Error("sms send failed", "workspace_id", m.WorkspaceID, "message_id", m.ID, "err", err)
+			continue
+		}
+		if ok {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (w SendWorker) send(ctx context.Context, m OutboundMessage) (bool, error) {
+	s := w.Messaging
+	opted, err := s.optOuts.IsOptedOut(ctx, m.WorkspaceID, m.To)
+	if err != nil {
+		return false, err
+	}
+	if opted {
+		m.Status = MessageSkipped
+		m.Error = ErrOptedOut.Error()
+		m.UpdatedAt = w.now()
+		return false, s.Queue.UpdateMessage(ctx, m)
+	}
+
+	m.Attempts++
+	res, sendErr := w.Sender.SendSMS(ctx, telephony.SendSMSRequest{
+		WorkspaceID: m.WorkspaceID,
+		From:        m.From,
+		To:          m.To,
+		Body:        m.Body,
+	})
+	now := w.now()
+	if sendErr == nil {
+		m.Status = MessageSent
+		m.ProviderMessageID = res.ProviderMessageID
+		m.Error = ""
+		m.SentAt = &now
+		m.UpdatedAt = now
+		return true, s.Queue.UpdateMessage(ctx, m)
+	}
+
+	m.Error = sendErr.Error()
+	if m.Attempts >= w.maxAttempts() {
+		m.Status = MessageFailed
+		m.UpdatedAt = now
+		if err := s.Queue.UpdateMessage(ctx, m); err != nil {
+			return false, err
+		}
+		return false, sendErr
+	}
+	if _, err := s.reschedule(ctx, m, now.Add(w.backoff())); err != nil {
+		return false, err
+	}
+	return false, sendErr
+}
+
+// Run sends due messages on every tick until ctx is canceled.
+func (w SendWorker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.RunOnce(ctx); err != nil {
+				logger.From(ctx).Error("sms send run failed", "err", err)
+			}
+		}
+	}
+}
+
+func (w SendWorker) now() time.Time {
+	if w.Now != nil {
+		return w.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (w SendWorker) batchSize() int {
+	if w.BatchSize > 0 {
+		return w.BatchSize
+	}
+	return 100
+}
+
+func (w SendWorker) maxAttempts() int {
+	if w.MaxAttempts > 0 {
+		return w.MaxAttempts
+	}
+	return 3
+}
+
+func (w SendWorker) backoff() time.Duration {
+	if w.RetryBackoff > 0 {
+		return w.RetryBackoff
+	}
+	return 30 * time.Second
+}
@@ -0,0 +1,153 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/telephony"
+)
+
+type stubSMSSender struct {
+	sent []telephony.SendSMSRequest
+	err  error
+}
+
+func (s *stubSMSSender) SendSMS(ctx context.Context, req telephony.SendSMSRequest) (telephony.SendSMSResult, error) {
+	if s.err != nil {
+		return telephony.SendSMSResult{}, s.err
+	}
+	s.sent = append(s.sent, req)
+	return telephony.SendSMSResult{ProviderMessageID: "SM" + req.To}, nil
+}
+
+func newQueueService(now *time.Time) *Service {
+	store := NewMemoryStore()
+	svc := NewService(store, store, store)
+	svc.Queue = store
+	svc.clock = func() time.Time { return *now }
+	return svc
+}
+
+func TestEnqueue_PacesLongCodeAndSpillsToPool(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc := newQueueService(&now)
+
+	pool := []Sender{{Number: "+15550001"}, {Number: "+15550002"}}
+	var got []OutboundMessage
+	for i := 0; i < 4; i++ {
+		m, err := svc.Enqueue(ctx, "ws", SendRequest{To: "+1999000" + string(rune('0'+i)), Body: "hi", Senders: pool})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, m)
+	}
+	// 1 MPS per long code: first two go out now on each number, the next two a second later.
+	want := []struct {
+		from string
+		at   time.Duration
+	}{{"+15550001", 0}, {"+15550002", 0}, {"+15550001", time.Second}, {"+15550002", time.Second}}
+	for i, w := range want {
+		if got[i].From != w.from || !got[i].SendAt.Equal(now.Add(w.at)) {
+			t.Fatalf("message %d: expected %s at +%s, got %s at %s", i, w.from, w.at, got[i].From, got[i].SendAt)
+		}
+	}
+
+	stats, err := svc.QueueStats(ctx, "ws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 || stats[0].Depth != 2 || stats[0].MPS != 1 || !stats[0].ETA.Equal(now.Add(time.Second)) {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestEnqueue_ShortCodeRateAndValidation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc := newQueueService(&now)
+
+	sc := []Sender{{Number: "12345", Class: SenderShortCode}}
+	svc.Enqueue(ctx, "ws", SendRequest{To: "+1999", Body: "a", Senders: sc})
+	m, err := svc.Enqueue(ctx, "ws", SendRequest{To: "+1998", Body: "b", Senders: sc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.SendAt.Equal(now.Add(10 * time.Millisecond)) {
+		t.Fatalf("expected 100 MPS spacing, got %s", m.SendAt.Sub(now))
+	}
+
+	bad := []SendRequest{
+		{Body: "x", Senders: sc},
+		{To: "+1999", Senders: sc},
+		{To: "+1999", Body: "x"},
+		{To: "+1999", Body: "x", Senders: []Sender{{Number: "+1555", Class: "carrier_pigeon"}}},
+	}
+	for _, req := range bad {
+		if _, err := svc.Enqueue(ctx, "ws", req); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("%+v: expected ErrInvalidArgument, got %v", req, err)
+		}
+	}
+}
+
+func TestSendWorker_SendsDueAndHonoursOptOut(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc := newQueueService(&now)
+	sender := &stubSMSSender{}
+	w := SendWorker{Messaging: svc, Sender: sender, Now: func() time.Time { return now }}
+
+	pool := []Sender{{Number: "+15550001"}}
+	first, _ := svc.Enqueue(ctx, "ws", SendRequest{To: "+1999", Body: "one", Senders: pool})
+	second, _ := svc.Enqueue(ctx, "ws", SendRequest{To: "+1998", Body: "two", Senders: pool})
+
+	if n, err := w.RunOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("expected only the first slot to be due, got %d, %v", n, err)
+	}
+	if m, _ := svc.GetMessage(ctx, "ws", first.ID); m.Status != MessageSent || m.ProviderMessageID == "" {
+		t.Fatalf("expected first sent, got %+v", m)
+	}
+
+	// The recipient opts out while the message waits for its slot.
+	if _, err := svc.HandleInbound(ctx, sms("+1998", "+15550001", "STOP")); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Second)
+	if n, _ := w.RunOnce(ctx); n != 0 {
+		t.Fatalf("expected no send to an opted-out recipient, got %d", n)
+	}
+	if m, _ := svc.GetMessage(ctx, "ws", second.ID); m.Status != MessageSkipped {
+		t.Fatalf("expected skipped, got %s", m.Status)
+	}
+	if len(sender.sent) != 1 {
+		t.Fatalf("expected one provider send, got %d", len(sender.sent))
+	}
+
+	if _, err := svc.Enqueue(ctx, "ws", SendRequest{To: "+1998", Body: "three", Senders: pool}); !errors.Is(err, ErrOptedOut) {
+		t.Fatalf("expected ErrOptedOut, got %v", err)
+	}
+}
+
+func TestSendWorker_RetriesThenFails(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc := newQueueService(&now)
+	sender := &stubSMSSender{err: errors.New("provider down")}
+	w := SendWorker{Messaging: svc, Sender: sender, MaxAttempts: 2, RetryBackoff: time.Minute, Now: func() time.Time { return now }}
+
+	m, _ := svc.Enqueue(ctx, "ws", SendRequest{To: "+1999", Body: "hi", Senders: []Sender{{Number: "+15550001"}}})
+	w.RunOnce(ctx)
+	got, _ := svc.GetMessage(ctx, "ws", m.ID)
+	if got.Status != MessageQueued || got.Attempts != 1 || !got.SendAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected retry in a minute, got %+v", got)
+	}
+
+	now = now.Add(time.Minute)
+	w.RunOnce(ctx)
+	got, _ = svc.GetMessage(ctx, "ws", m.ID)
+	if got.Status != MessageFailed || got.Attempts != 2 || got.Error == "" {
+		t.Fatalf("expected failed after two attempts, got %+v", got)
+	}
+}
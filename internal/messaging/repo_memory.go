@@ -0,0 +1,185 @@
+package messaging
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore implements RuleStore, OptOutStore, ConfigStore and QueueStore in
+// memory for tests.
+// It is not intended for production use.
+type MemoryStore struct {
+	mu      sync.Mutex
+	rules   []KeywordRule
+	optOuts map[string]OptOut // workspace|number
+	configs map[string]Config
+	queue   map[string]OutboundMessage
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{optOuts: map[string]OptOut{}, configs: map[string]Config{}, queue: map[string]OutboundMessage{}}
+}
+
+func (s *MemoryStore) PutRule(ctx context.Context, r KeywordRule) (KeywordRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, x := range s.rules {
+		if x.WorkspaceID == r.WorkspaceID && x.Number == r.Number && x.Keyword == r.Keyword {
+			x.Reply = r.Reply
+			x.UpdatedAt = r.UpdatedAt
+			s.rules[i] = x
+			return x, nil
+		}
+	}
+	s.rules = append(s.rules, r)
+	return r, nil
+}
+
+func (s *MemoryStore) DeleteRule(ctx context.Context, workspaceID, ruleID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, r := range s.rules {
+		if r.WorkspaceID == workspaceID && r.ID == ruleID {
+			s.rules = append(s.rules[:i], s.rules[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *MemoryStore) ListRules(ctx context.Context, workspaceID string) ([]KeywordRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]KeywordRule, 0)
+	for _, r := range s.rules {
+		if r.WorkspaceID == workspaceID {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (s *MemoryStore) AddOptOut(ctx context.Context, o OptOut) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := o.WorkspaceID + "|" + o.Number
+	if _, ok := s.optOuts[key]; !ok {
+		s.optOuts[key] = o
+	}
+	return nil
+}
+
+func (s *MemoryStore) RemoveOptOut(ctx context.Context, workspaceID, number string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.optOuts, workspaceID+"|"+number)
+	return nil
+}
+
+func (s *MemoryStore) IsOptedOut(ctx context.Context, workspaceID, number string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.optOuts[workspaceID+"|"+number]
+	return ok, nil
+}
+
+func (s *MemoryStore) ListOptOuts(ctx context.Context, workspaceID string) ([]OptOut, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]OptOut, 0)
+	for _, o := range s.optOuts {
+		if o.WorkspaceID == workspaceID {
+			out = append(out, o)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (s *MemoryStore) GetConfig(ctx context.Context, workspaceID string) (Config, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg, ok := s.configs[workspaceID]
+	return cfg, ok, nil
+}
+
+func (s *MemoryStore) SetConfig(ctx context.Context, cfg Config) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configs[cfg.WorkspaceID] = cfg
+	return nil
+}
+
+func (s *MemoryStore) CreateMessage(ctx context.Context, m OutboundMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue[m.ID] = m
+	return nil
+}
+
+func (s *MemoryStore) UpdateMessage(ctx context.Context, m OutboundMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.queue[m.ID]; !ok {
+		return ErrNotFound
+	}
+	s.queue[m.ID] = m
+	return nil
+}
+
+func (s *MemoryStore) GetMessage(ctx context.Context, workspaceID, messageID string) (OutboundMessage, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.queue[messageID]
+	if !ok || m.WorkspaceID != workspaceID {
+		return OutboundMessage{}, false, nil
+	}
+	return m, true, nil
+}
+
+func (s *MemoryStore) LastSlot(ctx context.Context, workspaceID, number string) (time.Time, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var last time.Time
+	found := false
+	for _, m := range s.queue {
+		if m.WorkspaceID != workspaceID || m.From != number || (m.Status != MessageQueued && m.Status != MessageSent) {
+			continue
+		}
+		if !found || m.SendAt.After(last) {
+			last, found = m.SendAt, true
+		}
+	}
+	return last, found, nil
+}
+
+func (s *MemoryStore) DueMessages(ctx context.Context, now time.Time, limit int) ([]OutboundMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []OutboundMessage
+	for _, m := range s.queue {
+		if m.Status == MessageQueued && !m.SendAt.After(now) {
+			out = append(out, m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].SendAt.Before(out[j].SendAt) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (s *MemoryStore) ListQueued(ctx context.Context, workspaceID string) ([]OutboundMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []OutboundMessage
+	for _, m := range s.queue {
+		if m.WorkspaceID == workspaceID && m.Status == MessageQueued {
+			out = append(out, m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].SendAt.Before(out[j].SendAt) })
+	return out, nil
+}
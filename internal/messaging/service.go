@@ -0,0 +1,332 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"telecom-platform/internal/telephony"
+
+	"github.com/google/uuid"
+)
+
+// Inbound SMS keywords and opt-outs.
+//
+// Every inbound message is checked against the keyword list:
+// - STOP-family keywords always opt the sender out of the workspace's messages
+//   (carrier and TCPA requirement); tenants may only customize the confirmation.
+// - START-family keywords opt the sender back in; HELP/INFO reply with help text.
+// - Tenant keywords reply with the configured text, unless the sender opted out.
+// Keywords match the whole message (case-insensitive, surrounding punctuation
+// ignored), so "please don't stop" is forwarded rather than treated as STOP.
+//
+// Anything else is forwarded to the workspace's ForwardURL. Opt-outs apply to the
+// whole workspace; senders must check IsOptedOut before messaging a recipient.
+
+const (
+	DefaultOptOutReply = "You have been unsubscribed and will receive no further messages. Reply START to resubscribe."
+	DefaultOptInReply  = "You have been resubscribed. Reply STOP to unsubscribe."
+	DefaultHelpReply   = "Reply STOP to unsubscribe. Msg & data rates may apply."
+
+	maxKeywordLen = 20
+	maxReplyLen   = 320
+)
+
+var builtinKeywords = map[string]KeywordAction{
+	"STOP": ActionOptOut, "STOPALL": ActionOptOut, "UNSUBSCRIBE": ActionOptOut,
+	"CANCEL": ActionOptOut, "END": ActionOptOut, "QUIT": ActionOptOut,
+	"START": ActionOptIn, "UNSTOP": ActionOptIn,
+	"HELP": ActionHelp, "INFO": ActionHelp,
+}
+
+// RuleStore persists keyword rules. Implementations must enforce workspace filtering.
+type RuleStore interface {
+	// PutRule upserts by (workspace, number, keyword).
+	PutRule(ctx context.Context, r KeywordRule) (KeywordRule, error)
+	DeleteRule(ctx context.Context, workspaceID, ruleID string) (bool, error)
+	ListRules(ctx context.Context, workspaceID string) ([]KeywordRule, error)
+}
+
+// OptOutStore persists the workspace opt-out list.
+type OptOutStore interface {
+	// AddOptOut is a no-op when the number is already opted out.
+	AddOptOut(ctx context.Context, o OptOut) error
+	RemoveOptOut(ctx context.Context, workspaceID, number string) error
+	IsOptedOut(ctx context.Context, workspaceID, number string) (bool, error)
+	ListOptOuts(ctx context.Context, workspaceID string) ([]OptOut, error)
+}
+
+// ConfigStore persists per-workspace messaging configuration.
+type ConfigStore interface {
+	GetConfig(ctx context.Context, workspaceID string) (Config, bool, error)
+	SetConfig(ctx context.Context, cfg Config) error
+}
+
+// Forwarder delivers an inbound message to a tenant endpoint.
+type Forwarder interface {
+	ForwardSMS(ctx context.Context, url string, msg telephony.InboundSMS) error
+}
+
+var (
+	ErrInvalidArgument = errors.New("messaging: invalid argument")
+	ErrNotFound        = errors.New("messaging: not found")
+)
+
+type Service struct {
+	rules   RuleStore
+	optOuts OptOutStore
+	configs ConfigStore
+	clock   func() time.Time
+
+	// Forwarder is optional; without it non-keyword messages are dropped.
+	Forwarder Forwarder
+
+	// Queue stores outbound messages (see queue.go). Optional.
+	Queue QueueStore
+	// SenderMPS overrides DefaultSenderMPS per sender class.
+	SenderMPS map[SenderClass]float64
+	queueMu   sync.Mutex
+}
+
+func NewService(rules RuleStore, optOuts OptOutStore, configs ConfigStore) *Service {
+	return &Service{rules: rules, optOuts: optOuts, configs: configs, clock: time.Now}
+}
+
+// HandleInbound applies keyword rules to msg or forwards it.
+func (s *Service) HandleInbound(ctx context.Context, msg telephony.InboundSMS) (Outcome, error) {
+	if msg.WorkspaceID == "" || msg.From == "" {
+		return Outcome{}, ErrInvalidArgument
+	}
+	kw := normalizeKeyword(msg.Body)
+	action, builtin := builtinKeywords[kw]
+	rule, hasRule, err := s.matchRule(ctx, msg.WorkspaceID, msg.To, kw)
+	if err != nil {
+		return Outcome{}, err
+	}
+	if !builtin && !hasRule {
+		return s.forward(ctx, msg)
+	}
+	if !builtin {
+		action = ActionReply
+	}
+
+	out := Outcome{Keyword: kw, Action: action}
+	switch action {
+	case ActionOptOut:
+		err = s.optOuts.AddOptOut(ctx, OptOut{
+			WorkspaceID:       msg.WorkspaceID,
+			Number:            msg.From,
+			Keyword:           kw,
+			ProviderMessageID: msg.ProviderMessageID,
+			ReceivedOn:        msg.To,
+			CreatedAt:         s.clock().UTC(),
+		})
+		out.Reply = DefaultOptOutReply
+	case ActionOptIn:
+		err = s.optOuts.RemoveOptOut(ctx, msg.WorkspaceID, msg.From)
+		out.Reply = DefaultOptInReply
+	case ActionHelp:
+		out.Reply = DefaultHelpReply
+	case ActionReply:
+		var opted bool
+		opted, err = s.optOuts.IsOptedOut(ctx, msg.WorkspaceID, msg.From)
+		if opted {
+			// Marketing replies to an opted-out sender are not allowed.
+			return Outcome{Keyword: kw, Action: action}, nil
+		}
+	}
+	if err != nil {
+		return Outcome{}, err
+	}
+	if hasRule {
+		out.Reply = rule.Reply
+	}
+	return out, nil
+}
+
+// RespondSMS implements telephony.SMSResponder.
+func (s *Service) RespondSMS(ctx context.Context, msg telephony.InboundSMS) (string, error) {
+	out, err := s.HandleInbound(ctx, msg)
+	return out.Reply, err
+}
+
+// IsOptedOut reports whether number asked not to receive the workspace's messages.
+func (s *Service) IsOptedOut(ctx context.Context, workspaceID, number string) (bool, error) {
+	if workspaceID == "" || number == "" {
+		return false, ErrInvalidArgument
+	}
+	return s.optOuts.IsOptedOut(ctx, workspaceID, number)
+}
+
+// ListOptOuts returns the workspace's opt-out list.
+func (s *Service) ListOptOuts(ctx context.Context, workspaceID string) ([]OptOut, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	return s.optOuts.ListOptOuts(ctx, workspaceID)
+}
+
+// PutRule creates or replaces the reply for keyword on number ("" for all numbers).
+func (s *Service) PutRule(ctx context.Context, workspaceID, number, keyword, reply string) (KeywordRule, error) {
+	keyword = normalizeKeyword(keyword)
+	reply = strings.TrimSpace(reply)
+	if workspaceID == "" || !validKeyword(keyword) || reply == "" || len(reply) > maxReplyLen {
+		return KeywordRule{}, ErrInvalidArgument
+	}
+	now := s.clock().UTC()
+	r, err := s.rules.PutRule(ctx, KeywordRule{
+		ID:          uuid.NewString(),
+		WorkspaceID: workspaceID,
+		Number:      strings.TrimSpace(number),
+		Keyword:     keyword,
+		Reply:       reply,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return KeywordRule{}, err
+	}
+	r.Action = actionFor(r.Keyword)
+	return r, nil
+}
+
+// ListRules returns the workspace's keyword rules.
+func (s *Service) ListRules(ctx context.Context, workspaceID string) ([]KeywordRule, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	list, err := s.rules.ListRules(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].Action = actionFor(list[i].Keyword)
+	}
+	return list, nil
+}
+
+// DeleteRule removes a rule. Built-in keywords fall back to their default reply.
+func (s *Service) DeleteRule(ctx context.Context, workspaceID, ruleID string) error {
+	if workspaceID == "" || ruleID == "" {
+		return ErrInvalidArgument
+	}
+	ok, err := s.rules.DeleteRule(ctx, workspaceID, ruleID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetConfig returns the workspace's messaging configuration (zero value when unset).
+func (s *Service) GetConfig(ctx context.Context, workspaceID string) (Config, error) {
+	if workspaceID == "" {
+		return Config{}, ErrInvalidArgument
+	}
+	cfg, ok, err := s.configs.GetConfig(ctx, workspaceID)
+	if err != nil {
+		return Config{}, err
+	}
+	if !ok {
+		cfg = Config{WorkspaceID: workspaceID}
+	}
+	return cfg, nil
+}
+
+// SetForwardURL sets where non-keyword messages are delivered ("" stops forwarding).
+func (s *Service) SetForwardURL(ctx context.Context, workspaceID, forwardURL string) (Config, error) {
+	forwardURL = strings.TrimSpace(forwardURL)
+	if workspaceID == "" {
+		return Config{}, ErrInvalidArgument
+	}
+	if forwardURL != "" {
+		u, err := url.Parse(forwardURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return Config{}, fmt.Errorf("%w: forward_url must be an https url", ErrInvalidArgument)
+		}
+	}
+	cfg := Config{WorkspaceID: workspaceID, ForwardURL: forwardURL, UpdatedAt: s.clock().UTC()}
+	if err := s.configs.SetConfig(ctx, cfg); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func (s *Service) forward(ctx context.Context, msg telephony.InboundSMS) (Outcome, error) {
+	if s.Forwarder == nil {
+		return Outcome{}, nil
+	}
+	cfg, ok, err := s.configs.GetConfig(ctx, msg.WorkspaceID)
+	if err != nil {
+		return Outcome{}, err
+	}
+	if !ok || cfg.ForwardURL == "" {
+		return Outcome{}, nil
+	}
+	if err := s.Forwarder.ForwardSMS(ctx, cfg.ForwardURL, msg); err != nil {
+		return Outcome{}, fmt.Errorf("messaging: forward: %w", err)
+	}
+	return Outcome{Forwarded: true}, nil
+}
+
+// matchRule prefers a rule for the receiving number over a workspace-wide one.
+func (s *Service) matchRule(ctx context.Context, workspaceID, number, keyword string) (KeywordRule, bool, error) {
+	if keyword == "" {
+		return KeywordRule{}, false, nil
+	}
+	list, err := s.rules.ListRules(ctx, workspaceID)
+	if err != nil {
+		return KeywordRule{}, false, err
+	}
+	var fallback *KeywordRule
+	for i, r := range list {
+		if r.Keyword != keyword {
+			continue
+		}
+		if r.Number == number {
+			return r, true, nil
+		}
+		if r.Number == "" {
+			fallback = &list[i]
+		}
+	}
+	if fallback != nil {
+		return *fallback, true, nil
+	}
+	return KeywordRule{}, false, nil
+}
+
+func actionFor(keyword string) KeywordAction {
+	if a, ok := builtinKeywords[keyword]; ok {
+		return a
+	}
+	return ActionReply
+}
+
+// normalizeKeyword returns the message as a keyword candidate: trimmed, uppercased,
+// without surrounding punctuation. Multi-word messages never match.
+func normalizeKeyword(body string) string {
+	kw := strings.ToUpper(strings.Trim(strings.TrimSpace(body), ".!?,;:\"' "))
+	if strings.ContainsAny(kw, " \t\r\n") {
+		return ""
+	}
+	return kw
+}
+
+func validKeyword(kw string) bool {
+	if kw == "" || len(kw) > maxKeywordLen {
+		return false
+	}
+	for _, r := range kw {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"telecom-platform/internal/telephony"
+)
+
+type stubForwarder struct {
+	urls []string
+	msgs []telephony.InboundSMS
+	err  error
+}
+
+func (f *stubForwarder) ForwardSMS(ctx context.Context, url string, msg telephony.InboundSMS) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.urls = append(f.urls, url)
+	f.msgs = append(f.msgs, msg)
+	return nil
+}
+
+func sms(from, to, body string) telephony.InboundSMS {
+	return telephony.InboundSMS{WorkspaceID: "ws", ProviderMessageID: "SM-" + body, From: from, To: to, Body: body}
+}
+
+func newTestService() (*Service, *stubForwarder) {
+	store := NewMemoryStore()
+	svc := NewService(store, store, store)
+	fwd := &stubForwarder{}
+	svc.Forwarder = fwd
+	return svc, fwd
+}
+
+func TestHandleInbound_StopAndStart(t *testing.T) {
+	ctx := context.Background()
+	svc, fwd := newTestService()
+
+	out, err := svc.HandleInbound(ctx, sms("+15551230000", "+15550001111", " Stop. "))
+	if err != nil || out.Action != ActionOptOut || out.Reply != DefaultOptOutReply {
+		t.Fatalf("unexpected outcome: %+v %v", out, err)
+	}
+	if opted, _ := svc.IsOptedOut(ctx, "ws", "+15551230000"); !opted {
+		t.Fatalf("expected sender opted out")
+	}
+	list, _ := svc.ListOptOuts(ctx, "ws")
+	if len(list) != 1 || list[0].Keyword != "STOP" || list[0].ReceivedOn != "+15550001111" || list[0].ProviderMessageID == "" {
+		t.Fatalf("unexpected opt-out record: %+v", list)
+	}
+	// A repeated STOP keeps the original evidence.
+	_, _ = svc.HandleInbound(ctx, sms("+15551230000", "+15550002222", "unsubscribe"))
+	if list, _ := svc.ListOptOuts(ctx, "ws"); len(list) != 1 || list[0].Keyword != "STOP" {
+		t.Fatalf("expected original opt-out kept, got %+v", list)
+	}
+
+	out, _ = svc.HandleInbound(ctx, sms("+15551230000", "+15550001111", "start"))
+	if out.Action != ActionOptIn || out.Reply != DefaultOptInReply {
+		t.Fatalf("unexpected outcome: %+v", out)
+	}
+	if opted, _ := svc.IsOptedOut(ctx, "ws", "+15551230000"); opted {
+		t.Fatalf("expected sender opted back in")
+	}
+	if len(fwd.msgs) != 0 {
+		t.Fatalf("keywords must not be forwarded")
+	}
+}
+
+func TestHandleInbound_CustomRules(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService()
+
+	if _, err := svc.PutRule(ctx, "ws", "", "hours", "Open 9-5 Mon-Fri."); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.PutRule(ctx, "ws", "+15550001111", "HOURS", "Store A: open 10-6."); err != nil {
+		t.Fatal(err)
+	}
+	stop, err := svc.PutRule(ctx, "ws", "", "stop", "Acme: unsubscribed.")
+	if err != nil || stop.Action != ActionOptOut {
+		t.Fatalf("STOP rule must keep opt-out action, got %+v %v", stop, err)
+	}
+
+	if out, _ := svc.HandleInbound(ctx, sms("+15551230000", "+15550001111", "hours")); out.Reply != "Store A: open 10-6." {
+		t.Fatalf("expected number-specific reply, got %+v", out)
+	}
+	if out, _ := svc.HandleInbound(ctx, sms("+15551230000", "+15550002222", "Hours!")); out.Reply != "Open 9-5 Mon-Fri." || out.Action != ActionReply {
+		t.Fatalf("expected workspace reply, got %+v", out)
+	}
+
+	out, _ := svc.HandleInbound(ctx, sms("+15551230000", "+15550002222", "STOP"))
+	if out.Action != ActionOptOut || out.Reply != "Acme: unsubscribed." {
+		t.Fatalf("expected custom STOP confirmation, got %+v", out)
+	}
+	if out, _ := svc.HandleInbound(ctx, sms("+15551230000", "+15550002222", "hours")); out.Reply != "" {
+		t.Fatalf("opted-out sender must not get keyword replies, got %+v", out)
+	}
+
+	for _, kw := range []string{"", "two words", "TOOLONGKEYWORDTOOLONGKEYWORD", "ÄPFEL"} {
+		if _, err := svc.PutRule(ctx, "ws", "", kw, "x"); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("%q: expected ErrInvalidArgument, got %v", kw, err)
+		}
+	}
+}
+
+func TestHandleInbound_ForwardsOtherMessages(t *testing.T) {
+	ctx := context.Background()
+	svc, fwd := newTestService()
+
+	if out, err := svc.HandleInbound(ctx, sms("+15551230000", "+15550001111", "please don't stop")); err != nil || out.Forwarded {
+		t.Fatalf("without a forward url nothing is forwarded: %+v %v", out, err)
+	}
+	if _, err := svc.SetForwardURL(ctx, "ws", "http://insecure.example"); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected https to be required, got %v", err)
+	}
+	if _, err := svc.SetForwardURL(ctx, "ws", "https://tenant.example/sms"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := svc.HandleInbound(ctx, sms("+15551230000", "+15550001111", "please don't stop"))
+	if err != nil || !out.Forwarded || out.Action != "" {
+		t.Fatalf("unexpected outcome: %+v %v", out, err)
+	}
+	if len(fwd.urls) != 1 || fwd.urls[0] != "https://tenant.example/sms" || fwd.msgs[0].Body != "please don't stop" {
+		t.Fatalf("unexpected forward: %v %+v", fwd.urls, fwd.msgs)
+	}
+	if opted, _ := svc.IsOptedOut(ctx, "ws", "+15551230000"); opted {
+		t.Fatalf("a sentence containing stop must not opt out")
+	}
+
+	fwd.err = errors.New("connection refused")
+	if _, err := svc.HandleInbound(ctx, sms("+15551230000", "+15550001111", "hello")); err == nil {
+		t.Fatalf("expected forward error to surface so the provider retries")
+	}
+}
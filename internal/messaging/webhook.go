@@ -0,0 +1,74 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"telecom-platform/internal/telephony"
+)
+
+// WebhookForwarder POSTs inbound messages as JSON to the tenant's ForwardURL.
+// X-Message-ID carries the provider message id so receivers can drop redelivered
+// messages; when Secret is set, X-Message-Signature is hex(HMAC-SHA256(secret, body)).
+type WebhookForwarder struct {
+	Client *http.Client
+	Secret string
+
+	// Signer adds the workspace's X-Webhook-Signature (see internal/webhooks). Optional.
+	Signer PayloadSigner
+}
+
+// PayloadSigner signs a webhook body with the workspace's signing keys and returns
+// the header value ("" when the workspace has none).
+type PayloadSigner interface {
+	Sign(ctx context.Context, workspaceID string, body []byte) (string, error)
+}
+
+func (f WebhookForwarder) ForwardSMS(ctx context.Context, url string, msg telephony.InboundSMS) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Message-ID", msg.ProviderMessageID)
+	if f.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(f.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Message-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	if f.Signer != nil {
+		sig, err := f.Signer.Sign(ctx, msg.WorkspaceID, body)
+		if err != nil {
+			return err
+		}
+		if sig != "" {
+			req.Header.Set("X-Webhook-Signature", sig)
+		}
+	}
+
+	client := f.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("messaging: forward endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package noc
+
+import "time"
+
+// Network operations console (NOC) models.
+//
+// These are platform-wide operational controls, not tenant data. They are only
+// reachable through /v1/noc, which is restricted to the hidden network_operator
+// role (and super_admin).
+
+// ProviderCallCount is the number of live calls on a provider right now.
+type ProviderCallCount struct {
+	Provider    string `json:"provider"`
+	ActiveCalls int    `json:"active_calls"`
+}
+
+type TrunkState string
+
+const (
+	TrunkUp       TrunkState = "up"
+	TrunkDegraded TrunkState = "degraded"
+	TrunkDown     TrunkState = "down"
+)
+
+// TrunkHealth is the latest health sample for a SIP trunk / provider connection.
+type TrunkHealth struct {
+	Provider string     `json:"provider"`
+	TrunkID  string     `json:"trunk_id"`
+	State    TrunkState `json:"state"`
+
+	// AnswerSeizureRatio and PostDialDelayMs are rolling-window metrics.
+	AnswerSeizureRatio float64 `json:"asr"`
+	PostDialDelayMs    int     `json:"pdd_ms"`
+
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// EmergencyStop halts new outbound call attempts platform-wide (Provider == "")
+// or for a single provider. In-flight calls are not torn down.
+type EmergencyStop struct {
+	Provider string `json:"provider,omitempty"`
+	Reason   string `json:"reason"`
+
+	EngagedBy string    `json:"engaged_by"`
+	EngagedAt time.Time `json:"engaged_at"`
+}
+
+// RerouteRule sends new calls destined for FromProvider to ToProvider until ExpiresAt.
+type RerouteRule struct {
+	ID           string `json:"id" db:"id"`
+	FromProvider string `json:"from_provider" db:"from_provider"`
+	ToProvider   string `json:"to_provider" db:"to_provider"`
+	Reason       string `json:"reason" db:"reason"`
+
+	CreatedBy string    `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+}
@@ -0,0 +1,46 @@
+package noc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"telecom-platform/internal/telephony"
+)
+
+// ErrCallsStopped is returned for call attempts halted by an emergency stop.
+var ErrCallsStopped = errors.New("noc: new calls are halted by an emergency stop")
+
+// OriginationGuard applies NOC controls to outbound calls: the call goes out on
+// Provider, or where an active reroute sends it, unless an emergency stop covers
+// that provider. Wrap it around the other origination guards (dial plan, caller
+// ID) so every placed call passes through it.
+type OriginationGuard struct {
+	NOC *Service
+	// Provider is the provider calls use when no reroute applies.
+	Provider string
+	// Providers places calls per provider name (lower case); reroute targets must
+	// be present.
+	Providers map[string]telephony.CallOriginator
+}
+
+func (g OriginationGuard) OriginateCall(ctx context.Context, req telephony.OriginateCallRequest) (string, error) {
+	provider, err := g.NOC.ResolveProvider(ctx, g.Provider)
+	if err != nil {
+		return "", err
+	}
+	provider = strings.ToLower(provider)
+	stopped, err := g.NOC.IsStopped(ctx, provider)
+	if err != nil {
+		return "", err
+	}
+	if stopped {
+		return "", ErrCallsStopped
+	}
+	next, ok := g.Providers[provider]
+	if !ok {
+		return "", fmt.Errorf("%w: no originator for provider %q", ErrNotConfigured, provider)
+	}
+	return next.OriginateCall(ctx, req)
+}
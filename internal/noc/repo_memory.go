@@ -0,0 +1,75 @@
+package noc
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryStore is a simple in-memory ControlStore for tests and single-instance
+// development. It is not intended for production use (state is per process).
+type MemoryStore struct {
+	mu       sync.Mutex
+	stops    map[string]EmergencyStop // key: provider ("" = global)
+	reroutes map[string]RerouteRule
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{stops: map[string]EmergencyStop{}, reroutes: map[string]RerouteRule{}}
+}
+
+func (s *MemoryStore) ListStops(ctx context.Context) ([]EmergencyStop, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]EmergencyStop, 0, len(s.stops))
+	for _, st := range s.stops {
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Provider < out[j].Provider })
+	return out, nil
+}
+
+func (s *MemoryStore) SetStop(ctx context.Context, st EmergencyStop) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stops[st.Provider] = st
+	return nil
+}
+
+func (s *MemoryStore) ClearStop(ctx context.Context, provider string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.stops[provider]; !ok {
+		return false, nil
+	}
+	delete(s.stops, provider)
+	return true, nil
+}
+
+func (s *MemoryStore) CreateReroute(ctx context.Context, r RerouteRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reroutes[r.ID] = r
+	return nil
+}
+
+func (s *MemoryStore) ListReroutes(ctx context.Context) ([]RerouteRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]RerouteRule, 0, len(s.reroutes))
+	for _, r := range s.reroutes {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (s *MemoryStore) DeleteReroute(ctx context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.reroutes[id]; !ok {
+		return false, nil
+	}
+	delete(s.reroutes, id)
+	return true, nil
+}
@@ -0,0 +1,234 @@
+package noc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/audit"
+
+	"github.com/google/uuid"
+)
+
+// Service backs the network operator console.
+//
+// Rules:
+// - Read endpoints (call counts, trunk health) come from injected live sources.
+// - Control actions (emergency stop, reroute) are audited in the platform stream.
+// - Reroutes are always time-bounded.
+// - Outbound calls go through OriginationGuard, which applies stops and reroutes.
+type Service struct {
+	calls  LiveCallSource
+	trunks TrunkHealthSource
+	store  ControlStore
+	audit  *audit.Service
+	clock  func() time.Time
+
+	// MaxRerouteTTL caps how long a reroute may last.
+	MaxRerouteTTL time.Duration
+}
+
+// LiveCallSource reports live call counts (e.g. from the call state Redis keys).
+type LiveCallSource interface {
+	ActiveCallsByProvider(ctx context.Context) (map[string]int, error)
+}
+
+// TrunkHealthSource reports the latest trunk health samples.
+type TrunkHealthSource interface {
+	TrunkHealth(ctx context.Context) ([]TrunkHealth, error)
+}
+
+// ControlStore persists NOC control state. It must be shared by every API/worker
+// instance (Redis or Postgres) so a stop takes effect everywhere.
+type ControlStore interface {
+	ListStops(ctx context.Context) ([]EmergencyStop, error)
+	SetStop(ctx context.Context, s EmergencyStop) error
+	ClearStop(ctx context.Context, provider string) (bool, error)
+
+	CreateReroute(ctx context.Context, r RerouteRule) error
+	ListReroutes(ctx context.Context) ([]RerouteRule, error)
+	DeleteReroute(ctx context.Context, id string) (bool, error)
+}
+
+func NewService(calls LiveCallSource, trunks TrunkHealthSource, store ControlStore, auditSvc *audit.Service) *Service {
+	return &Service{calls: calls, trunks: trunks, store: store, audit: auditSvc, clock: time.Now, MaxRerouteTTL: 24 * time.Hour}
+}
+
+var (
+	ErrInvalidArgument = errors.New("noc: invalid argument")
+	ErrNotFound        = errors.New("noc: not found")
+	ErrNotConfigured   = errors.New("noc: source not configured")
+	// ErrNotAudited is returned with the result of a control action that took
+	// effect but could not be recorded in the audit log.
+	ErrNotAudited = errors.New("noc: action applied but not audited")
+)
+
+// LiveCallCounts returns live calls per provider, sorted by provider.
+func (s *Service) LiveCallCounts(ctx context.Context) ([]ProviderCallCount, error) {
+	if s.calls == nil {
+		return nil, ErrNotConfigured
+	}
+	m, err := s.calls.ActiveCallsByProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ProviderCallCount, 0, len(m))
+	for p, n := range m {
+		out = append(out, ProviderCallCount{Provider: p, ActiveCalls: n})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Provider < out[j].Provider })
+	return out, nil
+}
+
+func (s *Service) TrunkHealth(ctx context.Context) ([]TrunkHealth, error) {
+	if s.trunks == nil {
+		return nil, ErrNotConfigured
+	}
+	return s.trunks.TrunkHealth(ctx)
+}
+
+func (s *Service) EmergencyStops(ctx context.Context) ([]EmergencyStop, error) {
+	return s.store.ListStops(ctx)
+}
+
+// EngageStop halts new calls globally (provider == "") or for one provider.
+func (s *Service) EngageStop(ctx context.Context, actorUserID, actorRole, provider, reason string) (EmergencyStop, error) {
+	if actorUserID == "" || strings.TrimSpace(reason) == "" {
+		return EmergencyStop{}, ErrInvalidArgument
+	}
+	st := EmergencyStop{Provider: strings.ToLower(strings.TrimSpace(provider)), Reason: reason, EngagedBy: actorUserID, EngagedAt: s.clock().UTC()}
+	if err := s.store.SetStop(ctx, st); err != nil {
+		return EmergencyStop{}, err
+	}
+	return st, s.log(ctx, actorUserID, actorRole, fmt.Sprintf("emergency stop engaged (%s): %s", scopeName(st.Provider), reason))
+}
+
+func (s *Service) ReleaseStop(ctx context.Context, actorUserID, actorRole, provider string) error {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	ok, err := s.store.ClearStop(ctx, provider)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotFound
+	}
+	return s.log(ctx, actorUserID, actorRole, fmt.Sprintf("emergency stop released (%s)", scopeName(provider)))
+}
+
+// IsStopped reports whether new calls on provider are halted (globally or per provider).
+func (s *Service) IsStopped(ctx context.Context, provider string) (bool, error) {
+	stops, err := s.store.ListStops(ctx)
+	if err != nil {
+		return false, err
+	}
+	provider = strings.ToLower(provider)
+	for _, st := range stops {
+		if st.Provider == "" || st.Provider == provider {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type RerouteRequest struct {
+	FromProvider string        `json:"from_provider"`
+	ToProvider   string        `json:"to_provider"`
+	Reason       string        `json:"reason"`
+	TTL          time.Duration `json:"-"`
+	TTLSeconds   int           `json:"ttl_seconds"`
+}
+
+func (s *Service) CreateReroute(ctx context.Context, actorUserID, actorRole string, req RerouteRequest) (RerouteRule, error) {
+	from := strings.ToLower(strings.TrimSpace(req.FromProvider))
+	to := strings.ToLower(strings.TrimSpace(req.ToProvider))
+	if actorUserID == "" || from == "" || to == "" || from == to || strings.TrimSpace(req.Reason) == "" {
+		return RerouteRule{}, ErrInvalidArgument
+	}
+	ttl := req.TTL
+	if ttl == 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	if ttl <= 0 || ttl > s.MaxRerouteTTL {
+		return RerouteRule{}, ErrInvalidArgument
+	}
+
+	now := s.clock().UTC()
+	r := RerouteRule{
+		ID:           uuid.NewString(),
+		FromProvider: from,
+		ToProvider:   to,
+		Reason:       req.Reason,
+		CreatedBy:    actorUserID,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(ttl),
+	}
+	if err := s.store.CreateReroute(ctx, r); err != nil {
+		return RerouteRule{}, err
+	}
+	return r, s.log(ctx, actorUserID, actorRole, fmt.Sprintf("reroute %s -> %s until %s: %s", from, to, r.ExpiresAt.Format(time.RFC3339), req.Reason))
+}
+
+// ActiveReroutes lists unexpired reroutes.
+func (s *Service) ActiveReroutes(ctx context.Context) ([]RerouteRule, error) {
+	all, err := s.store.ListReroutes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := s.clock()
+	out := make([]RerouteRule, 0, len(all))
+	for _, r := range all {
+		if now.Before(r.ExpiresAt) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (s *Service) DeleteReroute(ctx context.Context, actorUserID, actorRole, id string) error {
+	ok, err := s.store.DeleteReroute(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotFound
+	}
+	return s.log(ctx, actorUserID, actorRole, "reroute removed: "+id)
+}
+
+// ResolveProvider follows active reroutes (one hop) for provider.
+func (s *Service) ResolveProvider(ctx context.Context, provider string) (string, error) {
+	rules, err := s.ActiveReroutes(ctx)
+	if err != nil {
+		return "", err
+	}
+	p := strings.ToLower(provider)
+	for _, r := range rules {
+		if r.FromProvider == p {
+			return r.ToProvider, nil
+		}
+	}
+	return provider, nil
+}
+
+// log records a control action in the platform audit stream. Actions are applied
+// first, so a stop is never held up by an audit outage; a failed record is
+// returned as ErrNotAudited.
+func (s *Service) log(ctx context.Context, actorUserID, actorRole, message string) error {
+	if s.audit == nil {
+		return nil
+	}
+	if err := s.audit.LogPlatformAction(ctx, audit.EventTypeNOCAction, actorUserID, actorRole, "noc: "+message, ""); err != nil {
+		return fmt.Errorf("%w: %v", ErrNotAudited, err)
+	}
+	return nil
+}
+
+func scopeName(provider string) string {
+	if provider == "" {
+		return "global"
+	}
+	return "provider " + provider
+}
@@ -0,0 +1,97 @@
+package noc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/audit"
+	"telecom-platform/internal/telephony"
+)
+
+type staticCalls map[string]int
+
+func (s staticCalls) ActiveCallsByProvider(ctx context.Context) (map[string]int, error) {
+	return s, nil
+}
+
+func TestService_EmergencyStopScopes(t *testing.T) {
+	ctx := context.Background()
+	repo := audit.NewMemoryRepo()
+	svc := NewService(staticCalls{"twilio": 3, "bandwidth": 1}, nil, NewMemoryStore(), audit.NewService(repo))
+
+	counts, err := svc.LiveCallCounts(ctx)
+	if err != nil || len(counts) != 2 || counts[0].Provider != "bandwidth" {
+		t.Fatalf("unexpected counts %+v (%v)", counts, err)
+	}
+
+	if _, err := svc.EngageStop(ctx, "op", "network_operator", "Twilio", "carrier outage"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if stopped, _ := svc.IsStopped(ctx, "twilio"); !stopped {
+		t.Fatalf("expected twilio stopped")
+	}
+	if stopped, _ := svc.IsStopped(ctx, "bandwidth"); stopped {
+		t.Fatalf("expected bandwidth unaffected")
+	}
+	if err := svc.ReleaseStop(ctx, "op", "network_operator", "twilio"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if err := svc.ReleaseStop(ctx, "op", "network_operator", "twilio"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	evs := repo.Events()
+	if len(evs) != 2 || evs[0].WorkspaceID != audit.PlatformScope || evs[0].Type != audit.EventTypeNOCAction {
+		t.Fatalf("expected control actions in the platform audit stream, got %+v", evs)
+	}
+}
+
+func TestService_RerouteIsTimeBounded(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0).UTC()
+	svc := NewService(nil, nil, NewMemoryStore(), nil)
+	svc.clock = func() time.Time { return now }
+
+	if _, err := svc.CreateReroute(ctx, "op", "network_operator", RerouteRequest{FromProvider: "twilio", ToProvider: "bandwidth", Reason: "x", TTL: 48 * time.Hour}); err != ErrInvalidArgument {
+		t.Fatalf("expected TTL cap enforced, got %v", err)
+	}
+	if _, err := svc.CreateReroute(ctx, "op", "network_operator", RerouteRequest{FromProvider: "twilio", ToProvider: "bandwidth", Reason: "x", TTL: time.Hour}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if p, _ := svc.ResolveProvider(ctx, "twilio"); p != "bandwidth" {
+		t.Fatalf("expected reroute to bandwidth, got %q", p)
+	}
+	now = now.Add(2 * time.Hour)
+	if p, _ := svc.ResolveProvider(ctx, "twilio"); p != "twilio" {
+		t.Fatalf("expected reroute expired, got %q", p)
+	}
+}
+
+type recordingOriginator struct{ calls int }
+
+func (o *recordingOriginator) OriginateCall(ctx context.Context, req telephony.OriginateCallRequest) (string, error) {
+	o.calls++
+	return "CA1", nil
+}
+
+func TestOriginationGuard_AppliesStopsAndReroutes(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(nil, nil, NewMemoryStore(), nil)
+	twilio, bandwidth := &recordingOriginator{}, &recordingOriginator{}
+	g := OriginationGuard{NOC: svc, Provider: "twilio", Providers: map[string]telephony.CallOriginator{"twilio": twilio, "bandwidth": bandwidth}}
+	req := telephony.OriginateCallRequest{WorkspaceID: "w", From: "+14155550100", To: "+14155550101", AnswerURL: "https://x"}
+
+	if _, err := svc.CreateReroute(ctx, "op", "network_operator", RerouteRequest{FromProvider: "twilio", ToProvider: "bandwidth", Reason: "x", TTL: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.OriginateCall(ctx, req); err != nil || bandwidth.calls != 1 || twilio.calls != 0 {
+		t.Fatalf("expected call rerouted to bandwidth, got err=%v twilio=%d bandwidth=%d", err, twilio.calls, bandwidth.calls)
+	}
+	if _, err := svc.EngageStop(ctx, "op", "network_operator", "", "incident"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.OriginateCall(ctx, req); !errors.Is(err, ErrCallsStopped) || bandwidth.calls != 1 {
+		t.Fatalf("expected global stop to halt the call, got %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package numbers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/telephony"
+
+	"github.com/google/uuid"
+)
+
+// Verified caller IDs.
+//
+// Outbound calls may only present a number the workspace owns (inventory) or an
+// external number it has verified through the provider (see
+// telephony.CallerIDVerifier). Verification is one record per workspace and
+// number: pending while the provider's validation call is in progress, then
+// verified or failed from the provider callback. A pending request with no result
+// after VerificationTimeout may be started again.
+
+type CallerIDVerificationStatus string
+
+const (
+	CallerIDPending  CallerIDVerificationStatus = "pending"
+	CallerIDVerified CallerIDVerificationStatus = "verified"
+	CallerIDFailed   CallerIDVerificationStatus = "failed"
+)
+
+// VerificationTimeout is how long a pending verification blocks a new attempt.
+const VerificationTimeout = 10 * time.Minute
+
+var (
+	ErrCallerIDNotVerified        = errors.New("numbers: caller id is not owned or verified")
+	ErrCallerIDNotFound           = errors.New("numbers: caller id not found")
+	ErrVerificationPending        = errors.New("numbers: caller id verification already in progress")
+	ErrVerificationUnsupported    = errors.New("numbers: provider does not support caller id verification")
+	errVerificationsNotConfigured = errors.New("numbers: caller id store not configured")
+)
+
+// VerifiedCallerID is an external number registered as a workspace caller ID.
+type VerifiedCallerID struct {
+	ID           string                     `json:"id" db:"id"`
+	WorkspaceID  string                     `json:"workspace_id" db:"workspace_id"`
+	Number       string                     `json:"number" db:"number"` // E.164
+	FriendlyName string                     `json:"friendly_name,omitempty" db:"friendly_name"`
+	Status       CallerIDVerificationStatus `json:"status" db:"status"`
+
+	// ValidationCode is shown to the tenant while pending, to enter on the call.
+	ValidationCode string `json:"validation_code,omitempty" db:"validation_code"`
+	ProviderCallID string `json:"provider_call_id,omitempty" db:"provider_call_id"`
+	Attempts       int    `json:"attempts" db:"attempts"`
+
+	RequestedAt time.Time  `json:"requested_at" db:"requested_at"`
+	VerifiedAt  *time.Time `json:"verified_at,omitempty" db:"verified_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// VerifiedCallerIDStore persists verified caller IDs. Implementations must enforce
+// workspace filtering.
+type VerifiedCallerIDStore interface {
+	PutVerifiedCallerID(ctx context.Context, v VerifiedCallerID) error
+	GetVerifiedCallerID(ctx context.Context, workspaceID, id string) (VerifiedCallerID, bool, error)
+	FindVerifiedCallerID(ctx context.Context, workspaceID, number string) (VerifiedCallerID, bool, error)
+	ListVerifiedCallerIDs(ctx context.Context, workspaceID string) ([]VerifiedCallerID, error)
+	DeleteVerifiedCallerID(ctx context.Context, workspaceID, id string) (bool, error)
+}
+
+// StartCallerIDVerification asks the provider to call number with a validation
+// code. Owned numbers need no verification; verified numbers are returned as is.
+func (s *Service) StartCallerIDVerification(ctx context.Context, workspaceID, number, friendlyName string) (VerifiedCallerID, error) {
+	number = strings.TrimSpace(number)
+	if workspaceID == "" || len(number) < 8 || number[0] != '+' || !allDigits(number[1:]) {
+		return VerifiedCallerID{}, ErrInvalidArgument
+	}
+	if s.VerifiedCallerIDs == nil {
+		return VerifiedCallerID{}, errVerificationsNotConfigured
+	}
+	if s.Inventory != nil {
+		if _, owned, err := s.Inventory.GetNumber(ctx, workspaceID, number); err != nil {
+			return VerifiedCallerID{}, err
+		} else if owned {
+			return VerifiedCallerID{}, fmt.Errorf("%w: number is owned by the workspace", ErrInvalidArgument)
+		}
+	}
+	verifier, ok := s.provider.(telephony.CallerIDVerifier)
+	if !ok {
+		return VerifiedCallerID{}, ErrVerificationUnsupported
+	}
+	if !s.Webhooks.Configured() {
+		return VerifiedCallerID{}, ErrWebhooksUnsupported
+	}
+
+	now := s.clock().UTC()
+	v, found, err := s.VerifiedCallerIDs.FindVerifiedCallerID(ctx, workspaceID, number)
+	if err != nil {
+		return VerifiedCallerID{}, err
+	}
+	switch {
+	case !found:
+		v = VerifiedCallerID{ID: uuid.NewString(), WorkspaceID: workspaceID, Number: number, CreatedAt: now}
+	case v.Status == CallerIDVerified:
+		return v, nil
+	case v.Status == CallerIDPending && now.Sub(v.RequestedAt) < VerificationTimeout:
+		return VerifiedCallerID{}, ErrVerificationPending
+	}
+
+	start, err := verifier.StartCallerIDVerification(ctx, telephony.CallerIDVerificationRequest{
+		WorkspaceID:  workspaceID,
+		Number:       number,
+		FriendlyName: friendlyName,
+		CallbackURL:  s.Webhooks.CallerIDVerificationURL(s.provider.Name(), workspaceID, v.ID),
+	})
+	if err != nil {
+		return VerifiedCallerID{}, err
+	}
+	v.FriendlyName = strings.TrimSpace(friendlyName)
+	v.Status = CallerIDPending
+	v.ValidationCode = start.ValidationCode
+	v.ProviderCallID = start.ProviderCallID
+	v.Attempts++
+	v.RequestedAt = now
+	v.UpdatedAt = now
+	if err := s.VerifiedCallerIDs.PutVerifiedCallerID(ctx, v); err != nil {
+		return VerifiedCallerID{}, err
+	}
+	return v, nil
+}
+
+// CompleteCallerIDVerification records the provider's verification result
+// (implements telephony.CallerIDVerificationRecorder). Only a pending request for
+// the same number is updated.
+func (s *Service) CompleteCallerIDVerification(ctx context.Context, workspaceID, id, number string, verified bool) error {
+	if workspaceID == "" || id == "" {
+		return ErrInvalidArgument
+	}
+	if s.VerifiedCallerIDs == nil {
+		return errVerificationsNotConfigured
+	}
+	v, ok, err := s.VerifiedCallerIDs.GetVerifiedCallerID(ctx, workspaceID, id)
+	if err != nil {
+		return err
+	}
+	if !ok || v.Number != strings.TrimSpace(number) {
+		return ErrCallerIDNotFound
+	}
+	if v.Status != CallerIDPending {
+		return nil // duplicate callback
+	}
+	now := s.clock().UTC()
+	v.Status = CallerIDFailed
+	if verified {
+		v.Status = CallerIDVerified
+		v.VerifiedAt = &now
+	}
+	v.ValidationCode = ""
+	v.UpdatedAt = now
+	return s.VerifiedCallerIDs.PutVerifiedCallerID(ctx, v)
+}
+
+// ListVerifiedCallerIDs returns the workspace's registered external caller IDs.
+func (s *Service) ListVerifiedCallerIDs(ctx context.Context, workspaceID string) ([]VerifiedCallerID, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	if s.VerifiedCallerIDs == nil {
+		return nil, errVerificationsNotConfigured
+	}
+	return s.VerifiedCallerIDs.ListVerifiedCallerIDs(ctx, workspaceID)
+}
+
+// DeleteVerifiedCallerID unregisters an external caller ID; it can no longer be presented.
+func (s *Service) DeleteVerifiedCallerID(ctx context.Context, workspaceID, id string) error {
+	if workspaceID == "" || id == "" {
+		return ErrInvalidArgument
+	}
+	if s.VerifiedCallerIDs == nil {
+		return errVerificationsNotConfigured
+	}
+	ok, err := s.VerifiedCallerIDs.DeleteVerifiedCallerID(ctx, workspaceID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCallerIDNotFound
+	}
+	return nil
+}
+
+// AuthorizeCallerID returns nil when the workspace may present number as caller
+// ID: it owns the number, or has verified it. Everything else, including pending
+// and failed verifications, is ErrCallerIDNotVerified.
+func (s *Service) AuthorizeCallerID(ctx context.Context, workspaceID, number string) error {
+	number = strings.TrimSpace(number)
+	if workspaceID == "" || number == "" {
+		return ErrInvalidArgument
+	}
+	if s.Inventory != nil {
+		if _, owned, err := s.Inventory.GetNumber(ctx, workspaceID, number); err != nil || owned {
+			return err
+		}
+	}
+	if s.VerifiedCallerIDs != nil {
+		v, ok, err := s.VerifiedCallerIDs.FindVerifiedCallerID(ctx, workspaceID, number)
+		if err != nil {
+			return err
+		}
+		if ok && v.Status == CallerIDVerified {
+			return nil
+		}
+	}
+	return ErrCallerIDNotVerified
+}
+
+// CallerIDGuard wraps a provider's CallOriginator so every origination presents
+// an owned or verified caller ID.
+type CallerIDGuard struct {
+	Next    telephony.CallOriginator
+	Numbers *Service
+}
+
+func (g CallerIDGuard) OriginateCall(ctx context.Context, req telephony.OriginateCallRequest) (string, error) {
+	if err := g.Numbers.AuthorizeCallerID(ctx, req.WorkspaceID, req.From); err != nil {
+		return "", err
+	}
+	return g.Next.OriginateCall(ctx, req)
+}
@@ -0,0 +1,72 @@
+package numbers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"telecom-platform/internal/telephony"
+)
+
+type verifyingProvider struct {
+	telephony.SIPProvider
+	requests []telephony.CallerIDVerificationRequest
+}
+
+func (p *verifyingProvider) StartCallerIDVerification(ctx context.Context, req telephony.CallerIDVerificationRequest) (telephony.CallerIDVerificationStart, error) {
+	p.requests = append(p.requests, req)
+	return telephony.CallerIDVerificationStart{ValidationCode: "123456", ProviderCallID: "CA1"}, nil
+}
+
+func TestCallerIDVerification_OnlyVerifiedOrOwnedNumbersAuthorize(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepo()
+	repo.Numbers["w|+15550001"] = OwnedNumber{WorkspaceID: "w", Number: "+15550001", Version: 1}
+	provider := &verifyingProvider{}
+	svc := NewService(provider, repo, repo, nil)
+	svc.Inventory = repo
+	svc.VerifiedCallerIDs = repo
+	svc.Webhooks = telephony.WebhookURLs{BaseURL: "https://api.example.com"}
+
+	if _, err := svc.StartCallerIDVerification(ctx, "w", "+15550001", ""); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected owned number to be rejected, got %v", err)
+	}
+	v, err := svc.StartCallerIDVerification(ctx, "w", "+14155550100", "Head office")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Status != CallerIDPending || v.ValidationCode != "123456" || len(provider.requests) != 1 ||
+		!strings.HasPrefix(provider.requests[0].CallbackURL, "https://api.example.com/webhooks/sip/caller-id-verification?") {
+		t.Fatalf("unexpected verification %+v / %+v", v, provider.requests)
+	}
+	if _, err := svc.StartCallerIDVerification(ctx, "w", "+14155550100", ""); !errors.Is(err, ErrVerificationPending) {
+		t.Fatalf("expected ErrVerificationPending, got %v", err)
+	}
+
+	// Pending numbers are blocked; owned numbers never are.
+	if err := svc.AuthorizeCallerID(ctx, "w", "+14155550100"); !errors.Is(err, ErrCallerIDNotVerified) {
+		t.Fatalf("expected pending number blocked, got %v", err)
+	}
+	if err := svc.AuthorizeCallerID(ctx, "w", "+15550001"); err != nil {
+		t.Fatalf("expected owned number authorized, got %v", err)
+	}
+
+	if err := svc.CompleteCallerIDVerification(ctx, "w", v.ID, "+19999999999", true); !errors.Is(err, ErrCallerIDNotFound) {
+		t.Fatalf("expected mismatched number rejected, got %v", err)
+	}
+	if err := svc.CompleteCallerIDVerification(ctx, "w", v.ID, "+14155550100", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.AuthorizeCallerID(ctx, "w", "+14155550100"); err != nil {
+		t.Fatalf("expected verified number authorized, got %v", err)
+	}
+	if err := svc.AuthorizeCallerID(ctx, "other", "+14155550100"); !errors.Is(err, ErrCallerIDNotVerified) {
+		t.Fatalf("expected verification to be workspace scoped, got %v", err)
+	}
+
+	guard := CallerIDGuard{Next: nil, Numbers: svc}
+	if _, err := guard.OriginateCall(ctx, telephony.OriginateCallRequest{WorkspaceID: "w", From: "+12125550000", To: "+2", AnswerURL: "https://x"}); !errors.Is(err, ErrCallerIDNotVerified) {
+		t.Fatalf("expected guard to block unverified caller id, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package numbers
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"telecom-platform/internal/telephony"
+	"telecom-platform/pkg/logger"
+)
+
+// Provider config drift.
+//
+// ConfigDriftJob compares what the provider account has against the number
+// inventory, so changes made in the provider console stop going unnoticed:
+// - unknown_number: on the account but in no workspace's inventory (bought in
+//   the console, or left behind by a failed release); it is still billed;
+// - missing_number: in an inventory but not on the account (released in the
+//   console); calls to it no longer arrive;
+// - webhooks: callbacks not pointing at this environment;
+// - recording: provider-side recording switched on, bypassing the platform's
+//   per-call recording and consent checks.
+//
+// Number ownership drift is only reported: releasing or importing a number
+// needs a human to decide which workspace it belongs to. With AutoCorrect set,
+// webhook and recording drift is repaired by reconfiguring the number.
+//
+// Ownership needs a provider implementing telephony.NumberLister; webhook
+// and recording comparison needs telephony.NumberConfigReader and
+// Service.Webhooks.
+
+type DriftKind string
+
+const (
+	DriftUnknownNumber DriftKind = "unknown_number"
+	DriftMissingNumber DriftKind = "missing_number"
+	DriftWebhooks      DriftKind = "webhooks"
+	DriftRecording     DriftKind = "recording"
+)
+
+// ConfigDrift is one difference between the provider and the inventory.
+type ConfigDrift struct {
+	Kind             DriftKind `json:"kind"`
+	WorkspaceID      string    `json:"workspace_id,omitempty"`
+	Number           string    `json:"number"`
+	ProviderNumberID string    `json:"provider_number_id,omitempty"`
+	// Corrected is set when AutoCorrect repaired the drift in this run.
+	Corrected bool `json:"corrected"`
+}
+
+// ConfigDriftReport is the result of one ConfigDriftJob run.
+type ConfigDriftReport struct {
+	Provider  string        `json:"provider"`
+	CheckedAt time.Time     `json:"checked_at"`
+	Checked   int           `json:"checked"`
+	Drifts    []ConfigDrift `json:"drifts"`
+	Corrected int           `json:"corrected"`
+	// Failed counts numbers whose provider config could not be read or repaired.
+	Failed int `json:"failed"`
+}
+
+// DriftReportStore keeps drift reports for operators.
+type DriftReportStore interface {
+	SaveDriftReport(ctx context.Context, r ConfigDriftReport) error
+	// LatestDriftReport returns (ConfigDriftReport{}, false, nil) before the first run.
+	LatestDriftReport(ctx context.Context) (ConfigDriftReport, bool, error)
+}
+
+// ConfigDriftJob detects (and optionally corrects) provider config drift.
+// Run it periodically, e.g. hourly; it supersedes WebhookDriftJob.
+type ConfigDriftJob struct {
+	Numbers    *Service
+	Workspaces WorkspaceLister
+	// Reports is optional; each run is saved when set.
+	Reports     DriftReportStore
+	AutoCorrect bool
+}
+
+// RunOnce compares every owned number and the provider account once.
+func (j ConfigDriftJob) RunOnce(ctx context.Context) (ConfigDriftReport, error) {
+	s := j.Numbers
+	if s == nil || s.Inventory == nil || j.Workspaces == nil {
+		return ConfigDriftReport{}, errors.New("numbers: config drift job not configured")
+	}
+	rep := ConfigDriftReport{Provider: s.provider.Name(), CheckedAt: s.clock().UTC(), Drifts: []ConfigDrift{}}
+	log := logger.From(ctx)
+
+	ids, err := j.Workspaces.ListWorkspaceIDs(ctx)
+	if err != nil {
+		return rep, err
+	}
+	var owned []OwnedNumber
+	for _, workspaceID := range ids {
+		ns, err := s.Inventory.ListNumbers(ctx, workspaceID)
+		if err != nil {
+			return rep, err
+		}
+		owned = append(owned, ns...)
+	}
+
+	var atProvider map[string]telephony.ProviderNumber
+	if lister, ok := s.provider.(telephony.NumberLister); ok {
+		listed, err := lister.ListProviderNumbers(ctx)
+		if err != nil {
+			return rep, err
+		}
+		atProvider = make(map[string]telephony.ProviderNumber, len(listed))
+		for _, p := range listed {
+			atProvider[p.Number] = p
+		}
+	}
+	ownership := ownershipDrift(owned, atProvider)
+	rep.Drifts = append(rep.Drifts, ownership...)
+	missing := map[string]bool{}
+	for _, d := range ownership {
+		if d.Kind == DriftMissingNumber {
+			missing[d.WorkspaceID+"|"+d.Number] = true
+		}
+	}
+
+	reader, canRead := s.provider.(telephony.NumberConfigReader)
+	_, canConfigure := s.provider.(telephony.NumberConfigurer)
+	for _, n := range owned {
+		rep.Checked++
+		if !canRead || !s.Webhooks.Configured() || missing[n.WorkspaceID+"|"+n.Number] {
+			continue
+		}
+		got, err := reader.GetNumberConfig(ctx, n.WorkspaceID, n.Number, n.ProviderNumberID)
+		if err != nil {
+			rep.Failed++
+			log.Error("number config read failed", "workspace_id", n.WorkspaceID, "number", n.Number, "err", err)
+			continue
+		}
+		drifts := numberConfigDrift(n, got, s.expectedWebhooks(n))
+		if len(drifts) > 0 && j.AutoCorrect && canConfigure {
+			if _, err := s.ConfigureWebhooks(ctx, n.WorkspaceID, n.Number); err != nil {
+				rep.Failed++
+				log.Error("number config repair failed", "workspace_id", n.WorkspaceID, "number", n.Number, "err", err)
+			} else {
+				for i := range drifts {
+					drifts[i].Corrected = true
+				}
+				rep.Corrected += len(drifts)
+			}
+		}
+		rep.Drifts = append(rep.Drifts, drifts...)
+	}
+
+	for _, d := range rep.Drifts {
+		log.Warn("provider config drift", "kind", d.Kind, "workspace_id", d.WorkspaceID, "number", d.Number, "corrected", d.Corrected)
+	}
+	if j.Reports != nil {
+		if err := j.Reports.SaveDriftReport(ctx, rep); err != nil {
+			return rep, err
+		}
+	}
+	return rep, nil
+}
+
+// ownershipDrift compares the inventory with the provider account. A nil
+// atProvider (provider cannot list numbers) reports nothing.
+func ownershipDrift(owned []OwnedNumber, atProvider map[string]telephony.ProviderNumber) []ConfigDrift {
+	if atProvider == nil {
+		return nil
+	}
+	var out []ConfigDrift
+	inInventory := make(map[string]bool, len(owned))
+	for _, n := range owned {
+		inInventory[n.Number] = true
+		if _, ok := atProvider[n.Number]; !ok {
+			out = append(out, ConfigDrift{Kind: DriftMissingNumber, WorkspaceID: n.WorkspaceID, Number: n.Number, ProviderNumberID: n.ProviderNumberID})
+		}
+	}
+	var unknown []ConfigDrift
+	for number, p := range atProvider {
+		if !inInventory[number] {
+			unknown = append(unknown, ConfigDrift{Kind: DriftUnknownNumber, Number: number, ProviderNumberID: p.ProviderNumberID})
+		}
+	}
+	sort.Slice(unknown, func(i, k int) bool { return unknown[i].Number < unknown[k].Number })
+	return append(out, unknown...)
+}
+
+// numberConfigDrift compares a number's provider config with what this
+// environment expects.
+func numberConfigDrift(n OwnedNumber, got, want telephony.ConfigureNumberRequest) []ConfigDrift {
+	var out []ConfigDrift
+	add := func(k DriftKind) {
+		out = append(out, ConfigDrift{Kind: k, WorkspaceID: n.WorkspaceID, Number: n.Number, ProviderNumberID: n.ProviderNumberID})
+	}
+	if n.WebhooksConfiguredAt == nil || !got.SameWebhooks(want) {
+		add(DriftWebhooks)
+	}
+	if got.RecordingEnabled != want.RecordingEnabled {
+		add(DriftRecording)
+	}
+	return out
+}
+
+// Run checks on every tick until ctx is canceled.
+func (j ConfigDriftJob) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := j.RunOnce(ctx); err != nil {
+				logger.From(ctx).Error("provider config drift check failed", "err", err)
+			}
+		}
+	}
+}
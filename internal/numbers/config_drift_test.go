@@ -0,0 +1,75 @@
+package numbers
+
+import (
+	"context"
+	"testing"
+
+	"telecom-platform/internal/telephony"
+)
+
+// stubAccount is a provider that can also list its numbers.
+type stubAccount struct {
+	stubConfigurer
+	listed []telephony.ProviderNumber
+}
+
+func (s *stubAccount) ListProviderNumbers(ctx context.Context) ([]telephony.ProviderNumber, error) {
+	return s.listed, nil
+}
+
+func TestConfigDriftJob(t *testing.T) {
+	repo := NewMemoryRepo()
+	prov := &stubAccount{}
+	svc := NewService(prov, repo, repo, nil)
+	svc.Inventory = repo
+	svc.Webhooks, _ = telephony.NewWebhookURLs("https://api.example.com")
+	ctx := context.Background()
+
+	for _, number := range []string{"+14155550111", "+14155550122", "+14155550133"} {
+		if _, err := svc.ImportNumber(ctx, "w", ImportNumberRequest{Number: number, CountryISO2: "US", NumberType: "local", Capabilities: []string{"voice"}}); err != nil {
+			t.Fatalf("import %s: %v", number, err)
+		}
+	}
+	// Console changes: recording switched on for one number, another released,
+	// and a number bought outside the platform.
+	cfg := prov.current["+14155550111"]
+	cfg.RecordingEnabled = true
+	prov.current["+14155550111"] = cfg
+	prov.listed = []telephony.ProviderNumber{{Number: "+14155550111"}, {Number: "+14155550122"}, {Number: "+14155550199", ProviderNumberID: "PN9"}}
+
+	job := ConfigDriftJob{Numbers: svc, Workspaces: stubWorkspaces{"w"}, Reports: repo}
+	rep, err := job.RunOnce(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ConfigDrift{
+		{Kind: DriftMissingNumber, WorkspaceID: "w", Number: "+14155550133"},
+		{Kind: DriftUnknownNumber, Number: "+14155550199", ProviderNumberID: "PN9"},
+		{Kind: DriftRecording, WorkspaceID: "w", Number: "+14155550111"},
+	}
+	if rep.Checked != 3 || rep.Corrected != 0 || len(rep.Drifts) != len(want) {
+		t.Fatalf("unexpected report %+v", rep)
+	}
+	for i, d := range want {
+		if rep.Drifts[i] != d {
+			t.Fatalf("drift %d: got %+v, want %+v", i, rep.Drifts[i], d)
+		}
+	}
+	if !prov.current["+14155550111"].RecordingEnabled {
+		t.Fatalf("report-only run must not reconfigure numbers")
+	}
+
+	// Auto-correct repairs configuration but never ownership.
+	job.AutoCorrect = true
+	rep, err = job.RunOnce(ctx)
+	if err != nil || rep.Corrected != 1 || len(rep.Drifts) != 3 || !rep.Drifts[2].Corrected {
+		t.Fatalf("expected the recording drift corrected, got %+v err=%v", rep, err)
+	}
+	if prov.current["+14155550111"].RecordingEnabled {
+		t.Fatalf("recording not switched off")
+	}
+	latest, ok, _ := repo.LatestDriftReport(ctx)
+	if !ok || len(repo.DriftReports) != 2 || latest.Corrected != 1 {
+		t.Fatalf("expected both runs saved, got %+v", repo.DriftReports)
+	}
+}
@@ -0,0 +1,211 @@
+package numbers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/telephony"
+	"telecom-platform/pkg/logger"
+)
+
+// Emergency (E911) addresses.
+//
+// Every US voice number must carry a registered dispatchable address before it
+// can reach 911. RegisterEmergencyAddress provisions the address with the
+// provider (telephony.EmergencyAddressRegistrar) and records it per number;
+// EmergencyReadiness lists the workspace's US voice numbers still missing one and
+// logs a warning while any are. Emergency calls arriving on a SIP trunk are
+// checked by AuthorizeEmergencyCall against the trunk's explicit policy: block
+// rejects them, route requires the presented caller ID to have an address.
+
+var (
+	ErrEmergencyAddressMissing = errors.New("numbers: number has no registered emergency address")
+	ErrEmergencyUnsupported    = errors.New("numbers: provider does not support emergency address registration")
+	ErrEmergencyCallBlocked    = errors.New("numbers: emergency calls are blocked on this trunk")
+	errEmergencyNotConfigured  = errors.New("numbers: emergency address store not configured")
+)
+
+// NumberEmergencyAddress is the emergency address registered on one number.
+type NumberEmergencyAddress struct {
+	WorkspaceID       string                     `json:"workspace_id" db:"workspace_id"`
+	Number            string                     `json:"number" db:"number"` // E.164
+	Address           telephony.EmergencyAddress `json:"address" db:"address"`
+	ProviderAddressID string                     `json:"provider_address_id" db:"provider_address_id"`
+	RegisteredAt      time.Time                  `json:"registered_at" db:"registered_at"`
+	UpdatedAt         time.Time                  `json:"updated_at" db:"updated_at"`
+}
+
+// EmergencyReadiness reports the workspace's numbers that need an emergency
+// address and do not have one.
+type EmergencyReadiness struct {
+	Ready   bool     `json:"ready"`
+	Missing []string `json:"missing"`
+}
+
+// EmergencyAddressStore persists emergency addresses, one per workspace and
+// number. Implementations must enforce workspace filtering.
+type EmergencyAddressStore interface {
+	PutEmergencyAddress(ctx context.Context, a NumberEmergencyAddress) error
+	GetEmergencyAddress(ctx context.Context, workspaceID, number string) (NumberEmergencyAddress, bool, error)
+	ListEmergencyAddresses(ctx context.Context, workspaceID string) ([]NumberEmergencyAddress, error)
+}
+
+// RegisterEmergencyAddress provisions address on an owned number and records it.
+// Registering again replaces the number's address.
+func (s *Service) RegisterEmergencyAddress(ctx context.Context, workspaceID, number string, address telephony.EmergencyAddress) (NumberEmergencyAddress, error) {
+	number = strings.TrimSpace(number)
+	if workspaceID == "" || number == "" {
+		return NumberEmergencyAddress{}, ErrInvalidArgument
+	}
+	address, err := normalizeEmergencyAddress(address)
+	if err != nil {
+		return NumberEmergencyAddress{}, err
+	}
+	if s.EmergencyAddresses == nil || s.Inventory == nil {
+		return NumberEmergencyAddress{}, errEmergencyNotConfigured
+	}
+	if _, owned, err := s.Inventory.GetNumber(ctx, workspaceID, number); err != nil {
+		return NumberEmergencyAddress{}, err
+	} else if !owned {
+		return NumberEmergencyAddress{}, ErrNumberNotFound
+	}
+	registrar, ok := s.provider.(telephony.EmergencyAddressRegistrar)
+	if !ok {
+		return NumberEmergencyAddress{}, ErrEmergencyUnsupported
+	}
+	providerID, err := registrar.RegisterEmergencyAddress(ctx, workspaceID, number, address)
+	if err != nil {
+		return NumberEmergencyAddress{}, err
+	}
+
+	now := s.clock().UTC()
+	a, found, err := s.EmergencyAddresses.GetEmergencyAddress(ctx, workspaceID, number)
+	if err != nil {
+		return NumberEmergencyAddress{}, err
+	}
+	if !found {
+		a = NumberEmergencyAddress{WorkspaceID: workspaceID, Number: number}
+	}
+	a.Address = address
+	a.ProviderAddressID = providerID
+	a.RegisteredAt = now
+	a.UpdatedAt = now
+	if err := s.EmergencyAddresses.PutEmergencyAddress(ctx, a); err != nil {
+		return NumberEmergencyAddress{}, err
+	}
+	return a, nil
+}
+
+// GetEmergencyAddress returns the number's registered address, or
+// ErrEmergencyAddressMissing.
+func (s *Service) GetEmergencyAddress(ctx context.Context, workspaceID, number string) (NumberEmergencyAddress, error) {
+	number = strings.TrimSpace(number)
+	if workspaceID == "" || number == "" {
+		return NumberEmergencyAddress{}, ErrInvalidArgument
+	}
+	if s.EmergencyAddresses == nil {
+		return NumberEmergencyAddress{}, errEmergencyNotConfigured
+	}
+	a, ok, err := s.EmergencyAddresses.GetEmergencyAddress(ctx, workspaceID, number)
+	if err != nil {
+		return NumberEmergencyAddress{}, err
+	}
+	if !ok {
+		return NumberEmergencyAddress{}, ErrEmergencyAddressMissing
+	}
+	return a, nil
+}
+
+// EmergencyReadiness lists the workspace's US voice numbers without an emergency
+// address. Any missing address is logged as a warning.
+func (s *Service) EmergencyReadiness(ctx context.Context, workspaceID string) (EmergencyReadiness, error) {
+	if workspaceID == "" {
+		return EmergencyReadiness{}, ErrInvalidArgument
+	}
+	if s.EmergencyAddresses == nil || s.Inventory == nil {
+		return EmergencyReadiness{}, errEmergencyNotConfigured
+	}
+	nums, err := s.Inventory.ListNumbers(ctx, workspaceID)
+	if err != nil {
+		return EmergencyReadiness{}, err
+	}
+	registered, err := s.EmergencyAddresses.ListEmergencyAddresses(ctx, workspaceID)
+	if err != nil {
+		return EmergencyReadiness{}, err
+	}
+	has := make(map[string]bool, len(registered))
+	for _, a := range registered {
+		has[a.Number] = true
+	}
+	out := EmergencyReadiness{Missing: []string{}}
+	for _, n := range nums {
+		if needsEmergencyAddress(n) && !has[n.Number] {
+			out.Missing = append(out.Missing, n.Number)
+		}
+	}
+	out.Ready = len(out.Missing) == 0
+	if !out.Ready {
+		logger.From(ctx).Warn("US voice numbers missing emergency (E911) address", "workspace_id", workspaceID, "count", len(out.Missing), "numbers", out.Missing)
+	}
+	return out, nil
+}
+
+// AuthorizeEmergencyCall checks an emergency call placed on trunk presenting
+// callerID. Trunks with the block policy reject it (ErrEmergencyCallBlocked);
+// route trunks need callerID to have a registered address
+// (ErrEmergencyAddressMissing). Every rejection is logged as an error.
+func (s *Service) AuthorizeEmergencyCall(ctx context.Context, trunk telephony.SIPTrunk, callerID string) error {
+	callerID = strings.TrimSpace(callerID)
+	log := logger.From(ctx)
+	switch trunk.Emergency {
+	case telephony.EmergencyRoute:
+	case telephony.EmergencyBlock:
+		log.Error("emergency call blocked by trunk policy", "workspace_id", trunk.WorkspaceID, "trunk_id", trunk.ID, "caller_id", callerID)
+		return ErrEmergencyCallBlocked
+	default:
+		log.Error("emergency call on trunk without emergency policy", "workspace_id", trunk.WorkspaceID, "trunk_id", trunk.ID, "caller_id", callerID)
+		return fmt.Errorf("%w: trunk has no emergency policy", ErrEmergencyCallBlocked)
+	}
+	if s.EmergencyAddresses == nil {
+		return errEmergencyNotConfigured
+	}
+	if callerID != "" {
+		_, ok, err := s.EmergencyAddresses.GetEmergencyAddress(ctx, trunk.WorkspaceID, callerID)
+		if err != nil || ok {
+			return err
+		}
+	}
+	log.Error("emergency call without registered E911 address", "workspace_id", trunk.WorkspaceID, "trunk_id", trunk.ID, "caller_id", callerID)
+	return ErrEmergencyAddressMissing
+}
+
+// needsEmergencyAddress reports whether n is a US number that can place calls.
+func needsEmergencyAddress(n OwnedNumber) bool {
+	return strings.EqualFold(n.CountryISO2, "US") && n.HasCapability(CapabilityVoice)
+}
+
+// normalizeEmergencyAddress trims address and checks the fields a dispatchable
+// address needs. US addresses need a 2-letter state and a 5-digit (or ZIP+4) ZIP.
+func normalizeEmergencyAddress(a telephony.EmergencyAddress) (telephony.EmergencyAddress, error) {
+	for _, f := range []*string{&a.CustomerName, &a.Street, &a.Street2, &a.City, &a.Region, &a.PostalCode, &a.CountryISO2} {
+		*f = strings.TrimSpace(*f)
+	}
+	a.CountryISO2 = strings.ToUpper(a.CountryISO2)
+	a.Region = strings.ToUpper(a.Region)
+	if a.CustomerName == "" || a.Street == "" || a.City == "" || a.Region == "" || a.PostalCode == "" || len(a.CountryISO2) != 2 {
+		return a, fmt.Errorf("%w: customer_name, street, city, region, postal_code and country_iso2 required", ErrInvalidArgument)
+	}
+	if a.CountryISO2 == "US" {
+		zip := a.PostalCode
+		if len(zip) == 10 && zip[5] == '-' {
+			zip = zip[:5] + zip[6:]
+		}
+		if len(a.Region) != 2 || (len(zip) != 5 && len(zip) != 9) || !allDigits(zip) {
+			return a, fmt.Errorf("%w: invalid US state or ZIP code", ErrInvalidArgument)
+		}
+	}
+	return a, nil
+}
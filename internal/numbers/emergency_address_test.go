@@ -0,0 +1,78 @@
+package numbers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"telecom-platform/internal/telephony"
+)
+
+type emergencyProvider struct {
+	telephony.SIPProvider
+	registered []string
+}
+
+func (p *emergencyProvider) RegisterEmergencyAddress(ctx context.Context, workspaceID, number string, address telephony.EmergencyAddress) (string, error) {
+	p.registered = append(p.registered, number)
+	return "AD1", nil
+}
+
+func TestEmergencyAddress_ReadinessAndTrunkPolicy(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepo()
+	repo.Numbers["w|+14155550100"] = OwnedNumber{WorkspaceID: "w", Number: "+14155550100", CountryISO2: "US", Capabilities: []string{CapabilityVoice}, Version: 1}
+	repo.Numbers["w|+14155550101"] = OwnedNumber{WorkspaceID: "w", Number: "+14155550101", CountryISO2: "US", Capabilities: []string{CapabilitySMS}, Version: 1}
+	repo.Numbers["w|+442071234567"] = OwnedNumber{WorkspaceID: "w", Number: "+442071234567", CountryISO2: "GB", Capabilities: []string{CapabilityVoice}, Version: 1}
+	provider := &emergencyProvider{}
+	svc := NewService(provider, repo, repo, nil)
+	svc.Inventory = repo
+	svc.EmergencyAddresses = repo
+	var _ telephony.EmergencyCallAuthorizer = svc
+
+	// Only US voice numbers need an address.
+	r, err := svc.EmergencyReadiness(ctx, "w")
+	if err != nil || r.Ready || len(r.Missing) != 1 || r.Missing[0] != "+14155550100" {
+		t.Fatalf("unexpected readiness %+v err=%v", r, err)
+	}
+
+	addr := telephony.EmergencyAddress{CustomerName: "Acme", Street: "1 Main St", City: "San Francisco", Region: "ca", PostalCode: "9410", CountryISO2: "us"}
+	if _, err := svc.RegisterEmergencyAddress(ctx, "w", "+14155550100", addr); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected invalid ZIP rejected, got %v", err)
+	}
+	addr.PostalCode = "94105-1234"
+	if _, err := svc.RegisterEmergencyAddress(ctx, "w", "+14155550199", addr); !errors.Is(err, ErrNumberNotFound) {
+		t.Fatalf("expected unowned number rejected, got %v", err)
+	}
+	a, err := svc.RegisterEmergencyAddress(ctx, "w", "+14155550100", addr)
+	if err != nil || a.ProviderAddressID != "AD1" || a.Address.Region != "CA" || a.Address.CountryISO2 != "US" || len(provider.registered) != 1 {
+		t.Fatalf("unexpected registration %+v err=%v", a, err)
+	}
+	if r, err := svc.EmergencyReadiness(ctx, "w"); err != nil || !r.Ready {
+		t.Fatalf("expected ready, got %+v err=%v", r, err)
+	}
+
+	route := telephony.SIPTrunk{ID: "t1", WorkspaceID: "w", Emergency: telephony.EmergencyRoute}
+	if err := svc.AuthorizeEmergencyCall(ctx, route, "+14155550100"); err != nil {
+		t.Fatalf("expected registered caller id to route, got %v", err)
+	}
+	if err := svc.AuthorizeEmergencyCall(ctx, route, "+442071234567"); !errors.Is(err, ErrEmergencyAddressMissing) {
+		t.Fatalf("expected missing address, got %v", err)
+	}
+	block := route
+	block.Emergency = telephony.EmergencyBlock
+	if err := svc.AuthorizeEmergencyCall(ctx, block, "+14155550100"); !errors.Is(err, ErrEmergencyCallBlocked) {
+		t.Fatalf("expected blocked, got %v", err)
+	}
+	block.Emergency = ""
+	if err := svc.AuthorizeEmergencyCall(ctx, block, "+14155550100"); !errors.Is(err, ErrEmergencyCallBlocked) {
+		t.Fatalf("expected trunk without policy blocked, got %v", err)
+	}
+
+	unsupported := NewService(&telephony.SIPProvider{}, repo, repo, nil)
+	unsupported.Inventory = repo
+	unsupported.EmergencyAddresses = repo
+	if _, err := unsupported.RegisterEmergencyAddress(ctx, "w", "+14155550100", addr); !errors.Is(err, ErrEmergencyUnsupported) {
+		t.Fatalf("expected ErrEmergencyUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package numbers
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// InventoryStore persists a workspace's owned numbers. Implementations must enforce
+// workspace filtering.
+type InventoryStore interface {
+	// PutNumber must reject with ErrVersionConflict unless the stored version is n.Version-1
+	// (a number not yet in the inventory counts as 0).
+	PutNumber(ctx context.Context, n OwnedNumber) error
+	// GetNumber returns (OwnedNumber{}, false, nil) when the workspace does not own number.
+	GetNumber(ctx context.Context, workspaceID, number string) (OwnedNumber, bool, error)
+	ListNumbers(ctx context.Context, workspaceID string) ([]OwnedNumber, error)
+}
+
+var (
+	ErrNumberNotFound     = errors.New("numbers: number not found")
+	ErrCapabilityMismatch = errors.New("numbers: number lacks required capability")
+)
+
+// ListNumbers returns the workspace's number inventory.
+func (s *Service) ListNumbers(ctx context.Context, workspaceID string) ([]OwnedNumber, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	if s.Inventory == nil {
+		return nil, errors.New("numbers: inventory not configured")
+	}
+	return s.Inventory.ListNumbers(ctx, workspaceID)
+}
+
+// AssignNumber attaches a number to a campaign and/or messaging profile. Voice
+// campaigns need a voice-capable number; messaging needs sms or mms.
+func (s *Service) AssignNumber(ctx context.Context, workspaceID, number string, a NumberAssignment) (OwnedNumber, error) {
+	number = strings.TrimSpace(number)
+	a.CampaignID = strings.TrimSpace(a.CampaignID)
+	a.MessagingProfileID = strings.TrimSpace(a.MessagingProfileID)
+	if workspaceID == "" || number == "" {
+		return OwnedNumber{}, ErrInvalidArgument
+	}
+	if s.Inventory == nil {
+		return OwnedNumber{}, errors.New("numbers: inventory not configured")
+	}
+	n, ok, err := s.Inventory.GetNumber(ctx, workspaceID, number)
+	if err != nil {
+		return OwnedNumber{}, err
+	}
+	if !ok {
+		return OwnedNumber{}, ErrNumberNotFound
+	}
+	if err := CheckAssignment(n, a); err != nil {
+		return OwnedNumber{}, err
+	}
+	if n.Version, err = nextVersion(n.Version, a.IfVersion); err != nil {
+		return OwnedNumber{}, err
+	}
+	n.CampaignID = a.CampaignID
+	n.MessagingProfileID = a.MessagingProfileID
+	n.UpdatedAt = s.clock().UTC()
+	if err := s.Inventory.PutNumber(ctx, n); err != nil {
+		return OwnedNumber{}, err
+	}
+	return n, nil
+}
+
+// CheckAssignment validates a against n's capabilities. Exported for callers that
+// attach numbers elsewhere (e.g. campaign or messaging setup).
+func CheckAssignment(n OwnedNumber, a NumberAssignment) error {
+	if a.CampaignID != "" && !n.HasCapability(CapabilityVoice) {
+		return ErrCapabilityMismatch
+	}
+	if a.MessagingProfileID != "" && !n.HasCapability(CapabilitySMS) && !n.HasCapability(CapabilityMMS) {
+		return ErrCapabilityMismatch
+	}
+	return nil
+}
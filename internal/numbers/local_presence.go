@@ -0,0 +1,169 @@
+package numbers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"telecom-platform/pkg/logger"
+)
+
+// Local presence dialing.
+//
+// Callees answer more often when the caller ID looks local. SelectCallerID picks
+// the outbound caller ID for a callee from a caller_id pool using the
+// local_presence strategy, in order:
+//  1. an active number with the callee's area code (NANP: the 3 digits after +1);
+//  2. an active number in the callee's region (state/province), when the service
+//     has a RegionResolver;
+//  3. the pool's LocalFallback: any active number (default), the pool's first
+//     active number ("default"), or none (ErrNoLocalNumber).
+//
+// Among several matches the callee's number is hashed, so a lead keeps seeing the
+// same caller ID and can recognize it when calling back. Every pick made for a
+// call is recorded (CallerIDRecordStore) for disputes and answer-rate analysis.
+
+type LocalPresenceFallback string
+
+const (
+	LocalFallbackAny     LocalPresenceFallback = "any"
+	LocalFallbackDefault LocalPresenceFallback = "default"
+	LocalFallbackNone    LocalPresenceFallback = "none"
+)
+
+// CallerIDMatch says how a caller ID was chosen.
+type CallerIDMatch string
+
+const (
+	CallerIDMatchAreaCode CallerIDMatch = "area_code"
+	CallerIDMatchRegion   CallerIDMatch = "region"
+	CallerIDMatchFallback CallerIDMatch = "fallback"
+	// CallerIDMatchRotation is used by pools with a non-local strategy.
+	CallerIDMatchRotation CallerIDMatch = "rotation"
+)
+
+// RegionResolver maps a phone number to a region code (e.g. "US-CA"), typically
+// from a NANP area code table.
+type RegionResolver interface {
+	Region(number string) (string, bool)
+}
+
+// CallerIDRecord is the caller ID chosen for one outbound call.
+type CallerIDRecord struct {
+	WorkspaceID string        `json:"workspace_id" db:"workspace_id"`
+	CallID      string        `json:"call_id" db:"call_id"`
+	PoolID      string        `json:"pool_id" db:"pool_id"`
+	Destination string        `json:"destination" db:"destination"`
+	CallerID    string        `json:"caller_id" db:"caller_id"`
+	Match       CallerIDMatch `json:"match" db:"match"`
+	ChosenAt    time.Time     `json:"chosen_at" db:"chosen_at"`
+}
+
+// CallerIDRecordStore persists per-call caller ID choices. Implementations must
+// enforce workspace filtering.
+type CallerIDRecordStore interface {
+	RecordCallerID(ctx context.Context, r CallerIDRecord) error
+}
+
+var ErrNoLocalNumber = errors.New("numbers: no local caller id for destination")
+
+// SelectCallerID picks the caller ID for calling destination from a caller_id
+// pool. callID, when set, records the choice; leave it empty to preview.
+func (s *Service) SelectCallerID(ctx context.Context, workspaceID, poolID, destination, callID string) (CallerIDRecord, error) {
+	destination = strings.TrimSpace(destination)
+	if destination == "" || !strings.HasPrefix(destination, "+") {
+		return CallerIDRecord{}, ErrInvalidArgument
+	}
+	p, err := s.GetPool(ctx, workspaceID, poolID)
+	if err != nil {
+		return CallerIDRecord{}, err
+	}
+	if p.Purpose != PoolPurposeCallerID {
+		return CallerIDRecord{}, fmt.Errorf("%w: pool is not a caller_id pool", ErrInvalidArgument)
+	}
+
+	rec := CallerIDRecord{WorkspaceID: workspaceID, CallID: callID, PoolID: p.ID, Destination: destination}
+	if p.Strategy != RotationLocalPresence {
+		n, err := s.NextNumber(ctx, workspaceID, poolID, destination)
+		if err != nil {
+			return CallerIDRecord{}, err
+		}
+		rec.CallerID, rec.Match = n.Number, CallerIDMatchRotation
+		return rec, s.recordCallerID(ctx, rec)
+	}
+
+	var active []PoolNumber
+	for _, n := range p.Numbers {
+		if n.Status == PoolNumberActive {
+			active = append(active, n)
+		}
+	}
+	if len(active) == 0 {
+		return CallerIDRecord{}, ErrPoolExhausted
+	}
+
+	var byArea, byRegion []PoolNumber
+	area, hasArea := nanpAreaCode(destination)
+	region, hasRegion := "", false
+	if s.Regions != nil {
+		region, hasRegion = s.Regions.Region(destination)
+	}
+	for _, n := range active {
+		if a, ok := nanpAreaCode(n.Number); hasArea && ok && a == area {
+			byArea = append(byArea, n)
+		}
+		if hasRegion {
+			if r, ok := s.Regions.Region(n.Number); ok && r == region {
+				byRegion = append(byRegion, n)
+			}
+		}
+	}
+
+	switch {
+	case len(byArea) > 0:
+		rec.CallerID, rec.Match = stickyPick(byArea, destination).Number, CallerIDMatchAreaCode
+	case len(byRegion) > 0:
+		rec.CallerID, rec.Match = stickyPick(byRegion, destination).Number, CallerIDMatchRegion
+	default:
+		switch p.LocalFallback {
+		case LocalFallbackNone:
+			return CallerIDRecord{}, ErrNoLocalNumber
+		case LocalFallbackDefault:
+			rec.CallerID = active[0].Number
+		default:
+			rec.CallerID = stickyPick(active, destination).Number
+		}
+		rec.Match = CallerIDMatchFallback
+	}
+	return rec, s.recordCallerID(ctx, rec)
+}
+
+func (s *Service) recordCallerID(ctx context.Context, rec CallerIDRecord) error {
+	if rec.CallID == "" {
+		return nil
+	}
+	if s.CallerIDs == nil {
+		logger.From(ctx).Warn("caller id not recorded: no record store", "workspace_id", rec.WorkspaceID, "call_id", rec.CallID)
+		return nil
+	}
+	rec.ChosenAt = s.clock().UTC()
+	return s.CallerIDs.RecordCallerID(ctx, rec)
+}
+
+// stickyPick hashes key onto nums so the same callee gets the same number.
+func stickyPick(nums []PoolNumber, key string) PoolNumber {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return nums[h.Sum32()%uint32(len(nums))]
+}
+
+// nanpAreaCode returns the area code of a +1 number.
+func nanpAreaCode(e164 string) (string, bool) {
+	if len(e164) != 12 || !strings.HasPrefix(e164, "+1") || !allDigits(e164[2:]) {
+		return "", false
+	}
+	return e164[2:5], true
+}
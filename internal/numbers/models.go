@@ -0,0 +1,136 @@
+package numbers
+
+import (
+	"time"
+
+	"telecom-platform/internal/telephony"
+)
+
+// Number capabilities.
+const (
+	CapabilityVoice = "voice"
+	CapabilitySMS   = "sms"
+	CapabilityMMS   = "mms"
+	CapabilityFax   = "fax"
+)
+
+// OwnedNumber is a number in a workspace's inventory.
+type OwnedNumber struct {
+	WorkspaceID      string `json:"workspace_id" db:"workspace_id"`
+	Number           string `json:"number" db:"number"` // E.164
+	ProviderNumberID string `json:"provider_number_id,omitempty" db:"provider_number_id"`
+	CountryISO2      string `json:"country_iso2" db:"country_iso2"`
+	NumberType       string `json:"number_type" db:"number_type"`
+
+	// Capabilities holds the Capability* values the number supports.
+	Capabilities []string `json:"capabilities" db:"capabilities"`
+
+	// CampaignID routes inbound voice to a campaign (requires voice).
+	CampaignID string `json:"campaign_id,omitempty" db:"campaign_id"`
+	// MessagingProfileID attaches inbound/outbound messaging flows (requires sms or mms).
+	MessagingProfileID string `json:"messaging_profile_id,omitempty" db:"messaging_profile_id"`
+
+	// WebhooksConfiguredAt is when provider callbacks were last pointed at this
+	// environment; nil means they still need to be configured.
+	WebhooksConfiguredAt *time.Time `json:"webhooks_configured_at,omitempty" db:"webhooks_configured_at"`
+
+	PurchasedAt time.Time `json:"purchased_at" db:"purchased_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+
+	// Version increments on every write; see ErrVersionConflict.
+	Version int64 `json:"version" db:"version"`
+}
+
+// HasCapability reports whether the number supports c.
+func (n OwnedNumber) HasCapability(c string) bool {
+	for _, x := range n.Capabilities {
+		if x == c {
+			return true
+		}
+	}
+	return false
+}
+
+// NumberAssignment attaches a number to a voice campaign and/or messaging profile.
+// Empty fields detach.
+type NumberAssignment struct {
+	CampaignID         string `json:"campaign_id"`
+	MessagingProfileID string `json:"messaging_profile_id"`
+
+	// IfVersion is the OwnedNumber.Version the caller last read (from If-Match);
+	// 0 skips the check.
+	IfVersion int64 `json:"-"`
+}
+
+// PurchasePolicy constrains which numbers a workspace may buy.
+//
+// Multi-tenant invariant: one policy per workspace_id.
+// An empty allow-list means "no restriction" for that dimension.
+type PurchasePolicy struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+
+	// AllowedCountries holds ISO2 country codes (e.g., "US", "GB").
+	AllowedCountries []string `json:"allowed_countries,omitempty" db:"allowed_countries"`
+
+	// AllowedNumberTypes examples: local, mobile, toll_free.
+	AllowedNumberTypes []string `json:"allowed_number_types,omitempty" db:"allowed_number_types"`
+
+	// RequireRegulatoryBundle forces an approved regulatory bundle for countries that need one,
+	// even when the provider would accept the order without it.
+	RequireRegulatoryBundle bool `json:"require_regulatory_bundle" db:"require_regulatory_bundle"`
+
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	// Version increments on every write. On SetPolicy it is the version the caller
+	// last read (0 skips the check).
+	Version int64 `json:"version" db:"version"`
+}
+
+// RegulatoryRequirement describes what a provider needs before a number can be purchased
+// in a given country/number type (e.g., proof of address for DE local numbers).
+type RegulatoryRequirement struct {
+	CountryISO2 string `json:"country_iso2"`
+	NumberType  string `json:"number_type"`
+
+	// RequiredDocuments lists document types the workspace must upload
+	// (e.g., "business_registration", "proof_of_address", "identity").
+	RequiredDocuments []string `json:"required_documents"`
+
+	// EndUserType is "business" or "individual" when the provider distinguishes them.
+	EndUserType string `json:"end_user_type,omitempty"`
+}
+
+// NeedsDocuments reports whether the requirement has any document prerequisites.
+func (r RegulatoryRequirement) NeedsDocuments() bool { return len(r.RequiredDocuments) > 0 }
+
+// PurchaseRequest is the workspace-facing input to BuyNumber.
+type PurchaseRequest struct {
+	CountryISO2   string `json:"country_iso2"`
+	NumberType    string `json:"number_type"`
+	DesiredNumber string `json:"desired_number,omitempty"`
+
+	// Capabilities the number must support; empty means any.
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// RegulatoryBundleID references an approved set of compliance documents when required.
+	RegulatoryBundleID string `json:"regulatory_bundle_id,omitempty"`
+}
+
+// SearchQuery is the workspace-facing input to SearchNumbers.
+type SearchQuery struct {
+	CountryISO2  string   `json:"country_iso2"`
+	NumberType   string   `json:"number_type"`
+	AreaCode     string   `json:"area_code,omitempty"`
+	Contains     string   `json:"contains,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	PageSize  int    `json:"page_size,omitempty"`
+	PageToken string `json:"page_token,omitempty"`
+}
+
+// SearchPage is one page of normalized search results. Pass NextPageToken back with
+// the same query to continue.
+type SearchPage struct {
+	Numbers       []telephony.AvailableNumber `json:"numbers"`
+	NextPageToken string                      `json:"next_page_token,omitempty"`
+}
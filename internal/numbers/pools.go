@@ -0,0 +1,514 @@
+package numbers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	mrand "math/rand"
+	"strings"
+	"time"
+
+	"telecom-platform/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Number pools.
+//
+// A pool is a named set of the workspace's numbers used together, either to spread
+// outbound SMS across senders (messaging), to hand out tracking numbers per
+// visitor (tracking, for dynamic number insertion) or to present outbound caller
+// IDs (caller_id, see local_presence.go). A pool may be assigned to a
+// campaign; a number belongs to at most one pool so attribution stays unambiguous.
+//
+// NextNumber picks from the pool's active numbers by the pool's Strategy:
+// - round_robin cycles through them (per process);
+// - random picks uniformly;
+// - sticky hashes the key (recipient or visitor) so the same contact keeps seeing
+//   the same number while pool membership is unchanged;
+// - local_presence (caller_id pools) matches the callee's area code or region
+//   (SelectCallerID) and rotates round robin when there is no callee.
+//
+// Tracking pools get a publishable SiteKey when created. The number-insertion
+// script embeds it and starts sessions on the public tracking route, which
+// resolves the workspace and pool from it (PoolBySiteKey) instead of a token.
+//
+// PoolHealthWorker checks each number's reputation. Spam-flagged numbers are moved
+// out of rotation (status flagged) and return automatically once they check clean.
+
+type PoolPurpose string
+
+const (
+	PoolPurposeMessaging PoolPurpose = "messaging"
+	PoolPurposeTracking  PoolPurpose = "tracking"
+	PoolPurposeCallerID  PoolPurpose = "caller_id"
+)
+
+type RotationStrategy string
+
+const (
+	RotationRoundRobin RotationStrategy = "round_robin"
+	RotationRandom     RotationStrategy = "random"
+	RotationSticky     RotationStrategy = "sticky"
+	// RotationLocalPresence is only valid for caller_id pools.
+	RotationLocalPresence RotationStrategy = "local_presence"
+)
+
+type PoolNumberStatus string
+
+const (
+	PoolNumberActive  PoolNumberStatus = "active"
+	PoolNumberFlagged PoolNumberStatus = "flagged"
+)
+
+// PoolNumber is a member of a pool.
+type PoolNumber struct {
+	Number     string           `json:"number"`
+	NumberType string           `json:"number_type"`
+	Status     PoolNumberStatus `json:"status"`
+
+	// FlaggedAt/FlagReason are set while the number is out of rotation.
+	FlaggedAt  *time.Time `json:"flagged_at,omitempty"`
+	FlagReason string     `json:"flag_reason,omitempty"`
+
+	AddedAt time.Time `json:"added_at"`
+}
+
+// NumberPool is a rotating set of numbers.
+type NumberPool struct {
+	ID          string           `json:"id" db:"id"`
+	WorkspaceID string           `json:"workspace_id" db:"workspace_id"`
+	Name        string           `json:"name" db:"name"`
+	Purpose     PoolPurpose      `json:"purpose" db:"purpose"`
+	Strategy    RotationStrategy `json:"strategy" db:"strategy"`
+	CampaignID  string           `json:"campaign_id,omitempty" db:"campaign_id"`
+
+	// LocalFallback applies to local_presence pools with no local number for
+	// the callee; empty means LocalFallbackAny.
+	LocalFallback LocalPresenceFallback `json:"local_fallback,omitempty" db:"local_fallback"`
+
+	Numbers []PoolNumber `json:"numbers" db:"numbers"`
+
+	// SiteKey identifies a tracking pool to the public tracking route. It is
+	// embedded in web pages, so it grants nothing beyond starting sessions.
+	SiteKey string `json:"site_key,omitempty" db:"site_key"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	// Version increments on every write; see ErrVersionConflict.
+	Version int64 `json:"version" db:"version"`
+}
+
+// PoolHealth summarizes how much of a pool is usable.
+type PoolHealth struct {
+	Total   int `json:"total"`
+	Active  int `json:"active"`
+	Flagged int `json:"flagged"`
+}
+
+// Health counts the pool's numbers by status.
+func (p NumberPool) Health() PoolHealth {
+	h := PoolHealth{Total: len(p.Numbers)}
+	for _, n := range p.Numbers {
+		if n.Status == PoolNumberActive {
+			h.Active++
+		} else {
+			h.Flagged++
+		}
+	}
+	return h
+}
+
+// PoolRequest creates or replaces a pool's settings and membership.
+type PoolRequest struct {
+	Name       string           `json:"name"`
+	Purpose    PoolPurpose      `json:"purpose"`
+	Strategy   RotationStrategy `json:"strategy"`
+	CampaignID string           `json:"campaign_id"`
+	Numbers    []string         `json:"numbers"`
+
+	LocalFallback LocalPresenceFallback `json:"local_fallback"`
+
+	// IfVersion is the NumberPool.Version the caller last read (from If-Match);
+	// 0 skips the check. Ignored on create.
+	IfVersion int64 `json:"-"`
+}
+
+// PoolStore persists pools. Implementations must enforce workspace filtering.
+type PoolStore interface {
+	// PutPool must reject with ErrVersionConflict unless the stored version is p.Version-1
+	// (a new pool counts as 0).
+	PutPool(ctx context.Context, p NumberPool) error
+	GetPool(ctx context.Context, workspaceID, poolID string) (NumberPool, bool, error)
+	// GetPoolBySiteKey looks a tracking pool up across workspaces.
+	GetPoolBySiteKey(ctx context.Context, siteKey string) (NumberPool, bool, error)
+	ListPools(ctx context.Context, workspaceID string) ([]NumberPool, error)
+	DeletePool(ctx context.Context, workspaceID, poolID string) (bool, error)
+	// ListAllPools returns pools across all workspaces (health worker).
+	ListAllPools(ctx context.Context) ([]NumberPool, error)
+}
+
+// Reputation is a number's spam standing as reported by carriers or an analytics provider.
+type Reputation struct {
+	Flagged bool   `json:"flagged"`
+	Reason  string `json:"reason,omitempty"` // e.g. "spam_likely"
+}
+
+// ReputationChecker looks up a number's reputation.
+type ReputationChecker interface {
+	CheckReputation(ctx context.Context, number string) (Reputation, error)
+}
+
+var (
+	ErrPoolNotFound  = errors.New("numbers: pool not found")
+	ErrPoolExhausted = errors.New("numbers: pool has no active numbers")
+	ErrNumberInPool  = errors.New("numbers: number already belongs to another pool")
+
+	errPoolsNotConfigured = errors.New("numbers: pools not configured")
+)
+
+const maxPoolSize = 500
+
+// CreatePool creates a pool of numbers the workspace owns.
+func (s *Service) CreatePool(ctx context.Context, workspaceID string, req PoolRequest) (NumberPool, error) {
+	now := s.clock().UTC()
+	p := NumberPool{ID: uuid.NewString(), WorkspaceID: workspaceID, CreatedAt: now}
+	return s.savePool(ctx, p, req, now)
+}
+
+// UpdatePool replaces a pool's settings and membership. Numbers kept in the pool
+// keep their flagged status. Honours req.IfVersion.
+func (s *Service) UpdatePool(ctx context.Context, workspaceID, poolID string, req PoolRequest) (NumberPool, error) {
+	p, err := s.GetPool(ctx, workspaceID, poolID)
+	if err != nil {
+		return NumberPool{}, err
+	}
+	if req.IfVersion != 0 && req.IfVersion != p.Version {
+		return NumberPool{}, ErrVersionConflict
+	}
+	return s.savePool(ctx, p, req, s.clock().UTC())
+}
+
+// GetPool returns a pool in the workspace.
+func (s *Service) GetPool(ctx context.Context, workspaceID, poolID string) (NumberPool, error) {
+	if workspaceID == "" || poolID == "" {
+		return NumberPool{}, ErrInvalidArgument
+	}
+	if s.Pools == nil {
+		return NumberPool{}, errPoolsNotConfigured
+	}
+	p, ok, err := s.Pools.GetPool(ctx, workspaceID, poolID)
+	if err != nil {
+		return NumberPool{}, err
+	}
+	if !ok {
+		return NumberPool{}, ErrPoolNotFound
+	}
+	return p, nil
+}
+
+// PoolBySiteKey returns the tracking pool with siteKey, whatever its workspace.
+// The public tracking route uses it to find the workspace a visit belongs to.
+func (s *Service) PoolBySiteKey(ctx context.Context, siteKey string) (NumberPool, error) {
+	siteKey = strings.TrimSpace(siteKey)
+	if siteKey == "" {
+		return NumberPool{}, ErrInvalidArgument
+	}
+	if s.Pools == nil {
+		return NumberPool{}, errPoolsNotConfigured
+	}
+	p, ok, err := s.Pools.GetPoolBySiteKey(ctx, siteKey)
+	if err != nil {
+		return NumberPool{}, err
+	}
+	if !ok || p.Purpose != PoolPurposeTracking {
+		return NumberPool{}, ErrPoolNotFound
+	}
+	return p, nil
+}
+
+// ListPools returns the workspace's pools.
+func (s *Service) ListPools(ctx context.Context, workspaceID string) ([]NumberPool, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	if s.Pools == nil {
+		return nil, errPoolsNotConfigured
+	}
+	return s.Pools.ListPools(ctx, workspaceID)
+}
+
+// DeletePool removes a pool; its numbers stay in the inventory.
+func (s *Service) DeletePool(ctx context.Context, workspaceID, poolID string) error {
+	if workspaceID == "" || poolID == "" {
+		return ErrInvalidArgument
+	}
+	if s.Pools == nil {
+		return errPoolsNotConfigured
+	}
+	ok, err := s.Pools.DeletePool(ctx, workspaceID, poolID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrPoolNotFound
+	}
+	return nil
+}
+
+// NextNumber picks the pool number to use for key (a recipient or visitor id;
+// only the sticky strategy uses it).
+func (s *Service) NextNumber(ctx context.Context, workspaceID, poolID, key string) (PoolNumber, error) {
+	rotated, err := s.RotatedNumbers(ctx, workspaceID, poolID, key)
+	if err != nil {
+		return PoolNumber{}, err
+	}
+	return rotated[0], nil
+}
+
+// RotatedNumbers returns the pool's active numbers starting with NextNumber's
+// pick, followed by the rest in pool order. Senders that spill over to other
+// numbers (e.g. a paced SMS queue) use it as their preference list.
+func (s *Service) RotatedNumbers(ctx context.Context, workspaceID, poolID, key string) ([]PoolNumber, error) {
+	p, err := s.GetPool(ctx, workspaceID, poolID)
+	if err != nil {
+		return nil, err
+	}
+	active := make([]PoolNumber, 0, len(p.Numbers))
+	for _, n := range p.Numbers {
+		if n.Status == PoolNumberActive {
+			active = append(active, n)
+		}
+	}
+	if len(active) == 0 {
+		return nil, ErrPoolExhausted
+	}
+	start := s.pickIndex(p, key, len(active))
+	out := make([]PoolNumber, 0, len(active))
+	out = append(out, active[start:]...)
+	return append(out, active[:start]...), nil
+}
+
+func (s *Service) pickIndex(p NumberPool, key string, n int) int {
+	switch p.Strategy {
+	case RotationSticky:
+		h := fnv.New32a()
+		h.Write([]byte(key))
+		return int(h.Sum32() % uint32(n))
+	case RotationRandom:
+		return mrand.Intn(n)
+	default:
+		s.poolMu.Lock()
+		defer s.poolMu.Unlock()
+		if s.poolCursor == nil {
+			s.poolCursor = map[string]int{}
+		}
+		i := s.poolCursor[p.ID] % n
+		s.poolCursor[p.ID] = i + 1
+		return i
+	}
+}
+
+func (s *Service) savePool(ctx context.Context, p NumberPool, req PoolRequest, now time.Time) (NumberPool, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	req.CampaignID = strings.TrimSpace(req.CampaignID)
+	if req.Strategy == "" {
+		req.Strategy = RotationRoundRobin
+	}
+	if p.WorkspaceID == "" || req.Name == "" || len(req.Numbers) == 0 || len(req.Numbers) > maxPoolSize {
+		return NumberPool{}, ErrInvalidArgument
+	}
+	switch req.Purpose {
+	case PoolPurposeMessaging, PoolPurposeTracking, PoolPurposeCallerID:
+	default:
+		return NumberPool{}, fmt.Errorf("%w: purpose must be messaging, tracking or caller_id", ErrInvalidArgument)
+	}
+	switch req.Strategy {
+	case RotationRoundRobin, RotationRandom, RotationSticky:
+	case RotationLocalPresence:
+		if req.Purpose != PoolPurposeCallerID {
+			return NumberPool{}, fmt.Errorf("%w: local_presence requires a caller_id pool", ErrInvalidArgument)
+		}
+	default:
+		return NumberPool{}, fmt.Errorf("%w: unknown strategy %q", ErrInvalidArgument, req.Strategy)
+	}
+	switch req.LocalFallback {
+	case "", LocalFallbackAny, LocalFallbackDefault, LocalFallbackNone:
+	default:
+		return NumberPool{}, fmt.Errorf("%w: unknown local_fallback %q", ErrInvalidArgument, req.LocalFallback)
+	}
+	if s.Pools == nil || s.Inventory == nil {
+		return NumberPool{}, errPoolsNotConfigured
+	}
+
+	inOtherPool := map[string]bool{}
+	pools, err := s.Pools.ListPools(ctx, p.WorkspaceID)
+	if err != nil {
+		return NumberPool{}, err
+	}
+	for _, other := range pools {
+		if other.ID == p.ID {
+			continue
+		}
+		for _, n := range other.Numbers {
+			inOtherPool[n.Number] = true
+		}
+	}
+	existing := map[string]PoolNumber{}
+	for _, n := range p.Numbers {
+		existing[n.Number] = n
+	}
+
+	members := make([]PoolNumber, 0, len(req.Numbers))
+	seen := map[string]bool{}
+	for _, num := range req.Numbers {
+		num = strings.TrimSpace(num)
+		if num == "" || seen[num] {
+			return NumberPool{}, fmt.Errorf("%w: empty or duplicate number", ErrInvalidArgument)
+		}
+		seen[num] = true
+		if inOtherPool[num] {
+			return NumberPool{}, fmt.Errorf("%w: %s", ErrNumberInPool, num)
+		}
+		if m, ok := existing[num]; ok {
+			members = append(members, m)
+			continue
+		}
+		owned, ok, err := s.Inventory.GetNumber(ctx, p.WorkspaceID, num)
+		if err != nil {
+			return NumberPool{}, err
+		}
+		if !ok {
+			return NumberPool{}, fmt.Errorf("%w: %s", ErrNumberNotFound, num)
+		}
+		if !poolCapable(owned, req.Purpose) {
+			return NumberPool{}, fmt.Errorf("%w: %s", ErrCapabilityMismatch, num)
+		}
+		members = append(members, PoolNumber{Number: num, NumberType: owned.NumberType, Status: PoolNumberActive, AddedAt: now})
+	}
+
+	p.Name = req.Name
+	p.Purpose = req.Purpose
+	p.Strategy = req.Strategy
+	p.CampaignID = req.CampaignID
+	p.LocalFallback = req.LocalFallback
+	p.Numbers = members
+	if p.Purpose == PoolPurposeTracking && p.SiteKey == "" {
+		key, err := newSiteKey()
+		if err != nil {
+			return NumberPool{}, err
+		}
+		p.SiteKey = key
+	}
+	p.UpdatedAt = now
+	p.Version++
+	if err := s.Pools.PutPool(ctx, p); err != nil {
+		return NumberPool{}, err
+	}
+	return p, nil
+}
+
+func newSiteKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+	return "pk_" + hex.EncodeToString(b), nil
+}
+
+// poolCapable: messaging pools need sms or mms, tracking and caller_id pools need voice.
+func poolCapable(n OwnedNumber, purpose PoolPurpose) bool {
+	if purpose == PoolPurposeTracking || purpose == PoolPurposeCallerID {
+		return n.HasCapability(CapabilityVoice)
+	}
+	return n.HasCapability(CapabilitySMS) || n.HasCapability(CapabilityMMS)
+}
+
+// PoolHealthWorker rotates spam-flagged numbers out of pools and back in once they
+// check clean. Run a single instance.
+type PoolHealthWorker struct {
+	Numbers    *Service
+	Reputation ReputationChecker
+	Now        func() time.Time
+}
+
+// RunOnce checks every pooled number and returns how many were flagged and restored.
+// A failed lookup leaves the number as it was.
+func (w PoolHealthWorker) RunOnce(ctx context.Context) (flagged, restored int, err error) {
+	if w.Numbers == nil || w.Numbers.Pools == nil || w.Reputation == nil {
+		return 0, 0, errPoolsNotConfigured
+	}
+	pools, err := w.Numbers.Pools.ListAllPools(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, p := range pools {
+		changed := false
+		for i, n := range p.Numbers {
+			rep, err := w.Reputation.CheckReputation(ctx, n.Number)
+			if err != nil {
+				logger.From(ctx).Error("number reputation check failed", "workspace_id", p.WorkspaceID, "number", n.Number, "err", err)
+				continue
+			}
+			switch {
+			case rep.Flagged && n.Status == PoolNumberActive:
+				now := w.now()
+				n.Status = PoolNumberFlagged
+				n.FlaggedAt = &now
+				n.FlagReason = rep.Reason
+				flagged++
+			case !rep.Flagged && n.Status == PoolNumberFlagged:
+				n.Status = PoolNumberActive
+				n.FlaggedAt = nil
+				n.FlagReason = ""
+				restored++
+			default:
+				continue
+			}
+			p.Numbers[i] = n
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		p.UpdatedAt = w.now()
+		p.Version++
+		if err := w.Numbers.Pools.PutPool(ctx, p); err != nil {
+			// A concurrent edit wins; the next run re-checks.
+			logger.From(ctx).Error("pool health update failed", "workspace_id", p.WorkspaceID, "pool_id", p.ID, "err", err)
+			continue
+		}
+		if h := p.Health(); h.Active == 0 {
+			logger.From(ctx).Error("number pool has no active numbers", "workspace_id", p.WorkspaceID, "pool_id", p.ID, "flagged", h.Flagged)
+		}
+	}
+	return flagged, restored, nil
+}
+
+// Run checks pools on every tick until ctx is canceled.
+func (w PoolHealthWorker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, _, err := w.RunOnce(ctx); err != nil {
+				logger.From(ctx).Error("pool health run failed", "err", err)
+			}
+		}
+	}
+}
+
+func (w PoolHealthWorker) now() time.Time {
+	if w.Now != nil {
+		return w.Now().UTC()
+	}
+	return time.Now().UTC()
+}
@@ -0,0 +1,238 @@
+package numbers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"telecom-platform/internal/telephony"
+)
+
+type stubReputation map[string]Reputation
+
+func (s stubReputation) CheckReputation(ctx context.Context, number string) (Reputation, error) {
+	return s[number], nil
+}
+
+func newPoolService(t *testing.T, nums ...OwnedNumber) (*Service, *MemoryRepo) {
+	t.Helper()
+	repo := NewMemoryRepo()
+	for _, n := range nums {
+		n.Version = 1
+		repo.Numbers[n.WorkspaceID+"|"+n.Number] = n
+	}
+	svc := NewService(&telephony.SIPProvider{}, repo, repo, nil)
+	svc.Inventory = repo
+	svc.Pools = repo
+	return svc, repo
+}
+
+func smsNumber(num string) OwnedNumber {
+	return OwnedNumber{WorkspaceID: "w", Number: num, NumberType: "local", Capabilities: []string{CapabilitySMS}}
+}
+
+func TestCreatePool_ValidatesMembership(t *testing.T) {
+	ctx := context.Background()
+	voiceOnly := OwnedNumber{WorkspaceID: "w", Number: "+15550009", Capabilities: []string{CapabilityVoice}}
+	svc, _ := newPoolService(t, smsNumber("+15550001"), smsNumber("+15550002"), voiceOnly)
+
+	p, err := svc.CreatePool(ctx, "w", PoolRequest{Name: "bulk", Purpose: PoolPurposeMessaging, Numbers: []string{"+15550001"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Strategy != RotationRoundRobin || p.Version != 1 || p.Numbers[0].Status != PoolNumberActive || p.Numbers[0].NumberType != "local" {
+		t.Fatalf("unexpected pool %+v", p)
+	}
+
+	cases := []struct {
+		req  PoolRequest
+		want error
+	}{
+		{PoolRequest{Name: "x", Purpose: PoolPurposeMessaging, Numbers: []string{"+15550001"}}, ErrNumberInPool},
+		{PoolRequest{Name: "x", Purpose: PoolPurposeMessaging, Numbers: []string{"+15559999"}}, ErrNumberNotFound},
+		{PoolRequest{Name: "x", Purpose: PoolPurposeMessaging, Numbers: []string{"+15550009"}}, ErrCapabilityMismatch},
+		{PoolRequest{Name: "x", Purpose: "fax", Numbers: []string{"+15550002"}}, ErrInvalidArgument},
+		{PoolRequest{Name: "x", Purpose: PoolPurposeMessaging, Strategy: "weighted", Numbers: []string{"+15550002"}}, ErrInvalidArgument},
+		{PoolRequest{Name: "x", Purpose: PoolPurposeMessaging, Numbers: []string{"+15550002", "+15550002"}}, ErrInvalidArgument},
+	}
+	for _, tc := range cases {
+		if _, err := svc.CreatePool(ctx, "w", tc.req); !errors.Is(err, tc.want) {
+			t.Fatalf("%+v: expected %v, got %v", tc.req, tc.want, err)
+		}
+	}
+
+	// Updating with a stale version conflicts.
+	if _, err := svc.UpdatePool(ctx, "w", p.ID, PoolRequest{Name: "bulk", Purpose: PoolPurposeMessaging, Numbers: []string{"+15550001"}, IfVersion: 7}); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected ErrVersionConflict, got %v", err)
+	}
+}
+
+func TestNextNumber_Strategies(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newPoolService(t, smsNumber("+15550001"), smsNumber("+15550002"), smsNumber("+15550003"))
+	nums := []string{"+15550001", "+15550002", "+15550003"}
+
+	rr, _ := svc.CreatePool(ctx, "w", PoolRequest{Name: "rr", Purpose: PoolPurposeMessaging, Numbers: nums})
+	var got []string
+	for i := 0; i < 4; i++ {
+		n, err := svc.NextNumber(ctx, "w", rr.ID, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, n.Number)
+	}
+	if got[0] != nums[0] || got[1] != nums[1] || got[2] != nums[2] || got[3] != nums[0] {
+		t.Fatalf("expected round robin, got %v", got)
+	}
+
+	rotated, _ := svc.RotatedNumbers(ctx, "w", rr.ID, "")
+	if len(rotated) != 3 || rotated[0].Number != nums[1] || rotated[2].Number != nums[0] {
+		t.Fatalf("expected rotation starting at the next pick, got %+v", rotated)
+	}
+
+	if err := svc.DeletePool(ctx, "w", rr.ID); err != nil {
+		t.Fatal(err)
+	}
+	sticky, _ := svc.CreatePool(ctx, "w", PoolRequest{Name: "sticky", Purpose: PoolPurposeMessaging, Strategy: RotationSticky, Numbers: nums})
+	first, _ := svc.NextNumber(ctx, "w", sticky.ID, "+19990001")
+	for i := 0; i < 5; i++ {
+		if n, _ := svc.NextNumber(ctx, "w", sticky.ID, "+19990001"); n.Number != first.Number {
+			t.Fatalf("sticky pick changed from %s to %s", first.Number, n.Number)
+		}
+	}
+}
+
+func TestPoolBySiteKey_ResolvesTrackingPools(t *testing.T) {
+	ctx := context.Background()
+	voice := OwnedNumber{WorkspaceID: "w", Number: "+15550007", Capabilities: []string{CapabilityVoice}}
+	svc, _ := newPoolService(t, smsNumber("+15550001"), voice)
+
+	msg, _ := svc.CreatePool(ctx, "w", PoolRequest{Name: "sms", Purpose: PoolPurposeMessaging, Numbers: []string{"+15550001"}})
+	if msg.SiteKey != "" {
+		t.Fatalf("messaging pools get no site key, got %q", msg.SiteKey)
+	}
+	tr, err := svc.CreatePool(ctx, "w", PoolRequest{Name: "dni", Purpose: PoolPurposeTracking, Numbers: []string{"+15550007"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(tr.SiteKey, "pk_") {
+		t.Fatalf("expected a pk_ site key, got %q", tr.SiteKey)
+	}
+	updated, err := svc.UpdatePool(ctx, "w", tr.ID, PoolRequest{Name: "dni-renamed", Purpose: PoolPurposeTracking, Numbers: []string{"+15550007"}})
+	if err != nil || updated.SiteKey != tr.SiteKey {
+		t.Fatalf("expected site key kept on update, got %q err=%v", updated.SiteKey, err)
+	}
+
+	got, err := svc.PoolBySiteKey(ctx, tr.SiteKey)
+	if err != nil || got.ID != tr.ID || got.WorkspaceID != "w" {
+		t.Fatalf("expected pool %s in w, got %+v err=%v", tr.ID, got, err)
+	}
+	if _, err := svc.PoolBySiteKey(ctx, "pk_unknown"); !errors.Is(err, ErrPoolNotFound) {
+		t.Fatalf("expected ErrPoolNotFound, got %v", err)
+	}
+}
+
+func TestPoolHealthWorker_RotatesFlaggedNumbersOutAndBack(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newPoolService(t, smsNumber("+15550001"), smsNumber("+15550002"))
+	p, _ := svc.CreatePool(ctx, "w", PoolRequest{Name: "bulk", Purpose: PoolPurposeMessaging, Numbers: []string{"+15550001", "+15550002"}})
+
+	rep := stubReputation{"+15550001": {Flagged: true, Reason: "spam_likely"}}
+	w := PoolHealthWorker{Numbers: svc, Reputation: rep}
+	if flagged, restored, err := w.RunOnce(ctx); err != nil || flagged != 1 || restored != 0 {
+		t.Fatalf("expected one flagged, got %d/%d, %v", flagged, restored, err)
+	}
+	for i := 0; i < 3; i++ {
+		if n, _ := svc.NextNumber(ctx, "w", p.ID, ""); n.Number != "+15550002" {
+			t.Fatalf("flagged number still in rotation: %s", n.Number)
+		}
+	}
+	got, _ := svc.GetPool(ctx, "w", p.ID)
+	if h := got.Health(); h.Active != 1 || h.Flagged != 1 || got.Numbers[0].FlagReason != "spam_likely" {
+		t.Fatalf("unexpected health %+v / %+v", h, got.Numbers[0])
+	}
+
+	delete(rep, "+15550001")
+	if flagged, restored, _ := w.RunOnce(ctx); flagged != 0 || restored != 1 {
+		t.Fatalf("expected one restored, got %d/%d", flagged, restored)
+	}
+
+	rep["+15550001"] = Reputation{Flagged: true}
+	rep["+15550002"] = Reputation{Flagged: true}
+	w.RunOnce(ctx)
+	if _, err := svc.NextNumber(ctx, "w", p.ID, ""); !errors.Is(err, ErrPoolExhausted) {
+		t.Fatalf("expected ErrPoolExhausted, got %v", err)
+	}
+}
+
+type stubRegions map[string]string // area code -> region
+
+func (s stubRegions) Region(number string) (string, bool) {
+	area, ok := nanpAreaCode(number)
+	if !ok {
+		return "", false
+	}
+	r, ok := s[area]
+	return r, ok
+}
+
+func TestSelectCallerID_LocalPresence(t *testing.T) {
+	ctx := context.Background()
+	voice := func(num string) OwnedNumber {
+		return OwnedNumber{WorkspaceID: "w", Number: num, Capabilities: []string{CapabilityVoice}}
+	}
+	svc, repo := newPoolService(t, voice("+14155550001"), voice("+13105550002"), voice("+12125550003"), smsNumber("+15550001"))
+	svc.Regions = stubRegions{"415": "US-CA", "310": "US-CA", "213": "US-CA", "212": "US-NY"}
+	svc.CallerIDs = repo
+	nums := []string{"+12125550003", "+14155550001", "+13105550002"}
+
+	if _, err := svc.CreatePool(ctx, "w", PoolRequest{Name: "x", Purpose: PoolPurposeMessaging, Strategy: RotationLocalPresence, Numbers: []string{"+15550001"}}); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected local_presence to require a caller_id pool, got %v", err)
+	}
+	p, err := svc.CreatePool(ctx, "w", PoolRequest{Name: "local", Purpose: PoolPurposeCallerID, Strategy: RotationLocalPresence, Numbers: nums})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		to     string
+		number string
+		match  CallerIDMatch
+	}{
+		{"+14155559999", "+14155550001", CallerIDMatchAreaCode},
+		{"+12135559999", "", CallerIDMatchRegion}, // 415 or 310
+		{"+447700900123", "", CallerIDMatchFallback},
+	}
+	for _, tc := range cases {
+		rec, err := svc.SelectCallerID(ctx, "w", p.ID, tc.to, "call-"+tc.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.Match != tc.match || (tc.number != "" && rec.CallerID != tc.number) {
+			t.Fatalf("%s: unexpected pick %+v", tc.to, rec)
+		}
+		if tc.match == CallerIDMatchRegion && rec.CallerID == "+12125550003" {
+			t.Fatalf("region match picked an out-of-region number %s", rec.CallerID)
+		}
+	}
+	if len(repo.CallerIDs) != 3 || repo.CallerIDs[0].CallID != "call-+14155559999" || repo.CallerIDs[0].ChosenAt.IsZero() {
+		t.Fatalf("expected every pick recorded, got %+v", repo.CallerIDs)
+	}
+
+	// Previews are not recorded; fallback "default" is the pool's first number
+	// and "none" refuses a non-local caller ID.
+	p, err = svc.UpdatePool(ctx, "w", p.ID, PoolRequest{Name: "local", Purpose: PoolPurposeCallerID, Strategy: RotationLocalPresence, LocalFallback: LocalFallbackDefault, Numbers: nums})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec, _ := svc.SelectCallerID(ctx, "w", p.ID, "+447700900123", ""); rec.CallerID != nums[0] || len(repo.CallerIDs) != 3 {
+		t.Fatalf("expected unrecorded default fallback, got %+v", rec)
+	}
+	if _, err := svc.UpdatePool(ctx, "w", p.ID, PoolRequest{Name: "local", Purpose: PoolPurposeCallerID, Strategy: RotationLocalPresence, LocalFallback: LocalFallbackNone, Numbers: nums}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SelectCallerID(ctx, "w", p.ID, "+447700900123", ""); !errors.Is(err, ErrNoLocalNumber) {
+		t.Fatalf("expected ErrNoLocalNumber, got %v", err)
+	}
+}
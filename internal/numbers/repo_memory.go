@@ -0,0 +1,265 @@
+package numbers
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryRepo is a simple in-memory PolicyStore, RequirementsSource, InventoryStore,
+// PoolStore, DriftReportStore, CallerIDRecordStore, VerifiedCallerIDStore and
+// EmergencyAddressStore for tests and early development. It is not intended for production use.
+type MemoryRepo struct {
+	mu sync.Mutex
+
+	Policies map[string]PurchasePolicy // key: workspace_id
+
+	// Requirements is keyed by COUNTRY|number_type.
+	Requirements map[string]RegulatoryRequirement
+
+	Numbers map[string]OwnedNumber // key: workspace_id|number
+
+	Pools map[string]NumberPool // key: pool id
+
+	DriftReports []ConfigDriftReport
+
+	CallerIDs []CallerIDRecord
+
+	VerifiedCallerIDs map[string]VerifiedCallerID // key: id
+
+	EmergencyAddresses map[string]NumberEmergencyAddress // key: workspace_id|number
+}
+
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{Policies: map[string]PurchasePolicy{}, Requirements: map[string]RegulatoryRequirement{}, Numbers: map[string]OwnedNumber{}, Pools: map[string]NumberPool{}, VerifiedCallerIDs: map[string]VerifiedCallerID{}, EmergencyAddresses: map[string]NumberEmergencyAddress{}}
+}
+
+func (r *MemoryRepo) GetPolicy(ctx context.Context, workspaceID string) (PurchasePolicy, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.Policies[workspaceID]
+	return p, ok, nil
+}
+
+func (r *MemoryRepo) PutPolicy(ctx context.Context, p PurchasePolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Policies[p.WorkspaceID].Version != p.Version-1 {
+		return ErrVersionConflict
+	}
+	r.Policies[p.WorkspaceID] = p
+	return nil
+}
+
+func (r *MemoryRepo) GetRequirement(ctx context.Context, countryISO2, numberType string) (RegulatoryRequirement, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	req, ok := r.Requirements[strings.ToUpper(countryISO2)+"|"+numberType]
+	return req, ok, nil
+}
+
+func (r *MemoryRepo) PutNumber(ctx context.Context, n OwnedNumber) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := n.WorkspaceID + "|" + n.Number
+	if r.Numbers[key].Version != n.Version-1 {
+		return ErrVersionConflict
+	}
+	r.Numbers[key] = n
+	return nil
+}
+
+func (r *MemoryRepo) GetNumber(ctx context.Context, workspaceID, number string) (OwnedNumber, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n, ok := r.Numbers[workspaceID+"|"+number]
+	return n, ok, nil
+}
+
+func (r *MemoryRepo) ListNumbers(ctx context.Context, workspaceID string) ([]OwnedNumber, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []OwnedNumber
+	for _, n := range r.Numbers {
+		if n.WorkspaceID == workspaceID {
+			out = append(out, n)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Number < out[j].Number })
+	return out, nil
+}
+
+func (r *MemoryRepo) PutPool(ctx context.Context, p NumberPool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cur, ok := r.Pools[p.ID]; (ok && cur.WorkspaceID != p.WorkspaceID) || cur.Version != p.Version-1 {
+		return ErrVersionConflict
+	}
+	r.Pools[p.ID] = copyPool(p)
+	return nil
+}
+
+func (r *MemoryRepo) GetPool(ctx context.Context, workspaceID, poolID string) (NumberPool, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.Pools[poolID]
+	if !ok || p.WorkspaceID != workspaceID {
+		return NumberPool{}, false, nil
+	}
+	return copyPool(p), true, nil
+}
+
+func (r *MemoryRepo) GetPoolBySiteKey(ctx context.Context, siteKey string) (NumberPool, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.Pools {
+		if p.SiteKey != "" && p.SiteKey == siteKey {
+			return copyPool(p), true, nil
+		}
+	}
+	return NumberPool{}, false, nil
+}
+
+func (r *MemoryRepo) ListPools(ctx context.Context, workspaceID string) ([]NumberPool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]NumberPool, 0)
+	for _, p := range r.Pools {
+		if p.WorkspaceID == workspaceID {
+			out = append(out, copyPool(p))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (r *MemoryRepo) DeletePool(ctx context.Context, workspaceID, poolID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.Pools[poolID]
+	if !ok || p.WorkspaceID != workspaceID {
+		return false, nil
+	}
+	delete(r.Pools, poolID)
+	return true, nil
+}
+
+func (r *MemoryRepo) ListAllPools(ctx context.Context) ([]NumberPool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]NumberPool, 0, len(r.Pools))
+	for _, p := range r.Pools {
+		out = append(out, copyPool(p))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func copyPool(p NumberPool) NumberPool {
+	p.Numbers = append([]PoolNumber(nil), p.Numbers...)
+	return p
+}
+
+func (r *MemoryRepo) SaveDriftReport(ctx context.Context, rep ConfigDriftReport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rep.Drifts = append([]ConfigDrift(nil), rep.Drifts...)
+	r.DriftReports = append(r.DriftReports, rep)
+	return nil
+}
+
+func (r *MemoryRepo) LatestDriftReport(ctx context.Context) (ConfigDriftReport, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.DriftReports) == 0 {
+		return ConfigDriftReport{}, false, nil
+	}
+	return r.DriftReports[len(r.DriftReports)-1], true, nil
+}
+
+func (r *MemoryRepo) RecordCallerID(ctx context.Context, rec CallerIDRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.CallerIDs = append(r.CallerIDs, rec)
+	return nil
+}
+
+func (r *MemoryRepo) PutVerifiedCallerID(ctx context.Context, v VerifiedCallerID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.VerifiedCallerIDs[v.ID] = v
+	return nil
+}
+
+func (r *MemoryRepo) GetVerifiedCallerID(ctx context.Context, workspaceID, id string) (VerifiedCallerID, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v, ok := r.VerifiedCallerIDs[id]
+	if !ok || v.WorkspaceID != workspaceID {
+		return VerifiedCallerID{}, false, nil
+	}
+	return v, true, nil
+}
+
+func (r *MemoryRepo) FindVerifiedCallerID(ctx context.Context, workspaceID, number string) (VerifiedCallerID, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, v := range r.VerifiedCallerIDs {
+		if v.WorkspaceID == workspaceID && v.Number == number {
+			return v, true, nil
+		}
+	}
+	return VerifiedCallerID{}, false, nil
+}
+
+func (r *MemoryRepo) ListVerifiedCallerIDs(ctx context.Context, workspaceID string) ([]VerifiedCallerID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]VerifiedCallerID, 0)
+	for _, v := range r.VerifiedCallerIDs {
+		if v.WorkspaceID == workspaceID {
+			out = append(out, v)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Number < out[j].Number })
+	return out, nil
+}
+
+func (r *MemoryRepo) DeleteVerifiedCallerID(ctx context.Context, workspaceID, id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v, ok := r.VerifiedCallerIDs[id]
+	if !ok || v.WorkspaceID != workspaceID {
+		return false, nil
+	}
+	delete(r.VerifiedCallerIDs, id)
+	return true, nil
+}
+
+func (r *MemoryRepo) PutEmergencyAddress(ctx context.Context, a NumberEmergencyAddress) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.EmergencyAddresses[a.WorkspaceID+"|"+a.Number] = a
+	return nil
+}
+
+func (r *MemoryRepo) GetEmergencyAddress(ctx context.Context, workspaceID, number string) (NumberEmergencyAddress, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a, ok := r.EmergencyAddresses[workspaceID+"|"+number]
+	return a, ok, nil
+}
+
+func (r *MemoryRepo) ListEmergencyAddresses(ctx context.Context, workspaceID string) ([]NumberEmergencyAddress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]NumberEmergencyAddress, 0)
+	for _, a := range r.EmergencyAddresses {
+		if a.WorkspaceID == workspaceID {
+			out = append(out, a)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Number < out[j].Number })
+	return out, nil
+}
@@ -0,0 +1,269 @@
+package numbers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/telephony"
+)
+
+// Number search.
+//
+// Provider searches are slow, rate limited and paginated with provider-specific tokens.
+// Results are normalized and cached per query for SearchCacheTTL; clients page through
+// the cached list with our own opaque token so "next page" neither repeats numbers nor
+// costs another provider call until the cache is exhausted.
+
+const (
+	SearchCacheTTL         = 5 * time.Minute
+	DefaultSearchPageSize  = 20
+	MaxSearchPageSize      = 100
+	maxSearchCacheEntries  = 512
+	providerSearchPageSize = 100
+)
+
+var knownCapabilities = map[string]bool{CapabilityVoice: true, CapabilitySMS: true, CapabilityMMS: true, CapabilityFax: true}
+
+type searchEntry struct {
+	numbers      []telephony.AvailableNumber
+	providerNext string
+	fetchedAt    time.Time
+}
+
+// SearchNumbers lists numbers the workspace may buy. The workspace purchase policy is
+// applied before the provider is queried.
+func (s *Service) SearchNumbers(ctx context.Context, workspaceID string, q SearchQuery) (SearchPage, error) {
+	q, err := normalizeSearchQuery(q)
+	if err != nil || workspaceID == "" {
+		return SearchPage{}, ErrInvalidArgument
+	}
+	searcher, ok := s.provider.(telephony.NumberSearcher)
+	if !ok {
+		return SearchPage{}, ErrSearchUnsupported
+	}
+	if _, err := s.allowedPolicy(ctx, workspaceID, q.CountryISO2, q.NumberType); err != nil {
+		return SearchPage{}, err
+	}
+
+	key := searchKey(q)
+	offset := 0
+	if q.PageToken != "" {
+		offset, err = decodePageToken(q.PageToken, key)
+		if err != nil {
+			return SearchPage{}, err
+		}
+	}
+
+	now := s.clock()
+	s.searchMu.Lock()
+	entry, cached := s.searchCache[key]
+	if cached && now.Sub(entry.fetchedAt) > SearchCacheTTL {
+		delete(s.searchCache, key)
+		cached = false
+	}
+	s.searchMu.Unlock()
+	if !cached {
+		if offset > 0 {
+			return SearchPage{}, ErrInvalidPageToken
+		}
+		entry = &searchEntry{fetchedAt: now}
+	}
+
+	// Pull provider pages until this page is full or the provider runs out. The lock
+	// is not held across provider calls; concurrent identical searches may both fetch.
+	first := !cached
+	for len(entry.numbers) < offset+q.PageSize && (first || entry.providerNext != "") {
+		res, err := searcher.SearchNumbers(ctx, telephony.SearchNumbersRequest{
+			WorkspaceID:  workspaceID,
+			CountryISO2:  q.CountryISO2,
+			NumberType:   q.NumberType,
+			AreaCode:     q.AreaCode,
+			Contains:     q.Contains,
+			Capabilities: q.Capabilities,
+			PageToken:    entry.providerNext,
+			PageSize:     providerSearchPageSize,
+		})
+		if err != nil {
+			return SearchPage{}, err
+		}
+		first = false
+		entry = &searchEntry{
+			numbers:      appendNormalized(entry.numbers, res.Numbers, q),
+			providerNext: res.NextPageToken,
+			fetchedAt:    entry.fetchedAt,
+		}
+	}
+	s.storeSearch(key, entry, now)
+
+	end := offset + q.PageSize
+	if end > len(entry.numbers) {
+		end = len(entry.numbers)
+	}
+	page := SearchPage{Numbers: []telephony.AvailableNumber{}}
+	if offset < end {
+		page.Numbers = append(page.Numbers, entry.numbers[offset:end]...)
+	}
+	if end < len(entry.numbers) || entry.providerNext != "" {
+		page.NextPageToken = encodePageToken(end, key)
+	}
+	return page, nil
+}
+
+func (s *Service) storeSearch(key string, entry *searchEntry, now time.Time) {
+	s.searchMu.Lock()
+	defer s.searchMu.Unlock()
+	if len(s.searchCache) >= maxSearchCacheEntries {
+		for k, e := range s.searchCache {
+			if now.Sub(e.fetchedAt) > SearchCacheTTL {
+				delete(s.searchCache, k)
+			}
+		}
+		if len(s.searchCache) >= maxSearchCacheEntries {
+			s.searchCache = map[string]*searchEntry{}
+		}
+	}
+	s.searchCache[key] = entry
+}
+
+func normalizeSearchQuery(q SearchQuery) (SearchQuery, error) {
+	q.CountryISO2 = strings.ToUpper(strings.TrimSpace(q.CountryISO2))
+	q.NumberType = strings.TrimSpace(q.NumberType)
+	q.AreaCode = strings.TrimSpace(q.AreaCode)
+	q.Contains = strings.TrimSpace(q.Contains)
+	if q.CountryISO2 == "" || q.NumberType == "" || !allDigits(q.AreaCode) {
+		return q, ErrInvalidArgument
+	}
+	for _, r := range q.Contains {
+		if !(r >= '0' && r <= '9') && r != '*' {
+			return q, ErrInvalidArgument
+		}
+	}
+	caps, err := normalizeCapabilities(q.Capabilities)
+	if err != nil {
+		return q, err
+	}
+	q.Capabilities = caps
+	switch {
+	case q.PageSize <= 0:
+		q.PageSize = DefaultSearchPageSize
+	case q.PageSize > MaxSearchPageSize:
+		q.PageSize = MaxSearchPageSize
+	}
+	return q, nil
+}
+
+// appendNormalized adds provider results in E.164 with lower-case capabilities,
+// dropping duplicates and numbers missing a requested capability.
+func appendNormalized(dst, src []telephony.AvailableNumber, q SearchQuery) []telephony.AvailableNumber {
+	// Copy: dst may be shared with a cached entry another request is reading.
+	dst = append(make([]telephony.AvailableNumber, 0, len(dst)+len(src)), dst...)
+	seen := make(map[string]bool, len(dst))
+	for _, n := range dst {
+		seen[n.Number] = true
+	}
+	for _, n := range src {
+		n.Number = strings.TrimSpace(n.Number)
+		if n.Number == "" {
+			continue
+		}
+		if !strings.HasPrefix(n.Number, "+") {
+			n.Number = "+" + n.Number
+		}
+		if seen[n.Number] {
+			continue
+		}
+		if n.CountryISO2 == "" {
+			n.CountryISO2 = q.CountryISO2
+		}
+		if n.NumberType == "" {
+			n.NumberType = q.NumberType
+		}
+		caps := make([]string, 0, len(n.Capabilities))
+		for _, c := range n.Capabilities {
+			caps = append(caps, strings.ToLower(strings.TrimSpace(c)))
+		}
+		sort.Strings(caps)
+		n.Capabilities = caps
+		if !hasAll(caps, q.Capabilities) {
+			continue
+		}
+		seen[n.Number] = true
+		dst = append(dst, n)
+	}
+	return dst
+}
+
+// normalizeCapabilities lower-cases, de-duplicates and sorts caps, rejecting unknown values.
+func normalizeCapabilities(caps []string) ([]string, error) {
+	out := make([]string, 0, len(caps))
+	for _, c := range caps {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c == "" || hasAll(out, []string{c}) {
+			continue
+		}
+		if !knownCapabilities[c] {
+			return nil, ErrInvalidArgument
+		}
+		out = append(out, c)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func hasAll(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if h == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func allDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func searchKey(q SearchQuery) string {
+	return strings.Join([]string{q.CountryISO2, q.NumberType, q.AreaCode, q.Contains, strings.Join(q.Capabilities, ",")}, "|")
+}
+
+// Page tokens bind an offset to the query that produced it.
+func encodePageToken(offset int, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset) + "." + hex.EncodeToString(sum[:8])))
+}
+
+func decodePageToken(token, key string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidPageToken
+	}
+	off, sig, ok := strings.Cut(string(raw), ".")
+	sum := sha256.Sum256([]byte(key))
+	if !ok || sig != hex.EncodeToString(sum[:8]) {
+		return 0, ErrInvalidPageToken
+	}
+	n, err := strconv.Atoi(off)
+	if err != nil || n < 0 {
+		return 0, ErrInvalidPageToken
+	}
+	return n, nil
+}
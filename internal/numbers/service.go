@@ -0,0 +1,274 @@
+package numbers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"telecom-platform/internal/telephony"
+)
+
+// Service implements the number purchase use case.
+//
+// Rules:
+// - Workspace purchase policies (countries, number types) are enforced before any provider call.
+// - Countries that need KYC require an approved regulatory bundle.
+// - Provider calls only go through telephony.TelephonyProvider.
+type Service struct {
+	provider     telephony.TelephonyProvider
+	policies     PolicyStore
+	requirements RequirementsSource
+	bundles      BundleChecker
+	clock        func() time.Time
+
+	searchMu    sync.Mutex
+	searchCache map[string]*searchEntry
+
+	// Inventory records purchased numbers and their assignments (optional).
+	Inventory InventoryStore
+
+	// Pools stores number pools (see pools.go). Optional; requires Inventory.
+	Pools      PoolStore
+	poolMu     sync.Mutex
+	poolCursor map[string]int
+
+	// Regions and CallerIDs serve local presence caller ID selection (see
+	// local_presence.go); both are optional.
+	Regions   RegionResolver
+	CallerIDs CallerIDRecordStore
+
+	// VerifiedCallerIDs registers external caller IDs (see
+	// caller_id_verification.go). Optional; without it only owned numbers are
+	// authorized.
+	VerifiedCallerIDs VerifiedCallerIDStore
+
+	// EmergencyAddresses records E911 addresses per number (see
+	// emergency_address.go). Optional; requires Inventory.
+	EmergencyAddresses EmergencyAddressStore
+
+	// Webhooks is this environment's callback base; when set, purchased and imported
+	// numbers are configured to call back here (providers implementing
+	// telephony.NumberConfigurer). Setup is tried WebhookAttempts times,
+	// WebhookRetryDelay apart; WebhookDriftJob repairs anything left behind.
+	Webhooks          telephony.WebhookURLs
+	WebhookAttempts   int
+	WebhookRetryDelay time.Duration
+}
+
+// PolicyStore persists per-workspace purchase policies.
+type PolicyStore interface {
+	// GetPolicy returns (PurchasePolicy{}, false, nil) when the workspace has no policy.
+	GetPolicy(ctx context.Context, workspaceID string) (PurchasePolicy, bool, error)
+	// PutPolicy must reject with ErrVersionConflict unless the stored version is p.Version-1.
+	PutPolicy(ctx context.Context, p PurchasePolicy) error
+}
+
+// RequirementsSource exposes provider regulatory-document requirements.
+// Implementations typically wrap a provider regulatory API and cache results.
+type RequirementsSource interface {
+	// GetRequirement returns (RegulatoryRequirement{}, false, nil) for unregulated countries.
+	GetRequirement(ctx context.Context, countryISO2, numberType string) (RegulatoryRequirement, bool, error)
+}
+
+// BundleChecker verifies that a workspace's regulatory bundle is approved for a country.
+type BundleChecker interface {
+	IsBundleApproved(ctx context.Context, workspaceID, bundleID, countryISO2, numberType string) (bool, error)
+}
+
+// NumberLinker is optionally implemented by a BundleChecker to record which numbers
+// were purchased under a bundle.
+type NumberLinker interface {
+	LinkNumber(ctx context.Context, workspaceID, bundleID, number string) error
+}
+
+func NewService(provider telephony.TelephonyProvider, policies PolicyStore, requirements RequirementsSource, bundles BundleChecker) *Service {
+	return &Service{
+		provider: provider, policies: policies, requirements: requirements, bundles: bundles, clock: time.Now,
+		searchCache:     map[string]*searchEntry{},
+		WebhookAttempts: 3, WebhookRetryDelay: 500 * time.Millisecond,
+	}
+}
+
+var (
+	ErrInvalidArgument          = errors.New("numbers: invalid argument")
+	ErrPurchaseNotAllowed       = errors.New("numbers: purchase not allowed by workspace policy")
+	ErrRegulatoryBundleRequired = errors.New("numbers: approved regulatory bundle required")
+	ErrSearchUnsupported        = errors.New("numbers: provider does not support number search")
+	ErrInvalidPageToken         = errors.New("numbers: invalid or expired page token")
+
+	// ErrVersionConflict means the resource changed since the caller read it
+	// (optimistic concurrency for dashboard edits).
+	ErrVersionConflict = errors.New("numbers: resource was modified concurrently")
+)
+
+// Requirements returns the regulatory requirement for a country/number type.
+// found=false means no documents are needed.
+func (s *Service) Requirements(ctx context.Context, countryISO2, numberType string) (RegulatoryRequirement, bool, error) {
+	countryISO2 = strings.ToUpper(strings.TrimSpace(countryISO2))
+	numberType = strings.TrimSpace(numberType)
+	if countryISO2 == "" || numberType == "" {
+		return RegulatoryRequirement{}, false, ErrInvalidArgument
+	}
+	if s.requirements == nil {
+		return RegulatoryRequirement{}, false, nil
+	}
+	return s.requirements.GetRequirement(ctx, countryISO2, numberType)
+}
+
+// SetPolicy replaces the purchase policy of a workspace.
+func (s *Service) SetPolicy(ctx context.Context, p PurchasePolicy) (PurchasePolicy, error) {
+	if p.WorkspaceID == "" {
+		return PurchasePolicy{}, ErrInvalidArgument
+	}
+	if s.policies == nil {
+		return PurchasePolicy{}, errors.New("numbers: policy store not configured")
+	}
+	for i, c := range p.AllowedCountries {
+		p.AllowedCountries[i] = strings.ToUpper(strings.TrimSpace(c))
+	}
+	cur, _, err := s.policies.GetPolicy(ctx, p.WorkspaceID)
+	if err != nil {
+		return PurchasePolicy{}, err
+	}
+	if p.Version, err = nextVersion(cur.Version, p.Version); err != nil {
+		return PurchasePolicy{}, err
+	}
+	p.UpdatedAt = s.clock().UTC()
+	if err := s.policies.PutPolicy(ctx, p); err != nil {
+		return PurchasePolicy{}, err
+	}
+	return p, nil
+}
+
+// BuyNumber enforces the workspace policy and regulatory prerequisites, then purchases
+// the number through the provider.
+func (s *Service) BuyNumber(ctx context.Context, workspaceID string, req PurchaseRequest) (telephony.BuyNumberResult, error) {
+	req.CountryISO2 = strings.ToUpper(strings.TrimSpace(req.CountryISO2))
+	req.NumberType = strings.TrimSpace(req.NumberType)
+	if workspaceID == "" || req.CountryISO2 == "" || req.NumberType == "" {
+		return telephony.BuyNumberResult{}, ErrInvalidArgument
+	}
+	caps, err := normalizeCapabilities(req.Capabilities)
+	if err != nil {
+		return telephony.BuyNumberResult{}, err
+	}
+	if s.provider == nil {
+		return telephony.BuyNumberResult{}, errors.New("numbers: provider not configured")
+	}
+
+	policy, err := s.allowedPolicy(ctx, workspaceID, req.CountryISO2, req.NumberType)
+	if err != nil {
+		return telephony.BuyNumberResult{}, err
+	}
+
+	reqmt, regulated, err := s.Requirements(ctx, req.CountryISO2, req.NumberType)
+	if err != nil {
+		return telephony.BuyNumberResult{}, err
+	}
+	if reqmt.NeedsDocuments() || (regulated && policy.RequireRegulatoryBundle) {
+		if req.RegulatoryBundleID == "" || s.bundles == nil {
+			return telephony.BuyNumberResult{}, ErrRegulatoryBundleRequired
+		}
+		ok, err := s.bundles.IsBundleApproved(ctx, workspaceID, req.RegulatoryBundleID, req.CountryISO2, req.NumberType)
+		if err != nil {
+			return telephony.BuyNumberResult{}, err
+		}
+		if !ok {
+			return telephony.BuyNumberResult{}, ErrRegulatoryBundleRequired
+		}
+	}
+
+	metadata := ""
+	if req.RegulatoryBundleID != "" {
+		md, _ := json.Marshal(map[string]string{"regulatory_bundle_id": req.RegulatoryBundleID})
+		metadata = string(md)
+	}
+	res, err := s.provider.BuyNumber(ctx, telephony.BuyNumberRequest{
+		WorkspaceID:   workspaceID,
+		CountryISO2:   req.CountryISO2,
+		NumberType:    req.NumberType,
+		DesiredNumber: req.DesiredNumber,
+		Capabilities:  caps,
+		Metadata:      metadata,
+	})
+	if err != nil {
+		return telephony.BuyNumberResult{}, err
+	}
+	if len(res.Capabilities) > 0 {
+		if c, err := normalizeCapabilities(res.Capabilities); err == nil {
+			caps = c
+		}
+	}
+	res.Capabilities = caps
+
+	if req.RegulatoryBundleID != "" && res.Number != "" {
+		if l, ok := s.bundles.(NumberLinker); ok {
+			// Best-effort: the number is already bought; a missing link only affects re-verification.
+			_ = l.LinkNumber(ctx, workspaceID, req.RegulatoryBundleID, res.Number)
+		}
+	}
+	// Best-effort: a failed webhook setup leaves WebhooksConfiguredAt nil and can be
+	// retried with ConfigureWebhooks.
+	configuredAt, _ := s.configureWebhooks(ctx, workspaceID, res.Number, res.ProviderNumberID, messagingCapable(caps))
+	if s.Inventory != nil && res.Number != "" {
+		now := s.clock().UTC()
+		if err := s.Inventory.PutNumber(ctx, OwnedNumber{
+			WorkspaceID:          workspaceID,
+			Number:               res.Number,
+			ProviderNumberID:     res.ProviderNumberID,
+			CountryISO2:          req.CountryISO2,
+			NumberType:           req.NumberType,
+			Capabilities:         caps,
+			WebhooksConfiguredAt: configuredAt,
+			PurchasedAt:          now,
+			UpdatedAt:            now,
+			Version:              1,
+		}); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// allowedPolicy returns the workspace policy, or ErrPurchaseNotAllowed when it
+// excludes the country/number type.
+func (s *Service) allowedPolicy(ctx context.Context, workspaceID, countryISO2, numberType string) (PurchasePolicy, error) {
+	var policy PurchasePolicy
+	if s.policies != nil {
+		p, ok, err := s.policies.GetPolicy(ctx, workspaceID)
+		if err != nil {
+			return PurchasePolicy{}, err
+		}
+		if ok {
+			policy = p
+		}
+	}
+	if !allowed(policy.AllowedCountries, countryISO2) || !allowed(policy.AllowedNumberTypes, numberType) {
+		return PurchasePolicy{}, ErrPurchaseNotAllowed
+	}
+	return policy, nil
+}
+
+func allowed(list []string, v string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, x := range list {
+		if strings.EqualFold(x, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextVersion checks want (0 = any) against the stored version and returns the
+// version to write.
+func nextVersion(stored, want int64) (int64, error) {
+	if want != 0 && want != stored {
+		return 0, ErrVersionConflict
+	}
+	return stored + 1, nil
+}
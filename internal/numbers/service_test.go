@@ -0,0 +1,294 @@
+package numbers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"telecom-platform/internal/telephony"
+)
+
+type stubBundles struct{ approved bool }
+
+func (s stubBundles) IsBundleApproved(ctx context.Context, workspaceID, bundleID, countryISO2, numberType string) (bool, error) {
+	return s.approved, nil
+}
+
+func TestBuyNumber_EnforcesPolicy(t *testing.T) {
+	repo := NewMemoryRepo()
+	repo.Policies["w"] = PurchasePolicy{WorkspaceID: "w", AllowedCountries: []string{"US"}, AllowedNumberTypes: []string{"local"}}
+	svc := NewService(&telephony.SIPProvider{}, repo, repo, nil)
+
+	if _, err := svc.BuyNumber(context.Background(), "w", PurchaseRequest{CountryISO2: "gb", NumberType: "local"}); err != ErrPurchaseNotAllowed {
+		t.Fatalf("expected ErrPurchaseNotAllowed, got %v", err)
+	}
+	if _, err := svc.BuyNumber(context.Background(), "w", PurchaseRequest{CountryISO2: "US", NumberType: "toll_free"}); err != ErrPurchaseNotAllowed {
+		t.Fatalf("expected ErrPurchaseNotAllowed, got %v", err)
+	}
+	if _, err := svc.BuyNumber(context.Background(), "w", PurchaseRequest{CountryISO2: "us", NumberType: "local"}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestBuyNumber_RegulatedCountryRequiresApprovedBundle(t *testing.T) {
+	repo := NewMemoryRepo()
+	repo.Requirements["DE|local"] = RegulatoryRequirement{CountryISO2: "DE", NumberType: "local", RequiredDocuments: []string{"proof_of_address"}}
+
+	svc := NewService(&telephony.SIPProvider{}, repo, repo, stubBundles{approved: false})
+	if _, err := svc.BuyNumber(context.Background(), "w", PurchaseRequest{CountryISO2: "DE", NumberType: "local"}); err != ErrRegulatoryBundleRequired {
+		t.Fatalf("expected ErrRegulatoryBundleRequired, got %v", err)
+	}
+	if _, err := svc.BuyNumber(context.Background(), "w", PurchaseRequest{CountryISO2: "DE", NumberType: "local", RegulatoryBundleID: "b1"}); err != ErrRegulatoryBundleRequired {
+		t.Fatalf("expected ErrRegulatoryBundleRequired for unapproved bundle, got %v", err)
+	}
+
+	svc = NewService(&telephony.SIPProvider{}, repo, repo, stubBundles{approved: true})
+	if _, err := svc.BuyNumber(context.Background(), "w", PurchaseRequest{CountryISO2: "DE", NumberType: "local", RegulatoryBundleID: "b1"}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+type stubSearcher struct {
+	telephony.SIPProvider
+	calls int
+	pages []telephony.SearchNumbersResult
+}
+
+func (s *stubSearcher) SearchNumbers(ctx context.Context, req telephony.SearchNumbersRequest) (telephony.SearchNumbersResult, error) {
+	s.calls++
+	i := 0
+	if req.PageToken != "" {
+		i = int(req.PageToken[0] - '0')
+	}
+	return s.pages[i], nil
+}
+
+func TestSearchNumbers_NormalizesAndPagesFromCache(t *testing.T) {
+	prov := &stubSearcher{pages: []telephony.SearchNumbersResult{
+		{
+			Numbers: []telephony.AvailableNumber{
+				{Number: "14155550001", Capabilities: []string{"Voice", "SMS"}},
+				{Number: "+14155550002", Capabilities: []string{"voice"}},
+				{Number: "+14155550003", Capabilities: []string{"sms", "voice"}},
+			},
+			NextPageToken: "1",
+		},
+		{Numbers: []telephony.AvailableNumber{{Number: "+14155550004", Capabilities: []string{"voice", "sms"}}}},
+	}}
+	svc := NewService(prov, NewMemoryRepo(), nil, nil)
+	ctx := context.Background()
+	q := SearchQuery{CountryISO2: "us", NumberType: "local", AreaCode: "415", Capabilities: []string{"SMS"}, PageSize: 2}
+
+	p1, err := svc.SearchNumbers(ctx, "w", q)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(p1.Numbers) != 2 || p1.Numbers[0].Number != "+14155550001" || p1.Numbers[0].CountryISO2 != "US" || p1.NextPageToken == "" {
+		t.Fatalf("unexpected first page: %+v", p1)
+	}
+	if prov.calls != 1 {
+		t.Fatalf("expected one provider call, got %d", prov.calls)
+	}
+
+	q.PageToken = p1.NextPageToken
+	p2, err := svc.SearchNumbers(ctx, "w", q)
+	if err != nil {
+		t.Fatalf("page 2: %v", err)
+	}
+	if len(p2.Numbers) != 1 || p2.Numbers[0].Number != "+14155550004" || p2.NextPageToken != "" {
+		t.Fatalf("unexpected second page: %+v", p2)
+	}
+	if prov.calls != 2 {
+		t.Fatalf("expected provider continuation call, got %d", prov.calls)
+	}
+
+	// Replaying page 1 is served from cache.
+	q.PageToken = ""
+	if _, err := svc.SearchNumbers(ctx, "w", q); err != nil || prov.calls != 2 {
+		t.Fatalf("expected cached first page, err=%v calls=%d", err, prov.calls)
+	}
+
+	// Tokens are bound to their query.
+	q.PageToken = p1.NextPageToken
+	q.AreaCode = "628"
+	if _, err := svc.SearchNumbers(ctx, "w", q); err != ErrInvalidPageToken {
+		t.Fatalf("expected ErrInvalidPageToken, got %v", err)
+	}
+}
+
+func TestSearchNumbers_EnforcesPolicy(t *testing.T) {
+	repo := NewMemoryRepo()
+	repo.Policies["w"] = PurchasePolicy{WorkspaceID: "w", AllowedCountries: []string{"US"}}
+	svc := NewService(&stubSearcher{}, repo, nil, nil)
+	if _, err := svc.SearchNumbers(context.Background(), "w", SearchQuery{CountryISO2: "GB", NumberType: "local"}); err != ErrPurchaseNotAllowed {
+		t.Fatalf("expected ErrPurchaseNotAllowed, got %v", err)
+	}
+
+	svc = NewService(&telephony.SIPProvider{}, repo, nil, nil)
+	if _, err := svc.SearchNumbers(context.Background(), "w", SearchQuery{CountryISO2: "US", NumberType: "local"}); err != ErrSearchUnsupported {
+		t.Fatalf("expected ErrSearchUnsupported, got %v", err)
+	}
+}
+
+type stubBuyer struct {
+	telephony.SIPProvider
+	caps []string
+}
+
+func (s *stubBuyer) BuyNumber(ctx context.Context, req telephony.BuyNumberRequest) (telephony.BuyNumberResult, error) {
+	return telephony.BuyNumberResult{WorkspaceID: req.WorkspaceID, Number: "+14155550100", ProviderNumberID: "PN1", Capabilities: s.caps}, nil
+}
+
+func TestAssignNumber_ChecksCapabilities(t *testing.T) {
+	repo := NewMemoryRepo()
+	svc := NewService(&stubBuyer{caps: []string{"Voice"}}, repo, repo, nil)
+	svc.Inventory = repo
+	ctx := context.Background()
+
+	res, err := svc.BuyNumber(ctx, "w", PurchaseRequest{CountryISO2: "US", NumberType: "local", Capabilities: []string{"voice"}})
+	if err != nil {
+		t.Fatalf("buy: %v", err)
+	}
+	if len(res.Capabilities) != 1 || res.Capabilities[0] != CapabilityVoice {
+		t.Fatalf("expected normalized provider capabilities, got %v", res.Capabilities)
+	}
+
+	if _, err := svc.AssignNumber(ctx, "w", "+14155550100", NumberAssignment{MessagingProfileID: "mp1"}); err != ErrCapabilityMismatch {
+		t.Fatalf("expected ErrCapabilityMismatch for sms on voice-only number, got %v", err)
+	}
+	n, err := svc.AssignNumber(ctx, "w", "+14155550100", NumberAssignment{CampaignID: "c1"})
+	if err != nil || n.CampaignID != "c1" {
+		t.Fatalf("expected campaign assignment, got %+v err=%v", n, err)
+	}
+	if _, err := svc.AssignNumber(ctx, "other", "+14155550100", NumberAssignment{CampaignID: "c1"}); err != ErrNumberNotFound {
+		t.Fatalf("expected ErrNumberNotFound across workspaces, got %v", err)
+	}
+	if _, err := svc.BuyNumber(ctx, "w", PurchaseRequest{CountryISO2: "US", NumberType: "local", Capabilities: []string{"telex"}}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument for unknown capability, got %v", err)
+	}
+}
+
+func TestOptimisticConcurrency_RejectsStaleWrites(t *testing.T) {
+	repo := NewMemoryRepo()
+	svc := NewService(&stubBuyer{caps: []string{"voice"}}, repo, repo, nil)
+	svc.Inventory = repo
+	ctx := context.Background()
+
+	p1, err := svc.SetPolicy(ctx, PurchasePolicy{WorkspaceID: "w", AllowedCountries: []string{"us"}})
+	if err != nil || p1.Version != 1 {
+		t.Fatalf("expected version 1, got %+v err=%v", p1, err)
+	}
+	if _, err := svc.SetPolicy(ctx, PurchasePolicy{WorkspaceID: "w", AllowedCountries: []string{"GB"}, Version: 1}); err != nil {
+		t.Fatalf("update at current version: %v", err)
+	}
+	// A second dashboard still holding version 1.
+	if _, err := svc.SetPolicy(ctx, PurchasePolicy{WorkspaceID: "w", AllowedCountries: []string{"DE"}, Version: 1}); err != ErrVersionConflict {
+		t.Fatalf("expected ErrVersionConflict, got %v", err)
+	}
+	if got := repo.Policies["w"]; got.Version != 2 || got.AllowedCountries[0] != "GB" {
+		t.Fatalf("stale write must not land, got %+v", got)
+	}
+	repo.Policies["w"] = PurchasePolicy{WorkspaceID: "w", Version: 2}
+
+	if _, err := svc.BuyNumber(ctx, "w", PurchaseRequest{CountryISO2: "US", NumberType: "local"}); err != nil {
+		t.Fatalf("buy: %v", err)
+	}
+	n, err := svc.AssignNumber(ctx, "w", "+14155550100", NumberAssignment{CampaignID: "c1", IfVersion: 1})
+	if err != nil || n.Version != 2 {
+		t.Fatalf("expected version 2, got %+v err=%v", n, err)
+	}
+	if _, err := svc.AssignNumber(ctx, "w", "+14155550100", NumberAssignment{CampaignID: "c2", IfVersion: 1}); err != ErrVersionConflict {
+		t.Fatalf("expected ErrVersionConflict, got %v", err)
+	}
+	if n, err := svc.AssignNumber(ctx, "w", "+14155550100", NumberAssignment{CampaignID: "c2"}); err != nil || n.Version != 3 {
+		t.Fatalf("unconditional write should still succeed, got %+v err=%v", n, err)
+	}
+}
+
+type stubConfigurer struct {
+	stubBuyer
+	got      []telephony.ConfigureNumberRequest
+	failures int
+	current  map[string]telephony.ConfigureNumberRequest
+}
+
+func (s *stubConfigurer) Name() string { return "twilio" }
+
+func (s *stubConfigurer) ConfigureNumber(ctx context.Context, req telephony.ConfigureNumberRequest) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("provider unavailable")
+	}
+	s.got = append(s.got, req)
+	if s.current == nil {
+		s.current = map[string]telephony.ConfigureNumberRequest{}
+	}
+	s.current[req.Number] = req
+	return nil
+}
+
+func (s *stubConfigurer) GetNumberConfig(ctx context.Context, workspaceID, number, providerNumberID string) (telephony.ConfigureNumberRequest, error) {
+	return s.current[number], nil
+}
+
+type stubWorkspaces []string
+
+func (s stubWorkspaces) ListWorkspaceIDs(ctx context.Context) ([]string, error) { return s, nil }
+
+func TestBuyNumber_ConfiguresWebhooks(t *testing.T) {
+	repo := NewMemoryRepo()
+	prov := &stubConfigurer{}
+	svc := NewService(prov, repo, repo, nil)
+	svc.Inventory = repo
+	ctx := context.Background()
+
+	if _, err := svc.BuyNumber(ctx, "w", PurchaseRequest{CountryISO2: "US", NumberType: "local"}); err != nil {
+		t.Fatalf("buy: %v", err)
+	}
+	if len(prov.got) != 0 || repo.Numbers["w|+14155550100"].WebhooksConfiguredAt != nil {
+		t.Fatalf("webhooks must not be configured without a base url")
+	}
+
+	svc.Webhooks, _ = telephony.NewWebhookURLs("https://api.staging.example.com/")
+	n, err := svc.ConfigureWebhooks(ctx, "w", "+14155550100")
+	if err != nil || n.WebhooksConfiguredAt == nil {
+		t.Fatalf("configure: %+v err=%v", n, err)
+	}
+	if len(prov.got) != 1 || prov.got[0].VoiceURL != "https://api.staging.example.com/webhooks/twilio/voice" ||
+		prov.got[0].StatusCallbackURL != "https://api.staging.example.com/webhooks/twilio/status" ||
+		prov.got[0].ProviderNumberID != "PN1" {
+		t.Fatalf("unexpected configure request: %+v", prov.got)
+	}
+}
+
+func TestImportNumber_RetriesAndDriftJobRepairs(t *testing.T) {
+	repo := NewMemoryRepo()
+	prov := &stubConfigurer{failures: 1}
+	svc := NewService(prov, repo, repo, nil)
+	svc.Inventory = repo
+	svc.WebhookRetryDelay = 0
+	svc.Webhooks, _ = telephony.NewWebhookURLs("https://api.example.com")
+	ctx := context.Background()
+
+	n, err := svc.ImportNumber(ctx, "w", ImportNumberRequest{Number: "+14155550111", CountryISO2: "us", NumberType: "local", Capabilities: []string{"voice", "sms"}})
+	if err != nil || n.WebhooksConfiguredAt == nil {
+		t.Fatalf("expected import to configure after a retry, got %+v err=%v", n, err)
+	}
+	if prov.got[0].SMSURL != "https://api.example.com/webhooks/twilio/sms" {
+		t.Fatalf("expected sms webhook for messaging-capable number, got %+v", prov.got[0])
+	}
+
+	// Someone repoints the number at another environment in the provider console.
+	prov.current["+14155550111"] = telephony.ConfigureNumberRequest{VoiceURL: "https://api.staging.example.com/webhooks/twilio/voice"}
+	job := WebhookDriftJob{Numbers: svc, Workspaces: stubWorkspaces{"w"}}
+	rep, err := job.RunOnce(ctx)
+	if err != nil || rep.Checked != 1 || rep.Repaired != 1 {
+		t.Fatalf("expected one repair, got %+v err=%v", rep, err)
+	}
+	if !prov.current["+14155550111"].SameWebhooks(svc.expectedWebhooks(repo.Numbers["w|+14155550111"])) {
+		t.Fatalf("number not repointed: %+v", prov.current["+14155550111"])
+	}
+	if rep, _ := job.RunOnce(ctx); rep.Repaired != 0 {
+		t.Fatalf("expected no drift on second pass, got %+v", rep)
+	}
+}
@@ -0,0 +1,98 @@
+package numbers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"telecom-platform/internal/telephony"
+	"telecom-platform/pkg/logger"
+)
+
+// WorkspaceLister returns the workspaces whose numbers should be checked.
+type WorkspaceLister interface {
+	ListWorkspaceIDs(ctx context.Context) ([]string, error)
+}
+
+// WebhookDriftReport summarizes one drift-detection pass.
+type WebhookDriftReport struct {
+	Checked  int `json:"checked"`
+	Repaired int `json:"repaired"`
+	Failed   int `json:"failed"`
+}
+
+// WebhookDriftJob finds numbers whose provider callbacks do not point at this
+// environment (never configured, changed in the provider console, or set up by
+// another environment) and reconfigures them.
+//
+// Providers implementing telephony.NumberConfigReader are compared URL by URL;
+// otherwise only numbers that were never configured are repaired.
+type WebhookDriftJob struct {
+	Numbers    *Service
+	Workspaces WorkspaceLister
+}
+
+// RunOnce checks every owned number once.
+func (j WebhookDriftJob) RunOnce(ctx context.Context) (WebhookDriftReport, error) {
+	var rep WebhookDriftReport
+	s := j.Numbers
+	if s == nil || s.Inventory == nil || j.Workspaces == nil {
+		return rep, errors.New("numbers: webhook drift job not configured")
+	}
+	if _, ok := s.provider.(telephony.NumberConfigurer); !ok || !s.Webhooks.Configured() {
+		return rep, ErrWebhooksUnsupported
+	}
+	reader, canRead := s.provider.(telephony.NumberConfigReader)
+	log := logger.From(ctx)
+
+	ids, err := j.Workspaces.ListWorkspaceIDs(ctx)
+	if err != nil {
+		return rep, err
+	}
+	for _, workspaceID := range ids {
+		owned, err := s.Inventory.ListNumbers(ctx, workspaceID)
+		if err != nil {
+			return rep, err
+		}
+		for _, n := range owned {
+			rep.Checked++
+			drifted := n.WebhooksConfiguredAt == nil
+			if !drifted && canRead {
+				got, err := reader.GetNumberConfig(ctx, n.WorkspaceID, n.Number, n.ProviderNumberID)
+				if err != nil {
+					rep.Failed++
+					log.Error("number webhook read failed", "workspace_id", n.WorkspaceID, "number", n.Number, "err", err)
+					continue
+				}
+				drifted = !got.SameWebhooks(s.expectedWebhooks(n))
+			}
+			if !drifted {
+				continue
+			}
+			if _, err := s.ConfigureWebhooks(ctx, n.WorkspaceID, n.Number); err != nil {
+				rep.Failed++
+				log.Error("number webhook repair failed", "workspace_id", n.WorkspaceID, "number", n.Number, "err", err)
+				continue
+			}
+			rep.Repaired++
+			log.Info("number webhooks repaired", "workspace_id", n.WorkspaceID, "number", n.Number)
+		}
+	}
+	return rep, nil
+}
+
+// Run checks on every tick until ctx is canceled.
+func (j WebhookDriftJob) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := j.RunOnce(ctx); err != nil {
+				logger.From(ctx).Error("number webhook drift check failed", "err", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package numbers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/telephony"
+)
+
+var ErrWebhooksUnsupported = errors.New("numbers: provider or environment cannot configure webhooks")
+
+// ConfigureWebhooks (re)points an owned number's provider callbacks at this
+// environment, e.g. after a failed purchase-time setup or a base URL change.
+func (s *Service) ConfigureWebhooks(ctx context.Context, workspaceID, number string) (OwnedNumber, error) {
+	number = strings.TrimSpace(number)
+	if workspaceID == "" || number == "" {
+		return OwnedNumber{}, ErrInvalidArgument
+	}
+	if s.Inventory == nil {
+		return OwnedNumber{}, errors.New("numbers: inventory not configured")
+	}
+	n, ok, err := s.Inventory.GetNumber(ctx, workspaceID, number)
+	if err != nil {
+		return OwnedNumber{}, err
+	}
+	if !ok {
+		return OwnedNumber{}, ErrNumberNotFound
+	}
+	at, err := s.configureWebhooks(ctx, workspaceID, n.Number, n.ProviderNumberID, messagingCapable(n.Capabilities))
+	if err != nil {
+		return OwnedNumber{}, err
+	}
+	n.WebhooksConfiguredAt = at
+	n.UpdatedAt = s.clock().UTC()
+	n.Version++
+	if err := s.Inventory.PutNumber(ctx, n); err != nil {
+		return OwnedNumber{}, err
+	}
+	return n, nil
+}
+
+// configureWebhooks returns when the provider accepted the configuration, retrying
+// transient failures.
+func (s *Service) configureWebhooks(ctx context.Context, workspaceID, number, providerNumberID string, messaging bool) (*time.Time, error) {
+	cfg, ok := s.provider.(telephony.NumberConfigurer)
+	if !ok || !s.Webhooks.Configured() || number == "" {
+		return nil, ErrWebhooksUnsupported
+	}
+	req := s.Webhooks.ConfigureRequest(s.provider.Name(), workspaceID, number, providerNumberID, messaging)
+	attempts := s.WebhookAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 && s.WebhookRetryDelay > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(s.WebhookRetryDelay):
+			}
+		}
+		if err = cfg.ConfigureNumber(ctx, req); err == nil {
+			now := s.clock().UTC()
+			return &now, nil
+		}
+	}
+	return nil, err
+}
+
+// expectedWebhooks is the configuration n should have in this environment.
+func (s *Service) expectedWebhooks(n OwnedNumber) telephony.ConfigureNumberRequest {
+	return s.Webhooks.ConfigureRequest(s.provider.Name(), n.WorkspaceID, n.Number, n.ProviderNumberID, messagingCapable(n.Capabilities))
+}
+
+func messagingCapable(caps []string) bool {
+	return hasAll(caps, []string{CapabilitySMS}) || hasAll(caps, []string{CapabilityMMS})
+}
+
+// ImportNumberRequest brings a number already owned at the provider into the inventory.
+type ImportNumberRequest struct {
+	Number           string   `json:"number"`
+	ProviderNumberID string   `json:"provider_number_id,omitempty"`
+	CountryISO2      string   `json:"country_iso2"`
+	NumberType       string   `json:"number_type"`
+	Capabilities     []string `json:"capabilities"`
+}
+
+// ImportNumber records a provider number bought outside the platform and configures
+// its webhooks. Re-importing an owned number refreshes its details and keeps assignments.
+func (s *Service) ImportNumber(ctx context.Context, workspaceID string, req ImportNumberRequest) (OwnedNumber, error) {
+	req.Number = strings.TrimSpace(req.Number)
+	req.CountryISO2 = strings.ToUpper(strings.TrimSpace(req.CountryISO2))
+	req.NumberType = strings.TrimSpace(req.NumberType)
+	if workspaceID == "" || len(req.Number) < 8 || req.Number[0] != '+' || !allDigits(req.Number[1:]) ||
+		req.CountryISO2 == "" || req.NumberType == "" {
+		return OwnedNumber{}, ErrInvalidArgument
+	}
+	caps, err := normalizeCapabilities(req.Capabilities)
+	if err != nil {
+		return OwnedNumber{}, err
+	}
+	if s.Inventory == nil {
+		return OwnedNumber{}, errors.New("numbers: inventory not configured")
+	}
+	n, ok, err := s.Inventory.GetNumber(ctx, workspaceID, req.Number)
+	if err != nil {
+		return OwnedNumber{}, err
+	}
+	now := s.clock().UTC()
+	if !ok {
+		n = OwnedNumber{WorkspaceID: workspaceID, Number: req.Number, PurchasedAt: now}
+	}
+	n.ProviderNumberID = req.ProviderNumberID
+	n.CountryISO2 = req.CountryISO2
+	n.NumberType = req.NumberType
+	n.Capabilities = caps
+	n.UpdatedAt = now
+	n.Version++
+	// Best-effort, as for purchases; the drift job retries.
+	if at, err := s.configureWebhooks(ctx, workspaceID, n.Number, n.ProviderNumberID, messagingCapable(caps)); err == nil {
+		n.WebhooksConfiguredAt = at
+	}
+	if err := s.Inventory.PutNumber(ctx, n); err != nil {
+		return OwnedNumber{}, err
+	}
+	return n, nil
+}
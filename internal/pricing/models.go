@@ -61,6 +61,19 @@ type MinutePricing struct {
 	// MinimumBillableSeconds enforces a minimum charge duration.
 	MinimumBillableSeconds int `json:"minimum_billable_seconds" db:"minimum_billable_seconds"`
 
+	// Bands optionally override RatePerMinuteMinor by time of day / day of week
+	// (e.g. off-peak evenings and weekends). The first matching band wins; calls
+	// outside every band use RatePerMinuteMinor. Store as JSONB in Postgres.
+	Bands []RateBand `json:"bands,omitempty" db:"bands"`
+
+	// BandTimezone is the IANA timezone band windows are expressed in (default UTC).
+	BandTimezone string `json:"band_timezone,omitempty" db:"band_timezone"`
+
+	// Tiers optionally price by monthly volume (per workspace + destination), e.g.
+	// first 1000 minutes at rate A, then rate B. When set they replace the flat/band
+	// rate. Must be ascending by UpToMinutes with the last tier open-ended (0).
+	Tiers []VolumeTier `json:"tiers,omitempty" db:"tiers"`
+
 	EffectiveFrom time.Time  `json:"effective_from" db:"effective_from"`
 	EffectiveTo   *time.Time `json:"effective_to,omitempty" db:"effective_to"`
 
@@ -70,6 +83,79 @@ type MinutePricing struct {
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// RateBand is a recurring time window with its own per-minute rate.
+type RateBand struct {
+	// Name is recorded on quotes/costs (e.g. "peak", "off_peak", "weekend").
+	Name string `json:"name"`
+
+	// Days limits the band to these weekdays; empty means every day.
+	Days []time.Weekday `json:"days,omitempty"`
+
+	// StartMinute/EndMinute are minutes after local midnight, [start, end).
+	// A window with End <= Start wraps past midnight (e.g. 18:00-08:00).
+	// Both zero means the whole day.
+	StartMinute int `json:"start_minute"`
+	EndMinute   int `json:"end_minute"`
+
+	RatePerMinuteMinor int64 `json:"rate_per_minute_minor"`
+}
+
+// matches reports whether local time lt falls inside the band.
+// For wrapping windows, the day check applies to the day the window started.
+func (b RateBand) matches(lt time.Time) bool {
+	minute := lt.Hour()*60 + lt.Minute()
+	day := lt.Weekday()
+	switch {
+	case b.StartMinute == 0 && b.EndMinute == 0:
+	case b.StartMinute < b.EndMinute:
+		if minute < b.StartMinute || minute >= b.EndMinute {
+			return false
+		}
+	default:
+		if minute < b.StartMinute && minute >= b.EndMinute {
+			return false
+		}
+		if minute < b.EndMinute {
+			day = (day + 6) % 7
+		}
+	}
+	if len(b.Days) == 0 {
+		return true
+	}
+	for _, d := range b.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// RateAt returns the per-minute rate and band name (empty for the base rate) for a call starting at t.
+func (p MinutePricing) RateAt(t time.Time) (int64, string) {
+	if len(p.Bands) == 0 {
+		return p.RatePerMinuteMinor, ""
+	}
+	loc, err := time.LoadLocation(p.BandTimezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	lt := t.In(loc)
+	for _, b := range p.Bands {
+		if b.matches(lt) {
+			return b.RatePerMinuteMinor, b.Name
+		}
+	}
+	return p.RatePerMinuteMinor, ""
+}
+
+// VolumeTier prices minutes up to a cumulative monthly boundary.
+type VolumeTier struct {
+	// UpToMinutes is the cumulative monthly minute count where the tier ends; 0 = no limit.
+	UpToMinutes int `json:"up_to_minutes"`
+
+	RatePerMinuteMinor int64 `json:"rate_per_minute_minor"`
+}
+
 // RecordingPricing defines charges for call recordings (storage and/or processing).
 type RecordingPricing struct {
 	ID          string `json:"id" db:"id"`
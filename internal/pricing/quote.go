@@ -0,0 +1,173 @@
+package pricing
+
+import (
+	"context"
+	"time"
+
+	"telecom-platform/internal/calls"
+)
+
+// Rate quote locking.
+//
+// The effective rate is resolved once, when the call connects, and stored on the call.
+// Completion is billed against that locked quote, so an effective-dated rate change
+// during a long call never changes what the caller was quoted.
+
+// RateQuote is a resolved per-minute rate frozen at a point in time.
+type RateQuote struct {
+	RateID      string `json:"rate_id"`
+	WorkspaceID string `json:"workspace_id"`
+
+	Currency                string `json:"currency"`
+	RatePerMinuteMinor      int64  `json:"rate_per_minute_minor"`
+	BillingIncrementSeconds int    `json:"billing_increment_seconds"`
+	MinimumBillableSeconds  int    `json:"minimum_billable_seconds"`
+
+	// Band is the time-of-day band that applied at quote time (empty for the base rate).
+	Band string `json:"band,omitempty"`
+
+	QuotedAt time.Time `json:"quoted_at"`
+}
+
+// QuoteCallRate resolves the rate effective at `at` (service clock if zero).
+func (s *Service) QuoteCallRate(ctx context.Context, workspaceID string, direction CallDirection, destination string, at time.Time) (RateQuote, error) {
+	if workspaceID == "" || destination == "" {
+		return RateQuote{}, ErrInvalidPricingReq
+	}
+	if direction != CallDirectionInbound && direction != CallDirectionOutbound {
+		return RateQuote{}, ErrInvalidPricingReq
+	}
+	if at.IsZero() {
+		at = s.clock().UTC()
+	}
+	mp, ok, err := s.repo.FindMinutePricing(ctx, workspaceID, direction, destination, at)
+	if err != nil {
+		return RateQuote{}, err
+	}
+	if !ok {
+		return RateQuote{}, ErrPricingNotFound
+	}
+	return quoteFromPricing(mp, at), nil
+}
+
+// LockCallQuote resolves the current rate and records it on the call (at connect time).
+// A call that already carries a quote is left unchanged.
+func (s *Service) LockCallQuote(ctx context.Context, c *calls.Call, direction CallDirection, destination string) (RateQuote, error) {
+	if c == nil {
+		return RateQuote{}, ErrInvalidPricingReq
+	}
+	if q, ok := LockedQuote(*c); ok {
+		return q, nil
+	}
+	q, err := s.QuoteCallRate(ctx, c.WorkspaceID, direction, destination, time.Time{})
+	if err != nil {
+		return RateQuote{}, err
+	}
+	lockedAt := q.QuotedAt
+	c.RateID = q.RateID
+	c.RatePerMinuteMinor = q.RatePerMinuteMinor
+	c.RateCurrency = q.Currency
+	c.RateBillingIncrementSeconds = q.BillingIncrementSeconds
+	c.RateMinimumBillableSeconds = q.MinimumBillableSeconds
+	c.RateBand = q.Band
+	c.RateLockedAt = &lockedAt
+	return q, nil
+}
+
+// LockedQuote returns the quote stored on the call, if any.
+func LockedQuote(c calls.Call) (RateQuote, bool) {
+	if c.RateLockedAt == nil || c.RateCurrency == "" {
+		return RateQuote{}, false
+	}
+	return RateQuote{
+		RateID:                  c.RateID,
+		WorkspaceID:             c.WorkspaceID,
+		Currency:                c.RateCurrency,
+		RatePerMinuteMinor:      c.RatePerMinuteMinor,
+		BillingIncrementSeconds: c.RateBillingIncrementSeconds,
+		MinimumBillableSeconds:  c.RateMinimumBillableSeconds,
+		Band:                    c.RateBand,
+		QuotedAt:                *c.RateLockedAt,
+	}, true
+}
+
+// CompletedCallCost bills a finished call. It uses the locked quote when present and
+// otherwise falls back to the rate effective when the call was created. Volume tiers
+// of the rate are applied, and the call's minutes are recorded (RecordUsage) so the
+// result is the amount to charge; calling it again for the same call returns the
+// same cost without counting the minutes twice.
+func (s *Service) CompletedCallCost(ctx context.Context, c calls.Call, direction CallDirection, destination string) (CallCost, error) {
+	if c.WorkspaceID == "" || c.DurationSeconds <= 0 {
+		return CallCost{}, ErrInvalidPricingReq
+	}
+	var cost CallCost
+	at := c.CreatedAt
+	if q, ok := LockedQuote(c); ok {
+		at = q.QuotedAt
+		cost = costFromQuote(q, direction, destination, c.DurationSeconds)
+		// The quote does not carry tiers; read them from the rate row it locked.
+		mp, found, err := s.repo.FindMinutePricing(ctx, c.WorkspaceID, direction, destination, at)
+		if err != nil {
+			return CallCost{}, err
+		}
+		if found && mp.ID == q.RateID {
+			if err := s.estimateTiers(ctx, &cost, mp.Tiers, at); err != nil {
+				return CallCost{}, err
+			}
+		}
+	} else {
+		var err error
+		cost, err = s.CalculateCallCost(ctx, CallCostRequest{
+			WorkspaceID:     c.WorkspaceID,
+			Direction:       direction,
+			Destination:     destination,
+			DurationSeconds: c.DurationSeconds,
+			At:              at,
+		})
+		if err != nil {
+			return CallCost{}, err
+		}
+	}
+	return s.RecordUsage(ctx, cost, c.CallID, at)
+}
+
+// CostMinor prices durationSeconds at the quoted rate (increments and minimums applied).
+func (q RateQuote) CostMinor(durationSeconds int) int64 {
+	if durationSeconds <= 0 {
+		return 0
+	}
+	return costFromQuote(q, "", "", durationSeconds).TotalMinor
+}
+
+// quoteFromPricing resolves the band for a call starting at `at`; the whole call is
+// billed at that band, matching how carriers rate by call start time.
+func quoteFromPricing(mp MinutePricing, at time.Time) RateQuote {
+	rate, band := mp.RateAt(at)
+	return RateQuote{
+		RateID:                  mp.ID,
+		WorkspaceID:             mp.WorkspaceID,
+		Currency:                mp.Currency,
+		RatePerMinuteMinor:      rate,
+		Band:                    band,
+		BillingIncrementSeconds: mp.BillingIncrementSeconds,
+		MinimumBillableSeconds:  mp.MinimumBillableSeconds,
+		QuotedAt:                at,
+	}
+}
+
+func costFromQuote(q RateQuote, direction CallDirection, destination string, durationSeconds int) CallCost {
+	billableSec := billableSeconds(durationSeconds, q.MinimumBillableSeconds, q.BillingIncrementSeconds)
+	billableMin := billableMinutesFromSeconds(billableSec)
+	return CallCost{
+		WorkspaceID:        q.WorkspaceID,
+		Direction:          direction,
+		Destination:        destination,
+		Currency:           q.Currency,
+		BillableSeconds:    billableSec,
+		BillableMinutes:    billableMin,
+		RateID:             q.RateID,
+		Band:               q.Band,
+		RatePerMinuteMinor: q.RatePerMinuteMinor,
+		TotalMinor:         q.RatePerMinuteMinor * int64(billableMin),
+	}
+}
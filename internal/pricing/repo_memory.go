@@ -2,6 +2,7 @@ package pricing
 
 import (
 	"context"
+	"sync"
 	"time"
 )
 
@@ -48,3 +49,38 @@ func (r *MemoryRepo) FindMinutePricing(ctx context.Context, workspaceID string,
 
 	return best, found, nil
 }
+
+// MemoryUsage is an in-memory UsageCounter keyed by UTC month.
+type MemoryUsage struct {
+	mu      sync.Mutex
+	minutes map[string]int
+	// recorded is the usage each call was added after, by month key and call id.
+	recorded map[string]int
+}
+
+func NewMemoryUsage() *MemoryUsage {
+	return &MemoryUsage{minutes: map[string]int{}, recorded: map[string]int{}}
+}
+
+func usageKey(workspaceID string, direction CallDirection, destination string, at time.Time) string {
+	return workspaceID + "|" + string(direction) + "|" + destination + "|" + at.UTC().Format("2006-01")
+}
+
+func (m *MemoryUsage) MonthlyMinutes(ctx context.Context, workspaceID string, direction CallDirection, destination string, at time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.minutes[usageKey(workspaceID, direction, destination, at)], nil
+}
+
+func (m *MemoryUsage) AddMinutes(ctx context.Context, workspaceID string, direction CallDirection, destination string, at time.Time, callID string, minutes int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k := usageKey(workspaceID, direction, destination, at)
+	if before, ok := m.recorded[k+"|"+callID]; ok {
+		return before, nil
+	}
+	before := m.minutes[k]
+	m.recorded[k+"|"+callID] = before
+	m.minutes[k] = before + minutes
+	return before, nil
+}
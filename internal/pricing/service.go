@@ -17,6 +17,13 @@ import (
 type Service struct {
 	repo RateRepository
 	clock func() time.Time
+
+	// Usage provides monthly minute counters for volume tiers (optional; without it
+	// tiered rows are priced as if no minutes had been used this month).
+	Usage UsageCounter
+
+	// History supplies billed calls for rate-change simulations (optional).
+	History BilledCallSource
 }
 
 func NewService(repo RateRepository) *Service {
@@ -48,8 +55,21 @@ type CallCost struct {
 	BillableSeconds int
 	BillableMinutes int
 
+	// RateID is the MinutePricing row the cost was computed from.
+	RateID string
+	// Band is the time-of-day band applied (empty for the base rate).
+	Band string
+
+	// Tiers is the per-tier split for volume-tiered rates (empty for flat rates).
+	Tiers []TierCharge
+	// UsageBeforeMinutes is the month-to-date usage the tier split started from.
+	UsageBeforeMinutes int
+
 	RatePerMinuteMinor int64
 	TotalMinor         int64
+
+	// volumeTiers are the rate's tiers, kept so RecordUsage can reprice.
+	volumeTiers []VolumeTier
 }
 
 var (
@@ -82,21 +102,32 @@ func (s *Service) CalculateCallCost(ctx context.Context, req CallCostRequest) (C
 		return CallCost{}, ErrPricingNotFound
 	}
 
-	billableSec := billableSeconds(req.DurationSeconds, mp.MinimumBillableSeconds, mp.BillingIncrementSeconds)
-	billableMin := billableMinutesFromSeconds(billableSec)
-
-	total := mp.RatePerMinuteMinor * int64(billableMin)
+	cost := costFromQuote(quoteFromPricing(mp, at), req.Direction, req.Destination, req.DurationSeconds)
+	if err := s.estimateTiers(ctx, &cost, mp.Tiers, at); err != nil {
+		return CallCost{}, err
+	}
+	return cost, nil
+}
 
-	return CallCost{
-		WorkspaceID:        req.WorkspaceID,
-		Direction:          req.Direction,
-		Destination:        req.Destination,
-		Currency:           mp.Currency,
-		BillableSeconds:    billableSec,
-		BillableMinutes:    billableMin,
-		RatePerMinuteMinor: mp.RatePerMinuteMinor,
-		TotalMinor:         total,
-	}, nil
+// estimateTiers prices cost across tiers from the current month-to-date usage.
+// It does not record anything; see RecordUsage.
+func (s *Service) estimateTiers(ctx context.Context, cost *CallCost, tiers []VolumeTier, at time.Time) error {
+	if len(tiers) == 0 {
+		return nil
+	}
+	if err := ValidateTiers(tiers); err != nil {
+		return err
+	}
+	used := 0
+	if s.Usage != nil {
+		var err error
+		used, err = s.Usage.MonthlyMinutes(ctx, cost.WorkspaceID, cost.Direction, cost.Destination, at)
+		if err != nil {
+			return err
+		}
+	}
+	applyTiers(cost, tiers, used)
+	return nil
 }
 
 // RateRepository abstracts pricing persistence.
@@ -1,6 +1,14 @@
 package pricing
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/calls"
+)
 
 func TestBillableSeconds(t *testing.T) {
 	// 60s increment, 0 min
@@ -31,3 +39,223 @@ func TestBillableMinutesFromSeconds(t *testing.T) {
 		t.Fatalf("expected 2, got %d", got)
 	}
 }
+
+func TestCompletedCallCost_UsesLockedQuote(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 23, 50, 0, 0, time.UTC)
+	flip := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	repo := &MemoryRepo{Minute: []MinutePricing{
+		{ID: "old", WorkspaceID: "w", Direction: CallDirectionOutbound, Destination: "US", Currency: "USD", RatePerMinuteMinor: 2, BillingIncrementSeconds: 60, Status: PricingStatusActive, EffectiveFrom: t0.Add(-time.Hour), EffectiveTo: &flip},
+		{ID: "new", WorkspaceID: "w", Direction: CallDirectionOutbound, Destination: "US", Currency: "USD", RatePerMinuteMinor: 5, BillingIncrementSeconds: 60, Status: PricingStatusActive, EffectiveFrom: flip},
+	}}
+	svc := NewService(repo)
+	now := t0
+	svc.clock = func() time.Time { return now }
+
+	c := calls.Call{CallID: "c1", WorkspaceID: "w", CreatedAt: t0}
+	q, err := svc.LockCallQuote(context.Background(), &c, CallDirectionOutbound, "US")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if q.RateID != "old" || c.RateID != "old" || c.RateLockedAt == nil {
+		t.Fatalf("expected old rate locked, got %+v", c)
+	}
+
+	// Pricing flips mid-call; re-locking is a no-op and completion uses the locked rate.
+	now = flip.Add(30 * time.Minute)
+	if q2, _ := svc.LockCallQuote(context.Background(), &c, CallDirectionOutbound, "US"); q2.RateID != "old" {
+		t.Fatalf("expected lock to be sticky, got %s", q2.RateID)
+	}
+	c.DurationSeconds = 40 * 60
+	cost, err := svc.CompletedCallCost(context.Background(), c, CallDirectionOutbound, "US")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if cost.RateID != "old" || cost.TotalMinor != 80 {
+		t.Fatalf("expected 40 min at locked rate 2, got %+v", cost)
+	}
+}
+
+func TestCalculateCallCost_PicksTimeOfDayBand(t *testing.T) {
+	repo := &MemoryRepo{Minute: []MinutePricing{{
+		ID: "r", WorkspaceID: "w", Direction: CallDirectionOutbound, Destination: "US", Currency: "USD",
+		RatePerMinuteMinor: 10, BillingIncrementSeconds: 60, Status: PricingStatusActive,
+		EffectiveFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		BandTimezone:  "America/New_York",
+		Bands: []RateBand{
+			{Name: "weekend", Days: []time.Weekday{time.Saturday, time.Sunday}, RatePerMinuteMinor: 3},
+			{Name: "evening", StartMinute: 18 * 60, EndMinute: 8 * 60, RatePerMinuteMinor: 5},
+		},
+	}}}
+	svc := NewService(repo)
+
+	cases := []struct {
+		at   time.Time
+		band string
+		rate int64
+	}{
+		{time.Date(2024, 3, 5, 15, 0, 0, 0, time.UTC), "", 10},        // Tue 10:00 local
+		{time.Date(2024, 3, 5, 23, 30, 0, 0, time.UTC), "evening", 5}, // Tue 18:30 local
+		{time.Date(2024, 3, 6, 11, 0, 0, 0, time.UTC), "evening", 5},  // Wed 06:00 local (wrapped)
+		{time.Date(2024, 3, 9, 15, 0, 0, 0, time.UTC), "weekend", 3},  // Sat 10:00 local
+	}
+	for _, tc := range cases {
+		cost, err := svc.CalculateCallCost(context.Background(), CallCostRequest{WorkspaceID: "w", Direction: CallDirectionOutbound, Destination: "US", DurationSeconds: 60, At: tc.at})
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		if cost.Band != tc.band || cost.RatePerMinuteMinor != tc.rate {
+			t.Fatalf("at %v: expected %q/%d, got %q/%d", tc.at, tc.band, tc.rate, cost.Band, cost.RatePerMinuteMinor)
+		}
+	}
+}
+
+func TestCalculateCallCost_VolumeTiersSplitAtBoundary(t *testing.T) {
+	at := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	repo := &MemoryRepo{Minute: []MinutePricing{{
+		ID: "r", WorkspaceID: "w", Direction: CallDirectionOutbound, Destination: "US", Currency: "USD",
+		RatePerMinuteMinor: 10, BillingIncrementSeconds: 60, Status: PricingStatusActive,
+		EffectiveFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Tiers:         []VolumeTier{{UpToMinutes: 100, RatePerMinuteMinor: 10}, {RatePerMinuteMinor: 6}},
+	}}}
+	usage := NewMemoryUsage()
+	_, _ = usage.AddMinutes(context.Background(), "w", CallDirectionOutbound, "US", at, "earlier", 95)
+	svc := NewService(repo)
+	svc.Usage = usage
+
+	cost, err := svc.CalculateCallCost(context.Background(), CallCostRequest{WorkspaceID: "w", Direction: CallDirectionOutbound, Destination: "US", DurationSeconds: 10 * 60, At: at})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	// 5 minutes at 10 + 5 minutes at 6.
+	if cost.TotalMinor != 80 || len(cost.Tiers) != 2 || cost.Tiers[0].Minutes != 5 || cost.Tiers[1].Minutes != 5 {
+		t.Fatalf("unexpected tier split: %+v", cost)
+	}
+	if md := cost.BillingMetadata(); !strings.Contains(md, `"usage_before_minutes":95`) || !strings.Contains(md, `"up_to_minutes":100`) {
+		t.Fatalf("expected tier boundary in metadata: %s", md)
+	}
+
+	if _, err := svc.RecordUsage(context.Background(), cost, "c1", at); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	cost, _ = svc.CalculateCallCost(context.Background(), CallCostRequest{WorkspaceID: "w", Direction: CallDirectionOutbound, Destination: "US", DurationSeconds: 60, At: at})
+	if cost.TotalMinor != 6 {
+		t.Fatalf("expected next call fully in second tier, got %+v", cost)
+	}
+	// A new month resets usage.
+	cost, _ = svc.CalculateCallCost(context.Background(), CallCostRequest{WorkspaceID: "w", Direction: CallDirectionOutbound, Destination: "US", DurationSeconds: 60, At: at.AddDate(0, 1, 0)})
+	if cost.TotalMinor != 10 {
+		t.Fatalf("expected first tier in new month, got %+v", cost)
+	}
+}
+
+func TestCompletedCallCost_AppliesTiersAndRecordsUsageOnce(t *testing.T) {
+	at := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	repo := &MemoryRepo{Minute: []MinutePricing{{
+		ID: "r", WorkspaceID: "w", Direction: CallDirectionOutbound, Destination: "US", Currency: "USD",
+		RatePerMinuteMinor: 10, BillingIncrementSeconds: 60, Status: PricingStatusActive,
+		EffectiveFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Tiers:         []VolumeTier{{UpToMinutes: 100, RatePerMinuteMinor: 10}, {RatePerMinuteMinor: 6}},
+	}}}
+	usage := NewMemoryUsage()
+	_, _ = usage.AddMinutes(context.Background(), "w", CallDirectionOutbound, "US", at, "earlier", 95)
+	svc := NewService(repo)
+	svc.Usage = usage
+	svc.clock = func() time.Time { return at }
+
+	c := calls.Call{CallID: "c1", WorkspaceID: "w", CreatedAt: at, DurationSeconds: 10 * 60}
+	if _, err := svc.LockCallQuote(context.Background(), &c, CallDirectionOutbound, "US"); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	cost, err := svc.CompletedCallCost(context.Background(), c, CallDirectionOutbound, "US")
+	if err != nil || cost.TotalMinor != 80 || cost.UsageBeforeMinutes != 95 {
+		t.Fatalf("expected locked call priced across tiers, got %+v err=%v", cost, err)
+	}
+	// A retried charge prices the same and does not count the minutes again.
+	if again, _ := svc.CompletedCallCost(context.Background(), c, CallDirectionOutbound, "US"); again.TotalMinor != 80 {
+		t.Fatalf("expected retry priced identically, got %+v", again)
+	}
+	if used, _ := usage.MonthlyMinutes(context.Background(), "w", CallDirectionOutbound, "US", at); used != 105 {
+		t.Fatalf("expected 105 minutes recorded, got %d", used)
+	}
+	// The next call starts where the first one left off.
+	next, _ := svc.CompletedCallCost(context.Background(), calls.Call{CallID: "c2", WorkspaceID: "w", CreatedAt: at, DurationSeconds: 60}, CallDirectionOutbound, "US")
+	if next.TotalMinor != 6 || next.UsageBeforeMinutes != 105 {
+		t.Fatalf("expected next call in second tier, got %+v", next)
+	}
+}
+
+func TestValidateTiers(t *testing.T) {
+	valid := []VolumeTier{{UpToMinutes: 100, RatePerMinuteMinor: 10}, {UpToMinutes: 500, RatePerMinuteMinor: 8}, {RatePerMinuteMinor: 6}}
+	if err := ValidateTiers(valid); err != nil {
+		t.Fatalf("expected valid tiers, got %v", err)
+	}
+	for name, tiers := range map[string][]VolumeTier{
+		"bounded last":  {{UpToMinutes: 100, RatePerMinuteMinor: 10}, {UpToMinutes: 500, RatePerMinuteMinor: 6}},
+		"descending":    {{UpToMinutes: 500, RatePerMinuteMinor: 10}, {UpToMinutes: 100, RatePerMinuteMinor: 8}, {RatePerMinuteMinor: 6}},
+		"open middle":   {{RatePerMinuteMinor: 10}, {RatePerMinuteMinor: 6}},
+		"negative rate": {{RatePerMinuteMinor: -1}},
+	} {
+		if err := ValidateTiers(tiers); !errors.Is(err, ErrInvalidTiers) {
+			t.Fatalf("%s: expected ErrInvalidTiers, got %v", name, err)
+		}
+	}
+}
+
+type stubHistory []BilledCall
+
+func (h stubHistory) ListBilledCalls(ctx context.Context, workspaceID string, from, to time.Time) ([]BilledCall, error) {
+	return h, nil
+}
+
+func TestSimulate_RepricesOnlyChangedDestinations(t *testing.T) {
+	at := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	repo := &MemoryRepo{Minute: []MinutePricing{
+		{ID: "us", WorkspaceID: "w", Direction: CallDirectionOutbound, Destination: "US", Currency: "USD", RatePerMinuteMinor: 10, BillingIncrementSeconds: 60, Status: PricingStatusActive, EffectiveFrom: at.AddDate(0, -1, 0)},
+	}}
+	svc := NewService(repo)
+	svc.History = stubHistory{
+		{CallID: "c1", Direction: CallDirectionOutbound, Destination: "US", StartedAt: at, DurationSeconds: 90, Currency: "USD", ActualMinor: 20},
+		{CallID: "c2", Direction: CallDirectionOutbound, Destination: "US", StartedAt: at, DurationSeconds: 30, Currency: "USD", ActualMinor: 10},
+		{CallID: "c3", Direction: CallDirectionOutbound, Destination: "IN", StartedAt: at, DurationSeconds: 60, Currency: "USD", ActualMinor: 50},
+	}
+
+	out, err := svc.Simulate(context.Background(), SimulationRequest{
+		WorkspaceID: "w", From: at.Add(-time.Hour), To: at.Add(time.Hour),
+		Changes: []RateChange{{Direction: CallDirectionOutbound, Destination: "US", RatePerMinuteMinor: 15}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	// US: (2 + 1 minutes) * 15 = 45 vs 30 actual; IN unchanged.
+	want := SimulationTotal{Currency: "USD", Calls: 3, ActualMinor: 80, SimulatedMinor: 95, DeltaMinor: 15, DeltaBps: 1875}
+	if out.Calls != 3 || len(out.Totals) != 1 || out.Totals[0] != want {
+		t.Fatalf("unexpected totals: %+v", out)
+	}
+	if len(out.Rows) != 2 || out.Rows[0].Destination != "IN" || out.Rows[0].Changed || !out.Rows[1].Changed {
+		t.Fatalf("unexpected rows: %+v", out.Rows)
+	}
+}
+
+func TestSimulate_TotalsPerCurrency(t *testing.T) {
+	at := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	svc := NewService(&MemoryRepo{})
+	svc.History = stubHistory{
+		{CallID: "c1", Direction: CallDirectionOutbound, Destination: "GB", StartedAt: at, DurationSeconds: 60, Currency: "USD", ActualMinor: 10},
+		{CallID: "c2", Direction: CallDirectionOutbound, Destination: "GB", StartedAt: at, DurationSeconds: 60, Currency: "EUR", ActualMinor: 20},
+	}
+
+	out, err := svc.Simulate(context.Background(), SimulationRequest{
+		WorkspaceID: "w", From: at.Add(-time.Hour), To: at.Add(time.Hour),
+		Changes: []RateChange{{Direction: CallDirectionOutbound, Destination: "GB", RatePerMinuteMinor: 30}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(out.Totals) != 2 || out.Totals[0].Currency != "EUR" || out.Totals[0].ActualMinor != 20 || out.Totals[0].SimulatedMinor != 30 ||
+		out.Totals[1].Currency != "USD" || out.Totals[1].ActualMinor != 10 || out.Totals[1].DeltaMinor != 20 {
+		t.Fatalf("expected separate EUR and USD totals, got %+v", out.Totals)
+	}
+	if len(out.Rows) != 2 || out.Rows[0].Currency != "EUR" || out.Rows[1].Currency != "USD" {
+		t.Fatalf("expected one row per currency, got %+v", out.Rows)
+	}
+}
@@ -0,0 +1,183 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// Pricing simulation.
+//
+// Operators submit a hypothetical rate deck change and a historical period; every billed
+// call in the period is re-priced with the change applied (increments and minimums are
+// kept from the historical row unless overridden) and compared to what was actually charged.
+// Amounts are only summed within a currency: rows and totals are per currency.
+// Nothing is persisted.
+
+// BilledCall is a historical call with its actual charge.
+type BilledCall struct {
+	CallID          string        `json:"call_id"`
+	Direction       CallDirection `json:"direction"`
+	Destination     string        `json:"destination"`
+	StartedAt       time.Time     `json:"started_at"`
+	DurationSeconds int           `json:"duration_seconds"`
+	Currency        string        `json:"currency"`
+	ActualMinor     int64         `json:"actual_minor"`
+}
+
+// BilledCallSource lists billed calls for a period. Implementations must enforce workspace filtering.
+type BilledCallSource interface {
+	ListBilledCalls(ctx context.Context, workspaceID string, from, to time.Time) ([]BilledCall, error)
+}
+
+// RateChange is one hypothetical rate for a direction + destination.
+type RateChange struct {
+	Direction          CallDirection `json:"direction"`
+	Destination        string        `json:"destination"`
+	RatePerMinuteMinor int64         `json:"rate_per_minute_minor"`
+
+	// Optional overrides; zero keeps the historical value.
+	BillingIncrementSeconds int `json:"billing_increment_seconds,omitempty"`
+	MinimumBillableSeconds  int `json:"minimum_billable_seconds,omitempty"`
+}
+
+type SimulationRequest struct {
+	WorkspaceID string       `json:"workspace_id"`
+	From        time.Time    `json:"from"`
+	To          time.Time    `json:"to"`
+	Changes     []RateChange `json:"changes"`
+}
+
+// SimulationRow aggregates one direction + destination.
+type SimulationRow struct {
+	Direction   CallDirection `json:"direction"`
+	Destination string        `json:"destination"`
+	Currency    string        `json:"currency"`
+	Changed     bool          `json:"changed"`
+
+	Calls          int   `json:"calls"`
+	ActualMinor    int64 `json:"actual_minor"`
+	SimulatedMinor int64 `json:"simulated_minor"`
+	DeltaMinor     int64 `json:"delta_minor"`
+}
+
+// SimulationTotal sums the calls billed in one currency.
+type SimulationTotal struct {
+	Currency string `json:"currency"`
+
+	Calls          int   `json:"calls"`
+	ActualMinor    int64 `json:"actual_minor"`
+	SimulatedMinor int64 `json:"simulated_minor"`
+	DeltaMinor     int64 `json:"delta_minor"`
+	// DeltaBps is the revenue change in basis points of actual (0 when actual is 0).
+	DeltaBps int64 `json:"delta_bps"`
+}
+
+type SimulationResult struct {
+	WorkspaceID string    `json:"workspace_id"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+
+	Calls int `json:"calls"`
+	// Totals has one entry per currency, sorted by currency.
+	Totals []SimulationTotal `json:"totals"`
+
+	Rows []SimulationRow `json:"rows"`
+}
+
+// MaxSimulationCalls bounds a single simulation run.
+const MaxSimulationCalls = 500_000
+
+var ErrSimulationTooLarge = errors.New("pricing: simulation period has too many calls")
+
+// Simulate re-prices historical calls under the proposed changes.
+func (s *Service) Simulate(ctx context.Context, req SimulationRequest) (SimulationResult, error) {
+	if req.WorkspaceID == "" || req.From.IsZero() || !req.To.After(req.From) || len(req.Changes) == 0 {
+		return SimulationResult{}, ErrInvalidPricingReq
+	}
+	if s.History == nil {
+		return SimulationResult{}, errors.New("pricing: billed call history not configured")
+	}
+	changes := map[string]RateChange{}
+	for _, ch := range req.Changes {
+		if ch.Destination == "" || ch.RatePerMinuteMinor < 0 || (ch.Direction != CallDirectionInbound && ch.Direction != CallDirectionOutbound) {
+			return SimulationResult{}, ErrInvalidPricingReq
+		}
+		changes[string(ch.Direction)+"|"+ch.Destination] = ch
+	}
+
+	billed, err := s.History.ListBilledCalls(ctx, req.WorkspaceID, req.From, req.To)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+	if len(billed) > MaxSimulationCalls {
+		return SimulationResult{}, ErrSimulationTooLarge
+	}
+
+	out := SimulationResult{WorkspaceID: req.WorkspaceID, From: req.From, To: req.To}
+	rows := map[string]*SimulationRow{}
+	totals := map[string]*SimulationTotal{}
+	for _, bc := range billed {
+		key := string(bc.Direction) + "|" + bc.Destination
+		row, ok := rows[key+"|"+bc.Currency]
+		if !ok {
+			row = &SimulationRow{Direction: bc.Direction, Destination: bc.Destination, Currency: bc.Currency}
+			rows[key+"|"+bc.Currency] = row
+		}
+
+		simulated := bc.ActualMinor
+		if ch, ok := changes[key]; ok {
+			row.Changed = true
+			q := RateQuote{RatePerMinuteMinor: ch.RatePerMinuteMinor, BillingIncrementSeconds: ch.BillingIncrementSeconds, MinimumBillableSeconds: ch.MinimumBillableSeconds}
+			// Keep historical increments/minimums unless the change overrides them.
+			if mp, found, err := s.repo.FindMinutePricing(ctx, req.WorkspaceID, bc.Direction, bc.Destination, bc.StartedAt); err != nil {
+				return SimulationResult{}, err
+			} else if found {
+				if q.BillingIncrementSeconds == 0 {
+					q.BillingIncrementSeconds = mp.BillingIncrementSeconds
+				}
+				if q.MinimumBillableSeconds == 0 {
+					q.MinimumBillableSeconds = mp.MinimumBillableSeconds
+				}
+			}
+			simulated = q.CostMinor(bc.DurationSeconds)
+		}
+
+		row.Calls++
+		row.ActualMinor += bc.ActualMinor
+		row.SimulatedMinor += simulated
+		total, ok := totals[bc.Currency]
+		if !ok {
+			total = &SimulationTotal{Currency: bc.Currency}
+			totals[bc.Currency] = total
+		}
+		total.Calls++
+		total.ActualMinor += bc.ActualMinor
+		total.SimulatedMinor += simulated
+		out.Calls++
+	}
+
+	for _, r := range rows {
+		r.DeltaMinor = r.SimulatedMinor - r.ActualMinor
+		out.Rows = append(out.Rows, *r)
+	}
+	sort.Slice(out.Rows, func(i, j int) bool {
+		if out.Rows[i].Destination != out.Rows[j].Destination {
+			return out.Rows[i].Destination < out.Rows[j].Destination
+		}
+		if out.Rows[i].Direction != out.Rows[j].Direction {
+			return out.Rows[i].Direction < out.Rows[j].Direction
+		}
+		return out.Rows[i].Currency < out.Rows[j].Currency
+	})
+	for _, t := range totals {
+		t.DeltaMinor = t.SimulatedMinor - t.ActualMinor
+		if t.ActualMinor != 0 {
+			t.DeltaBps = t.DeltaMinor * 10_000 / t.ActualMinor
+		}
+		out.Totals = append(out.Totals, *t)
+	}
+	sort.Slice(out.Totals, func(i, j int) bool { return out.Totals[i].Currency < out.Totals[j].Currency })
+	return out, nil
+}
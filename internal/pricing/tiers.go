@@ -0,0 +1,146 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Volume-tiered pricing.
+//
+// Tier boundaries are cumulative per calendar month (UTC) per workspace + direction +
+// destination. CalculateCallCost estimates the split from the month-to-date usage.
+// The charge itself comes from RecordUsage (CompletedCallCost calls it): it advances
+// the counter and prices the tiers from the usage it advanced from in one atomic
+// step, so concurrent calls never both price from the same boundary.
+//
+// Tiers must ascend by UpToMinutes and end with an open-ended tier (UpToMinutes 0);
+// rows that do not are rejected (ValidateTiers) rather than leave minutes unpriced.
+
+var ErrInvalidTiers = errors.New("pricing: invalid volume tiers")
+
+// UsageCounter tracks month-to-date billable minutes.
+type UsageCounter interface {
+	MonthlyMinutes(ctx context.Context, workspaceID string, direction CallDirection, destination string, at time.Time) (int, error)
+	// AddMinutes atomically adds callID's minutes and returns the month-to-date
+	// usage before them. A callID already recorded is not added again and returns
+	// the same usage as the first time, so retried charges price identically.
+	AddMinutes(ctx context.Context, workspaceID string, direction CallDirection, destination string, at time.Time, callID string, minutes int) (int, error)
+}
+
+// TierCharge is the portion of a call priced in one tier.
+type TierCharge struct {
+	Tier               int   `json:"tier"`
+	UpToMinutes        int   `json:"up_to_minutes,omitempty"`
+	Minutes            int   `json:"minutes"`
+	RatePerMinuteMinor int64 `json:"rate_per_minute_minor"`
+	AmountMinor        int64 `json:"amount_minor"`
+}
+
+// ValidateTiers checks that tiers ascend by UpToMinutes, that only the last tier
+// is open-ended, and that no rate is negative.
+func ValidateTiers(tiers []VolumeTier) error {
+	prev := 0
+	for i, t := range tiers {
+		if t.RatePerMinuteMinor < 0 {
+			return fmt.Errorf("%w: tier %d has a negative rate", ErrInvalidTiers, i)
+		}
+		last := i == len(tiers)-1
+		switch {
+		case last && t.UpToMinutes != 0:
+			return fmt.Errorf("%w: last tier must be open-ended", ErrInvalidTiers)
+		case !last && t.UpToMinutes <= prev:
+			return fmt.Errorf("%w: tier %d must end above %d minutes", ErrInvalidTiers, i, prev)
+		}
+		prev = t.UpToMinutes
+	}
+	return nil
+}
+
+// RecordUsage adds the cost's billable minutes for callID to the monthly counter
+// and returns the cost to charge: for tiered rates the split is recomputed from
+// the usage the counter held before this call.
+func (s *Service) RecordUsage(ctx context.Context, cost CallCost, callID string, at time.Time) (CallCost, error) {
+	if s.Usage == nil || cost.BillableMinutes <= 0 {
+		return cost, nil
+	}
+	if callID == "" {
+		return CallCost{}, ErrInvalidPricingReq
+	}
+	if at.IsZero() {
+		at = s.clock().UTC()
+	}
+	used, err := s.Usage.AddMinutes(ctx, cost.WorkspaceID, cost.Direction, cost.Destination, at, callID, cost.BillableMinutes)
+	if err != nil {
+		return CallCost{}, err
+	}
+	if len(cost.volumeTiers) > 0 {
+		applyTiers(&cost, cost.volumeTiers, used)
+	}
+	return cost, nil
+}
+
+// applyTiers prices cost's billable minutes across tiers after `used` minutes.
+func applyTiers(cost *CallCost, tiers []VolumeTier, used int) {
+	cost.volumeTiers = tiers
+	cost.Tiers = splitTiers(tiers, used, cost.BillableMinutes)
+	cost.UsageBeforeMinutes = used
+	cost.TotalMinor = 0
+	for _, t := range cost.Tiers {
+		cost.TotalMinor += t.AmountMinor
+	}
+	// The marginal (last applied) tier rate is reported as the effective rate.
+	if n := len(cost.Tiers); n > 0 {
+		cost.RatePerMinuteMinor = cost.Tiers[n-1].RatePerMinuteMinor
+	}
+}
+
+// BillingMetadata renders the pricing decision (rate, band, tier boundaries) as JSON
+// suitable for wallet ledger metadata.
+func (c CallCost) BillingMetadata() string {
+	m := map[string]any{
+		"rate_id":               c.RateID,
+		"rate_per_minute_minor": c.RatePerMinuteMinor,
+		"billable_minutes":      c.BillableMinutes,
+	}
+	if c.Band != "" {
+		m["band"] = c.Band
+	}
+	if len(c.Tiers) > 0 {
+		m["tiers"] = c.Tiers
+		m["usage_before_minutes"] = c.UsageBeforeMinutes
+	}
+	b, _ := json.Marshal(m)
+	return string(b)
+}
+
+// splitTiers spreads minutes across tiers, starting after `used` month-to-date minutes.
+// tiers must pass ValidateTiers.
+func splitTiers(tiers []VolumeTier, used, minutes int) []TierCharge {
+	var out []TierCharge
+	pos, remaining := used, minutes
+	for i, t := range tiers {
+		if remaining <= 0 {
+			break
+		}
+		if t.UpToMinutes > 0 && pos >= t.UpToMinutes {
+			continue
+		}
+		take := remaining
+		if t.UpToMinutes > 0 && pos+take > t.UpToMinutes {
+			take = t.UpToMinutes - pos
+		}
+		out = append(out, TierCharge{
+			Tier:               i,
+			UpToMinutes:        t.UpToMinutes,
+			Minutes:            take,
+			RatePerMinuteMinor: t.RatePerMinuteMinor,
+			AmountMinor:        int64(take) * t.RatePerMinuteMinor,
+		})
+		pos += take
+		remaining -= take
+	}
+	return out
+}
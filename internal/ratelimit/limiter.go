@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"telecom-platform/pkg/logger"
+	"telecom-platform/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request rate limiting for public endpoints (tracking scripts, postbacks).
+//
+// Limits are fixed windows: each key may make Limit requests per Window, counted
+// in a shared store so every API instance sees the same totals. Keys combine the
+// caller (client IP) with what it is calling (e.g. a site key), so one abusive
+// visitor cannot exhaust a site's budget for everyone else.
+
+// Counter counts hits per key. Hit must be atomic across instances.
+type Counter interface {
+	// Hit increments key, expiring it after ttl, and returns the new count.
+	Hit(ctx context.Context, key string, ttl time.Duration) (int64, error)
+}
+
+// Limiter allows Limit requests per Window for each key.
+type Limiter struct {
+	Counter Counter
+
+	// Limit is the number of requests allowed per key per window.
+	Limit int64
+	// Window is the length of a counting window (default one minute).
+	Window time.Duration
+
+	// FailClosed rejects requests with 503 when the counter is unavailable.
+	// Off by default, unlike replay protection: throttling is best effort and
+	// an outage of the store should not take public endpoints down.
+	FailClosed bool
+
+	Now func() time.Time
+}
+
+// Allow counts a request for key. When the limit is exceeded it returns false
+// and the time until the current window ends.
+func (l Limiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	if l.Limit <= 0 {
+		return true, 0, nil
+	}
+	window := l.window()
+	now := l.now()
+	start := now.Truncate(window)
+	n, err := l.Counter.Hit(ctx, "ratelimit:"+key+":"+strconv.FormatInt(start.Unix(), 10), window)
+	if err != nil {
+		return false, 0, err
+	}
+	if n > l.Limit {
+		return false, start.Add(window).Sub(now), nil
+	}
+	return true, 0, nil
+}
+
+// Middleware answers 429 with Retry-After once key's limit is exceeded. key
+// derives the bucket from the request; nil means ClientIP and the route path.
+func (l Limiter) Middleware(key func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		k := ClientIP(c) + ":" + c.FullPath()
+		if key != nil {
+			k = key(c)
+		}
+		ok, retry, err := l.Allow(c.Request.Context(), k)
+		if err != nil {
+			logger.From(c.Request.Context()).Error("rate limit counter failed", "err", err)
+			if l.FailClosed {
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "rate limit unavailable"})
+				return
+			}
+			c.Next()
+			return
+		}
+		if !ok {
+			secs := int64((retry + time.Second - 1) / time.Second)
+			c.Header("Retry-After", strconv.FormatInt(secs, 10))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// ClientIP is the caller IP resolved by httpapi.ClientIPMiddleware, or gin's
+// own guess when that middleware did not run.
+func ClientIP(c *gin.Context) string {
+	if ip := utils.ClientIP(c.Request.Context()); ip != "" {
+		return ip
+	}
+	return c.ClientIP()
+}
+
+func (l Limiter) window() time.Duration {
+	if l.Window > 0 {
+		return l.Window
+	}
+	return time.Minute
+}
+
+func (l Limiter) now() time.Time {
+	if l.Now != nil {
+		return l.Now()
+	}
+	return time.Now()
+}
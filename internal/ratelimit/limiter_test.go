@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLimiter_AllowsLimitPerWindow(t *testing.T) {
+	now := time.Unix(1_800_000_010, 0)
+	l := Limiter{Counter: NewMemoryCounter(), Limit: 2, Window: time.Minute, Now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if ok, _, err := l.Allow(ctx, "ip1:site"); err != nil || !ok {
+			t.Fatalf("request %d: ok=%v err=%v", i+1, ok, err)
+		}
+	}
+	ok, retry, err := l.Allow(ctx, "ip1:site")
+	if err != nil || ok {
+		t.Fatalf("expected third request limited, ok=%v err=%v", ok, err)
+	}
+	if retry != 50*time.Second {
+		t.Fatalf("expected retry at window end (50s), got %s", retry)
+	}
+	if ok, _, _ := l.Allow(ctx, "ip2:site"); !ok {
+		t.Fatalf("expected keys counted separately")
+	}
+	now = now.Add(time.Minute)
+	if ok, _, _ := l.Allow(ctx, "ip1:site"); !ok {
+		t.Fatalf("expected a new window to reset the count")
+	}
+}
+
+type failingCounter struct{}
+
+func (failingCounter) Hit(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return 0, errors.New("redis down")
+}
+
+func TestLimiter_MiddlewareRejectsWithRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	serve := func(l Limiter) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.POST("/t", l.Middleware(nil), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/t", nil))
+		return w
+	}
+
+	l := Limiter{Counter: NewMemoryCounter(), Limit: 1}
+	if w := serve(l); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+	w := serve(l)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected 429 with Retry-After, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	down := Limiter{Counter: failingCounter{}, Limit: 1}
+	if w := serve(down); w.Code != http.StatusNoContent {
+		t.Fatalf("expected fail-open pass, got %d", w.Code)
+	}
+	down.FailClosed = true
+	if w := serve(down); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 when failing closed, got %d", w.Code)
+	}
+}
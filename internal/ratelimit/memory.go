@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryCounter is a single-process Counter useful for tests.
+// It is not intended for production use.
+
+type MemoryCounter struct {
+	mu    sync.Mutex
+	items map[string]memoryCount
+	Now   func() time.Time
+}
+
+type memoryCount struct {
+	n   int64
+	exp time.Time
+}
+
+func NewMemoryCounter() *MemoryCounter {
+	return &MemoryCounter{items: map[string]memoryCount{}}
+}
+
+func (s *MemoryCounter) Hit(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	now := time.Now()
+	if s.Now != nil {
+		now = s.Now()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.items[key]
+	if !now.Before(c.exp) {
+		c.n = 0
+	}
+	c.n++
+	c.exp = now.Add(ttl)
+	s.items[key] = c
+	return c.n, nil
+}
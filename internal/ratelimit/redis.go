@@ -0,0 +1,28 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisCounter counts with INCR and PEXPIRE in one transaction, so every API
+// instance shares the windows.
+type RedisCounter struct {
+	Client *redis.Client
+}
+
+func (r RedisCounter) Hit(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	if r.Client == nil {
+		return 0, errors.New("ratelimit: redis client is nil")
+	}
+	pipe := r.Client.TxPipeline()
+	n := pipe.Incr(ctx, key)
+	pipe.PExpire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return n.Val(), nil
+}
@@ -84,3 +84,35 @@ func RequireAnyRole(allowed ...string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+/*
+RequirePlatformRole guards platform-wide controls (e.g. the NOC) that act on no
+single workspace, so no workspace_id is required.
+
+Rules:
+- the caller's role must be a platform role (super_admin, network_operator)
+- super_admin bypasses the allowed list; other roles must be listed
+*/
+func RequirePlatformRole(allowed ...string) gin.HandlerFunc {
+	allowedSet := make(map[string]struct{}, len(allowed))
+	for _, r := range allowed {
+		allowedSet[r] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		role, err := auth.RoleFromGin(c)
+		if err != nil || role == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "role required",
+			})
+			return
+		}
+		if _, ok := allowedSet[role]; !IsPlatformRole(role) || (!ok && !IsSuperAdmin(role)) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "forbidden",
+			})
+			return
+		}
+		c.Next()
+	}
+}
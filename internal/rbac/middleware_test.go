@@ -69,3 +69,25 @@ func TestRequireAnyRole_WorkspaceRequired(t *testing.T) {
 		t.Fatalf("expected 401, got %d", w.Code)
 	}
 }
+
+func TestRequirePlatformRole_NoWorkspaceTenantRolesDenied(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for role, want := range map[string]int{RoleNetworkOperator: 200, RoleSuperAdmin: 200, RoleOwner: 403} {
+		r := gin.New()
+		r.GET("/x", func(c *gin.Context) {
+			ctx := auth.WithIdentity(c.Request.Context(), "u", "", role)
+			c.Request = c.Request.WithContext(ctx)
+			c.Next()
+		}, RequirePlatformRole(RoleNetworkOperator, RoleOwner), func(c *gin.Context) {
+			c.Status(200)
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/x", nil)
+		r.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Fatalf("%s: expected %d, got %d", role, want, w.Code)
+		}
+	}
+}
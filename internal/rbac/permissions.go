@@ -0,0 +1,179 @@
+package rbac
+
+import (
+	"net/http"
+	"sort"
+
+	"telecom-platform/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Permission is a concrete action a caller may perform.
+// Keep these stable; frontends key UI affordances off them.
+type Permission string
+
+const (
+	PermWalletBalanceRead     Permission = "wallet.balance.read"
+	PermWalletManualCredit    Permission = "wallet.manual_credit"
+	PermWalletManage          Permission = "wallet.manage"
+	PermCallsStart            Permission = "calls.start"
+	PermCallsMonitor          Permission = "calls.monitor"
+	PermCampaignsRead         Permission = "campaigns.read"
+	PermCallbacksManage       Permission = "callbacks.manage"
+	PermRoutingScheduleWrite  Permission = "routing.schedule.write"
+	PermDestinationGroupsRW   Permission = "routing.destination_groups.manage"
+	PermNumbersPurchase       Permission = "numbers.purchase"
+	PermNumbersPolicyWrite    Permission = "numbers.policy.write"
+	PermNumbersForwardingRW   Permission = "numbers.forwarding.manage"
+	PermComplianceDocsUpload  Permission = "compliance.documents.upload"
+	PermAuditExportManage     Permission = "audit.export.manage"
+	PermApprovalsDecide       Permission = "approvals.decide"
+	PermNOCConsole            Permission = "noc.console"
+	PermContractsRead         Permission = "contracts.read"
+	PermAdminAccess           Permission = "admin.access"
+	PermSystemAnnouncementsRW Permission = "system.announcements.manage"
+	PermJobsRead              Permission = "jobs.read"
+	PermLookupsRun            Permission = "lookups.run"
+	PermMessagingManage       Permission = "messaging.manage"
+	PermReportsRead           Permission = "reports.read"
+)
+
+// allPermissions is the full catalog; super_admin is granted every entry.
+var allPermissions = []Permission{
+	PermWalletBalanceRead,
+	PermWalletManualCredit,
+	PermWalletManage,
+	PermCallsStart,
+	PermCallsMonitor,
+	PermCampaignsRead,
+	PermCallbacksManage,
+	PermRoutingScheduleWrite,
+	PermDestinationGroupsRW,
+	PermNumbersPurchase,
+	PermNumbersPolicyWrite,
+	PermNumbersForwardingRW,
+	PermComplianceDocsUpload,
+	PermAuditExportManage,
+	PermApprovalsDecide,
+	PermNOCConsole,
+	PermContractsRead,
+	PermAdminAccess,
+	PermSystemAnnouncementsRW,
+	PermJobsRead,
+	PermLookupsRun,
+	PermMessagingManage,
+	PermReportsRead,
+}
+
+// permissionMatrix is the single source of truth for role -> permissions.
+// It must mirror the RequireAnyRole lists used in route wiring.
+// Hidden roles get only what is explicitly listed here.
+var permissionMatrix = map[string][]Permission{
+	RoleOwner: {
+		PermWalletBalanceRead,
+		PermWalletManualCredit,
+		PermWalletManage,
+		PermCallsStart,
+		PermCallsMonitor,
+		PermCampaignsRead,
+		PermCallbacksManage,
+		PermRoutingScheduleWrite,
+		PermDestinationGroupsRW,
+		PermNumbersPurchase,
+		PermNumbersPolicyWrite,
+		PermNumbersForwardingRW,
+		PermComplianceDocsUpload,
+		PermAuditExportManage,
+		PermApprovalsDecide,
+		PermContractsRead,
+		PermAdminAccess,
+		PermJobsRead,
+		PermLookupsRun,
+		PermMessagingManage,
+		PermReportsRead,
+	},
+	RoleAgent: {
+		PermWalletBalanceRead,
+		PermCallsStart,
+		PermCallbacksManage,
+		PermLookupsRun,
+	},
+	RoleAnalyst: {
+		PermWalletBalanceRead,
+		PermCampaignsRead,
+		PermJobsRead,
+		PermReportsRead,
+	},
+	RoleFinance: {
+		PermWalletBalanceRead,
+		PermContractsRead,
+		PermJobsRead,
+		PermReportsRead,
+	},
+	RoleReadOnly: {
+		PermWalletBalanceRead,
+		PermCampaignsRead,
+		PermContractsRead,
+		PermJobsRead,
+		PermReportsRead,
+	},
+	RoleNetworkOperator: {
+		PermWalletBalanceRead,
+		PermNOCConsole,
+	},
+}
+
+// PermissionsFor returns the sorted permissions granted to role.
+func PermissionsFor(role string) []Permission {
+	var src []Permission
+	if IsSuperAdmin(role) {
+		src = allPermissions
+	} else {
+		src = permissionMatrix[role]
+	}
+	out := make([]Permission, len(src))
+	copy(out, src)
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// Can reports whether role has permission p.
+func Can(role string, p Permission) bool {
+	if IsSuperAdmin(role) {
+		return true
+	}
+	for _, x := range permissionMatrix[role] {
+		if x == p {
+			return true
+		}
+	}
+	return false
+}
+
+// RequirePermission allows access if the caller's role is granted p in the permission matrix.
+func RequirePermission(p Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		wid, err := auth.WorkspaceIDFromGin(c)
+		if err != nil || wid == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "workspace_id required",
+			})
+			return
+		}
+		role, err := auth.RoleFromGin(c)
+		if err != nil || role == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "role required",
+			})
+			return
+		}
+		if !Can(role, p) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "forbidden",
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,21 @@
+package rbac
+
+import "testing"
+
+func TestPermissionsFor_SuperAdminGetsAll(t *testing.T) {
+	if got := PermissionsFor(RoleSuperAdmin); len(got) != len(allPermissions) {
+		t.Fatalf("expected %d permissions, got %d", len(allPermissions), len(got))
+	}
+}
+
+func TestCan_HiddenRoleOnlyExplicit(t *testing.T) {
+	if Can(RoleNetworkOperator, PermAdminAccess) {
+		t.Fatalf("expected hidden role denied admin access")
+	}
+	if !Can(RoleOwner, PermAdminAccess) {
+		t.Fatalf("expected owner admin access")
+	}
+	if Can("unknown", PermWalletBalanceRead) {
+		t.Fatalf("expected unknown role denied")
+	}
+}
@@ -0,0 +1,79 @@
+package rbac
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"telecom-platform/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*
+Read-only role (soft launch).
+
+read_only is a workspace role for auditors and embedded analytics. It may call
+GET/HEAD/OPTIONS endpoints only:
+- RequireSafeMethods, installed once on the protected API group, rejects every
+  other method for the role regardless of per-route role lists;
+- its tokens also carry only the read scope, so auth.RequireWriteScope rejects
+  writes even for tokens that outlive a role change.
+
+Workspaces opt in with the FeatureReadOnlyRole flag; IssueReadOnlyPair refuses
+to issue otherwise.
+*/
+
+// FeatureReadOnlyRole is the workspace feature flag that enables read_only tokens.
+const FeatureReadOnlyRole = "read_only_role"
+
+var ErrReadOnlyRoleDisabled = errors.New("read_only role is not enabled for this workspace")
+
+// IsSafeMethod reports whether an HTTP method is read-only.
+func IsSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+/*
+RequireSafeMethods is the central method-based policy for read_only callers.
+Requests without a role pass through; authentication is enforced elsewhere.
+*/
+func RequireSafeMethods() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if IsSafeMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if role, err := auth.RoleFromGin(c); err == nil && role == RoleReadOnly {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "read_only role cannot modify resources",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// IssueReadOnlyPair issues a read_only token pair for workspaceID, restricted to
+// the read scope. features are the workspace's enabled feature flags.
+func IssueReadOnlyPair(m *auth.Manager, now time.Time, userID, workspaceID string, features []string) (auth.TokenPair, error) {
+	enabled := false
+	for _, f := range features {
+		if f == FeatureReadOnlyRole {
+			enabled = true
+			break
+		}
+	}
+	if !enabled {
+		return auth.TokenPair{}, ErrReadOnlyRoleDisabled
+	}
+	return m.IssuePairWithOptions(now, userID, workspaceID, RoleReadOnly, auth.TokenOptions{
+		Features: features,
+		Scopes:   []string{auth.ScopeRead},
+	})
+}
@@ -0,0 +1,74 @@
+package rbac
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/auth"
+	"telecom-platform/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireSafeMethods_ReadOnlyRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		ctx := auth.WithIdentity(c.Request.Context(), "u", "w", c.GetHeader("X-Role"))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}, RequireSafeMethods())
+	ok := func(c *gin.Context) { c.Status(200) }
+	r.GET("/x", ok)
+	r.POST("/x", RequireAnyRole(RoleOwner, RoleReadOnly), ok)
+
+	cases := []struct {
+		method, role string
+		want         int
+	}{
+		{http.MethodGet, RoleReadOnly, 200},
+		{http.MethodPost, RoleReadOnly, 403},
+		{http.MethodPost, RoleOwner, 200},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, "/x", nil)
+		req.Header.Set("X-Role", tc.role)
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Fatalf("%s as %s: expected %d, got %d", tc.method, tc.role, tc.want, w.Code)
+		}
+	}
+}
+
+func TestIssueReadOnlyPair(t *testing.T) {
+	m, err := auth.NewManager(config.AuthConfig{
+		JWTSecret:       "secret",
+		JWTIssuer:       "issuer",
+		JWTAudience:     "aud",
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("manager: %v", err)
+	}
+	now := time.Now()
+
+	if _, err := IssueReadOnlyPair(m, now, "auditor", "ws-1", []string{"sso"}); err != ErrReadOnlyRoleDisabled {
+		t.Fatalf("expected ErrReadOnlyRoleDisabled, got %v", err)
+	}
+	pair, err := IssueReadOnlyPair(m, now, "auditor", "ws-1", []string{FeatureReadOnlyRole})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	claims, err := m.Verify(pair.AccessToken, auth.TokenTypeAccess, now)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if claims.Role != RoleReadOnly || claims.HasScope(auth.ScopeWrite) || !claims.HasScope(auth.ScopeRead) {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+}
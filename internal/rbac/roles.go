@@ -7,9 +7,14 @@ const (
 	RoleAnalyst         = "analyst"
 	RoleFinance         = "finance"
 	RoleSuperAdmin      = "super_admin"
+	RoleReadOnly        = "read_only"        // GET-only; see readonly.go
 	RoleNetworkOperator = "network_operator" // hidden role
 )
 
 func IsSuperAdmin(role string) bool { return role == RoleSuperAdmin }
 
 func IsHiddenRole(role string) bool { return role == RoleNetworkOperator }
+
+// IsPlatformRole reports whether role operates the platform itself rather than a
+// tenant workspace.
+func IsPlatformRole(role string) bool { return role == RoleSuperAdmin || role == RoleNetworkOperator }
@@ -0,0 +1,139 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"telecom-platform/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Replay protection for public, webhook-style endpoints (conversion postbacks,
+// tracking pixels).
+//
+// Each request carries a unix timestamp and a single-use nonce. The guard rejects
+// timestamps outside Window and claims the nonce in a shared store for twice the
+// window, so a captured request cannot be counted again while its timestamp would
+// still be accepted. Both values must be covered by the request's signature or
+// token; otherwise a replayer can simply pick a fresh nonce.
+//
+// Nonces are scoped (e.g. per workspace and endpoint) so senders only need unique
+// nonces within their own traffic.
+
+const (
+	HeaderTimestamp = "X-Request-Timestamp"
+	HeaderNonce     = "X-Request-Nonce"
+
+	// Query parameters, for senders that cannot set headers (tracking pixels).
+	ParamTimestamp = "ts"
+	ParamNonce     = "nonce"
+
+	maxNonceLen = 128
+)
+
+// NonceStore records nonces. Claim must be atomic: exactly one caller wins a key.
+type NonceStore interface {
+	// Claim stores key for ttl and reports whether it was not already present.
+	Claim(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+var (
+	ErrMissing  = errors.New("replay: timestamp and nonce required")
+	ErrStale    = errors.New("replay: timestamp outside window")
+	ErrReplayed = errors.New("replay: nonce already used")
+)
+
+// Guard checks timestamp+nonce pairs.
+type Guard struct {
+	Nonces NonceStore
+
+	// Window is the accepted clock skew in either direction.
+	Window time.Duration
+
+	// FailOpen lets requests through when the nonce store is unavailable.
+	// Off by default: a dropped postback is retried by the sender, an inflated
+	// conversion count is not undone.
+	FailOpen bool
+
+	Now func() time.Time
+}
+
+// Check validates ts (unix seconds) and claims nonce within scope.
+func (g Guard) Check(ctx context.Context, scope, ts, nonce string) error {
+	ts, nonce = strings.TrimSpace(ts), strings.TrimSpace(nonce)
+	if ts == "" || nonce == "" || len(nonce) > maxNonceLen {
+		return ErrMissing
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrMissing
+	}
+	window := g.window()
+	if d := g.now().Sub(time.Unix(sec, 0)); d > window || d < -window {
+		return ErrStale
+	}
+	ok, err := g.Nonces.Claim(ctx, "replay:"+scope+":"+nonce, 2*window)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrReplayed
+	}
+	return nil
+}
+
+// Middleware rejects replayed requests before the handler runs. scope derives the
+// nonce namespace from the request (e.g. workspace token + route); nil means the
+// route path. Timestamp and nonce are read from headers, falling back to the query.
+func (g Guard) Middleware(scope func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s := c.FullPath()
+		if scope != nil {
+			s = scope(c)
+		}
+		ts := c.GetHeader(HeaderTimestamp)
+		if ts == "" {
+			ts = c.Query(ParamTimestamp)
+		}
+		nonce := c.GetHeader(HeaderNonce)
+		if nonce == "" {
+			nonce = c.Query(ParamNonce)
+		}
+
+		err := g.Check(c.Request.Context(), s, ts, nonce)
+		switch {
+		case err == nil:
+			c.Next()
+		case errors.Is(err, ErrMissing), errors.Is(err, ErrStale):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrReplayed):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			logger.From(c.Request.Context()).Error("replay nonce store failed", "err", err)
+			if g.FailOpen {
+				c.Next()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "replay check unavailable"})
+		}
+	}
+}
+
+func (g Guard) window() time.Duration {
+	if g.Window > 0 {
+		return g.Window
+	}
+	return 5 * time.Minute
+}
+
+func (g Guard) now() time.Time {
+	if g.Now != nil {
+		return g.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,74 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGuard_RejectsReplayedAndStaleRequests(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	g := Guard{Nonces: NewMemoryNonceStore(), Window: time.Minute, Now: func() time.Time { return now }}
+	ctx := context.Background()
+	ts := strconv.FormatInt(now.Unix(), 10)
+
+	if err := g.Check(ctx, "w1:postback", ts, "n1"); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	if err := g.Check(ctx, "w1:postback", ts, "n1"); !errors.Is(err, ErrReplayed) {
+		t.Fatalf("expected ErrReplayed, got %v", err)
+	}
+	if err := g.Check(ctx, "w2:postback", ts, "n1"); err != nil {
+		t.Fatalf("expected nonce scoped per sender, got %v", err)
+	}
+	old := strconv.FormatInt(now.Add(-2*time.Minute).Unix(), 10)
+	if err := g.Check(ctx, "w1:postback", old, "n2"); !errors.Is(err, ErrStale) {
+		t.Fatalf("expected ErrStale, got %v", err)
+	}
+	if err := g.Check(ctx, "w1:postback", "", "n3"); !errors.Is(err, ErrMissing) {
+		t.Fatalf("expected ErrMissing, got %v", err)
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return false, errors.New("redis down")
+}
+
+func TestGuard_MiddlewareReadsQueryAndFailsClosed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Now()
+	ts := strconv.FormatInt(now.Unix(), 10)
+
+	serve := func(g Guard, target string) int {
+		r := gin.New()
+		r.GET("/px", g.Middleware(nil), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w.Code
+	}
+
+	g := Guard{Nonces: NewMemoryNonceStore()}
+	if code := serve(g, "/px?ts="+ts+"&nonce=a"); code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", code)
+	}
+	if code := serve(g, "/px?ts="+ts+"&nonce=a"); code != http.StatusConflict {
+		t.Fatalf("expected 409 for replay, got %d", code)
+	}
+
+	down := Guard{Nonces: failingStore{}}
+	if code := serve(down, "/px?ts="+ts+"&nonce=b"); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 when the store is down, got %d", code)
+	}
+	down.FailOpen = true
+	if code := serve(down, "/px?ts="+ts+"&nonce=b"); code != http.StatusNoContent {
+		t.Fatalf("expected fail-open pass, got %d", code)
+	}
+}
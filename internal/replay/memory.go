@@ -0,0 +1,34 @@
+package replay
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryNonceStore is a single-process NonceStore useful for tests.
+// It is not intended for production use.
+
+type MemoryNonceStore struct {
+	mu    sync.Mutex
+	items map[string]time.Time // key -> expiry
+	Now   func() time.Time
+}
+
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{items: map[string]time.Time{}}
+}
+
+func (s *MemoryNonceStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	if s.Now != nil {
+		now = s.Now()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if exp, ok := s.items[key]; ok && now.Before(exp) {
+		return false, nil
+	}
+	s.items[key] = now.Add(ttl)
+	return true, nil
+}
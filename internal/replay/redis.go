@@ -0,0 +1,21 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisNonceStore claims nonces with SET NX PX, so every API instance shares them.
+type RedisNonceStore struct {
+	Client *redis.Client
+}
+
+func (r RedisNonceStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if r.Client == nil {
+		return false, errors.New("replay: redis client is nil")
+	}
+	return r.Client.SetNX(ctx, key, 1, ttl).Result()
+}
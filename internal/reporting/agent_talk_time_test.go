@@ -0,0 +1,39 @@
+package reporting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/calls"
+)
+
+func TestAgentTalkTime_ExcludesHoldWhenConfigured(t *testing.T) {
+	repo := NewMemoryRepo()
+	now := time.Unix(1700000000, 0).UTC()
+	repo.Calls = []calls.Call{
+		{CallID: "c1", WorkspaceID: "w", AgentUserID: "a1", DurationSeconds: 300, HoldSeconds: 60, CreatedAt: now},
+		{CallID: "c2", WorkspaceID: "w", AgentUserID: "a1", DurationSeconds: 100, CreatedAt: now},
+		{CallID: "c3", WorkspaceID: "w", AgentUserID: "a2", DurationSeconds: 350, HoldSeconds: 200, CreatedAt: now},
+		{CallID: "c4", WorkspaceID: "w", DurationSeconds: 500, CreatedAt: now},
+	}
+	svc := NewService(repo)
+	req := AgentTalkTimeRequest{WorkspaceID: "w", Range: TimeRange{From: now.Add(-time.Hour), To: now.Add(time.Hour)}}
+
+	out, err := svc.AgentTalkTime(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.HoldExcluded || len(out.Agents) != 2 || out.Agents[0] != (AgentTalkTimeRow{AgentUserID: "a1", Calls: 2, TalkSeconds: 400, HoldSeconds: 60, AverageTalkSeconds: 200}) {
+		t.Fatalf("unexpected report %+v", out)
+	}
+
+	svc.ExcludeHoldFromTalkTime = true
+	out, err = svc.AgentTalkTime(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.HoldExcluded || out.Agents[0].TalkSeconds != 340 || out.Agents[1] != (AgentTalkTimeRow{AgentUserID: "a2", Calls: 1, TalkSeconds: 150, HoldSeconds: 200, AverageTalkSeconds: 150}) {
+		t.Fatalf("unexpected report excluding hold %+v", out)
+	}
+}
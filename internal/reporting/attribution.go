@@ -0,0 +1,156 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/calls"
+	"telecom-platform/internal/tracking"
+	"telecom-platform/internal/wallet"
+)
+
+// AttributionSource maps call ids to the visit attribution of the tracking number
+// they dialed; tracking.Service implements it.
+type AttributionSource interface {
+	CallAttributions(ctx context.Context, workspaceID string, callIDs []string) (map[string]tracking.Attribution, error)
+}
+
+// ConversionSource reports which calls were forwarded as ad platform conversions;
+// conversions.Service implements it.
+type ConversionSource interface {
+	ConvertedCalls(ctx context.Context, workspaceID string, callIDs []string) (map[string]bool, error)
+}
+
+// chargeWindow is how long after the report range usage debits for its calls are
+// still picked up (calls are billed when they end).
+const chargeWindow = 24 * time.Hour
+
+const unattributed = "(unattributed)"
+
+// AttributionReport joins the range's calls to their visit attribution, conversions and
+// usage spend. Without an Attribution source every call is unattributed; without
+// a Conversions source conversions are reported as 0.
+func (s *Service) AttributionReport(ctx context.Context, req AttributionRequest) (AttributionReport, error) {
+	if req.WorkspaceID == "" {
+		return AttributionReport{}, ErrInvalidRequest
+	}
+	if s.repo == nil {
+		return AttributionReport{}, errors.New("reporting: repository not configured")
+	}
+	release, err := s.acquire(req.WorkspaceID)
+	if err != nil {
+		return AttributionReport{}, err
+	}
+	defer release()
+	rng, loc, err := s.resolveRange(ctx, req.WorkspaceID, req.Timezone, req.Range)
+	if err != nil {
+		return AttributionReport{}, err
+	}
+
+	rows, err := s.repo.ListCalls(ctx, req.WorkspaceID, rng.From, rng.To, req.CampaignID)
+	if err != nil {
+		return AttributionReport{}, err
+	}
+	ids := make([]string, 0, len(rows))
+	for _, c := range rows {
+		ids = append(ids, c.CallID)
+	}
+	attrs := map[string]tracking.Attribution{}
+	if s.Attribution != nil {
+		if attrs, err = s.Attribution.CallAttributions(ctx, req.WorkspaceID, ids); err != nil {
+			return AttributionReport{}, err
+		}
+	}
+	converted := map[string]bool{}
+	if s.Conversions != nil {
+		if converted, err = s.Conversions.ConvertedCalls(ctx, req.WorkspaceID, ids); err != nil {
+			return AttributionReport{}, err
+		}
+	}
+
+	out := AttributionReport{WorkspaceID: req.WorkspaceID, Currency: req.Currency, Timezone: loc.String()}
+	spend, currency, err := s.callSpend(ctx, req.WorkspaceID, rng, req.Currency)
+	if err != nil {
+		return AttributionReport{}, err
+	}
+	if out.Currency == "" {
+		out.Currency = currency
+	}
+
+	type key struct{ source, medium, campaign string }
+	groups := map[key]*AttributionRow{}
+	for _, c := range rows {
+		a, ok := attrs[c.CallID]
+		k := key{unattributed, unattributed, ""}
+		if ok {
+			k = key{a.Source, a.Medium, a.Campaign}
+		}
+		row, found := groups[k]
+		if !found {
+			row = &AttributionRow{Source: k.source, Medium: k.medium, Campaign: k.campaign}
+			groups[k] = row
+		}
+		row.Calls++
+		row.TotalDurationSeconds += c.DurationSeconds
+		if c.Status == calls.CallStatusCompleted {
+			row.CompletedCalls++
+		}
+		if converted[c.CallID] {
+			row.Conversions++
+		}
+		row.SpendMinor += spend[c.CallID]
+		if a.GCLID != "" {
+			row.GCLIDCalls++
+		}
+		if a.FBCLID != "" {
+			row.FBCLIDCalls++
+		}
+	}
+
+	out.Rows = make([]AttributionRow, 0, len(groups))
+	for _, row := range groups {
+		out.Rows = append(out.Rows, *row)
+	}
+	sort.Slice(out.Rows, func(i, j int) bool {
+		a, b := out.Rows[i], out.Rows[j]
+		if a.Calls != b.Calls {
+			return a.Calls > b.Calls
+		}
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Medium != b.Medium {
+			return a.Medium < b.Medium
+		}
+		return a.Campaign < b.Campaign
+	})
+	return out, nil
+}
+
+// callSpend sums usage debits per call id (billing posts them with external_ref
+// "call:<id>") in currency, or in the first currency seen when it is empty.
+func (s *Service) callSpend(ctx context.Context, workspaceID string, rng TimeRange, currency string) (map[string]int64, string, error) {
+	ledgers, err := s.repo.ListWalletLedger(ctx, workspaceID, rng.From, rng.To.Add(chargeWindow), "")
+	if err != nil {
+		return nil, "", err
+	}
+	out := map[string]int64{}
+	for _, l := range ledgers {
+		callID, ok := strings.CutPrefix(l.ExternalRef, "call:")
+		// Call charges are debits; a positive reversal is a refund of one.
+		if !ok || (l.AmountMinor >= 0 && l.Type != wallet.LedgerEntryTypeReversal) {
+			continue
+		}
+		if currency == "" {
+			currency = l.Currency
+		}
+		if l.Currency != currency {
+			continue
+		}
+		out[callID] += -l.AmountMinor
+	}
+	return out, currency, nil
+}
@@ -0,0 +1,206 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"telecom-platform/internal/calls"
+	"telecom-platform/internal/wallet"
+	"telecom-platform/pkg/logger"
+)
+
+// Dashboard aggregates.
+//
+// The dashboard's headline numbers (today's calls, today's spend, campaigns with
+// calls today) are the most requested reports. DashboardWarmer recomputes them for
+// every active workspace each minute and stores them in a DashboardCache, so a
+// dashboard load reads Redis instead of scanning calls and the ledger. Dashboard
+// serves the cached copy and only computes (and caches) on a miss, e.g. for a
+// workspace that just became active. "Today" is the workspace's local day.
+
+// DashboardAggregates is the cached dashboard summary for one workspace.
+type DashboardAggregates struct {
+	WorkspaceID string `json:"workspace_id"`
+	Timezone    string `json:"timezone"`
+	// Day is the local date the aggregates cover (YYYY-MM-DD).
+	Day string `json:"day"`
+
+	Calls           int `json:"calls"`
+	CompletedCalls  int `json:"completed_calls"`
+	InProgressCalls int `json:"in_progress_calls"`
+	// ActiveCampaigns counts distinct campaigns with at least one call today.
+	ActiveCampaigns int `json:"active_campaigns"`
+
+	// SpendMinor is today's usage debits per currency.
+	SpendMinor map[string]int64 `json:"spend_minor"`
+
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+// DashboardCache stores precomputed aggregates. Implementations must key entries
+// by workspace.
+type DashboardCache interface {
+	GetDashboard(ctx context.Context, workspaceID string) (DashboardAggregates, bool, error)
+	PutDashboard(ctx context.Context, agg DashboardAggregates, ttl time.Duration) error
+}
+
+// ActiveWorkspaces lists workspaces worth warming (e.g. with calls or logins in the
+// last day).
+type ActiveWorkspaces interface {
+	ActiveWorkspaceIDs(ctx context.Context) ([]string, error)
+}
+
+// DefaultDashboardTTL outlives a missed warm-up or two so dashboards keep reading
+// the cache while the worker restarts.
+const DefaultDashboardTTL = 3 * time.Minute
+
+// Dashboard returns the workspace's dashboard aggregates, from DashboardCache when
+// present. A cache read failure falls back to computing.
+func (s *Service) Dashboard(ctx context.Context, workspaceID string) (DashboardAggregates, error) {
+	if workspaceID == "" {
+		return DashboardAggregates{}, ErrInvalidRequest
+	}
+	if s.Cache != nil {
+		agg, ok, err := s.Cache.GetDashboard(ctx, workspaceID)
+		if err != nil {
+			logger.From(ctx).Warn("dashboard cache read failed", "workspace_id", workspaceID, "err", err)
+		} else if ok {
+			return agg, nil
+		}
+	}
+	agg, err := s.ComputeDashboard(ctx, workspaceID)
+	if err != nil {
+		return DashboardAggregates{}, err
+	}
+	if s.Cache != nil {
+		if err := s.Cache.PutDashboard(ctx, agg, DefaultDashboardTTL); err != nil {
+			logger.From(ctx).Warn("dashboard cache write failed", "workspace_id", workspaceID, "err", err)
+		}
+	}
+	return agg, nil
+}
+
+// ComputeDashboard computes the aggregates for the workspace's current local day.
+func (s *Service) ComputeDashboard(ctx context.Context, workspaceID string) (DashboardAggregates, error) {
+	if workspaceID == "" {
+		return DashboardAggregates{}, ErrInvalidRequest
+	}
+	if s.repo == nil {
+		return DashboardAggregates{}, errors.New("reporting: repository not configured")
+	}
+	now := s.clock()
+	loc, err := s.location(ctx, workspaceID, "")
+	if err != nil {
+		return DashboardAggregates{}, err
+	}
+	day := bucketStart(now, loc, GranularityDay)
+	from, to := day, day.AddDate(0, 0, 1)
+
+	rows, err := s.repo.ListCalls(ctx, workspaceID, from, to, "")
+	if err != nil {
+		return DashboardAggregates{}, err
+	}
+	ledgers, err := s.repo.ListWalletLedger(ctx, workspaceID, from, to, "")
+	if err != nil {
+		return DashboardAggregates{}, err
+	}
+
+	out := DashboardAggregates{
+		WorkspaceID: workspaceID,
+		Timezone:    loc.String(),
+		Day:         day.Format(dateLayout),
+		SpendMinor:  map[string]int64{},
+		ComputedAt:  now.UTC(),
+	}
+	campaigns := map[string]bool{}
+	for _, c := range rows {
+		out.Calls++
+		switch c.Status {
+		case calls.CallStatusCompleted:
+			out.CompletedCalls++
+		case calls.CallStatusInProgress, calls.CallStatusRinging:
+			out.InProgressCalls++
+		}
+		if c.CampaignID != "" {
+			campaigns[c.CampaignID] = true
+		}
+	}
+	out.ActiveCampaigns = len(campaigns)
+	for _, l := range ledgers {
+		// Usage net of refunds: holds are not spend yet, and admin adjustments and
+		// contract true-ups are reported separately by SpendSummary.
+		if l.ExternalRef == "admin_manual_credit" || l.ExternalRef == "contract_true_up" {
+			continue
+		}
+		switch {
+		case l.Type == wallet.LedgerEntryTypeDebit:
+			out.SpendMinor[l.Currency] += -l.AmountMinor
+		case l.Type == wallet.LedgerEntryTypeReversal && l.AmountMinor > 0:
+			// Refunded usage.
+			out.SpendMinor[l.Currency] -= l.AmountMinor
+		}
+	}
+	return out, nil
+}
+
+// DashboardWarmer precomputes dashboard aggregates for active workspaces. Run a
+// single instance; a workspace that fails is logged and retried on the next run.
+type DashboardWarmer struct {
+	Reporting  *Service
+	Cache      DashboardCache
+	Workspaces ActiveWorkspaces
+	// TTL defaults to DefaultDashboardTTL.
+	TTL time.Duration
+}
+
+// RunOnce warms every active workspace and returns how many were cached.
+func (w DashboardWarmer) RunOnce(ctx context.Context) (int, error) {
+	if w.Reporting == nil || w.Cache == nil || w.Workspaces == nil {
+		return 0, errors.New("reporting: dashboard warmer not configured")
+	}
+	ids, err := w.Workspaces.ActiveWorkspaceIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	warmed := 0
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return warmed, ctx.Err()
+		}
+		agg, err := w.Reporting.ComputeDashboard(ctx, id)
+		if err != nil {
+			logger.From(ctx).Error("dashboard warm failed", "workspace_id", id, "err", err)
+			continue
+		}
+		if err := w.Cache.PutDashboard(ctx, agg, w.ttl()); err != nil {
+			logger.From(ctx).Error("dashboard cache write failed", "workspace_id", id, "err", err)
+			continue
+		}
+		warmed++
+	}
+	return warmed, nil
+}
+
+// Run warms on every tick (typically one minute) until ctx is done.
+func (w DashboardWarmer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.RunOnce(ctx); err != nil {
+				logger.From(ctx).Error("dashboard warm run failed", "err", err)
+			}
+		}
+	}
+}
+
+func (w DashboardWarmer) ttl() time.Duration {
+	if w.TTL > 0 {
+		return w.TTL
+	}
+	return DefaultDashboardTTL
+}
@@ -0,0 +1,90 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisDashboardCache stores aggregates as JSON under <Prefix><workspace_id> with
+// the warmer's TTL.
+type RedisDashboardCache struct {
+	Client *redis.Client
+	// Prefix defaults to "dashboard:".
+	Prefix string
+}
+
+func (c RedisDashboardCache) GetDashboard(ctx context.Context, workspaceID string) (DashboardAggregates, bool, error) {
+	if c.Client == nil {
+		return DashboardAggregates{}, false, errors.New("reporting: redis client is nil")
+	}
+	raw, err := c.Client.Get(ctx, c.key(workspaceID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return DashboardAggregates{}, false, nil
+	}
+	if err != nil {
+		return DashboardAggregates{}, false, err
+	}
+	var agg DashboardAggregates
+	if err := json.Unmarshal(raw, &agg); err != nil {
+		return DashboardAggregates{}, false, err
+	}
+	return agg, true, nil
+}
+
+func (c RedisDashboardCache) PutDashboard(ctx context.Context, agg DashboardAggregates, ttl time.Duration) error {
+	if c.Client == nil {
+		return errors.New("reporting: redis client is nil")
+	}
+	raw, err := json.Marshal(agg)
+	if err != nil {
+		return err
+	}
+	return c.Client.Set(ctx, c.key(agg.WorkspaceID), raw, ttl).Err()
+}
+
+func (c RedisDashboardCache) key(workspaceID string) string {
+	prefix := c.Prefix
+	if prefix == "" {
+		prefix = "dashboard:"
+	}
+	return prefix + workspaceID
+}
+
+// MemoryDashboardCache is an in-memory DashboardCache for tests and early
+// development. Entries expire after their TTL.
+type MemoryDashboardCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryDashboardEntry
+	Now     func() time.Time
+}
+
+type memoryDashboardEntry struct {
+	agg       DashboardAggregates
+	expiresAt time.Time
+}
+
+func NewMemoryDashboardCache() *MemoryDashboardCache {
+	return &MemoryDashboardCache{entries: map[string]memoryDashboardEntry{}, Now: time.Now}
+}
+
+func (c *MemoryDashboardCache) GetDashboard(ctx context.Context, workspaceID string) (DashboardAggregates, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[workspaceID]
+	if !ok || !c.Now().Before(e.expiresAt) {
+		return DashboardAggregates{}, false, nil
+	}
+	return e.agg, true, nil
+}
+
+func (c *MemoryDashboardCache) PutDashboard(ctx context.Context, agg DashboardAggregates, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[agg.WorkspaceID] = memoryDashboardEntry{agg: agg, expiresAt: c.Now().Add(ttl)}
+	return nil
+}
@@ -0,0 +1,80 @@
+package reporting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/calls"
+	"telecom-platform/internal/wallet"
+)
+
+type staticWorkspaces []string
+
+func (s staticWorkspaces) ActiveWorkspaceIDs(ctx context.Context) ([]string, error) { return s, nil }
+
+func TestComputeDashboard_LocalDay(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, loc)
+	repo := NewMemoryRepo()
+	repo.Calls = []calls.Call{
+		{CallID: "c1", WorkspaceID: "w", CampaignID: "a", Status: calls.CallStatusCompleted, CreatedAt: now.Add(-time.Hour)},
+		{CallID: "c2", WorkspaceID: "w", CampaignID: "a", Status: calls.CallStatusInProgress, CreatedAt: now.Add(-time.Minute)},
+		{CallID: "c3", WorkspaceID: "w", CampaignID: "b", Status: calls.CallStatusNoAnswer, CreatedAt: now.Add(-2 * time.Hour)},
+		// Yesterday, local time (but today in UTC).
+		{CallID: "c4", WorkspaceID: "w", CampaignID: "c", Status: calls.CallStatusCompleted, CreatedAt: now.Add(-10 * time.Hour)},
+	}
+	repo.Ledgers = []wallet.WalletLedger{
+		{ID: "l1", WorkspaceID: "w", Type: wallet.LedgerEntryTypeDebit, Currency: "USD", AmountMinor: -120, ExternalRef: "call:c1", CreatedAt: now.Add(-time.Hour)},
+		{ID: "l2", WorkspaceID: "w", Type: wallet.LedgerEntryTypeHold, Currency: "USD", AmountMinor: -500, CreatedAt: now.Add(-time.Minute)},
+		{ID: "l3", WorkspaceID: "w", Type: wallet.LedgerEntryTypeDebit, Currency: "EUR", AmountMinor: -40, ExternalRef: "call:c3", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "l4", WorkspaceID: "w", Type: wallet.LedgerEntryTypeCredit, Currency: "USD", AmountMinor: 1000, CreatedAt: now},
+	}
+	svc := NewService(repo)
+	svc.Settings = MemorySettings{Timezones: map[string]string{"w": "America/New_York"}}
+	svc.clock = func() time.Time { return now }
+
+	agg, err := svc.ComputeDashboard(context.Background(), "w")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if agg.Day != "2026-03-02" || agg.Calls != 3 || agg.CompletedCalls != 1 || agg.InProgressCalls != 1 || agg.ActiveCampaigns != 2 {
+		t.Fatalf("unexpected aggregates: %+v", agg)
+	}
+	if len(agg.SpendMinor) != 2 || agg.SpendMinor["USD"] != 120 || agg.SpendMinor["EUR"] != 40 {
+		t.Fatalf("unexpected spend: %+v", agg.SpendMinor)
+	}
+}
+
+func TestDashboardWarmer_ServesFromCache(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	repo := NewMemoryRepo()
+	repo.Calls = []calls.Call{{CallID: "c1", WorkspaceID: "w1", Status: calls.CallStatusCompleted, CreatedAt: now}}
+	svc := NewService(repo)
+	svc.clock = func() time.Time { return now }
+	cache := NewMemoryDashboardCache()
+	cache.Now = func() time.Time { return now }
+	svc.Cache = cache
+
+	w := DashboardWarmer{Reporting: svc, Cache: cache, Workspaces: staticWorkspaces{"w1", "w2"}}
+	if n, err := w.RunOnce(context.Background()); err != nil || n != 2 {
+		t.Fatalf("expected two workspaces warmed, got %d %v", n, err)
+	}
+
+	// New calls are not visible until the next warm-up.
+	repo.Calls = append(repo.Calls, calls.Call{CallID: "c2", WorkspaceID: "w1", CreatedAt: now})
+	if agg, err := svc.Dashboard(context.Background(), "w1"); err != nil || agg.Calls != 1 {
+		t.Fatalf("expected cached aggregates, got %+v %v", agg, err)
+	}
+
+	now = now.Add(DefaultDashboardTTL)
+	if agg, err := svc.Dashboard(context.Background(), "w1"); err != nil || agg.Calls != 2 {
+		t.Fatalf("expected recompute after expiry, got %+v %v", agg, err)
+	}
+	if _, err := svc.Dashboard(context.Background(), ""); err != ErrInvalidRequest {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+}
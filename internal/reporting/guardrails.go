@@ -0,0 +1,131 @@
+package reporting
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Query cost guardrails.
+//
+// Reports scan calls and the ledger, so an unbounded request ("five years by day")
+// can hurt the database for everyone. Every report:
+// - rejects ranges longer than Limits.MaxSpan with ErrRangeTooLarge;
+// - downsamples buckets (day -> week -> month) until at most Limits.MaxBuckets
+//   remain, reporting the granularity actually used; a range that is still too
+//   fine at month granularity is rejected;
+// - counts against a per-workspace limit of Limits.MaxConcurrent reports in
+//   flight (per process), failing fast with ErrTooManyReports.
+//
+// A zero limit disables that guardrail.
+
+var (
+	// ErrRangeTooLarge also matches ErrInvalidRequest.
+	ErrRangeTooLarge  = fmt.Errorf("%w: range too large", ErrInvalidRequest)
+	ErrTooManyReports = errors.New("reporting: too many concurrent reports for workspace")
+)
+
+// Limits bounds what a single report may scan.
+type Limits struct {
+	MaxSpan       time.Duration
+	MaxBuckets    int
+	MaxConcurrent int
+}
+
+// DefaultLimits allow two years of daily data (downsampled to weeks past
+// MaxBuckets) and two reports in flight per workspace.
+var DefaultLimits = Limits{
+	MaxSpan:       731 * 24 * time.Hour,
+	MaxBuckets:    400,
+	MaxConcurrent: 2,
+}
+
+// checkSpan enforces Limits.MaxSpan on a resolved range.
+func (s *Service) checkSpan(rng TimeRange) error {
+	if s.Limits.MaxSpan > 0 && rng.To.Sub(rng.From) > s.Limits.MaxSpan {
+		return fmt.Errorf("%w: at most %s", ErrRangeTooLarge, s.Limits.MaxSpan)
+	}
+	return nil
+}
+
+// downsample returns the finest granularity, starting at g, whose bucket count
+// over rng fits Limits.MaxBuckets.
+func (s *Service) downsample(rng TimeRange, loc *time.Location, g Granularity) (Granularity, error) {
+	if g == GranularityNone || s.Limits.MaxBuckets <= 0 {
+		return g, nil
+	}
+	for _, next := range []Granularity{GranularityDay, GranularityWeek, GranularityMonth} {
+		if finer(next, g) {
+			continue
+		}
+		if bucketCount(rng, loc, next) <= s.Limits.MaxBuckets {
+			return next, nil
+		}
+	}
+	return "", fmt.Errorf("%w: more than %d buckets", ErrRangeTooLarge, s.Limits.MaxBuckets)
+}
+
+// finer reports whether buckets of a are shorter than buckets of b.
+func finer(a, b Granularity) bool {
+	rank := map[Granularity]int{GranularityDay: 1, GranularityWeek: 2, GranularityMonth: 3}
+	return rank[a] < rank[b]
+}
+
+// bucketCount is the number of buckets of granularity g that rng touches.
+func bucketCount(rng TimeRange, loc *time.Location, g Granularity) int {
+	n := 0
+	for t := bucketStart(rng.From, loc, g); t.Before(rng.To); t = nextBucket(t, g) {
+		n++
+		if n > 100000 {
+			break
+		}
+	}
+	return n
+}
+
+func nextBucket(t time.Time, g Granularity) time.Time {
+	switch g {
+	case GranularityWeek:
+		return t.AddDate(0, 0, 7)
+	case GranularityMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// reportLimiter counts reports in flight per workspace.
+type reportLimiter struct {
+	mu     sync.Mutex
+	active map[string]int
+}
+
+// acquire reserves a report slot for the workspace; call the returned func when
+// the report is done.
+func (s *Service) acquire(workspaceID string) (func(), error) {
+	max := s.Limits.MaxConcurrent
+	if max <= 0 {
+		return func() {}, nil
+	}
+	if workspaceID == "" {
+		workspaceID = "*" // cross-workspace internal reports share one slot pool
+	}
+	l := &s.limiter
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.active == nil {
+		l.active = map[string]int{}
+	}
+	if l.active[workspaceID] >= max {
+		return nil, ErrTooManyReports
+	}
+	l.active[workspaceID]++
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.active[workspaceID]--; l.active[workspaceID] <= 0 {
+			delete(l.active, workspaceID)
+		}
+	}, nil
+}
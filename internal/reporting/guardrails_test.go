@@ -0,0 +1,73 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGuardrails_RejectsLongRanges(t *testing.T) {
+	svc := NewService(NewMemoryRepo())
+	_, err := svc.CallsSummary(context.Background(), CallsSummaryRequest{
+		WorkspaceID: "w",
+		Granularity: GranularityDay,
+		Range:       TimeRange{FromDate: "2020-01-01", ToDate: "2025-01-01"},
+	})
+	if !errors.Is(err, ErrRangeTooLarge) || !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrRangeTooLarge, got %v", err)
+	}
+}
+
+func TestGuardrails_DownsamplesBuckets(t *testing.T) {
+	svc := NewService(NewMemoryRepo())
+	svc.Limits.MaxBuckets = 20
+	ctx := context.Background()
+
+	cases := []struct {
+		rng  TimeRange
+		g    Granularity
+		want Granularity
+	}{
+		{TimeRange{FromDate: "2024-01-01", ToDate: "2024-01-10"}, GranularityDay, GranularityDay},
+		{TimeRange{FromDate: "2024-01-01", ToDate: "2024-03-01"}, GranularityDay, GranularityWeek},
+		{TimeRange{FromDate: "2024-01-01", ToDate: "2024-12-01"}, GranularityDay, GranularityMonth},
+		{TimeRange{FromDate: "2024-01-01", ToDate: "2024-01-10"}, GranularityMonth, GranularityMonth},
+		{TimeRange{FromDate: "2024-01-01", ToDate: "2024-12-01"}, GranularityNone, GranularityNone},
+	}
+	for _, tc := range cases {
+		out, err := svc.SpendSummary(ctx, SpendSummaryRequest{WorkspaceID: "w", Granularity: tc.g, Range: tc.rng})
+		if err != nil {
+			t.Fatalf("%v %s: unexpected err: %v", tc.rng, tc.g, err)
+		}
+		if out.Granularity != tc.want {
+			t.Fatalf("%v %s: expected %q, got %q", tc.rng, tc.g, tc.want, out.Granularity)
+		}
+	}
+
+	_, err := svc.SpendSummary(ctx, SpendSummaryRequest{WorkspaceID: "w", Granularity: GranularityDay, Range: TimeRange{FromDate: "2023-01-01", ToDate: "2025-01-01"}})
+	if !errors.Is(err, ErrRangeTooLarge) {
+		t.Fatalf("expected ErrRangeTooLarge past month granularity, got %v", err)
+	}
+}
+
+func TestGuardrails_LimitsConcurrentReportsPerWorkspace(t *testing.T) {
+	svc := NewService(NewMemoryRepo())
+	svc.Limits.MaxConcurrent = 1
+
+	release, err := svc.acquire("w1")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	rng := TimeRange{From: time.Unix(1700000000, 0), To: time.Unix(1700003600, 0)}
+	if _, err := svc.CallsSummary(context.Background(), CallsSummaryRequest{WorkspaceID: "w1", Range: rng}); !errors.Is(err, ErrTooManyReports) {
+		t.Fatalf("expected ErrTooManyReports, got %v", err)
+	}
+	if _, err := svc.CallsSummary(context.Background(), CallsSummaryRequest{WorkspaceID: "w2", Range: rng}); err != nil {
+		t.Fatalf("expected other workspaces unaffected, got %v", err)
+	}
+	release()
+	if _, err := svc.CallsSummary(context.Background(), CallsSummaryRequest{WorkspaceID: "w1", Range: rng}); err != nil {
+		t.Fatalf("expected slot after release, got %v", err)
+	}
+}
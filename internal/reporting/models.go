@@ -7,8 +7,25 @@ import "time"
 type TimeRange struct {
 	From time.Time `json:"from"`
 	To   time.Time `json:"to"`
+
+	// FromDate/ToDate (YYYY-MM-DD, ToDate inclusive) are an alternative to From/To.
+	// They are interpreted as local calendar days in the request timezone.
+	FromDate string `json:"from_date,omitempty"`
+	ToDate   string `json:"to_date,omitempty"`
 }
 
+// Granularity selects optional time buckets in a summary.
+type Granularity string
+
+const (
+	GranularityNone Granularity = ""
+	GranularityDay  Granularity = "day"
+	// GranularityWeek buckets start on Monday 00:00 local time.
+	GranularityWeek Granularity = "week"
+	// GranularityMonth buckets start on the 1st at 00:00 local time.
+	GranularityMonth Granularity = "month"
+)
+
 // CallsSummaryRequest requests aggregated call metrics.
 // Workspace isolation: WorkspaceID is required.
 
@@ -16,6 +33,10 @@ type CallsSummaryRequest struct {
 	WorkspaceID string    `json:"workspace_id"`
 	Range       TimeRange `json:"range"`
 	CampaignID  string    `json:"campaign_id,omitempty"`
+
+	// Timezone is an IANA name; empty uses the workspace setting (UTC if unset).
+	Timezone    string      `json:"timezone,omitempty"`
+	Granularity Granularity `json:"granularity,omitempty"`
 }
 
 type CallsSummary struct {
@@ -34,6 +55,21 @@ type CallsSummary struct {
 	AverageDurationSeconds int `json:"average_duration_seconds"`
 
 	RecordedCalls int `json:"recorded_calls"`
+
+	Timezone string `json:"timezone"`
+	// Granularity is the bucket size used, coarser than requested when the range
+	// would exceed the bucket limit (see guardrails.go).
+	Granularity Granularity   `json:"granularity,omitempty"`
+	Buckets     []CallsBucket `json:"buckets,omitempty"`
+}
+
+// CallsBucket is one local day/week within a calls summary.
+type CallsBucket struct {
+	Start          time.Time `json:"start"`
+	TotalCalls     int       `json:"total_calls"`
+	CompletedCalls int       `json:"completed_calls"`
+
+	TotalDurationSeconds int `json:"total_duration_seconds"`
 }
 
 // SpendSummaryRequest requests aggregated spend metrics.
@@ -44,6 +80,9 @@ type SpendSummaryRequest struct {
 	Range       TimeRange `json:"range"`
 	WalletID    string    `json:"wallet_id,omitempty"`
 	Currency    string    `json:"currency,omitempty"`
+
+	Timezone    string      `json:"timezone,omitempty"`
+	Granularity Granularity `json:"granularity,omitempty"`
 }
 
 type SpendSummary struct {
@@ -57,6 +96,19 @@ type SpendSummary struct {
 
 	UsageDebitMinor int64 `json:"usage_debit_minor"`
 	AdminAdjustMinor int64 `json:"admin_adjust_minor"`
+	// ContractTrueUpMinor is committed-use shortfall charged in the range.
+	ContractTrueUpMinor int64 `json:"contract_true_up_minor"`
+
+	Timezone    string        `json:"timezone"`
+	Granularity Granularity   `json:"granularity,omitempty"`
+	Buckets     []SpendBucket `json:"buckets,omitempty"`
+}
+
+// SpendBucket is one local day/week within a spend summary.
+type SpendBucket struct {
+	Start            time.Time `json:"start"`
+	TotalDebitMinor  int64     `json:"total_debit_minor"`
+	TotalCreditMinor int64     `json:"total_credit_minor"`
 }
 
 // ConversionMetricsRequest captures simple campaign conversion metrics.
@@ -66,6 +118,7 @@ type ConversionMetricsRequest struct {
 	WorkspaceID string    `json:"workspace_id"`
 	Range       TimeRange `json:"range"`
 	CampaignID  string    `json:"campaign_id"`
+	Timezone    string    `json:"timezone,omitempty"`
 }
 
 type ConversionMetrics struct {
@@ -79,3 +132,74 @@ type ConversionMetrics struct {
 	ConnectionRate float64 `json:"connection_rate"`
 	ConversionRate float64 `json:"conversion_rate"`
 }
+
+// OverrideUsageRequest requests the internal override usage report.
+//
+// INTERNAL ONLY (super_admin): this is the one report that may span workspaces.
+// An empty WorkspaceID means all workspaces.
+type OverrideUsageRequest struct {
+	WorkspaceID string    `json:"workspace_id,omitempty"`
+	Range       TimeRange `json:"range"`
+	Timezone    string    `json:"timezone,omitempty"`
+}
+
+type OverrideUsageReport struct {
+	Range          TimeRange          `json:"range"`
+	TotalOverrides int                `json:"total_overrides"`
+	Workspaces     []OverrideUsageRow `json:"workspaces"`
+}
+
+// OverrideUsageRow summarizes silent override usage for one workspace.
+type OverrideUsageRow struct {
+	WorkspaceID string `json:"workspace_id"`
+
+	// Applications is the number of times an override was applied.
+	Applications      int `json:"applications"`
+	AffectedCalls     int `json:"affected_calls"`
+	DistinctOverrides int `json:"distinct_overrides"`
+
+	Admins []OverrideAdminUsage `json:"admins"`
+}
+
+type OverrideAdminUsage struct {
+	AdminUserID  string `json:"admin_user_id"`
+	Applications int    `json:"applications"`
+}
+
+// AttributionRequest requests calls, conversions and spend per marketing source.
+// Calls are grouped by the source/medium/campaign of the visit that was shown
+// their tracking number (see internal/tracking); calls without one are reported
+// under "(unattributed)".
+
+type AttributionRequest struct {
+	WorkspaceID string    `json:"workspace_id"`
+	Range       TimeRange `json:"range"`
+	CampaignID  string    `json:"campaign_id,omitempty"`
+	Currency    string    `json:"currency,omitempty"`
+	Timezone    string    `json:"timezone,omitempty"`
+}
+
+type AttributionReport struct {
+	WorkspaceID string           `json:"workspace_id"`
+	Currency    string           `json:"currency"`
+	Timezone    string           `json:"timezone"`
+	Rows        []AttributionRow `json:"rows"`
+}
+
+// AttributionRow is one source/medium/campaign. SpendMinor is the usage debited
+// for its calls, so SpendMinor/Conversions is the cost per conversion.
+type AttributionRow struct {
+	Source   string `json:"source"`
+	Medium   string `json:"medium"`
+	Campaign string `json:"campaign,omitempty"`
+
+	Calls                int   `json:"calls"`
+	CompletedCalls       int   `json:"completed_calls"`
+	Conversions          int   `json:"conversions"`
+	TotalDurationSeconds int   `json:"total_duration_seconds"`
+	SpendMinor           int64 `json:"spend_minor"`
+
+	// GCLIDCalls/FBCLIDCalls count calls whose visit carried an ad click id.
+	GCLIDCalls  int `json:"gclid_calls"`
+	FBCLIDCalls int `json:"fbclid_calls"`
+}
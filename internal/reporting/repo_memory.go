@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"telecom-platform/internal/audit"
 	"telecom-platform/internal/calls"
 	"telecom-platform/internal/wallet"
 )
@@ -16,8 +17,9 @@ import (
 type MemoryRepo struct {
 	mu sync.Mutex
 
-	Calls   []calls.Call
-	Ledgers []wallet.WalletLedger
+	Calls       []calls.Call
+	Ledgers     []wallet.WalletLedger
+	AuditEvents []audit.Event
 
 	Conversions map[string]int // key: workspace_id|campaign_id
 }
@@ -83,3 +85,22 @@ func (r *MemoryRepo) ListConversions(ctx context.Context, workspaceID string, fr
 	defer r.mu.Unlock()
 	return r.Conversions[workspaceID+"|"+campaignID], nil
 }
+
+func (r *MemoryRepo) ListAuditEvents(ctx context.Context, workspaceID string, from, to time.Time, eventType audit.EventType) ([]audit.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]audit.Event, 0)
+	for _, e := range r.AuditEvents {
+		if workspaceID != "" && e.WorkspaceID != workspaceID {
+			continue
+		}
+		if eventType != "" && e.Type != eventType {
+			continue
+		}
+		if e.CreatedAt.Before(from) || !e.CreatedAt.Before(to) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
@@ -3,8 +3,10 @@ package reporting
 import (
 	"context"
 	"errors"
+	"sort"
 	"time"
 
+	"telecom-platform/internal/audit"
 	"telecom-platform/internal/calls"
 	"telecom-platform/internal/wallet"
 )
@@ -24,32 +26,84 @@ type Repository interface {
 	// Campaign conversions will likely come from a dedicated immutable events table.
 	// For now this is an optional hook.
 	ListConversions(ctx context.Context, workspaceID string, from, to time.Time, campaignID string) (conversions int, err error)
+
+	// ListAuditEvents reads the internal audit log. An empty workspaceID means all
+	// workspaces and is only used by internal (super_admin) reports.
+	ListAuditEvents(ctx context.Context, workspaceID string, from, to time.Time, eventType audit.EventType) ([]audit.Event, error)
 }
 
 type Service struct {
-	repo Repository
+	repo  Repository
+	clock func() time.Time
+
+	// Settings supplies the default timezone per workspace (optional; UTC when nil).
+	Settings WorkspaceSettings
+
+	// Attribution and Conversions feed the attribution report (both optional).
+	Attribution AttributionSource
+	Conversions ConversionSource
+
+	// ExcludeHoldFromTalkTime leaves hold time out of agent talk time
+	// (see agent_talk_time.go).
+	ExcludeHoldFromTalkTime bool
+
+	// Cache serves precomputed dashboard aggregates (optional; see dashboard.go).
+	Cache DashboardCache
+
+	// Live holds real-time wallboard counters (optional; see wallboard.go).
+	Live WallboardState
+
+	// Limits are the query cost guardrails (see guardrails.go).
+	Limits  Limits
+	limiter reportLimiter
 }
 
-func NewService(repo Repository) *Service { return &Service{repo: repo} }
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo, clock: time.Now, Limits: DefaultLimits}
+}
 
 func (s *Service) CallsSummary(ctx context.Context, req CallsSummaryRequest) (CallsSummary, error) {
-	if req.WorkspaceID == "" {
-		return CallsSummary{}, ErrInvalidRequest
-	}
-	if req.Range.From.IsZero() || req.Range.To.IsZero() || !req.Range.To.After(req.Range.From) {
+	if req.WorkspaceID == "" || !validGranularity(req.Granularity) {
 		return CallsSummary{}, ErrInvalidRequest
 	}
 	if s.repo == nil {
 		return CallsSummary{}, errors.New("reporting: repository not configured")
 	}
+	release, err := s.acquire(req.WorkspaceID)
+	if err != nil {
+		return CallsSummary{}, err
+	}
+	defer release()
+	rng, loc, err := s.resolveRange(ctx, req.WorkspaceID, req.Timezone, req.Range)
+	if err != nil {
+		return CallsSummary{}, err
+	}
+	g, err := s.downsample(rng, loc, req.Granularity)
+	if err != nil {
+		return CallsSummary{}, err
+	}
 
-	rows, err := s.repo.ListCalls(ctx, req.WorkspaceID, req.Range.From, req.Range.To, req.CampaignID)
+	rows, err := s.repo.ListCalls(ctx, req.WorkspaceID, rng.From, rng.To, req.CampaignID)
 	if err != nil {
 		return CallsSummary{}, err
 	}
 
-	out := CallsSummary{WorkspaceID: req.WorkspaceID, CampaignID: req.CampaignID}
+	out := CallsSummary{WorkspaceID: req.WorkspaceID, CampaignID: req.CampaignID, Timezone: loc.String(), Granularity: g}
+	buckets := map[time.Time]*CallsBucket{}
 	for _, c := range rows {
+		if g != GranularityNone {
+			start := bucketStart(c.CreatedAt, loc, g)
+			b, ok := buckets[start]
+			if !ok {
+				b = &CallsBucket{Start: start}
+				buckets[start] = b
+			}
+			b.TotalCalls++
+			b.TotalDurationSeconds += c.DurationSeconds
+			if c.Status == calls.CallStatusCompleted {
+				b.CompletedCalls++
+			}
+		}
 		out.TotalCalls++
 		out.TotalDurationSeconds += c.DurationSeconds
 		if c.RecordingURL != "" {
@@ -75,26 +129,41 @@ func (s *Service) CallsSummary(ctx context.Context, req CallsSummaryRequest) (Ca
 	if out.TotalCalls > 0 {
 		out.AverageDurationSeconds = out.TotalDurationSeconds / out.TotalCalls
 	}
+	for _, b := range buckets {
+		out.Buckets = append(out.Buckets, *b)
+	}
+	sort.Slice(out.Buckets, func(i, j int) bool { return out.Buckets[i].Start.Before(out.Buckets[j].Start) })
 	return out, nil
 }
 
 func (s *Service) SpendSummary(ctx context.Context, req SpendSummaryRequest) (SpendSummary, error) {
-	if req.WorkspaceID == "" {
-		return SpendSummary{}, ErrInvalidRequest
-	}
-	if req.Range.From.IsZero() || req.Range.To.IsZero() || !req.Range.To.After(req.Range.From) {
+	if req.WorkspaceID == "" || !validGranularity(req.Granularity) {
 		return SpendSummary{}, ErrInvalidRequest
 	}
 	if s.repo == nil {
 		return SpendSummary{}, errors.New("reporting: repository not configured")
 	}
+	release, err := s.acquire(req.WorkspaceID)
+	if err != nil {
+		return SpendSummary{}, err
+	}
+	defer release()
+	rng, loc, err := s.resolveRange(ctx, req.WorkspaceID, req.Timezone, req.Range)
+	if err != nil {
+		return SpendSummary{}, err
+	}
+	g, err := s.downsample(rng, loc, req.Granularity)
+	if err != nil {
+		return SpendSummary{}, err
+	}
 
-	ledgers, err := s.repo.ListWalletLedger(ctx, req.WorkspaceID, req.Range.From, req.Range.To, req.WalletID)
+	ledgers, err := s.repo.ListWalletLedger(ctx, req.WorkspaceID, rng.From, rng.To, req.WalletID)
 	if err != nil {
 		return SpendSummary{}, err
 	}
 
-	out := SpendSummary{WorkspaceID: req.WorkspaceID, WalletID: req.WalletID, Currency: req.Currency}
+	out := SpendSummary{WorkspaceID: req.WorkspaceID, WalletID: req.WalletID, Currency: req.Currency, Timezone: loc.String(), Granularity: g}
+	buckets := map[time.Time]*SpendBucket{}
 	for _, l := range ledgers {
 		// currency normalization: if request specified currency, filter; else populate from first row.
 		if out.Currency == "" {
@@ -109,10 +178,31 @@ func (s *Service) SpendSummary(ctx context.Context, req SpendSummaryRequest) (Sp
 		} else {
 			out.TotalDebitMinor += -l.AmountMinor
 		}
+		if g != GranularityNone {
+			start := bucketStart(l.CreatedAt, loc, g)
+			b, ok := buckets[start]
+			if !ok {
+				b = &SpendBucket{Start: start}
+				buckets[start] = b
+			}
+			if l.AmountMinor > 0 {
+				b.TotalCreditMinor += l.AmountMinor
+			} else {
+				b.TotalDebitMinor += -l.AmountMinor
+			}
+		}
 
-		// naive categorization: admin_manual_credit external ref is an admin adjustment; others count as usage.
+		// naive categorization: admin_manual_credit external ref is an admin adjustment,
+		// contract_true_up is a commitment charge; others count as usage.
 		if l.ExternalRef == "admin_manual_credit" {
 			out.AdminAdjustMinor += l.AmountMinor
+		} else if l.ExternalRef == "contract_true_up" {
+			out.ContractTrueUpMinor += -l.AmountMinor
+		} else if l.Type == wallet.LedgerEntryTypeReversal {
+			// A positive reversal refunds usage; a negative one claws back a top-up.
+			if l.AmountMinor > 0 {
+				out.UsageDebitMinor -= l.AmountMinor
+			}
 		} else {
 			if l.AmountMinor < 0 {
 				out.UsageDebitMinor += -l.AmountMinor
@@ -123,6 +213,10 @@ func (s *Service) SpendSummary(ctx context.Context, req SpendSummaryRequest) (Sp
 	if out.Currency == "" {
 		out.Currency = "UNKNOWN"
 	}
+	for _, b := range buckets {
+		out.Buckets = append(out.Buckets, *b)
+	}
+	sort.Slice(out.Buckets, func(i, j int) bool { return out.Buckets[i].Start.Before(out.Buckets[j].Start) })
 	return out, nil
 }
 
@@ -130,18 +224,24 @@ func (s *Service) ConversionMetrics(ctx context.Context, req ConversionMetricsRe
 	if req.WorkspaceID == "" || req.CampaignID == "" {
 		return ConversionMetrics{}, ErrInvalidRequest
 	}
-	if req.Range.From.IsZero() || req.Range.To.IsZero() || !req.Range.To.After(req.Range.From) {
-		return ConversionMetrics{}, ErrInvalidRequest
-	}
 	if s.repo == nil {
 		return ConversionMetrics{}, errors.New("reporting: repository not configured")
 	}
+	release, err := s.acquire(req.WorkspaceID)
+	if err != nil {
+		return ConversionMetrics{}, err
+	}
+	defer release()
+	rng, _, err := s.resolveRange(ctx, req.WorkspaceID, req.Timezone, req.Range)
+	if err != nil {
+		return ConversionMetrics{}, err
+	}
 
-	callsRows, err := s.repo.ListCalls(ctx, req.WorkspaceID, req.Range.From, req.Range.To, req.CampaignID)
+	callsRows, err := s.repo.ListCalls(ctx, req.WorkspaceID, rng.From, rng.To, req.CampaignID)
 	if err != nil {
 		return ConversionMetrics{}, err
 	}
-	conv, err := s.repo.ListConversions(ctx, req.WorkspaceID, req.Range.From, req.Range.To, req.CampaignID)
+	conv, err := s.repo.ListConversions(ctx, req.WorkspaceID, rng.From, rng.To, req.CampaignID)
 	if err != nil {
 		return ConversionMetrics{}, err
 	}
@@ -161,3 +261,74 @@ func (s *Service) ConversionMetrics(ctx context.Context, req ConversionMetricsRe
 	}
 	return out, nil
 }
+
+// OverrideUsage summarizes silent override usage per workspace from audit events.
+// INTERNAL ONLY: callers must restrict this to super_admin.
+func (s *Service) OverrideUsage(ctx context.Context, req OverrideUsageRequest) (OverrideUsageReport, error) {
+	if s.repo == nil {
+		return OverrideUsageReport{}, errors.New("reporting: repository not configured")
+	}
+	release, err := s.acquire(req.WorkspaceID)
+	if err != nil {
+		return OverrideUsageReport{}, err
+	}
+	defer release()
+	rng, _, err := s.resolveRange(ctx, req.WorkspaceID, req.Timezone, req.Range)
+	if err != nil {
+		return OverrideUsageReport{}, err
+	}
+
+	events, err := s.repo.ListAuditEvents(ctx, req.WorkspaceID, rng.From, rng.To, audit.EventTypeOverride)
+	if err != nil {
+		return OverrideUsageReport{}, err
+	}
+
+	type acc struct {
+		row       OverrideUsageRow
+		calls     map[string]bool
+		overrides map[string]bool
+		admins    map[string]int
+	}
+	byWorkspace := map[string]*acc{}
+	for _, e := range events {
+		if e.Type != audit.EventTypeOverride {
+			continue
+		}
+		a, ok := byWorkspace[e.WorkspaceID]
+		if !ok {
+			a = &acc{row: OverrideUsageRow{WorkspaceID: e.WorkspaceID}, calls: map[string]bool{}, overrides: map[string]bool{}, admins: map[string]int{}}
+			byWorkspace[e.WorkspaceID] = a
+		}
+		a.row.Applications++
+		if e.CallID != "" {
+			a.calls[e.CallID] = true
+		}
+		if e.OverrideID != "" {
+			a.overrides[e.OverrideID] = true
+		}
+		admin := e.ActorUserID
+		if admin == "" {
+			admin = "unknown"
+		}
+		a.admins[admin]++
+	}
+
+	out := OverrideUsageReport{Range: rng, Workspaces: make([]OverrideUsageRow, 0, len(byWorkspace))}
+	for _, a := range byWorkspace {
+		a.row.AffectedCalls = len(a.calls)
+		a.row.DistinctOverrides = len(a.overrides)
+		for id, n := range a.admins {
+			a.row.Admins = append(a.row.Admins, OverrideAdminUsage{AdminUserID: id, Applications: n})
+		}
+		sort.Slice(a.row.Admins, func(i, j int) bool {
+			if a.row.Admins[i].Applications != a.row.Admins[j].Applications {
+				return a.row.Admins[i].Applications > a.row.Admins[j].Applications
+			}
+			return a.row.Admins[i].AdminUserID < a.row.Admins[j].AdminUserID
+		})
+		out.TotalOverrides += a.row.Applications
+		out.Workspaces = append(out.Workspaces, a.row)
+	}
+	sort.Slice(out.Workspaces, func(i, j int) bool { return out.Workspaces[i].WorkspaceID < out.Workspaces[j].WorkspaceID })
+	return out, nil
+}
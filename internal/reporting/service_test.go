@@ -5,7 +5,9 @@ import (
 	"testing"
 	"time"
 
+	"telecom-platform/internal/audit"
 	"telecom-platform/internal/calls"
+	"telecom-platform/internal/tracking"
 	"telecom-platform/internal/wallet"
 )
 
@@ -74,3 +76,148 @@ func TestReporting_ConversionMetrics(t *testing.T) {
 		t.Fatalf("expected non-zero rates")
 	}
 }
+
+func TestReporting_OverrideUsageAggregatesPerWorkspace(t *testing.T) {
+	repo := NewMemoryRepo()
+	now := time.Unix(1700000000, 0).UTC()
+	repo.AuditEvents = []audit.Event{
+		{WorkspaceID: "w1", Type: audit.EventTypeOverride, ActorUserID: "op1", OverrideID: "o1", CallID: "pc1", CreatedAt: now},
+		{WorkspaceID: "w1", Type: audit.EventTypeOverride, ActorUserID: "op1", OverrideID: "o1", CallID: "pc2", CreatedAt: now},
+		{WorkspaceID: "w1", Type: audit.EventTypeOverride, ActorUserID: "op2", OverrideID: "o2", CallID: "pc2", CreatedAt: now},
+		{WorkspaceID: "w2", Type: audit.EventTypeOverride, ActorUserID: "op1", OverrideID: "o3", CallID: "pc9", CreatedAt: now},
+		{WorkspaceID: "w1", Type: audit.EventTypeAdminAction, ActorUserID: "op1", CreatedAt: now},
+		{WorkspaceID: "w1", Type: audit.EventTypeOverride, ActorUserID: "op1", CreatedAt: now.Add(-48 * time.Hour)},
+	}
+	svc := NewService(repo)
+
+	out, err := svc.OverrideUsage(context.Background(), OverrideUsageRequest{Range: TimeRange{From: now.Add(-time.Hour), To: now.Add(time.Hour)}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if out.TotalOverrides != 4 || len(out.Workspaces) != 2 {
+		t.Fatalf("unexpected totals: %+v", out)
+	}
+	w1 := out.Workspaces[0]
+	if w1.WorkspaceID != "w1" || w1.Applications != 3 || w1.AffectedCalls != 2 || w1.DistinctOverrides != 2 {
+		t.Fatalf("unexpected w1 row: %+v", w1)
+	}
+	if w1.Admins[0].AdminUserID != "op1" || w1.Admins[0].Applications != 2 {
+		t.Fatalf("expected op1 first, got %+v", w1.Admins)
+	}
+}
+
+func TestReporting_DateRangeUsesWorkspaceTimezone(t *testing.T) {
+	repo := NewMemoryRepo()
+	// 2023-11-15 05:30 UTC is still 2023-11-15 00:30 in New York; 04:30 UTC is the 14th.
+	repo.Calls = []calls.Call{
+		{CallID: "c1", WorkspaceID: "w", Status: calls.CallStatusCompleted, DurationSeconds: 10, CreatedAt: time.Date(2023, 11, 15, 4, 30, 0, 0, time.UTC)},
+		{CallID: "c2", WorkspaceID: "w", Status: calls.CallStatusCompleted, DurationSeconds: 20, CreatedAt: time.Date(2023, 11, 15, 5, 30, 0, 0, time.UTC)},
+		{CallID: "c3", WorkspaceID: "w", Status: calls.CallStatusFailed, CreatedAt: time.Date(2023, 11, 16, 4, 59, 0, 0, time.UTC)},
+	}
+	svc := NewService(repo)
+	svc.Settings = MemorySettings{Timezones: map[string]string{"w": "America/New_York"}}
+
+	out, err := svc.CallsSummary(context.Background(), CallsSummaryRequest{WorkspaceID: "w", Range: TimeRange{FromDate: "2023-11-15", ToDate: "2023-11-15"}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if out.TotalCalls != 2 || out.Timezone != "America/New_York" {
+		t.Fatalf("unexpected summary: %+v", out)
+	}
+
+	// Explicit request timezone overrides the workspace default.
+	out, err = svc.CallsSummary(context.Background(), CallsSummaryRequest{WorkspaceID: "w", Timezone: "UTC", Range: TimeRange{FromDate: "2023-11-15", ToDate: "2023-11-15"}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if out.TotalCalls != 2 || out.Timezone != "UTC" {
+		t.Fatalf("unexpected UTC summary: %+v", out)
+	}
+
+	if _, err := svc.CallsSummary(context.Background(), CallsSummaryRequest{WorkspaceID: "w", Timezone: "Mars/Olympus", Range: TimeRange{FromDate: "2023-11-15", ToDate: "2023-11-15"}}); err != ErrInvalidRequest {
+		t.Fatalf("expected ErrInvalidRequest for bad timezone, got %v", err)
+	}
+}
+
+func TestReporting_BucketsAlignToLocalDayAndWeek(t *testing.T) {
+	repo := NewMemoryRepo()
+	loc, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	repo.Ledgers = []wallet.WalletLedger{
+		// 2023-11-12 (Sunday) 23:00 IST and 2023-11-13 (Monday) 01:00 IST.
+		{ID: "l1", WorkspaceID: "w", WalletID: "wa", AmountMinor: -100, CreatedAt: time.Date(2023, 11, 12, 23, 0, 0, 0, loc)},
+		{ID: "l2", WorkspaceID: "w", WalletID: "wa", AmountMinor: -40, CreatedAt: time.Date(2023, 11, 13, 1, 0, 0, 0, loc)},
+		{ID: "l3", WorkspaceID: "w", WalletID: "wa", AmountMinor: 500, CreatedAt: time.Date(2023, 11, 13, 9, 0, 0, 0, loc)},
+	}
+	svc := NewService(repo)
+	rng := TimeRange{FromDate: "2023-11-10", ToDate: "2023-11-19"}
+
+	day, err := svc.SpendSummary(context.Background(), SpendSummaryRequest{WorkspaceID: "w", Timezone: "Asia/Kolkata", Granularity: GranularityDay, Range: rng})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(day.Buckets) != 2 || day.Buckets[0].TotalDebitMinor != 100 || day.Buckets[1].TotalDebitMinor != 40 || day.Buckets[1].TotalCreditMinor != 500 {
+		t.Fatalf("unexpected day buckets: %+v", day.Buckets)
+	}
+	if !day.Buckets[1].Start.Equal(time.Date(2023, 11, 13, 0, 0, 0, 0, loc)) {
+		t.Fatalf("expected bucket at local midnight, got %v", day.Buckets[1].Start)
+	}
+
+	week, err := svc.SpendSummary(context.Background(), SpendSummaryRequest{WorkspaceID: "w", Timezone: "Asia/Kolkata", Granularity: GranularityWeek, Range: rng})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(week.Buckets) != 2 || !week.Buckets[0].Start.Equal(time.Date(2023, 11, 6, 0, 0, 0, 0, loc)) {
+		t.Fatalf("unexpected week buckets: %+v", week.Buckets)
+	}
+}
+
+type staticAttributions map[string]tracking.Attribution
+
+func (s staticAttributions) CallAttributions(ctx context.Context, workspaceID string, callIDs []string) (map[string]tracking.Attribution, error) {
+	return s, nil
+}
+
+type staticConversions map[string]bool
+
+func (s staticConversions) ConvertedCalls(ctx context.Context, workspaceID string, callIDs []string) (map[string]bool, error) {
+	return s, nil
+}
+
+func TestReporting_AttributionJoinsCallsConversionsAndSpend(t *testing.T) {
+	repo := NewMemoryRepo()
+	now := time.Unix(1700000000, 0).UTC()
+	repo.Calls = []calls.Call{
+		{CallID: "c1", WorkspaceID: "w", Status: calls.CallStatusCompleted, DurationSeconds: 120, CreatedAt: now},
+		{CallID: "c2", WorkspaceID: "w", Status: calls.CallStatusCompleted, DurationSeconds: 30, CreatedAt: now},
+		{CallID: "c3", WorkspaceID: "w", Status: calls.CallStatusNoAnswer, CreatedAt: now},
+		{CallID: "c4", WorkspaceID: "w", Status: calls.CallStatusCompleted, DurationSeconds: 60, CreatedAt: now},
+	}
+	repo.Ledgers = []wallet.WalletLedger{
+		{ID: "l1", WorkspaceID: "w", Currency: "USD", AmountMinor: -200, ExternalRef: "call:c1", CreatedAt: now.Add(2 * time.Minute)},
+		// Billed after the range ends; still counted.
+		{ID: "l2", WorkspaceID: "w", Currency: "USD", AmountMinor: -50, ExternalRef: "call:c2", CreatedAt: now.Add(90 * time.Minute)},
+		{ID: "l3", WorkspaceID: "w", Currency: "USD", AmountMinor: 1000, ExternalRef: "topup", CreatedAt: now},
+	}
+	svc := NewService(repo)
+	google := tracking.Attribution{Source: "google", Medium: "cpc", Campaign: "spring", GCLID: "g1"}
+	svc.Attribution = staticAttributions{"c1": google, "c2": google, "c3": {Source: "bing", Medium: "cpc"}}
+	svc.Conversions = staticConversions{"c1": true}
+
+	out, err := svc.AttributionReport(context.Background(), AttributionRequest{WorkspaceID: "w", Range: TimeRange{From: now.Add(-time.Hour), To: now.Add(time.Hour)}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if out.Currency != "USD" || len(out.Rows) != 3 {
+		t.Fatalf("unexpected report: %+v", out)
+	}
+	top := out.Rows[0]
+	if top.Source != "google" || top.Campaign != "spring" || top.Calls != 2 || top.Conversions != 1 || top.SpendMinor != 250 || top.GCLIDCalls != 2 || top.TotalDurationSeconds != 150 {
+		t.Fatalf("unexpected google row: %+v", top)
+	}
+	if out.Rows[1].Source != "(unattributed)" || out.Rows[1].Calls != 1 || out.Rows[2].Source != "bing" || out.Rows[2].CompletedCalls != 0 {
+		t.Fatalf("unexpected rows: %+v", out.Rows)
+	}
+}
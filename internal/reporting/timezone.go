@@ -0,0 +1,95 @@
+package reporting
+
+import (
+	"context"
+	"time"
+)
+
+// Timezone-aware boundaries.
+//
+// Reports align with the tenant's local business day:
+// - Request Timezone wins; otherwise the workspace setting; otherwise UTC.
+// - FromDate/ToDate are local calendar days; To is exclusive at the next local midnight.
+// - Buckets start at local midnight (day), local Monday midnight (week) or the
+//   local 1st of the month, so DST transitions yield 23h/25h days rather than
+//   shifted boundaries.
+
+// WorkspaceSettings supplies per-workspace reporting defaults.
+type WorkspaceSettings interface {
+	// WorkspaceTimezone returns the IANA timezone name, or "" if unset.
+	WorkspaceTimezone(ctx context.Context, workspaceID string) (string, error)
+}
+
+const dateLayout = "2006-01-02"
+
+// resolveRange picks the location and converts the range into absolute instants.
+func (s *Service) resolveRange(ctx context.Context, workspaceID, tz string, r TimeRange) (TimeRange, *time.Location, error) {
+	loc, err := s.location(ctx, workspaceID, tz)
+	if err != nil {
+		return TimeRange{}, nil, err
+	}
+
+	out := r
+	if r.FromDate != "" || r.ToDate != "" {
+		from, err1 := time.ParseInLocation(dateLayout, r.FromDate, loc)
+		to, err2 := time.ParseInLocation(dateLayout, r.ToDate, loc)
+		if err1 != nil || err2 != nil {
+			return TimeRange{}, nil, ErrInvalidRequest
+		}
+		out.From = from
+		out.To = to.AddDate(0, 0, 1)
+	}
+	if out.From.IsZero() || out.To.IsZero() || !out.To.After(out.From) {
+		return TimeRange{}, nil, ErrInvalidRequest
+	}
+	if err := s.checkSpan(out); err != nil {
+		return TimeRange{}, nil, err
+	}
+	return out, loc, nil
+}
+
+// location resolves the request timezone, falling back to the workspace setting and UTC.
+func (s *Service) location(ctx context.Context, workspaceID, tz string) (*time.Location, error) {
+	if tz == "" && s.Settings != nil && workspaceID != "" {
+		v, err := s.Settings.WorkspaceTimezone(ctx, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		tz = v
+	}
+	if tz == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, ErrInvalidRequest
+	}
+	return loc, nil
+}
+
+// bucketStart returns the local bucket start containing t.
+func bucketStart(t time.Time, loc *time.Location, g Granularity) time.Time {
+	lt := t.In(loc)
+	day := time.Date(lt.Year(), lt.Month(), lt.Day(), 0, 0, 0, 0, loc)
+	if g == GranularityMonth {
+		return time.Date(lt.Year(), lt.Month(), 1, 0, 0, 0, 0, loc)
+	}
+	if g == GranularityWeek {
+		offset := (int(day.Weekday()) + 6) % 7 // Monday = 0
+		day = day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+func validGranularity(g Granularity) bool {
+	return g == GranularityNone || g == GranularityDay || g == GranularityWeek || g == GranularityMonth
+}
+
+// MemorySettings is an in-memory WorkspaceSettings for tests and early development.
+type MemorySettings struct {
+	Timezones map[string]string // key: workspace_id
+}
+
+func (m MemorySettings) WorkspaceTimezone(ctx context.Context, workspaceID string) (string, error) {
+	return m.Timezones[workspaceID], nil
+}
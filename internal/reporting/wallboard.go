@@ -0,0 +1,92 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// Real-time wallboard.
+//
+// Contact-center wallboards refresh every second, so the numbers never come from
+// call records: the call and agent event processors keep live counters per
+// campaign in WallboardState (Redis), through WallboardRecorder, and Wallboard
+// only reads them. "Today" for conversions is the workspace's local day.
+
+// WallboardCampaign is one campaign's live row.
+type WallboardCampaign struct {
+	CampaignID         string `json:"campaign_id"`
+	CallersWaiting     int    `json:"callers_waiting"`
+	LongestWaitSeconds int    `json:"longest_wait_seconds"`
+	AgentsAvailable    int    `json:"agents_available"`
+	CallsInProgress    int    `json:"calls_in_progress"`
+	ConversionsToday   int    `json:"conversions_today"`
+
+	// OldestQueuedAt is when the longest-waiting caller was queued (zero when
+	// nobody waits); Wallboard turns it into LongestWaitSeconds.
+	OldestQueuedAt time.Time `json:"-"`
+}
+
+// Wallboard is the live snapshot for a workspace.
+type Wallboard struct {
+	WorkspaceID string              `json:"workspace_id"`
+	Day         string              `json:"day"`
+	Campaigns   []WallboardCampaign `json:"campaigns"`
+	At          time.Time           `json:"at"`
+}
+
+// WallboardState reads live counters. Implementations must key state by workspace.
+type WallboardState interface {
+	// LiveCampaigns returns every campaign with live state; conversions are
+	// those recorded for day (YYYY-MM-DD).
+	LiveCampaigns(ctx context.Context, workspaceID, day string) ([]WallboardCampaign, error)
+}
+
+// WallboardRecorder is how event processors keep WallboardState current.
+// Every method is idempotent for the same call or agent.
+type WallboardRecorder interface {
+	CallerQueued(ctx context.Context, workspaceID, campaignID, callID string, at time.Time) error
+	CallerDequeued(ctx context.Context, workspaceID, campaignID, callID string) error
+	SetAgentAvailable(ctx context.Context, workspaceID, campaignID, agentUserID string, available bool) error
+	CallStarted(ctx context.Context, workspaceID, campaignID, callID string) error
+	CallEnded(ctx context.Context, workspaceID, campaignID, callID string) error
+	// ConversionRecorded counts a conversion for the local day it happened on.
+	ConversionRecorded(ctx context.Context, workspaceID, campaignID, day string) error
+}
+
+// Wallboard returns the workspace's live per-campaign stats, sorted by campaign.
+// campaignID narrows it to one campaign when set.
+func (s *Service) Wallboard(ctx context.Context, workspaceID, campaignID string) (Wallboard, error) {
+	if workspaceID == "" {
+		return Wallboard{}, ErrInvalidRequest
+	}
+	if s.Live == nil {
+		return Wallboard{}, errors.New("reporting: wallboard state not configured")
+	}
+	loc, err := s.location(ctx, workspaceID, "")
+	if err != nil {
+		return Wallboard{}, err
+	}
+	now := s.clock()
+	day := now.In(loc).Format(dateLayout)
+
+	rows, err := s.Live.LiveCampaigns(ctx, workspaceID, day)
+	if err != nil {
+		return Wallboard{}, err
+	}
+	out := Wallboard{WorkspaceID: workspaceID, Day: day, Campaigns: []WallboardCampaign{}, At: now.UTC()}
+	for _, r := range rows {
+		if campaignID != "" && r.CampaignID != campaignID {
+			continue
+		}
+		if r.CallersWaiting > 0 && !r.OldestQueuedAt.IsZero() {
+			if d := now.Sub(r.OldestQueuedAt); d > 0 {
+				r.LongestWaitSeconds = int(d / time.Second)
+			}
+		}
+		out.Campaigns = append(out.Campaigns, r)
+	}
+	sort.Slice(out.Campaigns, func(i, j int) bool { return out.Campaigns[i].CampaignID < out.Campaigns[j].CampaignID })
+	return out, nil
+}
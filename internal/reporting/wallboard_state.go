@@ -0,0 +1,277 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisWallboardState keeps wallboard counters under <Prefix><workspace_id>:
+//   - campaigns: set of campaign IDs with state
+//   - waiting:<campaign>: sorted set call_id -> queued at (unix ms)
+//   - agents:<campaign>: set of available agent user IDs
+//   - live:<campaign>: set of in-progress call IDs
+//   - conversions:<campaign>:<day>: counter
+//
+// Sets are refreshed to StateTTL on every write so a campaign whose events stop
+// (e.g. a lost hangup) ages out instead of showing stale calls forever.
+type RedisWallboardState struct {
+	Client *redis.Client
+	// Prefix defaults to "wallboard:".
+	Prefix string
+	// StateTTL defaults to 24h.
+	StateTTL time.Duration
+}
+
+var (
+	_ WallboardState    = RedisWallboardState{}
+	_ WallboardRecorder = RedisWallboardState{}
+)
+
+// conversionsTTL keeps yesterday's counter until the local day is over everywhere.
+const conversionsTTL = 48 * time.Hour
+
+func (r RedisWallboardState) LiveCampaigns(ctx context.Context, workspaceID, day string) ([]WallboardCampaign, error) {
+	if r.Client == nil {
+		return nil, errors.New("reporting: redis client is nil")
+	}
+	ids, err := r.Client.SMembers(ctx, r.key(workspaceID, "campaigns")).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	type cmds struct {
+		waiting     *redis.IntCmd
+		oldest      *redis.ZSliceCmd
+		agents      *redis.IntCmd
+		live        *redis.IntCmd
+		conversions *redis.StringCmd
+	}
+	pipe := r.Client.Pipeline()
+	pending := make([]cmds, len(ids))
+	for i, id := range ids {
+		pending[i] = cmds{
+			waiting:     pipe.ZCard(ctx, r.key(workspaceID, "waiting:"+id)),
+			oldest:      pipe.ZRangeWithScores(ctx, r.key(workspaceID, "waiting:"+id), 0, 0),
+			agents:      pipe.SCard(ctx, r.key(workspaceID, "agents:"+id)),
+			live:        pipe.SCard(ctx, r.key(workspaceID, "live:"+id)),
+			conversions: pipe.Get(ctx, r.key(workspaceID, "conversions:"+id+":"+day)),
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	out := make([]WallboardCampaign, 0, len(ids))
+	for i, id := range ids {
+		c := pending[i]
+		row := WallboardCampaign{
+			CampaignID:      id,
+			CallersWaiting:  int(c.waiting.Val()),
+			AgentsAvailable: int(c.agents.Val()),
+			CallsInProgress: int(c.live.Val()),
+		}
+		if z := c.oldest.Val(); len(z) > 0 {
+			row.OldestQueuedAt = time.UnixMilli(int64(z[0].Score))
+		}
+		if v := c.conversions.Val(); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, err
+			}
+			row.ConversionsToday = n
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+func (r RedisWallboardState) CallerQueued(ctx context.Context, workspaceID, campaignID, callID string, at time.Time) error {
+	return r.write(ctx, workspaceID, campaignID, "waiting:", func(pipe redis.Pipeliner, key string) {
+		pipe.ZAddNX(ctx, key, redis.Z{Score: float64(at.UnixMilli()), Member: callID})
+	})
+}
+
+func (r RedisWallboardState) CallerDequeued(ctx context.Context, workspaceID, campaignID, callID string) error {
+	return r.write(ctx, workspaceID, campaignID, "waiting:", func(pipe redis.Pipeliner, key string) {
+		pipe.ZRem(ctx, key, callID)
+	})
+}
+
+func (r RedisWallboardState) SetAgentAvailable(ctx context.Context, workspaceID, campaignID, agentUserID string, available bool) error {
+	return r.write(ctx, workspaceID, campaignID, "agents:", func(pipe redis.Pipeliner, key string) {
+		if available {
+			pipe.SAdd(ctx, key, agentUserID)
+		} else {
+			pipe.SRem(ctx, key, agentUserID)
+		}
+	})
+}
+
+func (r RedisWallboardState) CallStarted(ctx context.Context, workspaceID, campaignID, callID string) error {
+	return r.write(ctx, workspaceID, campaignID, "live:", func(pipe redis.Pipeliner, key string) {
+		pipe.SAdd(ctx, key, callID)
+	})
+}
+
+func (r RedisWallboardState) CallEnded(ctx context.Context, workspaceID, campaignID, callID string) error {
+	return r.write(ctx, workspaceID, campaignID, "live:", func(pipe redis.Pipeliner, key string) {
+		pipe.SRem(ctx, key, callID)
+	})
+}
+
+func (r RedisWallboardState) ConversionRecorded(ctx context.Context, workspaceID, campaignID, day string) error {
+	if r.Client == nil {
+		return errors.New("reporting: redis client is nil")
+	}
+	if workspaceID == "" || campaignID == "" || day == "" {
+		return ErrInvalidRequest
+	}
+	key := r.key(workspaceID, "conversions:"+campaignID+":"+day)
+	pipe := r.Client.TxPipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, conversionsTTL)
+	pipe.SAdd(ctx, r.key(workspaceID, "campaigns"), campaignID)
+	pipe.Expire(ctx, r.key(workspaceID, "campaigns"), r.ttl())
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// write applies op to the campaign's kind key and refreshes the TTLs.
+func (r RedisWallboardState) write(ctx context.Context, workspaceID, campaignID, kind string, op func(pipe redis.Pipeliner, key string)) error {
+	if r.Client == nil {
+		return errors.New("reporting: redis client is nil")
+	}
+	if workspaceID == "" || campaignID == "" {
+		return ErrInvalidRequest
+	}
+	key := r.key(workspaceID, kind+campaignID)
+	pipe := r.Client.TxPipeline()
+	op(pipe, key)
+	pipe.Expire(ctx, key, r.ttl())
+	pipe.SAdd(ctx, r.key(workspaceID, "campaigns"), campaignID)
+	pipe.Expire(ctx, r.key(workspaceID, "campaigns"), r.ttl())
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r RedisWallboardState) key(workspaceID, suffix string) string {
+	prefix := r.Prefix
+	if prefix == "" {
+		prefix = "wallboard:"
+	}
+	return prefix + workspaceID + ":" + suffix
+}
+
+func (r RedisWallboardState) ttl() time.Duration {
+	if r.StateTTL > 0 {
+		return r.StateTTL
+	}
+	return 24 * time.Hour
+}
+
+// MemoryWallboardState is an in-memory WallboardState and WallboardRecorder for
+// tests. It is not intended for production use (TTLs are ignored).
+type MemoryWallboardState struct {
+	mu        sync.Mutex
+	campaigns map[string]*memoryWallboardCampaign
+}
+
+type memoryWallboardCampaign struct {
+	waiting     map[string]time.Time
+	agents      map[string]bool
+	live        map[string]bool
+	conversions map[string]int
+}
+
+func NewMemoryWallboardState() *MemoryWallboardState {
+	return &MemoryWallboardState{campaigns: map[string]*memoryWallboardCampaign{}}
+}
+
+func (m *MemoryWallboardState) campaign(workspaceID, campaignID string) *memoryWallboardCampaign {
+	k := workspaceID + "|" + campaignID
+	c, ok := m.campaigns[k]
+	if !ok {
+		c = &memoryWallboardCampaign{waiting: map[string]time.Time{}, agents: map[string]bool{}, live: map[string]bool{}, conversions: map[string]int{}}
+		m.campaigns[k] = c
+	}
+	return c
+}
+
+func (m *MemoryWallboardState) LiveCampaigns(ctx context.Context, workspaceID, day string) ([]WallboardCampaign, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []WallboardCampaign
+	for k, c := range m.campaigns {
+		ws, id, _ := strings.Cut(k, "|")
+		if ws != workspaceID {
+			continue
+		}
+		row := WallboardCampaign{CampaignID: id, CallersWaiting: len(c.waiting), AgentsAvailable: len(c.agents), CallsInProgress: len(c.live), ConversionsToday: c.conversions[day]}
+		for _, at := range c.waiting {
+			if row.OldestQueuedAt.IsZero() || at.Before(row.OldestQueuedAt) {
+				row.OldestQueuedAt = at
+			}
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+func (m *MemoryWallboardState) CallerQueued(ctx context.Context, workspaceID, campaignID, callID string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := m.campaign(workspaceID, campaignID)
+	if _, ok := c.waiting[callID]; !ok {
+		c.waiting[callID] = at
+	}
+	return nil
+}
+
+func (m *MemoryWallboardState) CallerDequeued(ctx context.Context, workspaceID, campaignID, callID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.campaign(workspaceID, campaignID).waiting, callID)
+	return nil
+}
+
+func (m *MemoryWallboardState) SetAgentAvailable(ctx context.Context, workspaceID, campaignID, agentUserID string, available bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := m.campaign(workspaceID, campaignID)
+	if available {
+		c.agents[agentUserID] = true
+	} else {
+		delete(c.agents, agentUserID)
+	}
+	return nil
+}
+
+func (m *MemoryWallboardState) CallStarted(ctx context.Context, workspaceID, campaignID, callID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.campaign(workspaceID, campaignID).live[callID] = true
+	return nil
+}
+
+func (m *MemoryWallboardState) CallEnded(ctx context.Context, workspaceID, campaignID, callID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.campaign(workspaceID, campaignID).live, callID)
+	return nil
+}
+
+func (m *MemoryWallboardState) ConversionRecorded(ctx context.Context, workspaceID, campaignID, day string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.campaign(workspaceID, campaignID).conversions[day]++
+	return nil
+}
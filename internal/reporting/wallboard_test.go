@@ -0,0 +1,51 @@
+package reporting
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWallboard_LiveCounters(t *testing.T) {
+	ctx := context.Background()
+	live := NewMemoryWallboardState()
+	svc := NewService(nil)
+	svc.Live = live
+	svc.Settings = MemorySettings{Timezones: map[string]string{"w": "America/New_York"}}
+	// 02:00 UTC is still the previous local day in New York.
+	now := time.Date(2025, 3, 2, 2, 0, 0, 0, time.UTC)
+	svc.clock = func() time.Time { return now }
+
+	_ = live.CallerQueued(ctx, "w", "c1", "call-1", now.Add(-90*time.Second))
+	_ = live.CallerQueued(ctx, "w", "c1", "call-2", now.Add(-10*time.Second))
+	_ = live.CallerQueued(ctx, "w", "c1", "call-1", now) // re-queue keeps the original time
+	_ = live.CallerQueued(ctx, "w", "c1", "call-3", now.Add(-5*time.Minute))
+	_ = live.CallerDequeued(ctx, "w", "c1", "call-3")
+	_ = live.SetAgentAvailable(ctx, "w", "c1", "a1", true)
+	_ = live.SetAgentAvailable(ctx, "w", "c1", "a2", true)
+	_ = live.SetAgentAvailable(ctx, "w", "c1", "a2", false)
+	_ = live.CallStarted(ctx, "w", "c1", "call-3")
+	_ = live.ConversionRecorded(ctx, "w", "c1", "2025-03-01")
+	_ = live.ConversionRecorded(ctx, "w", "c1", "2025-03-02")
+	_ = live.CallStarted(ctx, "w", "c0", "call-9")
+	_ = live.CallStarted(ctx, "other", "c1", "call-x")
+
+	wb, err := svc.Wallboard(ctx, "w", "")
+	if err != nil {
+		t.Fatalf("wallboard: %v", err)
+	}
+	if wb.Day != "2025-03-01" || len(wb.Campaigns) != 2 || wb.Campaigns[0].CampaignID != "c0" {
+		t.Fatalf("unexpected wallboard %+v", wb)
+	}
+	got := wb.Campaigns[1]
+	want := WallboardCampaign{CampaignID: "c1", CallersWaiting: 2, LongestWaitSeconds: 90, AgentsAvailable: 1, CallsInProgress: 1, ConversionsToday: 1}
+	got.OldestQueuedAt = time.Time{}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	wb, err = svc.Wallboard(ctx, "w", "c0")
+	if err != nil || len(wb.Campaigns) != 1 || wb.Campaigns[0].CallsInProgress != 1 {
+		t.Fatalf("expected campaign filter, got %+v err=%v", wb, err)
+	}
+}
@@ -50,6 +50,8 @@ type Override struct {
 	CampaignID  string
 	// OverrideID is optional but recommended for correlating audit logs.
 	OverrideID string
+	// CreatedBy is the admin who created the override (for compliance reporting).
+	CreatedBy string
 
 	// ConnectTo is the forced dial target.
 	ConnectTo string
@@ -65,6 +67,7 @@ type OverrideAuditEvent struct {
 	WorkspaceID string
 	CampaignID  string
 	OverrideID  string
+	CreatedBy   string
 
 	ProviderCallID string
 	From           string
@@ -121,6 +124,7 @@ func (e *AdminOverrideEngine) Decide(ctx context.Context, workspaceID, campaignI
 			WorkspaceID:    workspaceID,
 			CampaignID:     campaignID,
 			OverrideID:     o.OverrideID,
+			CreatedBy:      o.CreatedBy,
 			ProviderCallID: req.ProviderCallID,
 			From:          req.From,
 			To:            req.To,
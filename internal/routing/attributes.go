@@ -0,0 +1,124 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"telecom-platform/internal/telephony"
+)
+
+// Attribute-based destination matching.
+//
+// Destinations can carry attributes (WeightedDestination.Attributes), e.g.
+// product=auto,home or skill=spanish,billing. A call may require attributes:
+// set directly on RouteInput.Attributes, or resolved by an AttributeResolver from
+// the dialed number or caller input gathered by an IVR step. Before the weighted
+// pick, targets are filtered to those carrying every required value; group
+// entries pass unless they declare a different value, and their members are
+// filtered in turn. When nothing matches, the call has no eligible destination.
+// Values compare case-insensitively. Attributes set on RouteInput win over
+// resolved ones.
+
+var ErrInvalidAttributeRule = errors.New("routing: invalid attribute rule")
+
+// AttributeRule sets a required attribute for calls to a dialed number, or for
+// calls whose caller entered Input at IVR step Step.
+type AttributeRule struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	CampaignID  string `json:"campaign_id" db:"campaign_id"`
+
+	Attribute string `json:"attribute" db:"attribute"`
+	Value     string `json:"value" db:"value"`
+
+	// Exactly one of DialedNumber and Step is set; Input goes with Step.
+	DialedNumber string `json:"dialed_number,omitempty" db:"dialed_number"`
+	Step         string `json:"step,omitempty" db:"step"`
+	Input        string `json:"input,omitempty" db:"input"`
+}
+
+// Validate checks the rule shape.
+func (r AttributeRule) Validate() error {
+	if r.WorkspaceID == "" || r.CampaignID == "" || r.Attribute == "" || r.Value == "" {
+		return ErrInvalidAttributeRule
+	}
+	if (r.DialedNumber == "") == (r.Step == "") || (r.Step != "" && r.Input == "") {
+		return ErrInvalidAttributeRule
+	}
+	return nil
+}
+
+// AttributeRuleStore loads a campaign's attribute rules.
+// Implementations must enforce workspace filtering.
+type AttributeRuleStore interface {
+	ListAttributeRules(ctx context.Context, workspaceID, campaignID string) ([]AttributeRule, error)
+}
+
+// AttributeResolver resolves a call's required attributes from its rules.
+type AttributeResolver struct {
+	Store AttributeRuleStore
+}
+
+// Resolve returns the attributes required by the rules matching req. Dialed
+// number rules apply first, so caller input overrides them.
+func (r *AttributeResolver) Resolve(ctx context.Context, workspaceID, campaignID string, req telephony.InboundCallRequest) (map[string]string, error) {
+	if r == nil || r.Store == nil {
+		return nil, nil
+	}
+	rules, err := r.Store.ListAttributeRules(ctx, workspaceID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].Step == "" && rules[j].Step != "" })
+	var out map[string]string
+	for _, rule := range rules {
+		matched := rule.DialedNumber != "" && rule.DialedNumber == req.To
+		if rule.Step != "" {
+			input, ok := req.Collected[rule.Step]
+			matched = ok && input == rule.Input
+		}
+		if !matched {
+			continue
+		}
+		if out == nil {
+			out = map[string]string{}
+		}
+		out[rule.Attribute] = rule.Value
+	}
+	return out, nil
+}
+
+// matchAttributes keeps the dests that can serve attrs.
+func matchAttributes(dests []WeightedDestination, attrs map[string]string) []WeightedDestination {
+	if len(attrs) == 0 {
+		return dests
+	}
+	var out []WeightedDestination
+	for _, d := range dests {
+		if hasAttributes(d, attrs) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func hasAttributes(d WeightedDestination, attrs map[string]string) bool {
+	for k, want := range attrs {
+		values, ok := d.Attributes[k]
+		if !ok && d.GroupID != "" {
+			continue
+		}
+		found := false
+		for _, v := range values {
+			if strings.EqualFold(v, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package routing
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryAttributeRuleStore is a simple in-memory AttributeRuleStore useful for tests.
+// It is not intended for production use.
+
+type MemoryAttributeRuleStore struct {
+	mu    sync.Mutex
+	rules map[string][]AttributeRule
+}
+
+func NewMemoryAttributeRuleStore() *MemoryAttributeRuleStore {
+	return &MemoryAttributeRuleStore{rules: map[string][]AttributeRule{}}
+}
+
+// AddAttributeRule stores r after validation.
+func (s *MemoryAttributeRuleStore) AddAttributeRule(r AttributeRule) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := r.WorkspaceID + "|" + r.CampaignID
+	s.rules[key] = append(s.rules[key], r)
+	return nil
+}
+
+func (s *MemoryAttributeRuleStore) ListAttributeRules(ctx context.Context, workspaceID, campaignID string) ([]AttributeRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]AttributeRule(nil), s.rules[workspaceID+"|"+campaignID]...), nil
+}
@@ -0,0 +1,62 @@
+package routing
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"telecom-platform/internal/telephony"
+)
+
+func TestAttributes_FilterDestinations(t *testing.T) {
+	ctx := context.Background()
+	groups := NewMemoryDestinationGroupStore()
+	_ = groups.PutGroup(ctx, DestinationGroup{ID: "g", WorkspaceID: "w", Name: "home team", Version: 1, Members: []WeightedDestination{
+		{TargetURI: "+15550003", Weight: 1, Attributes: map[string][]string{"product": {"home"}}},
+		{TargetURI: "+15550004", Weight: 1, Attributes: map[string][]string{"product": {"auto"}}},
+	}})
+	campaign := stubCampaigns{ev: CampaignEvaluation{Allowed: true, Destinations: []WeightedDestination{
+		{TargetURI: "+15550001", Weight: 100, Attributes: map[string][]string{"product": {"Auto", "life"}}},
+		{TargetURI: "+15550002", Weight: 100},
+		{GroupID: "g", Weight: 1},
+	}}}
+	rules := NewMemoryAttributeRuleStore()
+	for _, r := range []AttributeRule{
+		{WorkspaceID: "w", CampaignID: "c", Attribute: "product", Value: "auto", DialedNumber: "+18005550100"},
+		{WorkspaceID: "w", CampaignID: "c", Attribute: "product", Value: "home", Step: "product", Input: "2"},
+	} {
+		if err := rules.AddAttributeRule(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rules.AddAttributeRule(AttributeRule{WorkspaceID: "w", CampaignID: "c", Attribute: "product", Value: "x", Step: "product"}); err == nil {
+		t.Fatalf("expected invalid rule")
+	}
+	e := NewRoutingEngine(nil, campaign, rand.New(rand.NewSource(1)))
+	e.Groups = groups
+	e.Attributes = &AttributeResolver{Store: rules}
+	route := func(in telephony.InboundCallRequest, attrs map[string]string) Decision {
+		in.WorkspaceID, in.ProviderCallID, in.From = "w", "CA1", "+15551230000"
+		d, err := e.RouteWithFailover(ctx, RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: in, Attributes: attrs}, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	// Dialed number requires auto: the tagged target, or the auto member of the group.
+	d := route(telephony.InboundCallRequest{To: "+18005550100"}, nil)
+	if d.ConnectTo != "+15550001" || len(d.Alternates) != 1 || d.Alternates[0] != "+15550004" {
+		t.Fatalf("expected auto targets only, got %+v", d)
+	}
+	// Caller input overrides the dialed number.
+	d = route(telephony.InboundCallRequest{To: "+18005550100", Collected: map[string]string{"product": "2"}}, nil)
+	if d.ConnectTo != "+15550003" || len(d.Alternates) != 0 {
+		t.Fatalf("expected home target, got %+v", d)
+	}
+	// Explicit attributes win; nothing serves pets.
+	d = route(telephony.InboundCallRequest{To: "+18005550100"}, map[string]string{"product": "pets"})
+	if d.Action != ActionReject || d.Reason != "no_eligible_destination" {
+		t.Fatalf("expected no eligible destination, got %+v", d)
+	}
+}
@@ -22,16 +22,23 @@ func (a AuditAdapter) LogOverrideApplied(ctx context.Context, e OverrideAuditEve
 	if a.Audit == nil {
 		return nil
 	}
+	// Attribute the event to the admin who created the override when known.
+	actor := a.ActorUserID
+	if e.CreatedBy != "" {
+		actor = e.CreatedBy
+	}
 	return a.Audit.Append(ctx, audit.Event{
 		WorkspaceID: e.WorkspaceID,
 		Type:        audit.EventTypeOverride,
-		ActorUserID: a.ActorUserID,
+		ActorUserID: actor,
 		ActorRole:   a.ActorRole,
 		IPAddress:   e.IPAddress,
 		CampaignID:  e.CampaignID,
-		CallID:      "", // internal call id not available at this boundary yet
-		OverrideID:  e.OverrideID,
-		Message:     "routing override applied",
-		Metadata:    e.Metadata,
+		// Internal call id is not available at this boundary yet; the provider call id
+		// lets override usage reporting count affected calls.
+		CallID:     e.ProviderCallID,
+		OverrideID: e.OverrideID,
+		Message:    "routing override applied",
+		Metadata:   e.Metadata,
 	})
 }
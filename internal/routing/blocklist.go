@@ -0,0 +1,146 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Caller blocklist (DNC screening).
+//
+// Before forwarding and campaign rules, the engine rejects callers on the
+// workspace's blocklist or the platform-wide (global) one. Entries match the
+// caller's number exactly or by prefix ("+1900" blocks every +1 900 number). A
+// blocked call gets ActionReject with the generic reason "rejected", so neither
+// the caller nor downstream reports learn it was screened; the dry-run trace
+// names the matching entry. Privileged roles (admin override) and silent
+// overrides run before screening.
+//
+// Lists are stored in Postgres (PostgresBlocklistStore) and read through a Redis
+// cache (RedisBlocklistCache) so screening does not query the database per call.
+
+type BlockMatch string
+
+const (
+	BlockExact  BlockMatch = "exact"
+	BlockPrefix BlockMatch = "prefix"
+)
+
+// GlobalBlocklist is the WorkspaceID of platform-wide entries.
+const GlobalBlocklist = ""
+
+// blockedReason is deliberately non-revealing.
+const blockedReason = "rejected"
+
+var (
+	ErrInvalidBlockEntry  = errors.New("routing: invalid blocklist entry")
+	ErrBlockEntryNotFound = errors.New("routing: blocklist entry not found")
+)
+
+// BlockEntry blocks one caller number or prefix.
+type BlockEntry struct {
+	ID string `json:"id" db:"id"`
+	// WorkspaceID is GlobalBlocklist for platform-wide entries.
+	WorkspaceID string     `json:"workspace_id" db:"workspace_id"`
+	Pattern     string     `json:"pattern" db:"pattern"` // E.164 number or "+" digits prefix
+	Match       BlockMatch `json:"match" db:"match"`
+	// Note is internal (e.g. "fraud report #123").
+	Note      string    `json:"note,omitempty" db:"note"`
+	CreatedBy string    `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Validate checks the pattern shape.
+func (b BlockEntry) Validate() error {
+	switch b.Match {
+	case BlockExact:
+		if !isE164(b.Pattern) {
+			return ErrInvalidBlockEntry
+		}
+	case BlockPrefix:
+		if len(b.Pattern) < 2 || len(b.Pattern) > 16 || b.Pattern[0] != '+' || !allDigits(b.Pattern[1:]) {
+			return ErrInvalidBlockEntry
+		}
+	default:
+		return ErrInvalidBlockEntry
+	}
+	return nil
+}
+
+func (b BlockEntry) matches(number string) bool {
+	if b.Match == BlockPrefix {
+		return strings.HasPrefix(number, b.Pattern)
+	}
+	return number == b.Pattern
+}
+
+// BlocklistStore persists entries. ListBlockEntries(GlobalBlocklist) returns the
+// global list; otherwise implementations must enforce workspace filtering.
+type BlocklistStore interface {
+	ListBlockEntries(ctx context.Context, workspaceID string) ([]BlockEntry, error)
+	PutBlockEntry(ctx context.Context, b BlockEntry) error
+	DeleteBlockEntry(ctx context.Context, workspaceID, id string) (bool, error)
+}
+
+// Blocklist manages and screens against the blocklists.
+type Blocklist struct {
+	Store BlocklistStore
+	Now   func() time.Time
+}
+
+// Add stores a new entry for workspaceID (GlobalBlocklist for platform-wide).
+func (l *Blocklist) Add(ctx context.Context, b BlockEntry) (BlockEntry, error) {
+	b.Pattern = strings.TrimSpace(b.Pattern)
+	if err := b.Validate(); err != nil {
+		return BlockEntry{}, err
+	}
+	now := time.Now
+	if l.Now != nil {
+		now = l.Now
+	}
+	b.ID, b.CreatedAt = uuid.NewString(), now().UTC()
+	if err := l.Store.PutBlockEntry(ctx, b); err != nil {
+		return BlockEntry{}, err
+	}
+	return b, nil
+}
+
+// Remove deletes an entry.
+func (l *Blocklist) Remove(ctx context.Context, workspaceID, id string) error {
+	ok, err := l.Store.DeleteBlockEntry(ctx, workspaceID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrBlockEntryNotFound
+	}
+	return nil
+}
+
+// List returns the entries of one list.
+func (l *Blocklist) List(ctx context.Context, workspaceID string) ([]BlockEntry, error) {
+	return l.Store.ListBlockEntries(ctx, workspaceID)
+}
+
+// Blocked returns the entry blocking number for workspaceID, global entries first.
+func (l *Blocklist) Blocked(ctx context.Context, workspaceID, number string) (BlockEntry, bool, error) {
+	number = strings.TrimSpace(number)
+	if number == "" {
+		return BlockEntry{}, false, nil
+	}
+	for _, ws := range []string{GlobalBlocklist, workspaceID} {
+		entries, err := l.Store.ListBlockEntries(ctx, ws)
+		if err != nil {
+			return BlockEntry{}, false, err
+		}
+		for _, b := range entries {
+			if b.matches(number) {
+				return b, true, nil
+			}
+		}
+	}
+	return BlockEntry{}, false, nil
+}
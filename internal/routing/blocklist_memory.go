@@ -0,0 +1,43 @@
+package routing
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryBlocklistStore is a simple in-memory BlocklistStore useful for tests.
+// It is not intended for production use.
+
+type MemoryBlocklistStore struct {
+	mu      sync.Mutex
+	entries map[string][]BlockEntry
+}
+
+func NewMemoryBlocklistStore() *MemoryBlocklistStore {
+	return &MemoryBlocklistStore{entries: map[string][]BlockEntry{}}
+}
+
+func (s *MemoryBlocklistStore) ListBlockEntries(ctx context.Context, workspaceID string) ([]BlockEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]BlockEntry(nil), s.entries[workspaceID]...), nil
+}
+
+func (s *MemoryBlocklistStore) PutBlockEntry(ctx context.Context, b BlockEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[b.WorkspaceID] = append(s.entries[b.WorkspaceID], b)
+	return nil
+}
+
+func (s *MemoryBlocklistStore) DeleteBlockEntry(ctx context.Context, workspaceID, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, b := range s.entries[workspaceID] {
+		if b.ID == id {
+			s.entries[workspaceID] = append(s.entries[workspaceID][:i], s.entries[workspaceID][i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,75 @@
+package routing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"telecom-platform/pkg/utils"
+)
+
+// PostgresBlocklistStore stores entries in the routing_blocklist table:
+//
+//	routing_blocklist (id uuid PRIMARY KEY, workspace_id text NOT NULL ('' = global),
+//	  pattern, match, note, created_by text, created_at timestamptz)
+//	INDEX (workspace_id)
+type PostgresBlocklistStore struct {
+	DB *sql.DB
+}
+
+var _ BlocklistStore = PostgresBlocklistStore{}
+
+func (s PostgresBlocklistStore) ListBlockEntries(ctx context.Context, workspaceID string) (out []BlockEntry, err error) {
+	if s.DB == nil {
+		return nil, errors.New("routing: blocklist db is nil")
+	}
+	ctx, done := utils.TrackQuery(utils.WithQuery(ctx, "routing", "ListBlockEntries", utils.ReportBudget))
+	defer func() { done(err) }()
+	rows, err := s.DB.QueryContext(ctx, `
+SELECT id, workspace_id, pattern, match, note, created_by, created_at
+FROM routing_blocklist
+WHERE workspace_id = $1
+ORDER BY created_at, id`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out = make([]BlockEntry, 0)
+	for rows.Next() {
+		var b BlockEntry
+		var match string
+		if err := rows.Scan(&b.ID, &b.WorkspaceID, &b.Pattern, &match, &b.Note, &b.CreatedBy, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		b.Match = BlockMatch(match)
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+func (s PostgresBlocklistStore) PutBlockEntry(ctx context.Context, b BlockEntry) (err error) {
+	if s.DB == nil {
+		return errors.New("routing: blocklist db is nil")
+	}
+	ctx, done := utils.TrackQuery(utils.WithQuery(ctx, "routing", "PutBlockEntry", utils.ReportBudget))
+	defer func() { done(err) }()
+	_, err = s.DB.ExecContext(ctx, `
+INSERT INTO routing_blocklist (id, workspace_id, pattern, match, note, created_by, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		b.ID, b.WorkspaceID, b.Pattern, string(b.Match), b.Note, b.CreatedBy, b.CreatedAt)
+	return err
+}
+
+func (s PostgresBlocklistStore) DeleteBlockEntry(ctx context.Context, workspaceID, id string) (ok bool, err error) {
+	if s.DB == nil {
+		return false, errors.New("routing: blocklist db is nil")
+	}
+	ctx, done := utils.TrackQuery(utils.WithQuery(ctx, "routing", "DeleteBlockEntry", utils.ReportBudget))
+	defer func() { done(err) }()
+	res, err := s.DB.ExecContext(ctx, `DELETE FROM routing_blocklist WHERE workspace_id = $1 AND id = $2`, workspaceID, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
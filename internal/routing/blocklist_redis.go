@@ -0,0 +1,82 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultBlocklistCacheTTL bounds how stale a cached list can be when an
+// invalidation is lost (e.g. another writer bypassing the cache).
+const DefaultBlocklistCacheTTL = 5 * time.Minute
+
+// RedisBlocklistCache caches each list of Next in Redis. Writes go to Next and
+// then drop the cached list.
+type RedisBlocklistCache struct {
+	Client *redis.Client
+	Next   BlocklistStore
+	TTL    time.Duration
+}
+
+var _ BlocklistStore = RedisBlocklistCache{}
+
+func (c RedisBlocklistCache) ListBlockEntries(ctx context.Context, workspaceID string) ([]BlockEntry, error) {
+	if c.Client == nil {
+		return nil, errors.New("routing: redis client is nil")
+	}
+	key := blocklistKey(workspaceID)
+	raw, err := c.Client.Get(ctx, key).Bytes()
+	if err == nil {
+		var out []BlockEntry
+		if err := json.Unmarshal(raw, &out); err == nil {
+			return out, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	out, err := c.Next.ListBlockEntries(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if raw, err := json.Marshal(out); err == nil {
+		ttl := c.TTL
+		if ttl <= 0 {
+			ttl = DefaultBlocklistCacheTTL
+		}
+		// A failed cache fill only costs the next call a database read.
+		_ = c.Client.Set(ctx, key, raw, ttl).Err()
+	}
+	return out, nil
+}
+
+func (c RedisBlocklistCache) PutBlockEntry(ctx context.Context, b BlockEntry) error {
+	if err := c.Next.PutBlockEntry(ctx, b); err != nil {
+		return err
+	}
+	return c.invalidate(ctx, b.WorkspaceID)
+}
+
+func (c RedisBlocklistCache) DeleteBlockEntry(ctx context.Context, workspaceID, id string) (bool, error) {
+	ok, err := c.Next.DeleteBlockEntry(ctx, workspaceID, id)
+	if err != nil || !ok {
+		return ok, err
+	}
+	return true, c.invalidate(ctx, workspaceID)
+}
+
+func (c RedisBlocklistCache) invalidate(ctx context.Context, workspaceID string) error {
+	if c.Client == nil {
+		return errors.New("routing: redis client is nil")
+	}
+	return c.Client.Del(ctx, blocklistKey(workspaceID)).Err()
+}
+
+func blocklistKey(workspaceID string) string {
+	if workspaceID == GlobalBlocklist {
+		return "routing:blocklist:global"
+	}
+	return "routing:blocklist:ws:" + workspaceID
+}
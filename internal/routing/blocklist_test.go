@@ -0,0 +1,60 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"telecom-platform/internal/telephony"
+)
+
+func TestBlocklist_RejectsBeforeCampaignRules(t *testing.T) {
+	ctx := context.Background()
+	list := &Blocklist{Store: NewMemoryBlocklistStore()}
+	if _, err := list.Add(ctx, BlockEntry{WorkspaceID: GlobalBlocklist, Pattern: "+1900", Match: BlockPrefix}); err != nil {
+		t.Fatal(err)
+	}
+	wsEntry, err := list.Add(ctx, BlockEntry{WorkspaceID: "w", Pattern: "+15551230000", Match: BlockExact, Note: "abusive caller"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := list.Add(ctx, BlockEntry{WorkspaceID: "w", Pattern: "555", Match: BlockPrefix}); !errors.Is(err, ErrInvalidBlockEntry) {
+		t.Fatalf("expected invalid prefix, got %v", err)
+	}
+
+	e := NewRoutingEngine(nil, stubCampaigns{ev: CampaignEvaluation{Allowed: true, Destinations: []WeightedDestination{{TargetURI: "+15550001", Weight: 1}}}}, rand.New(rand.NewSource(1)))
+	e.Blocklist = list
+	route := func(ws, from string) Decision {
+		d, err := e.Route(ctx, RouteInput{WorkspaceID: ws, CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: ws, ProviderCallID: "CA1", From: from, To: "+15550000000"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	for _, from := range []string{"+15551230000", "+19005550100"} {
+		if d := route("w", from); d.Action != ActionReject || d.Reason != "rejected" {
+			t.Fatalf("%s: expected non-revealing reject, got %+v", from, d)
+		}
+	}
+	// Workspace entries do not apply elsewhere; global ones do.
+	if d := route("other", "+15551230000"); d.Action != ActionConnect {
+		t.Fatalf("expected other workspace to connect, got %+v", d)
+	}
+	if d := route("other", "+19005550100"); d.Action != ActionReject {
+		t.Fatalf("expected global block, got %+v", d)
+	}
+
+	res, err := e.DryRun(ctx, RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: "w", From: "+15551230000", To: "+15550000000"}}, e.Now())
+	if err != nil || len(res.Trace) == 0 || res.Trace[len(res.Trace)-1].Data["entry_id"] != wsEntry.ID {
+		t.Fatalf("expected trace naming the entry, got %+v err=%v", res.Trace, err)
+	}
+
+	if err := list.Remove(ctx, "w", wsEntry.ID); err != nil {
+		t.Fatal(err)
+	}
+	if d := route("w", "+15551230000"); d.Action != ActionConnect {
+		t.Fatalf("expected connect after removal, got %+v", d)
+	}
+}
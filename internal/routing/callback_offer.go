@@ -0,0 +1,134 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/callbacks"
+)
+
+// Callback offers.
+//
+// When routing cannot connect (campaign closed, pacing overflow, no eligible
+// destination) and the campaign has callbacks enabled, the caller is offered a
+// callback instead of being rejected:
+//
+//	callback:      "press 1 to be called back"  (anything else -> original rejection)
+//	callback_time: 1 = as soon as possible, 2 = tomorrow morning, 3 = tomorrow afternoon
+//
+// The caller ID is used as the callback number; anonymous callers are not offered a
+// callback. The task is created through CallbackSink and the call ends with a
+// confirmation announcement.
+
+const (
+	GatherStepCallback     = "callback"
+	GatherStepCallbackTime = "callback_time"
+
+	defaultCallbackOffer   = "All of our agents are currently unavailable. To receive a call back, press 1."
+	defaultCallbackTime    = "For a call back as soon as possible, press 1. Tomorrow morning, press 2. Tomorrow afternoon, press 3."
+	defaultCallbackConfirm = "Thank you. We will call you back. Goodbye."
+)
+
+// CallbackConfig enables callback offers for a campaign.
+type CallbackConfig struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	CampaignID  string `json:"campaign_id" db:"campaign_id"`
+
+	// Prompts; empty uses English defaults.
+	OfferPrompt   string `json:"offer_prompt,omitempty" db:"offer_prompt"`
+	TimePrompt    string `json:"time_prompt,omitempty" db:"time_prompt"`
+	ConfirmPrompt string `json:"confirm_prompt,omitempty" db:"confirm_prompt"`
+
+	// Timezone resolves "tomorrow morning/afternoon" (IANA; empty means UTC).
+	Timezone string `json:"timezone,omitempty" db:"timezone"`
+}
+
+// CallbackConfigStore loads callback configs. ok=false means callbacks are off for the campaign.
+type CallbackConfigStore interface {
+	GetCallbackConfig(ctx context.Context, workspaceID, campaignID string) (CallbackConfig, bool, error)
+}
+
+// CallbackSink creates callback tasks (implemented by callbacks.Service).
+type CallbackSink interface {
+	RequestCallback(ctx context.Context, workspaceID, campaignID, number string, preferredAt time.Time, reason, sourceProviderCallID string) (callbacks.Callback, error)
+}
+
+// CallbackOffer turns "cannot connect" outcomes into a callback IVR.
+type CallbackOffer struct {
+	Configs CallbackConfigStore
+	Sink    CallbackSink
+}
+
+// Offer returns the next IVR decision for an unconnectable call, or ok=false to keep
+// the original rejection.
+func (o *CallbackOffer) Offer(ctx context.Context, in RouteInput, now time.Time, rejected Decision) (Decision, bool, error) {
+	if o == nil || o.Configs == nil || o.Sink == nil || in.CampaignID == "" || !strings.HasPrefix(in.Inbound.From, "+") {
+		return Decision{}, false, nil
+	}
+	cfg, ok, err := o.Configs.GetCallbackConfig(ctx, in.WorkspaceID, in.CampaignID)
+	if err != nil || !ok {
+		return Decision{}, false, err
+	}
+	base := Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID}
+	collected := in.Inbound.Collected
+
+	accept, asked := collected[GatherStepCallback]
+	if !asked {
+		base.Action = ActionGather
+		base.Gather = &GatherPrompt{Step: GatherStepCallback, Prompt: orDefault(cfg.OfferPrompt, defaultCallbackOffer), NumDigits: 1, TimeoutSeconds: 5}
+		base.Reason = "offer_callback"
+		traceStep(ctx, "callback", "gather", "callback offer", nil)
+		return base, true, nil
+	}
+	if strings.TrimSpace(accept) != "1" {
+		return Decision{}, false, nil
+	}
+
+	choice, asked := collected[GatherStepCallbackTime]
+	if !asked {
+		base.Action = ActionGather
+		base.Gather = &GatherPrompt{Step: GatherStepCallbackTime, Prompt: orDefault(cfg.TimePrompt, defaultCallbackTime), NumDigits: 1, TimeoutSeconds: 5}
+		base.Reason = "collect_callback_time"
+		return base, true, nil
+	}
+
+	preferredAt := callbackTime(cfg.Timezone, now, strings.TrimSpace(choice))
+	if isDryRun(ctx) {
+		traceStep(ctx, "callback", "would_schedule", in.Inbound.From, map[string]any{"preferred_at": preferredAt.UTC()})
+	} else if _, err := o.Sink.RequestCallback(ctx, in.WorkspaceID, in.CampaignID, in.Inbound.From, preferredAt, rejected.Reason, in.Inbound.ProviderCallID); err != nil && !errors.Is(err, callbacks.ErrDuplicate) {
+		return Decision{}, false, err
+	}
+	base.Action = ActionHangup
+	base.Announcement = orDefault(cfg.ConfirmPrompt, defaultCallbackConfirm)
+	base.Reason = "callback_scheduled"
+	return base, true, nil
+}
+
+// callbackTime maps the caller's choice to a preferred dial time. Unknown input means ASAP.
+func callbackTime(tz string, now time.Time, choice string) time.Time {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		loc = time.UTC
+	}
+	lt := now.In(loc)
+	tomorrow := func(hour int) time.Time {
+		return time.Date(lt.Year(), lt.Month(), lt.Day()+1, hour, 0, 0, 0, loc)
+	}
+	switch choice {
+	case "2":
+		return tomorrow(9)
+	case "3":
+		return tomorrow(14)
+	default:
+		return now
+	}
+}
+
+func orDefault(s, def string) string {
+	if strings.TrimSpace(s) == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,31 @@
+package routing
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryCallbackConfigStore is a simple in-memory CallbackConfigStore useful for tests.
+// It is not intended for production use.
+
+type MemoryCallbackConfigStore struct {
+	mu      sync.Mutex
+	configs map[string]CallbackConfig
+}
+
+func NewMemoryCallbackConfigStore() *MemoryCallbackConfigStore {
+	return &MemoryCallbackConfigStore{configs: map[string]CallbackConfig{}}
+}
+
+func (s *MemoryCallbackConfigStore) SetCallbackConfig(cfg CallbackConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configs[cfg.WorkspaceID+"|"+cfg.CampaignID] = cfg
+}
+
+func (s *MemoryCallbackConfigStore) GetCallbackConfig(ctx context.Context, workspaceID, campaignID string) (CallbackConfig, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg, ok := s.configs[workspaceID+"|"+campaignID]
+	return cfg, ok, nil
+}
@@ -0,0 +1,60 @@
+package routing
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/callbacks"
+	"telecom-platform/internal/telephony"
+)
+
+func TestCallbackOffer_ClosedCampaignSchedulesCallback(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2030, 3, 4, 22, 0, 0, 0, time.UTC) // Monday 17:00 in New York
+	configs := NewMemoryCallbackConfigStore()
+	configs.SetCallbackConfig(CallbackConfig{WorkspaceID: "w", CampaignID: "c", Timezone: "America/New_York"})
+	cbRepo := callbacks.NewMemoryRepo()
+	cbSvc := callbacks.NewService(cbRepo, nil, nil)
+
+	e := NewRoutingEngine(nil, stubCampaigns{ev: CampaignEvaluation{Allowed: false, Reason: "campaign_closed"}}, rand.New(rand.NewSource(1)))
+	e.Now = func() time.Time { return now }
+	e.Callbacks = &CallbackOffer{Configs: configs, Sink: cbSvc}
+
+	route := func(from string, collected map[string]string) Decision {
+		t.Helper()
+		d, err := e.Route(ctx, RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: "w", ProviderCallID: "CA1", From: from, Collected: collected}})
+		if err != nil {
+			t.Fatalf("route: %v", err)
+		}
+		return d
+	}
+
+	if d := route("+15551230000", nil); d.Action != ActionGather || d.Gather.Step != GatherStepCallback {
+		t.Fatalf("expected callback offer, got %+v", d)
+	}
+	if d := route("+15551230000", map[string]string{"callback": ""}); d.Action != ActionReject || d.Reason != "campaign_closed" {
+		t.Fatalf("expected original rejection when declined, got %+v", d)
+	}
+	if d := route("+15551230000", map[string]string{"callback": "1"}); d.Gather == nil || d.Gather.Step != GatherStepCallbackTime {
+		t.Fatalf("expected time prompt, got %+v", d)
+	}
+	d := route("+15551230000", map[string]string{"callback": "1", "callback_time": "2"})
+	if d.Action != ActionHangup || d.Announcement == "" || d.Reason != "callback_scheduled" {
+		t.Fatalf("expected confirmation hangup, got %+v", d)
+	}
+
+	pending, _ := cbSvc.ListCallbacks(ctx, "w", callbacks.ListFilter{Status: callbacks.StatusPending})
+	if len(pending) != 1 {
+		t.Fatalf("expected one pending callback, got %d", len(pending))
+	}
+	want := time.Date(2030, 3, 5, 14, 0, 0, 0, time.UTC) // Tuesday 09:00 EST
+	if cb := pending[0]; cb.Number != "+15551230000" || !cb.PreferredAt.Equal(want) || cb.Reason != "campaign_closed" || cb.SourceProviderCallID != "CA1" {
+		t.Fatalf("unexpected callback: %+v", cb)
+	}
+
+	if d := route("anonymous", nil); d.Action != ActionReject {
+		t.Fatalf("anonymous callers must not be offered a callback, got %+v", d)
+	}
+}
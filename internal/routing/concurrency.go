@@ -0,0 +1,156 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"telecom-platform/pkg/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Per-destination concurrency limits.
+//
+// A target with WeightedDestination.MaxConcurrent > 0 takes at most that many
+// simultaneous calls (e.g. a buyer with five agents). When selection picks a
+// target, the engine acquires one of its slots for the call; a target at its
+// limit is skipped and selection runs again over the rest, backing out of a
+// destination group whose targets are all full. The slot is held by the provider
+// call ID and released when the provider reports the call ended (status callback,
+// see telephony.TwilioWebhookHandler.HandleCallStatus), or right away when
+// routing fails after taking it. Slots expire after SlotTTL in case that
+// callback never arrives. Privileged (admin override) routing selects the same
+// way, so forced connects count against limits too.
+//
+// Failover alternates skip targets that are full at routing time; the decision
+// lists their limits (Decision.AlternateLimits) so that, when the call fails
+// over, the webhook releases the primary's slot and acquires the alternate's
+// before dialing it (telephony.TwilioWebhookHandler.HandleDialStatus).
+
+// DefaultSlotTTL bounds how long a slot is held without a status callback.
+const DefaultSlotTTL = 4 * time.Hour
+
+// ConcurrencySlots counts in-progress calls per target.
+type ConcurrencySlots interface {
+	// Acquire takes a slot on target for callID if fewer than limit are in use.
+	// Acquiring again for a call that already holds the slot succeeds without
+	// taking another.
+	Acquire(ctx context.Context, workspaceID, target, callID string, limit int) (bool, error)
+	// Release frees every slot callID holds; releasing twice is a no-op.
+	Release(ctx context.Context, workspaceID, callID string) error
+	// InUse returns the slots in use on target.
+	InUse(ctx context.Context, workspaceID, target string) (int, error)
+}
+
+// RedisConcurrencySlots implements ConcurrencySlots on utils.AcquireConcurrencyCap
+// counters, one per target, plus a per-call set of the counters the call holds.
+type RedisConcurrencySlots struct {
+	Client *redis.Client
+	// SlotTTL defaults to DefaultSlotTTL.
+	SlotTTL time.Duration
+}
+
+var _ ConcurrencySlots = RedisConcurrencySlots{}
+
+func (r RedisConcurrencySlots) Acquire(ctx context.Context, workspaceID, target, callID string, limit int) (bool, error) {
+	if r.Client == nil {
+		return false, errors.New("routing: redis client is nil")
+	}
+	slot, held := slotKey(workspaceID, target), slotHolderKey(workspaceID, callID)
+	if ok, err := r.Client.SIsMember(ctx, held, slot).Result(); err != nil || ok {
+		return ok, err
+	}
+	ok, err := utils.AcquireConcurrencyCap(ctx, r.Client, slot, limit, r.ttl())
+	if err != nil || !ok {
+		return false, err
+	}
+	pipe := r.Client.TxPipeline()
+	pipe.SAdd(ctx, held, slot)
+	pipe.PExpire(ctx, held, r.ttl())
+	if _, err := pipe.Exec(ctx); err != nil {
+		// Give the slot back rather than leak it until the TTL.
+		_ = utils.ReleaseConcurrencyCap(ctx, r.Client, slot)
+		return false, err
+	}
+	return true, nil
+}
+
+func (r RedisConcurrencySlots) Release(ctx context.Context, workspaceID, callID string) error {
+	if r.Client == nil {
+		return errors.New("routing: redis client is nil")
+	}
+	// Read and delete together so duplicate callbacks release only once.
+	held := slotHolderKey(workspaceID, callID)
+	pipe := r.Client.TxPipeline()
+	members := pipe.SMembers(ctx, held)
+	pipe.Del(ctx, held)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	for _, slot := range members.Val() {
+		if err := utils.ReleaseConcurrencyCap(ctx, r.Client, slot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r RedisConcurrencySlots) InUse(ctx context.Context, workspaceID, target string) (int, error) {
+	if r.Client == nil {
+		return 0, errors.New("routing: redis client is nil")
+	}
+	n, err := r.Client.Get(ctx, slotKey(workspaceID, target)).Int()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return n, err
+}
+
+func (r RedisConcurrencySlots) ttl() time.Duration {
+	if r.SlotTTL > 0 {
+		return r.SlotTTL
+	}
+	return DefaultSlotTTL
+}
+
+func slotKey(workspaceID, target string) string {
+	return "routing:concurrency:" + workspaceID + ":" + target
+}
+
+func slotHolderKey(workspaceID, callID string) string {
+	return "routing:concurrency:call:" + workspaceID + ":" + callID
+}
+
+// acquireSlot takes a slot on a capped target for the call. Targets without a
+// limit, or engines without ConcurrencySlots, always pass. Dry runs and calls
+// without a provider call ID (nothing could release the slot) only check
+// current use.
+func (e *RoutingEngine) acquireSlot(ctx context.Context, workspaceID, callID string, d WeightedDestination) (bool, error) {
+	if d.MaxConcurrent <= 0 || d.TargetURI == "" || e.Concurrency == nil {
+		return true, nil
+	}
+	if isDryRun(ctx) || callID == "" {
+		n, err := e.Concurrency.InUse(ctx, workspaceID, d.TargetURI)
+		return n < d.MaxConcurrent, err
+	}
+	return e.Concurrency.Acquire(ctx, workspaceID, d.TargetURI, callID, d.MaxConcurrent)
+}
+
+// releaseSlots frees the slots a call took during a routing attempt that did
+// not produce a decision.
+func (e *RoutingEngine) releaseSlots(ctx context.Context, workspaceID, callID string) error {
+	if e.Concurrency == nil || callID == "" || isDryRun(ctx) {
+		return nil
+	}
+	return e.Concurrency.Release(ctx, workspaceID, callID)
+}
+
+// atCapacity reports whether a capped target has no free slot right now.
+func (e *RoutingEngine) atCapacity(ctx context.Context, workspaceID string, d WeightedDestination) (bool, error) {
+	if d.MaxConcurrent <= 0 || d.TargetURI == "" || e.Concurrency == nil {
+		return false, nil
+	}
+	n, err := e.Concurrency.InUse(ctx, workspaceID, d.TargetURI)
+	return n >= d.MaxConcurrent, err
+}
@@ -0,0 +1,96 @@
+package routing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryConcurrencySlots is an in-memory ConcurrencySlots for tests and
+// single-instance deployments (state is per process). Like the Redis store, a
+// call's slots are leased for SlotTTL so a missing status callback cannot hold
+// them forever.
+
+type MemoryConcurrencySlots struct {
+	// SlotTTL defaults to DefaultSlotTTL.
+	SlotTTL time.Duration
+	Now     func() time.Time
+
+	mu      sync.Mutex
+	inUse   map[string]int
+	held    map[string]map[string]bool // workspace|call -> slot keys
+	expires map[string]time.Time       // workspace|call -> lease end
+}
+
+func NewMemoryConcurrencySlots() *MemoryConcurrencySlots {
+	return &MemoryConcurrencySlots{inUse: map[string]int{}, held: map[string]map[string]bool{}, expires: map[string]time.Time{}}
+}
+
+func (s *MemoryConcurrencySlots) Acquire(ctx context.Context, workspaceID, target, callID string, limit int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.expire(now)
+	slot, call := slotKey(workspaceID, target), workspaceID+"|"+callID
+	if s.held[call][slot] {
+		return true, nil
+	}
+	if s.inUse[slot] >= limit {
+		return false, nil
+	}
+	s.inUse[slot]++
+	if s.held[call] == nil {
+		s.held[call] = map[string]bool{}
+	}
+	s.held[call][slot] = true
+	s.expires[call] = now.Add(s.ttl())
+	return true, nil
+}
+
+func (s *MemoryConcurrencySlots) Release(ctx context.Context, workspaceID, callID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.release(workspaceID + "|" + callID)
+	return nil
+}
+
+func (s *MemoryConcurrencySlots) InUse(ctx context.Context, workspaceID, target string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(s.now())
+	return s.inUse[slotKey(workspaceID, target)], nil
+}
+
+// expire releases every call whose lease has ended. Callers hold mu.
+func (s *MemoryConcurrencySlots) expire(now time.Time) {
+	for call, until := range s.expires {
+		if !now.Before(until) {
+			s.release(call)
+		}
+	}
+}
+
+// release frees the slots held by call (workspace|call). Callers hold mu.
+func (s *MemoryConcurrencySlots) release(call string) {
+	for slot := range s.held[call] {
+		if s.inUse[slot]--; s.inUse[slot] <= 0 {
+			delete(s.inUse, slot)
+		}
+	}
+	delete(s.held, call)
+	delete(s.expires, call)
+}
+
+func (s *MemoryConcurrencySlots) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func (s *MemoryConcurrencySlots) ttl() time.Duration {
+	if s.SlotTTL > 0 {
+		return s.SlotTTL
+	}
+	return DefaultSlotTTL
+}
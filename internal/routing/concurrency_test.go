@@ -0,0 +1,139 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/telephony"
+)
+
+func TestConcurrency_SkipsFullTargetsUntilReleased(t *testing.T) {
+	ctx := context.Background()
+	groups := NewMemoryDestinationGroupStore()
+	_ = groups.PutGroup(ctx, DestinationGroup{ID: "g", WorkspaceID: "w", Name: "buyer", Version: 1, Members: []WeightedDestination{
+		{TargetURI: "+15550002", Weight: 1, MaxConcurrent: 1},
+	}})
+	campaign := stubCampaigns{ev: CampaignEvaluation{Allowed: true, Strategy: StrategyRoundRobin, Destinations: []WeightedDestination{
+		{TargetURI: "+15550001", Weight: 1, MaxConcurrent: 1},
+		{GroupID: "g", Weight: 1},
+	}}}
+	e := NewRoutingEngine(nil, campaign, rand.New(rand.NewSource(1)))
+	e.Selection = NewMemorySelectionState()
+	e.Groups = groups
+	slots := NewMemoryConcurrencySlots()
+	e.Concurrency = slots
+	call := func(id string) RouteInput {
+		return RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: "w", ProviderCallID: id, From: "+1", To: "+2"}}
+	}
+
+	d1, err := e.Route(ctx, call("CA1"))
+	if err != nil || d1.ConnectTo != "+15550001" {
+		t.Fatalf("call 1: %+v err=%v", d1, err)
+	}
+	// Routing the same call again (a retried webhook) does not take a second slot.
+	if d, _ := e.Route(ctx, call("CA1")); d.ConnectTo != "+15550002" {
+		t.Fatalf("retry: expected rotation to the group, got %+v", d)
+	}
+	if n, _ := slots.InUse(ctx, "w", "+15550001"); n != 1 {
+		t.Fatalf("expected one slot on +15550001, got %d", n)
+	}
+
+	// Both targets are now held by CA1; a new call has nowhere to go.
+	d3, err := e.Route(ctx, call("CA3"))
+	if err != nil || d3.Action != ActionReject || d3.Reason != "no_eligible_destination" {
+		t.Fatalf("expected reject while full, got %+v err=%v", d3, err)
+	}
+
+	if err := slots.Release(ctx, "w", "CA1"); err != nil {
+		t.Fatal(err)
+	}
+	_ = slots.Release(ctx, "w", "CA1") // duplicate callback
+	if n, _ := slots.InUse(ctx, "w", "+15550001"); n != 0 {
+		t.Fatalf("expected slot released once, got %d in use", n)
+	}
+	if d, _ := e.Route(ctx, call("CA4")); d.Action != ActionConnect {
+		t.Fatalf("expected connect after release, got %+v", d)
+	}
+}
+
+func TestMemoryConcurrencySlots_LeaseExpires(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	slots := NewMemoryConcurrencySlots()
+	slots.SlotTTL = time.Hour
+	slots.Now = func() time.Time { return now }
+
+	if ok, _ := slots.Acquire(ctx, "w", "+15550001", "CA1", 1); !ok {
+		t.Fatalf("expected first slot")
+	}
+	if ok, _ := slots.Acquire(ctx, "w", "+15550001", "CA2", 1); ok {
+		t.Fatalf("expected target full")
+	}
+	// The status callback for CA1 never arrives; its lease runs out.
+	now = now.Add(time.Hour)
+	if n, _ := slots.InUse(ctx, "w", "+15550001"); n != 0 {
+		t.Fatalf("expected expired slot reclaimed, got %d in use", n)
+	}
+	if ok, _ := slots.Acquire(ctx, "w", "+15550001", "CA2", 1); !ok {
+		t.Fatalf("expected slot free after lease expiry")
+	}
+}
+
+type failingGroups struct{ *MemoryDestinationGroupStore }
+
+func (failingGroups) GetGroup(ctx context.Context, workspaceID, groupID string) (DestinationGroup, bool, error) {
+	return DestinationGroup{}, false, errors.New("store down")
+}
+
+func TestConcurrency_ReleasesSlotsWhenRoutingFails(t *testing.T) {
+	ctx := context.Background()
+	campaign := stubCampaigns{ev: CampaignEvaluation{Allowed: true, Strategy: StrategyRoundRobin, Destinations: []WeightedDestination{
+		{TargetURI: "+15550001", Weight: 1, MaxConcurrent: 1},
+		{GroupID: "g", Weight: 1},
+	}}}
+	e := NewRoutingEngine(nil, campaign, rand.New(rand.NewSource(1)))
+	e.Selection = NewMemorySelectionState()
+	e.Groups = failingGroups{NewMemoryDestinationGroupStore()}
+	slots := NewMemoryConcurrencySlots()
+	e.Concurrency = slots
+
+	// The primary's slot is taken, then building the failover chain fails.
+	in := RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: "w", ProviderCallID: "CA1"}}
+	if _, err := e.RouteWithFailover(ctx, in, 1); err == nil {
+		t.Fatalf("expected routing error")
+	}
+	if n, _ := slots.InUse(ctx, "w", "+15550001"); n != 0 {
+		t.Fatalf("expected slot released after failed routing, got %d in use", n)
+	}
+}
+
+func TestConcurrency_AlternatesCarryLimits(t *testing.T) {
+	ctx := context.Background()
+	campaign := stubCampaigns{ev: CampaignEvaluation{Allowed: true, Strategy: StrategyRoundRobin, Destinations: []WeightedDestination{
+		{TargetURI: "+15550001", Weight: 1, MaxConcurrent: 1},
+		{TargetURI: "+15550002", Weight: 1, MaxConcurrent: 3},
+		{TargetURI: "+15550003", Weight: 1},
+	}}}
+	e := NewRoutingEngine(nil, campaign, rand.New(rand.NewSource(1)))
+	e.Selection = NewMemorySelectionState()
+	e.Concurrency = NewMemoryConcurrencySlots()
+
+	in := RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: "w", ProviderCallID: "CA1"}}
+	d, err := e.RouteWithFailover(ctx, in, 2)
+	if err != nil || len(d.Alternates) != 2 || len(d.AlternateLimits) != 2 {
+		t.Fatalf("expected two alternates with limits, got %+v err=%v", d, err)
+	}
+	want := map[string]int{"+15550002": 3, "+15550003": 0}
+	for i, alt := range d.Alternates {
+		if d.AlternateLimits[i] != want[alt] {
+			t.Fatalf("alternate %s: expected limit %d, got %d", alt, want[alt], d.AlternateLimits[i])
+		}
+	}
+	// Only the primary holds a slot; alternates are reserved on failover.
+	if n, _ := e.Concurrency.InUse(ctx, "w", "+15550002"); n != 0 {
+		t.Fatalf("expected no slot on alternate yet, got %d", n)
+	}
+}
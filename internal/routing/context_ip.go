@@ -2,26 +2,20 @@ package routing
 
 import (
 	"context"
+
+	"telecom-platform/pkg/utils"
 )
 
-// clientIPKey is an unexported context key for passing client IP through internal layers.
+// Client IP is carried on the request context (see utils.WithClientIP).
 //
-// Provider HTTP handlers (Gin) should resolve the real client IP and attach it to
-// the request context using WithClientIP.
-
-type clientIPKey struct{}
+// The HTTP layer resolves the real client IP behind trusted proxies once per request
+// (httpapi.ClientIPMiddleware); these wrappers keep existing routing call sites working
+// and share the same context key so audit picks the value up as well.
 
 func WithClientIP(ctx context.Context, ip string) context.Context {
-	if ip == "" {
-		return ctx
-	}
-	return context.WithValue(ctx, clientIPKey{}, ip)
+	return utils.WithClientIP(ctx, ip)
 }
 
 func ClientIPFromContext(ctx context.Context) string {
-	v := ctx.Value(clientIPKey{})
-	if s, ok := v.(string); ok {
-		return s
-	}
-	return ""
+	return utils.ClientIP(ctx)
 }
@@ -1,5 +1,7 @@
 package routing
 
+import "time"
+
 // Decision is the provider-agnostic output of the routing engine.
 //
 // It must contain *only* information required for the provider adapter boundary
@@ -13,15 +15,60 @@ type Decision struct {
 
 	Action    Action `json:"action"`
 	ConnectTo string `json:"connect_to,omitempty"`
+	// Alternates are re-dialed in order when ConnectTo is busy or does not
+	// answer (see RoutingEngine.RouteWithFailover).
+	Alternates []string `json:"alternates,omitempty"`
+	// AlternateLimits is each alternate's MaxConcurrent (0: uncapped), so its
+	// slot can be acquired when the call fails over to it.
+	AlternateLimits []int `json:"alternate_limits,omitempty"`
+	// SIPHeaders are custom (X-) headers sent when ConnectTo is a SIP URI.
+	SIPHeaders map[string]string `json:"sip_headers,omitempty"`
+
+	// Gather is set when Action == "gather": collect caller input, then route again.
+	Gather *GatherPrompt `json:"gather,omitempty"`
+
+	// Language is the caller's selected language (BCP 47, e.g. "es-US"), if any.
+	Language string `json:"language,omitempty"`
+
+	// Announcement is read before a hangup (e.g. callback confirmation).
+	Announcement string `json:"announcement,omitempty"`
+
+	// PacedAt and PacingReservedMinor identify the campaign pacing reservation
+	// made for a connected call. They are platform bookkeeping, not adapter
+	// input: hand them to billing (billing.ActiveCall) so the reservation is
+	// reconciled to the actual cost.
+	PacedAt             *time.Time `json:"-"`
+	PacingReservedMinor int64      `json:"-"`
 
 	// Reason is optional and intended for internal logs/metrics.
 	Reason string `json:"reason,omitempty"`
 }
 
+// GatherPrompt is an IVR step asking the caller for DTMF digits.
+type GatherPrompt struct {
+	Step           string `json:"step"`
+	Prompt         string `json:"prompt"`
+	NumDigits      int    `json:"num_digits,omitempty"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+
+	// Language is the TTS language for Prompt.
+	Language string `json:"language,omitempty"`
+	// Segments, when set, replace Prompt (e.g. a menu read in several languages).
+	Segments []PromptSegment `json:"segments,omitempty"`
+}
+
+// PromptSegment is one piece of a prompt: an audio file, or text read by TTS in Language.
+type PromptSegment struct {
+	Text     string `json:"text,omitempty"`
+	Language string `json:"language,omitempty"`
+	AudioURL string `json:"audio_url,omitempty"`
+}
+
 type Action string
 
 const (
 	ActionReject  Action = "reject"
 	ActionConnect Action = "connect"
 	ActionHangup  Action = "hangup"
+	ActionGather  Action = "gather"
 )
@@ -0,0 +1,120 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"telecom-platform/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Routing decision log.
+//
+// With RoutingEngine.Decisions set, every Route and RouteWithFailover call is
+// recorded once it returns: the decision (action, reason, chosen destination) or
+// the error, and how long routing took. Ops query the log to answer "why was this
+// call rejected at 9:03?"; the dry-run trace (DryRun) explains a decision in more
+// depth. Dry runs are not recorded. A failed write is logged and does not fail
+// the call.
+
+// DecisionRecord is one recorded routing decision.
+type DecisionRecord struct {
+	ID             string `json:"id" db:"id"`
+	WorkspaceID    string `json:"workspace_id" db:"workspace_id"`
+	CampaignID     string `json:"campaign_id,omitempty" db:"campaign_id"`
+	ProviderCallID string `json:"provider_call_id,omitempty" db:"provider_call_id"`
+	From           string `json:"from,omitempty" db:"from_number"`
+	To             string `json:"to,omitempty" db:"to_number"`
+
+	// Action is empty when routing failed with Error.
+	Action    Action `json:"action,omitempty" db:"action"`
+	Reason    string `json:"reason,omitempty" db:"reason"`
+	ConnectTo string `json:"connect_to,omitempty" db:"connect_to"`
+	Error     string `json:"error,omitempty" db:"error"`
+
+	LatencyMicros int64     `json:"latency_us" db:"latency_us"`
+	DecidedAt     time.Time `json:"decided_at" db:"decided_at"`
+}
+
+// DecisionFilter narrows ListDecisions. Empty fields match everything; Since and
+// Until bound DecidedAt (Until exclusive).
+type DecisionFilter struct {
+	CampaignID     string
+	ProviderCallID string
+	Action         Action
+	Reason         string
+	Since          time.Time
+	Until          time.Time
+	Limit          int
+}
+
+var (
+	ErrInvalidDecisionFilter    = errors.New("routing: invalid decision filter")
+	errDecisionLogNotConfigured = errors.New("routing: decision log not configured")
+)
+
+const (
+	DefaultDecisionListLimit = 100
+	MaxDecisionListLimit     = 1000
+)
+
+// DecisionLog stores routing decisions. Implementations must enforce workspace
+// filtering and list newest first.
+type DecisionLog interface {
+	RecordDecision(ctx context.Context, r DecisionRecord) error
+	ListDecisions(ctx context.Context, workspaceID string, f DecisionFilter) ([]DecisionRecord, error)
+}
+
+// ListDecisions returns the workspace's recorded decisions matching f.
+func (e *RoutingEngine) ListDecisions(ctx context.Context, workspaceID string, f DecisionFilter) ([]DecisionRecord, error) {
+	if workspaceID == "" || f.Limit < 0 {
+		return nil, ErrInvalidDecisionFilter
+	}
+	if e.Decisions == nil {
+		return nil, errDecisionLogNotConfigured
+	}
+	if f.Limit == 0 {
+		f.Limit = DefaultDecisionListLimit
+	}
+	if f.Limit > MaxDecisionListLimit {
+		f.Limit = MaxDecisionListLimit
+	}
+	return e.Decisions.ListDecisions(ctx, workspaceID, f)
+}
+
+// recordDecision writes the outcome of one routing call.
+func (e *RoutingEngine) recordDecision(ctx context.Context, in RouteInput, d Decision, routeErr error, latency time.Duration) {
+	if e.Decisions == nil || isDryRun(ctx) {
+		return
+	}
+	now := time.Now
+	if e.Now != nil {
+		now = e.Now
+	}
+	r := DecisionRecord{
+		ID:             uuid.NewString(),
+		WorkspaceID:    in.WorkspaceID,
+		CampaignID:     in.CampaignID,
+		ProviderCallID: in.Inbound.ProviderCallID,
+		From:           in.Inbound.From,
+		To:             in.Inbound.To,
+		LatencyMicros:  latency.Microseconds(),
+		DecidedAt:      now().UTC(),
+	}
+	if routeErr != nil {
+		r.Error = routeErr.Error()
+	} else {
+		r.Action, r.Reason, r.ConnectTo = d.Action, d.Reason, d.ConnectTo
+		if d.CampaignID != "" {
+			r.CampaignID = d.CampaignID
+		}
+	}
+	if r.WorkspaceID == "" {
+		return
+	}
+	if err := e.Decisions.RecordDecision(ctx, r); err != nil {
+		logger.From(ctx).Warn("routing decision not recorded", "workspace_id", r.WorkspaceID, "provider_call_id", r.ProviderCallID, "err", err)
+	}
+}
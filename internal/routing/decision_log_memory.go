@@ -0,0 +1,46 @@
+package routing
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryDecisionLog is a simple in-memory DecisionLog useful for tests.
+// It is not intended for production use.
+
+type MemoryDecisionLog struct {
+	mu      sync.Mutex
+	records []DecisionRecord
+}
+
+func NewMemoryDecisionLog() *MemoryDecisionLog {
+	return &MemoryDecisionLog{}
+}
+
+func (l *MemoryDecisionLog) RecordDecision(ctx context.Context, r DecisionRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, r)
+	return nil
+}
+
+func (l *MemoryDecisionLog) ListDecisions(ctx context.Context, workspaceID string, f DecisionFilter) ([]DecisionRecord, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var out []DecisionRecord
+	for i := len(l.records) - 1; i >= 0 && (f.Limit <= 0 || len(out) < f.Limit); i-- {
+		r := l.records[i]
+		switch {
+		case r.WorkspaceID != workspaceID,
+			f.CampaignID != "" && r.CampaignID != f.CampaignID,
+			f.ProviderCallID != "" && r.ProviderCallID != f.ProviderCallID,
+			f.Action != "" && r.Action != f.Action,
+			f.Reason != "" && r.Reason != f.Reason,
+			!f.Since.IsZero() && r.DecidedAt.Before(f.Since),
+			!f.Until.IsZero() && !r.DecidedAt.Before(f.Until):
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
@@ -0,0 +1,105 @@
+package routing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"telecom-platform/pkg/utils"
+)
+
+// PostgresDecisionLog stores decisions in the routing_decisions table:
+//
+//	routing_decisions (id uuid PRIMARY KEY, workspace_id, campaign_id, provider_call_id,
+//	  from_number, to_number, action, reason, connect_to, error text NOT NULL DEFAULT '',
+//	  latency_us bigint, decided_at timestamptz)
+//	INDEX (workspace_id, decided_at DESC)
+type PostgresDecisionLog struct {
+	DB *sql.DB
+}
+
+var _ DecisionLog = PostgresDecisionLog{}
+
+// decisionWriteBudget keeps the write on the call path short.
+var decisionWriteBudget = utils.QueryBudget{StatementTimeout: 500 * time.Millisecond, SlowThreshold: 100 * time.Millisecond}
+
+func (l PostgresDecisionLog) RecordDecision(ctx context.Context, r DecisionRecord) (err error) {
+	if l.DB == nil {
+		return errors.New("routing: decision log db is nil")
+	}
+	ctx, done := utils.TrackQuery(utils.WithQuery(ctx, "routing", "RecordDecision", decisionWriteBudget))
+	defer func() { done(err) }()
+	_, err = l.DB.ExecContext(ctx, `
+INSERT INTO routing_decisions (id, workspace_id, campaign_id, provider_call_id, from_number, to_number,
+                               action, reason, connect_to, error, latency_us, decided_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		r.ID, r.WorkspaceID, r.CampaignID, r.ProviderCallID, r.From, r.To,
+		string(r.Action), r.Reason, r.ConnectTo, r.Error, r.LatencyMicros, r.DecidedAt)
+	return err
+}
+
+func (l PostgresDecisionLog) ListDecisions(ctx context.Context, workspaceID string, f DecisionFilter) (out []DecisionRecord, err error) {
+	if l.DB == nil {
+		return nil, errors.New("routing: decision log db is nil")
+	}
+	ctx, done := utils.TrackQuery(utils.WithQuery(ctx, "routing", "ListDecisions", utils.ReportBudget))
+	defer func() { done(err) }()
+
+	where := []string{"workspace_id = $1"}
+	args := []any{workspaceID}
+	add := func(cond string, v any) {
+		args = append(args, v)
+		where = append(where, strings.ReplaceAll(cond, "?", "$"+strconv.Itoa(len(args))))
+	}
+	if f.CampaignID != "" {
+		add("campaign_id = ?", f.CampaignID)
+	}
+	if f.ProviderCallID != "" {
+		add("provider_call_id = ?", f.ProviderCallID)
+	}
+	if f.Action != "" {
+		add("action = ?", string(f.Action))
+	}
+	if f.Reason != "" {
+		add("reason = ?", f.Reason)
+	}
+	if !f.Since.IsZero() {
+		add("decided_at >= ?", f.Since)
+	}
+	if !f.Until.IsZero() {
+		add("decided_at < ?", f.Until)
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = DefaultDecisionListLimit
+	}
+	args = append(args, limit)
+	query := `
+SELECT id, workspace_id, campaign_id, provider_call_id, from_number, to_number,
+       action, reason, connect_to, error, latency_us, decided_at
+FROM routing_decisions
+WHERE ` + strings.Join(where, " AND ") + `
+ORDER BY decided_at DESC
+LIMIT $` + strconv.Itoa(len(args))
+
+	rows, err := l.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out = make([]DecisionRecord, 0)
+	for rows.Next() {
+		var r DecisionRecord
+		var action string
+		if err := rows.Scan(&r.ID, &r.WorkspaceID, &r.CampaignID, &r.ProviderCallID, &r.From, &r.To,
+			&action, &r.Reason, &r.ConnectTo, &r.Error, &r.LatencyMicros, &r.DecidedAt); err != nil {
+			return nil, err
+		}
+		r.Action = Action(action)
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
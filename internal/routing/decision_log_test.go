@@ -0,0 +1,62 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/telephony"
+)
+
+type failingCampaigns struct{}
+
+func (failingCampaigns) EvaluateInbound(ctx context.Context, workspaceID, campaignID string, req telephony.InboundCallRequest) (CampaignEvaluation, error) {
+	return CampaignEvaluation{}, errors.New("campaign store down")
+}
+
+func TestDecisionLog_RecordsRouteOutcomes(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2024, 3, 5, 9, 3, 0, 0, time.UTC)
+	log := NewMemoryDecisionLog()
+	e := NewRoutingEngine(nil, stubCampaigns{ev: CampaignEvaluation{Allowed: true, Destinations: []WeightedDestination{{TargetURI: "+15550001", Weight: 1}}}}, rand.New(rand.NewSource(1)))
+	e.Now = func() time.Time { return at }
+	e.Decisions = log
+	call := func(id, campaignID string) RouteInput {
+		return RouteInput{WorkspaceID: "w", CampaignID: campaignID, Inbound: telephony.InboundCallRequest{WorkspaceID: "w", ProviderCallID: id, From: "+15551230000", To: "+15550000000"}}
+	}
+
+	if _, err := e.Route(ctx, call("CA1", "c1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Route(ctx, call("CA2", "")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.DryRun(ctx, call("CA3", "c1"), at); err != nil {
+		t.Fatal(err)
+	}
+	failing := *e
+	failing.Campaigns = failingCampaigns{}
+	if _, err := failing.Route(ctx, call("CA4", "c1")); err == nil {
+		t.Fatalf("expected routing error")
+	}
+
+	all, err := e.ListDecisions(ctx, "w", DecisionFilter{})
+	if err != nil || len(all) != 3 {
+		t.Fatalf("expected 3 recorded decisions (no dry run), got %+v err=%v", all, err)
+	}
+	if all[0].ProviderCallID != "CA4" || all[0].Error == "" || all[0].Action != "" {
+		t.Fatalf("expected newest first with the routing error, got %+v", all[0])
+	}
+	rejected, _ := e.ListDecisions(ctx, "w", DecisionFilter{Action: ActionReject, Since: at.Add(-time.Minute), Until: at.Add(time.Minute)})
+	if len(rejected) != 1 || rejected[0].ProviderCallID != "CA2" || rejected[0].Reason != "campaign_id_required" {
+		t.Fatalf("expected the rejected call, got %+v", rejected)
+	}
+	if got, _ := e.ListDecisions(ctx, "w", DecisionFilter{CampaignID: "c1", Action: ActionConnect}); len(got) != 1 || got[0].ConnectTo != "+15550001" {
+		t.Fatalf("expected the connected call, got %+v", got)
+	}
+	if got, _ := e.ListDecisions(ctx, "other", DecisionFilter{}); len(got) != 0 {
+		t.Fatalf("expected workspace filtering, got %+v", got)
+	}
+}
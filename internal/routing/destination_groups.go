@@ -0,0 +1,295 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/audit"
+
+	"github.com/google/uuid"
+)
+
+// Destination groups.
+//
+// A destination group is a named, workspace-level pool of weighted targets (e.g. one
+// buyer's call centers). Campaigns reference a group via WeightedDestination.GroupID
+// instead of copying targets, so changing a buyer's number once updates every campaign.
+//
+// Groups may nest (a member can itself reference a group). Selection is hierarchical:
+// the engine picks among the campaign's entries by weight; if the winner is a group it
+// picks among that group's members by their own weights, and so on.
+//
+// Consistency rules enforced on write:
+// - members reference groups in the same workspace that exist;
+// - no reference cycles and nesting depth <= MaxGroupDepth;
+// - a group still referenced by another group or a campaign cannot be deleted.
+
+const MaxGroupDepth = 4
+
+var (
+	ErrInvalidGroup  = errors.New("routing: invalid destination group")
+	ErrGroupNotFound = errors.New("routing: destination group not found")
+	ErrGroupCycle    = errors.New("routing: destination group reference cycle")
+	ErrGroupInUse    = errors.New("routing: destination group is still referenced")
+)
+
+// DestinationGroup is a reusable pool of weighted targets.
+type DestinationGroup struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	Name        string `json:"name" db:"name"`
+
+	// Members are stored as JSONB in Postgres.
+	Members []WeightedDestination `json:"members" db:"members"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	// Version increments on every write (see version.go).
+	Version int64 `json:"version" db:"version"`
+}
+
+// DestinationGroupStore persists destination groups.
+// Implementations must enforce workspace filtering.
+type DestinationGroupStore interface {
+	GetGroup(ctx context.Context, workspaceID, groupID string) (DestinationGroup, bool, error)
+	ListGroups(ctx context.Context, workspaceID string) ([]DestinationGroup, error)
+	// PutGroup must reject with ErrVersionConflict unless the stored version is g.Version-1.
+	PutGroup(ctx context.Context, g DestinationGroup) error
+	DeleteGroup(ctx context.Context, workspaceID, groupID string) error
+}
+
+// GroupReferences reports campaigns that reference a group (optional; used to block deletes).
+type GroupReferences interface {
+	CampaignsUsingGroup(ctx context.Context, workspaceID, groupID string) ([]string, error)
+}
+
+// DestinationGroupService manages destination groups.
+type DestinationGroupService struct {
+	Store      DestinationGroupStore
+	References GroupReferences
+	Audit      *audit.Service
+	Now        func() time.Time
+}
+
+func NewDestinationGroupService(store DestinationGroupStore, auditSvc *audit.Service) *DestinationGroupService {
+	return &DestinationGroupService{Store: store, Audit: auditSvc, Now: time.Now}
+}
+
+type DestinationGroupRequest struct {
+	Name    string
+	Members []WeightedDestination
+
+	// IfVersion is the version the caller last read; Update rejects stale edits
+	// with ErrVersionConflict. 0 skips the check.
+	IfVersion int64
+}
+
+// Create stores a new group after validating its members.
+func (s *DestinationGroupService) Create(ctx context.Context, workspaceID, actorUserID, actorRole string, req DestinationGroupRequest) (DestinationGroup, error) {
+	if workspaceID == "" || actorUserID == "" {
+		return DestinationGroup{}, ErrInvalidGroup
+	}
+	if s.Store == nil {
+		return DestinationGroup{}, errors.New("routing: destination group store not configured")
+	}
+	now := s.now()
+	g := DestinationGroup{
+		ID:          uuid.NewString(),
+		WorkspaceID: workspaceID,
+		Name:        strings.TrimSpace(req.Name),
+		Members:     req.Members,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Version:     1,
+	}
+	if err := s.validate(ctx, g); err != nil {
+		return DestinationGroup{}, err
+	}
+	if err := s.Store.PutGroup(ctx, g); err != nil {
+		return DestinationGroup{}, err
+	}
+	s.audit(ctx, g, actorUserID, actorRole, "destination group created")
+	return g, nil
+}
+
+// Update replaces a group's name and members. Every campaign referencing the group
+// routes to the new members on its next call.
+func (s *DestinationGroupService) Update(ctx context.Context, workspaceID, groupID, actorUserID, actorRole string, req DestinationGroupRequest) (DestinationGroup, error) {
+	if workspaceID == "" || groupID == "" || actorUserID == "" {
+		return DestinationGroup{}, ErrInvalidGroup
+	}
+	if s.Store == nil {
+		return DestinationGroup{}, errors.New("routing: destination group store not configured")
+	}
+	g, ok, err := s.Store.GetGroup(ctx, workspaceID, groupID)
+	if err != nil {
+		return DestinationGroup{}, err
+	}
+	if !ok {
+		return DestinationGroup{}, ErrGroupNotFound
+	}
+	if g.Version, err = nextVersion(g.Version, req.IfVersion); err != nil {
+		return DestinationGroup{}, err
+	}
+	g.Name = strings.TrimSpace(req.Name)
+	g.Members = req.Members
+	g.UpdatedAt = s.now()
+	if err := s.validate(ctx, g); err != nil {
+		return DestinationGroup{}, err
+	}
+	if err := s.Store.PutGroup(ctx, g); err != nil {
+		return DestinationGroup{}, err
+	}
+	s.audit(ctx, g, actorUserID, actorRole, "destination group updated")
+	return g, nil
+}
+
+// Delete removes a group that no other group or campaign references.
+func (s *DestinationGroupService) Delete(ctx context.Context, workspaceID, groupID, actorUserID, actorRole string) error {
+	if workspaceID == "" || groupID == "" || actorUserID == "" {
+		return ErrInvalidGroup
+	}
+	if s.Store == nil {
+		return errors.New("routing: destination group store not configured")
+	}
+	g, ok, err := s.Store.GetGroup(ctx, workspaceID, groupID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrGroupNotFound
+	}
+	all, err := s.Store.ListGroups(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	for _, other := range all {
+		for _, m := range other.Members {
+			if m.GroupID == groupID {
+				return ErrGroupInUse
+			}
+		}
+	}
+	if s.References != nil {
+		campaigns, err := s.References.CampaignsUsingGroup(ctx, workspaceID, groupID)
+		if err != nil {
+			return err
+		}
+		if len(campaigns) > 0 {
+			return ErrGroupInUse
+		}
+	}
+	if err := s.Store.DeleteGroup(ctx, workspaceID, groupID); err != nil {
+		return err
+	}
+	s.audit(ctx, g, actorUserID, actorRole, "destination group deleted")
+	return nil
+}
+
+// ValidateDestinations checks a campaign's destination list: group references must
+// exist in the workspace. Campaign writers should call this before saving.
+func (s *DestinationGroupService) ValidateDestinations(ctx context.Context, workspaceID string, dests []WeightedDestination) error {
+	if !hasEligibleDestination(dests) {
+		return ErrInvalidGroup
+	}
+	for _, d := range dests {
+		if err := validateMemberShape(d); err != nil {
+			return err
+		}
+		if d.GroupID == "" {
+			continue
+		}
+		if s.Store == nil {
+			return errors.New("routing: destination group store not configured")
+		}
+		if _, ok, err := s.Store.GetGroup(ctx, workspaceID, d.GroupID); err != nil {
+			return err
+		} else if !ok {
+			return ErrGroupNotFound
+		}
+	}
+	return nil
+}
+
+func (s *DestinationGroupService) validate(ctx context.Context, g DestinationGroup) error {
+	if g.Name == "" || !hasEligibleDestination(g.Members) {
+		return ErrInvalidGroup
+	}
+	for _, m := range g.Members {
+		if err := validateMemberShape(m); err != nil {
+			return err
+		}
+	}
+
+	// Check the whole workspace graph as it would look after the write, so groups
+	// that already nest g are covered by the depth limit too.
+	all, err := s.Store.ListGroups(ctx, g.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	graph := map[string][]WeightedDestination{g.ID: g.Members}
+	for _, other := range all {
+		if other.ID != g.ID {
+			graph[other.ID] = other.Members
+		}
+	}
+	var visit func(id string, path map[string]bool, depth int) error
+	visit = func(id string, path map[string]bool, depth int) error {
+		if depth > MaxGroupDepth {
+			return ErrInvalidGroup
+		}
+		members, ok := graph[id]
+		if !ok {
+			return ErrGroupNotFound
+		}
+		path[id] = true
+		defer delete(path, id)
+		for _, m := range members {
+			if m.GroupID == "" {
+				continue
+			}
+			if path[m.GroupID] {
+				return ErrGroupCycle
+			}
+			if err := visit(m.GroupID, path, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for id := range graph {
+		if err := visit(id, map[string]bool{}, 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateMemberShape requires exactly one of TargetURI/GroupID, a positive weight
+// and a concurrency limit only on targets.
+func validateMemberShape(m WeightedDestination) error {
+	if (m.TargetURI == "") == (m.GroupID == "") || m.Weight <= 0 || m.MaxConcurrent < 0 || (m.GroupID != "" && m.MaxConcurrent != 0) {
+		return ErrInvalidGroup
+	}
+	return nil
+}
+
+func (s *DestinationGroupService) now() time.Time {
+	if s.Now == nil {
+		return time.Now().UTC()
+	}
+	return s.Now().UTC()
+}
+
+func (s *DestinationGroupService) audit(ctx context.Context, g DestinationGroup, actorUserID, actorRole, message string) {
+	if s.Audit == nil {
+		return
+	}
+	// Best-effort; group changes must not fail on audit errors.
+	meta, _ := json.Marshal(map[string]string{"group_id": g.ID})
+	_ = s.Audit.LogAdminAction(ctx, g.WorkspaceID, actorUserID, actorRole, ClientIPFromContext(ctx), message, "", string(meta))
+}
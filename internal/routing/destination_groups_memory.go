@@ -0,0 +1,63 @@
+package routing
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryDestinationGroupStore is a simple in-memory DestinationGroupStore useful for tests.
+// It is not intended for production use.
+
+type MemoryDestinationGroupStore struct {
+	mu     sync.Mutex
+	groups map[string]DestinationGroup
+}
+
+func NewMemoryDestinationGroupStore() *MemoryDestinationGroupStore {
+	return &MemoryDestinationGroupStore{groups: map[string]DestinationGroup{}}
+}
+
+func (s *MemoryDestinationGroupStore) GetGroup(ctx context.Context, workspaceID, groupID string) (DestinationGroup, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.groups[groupID]
+	if !ok || g.WorkspaceID != workspaceID {
+		return DestinationGroup{}, false, nil
+	}
+	return g, true, nil
+}
+
+func (s *MemoryDestinationGroupStore) ListGroups(ctx context.Context, workspaceID string) ([]DestinationGroup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []DestinationGroup
+	for _, g := range s.groups {
+		if g.WorkspaceID == workspaceID {
+			out = append(out, g)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (s *MemoryDestinationGroupStore) PutGroup(ctx context.Context, g DestinationGroup) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.groups[g.ID].Version != g.Version-1 {
+		return ErrVersionConflict
+	}
+	s.groups[g.ID] = g
+	return nil
+}
+
+func (s *MemoryDestinationGroupStore) DeleteGroup(ctx context.Context, workspaceID, groupID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.groups[groupID]
+	if !ok || g.WorkspaceID != workspaceID {
+		return ErrGroupNotFound
+	}
+	delete(s.groups, groupID)
+	return nil
+}
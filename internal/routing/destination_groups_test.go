@@ -0,0 +1,95 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"telecom-platform/internal/telephony"
+)
+
+func TestDestinationGroups_UpdatePropagatesToCampaigns(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryDestinationGroupStore()
+	svc := NewDestinationGroupService(store, nil)
+
+	g, err := svc.Create(ctx, "w", "u", "owner", DestinationGroupRequest{Name: "buyer-a", Members: []WeightedDestination{{TargetURI: "+15550001", Weight: 1}}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	campaign := stubCampaigns{ev: CampaignEvaluation{Allowed: true, Destinations: []WeightedDestination{{GroupID: g.ID, Weight: 1}}}}
+	e := NewRoutingEngine(nil, campaign, rand.New(rand.NewSource(1)))
+	e.Groups = store
+	in := RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: "w", ProviderCallID: "p", From: "+1", To: "+2"}}
+
+	d, err := e.Route(ctx, in)
+	if err != nil || d.ConnectTo != "+15550001" {
+		t.Fatalf("expected group member, got %+v err=%v", d, err)
+	}
+
+	if _, err := svc.Update(ctx, "w", g.ID, "u", "owner", DestinationGroupRequest{Name: "buyer-a", Members: []WeightedDestination{{TargetURI: "+15550002", Weight: 1}}}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	d, err = e.Route(ctx, in)
+	if err != nil || d.ConnectTo != "+15550002" {
+		t.Fatalf("expected updated member, got %+v err=%v", d, err)
+	}
+}
+
+func TestDestinationGroups_RejectsCyclesAndMissingRefs(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryDestinationGroupStore()
+	svc := NewDestinationGroupService(store, nil)
+
+	if _, err := svc.Create(ctx, "w", "u", "owner", DestinationGroupRequest{Name: "x", Members: []WeightedDestination{{GroupID: "missing", Weight: 1}}}); !errors.Is(err, ErrGroupNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if _, err := svc.Create(ctx, "w", "u", "owner", DestinationGroupRequest{Name: "x", Members: []WeightedDestination{{TargetURI: "+1", GroupID: "g", Weight: 1}}}); !errors.Is(err, ErrInvalidGroup) {
+		t.Fatalf("expected invalid member shape, got %v", err)
+	}
+
+	a, _ := svc.Create(ctx, "w", "u", "owner", DestinationGroupRequest{Name: "a", Members: []WeightedDestination{{TargetURI: "+1", Weight: 1}}})
+	b, err := svc.Create(ctx, "w", "u", "owner", DestinationGroupRequest{Name: "b", Members: []WeightedDestination{{GroupID: a.ID, Weight: 1}}})
+	if err != nil {
+		t.Fatalf("create nested: %v", err)
+	}
+	if _, err := svc.Update(ctx, "w", a.ID, "u", "owner", DestinationGroupRequest{Name: "a", Members: []WeightedDestination{{GroupID: b.ID, Weight: 1}}}); !errors.Is(err, ErrGroupCycle) {
+		t.Fatalf("expected cycle, got %v", err)
+	}
+
+	// Other workspaces cannot reference the group.
+	if _, err := svc.Create(ctx, "w2", "u", "owner", DestinationGroupRequest{Name: "c", Members: []WeightedDestination{{GroupID: a.ID, Weight: 1}}}); !errors.Is(err, ErrGroupNotFound) {
+		t.Fatalf("expected cross-workspace ref rejected, got %v", err)
+	}
+
+	if err := svc.Delete(ctx, "w", a.ID, "u", "owner"); !errors.Is(err, ErrGroupInUse) {
+		t.Fatalf("expected in use, got %v", err)
+	}
+	if err := svc.Delete(ctx, "w", b.ID, "u", "owner"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := svc.Delete(ctx, "w", a.ID, "u", "owner"); err != nil {
+		t.Fatalf("delete after unreferenced: %v", err)
+	}
+}
+
+func TestDestinationGroups_RejectsStaleUpdate(t *testing.T) {
+	ctx := context.Background()
+	svc := NewDestinationGroupService(NewMemoryDestinationGroupStore(), nil)
+
+	g, err := svc.Create(ctx, "w", "u", "owner", DestinationGroupRequest{Name: "buyer-a", Members: []WeightedDestination{{TargetURI: "+15550001", Weight: 1}}})
+	if err != nil || g.Version != 1 {
+		t.Fatalf("create: %+v err=%v", g, err)
+	}
+	req := DestinationGroupRequest{Name: "buyer-a", Members: []WeightedDestination{{TargetURI: "+15550002", Weight: 1}}, IfVersion: 1}
+	if g, err = svc.Update(ctx, "w", g.ID, "u", "owner", req); err != nil || g.Version != 2 {
+		t.Fatalf("update: %+v err=%v", g, err)
+	}
+	req.Members = []WeightedDestination{{TargetURI: "+15550003", Weight: 1}}
+	if _, err := svc.Update(ctx, "w", g.ID, "u2", "owner", req); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected ErrVersionConflict, got %v", err)
+	}
+
+}
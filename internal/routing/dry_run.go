@@ -0,0 +1,79 @@
+package routing
+
+import (
+	"context"
+	"time"
+)
+
+// Routing dry runs (call flow debugger).
+//
+// DryRun evaluates a synthetic inbound call through the same Route code path with a
+// trace recorder attached to the context. Side effects are suppressed while tracing:
+// - silent override audit events are not written;
+// - pacing reads the day's counter instead of reserving against it;
+// - round_robin and least_used selection read their rotation without advancing it;
+// - callback offers report what would be scheduled instead of creating a task.
+//
+// The trace includes silent overrides, so it must only be exposed to super_admin.
+
+// TraceStep is one evaluated stage of the routing pipeline.
+type TraceStep struct {
+	Step    string         `json:"step"`    // override, admin_role, blocklist, wallet, campaign, language, zip, pacing, destination, callback
+	Outcome string         `json:"outcome"` // e.g. pass, skip, applied, block, gather, selected
+	Detail  string         `json:"detail,omitempty"`
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+// DryRunResult is the decision plus the trace that produced it.
+type DryRunResult struct {
+	At       time.Time   `json:"at"`
+	Decision Decision    `json:"decision"`
+	Trace    []TraceStep `json:"trace"`
+}
+
+type traceKey struct{}
+
+type trace struct {
+	steps []TraceStep
+}
+
+// traceStep records a step when ctx carries a dry-run trace; otherwise it is a no-op.
+func traceStep(ctx context.Context, step, outcome, detail string, data map[string]any) {
+	if t, ok := ctx.Value(traceKey{}).(*trace); ok {
+		t.steps = append(t.steps, TraceStep{Step: step, Outcome: outcome, Detail: detail, Data: data})
+	}
+}
+
+// isDryRun reports whether side effects must be suppressed.
+func isDryRun(ctx context.Context) bool {
+	_, ok := ctx.Value(traceKey{}).(*trace)
+	return ok
+}
+
+// DryRun routes in without side effects, evaluated as of at (zero means now).
+func (e *RoutingEngine) DryRun(ctx context.Context, in RouteInput, at time.Time) (DryRunResult, error) {
+	if at.IsZero() {
+		if e.Now != nil {
+			at = e.Now()
+		} else {
+			at = time.Now()
+		}
+	}
+	clock := func() time.Time { return at }
+
+	de := *e
+	de.Now = clock
+	if e.Overrides != nil {
+		oc := *e.Overrides
+		oc.Now = clock
+		oc.Audit = nil
+		de.Overrides = &oc
+	}
+
+	t := &trace{}
+	d, err := de.Route(context.WithValue(ctx, traceKey{}, t), in)
+	if err != nil {
+		return DryRunResult{}, err
+	}
+	return DryRunResult{At: at.UTC(), Decision: d, Trace: t.steps}, nil
+}
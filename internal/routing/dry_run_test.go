@@ -0,0 +1,76 @@
+package routing
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/callbacks"
+	"telecom-platform/internal/telephony"
+)
+
+// reserveForbidden fails the test if a dry run tries to move the pacing counter.
+type reserveForbidden struct {
+	*MemoryPacingStore
+	t *testing.T
+}
+
+func (r reserveForbidden) Reserve(ctx context.Context, key string, amount, limit int64, ttl time.Duration) (bool, int64, error) {
+	r.t.Fatalf("dry run reserved pacing budget on %s", key)
+	return false, 0, nil
+}
+
+func TestDryRun_TracesWithoutSideEffects(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryPacingStore()
+	store.SetPacing(PacingConfig{WorkspaceID: "w", CampaignID: "c", DailyBudgetMinor: 2400})
+	_ = store.Adjust(ctx, "pacing:w:c:2024-03-05", 40, time.Hour)
+
+	e := NewRoutingEngine(nil, stubCampaigns{ev: CampaignEvaluation{Allowed: true, Destinations: []WeightedDestination{{TargetURI: "sip:a", Weight: 1}}}}, rand.New(rand.NewSource(1)))
+	e.Pacing = &Pacer{Configs: store, State: reserveForbidden{MemoryPacingStore: store, t: t}}
+
+	in := RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: "w", From: "+15551230000", To: "+15559870000"}}
+	out, err := e.DryRun(ctx, in, time.Date(2024, 3, 5, 1, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if out.Decision.Action != ActionConnect || out.Decision.ConnectTo != "sip:a" {
+		t.Fatalf("unexpected decision: %+v", out.Decision)
+	}
+	var steps []string
+	for _, s := range out.Trace {
+		steps = append(steps, s.Step+":"+s.Outcome)
+	}
+	want := []string{"wallet:skip", "campaign:pass", "pacing:pass", "destination:selected"}
+	if len(steps) != len(want) {
+		t.Fatalf("unexpected trace %v", steps)
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Fatalf("unexpected trace %v", steps)
+		}
+	}
+	if spent, _ := store.Spent(ctx, "pacing:w:c:2024-03-05"); spent != 40 {
+		t.Fatalf("pacing counter changed: %d", spent)
+	}
+}
+
+func TestDryRun_DoesNotScheduleCallbacks(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMemoryCallbackConfigStore()
+	configs.SetCallbackConfig(CallbackConfig{WorkspaceID: "w", CampaignID: "c"})
+	cbSvc := callbacks.NewService(callbacks.NewMemoryRepo(), nil, nil)
+
+	e := NewRoutingEngine(nil, stubCampaigns{ev: CampaignEvaluation{Allowed: false, Reason: "campaign_closed"}}, rand.New(rand.NewSource(1)))
+	e.Callbacks = &CallbackOffer{Configs: configs, Sink: cbSvc}
+
+	in := RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: "w", From: "+15551230000", Collected: map[string]string{"callback": "1", "callback_time": "1"}}}
+	out, err := e.DryRun(ctx, in, time.Date(2030, 3, 4, 15, 0, 0, 0, time.UTC))
+	if err != nil || out.Decision.Reason != "callback_scheduled" {
+		t.Fatalf("unexpected dry run result: %+v err=%v", out.Decision, err)
+	}
+	if items, _ := cbSvc.ListCallbacks(ctx, "w", callbacks.ListFilter{}); len(items) != 0 {
+		t.Fatalf("dry run created %d callbacks", len(items))
+	}
+}
@@ -55,10 +55,15 @@ type AdapterOptions struct {
 	CampaignIDResolver func(ctx context.Context, req telephony.InboundCallRequest) (campaignID string, err error)
 
 	// WalletContextResolver resolves wallet + estimated charge (optional).
+	// The resolved campaign is available via CampaignIDFromContext; see WalletContextResolver.
 	WalletContextResolver func(ctx context.Context, req telephony.InboundCallRequest) (walletID string, estMinor int64, currency string, err error)
 
 	// RoleResolver resolves actor role (for admin override decisions).
 	RoleResolver func(ctx context.Context, req telephony.InboundCallRequest) (role string, err error)
+
+	// FailoverAlternates, when > 0, asks for a failover chain of up to this many
+	// targets (see RoutingEngine.RouteWithFailover).
+	FailoverAlternates int
 }
 
 type engineAdapter struct {
@@ -79,6 +84,7 @@ func (a engineAdapter) RouteInboundCall(ctx context.Context, req telephony.Inbou
 		}
 		campaignID = cid
 	}
+	ctx = WithCampaignID(ctx, campaignID)
 
 	walletID := ""
 	var estMinor int64
@@ -100,7 +106,7 @@ func (a engineAdapter) RouteInboundCall(ctx context.Context, req telephony.Inbou
 		role = r
 	}
 
-	d, err := a.engine.Route(ctx, RouteInput{
+	d, err := a.engine.RouteWithFailover(ctx, RouteInput{
 		WorkspaceID:    req.WorkspaceID,
 		CampaignID:     campaignID,
 		ActorRole:      role,
@@ -108,20 +114,39 @@ func (a engineAdapter) RouteInboundCall(ctx context.Context, req telephony.Inbou
 		EstimatedMinor: estMinor,
 		Currency:       currency,
 		Inbound:        req,
-	})
+	}, a.opts.FailoverAlternates)
 	if err != nil {
 		return telephony.InboundCallResult{}, err
 	}
 
-	res := telephony.InboundCallResult{WorkspaceID: d.WorkspaceID, CallID: ""}
+	res := telephony.InboundCallResult{WorkspaceID: d.WorkspaceID, CallID: "", Language: d.Language}
 	switch d.Action {
 	case ActionReject:
 		res.Action = telephony.InboundCallActionReject
 	case ActionHangup:
 		res.Action = telephony.InboundCallActionHangup
+		res.Announcement = d.Announcement
 	case ActionConnect:
 		res.Action = telephony.InboundCallActionConnect
 		res.ConnectTo = d.ConnectTo
+		res.Alternates = d.Alternates
+		res.AlternateLimits = d.AlternateLimits
+		res.SIPHeaders = d.SIPHeaders
+	case ActionGather:
+		if d.Gather == nil {
+			return telephony.InboundCallResult{}, errors.New("routing: gather decision without prompt")
+		}
+		res.Action = telephony.InboundCallActionGather
+		res.Gather = &telephony.GatherPrompt{
+			Step:           d.Gather.Step,
+			Prompt:         d.Gather.Prompt,
+			NumDigits:      d.Gather.NumDigits,
+			TimeoutSeconds: d.Gather.TimeoutSeconds,
+			Language:       d.Gather.Language,
+		}
+		for _, seg := range d.Gather.Segments {
+			res.Gather.Segments = append(res.Gather.Segments, telephony.PromptSegment(seg))
+		}
 	default:
 		return telephony.InboundCallResult{}, errors.New("routing: unknown decision action")
 	}
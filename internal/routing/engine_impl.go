@@ -15,9 +15,10 @@ import (
 //
 // Priority:
 //  1) Admin override
-//  2) Wallet balance
-//  3) Campaign rules
-//  4) Weighted destination selection
+//  2) Wallet balance and spend caps
+//  3) Per-number forwarding (numbers used without a campaign)
+//  4) Campaign rules (including daily budget pacing)
+//  5) Destination selection (campaign strategy)
 //
 // Return routing decision only. No side effects (no DB writes, no provider calls).
 //
@@ -25,7 +26,10 @@ import (
 // - Admin override means privileged actor can force connect even if wallet/campaign would block.
 // - Wallet balance check can block (reject) when insufficient.
 // - Campaign rules can block or restrict destinations.
-// - Weighted selection chooses a destination when multiple are eligible.
+// - The campaign's selection strategy (weighted random by default) chooses a
+//   destination when multiple are eligible (see selection.go).
+// - Exception to "no side effects": a selected target with a concurrency limit
+//   holds a slot until the call ends (see concurrency.go).
 
 type RoutingEngine struct {
 	Overrides *AdminOverrideEngine
@@ -33,6 +37,54 @@ type RoutingEngine struct {
 	Wallet wallet.BalanceService
 	Campaigns CampaignService
 
+	// SpendCaps rejects calls that would push the wallet past its daily or
+	// monthly spend cap (optional).
+	SpendCaps wallet.SpendCapService
+
+	// Pacing enforces daily campaign budget pacing (optional).
+	Pacing *Pacer
+
+	// Groups resolves destination group references (optional; group entries are
+	// ineligible without it).
+	Groups DestinationGroupStore
+
+	// Languages plays a language menu and routes to language-tagged destinations (optional).
+	Languages *LanguageRouter
+
+	// Zip collects the caller's ZIP code and routes to the serving group (optional).
+	Zip *ZipRouter
+
+	// Callbacks offers a callback when the call cannot be connected (optional).
+	Callbacks *CallbackOffer
+
+	// Forwarding forwards dialed numbers straight to a target, ahead of campaign rules (optional).
+	Forwarding ForwardingStore
+
+	// Selection holds round_robin and least_used rotation state (optional; those
+	// strategies fall back to weighted random without it).
+	Selection SelectionState
+	// Strategies adds custom selection strategies by name (optional).
+	Strategies map[SelectionStrategyName]SelectionStrategy
+
+	// Rates prices targets for least_cost selection (optional; usually
+	// pricing.Service).
+	Rates RateQuoter
+
+	// Concurrency enforces WeightedDestination.MaxConcurrent (optional; limits are
+	// ignored without it).
+	Concurrency ConcurrencySlots
+
+	// Attributes resolves required destination attributes from the dialed number
+	// or caller input (optional).
+	Attributes *AttributeResolver
+
+	// Decisions records every routing decision for later query (optional).
+	Decisions DecisionLog
+
+	// Blocklist rejects blocked callers before wallet, forwarding and campaign
+	// rules (optional).
+	Blocklist *Blocklist
+
 	RNG *rand.Rand
 	Now func() time.Time
 }
@@ -53,6 +105,17 @@ type CampaignEvaluation struct {
 	Reason  string
 
 	Destinations []WeightedDestination
+
+	// Strategy picks among eligible destinations; empty means weighted random.
+	Strategy SelectionStrategyName
+	// QualityBlend weighs Weight against price for least_cost (0 = cheapest wins).
+	QualityBlend float64
+
+	// SIPHeaders are custom headers sent to SIP targets; values may use
+	// placeholders (see sip_headers.go).
+	SIPHeaders map[string]string
+	// PassthroughHeaders names inbound SIP headers copied to the target.
+	PassthroughHeaders []string
 }
 
 type WeightedDestination struct {
@@ -62,8 +125,22 @@ type WeightedDestination struct {
 	// - +15551234567
 	TargetURI string
 
+	// GroupID references a workspace DestinationGroup instead of a single target.
+	// Exactly one of TargetURI and GroupID is set.
+	GroupID string
+
+	// Languages this destination serves (BCP 47). Empty means any language.
+	Languages []string
+
 	// Weight must be > 0.
 	Weight int
+
+	// MaxConcurrent caps simultaneous calls to TargetURI; 0 means unlimited.
+	MaxConcurrent int
+
+	// Attributes tag what this destination serves, e.g. "product": {"auto", "home"}
+	// (see attributes.go).
+	Attributes map[string][]string
 }
 
 type RouteInput struct {
@@ -78,6 +155,9 @@ type RouteInput struct {
 	Currency        string
 
 	Inbound telephony.InboundCallRequest
+
+	// Attributes the destination must serve, e.g. "product": "auto" (optional).
+	Attributes map[string]string
 }
 
 func NewRoutingEngine(walletSvc wallet.BalanceService, campaigns CampaignService, rng *rand.Rand) *RoutingEngine {
@@ -85,6 +165,29 @@ func NewRoutingEngine(walletSvc wallet.BalanceService, campaigns CampaignService
 }
 
 func (e *RoutingEngine) Route(ctx context.Context, in RouteInput) (Decision, error) {
+	return e.route(ctx, in, 0)
+}
+
+// RouteWithFailover is Route plus a failover chain: a connect decision from
+// weighted selection also lists up to maxAlternates other eligible targets in
+// Decision.Alternates, in the order to re-dial them when the primary is busy or
+// does not answer. Alternates are ordered by weighted draw without replacement
+// (language-preferred first, groups expanded), so heavier targets tend to come first.
+func (e *RoutingEngine) RouteWithFailover(ctx context.Context, in RouteInput, maxAlternates int) (Decision, error) {
+	return e.route(ctx, in, maxAlternates)
+}
+
+func (e *RoutingEngine) route(ctx context.Context, in RouteInput, maxAlternates int) (Decision, error) {
+	start := time.Now()
+	d, err := e.decide(ctx, in, maxAlternates)
+	if err != nil {
+		err = errors.Join(err, e.releaseSlots(ctx, in.WorkspaceID, in.Inbound.ProviderCallID))
+	}
+	e.recordDecision(ctx, in, d, err, time.Since(start))
+	return d, err
+}
+
+func (e *RoutingEngine) decide(ctx context.Context, in RouteInput, maxAlternates int) (Decision, error) {
 	if in.WorkspaceID == "" {
 		return Decision{}, errors.New("routing: workspace_id required")
 	}
@@ -96,18 +199,21 @@ func (e *RoutingEngine) Route(ctx context.Context, in RouteInput) (Decision, err
 			return Decision{}, err
 		}
 		if applied {
+			traceStep(ctx, "override", "applied", "silent override", map[string]any{"connect_to": d.ConnectTo})
 			return d, nil
 		}
+		traceStep(ctx, "override", "skip", "no active override", nil)
 	}
 
 	// 1) Admin override
 	if rbac.IsSuperAdmin(in.ActorRole) || in.ActorRole == rbac.RoleNetworkOperator {
+		traceStep(ctx, "admin_role", "applied", "privileged role bypasses wallet and campaign rules", map[string]any{"role": in.ActorRole})
 		// Still need a destination. If campaign logic exists, use it, but do not block.
 		if in.CampaignID != "" && e.Campaigns != nil {
 			ev, err := e.Campaigns.EvaluateInbound(ctx, in.WorkspaceID, in.CampaignID, in.Inbound)
 			if err == nil {
-				if dest, ok := e.pickDestination(ev.Destinations); ok {
-					return Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionConnect, ConnectTo: dest, Reason: "admin_override"}, nil
+				if dest, ok, err := e.selectDestination(ctx, in, "", ev); err == nil && ok {
+					return Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionConnect, ConnectTo: dest, SIPHeaders: sipHeaders(ctx, in, ev, ""), Reason: "admin_override"}, nil
 				}
 			}
 		}
@@ -115,7 +221,20 @@ func (e *RoutingEngine) Route(ctx context.Context, in RouteInput) (Decision, err
 		return Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionReject, Reason: "admin_override_no_destination"}, nil
 	}
 
-	// 2) Wallet balance
+	// 1b) Caller blocklist (DNC screening)
+	if e.Blocklist != nil {
+		b, blocked, err := e.Blocklist.Blocked(ctx, in.WorkspaceID, in.Inbound.From)
+		if err != nil {
+			return Decision{}, err
+		}
+		if blocked {
+			traceStep(ctx, "blocklist", "block", string(b.Match)+" "+b.Pattern, map[string]any{"entry_id": b.ID, "global": b.WorkspaceID == GlobalBlocklist})
+			return Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionReject, Reason: blockedReason}, nil
+		}
+		traceStep(ctx, "blocklist", "pass", "", nil)
+	}
+
+	// 2) Wallet balance and spend caps
 	if in.EstimatedMinor > 0 {
 		if e.Wallet == nil {
 			return Decision{}, errors.New("routing: wallet service not configured")
@@ -131,16 +250,51 @@ func (e *RoutingEngine) Route(ctx context.Context, in RouteInput) (Decision, err
 		if err != nil {
 			return Decision{}, err
 		}
+		walletData := map[string]any{"balance_minor": bal.BalanceMinor, "held_minor": bal.HeldMinor, "credit_limit_minor": bal.CreditLimitMinor, "currency": bal.Currency, "estimated_minor": in.EstimatedMinor}
 		if bal.Currency != in.Currency {
+			traceStep(ctx, "wallet", "block", "wallet_currency_mismatch", walletData)
 			return Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionReject, Reason: "wallet_currency_mismatch"}, nil
 		}
-		if bal.BalanceMinor < in.EstimatedMinor {
+		// Open holds (calls in progress) are already spoken for; the credit line is not.
+		if bal.Spendable() < in.EstimatedMinor {
+			traceStep(ctx, "wallet", "block", "insufficient_balance", walletData)
 			return Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionReject, Reason: "insufficient_balance"}, nil
 		}
+		if e.SpendCaps != nil {
+			u, err := e.SpendCaps.SpendCapUtilization(ctx, in.WorkspaceID, in.WalletID)
+			if err != nil {
+				return Decision{}, err
+			}
+			if err := u.Check(in.EstimatedMinor); err != nil {
+				walletData["daily_cap_minor"], walletData["daily_spent_minor"] = u.DailyCapMinor, u.DailySpentMinor
+				walletData["monthly_cap_minor"], walletData["monthly_spent_minor"] = u.MonthlyCapMinor, u.MonthlySpentMinor
+				traceStep(ctx, "wallet", "block", "spend_cap_exceeded", walletData)
+				return Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionReject, Reason: "spend_cap_exceeded"}, nil
+			}
+		}
+		traceStep(ctx, "wallet", "pass", "", walletData)
+	} else {
+		traceStep(ctx, "wallet", "skip", "no estimated cost", nil)
+	}
+
+	// 3) Per-number forwarding
+	{
+		now := time.Now
+		if e.Now != nil {
+			now = e.Now
+		}
+		d, ok, err := e.forward(ctx, in, now())
+		if err != nil {
+			return Decision{}, err
+		}
+		if ok {
+			return d, nil
+		}
 	}
 
-	// 3) Campaign rules
+	// 4) Campaign rules
 	if in.CampaignID == "" {
+		traceStep(ctx, "campaign", "block", "campaign_id_required", nil)
 		return Decision{WorkspaceID: in.WorkspaceID, Action: ActionReject, Reason: "campaign_id_required"}, nil
 	}
 	if e.Campaigns == nil {
@@ -156,43 +310,320 @@ func (e *RoutingEngine) Route(ctx context.Context, in RouteInput) (Decision, err
 		if reason == "" {
 			reason = "campaign_blocked"
 		}
-		return Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionReject, Reason: reason}, nil
+		traceStep(ctx, "campaign", "block", reason, nil)
+		return e.unavailable(ctx, in, Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionReject, Reason: reason})
+	}
+
+	traceStep(ctx, "campaign", "pass", "", map[string]any{"destinations": ev.Destinations})
+
+	// Caller input steps run before pacing so the budget is reserved once, on the
+	// final callback, not on every prompt.
+	var language string
+	if e.Languages != nil {
+		lang, g, err := e.Languages.Resolve(ctx, in.WorkspaceID, in.CampaignID, in.Inbound.Collected)
+		if err != nil {
+			return Decision{}, err
+		}
+		if g != nil {
+			traceStep(ctx, "language", "gather", "language menu", nil)
+			return Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionGather, Gather: g, Reason: "collect_language"}, nil
+		}
+		if lang != "" {
+			traceStep(ctx, "language", "selected", lang, nil)
+		}
+		language = lang
+	}
+	if e.Zip != nil {
+		zr, err := e.Zip.Resolve(ctx, in.WorkspaceID, in.CampaignID, language, in.Inbound.Collected)
+		if err != nil {
+			return Decision{}, err
+		}
+		if zr.Gather != nil {
+			traceStep(ctx, "zip", "gather", "zip prompt", nil)
+			return Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionGather, Gather: zr.Gather, Language: language, Reason: "collect_zip"}, nil
+		}
+		if len(zr.Destinations) > 0 {
+			traceStep(ctx, "zip", "routed", zr.MatchedZip, map[string]any{"destinations": zr.Destinations})
+			ev.Destinations = zr.Destinations
+		}
+	}
+	if e.Attributes != nil {
+		attrs, err := e.Attributes.Resolve(ctx, in.WorkspaceID, in.CampaignID, in.Inbound)
+		if err != nil {
+			return Decision{}, err
+		}
+		for k, v := range in.Attributes {
+			if attrs == nil {
+				attrs = map[string]string{}
+			}
+			attrs[k] = v
+		}
+		in.Attributes = attrs
+	}
+	if len(in.Attributes) > 0 {
+		traceStep(ctx, "attributes", "required", "", map[string]any{"attributes": in.Attributes})
+	}
+
+	// pacedAt is set once budget has been reserved for this call; it is released
+	// again if no destination takes the call.
+	var pacedAt *time.Time
+	if e.Pacing != nil {
+		now := time.Now
+		if e.Now != nil {
+			now = e.Now
+		}
+		at := now()
+		pd, err := e.Pacing.Check(ctx, in.WorkspaceID, in.CampaignID, at, in.EstimatedMinor)
+		if err != nil {
+			return Decision{}, err
+		}
+		if pd.Allowed && !isDryRun(ctx) && in.EstimatedMinor != 0 {
+			pacedAt = &at
+		}
+		pacingData := map[string]any{"spent_minor": pd.SpentMinor, "allowance_minor": pd.AllowanceMinor}
+		if pd.Allowed {
+			traceStep(ctx, "pacing", "pass", "", pacingData)
+		} else {
+			traceStep(ctx, "pacing", "block", "over pace", pacingData)
+		}
+		if !pd.Allowed {
+			if pd.Overflow != "" {
+				return Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionConnect, ConnectTo: pd.Overflow, Language: language, SIPHeaders: sipHeaders(ctx, in, ev, language), Reason: "pacing_overflow"}, nil
+			}
+			return e.unavailable(ctx, in, Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionReject, Language: language, Reason: "pacing_exceeded"})
+		}
+	}
+
+	// 5) Destination selection (campaign strategy, weighted random by default)
+	dest, ok, err := e.selectDestination(ctx, in, language, ev)
+	if err != nil {
+		return Decision{}, errors.Join(err, e.releasePacing(ctx, in, pacedAt))
+	}
+	if ok {
+		d := Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionConnect, ConnectTo: dest, Language: language, SIPHeaders: sipHeaders(ctx, in, ev, language), Reason: "selected"}
+		if pacedAt != nil {
+			d.PacedAt = pacedAt
+			d.PacingReservedMinor = in.EstimatedMinor
+		}
+		if maxAlternates > 0 {
+			if d.Alternates, d.AlternateLimits, err = e.failoverChain(ctx, in.WorkspaceID, language, in.Attributes, ev.Destinations, dest, maxAlternates); err != nil {
+				return Decision{}, err
+			}
+			traceStep(ctx, "failover", "alternates", "", map[string]any{"alternates": d.Alternates})
+		}
+		return d, nil
+	}
+	traceStep(ctx, "destination", "block", "no_eligible_destination", nil)
+	if err := e.releasePacing(ctx, in, pacedAt); err != nil {
+		return Decision{}, err
+	}
+	return e.unavailable(ctx, in, Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionReject, Language: language, Reason: "no_eligible_destination"})
+}
+
+// releasePacing returns a pacing reservation made at pacedAt (nil: none) for a
+// call that will not be connected.
+func (e *RoutingEngine) releasePacing(ctx context.Context, in RouteInput, pacedAt *time.Time) error {
+	if pacedAt == nil {
+		return nil
+	}
+	return e.Pacing.Release(ctx, in.WorkspaceID, in.CampaignID, *pacedAt, in.EstimatedMinor)
+}
+
+// unavailable offers a callback in place of rejected when the campaign allows it.
+func (e *RoutingEngine) unavailable(ctx context.Context, in RouteInput, rejected Decision) (Decision, error) {
+	if e.Callbacks == nil {
+		return rejected, nil
+	}
+	now := time.Now
+	if e.Now != nil {
+		now = e.Now
+	}
+	d, ok, err := e.Callbacks.Offer(ctx, in, now(), rejected)
+	if err != nil {
+		return Decision{}, err
+	}
+	if !ok {
+		return rejected, nil
+	}
+	d.Language = rejected.Language
+	return d, nil
+}
+
+// selectDestination picks a target with the campaign's strategy, descending into
+// destination groups. When language is set, destinations serving it are preferred
+// at each level. Targets at their concurrency limit are skipped (see concurrency.go).
+func (e *RoutingEngine) selectDestination(ctx context.Context, in RouteInput, language string, ev CampaignEvaluation) (string, bool, error) {
+	name, strat := e.selectionStrategy(ctx, in, ev)
+	return e.selectFrom(ctx, in, language, name, strat, selectionPool(in.WorkspaceID, in.CampaignID), ev.Destinations, 0)
+}
+
+// selectFrom picks among dests; when the pick is a full target, or a group with no
+// free target, it is set aside and the rest are tried.
+func (e *RoutingEngine) selectFrom(ctx context.Context, in RouteInput, language string, name SelectionStrategyName, strat SelectionStrategy, pool string, dests []WeightedDestination, depth int) (string, bool, error) {
+	for {
+		candidates := preferLanguage(matchAttributes(dests, in.Attributes), language)
+		d, ok, err := e.pickDestination(ctx, strat, pool, candidates)
+		if err != nil || !ok {
+			return "", false, err
+		}
+		if d.GroupID == "" {
+			free, err := e.acquireSlot(ctx, in.WorkspaceID, in.Inbound.ProviderCallID, d)
+			if err != nil {
+				return "", false, err
+			}
+			if free {
+				traceStep(ctx, "destination", "selected", d.TargetURI, map[string]any{"candidates": candidates, "strategy": name})
+				return d.TargetURI, true, nil
+			}
+			traceStep(ctx, "destination", "full", d.TargetURI, map[string]any{"max_concurrent": d.MaxConcurrent})
+		} else if depth < MaxGroupDepth {
+			traceStep(ctx, "destination", "group", d.GroupID, map[string]any{"candidates": candidates, "strategy": name})
+			g, found, err := e.Groups.GetGroup(ctx, in.WorkspaceID, d.GroupID)
+			if err != nil {
+				return "", false, err
+			}
+			if found {
+				groupPool := selectionPool(in.WorkspaceID, in.CampaignID) + ":group:" + d.GroupID
+				target, ok, err := e.selectFrom(ctx, in, language, name, strat, groupPool, g.Members, depth+1)
+				if err != nil || ok {
+					return target, ok, err
+				}
+			}
+		}
+		dests = withoutDestination(dests, d)
 	}
+}
 
-	// 4) Weighted destination selection
-	if dest, ok := e.pickDestination(ev.Destinations); ok {
-		return Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionConnect, ConnectTo: dest, Reason: "selected"}, nil
+// withoutDestination drops every entry for d's target or group.
+func withoutDestination(dests []WeightedDestination, d WeightedDestination) []WeightedDestination {
+	out := make([]WeightedDestination, 0, len(dests))
+	for _, x := range dests {
+		if selectionMember(x) != selectionMember(d) {
+			out = append(out, x)
+		}
 	}
-	return Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionReject, Reason: "no_eligible_destination"}, nil
+	return out
 }
 
-func (e *RoutingEngine) pickDestination(dests []WeightedDestination) (string, bool) {
-	var total int
+// pickDestination applies strat to the eligible entries of dests.
+func (e *RoutingEngine) pickDestination(ctx context.Context, strat SelectionStrategy, pool string, dests []WeightedDestination) (WeightedDestination, bool, error) {
+	var eligible []WeightedDestination
 	for _, d := range dests {
-		if d.Weight <= 0 {
-			continue
+		if e.eligible(d) {
+			eligible = append(eligible, d)
 		}
-		total += d.Weight
 	}
-	if total <= 0 {
-		return "", false
+	if len(eligible) == 0 {
+		return WeightedDestination{}, false, nil
 	}
+	d, err := strat.Pick(ctx, pool, eligible, isDryRun(ctx))
+	if err != nil {
+		return WeightedDestination{}, false, err
+	}
+	return d, true, nil
+}
 
-	rng := e.RNG
-	if rng == nil {
-		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+func (e *RoutingEngine) eligible(d WeightedDestination) bool {
+	if d.Weight <= 0 {
+		return false
+	}
+	if d.GroupID != "" {
+		return e.Groups != nil
+	}
+	return true
+}
+
+// preferLanguage narrows dests to those serving language; if none do, all are kept
+// so a language preference never drops a call.
+func preferLanguage(dests []WeightedDestination, language string) []WeightedDestination {
+	if language == "" {
+		return dests
+	}
+	var out []WeightedDestination
+	for _, d := range dests {
+		if servesLanguage(d, language) && d.Weight > 0 {
+			out = append(out, d)
+		}
+	}
+	if len(out) == 0 {
+		return dests
+	}
+	return out
+}
+
+// failoverChain orders up to n targets other than primary for re-dialing and
+// returns each one's MaxConcurrent alongside.
+func (e *RoutingEngine) failoverChain(ctx context.Context, workspaceID, language string, attrs map[string]string, dests []WeightedDestination, primary string, n int) ([]string, []int, error) {
+	seen := map[string]bool{primary: true}
+	var out []string
+	var limits []int
+	var walk func(dests []WeightedDestination, depth int) error
+	walk = func(dests []WeightedDestination, depth int) error {
+		for _, d := range e.weightedOrder(preferLanguage(matchAttributes(dests, attrs), language)) {
+			if len(out) >= n {
+				return nil
+			}
+			if d.GroupID == "" {
+				if seen[d.TargetURI] {
+					continue
+				}
+				seen[d.TargetURI] = true
+				full, err := e.atCapacity(ctx, workspaceID, d)
+				if err != nil {
+					return err
+				}
+				if !full {
+					out = append(out, d.TargetURI)
+					limits = append(limits, d.MaxConcurrent)
+				}
+				continue
+			}
+			if depth >= MaxGroupDepth {
+				continue
+			}
+			g, found, err := e.Groups.GetGroup(ctx, workspaceID, d.GroupID)
+			if err != nil {
+				return err
+			}
+			if found {
+				if err := walk(g.Members, depth+1); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(dests, 0); err != nil {
+		return nil, nil, err
 	}
-	r := rng.Intn(total) // 0..total-1
+	return out, limits, nil
+}
 
-	var acc int
+// weightedOrder returns the eligible dests in weighted random order (draws
+// without replacement).
+func (e *RoutingEngine) weightedOrder(dests []WeightedDestination) []WeightedDestination {
+	var pool []WeightedDestination
+	total := 0
 	for _, d := range dests {
-		if d.Weight <= 0 {
-			continue
+		if e.eligible(d) {
+			pool = append(pool, d)
+			total += d.Weight
 		}
-		acc += d.Weight
-		if r < acc {
-			return d.TargetURI, true
+	}
+	rng := e.RNG
+	if rng == nil {
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	out := make([]WeightedDestination, 0, len(pool))
+	for len(pool) > 0 {
+		r := rng.Intn(total)
+		i := 0
+		for acc := pool[0].Weight; r >= acc; acc += pool[i].Weight {
+			i++
 		}
+		out = append(out, pool[i])
+		total -= pool[i].Weight
+		pool = append(pool[:i], pool[i+1:]...)
 	}
-	return "", false
+	return out
 }
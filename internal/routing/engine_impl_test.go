@@ -87,3 +87,58 @@ func TestRoutingEngine_CampaignRulesThenWeightedPick(t *testing.T) {
 		t.Fatalf("expected connect_to")
 	}
 }
+
+func TestRoutingEngine_CreditLineCoversEstimate(t *testing.T) {
+	bal := wallet.Balance{Currency: "USD", BalanceMinor: -5, CreditLimitMinor: 20}
+	e := NewRoutingEngine(stubWallet{bal: bal}, stubCampaigns{ev: CampaignEvaluation{Allowed: true, Destinations: []WeightedDestination{{TargetURI: "+1555", Weight: 1}}}}, rand.New(rand.NewSource(1)))
+
+	in := RouteInput{
+		WorkspaceID:    "w",
+		CampaignID:     "c",
+		WalletID:       "wallet",
+		EstimatedMinor: 15,
+		Currency:       "USD",
+		Inbound:        telephony.InboundCallRequest{WorkspaceID: "w", ProviderCallID: "p", From: "+1", To: "+2"},
+	}
+	d, err := e.Route(context.Background(), in)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if d.Action != ActionConnect {
+		t.Fatalf("expected connect within credit line, got %q (%s)", d.Action, d.Reason)
+	}
+
+	in.EstimatedMinor = 16
+	if d, _ := e.Route(context.Background(), in); d.Reason != "insufficient_balance" {
+		t.Fatalf("expected insufficient_balance past the credit line, got %q", d.Reason)
+	}
+}
+
+type stubSpendCaps struct {
+	u wallet.SpendCapUtilization
+}
+
+func (s stubSpendCaps) SpendCapUtilization(ctx context.Context, workspaceID, walletID string) (wallet.SpendCapUtilization, error) {
+	return s.u, nil
+}
+
+func TestRoutingEngine_SpendCapRejects(t *testing.T) {
+	e := NewRoutingEngine(stubWallet{bal: wallet.Balance{Currency: "USD", BalanceMinor: 1000}}, stubCampaigns{ev: CampaignEvaluation{Allowed: true, Destinations: []WeightedDestination{{TargetURI: "+1555", Weight: 1}}}}, rand.New(rand.NewSource(1)))
+	e.SpendCaps = stubSpendCaps{u: wallet.SpendCapUtilization{DailyCapMinor: 100, DailySpentMinor: 95}}
+
+	in := RouteInput{
+		WorkspaceID:    "w",
+		CampaignID:     "c",
+		WalletID:       "wallet",
+		EstimatedMinor: 5,
+		Currency:       "USD",
+		Inbound:        telephony.InboundCallRequest{WorkspaceID: "w", ProviderCallID: "p", From: "+1", To: "+2"},
+	}
+	if d, err := e.Route(context.Background(), in); err != nil || d.Action != ActionConnect {
+		t.Fatalf("expected connect up to the cap, got %q (%s) err=%v", d.Action, d.Reason, err)
+	}
+	in.EstimatedMinor = 6
+	if d, _ := e.Route(context.Background(), in); d.Reason != "spend_cap_exceeded" {
+		t.Fatalf("expected spend_cap_exceeded, got %q", d.Reason)
+	}
+}
@@ -0,0 +1,57 @@
+package routing
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"telecom-platform/internal/telephony"
+)
+
+func TestRouteWithFailover_OrdersDistinctAlternates(t *testing.T) {
+	ctx := context.Background()
+	groups := NewMemoryDestinationGroupStore()
+	svc := NewDestinationGroupService(groups, nil)
+	g, err := svc.Create(ctx, "w", "u", "owner", DestinationGroupRequest{Name: "overflow", Members: []WeightedDestination{
+		{TargetURI: "+15550003", Weight: 1},
+		{TargetURI: "+15550001", Weight: 1},
+	}})
+	if err != nil {
+		t.Fatalf("create group: %v", err)
+	}
+
+	campaign := stubCampaigns{ev: CampaignEvaluation{Allowed: true, Destinations: []WeightedDestination{
+		{TargetURI: "+15550001", Weight: 5},
+		{TargetURI: "+15550002", Weight: 3},
+		{GroupID: g.ID, Weight: 1},
+	}}}
+	e := NewRoutingEngine(nil, campaign, rand.New(rand.NewSource(1)))
+	e.Groups = groups
+	in := RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: "w", ProviderCallID: "p", From: "+1", To: "+2"}}
+
+	d, err := e.RouteWithFailover(ctx, in, 5)
+	if err != nil || d.Action != ActionConnect {
+		t.Fatalf("expected connect, got %+v err=%v", d, err)
+	}
+	seen := map[string]bool{d.ConnectTo: true}
+	for _, a := range d.Alternates {
+		if seen[a] {
+			t.Fatalf("duplicate or primary target %q in %v (primary %s)", a, d.Alternates, d.ConnectTo)
+		}
+		seen[a] = true
+	}
+	// Every distinct target, group members included, is reachable exactly once.
+	if len(seen) != 3 || !seen["+15550003"] {
+		t.Fatalf("expected all three targets, got primary %s alternates %v", d.ConnectTo, d.Alternates)
+	}
+
+	d, err = e.RouteWithFailover(ctx, in, 1)
+	if err != nil || len(d.Alternates) != 1 {
+		t.Fatalf("expected one alternate, got %+v err=%v", d, err)
+	}
+
+	d, err = e.Route(ctx, in)
+	if err != nil || d.Alternates != nil {
+		t.Fatalf("expected no alternates from Route, got %+v err=%v", d, err)
+	}
+}
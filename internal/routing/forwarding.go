@@ -0,0 +1,267 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/audit"
+)
+
+// Per-number call forwarding.
+//
+// Tenants that don't need campaigns can forward a dialed number straight to a phone
+// or SIP target, optionally only inside a weekly schedule. The engine evaluates
+// forwarding after the wallet check and before campaign rules:
+// - inside the schedule (or no schedule): connect to ForwardTo;
+// - outside: connect to FallbackTo when set, otherwise continue to the campaign
+//   engine when the call has a campaign, otherwise reject.
+
+var (
+	ErrInvalidForwarding  = errors.New("routing: invalid forwarding config")
+	ErrForwardingNotFound = errors.New("routing: forwarding not found")
+)
+
+// NumberForwarding forwards calls to one dialed number.
+type NumberForwarding struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	Number      string `json:"number" db:"number"` // dialed number, E.164
+
+	ForwardTo string `json:"forward_to" db:"forward_to"` // E.164 or sip:/sips: URI
+
+	// Schedule limits forwarding to these windows; empty means always.
+	Schedule []ForwardingWindow `json:"schedule,omitempty" db:"schedule"`
+	Timezone string             `json:"timezone,omitempty" db:"timezone"` // IANA; empty means UTC
+
+	// FallbackTo receives calls outside the schedule (optional).
+	FallbackTo string `json:"fallback_to,omitempty" db:"fallback_to"`
+
+	Enabled   bool      `json:"enabled" db:"enabled"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	// Version increments on every write (see version.go).
+	Version int64 `json:"version" db:"version"`
+}
+
+// ForwardingWindow is a recurring weekly window in the config's timezone.
+type ForwardingWindow struct {
+	// Days limits the window to these weekdays; empty means every day.
+	Days []time.Weekday `json:"days,omitempty"`
+
+	// StartMinute/EndMinute are minutes after local midnight, [start, end).
+	// End <= Start wraps past midnight; both zero means the whole day.
+	StartMinute int `json:"start_minute"`
+	EndMinute   int `json:"end_minute"`
+}
+
+// Validate checks targets, timezone and window bounds.
+func (f NumberForwarding) Validate() error {
+	if f.WorkspaceID == "" || !isE164(f.Number) || !isForwardTarget(f.ForwardTo) {
+		return ErrInvalidForwarding
+	}
+	if f.FallbackTo != "" && !isForwardTarget(f.FallbackTo) {
+		return ErrInvalidForwarding
+	}
+	if f.Timezone != "" {
+		if _, err := time.LoadLocation(f.Timezone); err != nil {
+			return ErrInvalidForwarding
+		}
+	}
+	for _, w := range f.Schedule {
+		if w.StartMinute < 0 || w.StartMinute >= 24*60 || w.EndMinute < 0 || w.EndMinute > 24*60 {
+			return ErrInvalidForwarding
+		}
+		for _, d := range w.Days {
+			if d < time.Sunday || d > time.Saturday {
+				return ErrInvalidForwarding
+			}
+		}
+	}
+	return nil
+}
+
+// Active reports whether t falls inside the schedule.
+func (f NumberForwarding) Active(t time.Time) bool {
+	if len(f.Schedule) == 0 {
+		return true
+	}
+	loc, err := time.LoadLocation(f.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	lt := t.In(loc)
+	for _, w := range f.Schedule {
+		if w.matches(lt) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether local time lt falls inside the window.
+// For wrapping windows, the day check applies to the day the window started.
+func (w ForwardingWindow) matches(lt time.Time) bool {
+	minute := lt.Hour()*60 + lt.Minute()
+	day := lt.Weekday()
+	switch {
+	case w.StartMinute == 0 && w.EndMinute == 0:
+	case w.StartMinute < w.EndMinute:
+		if minute < w.StartMinute || minute >= w.EndMinute {
+			return false
+		}
+	default:
+		if minute < w.StartMinute && minute >= w.EndMinute {
+			return false
+		}
+		if minute < w.EndMinute {
+			day = (day + 6) % 7
+		}
+	}
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// ForwardingStore persists per-number forwarding. Implementations must enforce
+// workspace filtering.
+type ForwardingStore interface {
+	// GetForwarding returns ok=false when the number has no forwarding.
+	GetForwarding(ctx context.Context, workspaceID, number string) (NumberForwarding, bool, error)
+	// PutForwarding must reject with ErrVersionConflict unless the stored version is f.Version-1.
+	PutForwarding(ctx context.Context, f NumberForwarding) error
+	DeleteForwarding(ctx context.Context, workspaceID, number string) error
+}
+
+// ForwardingService manages per-number forwarding.
+type ForwardingService struct {
+	Store ForwardingStore
+	Audit *audit.Service
+	Now   func() time.Time
+}
+
+func NewForwardingService(store ForwardingStore, auditSvc *audit.Service) *ForwardingService {
+	return &ForwardingService{Store: store, Audit: auditSvc, Now: time.Now}
+}
+
+// Get returns the forwarding config for a number.
+func (s *ForwardingService) Get(ctx context.Context, workspaceID, number string) (NumberForwarding, error) {
+	if workspaceID == "" || number == "" {
+		return NumberForwarding{}, ErrInvalidForwarding
+	}
+	if s.Store == nil {
+		return NumberForwarding{}, errors.New("routing: forwarding store not configured")
+	}
+	f, ok, err := s.Store.GetForwarding(ctx, workspaceID, strings.TrimSpace(number))
+	if err != nil {
+		return NumberForwarding{}, err
+	}
+	if !ok {
+		return NumberForwarding{}, ErrForwardingNotFound
+	}
+	return f, nil
+}
+
+// Set creates or replaces the forwarding config for f.Number. f.Version is the
+// version the caller last read (0 skips the check).
+func (s *ForwardingService) Set(ctx context.Context, actorUserID, actorRole string, f NumberForwarding) (NumberForwarding, error) {
+	f.Number = strings.TrimSpace(f.Number)
+	f.ForwardTo = strings.TrimSpace(f.ForwardTo)
+	f.FallbackTo = strings.TrimSpace(f.FallbackTo)
+	if actorUserID == "" {
+		return NumberForwarding{}, ErrInvalidForwarding
+	}
+	if err := f.Validate(); err != nil {
+		return NumberForwarding{}, err
+	}
+	if s.Store == nil {
+		return NumberForwarding{}, errors.New("routing: forwarding store not configured")
+	}
+	cur, _, err := s.Store.GetForwarding(ctx, f.WorkspaceID, f.Number)
+	if err != nil {
+		return NumberForwarding{}, err
+	}
+	if f.Version, err = nextVersion(cur.Version, f.Version); err != nil {
+		return NumberForwarding{}, err
+	}
+	f.UpdatedAt = s.now()
+	if err := s.Store.PutForwarding(ctx, f); err != nil {
+		return NumberForwarding{}, err
+	}
+	s.audit(ctx, f, actorUserID, actorRole, "number forwarding updated")
+	return f, nil
+}
+
+// Delete removes forwarding; the number goes back to campaign routing.
+func (s *ForwardingService) Delete(ctx context.Context, workspaceID, number, actorUserID, actorRole string) error {
+	f, err := s.Get(ctx, workspaceID, number)
+	if err != nil {
+		return err
+	}
+	if err := s.Store.DeleteForwarding(ctx, workspaceID, f.Number); err != nil {
+		return err
+	}
+	s.audit(ctx, f, actorUserID, actorRole, "number forwarding removed")
+	return nil
+}
+
+func (s *ForwardingService) now() time.Time {
+	if s.Now == nil {
+		return time.Now().UTC()
+	}
+	return s.Now().UTC()
+}
+
+func (s *ForwardingService) audit(ctx context.Context, f NumberForwarding, actorUserID, actorRole, message string) {
+	if s.Audit == nil {
+		return
+	}
+	// Best-effort; forwarding changes must not fail on audit errors.
+	meta, _ := json.Marshal(map[string]string{"number": f.Number})
+	_ = s.Audit.LogAdminAction(ctx, f.WorkspaceID, actorUserID, actorRole, ClientIPFromContext(ctx), message, "",
+		string(meta))
+}
+
+// forward evaluates forwarding for the dialed number. ok=false means no forwarding
+// applies and routing continues.
+func (e *RoutingEngine) forward(ctx context.Context, in RouteInput, now time.Time) (Decision, bool, error) {
+	if e.Forwarding == nil || in.Inbound.To == "" {
+		return Decision{}, false, nil
+	}
+	f, ok, err := e.Forwarding.GetForwarding(ctx, in.WorkspaceID, in.Inbound.To)
+	if err != nil {
+		return Decision{}, false, err
+	}
+	if !ok || !f.Enabled {
+		return Decision{}, false, nil
+	}
+	d := Decision{WorkspaceID: in.WorkspaceID, CampaignID: in.CampaignID, Action: ActionConnect}
+	switch {
+	case f.Active(now):
+		d.ConnectTo, d.Reason = f.ForwardTo, "number_forwarding"
+	case f.FallbackTo != "":
+		d.ConnectTo, d.Reason = f.FallbackTo, "number_forwarding_fallback"
+	case in.CampaignID != "":
+		traceStep(ctx, "forwarding", "skip", "outside schedule; continuing to campaign", nil)
+		return Decision{}, false, nil
+	default:
+		d.Action, d.Reason = ActionReject, "forwarding_outside_schedule"
+	}
+	traceStep(ctx, "forwarding", d.Reason, d.ConnectTo, map[string]any{"number": f.Number})
+	return d, true, nil
+}
+
+func isE164(s string) bool {
+	return len(s) >= 8 && len(s) <= 16 && s[0] == '+' && allDigits(s[1:])
+}
+
+func isForwardTarget(s string) bool {
+	return isE164(s) || strings.HasPrefix(s, "sip:") || strings.HasPrefix(s, "sips:")
+}
@@ -0,0 +1,43 @@
+package routing
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryForwardingStore is a simple in-memory ForwardingStore useful for tests.
+// It is not intended for production use.
+
+type MemoryForwardingStore struct {
+	mu      sync.Mutex
+	configs map[string]NumberForwarding
+}
+
+func NewMemoryForwardingStore() *MemoryForwardingStore {
+	return &MemoryForwardingStore{configs: map[string]NumberForwarding{}}
+}
+
+func (s *MemoryForwardingStore) GetForwarding(ctx context.Context, workspaceID, number string) (NumberForwarding, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.configs[workspaceID+"|"+number]
+	return f, ok, nil
+}
+
+func (s *MemoryForwardingStore) PutForwarding(ctx context.Context, f NumberForwarding) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := f.WorkspaceID + "|" + f.Number
+	if s.configs[key].Version != f.Version-1 {
+		return ErrVersionConflict
+	}
+	s.configs[key] = f
+	return nil
+}
+
+func (s *MemoryForwardingStore) DeleteForwarding(ctx context.Context, workspaceID, number string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.configs, workspaceID+"|"+number)
+	return nil
+}
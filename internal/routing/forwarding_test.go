@@ -0,0 +1,102 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/telephony"
+)
+
+func TestForwardingWindow_WrapsPastMidnight(t *testing.T) {
+	f := NumberForwarding{
+		Timezone: "America/New_York",
+		Schedule: []ForwardingWindow{{Days: []time.Weekday{time.Friday}, StartMinute: 22 * 60, EndMinute: 2 * 60}},
+	}
+	// Saturday 01:00 New York belongs to Friday's window.
+	if !f.Active(time.Date(2024, 3, 9, 6, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected Friday night window to cover Saturday 01:00")
+	}
+	if f.Active(time.Date(2024, 3, 9, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Saturday 03:00 is outside the window")
+	}
+}
+
+func TestForwardingService_Validates(t *testing.T) {
+	svc := NewForwardingService(NewMemoryForwardingStore(), nil)
+	ctx := context.Background()
+	for _, f := range []NumberForwarding{
+		{WorkspaceID: "w", Number: "+14155550100", ForwardTo: "4155550199"},
+		{WorkspaceID: "w", Number: "+14155550100", ForwardTo: "sip:desk", Timezone: "Mars/Base"},
+		{WorkspaceID: "w", Number: "+14155550100", ForwardTo: "sip:desk", Schedule: []ForwardingWindow{{StartMinute: 9 * 60, EndMinute: 25 * 60}}},
+	} {
+		if _, err := svc.Set(ctx, "u", "owner", f); err != ErrInvalidForwarding {
+			t.Fatalf("expected ErrInvalidForwarding for %+v, got %v", f, err)
+		}
+	}
+	if _, err := svc.Set(ctx, "u", "owner", NumberForwarding{WorkspaceID: "w", Number: "+14155550100", ForwardTo: "sip:desk@example.com"}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestRoutingEngine_Forwarding(t *testing.T) {
+	store := NewMemoryForwardingStore()
+	svc := NewForwardingService(store, nil)
+	ctx := context.Background()
+	_, err := svc.Set(ctx, "u", "owner", NumberForwarding{
+		WorkspaceID: "w",
+		Number:      "+14155550100",
+		ForwardTo:   "+14155550199",
+		Schedule:    []ForwardingWindow{{StartMinute: 9 * 60, EndMinute: 17 * 60}},
+		Enabled:     true,
+	})
+	if err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	now := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	e := NewRoutingEngine(nil, stubCampaigns{ev: CampaignEvaluation{Allowed: true, Destinations: []WeightedDestination{{TargetURI: "sip:campaign", Weight: 1}}}}, rand.New(rand.NewSource(1)))
+	e.Forwarding = store
+	e.Now = func() time.Time { return now }
+	route := func(campaignID string) Decision {
+		t.Helper()
+		d, err := e.Route(ctx, RouteInput{WorkspaceID: "w", CampaignID: campaignID, Inbound: telephony.InboundCallRequest{WorkspaceID: "w", To: "+14155550100"}})
+		if err != nil {
+			t.Fatalf("route: %v", err)
+		}
+		return d
+	}
+
+	if d := route(""); d.Action != ActionConnect || d.ConnectTo != "+14155550199" || d.Reason != "number_forwarding" {
+		t.Fatalf("expected forwarding without campaign, got %+v", d)
+	}
+	now = time.Date(2024, 3, 5, 20, 0, 0, 0, time.UTC)
+	if d := route(""); d.Action != ActionReject || d.Reason != "forwarding_outside_schedule" {
+		t.Fatalf("expected rejection outside schedule, got %+v", d)
+	}
+	if d := route("c"); d.ConnectTo != "sip:campaign" {
+		t.Fatalf("expected campaign routing outside schedule, got %+v", d)
+	}
+	if err := svc.Delete(ctx, "w", "+14155550100", "u", "owner"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	now = time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	if d := route(""); d.Reason != "campaign_id_required" {
+		t.Fatalf("expected campaign routing after delete, got %+v", d)
+	}
+}
+
+func TestForwardingService_RejectsStaleVersion(t *testing.T) {
+	ctx := context.Background()
+	fwd := NewForwardingService(NewMemoryForwardingStore(), nil)
+	f, err := fwd.Set(ctx, "u", "owner", NumberForwarding{WorkspaceID: "w", Number: "+15551230000", ForwardTo: "+15550009", Enabled: true})
+	if err != nil || f.Version != 1 {
+		t.Fatalf("set forwarding: %+v err=%v", f, err)
+	}
+	f.Version = 5
+	if _, err := fwd.Set(ctx, "u", "owner", f); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected ErrVersionConflict, got %v", err)
+	}
+}
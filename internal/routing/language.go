@@ -0,0 +1,130 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Language selection.
+//
+// Campaigns with a LanguageMenu play a multilingual menu ("For English press 1.
+// Para español oprima 2.") before any other IVR step. The chosen language becomes a
+// call attribute (Decision.Language) and:
+// - destination selection prefers destinations tagged with that language
+//   (WeightedDestination.Languages), falling back to all destinations if none match;
+// - later prompts (e.g. ZIP collection) use their localized text and TTS voice.
+//
+// No input or an unknown digit selects DefaultLanguage.
+
+const GatherStepLanguage = "language"
+
+var ErrInvalidLanguageMenu = errors.New("routing: invalid language menu")
+
+// LanguageOption is one menu entry.
+type LanguageOption struct {
+	Digit    string `json:"digit"`
+	Language string `json:"language"` // BCP 47, e.g. "en-US", "es-US"
+
+	// Prompt is read in Language, e.g. "Para español, oprima dos." AudioURL, if set,
+	// is played instead (pre-recorded prompt).
+	Prompt   string `json:"prompt"`
+	AudioURL string `json:"audio_url,omitempty"`
+}
+
+// LanguageMenu enables language selection for a campaign.
+type LanguageMenu struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	CampaignID  string `json:"campaign_id" db:"campaign_id"`
+
+	// Options are stored as JSONB in Postgres.
+	Options         []LanguageOption `json:"options" db:"options"`
+	DefaultLanguage string           `json:"default_language" db:"default_language"`
+	TimeoutSeconds  int              `json:"timeout_seconds,omitempty" db:"timeout_seconds"`
+}
+
+// Validate checks the menu shape: single-digit unique keys, and a default that is one of the options.
+func (m LanguageMenu) Validate() error {
+	if m.WorkspaceID == "" || m.CampaignID == "" || len(m.Options) < 2 {
+		return ErrInvalidLanguageMenu
+	}
+	seen := map[string]bool{}
+	hasDefault := false
+	for _, o := range m.Options {
+		if len(o.Digit) != 1 || !allDigits(o.Digit) || seen[o.Digit] || o.Language == "" {
+			return ErrInvalidLanguageMenu
+		}
+		if o.Prompt == "" && o.AudioURL == "" {
+			return ErrInvalidLanguageMenu
+		}
+		seen[o.Digit] = true
+		hasDefault = hasDefault || o.Language == m.DefaultLanguage
+	}
+	if !hasDefault {
+		return ErrInvalidLanguageMenu
+	}
+	return nil
+}
+
+// LanguageMenuStore loads campaign language menus.
+// Implementations must enforce workspace filtering.
+type LanguageMenuStore interface {
+	GetLanguageMenu(ctx context.Context, workspaceID, campaignID string) (LanguageMenu, bool, error)
+}
+
+// LanguageRouter applies language selection during routing.
+type LanguageRouter struct {
+	Store LanguageMenuStore
+}
+
+// Resolve returns the menu prompt on first contact, or the selected language once answered.
+// Campaigns without a menu get ("", nil, nil).
+func (l *LanguageRouter) Resolve(ctx context.Context, workspaceID, campaignID string, collected map[string]string) (string, *GatherPrompt, error) {
+	if l == nil || l.Store == nil || campaignID == "" {
+		return "", nil, nil
+	}
+	menu, ok, err := l.Store.GetLanguageMenu(ctx, workspaceID, campaignID)
+	if err != nil || !ok {
+		return "", nil, err
+	}
+
+	digit, asked := collected[GatherStepLanguage]
+	if !asked {
+		g := &GatherPrompt{Step: GatherStepLanguage, NumDigits: 1, TimeoutSeconds: menu.TimeoutSeconds}
+		if g.TimeoutSeconds <= 0 {
+			g.TimeoutSeconds = 5
+		}
+		for _, o := range menu.Options {
+			g.Segments = append(g.Segments, PromptSegment{Text: o.Prompt, Language: o.Language, AudioURL: o.AudioURL})
+		}
+		return "", g, nil
+	}
+	digit = strings.TrimSpace(digit)
+	for _, o := range menu.Options {
+		if o.Digit == digit {
+			return o.Language, nil, nil
+		}
+	}
+	return menu.DefaultLanguage, nil, nil
+}
+
+// servesLanguage reports whether d is tagged for lang (untagged destinations serve all).
+func servesLanguage(d WeightedDestination, lang string) bool {
+	if lang == "" || len(d.Languages) == 0 {
+		return true
+	}
+	for _, l := range d.Languages {
+		if strings.EqualFold(l, lang) || strings.EqualFold(primaryLanguage(l), primaryLanguage(lang)) {
+			return true
+		}
+	}
+	return false
+}
+
+// primaryLanguage returns the language subtag ("es" for "es-US").
+func primaryLanguage(tag string) string {
+	if i := strings.IndexAny(tag, "-_"); i > 0 {
+		return tag[:i]
+	}
+	return tag
+}
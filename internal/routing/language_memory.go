@@ -0,0 +1,36 @@
+package routing
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryLanguageMenuStore is a simple in-memory LanguageMenuStore useful for tests.
+// It is not intended for production use.
+
+type MemoryLanguageMenuStore struct {
+	mu    sync.Mutex
+	menus map[string]LanguageMenu
+}
+
+func NewMemoryLanguageMenuStore() *MemoryLanguageMenuStore {
+	return &MemoryLanguageMenuStore{menus: map[string]LanguageMenu{}}
+}
+
+// SetLanguageMenu stores m after validation.
+func (s *MemoryLanguageMenuStore) SetLanguageMenu(m LanguageMenu) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.menus[m.WorkspaceID+"|"+m.CampaignID] = m
+	return nil
+}
+
+func (s *MemoryLanguageMenuStore) GetLanguageMenu(ctx context.Context, workspaceID, campaignID string) (LanguageMenu, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.menus[workspaceID+"|"+campaignID]
+	return m, ok, nil
+}
@@ -0,0 +1,67 @@
+package routing
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"telecom-platform/internal/telephony"
+)
+
+func TestLanguageRouting_MenuThenTaggedDestinations(t *testing.T) {
+	ctx := context.Background()
+	menus := NewMemoryLanguageMenuStore()
+	if err := menus.SetLanguageMenu(LanguageMenu{
+		WorkspaceID: "w", CampaignID: "c", DefaultLanguage: "en-US",
+		Options: []LanguageOption{
+			{Digit: "1", Language: "en-US", Prompt: "For English, press 1."},
+			{Digit: "2", Language: "es-US", Prompt: "Para español, oprima 2."},
+		},
+	}); err != nil {
+		t.Fatalf("menu: %v", err)
+	}
+	zips := NewMemoryZipRouteStore()
+	zips.SetZipCollection(ZipCollectionConfig{WorkspaceID: "w", CampaignID: "c", LocalizedPrompts: map[string]string{"es": "Ingrese su código postal."}})
+
+	dests := []WeightedDestination{
+		{TargetURI: "+15550001", Languages: []string{"en"}, Weight: 1},
+		{TargetURI: "+15550002", Languages: []string{"es-US"}, Weight: 1},
+	}
+	e := NewRoutingEngine(nil, stubCampaigns{ev: CampaignEvaluation{Allowed: true, Destinations: dests}}, rand.New(rand.NewSource(1)))
+	e.Languages = &LanguageRouter{Store: menus}
+	e.Zip = &ZipRouter{Store: zips}
+
+	route := func(collected map[string]string) Decision {
+		t.Helper()
+		d, err := e.Route(ctx, RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: "w", Collected: collected}})
+		if err != nil {
+			t.Fatalf("route: %v", err)
+		}
+		return d
+	}
+
+	d := route(nil)
+	if d.Action != ActionGather || d.Gather.Step != GatherStepLanguage || len(d.Gather.Segments) != 2 || d.Gather.Segments[1].Language != "es-US" {
+		t.Fatalf("expected language menu, got %+v", d)
+	}
+	d = route(map[string]string{"language": "2"})
+	if d.Gather == nil || d.Gather.Step != GatherStepZip || d.Gather.Prompt != "Ingrese su código postal." || d.Gather.Language != "es-US" {
+		t.Fatalf("expected localized zip prompt, got %+v", d.Gather)
+	}
+	for i := 0; i < 5; i++ {
+		d = route(map[string]string{"language": "2", "zip": ""})
+		if d.ConnectTo != "+15550002" || d.Language != "es-US" {
+			t.Fatalf("expected spanish destination, got %+v", d)
+		}
+	}
+	if d = route(map[string]string{"language": "", "zip": ""}); d.Language != "en-US" || d.ConnectTo != "+15550001" {
+		t.Fatalf("expected default language on no input, got %+v", d)
+	}
+}
+
+func TestPreferLanguage_FallsBackWhenNoneMatch(t *testing.T) {
+	dests := []WeightedDestination{{TargetURI: "a", Languages: []string{"en"}, Weight: 1}}
+	if got := preferLanguage(dests, "fr-FR"); len(got) != 1 {
+		t.Fatalf("expected fallback to all destinations, got %v", got)
+	}
+}
@@ -0,0 +1,86 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"telecom-platform/internal/pricing"
+)
+
+// Least-cost routing.
+//
+// The least_cost strategy quotes the workspace's outbound per-minute rate for
+// each eligible target (pricing.Service.QuoteCallRate, by the target number) and
+// sends the call to the best score:
+//
+//	score = (1-QualityBlend) * cheapest/rate + QualityBlend * weight/max weight
+//
+// QualityBlend 0 (the default) always picks the cheapest target; raising it lets
+// better-quality routes (higher Weight) win when they cost a little more. Targets
+// without a rate (SIP URIs, unpriced prefixes, group entries) only score on
+// weight, so any priced target with a comparable weight is preferred. Ties go to
+// the earlier entry. Rates are compared in minor units; a workspace's rate card
+// is expected to use one currency.
+
+const StrategyLeastCost SelectionStrategyName = "least_cost"
+
+// LeastCost picks the cheapest target, blended with weight.
+type LeastCost struct {
+	Rates       RateQuoter
+	WorkspaceID string
+	// QualityBlend is clamped to [0, 1].
+	QualityBlend float64
+	// At is the rate time (the pricing service clock when zero).
+	At time.Time
+}
+
+func (s LeastCost) Pick(ctx context.Context, pool string, dests []WeightedDestination, peek bool) (WeightedDestination, error) {
+	rates := make([]int64, len(dests))
+	priced := make([]bool, len(dests))
+	cheapest, maxWeight := int64(-1), 0
+	for i, d := range dests {
+		if d.Weight > maxWeight {
+			maxWeight = d.Weight
+		}
+		if d.TargetURI == "" {
+			continue
+		}
+		q, err := s.Rates.QuoteCallRate(ctx, s.WorkspaceID, pricing.CallDirectionOutbound, d.TargetURI, s.At)
+		if errors.Is(err, pricing.ErrPricingNotFound) {
+			continue
+		}
+		if err != nil {
+			return WeightedDestination{}, err
+		}
+		rates[i], priced[i] = q.RatePerMinuteMinor, true
+		if cheapest < 0 || q.RatePerMinuteMinor < cheapest {
+			cheapest = q.RatePerMinuteMinor
+		}
+	}
+
+	blend := s.QualityBlend
+	if blend < 0 {
+		blend = 0
+	} else if blend > 1 {
+		blend = 1
+	}
+	best, bestScore := 0, -1.0
+	quoted := map[string]int64{}
+	for i, d := range dests {
+		score := blend * float64(d.Weight) / float64(maxWeight)
+		if priced[i] {
+			quoted[d.TargetURI] = rates[i]
+			cost := 1.0
+			if rates[i] > 0 {
+				cost = float64(cheapest) / float64(rates[i])
+			}
+			score += (1 - blend) * cost
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	traceStep(ctx, "destination", "least_cost", selectionMember(dests[best]), map[string]any{"rates_minor": quoted, "quality_blend": blend})
+	return dests[best], nil
+}
@@ -0,0 +1,56 @@
+package routing
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/pricing"
+	"telecom-platform/internal/telephony"
+)
+
+func TestLeastCost_PrefersCheapestWithQualityBlend(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	rate := func(dest string, minor int64) pricing.MinutePricing {
+		return pricing.MinutePricing{ID: dest, WorkspaceID: "w", Direction: pricing.CallDirectionOutbound, Destination: dest,
+			Currency: "USD", RatePerMinuteMinor: minor, BillingIncrementSeconds: 60, EffectiveFrom: at.Add(-time.Hour), Status: pricing.PricingStatusActive}
+	}
+	rates := pricing.NewService(&pricing.MemoryRepo{Minute: []pricing.MinutePricing{rate("+15550001", 10), rate("+15550002", 4)}})
+	dests := []WeightedDestination{
+		{TargetURI: "+15550001", Weight: 10},
+		{TargetURI: "+15550002", Weight: 2},
+		{TargetURI: "sip:pbx@example.com", Weight: 10}, // unpriced
+	}
+	in := RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: "w", ProviderCallID: "p", From: "+1", To: "+2", OccurredAt: at}}
+
+	cases := []struct {
+		blend float64
+		want  string
+	}{
+		{0, "+15550002"},   // cheapest
+		{0.5, "+15550001"}, // 0.5*0.4+0.5*1 beats 0.5*1+0.5*0.2
+		{1, "+15550001"},   // weight only; ties go to the earlier entry
+	}
+	for _, tc := range cases {
+		e := NewRoutingEngine(nil, stubCampaigns{ev: CampaignEvaluation{Allowed: true, Strategy: StrategyLeastCost, QualityBlend: tc.blend, Destinations: dests}}, rand.New(rand.NewSource(1)))
+		e.Rates = rates
+		d, err := e.Route(ctx, in)
+		if err != nil || d.ConnectTo != tc.want {
+			t.Fatalf("blend %v: expected %s, got %+v err=%v", tc.blend, tc.want, d, err)
+		}
+	}
+
+	// The cheapest target at its concurrency limit gives way to the next.
+	dests[1].MaxConcurrent = 1
+	e := NewRoutingEngine(nil, stubCampaigns{ev: CampaignEvaluation{Allowed: true, Strategy: StrategyLeastCost, Destinations: dests}}, rand.New(rand.NewSource(1)))
+	e.Rates = rates
+	e.Concurrency = NewMemoryConcurrencySlots()
+	if _, err := e.Concurrency.Acquire(ctx, "w", "+15550002", "other", 1); err != nil {
+		t.Fatal(err)
+	}
+	if d, err := e.Route(ctx, in); err != nil || d.ConnectTo != "+15550001" {
+		t.Fatalf("expected next cheapest while full, got %+v err=%v", d, err)
+	}
+}
@@ -0,0 +1,235 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Campaign budget pacing.
+//
+// A campaign can have a daily budget that is spread across the local business day:
+// - PacingCurveEven releases budget linearly from local midnight to midnight.
+// - PacingCurveCustom releases budget according to 24 hourly weights (linear within the hour).
+//
+// At routing time the engine reserves the call's estimated cost against the day's
+// spend counter. If the reservation would exceed the allowance released so far, the call
+// is sent to the overflow target (if configured) or rejected with reason "pacing_exceeded".
+// A reservation is released when no destination takes the call; for connected calls
+// the decision carries it (Decision.PacedAt) and billing reconciles it to the actual
+// cost with RecordActual when the call completes.
+//
+// Spend counters live in Redis, keyed by the local calendar date, so the daily reset
+// follows the workspace timezone without a reset job.
+
+type PacingCurve string
+
+const (
+	PacingCurveEven   PacingCurve = "even"
+	PacingCurveCustom PacingCurve = "custom"
+)
+
+var ErrInvalidPacing = errors.New("routing: invalid pacing config")
+
+// PacingConfig is the per-campaign pacing configuration.
+type PacingConfig struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	CampaignID  string `json:"campaign_id" db:"campaign_id"`
+
+	DailyBudgetMinor int64  `json:"daily_budget_minor" db:"daily_budget_minor"`
+	Currency         string `json:"currency" db:"currency"`
+
+	Curve PacingCurve `json:"curve" db:"curve"`
+	// HourlyWeights is required for PacingCurveCustom (index 0 = 00:00-01:00 local).
+	HourlyWeights []int `json:"hourly_weights,omitempty" db:"hourly_weights"`
+
+	// Timezone is the workspace's IANA timezone; empty means UTC.
+	Timezone string `json:"timezone,omitempty" db:"timezone"`
+
+	// OverflowTarget receives calls over pace instead of rejecting them (optional).
+	OverflowTarget string `json:"overflow_target,omitempty" db:"overflow_target"`
+}
+
+// Validate checks the config shape.
+func (c PacingConfig) Validate() error {
+	if c.WorkspaceID == "" || c.CampaignID == "" || c.DailyBudgetMinor <= 0 {
+		return ErrInvalidPacing
+	}
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		return ErrInvalidPacing
+	}
+	switch c.Curve {
+	case PacingCurveEven, "":
+		return nil
+	case PacingCurveCustom:
+		if len(c.HourlyWeights) != 24 {
+			return ErrInvalidPacing
+		}
+		var total int
+		for _, w := range c.HourlyWeights {
+			if w < 0 {
+				return ErrInvalidPacing
+			}
+			total += w
+		}
+		if total == 0 {
+			return ErrInvalidPacing
+		}
+		return nil
+	default:
+		return ErrInvalidPacing
+	}
+}
+
+// PacingConfigStore loads pacing configs. ok=false means the campaign is not paced.
+type PacingConfigStore interface {
+	GetPacing(ctx context.Context, workspaceID, campaignID string) (PacingConfig, bool, error)
+}
+
+// PacingState holds the daily spend counters.
+type PacingState interface {
+	// Reserve atomically adds amountMinor to key if the result stays <= limitMinor.
+	// It returns whether the reservation was made and the counter value afterwards.
+	Reserve(ctx context.Context, key string, amountMinor, limitMinor int64, ttl time.Duration) (bool, int64, error)
+	// Adjust adds deltaMinor (may be negative) unconditionally, e.g. to reconcile actual cost.
+	Adjust(ctx context.Context, key string, deltaMinor int64, ttl time.Duration) error
+}
+
+// PacingPeeker is optionally implemented by PacingState to read a counter without
+// changing it (used by routing dry runs).
+type PacingPeeker interface {
+	Spent(ctx context.Context, key string) (int64, error)
+}
+
+// PaceDecision is the pacing outcome for one call attempt.
+type PaceDecision struct {
+	Allowed        bool
+	Overflow       string
+	SpentMinor     int64
+	AllowanceMinor int64
+}
+
+// Pacer enforces daily campaign pacing.
+type Pacer struct {
+	Configs PacingConfigStore
+	State   PacingState
+
+	// KeyTTL keeps yesterday's counters around for inspection; defaults to 48h.
+	KeyTTL time.Duration
+}
+
+// Check reserves estMinor against today's paced allowance for the campaign.
+// Campaigns without a pacing config are always allowed.
+func (p *Pacer) Check(ctx context.Context, workspaceID, campaignID string, now time.Time, estMinor int64) (PaceDecision, error) {
+	if p == nil || p.Configs == nil {
+		return PaceDecision{Allowed: true}, nil
+	}
+	cfg, ok, err := p.Configs.GetPacing(ctx, workspaceID, campaignID)
+	if err != nil {
+		return PaceDecision{}, err
+	}
+	if !ok {
+		return PaceDecision{Allowed: true}, nil
+	}
+	if p.State == nil {
+		return PaceDecision{}, errors.New("routing: pacing state not configured")
+	}
+	if err := cfg.Validate(); err != nil {
+		return PaceDecision{}, err
+	}
+
+	key, allowance := pacingWindow(cfg, now)
+	if isDryRun(ctx) {
+		// Debugger: report against the current counter without reserving.
+		peeker, ok := p.State.(PacingPeeker)
+		if !ok {
+			return PaceDecision{Allowed: true, AllowanceMinor: allowance}, nil
+		}
+		spent, err := peeker.Spent(ctx, key)
+		if err != nil {
+			return PaceDecision{}, err
+		}
+		out := PaceDecision{Allowed: spent+estMinor <= allowance, SpentMinor: spent, AllowanceMinor: allowance}
+		if !out.Allowed {
+			out.Overflow = cfg.OverflowTarget
+		}
+		return out, nil
+	}
+	reserved, spent, err := p.State.Reserve(ctx, key, estMinor, allowance, p.keyTTL())
+	if err != nil {
+		return PaceDecision{}, err
+	}
+	out := PaceDecision{Allowed: reserved, SpentMinor: spent, AllowanceMinor: allowance}
+	if !reserved {
+		out.Overflow = cfg.OverflowTarget
+	}
+	return out, nil
+}
+
+// RecordActual reconciles the counter once the real call cost is known.
+// startedAt must be the time passed to Check so the same local day is adjusted.
+func (p *Pacer) RecordActual(ctx context.Context, workspaceID, campaignID string, startedAt time.Time, estMinor, actualMinor int64) error {
+	if p == nil || p.Configs == nil || p.State == nil || estMinor == actualMinor {
+		return nil
+	}
+	cfg, ok, err := p.Configs.GetPacing(ctx, workspaceID, campaignID)
+	if err != nil || !ok {
+		return err
+	}
+	key, _ := pacingWindow(cfg, startedAt)
+	return p.State.Adjust(ctx, key, actualMinor-estMinor, p.keyTTL())
+}
+
+// Release returns a reservation made by Check for a call that was not connected.
+func (p *Pacer) Release(ctx context.Context, workspaceID, campaignID string, startedAt time.Time, estMinor int64) error {
+	return p.RecordActual(ctx, workspaceID, campaignID, startedAt, estMinor, 0)
+}
+
+func (p *Pacer) keyTTL() time.Duration {
+	if p.KeyTTL > 0 {
+		return p.KeyTTL
+	}
+	return 48 * time.Hour
+}
+
+// pacingWindow returns the local-day counter key and the allowance released by now.
+func pacingWindow(cfg PacingConfig, now time.Time) (string, int64) {
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	lt := now.In(loc)
+	midnight := time.Date(lt.Year(), lt.Month(), lt.Day(), 0, 0, 0, 0, loc)
+	next := midnight.AddDate(0, 0, 1)
+	key := fmt.Sprintf("pacing:%s:%s:%s", cfg.WorkspaceID, cfg.CampaignID, midnight.Format("2006-01-02"))
+
+	// Fraction of the day elapsed, measured in wall-clock hours so DST days still
+	// release the full budget by local midnight.
+	dayLen := next.Sub(midnight)
+	elapsed := lt.Sub(midnight)
+	hourPos := float64(elapsed) / float64(dayLen) * 24
+
+	var frac float64
+	if cfg.Curve == PacingCurveCustom && len(cfg.HourlyWeights) == 24 {
+		var total, acc int
+		for _, w := range cfg.HourlyWeights {
+			total += w
+		}
+		h := int(hourPos)
+		if h > 23 {
+			h = 23
+		}
+		for i := 0; i < h; i++ {
+			acc += cfg.HourlyWeights[i]
+		}
+		within := hourPos - float64(h)
+		frac = (float64(acc) + within*float64(cfg.HourlyWeights[h])) / float64(total)
+	} else {
+		frac = hourPos / 24
+	}
+	if frac > 1 {
+		frac = 1
+	}
+	return key, int64(frac * float64(cfg.DailyBudgetMinor))
+}
@@ -0,0 +1,57 @@
+package routing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryPacingStore is an in-memory PacingConfigStore and PacingState useful for tests.
+// It is not intended for production use (TTLs are ignored).
+
+type MemoryPacingStore struct {
+	mu       sync.Mutex
+	configs  map[string]PacingConfig
+	counters map[string]int64
+}
+
+func NewMemoryPacingStore() *MemoryPacingStore {
+	return &MemoryPacingStore{configs: map[string]PacingConfig{}, counters: map[string]int64{}}
+}
+
+func (s *MemoryPacingStore) SetPacing(cfg PacingConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configs[cfg.WorkspaceID+"|"+cfg.CampaignID] = cfg
+}
+
+func (s *MemoryPacingStore) GetPacing(ctx context.Context, workspaceID, campaignID string) (PacingConfig, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg, ok := s.configs[workspaceID+"|"+campaignID]
+	return cfg, ok, nil
+}
+
+func (s *MemoryPacingStore) Reserve(ctx context.Context, key string, amountMinor, limitMinor int64, ttl time.Duration) (bool, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur := s.counters[key]
+	if cur+amountMinor > limitMinor {
+		return false, cur, nil
+	}
+	s.counters[key] = cur + amountMinor
+	return true, cur + amountMinor, nil
+}
+
+func (s *MemoryPacingStore) Adjust(ctx context.Context, key string, deltaMinor int64, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counters[key] += deltaMinor
+	return nil
+}
+
+func (s *MemoryPacingStore) Spent(ctx context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.counters[key], nil
+}
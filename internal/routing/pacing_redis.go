@@ -0,0 +1,67 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var pacingReserveScript = redis.NewScript(`
+-- KEYS[1] = daily spend counter
+-- ARGV[1] = amount (int), ARGV[2] = limit (int), ARGV[3] = ttl_ms (int)
+--
+-- Returns {1, spent} if reserved, {0, spent} if the pace would be exceeded.
+local cur = tonumber(redis.call('GET', KEYS[1]) or '0')
+local amt = tonumber(ARGV[1])
+if cur + amt > tonumber(ARGV[2]) then
+  return {0, cur}
+end
+local v = redis.call('INCRBY', KEYS[1], amt)
+if redis.call('PTTL', KEYS[1]) < 0 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+return {1, v}
+`)
+
+// RedisPacingState implements PacingState with an atomic Lua check-and-add.
+type RedisPacingState struct {
+	Client *redis.Client
+}
+
+func (r RedisPacingState) Reserve(ctx context.Context, key string, amountMinor, limitMinor int64, ttl time.Duration) (bool, int64, error) {
+	if r.Client == nil {
+		return false, 0, errors.New("routing: redis client is nil")
+	}
+	res, err := pacingReserveScript.Run(ctx, r.Client, []string{key}, amountMinor, limitMinor, ttl.Milliseconds()).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	if len(res) != 2 {
+		return false, 0, errors.New("routing: unexpected pacing script result")
+	}
+	return res[0] == 1, res[1], nil
+}
+
+func (r RedisPacingState) Adjust(ctx context.Context, key string, deltaMinor int64, ttl time.Duration) error {
+	if r.Client == nil {
+		return errors.New("routing: redis client is nil")
+	}
+	pipe := r.Client.TxPipeline()
+	pipe.IncrBy(ctx, key, deltaMinor)
+	pipe.PExpire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r RedisPacingState) Spent(ctx context.Context, key string) (int64, error) {
+	if r.Client == nil {
+		return 0, errors.New("routing: redis client is nil")
+	}
+	v, err := r.Client.Get(ctx, key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return v, err
+}
@@ -0,0 +1,106 @@
+package routing
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/telephony"
+	"telecom-platform/internal/wallet"
+)
+
+func TestPacingWindow_EvenCurveFollowsLocalDay(t *testing.T) {
+	cfg := PacingConfig{WorkspaceID: "w", CampaignID: "c", DailyBudgetMinor: 2400, Timezone: "America/New_York"}
+	// 2024-03-05 12:00 New York (EST, UTC-5) is half way through the local day.
+	key, allowance := pacingWindow(cfg, time.Date(2024, 3, 5, 17, 0, 0, 0, time.UTC))
+	if key != "pacing:w:c:2024-03-05" || allowance != 1200 {
+		t.Fatalf("unexpected window: %s %d", key, allowance)
+	}
+	// 03:00 UTC on the 6th is still the 5th locally.
+	key, _ = pacingWindow(cfg, time.Date(2024, 3, 6, 3, 0, 0, 0, time.UTC))
+	if key != "pacing:w:c:2024-03-05" {
+		t.Fatalf("expected local date key, got %s", key)
+	}
+}
+
+func TestPacingWindow_CustomCurve(t *testing.T) {
+	weights := make([]int, 24)
+	weights[9], weights[10] = 1, 1
+	cfg := PacingConfig{WorkspaceID: "w", CampaignID: "c", DailyBudgetMinor: 1000, Curve: PacingCurveCustom, HourlyWeights: weights}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected validate err: %v", err)
+	}
+	cases := map[int]int64{8: 0, 10: 500, 12: 1000}
+	for hour, want := range cases {
+		_, got := pacingWindow(cfg, time.Date(2024, 3, 5, hour, 0, 0, 0, time.UTC))
+		if got != want {
+			t.Fatalf("hour %d: expected %d, got %d", hour, want, got)
+		}
+	}
+}
+
+func TestRoutingEngine_PacingOverflowsThenRejects(t *testing.T) {
+	store := NewMemoryPacingStore()
+	store.SetPacing(PacingConfig{WorkspaceID: "w", CampaignID: "c", DailyBudgetMinor: 2400, OverflowTarget: "sip:overflow"})
+	e := NewRoutingEngine(nil, stubCampaigns{ev: CampaignEvaluation{Allowed: true, Destinations: []WeightedDestination{{TargetURI: "sip:a", Weight: 1}}}}, rand.New(rand.NewSource(1)))
+	e.Pacing = &Pacer{Configs: store, State: store}
+	e.Now = func() time.Time { return time.Date(2024, 3, 5, 1, 0, 0, 0, time.UTC) } // 100 released
+
+	in := RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: "w"}}
+	d, err := e.Route(context.Background(), in)
+	if err != nil || d.ConnectTo != "sip:a" {
+		t.Fatalf("expected normal connect, got %+v err=%v", d, err)
+	}
+	_ = store.Adjust(context.Background(), "pacing:w:c:2024-03-05", 150, time.Hour)
+
+	d, err = e.Route(context.Background(), in)
+	if err != nil || d.Reason != "pacing_overflow" || d.ConnectTo != "sip:overflow" {
+		t.Fatalf("expected overflow, got %+v err=%v", d, err)
+	}
+
+	store.SetPacing(PacingConfig{WorkspaceID: "w", CampaignID: "c", DailyBudgetMinor: 2400})
+	d, err = e.Route(context.Background(), in)
+	if err != nil || d.Action != ActionReject || d.Reason != "pacing_exceeded" {
+		t.Fatalf("expected reject, got %+v err=%v", d, err)
+	}
+
+	// Next local day starts from zero.
+	e.Now = func() time.Time { return time.Date(2024, 3, 6, 1, 0, 0, 0, time.UTC) }
+	d, err = e.Route(context.Background(), in)
+	if err != nil || d.Action != ActionConnect {
+		t.Fatalf("expected connect after reset, got %+v err=%v", d, err)
+	}
+}
+
+func TestRoutingEngine_PacingReleasedWhenNoDestination(t *testing.T) {
+	store := NewMemoryPacingStore()
+	store.SetPacing(PacingConfig{WorkspaceID: "w", CampaignID: "c", DailyBudgetMinor: 2400})
+	campaigns := stubCampaigns{ev: CampaignEvaluation{Allowed: true, Destinations: []WeightedDestination{{TargetURI: "sip:a", Weight: 0}}}}
+	e := NewRoutingEngine(stubWallet{bal: wallet.Balance{Currency: "USD", BalanceMinor: 1000}}, campaigns, rand.New(rand.NewSource(1)))
+	e.Pacing = &Pacer{Configs: store, State: store}
+	e.Now = func() time.Time { return time.Date(2024, 3, 5, 1, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+
+	in := RouteInput{WorkspaceID: "w", CampaignID: "c", WalletID: "wa", Currency: "USD", EstimatedMinor: 40, Inbound: telephony.InboundCallRequest{WorkspaceID: "w"}}
+	d, err := e.Route(ctx, in)
+	if err != nil || d.Reason != "no_eligible_destination" {
+		t.Fatalf("expected no destination, got %+v err=%v", d, err)
+	}
+	if spent, _ := store.Spent(ctx, "pacing:w:c:2024-03-05"); spent != 0 {
+		t.Fatalf("expected reservation released, spent=%d", spent)
+	}
+
+	// A connected call carries its reservation for billing to reconcile.
+	e.Campaigns = stubCampaigns{ev: CampaignEvaluation{Allowed: true, Destinations: []WeightedDestination{{TargetURI: "sip:a", Weight: 1}}}}
+	d, err = e.Route(ctx, in)
+	if err != nil || d.PacedAt == nil || d.PacingReservedMinor != 40 {
+		t.Fatalf("expected reservation on decision, got %+v err=%v", d, err)
+	}
+	if err := e.Pacing.RecordActual(ctx, "w", "c", *d.PacedAt, d.PacingReservedMinor, 25); err != nil {
+		t.Fatalf("RecordActual: %v", err)
+	}
+	if spent, _ := store.Spent(ctx, "pacing:w:c:2024-03-05"); spent != 25 {
+		t.Fatalf("expected spend reconciled to 25, got %d", spent)
+	}
+}
@@ -0,0 +1,350 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"telecom-platform/internal/audit"
+	"telecom-platform/internal/telephony"
+	"telecom-platform/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Scheduled routing changes.
+//
+// A campaign can have future routing configs stored as dated versions, e.g.
+// "switch to the overflow center at 18:00 Friday until Monday 08:00".
+//
+// Flow:
+// - An operator schedules a version (ScheduleService.Schedule). Who scheduled it is audited.
+// - The worker periodically calls ScheduleActivator.RunOnce, which activates versions whose
+//   window has started and expires versions whose window has ended.
+// - ScheduledCampaigns wraps the regular CampaignService and swaps in the destinations of
+//   the currently active version (if any). Campaign allow/block rules are left untouched.
+
+type ScheduleStatus string
+
+const (
+	ScheduleStatusPending  ScheduleStatus = "pending"
+	ScheduleStatusActive   ScheduleStatus = "active"
+	ScheduleStatusExpired  ScheduleStatus = "expired"
+	ScheduleStatusCanceled ScheduleStatus = "canceled"
+)
+
+// RoutingConfigVersion is a dated routing config for a campaign.
+type RoutingConfigVersion struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	CampaignID  string `json:"campaign_id" db:"campaign_id"`
+
+	// Destinations replace the campaign destinations while the version is active.
+	// Store as JSONB in Postgres.
+	Destinations []WeightedDestination `json:"destinations" db:"destinations"`
+
+	// EffectiveFrom is inclusive. EffectiveTo is exclusive; nil means open-ended.
+	EffectiveFrom time.Time  `json:"effective_from" db:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty" db:"effective_to"`
+
+	Status ScheduleStatus `json:"status" db:"status"`
+
+	ScheduledByUserID string `json:"scheduled_by_user_id" db:"scheduled_by_user_id"`
+	ScheduledByRole   string `json:"scheduled_by_role" db:"scheduled_by_role"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// coversTime reports whether t falls inside the version window.
+func (v RoutingConfigVersion) coversTime(t time.Time) bool {
+	if t.Before(v.EffectiveFrom) {
+		return false
+	}
+	if v.EffectiveTo != nil && !t.Before(*v.EffectiveTo) {
+		return false
+	}
+	return true
+}
+
+// ScheduleStore persists routing config versions.
+// Implementations must enforce workspace filtering.
+type ScheduleStore interface {
+	CreateVersion(ctx context.Context, v RoutingConfigVersion) error
+	GetVersion(ctx context.Context, workspaceID, versionID string) (RoutingConfigVersion, bool, error)
+	UpdateVersionStatus(ctx context.Context, workspaceID, versionID string, status ScheduleStatus, now time.Time) error
+
+	// ListCampaignVersions returns all versions for a campaign ordered by EffectiveFrom.
+	ListCampaignVersions(ctx context.Context, workspaceID, campaignID string) ([]RoutingConfigVersion, error)
+
+	// ListDueTransitions returns pending versions whose window has started and active versions
+	// whose window has ended, across all workspaces. Used by the worker only.
+	ListDueTransitions(ctx context.Context, now time.Time) ([]RoutingConfigVersion, error)
+}
+
+var (
+	ErrInvalidSchedule  = errors.New("routing: invalid schedule")
+	ErrScheduleNotFound = errors.New("routing: schedule not found")
+	ErrScheduleOverlap  = errors.New("routing: schedule overlaps an existing version")
+)
+
+// ScheduleService manages scheduled routing versions.
+type ScheduleService struct {
+	Store ScheduleStore
+	Audit *audit.Service
+	Now   func() time.Time
+}
+
+func NewScheduleService(store ScheduleStore, auditSvc *audit.Service) *ScheduleService {
+	return &ScheduleService{Store: store, Audit: auditSvc, Now: time.Now}
+}
+
+type ScheduleRequest struct {
+	CampaignID    string
+	Destinations  []WeightedDestination
+	EffectiveFrom time.Time
+	EffectiveTo   *time.Time
+}
+
+// Schedule stores a new pending version. Overlapping windows for the same campaign are rejected
+// so activation is always unambiguous.
+func (s *ScheduleService) Schedule(ctx context.Context, workspaceID, actorUserID, actorRole string, req ScheduleRequest) (RoutingConfigVersion, error) {
+	if workspaceID == "" || req.CampaignID == "" || actorUserID == "" {
+		return RoutingConfigVersion{}, ErrInvalidSchedule
+	}
+	if req.EffectiveFrom.IsZero() {
+		return RoutingConfigVersion{}, ErrInvalidSchedule
+	}
+	if req.EffectiveTo != nil && !req.EffectiveTo.After(req.EffectiveFrom) {
+		return RoutingConfigVersion{}, ErrInvalidSchedule
+	}
+	if !hasEligibleDestination(req.Destinations) {
+		return RoutingConfigVersion{}, ErrInvalidSchedule
+	}
+	if s.Store == nil {
+		return RoutingConfigVersion{}, errors.New("routing: schedule store not configured")
+	}
+	if s.Now == nil {
+		s.Now = time.Now
+	}
+
+	existing, err := s.Store.ListCampaignVersions(ctx, workspaceID, req.CampaignID)
+	if err != nil {
+		return RoutingConfigVersion{}, err
+	}
+	for _, v := range existing {
+		if v.Status != ScheduleStatusPending && v.Status != ScheduleStatusActive {
+			continue
+		}
+		if windowsOverlap(v.EffectiveFrom, v.EffectiveTo, req.EffectiveFrom, req.EffectiveTo) {
+			return RoutingConfigVersion{}, ErrScheduleOverlap
+		}
+	}
+
+	now := s.Now().UTC()
+	v := RoutingConfigVersion{
+		ID:                uuid.NewString(),
+		WorkspaceID:       workspaceID,
+		CampaignID:        req.CampaignID,
+		Destinations:      req.Destinations,
+		EffectiveFrom:     req.EffectiveFrom.UTC(),
+		EffectiveTo:       req.EffectiveTo,
+		Status:            ScheduleStatusPending,
+		ScheduledByUserID: actorUserID,
+		ScheduledByRole:   actorRole,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := s.Store.CreateVersion(ctx, v); err != nil {
+		return RoutingConfigVersion{}, err
+	}
+
+	s.audit(ctx, v, actorUserID, actorRole, "routing change scheduled")
+	return v, nil
+}
+
+// Cancel cancels a pending or active version.
+func (s *ScheduleService) Cancel(ctx context.Context, workspaceID, versionID, actorUserID, actorRole string) error {
+	if workspaceID == "" || versionID == "" || actorUserID == "" {
+		return ErrInvalidSchedule
+	}
+	if s.Store == nil {
+		return errors.New("routing: schedule store not configured")
+	}
+	if s.Now == nil {
+		s.Now = time.Now
+	}
+
+	v, ok, err := s.Store.GetVersion(ctx, workspaceID, versionID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrScheduleNotFound
+	}
+	if v.Status != ScheduleStatusPending && v.Status != ScheduleStatusActive {
+		return ErrInvalidSchedule
+	}
+	if err := s.Store.UpdateVersionStatus(ctx, workspaceID, versionID, ScheduleStatusCanceled, s.Now().UTC()); err != nil {
+		return err
+	}
+
+	s.audit(ctx, v, actorUserID, actorRole, "routing change canceled")
+	return nil
+}
+
+func (s *ScheduleService) audit(ctx context.Context, v RoutingConfigVersion, actorUserID, actorRole, message string) {
+	if s.Audit == nil {
+		return
+	}
+	// Best-effort; scheduling must not fail on audit errors.
+	_ = s.Audit.Append(ctx, audit.Event{
+		WorkspaceID: v.WorkspaceID,
+		Type:        audit.EventTypeRoutingSchedule,
+		ActorUserID: actorUserID,
+		ActorRole:   actorRole,
+		IPAddress:   ClientIPFromContext(ctx),
+		CampaignID:  v.CampaignID,
+		Message:     message,
+		Metadata:    versionMetadata(v),
+	})
+}
+
+func versionMetadata(v RoutingConfigVersion) string {
+	meta, _ := json.Marshal(map[string]string{"version_id": v.ID})
+	return string(meta)
+}
+
+// ScheduleActivator is run by the worker to move versions through their lifecycle.
+type ScheduleActivator struct {
+	Store ScheduleStore
+	Audit *audit.Service
+	Now   func() time.Time
+}
+
+func NewScheduleActivator(store ScheduleStore, auditSvc *audit.Service) *ScheduleActivator {
+	return &ScheduleActivator{Store: store, Audit: auditSvc, Now: time.Now}
+}
+
+// RunOnce applies all due transitions and returns how many versions changed status.
+func (a *ScheduleActivator) RunOnce(ctx context.Context) (int, error) {
+	if a.Store == nil {
+		return 0, errors.New("routing: schedule store not configured")
+	}
+	if a.Now == nil {
+		a.Now = time.Now
+	}
+	now := a.Now().UTC()
+
+	due, err := a.Store.ListDueTransitions(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, v := range due {
+		next := v.Status
+		switch {
+		case v.EffectiveTo != nil && !now.Before(*v.EffectiveTo):
+			next = ScheduleStatusExpired
+		case v.Status == ScheduleStatusPending && v.coversTime(now):
+			next = ScheduleStatusActive
+		}
+		if next == v.Status {
+			continue
+		}
+		if err := a.Store.UpdateVersionStatus(ctx, v.WorkspaceID, v.ID, next, now); err != nil {
+			return changed, err
+		}
+		changed++
+
+		if a.Audit != nil {
+			_ = a.Audit.Append(ctx, audit.Event{
+				WorkspaceID: v.WorkspaceID,
+				Type:        audit.EventTypeRoutingSchedule,
+				ActorUserID: v.ScheduledByUserID,
+				ActorRole:   v.ScheduledByRole,
+				CampaignID:  v.CampaignID,
+				Message:     "routing change " + string(next),
+				Metadata:    versionMetadata(v),
+			})
+		}
+	}
+	return changed, nil
+}
+
+// Run calls RunOnce every interval until ctx is canceled.
+func (a *ScheduleActivator) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if _, err := a.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			// Keep going; transient store errors are retried on the next tick.
+			logger.From(ctx).Error("routing schedule activation failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// ScheduledCampaigns decorates a CampaignService with scheduled destination switches.
+type ScheduledCampaigns struct {
+	Next  CampaignService
+	Store ScheduleStore
+	Now   func() time.Time
+}
+
+func NewScheduledCampaigns(next CampaignService, store ScheduleStore) *ScheduledCampaigns {
+	return &ScheduledCampaigns{Next: next, Store: store, Now: time.Now}
+}
+
+func (s *ScheduledCampaigns) EvaluateInbound(ctx context.Context, workspaceID, campaignID string, req telephony.InboundCallRequest) (CampaignEvaluation, error) {
+	if s.Next == nil {
+		return CampaignEvaluation{}, errors.New("routing: campaign service not configured")
+	}
+	ev, err := s.Next.EvaluateInbound(ctx, workspaceID, campaignID, req)
+	if err != nil || !ev.Allowed || s.Store == nil {
+		return ev, err
+	}
+	if s.Now == nil {
+		s.Now = time.Now
+	}
+	now := s.Now().UTC()
+
+	versions, err := s.Store.ListCampaignVersions(ctx, workspaceID, campaignID)
+	if err != nil {
+		return CampaignEvaluation{}, err
+	}
+	// Only versions the worker has activated apply; the window check guards against
+	// a lagging worker keeping an ended version alive.
+	for _, v := range versions {
+		if v.Status == ScheduleStatusActive && v.coversTime(now) {
+			ev.Destinations = v.Destinations
+			break
+		}
+	}
+	return ev, nil
+}
+
+func hasEligibleDestination(dests []WeightedDestination) bool {
+	for _, d := range dests {
+		if (d.TargetURI != "" || d.GroupID != "") && d.Weight > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func windowsOverlap(aFrom time.Time, aTo *time.Time, bFrom time.Time, bTo *time.Time) bool {
+	// [aFrom, aTo) and [bFrom, bTo) overlap when each starts before the other ends.
+	aStartsBeforeBEnds := bTo == nil || aFrom.Before(*bTo)
+	bStartsBeforeAEnds := aTo == nil || bFrom.Before(*aTo)
+	return aStartsBeforeBEnds && bStartsBeforeAEnds
+}
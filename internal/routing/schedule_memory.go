@@ -0,0 +1,88 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryScheduleStore is a simple in-memory ScheduleStore useful for tests.
+// It is not intended for production use.
+
+type MemoryScheduleStore struct {
+	mu       sync.Mutex
+	versions map[string]RoutingConfigVersion
+}
+
+func NewMemoryScheduleStore() *MemoryScheduleStore {
+	return &MemoryScheduleStore{versions: map[string]RoutingConfigVersion{}}
+}
+
+func (s *MemoryScheduleStore) CreateVersion(ctx context.Context, v RoutingConfigVersion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.versions[v.ID]; ok {
+		return errors.New("routing: version already exists")
+	}
+	s.versions[v.ID] = v
+	return nil
+}
+
+func (s *MemoryScheduleStore) GetVersion(ctx context.Context, workspaceID, versionID string) (RoutingConfigVersion, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.versions[versionID]
+	if !ok || v.WorkspaceID != workspaceID {
+		return RoutingConfigVersion{}, false, nil
+	}
+	return v, true, nil
+}
+
+func (s *MemoryScheduleStore) UpdateVersionStatus(ctx context.Context, workspaceID, versionID string, status ScheduleStatus, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.versions[versionID]
+	if !ok || v.WorkspaceID != workspaceID {
+		return ErrScheduleNotFound
+	}
+	v.Status = status
+	v.UpdatedAt = now
+	s.versions[versionID] = v
+	return nil
+}
+
+func (s *MemoryScheduleStore) ListCampaignVersions(ctx context.Context, workspaceID, campaignID string) ([]RoutingConfigVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]RoutingConfigVersion, 0)
+	for _, v := range s.versions {
+		if v.WorkspaceID == workspaceID && v.CampaignID == campaignID {
+			out = append(out, v)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].EffectiveFrom.Before(out[j].EffectiveFrom) })
+	return out, nil
+}
+
+func (s *MemoryScheduleStore) ListDueTransitions(ctx context.Context, now time.Time) ([]RoutingConfigVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]RoutingConfigVersion, 0)
+	for _, v := range s.versions {
+		ended := v.EffectiveTo != nil && !now.Before(*v.EffectiveTo)
+		switch v.Status {
+		case ScheduleStatusPending:
+			if !now.Before(v.EffectiveFrom) {
+				out = append(out, v)
+			}
+		case ScheduleStatusActive:
+			if ended {
+				out = append(out, v)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].EffectiveFrom.Before(out[j].EffectiveFrom) })
+	return out, nil
+}
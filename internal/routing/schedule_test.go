@@ -0,0 +1,81 @@
+package routing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/audit"
+	"telecom-platform/internal/telephony"
+)
+
+func TestScheduleService_RejectsOverlap(t *testing.T) {
+	now := time.Unix(1700000000, 0).UTC()
+	svc := NewScheduleService(NewMemoryScheduleStore(), nil)
+	svc.Now = func() time.Time { return now }
+
+	end := now.Add(2 * time.Hour)
+	req := ScheduleRequest{CampaignID: "c", Destinations: []WeightedDestination{{TargetURI: "sip:overflow", Weight: 1}}, EffectiveFrom: now.Add(time.Hour), EffectiveTo: &end}
+	if _, err := svc.Schedule(context.Background(), "w", "u", "owner", req); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	req.EffectiveFrom = now.Add(90 * time.Minute)
+	req.EffectiveTo = nil
+	if _, err := svc.Schedule(context.Background(), "w", "u", "owner", req); err != ErrScheduleOverlap {
+		t.Fatalf("expected ErrScheduleOverlap, got %v", err)
+	}
+}
+
+func TestScheduleActivator_ActivatesThenExpires(t *testing.T) {
+	now := time.Unix(1700000000, 0).UTC()
+	store := NewMemoryScheduleStore()
+	repo := audit.NewMemoryRepo()
+	svc := NewScheduleService(store, audit.NewService(repo))
+	svc.Now = func() time.Time { return now }
+
+	end := now.Add(2 * time.Hour)
+	v, err := svc.Schedule(context.Background(), "w", "u", "owner", ScheduleRequest{
+		CampaignID:    "c",
+		Destinations:  []WeightedDestination{{TargetURI: "sip:overflow", Weight: 1}},
+		EffectiveFrom: now.Add(time.Hour),
+		EffectiveTo:   &end,
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	campaigns := NewScheduledCampaigns(stubCampaigns{ev: CampaignEvaluation{Allowed: true, Destinations: []WeightedDestination{{TargetURI: "sip:primary", Weight: 1}}}}, store)
+	act := NewScheduleActivator(store, nil)
+
+	at := now.Add(time.Hour)
+	act.Now = func() time.Time { return at }
+	campaigns.Now = act.Now
+	if n, err := act.RunOnce(context.Background()); err != nil || n != 1 {
+		t.Fatalf("expected 1 activation, got %d (%v)", n, err)
+	}
+	ev, err := campaigns.EvaluateInbound(context.Background(), "w", "c", telephony.InboundCallRequest{WorkspaceID: "w"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(ev.Destinations) != 1 || ev.Destinations[0].TargetURI != "sip:overflow" {
+		t.Fatalf("expected overflow destination, got %+v", ev.Destinations)
+	}
+
+	at = end
+	if n, err := act.RunOnce(context.Background()); err != nil || n != 1 {
+		t.Fatalf("expected 1 expiry, got %d (%v)", n, err)
+	}
+	got, _, _ := store.GetVersion(context.Background(), "w", v.ID)
+	if got.Status != ScheduleStatusExpired {
+		t.Fatalf("expected expired, got %q", got.Status)
+	}
+	ev, _ = campaigns.EvaluateInbound(context.Background(), "w", "c", telephony.InboundCallRequest{WorkspaceID: "w"})
+	if ev.Destinations[0].TargetURI != "sip:primary" {
+		t.Fatalf("expected primary destination after expiry, got %+v", ev.Destinations)
+	}
+
+	if evs := repo.Events(); len(evs) != 1 || evs[0].ActorUserID != "u" {
+		t.Fatalf("expected scheduling audited with actor, got %+v", evs)
+	}
+}
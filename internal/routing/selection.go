@@ -0,0 +1,162 @@
+package routing
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Destination selection strategies.
+//
+// A campaign chooses how one of its eligible destinations is picked
+// (CampaignEvaluation.Strategy):
+// - weighted_random (default): random draw proportional to Weight;
+// - round_robin: each destination in turn, in list order;
+// - least_used: the destination selected longest ago (never-used ones first);
+// - least_cost: the cheapest target by outbound rate (see least_cost.go).
+//
+// The rotating strategies keep their state in SelectionState (Redis in
+// production) so every API instance shares one rotation; for them Weight only
+// decides eligibility (> 0). Each pool rotates on its own: the campaign's
+// top-level destinations, and each destination group reached from it.
+//
+// Without SelectionState configured, the rotating strategies fall back to
+// weighted_random so a missing dependency never drops calls.
+
+type SelectionStrategyName string
+
+const (
+	StrategyWeightedRandom SelectionStrategyName = "weighted_random"
+	StrategyRoundRobin     SelectionStrategyName = "round_robin"
+	StrategyLeastUsed      SelectionStrategyName = "least_used"
+)
+
+// SelectionStrategy picks one of dests for a call. dests is non-empty and every
+// entry is eligible. pool identifies the destination list for stateful
+// strategies; peek is set during dry runs, when state must not change.
+type SelectionStrategy interface {
+	Pick(ctx context.Context, pool string, dests []WeightedDestination, peek bool) (WeightedDestination, error)
+}
+
+// SelectionState holds rotation state shared by API instances.
+type SelectionState interface {
+	// Rotate returns the pool's counter for this call (1 on first use) and, unless
+	// peek, advances it.
+	Rotate(ctx context.Context, pool string, peek bool) (int64, error)
+	// LeastRecent returns the member used longest ago (never-used members first, in
+	// list order) and, unless peek, records it as used at now.
+	LeastRecent(ctx context.Context, pool string, members []string, now time.Time, peek bool) (string, error)
+}
+
+// WeightedRandom draws a destination with probability proportional to its weight.
+type WeightedRandom struct {
+	RNG *rand.Rand
+}
+
+func (s WeightedRandom) Pick(ctx context.Context, pool string, dests []WeightedDestination, peek bool) (WeightedDestination, error) {
+	total := 0
+	for _, d := range dests {
+		total += d.Weight
+	}
+	rng := s.RNG
+	if rng == nil {
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	r := rng.Intn(total) // 0..total-1
+
+	var acc int
+	for _, d := range dests {
+		acc += d.Weight
+		if r < acc {
+			return d, nil
+		}
+	}
+	return dests[len(dests)-1], nil
+}
+
+// RoundRobin hands calls to each destination in turn.
+type RoundRobin struct {
+	State SelectionState
+}
+
+func (s RoundRobin) Pick(ctx context.Context, pool string, dests []WeightedDestination, peek bool) (WeightedDestination, error) {
+	n, err := s.State.Rotate(ctx, pool, peek)
+	if err != nil {
+		return WeightedDestination{}, err
+	}
+	i := (n - 1) % int64(len(dests))
+	if i < 0 {
+		i += int64(len(dests))
+	}
+	return dests[i], nil
+}
+
+// LeastUsed hands the call to the destination selected longest ago.
+type LeastUsed struct {
+	State SelectionState
+	Now   func() time.Time
+}
+
+func (s LeastUsed) Pick(ctx context.Context, pool string, dests []WeightedDestination, peek bool) (WeightedDestination, error) {
+	members := make([]string, len(dests))
+	for i, d := range dests {
+		members[i] = selectionMember(d)
+	}
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	m, err := s.State.LeastRecent(ctx, pool, members, now(), peek)
+	if err != nil {
+		return WeightedDestination{}, err
+	}
+	for i := range dests {
+		if members[i] == m {
+			return dests[i], nil
+		}
+	}
+	return dests[0], nil
+}
+
+func selectionMember(d WeightedDestination) string {
+	if d.GroupID != "" {
+		return "group:" + d.GroupID
+	}
+	return d.TargetURI
+}
+
+func selectionPool(workspaceID, campaignID string) string {
+	return "routing:select:" + workspaceID + ":" + campaignID
+}
+
+// selectionStrategy resolves a campaign's strategy for a call. Strategies
+// registers custom strategies (and may replace built-ins); unknown names, rotating
+// strategies without state and least_cost without Rates fall back to
+// weighted_random.
+func (e *RoutingEngine) selectionStrategy(ctx context.Context, in RouteInput, ev CampaignEvaluation) (SelectionStrategyName, SelectionStrategy) {
+	name := ev.Strategy
+	if s, ok := e.Strategies[name]; ok && name != "" {
+		return name, s
+	}
+	switch name {
+	case "", StrategyWeightedRandom:
+	case StrategyRoundRobin, StrategyLeastUsed:
+		if e.Selection == nil {
+			traceStep(ctx, "destination", "fallback", "selection state not configured", map[string]any{"strategy": name})
+			break
+		}
+		if name == StrategyRoundRobin {
+			return name, RoundRobin{State: e.Selection}
+		}
+		return name, LeastUsed{State: e.Selection, Now: e.Now}
+	case StrategyLeastCost:
+		if e.Rates == nil {
+			traceStep(ctx, "destination", "fallback", "rates not configured", map[string]any{"strategy": name})
+			break
+		}
+		return name, LeastCost{Rates: e.Rates, WorkspaceID: in.WorkspaceID, QualityBlend: ev.QualityBlend, At: in.Inbound.OccurredAt}
+	default:
+		traceStep(ctx, "destination", "fallback", "unknown selection strategy", map[string]any{"strategy": name})
+	}
+	return StrategyWeightedRandom, WeightedRandom{RNG: e.RNG}
+}
@@ -0,0 +1,60 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// MemorySelectionState is an in-memory SelectionState useful for tests.
+// It is not intended for production use (state is per process).
+
+type MemorySelectionState struct {
+	mu       sync.Mutex
+	counters map[string]int64
+	lastUsed map[string]map[string]time.Time
+}
+
+func NewMemorySelectionState() *MemorySelectionState {
+	return &MemorySelectionState{counters: map[string]int64{}, lastUsed: map[string]map[string]time.Time{}}
+}
+
+func (s *MemorySelectionState) Rotate(ctx context.Context, pool string, peek bool) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := s.counters[pool] + 1
+	if !peek {
+		s.counters[pool] = n
+	}
+	return n, nil
+}
+
+func (s *MemorySelectionState) LeastRecent(ctx context.Context, pool string, members []string, now time.Time, peek bool) (string, error) {
+	if len(members) == 0 {
+		return "", errors.New("routing: no members to select from")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	used := s.lastUsed[pool]
+	best := ""
+	var bestAt time.Time
+	for i, m := range members {
+		at, ok := used[m]
+		if !ok {
+			best = m
+			break
+		}
+		if i == 0 || at.Before(bestAt) {
+			best, bestAt = m, at
+		}
+	}
+	if !peek {
+		if used == nil {
+			used = map[string]time.Time{}
+			s.lastUsed[pool] = used
+		}
+		used[best] = now
+	}
+	return best, nil
+}
@@ -0,0 +1,84 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var leastRecentScript = redis.NewScript(`
+-- KEYS[1] = sorted set member -> last selected (unix ms)
+-- ARGV[1] = now_ms, ARGV[2] = peek (0/1), ARGV[3] = ttl_ms, ARGV[4..] = members
+--
+-- Returns the member with the lowest score; unknown members count as never used.
+local best, bestScore
+for i = 4, #ARGV do
+  local s = redis.call('ZSCORE', KEYS[1], ARGV[i])
+  local score = s and tonumber(s) or -1
+  if best == nil or score < bestScore then
+    best, bestScore = ARGV[i], score
+  end
+end
+if ARGV[2] == '0' then
+  redis.call('ZADD', KEYS[1], ARGV[1], best)
+  redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+return best
+`)
+
+// RedisSelectionState implements SelectionState with a counter per pool
+// (round_robin) and a sorted set of last-selected times (least_used).
+type RedisSelectionState struct {
+	Client *redis.Client
+
+	// TTL drops a pool's state after this long without calls; defaults to 7 days.
+	TTL time.Duration
+}
+
+func (r RedisSelectionState) ttl() time.Duration {
+	if r.TTL > 0 {
+		return r.TTL
+	}
+	return 7 * 24 * time.Hour
+}
+
+func (r RedisSelectionState) Rotate(ctx context.Context, pool string, peek bool) (int64, error) {
+	if r.Client == nil {
+		return 0, errors.New("routing: redis client is nil")
+	}
+	key := pool + ":rr"
+	if peek {
+		v, err := r.Client.Get(ctx, key).Int64()
+		if errors.Is(err, redis.Nil) {
+			return 1, nil
+		}
+		return v + 1, err
+	}
+	pipe := r.Client.TxPipeline()
+	n := pipe.Incr(ctx, key)
+	pipe.PExpire(ctx, key, r.ttl())
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return n.Val(), nil
+}
+
+func (r RedisSelectionState) LeastRecent(ctx context.Context, pool string, members []string, now time.Time, peek bool) (string, error) {
+	if r.Client == nil {
+		return "", errors.New("routing: redis client is nil")
+	}
+	if len(members) == 0 {
+		return "", errors.New("routing: no members to select from")
+	}
+	peekArg := 0
+	if peek {
+		peekArg = 1
+	}
+	args := []any{now.UnixMilli(), peekArg, r.ttl().Milliseconds()}
+	for _, m := range members {
+		args = append(args, m)
+	}
+	return leastRecentScript.Run(ctx, r.Client, []string{pool + ":lru"}, args...).Text()
+}
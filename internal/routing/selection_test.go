@@ -0,0 +1,81 @@
+package routing
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/telephony"
+)
+
+func TestSelection_RoundRobinRotatesAcrossCalls(t *testing.T) {
+	ctx := context.Background()
+	campaign := stubCampaigns{ev: CampaignEvaluation{Allowed: true, Strategy: StrategyRoundRobin, Destinations: []WeightedDestination{
+		{TargetURI: "+15550001", Weight: 9},
+		{TargetURI: "+15550002", Weight: 1},
+		{TargetURI: "+15550003", Weight: 0}, // ineligible
+		{TargetURI: "+15550004", Weight: 1},
+	}}}
+	e := NewRoutingEngine(nil, campaign, rand.New(rand.NewSource(1)))
+	e.Selection = NewMemorySelectionState()
+	in := RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: "w", ProviderCallID: "p", From: "+1", To: "+2"}}
+
+	// A dry run reports the next target without advancing the rotation.
+	dr, err := e.DryRun(ctx, in, time.Time{})
+	if err != nil || dr.Decision.ConnectTo != "+15550001" {
+		t.Fatalf("dry run: %+v err=%v", dr.Decision, err)
+	}
+
+	want := []string{"+15550001", "+15550002", "+15550004", "+15550001"}
+	for i, w := range want {
+		d, err := e.Route(ctx, in)
+		if err != nil || d.ConnectTo != w {
+			t.Fatalf("call %d: expected %s, got %+v err=%v", i, w, d, err)
+		}
+	}
+}
+
+func TestSelection_LeastUsedPrefersStalestTarget(t *testing.T) {
+	ctx := context.Background()
+	campaign := stubCampaigns{ev: CampaignEvaluation{Allowed: true, Strategy: StrategyLeastUsed, Destinations: []WeightedDestination{
+		{TargetURI: "+15550001", Weight: 1},
+		{TargetURI: "+15550002", Weight: 1},
+	}}}
+	e := NewRoutingEngine(nil, campaign, rand.New(rand.NewSource(1)))
+	state := NewMemorySelectionState()
+	e.Selection = state
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	e.Now = func() time.Time { return now }
+	in := RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: "w", ProviderCallID: "p", From: "+1", To: "+2"}}
+
+	// +15550002 was used recently by another instance; +15550001 never.
+	if _, err := state.LeastRecent(ctx, selectionPool("w", "c"), []string{"+15550002"}, now.Add(-time.Minute), false); err != nil {
+		t.Fatal(err)
+	}
+	for i, w := range []string{"+15550001", "+15550002", "+15550001"} {
+		now = now.Add(time.Second)
+		d, err := e.Route(ctx, in)
+		if err != nil || d.ConnectTo != w {
+			t.Fatalf("call %d: expected %s, got %+v err=%v", i, w, d, err)
+		}
+	}
+}
+
+func TestSelection_FallsBackToWeightedWithoutState(t *testing.T) {
+	campaign := stubCampaigns{ev: CampaignEvaluation{Allowed: true, Strategy: StrategyRoundRobin, Destinations: []WeightedDestination{{TargetURI: "+15550001", Weight: 1}}}}
+	e := NewRoutingEngine(nil, campaign, rand.New(rand.NewSource(1)))
+	in := RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: "w", ProviderCallID: "p", From: "+1", To: "+2"}}
+
+	dr, err := e.DryRun(context.Background(), in, time.Time{})
+	if err != nil || dr.Decision.ConnectTo != "+15550001" {
+		t.Fatalf("expected weighted fallback, got %+v err=%v", dr.Decision, err)
+	}
+	var fellBack bool
+	for _, s := range dr.Trace {
+		fellBack = fellBack || (s.Step == "destination" && s.Outcome == "fallback")
+	}
+	if !fellBack {
+		t.Fatalf("expected fallback trace step: %+v", dr.Trace)
+	}
+}
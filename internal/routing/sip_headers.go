@@ -0,0 +1,87 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"telecom-platform/internal/telephony"
+)
+
+// Custom SIP headers.
+//
+// A campaign can send call context to the PBX it connects to as custom SIP
+// headers (CampaignEvaluation.SIPHeaders), e.g. X-Campaign-ID: {campaign_id}.
+// Values may use these placeholders:
+//
+//	{workspace_id} {campaign_id} {provider_call_id} {from} {to} {language}
+//
+// PassthroughHeaders copy headers the inbound call arrived with (e.g. a lead ID
+// set by the upstream dialer); a configured header of the same name wins. Headers
+// are only sent to SIP targets (see telephony.RenderTwiML). Invalid names are
+// dropped, and headers past telephony.MaxSIPHeaderBytes are dropped in name
+// order, each with a trace step.
+
+// ValidateSIPHeaders checks a campaign's custom header configuration.
+func ValidateSIPHeaders(headers map[string]string) error {
+	for name := range headers {
+		if !telephony.ValidSIPHeaderName(name) {
+			return fmt.Errorf("routing: sip header %q must start with X- and contain only letters, digits and dashes", name)
+		}
+	}
+	if n := telephony.SIPHeadersSize(headers); n > telephony.MaxSIPHeaderBytes {
+		return fmt.Errorf("routing: sip headers are %d bytes, limit is %d", n, telephony.MaxSIPHeaderBytes)
+	}
+	return nil
+}
+
+// sipHeaders builds the headers for a connect decision.
+func sipHeaders(ctx context.Context, in RouteInput, ev CampaignEvaluation, language string) map[string]string {
+	if len(ev.SIPHeaders) == 0 && len(ev.PassthroughHeaders) == 0 {
+		return nil
+	}
+	out := map[string]string{}
+	for _, name := range ev.PassthroughHeaders {
+		for k, v := range in.Inbound.SIPHeaders {
+			if strings.EqualFold(k, name) {
+				out[name] = v
+			}
+		}
+	}
+	r := strings.NewReplacer(
+		"{workspace_id}", in.WorkspaceID,
+		"{campaign_id}", in.CampaignID,
+		"{provider_call_id}", in.Inbound.ProviderCallID,
+		"{from}", in.Inbound.From,
+		"{to}", in.Inbound.To,
+		"{language}", language,
+	)
+	for name, tmpl := range ev.SIPHeaders {
+		out[name] = r.Replace(tmpl)
+	}
+
+	names := make([]string, 0, len(out))
+	for name := range out {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	headers, size := map[string]string{}, 0
+	for _, name := range names {
+		if !telephony.ValidSIPHeaderName(name) {
+			traceStep(ctx, "sip_headers", "skip", "invalid header name", map[string]any{"header": name})
+			continue
+		}
+		n := telephony.SIPHeadersSize(map[string]string{name: out[name]})
+		if size+n > telephony.MaxSIPHeaderBytes {
+			traceStep(ctx, "sip_headers", "skip", "header size limit", map[string]any{"header": name})
+			continue
+		}
+		headers[name], size = out[name], size+n
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+	traceStep(ctx, "sip_headers", "applied", "", map[string]any{"headers": headers})
+	return headers
+}
@@ -0,0 +1,44 @@
+package routing
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"telecom-platform/internal/telephony"
+)
+
+func TestRoute_SIPHeaders(t *testing.T) {
+	ev := CampaignEvaluation{
+		Allowed:            true,
+		Destinations:       []WeightedDestination{{TargetURI: "sip:agent@pbx.example.com", Weight: 1}},
+		SIPHeaders:         map[string]string{"X-Campaign-ID": "{campaign_id}", "X-Caller": "{from}", "Bad Header": "x"},
+		PassthroughHeaders: []string{"X-Lead-ID", "X-Missing"},
+	}
+	e := NewRoutingEngine(nil, stubCampaigns{ev: ev}, rand.New(rand.NewSource(1)))
+	in := RouteInput{WorkspaceID: "w", CampaignID: "c1", Inbound: telephony.InboundCallRequest{
+		WorkspaceID: "w", ProviderCallID: "p", From: "+15551230000", To: "+15550000000",
+		SIPHeaders: map[string]string{"x-lead-id": "L42", "X-Other": "drop"},
+	}}
+	d, err := e.Route(context.Background(), in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"X-Campaign-ID": "c1", "X-Caller": "+15551230000", "X-Lead-ID": "L42"}
+	if len(d.SIPHeaders) != len(want) {
+		t.Fatalf("expected %v, got %v", want, d.SIPHeaders)
+	}
+	for k, v := range want {
+		if d.SIPHeaders[k] != v {
+			t.Fatalf("expected %v, got %v", want, d.SIPHeaders)
+		}
+	}
+
+	if err := ValidateSIPHeaders(map[string]string{"X-Note": strings.Repeat("a", telephony.MaxSIPHeaderBytes)}); err == nil {
+		t.Fatalf("expected size limit error")
+	}
+	if err := ValidateSIPHeaders(map[string]string{"Contact": "x"}); err == nil {
+		t.Fatalf("expected name error")
+	}
+}
@@ -0,0 +1,25 @@
+package routing
+
+import "errors"
+
+// Optimistic concurrency.
+//
+// Operator-edited configs (destination groups, number forwarding) carry a Version
+// that starts at 1 and increments on every write. Writers pass the version they
+// read; a mismatch means someone else saved in between and returns
+// ErrVersionConflict instead of silently overwriting their change. Zero skips the
+// check for callers that want last-write-wins.
+//
+// Stores enforce the same rule atomically: a Put must only succeed when the stored
+// version is Version-1 (a missing row counts as 0), e.g.
+// UPDATE ... SET version = $n WHERE id = $1 AND version = $n - 1.
+
+var ErrVersionConflict = errors.New("routing: config was modified concurrently")
+
+// nextVersion checks want against the stored version and returns the version to write.
+func nextVersion(stored, want int64) (int64, error) {
+	if want != 0 && want != stored {
+		return 0, ErrVersionConflict
+	}
+	return stored + 1, nil
+}
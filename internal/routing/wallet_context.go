@@ -0,0 +1,115 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"telecom-platform/internal/pricing"
+	"telecom-platform/internal/telephony"
+)
+
+// Wallet auto-selection.
+//
+// WalletContextResolver implements AdapterOptions.WalletContextResolver: it picks the
+// campaign's wallet override, falling back to the workspace default wallet, and
+// derives the estimated charge from the workspace's inbound rate for the dialed
+// number. Calls without a wallet or a rate carry no estimate, so the engine skips
+// the balance check for them.
+
+// DefaultEstimatedSeconds is the call length priced for the pre-connect balance check.
+const DefaultEstimatedSeconds = 60
+
+// WalletAssignmentStore resolves which wallet pays for a workspace's calls.
+type WalletAssignmentStore interface {
+	// DefaultWallet returns ok=false when the workspace has no default wallet.
+	DefaultWallet(ctx context.Context, workspaceID string) (walletID string, ok bool, err error)
+	// CampaignWallet returns ok=false when the campaign uses the workspace default.
+	CampaignWallet(ctx context.Context, workspaceID, campaignID string) (walletID string, ok bool, err error)
+}
+
+// RateQuoter resolves the effective per-minute rate (implemented by pricing.Service).
+type RateQuoter interface {
+	QuoteCallRate(ctx context.Context, workspaceID string, direction pricing.CallDirection, destination string, at time.Time) (pricing.RateQuote, error)
+}
+
+type WalletContextResolver struct {
+	Wallets WalletAssignmentStore
+	Rates   RateQuoter
+
+	// Destination maps the inbound request to a pricing destination; defaults to the dialed number.
+	Destination func(req telephony.InboundCallRequest) string
+	// EstimatedSeconds is the call length to price; defaults to DefaultEstimatedSeconds.
+	EstimatedSeconds int
+	Now              func() time.Time
+}
+
+// Resolve returns the paying wallet and estimated charge for req. The campaign
+// resolved earlier by the adapter is read from ctx.
+func (r *WalletContextResolver) Resolve(ctx context.Context, req telephony.InboundCallRequest) (walletID string, estMinor int64, currency string, err error) {
+	if req.WorkspaceID == "" {
+		return "", 0, "", errors.New("routing: workspace_id required")
+	}
+	if r.Wallets == nil {
+		return "", 0, "", nil
+	}
+	if campaignID := CampaignIDFromContext(ctx); campaignID != "" {
+		id, ok, err := r.Wallets.CampaignWallet(ctx, req.WorkspaceID, campaignID)
+		if err != nil {
+			return "", 0, "", err
+		}
+		if ok {
+			walletID = id
+		}
+	}
+	if walletID == "" {
+		id, ok, err := r.Wallets.DefaultWallet(ctx, req.WorkspaceID)
+		if err != nil {
+			return "", 0, "", err
+		}
+		if !ok {
+			return "", 0, "", nil
+		}
+		walletID = id
+	}
+
+	if r.Rates == nil {
+		return walletID, 0, "", nil
+	}
+	dest := req.To
+	if r.Destination != nil {
+		dest = r.Destination(req)
+	}
+	if dest == "" {
+		return walletID, 0, "", nil
+	}
+	at := req.OccurredAt
+	if at.IsZero() && r.Now != nil {
+		at = r.Now()
+	}
+	q, err := r.Rates.QuoteCallRate(ctx, req.WorkspaceID, pricing.CallDirectionInbound, dest, at)
+	if errors.Is(err, pricing.ErrPricingNotFound) {
+		return walletID, 0, "", nil
+	}
+	if err != nil {
+		return "", 0, "", err
+	}
+	secs := r.EstimatedSeconds
+	if secs <= 0 {
+		secs = DefaultEstimatedSeconds
+	}
+	return walletID, q.CostMinor(secs), q.Currency, nil
+}
+
+type campaignIDKey struct{}
+
+// WithCampaignID records the resolved campaign for later adapter resolvers.
+func WithCampaignID(ctx context.Context, campaignID string) context.Context {
+	return context.WithValue(ctx, campaignIDKey{}, campaignID)
+}
+
+// CampaignIDFromContext returns the campaign resolved for the current inbound call.
+func CampaignIDFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(campaignIDKey{}).(string)
+	return v
+}
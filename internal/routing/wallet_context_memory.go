@@ -0,0 +1,45 @@
+package routing
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryWalletAssignmentStore is a simple in-memory WalletAssignmentStore useful for tests.
+// It is not intended for production use.
+
+type MemoryWalletAssignmentStore struct {
+	mu        sync.Mutex
+	defaults  map[string]string
+	campaigns map[string]string
+}
+
+func NewMemoryWalletAssignmentStore() *MemoryWalletAssignmentStore {
+	return &MemoryWalletAssignmentStore{defaults: map[string]string{}, campaigns: map[string]string{}}
+}
+
+func (s *MemoryWalletAssignmentStore) SetDefaultWallet(workspaceID, walletID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.defaults[workspaceID] = walletID
+}
+
+func (s *MemoryWalletAssignmentStore) SetCampaignWallet(workspaceID, campaignID, walletID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.campaigns[workspaceID+"|"+campaignID] = walletID
+}
+
+func (s *MemoryWalletAssignmentStore) DefaultWallet(ctx context.Context, workspaceID string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.defaults[workspaceID]
+	return id, ok, nil
+}
+
+func (s *MemoryWalletAssignmentStore) CampaignWallet(ctx context.Context, workspaceID, campaignID string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.campaigns[workspaceID+"|"+campaignID]
+	return id, ok, nil
+}
@@ -0,0 +1,45 @@
+package routing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/pricing"
+	"telecom-platform/internal/telephony"
+)
+
+func TestWalletContextResolver_CampaignOverrideAndEstimate(t *testing.T) {
+	wallets := NewMemoryWalletAssignmentStore()
+	wallets.SetDefaultWallet("w", "wal-default")
+	wallets.SetCampaignWallet("w", "c-vip", "wal-vip")
+
+	at := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	rates := pricing.NewService(&pricing.MemoryRepo{Minute: []pricing.MinutePricing{{
+		ID: "r1", WorkspaceID: "w", Direction: pricing.CallDirectionInbound, Destination: "+14155550100",
+		Currency: "USD", RatePerMinuteMinor: 3, BillingIncrementSeconds: 60,
+		EffectiveFrom: at.Add(-time.Hour), Status: pricing.PricingStatusActive,
+	}}})
+	r := &WalletContextResolver{Wallets: wallets, Rates: rates, EstimatedSeconds: 120}
+	req := telephony.InboundCallRequest{WorkspaceID: "w", To: "+14155550100", OccurredAt: at}
+
+	id, est, cur, err := r.Resolve(WithCampaignID(context.Background(), "c-vip"), req)
+	if err != nil || id != "wal-vip" || est != 6 || cur != "USD" {
+		t.Fatalf("expected campaign wallet with estimate, got %s %d %s err=%v", id, est, cur, err)
+	}
+	id, _, _, err = r.Resolve(WithCampaignID(context.Background(), "c-other"), req)
+	if err != nil || id != "wal-default" {
+		t.Fatalf("expected default wallet, got %s err=%v", id, err)
+	}
+
+	req.To = "+442079460000"
+	id, est, _, err = r.Resolve(context.Background(), req)
+	if err != nil || id != "wal-default" || est != 0 {
+		t.Fatalf("expected no estimate without a rate, got %s %d err=%v", id, est, err)
+	}
+
+	id, est, _, err = r.Resolve(context.Background(), telephony.InboundCallRequest{WorkspaceID: "no-default", To: "+14155550100"})
+	if err != nil || id != "" || est != 0 {
+		t.Fatalf("expected no wallet, got %s %d err=%v", id, est, err)
+	}
+}
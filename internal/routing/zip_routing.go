@@ -0,0 +1,150 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ZIP code collection.
+//
+// Campaigns with a ZipCollectionConfig prompt the caller for their 5-digit ZIP code
+// before routing. The answer is matched against the campaign's ZipRoutes by longest
+// prefix ("9" < "94" < "94107") and the call is sent to the matched destination group.
+// Invalid or missing input goes to FallbackGroupID, or to the campaign's regular
+// destinations when no fallback is set.
+
+const (
+	GatherStepZip = "zip"
+
+	zipDigits         = 5
+	defaultZipPrompt  = "Please enter your five digit ZIP code."
+	defaultZipTimeout = 6
+)
+
+var ErrInvalidZipRoute = errors.New("routing: invalid zip route")
+
+// ZipCollectionConfig enables ZIP collection for a campaign.
+type ZipCollectionConfig struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	CampaignID  string `json:"campaign_id" db:"campaign_id"`
+
+	// Prompt is read to the caller; empty uses a default English prompt.
+	Prompt         string `json:"prompt,omitempty" db:"prompt"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" db:"timeout_seconds"`
+
+	// LocalizedPrompts override Prompt for callers who selected a language
+	// (key: BCP 47 tag or primary subtag, e.g. "es").
+	LocalizedPrompts map[string]string `json:"localized_prompts,omitempty" db:"localized_prompts"`
+
+	// FallbackGroupID receives callers whose ZIP is missing or unmapped (optional).
+	FallbackGroupID string `json:"fallback_group_id,omitempty" db:"fallback_group_id"`
+}
+
+// ZipRoute maps a ZIP prefix (1-5 digits) to a destination group.
+type ZipRoute struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	CampaignID  string `json:"campaign_id" db:"campaign_id"`
+	Prefix      string `json:"prefix" db:"prefix"`
+	GroupID     string `json:"group_id" db:"group_id"`
+}
+
+// Validate checks the route shape.
+func (r ZipRoute) Validate() error {
+	if r.WorkspaceID == "" || r.CampaignID == "" || r.GroupID == "" {
+		return ErrInvalidZipRoute
+	}
+	if len(r.Prefix) == 0 || len(r.Prefix) > zipDigits || !allDigits(r.Prefix) {
+		return ErrInvalidZipRoute
+	}
+	return nil
+}
+
+// ZipRouteStore loads ZIP collection config and mappings.
+// Implementations must enforce workspace filtering.
+type ZipRouteStore interface {
+	GetZipCollection(ctx context.Context, workspaceID, campaignID string) (ZipCollectionConfig, bool, error)
+	ListZipRoutes(ctx context.Context, workspaceID, campaignID string) ([]ZipRoute, error)
+}
+
+// ZipRouter applies ZIP collection during routing.
+type ZipRouter struct {
+	Store ZipRouteStore
+}
+
+// ZipResolution is the outcome of the ZIP step: either a prompt to play, or the
+// destinations to use (empty means keep the campaign's own destinations).
+type ZipResolution struct {
+	Gather       *GatherPrompt
+	Destinations []WeightedDestination
+	MatchedZip   string
+}
+
+// Resolve asks for the ZIP on first contact and maps the answer on the callback.
+// language (optional) selects a localized prompt and TTS voice.
+func (z *ZipRouter) Resolve(ctx context.Context, workspaceID, campaignID, language string, collected map[string]string) (ZipResolution, error) {
+	if z == nil || z.Store == nil || campaignID == "" {
+		return ZipResolution{}, nil
+	}
+	cfg, ok, err := z.Store.GetZipCollection(ctx, workspaceID, campaignID)
+	if err != nil || !ok {
+		return ZipResolution{}, err
+	}
+
+	zip, asked := collected[GatherStepZip]
+	if !asked {
+		prompt := cfg.Prompt
+		if p, ok := cfg.LocalizedPrompts[language]; ok && language != "" {
+			prompt = p
+		} else if p, ok := cfg.LocalizedPrompts[primaryLanguage(language)]; ok && language != "" {
+			prompt = p
+		}
+		if prompt == "" {
+			prompt = defaultZipPrompt
+		}
+		timeout := cfg.TimeoutSeconds
+		if timeout <= 0 {
+			timeout = defaultZipTimeout
+		}
+		return ZipResolution{Gather: &GatherPrompt{Step: GatherStepZip, Prompt: prompt, Language: language, NumDigits: zipDigits, TimeoutSeconds: timeout}}, nil
+	}
+
+	zip = strings.TrimSpace(zip)
+	if len(zip) == zipDigits && allDigits(zip) {
+		routes, err := z.Store.ListZipRoutes(ctx, workspaceID, campaignID)
+		if err != nil {
+			return ZipResolution{}, err
+		}
+		if r, ok := matchZipRoute(routes, zip); ok {
+			return ZipResolution{Destinations: []WeightedDestination{{GroupID: r.GroupID, Weight: 1}}, MatchedZip: zip}, nil
+		}
+	}
+	if cfg.FallbackGroupID != "" {
+		return ZipResolution{Destinations: []WeightedDestination{{GroupID: cfg.FallbackGroupID, Weight: 1}}}, nil
+	}
+	return ZipResolution{}, nil
+}
+
+// matchZipRoute returns the route with the longest prefix of zip.
+func matchZipRoute(routes []ZipRoute, zip string) (ZipRoute, bool) {
+	var best ZipRoute
+	found := false
+	for _, r := range routes {
+		if !strings.HasPrefix(zip, r.Prefix) {
+			continue
+		}
+		if !found || len(r.Prefix) > len(best.Prefix) {
+			best, found = r, true
+		}
+	}
+	return best, found
+}
+
+func allDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
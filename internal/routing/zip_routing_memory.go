@@ -0,0 +1,56 @@
+package routing
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryZipRouteStore is a simple in-memory ZipRouteStore useful for tests.
+// It is not intended for production use.
+
+type MemoryZipRouteStore struct {
+	mu      sync.Mutex
+	configs map[string]ZipCollectionConfig
+	routes  map[string][]ZipRoute
+}
+
+func NewMemoryZipRouteStore() *MemoryZipRouteStore {
+	return &MemoryZipRouteStore{configs: map[string]ZipCollectionConfig{}, routes: map[string][]ZipRoute{}}
+}
+
+func (s *MemoryZipRouteStore) SetZipCollection(cfg ZipCollectionConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configs[cfg.WorkspaceID+"|"+cfg.CampaignID] = cfg
+}
+
+// AddZipRoute stores r after validation; an existing route with the same prefix is replaced.
+func (s *MemoryZipRouteStore) AddZipRoute(r ZipRoute) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := r.WorkspaceID + "|" + r.CampaignID
+	for i, existing := range s.routes[key] {
+		if existing.Prefix == r.Prefix {
+			s.routes[key][i] = r
+			return nil
+		}
+	}
+	s.routes[key] = append(s.routes[key], r)
+	return nil
+}
+
+func (s *MemoryZipRouteStore) GetZipCollection(ctx context.Context, workspaceID, campaignID string) (ZipCollectionConfig, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg, ok := s.configs[workspaceID+"|"+campaignID]
+	return cfg, ok, nil
+}
+
+func (s *MemoryZipRouteStore) ListZipRoutes(ctx context.Context, workspaceID, campaignID string) ([]ZipRoute, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ZipRoute(nil), s.routes[workspaceID+"|"+campaignID]...), nil
+}
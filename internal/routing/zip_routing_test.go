@@ -0,0 +1,63 @@
+package routing
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"telecom-platform/internal/telephony"
+)
+
+func TestZipRouting_PromptsThenRoutesByLongestPrefix(t *testing.T) {
+	ctx := context.Background()
+	groups := NewMemoryDestinationGroupStore()
+	_ = groups.PutGroup(ctx, DestinationGroup{ID: "west", WorkspaceID: "w", Name: "west", Version: 1, Members: []WeightedDestination{{TargetURI: "+15550009", Weight: 1}}})
+	_ = groups.PutGroup(ctx, DestinationGroup{ID: "sf", WorkspaceID: "w", Name: "sf", Version: 1, Members: []WeightedDestination{{TargetURI: "+15550941", Weight: 1}}})
+	_ = groups.PutGroup(ctx, DestinationGroup{ID: "other", WorkspaceID: "w", Name: "other", Version: 1, Members: []WeightedDestination{{TargetURI: "+15550000", Weight: 1}}})
+
+	zips := NewMemoryZipRouteStore()
+	zips.SetZipCollection(ZipCollectionConfig{WorkspaceID: "w", CampaignID: "c", FallbackGroupID: "other"})
+	for _, r := range []ZipRoute{{Prefix: "9", GroupID: "west"}, {Prefix: "941", GroupID: "sf"}} {
+		r.WorkspaceID, r.CampaignID = "w", "c"
+		if err := zips.AddZipRoute(r); err != nil {
+			t.Fatalf("add route: %v", err)
+		}
+	}
+
+	e := NewRoutingEngine(nil, stubCampaigns{ev: CampaignEvaluation{Allowed: true, Destinations: []WeightedDestination{{TargetURI: "+15551111", Weight: 1}}}}, rand.New(rand.NewSource(1)))
+	e.Groups = groups
+	e.Zip = &ZipRouter{Store: zips}
+
+	route := func(collected map[string]string) Decision {
+		t.Helper()
+		d, err := e.Route(ctx, RouteInput{WorkspaceID: "w", CampaignID: "c", Inbound: telephony.InboundCallRequest{WorkspaceID: "w", Collected: collected}})
+		if err != nil {
+			t.Fatalf("route: %v", err)
+		}
+		return d
+	}
+
+	if d := route(nil); d.Action != ActionGather || d.Gather == nil || d.Gather.Step != GatherStepZip || d.Gather.NumDigits != 5 {
+		t.Fatalf("expected zip prompt, got %+v", d)
+	}
+	if d := route(map[string]string{"zip": "94107"}); d.ConnectTo != "+15550941" {
+		t.Fatalf("expected longest prefix match, got %+v", d)
+	}
+	if d := route(map[string]string{"zip": "90210"}); d.ConnectTo != "+15550009" {
+		t.Fatalf("expected short prefix match, got %+v", d)
+	}
+	if d := route(map[string]string{"zip": "10001"}); d.ConnectTo != "+15550000" {
+		t.Fatalf("expected fallback group, got %+v", d)
+	}
+	if d := route(map[string]string{"zip": ""}); d.ConnectTo != "+15550000" {
+		t.Fatalf("expected fallback on no input, got %+v", d)
+	}
+}
+
+func TestZipRoute_Validate(t *testing.T) {
+	for _, p := range []string{"", "123456", "9a"} {
+		if err := (ZipRoute{WorkspaceID: "w", CampaignID: "c", GroupID: "g", Prefix: p}).Validate(); err == nil {
+			t.Fatalf("expected prefix %q to be rejected", p)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package scim
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"telecom-platform/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler exposes the SCIM 2.0 Users/Groups endpoints under /scim/v2.
+//
+// SCIM is a wire protocol with its own error format and content type, so the
+// handlers live next to the service instead of in internal/httpapi.
+//
+// Tenant scoping:
+// - IdPs authenticate with a per-workspace bearer token (see RequireToken).
+// - The workspace never comes from the request body.
+type Handler struct {
+	Service *Service
+}
+
+// TokenStore resolves a SCIM bearer token (stored as a sha256 hex hash) to a workspace.
+type TokenStore interface {
+	WorkspaceForToken(ctx context.Context, tokenHash string) (string, bool, error)
+}
+
+const (
+	ctxWorkspaceID = "scim_workspace_id"
+	contentType    = "application/scim+json"
+)
+
+// HashToken returns the storage form of a SCIM bearer token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RequireToken authenticates the IdP and pins the workspace for the request.
+func RequireToken(store TokenStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h := c.GetHeader("Authorization")
+		if store == nil || !strings.HasPrefix(h, "Bearer ") {
+			writeError(c, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+		workspaceID, ok, err := store.WorkspaceForToken(c.Request.Context(), HashToken(strings.TrimPrefix(h, "Bearer ")))
+		if err != nil {
+			logger.FromGin(c).Error("scim token lookup failed", "err", err)
+			writeError(c, http.StatusInternalServerError, "internal error")
+			return
+		}
+		if !ok || workspaceID == "" {
+			writeError(c, http.StatusUnauthorized, "invalid bearer token")
+			return
+		}
+		c.Set(ctxWorkspaceID, workspaceID)
+		c.Next()
+	}
+}
+
+// Register mounts the SCIM endpoints on the given group.
+func (h Handler) Register(g *gin.RouterGroup) {
+	g.GET("/Users", h.ListUsers)
+	g.POST("/Users", h.CreateUser)
+	g.GET("/Users/:id", h.GetUser)
+	g.PUT("/Users/:id", h.ReplaceUser)
+	g.PATCH("/Users/:id", h.PatchUser)
+	g.DELETE("/Users/:id", h.DeleteUser)
+
+	g.GET("/Groups", h.ListGroups)
+	g.POST("/Groups", h.CreateGroup)
+	g.GET("/Groups/:id", h.GetGroup)
+	g.PATCH("/Groups/:id", h.PatchGroup)
+	g.DELETE("/Groups/:id", h.DeleteGroup)
+}
+
+type userResource struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	UserName    string      `json:"userName"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Active      *bool       `json:"active,omitempty"`
+	Emails      []emailAttr `json:"emails,omitempty"`
+	Meta        *meta       `json:"meta,omitempty"`
+}
+
+type emailAttr struct {
+	Value   string `json:"value"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type memberRef struct {
+	Value string `json:"value"`
+}
+
+type groupResource struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	DisplayName string      `json:"displayName"`
+	Members     []memberRef `json:"members,omitempty"`
+	Meta        *meta       `json:"meta,omitempty"`
+}
+
+type meta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created"`
+	LastModified string `json:"lastModified"`
+}
+
+type patchRequest struct {
+	Operations []PatchOperation `json:"Operations"`
+}
+
+func (h Handler) CreateUser(c *gin.Context) {
+	var in userResource
+	if err := c.ShouldBindJSON(&in); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid json")
+		return
+	}
+	u, err := h.Service.CreateUser(c.Request.Context(), workspaceID(c), userFromResource(in))
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	writeJSON(c, http.StatusCreated, toUserResource(u))
+}
+
+func (h Handler) GetUser(c *gin.Context) {
+	u, err := h.Service.GetUser(c.Request.Context(), workspaceID(c), c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	writeJSON(c, http.StatusOK, toUserResource(u))
+}
+
+func (h Handler) ListUsers(c *gin.Context) {
+	users, err := h.Service.ListUsers(c.Request.Context(), workspaceID(c), c.Query("filter"))
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	out := make([]any, 0, len(users))
+	for _, u := range users {
+		out = append(out, toUserResource(u))
+	}
+	writeList(c, out)
+}
+
+func (h Handler) ReplaceUser(c *gin.Context) {
+	var in userResource
+	if err := c.ShouldBindJSON(&in); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid json")
+		return
+	}
+	u, err := h.Service.ReplaceUser(c.Request.Context(), workspaceID(c), c.Param("id"), userFromResource(in))
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	writeJSON(c, http.StatusOK, toUserResource(u))
+}
+
+func (h Handler) PatchUser(c *gin.Context) {
+	var in patchRequest
+	if err := c.ShouldBindJSON(&in); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid json")
+		return
+	}
+	u, err := h.Service.PatchUser(c.Request.Context(), workspaceID(c), c.Param("id"), in.Operations)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	writeJSON(c, http.StatusOK, toUserResource(u))
+}
+
+func (h Handler) DeleteUser(c *gin.Context) {
+	if err := h.Service.DeleteUser(c.Request.Context(), workspaceID(c), c.Param("id")); err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h Handler) CreateGroup(c *gin.Context) {
+	var in groupResource
+	if err := c.ShouldBindJSON(&in); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid json")
+		return
+	}
+	g := Group{ExternalID: in.ExternalID, DisplayName: in.DisplayName}
+	for _, m := range in.Members {
+		g.MemberIDs = append(g.MemberIDs, m.Value)
+	}
+	g, err := h.Service.CreateGroup(c.Request.Context(), workspaceID(c), g)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	writeJSON(c, http.StatusCreated, toGroupResource(g))
+}
+
+func (h Handler) GetGroup(c *gin.Context) {
+	g, err := h.Service.GetGroup(c.Request.Context(), workspaceID(c), c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	writeJSON(c, http.StatusOK, toGroupResource(g))
+}
+
+func (h Handler) ListGroups(c *gin.Context) {
+	groups, err := h.Service.ListGroups(c.Request.Context(), workspaceID(c))
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	out := make([]any, 0, len(groups))
+	for _, g := range groups {
+		out = append(out, toGroupResource(g))
+	}
+	writeList(c, out)
+}
+
+func (h Handler) PatchGroup(c *gin.Context) {
+	var in patchRequest
+	if err := c.ShouldBindJSON(&in); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid json")
+		return
+	}
+	g, err := h.Service.PatchGroup(c.Request.Context(), workspaceID(c), c.Param("id"), in.Operations)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	writeJSON(c, http.StatusOK, toGroupResource(g))
+}
+
+func (h Handler) DeleteGroup(c *gin.Context) {
+	if err := h.Service.DeleteGroup(c.Request.Context(), workspaceID(c), c.Param("id")); err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func workspaceID(c *gin.Context) string {
+	return c.GetString(ctxWorkspaceID)
+}
+
+func userFromResource(in userResource) User {
+	u := User{ExternalID: in.ExternalID, UserName: in.UserName, DisplayName: in.DisplayName, Active: true}
+	if in.Active != nil {
+		u.Active = *in.Active
+	}
+	for _, e := range in.Emails {
+		if e.Primary || u.Email == "" {
+			u.Email = e.Value
+		}
+	}
+	return u
+}
+
+func toUserResource(u User) userResource {
+	active := u.Active
+	out := userResource{
+		Schemas:     []string{SchemaUser},
+		ID:          u.ID,
+		ExternalID:  u.ExternalID,
+		UserName:    u.UserName,
+		DisplayName: u.DisplayName,
+		Active:      &active,
+		Meta:        &meta{ResourceType: "User", Created: u.CreatedAt.Format("2006-01-02T15:04:05Z"), LastModified: u.UpdatedAt.Format("2006-01-02T15:04:05Z")},
+	}
+	if u.Email != "" {
+		out.Emails = []emailAttr{{Value: u.Email, Primary: true}}
+	}
+	return out
+}
+
+func toGroupResource(g Group) groupResource {
+	out := groupResource{
+		Schemas:     []string{SchemaGroup},
+		ID:          g.ID,
+		ExternalID:  g.ExternalID,
+		DisplayName: g.DisplayName,
+		Meta:        &meta{ResourceType: "Group", Created: g.CreatedAt.Format("2006-01-02T15:04:05Z"), LastModified: g.UpdatedAt.Format("2006-01-02T15:04:05Z")},
+	}
+	for _, id := range g.MemberIDs {
+		out.Members = append(out.Members, memberRef{Value: id})
+	}
+	return out
+}
+
+func writeJSON(c *gin.Context, status int, v any) {
+	c.Header("Content-Type", contentType)
+	c.JSON(status, v)
+}
+
+func writeList(c *gin.Context, resources []any) {
+	writeJSON(c, http.StatusOK, gin.H{
+		"schemas":      []string{SchemaListResponse},
+		"totalResults": len(resources),
+		"startIndex":   1,
+		"itemsPerPage": len(resources),
+		"Resources":    resources,
+	})
+}
+
+func writeError(c *gin.Context, status int, detail string) {
+	c.Header("Content-Type", contentType)
+	c.AbortWithStatusJSON(status, gin.H{
+		"schemas": []string{SchemaError},
+		"status":  strconv.Itoa(status),
+		"detail":  detail,
+	})
+}
+
+func writeServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidArgument):
+		writeError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrNotFound):
+		writeError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrConflict):
+		writeError(c, http.StatusConflict, err.Error())
+	default:
+		logger.FromGin(c).Error("scim request failed", "err", err)
+		writeError(c, http.StatusInternalServerError, "internal error")
+	}
+}
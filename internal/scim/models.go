@@ -0,0 +1,62 @@
+package scim
+
+import "time"
+
+// SCIM 2.0 (RFC 7643/7644) resources, limited to the subset enterprise IdPs
+// (Okta, Entra ID, OneLogin) use for workspace member provisioning.
+
+const (
+	SchemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// User is a provisioned workspace member.
+// Multi-tenant invariant: workspace_id required; never serialized to the IdP.
+type User struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"-" db:"workspace_id"`
+	ExternalID  string `json:"externalId,omitempty" db:"external_id"`
+
+	UserName    string `json:"userName" db:"user_name"`
+	DisplayName string `json:"displayName,omitempty" db:"display_name"`
+	Email       string `json:"-" db:"email"`
+	Active      bool   `json:"active" db:"active"`
+
+	// Role is derived from group mappings; it is not writable by the IdP directly.
+	Role string `json:"-" db:"role"`
+
+	CreatedAt time.Time `json:"-" db:"created_at"`
+	UpdatedAt time.Time `json:"-" db:"updated_at"`
+}
+
+// Group is an IdP group. Groups map to platform roles via RoleMapping.
+type Group struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"-" db:"workspace_id"`
+	ExternalID  string `json:"externalId,omitempty" db:"external_id"`
+
+	DisplayName string   `json:"displayName" db:"display_name"`
+	MemberIDs   []string `json:"-" db:"member_ids"`
+
+	CreatedAt time.Time `json:"-" db:"created_at"`
+	UpdatedAt time.Time `json:"-" db:"updated_at"`
+}
+
+// RoleMapping maps an IdP group display name to a platform role for a workspace.
+// Priority resolves users in several mapped groups: the highest priority wins.
+type RoleMapping struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	GroupName   string `json:"group_name" db:"group_name"`
+	Role        string `json:"role" db:"role"`
+	Priority    int    `json:"priority" db:"priority"`
+}
+
+// PatchOperation is a single SCIM PATCH operation.
+type PatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
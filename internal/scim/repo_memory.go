@@ -0,0 +1,178 @@
+package scim
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryRepo is a simple in-memory Repository and TokenStore for tests and
+// early development. It is not intended for production use.
+type MemoryRepo struct {
+	mu sync.Mutex
+
+	users    map[string]User
+	groups   map[string]Group
+	mappings []RoleMapping
+
+	// tokens maps sha256(token) hex -> workspace_id.
+	tokens map[string]string
+}
+
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{users: map[string]User{}, groups: map[string]Group{}, tokens: map[string]string{}}
+}
+
+func (r *MemoryRepo) SetRoleMapping(ctx context.Context, m RoleMapping) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, x := range r.mappings {
+		if x.WorkspaceID == m.WorkspaceID && strings.EqualFold(x.GroupName, m.GroupName) {
+			r.mappings[i] = m
+			return nil
+		}
+	}
+	r.mappings = append(r.mappings, m)
+	return nil
+}
+
+// AddToken registers a SCIM bearer token for a workspace.
+func (r *MemoryRepo) AddToken(token, workspaceID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[HashToken(token)] = workspaceID
+}
+
+func (r *MemoryRepo) WorkspaceForToken(ctx context.Context, tokenHash string) (string, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	w, ok := r.tokens[tokenHash]
+	return w, ok, nil
+}
+
+func (r *MemoryRepo) CreateUser(ctx context.Context, u User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[u.ID]; ok {
+		return ErrConflict
+	}
+	r.users[u.ID] = u
+	return nil
+}
+
+func (r *MemoryRepo) UpdateUser(ctx context.Context, u User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cur, ok := r.users[u.ID]
+	if !ok || cur.WorkspaceID != u.WorkspaceID {
+		return ErrNotFound
+	}
+	r.users[u.ID] = u
+	return nil
+}
+
+func (r *MemoryRepo) GetUser(ctx context.Context, workspaceID, id string) (User, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok || u.WorkspaceID != workspaceID {
+		return User{}, false, nil
+	}
+	return u, true, nil
+}
+
+func (r *MemoryRepo) FindUserByUserName(ctx context.Context, workspaceID, userName string) (User, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.WorkspaceID == workspaceID && strings.EqualFold(u.UserName, userName) {
+			return u, true, nil
+		}
+	}
+	return User{}, false, nil
+}
+
+func (r *MemoryRepo) ListUsers(ctx context.Context, workspaceID string) ([]User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]User, 0)
+	for _, u := range r.users {
+		if u.WorkspaceID == workspaceID {
+			out = append(out, u)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (r *MemoryRepo) CreateGroup(ctx context.Context, g Group) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.groups[g.ID]; ok {
+		return ErrConflict
+	}
+	g.MemberIDs = append([]string(nil), g.MemberIDs...)
+	r.groups[g.ID] = g
+	return nil
+}
+
+func (r *MemoryRepo) UpdateGroup(ctx context.Context, g Group) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cur, ok := r.groups[g.ID]
+	if !ok || cur.WorkspaceID != g.WorkspaceID {
+		return ErrNotFound
+	}
+	g.MemberIDs = append([]string(nil), g.MemberIDs...)
+	r.groups[g.ID] = g
+	return nil
+}
+
+func (r *MemoryRepo) DeleteGroup(ctx context.Context, workspaceID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cur, ok := r.groups[id]
+	if !ok || cur.WorkspaceID != workspaceID {
+		return ErrNotFound
+	}
+	delete(r.groups, id)
+	return nil
+}
+
+func (r *MemoryRepo) GetGroup(ctx context.Context, workspaceID, id string) (Group, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	g, ok := r.groups[id]
+	if !ok || g.WorkspaceID != workspaceID {
+		return Group{}, false, nil
+	}
+	g.MemberIDs = append([]string(nil), g.MemberIDs...)
+	return g, true, nil
+}
+
+func (r *MemoryRepo) ListGroups(ctx context.Context, workspaceID string) ([]Group, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Group, 0)
+	for _, g := range r.groups {
+		if g.WorkspaceID == workspaceID {
+			g.MemberIDs = append([]string(nil), g.MemberIDs...)
+			out = append(out, g)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (r *MemoryRepo) ListRoleMappings(ctx context.Context, workspaceID string) ([]RoleMapping, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]RoleMapping, 0)
+	for _, m := range r.mappings {
+		if m.WorkspaceID == workspaceID {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
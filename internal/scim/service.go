@@ -0,0 +1,512 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/audit"
+	"telecom-platform/internal/rbac"
+
+	"github.com/google/uuid"
+)
+
+// Service implements SCIM provisioning for one platform.
+//
+// Rules:
+// - Every call is workspace-scoped; the workspace comes from the SCIM bearer token, never the payload.
+// - Roles are derived from group membership via RoleMapping; users with no mapped group get DefaultRole.
+// - Every provisioning change is audited.
+// - Deprovisioning (active=false or DELETE) keeps the row for audit and disables the member.
+type Service struct {
+	repo  Repository
+	audit *audit.Service
+	clock func() time.Time
+
+	// DefaultRole is assigned to users without a mapped group.
+	DefaultRole string
+}
+
+// Repository persists SCIM users, groups and role mappings.
+// Implementations must enforce workspace filtering.
+type Repository interface {
+	CreateUser(ctx context.Context, u User) error
+	UpdateUser(ctx context.Context, u User) error
+	GetUser(ctx context.Context, workspaceID, id string) (User, bool, error)
+	FindUserByUserName(ctx context.Context, workspaceID, userName string) (User, bool, error)
+	ListUsers(ctx context.Context, workspaceID string) ([]User, error)
+
+	CreateGroup(ctx context.Context, g Group) error
+	UpdateGroup(ctx context.Context, g Group) error
+	DeleteGroup(ctx context.Context, workspaceID, id string) error
+	GetGroup(ctx context.Context, workspaceID, id string) (Group, bool, error)
+	ListGroups(ctx context.Context, workspaceID string) ([]Group, error)
+
+	ListRoleMappings(ctx context.Context, workspaceID string) ([]RoleMapping, error)
+	SetRoleMapping(ctx context.Context, m RoleMapping) error
+}
+
+func NewService(repo Repository, auditSvc *audit.Service, defaultRole string) *Service {
+	return &Service{repo: repo, audit: auditSvc, clock: time.Now, DefaultRole: defaultRole}
+}
+
+var (
+	ErrInvalidArgument = errors.New("scim: invalid argument")
+	ErrNotFound        = errors.New("scim: not found")
+	ErrConflict        = errors.New("scim: conflict")
+)
+
+func (s *Service) CreateUser(ctx context.Context, workspaceID string, u User) (User, error) {
+	u.UserName = strings.TrimSpace(u.UserName)
+	if workspaceID == "" || u.UserName == "" {
+		return User{}, ErrInvalidArgument
+	}
+	if _, ok, err := s.repo.FindUserByUserName(ctx, workspaceID, u.UserName); err != nil {
+		return User{}, err
+	} else if ok {
+		return User{}, ErrConflict
+	}
+
+	now := s.clock().UTC()
+	u.ID = uuid.NewString()
+	u.WorkspaceID = workspaceID
+	u.Role = s.DefaultRole
+	u.CreatedAt = now
+	u.UpdatedAt = now
+	if err := s.repo.CreateUser(ctx, u); err != nil {
+		return User{}, err
+	}
+	s.log(ctx, workspaceID, "scim user provisioned", u.ID)
+	return u, nil
+}
+
+func (s *Service) GetUser(ctx context.Context, workspaceID, id string) (User, error) {
+	u, ok, err := s.repo.GetUser(ctx, workspaceID, id)
+	if err != nil {
+		return User{}, err
+	}
+	if !ok {
+		return User{}, ErrNotFound
+	}
+	return u, nil
+}
+
+// ListUsers supports the `userName eq "..."` filter IdPs use to look up existing accounts.
+func (s *Service) ListUsers(ctx context.Context, workspaceID, filter string) ([]User, error) {
+	if filter != "" {
+		name, ok := parseUserNameFilter(filter)
+		if !ok {
+			return nil, ErrInvalidArgument
+		}
+		u, found, err := s.repo.FindUserByUserName(ctx, workspaceID, name)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return []User{}, nil
+		}
+		return []User{u}, nil
+	}
+	return s.repo.ListUsers(ctx, workspaceID)
+}
+
+// ReplaceUser implements PUT: IdP-writable fields are replaced; role is preserved.
+func (s *Service) ReplaceUser(ctx context.Context, workspaceID, id string, in User) (User, error) {
+	u, err := s.GetUser(ctx, workspaceID, id)
+	if err != nil {
+		return User{}, err
+	}
+	if strings.TrimSpace(in.UserName) == "" {
+		return User{}, ErrInvalidArgument
+	}
+	wasActive := u.Active
+	u.UserName = strings.TrimSpace(in.UserName)
+	u.DisplayName = in.DisplayName
+	u.ExternalID = in.ExternalID
+	u.Email = in.Email
+	u.Active = in.Active
+	u.UpdatedAt = s.clock().UTC()
+	if err := s.repo.UpdateUser(ctx, u); err != nil {
+		return User{}, err
+	}
+	s.log(ctx, workspaceID, activeMessage(wasActive, u.Active, "scim user replaced"), u.ID)
+	return u, nil
+}
+
+// PatchUser supports the operations IdPs send in practice: replace active/displayName/userName.
+func (s *Service) PatchUser(ctx context.Context, workspaceID, id string, ops []PatchOperation) (User, error) {
+	u, err := s.GetUser(ctx, workspaceID, id)
+	if err != nil {
+		return User{}, err
+	}
+	wasActive := u.Active
+	for _, op := range ops {
+		if !strings.EqualFold(op.Op, "replace") && !strings.EqualFold(op.Op, "add") {
+			return User{}, ErrInvalidArgument
+		}
+		// Entra ID sends path-less ops with an object value.
+		if op.Path == "" {
+			m, ok := op.Value.(map[string]any)
+			if !ok {
+				return User{}, ErrInvalidArgument
+			}
+			for k, v := range m {
+				if err := applyUserAttr(&u, k, v); err != nil {
+					return User{}, err
+				}
+			}
+			continue
+		}
+		if err := applyUserAttr(&u, op.Path, op.Value); err != nil {
+			return User{}, err
+		}
+	}
+	u.UpdatedAt = s.clock().UTC()
+	if err := s.repo.UpdateUser(ctx, u); err != nil {
+		return User{}, err
+	}
+	s.log(ctx, workspaceID, activeMessage(wasActive, u.Active, "scim user updated"), u.ID)
+	return u, nil
+}
+
+// DeleteUser deprovisions the user (soft delete) and removes group memberships.
+func (s *Service) DeleteUser(ctx context.Context, workspaceID, id string) error {
+	u, err := s.GetUser(ctx, workspaceID, id)
+	if err != nil {
+		return err
+	}
+	u.Active = false
+	u.UpdatedAt = s.clock().UTC()
+	if err := s.repo.UpdateUser(ctx, u); err != nil {
+		return err
+	}
+
+	groups, err := s.repo.ListGroups(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		if removeString(&g.MemberIDs, id) {
+			g.UpdatedAt = u.UpdatedAt
+			if err := s.repo.UpdateGroup(ctx, g); err != nil {
+				return err
+			}
+		}
+	}
+	s.log(ctx, workspaceID, "scim user deprovisioned", u.ID)
+	return nil
+}
+
+func (s *Service) CreateGroup(ctx context.Context, workspaceID string, g Group) (Group, error) {
+	g.DisplayName = strings.TrimSpace(g.DisplayName)
+	if workspaceID == "" || g.DisplayName == "" {
+		return Group{}, ErrInvalidArgument
+	}
+	now := s.clock().UTC()
+	g.ID = uuid.NewString()
+	g.WorkspaceID = workspaceID
+	g.CreatedAt = now
+	g.UpdatedAt = now
+	if err := s.repo.CreateGroup(ctx, g); err != nil {
+		return Group{}, err
+	}
+	s.log(ctx, workspaceID, "scim group created", g.ID)
+	if err := s.syncRoles(ctx, workspaceID, g.MemberIDs); err != nil {
+		return Group{}, err
+	}
+	return g, nil
+}
+
+func (s *Service) GetGroup(ctx context.Context, workspaceID, id string) (Group, error) {
+	g, ok, err := s.repo.GetGroup(ctx, workspaceID, id)
+	if err != nil {
+		return Group{}, err
+	}
+	if !ok {
+		return Group{}, ErrNotFound
+	}
+	return g, nil
+}
+
+func (s *Service) ListGroups(ctx context.Context, workspaceID string) ([]Group, error) {
+	return s.repo.ListGroups(ctx, workspaceID)
+}
+
+// PatchGroup supports add/remove of members and displayName replace.
+func (s *Service) PatchGroup(ctx context.Context, workspaceID, id string, ops []PatchOperation) (Group, error) {
+	g, err := s.GetGroup(ctx, workspaceID, id)
+	if err != nil {
+		return Group{}, err
+	}
+	touched := append([]string(nil), g.MemberIDs...)
+
+	for _, op := range ops {
+		path := strings.ToLower(op.Path)
+		switch {
+		case strings.EqualFold(op.Op, "add") && path == "members":
+			for _, m := range memberValues(op.Value) {
+				if !containsString(g.MemberIDs, m) {
+					g.MemberIDs = append(g.MemberIDs, m)
+				}
+				touched = append(touched, m)
+			}
+		case strings.EqualFold(op.Op, "remove") && strings.HasPrefix(path, "members"):
+			ids := memberValues(op.Value)
+			// Okta style: members[value eq "id"]
+			if v, ok := parseMemberPathFilter(op.Path); ok {
+				ids = append(ids, v)
+			}
+			if len(ids) == 0 && path == "members" {
+				ids = append(ids, g.MemberIDs...)
+			}
+			for _, m := range ids {
+				removeString(&g.MemberIDs, m)
+			}
+		case strings.EqualFold(op.Op, "replace") && path == "displayname":
+			name, _ := op.Value.(string)
+			if strings.TrimSpace(name) == "" {
+				return Group{}, ErrInvalidArgument
+			}
+			g.DisplayName = strings.TrimSpace(name)
+		default:
+			return Group{}, ErrInvalidArgument
+		}
+	}
+
+	g.UpdatedAt = s.clock().UTC()
+	if err := s.repo.UpdateGroup(ctx, g); err != nil {
+		return Group{}, err
+	}
+	s.log(ctx, workspaceID, "scim group updated", g.ID)
+	if err := s.syncRoles(ctx, workspaceID, touched); err != nil {
+		return Group{}, err
+	}
+	return g, nil
+}
+
+func (s *Service) DeleteGroup(ctx context.Context, workspaceID, id string) error {
+	g, err := s.GetGroup(ctx, workspaceID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteGroup(ctx, workspaceID, id); err != nil {
+		return err
+	}
+	s.log(ctx, workspaceID, "scim group deleted", g.ID)
+	return s.syncRoles(ctx, workspaceID, g.MemberIDs)
+}
+
+// SetRoleMapping maps an IdP group to a workspace role and re-syncs affected members.
+// Platform and hidden roles cannot be granted through an IdP.
+func (s *Service) SetRoleMapping(ctx context.Context, m RoleMapping) error {
+	m.GroupName = strings.TrimSpace(m.GroupName)
+	if m.WorkspaceID == "" || m.GroupName == "" {
+		return ErrInvalidArgument
+	}
+	switch m.Role {
+	case rbac.RoleOwner, rbac.RoleAgent, rbac.RoleAnalyst, rbac.RoleFinance:
+	default:
+		return ErrInvalidArgument
+	}
+	if err := s.repo.SetRoleMapping(ctx, m); err != nil {
+		return err
+	}
+	s.log(ctx, m.WorkspaceID, "scim role mapping set: "+m.GroupName+" -> "+m.Role, m.GroupName)
+
+	groups, err := s.repo.ListGroups(ctx, m.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	var members []string
+	for _, g := range groups {
+		if strings.EqualFold(g.DisplayName, m.GroupName) {
+			members = append(members, g.MemberIDs...)
+		}
+	}
+	return s.syncRoles(ctx, m.WorkspaceID, members)
+}
+
+// syncRoles recomputes the role of each given user from current group memberships.
+func (s *Service) syncRoles(ctx context.Context, workspaceID string, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	mappings, err := s.repo.ListRoleMappings(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	groups, err := s.repo.ListGroups(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(mappings, func(i, j int) bool { return mappings[i].Priority > mappings[j].Priority })
+
+	seen := map[string]bool{}
+	for _, uid := range userIDs {
+		if seen[uid] {
+			continue
+		}
+		seen[uid] = true
+
+		u, ok, err := s.repo.GetUser(ctx, workspaceID, uid)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		role := s.DefaultRole
+		for _, m := range mappings {
+			if userInGroupNamed(groups, uid, m.GroupName) {
+				role = m.Role
+				break
+			}
+		}
+		if role == u.Role {
+			continue
+		}
+		u.Role = role
+		u.UpdatedAt = s.clock().UTC()
+		if err := s.repo.UpdateUser(ctx, u); err != nil {
+			return err
+		}
+		s.log(ctx, workspaceID, "scim role changed to "+role, u.ID)
+	}
+	return nil
+}
+
+func (s *Service) log(ctx context.Context, workspaceID, message, resourceID string) {
+	if s.audit == nil {
+		return
+	}
+	// resourceID may be an IdP-supplied group name; encode it rather than splice it.
+	meta, _ := json.Marshal(map[string]string{"resource_id": resourceID})
+	_ = s.audit.Append(ctx, audit.Event{
+		WorkspaceID: workspaceID,
+		Type:        audit.EventTypeProvisioning,
+		ActorUserID: "scim",
+		Message:     message,
+		Metadata:    string(meta),
+	})
+}
+
+func activeMessage(was, is bool, fallback string) string {
+	switch {
+	case was && !is:
+		return "scim user deprovisioned"
+	case !was && is:
+		return "scim user reactivated"
+	default:
+		return fallback
+	}
+}
+
+func applyUserAttr(u *User, attr string, v any) error {
+	switch strings.ToLower(attr) {
+	case "active":
+		b, ok := v.(bool)
+		if !ok {
+			// Some IdPs send "False"/"True" strings.
+			s, isStr := v.(string)
+			if !isStr {
+				return ErrInvalidArgument
+			}
+			b = strings.EqualFold(s, "true")
+		}
+		u.Active = b
+	case "displayname":
+		s, ok := v.(string)
+		if !ok {
+			return ErrInvalidArgument
+		}
+		u.DisplayName = s
+	case "username":
+		s, ok := v.(string)
+		if !ok || strings.TrimSpace(s) == "" {
+			return ErrInvalidArgument
+		}
+		u.UserName = strings.TrimSpace(s)
+	case "externalid":
+		s, ok := v.(string)
+		if !ok {
+			return ErrInvalidArgument
+		}
+		u.ExternalID = s
+	default:
+		// Unknown attributes are ignored rather than failing the whole sync.
+	}
+	return nil
+}
+
+func parseUserNameFilter(filter string) (string, bool) {
+	f := strings.TrimSpace(filter)
+	const prefix = "username eq "
+	if len(f) <= len(prefix) || !strings.EqualFold(f[:len(prefix)], prefix) {
+		return "", false
+	}
+	v := strings.TrimSpace(f[len(prefix):])
+	v = strings.Trim(v, `"`)
+	return v, v != ""
+}
+
+func parseMemberPathFilter(path string) (string, bool) {
+	i := strings.Index(path, `value eq "`)
+	if i < 0 {
+		return "", false
+	}
+	rest := path[i+len(`value eq "`):]
+	j := strings.Index(rest, `"`)
+	if j <= 0 {
+		return "", false
+	}
+	return rest[:j], true
+}
+
+func memberValues(v any) []string {
+	list, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if id, ok := m["value"].(string); ok && id != "" {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func userInGroupNamed(groups []Group, userID, name string) bool {
+	for _, g := range groups {
+		if strings.EqualFold(g.DisplayName, name) && containsString(g.MemberIDs, userID) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, v string) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(list *[]string, v string) bool {
+	for i, x := range *list {
+		if x == v {
+			*list = append((*list)[:i], (*list)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"telecom-platform/internal/audit"
+)
+
+func TestService_GroupMembershipDrivesRole(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepo()
+	auditRepo := audit.NewMemoryRepo()
+	svc := NewService(repo, audit.NewService(auditRepo), "agent")
+
+	if err := svc.SetRoleMapping(ctx, RoleMapping{WorkspaceID: "w", GroupName: "Telecom Admins", Role: "owner", Priority: 10}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if err := svc.SetRoleMapping(ctx, RoleMapping{WorkspaceID: "w", GroupName: "Ops", Role: "super_admin"}); err != ErrInvalidArgument {
+		t.Fatalf("expected platform role mapping to be rejected, got %v", err)
+	}
+
+	u, err := svc.CreateUser(ctx, "w", User{UserName: "ana@example.com", Active: true})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if u.Role != "agent" {
+		t.Fatalf("expected default role, got %q", u.Role)
+	}
+	if _, err := svc.CreateUser(ctx, "w", User{UserName: "ANA@example.com"}); err != ErrConflict {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+
+	g, err := svc.CreateGroup(ctx, "w", Group{DisplayName: "Telecom Admins"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	add := []PatchOperation{{Op: "add", Path: "members", Value: []any{map[string]any{"value": u.ID}}}}
+	if _, err := svc.PatchGroup(ctx, "w", g.ID, add); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got, _ := svc.GetUser(ctx, "w", u.ID); got.Role != "owner" {
+		t.Fatalf("expected owner via group mapping, got %q", got.Role)
+	}
+
+	remove := []PatchOperation{{Op: "remove", Path: `members[value eq "` + u.ID + `"]`}}
+	if _, err := svc.PatchGroup(ctx, "w", g.ID, remove); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got, _ := svc.GetUser(ctx, "w", u.ID); got.Role != "agent" {
+		t.Fatalf("expected role reverted to default, got %q", got.Role)
+	}
+
+	if len(auditRepo.Events()) == 0 {
+		t.Fatalf("expected provisioning changes to be audited")
+	}
+}
+
+func TestService_DeprovisionAndWorkspaceIsolation(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepo()
+	svc := NewService(repo, nil, "agent")
+
+	u, _ := svc.CreateUser(ctx, "w", User{UserName: "bo@example.com", Active: true})
+	if _, err := svc.GetUser(ctx, "other", u.ID); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound across workspaces, got %v", err)
+	}
+
+	got, err := svc.PatchUser(ctx, "w", u.ID, []PatchOperation{{Op: "Replace", Value: map[string]any{"active": "False"}}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got.Active {
+		t.Fatalf("expected user deprovisioned")
+	}
+
+	list, err := svc.ListUsers(ctx, "w", `userName eq "bo@example.com"`)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("expected filter match, got %d (%v)", len(list), err)
+	}
+}
+
+func TestService_AuditMetadataEncodesGroupName(t *testing.T) {
+	ctx := context.Background()
+	auditRepo := audit.NewMemoryRepo()
+	svc := NewService(NewMemoryRepo(), audit.NewService(auditRepo), "agent")
+
+	name := `Admins", "resource_id": "spoofed`
+	if err := svc.SetRoleMapping(ctx, RoleMapping{WorkspaceID: "w", GroupName: name, Role: "owner"}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	events := auditRepo.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected one audit event, got %d", len(events))
+	}
+	var meta map[string]string
+	if err := json.Unmarshal([]byte(events[0].Metadata), &meta); err != nil || meta["resource_id"] != name {
+		t.Fatalf("expected group name round-tripped in metadata, got %q err=%v", events[0].Metadata, err)
+	}
+}
@@ -0,0 +1,128 @@
+package telephony
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"telecom-platform/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Outbound caller ID verification.
+//
+// A tenant may present a number it does not buy from us (e.g. its office line) as
+// caller ID once the provider has verified it owns the number: the provider calls
+// the number and the person answering enters the validation code shown in the
+// dashboard. The result arrives on the verification callback.
+
+// CallerIDVerifier is implemented by providers that can verify external numbers
+// for use as outbound caller ID.
+type CallerIDVerifier interface {
+	StartCallerIDVerification(ctx context.Context, req CallerIDVerificationRequest) (CallerIDVerificationStart, error)
+}
+
+type CallerIDVerificationRequest struct {
+	WorkspaceID  string `json:"workspace_id"`
+	Number       string `json:"number"` // E.164
+	FriendlyName string `json:"friendly_name,omitempty"`
+	// CallbackURL receives the verification result (see CallerIDVerificationURL).
+	CallbackURL string `json:"callback_url"`
+}
+
+// CallerIDVerificationStart is the in-progress verification.
+type CallerIDVerificationStart struct {
+	// ValidationCode is what the callee must enter; show it to the tenant.
+	ValidationCode string `json:"validation_code"`
+	// ProviderCallID is the provider's verification call.
+	ProviderCallID string `json:"provider_call_id,omitempty"`
+}
+
+// CallOriginator is implemented by providers that can place outbound calls.
+// Callers must authorize From as the workspace's caller ID first
+// (numbers.Service.AuthorizeCallerID, or numbers.CallerIDGuard), normalize To
+// with the dial plan (DialPlanGuard) and apply NOC stops and reroutes
+// (noc.OriginationGuard).
+type CallOriginator interface {
+	OriginateCall(ctx context.Context, req OriginateCallRequest) (providerCallID string, err error)
+}
+
+type OriginateCallRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	From        string `json:"from"` // caller ID presented, E.164
+	To          string `json:"to"`
+	// AnswerURL is fetched for call instructions once the callee answers.
+	AnswerURL string `json:"answer_url"`
+}
+
+// CallerIDVerificationURL is the verification callback for one request. The
+// workspace and verification are carried in the query because the provider's
+// callback only identifies the number.
+func (w WebhookURLs) CallerIDVerificationURL(provider, workspaceID, verificationID string) string {
+	return w.path(provider, "caller-id-verification") + "?" + url.Values{"workspace_id": {workspaceID}, "id": {verificationID}}.Encode()
+}
+
+// CallerIDVerificationRecorder stores a verification outcome (numbers.Service).
+type CallerIDVerificationRecorder interface {
+	CompleteCallerIDVerification(ctx context.Context, workspaceID, verificationID, number string, verified bool) error
+}
+
+// TwilioCallerIDHandler receives Twilio's outgoing caller ID validation callback.
+type TwilioCallerIDHandler struct {
+	Results CallerIDVerificationRecorder
+}
+
+// HandleVerification records VerificationStatus ("success" or "failed") for the
+// request named in the callback query.
+func (h TwilioCallerIDHandler) HandleVerification(c *gin.Context) {
+	log := logger.FromGin(c)
+
+	if h.Results == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "caller id verification not configured"})
+		return
+	}
+	if err := c.Request.ParseForm(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid form"})
+		return
+	}
+	workspaceID, id := c.Query("workspace_id"), c.Query("id")
+	number := normalizePhone(c.Request.PostFormValue("PhoneNumber"))
+	status := strings.ToLower(c.Request.PostFormValue("VerificationStatus"))
+	if workspaceID == "" || id == "" || number == "" || (status != "success" && status != "failed") {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid verification callback"})
+		return
+	}
+	if err := h.Results.CompleteCallerIDVerification(c.Request.Context(), workspaceID, id, number, status == "success"); err != nil {
+		log.Warn("caller id verification callback failed", "workspace_id", workspaceID, "id", id, "err", err)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "verification not recorded"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// StartCallerIDVerification requests a validation call to req.Number.
+// TODO: POST /OutgoingCallerIds/ValidationRequests once the REST client is wired.
+func (p *TwilioProvider) StartCallerIDVerification(ctx context.Context, req CallerIDVerificationRequest) (CallerIDVerificationStart, error) {
+	if req.WorkspaceID == "" || req.Number == "" || req.CallbackURL == "" {
+		return CallerIDVerificationStart{}, errors.New("telephony: workspace_id, number and callback_url required")
+	}
+	if err := p.waitREST(ctx, RESTPriorityNormal); err != nil {
+		return CallerIDVerificationStart{}, err
+	}
+	return CallerIDVerificationStart{}, errors.New("telephony: twilio StartCallerIDVerification not implemented")
+}
+
+// OriginateCall places an outbound call from req.From.
+// TODO: POST /Calls once the REST client is wired.
+func (p *TwilioProvider) OriginateCall(ctx context.Context, req OriginateCallRequest) (string, error) {
+	if req.WorkspaceID == "" || req.From == "" || req.To == "" || req.AnswerURL == "" {
+		return "", errors.New("telephony: workspace_id, from, to and answer_url required")
+	}
+	if err := p.waitREST(ctx, RESTPriorityCritical); err != nil {
+		return "", err
+	}
+	return "", errors.New("telephony: twilio OriginateCall not implemented")
+}
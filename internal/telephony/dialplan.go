@@ -0,0 +1,222 @@
+package telephony
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Dial plan normalization for outbound calls.
+//
+// Tenants enter destinations the way they would dial them locally: "(415)
+// 555-0100", "020 7946 0018", "011 44 20 7946 0018". Before origination the dial
+// plan turns them into E.164 using the destination country (the request's, or the
+// workspace default):
+//  1. punctuation and spaces are dropped;
+//  2. emergency numbers of that country (and 911/112 everywhere) are rejected with
+//     ErrEmergencyNumber: emergency calls must not be placed through the API, where
+//     no caller location can be provided;
+//  3. "+..." and international-prefixed numbers ("00", "011") are kept as is;
+//  4. national numbers lose the trunk prefix ("0") or NANP long-distance "1" and
+//     get the country calling code.
+//
+// Countries without a plan accept only international formats.
+
+var (
+	ErrInvalidDestination = errors.New("telephony: invalid destination number")
+	ErrEmergencyNumber    = errors.New("telephony: emergency numbers cannot be dialed via the api")
+	ErrUnknownCountry     = errors.New("telephony: no dial plan for country")
+)
+
+// CountryDialPlan describes how numbers are dialed within one country.
+type CountryDialPlan struct {
+	ISO2                string
+	CallingCode         string
+	TrunkPrefix         string
+	InternationalPrefix string
+	// NationalLengths are the valid national significant number lengths.
+	NationalLengths []int
+	Emergency       []string
+}
+
+// DialPlans are the built-in country plans.
+var DialPlans = map[string]CountryDialPlan{
+	"US": {ISO2: "US", CallingCode: "1", TrunkPrefix: "1", InternationalPrefix: "011", NationalLengths: []int{10}, Emergency: []string{"911", "933"}},
+	"CA": {ISO2: "CA", CallingCode: "1", TrunkPrefix: "1", InternationalPrefix: "011", NationalLengths: []int{10}, Emergency: []string{"911"}},
+	"GB": {ISO2: "GB", CallingCode: "44", TrunkPrefix: "0", InternationalPrefix: "00", NationalLengths: []int{9, 10}, Emergency: []string{"999", "112"}},
+	"IN": {ISO2: "IN", CallingCode: "91", TrunkPrefix: "0", InternationalPrefix: "00", NationalLengths: []int{10}, Emergency: []string{"112", "100", "101", "102", "108"}},
+	"AU": {ISO2: "AU", CallingCode: "61", TrunkPrefix: "0", InternationalPrefix: "0011", NationalLengths: []int{9}, Emergency: []string{"000", "112", "106"}},
+}
+
+// globalEmergency are blocked whatever the country.
+var globalEmergency = []string{"911", "112"}
+
+// NormalizeDestination returns number as E.164 for a caller in country (ISO
+// 3166-1 alpha-2; may be empty for international formats).
+func NormalizeDestination(number, country string) (string, error) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(number) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", fmt.Errorf("%w: unexpected %q", ErrInvalidDestination, r)
+		}
+	}
+	n := b.String()
+
+	country = strings.ToUpper(strings.TrimSpace(country))
+	plan, hasPlan := DialPlans[country]
+	if isEmergency(n, globalEmergency) || (hasPlan && isEmergency(n, plan.Emergency)) {
+		return "", ErrEmergencyNumber
+	}
+
+	switch {
+	case strings.HasPrefix(n, "+"):
+		return e164(n[1:])
+	case hasPlan && strings.HasPrefix(n, plan.InternationalPrefix):
+		return e164(n[len(plan.InternationalPrefix):])
+	case !hasPlan && strings.HasPrefix(n, "00"):
+		return e164(n[2:])
+	case !hasPlan && country != "":
+		return "", fmt.Errorf("%w: %s", ErrUnknownCountry, country)
+	case !hasPlan:
+		return "", fmt.Errorf("%w: national format needs a country", ErrInvalidDestination)
+	}
+
+	national := n
+	if plan.TrunkPrefix != "" && strings.HasPrefix(n, plan.TrunkPrefix) && !validLength(n, plan.NationalLengths) {
+		national = n[len(plan.TrunkPrefix):]
+	}
+	if !validLength(national, plan.NationalLengths) {
+		return "", fmt.Errorf("%w: not a %s national number", ErrInvalidDestination, plan.ISO2)
+	}
+	return "+" + plan.CallingCode + national, nil
+}
+
+func isEmergency(n string, numbers []string) bool {
+	for _, e := range numbers {
+		if n == e {
+			return true
+		}
+	}
+	return false
+}
+
+func validLength(n string, lengths []int) bool {
+	for _, l := range lengths {
+		if len(n) == l {
+			return true
+		}
+	}
+	return false
+}
+
+// e164 checks an international number (without "+"): 8 to 15 digits, not
+// starting with 0.
+func e164(digits string) (string, error) {
+	if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return "", fmt.Errorf("%w: not an international number", ErrInvalidDestination)
+	}
+	return "+" + digits, nil
+}
+
+// DialPlanSettingsStore holds each workspace's default destination country.
+// Implementations must enforce workspace filtering.
+type DialPlanSettingsStore interface {
+	GetDefaultCountry(ctx context.Context, workspaceID string) (string, bool, error)
+	SetDefaultCountry(ctx context.Context, workspaceID, country string) error
+}
+
+// DialPlanner normalizes destinations with per-workspace defaults.
+type DialPlanner struct {
+	Settings DialPlanSettingsStore
+}
+
+// SetDefaultCountry sets the country used for national-format destinations.
+func (p *DialPlanner) SetDefaultCountry(ctx context.Context, workspaceID, country string) error {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if workspaceID == "" {
+		return ErrInvalidDestination
+	}
+	if _, ok := DialPlans[country]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownCountry, country)
+	}
+	return p.Settings.SetDefaultCountry(ctx, workspaceID, country)
+}
+
+// DefaultCountry returns the workspace default ("" when unset).
+func (p *DialPlanner) DefaultCountry(ctx context.Context, workspaceID string) (string, error) {
+	if p.Settings == nil {
+		return "", nil
+	}
+	c, _, err := p.Settings.GetDefaultCountry(ctx, workspaceID)
+	return c, err
+}
+
+// Normalize returns number as E.164, using country or else the workspace default.
+func (p *DialPlanner) Normalize(ctx context.Context, workspaceID, number, country string) (string, error) {
+	if country == "" {
+		c, err := p.DefaultCountry(ctx, workspaceID)
+		if err != nil {
+			return "", err
+		}
+		country = c
+	}
+	return NormalizeDestination(number, country)
+}
+
+// DialPlanGuard normalizes every origination's phone destination before passing
+// it on; emergency and invalid destinations are rejected. SIP URIs pass as is
+// unless their user part is an emergency number (sip:911@gw).
+type DialPlanGuard struct {
+	Next CallOriginator
+	Plan *DialPlanner
+}
+
+func (g DialPlanGuard) OriginateCall(ctx context.Context, req OriginateCallRequest) (string, error) {
+	if user, ok := sipURIUser(req.To); ok {
+		country, err := g.Plan.DefaultCountry(ctx, req.WorkspaceID)
+		if err != nil {
+			return "", err
+		}
+		if IsEmergencyNumber(user, country) {
+			return "", ErrEmergencyNumber
+		}
+		return g.Next.OriginateCall(ctx, req)
+	}
+	to, err := g.Plan.Normalize(ctx, req.WorkspaceID, req.To, "")
+	if err != nil {
+		return "", err
+	}
+	req.To = to
+	return g.Next.OriginateCall(ctx, req)
+}
+
+// sipURIUser returns the user part of a sip: or sips: URI, without parameters.
+func sipURIUser(to string) (string, bool) {
+	lower := strings.ToLower(strings.TrimSpace(to))
+	var rest string
+	switch {
+	case strings.HasPrefix(lower, "sip:"):
+		rest = strings.TrimSpace(to)[len("sip:"):]
+	case strings.HasPrefix(lower, "sips:"):
+		rest = strings.TrimSpace(to)[len("sips:"):]
+	default:
+		return "", false
+	}
+	user, _, _ := strings.Cut(rest, "@")
+	user, _, _ = strings.Cut(user, ";")
+	return user, true
+}
+
+// IsEmergencyNumber reports whether number, as dialed from country, is an
+// emergency number.
+func IsEmergencyNumber(number, country string) bool {
+	_, err := NormalizeDestination(number, country)
+	return errors.Is(err, ErrEmergencyNumber)
+}
@@ -0,0 +1,32 @@
+package telephony
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryDialPlanSettings is a simple in-memory DialPlanSettingsStore useful for tests.
+// It is not intended for production use.
+
+type MemoryDialPlanSettings struct {
+	mu        sync.Mutex
+	countries map[string]string
+}
+
+func NewMemoryDialPlanSettings() *MemoryDialPlanSettings {
+	return &MemoryDialPlanSettings{countries: map[string]string{}}
+}
+
+func (s *MemoryDialPlanSettings) GetDefaultCountry(ctx context.Context, workspaceID string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.countries[workspaceID]
+	return c, ok, nil
+}
+
+func (s *MemoryDialPlanSettings) SetDefaultCountry(ctx context.Context, workspaceID, country string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.countries[workspaceID] = country
+	return nil
+}
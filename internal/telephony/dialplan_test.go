@@ -0,0 +1,81 @@
+package telephony
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNormalizeDestination(t *testing.T) {
+	cases := []struct {
+		number, country, want string
+		err                   error
+	}{
+		{"(415) 555-0100", "US", "+14155550100", nil},
+		{"1-415-555-0100", "us", "+14155550100", nil},
+		{"011 44 20 7946 0018", "US", "+442079460018", nil},
+		{"020 7946 0018", "GB", "+442079460018", nil},
+		{"00 1 415 555 0100", "GB", "+14155550100", nil},
+		{"098765 43210", "IN", "+919876543210", nil},
+		{"+61 2 1234 5678", "", "+61212345678", nil},
+		{"911", "US", "", ErrEmergencyNumber},
+		{"999", "GB", "", ErrEmergencyNumber},
+		{"112", "", "", ErrEmergencyNumber},
+		{"000", "AU", "", ErrEmergencyNumber},
+		{"4155550100", "", "", ErrInvalidDestination},
+		{"4155550100", "ZZ", "", ErrUnknownCountry},
+		{"555-0100", "US", "", ErrInvalidDestination},
+		{"415*555", "US", "", ErrInvalidDestination},
+	}
+	for _, tc := range cases {
+		got, err := NormalizeDestination(tc.number, tc.country)
+		if tc.err != nil {
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("%q/%s: expected %v, got %q err=%v", tc.number, tc.country, tc.err, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Fatalf("%q/%s: expected %s, got %q err=%v", tc.number, tc.country, tc.want, got, err)
+		}
+	}
+}
+
+type recordingOriginator struct{ to string }
+
+func (o *recordingOriginator) OriginateCall(ctx context.Context, req OriginateCallRequest) (string, error) {
+	o.to = req.To
+	return "CA1", nil
+}
+
+func TestDialPlanGuard_UsesWorkspaceDefaultCountry(t *testing.T) {
+	ctx := context.Background()
+	plan := &DialPlanner{Settings: NewMemoryDialPlanSettings()}
+	if err := plan.SetDefaultCountry(ctx, "w", "gb"); err != nil {
+		t.Fatal(err)
+	}
+	if err := plan.SetDefaultCountry(ctx, "w", "ZZ"); !errors.Is(err, ErrUnknownCountry) {
+		t.Fatalf("expected unknown country, got %v", err)
+	}
+	next := &recordingOriginator{}
+	g := DialPlanGuard{Next: next, Plan: plan}
+	req := OriginateCallRequest{WorkspaceID: "w", From: "+442071234567", To: "020 7946 0018", AnswerURL: "https://example.com/answer"}
+	if _, err := g.OriginateCall(ctx, req); err != nil || next.to != "+442079460018" {
+		t.Fatalf("expected normalized destination, got %q err=%v", next.to, err)
+	}
+	req.To = "999"
+	next.to = ""
+	if _, err := g.OriginateCall(ctx, req); !errors.Is(err, ErrEmergencyNumber) || next.to != "" {
+		t.Fatalf("expected emergency rejection before origination, got %q err=%v", next.to, err)
+	}
+	for _, to := range []string{"sip:911@gw.example.com", "SIPS:999@gw.example.com;transport=tls", "sip:112;user=phone@gw"} {
+		req.To = to
+		if _, err := g.OriginateCall(ctx, req); !errors.Is(err, ErrEmergencyNumber) || next.to != "" {
+			t.Fatalf("expected emergency sip uri %q rejected, got %q err=%v", to, next.to, err)
+		}
+	}
+	req.To = "sip:alice@pbx.example.com"
+	if _, err := g.OriginateCall(ctx, req); err != nil || next.to != req.To {
+		t.Fatalf("expected sip uri passed through, got %q err=%v", next.to, err)
+	}
+}
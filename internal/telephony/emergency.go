@@ -0,0 +1,55 @@
+package telephony
+
+import (
+	"context"
+	"errors"
+)
+
+// Emergency calling (E911).
+//
+// US voice services must attach a registered service address to every number
+// that can call 911, so the emergency center receives the caller's location.
+// Providers that can provision addresses implement EmergencyAddressRegistrar; the
+// numbers service records the result per number and reports numbers missing one
+// (numbers.Service.EmergencyReadiness). Emergency handling on SIP trunks is an
+// explicit per-trunk policy (SIPTrunk.Emergency), enforced by
+// SIPProvider.HandleInboundCall for calls that carry a TrunkID.
+
+// EmergencyAddress is a dispatchable service address.
+type EmergencyAddress struct {
+	CustomerName string `json:"customer_name"`
+	Street       string `json:"street"`
+	Street2      string `json:"street2,omitempty"` // suite, floor
+	City         string `json:"city"`
+	Region       string `json:"region"` // state/province code
+	PostalCode   string `json:"postal_code"`
+	CountryISO2  string `json:"country_iso2"`
+}
+
+// EmergencyCallAuthorizer checks an emergency call on a trunk against its policy;
+// numbers.Service implements it.
+type EmergencyCallAuthorizer interface {
+	AuthorizeEmergencyCall(ctx context.Context, trunk SIPTrunk, callerID string) error
+}
+
+// EmergencyAddressRegistrar is implemented by providers that can provision an
+// emergency address on a number.
+type EmergencyAddressRegistrar interface {
+	// RegisterEmergencyAddress validates address with the provider and attaches it
+	// to number, returning the provider's address ID.
+	RegisterEmergencyAddress(ctx context.Context, workspaceID, number string, address EmergencyAddress) (providerAddressID string, err error)
+}
+
+// RegisterEmergencyAddress creates the address and sets it as the number's
+// emergency address.
+// TODO: POST /Addresses (EmergencyEnabled) and update IncomingPhoneNumbers
+// EmergencyAddressSid once the REST client is wired.
+func (p *TwilioProvider) RegisterEmergencyAddress(ctx context.Context, workspaceID, number string, address EmergencyAddress) (string, error) {
+	if workspaceID == "" || number == "" || address.Street == "" {
+		return "", errors.New("telephony: workspace_id, number and address required")
+	}
+	if err := p.waitREST(ctx, RESTPriorityNormal); err != nil {
+		return "", err
+	}
+	return "", errors.New("telephony: twilio RegisterEmergencyAddress not implemented")
+}
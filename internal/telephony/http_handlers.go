@@ -1,13 +1,20 @@
 package telephony
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
+	"telecom-platform/internal/events"
 	"telecom-platform/internal/routing"
 	"telecom-platform/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // TwilioWebhookHandler converts the Twilio webhook to internal types,
@@ -26,6 +33,20 @@ type TwilioWebhookHandler struct {
 	// For now, it's an injected function to avoid any persistence assumptions in this skeleton.
 	WorkspaceIDResolver func(c *gin.Context, toNumber string) (string, error)
 
+	// Events publishes call lifecycle events to the worker bus (optional).
+	// Publishing is best-effort and never fails the webhook.
+	Events events.Publisher
+
+	// DialStatusPath is where HandleDialStatus is mounted. Failover alternates
+	// are only dialed when it is set.
+	DialStatusPath string
+
+	// Concurrency is the routing engine's slot store (routing.ConcurrencySlots,
+	// optional). HandleCallStatus frees a call's destination slots when it ends,
+	// HandleInboundCall when the routed call cannot be answered, and
+	// HandleDialStatus moves them to the alternate a call fails over to.
+	Concurrency SlotStore
+
 	Now func() time.Time
 }
 
@@ -59,22 +80,300 @@ func (h TwilioWebhookHandler) HandleInboundCall(c *gin.Context) {
 	}
 
 	in := form.ToInboundCallRequest(workspaceID, h.Now())
+	in.Collected = gatherCollected(c, form.Digits)
 	ctx := routing.WithClientIP(c.Request.Context(), c.ClientIP())
 
 	res, err := h.Provider.HandleInboundCall(ctx, in)
 	if err != nil {
 		log.Error("inbound call routing failed", "err", err)
+		h.releaseSlots(c, workspaceID, in.ProviderCallID)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "routing failed"})
 		return
 	}
 
+	if res.Action == InboundCallActionGather && res.Gather != nil && res.Gather.ActionURL == "" {
+		res.Gather.ActionURL = gatherActionURL(c.Request.URL.Path, in.Collected, res.Gather.Step)
+	}
+	if res.Action == InboundCallActionConnect && len(res.Alternates) > 0 && h.DialStatusPath != "" {
+		res.DialActionURL = failoverActionURL(h.DialStatusPath, res.Alternates, res.AlternateLimits, res.SIPHeaders)
+	}
+
 	twiml, err := RenderTwiML(res)
 	if err != nil {
 		log.Error("twiml render failed", "err", err)
+		h.releaseSlots(c, workspaceID, in.ProviderCallID)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "twiml failed"})
 		return
 	}
 
+	h.publishCallEvent(c, events.TypeCallInbound, workspaceID, in.OccurredAt, events.CallPayload{
+		CallID:         res.CallID,
+		ProviderCallID: in.ProviderCallID,
+		Provider:       "twilio",
+		From:           in.From,
+		To:             in.To,
+		Status:         string(res.Action),
+	})
+
+	c.Header("Content-Type", "application/xml")
+	c.String(http.StatusOK, twiml)
+}
+
+// callEventID derives the event id from the call and event type, so a webhook
+// Twilio retries publishes the same id and consumers drop the duplicate.
+// Returns "" (rejected by NewCallEvent) when the call sid is missing.
+func callEventID(callSid string, t events.Type) string {
+	if callSid == "" {
+		return ""
+	}
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte("twilio:"+callSid+":"+string(t))).String()
+}
+
+// publishCallEvent publishes a call lifecycle event when Events is set.
+// Failures are logged and never fail the webhook.
+func (h TwilioWebhookHandler) publishCallEvent(c *gin.Context, t events.Type, workspaceID string, occurredAt time.Time, p events.CallPayload) {
+	if h.Events == nil {
+		return
+	}
+	ev, err := events.NewCallEvent(callEventID(p.ProviderCallID, t), t, workspaceID, occurredAt, p)
+	if err == nil {
+		_, err = h.Events.Publish(c.Request.Context(), ev)
+	}
+	if err != nil {
+		logger.FromGin(c).Warn("call event publish failed", "type", t, "call_sid", p.ProviderCallID, "err", err)
+	}
+}
+
+// IVR state is carried in the <Gather> callback URL so the webhook stays stateless:
+// earlier answers as ivr_<step>=<digits> and the step being answered as step=<name>.
+const gatherParamPrefix = "ivr_"
+
+// gatherCollected rebuilds caller input from the callback query plus this request's digits.
+func gatherCollected(c *gin.Context, digits string) map[string]string {
+	out := map[string]string{}
+	for k, v := range c.Request.URL.Query() {
+		if strings.HasPrefix(k, gatherParamPrefix) && len(v) > 0 {
+			out[strings.TrimPrefix(k, gatherParamPrefix)] = v[0]
+		}
+	}
+	if step := c.Query("step"); step != "" {
+		out[step] = digits
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func gatherActionURL(path string, collected map[string]string, step string) string {
+	q := url.Values{}
+	for k, v := range collected {
+		q.Set(gatherParamPrefix+k, v)
+	}
+	q.Set("step", step)
+	return path + "?" + q.Encode()
+}
+
+// Failover state is carried in the <Dial action> URL like IVR state: the targets
+// still to try, in order, as repeated next=<target>, their concurrency limits as
+// repeated max=<n>, and the custom SIP headers as hdr_<name>=<value> so
+// alternates receive them too.
+const (
+	failoverParam       = "next"
+	failoverLimitParam  = "max"
+	failoverHeaderParam = "hdr_"
+)
+
+func failoverActionURL(path string, targets []string, limits []int, headers map[string]string) string {
+	q := url.Values{failoverParam: targets}
+	if len(limits) == len(targets) {
+		for _, n := range limits {
+			q.Add(failoverLimitParam, strconv.Itoa(n))
+		}
+	}
+	for k, v := range headers {
+		q.Set(failoverHeaderParam+k, v)
+	}
+	return path + "?" + q.Encode()
+}
+
+// failoverHeaders reads the SIP headers back from a failover callback URL.
+func failoverHeaders(q url.Values) map[string]string {
+	return prefixedSIPHeaders(q, failoverHeaderParam)
+}
+
+// retryDialStatus reports whether a DialCallStatus means the target never
+// picked up, so the next alternate should be tried.
+func retryDialStatus(status string) bool {
+	switch status {
+	case "busy", "no-answer", "failed":
+		return true
+	}
+	return false
+}
+
+// HandleDialStatus receives the outcome of a <Dial> that has failover
+// alternates. When the target was busy, did not answer or failed, it dials the
+// next alternate (with the rest of the chain on its action URL); otherwise, or
+// once the chain is exhausted, it ends the call. With Concurrency set the call's
+// slot moves from the failed target to the alternate, and alternates that are
+// now full are skipped.
+func (h TwilioWebhookHandler) HandleDialStatus(c *gin.Context) {
+	log := logger.FromGin(c)
+
+	status := c.PostForm("DialCallStatus")
+	next := c.QueryArray(failoverParam)
+	limits := c.QueryArray(failoverLimitParam)
+	if len(limits) != len(next) {
+		limits = nil
+	}
+	res := InboundCallResult{Action: InboundCallActionHangup}
+	if retryDialStatus(status) && len(next) > 0 {
+		i, err := h.reserveAlternate(c, next, limits)
+		if err != nil {
+			log.Error("failover slot reservation failed", "call_sid", c.PostForm("CallSid"), "err", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failover failed"})
+			return
+		}
+		if i < len(next) {
+			res = InboundCallResult{Action: InboundCallActionConnect, ConnectTo: next[i], SIPHeaders: failoverHeaders(c.Request.URL.Query())}
+			if rest := next[i+1:]; len(rest) > 0 {
+				var restLimits []int
+				if limits != nil {
+					for _, v := range limits[i+1:] {
+						n, _ := strconv.Atoi(v)
+						restLimits = append(restLimits, n)
+					}
+				}
+				res.DialActionURL = failoverActionURL(c.Request.URL.Path, rest, restLimits, res.SIPHeaders)
+			}
+			log.Info("dial failover", "call_sid", c.PostForm("CallSid"), "status", status, "next", next[i], "remaining", len(next)-i-1)
+		}
+	}
+
+	twiml, err := RenderTwiML(res)
+	if err != nil {
+		log.Error("twiml render failed", "err", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "twiml failed"})
+		return
+	}
 	c.Header("Content-Type", "application/xml")
 	c.String(http.StatusOK, twiml)
 }
+
+// reserveAlternate frees the slot the call held on the target that failed and
+// takes one on the first alternate with room, returning its index (len(next)
+// when every alternate is full). Without Concurrency or a call sid it returns 0.
+func (h TwilioWebhookHandler) reserveAlternate(c *gin.Context, next, limits []string) (int, error) {
+	callSid := c.PostForm("CallSid")
+	if h.Concurrency == nil || callSid == "" {
+		return 0, nil
+	}
+	if h.WorkspaceIDResolver == nil {
+		return 0, errors.New("telephony: workspace resolver not configured")
+	}
+	workspaceID, err := h.WorkspaceIDResolver(c, normalizePhone(c.PostForm("To")))
+	if err != nil {
+		return 0, err
+	}
+	ctx := c.Request.Context()
+	if err := h.Concurrency.Release(ctx, workspaceID, callSid); err != nil {
+		return 0, err
+	}
+	for i, target := range next {
+		limit := 0
+		if limits != nil {
+			limit, _ = strconv.Atoi(limits[i])
+		}
+		if limit <= 0 {
+			return i, nil
+		}
+		ok, err := h.Concurrency.Acquire(ctx, workspaceID, target, callSid, limit)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return i, nil
+		}
+		logger.FromGin(c).Info("failover alternate full", "call_sid", callSid, "target", target)
+	}
+	return len(next), nil
+}
+
+// releaseSlots frees a call's slots after routing took them but the call cannot
+// be answered. Failures are logged; the slot TTL reclaims them eventually.
+func (h TwilioWebhookHandler) releaseSlots(c *gin.Context, workspaceID, callSid string) {
+	if h.Concurrency == nil || callSid == "" {
+		return
+	}
+	if err := h.Concurrency.Release(c.Request.Context(), workspaceID, callSid); err != nil {
+		logger.FromGin(c).Error("concurrency slot release failed", "call_sid", callSid, "err", err)
+	}
+}
+
+// SlotStore holds destination concurrency slots per call.
+type SlotStore interface {
+	// Acquire takes a slot on target for callID if fewer than limit are in use.
+	Acquire(ctx context.Context, workspaceID, target, callID string, limit int) (bool, error)
+	// Release frees every slot callID holds.
+	Release(ctx context.Context, workspaceID, callID string) error
+}
+
+// callEnded reports whether a CallStatus is final.
+func callEnded(status string) bool {
+	switch status {
+	case "completed", "busy", "no-answer", "failed", "canceled":
+		return true
+	}
+	return false
+}
+
+// HandleCallStatus receives the call status callback. When the call has ended it
+// releases the destination concurrency slots the call held, so the next caller
+// can be routed there, and publishes call.completed (or call.failed for calls
+// that never connected).
+func (h TwilioWebhookHandler) HandleCallStatus(c *gin.Context) {
+	log := logger.FromGin(c)
+
+	if h.Now == nil {
+		h.Now = time.Now
+	}
+	form, err := ParseTwilioInboundCall(c.Request)
+	if err != nil {
+		log.Warn("twilio status callback parse failed", "err", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid form"})
+		return
+	}
+	if !callEnded(form.CallStatus) || (h.Concurrency == nil && h.Events == nil) || form.CallSid == "" {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	if h.WorkspaceIDResolver == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "workspace resolver not configured"})
+		return
+	}
+	workspaceID, err := h.WorkspaceIDResolver(c, form.To)
+	if err != nil {
+		log.Warn("workspace resolution failed", "to", form.To, "err", err)
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "unknown destination"})
+		return
+	}
+	if h.Concurrency != nil {
+		if err := h.Concurrency.Release(c.Request.Context(), workspaceID, form.CallSid); err != nil {
+			log.Error("concurrency slot release failed", "call_sid", form.CallSid, "err", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "release failed"})
+			return
+		}
+	}
+
+	p := events.CallPayload{ProviderCallID: form.CallSid, Provider: "twilio", From: form.From, To: form.To, Status: form.CallStatus}
+	t := events.TypeCallCompleted
+	if form.CallStatus == "completed" {
+		p.DurationSeconds, _ = strconv.Atoi(form.CallDuration)
+	} else {
+		t = events.TypeCallFailed
+		p.FailureReason = form.CallStatus
+	}
+	h.publishCallEvent(c, t, workspaceID, h.Now(), p)
+	c.Status(http.StatusNoContent)
+}
@@ -24,6 +24,43 @@ type TelephonyProvider interface {
 	FetchCDR(ctx context.Context, req FetchCDRRequest) (FetchCDRResult, error)
 }
 
+// CallTerminator is implemented by providers that can end a live call.
+// It is kept out of TelephonyProvider so adapters can adopt it incrementally.
+type CallTerminator interface {
+	// HangupCall plays announcement (optional) and then ends the call.
+	HangupCall(ctx context.Context, workspaceID, providerCallID, announcement string) error
+}
+
+// CallHolder is implemented by providers that can put the remote party of a live
+// call on hold music and take it off again.
+type CallHolder interface {
+	// HoldCall plays musicURL (the provider's default when empty) until ResumeCall.
+	HoldCall(ctx context.Context, workspaceID, providerCallID, musicURL string) error
+	ResumeCall(ctx context.Context, workspaceID, providerCallID string) error
+}
+
+// CallMonitor is implemented by providers that can bridge a supervisor into a
+// live call through a conference. mode is "listen" (muted), "whisper" (heard by
+// the agent only) or "barge".
+type CallMonitor interface {
+	JoinMonitor(ctx context.Context, workspaceID, providerCallID, supervisorTarget, mode string) (legID string, err error)
+	SetMonitorMode(ctx context.Context, workspaceID, providerCallID, legID, mode string) error
+	LeaveMonitor(ctx context.Context, workspaceID, providerCallID, legID string) error
+	// AnnounceToCaller plays message to the remote party only.
+	AnnounceToCaller(ctx context.Context, workspaceID, providerCallID, message string) error
+}
+
+// NumberSearcher is implemented by providers that can list numbers available for purchase.
+type NumberSearcher interface {
+	SearchNumbers(ctx context.Context, req SearchNumbersRequest) (SearchNumbersResult, error)
+}
+
+// SMSSender is implemented by providers that can send outbound SMS. Callers are
+// responsible for pacing sends to carrier throughput limits.
+type SMSSender interface {
+	SendSMS(ctx context.Context, req SendSMSRequest) (SendSMSResult, error)
+}
+
 // InboundCallRequest represents an inbound call event received from a provider.
 type InboundCallRequest struct {
 	WorkspaceID string `json:"workspace_id"`
@@ -40,6 +77,17 @@ type InboundCallRequest struct {
 
 	// RawPayload is optional for debugging/audit; store as JSON string.
 	RawPayload string `json:"raw_payload,omitempty"`
+
+	// Collected holds caller input gathered by earlier IVR steps, keyed by step name.
+	// A present key with an empty value means the step was asked but got no input.
+	Collected map[string]string `json:"collected,omitempty"`
+
+	// SIPHeaders are the custom (X-) SIP headers the call arrived with.
+	SIPHeaders map[string]string `json:"sip_headers,omitempty"`
+
+	// TrunkID is set for calls placed by a PBX over one of the workspace's SIP
+	// trunks; emergency calls on it are subject to the trunk's policy.
+	TrunkID string `json:"trunk_id,omitempty"`
 }
 
 // InboundCallResult is the provider adapter response used to drive next steps.
@@ -52,6 +100,49 @@ type InboundCallResult struct {
 
 	// ConnectTo is used when Action == "connect".
 	ConnectTo string `json:"connect_to,omitempty"`
+	// Alternates are dialed in order when ConnectTo is busy, does not answer or
+	// fails (connect only).
+	Alternates []string `json:"alternates,omitempty"`
+	// AlternateLimits is each alternate's concurrency limit (0: uncapped).
+	AlternateLimits []int `json:"alternate_limits,omitempty"`
+	// DialActionURL receives the dial outcome and dials the next alternate.
+	// Webhook handlers fill it in.
+	DialActionURL string `json:"dial_action_url,omitempty"`
+	// SIPHeaders are custom (X-) headers sent with SIP targets, e.g. X-Campaign-ID.
+	SIPHeaders map[string]string `json:"sip_headers,omitempty"`
+
+	// Gather is used when Action == "gather".
+	Gather *GatherPrompt `json:"gather,omitempty"`
+
+	// Language is the caller's selected language (BCP 47), recorded as a call attribute.
+	Language string `json:"language,omitempty"`
+
+	// Announcement is read before a hangup (optional).
+	Announcement string `json:"announcement,omitempty"`
+}
+
+// GatherPrompt asks the caller for DTMF input. The provider calls back with the
+// digits, which arrive in InboundCallRequest.Collected[Step].
+type GatherPrompt struct {
+	Step           string `json:"step"`
+	Prompt         string `json:"prompt"`
+	NumDigits      int    `json:"num_digits,omitempty"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+
+	// ActionURL is the callback for the collected digits. Webhook handlers fill it in.
+	ActionURL string `json:"action_url,omitempty"`
+
+	// Language is the TTS language for Prompt.
+	Language string `json:"language,omitempty"`
+	// Segments, when set, replace Prompt (e.g. a menu read in several languages).
+	Segments []PromptSegment `json:"segments,omitempty"`
+}
+
+// PromptSegment is one piece of a prompt: an audio file, or text read by TTS in Language.
+type PromptSegment struct {
+	Text     string `json:"text,omitempty"`
+	Language string `json:"language,omitempty"`
+	AudioURL string `json:"audio_url,omitempty"`
 }
 
 type InboundCallAction string
@@ -60,6 +151,7 @@ const (
 	InboundCallActionReject  InboundCallAction = "reject"
 	InboundCallActionConnect InboundCallAction = "connect"
 	InboundCallActionHangup  InboundCallAction = "hangup"
+	InboundCallActionGather  InboundCallAction = "gather"
 )
 
 type BuyNumberRequest struct {
@@ -71,6 +163,9 @@ type BuyNumberRequest struct {
 	// DesiredNumber is optional; if empty, provider selects best available.
 	DesiredNumber string `json:"desired_number,omitempty"`
 
+	// Capabilities the number must support (voice, sms, mms, fax); empty means any.
+	Capabilities []string `json:"capabilities,omitempty"`
+
 	// Metadata is optional JSON.
 	Metadata string `json:"metadata,omitempty"`
 }
@@ -82,6 +177,46 @@ type BuyNumberResult struct {
 	Number string `json:"number"`
 
 	ProviderNumberID string `json:"provider_number_id"`
+
+	// Capabilities reported by the provider for the purchased number.
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+type SearchNumbersRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+
+	CountryISO2 string `json:"country_iso2"`
+	NumberType  string `json:"number_type"`
+
+	// Optional filters.
+	AreaCode     string   `json:"area_code,omitempty"`
+	Contains     string   `json:"contains,omitempty"`     // digits pattern; provider wildcard syntax is adapter-specific
+	Capabilities []string `json:"capabilities,omitempty"` // voice, sms, mms, fax
+
+	// PageToken is the provider's opaque continuation token; empty for the first page.
+	PageToken string `json:"page_token,omitempty"`
+	PageSize  int    `json:"page_size,omitempty"`
+}
+
+type SearchNumbersResult struct {
+	Numbers       []AvailableNumber `json:"numbers"`
+	NextPageToken string            `json:"next_page_token,omitempty"`
+}
+
+// AvailableNumber is a provider-agnostic number offered for purchase.
+type AvailableNumber struct {
+	Number      string `json:"number"` // E.164
+	CountryISO2 string `json:"country_iso2"`
+	NumberType  string `json:"number_type"`
+
+	Locality   string `json:"locality,omitempty"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+
+	Capabilities []string `json:"capabilities"`
+
+	MonthlyCostMinor int64  `json:"monthly_cost_minor,omitempty"`
+	Currency         string `json:"currency,omitempty"`
 }
 
 type ReleaseNumberRequest struct {
@@ -150,3 +285,16 @@ type CDR struct {
 	// Raw is optional JSON.
 	Raw string `json:"raw,omitempty"`
 }
+
+type SendSMSRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+
+	// From is the sending workspace number and To the recipient, both E.164.
+	From string `json:"from"`
+	To   string `json:"to"`
+	Body string `json:"body"`
+}
+
+type SendSMSResult struct {
+	ProviderMessageID string `json:"provider_message_id"`
+}
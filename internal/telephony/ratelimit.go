@@ -0,0 +1,229 @@
+package telephony
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// Client-side rate limiting for provider REST APIs.
+//
+// Providers enforce per-account request limits and answer 429 when exceeded. Adapters
+// call RESTLimiter.Wait before each REST request so bulk work (number searches, CDR
+// pulls) queues behind call-critical requests instead of tripping the provider limit.
+//
+// Each account has a token bucket. Waiters are served in priority order (FIFO within a
+// priority), and bulk requests may not spend the last BulkReserve tokens so a burst of
+// bulk work always leaves headroom for live call control.
+
+// RESTPriority orders queued provider REST requests; lower values are served first.
+type RESTPriority int
+
+const (
+	// RESTPriorityCritical is live call control (hangup, recording).
+	RESTPriorityCritical RESTPriority = iota
+	// RESTPriorityNormal is interactive provisioning (buy/release numbers).
+	RESTPriorityNormal
+	// RESTPriorityBulk is background or paginated work (number searches, CDR pulls).
+	RESTPriorityBulk
+)
+
+var ErrRESTQueueFull = errors.New("telephony: provider rest queue full")
+
+// RESTLimitConfig configures a RESTLimiter. Zero values use defaults.
+type RESTLimitConfig struct {
+	// RatePerSecond is the sustained request rate per account (default 10).
+	RatePerSecond float64
+	// Burst is the bucket size (default: RatePerSecond rounded up).
+	Burst int
+	// BulkReserve is the number of tokens bulk requests leave for others (default Burst/4).
+	BulkReserve int
+	// MaxQueue bounds waiters per account (default 1000).
+	MaxQueue int
+}
+
+// RESTLimiter is a per-account token bucket with a priority queue. A nil limiter
+// never waits. Safe for concurrent use.
+type RESTLimiter struct {
+	cfg RESTLimitConfig
+	now func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*restBucket
+}
+
+type restBucket struct {
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+
+	queue []*restWaiter // ordered by priority, then arrival
+	wake  chan struct{} // closed (and replaced) whenever the queue or pause changes
+}
+
+type restWaiter struct {
+	prio RESTPriority
+}
+
+func NewRESTLimiter(cfg RESTLimitConfig) *RESTLimiter {
+	if cfg.RatePerSecond <= 0 {
+		cfg.RatePerSecond = 10
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = int(math.Ceil(cfg.RatePerSecond))
+	}
+	if cfg.BulkReserve < 0 || cfg.BulkReserve >= cfg.Burst {
+		cfg.BulkReserve = 0
+	} else if cfg.BulkReserve == 0 {
+		cfg.BulkReserve = cfg.Burst / 4
+	}
+	if cfg.MaxQueue <= 0 {
+		cfg.MaxQueue = 1000
+	}
+	return &RESTLimiter{cfg: cfg, now: time.Now, buckets: map[string]*restBucket{}}
+}
+
+// Wait blocks until a request for account may be sent, ctx is done, or the account's
+// queue is full.
+func (l *RESTLimiter) Wait(ctx context.Context, account string, prio RESTPriority) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	b := l.bucket(account)
+	if len(b.queue) >= l.cfg.MaxQueue {
+		l.mu.Unlock()
+		return ErrRESTQueueFull
+	}
+	w := &restWaiter{prio: prio}
+	b.enqueue(w)
+
+	for {
+		now := l.now()
+		b.refill(now, l.cfg)
+		delay := b.delay(w, now, l.cfg)
+		if delay == 0 {
+			b.tokens--
+			b.remove(w)
+			l.mu.Unlock()
+			return nil
+		}
+		wake := b.wake
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			l.mu.Lock()
+			b.remove(w)
+			l.mu.Unlock()
+			return ctx.Err()
+		case <-timer.C:
+		case <-wake:
+			timer.Stop()
+		}
+		l.mu.Lock()
+	}
+}
+
+// Throttled records a provider 429 for account: the bucket is drained and nothing is
+// sent until retryAfter has passed.
+func (l *RESTLimiter) Throttled(account string, retryAfter time.Duration) {
+	if l == nil {
+		return
+	}
+	if retryAfter <= 0 {
+		retryAfter = time.Second
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.bucket(account)
+	now := l.now()
+	b.refill(now, l.cfg)
+	b.tokens = 0
+	if until := now.Add(retryAfter); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+	b.notify()
+}
+
+// Queued returns the number of requests waiting for account.
+func (l *RESTLimiter) Queued(account string) int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.buckets[account]; ok {
+		return len(b.queue)
+	}
+	return 0
+}
+
+func (l *RESTLimiter) bucket(account string) *restBucket {
+	b, ok := l.buckets[account]
+	if !ok {
+		b = &restBucket{tokens: float64(l.cfg.Burst), last: l.now(), wake: make(chan struct{})}
+		l.buckets[account] = b
+	}
+	return b
+}
+
+func (b *restBucket) refill(now time.Time, cfg RESTLimitConfig) {
+	if now.After(b.last) {
+		b.tokens = math.Min(float64(cfg.Burst), b.tokens+now.Sub(b.last).Seconds()*cfg.RatePerSecond)
+		b.last = now
+	}
+}
+
+// delay returns how long w must wait before re-checking; zero means send now.
+func (b *restBucket) delay(w *restWaiter, now time.Time, cfg RESTLimitConfig) time.Duration {
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now)
+	}
+	if b.queue[0] != w {
+		// Not at the head: woken when the queue changes; the timer is a safety net.
+		return time.Second
+	}
+	need := 1.0
+	if w.prio >= RESTPriorityBulk {
+		need += float64(cfg.BulkReserve)
+	}
+	if b.tokens >= need {
+		return 0
+	}
+	d := time.Duration((need - b.tokens) / cfg.RatePerSecond * float64(time.Second))
+	if d < time.Millisecond {
+		d = time.Millisecond
+	}
+	return d
+}
+
+func (b *restBucket) enqueue(w *restWaiter) {
+	i := len(b.queue)
+	for i > 0 && b.queue[i-1].prio > w.prio {
+		i--
+	}
+	b.queue = append(b.queue, nil)
+	copy(b.queue[i+1:], b.queue[i:])
+	b.queue[i] = w
+	b.notify()
+}
+
+func (b *restBucket) remove(w *restWaiter) {
+	for i, x := range b.queue {
+		if x == w {
+			b.queue = append(b.queue[:i], b.queue[i+1:]...)
+			b.notify()
+			return
+		}
+	}
+}
+
+func (b *restBucket) notify() {
+	close(b.wake)
+	b.wake = make(chan struct{})
+}
@@ -0,0 +1,88 @@
+package telephony
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRESTLimiter_CriticalJumpsBulkQueue(t *testing.T) {
+	l := NewRESTLimiter(RESTLimitConfig{RatePerSecond: 20, Burst: 1, BulkReserve: -1})
+	ctx := context.Background()
+	if err := l.Wait(ctx, "AC1", RESTPriorityCritical); err != nil {
+		t.Fatalf("first wait: %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	start := func(name string, prio RESTPriority) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.Wait(ctx, "AC1", prio); err != nil {
+				t.Errorf("%s: %v", name, err)
+				return
+			}
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}()
+	}
+	start("bulk1", RESTPriorityBulk)
+	start("bulk2", RESTPriorityBulk)
+	for l.Queued("AC1") < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	start("critical", RESTPriorityCritical)
+	wg.Wait()
+
+	if len(order) != 3 || order[0] != "critical" {
+		t.Fatalf("expected critical first, got %v", order)
+	}
+}
+
+func TestRESTLimiter_BulkLeavesReserve(t *testing.T) {
+	l := NewRESTLimiter(RESTLimitConfig{RatePerSecond: 1, Burst: 4, BulkReserve: 2})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(ctx, "AC1", RESTPriorityBulk); err != nil {
+			t.Fatalf("bulk %d: %v", i, err)
+		}
+	}
+	if err := l.Wait(ctx, "AC1", RESTPriorityBulk); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected bulk to wait for reserve, got %v", err)
+	}
+	if err := l.Wait(context.Background(), "AC1", RESTPriorityCritical); err != nil {
+		t.Fatalf("critical should use the reserve: %v", err)
+	}
+	if l.Queued("AC1") != 0 {
+		t.Fatalf("canceled waiter left in queue")
+	}
+}
+
+func TestRESTLimiter_ThrottledPausesAccount(t *testing.T) {
+	l := NewRESTLimiter(RESTLimitConfig{RatePerSecond: 100, Burst: 10, MaxQueue: 1})
+	l.Throttled("AC1", time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- l.Wait(ctx, "AC1", RESTPriorityCritical) }()
+	for l.Queued("AC1") < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	if err := l.Wait(context.Background(), "AC1", RESTPriorityCritical); !errors.Is(err, ErrRESTQueueFull) {
+		t.Fatalf("expected queue full, got %v", err)
+	}
+	if err := <-done; !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected paused account to block, got %v", err)
+	}
+	if err := l.Wait(context.Background(), "AC2", RESTPriorityBulk); err != nil {
+		t.Fatalf("other accounts are unaffected: %v", err)
+	}
+}
@@ -2,6 +2,8 @@ package telephony
 
 import (
 	"context"
+	"errors"
+	"strings"
 )
 
 // SIPProvider is a stub adapter for SIP trunk / gateway integrations.
@@ -16,18 +18,60 @@ import (
 // - Keep this adapter free of business logic.
 // - It should only translate SIP/FreeSWITCH boundary events into internal types and delegate decisions
 //   to internal/routing and internal/calls.
-type SIPProvider struct{}
+type SIPProvider struct {
+	// Trunks, when set, makes HealthCheck verify the secure path of enabled TLS
+	// trunks (see sip_trunk.go).
+	Trunks *SIPTrunkService
+	// Emergency authorizes emergency calls arriving on a trunk. Without it (or
+	// Trunks) such calls are rejected.
+	Emergency EmergencyCallAuthorizer
+}
 
 func (p *SIPProvider) Name() string { return "sip" }
 
 func (p *SIPProvider) HealthCheck(ctx context.Context) error {
+	if p.Trunks != nil {
+		return p.Trunks.CheckSecureTrunks(ctx)
+	}
 	return nil
 }
 
 func (p *SIPProvider) HandleInboundCall(ctx context.Context, req InboundCallRequest) (InboundCallResult, error) {
+	if err := p.authorizeTrunkEmergency(ctx, req); err != nil {
+		return InboundCallResult{}, err
+	}
 	return InboundCallResult{}, nil
 }
 
+// authorizeTrunkEmergency applies the trunk's emergency policy when a PBX dials an
+// emergency number over it. The number is checked against the global list and
+// the plans of the caller ID's calling code.
+func (p *SIPProvider) authorizeTrunkEmergency(ctx context.Context, req InboundCallRequest) error {
+	if req.TrunkID == "" {
+		return nil
+	}
+	emergency := IsEmergencyNumber(req.To, "")
+	for iso, plan := range DialPlans {
+		if strings.HasPrefix(req.From, "+"+plan.CallingCode) && IsEmergencyNumber(req.To, iso) {
+			emergency = true
+		}
+	}
+	if !emergency {
+		return nil
+	}
+	if p.Trunks == nil || p.Trunks.Store == nil || p.Emergency == nil {
+		return errors.New("telephony: trunk emergency policy not configured")
+	}
+	trunk, ok, err := p.Trunks.Store.GetSIPTrunk(ctx, req.WorkspaceID, req.TrunkID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTrunkNotFound
+	}
+	return p.Emergency.AuthorizeEmergencyCall(ctx, trunk, req.From)
+}
+
 func (p *SIPProvider) BuyNumber(ctx context.Context, req BuyNumberRequest) (BuyNumberResult, error) {
 	return BuyNumberResult{}, nil
 }
@@ -43,3 +87,9 @@ func (p *SIPProvider) StartRecording(ctx context.Context, req StartRecordingRequ
 func (p *SIPProvider) FetchCDR(ctx context.Context, req FetchCDRRequest) (FetchCDRResult, error) {
 	return FetchCDRResult{}, nil
 }
+
+// HangupCall is not available until ESL call control lands; it fails rather
+// than report a call as ended while it keeps running.
+func (p *SIPProvider) HangupCall(ctx context.Context, workspaceID, providerCallID, announcement string) error {
+	return errors.New("telephony: sip HangupCall not implemented")
+}
@@ -0,0 +1,90 @@
+package telephony
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Custom SIP headers.
+//
+// Calls to SIP destinations can carry call context (e.g. X-Campaign-ID,
+// X-Lead-ID) so the receiving PBX can act on it. Twilio sends custom headers
+// given as query parameters on the <Sip> URI, and delivers headers of calls it
+// receives as SipHeader_<name> webhook parameters. Only X- headers are passed
+// either way, up to MaxSIPHeaderBytes in total.
+
+// MaxSIPHeaderBytes is Twilio's limit on the custom headers of one <Sip> dial.
+const MaxSIPHeaderBytes = 1024
+
+const sipHeaderParamPrefix = "SipHeader_"
+
+// ValidSIPHeaderName reports whether name is a custom header (X- followed by
+// letters, digits or dashes).
+func ValidSIPHeaderName(name string) bool {
+	if len(name) < 3 || !strings.EqualFold(name[:2], "x-") {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// SIPHeadersSize is the byte size headers add to a dial (name=value pairs).
+func SIPHeadersSize(headers map[string]string) int {
+	n := 0
+	for k, v := range headers {
+		n += len(k) + 1 + len(url.QueryEscape(v)) + 1
+	}
+	return n
+}
+
+// sipURIWithHeaders appends headers to a SIP URI as query parameters, sorted
+// by name so the TwiML is stable.
+func sipURIWithHeaders(uri string, headers map[string]string) string {
+	if len(headers) == 0 {
+		return uri
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		if ValidSIPHeaderName(k) {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString(uri)
+	sep := "?"
+	if strings.Contains(uri, "?") {
+		sep = "&"
+	}
+	for _, k := range names {
+		b.WriteString(sep + k + "=" + url.QueryEscape(headers[k]))
+		sep = "&"
+	}
+	return b.String()
+}
+
+// parseSIPHeaders collects SipHeader_X-* webhook parameters.
+func parseSIPHeaders(form url.Values) map[string]string {
+	return prefixedSIPHeaders(form, sipHeaderParamPrefix)
+}
+
+// prefixedSIPHeaders collects the <prefix><X- header> parameters of q.
+func prefixedSIPHeaders(q url.Values, prefix string) map[string]string {
+	var out map[string]string
+	for k, v := range q {
+		name, ok := strings.CutPrefix(k, prefix)
+		if !ok || !ValidSIPHeaderName(name) || len(v) == 0 {
+			continue
+		}
+		if out == nil {
+			out = map[string]string{}
+		}
+		out[name] = v[0]
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package telephony
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSIPHeadersRoundTrip(t *testing.T) {
+	body := strings.NewReader("CallSid=CA1&From=sip%3Adialer%40example.com&To=%2B15557654321&SipHeader_X-Lead-ID=L42&SipHeader_User-Agent=pbx")
+	r := httptest.NewRequest(http.MethodPost, "/webhooks/twilio/voice", body)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	form, err := ParseTwilioInboundCall(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := form.ToInboundCallRequest("w1", time.Unix(1700000000, 0).UTC())
+	if len(in.SIPHeaders) != 1 || in.SIPHeaders["X-Lead-ID"] != "L42" {
+		t.Fatalf("expected only the X- header, got %v", in.SIPHeaders)
+	}
+
+	headers := map[string]string{"X-Lead-ID": "L42", "X-Campaign-ID": "c 1"}
+	xml, err := RenderTwiML(InboundCallResult{Action: InboundCallActionConnect, ConnectTo: "sip:agent@pbx.example.com", SIPHeaders: headers})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(xml, "<Sip>sip:agent@pbx.example.com?X-Campaign-ID=c+1&amp;X-Lead-ID=L42</Sip>") {
+		t.Fatalf("expected headers on the Sip URI: %s", xml)
+	}
+	xml, _ = RenderTwiML(InboundCallResult{Action: InboundCallActionConnect, ConnectTo: "+15550001", SIPHeaders: headers})
+	if strings.Contains(xml, "X-Lead-ID") {
+		t.Fatalf("expected no headers on a PSTN dial: %s", xml)
+	}
+
+	// Failover alternates keep the headers.
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	e.POST("/webhooks/twilio/dial-status", TwilioWebhookHandler{}.HandleDialStatus)
+	target := failoverActionURL("/webhooks/twilio/dial-status", []string{"sip:a@pbx.example.com", "sip:b@pbx.example.com"}, nil, headers)
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader("DialCallStatus=busy"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+	if xml := w.Body.String(); !strings.Contains(xml, "sip:a@pbx.example.com?X-Campaign-ID=c+1&amp;X-Lead-ID=L42") || !strings.Contains(xml, "hdr_X-Lead-ID=L42") {
+		t.Fatalf("expected headers on the re-dial and its action: %s", xml)
+	}
+}
+
+func TestValidSIPHeaderName(t *testing.T) {
+	for name, want := range map[string]bool{"X-Campaign-ID": true, "x-lead": true, "X-": false, "User-Agent": false, "X-Bad Name": false} {
+		if got := ValidSIPHeaderName(name); got != want {
+			t.Fatalf("%q: expected %v", name, want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package telephony
 
 import (
 	"context"
+	"errors"
 	"testing"
 )
 
@@ -29,3 +30,42 @@ func TestSIPProvider_EmptyMethods(t *testing.T) {
 		t.Fatalf("expected nil err, got %v", err)
 	}
 }
+
+type stubEmergencyAuthorizer struct {
+	err   error
+	calls []string
+}
+
+func (a *stubEmergencyAuthorizer) AuthorizeEmergencyCall(ctx context.Context, trunk SIPTrunk, callerID string) error {
+	a.calls = append(a.calls, trunk.ID+"|"+callerID)
+	return a.err
+}
+
+func TestSIPProvider_AppliesTrunkEmergencyPolicy(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemorySIPTrunkStore()
+	_ = store.PutSIPTrunk(ctx, SIPTrunk{ID: "t1", WorkspaceID: "w", Host: "pbx.example.com", Emergency: EmergencyBlock})
+	auth := &stubEmergencyAuthorizer{err: errors.New("blocked")}
+	p := &SIPProvider{Trunks: &SIPTrunkService{Store: store}, Emergency: auth}
+
+	if _, err := p.HandleInboundCall(ctx, InboundCallRequest{WorkspaceID: "w", TrunkID: "t1", From: "+14155550100", To: "+14155550199"}); err != nil || len(auth.calls) != 0 {
+		t.Fatalf("expected ordinary trunk call to pass unchecked, got err=%v calls=%v", err, auth.calls)
+	}
+	if _, err := p.HandleInboundCall(ctx, InboundCallRequest{WorkspaceID: "w", TrunkID: "t1", From: "+14155550100", To: "911"}); err == nil {
+		t.Fatalf("expected authorizer rejection to fail the call")
+	}
+	if _, err := p.HandleInboundCall(ctx, InboundCallRequest{WorkspaceID: "w", TrunkID: "t1", From: "+442071234567", To: "999"}); err == nil {
+		t.Fatalf("expected caller country emergency number to be checked")
+	}
+	if len(auth.calls) != 2 || auth.calls[0] != "t1|+14155550100" {
+		t.Fatalf("expected trunk and caller id passed to authorizer, got %v", auth.calls)
+	}
+
+	auth.err = nil
+	if _, err := p.HandleInboundCall(ctx, InboundCallRequest{WorkspaceID: "w", TrunkID: "t1", From: "+14155550100", To: "911"}); err != nil {
+		t.Fatalf("expected authorized emergency call, got %v", err)
+	}
+	if _, err := (&SIPProvider{}).HandleInboundCall(ctx, InboundCallRequest{WorkspaceID: "w", TrunkID: "t1", From: "+14155550100", To: "911"}); err == nil {
+		t.Fatalf("expected emergency call rejected without an authorizer")
+	}
+}
@@ -0,0 +1,301 @@
+package telephony
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SIP trunk transport and media security.
+//
+// Each trunk carries its signaling transport (udp, tcp or tls) and SRTP policy.
+// SRTP keys are exchanged in the SDP (SDES), so a trunk that requires SRTP must
+// also use TLS signaling. Dial targets built with SIPTrunk.DialURI carry the
+// policy as URI parameters (transport=tls; secure=true for SRTP), which the
+// provider adapters pass through unchanged.
+//
+// A secure trunk is only enabled after its secure path is verified: the TLS
+// handshake with the trunk host must succeed with a certificate valid for that
+// host. SIPProvider.HealthCheck repeats the check for enabled secure trunks.
+
+type SIPTransport string
+
+const (
+	SIPTransportUDP SIPTransport = "udp"
+	SIPTransportTCP SIPTransport = "tcp"
+	SIPTransportTLS SIPTransport = "tls"
+)
+
+type SRTPPolicy string
+
+const (
+	SRTPDisabled SRTPPolicy = "disabled"
+	SRTPOptional SRTPPolicy = "optional"
+	SRTPRequired SRTPPolicy = "required"
+)
+
+// EmergencyPolicy is how a trunk handles emergency calls from the PBX. It has no
+// default: every trunk must state it.
+type EmergencyPolicy string
+
+const (
+	// EmergencyBlock rejects emergency calls on the trunk; the PBX must reach
+	// emergency services another way.
+	EmergencyBlock EmergencyPolicy = "block"
+	// EmergencyRoute carries emergency calls; the caller ID presented must have a
+	// registered emergency address (see emergency.go).
+	EmergencyRoute EmergencyPolicy = "route"
+)
+
+// DefaultSIPTLSPort is used when a TLS trunk host has no port.
+const DefaultSIPTLSPort = "5061"
+
+var (
+	ErrInvalidTrunk  = errors.New("telephony: invalid sip trunk")
+	ErrTrunkNotFound = errors.New("telephony: sip trunk not found")
+	// ErrSecurePathFailed means the trunk's TLS handshake could not be verified.
+	ErrSecurePathFailed = errors.New("telephony: sip trunk secure path check failed")
+)
+
+// SIPTrunk is a workspace's SIP trunk to its PBX or carrier.
+type SIPTrunk struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	Name        string `json:"name" db:"name"`
+
+	// Host is the trunk's signaling address, host or host:port.
+	Host      string       `json:"host" db:"host"`
+	Transport SIPTransport `json:"transport" db:"transport"`
+	SRTP      SRTPPolicy   `json:"srtp" db:"srtp"`
+
+	Emergency EmergencyPolicy `json:"emergency_policy" db:"emergency_policy"`
+
+	Enabled bool `json:"enabled" db:"enabled"`
+	// SecureVerifiedAt is the last successful secure path check (TLS trunks).
+	SecureVerifiedAt *time.Time `json:"secure_verified_at,omitempty" db:"secure_verified_at"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Secure reports whether the trunk uses TLS signaling.
+func (t SIPTrunk) Secure() bool { return t.Transport == SIPTransportTLS }
+
+// Validate checks the trunk's transport and security settings.
+func (t SIPTrunk) Validate() error {
+	if t.WorkspaceID == "" || t.ID == "" || strings.TrimSpace(t.Name) == "" {
+		return ErrInvalidTrunk
+	}
+	host, port, err := net.SplitHostPort(t.Host)
+	if err != nil {
+		host, port = t.Host, ""
+	} else if _, perr := strconv.ParseUint(port, 10, 16); perr != nil {
+		host = ""
+	}
+	if host == "" || strings.ContainsAny(host, " /;?@:") {
+		return fmt.Errorf("%w: host must be host or host:port", ErrInvalidTrunk)
+	}
+	switch t.Transport {
+	case SIPTransportUDP, SIPTransportTCP, SIPTransportTLS:
+	default:
+		return fmt.Errorf("%w: unknown transport %q", ErrInvalidTrunk, t.Transport)
+	}
+	switch t.SRTP {
+	case SRTPDisabled, SRTPOptional:
+	case SRTPRequired:
+		if !t.Secure() {
+			return fmt.Errorf("%w: srtp required needs tls transport", ErrInvalidTrunk)
+		}
+	default:
+		return fmt.Errorf("%w: unknown srtp policy %q", ErrInvalidTrunk, t.SRTP)
+	}
+	switch t.Emergency {
+	case EmergencyBlock, EmergencyRoute:
+	default:
+		return fmt.Errorf("%w: emergency_policy must be block or route", ErrInvalidTrunk)
+	}
+	return nil
+}
+
+// DialURI is the SIP URI for calling user over the trunk.
+func (t SIPTrunk) DialURI(user string) string {
+	uri := "sip:" + user + "@" + t.Host
+	if t.Transport != "" && t.Transport != SIPTransportUDP {
+		uri += ";transport=" + string(t.Transport)
+	}
+	if t.SRTP == SRTPRequired {
+		uri += ";secure=true"
+	}
+	return uri
+}
+
+// SIPTrunkStore persists trunks. Implementations must enforce workspace filtering.
+type SIPTrunkStore interface {
+	GetSIPTrunk(ctx context.Context, workspaceID, id string) (SIPTrunk, bool, error)
+	ListSIPTrunks(ctx context.Context, workspaceID string) ([]SIPTrunk, error)
+	// ListEnabledSIPTrunks spans workspaces (health checks).
+	ListEnabledSIPTrunks(ctx context.Context) ([]SIPTrunk, error)
+	PutSIPTrunk(ctx context.Context, t SIPTrunk) error
+}
+
+// SecurePathProbe verifies a trunk's secure signaling path.
+type SecurePathProbe interface {
+	ProbeSecurePath(ctx context.Context, t SIPTrunk) error
+}
+
+// TLSProbe completes a TLS handshake with the trunk host and verifies its
+// certificate for that host.
+type TLSProbe struct {
+	// Config supplies root CAs (system roots when nil).
+	Config *tls.Config
+	// Timeout defaults to 5s.
+	Timeout time.Duration
+}
+
+func (p TLSProbe) ProbeSecurePath(ctx context.Context, t SIPTrunk) error {
+	addr := t.Host
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host, addr = t.Host, net.JoinHostPort(t.Host, DefaultSIPTLSPort)
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if p.Config != nil {
+		cfg = p.Config.Clone()
+	}
+	cfg.ServerName = host
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	d := tls.Dialer{NetDialer: &net.Dialer{Timeout: timeout}, Config: cfg}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSecurePathFailed, err)
+	}
+	return conn.Close()
+}
+
+// SIPTrunkService manages trunk settings and enabling.
+type SIPTrunkService struct {
+	Store SIPTrunkStore
+	Probe SecurePathProbe
+	Now   func() time.Time
+}
+
+// PutTrunk creates or updates a trunk. Changing a trunk's host, transport or
+// SRTP policy disables it until it is enabled (and verified) again.
+func (s *SIPTrunkService) PutTrunk(ctx context.Context, t SIPTrunk) (SIPTrunk, error) {
+	if err := t.Validate(); err != nil {
+		return SIPTrunk{}, err
+	}
+	now := s.now()
+	prev, found, err := s.Store.GetSIPTrunk(ctx, t.WorkspaceID, t.ID)
+	if err != nil {
+		return SIPTrunk{}, err
+	}
+	t.Enabled, t.SecureVerifiedAt, t.CreatedAt = false, nil, now
+	if found {
+		t.CreatedAt = prev.CreatedAt
+		if prev.Host == t.Host && prev.Transport == t.Transport && prev.SRTP == t.SRTP {
+			t.Enabled, t.SecureVerifiedAt = prev.Enabled, prev.SecureVerifiedAt
+		}
+	}
+	t.UpdatedAt = now
+	if err := s.Store.PutSIPTrunk(ctx, t); err != nil {
+		return SIPTrunk{}, err
+	}
+	return t, nil
+}
+
+// ListTrunks returns the workspace's trunks.
+func (s *SIPTrunkService) ListTrunks(ctx context.Context, workspaceID string) ([]SIPTrunk, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidTrunk
+	}
+	return s.Store.ListSIPTrunks(ctx, workspaceID)
+}
+
+// EnableTrunk verifies a secure trunk's path, then enables the trunk. A failed
+// check leaves the trunk disabled and returns ErrSecurePathFailed.
+func (s *SIPTrunkService) EnableTrunk(ctx context.Context, workspaceID, id string) (SIPTrunk, error) {
+	t, ok, err := s.Store.GetSIPTrunk(ctx, workspaceID, id)
+	if err != nil {
+		return SIPTrunk{}, err
+	}
+	if !ok {
+		return SIPTrunk{}, ErrTrunkNotFound
+	}
+	if err := t.Validate(); err != nil {
+		return SIPTrunk{}, err
+	}
+	now := s.now()
+	if t.Secure() {
+		if err := s.probe(ctx, t); err != nil {
+			return SIPTrunk{}, err
+		}
+		t.SecureVerifiedAt = &now
+	}
+	t.Enabled, t.UpdatedAt = true, now
+	if err := s.Store.PutSIPTrunk(ctx, t); err != nil {
+		return SIPTrunk{}, err
+	}
+	return t, nil
+}
+
+// DisableTrunk stops using a trunk.
+func (s *SIPTrunkService) DisableTrunk(ctx context.Context, workspaceID, id string) (SIPTrunk, error) {
+	t, ok, err := s.Store.GetSIPTrunk(ctx, workspaceID, id)
+	if err != nil {
+		return SIPTrunk{}, err
+	}
+	if !ok {
+		return SIPTrunk{}, ErrTrunkNotFound
+	}
+	t.Enabled, t.UpdatedAt = false, s.now()
+	if err := s.Store.PutSIPTrunk(ctx, t); err != nil {
+		return SIPTrunk{}, err
+	}
+	return t, nil
+}
+
+// CheckSecureTrunks re-verifies every enabled secure trunk; the error wraps
+// ErrSecurePathFailed and names the trunks that failed.
+func (s *SIPTrunkService) CheckSecureTrunks(ctx context.Context) error {
+	trunks, err := s.Store.ListEnabledSIPTrunks(ctx)
+	if err != nil {
+		return err
+	}
+	var failed []string
+	for _, t := range trunks {
+		if !t.Secure() {
+			continue
+		}
+		if err := s.probe(ctx, t); err != nil {
+			failed = append(failed, t.WorkspaceID+"/"+t.ID)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %s", ErrSecurePathFailed, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (s *SIPTrunkService) probe(ctx context.Context, t SIPTrunk) error {
+	p := s.Probe
+	if p == nil {
+		p = TLSProbe{}
+	}
+	return p.ProbeSecurePath(ctx, t)
+}
+
+func (s *SIPTrunkService) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
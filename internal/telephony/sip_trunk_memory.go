@@ -0,0 +1,54 @@
+package telephony
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemorySIPTrunkStore is a simple in-memory SIPTrunkStore useful for tests.
+// It is not intended for production use.
+
+type MemorySIPTrunkStore struct {
+	mu     sync.Mutex
+	trunks map[string]SIPTrunk
+}
+
+func NewMemorySIPTrunkStore() *MemorySIPTrunkStore {
+	return &MemorySIPTrunkStore{trunks: map[string]SIPTrunk{}}
+}
+
+func (s *MemorySIPTrunkStore) GetSIPTrunk(ctx context.Context, workspaceID, id string) (SIPTrunk, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.trunks[workspaceID+"|"+id]
+	return t, ok, nil
+}
+
+func (s *MemorySIPTrunkStore) ListSIPTrunks(ctx context.Context, workspaceID string) ([]SIPTrunk, error) {
+	return s.list(func(t SIPTrunk) bool { return t.WorkspaceID == workspaceID }), nil
+}
+
+func (s *MemorySIPTrunkStore) ListEnabledSIPTrunks(ctx context.Context) ([]SIPTrunk, error) {
+	return s.list(func(t SIPTrunk) bool { return t.Enabled }), nil
+}
+
+func (s *MemorySIPTrunkStore) PutSIPTrunk(ctx context.Context, t SIPTrunk) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trunks[t.WorkspaceID+"|"+t.ID] = t
+	return nil
+}
+
+func (s *MemorySIPTrunkStore) list(keep func(SIPTrunk) bool) []SIPTrunk {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []SIPTrunk
+	for _, t := range s.trunks {
+		if keep(t) {
+			out = append(out, t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].WorkspaceID+out[i].ID < out[j].WorkspaceID+out[j].ID })
+	return out
+}
@@ -0,0 +1,77 @@
+package telephony
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSIPTrunkValidateAndDialURI(t *testing.T) {
+	base := SIPTrunk{ID: "t1", WorkspaceID: "w", Name: "pbx", Host: "pbx.example.com", Transport: SIPTransportTLS, SRTP: SRTPRequired, Emergency: EmergencyBlock}
+	if err := base.Validate(); err != nil {
+		t.Fatalf("expected valid trunk, got %v", err)
+	}
+	if got := base.DialURI("agent"); got != "sip:agent@pbx.example.com;transport=tls;secure=true" {
+		t.Fatalf("unexpected dial uri %q", got)
+	}
+	insecure := base
+	insecure.Transport = SIPTransportUDP
+	if err := insecure.Validate(); !errors.Is(err, ErrInvalidTrunk) {
+		t.Fatalf("expected srtp required to need tls, got %v", err)
+	}
+	insecure.SRTP = SRTPDisabled
+	if got := insecure.DialURI("agent"); got != "sip:agent@pbx.example.com" {
+		t.Fatalf("unexpected dial uri %q", got)
+	}
+	bad := base
+	bad.Host = "sip:pbx.example.com"
+	if err := bad.Validate(); !errors.Is(err, ErrInvalidTrunk) {
+		t.Fatalf("expected invalid host, got %v", err)
+	}
+	bad = base
+	bad.Emergency = ""
+	if err := bad.Validate(); !errors.Is(err, ErrInvalidTrunk) {
+		t.Fatalf("expected emergency policy to be required, got %v", err)
+	}
+}
+
+func TestSIPTrunkEnableVerifiesSecurePath(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	svc := &SIPTrunkService{Store: NewMemorySIPTrunkStore(), Probe: TLSProbe{Config: &tls.Config{RootCAs: roots}}}
+	host := strings.TrimPrefix(srv.URL, "https://")
+	trunk, err := svc.PutTrunk(ctx, SIPTrunk{ID: "t1", WorkspaceID: "w", Name: "pbx", Host: host, Transport: SIPTransportTLS, SRTP: SRTPRequired, Emergency: EmergencyRoute})
+	if err != nil || trunk.Enabled {
+		t.Fatalf("expected stored disabled trunk, got %+v err=%v", trunk, err)
+	}
+	trunk, err = svc.EnableTrunk(ctx, "w", "t1")
+	if err != nil || !trunk.Enabled || trunk.SecureVerifiedAt == nil {
+		t.Fatalf("expected verified enabled trunk, got %+v err=%v", trunk, err)
+	}
+	p := &SIPProvider{Trunks: svc}
+	if err := p.HealthCheck(ctx); err != nil {
+		t.Fatalf("expected healthy, got %v", err)
+	}
+
+	// An untrusted certificate fails the check and keeps the trunk disabled.
+	svc.Probe = TLSProbe{Config: &tls.Config{RootCAs: x509.NewCertPool()}}
+	if err := p.HealthCheck(ctx); !errors.Is(err, ErrSecurePathFailed) {
+		t.Fatalf("expected health check failure, got %v", err)
+	}
+	_, _ = svc.DisableTrunk(ctx, "w", "t1")
+	if _, err := svc.EnableTrunk(ctx, "w", "t1"); !errors.Is(err, ErrSecurePathFailed) {
+		t.Fatalf("expected enable to fail, got %v", err)
+	}
+	if got, _, _ := svc.Store.GetSIPTrunk(ctx, "w", "t1"); got.Enabled {
+		t.Fatalf("expected trunk to stay disabled")
+	}
+}
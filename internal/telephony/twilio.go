@@ -11,12 +11,27 @@ import (
 // TODO: wire in Twilio REST client + credentials from config.
 type TwilioProvider struct {
 	router routing.Engine
+
+	// REST requests are paced per account; nil limiter means unpaced.
+	accountSID string
+	limiter    *RESTLimiter
 }
 
 func NewTwilioProvider(router routing.Engine) *TwilioProvider {
 	return &TwilioProvider{router: router}
 }
 
+// WithRESTLimiter paces REST requests for accountSID through l.
+func (p *TwilioProvider) WithRESTLimiter(accountSID string, l *RESTLimiter) *TwilioProvider {
+	p.accountSID = accountSID
+	p.limiter = l
+	return p
+}
+
+func (p *TwilioProvider) waitREST(ctx context.Context, prio RESTPriority) error {
+	return p.limiter.Wait(ctx, p.accountSID, prio)
+}
+
 func (p *TwilioProvider) Name() string { return "twilio" }
 
 func (p *TwilioProvider) HealthCheck(ctx context.Context) error {
@@ -32,17 +47,167 @@ func (p *TwilioProvider) HandleInboundCall(ctx context.Context, req InboundCallR
 }
 
 func (p *TwilioProvider) BuyNumber(ctx context.Context, req BuyNumberRequest) (BuyNumberResult, error) {
+	if err := p.waitREST(ctx, RESTPriorityNormal); err != nil {
+		return BuyNumberResult{}, err
+	}
 	return BuyNumberResult{}, errors.New("telephony: twilio BuyNumber not implemented")
 }
 
+// SearchNumbers lists AvailablePhoneNumbers. Searches are paginated and often
+// repeated, so they queue as bulk work behind call control.
+func (p *TwilioProvider) SearchNumbers(ctx context.Context, req SearchNumbersRequest) (SearchNumbersResult, error) {
+	if err := p.waitREST(ctx, RESTPriorityBulk); err != nil {
+		return SearchNumbersResult{}, err
+	}
+	return SearchNumbersResult{}, errors.New("telephony: twilio SearchNumbers not implemented")
+}
+
+// ConfigureNumber points the IncomingPhoneNumber's voice, status and recording
+// callbacks at this environment.
+func (p *TwilioProvider) ConfigureNumber(ctx context.Context, req ConfigureNumberRequest) error {
+	if req.WorkspaceID == "" || (req.Number == "" && req.ProviderNumberID == "") || req.VoiceURL == "" {
+		return errors.New("telephony: workspace_id, number and voice_url required")
+	}
+	if err := p.waitREST(ctx, RESTPriorityNormal); err != nil {
+		return err
+	}
+	return errors.New("telephony: twilio ConfigureNumber not implemented")
+}
+
+// GetNumberConfig reads the IncomingPhoneNumber's callback URLs and recording setting.
+func (p *TwilioProvider) GetNumberConfig(ctx context.Context, workspaceID, number, providerNumberID string) (ConfigureNumberRequest, error) {
+	if err := p.waitREST(ctx, RESTPriorityBulk); err != nil {
+		return ConfigureNumberRequest{}, err
+	}
+	return ConfigureNumberRequest{}, errors.New("telephony: twilio GetNumberConfig not implemented")
+}
+
+// ListProviderNumbers pages through the account's IncomingPhoneNumbers.
+func (p *TwilioProvider) ListProviderNumbers(ctx context.Context) ([]ProviderNumber, error) {
+	if err := p.waitREST(ctx, RESTPriorityBulk); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("telephony: twilio ListProviderNumbers not implemented")
+}
+
 func (p *TwilioProvider) ReleaseNumber(ctx context.Context, req ReleaseNumberRequest) (ReleaseNumberResult, error) {
+	if err := p.waitREST(ctx, RESTPriorityNormal); err != nil {
+		return ReleaseNumberResult{}, err
+	}
 	return ReleaseNumberResult{}, errors.New("telephony: twilio ReleaseNumber not implemented")
 }
 
 func (p *TwilioProvider) StartRecording(ctx context.Context, req StartRecordingRequest) (StartRecordingResult, error) {
+	if err := p.waitREST(ctx, RESTPriorityCritical); err != nil {
+		return StartRecordingResult{}, err
+	}
 	return StartRecordingResult{}, errors.New("telephony: twilio StartRecording not implemented")
 }
 
 func (p *TwilioProvider) FetchCDR(ctx context.Context, req FetchCDRRequest) (FetchCDRResult, error) {
+	if err := p.waitREST(ctx, RESTPriorityBulk); err != nil {
+		return FetchCDRResult{}, err
+	}
 	return FetchCDRResult{}, errors.New("telephony: twilio FetchCDR not implemented")
 }
+
+// SendSMS creates a Message resource. Pacing to carrier limits happens upstream
+// (see messaging's send queue); this only waits for REST capacity.
+func (p *TwilioProvider) SendSMS(ctx context.Context, req SendSMSRequest) (SendSMSResult, error) {
+	if req.WorkspaceID == "" || req.From == "" || req.To == "" || req.Body == "" {
+		return SendSMSResult{}, errors.New("telephony: workspace_id, from, to and body required")
+	}
+	if err := p.waitREST(ctx, RESTPriorityNormal); err != nil {
+		return SendSMSResult{}, err
+	}
+	return SendSMSResult{}, errors.New("telephony: twilio SendSMS not implemented")
+}
+
+// HangupCall redirects the live call to announced-hangup TwiML.
+// TODO: POST the TwiML to the Calls resource once the REST client is wired.
+func (p *TwilioProvider) HangupCall(ctx context.Context, workspaceID, providerCallID, announcement string) error {
+	if workspaceID == "" || providerCallID == "" {
+		return errors.New("telephony: workspace_id and provider_call_id required")
+	}
+	if _, err := RenderAnnouncedHangupTwiML(announcement); err != nil {
+		return err
+	}
+	if err := p.waitREST(ctx, RESTPriorityCritical); err != nil {
+		return err
+	}
+	return errors.New("telephony: twilio HangupCall not implemented")
+}
+
+// HoldCall redirects the remote party to looping hold music.
+// TODO: POST <Play loop="0"> TwiML to the Calls resource once the REST client is wired.
+func (p *TwilioProvider) HoldCall(ctx context.Context, workspaceID, providerCallID, musicURL string) error {
+	if workspaceID == "" || providerCallID == "" {
+		return errors.New("telephony: workspace_id and provider_call_id required")
+	}
+	if err := p.waitREST(ctx, RESTPriorityCritical); err != nil {
+		return err
+	}
+	return errors.New("telephony: twilio HoldCall not implemented")
+}
+
+// ResumeCall redirects the remote party back to the agent.
+// TODO: POST the reconnect TwiML to the Calls resource once the REST client is wired.
+func (p *TwilioProvider) ResumeCall(ctx context.Context, workspaceID, providerCallID string) error {
+	if workspaceID == "" || providerCallID == "" {
+		return errors.New("telephony: workspace_id and provider_call_id required")
+	}
+	if err := p.waitREST(ctx, RESTPriorityCritical); err != nil {
+		return err
+	}
+	return errors.New("telephony: twilio ResumeCall not implemented")
+}
+
+// JoinMonitor moves the call into a per-call conference and dials the
+// supervisor in as a participant: muted for listen, with coach set to the agent
+// leg for whisper, unmuted for barge.
+// TODO: redirect the call to <Dial><Conference> and create the participant once the REST client is wired.
+func (p *TwilioProvider) JoinMonitor(ctx context.Context, workspaceID, providerCallID, supervisorTarget, mode string) (string, error) {
+	if workspaceID == "" || providerCallID == "" || supervisorTarget == "" {
+		return "", errors.New("telephony: workspace_id, provider_call_id and supervisor target required")
+	}
+	if err := p.waitREST(ctx, RESTPriorityCritical); err != nil {
+		return "", err
+	}
+	return "", errors.New("telephony: twilio JoinMonitor not implemented")
+}
+
+// SetMonitorMode updates the supervisor participant's muted/coaching flags.
+// TODO: POST to the conference Participants resource once the REST client is wired.
+func (p *TwilioProvider) SetMonitorMode(ctx context.Context, workspaceID, providerCallID, legID, mode string) error {
+	if workspaceID == "" || providerCallID == "" || legID == "" {
+		return errors.New("telephony: workspace_id, provider_call_id and leg_id required")
+	}
+	if err := p.waitREST(ctx, RESTPriorityCritical); err != nil {
+		return err
+	}
+	return errors.New("telephony: twilio SetMonitorMode not implemented")
+}
+
+// LeaveMonitor removes the supervisor participant from the conference.
+// TODO: DELETE the conference participant once the REST client is wired.
+func (p *TwilioProvider) LeaveMonitor(ctx context.Context, workspaceID, providerCallID, legID string) error {
+	if workspaceID == "" || providerCallID == "" || legID == "" {
+		return errors.New("telephony: workspace_id, provider_call_id and leg_id required")
+	}
+	if err := p.waitREST(ctx, RESTPriorityCritical); err != nil {
+		return err
+	}
+	return errors.New("telephony: twilio LeaveMonitor not implemented")
+}
+
+// AnnounceToCaller plays message to the remote party's leg only.
+// TODO: use the conference announce URL once the REST client is wired.
+func (p *TwilioProvider) AnnounceToCaller(ctx context.Context, workspaceID, providerCallID, message string) error {
+	if workspaceID == "" || providerCallID == "" {
+		return errors.New("telephony: workspace_id and provider_call_id required")
+	}
+	if err := p.waitREST(ctx, RESTPriorityCritical); err != nil {
+		return err
+	}
+	return errors.New("telephony: twilio AnnounceToCaller not implemented")
+}
@@ -0,0 +1,134 @@
+package telephony
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"time"
+
+	"telecom-platform/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Inbound SMS.
+//
+// Like voice, the adapter only converts the provider webhook; keyword handling,
+// opt-outs and tenant forwarding live behind SMSResponder (internal/messaging).
+
+// InboundSMS is a provider-neutral inbound message.
+type InboundSMS struct {
+	WorkspaceID       string    `json:"workspace_id"`
+	ProviderMessageID string    `json:"provider_message_id"`
+	From              string    `json:"from"`
+	To                string    `json:"to"`
+	Body              string    `json:"body"`
+	ReceivedAt        time.Time `json:"received_at"`
+}
+
+// SMSResponder handles an inbound message and returns the auto-reply to send back
+// on the same thread ("" for none).
+type SMSResponder interface {
+	RespondSMS(ctx context.Context, msg InboundSMS) (reply string, err error)
+}
+
+// ParseTwilioInboundSMS reads the messaging webhook form (MessageSid, From, To, Body).
+func ParseTwilioInboundSMS(r *http.Request, workspaceID string, receivedAt time.Time) (InboundSMS, error) {
+	if err := r.ParseForm(); err != nil {
+		return InboundSMS{}, err
+	}
+	return InboundSMS{
+		WorkspaceID:       workspaceID,
+		ProviderMessageID: r.PostFormValue("MessageSid"),
+		From:              normalizePhone(r.PostFormValue("From")),
+		To:                normalizePhone(r.PostFormValue("To")),
+		Body:              r.PostFormValue("Body"),
+		ReceivedAt:        receivedAt.UTC(),
+	}, nil
+}
+
+type twimlMessage struct {
+	XMLName xml.Name `xml:"Message"`
+	Text    string   `xml:",chardata"`
+}
+
+// RenderMessageTwiML replies with reply, or returns an empty response when reply is "".
+func RenderMessageTwiML(reply string) (string, error) {
+	var r twimlResponse
+	if strings.TrimSpace(reply) != "" {
+		r.Verbs = append(r.Verbs, twimlMessage{Text: reply})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return "", err
+	}
+	if err := enc.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// TwilioSMSHandler serves the Twilio inbound messaging webhook.
+type TwilioSMSHandler struct {
+	Responder SMSResponder
+
+	// WorkspaceIDResolver resolves which workspace owns the receiving number.
+	WorkspaceIDResolver func(c *gin.Context, toNumber string) (string, error)
+
+	Now func() time.Time
+}
+
+func (h TwilioSMSHandler) HandleInboundSMS(c *gin.Context) {
+	log := logger.FromGin(c)
+
+	if h.Now == nil {
+		h.Now = time.Now
+	}
+	if h.Responder == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "sms responder not configured"})
+		return
+	}
+	if h.WorkspaceIDResolver == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "workspace resolver not configured"})
+		return
+	}
+	if err := c.Request.ParseForm(); err != nil {
+		log.Warn("twilio sms webhook parse failed", "err", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid form"})
+		return
+	}
+	to := normalizePhone(c.Request.PostFormValue("To"))
+	workspaceID, err := h.WorkspaceIDResolver(c, to)
+	if err != nil {
+		log.Warn("workspace resolution failed", "to", to, "err", err)
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "unknown destination"})
+		return
+	}
+	msg, err := ParseTwilioInboundSMS(c.Request, workspaceID, h.Now())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid form"})
+		return
+	}
+
+	reply, err := h.Responder.RespondSMS(c.Request.Context(), msg)
+	if err != nil {
+		// A 5xx makes Twilio retry; responders must tolerate seeing a message twice.
+		log.Error("inbound sms handling failed", "message_sid", msg.ProviderMessageID, "err", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "sms handling failed"})
+		return
+	}
+	twiml, err := RenderMessageTwiML(reply)
+	if err != nil {
+		log.Error("twiml render failed", "err", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "twiml failed"})
+		return
+	}
+	c.Header("Content-Type", "application/xml")
+	c.String(http.StatusOK, twiml)
+}
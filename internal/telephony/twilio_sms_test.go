@@ -0,0 +1,58 @@
+package telephony
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type stubSMSResponder struct {
+	got   InboundSMS
+	reply string
+}
+
+func (s *stubSMSResponder) RespondSMS(ctx context.Context, msg InboundSMS) (string, error) {
+	s.got = msg
+	return s.reply, nil
+}
+
+func TestTwilioSMSHandler_RepliesWithMessageTwiML(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	resp := &stubSMSResponder{reply: "You are unsubscribed & will get no more messages."}
+	h := TwilioSMSHandler{
+		Responder:           resp,
+		WorkspaceIDResolver: func(c *gin.Context, to string) (string, error) { return "ws-" + to, nil },
+	}
+	r := gin.New()
+	r.POST("/webhooks/twilio/sms", h.HandleInboundSMS)
+
+	body := strings.NewReader("MessageSid=SM1&From=%2B15551234567&To=%2B15557654321&Body=stop")
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/twilio/sms", body)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if resp.got.WorkspaceID != "ws-+15557654321" || resp.got.ProviderMessageID != "SM1" || resp.got.Body != "stop" || resp.got.From != "+15551234567" {
+		t.Fatalf("unexpected message: %+v", resp.got)
+	}
+	if !strings.Contains(w.Body.String(), "<Message>You are unsubscribed &amp; will get no more messages.</Message>") {
+		t.Fatalf("unexpected twiml: %s", w.Body.String())
+	}
+}
+
+func TestRenderMessageTwiML_EmptyReply(t *testing.T) {
+	out, err := RenderMessageTwiML(" ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "<Message>") || !strings.Contains(out, "<Response></Response>") {
+		t.Fatalf("expected empty response, got %s", out)
+	}
+}
@@ -33,6 +33,15 @@ type TwilioInboundForm struct {
 	ToZip        string
 	ToCountry    string
 	ForwardedFrom string
+
+	// CallDuration is set on the final status callback, in seconds.
+	CallDuration string
+
+	// Digits is set on <Gather> callbacks.
+	Digits string
+
+	// SIPHeaders are the SipHeader_X-* parameters of calls arriving over SIP.
+	SIPHeaders map[string]string
 }
 
 func ParseTwilioInboundCall(r *http.Request) (TwilioInboundForm, error) {
@@ -58,6 +67,9 @@ func ParseTwilioInboundCall(r *http.Request) (TwilioInboundForm, error) {
 		ToZip:         r.PostFormValue("ToZip"),
 		ToCountry:     r.PostFormValue("ToCountry"),
 		ForwardedFrom: normalizePhone(r.PostFormValue("ForwardedFrom")),
+		CallDuration:  r.PostFormValue("CallDuration"),
+		Digits:        strings.TrimSpace(r.PostFormValue("Digits")),
+		SIPHeaders:    parseSIPHeaders(r.PostForm),
 	}
 	return f, nil
 }
@@ -77,5 +89,6 @@ func (f TwilioInboundForm) ToInboundCallRequest(workspaceID string, occurredAt t
 		To:             f.To,
 		OccurredAt:     occurredAt,
 		RawPayload:     string(raw),
+		SIPHeaders:     f.SIPHeaders,
 	}
 }
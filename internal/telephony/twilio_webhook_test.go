@@ -1,11 +1,16 @@
 package telephony
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	"telecom-platform/internal/events"
+
+	"github.com/gin-gonic/gin"
 )
 
 func TestParseTwilioInboundCall(t *testing.T) {
@@ -35,3 +40,177 @@ func TestParseTwilioInboundCall(t *testing.T) {
 		t.Fatalf("expected from/to")
 	}
 }
+
+func TestHandleDialStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/webhooks/twilio/dial-status", TwilioWebhookHandler{}.HandleDialStatus)
+
+	post := func(target, status string) string {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader("CallSid=CA123&DialCallStatus="+status))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		return w.Body.String()
+	}
+
+	xml := post("/webhooks/twilio/dial-status?next=%2B15550002&next=%2B15550003", "no-answer")
+	if !strings.Contains(xml, "<Number>+15550002</Number>") || !strings.Contains(xml, `action="/webhooks/twilio/dial-status?next=%2B15550003"`) {
+		t.Fatalf("expected re-dial of next target with rest of chain: %s", xml)
+	}
+
+	xml = post("/webhooks/twilio/dial-status?next=%2B15550003", "busy")
+	if !strings.Contains(xml, "<Number>+15550003</Number>") || strings.Contains(xml, "action=") {
+		t.Fatalf("expected final re-dial without action: %s", xml)
+	}
+
+	for _, status := range []string{"completed", "no-answer"} {
+		target := "/webhooks/twilio/dial-status?next=%2B15550002"
+		if status == "no-answer" {
+			target = "/webhooks/twilio/dial-status"
+		}
+		if xml := post(target, status); !strings.Contains(xml, "<Hangup") {
+			t.Fatalf("expected hangup for %s: %s", status, xml)
+		}
+	}
+}
+
+type stubReleaser []string
+
+func (s *stubReleaser) Acquire(ctx context.Context, workspaceID, target, callID string, limit int) (bool, error) {
+	return true, nil
+}
+
+func (s *stubReleaser) Release(ctx context.Context, workspaceID, callID string) error {
+	*s = append(*s, workspaceID+"/"+callID)
+	return nil
+}
+
+func TestHandleCallStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	released := &stubReleaser{}
+	h := TwilioWebhookHandler{
+		Concurrency:         released,
+		WorkspaceIDResolver: func(c *gin.Context, to string) (string, error) { return "w", nil },
+	}
+	r := gin.New()
+	r.POST("/webhooks/twilio/status", h.HandleCallStatus)
+
+	for _, status := range []string{"ringing", "in-progress", "completed"} {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/twilio/status", strings.NewReader("CallSid=CA123&To=%2B15550001&CallStatus="+status))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("%s: expected 204, got %d", status, w.Code)
+		}
+	}
+	if len(*released) != 1 || (*released)[0] != "w/CA123" {
+		t.Fatalf("expected one release on completion, got %v", *released)
+	}
+}
+
+type recordingPublisher []events.Envelope
+
+func (p *recordingPublisher) Publish(ctx context.Context, e events.Envelope) (string, error) {
+	*p = append(*p, e)
+	return "1-0", nil
+}
+
+func TestHandleCallStatus_PublishesTerminalEventsWithStableIDs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	published := &recordingPublisher{}
+	h := TwilioWebhookHandler{
+		Events:              published,
+		WorkspaceIDResolver: func(c *gin.Context, to string) (string, error) { return "w", nil },
+	}
+	r := gin.New()
+	r.POST("/webhooks/twilio/status", h.HandleCallStatus)
+	post := func(body string) {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/twilio/status", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("expected 204, got %d", w.Code)
+		}
+	}
+
+	// Twilio retries the same callback; both deliveries carry the same id.
+	post("CallSid=CA1&To=%2B15550001&CallStatus=completed&CallDuration=42")
+	post("CallSid=CA1&To=%2B15550001&CallStatus=completed&CallDuration=42")
+	post("CallSid=CA2&To=%2B15550001&CallStatus=busy")
+	if len(*published) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(*published))
+	}
+	first, retry, failed := (*published)[0], (*published)[1], (*published)[2]
+	if first.Type != events.TypeCallCompleted || first.ID == "" || first.ID != retry.ID {
+		t.Fatalf("expected completed events with one stable id, got %+v / %+v", first, retry)
+	}
+	if p, _ := first.CallPayload(); p.DurationSeconds != 42 {
+		t.Fatalf("expected duration 42, got %+v", p)
+	}
+	if failed.Type != events.TypeCallFailed || failed.ID == first.ID {
+		t.Fatalf("expected distinct call.failed event, got %+v", failed)
+	}
+	if p, _ := failed.CallPayload(); p.FailureReason != "busy" {
+		t.Fatalf("expected failure reason busy, got %+v", p)
+	}
+	if callEventID("CA1", events.TypeCallInbound) == first.ID {
+		t.Fatalf("expected event id to depend on the event type")
+	}
+}
+
+// memorySlots is a minimal SlotStore for failover tests.
+type memorySlots struct {
+	inUse map[string]int
+	held  map[string][]string
+}
+
+func (s *memorySlots) Acquire(ctx context.Context, workspaceID, target, callID string, limit int) (bool, error) {
+	if s.inUse[target] >= limit {
+		return false, nil
+	}
+	s.inUse[target]++
+	s.held[callID] = append(s.held[callID], target)
+	return true, nil
+}
+
+func (s *memorySlots) Release(ctx context.Context, workspaceID, callID string) error {
+	for _, target := range s.held[callID] {
+		s.inUse[target]--
+	}
+	delete(s.held, callID)
+	return nil
+}
+
+func TestHandleDialStatus_MovesSlotToAlternate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	slots := &memorySlots{inUse: map[string]int{"+15550001": 1, "+15550002": 1}, held: map[string][]string{"CA1": {"+15550001"}}}
+	h := TwilioWebhookHandler{
+		Concurrency:         slots,
+		WorkspaceIDResolver: func(c *gin.Context, to string) (string, error) { return "w", nil },
+	}
+	r := gin.New()
+	r.POST("/webhooks/twilio/dial-status", h.HandleDialStatus)
+
+	// +15550002 is full (limit 1, held by another call); +15550003 has room.
+	target := "/webhooks/twilio/dial-status?next=%2B15550002&next=%2B15550003&next=%2B15550004&max=1&max=2&max=0"
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader("CallSid=CA1&To=%2B15559999&DialCallStatus=busy"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	xml := w.Body.String()
+	if w.Code != http.StatusOK || !strings.Contains(xml, "<Number>+15550003</Number>") {
+		t.Fatalf("expected dial of first alternate with room, got %d: %s", w.Code, xml)
+	}
+	if !strings.Contains(xml, "next=%2B15550004") || !strings.Contains(xml, "max=0") {
+		t.Fatalf("expected rest of chain with limits on action url: %s", xml)
+	}
+	if slots.inUse["+15550001"] != 0 || slots.inUse["+15550003"] != 1 || slots.inUse["+15550002"] != 1 {
+		t.Fatalf("expected slot moved from primary to alternate, got %v", slots.inUse)
+	}
+}
@@ -26,16 +26,47 @@ type twimlHangup struct {
 	XMLName xml.Name `xml:"Hangup"`
 }
 
+type twimlSay struct {
+	XMLName  xml.Name `xml:"Say"`
+	Language string   `xml:"language,attr,omitempty"`
+	Text     string   `xml:",chardata"`
+}
+
+type twimlPlay struct {
+	XMLName xml.Name `xml:"Play"`
+	URL     string   `xml:",chardata"`
+}
+
 type twimlDial struct {
 	XMLName xml.Name `xml:"Dial"`
-	Number  string   `xml:"Number,omitempty"`
-	Sip     *twimlSip `xml:"Sip,omitempty"`
+
+	// Action receives the dial outcome (DialCallStatus) for failover.
+	Action string    `xml:"action,attr,omitempty"`
+	Method string    `xml:"method,attr,omitempty"`
+	Number string    `xml:"Number,omitempty"`
+	Sip    *twimlSip `xml:"Sip,omitempty"`
 }
 
 type twimlSip struct {
 	URI string `xml:",chardata"`
 }
 
+type twimlGather struct {
+	XMLName   xml.Name `xml:"Gather"`
+	Input     string   `xml:"input,attr"`
+	NumDigits int      `xml:"numDigits,attr,omitempty"`
+	Timeout   int      `xml:"timeout,attr,omitempty"`
+	Action    string   `xml:"action,attr"`
+	Method    string   `xml:"method,attr"`
+	Verbs     []any    `xml:",any"`
+}
+
+type twimlRedirect struct {
+	XMLName xml.Name `xml:"Redirect"`
+	Method  string   `xml:"method,attr"`
+	URL     string   `xml:",chardata"`
+}
+
 // RenderTwiML maps an InboundCallResult to TwiML.
 func RenderTwiML(res InboundCallResult) (string, error) {
 	var r twimlResponse
@@ -44,19 +75,51 @@ func RenderTwiML(res InboundCallResult) (string, error) {
 	case InboundCallActionReject:
 		r.Verbs = append(r.Verbs, twimlReject{Reason: "busy"})
 	case InboundCallActionHangup:
+		if strings.TrimSpace(res.Announcement) != "" {
+			r.Verbs = append(r.Verbs, twimlSay{Language: res.Language, Text: res.Announcement})
+		}
 		r.Verbs = append(r.Verbs, twimlHangup{})
 	case InboundCallActionConnect:
 		if strings.TrimSpace(res.ConnectTo) == "" {
 			return "", errors.New("telephony: connect_to required for connect action")
 		}
 		d := twimlDial{}
+		if res.DialActionURL != "" {
+			d.Action, d.Method = res.DialActionURL, "POST"
+		}
 		// Prefer SIP if it looks like sip:... otherwise treat as a PSTN number.
 		if strings.HasPrefix(strings.ToLower(res.ConnectTo), "sip:") {
-			d.Sip = &twimlSip{URI: res.ConnectTo}
+			d.Sip = &twimlSip{URI: sipURIWithHeaders(res.ConnectTo, res.SIPHeaders)}
 		} else {
 			d.Number = res.ConnectTo
 		}
 		r.Verbs = append(r.Verbs, d)
+	case InboundCallActionGather:
+		if res.Gather == nil || res.Gather.Step == "" || res.Gather.ActionURL == "" {
+			return "", errors.New("telephony: gather step and action url required for gather action")
+		}
+		g := twimlGather{
+			Input:     "dtmf",
+			NumDigits: res.Gather.NumDigits,
+			Timeout:   res.Gather.TimeoutSeconds,
+			Action:    res.Gather.ActionURL,
+			Method:    "POST",
+		}
+		switch {
+		case len(res.Gather.Segments) > 0:
+			for _, seg := range res.Gather.Segments {
+				if seg.AudioURL != "" {
+					g.Verbs = append(g.Verbs, twimlPlay{URL: seg.AudioURL})
+				} else if strings.TrimSpace(seg.Text) != "" {
+					g.Verbs = append(g.Verbs, twimlSay{Language: seg.Language, Text: seg.Text})
+				}
+			}
+		case strings.TrimSpace(res.Gather.Prompt) != "":
+			g.Verbs = append(g.Verbs, twimlSay{Language: res.Gather.Language, Text: res.Gather.Prompt})
+		}
+		// Without input Twilio falls through to the next verb; the redirect reports
+		// the step as asked-but-empty so routing can fall back instead of re-prompting.
+		r.Verbs = append(r.Verbs, g, twimlRedirect{Method: "POST", URL: res.Gather.ActionURL})
 	default:
 		return "", errors.New("telephony: unknown inbound action")
 	}
@@ -73,3 +136,25 @@ func RenderTwiML(res InboundCallResult) (string, error) {
 	}
 	return buf.String(), nil
 }
+
+// RenderAnnouncedHangupTwiML speaks announcement (if any) and then hangs up.
+// Used to redirect a live call, e.g. when its wallet runs out of funds.
+func RenderAnnouncedHangupTwiML(announcement string) (string, error) {
+	var r twimlResponse
+	if strings.TrimSpace(announcement) != "" {
+		r.Verbs = append(r.Verbs, twimlSay{Text: announcement})
+	}
+	r.Verbs = append(r.Verbs, twimlHangup{})
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return "", err
+	}
+	if err := enc.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -22,6 +22,43 @@ func TestRenderTwiMLConnectRequiresTarget(t *testing.T) {
 	}
 }
 
+func TestRenderAnnouncedHangupTwiML(t *testing.T) {
+	xml, err := RenderAnnouncedHangupTwiML("Your balance is exhausted. Goodbye.")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	say, hang := indexOf(xml, "<Say>"), indexOf(xml, "<Hangup>")
+	if say < 0 || hang < 0 || say > hang {
+		t.Fatalf("expected Say before Hangup: %s", xml)
+	}
+}
+
+func TestRenderTwiMLGather(t *testing.T) {
+	xml, err := RenderTwiML(InboundCallResult{WorkspaceID: "w", Action: InboundCallActionGather, Gather: &GatherPrompt{
+		Step: "zip", Prompt: "Enter your ZIP code.", NumDigits: 5, ActionURL: "/webhooks/twilio/voice?step=zip",
+	}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	gather, redirect := indexOf(xml, "<Gather"), indexOf(xml, "<Redirect")
+	if gather < 0 || redirect < 0 || gather > redirect || !contains(xml, `numDigits="5"`) {
+		t.Fatalf("expected Gather then Redirect fallback: %s", xml)
+	}
+	if _, err := RenderTwiML(InboundCallResult{WorkspaceID: "w", Action: InboundCallActionGather, Gather: &GatherPrompt{Step: "zip"}}); err == nil {
+		t.Fatalf("expected error without action url")
+	}
+}
+
+func TestRenderTwiMLDialAction(t *testing.T) {
+	xml, err := RenderTwiML(InboundCallResult{WorkspaceID: "w", Action: InboundCallActionConnect, ConnectTo: "+15550001", DialActionURL: "/webhooks/twilio/dial-status?next=%2B15550002"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !contains(xml, `action="/webhooks/twilio/dial-status?next=%2B15550002"`) || !contains(xml, `method="POST"`) {
+		t.Fatalf("expected Dial action: %s", xml)
+	}
+}
+
 func contains(s, sub string) bool {
 	return len(sub) == 0 || (len(s) >= len(sub) && (func() bool { return indexOf(s, sub) >= 0 })())
 }
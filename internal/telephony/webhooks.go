@@ -0,0 +1,112 @@
+package telephony
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// Provider webhook URLs.
+//
+// Each environment has its own public base URL (config PUBLIC_BASE_URL). Numbers are
+// pointed at that environment programmatically through NumberConfigurer instead of
+// manual console setup, so staging and production numbers never cross-deliver.
+
+// NumberConfigurer is implemented by providers that can set a number's webhooks.
+// It is kept out of TelephonyProvider so adapters can adopt it incrementally.
+type NumberConfigurer interface {
+	ConfigureNumber(ctx context.Context, req ConfigureNumberRequest) error
+}
+
+// NumberConfigReader is implemented by providers that can report a number's current
+// webhook configuration (used for drift detection).
+type NumberConfigReader interface {
+	GetNumberConfig(ctx context.Context, workspaceID, number, providerNumberID string) (ConfigureNumberRequest, error)
+}
+
+// NumberLister is implemented by providers that can list every number on the
+// account (used for drift detection).
+type NumberLister interface {
+	ListProviderNumbers(ctx context.Context) ([]ProviderNumber, error)
+}
+
+// ProviderNumber is a number as the provider account sees it.
+type ProviderNumber struct {
+	Number           string `json:"number"`
+	ProviderNumberID string `json:"provider_number_id,omitempty"`
+}
+
+type ConfigureNumberRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+
+	Number           string `json:"number"`
+	ProviderNumberID string `json:"provider_number_id,omitempty"`
+
+	VoiceURL             string `json:"voice_url"`
+	StatusCallbackURL    string `json:"status_callback_url"`
+	RecordingCallbackURL string `json:"recording_callback_url"`
+	SMSURL               string `json:"sms_url,omitempty"`
+
+	// RecordingEnabled is the provider's own record-every-call setting. The
+	// platform starts recordings per call (StartRecording) after its consent
+	// checks, so it always configures this off.
+	RecordingEnabled bool `json:"recording_enabled"`
+}
+
+// SameWebhooks reports whether r and o point at the same callback URLs.
+func (r ConfigureNumberRequest) SameWebhooks(o ConfigureNumberRequest) bool {
+	return r.VoiceURL == o.VoiceURL && r.StatusCallbackURL == o.StatusCallbackURL &&
+		r.RecordingCallbackURL == o.RecordingCallbackURL && r.SMSURL == o.SMSURL
+}
+
+// WebhookURLs builds provider callback URLs under an environment's public base URL.
+type WebhookURLs struct {
+	BaseURL string
+}
+
+// NewWebhookURLs validates base (absolute http(s), no query).
+func NewWebhookURLs(base string) (WebhookURLs, error) {
+	base = strings.TrimRight(strings.TrimSpace(base), "/")
+	u, err := url.Parse(base)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") || u.RawQuery != "" {
+		return WebhookURLs{}, errors.New("telephony: webhook base url must be an absolute http(s) url")
+	}
+	return WebhookURLs{BaseURL: base}, nil
+}
+
+// Configured reports whether a base URL is set.
+func (w WebhookURLs) Configured() bool { return w.BaseURL != "" }
+
+// Voice is the inbound call webhook for provider (e.g. /webhooks/twilio/voice).
+func (w WebhookURLs) Voice(provider string) string { return w.path(provider, "voice") }
+
+// Status receives call progress callbacks.
+func (w WebhookURLs) Status(provider string) string { return w.path(provider, "status") }
+
+// Recording receives recording-ready callbacks.
+func (w WebhookURLs) Recording(provider string) string { return w.path(provider, "recording") }
+
+// SMS receives inbound messages.
+func (w WebhookURLs) SMS(provider string) string { return w.path(provider, "sms") }
+
+// ConfigureRequest fills the webhook URLs for a number. The SMS URL is only set
+// for messaging-capable numbers.
+func (w WebhookURLs) ConfigureRequest(provider, workspaceID, number, providerNumberID string, messaging bool) ConfigureNumberRequest {
+	req := ConfigureNumberRequest{
+		WorkspaceID:          workspaceID,
+		Number:               number,
+		ProviderNumberID:     providerNumberID,
+		VoiceURL:             w.Voice(provider),
+		StatusCallbackURL:    w.Status(provider),
+		RecordingCallbackURL: w.Recording(provider),
+	}
+	if messaging {
+		req.SMSURL = w.SMS(provider)
+	}
+	return req
+}
+
+func (w WebhookURLs) path(provider, event string) string {
+	return w.BaseURL + "/webhooks/" + url.PathEscape(provider) + "/" + event
+}
@@ -0,0 +1,63 @@
+package tracking
+
+import "time"
+
+// Attribution is the marketing source of a visit: UTM parameters and ad click ids.
+type Attribution struct {
+	Source   string `json:"source"`
+	Medium   string `json:"medium"`
+	Campaign string `json:"campaign,omitempty"`
+	Term     string `json:"term,omitempty"`
+	Content  string `json:"content,omitempty"`
+
+	// GCLID/FBCLID are the Google Ads and Meta click ids from the landing URL.
+	GCLID  string `json:"gclid,omitempty"`
+	FBCLID string `json:"fbclid,omitempty"`
+
+	LandingURL string `json:"landing_url,omitempty"`
+	Referrer   string `json:"referrer,omitempty"`
+}
+
+// Session is one website visit that was shown a tracking number.
+type Session struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	PoolID      string `json:"pool_id" db:"pool_id"`
+	VisitorID   string `json:"visitor_id" db:"visitor_id"`
+
+	// TrackingNumber is the E.164 number displayed to the visitor.
+	TrackingNumber string `json:"tracking_number" db:"tracking_number"`
+
+	Attribution Attribution `json:"attribution" db:"attribution"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	// ExpiresAt ends the window in which a call to TrackingNumber is credited to
+	// this session.
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+}
+
+// StartSessionRequest is sent by the number-insertion script on page load.
+// Explicit UTM fields override those parsed from LandingURL.
+type StartSessionRequest struct {
+	PoolID     string `json:"pool_id"`
+	VisitorID  string `json:"visitor_id"`
+	LandingURL string `json:"landing_url"`
+	Referrer   string `json:"referrer,omitempty"`
+
+	Source   string `json:"utm_source,omitempty"`
+	Medium   string `json:"utm_medium,omitempty"`
+	Campaign string `json:"utm_campaign,omitempty"`
+	Term     string `json:"utm_term,omitempty"`
+	Content  string `json:"utm_content,omitempty"`
+	GCLID    string `json:"gclid,omitempty"`
+	FBCLID   string `json:"fbclid,omitempty"`
+}
+
+// CallAttribution links a call to the session whose tracking number it dialed.
+type CallAttribution struct {
+	WorkspaceID string      `json:"workspace_id" db:"workspace_id"`
+	CallID      string      `json:"call_id" db:"call_id"`
+	SessionID   string      `json:"session_id" db:"session_id"`
+	Attribution Attribution `json:"attribution" db:"attribution"`
+	MatchedAt   time.Time   `json:"matched_at" db:"matched_at"`
+}
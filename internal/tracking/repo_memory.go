@@ -0,0 +1,76 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a simple in-memory Store useful for tests.
+// It is not intended for production use.
+
+type MemoryStore struct {
+	mu           sync.Mutex
+	sessions     []Session
+	attributions map[string]CallAttribution // key: workspace_id|call_id
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{attributions: map[string]CallAttribution{}}
+}
+
+func (s *MemoryStore) CreateSession(ctx context.Context, sess Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = append(s.sessions, sess)
+	return nil
+}
+
+func (s *MemoryStore) LatestSession(ctx context.Context, workspaceID, number string, at time.Time) (Session, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out Session
+	found := false
+	for _, sess := range s.sessions {
+		if sess.WorkspaceID != workspaceID || sess.TrackingNumber != number {
+			continue
+		}
+		if sess.CreatedAt.After(at) || !sess.ExpiresAt.After(at) {
+			continue
+		}
+		if !found || sess.CreatedAt.After(out.CreatedAt) {
+			out, found = sess, true
+		}
+	}
+	return out, found, nil
+}
+
+func (s *MemoryStore) PutCallAttribution(ctx context.Context, a CallAttribution) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := a.WorkspaceID + "|" + a.CallID
+	if _, ok := s.attributions[key]; ok {
+		return false, nil
+	}
+	s.attributions[key] = a
+	return true, nil
+}
+
+func (s *MemoryStore) GetCallAttribution(ctx context.Context, workspaceID, callID string) (CallAttribution, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.attributions[workspaceID+"|"+callID]
+	return a, ok, nil
+}
+
+func (s *MemoryStore) ListCallAttributions(ctx context.Context, workspaceID string, callIDs []string) ([]CallAttribution, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]CallAttribution, 0, len(callIDs))
+	for _, id := range callIDs {
+		if a, ok := s.attributions[workspaceID+"|"+id]; ok {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,217 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/numbers"
+
+	"github.com/google/uuid"
+)
+
+// Visit tracking for call attribution (dynamic number insertion).
+//
+// A script on the advertiser's site starts a Session on page load: it sends the
+// landing URL (with its utm_* and gclid/fbclid parameters) and gets back a number
+// from a tracking pool to display. When a call arrives on that number within
+// SessionTTL, AttributeCall credits it to the most recent session that was shown
+// the number, and reporting joins calls to their attribution by call id.
+//
+// Visits without UTM parameters are classified the way web analytics does: a
+// click id implies the ad network (google/cpc, facebook/paid_social), an external
+// referrer is <host>/referral, and anything else is (direct)/(none).
+
+// Store persists sessions and call attributions. Implementations must enforce
+// workspace filtering.
+type Store interface {
+	CreateSession(ctx context.Context, s Session) error
+	// LatestSession returns the newest session shown number that was live at t.
+	LatestSession(ctx context.Context, workspaceID, number string, at time.Time) (Session, bool, error)
+
+	// PutCallAttribution returns false when the call is already attributed.
+	PutCallAttribution(ctx context.Context, a CallAttribution) (bool, error)
+	GetCallAttribution(ctx context.Context, workspaceID, callID string) (CallAttribution, bool, error)
+	ListCallAttributions(ctx context.Context, workspaceID string, callIDs []string) ([]CallAttribution, error)
+}
+
+// NumberPicker hands out tracking numbers; numbers.Service implements it.
+type NumberPicker interface {
+	NextNumber(ctx context.Context, workspaceID, poolID, key string) (numbers.PoolNumber, error)
+}
+
+var ErrInvalidArgument = errors.New("tracking: invalid argument")
+
+type Service struct {
+	store   Store
+	numbers NumberPicker
+	clock   func() time.Time
+
+	// SessionTTL is how long after a visit a call to its number is attributed to it.
+	SessionTTL time.Duration
+}
+
+func NewService(store Store, picker NumberPicker) *Service {
+	return &Service{store: store, numbers: picker, clock: time.Now, SessionTTL: 30 * time.Minute}
+}
+
+// StartSession records a visit and assigns it a number from the pool. The pool's
+// rotation strategy decides the number; sticky pools keep a visitor on one number.
+func (s *Service) StartSession(ctx context.Context, workspaceID string, req StartSessionRequest) (Session, error) {
+	req.VisitorID = strings.TrimSpace(req.VisitorID)
+	if workspaceID == "" || req.PoolID == "" || req.VisitorID == "" {
+		return Session{}, ErrInvalidArgument
+	}
+	attr, err := ParseAttribution(req)
+	if err != nil {
+		return Session{}, err
+	}
+	n, err := s.numbers.NextNumber(ctx, workspaceID, req.PoolID, req.VisitorID)
+	if err != nil {
+		return Session{}, err
+	}
+	now := s.clock().UTC()
+	sess := Session{
+		ID:             uuid.NewString(),
+		WorkspaceID:    workspaceID,
+		PoolID:         req.PoolID,
+		VisitorID:      req.VisitorID,
+		TrackingNumber: n.Number,
+		Attribution:    attr,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(s.SessionTTL),
+	}
+	if err := s.store.CreateSession(ctx, sess); err != nil {
+		return Session{}, err
+	}
+	return sess, nil
+}
+
+// AttributeCall credits a call to calledNumber at startedAt to the latest live
+// session shown that number. It reports false when no session matches. A call is
+// attributed once; repeating returns the stored attribution.
+func (s *Service) AttributeCall(ctx context.Context, workspaceID, callID, calledNumber string, startedAt time.Time) (CallAttribution, bool, error) {
+	if workspaceID == "" || callID == "" || calledNumber == "" {
+		return CallAttribution{}, false, ErrInvalidArgument
+	}
+	if existing, ok, err := s.store.GetCallAttribution(ctx, workspaceID, callID); err != nil || ok {
+		return existing, ok, err
+	}
+	sess, ok, err := s.store.LatestSession(ctx, workspaceID, calledNumber, startedAt)
+	if err != nil || !ok {
+		return CallAttribution{}, false, err
+	}
+	a := CallAttribution{
+		WorkspaceID: workspaceID,
+		CallID:      callID,
+		SessionID:   sess.ID,
+		Attribution: sess.Attribution,
+		MatchedAt:   s.clock().UTC(),
+	}
+	created, err := s.store.PutCallAttribution(ctx, a)
+	if err != nil {
+		return CallAttribution{}, false, err
+	}
+	if !created {
+		// Lost a race with a concurrent attribution of the same call.
+		return s.store.GetCallAttribution(ctx, workspaceID, callID)
+	}
+	return a, true, nil
+}
+
+// CallAttributions returns the attribution of each attributed call, keyed by call
+// id. Reporting uses it to join calls to their sources.
+func (s *Service) CallAttributions(ctx context.Context, workspaceID string, callIDs []string) (map[string]Attribution, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	out := map[string]Attribution{}
+	if len(callIDs) == 0 {
+		return out, nil
+	}
+	rows, err := s.store.ListCallAttributions(ctx, workspaceID, callIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range rows {
+		out[a.CallID] = a.Attribution
+	}
+	return out, nil
+}
+
+// ParseAttribution builds a visit's attribution from its landing URL, referrer
+// and any explicit fields in req.
+func ParseAttribution(req StartSessionRequest) (Attribution, error) {
+	a := Attribution{LandingURL: strings.TrimSpace(req.LandingURL), Referrer: strings.TrimSpace(req.Referrer)}
+	var landingHost string
+	if a.LandingURL != "" {
+		u, err := url.Parse(a.LandingURL)
+		if err != nil {
+			return Attribution{}, ErrInvalidArgument
+		}
+		landingHost = hostOf(a.LandingURL)
+		q := u.Query()
+		a.Source = q.Get("utm_source")
+		a.Medium = q.Get("utm_medium")
+		a.Campaign = q.Get("utm_campaign")
+		a.Term = q.Get("utm_term")
+		a.Content = q.Get("utm_content")
+		a.GCLID = q.Get("gclid")
+		a.FBCLID = q.Get("fbclid")
+	}
+	override(&a.Source, req.Source)
+	override(&a.Medium, req.Medium)
+	override(&a.Campaign, req.Campaign)
+	override(&a.Term, req.Term)
+	override(&a.Content, req.Content)
+	override(&a.GCLID, req.GCLID)
+	override(&a.FBCLID, req.FBCLID)
+	a.Source = strings.ToLower(strings.TrimSpace(a.Source))
+	a.Medium = strings.ToLower(strings.TrimSpace(a.Medium))
+
+	if a.Source == "" {
+		switch {
+		case a.GCLID != "":
+			a.Source, a.Medium = "google", orDefault(a.Medium, "cpc")
+		case a.FBCLID != "":
+			a.Source, a.Medium = "facebook", orDefault(a.Medium, "paid_social")
+		default:
+			if host := hostOf(a.Referrer); host != "" && host != landingHost {
+				a.Source, a.Medium = host, orDefault(a.Medium, "referral")
+			} else {
+				a.Source, a.Medium = "(direct)", orDefault(a.Medium, "(none)")
+			}
+		}
+	}
+	if a.Medium == "" {
+		a.Medium = "(none)"
+	}
+	return a, nil
+}
+
+// hostOf returns rawURL's host without a leading "www.".
+func hostOf(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+func override(dst *string, v string) {
+	if v = strings.TrimSpace(v); v != "" {
+		*dst = v
+	}
+}
+
+func orDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,120 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/numbers"
+)
+
+type fixedPicker struct{ number string }
+
+func (p fixedPicker) NextNumber(ctx context.Context, workspaceID, poolID, key string) (numbers.PoolNumber, error) {
+	return numbers.PoolNumber{Number: p.number, Status: numbers.PoolNumberActive}, nil
+}
+
+func TestParseAttribution(t *testing.T) {
+	cases := []struct {
+		name string
+		req  StartSessionRequest
+		want Attribution
+	}{
+		{
+			name: "utm parameters",
+			req:  StartSessionRequest{LandingURL: "https://acme.test/?utm_source=Google&utm_medium=CPC&utm_campaign=spring&gclid=abc"},
+			want: Attribution{Source: "google", Medium: "cpc", Campaign: "spring", GCLID: "abc"},
+		},
+		{
+			name: "gclid without utm",
+			req:  StartSessionRequest{LandingURL: "https://acme.test/?gclid=abc"},
+			want: Attribution{Source: "google", Medium: "cpc", GCLID: "abc"},
+		},
+		{
+			name: "fbclid without utm",
+			req:  StartSessionRequest{LandingURL: "https://acme.test/?fbclid=xyz"},
+			want: Attribution{Source: "facebook", Medium: "paid_social", FBCLID: "xyz"},
+		},
+		{
+			name: "external referrer",
+			req:  StartSessionRequest{LandingURL: "https://acme.test/", Referrer: "https://www.bing.com/search?q=plumber"},
+			want: Attribution{Source: "bing.com", Medium: "referral"},
+		},
+		{
+			name: "internal referrer is direct",
+			req:  StartSessionRequest{LandingURL: "https://www.acme.test/pricing", Referrer: "https://acme.test/"},
+			want: Attribution{Source: "(direct)", Medium: "(none)"},
+		},
+		{
+			name: "explicit fields override the url",
+			req:  StartSessionRequest{LandingURL: "https://acme.test/?utm_source=a&utm_campaign=x", Source: "newsletter", Medium: "email"},
+			want: Attribution{Source: "newsletter", Medium: "email", Campaign: "x"},
+		},
+	}
+	for _, tc := range cases {
+		got, err := ParseAttribution(tc.req)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		got.LandingURL, got.Referrer = "", ""
+		if got != tc.want {
+			t.Fatalf("%s: got %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestService_AttributeCallToLatestLiveSession(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore(), fixedPicker{number: "+15550001000"})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.clock = func() time.Time { return now }
+
+	first, err := svc.StartSession(ctx, "w1", StartSessionRequest{PoolID: "p1", VisitorID: "v1", LandingURL: "https://acme.test/?utm_source=google&utm_medium=cpc"})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	now = now.Add(10 * time.Minute)
+	second, err := svc.StartSession(ctx, "w1", StartSessionRequest{PoolID: "p1", VisitorID: "v2", LandingURL: "https://acme.test/?utm_source=bing&utm_medium=cpc"})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if first.TrackingNumber != "+15550001000" || second.ExpiresAt != now.Add(30*time.Minute) {
+		t.Fatalf("unexpected session %+v", second)
+	}
+
+	a, ok, err := svc.AttributeCall(ctx, "w1", "call-1", "+15550001000", now.Add(time.Minute))
+	if err != nil || !ok || a.SessionID != second.ID || a.Attribution.Source != "bing" {
+		t.Fatalf("expected latest session, got %+v %v %v", a, ok, err)
+	}
+	// Re-attributing keeps the first match.
+	now = now.Add(time.Minute)
+	if again, ok, _ := svc.AttributeCall(ctx, "w1", "call-1", "+15550001000", now); !ok || again.SessionID != second.ID {
+		t.Fatalf("expected stored attribution, got %+v", again)
+	}
+
+	if _, ok, _ := svc.AttributeCall(ctx, "w1", "call-2", "+15550001000", now.Add(time.Hour)); ok {
+		t.Fatalf("expected no attribution after the session window")
+	}
+	if _, ok, _ := svc.AttributeCall(ctx, "w2", "call-3", "+15550001000", now); ok {
+		t.Fatalf("expected no attribution across workspaces")
+	}
+
+	got, err := svc.CallAttributions(ctx, "w1", []string{"call-1", "call-2"})
+	if err != nil || len(got) != 1 || got["call-1"].Source != "bing" {
+		t.Fatalf("unexpected attributions %+v %v", got, err)
+	}
+}
+
+func TestService_RejectsInvalidArgs(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore(), fixedPicker{number: "+15550001000"})
+	if _, err := svc.StartSession(ctx, "w1", StartSessionRequest{PoolID: "p1"}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without visitor, got %v", err)
+	}
+	if _, err := svc.StartSession(ctx, "w1", StartSessionRequest{PoolID: "p1", VisitorID: "v", LandingURL: "://bad"}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument for bad landing url, got %v", err)
+	}
+	if _, _, err := svc.AttributeCall(ctx, "w1", "", "+15550001000", time.Now()); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without call id, got %v", err)
+	}
+}
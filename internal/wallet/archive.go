@@ -0,0 +1,380 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"telecom-platform/pkg/logger"
+	"telecom-platform/pkg/utils"
+)
+
+// Ledger partitioning and archival.
+//
+// wallet_ledger is range-partitioned by created_at, one partition per UTC month
+// named wallet_ledger_pYYYYMM (see LedgerPartitionFor). LedgerArchiver creates
+// partitions ahead of time and moves partitions older than RetainMonths to cold
+// storage, oldest first:
+// - each wallet's rows in the partition must continue the wallet's checkpoint;
+//   they are written to the LedgerArchiveStore as one LedgerArchive;
+// - then, in one transaction, every checkpoint advances to the wallet's last
+//   archived entry and the partition is detached and dropped.
+//
+// A LedgerCheckpoint carries the chain head and the posted/held totals of
+// everything archived, so balance invariants stay verifiable: VerifyLedgerChain
+// continues from the checkpoint, appendLedger links to it when no live rows
+// remain, reconciliation adds its totals to the live sums, and
+// VerifyLedgerArchive checks that an archive leads from its Start checkpoint to
+// its End checkpoint.
+//
+// Archived entries cannot be reversed, and reports over archived months only see
+// live rows. system_ledger_postings are not archived.
+
+var ErrLedgerChainBroken = errors.New("wallet: ledger chain broken")
+
+const ledgerPartitionPrefix = "wallet_ledger_p"
+
+// LedgerArchiveSchemaVersion is the version of the LedgerArchive JSON schema.
+const LedgerArchiveSchemaVersion = 1
+
+// LedgerPartition is one month of wallet_ledger: [From, To).
+type LedgerPartition struct {
+	Name string    `json:"name"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// LedgerPartitionFor returns the partition holding entries created at t.
+func LedgerPartitionFor(t time.Time) LedgerPartition {
+	t = t.UTC()
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return LedgerPartition{
+		Name: ledgerPartitionPrefix + from.Format("200601"),
+		From: from,
+		To:   from.AddDate(0, 1, 0),
+	}
+}
+
+func parseLedgerPartition(name string) (LedgerPartition, bool) {
+	suffix, ok := strings.CutPrefix(name, ledgerPartitionPrefix)
+	if !ok || len(suffix) != len("200601") {
+		return LedgerPartition{}, false
+	}
+	t, err := time.Parse("200601", suffix)
+	if err != nil {
+		return LedgerPartition{}, false
+	}
+	return LedgerPartitionFor(t), true
+}
+
+// createSQL returns the DDL for p. DDL does not take bind parameters; the name
+// and bounds are generated by LedgerPartitionFor.
+func (p LedgerPartition) createSQL() string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF wallet_ledger FOR VALUES FROM ('%s') TO ('%s')",
+		p.Name, p.From.Format(time.RFC3339), p.To.Format(time.RFC3339))
+}
+
+// archiveCutoff is the start of the oldest month kept live: partitions ending
+// on or before it may be archived.
+func archiveCutoff(now time.Time, retainMonths int) time.Time {
+	return LedgerPartitionFor(now).From.AddDate(0, -retainMonths, 0)
+}
+
+// LedgerCheckpoint summarizes a wallet's archived entries.
+type LedgerCheckpoint struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	WalletID    string `json:"wallet_id" db:"wallet_id"`
+
+	// Seq/Hash identify the last archived entry (0/"" when nothing is archived).
+	Seq  int64  `json:"chain_seq" db:"chain_seq"`
+	Hash string `json:"hash,omitempty" db:"hash"`
+
+	// PostedMinor is the sum of archived credit, debit and reversal entries;
+	// HeldMinor is minus the sum of archived hold and release entries.
+	PostedMinor int64 `json:"posted_minor" db:"posted_minor"`
+	HeldMinor   int64 `json:"held_minor" db:"held_minor"`
+
+	Partition  string    `json:"partition,omitempty" db:"partition"`
+	ArchivedAt time.Time `json:"archived_at" db:"archived_at"`
+}
+
+// Anchor is the chain position the live ledger continues from.
+func (c LedgerCheckpoint) Anchor() LedgerAnchor {
+	return LedgerAnchor{Seq: c.Seq, Hash: c.Hash}
+}
+
+// advanceCheckpoint verifies that entries continue prev's chain and returns the
+// checkpoint after them (only meaningful when the verification is valid).
+func advanceCheckpoint(prev LedgerCheckpoint, entries []WalletLedger) (LedgerCheckpoint, ChainVerification) {
+	v := VerifyLedgerEntriesFrom(prev.Anchor(), entries)
+	next := prev
+	next.Seq, next.Hash = v.HeadSeq, v.HeadHash
+	for _, e := range entries {
+		switch e.Type {
+		case LedgerEntryTypeCredit, LedgerEntryTypeDebit, LedgerEntryTypeReversal:
+			next.PostedMinor += e.AmountMinor
+		case LedgerEntryTypeHold, LedgerEntryTypeRelease:
+			next.HeldMinor -= e.AmountMinor
+		}
+	}
+	return next, v
+}
+
+// LedgerArchive is one wallet's entries from one archived partition, as written
+// to cold storage.
+type LedgerArchive struct {
+	SchemaVersion int              `json:"schema_version"`
+	Partition     string           `json:"partition"`
+	WorkspaceID   string           `json:"workspace_id"`
+	WalletID      string           `json:"wallet_id"`
+	Start         LedgerCheckpoint `json:"start"`
+	End           LedgerCheckpoint `json:"end"`
+	Entries       []WalletLedger   `json:"entries"`
+}
+
+// VerifyLedgerArchive re-hashes an archive's entries from its Start checkpoint
+// and checks that they lead to its End checkpoint, totals included.
+func VerifyLedgerArchive(a LedgerArchive) ChainVerification {
+	end, out := advanceCheckpoint(a.Start, a.Entries)
+	out.WorkspaceID = a.WorkspaceID
+	out.WalletID = a.WalletID
+	if out.Valid && (end.Seq != a.End.Seq || end.Hash != a.End.Hash ||
+		end.PostedMinor != a.End.PostedMinor || end.HeldMinor != a.End.HeldMinor) {
+		out.Valid = false
+		out.BrokenAtSeq = a.End.Seq
+		out.Reason = "archive end checkpoint mismatch"
+		out.HeadSeq, out.HeadHash = 0, ""
+	}
+	return out
+}
+
+// LedgerArchiveStore writes archives to cold storage. Writes must be idempotent:
+// a partition whose drop failed is archived again on the next run.
+type LedgerArchiveStore interface {
+	PutLedgerArchive(ctx context.Context, a LedgerArchive) error
+}
+
+// ObjectWriter is the minimal S3-compatible put used by ObjectArchiveStore.
+type ObjectWriter interface {
+	PutObject(ctx context.Context, bucket, key string, body []byte, contentType string) error
+}
+
+// ObjectArchiveStore writes each archive as a JSON object.
+// Keys: <prefix>/<partition>/<workspace_id>/<wallet_id>.json
+type ObjectArchiveStore struct {
+	Objects ObjectWriter
+	Bucket  string
+	Prefix  string
+}
+
+func (s ObjectArchiveStore) PutLedgerArchive(ctx context.Context, a LedgerArchive) error {
+	if s.Objects == nil || s.Bucket == "" {
+		return errors.New("wallet: archive object store not configured")
+	}
+	body, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	return s.Objects.PutObject(ctx, s.Bucket, s.key(a), body, "application/json")
+}
+
+func (s ObjectArchiveStore) key(a LedgerArchive) string {
+	key := fmt.Sprintf("%s/%s/%s.json", a.Partition, a.WorkspaceID, a.WalletID)
+	if p := strings.Trim(s.Prefix, "/"); p != "" {
+		key = p + "/" + key
+	}
+	return key
+}
+
+// EnsureLedgerPartitions creates the partitions for now's month and the next
+// ahead months; existing partitions are left alone.
+func (s *Service) EnsureLedgerPartitions(ctx context.Context, now time.Time, ahead int) error {
+	if ahead < 0 {
+		return ErrInvalidArgument
+	}
+	ctx, done := utils.TrackQuery(reportQuery(ctx, "EnsureLedgerPartitions"))
+	var err error
+	p := LedgerPartitionFor(now)
+	for i := 0; i <= ahead && err == nil; i++ {
+		err = createLedgerPartition(ctx, s.db, p)
+		p = LedgerPartitionFor(p.To)
+	}
+	done(err)
+	return err
+}
+
+// LedgerPartitions lists wallet_ledger's monthly partitions, oldest first.
+// Partitions not named like LedgerPartitionFor's are ignored.
+func (s *Service) LedgerPartitions(ctx context.Context) ([]LedgerPartition, error) {
+	qctx, done := utils.TrackQuery(reportQuery(ctx, "LedgerPartitions"))
+	names, err := listLedgerPartitionNames(qctx, s.db)
+	done(err)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]LedgerPartition, 0, len(names))
+	for _, name := range names {
+		if p, ok := parseLedgerPartition(name); ok {
+			out = append(out, p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].From.Before(out[j].From) })
+	return out, nil
+}
+
+// ArchiveLedgerPartition writes every wallet's rows in p to store, then advances
+// the wallets' checkpoints and drops p in one transaction. p must be the oldest
+// partition: rows that do not continue their wallet's checkpoint fail with
+// ErrLedgerChainBroken and nothing is dropped. It returns the number of wallets
+// archived.
+func (s *Service) ArchiveLedgerPartition(ctx context.Context, p LedgerPartition, store LedgerArchiveStore) (int, error) {
+	if store == nil {
+		return 0, ErrInvalidArgument
+	}
+	if q, ok := parseLedgerPartition(p.Name); !ok || q != p {
+		return 0, ErrInvalidArgument
+	}
+	archivedAt := s.clock().UTC()
+
+	var (
+		cps []LedgerCheckpoint
+		cur LedgerArchive
+	)
+	qctx, done := utils.TrackQuery(utils.WithQuery(ctx, "wallet", "ArchiveLedgerPartition", utils.BatchBudget))
+	flush := func() error {
+		if len(cur.Entries) == 0 {
+			return nil
+		}
+		start, err := getLedgerCheckpoint(qctx, s.db, cur.WorkspaceID, cur.WalletID)
+		if err != nil {
+			return err
+		}
+		end, v := advanceCheckpoint(start, cur.Entries)
+		if !v.Valid {
+			return fmt.Errorf("%w: %s wallet %s at seq %d: %s", ErrLedgerChainBroken, p.Name, cur.WalletID, v.BrokenAtSeq, v.Reason)
+		}
+		end.Partition, end.ArchivedAt = p.Name, archivedAt
+		cur.Start, cur.End = start, end
+		if err := store.PutLedgerArchive(ctx, cur); err != nil {
+			return err
+		}
+		cps = append(cps, end)
+		return nil
+	}
+	err := streamLedgerPartition(qctx, s.db, p, func(e WalletLedger) error {
+		if e.WorkspaceID != cur.WorkspaceID || e.WalletID != cur.WalletID {
+			if err := flush(); err != nil {
+				return err
+			}
+			cur = LedgerArchive{SchemaVersion: LedgerArchiveSchemaVersion, Partition: p.Name, WorkspaceID: e.WorkspaceID, WalletID: e.WalletID}
+		}
+		cur.Entries = append(cur.Entries, e)
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	done(err)
+	if err != nil {
+		return 0, err
+	}
+
+	err = utils.WithTx(utils.WithQuery(ctx, "wallet", "DropLedgerPartition", utils.BatchBudget), s.db, &sql.TxOptions{}, func(ctx context.Context, tx *sql.Tx) error {
+		for _, c := range cps {
+			if err := upsertLedgerCheckpoint(ctx, tx, c); err != nil {
+				return err
+			}
+		}
+		return dropLedgerPartition(ctx, tx, p)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(cps), nil
+}
+
+// LedgerArchiver maintains wallet_ledger partitions. Run a single instance
+// (e.g. daily).
+type LedgerArchiver struct {
+	Wallet *Service
+	Store  LedgerArchiveStore
+
+	// RetainMonths full months stay in Postgres besides the current one; 0
+	// disables archival. It must outlast the wallet's idempotency TTL.
+	RetainMonths int
+	// PartitionsAhead months are created in advance; defaults to 2.
+	PartitionsAhead int
+	Now             func() time.Time
+}
+
+// RunOnce creates upcoming partitions, then archives expired partitions oldest
+// first and returns their names.
+func (a LedgerArchiver) RunOnce(ctx context.Context) ([]string, error) {
+	if a.Wallet == nil || (a.RetainMonths > 0 && a.Store == nil) {
+		return nil, errors.New("wallet: ledger archiver not configured")
+	}
+	now := a.now()
+	cutoff := archiveCutoff(now, a.RetainMonths)
+	if a.RetainMonths > 0 && cutoff.After(now.Add(-a.Wallet.idempotencyTTL())) {
+		return nil, errors.New("wallet: ledger retention shorter than the idempotency TTL")
+	}
+	ahead := a.PartitionsAhead
+	if ahead <= 0 {
+		ahead = 2
+	}
+	if err := a.Wallet.EnsureLedgerPartitions(ctx, now, ahead); err != nil {
+		return nil, err
+	}
+	if a.RetainMonths <= 0 {
+		return nil, nil
+	}
+
+	parts, err := a.Wallet.LedgerPartitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var archived []string
+	for _, p := range parts {
+		if p.To.After(cutoff) {
+			break
+		}
+		if ctx.Err() != nil {
+			return archived, ctx.Err()
+		}
+		n, err := a.Wallet.ArchiveLedgerPartition(ctx, p, a.Store)
+		if err != nil {
+			return archived, err
+		}
+		logger.From(ctx).Info("ledger partition archived", "partition", p.Name, "wallets", n)
+		archived = append(archived, p.Name)
+	}
+	return archived, nil
+}
+
+// Run maintains partitions on every tick until ctx is canceled.
+func (a LedgerArchiver) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := a.RunOnce(ctx); err != nil {
+				logger.From(ctx).Error("ledger archival failed", "err", err)
+			}
+		}
+	}
+}
+
+func (a LedgerArchiver) now() time.Time {
+	if a.Now != nil {
+		return a.Now().UTC()
+	}
+	return time.Now().UTC()
+}
@@ -0,0 +1,148 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLedgerPartitionFor(t *testing.T) {
+	p := LedgerPartitionFor(time.Date(2024, 1, 31, 23, 59, 0, 0, time.FixedZone("x", -3600)))
+	if p.Name != "wallet_ledger_p202402" || !p.From.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) || !p.To.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected partition %+v", p)
+	}
+	want := "CREATE TABLE IF NOT EXISTS wallet_ledger_p202402 PARTITION OF wallet_ledger FOR VALUES FROM ('2024-02-01T00:00:00Z') TO ('2024-03-01T00:00:00Z')"
+	if got := p.createSQL(); got != want {
+		t.Fatalf("got %q", got)
+	}
+
+	if q, ok := parseLedgerPartition("wallet_ledger_p202402"); !ok || q != p {
+		t.Fatalf("expected round trip, got %+v %v", q, ok)
+	}
+	for _, name := range []string{"wallet_ledger_default", "wallet_ledger_p2024", "wallet_ledger_p202413", "wallet_ledger_p202402; DROP TABLE wallets"} {
+		if _, ok := parseLedgerPartition(name); ok {
+			t.Fatalf("expected %q to be rejected", name)
+		}
+	}
+
+	now := time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC)
+	if got := archiveCutoff(now, 3); !got.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected cutoff %s", got)
+	}
+}
+
+func archiveChain(n int) []WalletLedger {
+	types := []LedgerEntryType{LedgerEntryTypeCredit, LedgerEntryTypeHold, LedgerEntryTypeDebit, LedgerEntryTypeRelease, LedgerEntryTypeReversal}
+	amounts := []int64{1000, -300, -200, 300, 50}
+	var chain []WalletLedger
+	head := WalletLedger{}
+	for i := 0; i < n; i++ {
+		head = linkLedger(head, WalletLedger{ID: string(rune('a' + i)), WorkspaceID: "ws", WalletID: "w", Type: types[i%len(types)], AmountMinor: amounts[i%len(amounts)], Currency: "USD"})
+		chain = append(chain, head)
+	}
+	return chain
+}
+
+func TestAdvanceCheckpoint(t *testing.T) {
+	chain := archiveChain(7)
+	start := LedgerCheckpoint{WorkspaceID: "ws", WalletID: "w"}
+
+	first, v := advanceCheckpoint(start, chain[:5])
+	if !v.Valid || first.Seq != 5 || first.Hash != chain[4].Hash || first.PostedMinor != 850 || first.HeldMinor != 0 {
+		t.Fatalf("unexpected checkpoint %+v %+v", first, v)
+	}
+	second, v := advanceCheckpoint(first, chain[5:])
+	if !v.Valid || second.Seq != 7 || second.PostedMinor != 1850 || second.HeldMinor != 300 {
+		t.Fatalf("unexpected checkpoint %+v %+v", second, v)
+	}
+	if _, v := advanceCheckpoint(start, chain[5:]); v.Valid || v.Reason != "sequence gap" {
+		t.Fatalf("expected rows not continuing the checkpoint to be rejected, got %+v", v)
+	}
+
+	// The live chain after the checkpoint verifies from it, anchors included.
+	if res := CheckLedgerAnchorFrom(first.Anchor(), chain[5:], LedgerAnchor{Seq: 6, Hash: chain[5].Hash}); !res.Valid || res.HeadSeq != 7 {
+		t.Fatalf("expected live chain to verify from the checkpoint, got %+v", res)
+	}
+	if res := CheckLedgerAnchorFrom(first.Anchor(), nil, first.Anchor()); !res.Valid || res.HeadSeq != 5 {
+		t.Fatalf("expected fully archived chain to verify, got %+v", res)
+	}
+	if res := CheckLedgerAnchorFrom(first.Anchor(), chain[5:], LedgerAnchor{Seq: 5, Hash: "bogus"}); res.Valid {
+		t.Fatalf("expected checkpoint hash mismatch to be reported")
+	}
+}
+
+func TestVerifyLedgerArchive(t *testing.T) {
+	chain := archiveChain(5)
+	start := LedgerCheckpoint{WorkspaceID: "ws", WalletID: "w"}
+	end, _ := advanceCheckpoint(start, chain)
+	a := LedgerArchive{SchemaVersion: LedgerArchiveSchemaVersion, WorkspaceID: "ws", WalletID: "w", Start: start, End: end, Entries: chain}
+
+	// Archives are verified after a JSON round trip, as read back from storage.
+	raw, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back LedgerArchive
+	if err := json.Unmarshal(raw, &back); err != nil {
+		t.Fatal(err)
+	}
+	if res := VerifyLedgerArchive(back); !res.Valid || res.Checked != 5 {
+		t.Fatalf("expected archive to verify, got %+v", res)
+	}
+
+	back.End.PostedMinor++
+	if res := VerifyLedgerArchive(back); res.Valid || res.Reason != "archive end checkpoint mismatch" {
+		t.Fatalf("expected totals mismatch, got %+v", res)
+	}
+	back.End.PostedMinor--
+	back.Entries[2].AmountMinor = -20
+	if res := VerifyLedgerArchive(back); res.Valid || res.BrokenAtSeq != 3 {
+		t.Fatalf("expected edited entry to be detected, got %+v", res)
+	}
+}
+
+type fakeObjects struct {
+	bucket, key, contentType string
+	body                     []byte
+}
+
+func (f *fakeObjects) PutObject(_ context.Context, bucket, key string, body []byte, contentType string) error {
+	f.bucket, f.key, f.body, f.contentType = bucket, key, body, contentType
+	return nil
+}
+
+func TestObjectArchiveStore(t *testing.T) {
+	objs := &fakeObjects{}
+	store := ObjectArchiveStore{Objects: objs, Bucket: "cold", Prefix: "/ledger/"}
+	a := LedgerArchive{SchemaVersion: LedgerArchiveSchemaVersion, Partition: "wallet_ledger_p202401", WorkspaceID: "ws", WalletID: "w"}
+	if err := store.PutLedgerArchive(context.Background(), a); err != nil {
+		t.Fatal(err)
+	}
+	if objs.bucket != "cold" || objs.key != "ledger/wallet_ledger_p202401/ws/w.json" || objs.contentType != "application/json" || !strings.Contains(string(objs.body), `"schema_version":1`) {
+		t.Fatalf("unexpected object %+v", objs)
+	}
+	if err := (ObjectArchiveStore{}).PutLedgerArchive(context.Background(), a); err == nil {
+		t.Fatalf("expected unconfigured store to fail")
+	}
+}
+
+func TestLedgerArchiver_RejectsUnsafeConfig(t *testing.T) {
+	svc := NewService((*sql.DB)(nil))
+	ctx := context.Background()
+	now := func() time.Time { return time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC) }
+
+	if _, err := (LedgerArchiver{Wallet: svc, RetainMonths: 3, Now: now}).RunOnce(ctx); err == nil {
+		t.Fatalf("expected archival without a store to fail")
+	}
+	if _, err := svc.ArchiveLedgerPartition(ctx, LedgerPartition{Name: "wallet_ledger"}, ObjectArchiveStore{}); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected unknown partition to be rejected, got %v", err)
+	}
+	svc.IdempotencyTTL = 90 * 24 * time.Hour
+	if _, err := (LedgerArchiver{Wallet: svc, Store: ObjectArchiveStore{}, RetainMonths: 2, Now: now}).RunOnce(ctx); err == nil || !strings.Contains(err.Error(), "idempotency TTL") {
+		t.Fatalf("expected retention shorter than the idempotency TTL to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,276 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/audit"
+	"telecom-platform/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Auto top-up.
+//
+// A wallet may carry a rule "when available balance drops below ThresholdMinor,
+// charge PaymentMethodRef for TopUpAmountMinor". AutoTopUpWorker evaluates the
+// rules and posts the credit.
+//
+// Idempotency: before charging, the worker persists a PendingKey on the rule and
+// uses it as both the payment provider idempotency key and the ledger idempotency
+// key. A crash between charge and credit therefore retries with the same key: the
+// provider returns the original payment and Credit returns the original entry, so
+// the customer is never charged twice for one top-up.
+//
+// Failed charges back off by RetryBackoff; after MaxFailures in a row the rule is
+// disabled so a dead card is not retried forever. Outcomes are audited.
+
+type AutoTopUpConfig struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	WalletID    string `json:"wallet_id" db:"wallet_id"`
+
+	Enabled          bool   `json:"enabled" db:"enabled"`
+	ThresholdMinor   int64  `json:"threshold_minor" db:"threshold_minor"`
+	TopUpAmountMinor int64  `json:"topup_amount_minor" db:"topup_amount_minor"`
+	PaymentMethodRef string `json:"payment_method_ref" db:"payment_method_ref"`
+
+	// Worker state.
+	PendingKey          string     `json:"-" db:"pending_key"`
+	ConsecutiveFailures int        `json:"consecutive_failures" db:"consecutive_failures"`
+	LastAttemptAt       *time.Time `json:"last_attempt_at,omitempty" db:"last_attempt_at"`
+	LastError           string     `json:"last_error,omitempty" db:"last_error"`
+
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// AutoTopUpStore persists rules. Implementations must enforce workspace filtering.
+type AutoTopUpStore interface {
+	GetAutoTopUp(ctx context.Context, workspaceID, walletID string) (AutoTopUpConfig, bool, error)
+	PutAutoTopUp(ctx context.Context, cfg AutoTopUpConfig) error
+	// ListEnabledAutoTopUps returns enabled rules across all workspaces.
+	ListEnabledAutoTopUps(ctx context.Context) ([]AutoTopUpConfig, error)
+}
+
+// PaymentCharger charges a stored payment method. idempotencyKey must make
+// repeated calls return the original payment instead of charging again.
+type PaymentCharger interface {
+	Charge(ctx context.Context, workspaceID, paymentMethodRef string, amountMinor int64, currency, idempotencyKey string) (paymentRef string, err error)
+}
+
+// autoTopUpWallet is the part of Service the worker needs.
+type autoTopUpWallet interface {
+	GetBalance(ctx context.Context, workspaceID, walletID string) (Balance, error)
+	Credit(ctx context.Context, workspaceID, walletID string, req CreditRequest) (WalletLedger, Balance, error)
+}
+
+var ErrInvalidAutoTopUp = errors.New("invalid auto top-up config")
+
+// ValidateAutoTopUp checks a rule before it is stored.
+func ValidateAutoTopUp(cfg AutoTopUpConfig) error {
+	if cfg.WorkspaceID == "" || cfg.WalletID == "" {
+		return ErrInvalidAutoTopUp
+	}
+	if cfg.ThresholdMinor < 0 || cfg.TopUpAmountMinor < 0 {
+		return fmt.Errorf("%w: amounts must not be negative", ErrInvalidAutoTopUp)
+	}
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.TopUpAmountMinor == 0 {
+		return fmt.Errorf("%w: topup_amount_minor required", ErrInvalidAutoTopUp)
+	}
+	if strings.TrimSpace(cfg.PaymentMethodRef) == "" {
+		return fmt.Errorf("%w: payment_method_ref required", ErrInvalidAutoTopUp)
+	}
+	return nil
+}
+
+// AutoTopUpWorker evaluates enabled rules. Run a single instance.
+type AutoTopUpWorker struct {
+	Store    AutoTopUpStore
+	Wallet   autoTopUpWallet
+	Payments PaymentCharger
+	Audit    *audit.Service // optional
+
+	RetryBackoff time.Duration
+	MaxFailures  int
+	Now          func() time.Time
+}
+
+// RunOnce evaluates every enabled rule once and returns the number of top-ups posted.
+// A failing rule is recorded and skipped; it never stops the others.
+func (w AutoTopUpWorker) RunOnce(ctx context.Context) (int, error) {
+	cfgs, err := w.Store.ListEnabledAutoTopUps(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, cfg := range cfgs {
+		ok, err := w.evaluate(ctx, cfg)
+		if err != nil {
+			logger.From(ctx).Error("auto top-up failed", "workspace_id", cfg.WorkspaceID, "wallet_id", cfg.WalletID, "err", err)
+			continue
+		}
+		if ok {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (w AutoTopUpWorker) evaluate(ctx context.Context, cfg AutoTopUpConfig) (bool, error) {
+	now := w.now()
+	if cfg.ConsecutiveFailures > 0 && cfg.LastAttemptAt != nil && now.Before(cfg.LastAttemptAt.Add(w.backoff())) {
+		return false, nil
+	}
+	bal, err := w.Wallet.GetBalance(ctx, cfg.WorkspaceID, cfg.WalletID)
+	if err != nil {
+		return false, err
+	}
+	// A pending top-up is finished even if the balance recovered meanwhile:
+	// the customer may already have been charged for it.
+	if cfg.PendingKey == "" {
+		if bal.Available() >= cfg.ThresholdMinor {
+			return false, nil
+		}
+		cfg.PendingKey = "auto-topup:" + uuid.NewString()
+		cfg.UpdatedAt = now
+		if err := w.Store.PutAutoTopUp(ctx, cfg); err != nil {
+			return false, err
+		}
+	}
+
+	cfg.LastAttemptAt = &now
+	paymentRef, err := w.Payments.Charge(ctx, cfg.WorkspaceID, cfg.PaymentMethodRef, cfg.TopUpAmountMinor, bal.Currency, cfg.PendingKey)
+	if err != nil {
+		return false, w.recordFailure(ctx, cfg, err)
+	}
+	md, _ := json.Marshal(map[string]string{"source": "auto_topup", "payment_ref": paymentRef})
+	entry, _, err := w.Wallet.Credit(ctx, cfg.WorkspaceID, cfg.WalletID, CreditRequest{
+		AmountMinor:    cfg.TopUpAmountMinor,
+		Currency:       bal.Currency,
+		ExternalRef:    paymentRef,
+		IdempotencyKey: cfg.PendingKey,
+		Metadata:       string(md),
+	})
+	if err != nil {
+		// Keep PendingKey: the retry must credit this payment, not charge again.
+		return false, w.recordFailure(ctx, cfg, err)
+	}
+
+	cfg.PendingKey = ""
+	cfg.ConsecutiveFailures = 0
+	cfg.LastError = ""
+	cfg.UpdatedAt = w.now()
+	if err := w.Store.PutAutoTopUp(ctx, cfg); err != nil {
+		return false, err
+	}
+	w.log(ctx, cfg, fmt.Sprintf("auto top-up credited %d %s", cfg.TopUpAmountMinor, bal.Currency),
+		map[string]any{"ledger_id": entry.ID, "payment_ref": paymentRef, "balance_before_minor": bal.Available()})
+	return true, nil
+}
+
+func (w AutoTopUpWorker) recordFailure(ctx context.Context, cfg AutoTopUpConfig, cause error) error {
+	cfg.ConsecutiveFailures++
+	cfg.LastError = cause.Error()
+	cfg.UpdatedAt = w.now()
+	msg := "auto top-up failed: " + cause.Error()
+	if max := w.maxFailures(); cfg.ConsecutiveFailures >= max {
+		cfg.Enabled = false
+		msg = fmt.Sprintf("auto top-up disabled after %d failures: %s", cfg.ConsecutiveFailures, cause.Error())
+	}
+	if err := w.Store.PutAutoTopUp(ctx, cfg); err != nil {
+		return err
+	}
+	w.log(ctx, cfg, msg, map[string]any{"consecutive_failures": cfg.ConsecutiveFailures})
+	return cause
+}
+
+func (w AutoTopUpWorker) log(ctx context.Context, cfg AutoTopUpConfig, message string, metadata map[string]any) {
+	if w.Audit == nil {
+		return
+	}
+	meta, _ := json.Marshal(metadata)
+	_ = w.Audit.LogAdminAction(ctx, cfg.WorkspaceID, "system", "", "", message, cfg.WalletID, string(meta))
+}
+
+// Run evaluates rules on every tick until ctx is canceled.
+func (w AutoTopUpWorker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.RunOnce(ctx); err != nil {
+				logger.From(ctx).Error("auto top-up run failed", "err", err)
+			}
+		}
+	}
+}
+
+func (w AutoTopUpWorker) now() time.Time {
+	if w.Now != nil {
+		return w.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (w AutoTopUpWorker) backoff() time.Duration {
+	if w.RetryBackoff > 0 {
+		return w.RetryBackoff
+	}
+	return 15 * time.Minute
+}
+
+func (w AutoTopUpWorker) maxFailures() int {
+	if w.MaxFailures > 0 {
+		return w.MaxFailures
+	}
+	return 3
+}
+
+// GetAutoTopUp returns the wallet's rule (ok=false when none is configured).
+func (s *Service) GetAutoTopUp(ctx context.Context, workspaceID, walletID string) (AutoTopUpConfig, bool, error) {
+	if workspaceID == "" || walletID == "" {
+		return AutoTopUpConfig{}, false, ErrInvalidArgument
+	}
+	if s.AutoTopUps == nil {
+		return AutoTopUpConfig{}, false, errors.New("auto top-up store not configured")
+	}
+	return s.AutoTopUps.GetAutoTopUp(ctx, workspaceID, walletID)
+}
+
+// SetAutoTopUp stores the wallet's rule. Worker state is kept so an in-flight
+// top-up still completes; saving an enabled rule clears the failure count.
+func (s *Service) SetAutoTopUp(ctx context.Context, cfg AutoTopUpConfig) (AutoTopUpConfig, error) {
+	cfg.PaymentMethodRef = strings.TrimSpace(cfg.PaymentMethodRef)
+	if err := ValidateAutoTopUp(cfg); err != nil {
+		return AutoTopUpConfig{}, err
+	}
+	cur, ok, err := s.GetAutoTopUp(ctx, cfg.WorkspaceID, cfg.WalletID)
+	if err != nil {
+		return AutoTopUpConfig{}, err
+	}
+	if ok {
+		cfg.PendingKey = cur.PendingKey
+		cfg.LastAttemptAt = cur.LastAttemptAt
+		cfg.LastError = cur.LastError
+		if !cfg.Enabled {
+			cfg.ConsecutiveFailures = cur.ConsecutiveFailures
+		}
+	}
+	if cfg.Enabled {
+		cfg.ConsecutiveFailures = 0
+	}
+	cfg.UpdatedAt = s.clock().UTC()
+	if err := s.AutoTopUps.PutAutoTopUp(ctx, cfg); err != nil {
+		return AutoTopUpConfig{}, err
+	}
+	return cfg, nil
+}
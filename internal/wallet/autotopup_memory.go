@@ -0,0 +1,47 @@
+package wallet
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryAutoTopUpStore is a simple in-memory AutoTopUpStore useful for tests.
+// It is not intended for production use.
+type MemoryAutoTopUpStore struct {
+	mu    sync.Mutex
+	items map[string]AutoTopUpConfig
+}
+
+func NewMemoryAutoTopUpStore() *MemoryAutoTopUpStore {
+	return &MemoryAutoTopUpStore{items: map[string]AutoTopUpConfig{}}
+}
+
+func (s *MemoryAutoTopUpStore) GetAutoTopUp(ctx context.Context, workspaceID, walletID string) (AutoTopUpConfig, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.items[workspaceID+"|"+walletID]
+	return c, ok, nil
+}
+
+func (s *MemoryAutoTopUpStore) PutAutoTopUp(ctx context.Context, c AutoTopUpConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[c.WorkspaceID+"|"+c.WalletID] = c
+	return nil
+}
+
+func (s *MemoryAutoTopUpStore) ListEnabledAutoTopUps(ctx context.Context) ([]AutoTopUpConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []AutoTopUpConfig
+	for _, c := range s.items {
+		if c.Enabled {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].WorkspaceID+"|"+out[i].WalletID < out[j].WorkspaceID+"|"+out[j].WalletID
+	})
+	return out, nil
+}
@@ -0,0 +1,194 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/audit"
+)
+
+type stubTopUpWallet struct {
+	bal       Balance
+	creditErr error
+	credits   map[string]int64 // idempotency key -> amount
+}
+
+func (w *stubTopUpWallet) GetBalance(ctx context.Context, workspaceID, walletID string) (Balance, error) {
+	return w.bal, nil
+}
+
+func (w *stubTopUpWallet) Credit(ctx context.Context, workspaceID, walletID string, req CreditRequest) (WalletLedger, Balance, error) {
+	if w.creditErr != nil {
+		return WalletLedger{}, Balance{}, w.creditErr
+	}
+	if _, ok := w.credits[req.IdempotencyKey]; !ok {
+		w.credits[req.IdempotencyKey] = req.AmountMinor
+		w.bal.BalanceMinor += req.AmountMinor
+	}
+	return WalletLedger{ID: "l-" + req.IdempotencyKey}, w.bal, nil
+}
+
+type stubCharger struct {
+	err     error
+	ref     string           // payment reference to return; default "pay-<key>"
+	charges map[string]int64 // idempotency key -> amount
+}
+
+func (p *stubCharger) Charge(ctx context.Context, workspaceID, paymentMethodRef string, amountMinor int64, currency, idempotencyKey string) (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+	p.charges[idempotencyKey] = amountMinor
+	if p.ref != "" {
+		return p.ref, nil
+	}
+	return "pay-" + idempotencyKey, nil
+}
+
+func newTopUpFixture(t *testing.T, available int64) (*AutoTopUpWorker, *MemoryAutoTopUpStore, *stubTopUpWallet, *stubCharger, *time.Time) {
+	t.Helper()
+	store := NewMemoryAutoTopUpStore()
+	if err := store.PutAutoTopUp(context.Background(), AutoTopUpConfig{
+		WorkspaceID: "ws", WalletID: "w1", Enabled: true,
+		ThresholdMinor: 500, TopUpAmountMinor: 2000, PaymentMethodRef: "pm_1",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	wal := &stubTopUpWallet{bal: Balance{Currency: "USD", BalanceMinor: available}, credits: map[string]int64{}}
+	pay := &stubCharger{charges: map[string]int64{}}
+	w := &AutoTopUpWorker{
+		Store: store, Wallet: wal, Payments: pay,
+		Audit:        audit.NewService(audit.NewMemoryRepo()),
+		RetryBackoff: time.Minute, MaxFailures: 2,
+		Now: func() time.Time { return now },
+	}
+	return w, store, wal, pay, &now
+}
+
+func TestAutoTopUpWorker_TopsUpBelowThresholdOnly(t *testing.T) {
+	ctx := context.Background()
+	w, store, wal, pay, _ := newTopUpFixture(t, 499)
+
+	n, err := w.RunOnce(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("expected one top-up, got %d %v", n, err)
+	}
+	if len(pay.charges) != 1 || len(wal.credits) != 1 || wal.bal.BalanceMinor != 2499 {
+		t.Fatalf("unexpected state: charges=%v credits=%v balance=%d", pay.charges, wal.credits, wal.bal.BalanceMinor)
+	}
+	cfg, _, _ := store.GetAutoTopUp(ctx, "ws", "w1")
+	if cfg.PendingKey != "" || cfg.LastAttemptAt == nil {
+		t.Fatalf("expected settled rule, got %+v", cfg)
+	}
+
+	// Back above the threshold: nothing to do.
+	if n, err := w.RunOnce(ctx); err != nil || n != 0 || len(pay.charges) != 1 {
+		t.Fatalf("expected no further top-up, got %d %v charges=%d", n, err, len(pay.charges))
+	}
+}
+
+func TestAutoTopUpWorker_AuditMetadataIsValidJSON(t *testing.T) {
+	ctx := context.Background()
+	w, _, _, pay, _ := newTopUpFixture(t, 0)
+	repo := audit.NewMemoryRepo()
+	w.Audit = audit.NewService(repo)
+	pay.ref = "ch_\"1\"\x01" // %q would write \x01, which is not json
+
+	if _, err := w.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	events := repo.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected one audit event, got %d", len(events))
+	}
+	var meta map[string]any
+	if err := json.Unmarshal([]byte(events[0].Metadata), &meta); err != nil {
+		t.Fatalf("metadata %q is not json: %v", events[0].Metadata, err)
+	}
+	if meta["payment_ref"] != pay.ref {
+		t.Fatalf("expected payment_ref %q, got %v", pay.ref, meta["payment_ref"])
+	}
+}
+
+func TestAutoTopUpWorker_CreditFailureRetriesWithSameKey(t *testing.T) {
+	ctx := context.Background()
+	w, store, wal, pay, now := newTopUpFixture(t, 0)
+	wal.creditErr = errors.New("db down")
+
+	if n, _ := w.RunOnce(ctx); n != 0 {
+		t.Fatalf("expected no completed top-up, got %d", n)
+	}
+	cfg, _, _ := store.GetAutoTopUp(ctx, "ws", "w1")
+	if cfg.PendingKey == "" || cfg.ConsecutiveFailures != 1 {
+		t.Fatalf("expected pending key kept after credit failure, got %+v", cfg)
+	}
+	key := cfg.PendingKey
+
+	// Within the backoff nothing is attempted.
+	if _, _ = w.RunOnce(ctx); len(pay.charges) != 1 {
+		t.Fatalf("expected backoff to skip the retry")
+	}
+
+	wal.creditErr = nil
+	*now = now.Add(2 * time.Minute)
+	if n, err := w.RunOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("expected retry to complete, got %d %v", n, err)
+	}
+	if len(pay.charges) != 1 || pay.charges[key] != 2000 || wal.credits[key] != 2000 {
+		t.Fatalf("expected a single charge and credit under %q, got charges=%v credits=%v", key, pay.charges, wal.credits)
+	}
+}
+
+func TestAutoTopUpWorker_DisablesAfterMaxFailures(t *testing.T) {
+	ctx := context.Background()
+	w, store, _, pay, now := newTopUpFixture(t, 0)
+	pay.err = errors.New("card declined")
+
+	for i := 0; i < 2; i++ {
+		_, _ = w.RunOnce(ctx)
+		*now = now.Add(2 * time.Minute)
+	}
+	cfg, _, _ := store.GetAutoTopUp(ctx, "ws", "w1")
+	if cfg.Enabled || cfg.ConsecutiveFailures != 2 || cfg.LastError != "card declined" {
+		t.Fatalf("expected rule disabled after 2 failures, got %+v", cfg)
+	}
+	if list, _ := store.ListEnabledAutoTopUps(ctx); len(list) != 0 {
+		t.Fatalf("disabled rule must not be listed, got %v", list)
+	}
+}
+
+func TestSetAutoTopUp_ValidatesAndKeepsWorkerState(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService((*sql.DB)(nil))
+	store := NewMemoryAutoTopUpStore()
+	svc.AutoTopUps = store
+
+	bad := []AutoTopUpConfig{
+		{WalletID: "w1"},
+		{WorkspaceID: "ws", WalletID: "w1", Enabled: true, TopUpAmountMinor: 100},
+		{WorkspaceID: "ws", WalletID: "w1", Enabled: true, PaymentMethodRef: "pm"},
+		{WorkspaceID: "ws", WalletID: "w1", ThresholdMinor: -1},
+	}
+	for i, cfg := range bad {
+		if _, err := svc.SetAutoTopUp(ctx, cfg); !errors.Is(err, ErrInvalidAutoTopUp) {
+			t.Fatalf("case %d: expected ErrInvalidAutoTopUp, got %v", i, err)
+		}
+	}
+
+	_ = store.PutAutoTopUp(ctx, AutoTopUpConfig{WorkspaceID: "ws", WalletID: "w1", PendingKey: "auto-topup:k", ConsecutiveFailures: 3})
+	cfg, err := svc.SetAutoTopUp(ctx, AutoTopUpConfig{
+		WorkspaceID: "ws", WalletID: "w1", Enabled: true,
+		ThresholdMinor: 100, TopUpAmountMinor: 1000, PaymentMethodRef: " pm_2 ",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PendingKey != "auto-topup:k" || cfg.ConsecutiveFailures != 0 || cfg.PaymentMethodRef != "pm_2" {
+		t.Fatalf("unexpected stored rule: %+v", cfg)
+	}
+}
@@ -0,0 +1,197 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"telecom-platform/pkg/logger"
+	"telecom-platform/pkg/utils"
+)
+
+// As-of balances.
+//
+// BalanceAt answers "what was the balance at t" (every entry created before t)
+// without replaying the whole ledger: it starts from the latest daily snapshot
+// in wallet_balance_snapshots at or before t and adds the ledger entries between
+// the snapshot and t. BalanceSnapshotter writes one snapshot per wallet for each
+// UTC day end, once postings for that day have settled.
+//
+// Archived months (see archive.go) are covered by the wallet's checkpoint, which
+// is a snapshot at the end of the last archived month. Inside archived months
+// only snapshot times (UTC midnights) can be answered; other times fail with
+// ErrBalanceArchived.
+
+var ErrBalanceArchived = errors.New("balance history archived; only snapshot times are available")
+
+// BalanceSnapshot is a wallet's balance at AsOf: the sum of entries created
+// before AsOf.
+type BalanceSnapshot struct {
+	WorkspaceID  string    `json:"workspace_id" db:"workspace_id"`
+	WalletID     string    `json:"wallet_id" db:"wallet_id"`
+	Currency     string    `json:"currency" db:"currency"`
+	AsOf         time.Time `json:"as_of" db:"as_of"`
+	BalanceMinor int64     `json:"balance_minor" db:"balance_minor"`
+	HeldMinor    int64     `json:"held_minor" db:"held_minor"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// checkpointAsOf is when cp applies: the end of its archived partition.
+func checkpointAsOf(cp LedgerCheckpoint) (time.Time, bool) {
+	p, ok := parseLedgerPartition(cp.Partition)
+	return p.To, ok
+}
+
+// balanceBase picks where to start summing the ledger for the balance at t: the
+// latest snapshot at or before t, or the archive checkpoint when it is later.
+// ok=false means entries before t are archived and no snapshot is exactly at t.
+func balanceBase(snap BalanceSnapshot, haveSnap bool, cp LedgerCheckpoint, t time.Time) (BalanceSnapshot, bool) {
+	base := BalanceSnapshot{}
+	if haveSnap {
+		base = snap
+	}
+	if cp.Seq == 0 {
+		return base, true
+	}
+	cpAt, ok := checkpointAsOf(cp)
+	if !ok {
+		return BalanceSnapshot{}, false
+	}
+	if !cpAt.After(t) {
+		if cpAt.After(base.AsOf) {
+			base = BalanceSnapshot{AsOf: cpAt, BalanceMinor: cp.PostedMinor, HeldMinor: cp.HeldMinor}
+		}
+		return base, true
+	}
+	// t is inside the archive: nothing may be summed.
+	return base, haveSnap && base.AsOf.Equal(t)
+}
+
+// BalanceAt returns the wallet's balance at t (entries created before t).
+func (s *Service) BalanceAt(ctx context.Context, workspaceID, walletID string, t time.Time) (BalanceSnapshot, error) {
+	if workspaceID == "" || walletID == "" || t.IsZero() || t.After(s.clock()) {
+		return BalanceSnapshot{}, ErrInvalidArgument
+	}
+	ctx, done := utils.TrackQuery(reportQuery(ctx, "BalanceAt"))
+	out, err := s.balanceAt(ctx, workspaceID, walletID, t.UTC())
+	done(err)
+	return out, err
+}
+
+func (s *Service) balanceAt(ctx context.Context, workspaceID, walletID string, t time.Time) (BalanceSnapshot, error) {
+	cur, err := getBalance(ctx, s.db, workspaceID, walletID)
+	if err != nil {
+		return BalanceSnapshot{}, err
+	}
+	snap, haveSnap, err := latestBalanceSnapshot(ctx, s.db, workspaceID, walletID, t)
+	if err != nil {
+		return BalanceSnapshot{}, err
+	}
+	cp, err := getLedgerCheckpoint(ctx, s.db, workspaceID, walletID)
+	if err != nil {
+		return BalanceSnapshot{}, err
+	}
+	base, ok := balanceBase(snap, haveSnap, cp, t)
+	if !ok {
+		return BalanceSnapshot{}, ErrBalanceArchived
+	}
+	out := BalanceSnapshot{
+		WorkspaceID:  workspaceID,
+		WalletID:     walletID,
+		Currency:     cur.Currency,
+		AsOf:         t,
+		BalanceMinor: base.BalanceMinor,
+		HeldMinor:    base.HeldMinor,
+	}
+	if base.AsOf.Before(t) {
+		posted, held, err := sumLedgerBetween(ctx, s.db, workspaceID, walletID, base.AsOf, t)
+		if err != nil {
+			return BalanceSnapshot{}, err
+		}
+		out.BalanceMinor += posted
+		out.HeldMinor += held
+	}
+	return out, nil
+}
+
+// BalanceSnapshotter writes nightly balance snapshots. Run a single instance
+// (e.g. hourly; a day already snapshotted is rewritten with the same values).
+type BalanceSnapshotter struct {
+	Wallet *Service
+	// Settle is how long after midnight UTC the day is snapshotted, so money
+	// operations started before midnight have committed. Defaults to 15 minutes.
+	Settle time.Duration
+	Now    func() time.Time
+}
+
+// RunOnce snapshots every wallet at the latest settled UTC midnight and returns
+// how many snapshots it wrote.
+func (b BalanceSnapshotter) RunOnce(ctx context.Context) (int, error) {
+	if b.Wallet == nil {
+		return 0, errors.New("wallet: balance snapshotter not configured")
+	}
+	settle := b.Settle
+	if settle <= 0 {
+		settle = 15 * time.Minute
+	}
+	asOf := b.now().Add(-settle).Truncate(24 * time.Hour)
+	return b.Wallet.SnapshotBalances(ctx, asOf)
+}
+
+// SnapshotBalances stores every wallet's balance at asOf (wallets created since
+// are skipped) and returns how many snapshots it wrote.
+func (s *Service) SnapshotBalances(ctx context.Context, asOf time.Time) (int, error) {
+	if asOf.IsZero() || asOf.After(s.clock()) {
+		return 0, ErrInvalidArgument
+	}
+	asOf = asOf.UTC()
+	ctx = utils.WithQuery(ctx, "wallet", "SnapshotBalances", utils.BatchBudget)
+	qctx, done := utils.TrackQuery(ctx)
+	wallets, err := listWalletsCreatedBefore(qctx, s.db, asOf)
+	done(err)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, w := range wallets {
+		if ctx.Err() != nil {
+			return n, ctx.Err()
+		}
+		qctx, done := utils.TrackQuery(reportQuery(ctx, "SnapshotBalance"))
+		snap, err := s.balanceAt(qctx, w.WorkspaceID, w.ID, asOf)
+		if err == nil {
+			snap.CreatedAt = s.clock().UTC()
+			err = upsertBalanceSnapshot(qctx, s.db, snap)
+		}
+		done(err)
+		if err != nil {
+			return n, fmt.Errorf("snapshot wallet %s: %w", w.ID, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Run snapshots on every tick until ctx is canceled.
+func (b BalanceSnapshotter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := b.RunOnce(ctx); err != nil {
+				logger.From(ctx).Error("balance snapshot failed", "err", err)
+			}
+		}
+	}
+}
+
+func (b BalanceSnapshotter) now() time.Time {
+	if b.Now != nil {
+		return b.Now().UTC()
+	}
+	return time.Now().UTC()
+}
@@ -0,0 +1,67 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestBalanceBase(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	snap := BalanceSnapshot{AsOf: day(3), BalanceMinor: 700, HeldMinor: 50}
+	// Archived through February: the checkpoint applies from March 1st.
+	cp := LedgerCheckpoint{Seq: 40, Partition: "wallet_ledger_p202402", PostedMinor: 500, HeldMinor: 20}
+	noon := day(5).Add(12 * time.Hour)
+
+	cases := []struct {
+		name     string
+		haveSnap bool
+		cp       LedgerCheckpoint
+		at       time.Time
+		want     BalanceSnapshot
+		ok       bool
+	}{
+		{"nothing: sum from the start", false, LedgerCheckpoint{}, noon, BalanceSnapshot{}, true},
+		{"latest snapshot", true, LedgerCheckpoint{}, noon, snap, true},
+		{"snapshot newer than the checkpoint", true, cp, noon, snap, true},
+		{"checkpoint without snapshots", false, cp, noon, BalanceSnapshot{AsOf: day(1), BalanceMinor: 500, HeldMinor: 20}, true},
+		{"at the checkpoint", false, cp, day(1), BalanceSnapshot{AsOf: day(1), BalanceMinor: 500, HeldMinor: 20}, true},
+		{"inside the archive at a snapshot", true, LedgerCheckpoint{Seq: 90, Partition: "wallet_ledger_p202403"}, day(3), snap, true},
+		{"inside the archive between snapshots", true, LedgerCheckpoint{Seq: 90, Partition: "wallet_ledger_p202403"}, noon, BalanceSnapshot{}, false},
+		{"inside the archive without snapshots", false, cp, day(1).Add(-time.Hour), BalanceSnapshot{}, false},
+	}
+	for _, tc := range cases {
+		s := BalanceSnapshot{}
+		if tc.haveSnap {
+			s = snap
+		}
+		got, ok := balanceBase(s, tc.haveSnap, tc.cp, tc.at)
+		if ok != tc.ok || (ok && (!got.AsOf.Equal(tc.want.AsOf) || got.BalanceMinor != tc.want.BalanceMinor || got.HeldMinor != tc.want.HeldMinor)) {
+			t.Fatalf("%s: got %+v %v, want %+v %v", tc.name, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestBalanceAt_RejectsInvalidArgs(t *testing.T) {
+	svc := NewService((*sql.DB)(nil))
+	now := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	svc.clock = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := svc.BalanceAt(ctx, "ws", "", now); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without wallet, got %v", err)
+	}
+	if _, err := svc.BalanceAt(ctx, "ws", "w", time.Time{}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without time, got %v", err)
+	}
+	if _, err := svc.BalanceAt(ctx, "ws", "w", now.Add(time.Hour)); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument for the future, got %v", err)
+	}
+	if _, err := svc.SnapshotBalances(ctx, now.Add(time.Hour)); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument for a future snapshot, got %v", err)
+	}
+	if _, err := (BalanceSnapshotter{}).RunOnce(ctx); err == nil {
+		t.Fatalf("expected unconfigured snapshotter to fail")
+	}
+}
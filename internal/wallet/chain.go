@@ -0,0 +1,197 @@
+package wallet
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"telecom-platform/pkg/utils"
+)
+
+// Ledger tamper evidence.
+//
+// Each wallet's ledger is a hash chain: entry N stores the hash of entry N-1
+// (PrevHash) and Hash = ChainHash(PrevHash, contents). Any retroactive change
+// to wallet_ledger (UPDATE, DELETE or an out-of-band INSERT) breaks the chain.
+//
+// Deleting the newest entries leaves a shorter chain that still verifies, so a
+// verification reports the chain head (HeadSeq/HeadHash). Auditors record it and
+// pass it back later as a LedgerAnchor: the anchored entry must still exist with
+// the same hash, which proves nothing up to that point was rewritten or removed.
+//
+// Once old entries are archived (see archive.go), the live chain starts after the
+// wallet's LedgerCheckpoint and is verified from there.
+
+// ChainVerification is the outcome of verifying one wallet's ledger chain.
+type ChainVerification struct {
+	WorkspaceID string `json:"workspace_id"`
+	WalletID    string `json:"wallet_id"`
+	Checked     int    `json:"checked"`
+	Valid       bool   `json:"valid"`
+
+	// BrokenAtSeq is the first seq whose link or hash does not match (0 when valid).
+	BrokenAtSeq int64  `json:"broken_at_seq,omitempty"`
+	BrokenID    string `json:"broken_id,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+
+	// HeadSeq/HeadHash identify the last verified entry (set when valid).
+	HeadSeq  int64  `json:"head_seq"`
+	HeadHash string `json:"head_hash,omitempty"`
+
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+// LedgerAnchor is a previously recorded chain head.
+type LedgerAnchor struct {
+	Seq  int64  `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// LedgerHash computes the chain hash for e given the previous hash.
+// Field order is part of the stored format; do not reorder.
+//
+// Metadata is hashed as written. The JSONB metadata column is re-serialized on
+// read (key order, spacing), so wallet_ledger also keeps the written string in
+// metadata_text and chain reads use that column.
+func LedgerHash(prev string, e WalletLedger) string {
+	return utils.ChainHash(prev,
+		e.ID,
+		e.WorkspaceID,
+		e.WalletID,
+		string(e.Type),
+		strconv.FormatInt(e.AmountMinor, 10),
+		e.Currency,
+		e.ExternalRef,
+		e.IdempotencyKey,
+		e.Metadata,
+		e.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+		strconv.FormatInt(e.ChainSeq, 10),
+	)
+}
+
+// linkLedger chains e after head. CreatedAt is truncated to the microsecond
+// Postgres stores, so the hash still matches once the row is read back.
+func linkLedger(head WalletLedger, e WalletLedger) WalletLedger {
+	e.CreatedAt = e.CreatedAt.Truncate(time.Microsecond)
+	e.ChainSeq = head.ChainSeq + 1
+	e.PrevHash = head.Hash
+	e.Hash = LedgerHash(e.PrevHash, e)
+	return e
+}
+
+// VerifyLedgerEntries checks a wallet chain given in seq order.
+func VerifyLedgerEntries(entries []WalletLedger) ChainVerification {
+	return VerifyLedgerEntriesFrom(LedgerAnchor{}, entries)
+}
+
+// VerifyLedgerEntriesFrom checks entries that continue the chain after start
+// (the zero anchor is the beginning of the chain).
+func VerifyLedgerEntriesFrom(start LedgerAnchor, entries []WalletLedger) ChainVerification {
+	out := ChainVerification{Valid: true}
+	prev := WalletLedger{ChainSeq: start.Seq, Hash: start.Hash}
+	for _, e := range entries {
+		out.Checked++
+		reason := ""
+		switch {
+		case e.ChainSeq != prev.ChainSeq+1:
+			reason = "sequence gap"
+		case e.PrevHash != prev.Hash:
+			reason = "previous hash mismatch"
+		case e.Hash != LedgerHash(e.PrevHash, e):
+			reason = "row contents modified"
+		}
+		if reason != "" {
+			out.Valid = false
+			out.BrokenAtSeq = e.ChainSeq
+			out.BrokenID = e.ID
+			out.Reason = reason
+			return out
+		}
+		prev = e
+	}
+	out.HeadSeq = prev.ChainSeq
+	out.HeadHash = prev.Hash
+	return out
+}
+
+// CheckLedgerAnchor verifies entries and then that the anchored entry is still in
+// the chain with the recorded hash.
+func CheckLedgerAnchor(entries []WalletLedger, anchor LedgerAnchor) ChainVerification {
+	return CheckLedgerAnchorFrom(LedgerAnchor{}, entries, anchor)
+}
+
+// CheckLedgerAnchorFrom is CheckLedgerAnchor for entries continuing the chain
+// after start. Anchors before start are archived; check them against the archive
+// with VerifyLedgerArchive.
+func CheckLedgerAnchorFrom(start LedgerAnchor, entries []WalletLedger, anchor LedgerAnchor) ChainVerification {
+	out := VerifyLedgerEntriesFrom(start, entries)
+	if !out.Valid {
+		return out
+	}
+	reason := ""
+	switch {
+	case anchor.Seq > out.HeadSeq:
+		reason = "anchored entry missing (ledger truncated)"
+	case anchor.Seq > 0 && anchor.Seq == start.Seq && start.Hash != anchor.Hash:
+		reason = "anchored entry hash mismatch"
+	case anchor.Seq > start.Seq && entries[anchor.Seq-start.Seq-1].Hash != anchor.Hash:
+		reason = "anchored entry hash mismatch"
+	}
+	if reason != "" {
+		out.Valid = false
+		out.BrokenAtSeq = anchor.Seq
+		out.Reason = reason
+		out.HeadSeq, out.HeadHash = 0, ""
+	}
+	return out
+}
+
+// VerifyLedgerChain re-hashes a wallet's ledger chain.
+func (s *Service) VerifyLedgerChain(ctx context.Context, workspaceID, walletID string) (ChainVerification, error) {
+	return s.VerifyLedgerChainAnchor(ctx, workspaceID, walletID, LedgerAnchor{})
+}
+
+// VerifyLedgerChainAnchor re-hashes a wallet's ledger chain and checks that it
+// still contains anchor (a zero anchor skips that check).
+func (s *Service) VerifyLedgerChainAnchor(ctx context.Context, workspaceID, walletID string, anchor LedgerAnchor) (ChainVerification, error) {
+	if workspaceID == "" || walletID == "" || anchor.Seq < 0 || (anchor.Seq > 0) != (anchor.Hash != "") {
+		return ChainVerification{}, ErrInvalidArgument
+	}
+	qctx, done := utils.TrackQuery(reportQuery(ctx, "VerifyLedgerChain"))
+	cp, err := getLedgerCheckpoint(qctx, s.db, workspaceID, walletID)
+	var entries []WalletLedger
+	if err == nil {
+		entries, err = listLedgerChain(qctx, s.db, workspaceID, walletID)
+	}
+	done(err)
+	if err != nil {
+		return ChainVerification{}, err
+	}
+	out := CheckLedgerAnchorFrom(cp.Anchor(), entries, anchor)
+	out.WorkspaceID = workspaceID
+	out.WalletID = walletID
+	out.VerifiedAt = s.clock().UTC()
+	return out, nil
+}
+
+// VerifyWorkspaceLedgers verifies every wallet chain in a workspace.
+func (s *Service) VerifyWorkspaceLedgers(ctx context.Context, workspaceID string) ([]ChainVerification, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	qctx, done := utils.TrackQuery(reportQuery(ctx, "VerifyWorkspaceLedgers"))
+	ids, err := listWalletIDs(qctx, s.db, workspaceID)
+	done(err)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ChainVerification, 0, len(ids))
+	for _, id := range ids {
+		v, err := s.VerifyLedgerChain(ctx, workspaceID, id)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
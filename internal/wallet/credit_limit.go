@@ -0,0 +1,95 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"telecom-platform/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// Credit limits (overdraft).
+//
+// A wallet with CreditLimitMinor > 0 may be debited or reserved below zero, down to
+// -CreditLimitMinor. The ledger is unchanged: the negative balance is simply what
+// the posted entries sum to. Spending past the line fails with ErrCreditLimitExceeded,
+// which also matches ErrInsufficientFunds so existing "out of money" handling (e.g.
+// billing's funds-exhausted flag) keeps working.
+//
+// Only platform admins set credit limits; every change is recorded in
+// admin_wallet_actions. Lowering a limit below the current debt is allowed and
+// simply blocks further spending until the wallet is topped up.
+
+var ErrCreditLimitExceeded = fmt.Errorf("credit limit exceeded: %w", ErrInsufficientFunds)
+
+type SetCreditLimitRequest struct {
+	CreditLimitMinor int64  `json:"credit_limit_minor"`
+	Reason           string `json:"reason"`
+}
+
+// SetCreditLimit changes the wallet's credit line. A limit of 0 disables overdraft.
+func (s *Service) SetCreditLimit(ctx context.Context, workspaceID, walletID, adminUserID, adminRole string, req SetCreditLimitRequest) (Wallet, error) {
+	req.Reason = strings.TrimSpace(req.Reason)
+	if workspaceID == "" || walletID == "" || adminUserID == "" || adminRole == "" || req.Reason == "" || req.CreditLimitMinor < 0 {
+		return Wallet{}, ErrInvalidArgument
+	}
+	now := s.clock().UTC()
+	var out Wallet
+	err := utils.WithTxRetry(moneyQuery(ctx, "SetCreditLimit"), s.db, &sql.TxOptions{}, utils.DefaultTxRetry, func(ctx context.Context, tx *sql.Tx) error {
+		w, err := lockWallet(ctx, tx, workspaceID, walletID)
+		if err != nil {
+			return err
+		}
+		if w.Status == WalletStatusClosed {
+			return ErrWalletClosed
+		}
+		if w.CreditLimitMinor == req.CreditLimitMinor {
+			out = w
+			return nil
+		}
+		previous := w.CreditLimitMinor
+		w.CreditLimitMinor = req.CreditLimitMinor
+		w.UpdatedAt = now
+		if err := updateWalletCreditLimit(ctx, tx, w); err != nil {
+			return err
+		}
+		actionMeta, err := json.Marshal(map[string]int64{"previous_credit_limit_minor": previous})
+		if err != nil {
+			return err
+		}
+		if err := insertAdminAction(ctx, tx, AdminWalletAction{
+			ID:          uuid.NewString(),
+			WorkspaceID: workspaceID,
+			WalletID:    walletID,
+			AdminUserID: adminUserID,
+			AdminRole:   adminRole,
+			Action:      AdminWalletActionTypeCreditLimit,
+			Reason:      req.Reason,
+			AmountMinor: req.CreditLimitMinor,
+			Currency:    w.Currency,
+			Metadata:    string(actionMeta),
+			CreatedAt:   now,
+		}); err != nil {
+			return err
+		}
+		out = w
+		return nil
+	})
+	return out, err
+}
+
+// checkFunds reports whether amountMinor can be spent from b, naming the credit
+// line in the error when the wallet has one.
+func checkFunds(b Balance, amountMinor int64) error {
+	if b.Spendable() >= amountMinor {
+		return nil
+	}
+	if b.CreditLimitMinor > 0 {
+		return ErrCreditLimitExceeded
+	}
+	return ErrInsufficientFunds
+}
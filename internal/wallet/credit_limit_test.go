@@ -0,0 +1,50 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestCheckFunds_CreditLine(t *testing.T) {
+	cases := []struct {
+		name   string
+		bal    Balance
+		amount int64
+		want   error
+	}{
+		{"covered by balance", Balance{BalanceMinor: 100}, 100, nil},
+		{"no credit line", Balance{BalanceMinor: 100}, 101, ErrInsufficientFunds},
+		{"covered by credit line", Balance{BalanceMinor: 100, CreditLimitMinor: 50}, 150, nil},
+		{"already overdrawn", Balance{BalanceMinor: -40, CreditLimitMinor: 50}, 10, nil},
+		{"past the line", Balance{BalanceMinor: -40, CreditLimitMinor: 50}, 11, ErrCreditLimitExceeded},
+		{"holds count against the line", Balance{BalanceMinor: 0, HeldMinor: 30, CreditLimitMinor: 50}, 21, ErrCreditLimitExceeded},
+	}
+	for _, tc := range cases {
+		if err := checkFunds(tc.bal, tc.amount); err != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+	if !errors.Is(ErrCreditLimitExceeded, ErrInsufficientFunds) {
+		t.Fatalf("ErrCreditLimitExceeded must match ErrInsufficientFunds")
+	}
+}
+
+func TestSetCreditLimit_RejectsInvalidArgs(t *testing.T) {
+	svc := NewService((*sql.DB)(nil))
+	ctx := context.Background()
+
+	bad := []SetCreditLimitRequest{
+		{CreditLimitMinor: 100},
+		{CreditLimitMinor: -1, Reason: "risk review"},
+	}
+	for _, req := range bad {
+		if _, err := svc.SetCreditLimit(ctx, "ws", "w", "admin", "super_admin", req); err != ErrInvalidArgument {
+			t.Fatalf("%+v: expected ErrInvalidArgument, got %v", req, err)
+		}
+	}
+	if _, err := svc.SetCreditLimit(ctx, "ws", "w", "", "super_admin", SetCreditLimitRequest{CreditLimitMinor: 1, Reason: "x"}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without admin, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"telecom-platform/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// Wallet freezes.
+//
+// A freeze is a platform risk action (suspected fraud, chargeback, legal hold).
+// A frozen wallet rejects every new money movement with ErrWalletFrozen: credits,
+// debits and new holds. Open holds may still be released or captured, so calls
+// already in flight settle against funds that were reserved before the freeze.
+//
+// Only platform admins freeze and unfreeze; both are recorded in
+// admin_wallet_actions. The tenant's own lifecycle endpoints (enable, disable,
+// close) fail with ErrWalletFrozen, so an owner cannot lift a freeze by
+// re-enabling the wallet. Unfreeze restores the status the wallet had when it was
+// frozen (active or disabled).
+
+// ErrWalletFrozen also matches ErrWalletNotActive, so callers that already treat
+// an inactive wallet as "cannot spend" keep working.
+var ErrWalletFrozen = fmt.Errorf("wallet is frozen: %w", ErrWalletNotActive)
+
+type FreezeWalletRequest struct {
+	Reason string `json:"reason"`
+}
+
+type freezeMetadata struct {
+	PreviousStatus WalletStatus `json:"previous_status"`
+}
+
+// FreezeWallet blocks all money movement on the wallet. Freezing a frozen wallet
+// is a no-op.
+func (s *Service) FreezeWallet(ctx context.Context, workspaceID, walletID, adminUserID, adminRole string, req FreezeWalletRequest) (Wallet, error) {
+	req.Reason = strings.TrimSpace(req.Reason)
+	if workspaceID == "" || walletID == "" || adminUserID == "" || adminRole == "" || req.Reason == "" {
+		return Wallet{}, ErrInvalidArgument
+	}
+	return s.adminTransition(ctx, workspaceID, walletID, adminUserID, adminRole, req.Reason, func(ctx context.Context, tx *sql.Tx, w Wallet) (WalletStatus, AdminWalletActionType, string, error) {
+		switch w.Status {
+		case WalletStatusClosed:
+			return "", "", "", ErrWalletClosed
+		case WalletStatusFrozen:
+			return "", "", "", nil
+		}
+		meta, err := json.Marshal(freezeMetadata{PreviousStatus: w.Status})
+		if err != nil {
+			return "", "", "", err
+		}
+		return WalletStatusFrozen, AdminWalletActionTypeFreeze, string(meta), nil
+	})
+}
+
+// UnfreezeWallet lifts a freeze. Unfreezing a wallet that is not frozen is a no-op.
+func (s *Service) UnfreezeWallet(ctx context.Context, workspaceID, walletID, adminUserID, adminRole string, req FreezeWalletRequest) (Wallet, error) {
+	req.Reason = strings.TrimSpace(req.Reason)
+	if workspaceID == "" || walletID == "" || adminUserID == "" || adminRole == "" || req.Reason == "" {
+		return Wallet{}, ErrInvalidArgument
+	}
+	return s.adminTransition(ctx, workspaceID, walletID, adminUserID, adminRole, req.Reason, func(ctx context.Context, tx *sql.Tx, w Wallet) (WalletStatus, AdminWalletActionType, string, error) {
+		if w.Status != WalletStatusFrozen {
+			return "", "", "", nil
+		}
+		to := WalletStatusActive
+		freeze, ok, err := findLatestAdminAction(ctx, tx, workspaceID, walletID, AdminWalletActionTypeFreeze)
+		if err != nil {
+			return "", "", "", err
+		}
+		if ok {
+			to = restoredStatus(freeze.Metadata)
+		}
+		meta, err := json.Marshal(map[string]WalletStatus{"restored_status": to})
+		if err != nil {
+			return "", "", "", err
+		}
+		return to, AdminWalletActionTypeUnfreeze, string(meta), nil
+	})
+}
+
+// adminTransition locks the wallet, asks decide for the new status and, when it
+// changes, updates the wallet and records the admin action in one transaction.
+// decide returns an empty status for a no-op.
+func (s *Service) adminTransition(
+	ctx context.Context,
+	workspaceID, walletID, adminUserID, adminRole, reason string,
+	decide func(ctx context.Context, tx *sql.Tx, w Wallet) (WalletStatus, AdminWalletActionType, string, error),
+) (Wallet, error) {
+	now := s.clock().UTC()
+	var out Wallet
+	err := utils.WithTxRetry(moneyQuery(ctx, "adminTransition"), s.db, &sql.TxOptions{}, utils.DefaultTxRetry, func(ctx context.Context, tx *sql.Tx) error {
+		w, err := lockWallet(ctx, tx, workspaceID, walletID)
+		if err != nil {
+			return err
+		}
+		to, action, metadata, err := decide(ctx, tx, w)
+		if err != nil {
+			return err
+		}
+		if to == "" || to == w.Status {
+			out = w
+			return nil
+		}
+		w.Status = to
+		w.UpdatedAt = now
+		if err := updateWalletStatus(ctx, tx, w); err != nil {
+			return err
+		}
+		if err := insertAdminAction(ctx, tx, AdminWalletAction{
+			ID:          uuid.NewString(),
+			WorkspaceID: workspaceID,
+			WalletID:    walletID,
+			AdminUserID: adminUserID,
+			AdminRole:   adminRole,
+			Action:      action,
+			Reason:      reason,
+			Currency:    w.Currency,
+			Metadata:    metadata,
+			CreatedAt:   now,
+		}); err != nil {
+			return err
+		}
+		out = w
+		return nil
+	})
+	return out, err
+}
+
+// restoredStatus reads the pre-freeze status from a freeze action's metadata,
+// falling back to active.
+func restoredStatus(metadata string) WalletStatus {
+	var m freezeMetadata
+	if err := json.Unmarshal([]byte(metadata), &m); err != nil {
+		return WalletStatusActive
+	}
+	if m.PreviousStatus == WalletStatusDisabled {
+		return WalletStatusDisabled
+	}
+	return WalletStatusActive
+}
@@ -0,0 +1,43 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestFreezeWallet_RejectsInvalidArgs(t *testing.T) {
+	svc := NewService((*sql.DB)(nil))
+	ctx := context.Background()
+
+	if _, err := svc.FreezeWallet(ctx, "ws", "w", "admin", "super_admin", FreezeWalletRequest{Reason: "  "}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without reason, got %v", err)
+	}
+	if _, err := svc.FreezeWallet(ctx, "ws", "w", "", "super_admin", FreezeWalletRequest{Reason: "fraud review"}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without admin, got %v", err)
+	}
+	if _, err := svc.UnfreezeWallet(ctx, "ws", "", "admin", "super_admin", FreezeWalletRequest{Reason: "cleared"}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without wallet id, got %v", err)
+	}
+}
+
+func TestErrWalletFrozen_MatchesNotActive(t *testing.T) {
+	if !errors.Is(ErrWalletFrozen, ErrWalletNotActive) {
+		t.Fatalf("ErrWalletFrozen must match ErrWalletNotActive")
+	}
+}
+
+func TestRestoredStatus(t *testing.T) {
+	cases := map[string]WalletStatus{
+		`{"previous_status":"disabled"}`: WalletStatusDisabled,
+		`{"previous_status":"active"}`:   WalletStatusActive,
+		`{"previous_status":"closed"}`:   WalletStatusActive,
+		``:                               WalletStatusActive,
+	}
+	for meta, want := range cases {
+		if got := restoredStatus(meta); got != want {
+			t.Fatalf("%q: got %s, want %s", meta, got, want)
+		}
+	}
+}
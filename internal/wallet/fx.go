@@ -0,0 +1,221 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Multi-currency credits.
+//
+// A workspace may hold one wallet per currency (or several); each wallet keeps a
+// single currency for life. CreditConverted accepts a payment in another currency
+// (e.g. EUR into a USD wallet), converts it with the configured FXRates and posts an
+// ordinary credit in the wallet's currency. The original amount and the rate used
+// are recorded under the platform-reserved "_fx" metadata key, so statements and
+// reconciliation can trace every converted cent back to the payment.
+//
+// Rates are decimal strings and conversion is exact (math/big). The result is
+// rounded down to the wallet's minor unit, so a conversion never credits more
+// than the payment is worth.
+
+// FXRate converts From into To: one unit of From buys Rate units of To.
+type FXRate struct {
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	Rate   string    `json:"rate"`
+	Source string    `json:"source,omitempty"`
+	AsOf   time.Time `json:"as_of"`
+}
+
+// FXRates returns the current rate for a currency pair, or ErrNoFXRate.
+type FXRates interface {
+	Rate(ctx context.Context, from, to string) (FXRate, error)
+}
+
+var ErrNoFXRate = errors.New("no fx rate for currency pair")
+
+type ConvertedCreditRequest struct {
+	// AmountMinor is in Currency, the currency the payment was made in.
+	AmountMinor    int64  `json:"amount_minor"`
+	Currency       string `json:"currency"`
+	ExternalRef    string `json:"external_ref,omitempty"`
+	IdempotencyKey string `json:"idempotency_key"`
+	Metadata       string `json:"metadata,omitempty"`
+}
+
+// fxMetadata is stored under "_fx" on converted credits.
+type fxMetadata struct {
+	OriginalAmountMinor  int64     `json:"original_amount_minor"`
+	OriginalCurrency     string    `json:"original_currency"`
+	ConvertedAmountMinor int64     `json:"converted_amount_minor"`
+	Rate                 string    `json:"rate"`
+	RateSource           string    `json:"rate_source,omitempty"`
+	RateAsOf             time.Time `json:"rate_as_of"`
+}
+
+// CreditConverted credits the wallet with req converted into the wallet's currency.
+// A request already in the wallet's currency is a plain Credit. Retrying with the
+// same idempotency key returns the original entry even if the rate has moved since.
+func (s *Service) CreditConverted(ctx context.Context, workspaceID, walletID string, req ConvertedCreditRequest) (WalletLedger, Balance, error) {
+	req.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+	if err := validateMoneyReq(workspaceID, walletID, req.AmountMinor, req.Currency, req.IdempotencyKey); err != nil {
+		return WalletLedger{}, Balance{}, err
+	}
+	if req.AmountMinor <= 0 || !validCurrency(req.Currency) {
+		return WalletLedger{}, Balance{}, ErrInvalidArgument
+	}
+	metadata, err := NormalizeMetadata(LedgerEntryTypeCredit, req.Metadata)
+	if err != nil {
+		return WalletLedger{}, Balance{}, err
+	}
+	// The fingerprint covers the request as sent, not the converted amount, so a
+	// retry after the rate moved still replays.
+	fp := requestFingerprint("credit_converted", amountField(req.AmountMinor), req.Currency, req.ExternalRef, metadata)
+
+	// The rate lookup may call out to a provider, so it happens before the
+	// transaction; postCredit re-checks the wallet currency under lock.
+	bal, err := s.GetBalance(ctx, workspaceID, walletID)
+	if err != nil {
+		return WalletLedger{}, Balance{}, err
+	}
+	credit := CreditRequest{
+		AmountMinor:    req.AmountMinor,
+		Currency:       bal.Currency,
+		ExternalRef:    req.ExternalRef,
+		IdempotencyKey: req.IdempotencyKey,
+	}
+	if req.Currency == bal.Currency {
+		return s.postCredit(ctx, workspaceID, walletID, credit, metadata, fp)
+	}
+
+	if s.FX == nil {
+		return WalletLedger{}, Balance{}, errors.New("fx rates not configured")
+	}
+	rate, err := s.FX.Rate(ctx, req.Currency, bal.Currency)
+	if err != nil {
+		return WalletLedger{}, Balance{}, err
+	}
+	converted, err := ConvertMinor(req.AmountMinor, req.Currency, bal.Currency, rate.Rate)
+	if err != nil {
+		return WalletLedger{}, Balance{}, err
+	}
+	if converted <= 0 {
+		// Too small to be worth a minor unit of the wallet currency.
+		return WalletLedger{}, Balance{}, ErrInvalidArgument
+	}
+	credit.AmountMinor = converted
+	metadata, err = withFXMetadata(metadata, fxMetadata{
+		OriginalAmountMinor:  req.AmountMinor,
+		OriginalCurrency:     req.Currency,
+		ConvertedAmountMinor: converted,
+		Rate:                 rate.Rate,
+		RateSource:           rate.Source,
+		RateAsOf:             rate.AsOf.UTC(),
+	})
+	if err != nil {
+		return WalletLedger{}, Balance{}, err
+	}
+	return s.postCredit(ctx, workspaceID, walletID, credit, metadata, fp)
+}
+
+// ConvertMinor converts amountMinor of from into minor units of to at rate
+// (units of to per unit of from), rounding toward zero.
+func ConvertMinor(amountMinor int64, from, to, rate string) (int64, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(rate))
+	if !ok || r.Sign() <= 0 {
+		return 0, fmt.Errorf("%w: invalid fx rate %q", ErrInvalidArgument, rate)
+	}
+	v := new(big.Rat).Mul(new(big.Rat).SetInt64(amountMinor), r)
+	shift := MinorUnitExponent(to) - MinorUnitExponent(from)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(shift))), nil))
+	if shift >= 0 {
+		v.Mul(v, scale)
+	} else {
+		v.Quo(v, scale)
+	}
+	q := new(big.Int).Quo(v.Num(), v.Denom())
+	if !q.IsInt64() {
+		return 0, fmt.Errorf("%w: converted amount out of range", ErrInvalidArgument)
+	}
+	return q.Int64(), nil
+}
+
+// zeroDecimalCurrencies and threeDecimalCurrencies are the ISO 4217 exceptions
+// to two minor-unit digits.
+var (
+	zeroDecimalCurrencies = map[string]bool{
+		"BIF": true, "CLP": true, "DJF": true, "GNF": true, "ISK": true, "JPY": true,
+		"KMF": true, "KRW": true, "PYG": true, "RWF": true, "UGX": true, "UYI": true,
+		"VND": true, "VUV": true, "XAF": true, "XOF": true, "XPF": true,
+	}
+	threeDecimalCurrencies = map[string]bool{
+		"BHD": true, "IQD": true, "JOD": true, "KWD": true, "LYD": true, "OMR": true, "TND": true,
+	}
+)
+
+// MinorUnitExponent is the number of minor-unit digits of an ISO 4217 currency.
+func MinorUnitExponent(currency string) int {
+	switch {
+	case zeroDecimalCurrencies[currency]:
+		return 0
+	case threeDecimalCurrencies[currency]:
+		return 3
+	default:
+		return 2
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// withFXMetadata adds the "_fx" object to already normalized metadata.
+func withFXMetadata(metadata string, fx fxMetadata) (string, error) {
+	return withReservedMetadata(metadata, "fx", fx)
+}
+
+// StaticFXRates is an in-memory rate table, e.g. loaded from config or refreshed
+// by a job. Only pairs that were Set are quoted; inverses are not derived, so the
+// table owner decides the rate in each direction.
+type StaticFXRates struct {
+	mu    sync.RWMutex
+	rates map[string]FXRate
+}
+
+func NewStaticFXRates() *StaticFXRates {
+	return &StaticFXRates{rates: map[string]FXRate{}}
+}
+
+// Set stores r for its pair, replacing any previous rate.
+func (t *StaticFXRates) Set(r FXRate) error {
+	r.From = strings.ToUpper(strings.TrimSpace(r.From))
+	r.To = strings.ToUpper(strings.TrimSpace(r.To))
+	if !validCurrency(r.From) || !validCurrency(r.To) || r.From == r.To {
+		return ErrInvalidArgument
+	}
+	if v, ok := new(big.Rat).SetString(strings.TrimSpace(r.Rate)); !ok || v.Sign() <= 0 {
+		return fmt.Errorf("%w: invalid fx rate %q", ErrInvalidArgument, r.Rate)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rates[r.From+"/"+r.To] = r
+	return nil
+}
+
+func (t *StaticFXRates) Rate(ctx context.Context, from, to string) (FXRate, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	r, ok := t.rates[from+"/"+to]
+	if !ok {
+		return FXRate{}, fmt.Errorf("%w: %s/%s", ErrNoFXRate, from, to)
+	}
+	return r, nil
+}
@@ -0,0 +1,119 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConvertMinor(t *testing.T) {
+	cases := []struct {
+		name     string
+		amount   int64
+		from, to string
+		rate     string
+		want     int64
+	}{
+		{"eur to usd rounds down", 1000, "EUR", "USD", "1.0865", 1086},
+		{"exact", 2500, "GBP", "USD", "1.2", 3000},
+		{"into zero-decimal currency", 1000, "EUR", "JPY", "161.5", 1615},
+		{"from zero-decimal currency", 1000, "JPY", "USD", "0.0067", 670},
+		{"into three-decimal currency", 100, "USD", "KWD", "0.307", 307},
+		{"too small for a minor unit", 1, "JPY", "EUR", "0.006", 0},
+	}
+	for _, tc := range cases {
+		got, err := ConvertMinor(tc.amount, tc.from, tc.to, tc.rate)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.want, got)
+		}
+	}
+	for _, rate := range []string{"", "abc", "0", "-1.1"} {
+		if _, err := ConvertMinor(100, "EUR", "USD", rate); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("rate %q: expected ErrInvalidArgument, got %v", rate, err)
+		}
+	}
+}
+
+func TestWithFXMetadata_KeepsCallerKeys(t *testing.T) {
+	md, err := NormalizeMetadata(LedgerEntryTypeCredit, `{"payment_ref":"pi_1"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := withFXMetadata(md, fxMetadata{
+		OriginalAmountMinor:  1000,
+		OriginalCurrency:     "EUR",
+		ConvertedAmountMinor: 1086,
+		Rate:                 "1.0865",
+		RateAsOf:             time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		PaymentRef string     `json:"payment_ref"`
+		FX         fxMetadata `json:"_fx"`
+	}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.PaymentRef != "pi_1" || doc.FX.OriginalAmountMinor != 1000 || doc.FX.OriginalCurrency != "EUR" || doc.FX.Rate != "1.0865" {
+		t.Fatalf("unexpected metadata %s", out)
+	}
+
+	// Callers still cannot forge the reserved key themselves.
+	if _, err := NormalizeMetadata(LedgerEntryTypeCredit, out); !errors.Is(err, ErrInvalidMetadata) {
+		t.Fatalf("expected ErrInvalidMetadata for caller-supplied _fx, got %v", err)
+	}
+}
+
+func TestStaticFXRates(t *testing.T) {
+	ctx := context.Background()
+	rates := NewStaticFXRates()
+	if err := rates.Set(FXRate{From: "eur", To: "usd", Rate: "1.0865"}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := rates.Rate(ctx, "EUR", "USD")
+	if err != nil || r.Rate != "1.0865" {
+		t.Fatalf("expected EUR/USD rate, got %+v, %v", r, err)
+	}
+	if _, err := rates.Rate(ctx, "USD", "EUR"); !errors.Is(err, ErrNoFXRate) {
+		t.Fatalf("inverse must not be derived, got %v", err)
+	}
+	for _, bad := range []FXRate{
+		{From: "EUR", To: "EUR", Rate: "1"},
+		{From: "EUR", To: "USD", Rate: "0"},
+		{From: "EURO", To: "USD", Rate: "1.1"},
+	} {
+		if err := rates.Set(bad); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("%+v: expected ErrInvalidArgument, got %v", bad, err)
+		}
+	}
+}
+
+func TestCreditConverted_RejectsInvalidArgs(t *testing.T) {
+	svc := NewService((*sql.DB)(nil))
+	ctx := context.Background()
+
+	bad := []ConvertedCreditRequest{
+		{AmountMinor: 100, Currency: "EUR"},
+		{AmountMinor: -100, Currency: "EUR", IdempotencyKey: "k"},
+		{AmountMinor: 100, Currency: "EURO", IdempotencyKey: "k"},
+		{AmountMinor: 100, IdempotencyKey: "k"},
+	}
+	for _, req := range bad {
+		if _, _, err := svc.CreditConverted(ctx, "ws", "w", req); err != ErrInvalidArgument {
+			t.Fatalf("%+v: expected ErrInvalidArgument, got %v", req, err)
+		}
+	}
+	if _, _, err := svc.CreditConverted(ctx, "ws", "w", ConvertedCreditRequest{
+		AmountMinor: 100, Currency: "EUR", IdempotencyKey: "k", Metadata: `{"_fx":{}}`,
+	}); !errors.Is(err, ErrInvalidMetadata) {
+		t.Fatalf("expected ErrInvalidMetadata, got %v", err)
+	}
+}
@@ -0,0 +1,379 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"telecom-platform/internal/events"
+	"telecom-platform/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// Holds (reserve / capture / release).
+//
+// A call reserves its estimated cost at answer time and captures the actual cost
+// at hangup. Each step is a ledger entry so the ledger alone explains available
+// balance:
+// - Reserve posts a hold entry (AmountMinor = -amount) and raises held_minor.
+// - Release posts a release entry (+amount) and lowers held_minor.
+// - Capture releases the hold and posts a regular debit for the actual cost.
+//
+// So sum(credit, debit) = balance_minor and sum(all entries) = available balance.
+// The hold's lifecycle lives in wallet_holds; the ledger stays append-only.
+
+type HoldStatus string
+
+const (
+	HoldStatusOpen     HoldStatus = "open"
+	HoldStatusCaptured HoldStatus = "captured"
+	HoldStatusReleased HoldStatus = "released"
+)
+
+// WalletHold is an outstanding or settled reservation.
+type WalletHold struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	WalletID    string `json:"wallet_id" db:"wallet_id"`
+
+	// LedgerID is the hold entry that placed the reservation.
+	LedgerID string `json:"ledger_id" db:"ledger_id"`
+
+	AmountMinor int64  `json:"amount_minor" db:"amount_minor"` // positive
+	Currency    string `json:"currency" db:"currency"`
+	ExternalRef string `json:"external_ref" db:"external_ref"` // call_id
+
+	Status HoldStatus `json:"status" db:"status"`
+
+	// CaptureLedgerID/CapturedMinor are set once the hold is captured.
+	CaptureLedgerID string `json:"capture_ledger_id,omitempty" db:"capture_ledger_id"`
+	CapturedMinor   int64  `json:"captured_minor" db:"captured_minor"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type HoldRequest struct {
+	AmountMinor    int64  `json:"amount_minor"`
+	Currency       string `json:"currency"`
+	ExternalRef    string `json:"external_ref"` // required: the call (or usage) being reserved for
+	IdempotencyKey string `json:"idempotency_key"`
+	Metadata       string `json:"metadata,omitempty"`
+}
+
+type CaptureRequest struct {
+	// AmountMinor is the actual cost. Above the held amount, the excess must be
+	// covered by available balance.
+	AmountMinor    int64  `json:"amount_minor"`
+	IdempotencyKey string `json:"idempotency_key"`
+	Metadata       string `json:"metadata,omitempty"`
+}
+
+var (
+	ErrHoldNotFound = errors.New("hold not found")
+	ErrHoldNotOpen  = errors.New("hold is not open")
+)
+
+// Reserve places a hold for req.AmountMinor against available balance.
+// Retrying with the same idempotency key returns the original hold.
+func (s *Service) Reserve(ctx context.Context, workspaceID, walletID string, req HoldRequest) (WalletHold, Balance, error) {
+	if err := validateMoneyReq(workspaceID, walletID, req.AmountMinor, req.Currency, req.IdempotencyKey); err != nil {
+		return WalletHold{}, Balance{}, err
+	}
+	req.ExternalRef = strings.TrimSpace(req.ExternalRef)
+	if req.AmountMinor <= 0 || req.ExternalRef == "" {
+		return WalletHold{}, Balance{}, ErrInvalidArgument
+	}
+	metadata, err := holdMetadata(req.Metadata, req.ExternalRef)
+	if err != nil {
+		return WalletHold{}, Balance{}, err
+	}
+	fp := requestFingerprint("hold", amountField(req.AmountMinor), req.Currency, req.ExternalRef, metadata)
+
+	now := s.clock().UTC()
+	var outHold WalletHold
+	var outBal Balance
+
+	ctx, op := s.Metrics.track(ctx, "Reserve")
+	err = utils.WithTxRetry(moneyQuery(ctx, "Reserve"), s.db, &sql.TxOptions{}, utils.DefaultTxRetry, func(ctx context.Context, tx *sql.Tx) error {
+		w, err := lockWallet(ctx, tx, workspaceID, walletID)
+		if err != nil {
+			return err
+		}
+		if w.Currency != req.Currency {
+			return ErrInvalidArgument
+		}
+
+		if existing, ok, err := replayIdempotent(ctx, tx, workspaceID, walletID, req.IdempotencyKey, fp, sameEntry(LedgerEntryTypeHold, -req.AmountMinor, req.ExternalRef)); err != nil {
+			return err
+		} else if ok {
+			h, found, err := findHoldByLedger(ctx, tx, workspaceID, walletID, existing.ID)
+			if err != nil {
+				return err
+			}
+			if !found {
+				// The key was used for a different kind of entry.
+				return ErrInvalidArgument
+			}
+			outHold = h
+			outBal, err = getBalanceTx(ctx, tx, workspaceID, walletID)
+			return err
+		}
+
+		if err := checkCanSpend(w); err != nil {
+			return err
+		}
+		b, err := getBalanceForUpdate(ctx, tx, workspaceID, walletID)
+		if err != nil {
+			return err
+		}
+		if err := checkFunds(b, req.AmountMinor); err != nil {
+			return err
+		}
+		if err := checkSpendCaps(ctx, tx, b, req.AmountMinor, now); err != nil {
+			return err
+		}
+
+		entry, err := appendLedger(ctx, tx, WalletLedger{
+			ID:             uuid.NewString(),
+			WorkspaceID:    workspaceID,
+			WalletID:       walletID,
+			Type:           LedgerEntryTypeHold,
+			AmountMinor:    -req.AmountMinor,
+			Currency:       req.Currency,
+			ExternalRef:    req.ExternalRef,
+			IdempotencyKey: req.IdempotencyKey,
+			Metadata:       metadata,
+			CreatedAt:      now,
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.recordIdempotency(ctx, tx, entry, fp); err != nil {
+			return err
+		}
+		h := WalletHold{
+			ID:          uuid.NewString(),
+			WorkspaceID: workspaceID,
+			WalletID:    walletID,
+			LedgerID:    entry.ID,
+			AmountMinor: req.AmountMinor,
+			Currency:    req.Currency,
+			ExternalRef: req.ExternalRef,
+			Status:      HoldStatusOpen,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := insertHold(ctx, tx, h); err != nil {
+			return err
+		}
+		outBal, err = applyHoldDelta(ctx, tx, workspaceID, walletID, req.AmountMinor, now)
+		if err != nil {
+			return err
+		}
+		if err := emitEvent(ctx, tx, events.TypeWalletHoldPlaced, entry, outBal, h.ID); err != nil {
+			return err
+		}
+		outHold = h
+		return nil
+	})
+	op.end(err)
+	return outHold, outBal, err
+}
+
+// Capture settles an open hold: the reservation is released and the actual cost
+// debited in one transaction. Retrying with the same idempotency key returns the
+// original debit.
+func (s *Service) Capture(ctx context.Context, workspaceID, walletID, holdID string, req CaptureRequest) (WalletLedger, Balance, error) {
+	if workspaceID == "" || walletID == "" || holdID == "" || req.IdempotencyKey == "" || req.AmountMinor <= 0 {
+		return WalletLedger{}, Balance{}, ErrInvalidArgument
+	}
+	metadata, err := NormalizeMetadata(LedgerEntryTypeDebit, req.Metadata)
+	if err != nil {
+		return WalletLedger{}, Balance{}, err
+	}
+	fp := requestFingerprint("capture", holdID, amountField(req.AmountMinor), metadata)
+
+	now := s.clock().UTC()
+	var outLedger WalletLedger
+	var outBal Balance
+
+	ctx, op := s.Metrics.track(ctx, "Capture")
+	err = utils.WithTxRetry(moneyQuery(ctx, "Capture"), s.db, &sql.TxOptions{}, utils.DefaultTxRetry, func(ctx context.Context, tx *sql.Tx) error {
+		if _, err := lockWallet(ctx, tx, workspaceID, walletID); err != nil {
+			return err
+		}
+		// A capture's debit is the one the hold records as its capture.
+		capturedBy := func(e WalletLedger) (bool, error) {
+			if e.Type != LedgerEntryTypeDebit || e.AmountMinor != -req.AmountMinor {
+				return false, nil
+			}
+			h, err := getHoldForUpdate(ctx, tx, workspaceID, walletID, holdID)
+			if err != nil {
+				return false, err
+			}
+			return h.CaptureLedgerID == e.ID, nil
+		}
+		if existing, ok, err := replayIdempotent(ctx, tx, workspaceID, walletID, req.IdempotencyKey, fp, capturedBy); err != nil {
+			return err
+		} else if ok {
+			outLedger = existing
+			outBal, err = getBalanceTx(ctx, tx, workspaceID, walletID)
+			return err
+		}
+
+		h, err := getHoldForUpdate(ctx, tx, workspaceID, walletID, holdID)
+		if err != nil {
+			return err
+		}
+		if h.Status != HoldStatusOpen {
+			return ErrHoldNotOpen
+		}
+		b, err := getBalanceForUpdate(ctx, tx, workspaceID, walletID)
+		if err != nil {
+			return err
+		}
+		// The hold itself is available to this capture; only the excess needs funds.
+		if excess := req.AmountMinor - h.AmountMinor; excess > 0 {
+			if err := checkFunds(b, excess); err != nil {
+				return err
+			}
+		}
+
+		if _, err := appendLedger(ctx, tx, releaseEntry(h, now)); err != nil {
+			return err
+		}
+		debit, err := appendLedger(ctx, tx, WalletLedger{
+			ID:             uuid.NewString(),
+			WorkspaceID:    workspaceID,
+			WalletID:       walletID,
+			Type:           LedgerEntryTypeDebit,
+			AmountMinor:    -req.AmountMinor,
+			Currency:       h.Currency,
+			ExternalRef:    h.ExternalRef,
+			IdempotencyKey: req.IdempotencyKey,
+			Metadata:       metadata,
+			CreatedAt:      now,
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.recordIdempotency(ctx, tx, debit, fp); err != nil {
+			return err
+		}
+
+		h.Status = HoldStatusCaptured
+		h.CaptureLedgerID = debit.ID
+		h.CapturedMinor = req.AmountMinor
+		h.UpdatedAt = now
+		if err := updateHold(ctx, tx, h); err != nil {
+			return err
+		}
+		if _, err := applyHoldDelta(ctx, tx, workspaceID, walletID, -h.AmountMinor, now); err != nil {
+			return err
+		}
+		outBal, err = applyBalanceDelta(ctx, tx, workspaceID, walletID, h.Currency, -req.AmountMinor, now)
+		if err != nil {
+			return err
+		}
+		if err := emitEvent(ctx, tx, events.TypeWalletDebited, debit, outBal, h.ID); err != nil {
+			return err
+		}
+		outLedger = debit
+		return nil
+	})
+	op.end(err)
+	return outLedger, outBal, err
+}
+
+// Release returns an open hold to available balance (e.g. the call never
+// connected). Releasing an already released hold is a no-op.
+func (s *Service) Release(ctx context.Context, workspaceID, walletID, holdID string) (WalletHold, Balance, error) {
+	if workspaceID == "" || walletID == "" || holdID == "" {
+		return WalletHold{}, Balance{}, ErrInvalidArgument
+	}
+
+	now := s.clock().UTC()
+	var outHold WalletHold
+	var outBal Balance
+
+	ctx, op := s.Metrics.track(ctx, "Release")
+	err := utils.WithTxRetry(moneyQuery(ctx, "Release"), s.db, &sql.TxOptions{}, utils.DefaultTxRetry, func(ctx context.Context, tx *sql.Tx) error {
+		if _, err := lockWallet(ctx, tx, workspaceID, walletID); err != nil {
+			return err
+		}
+		h, err := getHoldForUpdate(ctx, tx, workspaceID, walletID, holdID)
+		if err != nil {
+			return err
+		}
+		switch h.Status {
+		case HoldStatusReleased:
+			outHold = h
+			outBal, err = getBalanceTx(ctx, tx, workspaceID, walletID)
+			return err
+		case HoldStatusCaptured:
+			return ErrHoldNotOpen
+		}
+
+		if _, err := appendLedger(ctx, tx, releaseEntry(h, now)); err != nil {
+			return err
+		}
+		h.Status = HoldStatusReleased
+		h.UpdatedAt = now
+		if err := updateHold(ctx, tx, h); err != nil {
+			return err
+		}
+		outBal, err = applyHoldDelta(ctx, tx, workspaceID, walletID, -h.AmountMinor, now)
+		if err != nil {
+			return err
+		}
+		outHold = h
+		return nil
+	})
+	op.end(err)
+	return outHold, outBal, err
+}
+
+// releaseEntry is the ledger entry returning h to available balance. Its
+// idempotency key is derived from the hold so a hold can only be released once.
+func releaseEntry(h WalletHold, now time.Time) WalletLedger {
+	md, _ := json.Marshal(map[string]string{"call_id": h.ExternalRef, "hold_ledger_id": h.LedgerID})
+	return WalletLedger{
+		ID:             uuid.NewString(),
+		WorkspaceID:    h.WorkspaceID,
+		WalletID:       h.WalletID,
+		Type:           LedgerEntryTypeRelease,
+		AmountMinor:    h.AmountMinor,
+		Currency:       h.Currency,
+		ExternalRef:    h.ExternalRef,
+		IdempotencyKey: "hold-release:" + h.ID,
+		Metadata:       string(md),
+		CreatedAt:      now,
+	}
+}
+
+// holdMetadata validates caller metadata for a hold entry, filling call_id from
+// the hold's external reference when the caller did not set it.
+func holdMetadata(raw, externalRef string) (string, error) {
+	doc := map[string]any{}
+	if strings.TrimSpace(raw) != "" {
+		dec := json.NewDecoder(strings.NewReader(raw))
+		dec.UseNumber()
+		if err := dec.Decode(&doc); err != nil || doc == nil {
+			return "", fmt.Errorf("%w: must be a json object", ErrInvalidMetadata)
+		}
+	}
+	if _, ok := doc["call_id"]; !ok {
+		doc["call_id"] = externalRef
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidMetadata, err)
+	}
+	return NormalizeMetadata(LedgerEntryTypeHold, string(b))
+}
@@ -0,0 +1,59 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBalance_AvailableExcludesHolds(t *testing.T) {
+	b := Balance{BalanceMinor: 1000, HeldMinor: 300}
+	if b.Available() != 700 {
+		t.Fatalf("expected 700 available, got %d", b.Available())
+	}
+}
+
+func TestWalletService_Holds_RejectInvalidArgs(t *testing.T) {
+	svc := NewService((*sql.DB)(nil))
+	ctx := context.Background()
+
+	if _, _, err := svc.Reserve(ctx, "ws", "w", HoldRequest{AmountMinor: 100, Currency: "USD", IdempotencyKey: "k"}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without external_ref, got %v", err)
+	}
+	if _, _, err := svc.Reserve(ctx, "ws", "w", HoldRequest{AmountMinor: -5, Currency: "USD", ExternalRef: "call-1", IdempotencyKey: "k"}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument for negative amount, got %v", err)
+	}
+	if _, _, err := svc.Reserve(ctx, "ws", "w", HoldRequest{AmountMinor: 100, Currency: "USD", ExternalRef: "call-1", IdempotencyKey: "k", Metadata: `{"call_id":7}`}); !errors.Is(err, ErrInvalidMetadata) {
+		t.Fatalf("expected ErrInvalidMetadata, got %v", err)
+	}
+	if _, _, err := svc.Capture(ctx, "ws", "w", "", CaptureRequest{AmountMinor: 100, IdempotencyKey: "k"}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without hold id, got %v", err)
+	}
+	if _, _, err := svc.Capture(ctx, "ws", "w", "h", CaptureRequest{AmountMinor: 100}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without idempotency key, got %v", err)
+	}
+	if _, _, err := svc.Release(ctx, "ws", "", "h"); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without wallet id, got %v", err)
+	}
+}
+
+func TestHoldMetadata_DefaultsCallID(t *testing.T) {
+	md, err := holdMetadata("", "call-1")
+	if err != nil || md != `{"call_id":"call-1"}` {
+		t.Fatalf("unexpected metadata %q err=%v", md, err)
+	}
+	md, err = holdMetadata(`{"call_id":"other","estimated_seconds":120}`, "call-1")
+	if err != nil || md != `{"call_id":"other","estimated_seconds":120}` {
+		t.Fatalf("caller call_id must win, got %q err=%v", md, err)
+	}
+
+	rel := releaseEntry(WalletHold{ID: "h1", LedgerID: "l1", AmountMinor: 250, ExternalRef: "call-1"}, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	if rel.Type != LedgerEntryTypeRelease || rel.AmountMinor != 250 || rel.IdempotencyKey != "hold-release:h1" {
+		t.Fatalf("unexpected release entry %+v", rel)
+	}
+	if _, err := NormalizeMetadata(LedgerEntryTypeRelease, rel.Metadata); err != nil {
+		t.Fatalf("release metadata must satisfy its schema: %v", err)
+	}
+}
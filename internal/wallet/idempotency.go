@@ -0,0 +1,193 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"telecom-platform/pkg/logger"
+	"telecom-platform/pkg/utils"
+)
+
+// Idempotency keys.
+//
+// Every money operation takes an idempotency key. The first use of a key is
+// recorded in wallet_idempotency_keys with a fingerprint of the request (the
+// operation and every field that decides what gets posted) and the ledger entry
+// it produced, in the same transaction as the entry. A retry with the same key and
+// the same request replays that entry; the same key with a different request fails
+// with ErrIdempotencyConflict instead of returning the earlier entry.
+//
+// Records are kept for IdempotencyTTL (DefaultIdempotencyTTL when zero), then
+// removed by IdempotencyPurger. Ledger entries keep their key forever. A key
+// whose record was purged, that was used before fingerprints were recorded, or
+// whose record was backfilled without a fingerprint, is resolved against the
+// entry itself: a request that would post the same entry (type, amount and
+// reference, see entryMatcher) replays it, anything else is a conflict.
+
+var ErrIdempotencyConflict = errors.New("idempotency key reused with a different request")
+
+// DefaultIdempotencyTTL comfortably outlives any client retry policy.
+const DefaultIdempotencyTTL = 30 * 24 * time.Hour
+
+// IdempotencyKey records what a key was first used for. Fingerprint is empty for
+// records backfilled from existing ledger entries.
+type IdempotencyKey struct {
+	WorkspaceID string    `json:"workspace_id" db:"workspace_id"`
+	WalletID    string    `json:"wallet_id" db:"wallet_id"`
+	Key         string    `json:"key" db:"key"`
+	Fingerprint string    `json:"fingerprint" db:"fingerprint"`
+	LedgerID    string    `json:"ledger_id" db:"ledger_id"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at" db:"expires_at"`
+}
+
+// requestFingerprint identifies a request by its operation and fields, given in a
+// fixed order per operation.
+func requestFingerprint(op string, fields ...string) string {
+	return utils.ChainHash(op, fields...)
+}
+
+func amountField(n int64) string { return strconv.FormatInt(n, 10) }
+
+// entryMatcher reports whether a stored entry is the one the request would post.
+// It decides keys that have no fingerprint to compare.
+type entryMatcher func(e WalletLedger) (bool, error)
+
+// sameEntry matches entries by type, signed amount and external reference.
+func sameEntry(typ LedgerEntryType, amountMinor int64, externalRef string) entryMatcher {
+	return func(e WalletLedger) (bool, error) {
+		return e.Type == typ && e.AmountMinor == amountMinor && e.ExternalRef == externalRef, nil
+	}
+}
+
+// replayIdempotent resolves key inside a money transaction (after lockWallet).
+// ok reports that the request was already executed and returns its entry.
+func replayIdempotent(ctx context.Context, tx *sql.Tx, workspaceID, walletID, key, fingerprint string, match entryMatcher) (WalletLedger, bool, error) {
+	rec, found, err := getIdempotencyKey(ctx, tx, workspaceID, walletID, key)
+	if err != nil {
+		return WalletLedger{}, false, err
+	}
+	var e WalletLedger
+	switch {
+	case found && rec.Fingerprint != "":
+		if rec.Fingerprint != fingerprint {
+			return WalletLedger{}, false, ErrIdempotencyConflict
+		}
+		if e, err = getLedgerEntry(ctx, tx, workspaceID, walletID, rec.LedgerID); err != nil {
+			return WalletLedger{}, false, err
+		}
+		markReplayed(ctx)
+		return e, true, nil
+	case found:
+		// Backfilled record: only the entry is known.
+		if e, err = getLedgerEntry(ctx, tx, workspaceID, walletID, rec.LedgerID); err != nil {
+			return WalletLedger{}, false, err
+		}
+	default:
+		// No record: the key is new unless an entry already carries it.
+		var used bool
+		if e, used, err = findLedgerByIdempotency(ctx, tx, workspaceID, walletID, key); err != nil {
+			return WalletLedger{}, false, err
+		} else if !used {
+			return WalletLedger{}, false, nil
+		}
+	}
+	if ok, err := match(e); err != nil {
+		return WalletLedger{}, false, err
+	} else if !ok {
+		return WalletLedger{}, false, ErrIdempotencyConflict
+	}
+	markReplayed(ctx)
+	return e, true, nil
+}
+
+// recordIdempotency stores the key for the entry just posted by the request.
+func (s *Service) recordIdempotency(ctx context.Context, tx *sql.Tx, e WalletLedger, fingerprint string) error {
+	ttl := s.idempotencyTTL()
+	return insertIdempotencyKey(ctx, tx, IdempotencyKey{
+		WorkspaceID: e.WorkspaceID,
+		WalletID:    e.WalletID,
+		Key:         e.IdempotencyKey,
+		Fingerprint: fingerprint,
+		LedgerID:    e.ID,
+		CreatedAt:   e.CreatedAt,
+		ExpiresAt:   e.CreatedAt.Add(ttl),
+	})
+}
+
+func (s *Service) idempotencyTTL() time.Duration {
+	if s.IdempotencyTTL <= 0 {
+		return DefaultIdempotencyTTL
+	}
+	return s.IdempotencyTTL
+}
+
+// PurgeIdempotencyKeys deletes up to limit records that expired before now and
+// returns how many were deleted.
+func (s *Service) PurgeIdempotencyKeys(ctx context.Context, now time.Time, limit int) (int64, error) {
+	if limit <= 0 {
+		return 0, ErrInvalidArgument
+	}
+	ctx, done := utils.TrackQuery(reportQuery(ctx, "PurgeIdempotencyKeys"))
+	n, err := deleteExpiredIdempotencyKeys(ctx, s.db, now, limit)
+	done(err)
+	return n, err
+}
+
+// IdempotencyPurger removes expired idempotency records in batches. Run a single
+// instance (e.g. hourly).
+type IdempotencyPurger struct {
+	Wallet *Service
+	// BatchSize defaults to 1000 rows per statement.
+	BatchSize int
+	Now       func() time.Time
+}
+
+// RunOnce purges until no expired records remain and returns how many it deleted.
+func (p IdempotencyPurger) RunOnce(ctx context.Context) (int64, error) {
+	if p.Wallet == nil {
+		return 0, errors.New("wallet: idempotency purger not configured")
+	}
+	batch := p.BatchSize
+	if batch <= 0 {
+		batch = 1000
+	}
+	now := p.now()
+	var total int64
+	for {
+		if ctx.Err() != nil {
+			return total, ctx.Err()
+		}
+		n, err := p.Wallet.PurgeIdempotencyKeys(ctx, now, batch)
+		total += n
+		if err != nil || n < int64(batch) {
+			return total, err
+		}
+	}
+}
+
+// Run purges on every tick until ctx is canceled.
+func (p IdempotencyPurger) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := p.RunOnce(ctx); err != nil {
+				logger.From(ctx).Error("idempotency key purge failed", "err", err)
+			}
+		}
+	}
+}
+
+func (p IdempotencyPurger) now() time.Time {
+	if p.Now != nil {
+		return p.Now().UTC()
+	}
+	return time.Now().UTC()
+}
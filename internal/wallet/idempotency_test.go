@@ -0,0 +1,74 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestRequestFingerprint(t *testing.T) {
+	base := requestFingerprint("debit", "100", "USD", "call:c1", `{"call_id":"c1"}`)
+	if base != requestFingerprint("debit", "100", "USD", "call:c1", `{"call_id":"c1"}`) {
+		t.Fatalf("expected a stable fingerprint")
+	}
+	others := []string{
+		requestFingerprint("credit", "100", "USD", "call:c1", `{"call_id":"c1"}`),
+		requestFingerprint("debit", "101", "USD", "call:c1", `{"call_id":"c1"}`),
+		requestFingerprint("debit", "100", "EUR", "call:c1", `{"call_id":"c1"}`),
+		requestFingerprint("debit", "100", "USD", "call:c2", `{"call_id":"c1"}`),
+		requestFingerprint("debit", "100", "USD", "call:c1", ""),
+		// Field boundaries are part of the fingerprint.
+		requestFingerprint("debit", "10", "0USD", "call:c1", `{"call_id":"c1"}`),
+	}
+	for i, fp := range others {
+		if fp == base {
+			t.Fatalf("case %d: expected a different fingerprint", i)
+		}
+	}
+}
+
+func TestPurgeIdempotencyKeys_RejectsInvalidArgs(t *testing.T) {
+	svc := NewService((*sql.DB)(nil))
+	if _, err := svc.PurgeIdempotencyKeys(context.Background(), time.Now(), 0); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without a limit, got %v", err)
+	}
+	if _, err := (IdempotencyPurger{}).RunOnce(context.Background()); err == nil {
+		t.Fatalf("expected an error from an unconfigured purger")
+	}
+}
+
+func TestEntryMatchers_ResolveKeysWithoutFingerprint(t *testing.T) {
+	debit := WalletLedger{Type: LedgerEntryTypeDebit, AmountMinor: -250, ExternalRef: "call:c1"}
+	cases := []struct {
+		name  string
+		match entryMatcher
+		want  bool
+	}{
+		{"same debit", sameEntry(LedgerEntryTypeDebit, -250, "call:c1"), true},
+		{"other amount", sameEntry(LedgerEntryTypeDebit, -300, "call:c1"), false},
+		{"other type", sameEntry(LedgerEntryTypeCredit, -250, "call:c1"), false},
+		{"other reference", sameEntry(LedgerEntryTypeDebit, -250, "call:c2"), false},
+	}
+	for _, tc := range cases {
+		if ok, err := tc.match(debit); err != nil || ok != tc.want {
+			t.Fatalf("%s: expected %v, got %v (err %v)", tc.name, tc.want, ok, err)
+		}
+	}
+
+	reversal := WalletLedger{Type: LedgerEntryTypeReversal, AmountMinor: 100, Metadata: `{"_reversal_of":"l1","note":"x"}`}
+	for _, tc := range []struct {
+		ledgerID  string
+		requested int64
+		want      bool
+	}{
+		{"l1", 100, true},
+		{"l1", 0, true}, // reverse the remainder
+		{"l1", 50, false},
+		{"l2", 100, false},
+	} {
+		if ok, _ := reversalMatch(tc.ledgerID, tc.requested)(reversal); ok != tc.want {
+			t.Fatalf("reversal of %s for %d: expected %v, got %v", tc.ledgerID, tc.requested, tc.want, ok)
+		}
+	}
+}
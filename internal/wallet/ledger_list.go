@@ -0,0 +1,134 @@
+package wallet
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"telecom-platform/pkg/utils"
+)
+
+// Ledger history listing for tenant reconciliation.
+//
+// Entries are returned newest first. Pagination is keyed on chain_seq, which is
+// dense and strictly increasing per wallet, so pages never skip or repeat an entry
+// even when new entries are posted between requests (they land before page one).
+
+const (
+	DefaultLedgerPageSize = 50
+	MaxLedgerPageSize     = 500
+)
+
+// LedgerQuery filters ListLedger. Zero fields match everything.
+type LedgerQuery struct {
+	// From/To bound created_at as [From, To).
+	From time.Time
+	To   time.Time
+
+	Types       []LedgerEntryType
+	ExternalRef string
+	Metadata    MetadataFilter
+
+	// Cursor is NextCursor from the previous page; empty starts at the newest entry.
+	Cursor string
+	Limit  int
+}
+
+type LedgerPage struct {
+	Entries []WalletLedger `json:"entries"`
+	// NextCursor is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+var knownLedgerTypes = map[LedgerEntryType]bool{
+	LedgerEntryTypeCredit:   true,
+	LedgerEntryTypeDebit:    true,
+	LedgerEntryTypeHold:     true,
+	LedgerEntryTypeRelease:  true,
+	LedgerEntryTypeReversal: true,
+}
+
+// ListLedger returns one page of a wallet's ledger matching q.
+func (s *Service) ListLedger(ctx context.Context, workspaceID, walletID string, q LedgerQuery) (LedgerPage, error) {
+	if workspaceID == "" || walletID == "" {
+		return LedgerPage{}, ErrInvalidArgument
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return LedgerPage{}, ErrInvalidArgument
+	}
+	for _, t := range q.Types {
+		if !knownLedgerTypes[t] {
+			return LedgerPage{}, ErrInvalidArgument
+		}
+	}
+	var beforeSeq int64
+	if q.Cursor != "" {
+		seq, err := decodeLedgerCursor(q.Cursor)
+		if err != nil {
+			return LedgerPage{}, err
+		}
+		beforeSeq = seq
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLedgerPageSize
+	}
+	if limit > MaxLedgerPageSize {
+		limit = MaxLedgerPageSize
+	}
+	containment := ""
+	if len(q.Metadata) > 0 {
+		c, err := q.Metadata.containment()
+		if err != nil {
+			return LedgerPage{}, err
+		}
+		containment = c
+	}
+
+	// Fetch one extra row to learn whether another page exists.
+	qctx, done := utils.TrackQuery(reportQuery(ctx, "ListLedger"))
+	entries, err := listLedger(qctx, s.db, workspaceID, walletID, q, containment, beforeSeq, limit+1)
+	done(err)
+	if err != nil {
+		return LedgerPage{}, err
+	}
+	page := LedgerPage{Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		page.NextCursor = encodeLedgerCursor(page.Entries[limit-1].ChainSeq)
+	}
+	return page, nil
+}
+
+// ParseLedgerTypes splits a comma-separated type list (e.g. "debit,credit").
+func ParseLedgerTypes(v string) []LedgerEntryType {
+	var out []LedgerEntryType
+	for _, p := range strings.Split(v, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, LedgerEntryType(p))
+		}
+	}
+	return out
+}
+
+const ledgerCursorPrefix = "seq:"
+
+// encodeLedgerCursor keeps the cursor opaque so its format can change later.
+func encodeLedgerCursor(chainSeq int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(ledgerCursorPrefix + strconv.FormatInt(chainSeq, 10)))
+}
+
+func decodeLedgerCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), ledgerCursorPrefix) {
+		return 0, fmt.Errorf("%w: invalid cursor", ErrInvalidArgument)
+	}
+	seq, err := strconv.ParseInt(strings.TrimPrefix(string(raw), ledgerCursorPrefix), 10, 64)
+	if err != nil || seq <= 0 {
+		return 0, fmt.Errorf("%w: invalid cursor", ErrInvalidArgument)
+	}
+	return seq, nil
+}
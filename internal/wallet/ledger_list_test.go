@@ -0,0 +1,53 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLedgerCursor_RoundTrip(t *testing.T) {
+	c := encodeLedgerCursor(42)
+	seq, err := decodeLedgerCursor(c)
+	if err != nil || seq != 42 {
+		t.Fatalf("round trip: %d %v", seq, err)
+	}
+	for _, bad := range []string{"42", "!!", encodeLedgerCursor(0), "c2VxOng"} {
+		if _, err := decodeLedgerCursor(bad); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("cursor %q: expected ErrInvalidArgument, got %v", bad, err)
+		}
+	}
+}
+
+func TestWalletService_ListLedger_RejectsInvalidQuery(t *testing.T) {
+	svc := NewService((*sql.DB)(nil))
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []LedgerQuery{
+		{From: now, To: now},
+		{From: now, To: now.Add(-time.Hour)},
+		{Types: []LedgerEntryType{"refund"}},
+		{Cursor: "not-a-cursor"},
+	}
+	for i, q := range cases {
+		if _, err := svc.ListLedger(ctx, "ws", "w", q); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("case %d: expected ErrInvalidArgument, got %v", i, err)
+		}
+	}
+	if _, err := svc.ListLedger(ctx, "ws", "", LedgerQuery{}); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument without wallet id, got %v", err)
+	}
+}
+
+func TestParseLedgerTypes(t *testing.T) {
+	got := ParseLedgerTypes(" debit, ,credit")
+	if len(got) != 2 || got[0] != LedgerEntryTypeDebit || got[1] != LedgerEntryTypeCredit {
+		t.Fatalf("unexpected types: %v", got)
+	}
+	if ParseLedgerTypes("") != nil {
+		t.Fatalf("expected no types for empty input")
+	}
+}
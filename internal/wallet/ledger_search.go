@@ -0,0 +1,209 @@
+package wallet
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"telecom-platform/pkg/utils"
+)
+
+// Ledger search for support investigations.
+//
+// SearchLedger looks across all of a workspace's wallets (or one of them) for
+// entries by external_ref prefix, metadata values and keys, and amount range,
+// e.g. "the 12.34 debit for call-abc... last Tuesday". Amount bounds apply to
+// the absolute amount, so a debit of -1234 matches MinAmountMinor=1234.
+//
+// Entries are returned newest first, paged on (created_at, id) since chain_seq
+// is only ordered within a wallet.
+
+// LedgerSearch filters SearchLedger. Zero fields match everything.
+type LedgerSearch struct {
+	WalletID string
+
+	// From/To bound created_at as [From, To).
+	From time.Time
+	To   time.Time
+
+	Types             []LedgerEntryType
+	ExternalRefPrefix string
+	// Metadata matches entries whose metadata contains every key/value pair.
+	Metadata MetadataFilter
+	// MetadataKeys matches entries whose metadata has every key, whatever its value.
+	MetadataKeys []string
+
+	// MinAmountMinor/MaxAmountMinor bound abs(amount_minor), inclusive; 0 is unbounded.
+	MinAmountMinor int64
+	MaxAmountMinor int64
+
+	// Cursor is NextCursor from the previous page; empty starts at the newest entry.
+	Cursor string
+	Limit  int
+}
+
+// metadataKeyPattern keeps keys safe inside a text[] literal.
+var metadataKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// searchCursor is the last entry of a page.
+type searchCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// SearchLedger returns one page of the workspace's ledger matching q.
+func (s *Service) SearchLedger(ctx context.Context, workspaceID string, q LedgerSearch) (LedgerPage, error) {
+	if workspaceID == "" {
+		return LedgerPage{}, ErrInvalidArgument
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return LedgerPage{}, ErrInvalidArgument
+	}
+	if q.MinAmountMinor < 0 || q.MaxAmountMinor < 0 || (q.MaxAmountMinor > 0 && q.MinAmountMinor > q.MaxAmountMinor) {
+		return LedgerPage{}, ErrInvalidArgument
+	}
+	for _, t := range q.Types {
+		if !knownLedgerTypes[t] {
+			return LedgerPage{}, ErrInvalidArgument
+		}
+	}
+	for _, k := range q.MetadataKeys {
+		if !metadataKeyPattern.MatchString(k) {
+			return LedgerPage{}, fmt.Errorf("%w: invalid metadata key %q", ErrInvalidArgument, k)
+		}
+	}
+	var after searchCursor
+	if q.Cursor != "" {
+		c, err := decodeSearchCursor(q.Cursor)
+		if err != nil {
+			return LedgerPage{}, err
+		}
+		after = c
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLedgerPageSize
+	}
+	if limit > MaxLedgerPageSize {
+		limit = MaxLedgerPageSize
+	}
+	containment := ""
+	if len(q.Metadata) > 0 {
+		c, err := q.Metadata.containment()
+		if err != nil {
+			return LedgerPage{}, err
+		}
+		containment = c
+	}
+
+	// Fetch one extra row to learn whether another page exists.
+	query, args := ledgerSearchSQL(workspaceID, q, containment, after, limit+1)
+	qctx, done := utils.TrackQuery(reportQuery(ctx, "SearchLedger"))
+	entries, err := searchLedger(qctx, s.db, query, args)
+	done(err)
+	if err != nil {
+		return LedgerPage{}, err
+	}
+	page := LedgerPage{Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		last := page.Entries[limit-1]
+		page.NextCursor = encodeSearchCursor(searchCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	return page, nil
+}
+
+// ParseMetadataKeys splits a comma-separated key list (e.g. "call_id,lookup_id").
+func ParseMetadataKeys(v string) []string {
+	var out []string
+	for _, p := range strings.Split(v, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// ledgerSearchSQL builds the search query. Conditions use %s for their
+// placeholder because "?" is a jsonb operator.
+func ledgerSearchSQL(workspaceID string, q LedgerSearch, containment string, after searchCursor, limit int) (string, []any) {
+	where := []string{"workspace_id = $1"}
+	args := []any{workspaceID}
+	add := func(cond string, v any) {
+		args = append(args, v)
+		where = append(where, strings.ReplaceAll(cond, "%s", "$"+strconv.Itoa(len(args))))
+	}
+	if q.WalletID != "" {
+		add("wallet_id = %s", q.WalletID)
+	}
+	if !after.CreatedAt.IsZero() {
+		args = append(args, after.CreatedAt, after.ID)
+		where = append(where, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+	if !q.From.IsZero() {
+		add("created_at >= %s", q.From)
+	}
+	if !q.To.IsZero() {
+		add("created_at < %s", q.To)
+	}
+	if len(q.Types) > 0 {
+		types := make([]string, len(q.Types))
+		for i, t := range q.Types {
+			types[i] = string(t)
+		}
+		add("type = ANY(%s::text[])", "{"+strings.Join(types, ",")+"}")
+	}
+	if q.ExternalRefPrefix != "" {
+		add(`external_ref LIKE %s ESCAPE '\'`, escapeLike(q.ExternalRefPrefix)+"%")
+	}
+	if containment != "" {
+		add("metadata @> %s::jsonb", containment)
+	}
+	if len(q.MetadataKeys) > 0 {
+		add("metadata ?& %s::text[]", "{"+strings.Join(q.MetadataKeys, ",")+"}")
+	}
+	if q.MinAmountMinor > 0 {
+		add("abs(amount_minor) >= %s", q.MinAmountMinor)
+	}
+	if q.MaxAmountMinor > 0 {
+		add("abs(amount_minor) <= %s", q.MaxAmountMinor)
+	}
+	args = append(args, limit)
+	query := `
+SELECT id, workspace_id, wallet_id, type, amount_minor, currency, external_ref, idempotency_key, metadata, created_at,
+       chain_seq, prev_hash, hash
+FROM wallet_ledger
+WHERE ` + strings.Join(where, " AND ") + `
+ORDER BY created_at DESC, id DESC
+LIMIT $` + strconv.Itoa(len(args))
+	return query, args
+}
+
+// escapeLike escapes LIKE wildcards so the prefix matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+const searchCursorPrefix = "at:"
+
+func encodeSearchCursor(c searchCursor) string {
+	raw := searchCursorPrefix + strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSearchCursor(cursor string) (searchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), searchCursorPrefix) {
+		return searchCursor{}, fmt.Errorf("%w: invalid cursor", ErrInvalidArgument)
+	}
+	nanos, id, ok := strings.Cut(strings.TrimPrefix(string(raw), searchCursorPrefix), ":")
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if !ok || err != nil || n <= 0 || id == "" {
+		return searchCursor{}, fmt.Errorf("%w: invalid cursor", ErrInvalidArgument)
+	}
+	return searchCursor{CreatedAt: time.Unix(0, n).UTC(), ID: id}, nil
+}
@@ -0,0 +1,82 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLedgerSearchSQL(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	after := searchCursor{CreatedAt: from.Add(time.Hour), ID: "le-9"}
+	q := LedgerSearch{
+		WalletID:          "w1",
+		From:              from,
+		Types:             []LedgerEntryType{LedgerEntryTypeDebit},
+		ExternalRefPrefix: "call_50%",
+		MetadataKeys:      []string{"call_id", "lookup_id"},
+		MinAmountMinor:    100,
+		MaxAmountMinor:    2000,
+	}
+	query, args := ledgerSearchSQL("ws", q, `{"campaign_id":"c1"}`, after, 51)
+
+	for _, want := range []string{
+		"workspace_id = $1 AND wallet_id = $2 AND (created_at, id) < ($3, $4) AND created_at >= $5",
+		"type = ANY($6::text[])",
+		`external_ref LIKE $7 ESCAPE '\'`,
+		"metadata @> $8::jsonb",
+		"metadata ?& $9::text[]",
+		"abs(amount_minor) >= $10 AND abs(amount_minor) <= $11",
+		"ORDER BY created_at DESC, id DESC\nLIMIT $12",
+	} {
+		if !strings.Contains(query, want) {
+			t.Fatalf("expected %q in\n%s", want, query)
+		}
+	}
+	if len(args) != 12 || args[6] != `call\_50\%%` || args[8] != "{call_id,lookup_id}" || args[11] != 51 {
+		t.Fatalf("unexpected args %#v", args)
+	}
+
+	query, args = ledgerSearchSQL("ws", LedgerSearch{}, "", searchCursor{}, 50)
+	if !strings.Contains(query, "WHERE workspace_id = $1\nORDER BY") || len(args) != 2 {
+		t.Fatalf("unexpected unfiltered query %s %#v", query, args)
+	}
+}
+
+func TestSearchCursorRoundTrip(t *testing.T) {
+	c := searchCursor{CreatedAt: time.Date(2024, 3, 5, 12, 0, 0, 123456000, time.UTC), ID: "5f0c:1"}
+	got, err := decodeSearchCursor(encodeSearchCursor(c))
+	if err != nil || !got.CreatedAt.Equal(c.CreatedAt) || got.ID != c.ID {
+		t.Fatalf("round trip: got %+v %v", got, err)
+	}
+	for _, bad := range []string{"!!", encodeLedgerCursor(7), "YXQ6MTIz"} {
+		if _, err := decodeSearchCursor(bad); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("%q: expected ErrInvalidArgument, got %v", bad, err)
+		}
+	}
+}
+
+func TestSearchLedger_RejectsInvalidArgs(t *testing.T) {
+	svc := NewService((*sql.DB)(nil))
+	ctx := context.Background()
+	day := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	for name, q := range map[string]LedgerSearch{
+		"reversed range":   {From: day, To: day},
+		"negative amount":  {MinAmountMinor: -1},
+		"min above max":    {MinAmountMinor: 500, MaxAmountMinor: 100},
+		"unknown type":     {Types: []LedgerEntryType{"refund"}},
+		"unsafe key":       {MetadataKeys: []string{"a,b"}},
+		"malformed cursor": {Cursor: "nope"},
+	} {
+		if _, err := svc.SearchLedger(ctx, "ws", q); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("%s: expected ErrInvalidArgument, got %v", name, err)
+		}
+	}
+	if _, err := svc.SearchLedger(ctx, "", LedgerSearch{}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without workspace, got %v", err)
+	}
+}
@@ -0,0 +1,334 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Ledger metadata is stored as JSONB. Callers still pass it as a string, but it
+// must be a JSON object that fits the per-type schema below so support and
+// reconciliation tooling can rely on well-known keys instead of free text.
+
+// MaxMetadataBytes bounds the compacted metadata document per entry.
+const MaxMetadataBytes = 4096
+
+// reservedMetadataPrefix marks keys written by the platform itself
+// (e.g. FX conversion details); tenant-supplied metadata may not use it.
+const reservedMetadataPrefix = "_"
+
+// reservedMetadataKeys duplicate ledger columns; allowing them in metadata
+// would let a caller shadow the authoritative value in exports.
+var reservedMetadataKeys = map[string]bool{
+	"id":              true,
+	"workspace_id":    true,
+	"wallet_id":       true,
+	"type":            true,
+	"amount_minor":    true,
+	"currency":        true,
+	"idempotency_key": true,
+	"chain_seq":       true,
+	"prev_hash":       true,
+	"hash":            true,
+}
+
+var ErrInvalidMetadata = errors.New("invalid metadata")
+
+// MetadataValueType is the JSON type a schema field must have.
+type MetadataValueType string
+
+const (
+	MetadataString  MetadataValueType = "string"
+	MetadataNumber  MetadataValueType = "number"
+	MetadataBoolean MetadataValueType = "boolean"
+	MetadataObject  MetadataValueType = "object"
+	MetadataArray   MetadataValueType = "array"
+)
+
+// MetadataSchema describes the top-level keys of a metadata object.
+// Unknown keys are accepted unless Strict is set, so tenants can attach their own context.
+type MetadataSchema struct {
+	Fields   map[string]MetadataValueType
+	Required []string
+	Strict   bool
+}
+
+// metadataSchemas is keyed by ledger entry type. Keep field names stable;
+// dashboards and exports key off them.
+var metadataSchemas = map[LedgerEntryType]MetadataSchema{
+	LedgerEntryTypeCredit: {
+		Fields: map[string]MetadataValueType{
+			"source":      MetadataString, // e.g. "stripe", "bank_transfer"
+			"payment_ref": MetadataString,
+			"invoice_id":  MetadataString,
+			"note":        MetadataString,
+		},
+	},
+	LedgerEntryTypeDebit: {
+		Fields: map[string]MetadataValueType{
+			"call_id":          MetadataString,
+			"campaign_id":      MetadataString,
+			"destination":      MetadataString,
+			"billable_seconds": MetadataNumber,
+			"rate_minor":       MetadataNumber,
+			"lookup_id":        MetadataString,
+			"note":             MetadataString,
+		},
+	},
+	LedgerEntryTypeHold: {
+		Fields: map[string]MetadataValueType{
+			"call_id":           MetadataString,
+			"estimated_seconds": MetadataNumber,
+		},
+		Required: []string{"call_id"},
+	},
+	LedgerEntryTypeRelease: {
+		Fields: map[string]MetadataValueType{
+			"call_id":        MetadataString,
+			"hold_ledger_id": MetadataString,
+		},
+		Required: []string{"call_id"},
+	},
+	// The reversed entry's id is recorded by the platform under "_reversal_of".
+	LedgerEntryTypeReversal: {
+		Fields: map[string]MetadataValueType{
+			"refund_ref": MetadataString, // e.g. payment provider refund id
+			"note":       MetadataString,
+		},
+	},
+}
+
+// NormalizeMetadata validates raw against the schema for entryType and returns
+// its compacted form. Empty input stays empty. Errors wrap ErrInvalidMetadata.
+//
+// Normalization must happen before the entry is hashed (see chain.go) so the
+// bytes kept in metadata_text are exactly what the chain covers.
+func NormalizeMetadata(entryType LedgerEntryType, raw string) (string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(raw)); err != nil {
+		return "", fmt.Errorf("%w: not valid json", ErrInvalidMetadata)
+	}
+	if buf.Len() > MaxMetadataBytes {
+		return "", fmt.Errorf("%w: exceeds %d bytes", ErrInvalidMetadata, MaxMetadataBytes)
+	}
+
+	var doc map[string]any
+	dec := json.NewDecoder(bytes.NewReader(buf.Bytes()))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil || doc == nil {
+		return "", fmt.Errorf("%w: must be a json object", ErrInvalidMetadata)
+	}
+
+	keys := make([]string, 0, len(doc))
+	for k := range doc {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if k == "" || strings.HasPrefix(k, reservedMetadataPrefix) || reservedMetadataKeys[k] {
+			return "", fmt.Errorf("%w: key %q is reserved", ErrInvalidMetadata, k)
+		}
+	}
+
+	schema := metadataSchemas[entryType]
+	for _, k := range schema.Required {
+		if _, ok := doc[k]; !ok {
+			return "", fmt.Errorf("%w: %q is required for %s entries", ErrInvalidMetadata, k, entryType)
+		}
+	}
+	for _, k := range keys {
+		want, known := schema.Fields[k]
+		if !known {
+			if schema.Strict {
+				return "", fmt.Errorf("%w: unknown key %q", ErrInvalidMetadata, k)
+			}
+			continue
+		}
+		if got := metadataTypeOf(doc[k]); got != want {
+			return "", fmt.Errorf("%w: %q must be a %s", ErrInvalidMetadata, k, want)
+		}
+	}
+	return buf.String(), nil
+}
+
+// withReservedMetadata sets the platform-reserved key "_"+key on already
+// normalized metadata. It runs after NormalizeMetadata because callers may not
+// write reserved keys.
+func withReservedMetadata(metadata, key string, value any) (string, error) {
+	doc := map[string]any{}
+	if metadata != "" {
+		dec := json.NewDecoder(strings.NewReader(metadata))
+		dec.UseNumber()
+		if err := dec.Decode(&doc); err != nil {
+			return "", fmt.Errorf("%w: must be a json object", ErrInvalidMetadata)
+		}
+	}
+	doc[reservedMetadataPrefix+key] = value
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidMetadata, err)
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, b); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidMetadata, err)
+	}
+	if buf.Len() > MaxMetadataBytes {
+		return "", fmt.Errorf("%w: exceeds %d bytes", ErrInvalidMetadata, MaxMetadataBytes)
+	}
+	return buf.String(), nil
+}
+
+func metadataTypeOf(v any) MetadataValueType {
+	switch v.(type) {
+	case string:
+		return MetadataString
+	case json.Number:
+		return MetadataNumber
+	case bool:
+		return MetadataBoolean
+	case map[string]any:
+		return MetadataObject
+	case []any:
+		return MetadataArray
+	default:
+		return ""
+	}
+}
+
+// MetadataFilter selects ledger entries whose metadata contains every key/value
+// pair (JSONB containment, metadata @> filter). Values are compared as JSON, so
+// {"billable_seconds": 60} does not match "60".
+type MetadataFilter map[string]any
+
+// metadataQueryPrefix namespaces metadata filters in list query strings:
+// ?metadata.call_id=abc&metadata.billable_seconds=60
+const metadataQueryPrefix = "metadata."
+
+// ParseMetadataFilter extracts metadata.<key>=<value> pairs from q.
+// Values that parse as JSON scalars (numbers, true/false) are matched as such;
+// everything else is matched as a string. A key may appear only once.
+func ParseMetadataFilter(q url.Values) (MetadataFilter, error) {
+	var f MetadataFilter
+	for name, vals := range q {
+		if !strings.HasPrefix(name, metadataQueryPrefix) {
+			continue
+		}
+		key := strings.TrimPrefix(name, metadataQueryPrefix)
+		if key == "" {
+			return nil, fmt.Errorf("%w: empty filter key", ErrInvalidMetadata)
+		}
+		if len(vals) != 1 {
+			return nil, fmt.Errorf("%w: filter %q given more than once", ErrInvalidMetadata, key)
+		}
+		if f == nil {
+			f = MetadataFilter{}
+		}
+		f[key] = parseFilterScalar(vals[0])
+	}
+	return f, nil
+}
+
+func parseFilterScalar(s string) any {
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return json.Number(s)
+	}
+	return s
+}
+
+// containment renders f as the JSONB document for a "metadata @> $n::jsonb" predicate.
+func (f MetadataFilter) containment() (string, error) {
+	b, err := json.Marshal(map[string]any(f))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidMetadata, err)
+	}
+	return string(b), nil
+}
+
+// Matches reports whether the metadata document contains every pair in f.
+// It mirrors the Postgres predicate for callers that filter in memory.
+func (f MetadataFilter) Matches(metadata string) bool {
+	if len(f) == 0 {
+		return true
+	}
+	if metadata == "" {
+		return false
+	}
+	var doc map[string]any
+	dec := json.NewDecoder(strings.NewReader(metadata))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return false
+	}
+	want, err := f.containment()
+	if err != nil {
+		return false
+	}
+	var wantDoc map[string]any
+	dec = json.NewDecoder(strings.NewReader(want))
+	dec.UseNumber()
+	if err := dec.Decode(&wantDoc); err != nil {
+		return false
+	}
+	return jsonContains(doc, wantDoc)
+}
+
+// jsonContains follows JSONB @> semantics: objects match on a subset of keys,
+// arrays when every wanted element is contained in some element, scalars by equality.
+func jsonContains(have, want any) bool {
+	switch w := want.(type) {
+	case map[string]any:
+		h, ok := have.(map[string]any)
+		if !ok {
+			return false
+		}
+		for k, wv := range w {
+			hv, ok := h[k]
+			if !ok || !jsonContains(hv, wv) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		h, ok := have.([]any)
+		if !ok {
+			return false
+		}
+		for _, wv := range w {
+			found := false
+			for _, hv := range h {
+				if jsonContains(hv, wv) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		h, ok := have.(json.Number)
+		if !ok {
+			return false
+		}
+		hf, err1 := h.Float64()
+		wf, err2 := w.Float64()
+		return err1 == nil && err2 == nil && hf == wf
+	default:
+		return have == want
+	}
+}
@@ -0,0 +1,110 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeMetadata(t *testing.T) {
+	out, err := NormalizeMetadata(LedgerEntryTypeDebit, "{ \"call_id\": \"c1\",\n \"billable_seconds\": 60 }")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != `{"call_id":"c1","billable_seconds":60}` {
+		t.Fatalf("not compacted: %s", out)
+	}
+	if out, err := NormalizeMetadata(LedgerEntryTypeCredit, "  "); err != nil || out != "" {
+		t.Fatalf("empty metadata should pass through, got %q %v", out, err)
+	}
+	if _, err := NormalizeMetadata(LedgerEntryTypeCredit, `{"crm_id":"x"}`); err != nil {
+		t.Fatalf("unknown keys are allowed: %v", err)
+	}
+
+	bad := []struct {
+		name string
+		typ  LedgerEntryType
+		raw  string
+	}{
+		{"not json", LedgerEntryTypeCredit, "free text"},
+		{"not object", LedgerEntryTypeCredit, `["a"]`},
+		{"null", LedgerEntryTypeCredit, `null`},
+		{"reserved prefix", LedgerEntryTypeCredit, `{"_fx_rate":1.1}`},
+		{"reserved column", LedgerEntryTypeDebit, `{"amount_minor":5}`},
+		{"wrong type", LedgerEntryTypeDebit, `{"billable_seconds":"60"}`},
+		{"missing required", LedgerEntryTypeHold, `{"estimated_seconds":30}`},
+		{"too large", LedgerEntryTypeCredit, `{"note":"` + strings.Repeat("x", MaxMetadataBytes) + `"}`},
+	}
+	for _, tc := range bad {
+		if _, err := NormalizeMetadata(tc.typ, tc.raw); !errors.Is(err, ErrInvalidMetadata) {
+			t.Fatalf("%s: expected ErrInvalidMetadata, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestWalletService_RejectsInvalidMetadata(t *testing.T) {
+	svc := NewService((*sql.DB)(nil))
+
+	_, _, err := svc.Debit(context.Background(), "ws", "w", DebitRequest{AmountMinor: 100, Currency: "USD", IdempotencyKey: "k", Metadata: `{"hash":"x"}`})
+	if !errors.Is(err, ErrInvalidMetadata) {
+		t.Fatalf("expected ErrInvalidMetadata, got %v", err)
+	}
+	_, _, _, err = svc.AdminManualCredit(context.Background(), "ws", "w", "u", "owner", AdminCreditRequest{AmountMinor: 100, Currency: "USD", Reason: "r", IdempotencyKey: "k", Metadata: "oops"})
+	if !errors.Is(err, ErrInvalidMetadata) {
+		t.Fatalf("expected ErrInvalidMetadata, got %v", err)
+	}
+}
+
+func TestParseMetadataFilter(t *testing.T) {
+	q, _ := url.ParseQuery("metadata.call_id=c1&metadata.billable_seconds=60&metadata.test=true&limit=10")
+	f, err := ParseMetadataFilter(q)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(f) != 3 {
+		t.Fatalf("expected 3 filters, got %v", f)
+	}
+	got, err := f.containment()
+	if err != nil {
+		t.Fatalf("containment: %v", err)
+	}
+	if got != `{"billable_seconds":60,"call_id":"c1","test":true}` {
+		t.Fatalf("unexpected containment doc: %s", got)
+	}
+
+	q, _ = url.ParseQuery("metadata.call_id=a&metadata.call_id=b")
+	if _, err := ParseMetadataFilter(q); !errors.Is(err, ErrInvalidMetadata) {
+		t.Fatalf("expected ErrInvalidMetadata for repeated key, got %v", err)
+	}
+	if f, err := ParseMetadataFilter(url.Values{"limit": {"5"}}); err != nil || f != nil {
+		t.Fatalf("expected no filter, got %v %v", f, err)
+	}
+}
+
+func TestMetadataFilter_Matches(t *testing.T) {
+	doc := `{"call_id":"c1","billable_seconds":60,"tags":["a","b"],"ctx":{"lead":"l1","src":"ppc"}}`
+	cases := []struct {
+		f    MetadataFilter
+		want bool
+	}{
+		{MetadataFilter{"call_id": "c1"}, true},
+		{MetadataFilter{"call_id": "c2"}, false},
+		{MetadataFilter{"billable_seconds": 60}, true},
+		{MetadataFilter{"billable_seconds": "60"}, false},
+		{MetadataFilter{"tags": []any{"b"}}, true},
+		{MetadataFilter{"ctx": map[string]any{"lead": "l1"}}, true},
+		{MetadataFilter{"missing": "x"}, false},
+		{nil, true},
+	}
+	for i, tc := range cases {
+		if got := tc.f.Matches(doc); got != tc.want {
+			t.Fatalf("case %d: Matches(%v) = %v, want %v", i, tc.f, got, tc.want)
+		}
+	}
+	if (MetadataFilter{"call_id": "c1"}).Matches("") {
+		t.Fatalf("empty metadata must not match a non-empty filter")
+	}
+}
@@ -0,0 +1,314 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Money operation metrics.
+//
+// MoneyMetrics records each money operation (Credit, Debit, Reserve, Capture,
+// Release, Reverse, AdminManualCredit) by outcome, with latency and wallet
+// lock-wait histograms, and tracks an SLO with burn rates over SLOBurnWindows.
+// WritePrometheus renders them in the Prometheus text format, so billing health
+// can be scraped and alerted on separately from HTTP metrics.
+//
+// Outcomes:
+// - ok: posted; replayed: an idempotency hit, nothing posted;
+// - insufficient_funds and rejected (other business rules and bad input): the
+//   caller's problem, not counted against the SLO;
+// - error: anything else (database errors, timeouts).
+//
+// An operation is bad for the SLO when its outcome is error or it took longer
+// than the SLO's LatencyTarget. A burn rate of 1 spends the error budget exactly
+// over the SLO period; alert on a short and a long window together (e.g. 5m and
+// 1h above 14).
+
+type Outcome string
+
+const (
+	OutcomeOK                Outcome = "ok"
+	OutcomeReplayed          Outcome = "replayed"
+	OutcomeInsufficientFunds Outcome = "insufficient_funds"
+	OutcomeRejected          Outcome = "rejected"
+	OutcomeError             Outcome = "error"
+)
+
+var allOutcomes = []Outcome{OutcomeOK, OutcomeReplayed, OutcomeInsufficientFunds, OutcomeRejected, OutcomeError}
+
+// MoneySLO is the objective tracked by MoneyMetrics: Objective of operations
+// complete without error within LatencyTarget.
+type MoneySLO struct {
+	Objective     float64
+	LatencyTarget time.Duration
+}
+
+var DefaultMoneySLO = MoneySLO{Objective: 0.999, LatencyTarget: 500 * time.Millisecond}
+
+// SLOBurnWindows are the windows burn rates are reported over.
+var SLOBurnWindows = []time.Duration{5 * time.Minute, 30 * time.Minute, time.Hour, 6 * time.Hour}
+
+// sloSlots keeps one slot per minute, enough for the longest burn window.
+const sloSlots = 360
+
+// durationBuckets are histogram upper bounds in seconds.
+var durationBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+type histogram struct {
+	counts []uint64 // per bucket, the last one is +Inf
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(d time.Duration) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(durationBuckets)+1)
+	}
+	s := d.Seconds()
+	h.counts[sort.SearchFloat64s(durationBuckets, s)]++
+	h.sum += s
+	h.count++
+}
+
+type opMetrics struct {
+	outcomes map[Outcome]uint64
+	duration histogram
+	lockWait histogram
+}
+
+type sloSlot struct {
+	minute     int64
+	total, bad uint64
+}
+
+// MoneyMetrics is safe for concurrent use. A nil *MoneyMetrics records nothing.
+type MoneyMetrics struct {
+	SLO MoneySLO
+
+	clock func() time.Time
+
+	mu    sync.Mutex
+	ops   map[string]*opMetrics
+	slots [sloSlots]sloSlot
+}
+
+func NewMoneyMetrics() *MoneyMetrics {
+	return &MoneyMetrics{SLO: DefaultMoneySLO, clock: time.Now, ops: map[string]*opMetrics{}}
+}
+
+// Observe records one finished operation; lockWait is 0 when the wallet was not
+// locked.
+func (m *MoneyMetrics) Observe(op string, outcome Outcome, took, lockWait time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o := m.ops[op]
+	if o == nil {
+		o = &opMetrics{outcomes: map[Outcome]uint64{}}
+		m.ops[op] = o
+	}
+	o.outcomes[outcome]++
+	o.duration.observe(took)
+	if lockWait > 0 {
+		o.lockWait.observe(lockWait)
+	}
+
+	minute := m.clock().Unix() / 60
+	slot := &m.slots[minute%sloSlots]
+	if slot.minute != minute {
+		*slot = sloSlot{minute: minute}
+	}
+	slot.total++
+	if outcome == OutcomeError || took > m.SLO.LatencyTarget {
+		slot.bad++
+	}
+}
+
+// BurnRate is how fast the error budget was spent over the last window (0
+// without traffic).
+func (m *MoneyMetrics) BurnRate(window time.Duration) float64 {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.burnRate(window)
+}
+
+func (m *MoneyMetrics) burnRate(window time.Duration) float64 {
+	budget := 1 - m.SLO.Objective
+	minutes := int64(window / time.Minute)
+	if minutes > sloSlots {
+		minutes = sloSlots
+	}
+	if budget <= 0 || minutes <= 0 {
+		return 0
+	}
+	now := m.clock().Unix() / 60
+	var total, bad uint64
+	for _, s := range m.slots {
+		if s.minute > now-minutes && s.minute <= now {
+			total += s.total
+			bad += s.bad
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(bad) / float64(total) / budget
+}
+
+// WritePrometheus writes every metric in the Prometheus text exposition format.
+func (m *MoneyMetrics) WritePrometheus(w io.Writer) error {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.ops))
+	for name := range m.ops {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("# HELP wallet_money_operations_total Money operations by outcome (replayed is an idempotency hit).\n")
+	b.WriteString("# TYPE wallet_money_operations_total counter\n")
+	for _, name := range names {
+		for _, outcome := range allOutcomes {
+			fmt.Fprintf(&b, "wallet_money_operations_total{op=%q,outcome=%q} %d\n", name, outcome, m.ops[name].outcomes[outcome])
+		}
+	}
+	m.writeHistogram(&b, "wallet_money_operation_duration_seconds", "Money operation latency, retries included.", names,
+		func(o *opMetrics) histogram { return o.duration })
+	m.writeHistogram(&b, "wallet_money_lock_wait_seconds", "Time spent acquiring the wallet row lock.", names,
+		func(o *opMetrics) histogram { return o.lockWait })
+
+	b.WriteString("# HELP wallet_money_slo_objective Fraction of money operations that must succeed within the latency target.\n")
+	b.WriteString("# TYPE wallet_money_slo_objective gauge\n")
+	fmt.Fprintf(&b, "wallet_money_slo_objective %s\n", formatFloat(m.SLO.Objective))
+	b.WriteString("# HELP wallet_money_slo_latency_target_seconds Latency above which a money operation counts against the SLO.\n")
+	b.WriteString("# TYPE wallet_money_slo_latency_target_seconds gauge\n")
+	fmt.Fprintf(&b, "wallet_money_slo_latency_target_seconds %s\n", formatFloat(m.SLO.LatencyTarget.Seconds()))
+	b.WriteString("# HELP wallet_money_slo_burn_rate Error budget burn rate over the window.\n")
+	b.WriteString("# TYPE wallet_money_slo_burn_rate gauge\n")
+	for _, window := range SLOBurnWindows {
+		fmt.Fprintf(&b, "wallet_money_slo_burn_rate{window=%q} %s\n", windowLabel(window), formatFloat(m.burnRate(window)))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (m *MoneyMetrics) writeHistogram(b *strings.Builder, metric, help string, names []string, pick func(*opMetrics) histogram) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s histogram\n", metric, help, metric)
+	for _, name := range names {
+		h := pick(m.ops[name])
+		var cum uint64
+		for i, le := range durationBuckets {
+			if h.counts != nil {
+				cum += h.counts[i]
+			}
+			fmt.Fprintf(b, "%s_bucket{op=%q,le=%q} %d\n", metric, name, formatFloat(le), cum)
+		}
+		fmt.Fprintf(b, "%s_bucket{op=%q,le=\"+Inf\"} %d\n", metric, name, h.count)
+		fmt.Fprintf(b, "%s_sum{op=%q} %s\n", metric, name, formatFloat(h.sum))
+		fmt.Fprintf(b, "%s_count{op=%q} %d\n", metric, name, h.count)
+	}
+}
+
+func formatFloat(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) }
+
+func windowLabel(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("%dh", d/time.Hour)
+	}
+	return fmt.Sprintf("%dm", d/time.Minute)
+}
+
+// rejections are errors caused by the request or the wallet's state.
+var rejections = []error{
+	ErrInvalidArgument,
+	ErrInvalidMetadata,
+	ErrNotFound,
+	ErrWalletNotActive,
+	ErrWalletClosed,
+	ErrSpendCapExceeded,
+	ErrIdempotencyConflict,
+	ErrHoldNotFound,
+	ErrHoldNotOpen,
+	ErrAlreadyReversed,
+}
+
+func classifyOutcome(err error, replayed bool) Outcome {
+	switch {
+	case err == nil && replayed:
+		return OutcomeReplayed
+	case err == nil:
+		return OutcomeOK
+	case errors.Is(err, ErrInsufficientFunds):
+		return OutcomeInsufficientFunds
+	}
+	for _, r := range rejections {
+		if errors.Is(err, r) {
+			return OutcomeRejected
+		}
+	}
+	return OutcomeError
+}
+
+// moneyOp times one operation; lockWallet and replayIdempotent report into it
+// through the context.
+type moneyOp struct {
+	m        *MoneyMetrics
+	name     string
+	start    time.Time
+	lockWait time.Duration
+	replayed bool
+}
+
+type moneyOpKey struct{}
+
+// track starts timing op; call end with the operation's error.
+func (m *MoneyMetrics) track(ctx context.Context, op string) (context.Context, *moneyOp) {
+	if m == nil {
+		return ctx, nil
+	}
+	o := &moneyOp{m: m, name: op, start: time.Now()}
+	return context.WithValue(ctx, moneyOpKey{}, o), o
+}
+
+func moneyOpFrom(ctx context.Context) *moneyOp {
+	o, _ := ctx.Value(moneyOpKey{}).(*moneyOp)
+	return o
+}
+
+func (o *moneyOp) end(err error) {
+	if o == nil {
+		return
+	}
+	o.m.Observe(o.name, classifyOutcome(err, o.replayed), time.Since(o.start), o.lockWait)
+}
+
+func observeLockWait(ctx context.Context, start time.Time) {
+	if o := moneyOpFrom(ctx); o != nil {
+		o.lockWait += time.Since(start)
+	}
+}
+
+func markReplayed(ctx context.Context) {
+	if o := moneyOpFrom(ctx); o != nil {
+		o.replayed = true
+	}
+}
@@ -0,0 +1,114 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClassifyOutcome(t *testing.T) {
+	cases := []struct {
+		err      error
+		replayed bool
+		want     Outcome
+	}{
+		{nil, false, OutcomeOK},
+		{nil, true, OutcomeReplayed},
+		{ErrInsufficientFunds, false, OutcomeInsufficientFunds},
+		{ErrCreditLimitExceeded, false, OutcomeInsufficientFunds},
+		{ErrWalletFrozen, false, OutcomeRejected},
+		{ErrReversalExceedsEntry, false, OutcomeRejected},
+		{ErrIdempotencyConflict, false, OutcomeRejected},
+		{errors.New("canceling statement due to statement timeout"), false, OutcomeError},
+		{ErrUnbalancedEntry, false, OutcomeError},
+	}
+	for _, tc := range cases {
+		if got := classifyOutcome(tc.err, tc.replayed); got != tc.want {
+			t.Fatalf("%v: got %s, want %s", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestMoneyMetrics_BurnRate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := NewMoneyMetrics()
+	m.clock = func() time.Time { return now }
+
+	if got := m.BurnRate(time.Hour); got != 0 {
+		t.Fatalf("expected no burn without traffic, got %v", got)
+	}
+
+	// 50 minutes ago: 1000 good operations.
+	now = now.Add(-50 * time.Minute)
+	for i := 0; i < 1000; i++ {
+		m.Observe("Debit", OutcomeOK, time.Millisecond, 0)
+	}
+	// Now: 1 error, 1 too slow, and business rejections that do not count.
+	now = now.Add(50 * time.Minute)
+	m.Observe("Debit", OutcomeError, time.Millisecond, 0)
+	m.Observe("Credit", OutcomeOK, time.Second, 0)
+	m.Observe("Debit", OutcomeInsufficientFunds, time.Millisecond, 0)
+	m.Observe("Debit", OutcomeReplayed, time.Millisecond, 0)
+
+	// 5m: 2 bad of 4 = 50% with a 0.1% budget.
+	if got := m.BurnRate(5 * time.Minute); math.Abs(got-500) > 1e-6 {
+		t.Fatalf("unexpected 5m burn rate %v", got)
+	}
+	// 1h: 2 bad of 1004.
+	if got, want := m.BurnRate(time.Hour), 2.0/1004/0.001; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("unexpected 1h burn rate %v, want %v", got, want)
+	}
+
+	// Slots older than the window are ignored, even when their ring slot is reused.
+	now = now.Add(sloSlots * time.Minute)
+	if got := m.BurnRate(6 * time.Hour); got != 0 {
+		t.Fatalf("expected old traffic to age out, got %v", got)
+	}
+}
+
+func TestMoneyMetrics_TrackAndExport(t *testing.T) {
+	m := NewMoneyMetrics()
+
+	ctx, op := m.track(context.Background(), "Debit")
+	observeLockWait(ctx, time.Now().Add(-20*time.Millisecond))
+	markReplayed(ctx)
+	op.end(nil)
+
+	_, op = m.track(context.Background(), "Debit")
+	op.end(ErrInsufficientFunds)
+
+	var buf strings.Builder
+	if err := m.WritePrometheus(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`wallet_money_operations_total{op="Debit",outcome="replayed"} 1`,
+		`wallet_money_operations_total{op="Debit",outcome="insufficient_funds"} 1`,
+		`wallet_money_operations_total{op="Debit",outcome="ok"} 0`,
+		`wallet_money_operation_duration_seconds_count{op="Debit"} 2`,
+		`wallet_money_lock_wait_seconds_bucket{op="Debit",le="0.01"} 0`,
+		`wallet_money_lock_wait_seconds_bucket{op="Debit",le="0.025"} 1`,
+		`wallet_money_lock_wait_seconds_bucket{op="Debit",le="+Inf"} 1`,
+		`wallet_money_slo_objective 0.999`,
+		`wallet_money_slo_burn_rate{window="5m"} 0`,
+		`wallet_money_slo_burn_rate{window="6h"} 0`,
+		"# TYPE wallet_money_operation_duration_seconds histogram",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %s in\n%s", want, out)
+		}
+	}
+
+	// A service without metrics records nothing.
+	var none *MoneyMetrics
+	ctx, op = none.track(context.Background(), "Debit")
+	observeLockWait(ctx, time.Now())
+	op.end(nil)
+	if err := none.WritePrometheus(&buf); err != nil || none.BurnRate(time.Hour) != 0 {
+		t.Fatalf("expected nil metrics to be a no-op")
+	}
+}
@@ -81,7 +81,7 @@ func RequireSufficientBalance(svc BalanceService) gin.HandlerFunc {
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "currency mismatch"})
 			return
 		}
-		if bal.BalanceMinor < estMinor {
+		if bal.Spendable() < estMinor {
 			// 402 Payment Required is semantically appropriate.
 			c.AbortWithStatusJSON(http.StatusPaymentRequired, gin.H{"error": "insufficient balance"})
 			return
@@ -12,6 +12,8 @@ type Wallet struct {
 
 	// Optional operational flags (do not encode money state here).
 	Status WalletStatus `json:"status" db:"status"`
+	// CreditLimitMinor lets the balance go negative down to -CreditLimitMinor (see credit_limit.go).
+	CreditLimitMinor int64 `json:"credit_limit_minor" db:"credit_limit_minor"`
 
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
@@ -19,9 +21,12 @@ type Wallet struct {
 
 type WalletStatus string
 
+// Wallet statuses (see wallets.go for what each allows).
 const (
 	WalletStatusActive   WalletStatus = "active"
 	WalletStatusDisabled WalletStatus = "disabled"
+	WalletStatusFrozen   WalletStatus = "frozen"
+	WalletStatusClosed   WalletStatus = "closed"
 )
 
 // WalletLedger is an immutable append-only entry.
@@ -52,6 +57,16 @@ type WalletLedger struct {
 	Metadata string `json:"metadata,omitempty" db:"metadata"`
 
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// Tamper evidence (see chain.go). ChainSeq is per wallet, starting at 1;
+	// Hash covers PrevHash + row contents.
+	ChainSeq int64  `json:"chain_seq" db:"chain_seq"`
+	PrevHash string `json:"prev_hash,omitempty" db:"prev_hash"`
+	Hash     string `json:"hash" db:"hash"`
+
+	// ContraAccount picks the system account balancing this entry on insert
+	// (see postings.go); empty uses the type's default. Not read back.
+	ContraAccount SystemAccount `json:"-" db:"-"`
 }
 
 type LedgerEntryType string
@@ -59,8 +74,9 @@ type LedgerEntryType string
 const (
 	LedgerEntryTypeCredit LedgerEntryType = "credit" // top-up, adjustment, etc.
 	LedgerEntryTypeDebit  LedgerEntryType = "debit"  // usage charge, fee, etc.
-	LedgerEntryTypeHold   LedgerEntryType = "hold"   // reservation (optional future)
-	LedgerEntryTypeRelease LedgerEntryType = "release" // release reservation (optional future)
+	LedgerEntryTypeHold   LedgerEntryType = "hold"   // reservation (see holds.go)
+	LedgerEntryTypeRelease LedgerEntryType = "release" // release of a reservation
+	LedgerEntryTypeReversal LedgerEntryType = "reversal" // refund/reversal of a credit or debit (see reversals.go)
 )
 
 // AdminWalletAction tracks privileged/manual actions performed by admins.
@@ -99,4 +115,6 @@ const (
 	AdminWalletActionTypeAdjustBalance AdminWalletActionType = "adjust_balance"
 	AdminWalletActionTypeFreeze        AdminWalletActionType = "freeze"
 	AdminWalletActionTypeUnfreeze      AdminWalletActionType = "unfreeze"
+	AdminWalletActionTypeCreditLimit   AdminWalletActionType = "set_credit_limit"
+	AdminWalletActionTypeReverse       AdminWalletActionType = "reverse_entry"
 )
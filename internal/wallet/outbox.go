@@ -0,0 +1,168 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"telecom-platform/internal/events"
+	"telecom-platform/pkg/logger"
+	"telecom-platform/pkg/utils"
+)
+
+// Wallet domain events via a transactional outbox.
+//
+// Credit (and AdminManualCredit), Debit, Capture and Reserve write a
+// wallet.credited, wallet.debited or wallet.hold_placed event to wallet_outbox in
+// the same transaction as the ledger entry, so an event exists exactly when the
+// entry does. Idempotent replays write nothing. OutboxRelay publishes pending
+// events in outbox order and marks them published.
+//
+// Delivery is at-least-once: a crash between publishing and marking republishes
+// the event. The event ID is the ledger entry ID, so consumers dedupe on it.
+//
+// An event that fails to publish MaxAttempts times is parked (parked_at set) so
+// it stops blocking the events behind it. Parked events are kept, not pruned;
+// clearing parked_at and attempts replays one.
+
+// DefaultOutboxRetention is how long published events are kept.
+const DefaultOutboxRetention = 7 * 24 * time.Hour
+
+// DefaultOutboxMaxAttempts is how many failed publishes park an event.
+const DefaultOutboxMaxAttempts = 20
+
+// OutboxEvent is a pending row of wallet_outbox.
+type OutboxEvent struct {
+	Envelope  events.Envelope
+	CreatedAt time.Time
+	// Attempts counts failed publishes.
+	Attempts int
+}
+
+// walletEvent builds the event for a new ledger entry; holdID is set for holds.
+func walletEvent(t events.Type, entry WalletLedger, bal Balance, holdID string) (events.Envelope, error) {
+	amount := entry.AmountMinor
+	if amount < 0 {
+		amount = -amount
+	}
+	return events.NewWalletEvent(entry.ID, t, entry.WorkspaceID, entry.CreatedAt, events.WalletPayload{
+		WalletID:     entry.WalletID,
+		LedgerID:     entry.ID,
+		HoldID:       holdID,
+		AmountMinor:  amount,
+		Currency:     entry.Currency,
+		ExternalRef:  entry.ExternalRef,
+		BalanceMinor: bal.BalanceMinor,
+		HeldMinor:    bal.HeldMinor,
+	})
+}
+
+// emitEvent adds the event for entry to the outbox inside the money operation's tx.
+func emitEvent(ctx context.Context, tx *sql.Tx, t events.Type, entry WalletLedger, bal Balance, holdID string) error {
+	e, err := walletEvent(t, entry, bal, holdID)
+	if err != nil {
+		return err
+	}
+	return insertOutboxEvent(ctx, tx, e)
+}
+
+// OutboxRelay publishes the outbox to the event bus. Run a single instance;
+// extra instances skip locked rows, so they are safe but may reorder events.
+type OutboxRelay struct {
+	Wallet    *Service
+	Publisher events.Publisher
+	// BatchSize defaults to 100.
+	BatchSize int
+	// Retain is how long published events are kept; defaults to DefaultOutboxRetention.
+	Retain time.Duration
+	// MaxAttempts parks an event after that many failed publishes; defaults to
+	// DefaultOutboxMaxAttempts.
+	MaxAttempts int
+	Now         func() time.Time
+}
+
+// RunOnce publishes one batch of pending events and returns how many were
+// published. A publish failure stops the batch, so later events wait for earlier
+// ones, unless it parks the event; the batch then continues.
+func (r OutboxRelay) RunOnce(ctx context.Context) (int, error) {
+	if r.Wallet == nil || r.Publisher == nil {
+		return 0, errors.New("wallet: outbox relay not configured")
+	}
+	batch := r.BatchSize
+	if batch <= 0 {
+		batch = 100
+	}
+	ctx = utils.WithQuery(ctx, "wallet", "RelayOutbox", utils.BatchBudget)
+
+	maxAttempts := r.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultOutboxMaxAttempts
+	}
+	published := 0
+	var publishErr error
+	err := utils.WithTx(ctx, r.Wallet.db, &sql.TxOptions{}, func(ctx context.Context, tx *sql.Tx) error {
+		pending, err := claimOutboxEvents(ctx, tx, batch)
+		if err != nil {
+			return err
+		}
+		for _, e := range pending {
+			if _, perr := r.Publisher.Publish(ctx, e.Envelope); perr != nil {
+				if e.Attempts+1 >= maxAttempts {
+					logger.From(ctx).Error("wallet outbox event parked", "event_id", e.Envelope.ID, "type", e.Envelope.Type, "attempts", e.Attempts+1, "err", perr)
+					if err := markOutboxParked(ctx, tx, e.Envelope.ID, perr.Error(), r.now()); err != nil {
+						return err
+					}
+					continue
+				}
+				// Commit what was published and the failure; retry on the next run.
+				publishErr = fmt.Errorf("publish event %s: %w", e.Envelope.ID, perr)
+				return markOutboxFailed(ctx, tx, e.Envelope.ID, perr.Error())
+			}
+			if err := markOutboxPublished(ctx, tx, e.Envelope.ID, r.now()); err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if publishErr != nil {
+		return published, publishErr
+	}
+
+	retain := r.Retain
+	if retain <= 0 {
+		retain = DefaultOutboxRetention
+	}
+	if err := pruneOutbox(ctx, r.Wallet.db, r.now().Add(-retain)); err != nil {
+		return published, fmt.Errorf("prune outbox: %w", err)
+	}
+	return published, nil
+}
+
+// Run relays on every tick until ctx is canceled.
+func (r OutboxRelay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.RunOnce(ctx); err != nil {
+				logger.From(ctx).Error("wallet outbox relay failed", "err", err)
+			}
+		}
+	}
+}
+
+func (r OutboxRelay) now() time.Time {
+	if r.Now != nil {
+		return r.Now().UTC()
+	}
+	return time.Now().UTC()
+}
@@ -0,0 +1,45 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"telecom-platform/internal/events"
+)
+
+func TestWalletEvent(t *testing.T) {
+	at := time.Date(2024, 3, 5, 12, 0, 0, 0, time.FixedZone("x", 3600))
+	entry := WalletLedger{ID: "le-1", WorkspaceID: "ws", WalletID: "w", AmountMinor: -250, Currency: "USD", ExternalRef: "call-1", CreatedAt: at}
+
+	e, err := walletEvent(events.TypeWalletHoldPlaced, entry, Balance{BalanceMinor: 1000, HeldMinor: 250}, "hold-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.ID != "le-1" || e.WorkspaceID != "ws" || e.Type != events.TypeWalletHoldPlaced || !e.OccurredAt.Equal(at) || e.OccurredAt.Location() != time.UTC {
+		t.Fatalf("unexpected envelope %+v", e)
+	}
+	p, err := e.WalletPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := events.WalletPayload{WalletID: "w", LedgerID: "le-1", HoldID: "hold-1", AmountMinor: 250, Currency: "USD", ExternalRef: "call-1", BalanceMinor: 1000, HeldMinor: 250}
+	if p != want {
+		t.Fatalf("got %+v, want %+v", p, want)
+	}
+
+	if _, err := walletEvent(events.TypeWalletCredited, WalletLedger{ID: "le-2", WalletID: "w"}, Balance{}, ""); err != events.ErrInvalidEvent {
+		t.Fatalf("expected ErrInvalidEvent without workspace, got %v", err)
+	}
+}
+
+func TestOutboxRelay_RequiresConfig(t *testing.T) {
+	ctx := context.Background()
+	if _, err := (OutboxRelay{}).RunOnce(ctx); err == nil {
+		t.Fatalf("expected unconfigured relay to fail")
+	}
+	if _, err := (OutboxRelay{Wallet: NewService((*sql.DB)(nil))}).RunOnce(ctx); err == nil {
+		t.Fatalf("expected relay without publisher to fail")
+	}
+}
@@ -0,0 +1,125 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"telecom-platform/pkg/utils"
+)
+
+// Double-entry postings.
+//
+// Every wallet_ledger row is the tenant leg of a journal; insertLedger writes the
+// balancing leg to a platform system account in the same transaction, so each
+// journal sums to zero:
+// - credit (top-up):        wallet +X, funding     -X (or adjustments for admin credits)
+// - debit (usage, fees):    wallet -X, revenue     +X
+// - hold / release:         wallet -X/+X, holds    +X/-X
+// - reversal:               the original's legs negated, against the same account
+//
+// System legs live in system_ledger_postings, one per ledger entry, and are never
+// updated. Platform revenue for a period is therefore the sum of the revenue
+// account's postings (see SystemAccountTotals). The wallet hash chain covers the
+// tenant leg only, so chains written before postings existed still verify; those
+// older entries get their system legs from a one-off backfill using the defaults.
+
+// SystemAccount is a platform-side ledger account.
+type SystemAccount string
+
+const (
+	// SystemAccountFunding is money received from customers (payments clearing).
+	SystemAccountFunding SystemAccount = "funding"
+	// SystemAccountAdjustments funds manual and goodwill credits.
+	SystemAccountAdjustments SystemAccount = "adjustments"
+	// SystemAccountRevenue is earned platform revenue.
+	SystemAccountRevenue SystemAccount = "revenue"
+	// SystemAccountHolds carries funds reserved by open holds.
+	SystemAccountHolds SystemAccount = "holds"
+)
+
+// SystemPosting is the balancing leg of one ledger entry.
+type SystemPosting struct {
+	// LedgerID is the tenant leg; it is also the posting's primary key.
+	LedgerID    string        `json:"ledger_id" db:"ledger_id"`
+	WorkspaceID string        `json:"workspace_id" db:"workspace_id"`
+	WalletID    string        `json:"wallet_id" db:"wallet_id"`
+	Account     SystemAccount `json:"account" db:"account"`
+	// AmountMinor is always -ledger.AmountMinor.
+	AmountMinor int64     `json:"amount_minor" db:"amount_minor"`
+	Currency    string    `json:"currency" db:"currency"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// SystemAccountTotal is the net of one system account in one currency.
+type SystemAccountTotal struct {
+	Account     SystemAccount `json:"account"`
+	Currency    string        `json:"currency"`
+	AmountMinor int64         `json:"amount_minor"`
+}
+
+var ErrUnbalancedEntry = errors.New("ledger entry does not balance")
+
+// contraAccounts lists the system accounts allowed to balance each entry type;
+// the first is the default. sign is the required sign of the tenant leg; 0 allows
+// either (a reversal takes the opposite sign of whatever it reverses).
+var contraAccounts = map[LedgerEntryType]struct {
+	sign     int64
+	accounts []SystemAccount
+}{
+	LedgerEntryTypeCredit:  {1, []SystemAccount{SystemAccountFunding, SystemAccountAdjustments}},
+	LedgerEntryTypeDebit:   {-1, []SystemAccount{SystemAccountRevenue}},
+	LedgerEntryTypeHold:    {-1, []SystemAccount{SystemAccountHolds}},
+	LedgerEntryTypeRelease: {1, []SystemAccount{SystemAccountHolds}},
+	// Reversals name the original's account explicitly (see reversals.go).
+	LedgerEntryTypeReversal: {0, []SystemAccount{SystemAccountRevenue, SystemAccountFunding, SystemAccountAdjustments}},
+}
+
+// balancingPosting returns the system leg for e, enforcing the double-entry
+// invariants: a known entry type, a non-zero amount with the type's sign, a
+// currency, and a contra account allowed for the type.
+func balancingPosting(e WalletLedger) (SystemPosting, error) {
+	rule, ok := contraAccounts[e.Type]
+	if !ok {
+		return SystemPosting{}, fmt.Errorf("%w: unknown entry type %q", ErrUnbalancedEntry, e.Type)
+	}
+	if e.AmountMinor == 0 || (rule.sign != 0 && (e.AmountMinor > 0) != (rule.sign > 0)) {
+		return SystemPosting{}, fmt.Errorf("%w: %s amount %d has the wrong sign", ErrUnbalancedEntry, e.Type, e.AmountMinor)
+	}
+	if e.Currency == "" {
+		return SystemPosting{}, fmt.Errorf("%w: currency required", ErrUnbalancedEntry)
+	}
+	account := e.ContraAccount
+	if account == "" {
+		account = rule.accounts[0]
+	}
+	allowed := false
+	for _, a := range rule.accounts {
+		allowed = allowed || a == account
+	}
+	if !allowed {
+		return SystemPosting{}, fmt.Errorf("%w: %s entries cannot post against %s", ErrUnbalancedEntry, e.Type, account)
+	}
+	return SystemPosting{
+		LedgerID:    e.ID,
+		WorkspaceID: e.WorkspaceID,
+		WalletID:    e.WalletID,
+		Account:     account,
+		AmountMinor: -e.AmountMinor,
+		Currency:    e.Currency,
+		CreatedAt:   e.CreatedAt,
+	}, nil
+}
+
+// SystemAccountTotals nets every system account per currency for postings created
+// in [from, to). Revenue is positive; funding is negative by the amount customers paid in.
+func (s *Service) SystemAccountTotals(ctx context.Context, from, to time.Time) ([]SystemAccountTotal, error) {
+	if from.IsZero() || to.IsZero() || !from.Before(to) {
+		return nil, ErrInvalidArgument
+	}
+	ctx, done := utils.TrackQuery(reportQuery(ctx, "SystemAccountTotals"))
+	out, err := sumSystemPostings(ctx, s.db, from, to)
+	done(err)
+	return out, err
+}
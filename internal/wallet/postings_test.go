@@ -0,0 +1,47 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBalancingPosting(t *testing.T) {
+	cases := []struct {
+		name    string
+		entry   WalletLedger
+		account SystemAccount
+	}{
+		{"top-up is funded", WalletLedger{Type: LedgerEntryTypeCredit, AmountMinor: 500}, SystemAccountFunding},
+		{"admin credit", WalletLedger{Type: LedgerEntryTypeCredit, AmountMinor: 500, ContraAccount: SystemAccountAdjustments}, SystemAccountAdjustments},
+		{"usage is revenue", WalletLedger{Type: LedgerEntryTypeDebit, AmountMinor: -120}, SystemAccountRevenue},
+		{"hold", WalletLedger{Type: LedgerEntryTypeHold, AmountMinor: -300}, SystemAccountHolds},
+		{"release", WalletLedger{Type: LedgerEntryTypeRelease, AmountMinor: 300}, SystemAccountHolds},
+	}
+	for _, tc := range cases {
+		tc.entry.ID = "l1"
+		tc.entry.Currency = "USD"
+		p, err := balancingPosting(tc.entry)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", tc.name, err)
+		}
+		if p.Account != tc.account || p.AmountMinor+tc.entry.AmountMinor != 0 || p.LedgerID != "l1" || p.Currency != "USD" {
+			t.Fatalf("%s: unexpected posting %+v", tc.name, p)
+		}
+	}
+}
+
+func TestBalancingPosting_RejectsUnbalancedEntries(t *testing.T) {
+	bad := []WalletLedger{
+		{Type: LedgerEntryTypeDebit, AmountMinor: 100, Currency: "USD"},
+		{Type: LedgerEntryTypeCredit, AmountMinor: -100, Currency: "USD"},
+		{Type: LedgerEntryTypeCredit, AmountMinor: 0, Currency: "USD"},
+		{Type: LedgerEntryTypeCredit, AmountMinor: 100},
+		{Type: "refund", AmountMinor: 100, Currency: "USD"},
+		{Type: LedgerEntryTypeDebit, AmountMinor: -100, Currency: "USD", ContraAccount: SystemAccountFunding},
+	}
+	for _, e := range bad {
+		if _, err := balancingPosting(e); !errors.Is(err, ErrUnbalancedEntry) {
+			t.Fatalf("%+v: expected ErrUnbalancedEntry, got %v", e, err)
+		}
+	}
+}
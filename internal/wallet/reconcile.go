@@ -0,0 +1,184 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"telecom-platform/internal/audit"
+	"telecom-platform/pkg/logger"
+	"telecom-platform/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// Ledger vs projection reconciliation.
+//
+// wallet_balances is a projection of wallet_ledger kept in step by the money
+// operations in this package. ReconciliationWorker is the safety net: it recomputes
+// every wallet from the ledger and compares it with the projection:
+// - balance_minor must equal the sum of credit, debit and reversal entries;
+// - held_minor must equal minus the sum of hold and release entries.
+//
+// Archived entries (see archive.go) count through the totals carried by the
+// wallet's LedgerCheckpoint.
+//
+// Both sides are read in one repeatable-read snapshot, so in-flight operations
+// never show up as false discrepancies. Every run is saved as a report; a run with
+// discrepancies is logged at error level, sent to the Alerter and audited in each
+// affected workspace. The worker never corrects balances itself: a mismatch means a
+// bug or an out-of-band write and needs a human.
+
+// WalletTotals is one wallet's projection next to its ledger sums.
+type WalletTotals struct {
+	WorkspaceID string `json:"workspace_id"`
+	WalletID    string `json:"wallet_id"`
+	Currency    string `json:"currency"`
+
+	BalanceMinor int64 `json:"balance_minor"`
+	HeldMinor    int64 `json:"held_minor"`
+
+	// LedgerPostedMinor sums credit, debit and reversal entries; LedgerHeldMinor is minus
+	// the sum of hold and release entries.
+	LedgerPostedMinor int64 `json:"ledger_posted_minor"`
+	LedgerHeldMinor   int64 `json:"ledger_held_minor"`
+}
+
+// Consistent reports whether the projection matches the ledger.
+func (t WalletTotals) Consistent() bool {
+	return t.BalanceMinor == t.LedgerPostedMinor && t.HeldMinor == t.LedgerHeldMinor
+}
+
+// ReconciliationDiscrepancy is a wallet whose projection drifted from its ledger.
+type ReconciliationDiscrepancy struct {
+	ReportID string `json:"report_id" db:"report_id"`
+	WalletTotals
+
+	// Deltas are projection minus ledger.
+	BalanceDeltaMinor int64 `json:"balance_delta_minor" db:"balance_delta_minor"`
+	HeldDeltaMinor    int64 `json:"held_delta_minor" db:"held_delta_minor"`
+}
+
+// ReconciliationReport is the outcome of one run across all wallets.
+type ReconciliationReport struct {
+	ID             string                      `json:"id" db:"id"`
+	StartedAt      time.Time                   `json:"started_at" db:"started_at"`
+	FinishedAt     time.Time                   `json:"finished_at" db:"finished_at"`
+	WalletsChecked int                         `json:"wallets_checked" db:"wallets_checked"`
+	Discrepancies  []ReconciliationDiscrepancy `json:"discrepancies" db:"-"`
+}
+
+// ReconciliationStore persists reports and their discrepancies.
+type ReconciliationStore interface {
+	SaveReconciliation(ctx context.Context, r ReconciliationReport) error
+	// ListReconciliations returns the newest reports first.
+	ListReconciliations(ctx context.Context, limit int) ([]ReconciliationReport, error)
+}
+
+// ReconciliationAlerter is notified of runs that found discrepancies.
+type ReconciliationAlerter interface {
+	ReconciliationMismatch(ctx context.Context, r ReconciliationReport) error
+}
+
+// walletTotalsSource is the part of Service the worker needs.
+type walletTotalsSource interface {
+	WalletTotals(ctx context.Context, each func(WalletTotals) error) error
+}
+
+// WalletTotals streams every wallet's projection and ledger sums, across all
+// workspaces, from a single snapshot.
+func (s *Service) WalletTotals(ctx context.Context, each func(WalletTotals) error) error {
+	ctx = utils.WithQuery(ctx, "wallet", "WalletTotals", utils.BatchBudget)
+	return utils.WithTx(ctx, s.db, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, func(ctx context.Context, tx *sql.Tx) error {
+		return listWalletTotals(ctx, tx, each)
+	})
+}
+
+// ReconcileTotals compares one wallet; ok=false means it is consistent.
+func ReconcileTotals(t WalletTotals) (ReconciliationDiscrepancy, bool) {
+	if t.Consistent() {
+		return ReconciliationDiscrepancy{}, false
+	}
+	return ReconciliationDiscrepancy{
+		WalletTotals:      t,
+		BalanceDeltaMinor: t.BalanceMinor - t.LedgerPostedMinor,
+		HeldDeltaMinor:    t.HeldMinor - t.LedgerHeldMinor,
+	}, true
+}
+
+// ReconciliationWorker compares the projection with the ledger. Run it nightly,
+// as a single instance, off-peak: it reads the whole ledger.
+type ReconciliationWorker struct {
+	Wallet  walletTotalsSource
+	Store   ReconciliationStore
+	Alerter ReconciliationAlerter // optional
+	Audit   *audit.Service        // optional
+	Now     func() time.Time
+}
+
+// RunOnce reconciles every wallet and saves the report.
+func (w ReconciliationWorker) RunOnce(ctx context.Context) (ReconciliationReport, error) {
+	r := ReconciliationReport{ID: uuid.NewString(), StartedAt: w.now(), Discrepancies: []ReconciliationDiscrepancy{}}
+	err := w.Wallet.WalletTotals(ctx, func(t WalletTotals) error {
+		r.WalletsChecked++
+		if d, ok := ReconcileTotals(t); ok {
+			d.ReportID = r.ID
+			r.Discrepancies = append(r.Discrepancies, d)
+		}
+		return nil
+	})
+	if err != nil {
+		return ReconciliationReport{}, err
+	}
+	r.FinishedAt = w.now()
+	if err := w.Store.SaveReconciliation(ctx, r); err != nil {
+		return r, err
+	}
+	if len(r.Discrepancies) == 0 {
+		return r, nil
+	}
+
+	logger.From(ctx).Error("wallet reconciliation found discrepancies", "report_id", r.ID, "wallets", len(r.Discrepancies), "checked", r.WalletsChecked)
+	for _, d := range r.Discrepancies {
+		w.log(ctx, d)
+	}
+	if w.Alerter != nil {
+		if err := w.Alerter.ReconciliationMismatch(ctx, r); err != nil {
+			return r, fmt.Errorf("reconciliation alert failed: %w", err)
+		}
+	}
+	return r, nil
+}
+
+func (w ReconciliationWorker) log(ctx context.Context, d ReconciliationDiscrepancy) {
+	if w.Audit == nil {
+		return
+	}
+	_ = w.Audit.LogAdminAction(ctx, d.WorkspaceID, "system", "", "",
+		fmt.Sprintf("wallet balance does not match ledger (balance delta %d, held delta %d %s)", d.BalanceDeltaMinor, d.HeldDeltaMinor, d.Currency),
+		d.WalletID, fmt.Sprintf(`{"report_id":%q}`, d.ReportID))
+}
+
+// Run reconciles on every tick until ctx is canceled (e.g. interval = 24h).
+func (w ReconciliationWorker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.RunOnce(ctx); err != nil {
+				logger.From(ctx).Error("wallet reconciliation failed", "err", err)
+			}
+		}
+	}
+}
+
+func (w ReconciliationWorker) now() time.Time {
+	if w.Now != nil {
+		return w.Now().UTC()
+	}
+	return time.Now().UTC()
+}
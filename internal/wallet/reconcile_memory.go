@@ -0,0 +1,37 @@
+package wallet
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryReconciliationStore is a simple in-memory ReconciliationStore useful for tests.
+// It is not intended for production use.
+type MemoryReconciliationStore struct {
+	mu      sync.Mutex
+	reports []ReconciliationReport
+}
+
+func NewMemoryReconciliationStore() *MemoryReconciliationStore {
+	return &MemoryReconciliationStore{}
+}
+
+func (s *MemoryReconciliationStore) SaveReconciliation(ctx context.Context, r ReconciliationReport) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reports = append(s.reports, r)
+	return nil
+}
+
+func (s *MemoryReconciliationStore) ListReconciliations(ctx context.Context, limit int) ([]ReconciliationReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]ReconciliationReport, 0, len(s.reports))
+	for i := len(s.reports) - 1; i >= 0; i-- {
+		if limit > 0 && len(out) == limit {
+			break
+		}
+		out = append(out, s.reports[i])
+	}
+	return out, nil
+}
@@ -3,23 +3,44 @@ package wallet
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"strconv"
+	"strings"
 	"time"
+
+	"telecom-platform/internal/events"
+	"telecom-platform/pkg/utils"
 )
 
 // NOTE: This repository assumes the following tables exist:
-// - wallets
+// - wallets (credit_limit_minor default 0)
 // - wallet_ledger (immutable append-only)
-// - wallet_balances (projection)
+// - wallet_balances (projection; balance_minor = posted credits/debits,
+//   held_minor = open holds, both default 0)
+// - wallet_holds
+// - wallet_auto_topups (PRIMARY KEY (workspace_id, wallet_id))
+// - wallet_reconciliation_reports, wallet_reconciliation_discrepancies (report_id FK)
+// - system_ledger_postings (PRIMARY KEY (ledger_id); balancing legs, see postings.go)
+// - wallet_spend_caps (PRIMARY KEY (workspace_id, wallet_id))
 // - admin_wallet_actions
+// - wallet_ledger_checkpoints (PRIMARY KEY (workspace_id, wallet_id); see archive.go)
+// - wallet_balance_snapshots (PRIMARY KEY (workspace_id, wallet_id, as_of); see balance_history.go)
+// - wallet_outbox (PRIMARY KEY (id), envelope jsonb; index on (created_at, id) WHERE published_at IS NULL; see outbox.go)
+//
+// wallet_ledger may be range-partitioned by created_at (see archive.go).
 //
 // It also assumes an idempotency constraint, e.g.:
 // UNIQUE (wallet_id, idempotency_key)
+//
+// and a chain constraint for tamper evidence:
+// UNIQUE (wallet_id, chain_seq)
 
 func lockWallet(ctx context.Context, tx *sql.Tx, workspaceID, walletID string) (Wallet, error) {
+	defer observeLockWait(ctx, time.Now())
 	// Lock the wallet row to serialize concurrent money operations per wallet.
 	const q = `
-SELECT id, workspace_id, currency, status, created_at, updated_at
+SELECT id, workspace_id, currency, status, credit_limit_minor, created_at, updated_at
 FROM wallets
 WHERE workspace_id = $1 AND id = $2
 FOR UPDATE
@@ -30,6 +51,7 @@ FOR UPDATE
 		&w.WorkspaceID,
 		&w.Currency,
 		&w.Status,
+		&w.CreditLimitMinor,
 		&w.CreatedAt,
 		&w.UpdatedAt,
 	); err != nil {
@@ -43,9 +65,10 @@ FOR UPDATE
 
 func getBalance(ctx context.Context, db *sql.DB, workspaceID, walletID string) (Balance, error) {
 	const q = `
-SELECT workspace_id, wallet_id, currency, balance_minor, updated_at
-FROM wallet_balances
-WHERE workspace_id = $1 AND wallet_id = $2
+SELECT b.workspace_id, b.wallet_id, b.currency, b.balance_minor, b.held_minor, w.credit_limit_minor, b.updated_at
+FROM wallet_balances b
+JOIN wallets w ON w.workspace_id = b.workspace_id AND w.id = b.wallet_id
+WHERE b.workspace_id = $1 AND b.wallet_id = $2
 `
 	var b Balance
 	if err := db.QueryRowContext(ctx, q, workspaceID, walletID).Scan(
@@ -53,6 +76,8 @@ WHERE workspace_id = $1 AND wallet_id = $2
 		&b.WalletID,
 		&b.Currency,
 		&b.BalanceMinor,
+		&b.HeldMinor,
+		&b.CreditLimitMinor,
 		&b.UpdatedAt,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -65,9 +90,10 @@ WHERE workspace_id = $1 AND wallet_id = $2
 
 func getBalanceTx(ctx context.Context, tx *sql.Tx, workspaceID, walletID string) (Balance, error) {
 	const q = `
-SELECT workspace_id, wallet_id, currency, balance_minor, updated_at
-FROM wallet_balances
-WHERE workspace_id = $1 AND wallet_id = $2
+SELECT b.workspace_id, b.wallet_id, b.currency, b.balance_minor, b.held_minor, w.credit_limit_minor, b.updated_at
+FROM wallet_balances b
+JOIN wallets w ON w.workspace_id = b.workspace_id AND w.id = b.wallet_id
+WHERE b.workspace_id = $1 AND b.wallet_id = $2
 `
 	var b Balance
 	if err := tx.QueryRowContext(ctx, q, workspaceID, walletID).Scan(
@@ -75,6 +101,8 @@ WHERE workspace_id = $1 AND wallet_id = $2
 		&b.WalletID,
 		&b.Currency,
 		&b.BalanceMinor,
+		&b.HeldMinor,
+		&b.CreditLimitMinor,
 		&b.UpdatedAt,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -87,10 +115,11 @@ WHERE workspace_id = $1 AND wallet_id = $2
 
 func getBalanceForUpdate(ctx context.Context, tx *sql.Tx, workspaceID, walletID string) (Balance, error) {
 	const q = `
-SELECT workspace_id, wallet_id, currency, balance_minor, updated_at
-FROM wallet_balances
-WHERE workspace_id = $1 AND wallet_id = $2
-FOR UPDATE
+SELECT b.workspace_id, b.wallet_id, b.currency, b.balance_minor, b.held_minor, w.credit_limit_minor, b.updated_at
+FROM wallet_balances b
+JOIN wallets w ON w.workspace_id = b.workspace_id AND w.id = b.wallet_id
+WHERE b.workspace_id = $1 AND b.wallet_id = $2
+FOR UPDATE OF b
 `
 	var b Balance
 	if err := tx.QueryRowContext(ctx, q, workspaceID, walletID).Scan(
@@ -98,6 +127,8 @@ FOR UPDATE
 		&b.WalletID,
 		&b.Currency,
 		&b.BalanceMinor,
+		&b.HeldMinor,
+		&b.CreditLimitMinor,
 		&b.UpdatedAt,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -110,7 +141,8 @@ FOR UPDATE
 
 func findLedgerByIdempotency(ctx context.Context, tx *sql.Tx, workspaceID, walletID, key string) (WalletLedger, bool, error) {
 	const q = `
-SELECT id, workspace_id, wallet_id, type, amount_minor, currency, external_ref, idempotency_key, metadata, created_at
+SELECT id, workspace_id, wallet_id, type, amount_minor, currency, external_ref, idempotency_key, metadata, created_at,
+       chain_seq, prev_hash, hash
 FROM wallet_ledger
 WHERE workspace_id = $1 AND wallet_id = $2 AND idempotency_key = $3
 LIMIT 1
@@ -127,6 +159,9 @@ LIMIT 1
 		&e.IdempotencyKey,
 		&e.Metadata,
 		&e.CreatedAt,
+		&e.ChainSeq,
+		&e.PrevHash,
+		&e.Hash,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -137,15 +172,133 @@ LIMIT 1
 	return e, true, nil
 }
 
+// getLedgerEntry loads one entry of the wallet; ErrNotFound when absent.
+func getLedgerEntry(ctx context.Context, tx *sql.Tx, workspaceID, walletID, ledgerID string) (WalletLedger, error) {
+	const q = `
+SELECT id, workspace_id, wallet_id, type, amount_minor, currency, external_ref, idempotency_key, metadata, created_at,
+       chain_seq, prev_hash, hash
+FROM wallet_ledger
+WHERE workspace_id = $1 AND wallet_id = $2 AND id = $3
+`
+	var e WalletLedger
+	err := tx.QueryRowContext(ctx, q, workspaceID, walletID, ledgerID).Scan(
+		&e.ID,
+		&e.WorkspaceID,
+		&e.WalletID,
+		&e.Type,
+		&e.AmountMinor,
+		&e.Currency,
+		&e.ExternalRef,
+		&e.IdempotencyKey,
+		&e.Metadata,
+		&e.CreatedAt,
+		&e.ChainSeq,
+		&e.PrevHash,
+		&e.Hash,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return WalletLedger{}, ErrNotFound
+		}
+		return WalletLedger{}, err
+	}
+	return e, nil
+}
+
+// sumReversals returns the absolute amount already reversed of ledgerID.
+// Backed by the GIN index on wallet_ledger(metadata jsonb_path_ops).
+func sumReversals(ctx context.Context, tx *sql.Tx, workspaceID, walletID, ledgerID string) (int64, error) {
+	const q = `
+SELECT COALESCE(SUM(ABS(amount_minor)), 0)
+FROM wallet_ledger
+WHERE workspace_id = $1 AND wallet_id = $2 AND type = 'reversal' AND metadata @> jsonb_build_object('_reversal_of', $3::text)
+`
+	var n int64
+	err := tx.QueryRowContext(ctx, q, workspaceID, walletID, ledgerID).Scan(&n)
+	return n, err
+}
+
+// getPostingAccount returns the system account that balanced e. Entries without
+// a posting row (not yet backfilled) fall back to the type's default account.
+func getPostingAccount(ctx context.Context, tx *sql.Tx, e WalletLedger) (SystemAccount, error) {
+	const q = `SELECT account FROM system_ledger_postings WHERE ledger_id = $1`
+	var a SystemAccount
+	err := tx.QueryRowContext(ctx, q, e.ID).Scan(&a)
+	if errors.Is(err, sql.ErrNoRows) {
+		if rule, ok := contraAccounts[e.Type]; ok {
+			return rule.accounts[0], nil
+		}
+		return "", ErrNotReversible
+	}
+	return a, err
+}
+
+func getIdempotencyKey(ctx context.Context, tx *sql.Tx, workspaceID, walletID, key string) (IdempotencyKey, bool, error) {
+	const q = `
+SELECT workspace_id, wallet_id, key, fingerprint, ledger_id, created_at, expires_at
+FROM wallet_idempotency_keys
+WHERE workspace_id = $1 AND wallet_id = $2 AND key = $3
+`
+	var k IdempotencyKey
+	err := tx.QueryRowContext(ctx, q, workspaceID, walletID, key).Scan(
+		&k.WorkspaceID,
+		&k.WalletID,
+		&k.Key,
+		&k.Fingerprint,
+		&k.LedgerID,
+		&k.CreatedAt,
+		&k.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return IdempotencyKey{}, false, nil
+		}
+		return IdempotencyKey{}, false, err
+	}
+	return k, true, nil
+}
+
+func insertIdempotencyKey(ctx context.Context, tx *sql.Tx, k IdempotencyKey) error {
+	const q = `
+INSERT INTO wallet_idempotency_keys (workspace_id, wallet_id, key, fingerprint, ledger_id, created_at, expires_at)
+VALUES ($1,$2,$3,$4,$5,$6,$7)
+`
+	_, err := tx.ExecContext(ctx, q, k.WorkspaceID, k.WalletID, k.Key, k.Fingerprint, k.LedgerID, k.CreatedAt, k.ExpiresAt)
+	return err
+}
+
+// deleteExpiredIdempotencyKeys deletes one batch. Backed by an index on
+// wallet_idempotency_keys(expires_at).
+func deleteExpiredIdempotencyKeys(ctx context.Context, db *sql.DB, now time.Time, limit int) (int64, error) {
+	const q = `
+DELETE FROM wallet_idempotency_keys
+WHERE ctid IN (
+  SELECT ctid FROM wallet_idempotency_keys WHERE expires_at < $1 LIMIT $2
+)
+`
+	res, err := db.ExecContext(ctx, q, now, limit)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// insertLedger writes e and its balancing system posting; an entry that would
+// not balance (see balancingPosting) is rejected before anything is written.
 func insertLedger(ctx context.Context, tx *sql.Tx, e WalletLedger) error {
+	p, err := balancingPosting(e)
+	if err != nil {
+		return err
+	}
 	const q = `
 INSERT INTO wallet_ledger (
-  id, workspace_id, wallet_id, type, amount_minor, currency, external_ref, idempotency_key, metadata, created_at
+  id, workspace_id, wallet_id, type, amount_minor, currency, external_ref, idempotency_key, metadata, created_at,
+  chain_seq, prev_hash, hash, metadata_text
 ) VALUES (
-  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14
 )
 `
-	_, err := tx.ExecContext(ctx, q,
+	if _, err := tx.ExecContext(ctx, q,
 		e.ID,
 		e.WorkspaceID,
 		e.WalletID,
@@ -156,10 +309,98 @@ INSERT INTO wallet_ledger (
 		e.IdempotencyKey,
 		e.Metadata,
 		e.CreatedAt,
+		e.ChainSeq,
+		e.PrevHash,
+		e.Hash,
+		e.Metadata, // metadata_text: verbatim copy the chain hashed
+	); err != nil {
+		return err
+	}
+	const qp = `
+INSERT INTO system_ledger_postings (ledger_id, workspace_id, wallet_id, account, amount_minor, currency, created_at)
+VALUES ($1,$2,$3,$4,$5,$6,$7)
+`
+	_, err = tx.ExecContext(ctx, qp,
+		p.LedgerID,
+		p.WorkspaceID,
+		p.WalletID,
+		p.Account,
+		p.AmountMinor,
+		p.Currency,
+		p.CreatedAt,
 	)
 	return err
 }
 
+// appendLedger links e to the wallet's chain head and inserts it.
+// Callers must hold the wallet lock (lockWallet) so the head cannot move.
+func appendLedger(ctx context.Context, tx *sql.Tx, e WalletLedger) (WalletLedger, error) {
+	const q = `
+SELECT chain_seq, hash
+FROM wallet_ledger
+WHERE workspace_id = $1 AND wallet_id = $2
+ORDER BY chain_seq DESC
+LIMIT 1
+`
+	var head WalletLedger
+	err := tx.QueryRowContext(ctx, q, e.WorkspaceID, e.WalletID).Scan(&head.ChainSeq, &head.Hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Nothing live: continue from the last archived entry, if any.
+		var cp LedgerCheckpoint
+		cp, err = getLedgerCheckpoint(ctx, tx, e.WorkspaceID, e.WalletID)
+		head.ChainSeq, head.Hash = cp.Seq, cp.Hash
+	}
+	if err != nil {
+		return WalletLedger{}, err
+	}
+	e = linkLedger(head, e)
+	if err := insertLedger(ctx, tx, e); err != nil {
+		return WalletLedger{}, err
+	}
+	return e, nil
+}
+
+// listLedgerChain reads metadata_text, the verbatim metadata the chain hashed
+// (see LedgerHash), rather than the re-serialized JSONB column.
+func listLedgerChain(ctx context.Context, db *sql.DB, workspaceID, walletID string) ([]WalletLedger, error) {
+	const q = `
+SELECT id, workspace_id, wallet_id, type, amount_minor, currency, external_ref, idempotency_key, metadata_text, created_at,
+       chain_seq, prev_hash, hash
+FROM wallet_ledger
+WHERE workspace_id = $1 AND wallet_id = $2
+ORDER BY chain_seq ASC
+`
+	rows, err := db.QueryContext(ctx, q, workspaceID, walletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]WalletLedger, 0)
+	for rows.Next() {
+		var e WalletLedger
+		if err := rows.Scan(
+			&e.ID,
+			&e.WorkspaceID,
+			&e.WalletID,
+			&e.Type,
+			&e.AmountMinor,
+			&e.Currency,
+			&e.ExternalRef,
+			&e.IdempotencyKey,
+			&e.Metadata,
+			&e.CreatedAt,
+			&e.ChainSeq,
+			&e.PrevHash,
+			&e.Hash,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
 func applyBalanceDelta(ctx context.Context, tx *sql.Tx, workspaceID, walletID, currency string, deltaMinor int64, now time.Time) (Balance, error) {
 	// Upsert the balance row. We keep currency stable. If currency mismatch happens,
 	// the wallet lock + service-level currency check should prevent inconsistencies.
@@ -169,7 +410,8 @@ VALUES ($1,$2,$3,$4,$5)
 ON CONFLICT (workspace_id, wallet_id)
 DO UPDATE SET balance_minor = wallet_balances.balance_minor + EXCLUDED.balance_minor,
               updated_at = EXCLUDED.updated_at
-RETURNING workspace_id, wallet_id, currency, balance_minor, updated_at
+RETURNING workspace_id, wallet_id, currency, balance_minor, held_minor,
+          (SELECT credit_limit_minor FROM wallets WHERE workspace_id = $1 AND id = $2), updated_at
 `
 	var b Balance
 	if err := tx.QueryRowContext(ctx, q, workspaceID, walletID, currency, deltaMinor, now).Scan(
@@ -177,6 +419,8 @@ RETURNING workspace_id, wallet_id, currency, balance_minor, updated_at
 		&b.WalletID,
 		&b.Currency,
 		&b.BalanceMinor,
+		&b.HeldMinor,
+		&b.CreditLimitMinor,
 		&b.UpdatedAt,
 	); err != nil {
 		return Balance{}, err
@@ -241,3 +485,974 @@ LIMIT 1
 	}
 	return a, true, nil
 }
+
+// findLatestAdminAction returns the wallet's most recent admin action of the given type.
+func findLatestAdminAction(ctx context.Context, tx *sql.Tx, workspaceID, walletID string, action AdminWalletActionType) (AdminWalletAction, bool, error) {
+	const q = `
+SELECT id, workspace_id, wallet_id, admin_user_id, admin_role, action, reason,
+       amount_minor, currency, related_ledger_id, metadata, created_at
+FROM admin_wallet_actions
+WHERE workspace_id = $1 AND wallet_id = $2 AND action = $3
+ORDER BY created_at DESC
+LIMIT 1
+`
+	var a AdminWalletAction
+	err := tx.QueryRowContext(ctx, q, workspaceID, walletID, action).Scan(
+		&a.ID,
+		&a.WorkspaceID,
+		&a.WalletID,
+		&a.AdminUserID,
+		&a.AdminRole,
+		&a.Action,
+		&a.Reason,
+		&a.AmountMinor,
+		&a.Currency,
+		&a.RelatedLedgerID,
+		&a.Metadata,
+		&a.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return AdminWalletAction{}, false, nil
+		}
+		return AdminWalletAction{}, false, err
+	}
+	return a, true, nil
+}
+
+func insertWallet(ctx context.Context, tx *sql.Tx, w Wallet) error {
+	const q = `
+INSERT INTO wallets (id, workspace_id, currency, status, credit_limit_minor, created_at, updated_at)
+VALUES ($1,$2,$3,$4,$5,$6,$7)
+`
+	_, err := tx.ExecContext(ctx, q, w.ID, w.WorkspaceID, w.Currency, w.Status, w.CreditLimitMinor, w.CreatedAt, w.UpdatedAt)
+	return err
+}
+
+func updateWalletStatus(ctx context.Context, tx *sql.Tx, w Wallet) error {
+	const q = `
+UPDATE wallets
+SET status = $3, updated_at = $4
+WHERE workspace_id = $1 AND id = $2
+`
+	_, err := tx.ExecContext(ctx, q, w.WorkspaceID, w.ID, w.Status, w.UpdatedAt)
+	return err
+}
+
+func listWallets(ctx context.Context, db *sql.DB, workspaceID string) ([]Wallet, error) {
+	const q = `
+SELECT id, workspace_id, currency, status, credit_limit_minor, created_at, updated_at
+FROM wallets
+WHERE workspace_id = $1
+ORDER BY created_at ASC
+`
+	rows, err := db.QueryContext(ctx, q, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]Wallet, 0)
+	for rows.Next() {
+		var w Wallet
+		if err := rows.Scan(&w.ID, &w.WorkspaceID, &w.Currency, &w.Status, &w.CreditLimitMinor, &w.CreatedAt, &w.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+func listWalletIDs(ctx context.Context, db *sql.DB, workspaceID string) ([]string, error) {
+	const q = `
+SELECT id
+FROM wallets
+WHERE workspace_id = $1
+ORDER BY created_at ASC
+`
+	rows, err := db.QueryContext(ctx, q, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// listLedgerByMetadata returns the newest entries whose metadata contains the
+// given JSONB document. Backed by a GIN index on wallet_ledger(metadata jsonb_path_ops).
+func listLedgerByMetadata(ctx context.Context, db *sql.DB, workspaceID, walletID, containment string, limit int) ([]WalletLedger, error) {
+	const q = `
+SELECT id, workspace_id, wallet_id, type, amount_minor, currency, external_ref, idempotency_key, metadata, created_at,
+       chain_seq, prev_hash, hash
+FROM wallet_ledger
+WHERE workspace_id = $1 AND wallet_id = $2 AND metadata @> $3::jsonb
+ORDER BY chain_seq DESC
+LIMIT $4
+`
+	rows, err := db.QueryContext(ctx, q, workspaceID, walletID, containment, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]WalletLedger, 0)
+	for rows.Next() {
+		var e WalletLedger
+		if err := rows.Scan(
+			&e.ID,
+			&e.WorkspaceID,
+			&e.WalletID,
+			&e.Type,
+			&e.AmountMinor,
+			&e.Currency,
+			&e.ExternalRef,
+			&e.IdempotencyKey,
+			&e.Metadata,
+			&e.CreatedAt,
+			&e.ChainSeq,
+			&e.PrevHash,
+			&e.Hash,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// listLedger returns entries matching q, newest first, with chain_seq < beforeSeq
+// when beforeSeq > 0. Backed by the (wallet_id, chain_seq) unique index.
+func listLedger(ctx context.Context, db *sql.DB, workspaceID, walletID string, q LedgerQuery, containment string, beforeSeq int64, limit int) ([]WalletLedger, error) {
+	where := []string{"workspace_id = $1", "wallet_id = $2"}
+	args := []any{workspaceID, walletID}
+	add := func(cond string, v any) {
+		args = append(args, v)
+		where = append(where, strings.ReplaceAll(cond, "?", "$"+strconv.Itoa(len(args))))
+	}
+	if beforeSeq > 0 {
+		add("chain_seq < ?", beforeSeq)
+	}
+	if !q.From.IsZero() {
+		add("created_at >= ?", q.From)
+	}
+	if !q.To.IsZero() {
+		add("created_at < ?", q.To)
+	}
+	if len(q.Types) > 0 {
+		types := make([]string, len(q.Types))
+		for i, t := range q.Types {
+			types[i] = string(t)
+		}
+		add("type = ANY(?::text[])", "{"+strings.Join(types, ",")+"}")
+	}
+	if q.ExternalRef != "" {
+		add("external_ref = ?", q.ExternalRef)
+	}
+	if containment != "" {
+		add("metadata @> ?::jsonb", containment)
+	}
+	args = append(args, limit)
+	query := `
+SELECT id, workspace_id, wallet_id, type, amount_minor, currency, external_ref, idempotency_key, metadata, created_at,
+       chain_seq, prev_hash, hash
+FROM wallet_ledger
+WHERE ` + strings.Join(where, " AND ") + `
+ORDER BY chain_seq DESC
+LIMIT $` + strconv.Itoa(len(args))
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]WalletLedger, 0)
+	for rows.Next() {
+		var e WalletLedger
+		if err := rows.Scan(
+			&e.ID,
+			&e.WorkspaceID,
+			&e.WalletID,
+			&e.Type,
+			&e.AmountMinor,
+			&e.Currency,
+			&e.ExternalRef,
+			&e.IdempotencyKey,
+			&e.Metadata,
+			&e.CreatedAt,
+			&e.ChainSeq,
+			&e.PrevHash,
+			&e.Hash,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// searchLedger runs a query built by ledgerSearchSQL.
+func searchLedger(ctx context.Context, db *sql.DB, query string, args []any) ([]WalletLedger, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]WalletLedger, 0)
+	for rows.Next() {
+		var e WalletLedger
+		if err := rows.Scan(
+			&e.ID,
+			&e.WorkspaceID,
+			&e.WalletID,
+			&e.Type,
+			&e.AmountMinor,
+			&e.Currency,
+			&e.ExternalRef,
+			&e.IdempotencyKey,
+			&e.Metadata,
+			&e.CreatedAt,
+			&e.ChainSeq,
+			&e.PrevHash,
+			&e.Hash,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// applyHoldDelta adjusts held_minor on the projection row (positive when a hold is
+// placed, negative when it is captured or released).
+func applyHoldDelta(ctx context.Context, tx *sql.Tx, workspaceID, walletID string, deltaMinor int64, now time.Time) (Balance, error) {
+	const q = `
+UPDATE wallet_balances
+SET held_minor = held_minor + $3,
+    updated_at = $4
+WHERE workspace_id = $1 AND wallet_id = $2
+RETURNING workspace_id, wallet_id, currency, balance_minor, held_minor,
+          (SELECT credit_limit_minor FROM wallets WHERE workspace_id = $1 AND id = $2), updated_at
+`
+	var b Balance
+	if err := tx.QueryRowContext(ctx, q, workspaceID, walletID, deltaMinor, now).Scan(
+		&b.WorkspaceID,
+		&b.WalletID,
+		&b.Currency,
+		&b.BalanceMinor,
+		&b.HeldMinor,
+		&b.CreditLimitMinor,
+		&b.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Balance{}, ErrNotFound
+		}
+		return Balance{}, err
+	}
+	return b, nil
+}
+
+func insertHold(ctx context.Context, tx *sql.Tx, h WalletHold) error {
+	const q = `
+INSERT INTO wallet_holds (
+  id, workspace_id, wallet_id, ledger_id, amount_minor, currency, external_ref, status,
+  capture_ledger_id, captured_minor, created_at, updated_at
+) VALUES (
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12
+)
+`
+	_, err := tx.ExecContext(ctx, q,
+		h.ID,
+		h.WorkspaceID,
+		h.WalletID,
+		h.LedgerID,
+		h.AmountMinor,
+		h.Currency,
+		h.ExternalRef,
+		h.Status,
+		h.CaptureLedgerID,
+		h.CapturedMinor,
+		h.CreatedAt,
+		h.UpdatedAt,
+	)
+	return err
+}
+
+func updateHold(ctx context.Context, tx *sql.Tx, h WalletHold) error {
+	const q = `
+UPDATE wallet_holds
+SET status = $4, capture_ledger_id = $5, captured_minor = $6, updated_at = $7
+WHERE workspace_id = $1 AND wallet_id = $2 AND id = $3
+`
+	_, err := tx.ExecContext(ctx, q,
+		h.WorkspaceID,
+		h.WalletID,
+		h.ID,
+		h.Status,
+		h.CaptureLedgerID,
+		h.CapturedMinor,
+		h.UpdatedAt,
+	)
+	return err
+}
+
+func getHoldForUpdate(ctx context.Context, tx *sql.Tx, workspaceID, walletID, holdID string) (WalletHold, error) {
+	const q = `
+SELECT id, workspace_id, wallet_id, ledger_id, amount_minor, currency, external_ref, status,
+       capture_ledger_id, captured_minor, created_at, updated_at
+FROM wallet_holds
+WHERE workspace_id = $1 AND wallet_id = $2 AND id = $3
+FOR UPDATE
+`
+	h, err := scanHold(tx.QueryRowContext(ctx, q, workspaceID, walletID, holdID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return WalletHold{}, ErrHoldNotFound
+	}
+	return h, err
+}
+
+func findHoldByLedger(ctx context.Context, tx *sql.Tx, workspaceID, walletID, ledgerID string) (WalletHold, bool, error) {
+	const q = `
+SELECT id, workspace_id, wallet_id, ledger_id, amount_minor, currency, external_ref, status,
+       capture_ledger_id, captured_minor, created_at, updated_at
+FROM wallet_holds
+WHERE workspace_id = $1 AND wallet_id = $2 AND ledger_id = $3
+LIMIT 1
+`
+	h, err := scanHold(tx.QueryRowContext(ctx, q, workspaceID, walletID, ledgerID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return WalletHold{}, false, nil
+		}
+		return WalletHold{}, false, err
+	}
+	return h, true, nil
+}
+
+func scanHold(row *sql.Row) (WalletHold, error) {
+	var h WalletHold
+	err := row.Scan(
+		&h.ID,
+		&h.WorkspaceID,
+		&h.WalletID,
+		&h.LedgerID,
+		&h.AmountMinor,
+		&h.Currency,
+		&h.ExternalRef,
+		&h.Status,
+		&h.CaptureLedgerID,
+		&h.CapturedMinor,
+		&h.CreatedAt,
+		&h.UpdatedAt,
+	)
+	return h, err
+}
+
+// SQLAutoTopUpStore persists auto top-up rules in wallet_auto_topups.
+type SQLAutoTopUpStore struct {
+	DB *sql.DB
+}
+
+const autoTopUpColumns = `workspace_id, wallet_id, enabled, threshold_minor, topup_amount_minor, payment_method_ref,
+       pending_key, consecutive_failures, last_attempt_at, last_error, updated_at`
+
+func (s SQLAutoTopUpStore) GetAutoTopUp(ctx context.Context, workspaceID, walletID string) (AutoTopUpConfig, bool, error) {
+	q := `SELECT ` + autoTopUpColumns + `
+FROM wallet_auto_topups
+WHERE workspace_id = $1 AND wallet_id = $2
+`
+	rows, err := s.DB.QueryContext(ctx, q, workspaceID, walletID)
+	if err != nil {
+		return AutoTopUpConfig{}, false, err
+	}
+	out, err := scanAutoTopUps(rows)
+	if err != nil || len(out) == 0 {
+		return AutoTopUpConfig{}, false, err
+	}
+	return out[0], true, nil
+}
+
+func (s SQLAutoTopUpStore) PutAutoTopUp(ctx context.Context, c AutoTopUpConfig) error {
+	const q = `
+INSERT INTO wallet_auto_topups (
+  workspace_id, wallet_id, enabled, threshold_minor, topup_amount_minor, payment_method_ref,
+  pending_key, consecutive_failures, last_attempt_at, last_error, updated_at
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+ON CONFLICT (workspace_id, wallet_id) DO UPDATE SET
+  enabled = EXCLUDED.enabled,
+  threshold_minor = EXCLUDED.threshold_minor,
+  topup_amount_minor = EXCLUDED.topup_amount_minor,
+  payment_method_ref = EXCLUDED.payment_method_ref,
+  pending_key = EXCLUDED.pending_key,
+  consecutive_failures = EXCLUDED.consecutive_failures,
+  last_attempt_at = EXCLUDED.last_attempt_at,
+  last_error = EXCLUDED.last_error,
+  updated_at = EXCLUDED.updated_at
+`
+	_, err := s.DB.ExecContext(ctx, q,
+		c.WorkspaceID,
+		c.WalletID,
+		c.Enabled,
+		c.ThresholdMinor,
+		c.TopUpAmountMinor,
+		c.PaymentMethodRef,
+		c.PendingKey,
+		c.ConsecutiveFailures,
+		c.LastAttemptAt,
+		c.LastError,
+		c.UpdatedAt,
+	)
+	return err
+}
+
+func (s SQLAutoTopUpStore) ListEnabledAutoTopUps(ctx context.Context) ([]AutoTopUpConfig, error) {
+	q := `SELECT ` + autoTopUpColumns + `
+FROM wallet_auto_topups
+WHERE enabled
+ORDER BY workspace_id, wallet_id
+`
+	rows, err := s.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return scanAutoTopUps(rows)
+}
+
+func scanAutoTopUps(rows *sql.Rows) ([]AutoTopUpConfig, error) {
+	defer rows.Close()
+	out := make([]AutoTopUpConfig, 0)
+	for rows.Next() {
+		var c AutoTopUpConfig
+		if err := rows.Scan(
+			&c.WorkspaceID,
+			&c.WalletID,
+			&c.Enabled,
+			&c.ThresholdMinor,
+			&c.TopUpAmountMinor,
+			&c.PaymentMethodRef,
+			&c.PendingKey,
+			&c.ConsecutiveFailures,
+			&c.LastAttemptAt,
+			&c.LastError,
+			&c.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func updateWalletCreditLimit(ctx context.Context, tx *sql.Tx, w Wallet) error {
+	const q = `
+UPDATE wallets
+SET credit_limit_minor = $3, updated_at = $4
+WHERE workspace_id = $1 AND id = $2
+`
+	_, err := tx.ExecContext(ctx, q, w.WorkspaceID, w.ID, w.CreditLimitMinor, w.UpdatedAt)
+	return err
+}
+
+func listWalletTotals(ctx context.Context, tx *sql.Tx, each func(WalletTotals) error) error {
+	const q = `
+SELECT b.workspace_id, b.wallet_id, b.currency, b.balance_minor, b.held_minor,
+       COALESCE(c.posted_minor, 0) + COALESCE(SUM(l.amount_minor) FILTER (WHERE l.type IN ('credit', 'debit', 'reversal')), 0),
+       COALESCE(c.held_minor, 0) - COALESCE(SUM(l.amount_minor) FILTER (WHERE l.type IN ('hold', 'release')), 0)
+FROM wallet_balances b
+LEFT JOIN wallet_ledger_checkpoints c ON c.workspace_id = b.workspace_id AND c.wallet_id = b.wallet_id
+LEFT JOIN wallet_ledger l ON l.workspace_id = b.workspace_id AND l.wallet_id = b.wallet_id
+GROUP BY b.workspace_id, b.wallet_id, b.currency, b.balance_minor, b.held_minor, c.posted_minor, c.held_minor
+ORDER BY b.workspace_id, b.wallet_id
+`
+	rows, err := tx.QueryContext(ctx, q)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t WalletTotals
+		if err := rows.Scan(
+			&t.WorkspaceID,
+			&t.WalletID,
+			&t.Currency,
+			&t.BalanceMinor,
+			&t.HeldMinor,
+			&t.LedgerPostedMinor,
+			&t.LedgerHeldMinor,
+		); err != nil {
+			return err
+		}
+		if err := each(t); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// SQLReconciliationStore persists reconciliation reports in
+// wallet_reconciliation_reports and wallet_reconciliation_discrepancies.
+type SQLReconciliationStore struct {
+	DB *sql.DB
+}
+
+func (s SQLReconciliationStore) SaveReconciliation(ctx context.Context, r ReconciliationReport) error {
+	return utils.WithTx(reportQuery(ctx, "SaveReconciliation"), s.DB, &sql.TxOptions{}, func(ctx context.Context, tx *sql.Tx) error {
+		const qr = `
+INSERT INTO wallet_reconciliation_reports (id, started_at, finished_at, wallets_checked, discrepancies)
+VALUES ($1,$2,$3,$4,$5)
+`
+		if _, err := tx.ExecContext(ctx, qr, r.ID, r.StartedAt, r.FinishedAt, r.WalletsChecked, len(r.Discrepancies)); err != nil {
+			return err
+		}
+		const qd = `
+INSERT INTO wallet_reconciliation_discrepancies (
+  report_id, workspace_id, wallet_id, currency, balance_minor, held_minor,
+  ledger_posted_minor, ledger_held_minor, balance_delta_minor, held_delta_minor
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+`
+		for _, d := range r.Discrepancies {
+			if _, err := tx.ExecContext(ctx, qd,
+				r.ID,
+				d.WorkspaceID,
+				d.WalletID,
+				d.Currency,
+				d.BalanceMinor,
+				d.HeldMinor,
+				d.LedgerPostedMinor,
+				d.LedgerHeldMinor,
+				d.BalanceDeltaMinor,
+				d.HeldDeltaMinor,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s SQLReconciliationStore) ListReconciliations(ctx context.Context, limit int) ([]ReconciliationReport, error) {
+	const q = `
+SELECT id, started_at, finished_at, wallets_checked
+FROM wallet_reconciliation_reports
+ORDER BY started_at DESC
+LIMIT $1
+`
+	rows, err := s.DB.QueryContext(ctx, q, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]ReconciliationReport, 0)
+	for rows.Next() {
+		var r ReconciliationReport
+		if err := rows.Scan(&r.ID, &r.StartedAt, &r.FinishedAt, &r.WalletsChecked); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range out {
+		if out[i].Discrepancies, err = s.listDiscrepancies(ctx, out[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (s SQLReconciliationStore) listDiscrepancies(ctx context.Context, reportID string) ([]ReconciliationDiscrepancy, error) {
+	const q = `
+SELECT report_id, workspace_id, wallet_id, currency, balance_minor, held_minor,
+       ledger_posted_minor, ledger_held_minor, balance_delta_minor, held_delta_minor
+FROM wallet_reconciliation_discrepancies
+WHERE report_id = $1
+ORDER BY workspace_id, wallet_id
+`
+	rows, err := s.DB.QueryContext(ctx, q, reportID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]ReconciliationDiscrepancy, 0)
+	for rows.Next() {
+		var d ReconciliationDiscrepancy
+		if err := rows.Scan(
+			&d.ReportID,
+			&d.WorkspaceID,
+			&d.WalletID,
+			&d.Currency,
+			&d.BalanceMinor,
+			&d.HeldMinor,
+			&d.LedgerPostedMinor,
+			&d.LedgerHeldMinor,
+			&d.BalanceDeltaMinor,
+			&d.HeldDeltaMinor,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func sumSystemPostings(ctx context.Context, db *sql.DB, from, to time.Time) ([]SystemAccountTotal, error) {
+	const q = `
+SELECT account, currency, SUM(amount_minor)
+FROM system_ledger_postings
+WHERE created_at >= $1 AND created_at < $2
+GROUP BY account, currency
+ORDER BY account, currency
+`
+	rows, err := db.QueryContext(ctx, q, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]SystemAccountTotal, 0)
+	for rows.Next() {
+		var t SystemAccountTotal
+		if err := rows.Scan(&t.Account, &t.Currency, &t.AmountMinor); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func getSpendCaps(ctx context.Context, tx *sql.Tx, workspaceID, walletID string) (SpendCaps, error) {
+	const q = `
+SELECT workspace_id, wallet_id, daily_cap_minor, monthly_cap_minor, updated_at
+FROM wallet_spend_caps
+WHERE workspace_id = $1 AND wallet_id = $2
+`
+	var c SpendCaps
+	if err := tx.QueryRowContext(ctx, q, workspaceID, walletID).Scan(
+		&c.WorkspaceID,
+		&c.WalletID,
+		&c.DailyCapMinor,
+		&c.MonthlyCapMinor,
+		&c.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return SpendCaps{WorkspaceID: workspaceID, WalletID: walletID}, nil
+		}
+		return SpendCaps{}, err
+	}
+	return c, nil
+}
+
+func upsertSpendCaps(ctx context.Context, tx *sql.Tx, c SpendCaps) error {
+	const q = `
+INSERT INTO wallet_spend_caps (workspace_id, wallet_id, daily_cap_minor, monthly_cap_minor, updated_at)
+VALUES ($1,$2,$3,$4,$5)
+ON CONFLICT (workspace_id, wallet_id) DO UPDATE SET
+  daily_cap_minor = EXCLUDED.daily_cap_minor,
+  monthly_cap_minor = EXCLUDED.monthly_cap_minor,
+  updated_at = EXCLUDED.updated_at
+`
+	_, err := tx.ExecContext(ctx, q, c.WorkspaceID, c.WalletID, c.DailyCapMinor, c.MonthlyCapMinor, c.UpdatedAt)
+	return err
+}
+
+// sumDebitsSince returns debits posted since dayStart and since monthStart
+// (dayStart is never before monthStart), as positive amounts.
+func sumDebitsSince(ctx context.Context, tx *sql.Tx, workspaceID, walletID string, dayStart, monthStart time.Time) (day, month int64, err error) {
+	const q = `
+SELECT -COALESCE(SUM(amount_minor) FILTER (WHERE created_at >= $3), 0),
+       -COALESCE(SUM(amount_minor), 0)
+FROM wallet_ledger
+WHERE workspace_id = $1 AND wallet_id = $2 AND type = 'debit' AND created_at >= $4
+`
+	err = tx.QueryRowContext(ctx, q, workspaceID, walletID, dayStart, monthStart).Scan(&day, &month)
+	return day, month, err
+}
+
+// rowQuerier is satisfied by *sql.DB and *sql.Tx.
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// getLedgerCheckpoint returns the wallet's archive checkpoint; the zero
+// checkpoint (Seq 0) when nothing was archived.
+func getLedgerCheckpoint(ctx context.Context, q rowQuerier, workspaceID, walletID string) (LedgerCheckpoint, error) {
+	const qc = `
+SELECT workspace_id, wallet_id, chain_seq, hash, posted_minor, held_minor, partition, archived_at
+FROM wallet_ledger_checkpoints
+WHERE workspace_id = $1 AND wallet_id = $2
+`
+	var c LedgerCheckpoint
+	if err := q.QueryRowContext(ctx, qc, workspaceID, walletID).Scan(
+		&c.WorkspaceID,
+		&c.WalletID,
+		&c.Seq,
+		&c.Hash,
+		&c.PostedMinor,
+		&c.HeldMinor,
+		&c.Partition,
+		&c.ArchivedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return LedgerCheckpoint{WorkspaceID: workspaceID, WalletID: walletID}, nil
+		}
+		return LedgerCheckpoint{}, err
+	}
+	return c, nil
+}
+
+func upsertLedgerCheckpoint(ctx context.Context, tx *sql.Tx, c LedgerCheckpoint) error {
+	const q = `
+INSERT INTO wallet_ledger_checkpoints (workspace_id, wallet_id, chain_seq, hash, posted_minor, held_minor, partition, archived_at)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+ON CONFLICT (workspace_id, wallet_id) DO UPDATE SET
+  chain_seq = EXCLUDED.chain_seq,
+  hash = EXCLUDED.hash,
+  posted_minor = EXCLUDED.posted_minor,
+  held_minor = EXCLUDED.held_minor,
+  partition = EXCLUDED.partition,
+  archived_at = EXCLUDED.archived_at
+`
+	_, err := tx.ExecContext(ctx, q, c.WorkspaceID, c.WalletID, c.Seq, c.Hash, c.PostedMinor, c.HeldMinor, c.Partition, c.ArchivedAt)
+	return err
+}
+
+// listLedgerPartitionNames returns the names of wallet_ledger's partitions.
+func listLedgerPartitionNames(ctx context.Context, db *sql.DB) ([]string, error) {
+	const q = `
+SELECT c.relname
+FROM pg_inherits i
+JOIN pg_class c ON c.oid = i.inhrelid
+JOIN pg_class p ON p.oid = i.inhparent
+WHERE p.relname = 'wallet_ledger'
+`
+	rows, err := db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		out = append(out, name)
+	}
+	return out, rows.Err()
+}
+
+func createLedgerPartition(ctx context.Context, db *sql.DB, p LedgerPartition) error {
+	_, err := db.ExecContext(ctx, p.createSQL())
+	return err
+}
+
+// streamLedgerPartition reads every row of partition p ordered by wallet and seq,
+// with the verbatim metadata_text the chain hashed.
+// p comes from LedgerPartitionFor/parseLedgerPartition, so its name is safe to
+// interpolate.
+func streamLedgerPartition(ctx context.Context, db *sql.DB, p LedgerPartition, each func(WalletLedger) error) error {
+	q := `
+SELECT id, workspace_id, wallet_id, type, amount_minor, currency, external_ref, idempotency_key, metadata_text, created_at,
+       chain_seq, prev_hash, hash
+FROM ` + p.Name + `
+ORDER BY workspace_id, wallet_id, chain_seq
+`
+	rows, err := db.QueryContext(ctx, q)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e WalletLedger
+		if err := rows.Scan(
+			&e.ID,
+			&e.WorkspaceID,
+			&e.WalletID,
+			&e.Type,
+			&e.AmountMinor,
+			&e.Currency,
+			&e.ExternalRef,
+			&e.IdempotencyKey,
+			&e.Metadata,
+			&e.CreatedAt,
+			&e.ChainSeq,
+			&e.PrevHash,
+			&e.Hash,
+		); err != nil {
+			return err
+		}
+		if err := each(e); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// dropLedgerPartition detaches and drops an archived partition. DETACH takes an
+// exclusive lock on wallet_ledger for the rest of the transaction.
+func dropLedgerPartition(ctx context.Context, tx *sql.Tx, p LedgerPartition) error {
+	// Give up rather than queue money operations behind a long wait for the lock.
+	if _, err := tx.ExecContext(ctx, `SET LOCAL lock_timeout = 5000`); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `ALTER TABLE wallet_ledger DETACH PARTITION `+p.Name); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `DROP TABLE `+p.Name)
+	return err
+}
+
+// latestBalanceSnapshot returns the wallet's newest snapshot at or before t.
+func latestBalanceSnapshot(ctx context.Context, db *sql.DB, workspaceID, walletID string, t time.Time) (BalanceSnapshot, bool, error) {
+	const q = `
+SELECT workspace_id, wallet_id, currency, as_of, balance_minor, held_minor, created_at
+FROM wallet_balance_snapshots
+WHERE workspace_id = $1 AND wallet_id = $2 AND as_of <= $3
+ORDER BY as_of DESC
+LIMIT 1
+`
+	var b BalanceSnapshot
+	if err := db.QueryRowContext(ctx, q, workspaceID, walletID, t).Scan(
+		&b.WorkspaceID,
+		&b.WalletID,
+		&b.Currency,
+		&b.AsOf,
+		&b.BalanceMinor,
+		&b.HeldMinor,
+		&b.CreatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BalanceSnapshot{}, false, nil
+		}
+		return BalanceSnapshot{}, false, err
+	}
+	return b, true, nil
+}
+
+func upsertBalanceSnapshot(ctx context.Context, db *sql.DB, b BalanceSnapshot) error {
+	const q = `
+INSERT INTO wallet_balance_snapshots (workspace_id, wallet_id, currency, as_of, balance_minor, held_minor, created_at)
+VALUES ($1,$2,$3,$4,$5,$6,$7)
+ON CONFLICT (workspace_id, wallet_id, as_of) DO UPDATE SET
+  balance_minor = EXCLUDED.balance_minor,
+  held_minor = EXCLUDED.held_minor,
+  created_at = EXCLUDED.created_at
+`
+	_, err := db.ExecContext(ctx, q, b.WorkspaceID, b.WalletID, b.Currency, b.AsOf, b.BalanceMinor, b.HeldMinor, b.CreatedAt)
+	return err
+}
+
+// sumLedgerBetween returns the posted and held deltas of entries created in
+// [from, to), with the signs of wallet_balances.
+func sumLedgerBetween(ctx context.Context, db *sql.DB, workspaceID, walletID string, from, to time.Time) (posted, held int64, err error) {
+	const q = `
+SELECT COALESCE(SUM(amount_minor) FILTER (WHERE type IN ('credit', 'debit', 'reversal')), 0),
+       -COALESCE(SUM(amount_minor) FILTER (WHERE type IN ('hold', 'release')), 0)
+FROM wallet_ledger
+WHERE workspace_id = $1 AND wallet_id = $2 AND created_at >= $3 AND created_at < $4
+`
+	err = db.QueryRowContext(ctx, q, workspaceID, walletID, from, to).Scan(&posted, &held)
+	return posted, held, err
+}
+
+// listWalletsCreatedBefore lists wallets across all workspaces created before t.
+func listWalletsCreatedBefore(ctx context.Context, db *sql.DB, t time.Time) ([]Wallet, error) {
+	const q = `
+SELECT id, workspace_id, currency, status, credit_limit_minor, created_at, updated_at
+FROM wallets
+WHERE created_at < $1
+ORDER BY workspace_id, id
+`
+	rows, err := db.QueryContext(ctx, q, t)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]Wallet, 0)
+	for rows.Next() {
+		var w Wallet
+		if err := rows.Scan(&w.ID, &w.WorkspaceID, &w.Currency, &w.Status, &w.CreditLimitMinor, &w.CreatedAt, &w.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+func insertOutboxEvent(ctx context.Context, tx *sql.Tx, e events.Envelope) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	const q = `
+INSERT INTO wallet_outbox (id, workspace_id, type, envelope, created_at)
+VALUES ($1,$2,$3,$4,$5)
+`
+	_, err = tx.ExecContext(ctx, q, e.ID, e.WorkspaceID, string(e.Type), string(body), e.OccurredAt)
+	return err
+}
+
+// claimOutboxEvents locks the oldest unpublished, unparked events for this transaction.
+func claimOutboxEvents(ctx context.Context, tx *sql.Tx, limit int) ([]OutboxEvent, error) {
+	const q = `
+SELECT envelope, created_at, attempts
+FROM wallet_outbox
+WHERE published_at IS NULL AND parked_at IS NULL
+ORDER BY created_at, id
+LIMIT $1
+FOR UPDATE SKIP LOCKED
+`
+	rows, err := tx.QueryContext(ctx, q, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []OutboxEvent
+	for rows.Next() {
+		var body []byte
+		var e OutboxEvent
+		if err := rows.Scan(&body, &e.CreatedAt, &e.Attempts); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(body, &e.Envelope); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func markOutboxPublished(ctx context.Context, tx *sql.Tx, id string, at time.Time) error {
+	const q = `UPDATE wallet_outbox SET published_at = $2, last_error = NULL WHERE id = $1`
+	_, err := tx.ExecContext(ctx, q, id, at)
+	return err
+}
+
+func markOutboxFailed(ctx context.Context, tx *sql.Tx, id, reason string) error {
+	const q = `UPDATE wallet_outbox SET attempts = attempts + 1, last_error = $2 WHERE id = $1`
+	_, err := tx.ExecContext(ctx, q, id, reason)
+	return err
+}
+
+func markOutboxParked(ctx context.Context, tx *sql.Tx, id, reason string, at time.Time) error {
+	const q = `UPDATE wallet_outbox SET attempts = attempts + 1, last_error = $2, parked_at = $3 WHERE id = $1`
+	_, err := tx.ExecContext(ctx, q, id, reason, at)
+	return err
+}
+
+func pruneOutbox(ctx context.Context, db *sql.DB, before time.Time) error {
+	const q = `DELETE FROM wallet_outbox WHERE published_at < $1`
+	_, err := db.ExecContext(ctx, q, before)
+	return err
+}
@@ -0,0 +1,242 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"telecom-platform/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// Refunds and reversals.
+//
+// A posted entry is never edited. To undo a credit or debit (refund a call charge,
+// claw back a top-up that was charged back), an admin posts a reversal: an entry of
+// type "reversal" with the opposite sign, the original's currency and ExternalRef,
+// balanced against the same system account as the original, so revenue, funding
+// and adjustments net out exactly. The original's id is recorded under the
+// platform-reserved "_reversal_of" metadata key.
+//
+// An entry may be reversed in several parts until its full amount has been
+// reversed; after that Reverse fails with ErrAlreadyReversed. Holds, releases and
+// reversals themselves cannot be reversed (holds settle through holds.go).
+//
+// Reversing a debit credits the wallet and follows the credit rules (checkCanCredit).
+// Reversing a credit takes money back and needs spendable funds (checkCanSpend,
+// checkFunds); it does not count against spend caps.
+
+var (
+	ErrAlreadyReversed = errors.New("ledger entry already fully reversed")
+	// ErrReversalExceedsEntry and ErrNotReversible also match ErrInvalidArgument.
+	ErrReversalExceedsEntry = fmt.Errorf("%w: reversal exceeds the unreversed amount", ErrInvalidArgument)
+	ErrNotReversible        = fmt.Errorf("%w: ledger entry type cannot be reversed", ErrInvalidArgument)
+)
+
+type ReverseRequest struct {
+	// AmountMinor is the positive amount to reverse; 0 reverses whatever is left.
+	AmountMinor    int64  `json:"amount_minor"`
+	Reason         string `json:"reason"`
+	IdempotencyKey string `json:"idempotency_key"`
+	Metadata       string `json:"metadata,omitempty"`
+}
+
+// reversalMetadataKey holds the reversed entry's id (prefixed with "_" when stored).
+const reversalMetadataKey = "reversal_of"
+
+// Reverse posts a full or partial reversal of the wallet's ledger entry ledgerID
+// and records the admin action. Repeating a request with the same idempotency key
+// returns the original result.
+func (s *Service) Reverse(ctx context.Context, workspaceID, walletID, ledgerID, adminUserID, adminRole string, req ReverseRequest) (AdminWalletAction, WalletLedger, Balance, error) {
+	req.Reason = strings.TrimSpace(req.Reason)
+	if workspaceID == "" || walletID == "" || ledgerID == "" || adminUserID == "" || adminRole == "" {
+		return AdminWalletAction{}, WalletLedger{}, Balance{}, ErrInvalidArgument
+	}
+	if req.Reason == "" || req.IdempotencyKey == "" || req.AmountMinor < 0 {
+		return AdminWalletAction{}, WalletLedger{}, Balance{}, ErrInvalidArgument
+	}
+	metadata, err := NormalizeMetadata(LedgerEntryTypeReversal, req.Metadata)
+	if err != nil {
+		return AdminWalletAction{}, WalletLedger{}, Balance{}, err
+	}
+	fp := requestFingerprint("reverse", ledgerID, amountField(req.AmountMinor), req.Reason, metadata)
+	metadata, err = withReservedMetadata(metadata, reversalMetadataKey, ledgerID)
+	if err != nil {
+		return AdminWalletAction{}, WalletLedger{}, Balance{}, err
+	}
+
+	now := s.clock().UTC()
+	actionID := uuid.NewString()
+	reversalID := uuid.NewString()
+
+	var outAction AdminWalletAction
+	var outLedger WalletLedger
+	var outBal Balance
+
+	ctx, op := s.Metrics.track(ctx, "Reverse")
+	err = utils.WithTxRetry(moneyQuery(ctx, "Reverse"), s.db, &sql.TxOptions{}, utils.DefaultTxRetry, func(ctx context.Context, tx *sql.Tx) error {
+		// The wallet lock also serializes reversals of the same entry.
+		w, err := lockWallet(ctx, tx, workspaceID, walletID)
+		if err != nil {
+			return err
+		}
+
+		if existing, ok, err := replayIdempotent(ctx, tx, workspaceID, walletID, req.IdempotencyKey, fp, reversalMatch(ledgerID, req.AmountMinor)); err != nil {
+			return err
+		} else if ok {
+			outLedger = existing
+			act, ok, err := findAdminActionByLedger(ctx, tx, workspaceID, walletID, existing.ID)
+			if err != nil {
+				return err
+			}
+			if ok {
+				outAction = act
+			}
+			b, err := getBalanceTx(ctx, tx, workspaceID, walletID)
+			if err != nil {
+				return err
+			}
+			outBal = b
+			return nil
+		}
+
+		orig, err := getLedgerEntry(ctx, tx, workspaceID, walletID, ledgerID)
+		if err != nil {
+			return err
+		}
+		if orig.Type != LedgerEntryTypeCredit && orig.Type != LedgerEntryTypeDebit {
+			return ErrNotReversible
+		}
+		reversed, err := sumReversals(ctx, tx, workspaceID, walletID, ledgerID)
+		if err != nil {
+			return err
+		}
+		amount, err := reversalAmount(orig, reversed, req.AmountMinor)
+		if err != nil {
+			return err
+		}
+
+		if orig.Type == LedgerEntryTypeDebit {
+			if err := checkCanCredit(w); err != nil {
+				return err
+			}
+		} else {
+			if err := checkCanSpend(w); err != nil {
+				return err
+			}
+			b, err := getBalanceForUpdate(ctx, tx, workspaceID, walletID)
+			if err != nil {
+				return err
+			}
+			if err := checkFunds(b, -amount); err != nil {
+				return err
+			}
+		}
+
+		account, err := getPostingAccount(ctx, tx, orig)
+		if err != nil {
+			return err
+		}
+		entry := WalletLedger{
+			ID:             reversalID,
+			WorkspaceID:    workspaceID,
+			WalletID:       walletID,
+			Type:           LedgerEntryTypeReversal,
+			AmountMinor:    amount,
+			Currency:       orig.Currency,
+			ExternalRef:    orig.ExternalRef,
+			IdempotencyKey: req.IdempotencyKey,
+			Metadata:       metadata,
+			CreatedAt:      now,
+			ContraAccount:  account,
+		}
+		entry, err = appendLedger(ctx, tx, entry)
+		if err != nil {
+			return err
+		}
+		if err := s.recordIdempotency(ctx, tx, entry, fp); err != nil {
+			return err
+		}
+
+		b, err := applyBalanceDelta(ctx, tx, workspaceID, walletID, orig.Currency, amount, now)
+		if err != nil {
+			return err
+		}
+
+		actionMeta, err := json.Marshal(map[string]string{reversalMetadataKey: ledgerID})
+		if err != nil {
+			return err
+		}
+		action := AdminWalletAction{
+			ID:              actionID,
+			WorkspaceID:     workspaceID,
+			WalletID:        walletID,
+			AdminUserID:     adminUserID,
+			AdminRole:       adminRole,
+			Action:          AdminWalletActionTypeReverse,
+			Reason:          req.Reason,
+			AmountMinor:     amount,
+			Currency:        orig.Currency,
+			RelatedLedgerID: entry.ID,
+			Metadata:        string(actionMeta),
+			CreatedAt:       now,
+		}
+		if err := insertAdminAction(ctx, tx, action); err != nil {
+			return err
+		}
+
+		outAction = action
+		outLedger = entry
+		outBal = b
+		return nil
+	})
+	op.end(err)
+
+	return outAction, outLedger, outBal, err
+}
+
+// reversalAmount returns the signed amount of a reversal of orig given the
+// absolute amount already reversed; requested 0 means the remainder.
+func reversalAmount(orig WalletLedger, reversed, requested int64) (int64, error) {
+	total := orig.AmountMinor
+	if total < 0 {
+		total = -total
+	}
+	remaining := total - reversed
+	if remaining <= 0 {
+		return 0, ErrAlreadyReversed
+	}
+	if requested == 0 {
+		requested = remaining
+	}
+	if requested > remaining {
+		return 0, ErrReversalExceedsEntry
+	}
+	if orig.AmountMinor > 0 {
+		return -requested, nil
+	}
+	return requested, nil
+}
+
+// reversalMatch matches a reversal of ledgerID for requested (0: any amount),
+// for keys resolved without a fingerprint.
+func reversalMatch(ledgerID string, requested int64) entryMatcher {
+	return func(e WalletLedger) (bool, error) {
+		if e.Type != LedgerEntryTypeReversal {
+			return false, nil
+		}
+		if requested != 0 && e.AmountMinor != requested && e.AmountMinor != -requested {
+			return false, nil
+		}
+		var meta map[string]any
+		if err := json.Unmarshal([]byte(e.Metadata), &meta); err != nil {
+			return false, nil
+		}
+		of, _ := meta[reservedMetadataPrefix+reversalMetadataKey].(string)
+		return of == ledgerID, nil
+	}
+}
@@ -0,0 +1,71 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestReversalAmount(t *testing.T) {
+	debit := WalletLedger{Type: LedgerEntryTypeDebit, AmountMinor: -500}
+	credit := WalletLedger{Type: LedgerEntryTypeCredit, AmountMinor: 1000}
+
+	cases := []struct {
+		name      string
+		orig      WalletLedger
+		reversed  int64
+		requested int64
+		want      int64
+		err       error
+	}{
+		{"full refund of a debit", debit, 0, 0, 500, nil},
+		{"partial refund of a debit", debit, 0, 200, 200, nil},
+		{"rest after a partial refund", debit, 200, 0, 300, nil},
+		{"claw back a credit", credit, 0, 400, -400, nil},
+		{"more than remains", debit, 200, 301, 0, ErrReversalExceedsEntry},
+		{"already reversed", credit, 1000, 0, 0, ErrAlreadyReversed},
+	}
+	for _, tc := range cases {
+		got, err := reversalAmount(tc.orig, tc.reversed, tc.requested)
+		if !errors.Is(err, tc.err) || got != tc.want {
+			t.Fatalf("%s: got %d, %v; want %d, %v", tc.name, got, err, tc.want, tc.err)
+		}
+	}
+	if !errors.Is(ErrReversalExceedsEntry, ErrInvalidArgument) {
+		t.Fatalf("ErrReversalExceedsEntry must match ErrInvalidArgument")
+	}
+}
+
+func TestBalancingPosting_Reversal(t *testing.T) {
+	refund := WalletLedger{ID: "r1", Type: LedgerEntryTypeReversal, AmountMinor: 500, Currency: "USD", ContraAccount: SystemAccountRevenue}
+	if p, err := balancingPosting(refund); err != nil || p.Account != SystemAccountRevenue || p.AmountMinor != -500 {
+		t.Fatalf("unexpected refund posting %+v %v", p, err)
+	}
+	clawback := WalletLedger{ID: "r2", Type: LedgerEntryTypeReversal, AmountMinor: -300, Currency: "USD", ContraAccount: SystemAccountFunding}
+	if p, err := balancingPosting(clawback); err != nil || p.Account != SystemAccountFunding || p.AmountMinor != 300 {
+		t.Fatalf("unexpected clawback posting %+v %v", p, err)
+	}
+	held := WalletLedger{ID: "r3", Type: LedgerEntryTypeReversal, AmountMinor: 300, Currency: "USD", ContraAccount: SystemAccountHolds}
+	if _, err := balancingPosting(held); !errors.Is(err, ErrUnbalancedEntry) {
+		t.Fatalf("expected reversals against holds to be rejected, got %v", err)
+	}
+}
+
+func TestReverse_RejectsInvalidArgs(t *testing.T) {
+	svc := NewService((*sql.DB)(nil))
+	ctx := context.Background()
+
+	if _, _, _, err := svc.Reverse(ctx, "ws", "w", "l1", "admin", "super_admin", ReverseRequest{IdempotencyKey: "k"}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without reason, got %v", err)
+	}
+	if _, _, _, err := svc.Reverse(ctx, "ws", "w", "", "admin", "super_admin", ReverseRequest{Reason: "refund", IdempotencyKey: "k"}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without ledger id, got %v", err)
+	}
+	if _, _, _, err := svc.Reverse(ctx, "ws", "w", "l1", "admin", "super_admin", ReverseRequest{Reason: "refund", IdempotencyKey: "k", AmountMinor: -1}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument for negative amount, got %v", err)
+	}
+	if _, _, _, err := svc.Reverse(ctx, "ws", "w", "l1", "admin", "super_admin", ReverseRequest{Reason: "refund", IdempotencyKey: "k", Metadata: `{"_reversal_of":"x"}`}); !errors.Is(err, ErrInvalidMetadata) {
+		t.Fatalf("expected reserved metadata key to be rejected, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"time"
 
+	"telecom-platform/internal/events"
 	"telecom-platform/pkg/utils"
 
 	"github.com/google/uuid"
@@ -24,14 +25,24 @@ import (
 // Balance strategy:
 // - Balance is stored in a projection table (wallet_balances) updated atomically
 //   alongside ledger inserts.
+// - ReconciliationWorker (reconcile.go) checks the projection against the ledger.
 type Service struct {
 	db *sql.DB
 	// clock is injectable for deterministic tests.
 	clock func() time.Time
+
+	// AutoTopUps stores auto top-up rules (see autotopup.go). Optional.
+	AutoTopUps AutoTopUpStore
+	// FX supplies exchange rates for CreditConverted (see fx.go). Optional.
+	FX FXRates
+	// IdempotencyTTL is how long idempotency records are kept (see idempotency.go).
+	IdempotencyTTL time.Duration
+	// Metrics records money operations (see metrics.go); nil disables them.
+	Metrics *MoneyMetrics
 }
 
 func NewService(db *sql.DB) *Service {
-	return &Service{db: db, clock: time.Now}
+	return &Service{db: db, clock: time.Now, Metrics: NewMoneyMetrics()}
 }
 
 type Balance struct {
@@ -39,9 +50,25 @@ type Balance struct {
 	WalletID     string `json:"wallet_id"`
 	Currency     string `json:"currency"`
 	BalanceMinor int64  `json:"balance_minor"`
+	// HeldMinor is reserved by open holds (see holds.go); it is still part of BalanceMinor.
+	HeldMinor    int64  `json:"held_minor"`
+	// CreditLimitMinor is the wallet's overdraft allowance (see credit_limit.go).
+	CreditLimitMinor int64 `json:"credit_limit_minor"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// Available is the balance not reserved by open holds. It is negative while the
+// wallet is drawing on its credit line.
+func (b Balance) Available() int64 {
+	return b.BalanceMinor - b.HeldMinor
+}
+
+// Spendable is what can still be debited or reserved: available balance plus the
+// credit line.
+func (b Balance) Spendable() int64 {
+	return b.Available() + b.CreditLimitMinor
+}
+
 type CreditRequest struct {
 	AmountMinor     int64  `json:"amount_minor"`
 	Currency        string `json:"currency"`
@@ -72,11 +99,17 @@ var (
 	ErrInvalidArgument  = errors.New("invalid argument")
 )
 
+// maxMetadataResults caps ListLedgerByMetadata when the caller passes no limit.
+const maxMetadataResults = 200
+
 func (s *Service) GetBalance(ctx context.Context, workspaceID, walletID string) (Balance, error) {
 	if workspaceID == "" || walletID == "" {
 		return Balance{}, ErrInvalidArgument
 	}
-	return getBalance(ctx, s.db, workspaceID, walletID)
+	ctx, done := utils.TrackQuery(moneyQuery(ctx, "GetBalance"))
+	b, err := getBalance(ctx, s.db, workspaceID, walletID)
+	done(err)
+	return b, err
 }
 
 func (s *Service) Credit(ctx context.Context, workspaceID, walletID string, req CreditRequest) (WalletLedger, Balance, error) {
@@ -86,14 +119,25 @@ func (s *Service) Credit(ctx context.Context, workspaceID, walletID string, req
 	if req.AmountMinor <= 0 {
 		return WalletLedger{}, Balance{}, ErrInvalidArgument
 	}
+	metadata, err := NormalizeMetadata(LedgerEntryTypeCredit, req.Metadata)
+	if err != nil {
+		return WalletLedger{}, Balance{}, err
+	}
+	fp := requestFingerprint("credit", amountField(req.AmountMinor), req.Currency, req.ExternalRef, metadata)
+	return s.postCredit(ctx, workspaceID, walletID, req, metadata, fp)
+}
 
+// postCredit appends a credit whose metadata has already been normalized; fp is
+// the caller's request fingerprint.
+func (s *Service) postCredit(ctx context.Context, workspaceID, walletID string, req CreditRequest, metadata, fp string) (WalletLedger, Balance, error) {
 	now := s.clock().UTC()
 	ledgerID := uuid.NewString()
 
 	var outLedger WalletLedger
 	var outBal Balance
 
-	err := utils.WithTx(ctx, s.db, &sql.TxOptions{}, func(ctx context.Context, tx *sql.Tx) error {
+	ctx, op := s.Metrics.track(ctx, "Credit")
+	err := utils.WithTxRetry(moneyQuery(ctx, "Credit"), s.db, &sql.TxOptions{}, utils.DefaultTxRetry, func(ctx context.Context, tx *sql.Tx) error {
 		// Ensure wallet exists + currency matches.
 		w, err := lockWallet(ctx, tx, workspaceID, walletID)
 		if err != nil {
@@ -103,8 +147,8 @@ func (s *Service) Credit(ctx context.Context, workspaceID, walletID string, req
 			return ErrInvalidArgument
 		}
 
-		// Idempotency: if a ledger entry already exists for this wallet+key, return it and the balance.
-		if existing, ok, err := findLedgerByIdempotency(ctx, tx, workspaceID, walletID, req.IdempotencyKey); err != nil {
+		// Idempotency: a retry of the same request returns the original entry and the balance.
+		if existing, ok, err := replayIdempotent(ctx, tx, workspaceID, walletID, req.IdempotencyKey, fp, sameEntry(LedgerEntryTypeCredit, req.AmountMinor, req.ExternalRef)); err != nil {
 			return err
 		} else if ok {
 			outLedger = existing
@@ -116,6 +160,10 @@ func (s *Service) Credit(ctx context.Context, workspaceID, walletID string, req
 			return nil
 		}
 
+		if err := checkCanCredit(w); err != nil {
+			return err
+		}
+
 		entry := WalletLedger{
 			ID:             ledgerID,
 			WorkspaceID:    workspaceID,
@@ -125,10 +173,14 @@ func (s *Service) Credit(ctx context.Context, workspaceID, walletID string, req
 			Currency:       req.Currency,
 			ExternalRef:    req.ExternalRef,
 			IdempotencyKey: req.IdempotencyKey,
-			Metadata:       req.Metadata,
+			Metadata:       metadata,
 			CreatedAt:      now,
 		}
-		if err := insertLedger(ctx, tx, entry); err != nil {
+		entry, err = appendLedger(ctx, tx, entry)
+		if err != nil {
+			return err
+		}
+		if err := s.recordIdempotency(ctx, tx, entry, fp); err != nil {
 			return err
 		}
 
@@ -137,10 +189,14 @@ func (s *Service) Credit(ctx context.Context, workspaceID, walletID string, req
 		if err != nil {
 			return err
 		}
+		if err := emitEvent(ctx, tx, events.TypeWalletCredited, entry, b, ""); err != nil {
+			return err
+		}
 		outLedger = entry
 		outBal = b
 		return nil
 	})
+	op.end(err)
 
 	return outLedger, outBal, err
 }
@@ -152,6 +208,11 @@ func (s *Service) Debit(ctx context.Context, workspaceID, walletID string, req D
 	if req.AmountMinor <= 0 {
 		return WalletLedger{}, Balance{}, ErrInvalidArgument
 	}
+	metadata, err := NormalizeMetadata(LedgerEntryTypeDebit, req.Metadata)
+	if err != nil {
+		return WalletLedger{}, Balance{}, err
+	}
+	fp := requestFingerprint("debit", amountField(req.AmountMinor), req.Currency, req.ExternalRef, metadata)
 
 	now := s.clock().UTC()
 	ledgerID := uuid.NewString()
@@ -159,7 +220,8 @@ func (s *Service) Debit(ctx context.Context, workspaceID, walletID string, req D
 	var outLedger WalletLedger
 	var outBal Balance
 
-	err := utils.WithTx(ctx, s.db, &sql.TxOptions{}, func(ctx context.Context, tx *sql.Tx) error {
+	ctx, op := s.Metrics.track(ctx, "Debit")
+	err = utils.WithTxRetry(moneyQuery(ctx, "Debit"), s.db, &sql.TxOptions{}, utils.DefaultTxRetry, func(ctx context.Context, tx *sql.Tx) error {
 		w, err := lockWallet(ctx, tx, workspaceID, walletID)
 		if err != nil {
 			return err
@@ -168,7 +230,7 @@ func (s *Service) Debit(ctx context.Context, workspaceID, walletID string, req D
 			return ErrInvalidArgument
 		}
 
-		if existing, ok, err := findLedgerByIdempotency(ctx, tx, workspaceID, walletID, req.IdempotencyKey); err != nil {
+		if existing, ok, err := replayIdempotent(ctx, tx, workspaceID, walletID, req.IdempotencyKey, fp, sameEntry(LedgerEntryTypeDebit, -req.AmountMinor, req.ExternalRef)); err != nil {
 			return err
 		} else if ok {
 			outLedger = existing
@@ -180,7 +242,11 @@ func (s *Service) Debit(ctx context.Context, workspaceID, walletID string, req D
 			return nil
 		}
 
-		// Ensure sufficient funds using the projection row and lock it.
+		if err := checkCanSpend(w); err != nil {
+			return err
+		}
+
+		// Ensure sufficient funds (net of open holds) using the projection row and lock it.
 		b, err := getBalanceForUpdate(ctx, tx, workspaceID, walletID)
 		if err != nil {
 			return err
@@ -188,8 +254,11 @@ func (s *Service) Debit(ctx context.Context, workspaceID, walletID string, req D
 		if b.Currency != req.Currency {
 			return ErrInvalidArgument
 		}
-		if b.BalanceMinor < req.AmountMinor {
-			return ErrInsufficientFunds
+		if err := checkFunds(b, req.AmountMinor); err != nil {
+			return err
+		}
+		if err := checkSpendCaps(ctx, tx, b, req.AmountMinor, now); err != nil {
+			return err
 		}
 
 		entry := WalletLedger{
@@ -201,10 +270,14 @@ func (s *Service) Debit(ctx context.Context, workspaceID, walletID string, req D
 			Currency:       req.Currency,
 			ExternalRef:    req.ExternalRef,
 			IdempotencyKey: req.IdempotencyKey,
-			Metadata:       req.Metadata,
+			Metadata:       metadata,
 			CreatedAt:      now,
 		}
-		if err := insertLedger(ctx, tx, entry); err != nil {
+		entry, err = appendLedger(ctx, tx, entry)
+		if err != nil {
+			return err
+		}
+		if err := s.recordIdempotency(ctx, tx, entry, fp); err != nil {
 			return err
 		}
 
@@ -212,14 +285,21 @@ func (s *Service) Debit(ctx context.Context, workspaceID, walletID string, req D
 		if err != nil {
 			return err
 		}
+		if err := emitEvent(ctx, tx, events.TypeWalletDebited, entry, out, ""); err != nil {
+			return err
+		}
 		outLedger = entry
 		outBal = out
 		return nil
 	})
+	op.end(err)
 
 	return outLedger, outBal, err
 }
 
+// adminManualCreditRef is the external reference of manual credit entries.
+const adminManualCreditRef = "admin_manual_credit"
+
 func (s *Service) AdminManualCredit(ctx context.Context, workspaceID, walletID, adminUserID, adminRole string, req AdminCreditRequest) (AdminWalletAction, WalletLedger, Balance, error) {
 	if adminUserID == "" || adminRole == "" {
 		return AdminWalletAction{}, WalletLedger{}, Balance{}, ErrInvalidArgument
@@ -233,6 +313,11 @@ func (s *Service) AdminManualCredit(ctx context.Context, workspaceID, walletID,
 	if req.AmountMinor <= 0 {
 		return AdminWalletAction{}, WalletLedger{}, Balance{}, ErrInvalidArgument
 	}
+	metadata, err := NormalizeMetadata(LedgerEntryTypeCredit, req.Metadata)
+	if err != nil {
+		return AdminWalletAction{}, WalletLedger{}, Balance{}, err
+	}
+	fp := requestFingerprint("admin_credit", amountField(req.AmountMinor), req.Currency, req.Reason, metadata)
 
 	now := s.clock().UTC()
 	actionID := uuid.NewString()
@@ -242,7 +327,8 @@ func (s *Service) AdminManualCredit(ctx context.Context, workspaceID, walletID,
 	var outLedger WalletLedger
 	var outBal Balance
 
-	err := utils.WithTx(ctx, s.db, &sql.TxOptions{}, func(ctx context.Context, tx *sql.Tx) error {
+	ctx, op := s.Metrics.track(ctx, "AdminManualCredit")
+	err = utils.WithTxRetry(moneyQuery(ctx, "AdminManualCredit"), s.db, &sql.TxOptions{}, utils.DefaultTxRetry, func(ctx context.Context, tx *sql.Tx) error {
 		w, err := lockWallet(ctx, tx, workspaceID, walletID)
 		if err != nil {
 			return err
@@ -251,8 +337,8 @@ func (s *Service) AdminManualCredit(ctx context.Context, workspaceID, walletID,
 			return ErrInvalidArgument
 		}
 
-		// Idempotency based on ledger idempotency key; admin action will be derived.
-		if existing, ok, err := findLedgerByIdempotency(ctx, tx, workspaceID, walletID, req.IdempotencyKey); err != nil {
+		// Idempotency based on the request fingerprint; admin action will be derived.
+		if existing, ok, err := replayIdempotent(ctx, tx, workspaceID, walletID, req.IdempotencyKey, fp, sameEntry(LedgerEntryTypeCredit, req.AmountMinor, adminManualCreditRef)); err != nil {
 			return err
 		} else if ok {
 			outLedger = existing
@@ -272,6 +358,10 @@ func (s *Service) AdminManualCredit(ctx context.Context, workspaceID, walletID,
 			return nil
 		}
 
+		if err := checkCanCredit(w); err != nil {
+			return err
+		}
+
 		entry := WalletLedger{
 			ID:             ledgerID,
 			WorkspaceID:    workspaceID,
@@ -279,12 +369,17 @@ func (s *Service) AdminManualCredit(ctx context.Context, workspaceID, walletID,
 			Type:           LedgerEntryTypeCredit,
 			AmountMinor:    req.AmountMinor,
 			Currency:       req.Currency,
-			ExternalRef:    "admin_manual_credit",
+			ExternalRef:    adminManualCreditRef,
 			IdempotencyKey: req.IdempotencyKey,
-			Metadata:       req.Metadata,
+			Metadata:       metadata,
 			CreatedAt:      now,
+			ContraAccount:  SystemAccountAdjustments,
 		}
-		if err := insertLedger(ctx, tx, entry); err != nil {
+		entry, err = appendLedger(ctx, tx, entry)
+		if err != nil {
+			return err
+		}
+		if err := s.recordIdempotency(ctx, tx, entry, fp); err != nil {
 			return err
 		}
 
@@ -292,6 +387,9 @@ func (s *Service) AdminManualCredit(ctx context.Context, workspaceID, walletID,
 		if err != nil {
 			return err
 		}
+		if err := emitEvent(ctx, tx, events.TypeWalletCredited, entry, b, ""); err != nil {
+			return err
+		}
 
 		action := AdminWalletAction{
 			ID:              actionID,
@@ -304,7 +402,7 @@ func (s *Service) AdminManualCredit(ctx context.Context, workspaceID, walletID,
 			AmountMinor:     req.AmountMinor,
 			Currency:        req.Currency,
 			RelatedLedgerID: entry.ID,
-			Metadata:        req.Metadata,
+			Metadata:        metadata,
 			CreatedAt:       now,
 		}
 		if err := insertAdminAction(ctx, tx, action); err != nil {
@@ -316,10 +414,21 @@ func (s *Service) AdminManualCredit(ctx context.Context, workspaceID, walletID,
 		outBal = b
 		return nil
 	})
+	op.end(err)
 
 	return outAction, outLedger, outBal, err
 }
 
+// moneyQuery and reportQuery tag database work with this module, the operation
+// and its query budget (see utils.QueryBudget).
+func moneyQuery(ctx context.Context, op string) context.Context {
+	return utils.WithQuery(ctx, "wallet", op, utils.MoneyBudget)
+}
+
+func reportQuery(ctx context.Context, op string) context.Context {
+	return utils.WithQuery(ctx, "wallet", op, utils.ReportBudget)
+}
+
 func validateMoneyReq(workspaceID, walletID string, amountMinor int64, currency, idempotencyKey string) error {
 	if workspaceID == "" || walletID == "" {
 		return ErrInvalidArgument
@@ -335,3 +444,22 @@ func validateMoneyReq(workspaceID, walletID string, amountMinor int64, currency,
 	}
 	return nil
 }
+
+// ListLedgerByMetadata returns a wallet's newest ledger entries whose metadata
+// contains every pair in f (e.g. all debits for one call_id).
+func (s *Service) ListLedgerByMetadata(ctx context.Context, workspaceID, walletID string, f MetadataFilter, limit int) ([]WalletLedger, error) {
+	if workspaceID == "" || walletID == "" || len(f) == 0 {
+		return nil, ErrInvalidArgument
+	}
+	if limit <= 0 || limit > maxMetadataResults {
+		limit = maxMetadataResults
+	}
+	containment, err := f.containment()
+	if err != nil {
+		return nil, err
+	}
+	ctx, done := utils.TrackQuery(reportQuery(ctx, "ListLedgerByMetadata"))
+	out, err := listLedgerByMetadata(ctx, s.db, workspaceID, walletID, containment, limit)
+	done(err)
+	return out, err
+}
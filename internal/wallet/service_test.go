@@ -1,6 +1,9 @@
 package wallet
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestValidateMoneyReq(t *testing.T) {
 	if err := validateMoneyReq("w", "wallet", 1, "USD", "k"); err != nil {
@@ -10,3 +13,75 @@ func TestValidateMoneyReq(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+func TestVerifyLedgerEntries_DetectsTampering(t *testing.T) {
+	var chain []WalletLedger
+	head := WalletLedger{}
+	for i, amt := range []int64{500, -200, -100} {
+		head = linkLedger(head, WalletLedger{ID: string(rune('a' + i)), WorkspaceID: "w", WalletID: "wal", AmountMinor: amt, Currency: "USD"})
+		chain = append(chain, head)
+	}
+	if res := VerifyLedgerEntries(chain); !res.Valid || res.Checked != 3 {
+		t.Fatalf("expected valid chain, got %+v", res)
+	}
+
+	edited := append([]WalletLedger(nil), chain...)
+	edited[1].AmountMinor = -1
+	if res := VerifyLedgerEntries(edited); res.Valid || res.BrokenAtSeq != 2 {
+		t.Fatalf("expected break at seq 2, got %+v", res)
+	}
+
+	deleted := []WalletLedger{chain[0], chain[2]}
+	if res := VerifyLedgerEntries(deleted); res.Valid || res.Reason != "sequence gap" {
+		t.Fatalf("expected sequence gap, got %+v", res)
+	}
+}
+
+func TestVerifyLedgerEntries_SurvivesStoredTimestampPrecision(t *testing.T) {
+	const pgTimestamptz = "2006-01-02 15:04:05.999999-07"
+	created := time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC)
+	var stored []WalletLedger
+	head := WalletLedger{}
+	for i, amt := range []int64{500, -200} {
+		head = linkLedger(head, WalletLedger{ID: string(rune('a' + i)), WorkspaceID: "w", WalletID: "wal", AmountMinor: amt, Currency: "USD", CreatedAt: created})
+		// Round-trip through timestamptz text, as Postgres returns it.
+		e := head
+		back, err := time.Parse(pgTimestamptz, e.CreatedAt.In(time.FixedZone("", 2*3600)).Format(pgTimestamptz))
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.CreatedAt = back
+		stored = append(stored, e)
+	}
+	if res := VerifyLedgerEntries(stored); !res.Valid {
+		t.Fatalf("expected chain read back from storage to verify, got %+v", res)
+	}
+}
+
+func TestCheckLedgerAnchor_DetectsTruncation(t *testing.T) {
+	var chain []WalletLedger
+	head := WalletLedger{}
+	for i, amt := range []int64{500, -200, -100} {
+		head = linkLedger(head, WalletLedger{ID: string(rune('a' + i)), WorkspaceID: "w", WalletID: "wal", AmountMinor: amt, Currency: "USD"})
+		chain = append(chain, head)
+	}
+	res := VerifyLedgerEntries(chain)
+	if res.HeadSeq != 3 || res.HeadHash != chain[2].Hash {
+		t.Fatalf("expected head at seq 3, got %+v", res)
+	}
+	anchor := LedgerAnchor{Seq: res.HeadSeq, Hash: res.HeadHash}
+
+	if res := CheckLedgerAnchor(chain, anchor); !res.Valid {
+		t.Fatalf("expected anchored chain valid, got %+v", res)
+	}
+	// Dropping the newest entry still verifies on its own but loses the anchor.
+	if res := VerifyLedgerEntries(chain[:2]); !res.Valid {
+		t.Fatalf("expected truncated chain to verify without an anchor, got %+v", res)
+	}
+	if res := CheckLedgerAnchor(chain[:2], anchor); res.Valid || res.BrokenAtSeq != 3 {
+		t.Fatalf("expected truncation detected, got %+v", res)
+	}
+	if res := CheckLedgerAnchor(chain, LedgerAnchor{Seq: 2, Hash: "bogus"}); res.Valid || res.Reason != "anchored entry hash mismatch" {
+		t.Fatalf("expected hash mismatch, got %+v", res)
+	}
+}
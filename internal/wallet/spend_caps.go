@@ -0,0 +1,163 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"telecom-platform/pkg/utils"
+)
+
+// Spend caps.
+//
+// A wallet may cap what it spends per UTC day and per UTC calendar month,
+// independent of its balance (e.g. to stop a runaway campaign draining a large
+// prepaid balance). Spend is the sum of debit entries in the period; open holds
+// count as pending spend, so a call that reserved funds cannot push a later debit
+// past the cap. A cap of 0 means no cap.
+//
+// Debit and Reserve enforce the caps inside the money transaction and fail with
+// ErrSpendCapExceeded. Capture does not: the usage already happened and its hold
+// was checked when it was placed. RoutingEngine checks the same utilization in
+// step 2 so capped wallets are rejected before a call is connected.
+
+var ErrSpendCapExceeded = errors.New("spend cap exceeded")
+
+// SpendCaps is a wallet's configured limits.
+type SpendCaps struct {
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+	WalletID    string `json:"wallet_id" db:"wallet_id"`
+
+	DailyCapMinor   int64 `json:"daily_cap_minor" db:"daily_cap_minor"`
+	MonthlyCapMinor int64 `json:"monthly_cap_minor" db:"monthly_cap_minor"`
+
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type SetSpendCapsRequest struct {
+	DailyCapMinor   int64 `json:"daily_cap_minor"`
+	MonthlyCapMinor int64 `json:"monthly_cap_minor"`
+}
+
+// SpendCapUtilization is a wallet's spend against its caps in the current periods.
+type SpendCapUtilization struct {
+	WorkspaceID string `json:"workspace_id"`
+	WalletID    string `json:"wallet_id"`
+	Currency    string `json:"currency"`
+
+	DailyCapMinor     int64 `json:"daily_cap_minor"`
+	DailySpentMinor   int64 `json:"daily_spent_minor"`
+	MonthlyCapMinor   int64 `json:"monthly_cap_minor"`
+	MonthlySpentMinor int64 `json:"monthly_spent_minor"`
+	// HeldMinor is reserved by open holds and counts against both caps.
+	HeldMinor int64 `json:"held_minor"`
+
+	DayResetsAt   time.Time `json:"day_resets_at"`
+	MonthResetsAt time.Time `json:"month_resets_at"`
+}
+
+// Check reports whether amountMinor more can be spent without breaching a cap.
+func (u SpendCapUtilization) Check(amountMinor int64) error {
+	if u.DailyCapMinor > 0 && u.DailySpentMinor+u.HeldMinor+amountMinor > u.DailyCapMinor {
+		return fmt.Errorf("%w: daily cap %d, spent %d, held %d", ErrSpendCapExceeded, u.DailyCapMinor, u.DailySpentMinor, u.HeldMinor)
+	}
+	if u.MonthlyCapMinor > 0 && u.MonthlySpentMinor+u.HeldMinor+amountMinor > u.MonthlyCapMinor {
+		return fmt.Errorf("%w: monthly cap %d, spent %d, held %d", ErrSpendCapExceeded, u.MonthlyCapMinor, u.MonthlySpentMinor, u.HeldMinor)
+	}
+	return nil
+}
+
+// SpendCapService reads cap utilization; RoutingEngine uses it in step 2.
+type SpendCapService interface {
+	SpendCapUtilization(ctx context.Context, workspaceID, walletID string) (SpendCapUtilization, error)
+}
+
+// SetSpendCaps replaces the wallet's caps. Lowering a cap below what was already
+// spent is allowed and simply blocks spending until the period resets.
+func (s *Service) SetSpendCaps(ctx context.Context, workspaceID, walletID string, req SetSpendCapsRequest) (SpendCaps, error) {
+	if workspaceID == "" || walletID == "" || req.DailyCapMinor < 0 || req.MonthlyCapMinor < 0 {
+		return SpendCaps{}, ErrInvalidArgument
+	}
+	caps := SpendCaps{
+		WorkspaceID:     workspaceID,
+		WalletID:        walletID,
+		DailyCapMinor:   req.DailyCapMinor,
+		MonthlyCapMinor: req.MonthlyCapMinor,
+		UpdatedAt:       s.clock().UTC(),
+	}
+	err := utils.WithTxRetry(moneyQuery(ctx, "SetSpendCaps"), s.db, &sql.TxOptions{}, utils.DefaultTxRetry, func(ctx context.Context, tx *sql.Tx) error {
+		w, err := lockWallet(ctx, tx, workspaceID, walletID)
+		if err != nil {
+			return err
+		}
+		if w.Status == WalletStatusClosed {
+			return ErrWalletClosed
+		}
+		return upsertSpendCaps(ctx, tx, caps)
+	})
+	if err != nil {
+		return SpendCaps{}, err
+	}
+	return caps, nil
+}
+
+// SpendCapUtilization returns the wallet's caps and spend in the current UTC day
+// and month. Wallets without caps report zero caps.
+func (s *Service) SpendCapUtilization(ctx context.Context, workspaceID, walletID string) (SpendCapUtilization, error) {
+	if workspaceID == "" || walletID == "" {
+		return SpendCapUtilization{}, ErrInvalidArgument
+	}
+	var out SpendCapUtilization
+	err := utils.WithTx(reportQuery(ctx, "SpendCapUtilization"), s.db, &sql.TxOptions{ReadOnly: true}, func(ctx context.Context, tx *sql.Tx) error {
+		b, err := getBalanceTx(ctx, tx, workspaceID, walletID)
+		if err != nil {
+			return err
+		}
+		out, err = spendCapUtilizationTx(ctx, tx, b, s.clock())
+		return err
+	})
+	return out, err
+}
+
+// checkSpendCaps is called by Debit and Reserve with the wallet and balance rows
+// already locked.
+func checkSpendCaps(ctx context.Context, tx *sql.Tx, b Balance, amountMinor int64, now time.Time) error {
+	u, err := spendCapUtilizationTx(ctx, tx, b, now)
+	if err != nil {
+		return err
+	}
+	return u.Check(amountMinor)
+}
+
+func spendCapUtilizationTx(ctx context.Context, tx *sql.Tx, b Balance, now time.Time) (SpendCapUtilization, error) {
+	caps, err := getSpendCaps(ctx, tx, b.WorkspaceID, b.WalletID)
+	if err != nil {
+		return SpendCapUtilization{}, err
+	}
+	day, month := spendPeriods(now)
+	u := SpendCapUtilization{
+		WorkspaceID:     b.WorkspaceID,
+		WalletID:        b.WalletID,
+		Currency:        b.Currency,
+		DailyCapMinor:   caps.DailyCapMinor,
+		MonthlyCapMinor: caps.MonthlyCapMinor,
+		HeldMinor:       b.HeldMinor,
+		DayResetsAt:     day.AddDate(0, 0, 1),
+		MonthResetsAt:   month.AddDate(0, 1, 0),
+	}
+	if caps.DailyCapMinor == 0 && caps.MonthlyCapMinor == 0 {
+		return u, nil
+	}
+	u.DailySpentMinor, u.MonthlySpentMinor, err = sumDebitsSince(ctx, tx, b.WorkspaceID, b.WalletID, day, month)
+	return u, err
+}
+
+// spendPeriods returns the start of now's UTC day and UTC calendar month.
+func spendPeriods(now time.Time) (day, month time.Time) {
+	now = now.UTC()
+	day = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return day, month
+}
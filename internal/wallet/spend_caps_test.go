@@ -0,0 +1,58 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSpendCapUtilization_Check(t *testing.T) {
+	cases := []struct {
+		name   string
+		u      SpendCapUtilization
+		amount int64
+		ok     bool
+	}{
+		{"no caps", SpendCapUtilization{DailySpentMinor: 1e9}, 1e9, true},
+		{"within daily cap", SpendCapUtilization{DailyCapMinor: 100, DailySpentMinor: 60}, 40, true},
+		{"past daily cap", SpendCapUtilization{DailyCapMinor: 100, DailySpentMinor: 60}, 41, false},
+		{"holds count as spend", SpendCapUtilization{DailyCapMinor: 100, DailySpentMinor: 60, HeldMinor: 30}, 11, false},
+		{"past monthly cap", SpendCapUtilization{DailyCapMinor: 100, MonthlyCapMinor: 500, MonthlySpentMinor: 480}, 21, false},
+	}
+	for _, tc := range cases {
+		err := tc.u.Check(tc.amount)
+		if tc.ok && err != nil {
+			t.Fatalf("%s: unexpected err %v", tc.name, err)
+		}
+		if !tc.ok && !errors.Is(err, ErrSpendCapExceeded) {
+			t.Fatalf("%s: expected ErrSpendCapExceeded, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestSpendPeriods_UTC(t *testing.T) {
+	ny := time.FixedZone("EST", -5*3600)
+	// 2026-03-31 22:30 EST is already April 1st in UTC.
+	day, month := spendPeriods(time.Date(2026, 3, 31, 22, 30, 0, 0, ny))
+	if want := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC); !day.Equal(want) {
+		t.Fatalf("day start: expected %v, got %v", want, day)
+	}
+	if want := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC); !month.Equal(want) {
+		t.Fatalf("month start: expected %v, got %v", want, month)
+	}
+}
+
+func TestSetSpendCaps_RejectsInvalidArgs(t *testing.T) {
+	svc := NewService((*sql.DB)(nil))
+	ctx := context.Background()
+	for _, req := range []SetSpendCapsRequest{{DailyCapMinor: -1}, {MonthlyCapMinor: -1}} {
+		if _, err := svc.SetSpendCaps(ctx, "ws", "w", req); err != ErrInvalidArgument {
+			t.Fatalf("%+v: expected ErrInvalidArgument, got %v", req, err)
+		}
+	}
+	if _, err := svc.SpendCapUtilization(ctx, "ws", ""); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without wallet, got %v", err)
+	}
+}
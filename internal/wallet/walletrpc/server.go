@@ -0,0 +1,214 @@
+// Package walletrpc serves wallet money operations to internal consumers (the
+// rating worker, the FreeSWITCH event processor) over gRPC, so they can charge
+// wallets without going through the Gin HTTP layer.
+//
+// wallet.proto is the contract. grpc-go is not a module dependency yet, so the
+// generated walletv1 stubs are not checked in; Server implements each RPC on
+// plain Go messages and the generated WalletServiceServer only converts and
+// delegates, returning Error values as statuses (Code, with Reason as ErrorInfo).
+//
+// There is no end-user token on this path: the caller is a trusted service and
+// names the workspace in each request. Expose the listener on the internal
+// network only.
+package walletrpc
+
+import (
+	"context"
+	"errors"
+
+	"telecom-platform/internal/wallet"
+	"telecom-platform/pkg/logger"
+)
+
+// Wallet is the subset of *wallet.Service the RPCs use.
+type Wallet interface {
+	GetBalance(ctx context.Context, workspaceID, walletID string) (wallet.Balance, error)
+	Credit(ctx context.Context, workspaceID, walletID string, req wallet.CreditRequest) (wallet.WalletLedger, wallet.Balance, error)
+	Debit(ctx context.Context, workspaceID, walletID string, req wallet.DebitRequest) (wallet.WalletLedger, wallet.Balance, error)
+	Reserve(ctx context.Context, workspaceID, walletID string, req wallet.HoldRequest) (wallet.WalletHold, wallet.Balance, error)
+	Capture(ctx context.Context, workspaceID, walletID, holdID string, req wallet.CaptureRequest) (wallet.WalletLedger, wallet.Balance, error)
+	Release(ctx context.Context, workspaceID, walletID, holdID string) (wallet.WalletHold, wallet.Balance, error)
+}
+
+var _ Wallet = (*wallet.Service)(nil)
+
+// Target identifies the wallet of every request.
+type Target struct {
+	WorkspaceID string
+	WalletID    string
+}
+
+type CreditRequest struct {
+	Target
+	wallet.CreditRequest
+}
+
+type DebitRequest struct {
+	Target
+	wallet.DebitRequest
+}
+
+type HoldRequest struct {
+	Target
+	wallet.HoldRequest
+}
+
+type CaptureRequest struct {
+	Target
+	HoldID string
+	wallet.CaptureRequest
+}
+
+type ReleaseRequest struct {
+	Target
+	HoldID string
+}
+
+// LedgerResult answers Credit, Debit and Capture.
+type LedgerResult struct {
+	Entry   wallet.WalletLedger
+	Balance wallet.Balance
+}
+
+// HoldResult answers Hold and Release.
+type HoldResult struct {
+	Hold    wallet.WalletHold
+	Balance wallet.Balance
+}
+
+// Server implements the WalletService RPCs.
+type Server struct {
+	Wallet Wallet
+}
+
+func (s Server) GetBalance(ctx context.Context, t Target) (wallet.Balance, error) {
+	if err := s.check(t); err != nil {
+		return wallet.Balance{}, err
+	}
+	b, err := s.Wallet.GetBalance(ctx, t.WorkspaceID, t.WalletID)
+	return b, toError(ctx, "GetBalance", err)
+}
+
+func (s Server) Credit(ctx context.Context, req CreditRequest) (LedgerResult, error) {
+	if err := s.check(req.Target); err != nil {
+		return LedgerResult{}, err
+	}
+	e, b, err := s.Wallet.Credit(ctx, req.WorkspaceID, req.WalletID, req.CreditRequest)
+	return LedgerResult{Entry: e, Balance: b}, toError(ctx, "Credit", err)
+}
+
+func (s Server) Debit(ctx context.Context, req DebitRequest) (LedgerResult, error) {
+	if err := s.check(req.Target); err != nil {
+		return LedgerResult{}, err
+	}
+	e, b, err := s.Wallet.Debit(ctx, req.WorkspaceID, req.WalletID, req.DebitRequest)
+	return LedgerResult{Entry: e, Balance: b}, toError(ctx, "Debit", err)
+}
+
+// Hold reserves funds (wallet.Service.Reserve).
+func (s Server) Hold(ctx context.Context, req HoldRequest) (HoldResult, error) {
+	if err := s.check(req.Target); err != nil {
+		return HoldResult{}, err
+	}
+	h, b, err := s.Wallet.Reserve(ctx, req.WorkspaceID, req.WalletID, req.HoldRequest)
+	return HoldResult{Hold: h, Balance: b}, toError(ctx, "Hold", err)
+}
+
+func (s Server) Capture(ctx context.Context, req CaptureRequest) (LedgerResult, error) {
+	if err := s.check(req.Target); err != nil {
+		return LedgerResult{}, err
+	}
+	if req.HoldID == "" {
+		return LedgerResult{}, &Error{Code: CodeInvalidArgument, Reason: ReasonInvalidArgument, Message: "hold_id is required"}
+	}
+	e, b, err := s.Wallet.Capture(ctx, req.WorkspaceID, req.WalletID, req.HoldID, req.CaptureRequest)
+	return LedgerResult{Entry: e, Balance: b}, toError(ctx, "Capture", err)
+}
+
+func (s Server) Release(ctx context.Context, req ReleaseRequest) (HoldResult, error) {
+	if err := s.check(req.Target); err != nil {
+		return HoldResult{}, err
+	}
+	if req.HoldID == "" {
+		return HoldResult{}, &Error{Code: CodeInvalidArgument, Reason: ReasonInvalidArgument, Message: "hold_id is required"}
+	}
+	h, b, err := s.Wallet.Release(ctx, req.WorkspaceID, req.WalletID, req.HoldID)
+	return HoldResult{Hold: h, Balance: b}, toError(ctx, "Release", err)
+}
+
+func (s Server) check(t Target) error {
+	if s.Wallet == nil {
+		return &Error{Code: CodeUnavailable, Reason: ReasonInternal, Message: "wallet service not configured"}
+	}
+	if t.WorkspaceID == "" || t.WalletID == "" {
+		return &Error{Code: CodeInvalidArgument, Reason: ReasonInvalidArgument, Message: "workspace_id and wallet_id are required"}
+	}
+	return nil
+}
+
+// Code is a gRPC status code (google.golang.org/grpc/codes values).
+type Code uint32
+
+const (
+	CodeInvalidArgument    Code = 3
+	CodeNotFound           Code = 5
+	CodeAlreadyExists      Code = 6
+	CodeFailedPrecondition Code = 9
+	CodeInternal           Code = 13
+	CodeUnavailable        Code = 14
+)
+
+// Stable error reasons consumers can branch on.
+const (
+	ReasonInvalidArgument     = "invalid_argument"
+	ReasonWalletNotFound      = "wallet_not_found"
+	ReasonHoldNotFound        = "hold_not_found"
+	ReasonWalletNotActive     = "wallet_not_active"
+	ReasonHoldNotOpen         = "hold_not_open"
+	ReasonInsufficientFunds   = "insufficient_funds"
+	ReasonSpendCapExceeded    = "spend_cap_exceeded"
+	ReasonIdempotencyConflict = "idempotency_conflict"
+	ReasonInternal            = "internal"
+)
+
+// Error is an RPC failure. Err is the wallet error, kept for errors.Is.
+type Error struct {
+	Code    Code
+	Reason  string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string { return e.Message }
+func (e *Error) Unwrap() error { return e.Err }
+
+// toError maps a wallet error to its RPC status. Unexpected errors are logged
+// and returned as Internal without details.
+func toError(ctx context.Context, method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	e := &Error{Message: err.Error(), Err: err}
+	switch {
+	case errors.Is(err, wallet.ErrHoldNotFound):
+		e.Code, e.Reason = CodeNotFound, ReasonHoldNotFound
+	case errors.Is(err, wallet.ErrNotFound):
+		e.Code, e.Reason, e.Message = CodeNotFound, ReasonWalletNotFound, "wallet not found"
+	case errors.Is(err, wallet.ErrInvalidArgument), errors.Is(err, wallet.ErrInvalidMetadata):
+		e.Code, e.Reason = CodeInvalidArgument, ReasonInvalidArgument
+	case errors.Is(err, wallet.ErrIdempotencyConflict):
+		e.Code, e.Reason = CodeAlreadyExists, ReasonIdempotencyConflict
+	case errors.Is(err, wallet.ErrWalletNotActive), errors.Is(err, wallet.ErrWalletClosed):
+		e.Code, e.Reason = CodeFailedPrecondition, ReasonWalletNotActive
+	case errors.Is(err, wallet.ErrHoldNotOpen):
+		e.Code, e.Reason = CodeFailedPrecondition, ReasonHoldNotOpen
+	case errors.Is(err, wallet.ErrInsufficientFunds):
+		e.Code, e.Reason = CodeFailedPrecondition, ReasonInsufficientFunds
+	case errors.Is(err, wallet.ErrSpendCapExceeded):
+		e.Code, e.Reason = CodeFailedPrecondition, ReasonSpendCapExceeded
+	default:
+		logger.From(ctx).Error("wallet rpc failed", "method", method, "err", err)
+		e.Code, e.Reason, e.Message = CodeInternal, ReasonInternal, "wallet operation failed"
+	}
+	return e
+}
@@ -0,0 +1,115 @@
+package walletrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"telecom-platform/internal/wallet"
+)
+
+type fakeWallet struct {
+	err      error
+	gotWS    string
+	gotHold  string
+	gotDebit wallet.DebitRequest
+}
+
+func (f *fakeWallet) GetBalance(ctx context.Context, workspaceID, walletID string) (wallet.Balance, error) {
+	f.gotWS = workspaceID
+	return wallet.Balance{WorkspaceID: workspaceID, WalletID: walletID, BalanceMinor: 500}, f.err
+}
+
+func (f *fakeWallet) Credit(ctx context.Context, workspaceID, walletID string, req wallet.CreditRequest) (wallet.WalletLedger, wallet.Balance, error) {
+	return wallet.WalletLedger{AmountMinor: req.AmountMinor}, wallet.Balance{}, f.err
+}
+
+func (f *fakeWallet) Debit(ctx context.Context, workspaceID, walletID string, req wallet.DebitRequest) (wallet.WalletLedger, wallet.Balance, error) {
+	f.gotWS, f.gotDebit = workspaceID, req
+	return wallet.WalletLedger{ID: "le-1", AmountMinor: -req.AmountMinor}, wallet.Balance{BalanceMinor: 400}, f.err
+}
+
+func (f *fakeWallet) Reserve(ctx context.Context, workspaceID, walletID string, req wallet.HoldRequest) (wallet.WalletHold, wallet.Balance, error) {
+	return wallet.WalletHold{ID: "h-1", AmountMinor: req.AmountMinor}, wallet.Balance{HeldMinor: req.AmountMinor}, f.err
+}
+
+func (f *fakeWallet) Capture(ctx context.Context, workspaceID, walletID, holdID string, req wallet.CaptureRequest) (wallet.WalletLedger, wallet.Balance, error) {
+	f.gotHold = holdID
+	return wallet.WalletLedger{}, wallet.Balance{}, f.err
+}
+
+func (f *fakeWallet) Release(ctx context.Context, workspaceID, walletID, holdID string) (wallet.WalletHold, wallet.Balance, error) {
+	f.gotHold = holdID
+	return wallet.WalletHold{ID: holdID}, wallet.Balance{}, f.err
+}
+
+func TestServer_Delegates(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeWallet{}
+	s := Server{Wallet: f}
+	target := Target{WorkspaceID: "ws", WalletID: "w"}
+
+	res, err := s.Debit(ctx, DebitRequest{Target: target, DebitRequest: wallet.DebitRequest{AmountMinor: 100, Currency: "USD", IdempotencyKey: "k"}})
+	if err != nil || res.Entry.ID != "le-1" || res.Balance.BalanceMinor != 400 {
+		t.Fatalf("unexpected debit %+v err=%v", res, err)
+	}
+	if f.gotWS != "ws" || f.gotDebit.IdempotencyKey != "k" || f.gotDebit.AmountMinor != 100 {
+		t.Fatalf("request not passed through: %+v", f)
+	}
+	hold, err := s.Hold(ctx, HoldRequest{Target: target, HoldRequest: wallet.HoldRequest{AmountMinor: 300}})
+	if err != nil || hold.Hold.ID != "h-1" || hold.Balance.HeldMinor != 300 {
+		t.Fatalf("unexpected hold %+v err=%v", hold, err)
+	}
+	if _, err := s.Capture(ctx, CaptureRequest{Target: target, HoldID: "h-1"}); err != nil || f.gotHold != "h-1" {
+		t.Fatalf("capture: err=%v hold=%q", err, f.gotHold)
+	}
+
+	for _, call := range []func() error{
+		func() error { _, err := s.GetBalance(ctx, Target{WalletID: "w"}); return err },
+		func() error { _, err := s.Capture(ctx, CaptureRequest{Target: target}); return err },
+		func() error { _, err := s.Release(ctx, ReleaseRequest{Target: target}); return err },
+	} {
+		var rpcErr *Error
+		if err := call(); !errors.As(err, &rpcErr) || rpcErr.Code != CodeInvalidArgument {
+			t.Fatalf("expected InvalidArgument, got %v", err)
+		}
+	}
+	var rpcErr *Error
+	if _, err := (Server{}).GetBalance(ctx, target); !errors.As(err, &rpcErr) || rpcErr.Code != CodeUnavailable {
+		t.Fatalf("expected Unavailable without wallet, got %v", err)
+	}
+}
+
+func TestServer_ErrorMapping(t *testing.T) {
+	cases := []struct {
+		err    error
+		code   Code
+		reason string
+	}{
+		{wallet.ErrNotFound, CodeNotFound, ReasonWalletNotFound},
+		{wallet.ErrHoldNotFound, CodeNotFound, ReasonHoldNotFound},
+		{fmt.Errorf("%w: amount must be positive", wallet.ErrInvalidArgument), CodeInvalidArgument, ReasonInvalidArgument},
+		{wallet.ErrIdempotencyConflict, CodeAlreadyExists, ReasonIdempotencyConflict},
+		{wallet.ErrWalletFrozen, CodeFailedPrecondition, ReasonWalletNotActive},
+		{wallet.ErrHoldNotOpen, CodeFailedPrecondition, ReasonHoldNotOpen},
+		{wallet.ErrCreditLimitExceeded, CodeFailedPrecondition, ReasonInsufficientFunds},
+		{wallet.ErrSpendCapExceeded, CodeFailedPrecondition, ReasonSpendCapExceeded},
+		{errors.New("pq: connection reset"), CodeInternal, ReasonInternal},
+	}
+	ctx := context.Background()
+	for _, tc := range cases {
+		s := Server{Wallet: &fakeWallet{err: tc.err}}
+		_, err := s.Debit(ctx, DebitRequest{Target: Target{WorkspaceID: "ws", WalletID: "w"}})
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != tc.code || rpcErr.Reason != tc.reason {
+			t.Fatalf("%v: got %+v", tc.err, err)
+		}
+		if tc.code != CodeInternal && !errors.Is(err, tc.err) {
+			t.Fatalf("%v: wallet error not wrapped", tc.err)
+		}
+	}
+	if _, err := (Server{Wallet: &fakeWallet{err: errors.New("secret dsn")}}).GetBalance(ctx, Target{WorkspaceID: "ws", WalletID: "w"}); err.Error() != "wallet operation failed" {
+		t.Fatalf("internal error details leaked: %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"telecom-platform/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// Wallet lifecycle.
+//
+// - CreateWallet opens an active wallet and its zero balance row in one transaction.
+// - DisableWallet stops spending (debits, new holds) but still accepts credits and
+//   lets open holds settle, so a tenant can pay down or finish in-flight calls.
+//   EnableWallet reverses it.
+// - FreezeWallet/UnfreezeWallet are admin-only and block all money movement (see
+//   freeze.go); the tenant transitions above fail on a frozen wallet.
+// - CloseWallet is terminal and requires a zero balance with no open holds; the
+//   wallet and its ledger are kept for history.
+
+var (
+	ErrWalletNotActive = errors.New("wallet is not active")
+	ErrWalletClosed    = errors.New("wallet is closed")
+	ErrWalletNotEmpty  = errors.New("wallet balance is not zero")
+)
+
+type CreateWalletRequest struct {
+	Currency string `json:"currency"`
+}
+
+// CreateWallet opens a wallet in the given ISO 4217 currency.
+func (s *Service) CreateWallet(ctx context.Context, workspaceID string, req CreateWalletRequest) (Wallet, error) {
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	if workspaceID == "" || !validCurrency(currency) {
+		return Wallet{}, ErrInvalidArgument
+	}
+	now := s.clock().UTC()
+	w := Wallet{
+		ID:          uuid.NewString(),
+		WorkspaceID: workspaceID,
+		Currency:    currency,
+		Status:      WalletStatusActive,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	err := utils.WithTxRetry(moneyQuery(ctx, "CreateWallet"), s.db, &sql.TxOptions{}, utils.DefaultTxRetry, func(ctx context.Context, tx *sql.Tx) error {
+		if err := insertWallet(ctx, tx, w); err != nil {
+			return err
+		}
+		_, err := applyBalanceDelta(ctx, tx, workspaceID, w.ID, currency, 0, now)
+		return err
+	})
+	if err != nil {
+		return Wallet{}, err
+	}
+	return w, nil
+}
+
+// ListWallets returns the workspace's wallets, oldest first (closed ones included).
+func (s *Service) ListWallets(ctx context.Context, workspaceID string) ([]Wallet, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	ctx, done := utils.TrackQuery(moneyQuery(ctx, "ListWallets"))
+	out, err := listWallets(ctx, s.db, workspaceID)
+	done(err)
+	return out, err
+}
+
+// DisableWallet blocks spending from an active wallet. Disabling twice is a no-op.
+func (s *Service) DisableWallet(ctx context.Context, workspaceID, walletID string) (Wallet, error) {
+	return s.transitionWallet(ctx, workspaceID, walletID, WalletStatusDisabled)
+}
+
+// EnableWallet re-activates a disabled wallet.
+func (s *Service) EnableWallet(ctx context.Context, workspaceID, walletID string) (Wallet, error) {
+	return s.transitionWallet(ctx, workspaceID, walletID, WalletStatusActive)
+}
+
+// CloseWallet permanently closes a wallet whose balance is zero with nothing held.
+func (s *Service) CloseWallet(ctx context.Context, workspaceID, walletID string) (Wallet, error) {
+	return s.transitionWallet(ctx, workspaceID, walletID, WalletStatusClosed)
+}
+
+func (s *Service) transitionWallet(ctx context.Context, workspaceID, walletID string, to WalletStatus) (Wallet, error) {
+	if workspaceID == "" || walletID == "" {
+		return Wallet{}, ErrInvalidArgument
+	}
+	var out Wallet
+	err := utils.WithTxRetry(moneyQuery(ctx, "transitionWallet"), s.db, &sql.TxOptions{}, utils.DefaultTxRetry, func(ctx context.Context, tx *sql.Tx) error {
+		w, err := lockWallet(ctx, tx, workspaceID, walletID)
+		if err != nil {
+			return err
+		}
+		switch w.Status {
+		case WalletStatusClosed:
+			return ErrWalletClosed
+		case WalletStatusFrozen:
+			return ErrWalletFrozen
+		}
+		if w.Status == to {
+			out = w
+			return nil
+		}
+		if to == WalletStatusClosed {
+			b, err := getBalanceForUpdate(ctx, tx, workspaceID, walletID)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return err
+			}
+			if b.BalanceMinor != 0 || b.HeldMinor != 0 {
+				return ErrWalletNotEmpty
+			}
+		}
+		w.Status = to
+		w.UpdatedAt = s.clock().UTC()
+		if err := updateWalletStatus(ctx, tx, w); err != nil {
+			return err
+		}
+		out = w
+		return nil
+	})
+	return out, err
+}
+
+// checkCanCredit rejects money in to a closed or frozen wallet.
+func checkCanCredit(w Wallet) error {
+	switch w.Status {
+	case WalletStatusClosed:
+		return ErrWalletClosed
+	case WalletStatusFrozen:
+		return ErrWalletFrozen
+	}
+	return nil
+}
+
+// checkCanSpend rejects debits and new holds unless the wallet is active.
+func checkCanSpend(w Wallet) error {
+	switch w.Status {
+	case WalletStatusActive:
+		return nil
+	case WalletStatusClosed:
+		return ErrWalletClosed
+	case WalletStatusFrozen:
+		return ErrWalletFrozen
+	default:
+		return ErrWalletNotActive
+	}
+}
+
+func validCurrency(c string) bool {
+	if len(c) != 3 {
+		return false
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func TestWalletService_Lifecycle_RejectsInvalidArgs(t *testing.T) {
+	svc := NewService((*sql.DB)(nil))
+	ctx := context.Background()
+
+	for _, cur := range []string{"", "US", "USDT", "U$D", "12A"} {
+		if _, err := svc.CreateWallet(ctx, "ws", CreateWalletRequest{Currency: cur}); err != ErrInvalidArgument {
+			t.Fatalf("currency %q: expected ErrInvalidArgument, got %v", cur, err)
+		}
+	}
+	if _, err := svc.CreateWallet(ctx, "", CreateWalletRequest{Currency: "USD"}); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without workspace, got %v", err)
+	}
+	if _, err := svc.ListWallets(ctx, ""); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without workspace, got %v", err)
+	}
+	if _, err := svc.CloseWallet(ctx, "ws", ""); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without wallet id, got %v", err)
+	}
+	if _, err := svc.DisableWallet(ctx, "", "w"); err != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument without workspace, got %v", err)
+	}
+}
+
+func TestWalletStatus_Checks(t *testing.T) {
+	cases := []struct {
+		status        WalletStatus
+		credit, spend error
+	}{
+		{WalletStatusActive, nil, nil},
+		{WalletStatusDisabled, nil, ErrWalletNotActive},
+		{WalletStatusFrozen, ErrWalletFrozen, ErrWalletFrozen},
+		{WalletStatusClosed, ErrWalletClosed, ErrWalletClosed},
+	}
+	for _, tc := range cases {
+		w := Wallet{Status: tc.status}
+		if err := checkCanCredit(w); err != tc.credit {
+			t.Fatalf("%s credit: got %v, want %v", tc.status, err, tc.credit)
+		}
+		if err := checkCanSpend(w); err != tc.spend {
+			t.Fatalf("%s spend: got %v, want %v", tc.status, err, tc.spend)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package webhooks
+
+// VerificationExamples returns receiver-side code for checking SignatureHeader,
+// served by the API so integrators do not have to reverse-engineer the scheme.
+func VerificationExamples() []VerificationExample {
+	return []VerificationExample{
+		{Language: "go", Code: goExample},
+		{Language: "node", Code: nodeExample},
+		{Language: "python", Code: pythonExample},
+	}
+}
+
+const goExample = `// verify checks X-Webhook-Signature against the raw request body.
+func verify(header string, body []byte, secret string) bool {
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(part, "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || time.Since(time.Unix(sec, 0)).Abs() > 5*time.Minute {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	want := hex.EncodeToString(mac.Sum(nil))
+	for _, sig := range sigs {
+		if hmac.Equal([]byte(sig), []byte(want)) {
+			return true
+		}
+	}
+	return false
+}`
+
+const nodeExample = `const crypto = require("crypto");
+
+// verify checks X-Webhook-Signature against the raw request body (a Buffer).
+function verify(header, rawBody, secret) {
+  const parts = header.split(",").map((p) => p.split("="));
+  const ts = (parts.find(([k]) => k === "t") || [])[1];
+  const sigs = parts.filter(([k]) => k === "v1").map(([, v]) => v);
+  if (!ts || Math.abs(Date.now() / 1000 - Number(ts)) > 300) return false;
+  const want = crypto.createHmac("sha256", secret)
+    .update(ts + ".").update(rawBody).digest("hex");
+  return sigs.some((sig) => sig.length === want.length &&
+    crypto.timingSafeEqual(Buffer.from(sig), Buffer.from(want)));
+}`
+
+const pythonExample = `import hashlib, hmac, time
+
+def verify(header: str, raw_body: bytes, secret: str) -> bool:
+    """Checks X-Webhook-Signature against the raw request body."""
+    parts = [p.split("=", 1) for p in header.split(",")]
+    ts = next((v for k, v in parts if k == "t"), None)
+    sigs = [v for k, v in parts if k == "v1"]
+    if ts is None or abs(time.time() - int(ts)) > 300:
+        return False
+    want = hmac.new(secret.encode(), ts.encode() + b"." + raw_body, hashlib.sha256).hexdigest()
+    return any(hmac.compare_digest(sig, want) for sig in sigs)`
@@ -0,0 +1,66 @@
+package webhooks
+
+import "time"
+
+type KeyStatus string
+
+const (
+	// KeyStatusActive signs every delivery. A workspace has at most one active key.
+	KeyStatusActive KeyStatus = "active"
+	// KeyStatusRetiring still signs deliveries, next to the active key, until
+	// ExpiresAt so receivers can switch secrets without dropping payloads.
+	KeyStatusRetiring KeyStatus = "retiring"
+	// KeyStatusRetired no longer signs anything.
+	KeyStatusRetired KeyStatus = "retired"
+)
+
+// SigningKey is a per-workspace webhook signing secret.
+type SigningKey struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+
+	// Secret is shown once, when the key is created; it is never listed again.
+	Secret string `json:"-" db:"secret"`
+
+	Status    KeyStatus  `json:"status" db:"status"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+}
+
+// signs reports whether k still signs deliveries at now.
+func (k SigningKey) signs(now time.Time) bool {
+	switch k.Status {
+	case KeyStatusActive:
+		return true
+	case KeyStatusRetiring:
+		return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+	default:
+		return false
+	}
+}
+
+// RevealedKey is a newly created key together with its secret.
+type RevealedKey struct {
+	SigningKey
+	Secret string `json:"secret"`
+}
+
+// TestDeliveryResult is the outcome of SendTest.
+type TestDeliveryResult struct {
+	URL       string `json:"url"`
+	Delivered bool   `json:"delivered"`
+	// Error is a generic message; receiver status codes and transport errors are
+	// only logged.
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+
+	// Signature is the header that was sent, for comparing with the receiver's computation.
+	Signature string `json:"signature"`
+	Payload   string `json:"payload"`
+}
+
+// VerificationExample shows receivers how to check SignatureHeader.
+type VerificationExample struct {
+	Language string `json:"language"`
+	Code     string `json:"code"`
+}
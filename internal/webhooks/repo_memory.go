@@ -0,0 +1,68 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// MemoryStore is a simple in-memory Store useful for tests.
+// It is not intended for production use.
+
+type MemoryStore struct {
+	mu    sync.Mutex
+	items map[string]SigningKey
+	// seq is each key's insertion order; it breaks CreatedAt ties in List.
+	seq  map[string]int
+	next int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: map[string]SigningKey{}, seq: map[string]int{}}
+}
+
+func (s *MemoryStore) Create(ctx context.Context, k SigningKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if k.Status == KeyStatusActive {
+		for _, cur := range s.items {
+			if cur.WorkspaceID == k.WorkspaceID && cur.Status == KeyStatusActive {
+				return errors.New("webhooks: workspace already has an active key")
+			}
+		}
+	}
+	s.items[k.ID] = k
+	s.next++
+	s.seq[k.ID] = s.next
+	return nil
+}
+
+func (s *MemoryStore) Update(ctx context.Context, k SigningKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur, ok := s.items[k.ID]
+	if !ok || cur.WorkspaceID != k.WorkspaceID {
+		return errors.New("webhooks: signing key not found")
+	}
+	s.items[k.ID] = k
+	return nil
+}
+
+func (s *MemoryStore) List(ctx context.Context, workspaceID string) ([]SigningKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []SigningKey
+	for _, k := range s.items {
+		if k.WorkspaceID == workspaceID {
+			out = append(out, k)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return s.seq[out[i].ID] < s.seq[out[j].ID]
+	})
+	return out, nil
+}
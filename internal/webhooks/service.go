@@ -0,0 +1,347 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"telecom-platform/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Webhook payload signing.
+//
+// Tenant-facing webhooks (job notifications, forwarded SMS) are signed with a
+// per-workspace secret. Every delivery carries
+//
+//	X-Webhook-Signature: t=<unix seconds>,v1=<hex HMAC-SHA256(secret, "<t>.<body>")>
+//
+// with one v1 per signing key. Normally only the active key signs. After Rotate the
+// previous key keeps signing for GracePeriod, so a delivery carries two v1 values
+// and a receiver still holding the old secret keeps verifying until it switches.
+// Receivers accept a payload when any v1 matches and reject stale timestamps,
+// which stops replays of captured deliveries.
+//
+// Secrets are only revealed when created. A workspace without a key gets
+// unsigned deliveries until it rotates its first key in.
+
+const SignatureHeader = "X-Webhook-Signature"
+
+// Store persists signing keys. Implementations must enforce workspace filtering.
+type Store interface {
+	Create(ctx context.Context, k SigningKey) error
+	Update(ctx context.Context, k SigningKey) error
+	List(ctx context.Context, workspaceID string) ([]SigningKey, error)
+}
+
+var (
+	ErrInvalidArgument    = errors.New("webhooks: invalid argument")
+	ErrNoSigningKey       = errors.New("webhooks: workspace has no signing key")
+	ErrInvalidSignature   = errors.New("webhooks: signature mismatch")
+	ErrSignatureExpired   = errors.New("webhooks: signature timestamp outside tolerance")
+	errMalformedSignature = fmt.Errorf("%w: malformed header", ErrInvalidSignature)
+)
+
+type Service struct {
+	store Store
+	clock func() time.Time
+
+	mu sync.Mutex
+
+	// GracePeriod is how long a rotated-out key keeps signing.
+	GracePeriod time.Duration
+
+	// Client sends test deliveries. Leave it nil in production: the default
+	// client dials through guardedDialContext, which a custom client bypasses.
+	Client *http.Client
+}
+
+func NewService(store Store) *Service {
+	return &Service{store: store, clock: time.Now, GracePeriod: 24 * time.Hour}
+}
+
+// Rotate creates a new active key and returns it with its secret. The previous
+// active key keeps signing until the grace period ends; a key still in an earlier
+// grace period is retired at once, so at most two secrets are ever valid.
+func (s *Service) Rotate(ctx context.Context, workspaceID string) (RevealedKey, error) {
+	if workspaceID == "" {
+		return RevealedKey{}, ErrInvalidArgument
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock().UTC()
+	keys, err := s.store.List(ctx, workspaceID)
+	if err != nil {
+		return RevealedKey{}, err
+	}
+	for _, k := range keys {
+		switch k.Status {
+		case KeyStatusActive:
+			k.Status = KeyStatusRetiring
+			expires := now.Add(s.GracePeriod)
+			k.ExpiresAt = &expires
+			if s.GracePeriod <= 0 {
+				k.Status = KeyStatusRetired
+				k.ExpiresAt = &now
+			}
+		case KeyStatusRetiring:
+			k.Status = KeyStatusRetired
+			if k.ExpiresAt == nil || k.ExpiresAt.After(now) {
+				k.ExpiresAt = &now
+			}
+		default:
+			continue
+		}
+		if err := s.store.Update(ctx, k); err != nil {
+			return RevealedKey{}, err
+		}
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return RevealedKey{}, err
+	}
+	k := SigningKey{
+		ID:          uuid.NewString(),
+		WorkspaceID: workspaceID,
+		Secret:      secret,
+		Status:      KeyStatusActive,
+		CreatedAt:   now,
+	}
+	if err := s.store.Create(ctx, k); err != nil {
+		return RevealedKey{}, err
+	}
+	return RevealedKey{SigningKey: k, Secret: secret}, nil
+}
+
+// Keys lists the workspace's signing keys without secrets. A retiring key past
+// its grace period is reported as retired.
+func (s *Service) Keys(ctx context.Context, workspaceID string) ([]SigningKey, error) {
+	if workspaceID == "" {
+		return nil, ErrInvalidArgument
+	}
+	keys, err := s.store.List(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.clock().UTC()
+	for i := range keys {
+		if keys[i].Status == KeyStatusRetiring && !keys[i].signs(now) {
+			keys[i].Status = KeyStatusRetired
+		}
+	}
+	return keys, nil
+}
+
+// Sign returns the SignatureHeader value for body, or "" when the workspace has
+// no signing key yet.
+func (s *Service) Sign(ctx context.Context, workspaceID string, body []byte) (string, error) {
+	if workspaceID == "" {
+		return "", ErrInvalidArgument
+	}
+	keys, err := s.store.List(ctx, workspaceID)
+	if err != nil {
+		return "", err
+	}
+	now := s.clock().UTC()
+	var secrets []string
+	for _, k := range keys {
+		if !k.signs(now) {
+			continue
+		}
+		// The active key's signature goes first.
+		if k.Status == KeyStatusActive {
+			secrets = append([]string{k.Secret}, secrets...)
+		} else {
+			secrets = append(secrets, k.Secret)
+		}
+	}
+	if len(secrets) == 0 {
+		return "", nil
+	}
+	return signatureHeader(now.Unix(), body, secrets), nil
+}
+
+// SendTest POSTs a signed webhook.test event to rawURL so a receiver can check its
+// verification code. A failed delivery is reported in the result, not as an error.
+func (s *Service) SendTest(ctx context.Context, workspaceID, rawURL string) (TestDeliveryResult, error) {
+	if workspaceID == "" {
+		return TestDeliveryResult{}, ErrInvalidArgument
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return TestDeliveryResult{}, fmt.Errorf("%w: url must be an https url", ErrInvalidArgument)
+	}
+	now := s.clock().UTC()
+	eventID := uuid.NewString()
+	body, err := json.Marshal(map[string]any{
+		"id":           eventID,
+		"type":         "webhook.test",
+		"workspace_id": workspaceID,
+		"created_at":   now,
+	})
+	if err != nil {
+		return TestDeliveryResult{}, err
+	}
+	sig, err := s.Sign(ctx, workspaceID, body)
+	if err != nil {
+		return TestDeliveryResult{}, err
+	}
+	if sig == "" {
+		return TestDeliveryResult{}, ErrNoSigningKey
+	}
+
+	out := TestDeliveryResult{URL: rawURL, Signature: sig, Payload: string(body)}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return TestDeliveryResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-ID", eventID)
+	req.Header.Set(SignatureHeader, sig)
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second, Transport: &http.Transport{DialContext: guardedDialContext}}
+	}
+	started := time.Now()
+	resp, err := client.Do(req)
+	out.DurationMS = time.Since(started).Milliseconds()
+	if err != nil {
+		// The receiver's status and transport errors stay in our logs: echoing
+		// them would let the caller probe hosts and ports through us.
+		logger.From(ctx).Info("webhook test delivery failed", "workspace_id", workspaceID, "err", err)
+		out.Error = testDeliveryFailed
+		return out, nil
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	out.Delivered = resp.StatusCode/100 == 2
+	if !out.Delivered {
+		logger.From(ctx).Info("webhook test delivery rejected", "workspace_id", workspaceID, "status", resp.StatusCode)
+		out.Error = testDeliveryFailed
+	}
+	return out, nil
+}
+
+// testDeliveryFailed is the only failure detail SendTest reports.
+const testDeliveryFailed = "delivery failed: the receiver did not accept the test event"
+
+var errForbiddenAddress = errors.New("webhooks: destination address not allowed")
+
+// guardedDialContext resolves addr and dials the first allowed address itself,
+// so a hostname cannot pass the check and then resolve somewhere internal.
+// Loopback, private, link-local (including cloud metadata), multicast and
+// unspecified addresses are refused.
+func guardedDialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	d := net.Dialer{Timeout: 5 * time.Second}
+	for _, ip := range ips {
+		if !publicIP(ip.IP) {
+			return nil, fmt.Errorf("%w: %s resolves to %s", errForbiddenAddress, host, ip.IP)
+		}
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("%w: %s does not resolve", errForbiddenAddress, host)
+	}
+	return d.DialContext(ctx, network, net.JoinHostPort(ips[0].IP.String(), port))
+}
+
+// carrierNAT is the shared address space (RFC 6598), internal on most networks.
+var carrierNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// publicIP reports whether ip is a globally routable unicast address.
+func publicIP(ip net.IP) bool {
+	switch {
+	case ip.IsLoopback(), ip.IsPrivate(), ip.IsUnspecified(),
+		ip.IsLinkLocalUnicast(), ip.IsLinkLocalMulticast(), ip.IsInterfaceLocalMulticast(), ip.IsMulticast():
+		return false
+	case carrierNAT.Contains(ip):
+		return false
+	}
+	return true
+}
+
+// Verify checks a SignatureHeader value against secret, as a receiver would.
+// Timestamps further than tolerance from now are rejected.
+func Verify(header string, body []byte, secret string, tolerance time.Duration, now time.Time) error {
+	var ts int64
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return errMalformedSignature
+		}
+		switch k {
+		case "t":
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return errMalformedSignature
+			}
+			ts = n
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	if ts == 0 || len(sigs) == 0 {
+		return errMalformedSignature
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > tolerance || d < -tolerance {
+		return ErrSignatureExpired
+	}
+	want := []byte(sign(ts, body, secret))
+	for _, sig := range sigs {
+		if hmac.Equal([]byte(sig), want) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func signatureHeader(ts int64, body []byte, secrets []string) string {
+	var b strings.Builder
+	b.WriteString("t=" + strconv.FormatInt(ts, 10))
+	for _, secret := range secrets {
+		b.WriteString(",v1=" + sign(ts, body, secret))
+	}
+	return b.String()
+}
+
+func sign(ts int64, body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(ts, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,152 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestService_RotationKeepsOldSecretDuringGrace(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewService(NewMemoryStore())
+	svc.clock = func() time.Time { return now }
+
+	if sig, err := svc.Sign(ctx, "w1", []byte("{}")); err != nil || sig != "" {
+		t.Fatalf("expected unsigned delivery without a key, got %q %v", sig, err)
+	}
+
+	first, err := svc.Rotate(ctx, "w1")
+	if err != nil || !strings.HasPrefix(first.Secret, "whsec_") {
+		t.Fatalf("rotate: %+v %v", first, err)
+	}
+	second, err := svc.Rotate(ctx, "w1")
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+
+	body := []byte(`{"id":"evt"}`)
+	sig, err := svc.Sign(ctx, "w1", body)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	for _, secret := range []string{first.Secret, second.Secret} {
+		if err := Verify(sig, body, secret, 5*time.Minute, now); err != nil {
+			t.Fatalf("expected both secrets to verify during grace, got %v", err)
+		}
+	}
+
+	now = now.Add(svc.GracePeriod + time.Second)
+	sig, _ = svc.Sign(ctx, "w1", body)
+	if err := Verify(sig, body, first.Secret, 5*time.Minute, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected old secret to stop verifying after grace, got %v", err)
+	}
+	if err := Verify(sig, body, second.Secret, 5*time.Minute, now); err != nil {
+		t.Fatalf("expected new secret to verify, got %v", err)
+	}
+
+	keys, _ := svc.Keys(ctx, "w1")
+	if len(keys) != 2 || keys[0].Status != KeyStatusRetired || keys[1].Status != KeyStatusActive {
+		t.Fatalf("unexpected key states: %+v", keys)
+	}
+	if other, _ := svc.Sign(ctx, "w2", body); other != "" {
+		t.Fatalf("expected other workspace to have no key")
+	}
+}
+
+func TestService_RotateTwiceRetiresOldestImmediately(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore())
+	a, _ := svc.Rotate(ctx, "w1")
+	_, _ = svc.Rotate(ctx, "w1")
+	_, _ = svc.Rotate(ctx, "w1")
+
+	body := []byte("x")
+	sig, _ := svc.Sign(ctx, "w1", body)
+	if n := strings.Count(sig, "v1="); n != 2 {
+		t.Fatalf("expected at most two signatures, got %d in %q", n, sig)
+	}
+	if err := Verify(sig, body, a.Secret, time.Minute, time.Now()); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected first secret retired, got %v", err)
+	}
+}
+
+func TestVerify_RejectsTamperingAndStaleTimestamps(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"a":1}`)
+	header := signatureHeader(now.Unix(), body, []string{"s"})
+
+	if err := Verify(header, []byte(`{"a":2}`), "s", time.Minute, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected mismatch for modified body, got %v", err)
+	}
+	if err := Verify(header, body, "s", time.Minute, now.Add(2*time.Minute)); !errors.Is(err, ErrSignatureExpired) {
+		t.Fatalf("expected stale timestamp to fail, got %v", err)
+	}
+	if err := Verify("garbage", body, "s", time.Minute, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected malformed header to fail, got %v", err)
+	}
+}
+
+func TestService_SendTestDeliversSignedEvent(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore())
+
+	if _, err := svc.SendTest(ctx, "w1", "https://example.com/hook"); !errors.Is(err, ErrNoSigningKey) {
+		t.Fatalf("expected ErrNoSigningKey, got %v", err)
+	}
+	key, _ := svc.Rotate(ctx, "w1")
+
+	var verifyErr error
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verifyErr = Verify(r.Header.Get(SignatureHeader), body, key.Secret, time.Minute, time.Now())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	svc.Client = srv.Client()
+
+	res, err := svc.SendTest(ctx, "w1", srv.URL)
+	if err != nil || !res.Delivered || res.Error != "" {
+		t.Fatalf("send test: %+v %v", res, err)
+	}
+	if verifyErr != nil {
+		t.Fatalf("receiver could not verify: %v", verifyErr)
+	}
+	if _, err := svc.SendTest(ctx, "w1", "http://example.com/hook"); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected plain http to be rejected, got %v", err)
+	}
+}
+
+func TestService_SendTestRefusesInternalAddresses(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore())
+	_, _ = svc.Rotate(ctx, "w1")
+
+	hit := false
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hit = true }))
+	defer srv.Close()
+
+	// The default client refuses the loopback receiver and says nothing specific.
+	res, err := svc.SendTest(ctx, "w1", srv.URL)
+	if err != nil || res.Delivered || res.Error != testDeliveryFailed || hit {
+		t.Fatalf("expected generic failure without delivery, got %+v err=%v hit=%v", res, err, hit)
+	}
+	if _, err := guardedDialContext(ctx, "tcp", "localhost:443"); !errors.Is(err, errForbiddenAddress) {
+		t.Fatalf("expected localhost refused, got %v", err)
+	}
+
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fe80::1", "fd00:ec2::254"} {
+		if publicIP(net.ParseIP(ip)) {
+			t.Fatalf("expected %s refused", ip)
+		}
+	}
+	if !publicIP(net.ParseIP("93.184.216.34")) || !publicIP(net.ParseIP("2606:2800:220:1::")) {
+		t.Fatalf("expected public addresses allowed")
+	}
+}
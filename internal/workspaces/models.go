@@ -0,0 +1,87 @@
+package workspaces
+
+import "time"
+
+// TerminationState is the stage of a workspace deletion.
+type TerminationState string
+
+const (
+	// StateSuspended: the workspace is read-only/blocked and can still be restored.
+	StateSuspended TerminationState = "suspended"
+	// StatePurging: the grace period is over; purge steps are running.
+	StatePurging TerminationState = "purging"
+	// StateCompleted: every purge step finished; Report is final.
+	StateCompleted TerminationState = "completed"
+	// StateCanceled: the owner restored the workspace during the grace period.
+	StateCanceled TerminationState = "canceled"
+)
+
+// Standard purge step names, in the order they should be configured.
+// Numbers are released first so they stop receiving calls; recordings are deleted
+// before call rows are anonymized so their URLs can still be resolved.
+const (
+	StepReleaseNumbers   = "release_numbers"
+	StepDeleteRecordings = "delete_recordings"
+	StepAnonymizeCalls   = "anonymize_calls"
+)
+
+// Termination tracks one workspace deletion request.
+type Termination struct {
+	ID          string `json:"id" db:"id"`
+	WorkspaceID string `json:"workspace_id" db:"workspace_id"`
+
+	State TerminationState `json:"state" db:"state"`
+
+	RequestedByUserID string `json:"requested_by_user_id" db:"requested_by_user_id"`
+	RequestedByRole   string `json:"requested_by_role" db:"requested_by_role"`
+	Reason            string `json:"reason,omitempty" db:"reason"`
+
+	RequestedAt time.Time `json:"requested_at" db:"requested_at"`
+	// PurgeAfter is the end of the grace period.
+	PurgeAfter time.Time `json:"purge_after" db:"purge_after"`
+
+	// Steps holds per-step progress so the purge resumes where it stopped.
+	// Store as JSONB in Postgres.
+	Steps []StepProgress `json:"steps" db:"steps"`
+
+	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	Report      *Report    `json:"report,omitempty" db:"report"`
+
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// StepProgress records one purge step.
+type StepProgress struct {
+	Name      string     `json:"name"`
+	Done      bool       `json:"done"`
+	Attempts  int        `json:"attempts"`
+	LastError string     `json:"last_error,omitempty"`
+	Affected  int        `json:"affected"`
+	Detail    string     `json:"detail,omitempty"`
+	DoneAt    *time.Time `json:"done_at,omitempty"`
+}
+
+// StepResult is what a purge step reports back.
+type StepResult struct {
+	// Affected is the number of records/resources handled (numbers released, files deleted, ...).
+	Affected int
+	Detail   string
+}
+
+// RetentionPolicy describes data that survives the purge for legal/financial reasons.
+type RetentionPolicy struct {
+	// LedgerRetention keeps wallet ledger rows (money invariant: never deleted early).
+	LedgerRetention time.Duration `json:"ledger_retention"`
+	// AuditRetention keeps audit events.
+	AuditRetention time.Duration `json:"audit_retention"`
+}
+
+// Report is the final completion report.
+type Report struct {
+	WorkspaceID string         `json:"workspace_id"`
+	CompletedAt time.Time      `json:"completed_at"`
+	Steps       []StepProgress `json:"steps"`
+
+	LedgerRetainedUntil time.Time `json:"ledger_retained_until"`
+	AuditRetainedUntil  time.Time `json:"audit_retained_until"`
+}
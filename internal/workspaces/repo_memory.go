@@ -0,0 +1,67 @@
+package workspaces
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryRepo is a simple in-memory Repository useful for tests.
+// It is not intended for production use.
+
+type MemoryRepo struct {
+	mu    sync.Mutex
+	items map[string]Termination
+}
+
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{items: map[string]Termination{}}
+}
+
+func cloneTermination(t Termination) Termination {
+	t.Steps = append([]StepProgress(nil), t.Steps...)
+	return t
+}
+
+func (r *MemoryRepo) Create(ctx context.Context, t Termination) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items[t.ID] = cloneTermination(t)
+	return nil
+}
+
+func (r *MemoryRepo) Update(ctx context.Context, t Termination) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cur, ok := r.items[t.ID]
+	if !ok || cur.WorkspaceID != t.WorkspaceID {
+		return ErrNotFound
+	}
+	r.items[t.ID] = cloneTermination(t)
+	return nil
+}
+
+func (r *MemoryRepo) GetOpen(ctx context.Context, workspaceID string) (Termination, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.items {
+		if t.WorkspaceID == workspaceID && t.State != StateCanceled {
+			return cloneTermination(t), true, nil
+		}
+	}
+	return Termination{}, false, nil
+}
+
+func (r *MemoryRepo) ListDue(ctx context.Context, now time.Time) ([]Termination, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Termination
+	for _, t := range r.items {
+		if (t.State == StateSuspended && !now.Before(t.PurgeAfter)) || t.State == StatePurging {
+			out = append(out, cloneTermination(t))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].RequestedAt.Before(out[j].RequestedAt) })
+	return out, nil
+}
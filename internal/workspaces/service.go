@@ -0,0 +1,283 @@
+package workspaces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"telecom-platform/internal/audit"
+	"telecom-platform/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Workspace termination (staged purge).
+//
+// Flow:
+// - RequestTermination suspends the workspace and starts a grace period.
+// - During the grace period CancelTermination restores it.
+// - After the grace period the worker calls RunOnce, which runs the purge steps in order
+//   (release numbers, delete recordings, anonymize calls, ...). Steps must be idempotent;
+//   progress is stored per step so a crash or failure resumes at the first unfinished step.
+// - While the workspace has data under legal hold the purge does not start (or
+//   continue); the termination stays due and is retried once the holds are released.
+// - Wallet ledger and audit events are never purged here; they are retained per
+//   RetentionPolicy and the completion report records until when.
+
+// PurgeStep is one idempotent purge action.
+type PurgeStep struct {
+	Name string
+	Run  func(ctx context.Context, workspaceID string) (StepResult, error)
+}
+
+// Suspender blocks/unblocks a workspace (login, API, calls).
+type Suspender interface {
+	SetSuspended(ctx context.Context, workspaceID string, suspended bool) error
+}
+
+// LegalHoldCounter reports how many legal holds are active in a workspace.
+type LegalHoldCounter interface {
+	CountActiveLegalHolds(ctx context.Context, workspaceID string) (int, error)
+}
+
+// Repository persists terminations. Implementations must enforce workspace filtering.
+type Repository interface {
+	Create(ctx context.Context, t Termination) error
+	Update(ctx context.Context, t Termination) error
+	// GetOpen returns the workspace's termination that is not canceled (if any).
+	GetOpen(ctx context.Context, workspaceID string) (Termination, bool, error)
+	// ListDue returns suspended terminations past PurgeAfter and unfinished purges.
+	ListDue(ctx context.Context, now time.Time) ([]Termination, error)
+}
+
+var (
+	ErrInvalidArgument  = errors.New("workspaces: invalid argument")
+	ErrNotFound         = errors.New("workspaces: termination not found")
+	ErrAlreadyRequested = errors.New("workspaces: termination already requested")
+	ErrGracePeriodOver  = errors.New("workspaces: grace period is over")
+	ErrLegalHold        = errors.New("workspaces: data is under legal hold")
+)
+
+type Service struct {
+	repo      Repository
+	suspender Suspender
+	audit     *audit.Service
+	steps     []PurgeStep
+	clock     func() time.Time
+
+	// GracePeriod between suspension and purge (default 30 days).
+	GracePeriod time.Duration
+	Retention   RetentionPolicy
+
+	// Holds is optional; when set, purging waits until no legal hold is active.
+	Holds LegalHoldCounter
+}
+
+func NewService(repo Repository, suspender Suspender, auditSvc *audit.Service, steps []PurgeStep) *Service {
+	return &Service{
+		repo:        repo,
+		suspender:   suspender,
+		audit:       auditSvc,
+		steps:       steps,
+		clock:       time.Now,
+		GracePeriod: 30 * 24 * time.Hour,
+		Retention:   RetentionPolicy{LedgerRetention: 7 * 365 * 24 * time.Hour, AuditRetention: 7 * 365 * 24 * time.Hour},
+	}
+}
+
+// RequestTermination suspends the workspace and schedules the purge.
+func (s *Service) RequestTermination(ctx context.Context, workspaceID, actorUserID, actorRole, reason string) (Termination, error) {
+	if workspaceID == "" || actorUserID == "" {
+		return Termination{}, ErrInvalidArgument
+	}
+	if _, ok, err := s.repo.GetOpen(ctx, workspaceID); err != nil {
+		return Termination{}, err
+	} else if ok {
+		return Termination{}, ErrAlreadyRequested
+	}
+
+	now := s.clock().UTC()
+	t := Termination{
+		ID:                uuid.NewString(),
+		WorkspaceID:       workspaceID,
+		State:             StateSuspended,
+		RequestedByUserID: actorUserID,
+		RequestedByRole:   actorRole,
+		Reason:            reason,
+		RequestedAt:       now,
+		PurgeAfter:        now.Add(s.GracePeriod),
+		UpdatedAt:         now,
+	}
+	for _, st := range s.steps {
+		t.Steps = append(t.Steps, StepProgress{Name: st.Name})
+	}
+	if s.suspender != nil {
+		if err := s.suspender.SetSuspended(ctx, workspaceID, true); err != nil {
+			return Termination{}, err
+		}
+	}
+	if err := s.repo.Create(ctx, t); err != nil {
+		return Termination{}, err
+	}
+	s.log(ctx, t, actorUserID, actorRole, "workspace termination requested; purge after "+t.PurgeAfter.Format(time.RFC3339))
+	return t, nil
+}
+
+// CancelTermination restores a suspended workspace during the grace period.
+func (s *Service) CancelTermination(ctx context.Context, workspaceID, actorUserID, actorRole string) (Termination, error) {
+	t, ok, err := s.repo.GetOpen(ctx, workspaceID)
+	if err != nil {
+		return Termination{}, err
+	}
+	if !ok {
+		return Termination{}, ErrNotFound
+	}
+	if t.State != StateSuspended {
+		return Termination{}, ErrGracePeriodOver
+	}
+	if s.suspender != nil {
+		if err := s.suspender.SetSuspended(ctx, workspaceID, false); err != nil {
+			return Termination{}, err
+		}
+	}
+	t.State = StateCanceled
+	t.UpdatedAt = s.clock().UTC()
+	if err := s.repo.Update(ctx, t); err != nil {
+		return Termination{}, err
+	}
+	s.log(ctx, t, actorUserID, actorRole, "workspace termination canceled")
+	return t, nil
+}
+
+// Status returns the workspace's open (or completed) termination.
+func (s *Service) Status(ctx context.Context, workspaceID string) (Termination, error) {
+	if workspaceID == "" {
+		return Termination{}, ErrInvalidArgument
+	}
+	t, ok, err := s.repo.GetOpen(ctx, workspaceID)
+	if err != nil {
+		return Termination{}, err
+	}
+	if !ok {
+		return Termination{}, ErrNotFound
+	}
+	return t, nil
+}
+
+// RunOnce advances every due termination. It returns the number completed in this run.
+func (s *Service) RunOnce(ctx context.Context) (int, error) {
+	now := s.clock().UTC()
+	due, err := s.repo.ListDue(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	completed := 0
+	for _, t := range due {
+		done, err := s.advance(ctx, t)
+		if err != nil {
+			logger.From(ctx).Error("workspace purge step failed", "workspace_id", t.WorkspaceID, "err", err)
+			continue
+		}
+		if done {
+			completed++
+		}
+	}
+	return completed, nil
+}
+
+func (s *Service) advance(ctx context.Context, t Termination) (bool, error) {
+	if s.Holds != nil {
+		n, err := s.Holds.CountActiveLegalHolds(ctx, t.WorkspaceID)
+		if err != nil {
+			return false, err
+		}
+		if n > 0 {
+			return false, fmt.Errorf("%w (%d active)", ErrLegalHold, n)
+		}
+	}
+	if t.State == StateSuspended {
+		t.State = StatePurging
+		t.UpdatedAt = s.clock().UTC()
+		if err := s.repo.Update(ctx, t); err != nil {
+			return false, err
+		}
+		s.log(ctx, t, "system", "", "workspace purge started")
+	}
+
+	for i := range t.Steps {
+		if t.Steps[i].Done {
+			continue
+		}
+		step, ok := s.step(t.Steps[i].Name)
+		if !ok {
+			return false, fmt.Errorf("workspaces: unknown purge step %q", t.Steps[i].Name)
+		}
+		t.Steps[i].Attempts++
+		res, err := step.Run(ctx, t.WorkspaceID)
+		now := s.clock().UTC()
+		t.UpdatedAt = now
+		if err != nil {
+			t.Steps[i].LastError = err.Error()
+			_ = s.repo.Update(ctx, t)
+			return false, err
+		}
+		t.Steps[i].Done = true
+		t.Steps[i].LastError = ""
+		t.Steps[i].Affected = res.Affected
+		t.Steps[i].Detail = res.Detail
+		t.Steps[i].DoneAt = &now
+		if err := s.repo.Update(ctx, t); err != nil {
+			return false, err
+		}
+	}
+
+	now := s.clock().UTC()
+	t.State = StateCompleted
+	t.CompletedAt = &now
+	t.UpdatedAt = now
+	t.Report = &Report{
+		WorkspaceID:         t.WorkspaceID,
+		CompletedAt:         now,
+		Steps:               t.Steps,
+		LedgerRetainedUntil: now.Add(s.Retention.LedgerRetention),
+		AuditRetainedUntil:  now.Add(s.Retention.AuditRetention),
+	}
+	if err := s.repo.Update(ctx, t); err != nil {
+		return false, err
+	}
+	s.log(ctx, t, "system", "", "workspace purge completed")
+	return true, nil
+}
+
+func (s *Service) step(name string) (PurgeStep, bool) {
+	for _, st := range s.steps {
+		if st.Name == name {
+			return st, true
+		}
+	}
+	return PurgeStep{}, false
+}
+
+// Run advances terminations on every tick until ctx is canceled.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.RunOnce(ctx); err != nil {
+				logger.From(ctx).Error("workspace purge run failed", "err", err)
+			}
+		}
+	}
+}
+
+func (s *Service) log(ctx context.Context, t Termination, actorUserID, actorRole, message string) {
+	if s.audit == nil {
+		return
+	}
+	_ = s.audit.LogAdminAction(ctx, t.WorkspaceID, actorUserID, actorRole, "", message, "", fmt.Sprintf(`{"termination_id":%q,"state":%q}`, t.ID, t.State))
+}
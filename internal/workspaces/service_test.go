@@ -0,0 +1,133 @@
+package workspaces
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type stubSuspender map[string]bool
+
+func (s stubSuspender) SetSuspended(ctx context.Context, workspaceID string, suspended bool) error {
+	s[workspaceID] = suspended
+	return nil
+}
+
+func TestTermination_GracePeriodThenResumablePurge(t *testing.T) {
+	var calls []string
+	failOnce := true
+	steps := []PurgeStep{
+		{Name: "release_numbers", Run: func(ctx context.Context, ws string) (StepResult, error) {
+			calls = append(calls, "release_numbers")
+			return StepResult{Affected: 3}, nil
+		}},
+		{Name: "delete_recordings", Run: func(ctx context.Context, ws string) (StepResult, error) {
+			calls = append(calls, "delete_recordings")
+			if failOnce {
+				failOnce = false
+				return StepResult{}, errors.New("storage unavailable")
+			}
+			return StepResult{Affected: 10}, nil
+		}},
+		{Name: "anonymize_calls", Run: func(ctx context.Context, ws string) (StepResult, error) {
+			calls = append(calls, "anonymize_calls")
+			return StepResult{Affected: 42}, nil
+		}},
+	}
+	susp := stubSuspender{}
+	svc := NewService(NewMemoryRepo(), susp, nil, steps)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc.clock = func() time.Time { return now }
+
+	if _, err := svc.RequestTermination(context.Background(), "w", "u", "owner", "closing"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !susp["w"] {
+		t.Fatalf("expected workspace suspended")
+	}
+	if _, err := svc.RequestTermination(context.Background(), "w", "u", "owner", ""); err != ErrAlreadyRequested {
+		t.Fatalf("expected ErrAlreadyRequested, got %v", err)
+	}
+
+	// Still in grace period: nothing runs.
+	if n, _ := svc.RunOnce(context.Background()); n != 0 || len(calls) != 0 {
+		t.Fatalf("expected no purge during grace period")
+	}
+
+	now = now.Add(svc.GracePeriod)
+	if n, _ := svc.RunOnce(context.Background()); n != 0 {
+		t.Fatalf("expected failed run not to complete")
+	}
+	st, _ := svc.Status(context.Background(), "w")
+	if st.State != StatePurging || !st.Steps[0].Done || st.Steps[1].LastError == "" {
+		t.Fatalf("unexpected progress: %+v", st)
+	}
+	if _, err := svc.CancelTermination(context.Background(), "w", "u", "owner"); err != ErrGracePeriodOver {
+		t.Fatalf("expected ErrGracePeriodOver, got %v", err)
+	}
+
+	if n, _ := svc.RunOnce(context.Background()); n != 1 {
+		t.Fatalf("expected completion on resume")
+	}
+	// release_numbers must not be re-run on resume.
+	want := []string{"release_numbers", "delete_recordings", "delete_recordings", "anonymize_calls"}
+	if len(calls) != len(want) {
+		t.Fatalf("unexpected step calls: %v", calls)
+	}
+	st, _ = svc.Status(context.Background(), "w")
+	if st.State != StateCompleted || st.Report == nil || st.Report.Steps[2].Affected != 42 || st.Steps[1].Attempts != 2 {
+		t.Fatalf("unexpected final state: %+v", st)
+	}
+	if !st.Report.LedgerRetainedUntil.After(now) {
+		t.Fatalf("expected ledger retention in report")
+	}
+}
+
+func TestTermination_CancelDuringGraceRestores(t *testing.T) {
+	susp := stubSuspender{}
+	svc := NewService(NewMemoryRepo(), susp, nil, nil)
+	if _, err := svc.RequestTermination(context.Background(), "w", "u", "owner", ""); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	out, err := svc.CancelTermination(context.Background(), "w", "u", "owner")
+	if err != nil || out.State != StateCanceled || susp["w"] {
+		t.Fatalf("expected restore, got %+v err=%v", out, err)
+	}
+}
+
+type stubHolds map[string]int
+
+func (h stubHolds) CountActiveLegalHolds(ctx context.Context, workspaceID string) (int, error) {
+	return h[workspaceID], nil
+}
+
+func TestTermination_LegalHoldBlocksPurge(t *testing.T) {
+	ran := false
+	steps := []PurgeStep{{Name: "delete_recordings", Run: func(ctx context.Context, ws string) (StepResult, error) {
+		ran = true
+		return StepResult{}, nil
+	}}}
+	holds := stubHolds{"w": 1}
+	svc := NewService(NewMemoryRepo(), nil, nil, steps)
+	svc.Holds = holds
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc.clock = func() time.Time { return now }
+
+	if _, err := svc.RequestTermination(context.Background(), "w", "u", "owner", ""); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	now = now.Add(svc.GracePeriod)
+	if n, _ := svc.RunOnce(context.Background()); n != 0 || ran {
+		t.Fatalf("purge must not run while a legal hold is active")
+	}
+	st, _ := svc.Status(context.Background(), "w")
+	if st.State != StateSuspended {
+		t.Fatalf("expected termination to stay suspended, got %s", st.State)
+	}
+
+	holds["w"] = 0
+	if n, _ := svc.RunOnce(context.Background()); n != 1 || !ran {
+		t.Fatalf("expected purge once the hold is released")
+	}
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// ClientIPResolver determines the real caller IP behind trusted proxies.
+//
+// X-Forwarded-For is walked right to left starting from the socket peer: each hop
+// is only believed if the hop that appended it is a trusted proxy. The first
+// untrusted address is the client. Without trusted proxies the header is ignored,
+// so a caller cannot spoof its audit/rate-limit IP by sending the header itself.
+type ClientIPResolver struct {
+	trusted []netip.Prefix
+}
+
+// NewClientIPResolver parses proxies as CIDRs or bare IPs.
+func NewClientIPResolver(proxies []string) (*ClientIPResolver, error) {
+	trusted, err := parsePrefixes(proxies)
+	if err != nil {
+		return nil, fmt.Errorf("clientip: invalid trusted proxy %w", err)
+	}
+	return &ClientIPResolver{trusted: trusted}, nil
+}
+
+// parsePrefixes parses CIDRs or bare IPs (as single-address prefixes), skipping
+// blank entries. The error names the offending entry.
+func parsePrefixes(list []string) ([]netip.Prefix, error) {
+	var out []netip.Prefix
+	for _, p := range list {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if pfx, err := netip.ParsePrefix(p); err == nil {
+			out = append(out, pfx.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(p)
+		if err != nil {
+			return nil, fmt.Errorf("%q", p)
+		}
+		addr = addr.Unmap()
+		out = append(out, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return out, nil
+}
+
+// Trusted reports whether ip belongs to a configured proxy.
+func (r *ClientIPResolver) Trusted(ip netip.Addr) bool {
+	if r == nil {
+		return false
+	}
+	ip = ip.Unmap()
+	for _, p := range r.trusted {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns the client IP for a request given its RemoteAddr ("host:port" or
+// bare IP) and all X-Forwarded-For header values in order.
+func (r *ClientIPResolver) Resolve(remoteAddr string, forwardedFor []string) string {
+	peer, ok := parseIP(remoteAddr)
+	if !ok {
+		return ""
+	}
+	if !r.Trusted(peer) {
+		return peer.String()
+	}
+
+	var hops []string
+	for _, v := range forwardedFor {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+	client := peer
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip, ok := parseIP(hops[i])
+		if !ok {
+			// Garbage in the chain: stop at the last address a trusted hop vouched for.
+			break
+		}
+		client = ip
+		if !r.Trusted(ip) {
+			break
+		}
+	}
+	return client.String()
+}
+
+func parseIP(s string) (netip.Addr, bool) {
+	s = strings.TrimSpace(s)
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	ip, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return ip.Unmap(), true
+}
+
+type clientIPKey struct{}
+
+// WithClientIP attaches the resolved client IP to ctx for audit and rate limiting.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	if ip == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIP returns the IP attached by WithClientIP, or "".
+func ClientIP(ctx context.Context) string {
+	s, _ := ctx.Value(clientIPKey{}).(string)
+	return s
+}
@@ -0,0 +1,32 @@
+package utils
+
+import "testing"
+
+func TestClientIPResolver_Resolve(t *testing.T) {
+	r, err := NewClientIPResolver([]string{"10.0.0.0/8", "192.168.1.10"})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	cases := []struct {
+		name   string
+		remote string
+		xff    []string
+		want   string
+	}{
+		{"untrusted peer ignores header", "203.0.113.9:5000", []string{"1.2.3.4"}, "203.0.113.9"},
+		{"single trusted proxy", "10.1.2.3:443", []string{"198.51.100.7"}, "198.51.100.7"},
+		{"spoofed leftmost entry skipped", "10.1.2.3:443", []string{"6.6.6.6, 198.51.100.7"}, "198.51.100.7"},
+		{"chained trusted proxies", "10.1.2.3:443", []string{"198.51.100.7, 192.168.1.10", "10.9.9.9"}, "198.51.100.7"},
+		{"no header falls back to peer", "10.1.2.3:443", nil, "10.1.2.3"},
+		{"garbage hop stops walk", "10.1.2.3:443", []string{"198.51.100.7, not-an-ip"}, "10.1.2.3"},
+		{"ipv6 peer", "[2001:db8::1]:443", nil, "2001:db8::1"},
+	}
+	for _, tc := range cases {
+		if got := r.Resolve(tc.remote, tc.xff); got != tc.want {
+			t.Fatalf("%s: got %q want %q", tc.name, got, tc.want)
+		}
+	}
+	if _, err := NewClientIPResolver([]string{"lb.internal"}); err == nil {
+		t.Fatalf("expected error for hostname")
+	}
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// ChainHash returns hex(sha256(prev || fields...)) for tamper-evident
+// append-only tables. Each field is length-prefixed so ("ab","c") and
+// ("a","bc") never collide.
+//
+// Callers pass the previous row's hash (empty for the first row) and the row
+// contents in a fixed order. Changing the field order is a breaking change for
+// every stored chain.
+func ChainHash(prev string, fields ...string) string {
+	h := sha256.New()
+	write := func(s string) {
+		h.Write([]byte(strconv.Itoa(len(s))))
+		h.Write([]byte{':'})
+		h.Write([]byte(s))
+	}
+	write(prev)
+	for _, f := range fields {
+		write(f)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
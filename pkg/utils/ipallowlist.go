@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// IPAllowlist matches client IPs against CIDRs (or bare IPs). An empty or nil
+// allowlist is disabled and allows every address.
+type IPAllowlist struct {
+	allowed []netip.Prefix
+}
+
+// NewIPAllowlist parses entries as CIDRs or bare IPs.
+func NewIPAllowlist(entries []string) (*IPAllowlist, error) {
+	allowed, err := parsePrefixes(entries)
+	if err != nil {
+		return nil, fmt.Errorf("ipallowlist: invalid entry %w", err)
+	}
+	return &IPAllowlist{allowed: allowed}, nil
+}
+
+// Enabled reports whether any entry is configured.
+func (a *IPAllowlist) Enabled() bool { return a != nil && len(a.allowed) > 0 }
+
+// Allows reports whether ip may pass. Unparseable addresses are rejected when
+// the allowlist is enabled.
+func (a *IPAllowlist) Allows(ip string) bool {
+	if !a.Enabled() {
+		return true
+	}
+	addr, ok := parseIP(ip)
+	if !ok {
+		return false
+	}
+	for _, p := range a.allowed {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package utils
+
+import "testing"
+
+func TestIPAllowlist(t *testing.T) {
+	a, err := NewIPAllowlist([]string{"10.0.0.0/8", "2001:db8::/32", "198.51.100.7"})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	cases := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.20.30.40", true},
+		{"198.51.100.7", true},
+		{"198.51.100.8", false},
+		{"2001:db8::1", true},
+		{"::ffff:10.0.0.1", true},
+		{"", false},
+		{"not-an-ip", false},
+	}
+	for _, tc := range cases {
+		if got := a.Allows(tc.ip); got != tc.want {
+			t.Fatalf("%q: got %v want %v", tc.ip, got, tc.want)
+		}
+	}
+
+	var disabled *IPAllowlist
+	if disabled.Enabled() || !disabled.Allows("203.0.113.9") {
+		t.Fatalf("nil allowlist must allow everything")
+	}
+	if empty, _ := NewIPAllowlist(nil); empty.Enabled() || !empty.Allows("") {
+		t.Fatalf("empty allowlist must be disabled")
+	}
+	if _, err := NewIPAllowlist([]string{"office"}); err == nil {
+		t.Fatalf("expected error for hostname")
+	}
+}
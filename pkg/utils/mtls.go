@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"os"
+
+	"golang.org/x/net/http2"
+)
+
+// ConfigureMTLSServer attaches TLS that requires a client certificate chaining
+// to the PEM bundle in clientCAFile. The server pair comes from cfg.CertFile and
+// cfg.KeyFile (ACME is not supported here); reload it with the returned reloader.
+// Serve with srv.ListenAndServeTLS("", "").
+func ConfigureMTLSServer(srv *http.Server, cfg TLSServerConfig, clientCAFile string) (*CertReloader, error) {
+	tc, err := BuildTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	pem, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("tls: no certificates in client CA file")
+	}
+	tc.ClientCAs = pool
+	tc.ClientAuth = tls.RequireAndVerifyClientCert
+
+	reloader, err := NewCertReloader(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	tc.GetCertificate = reloader.GetCertificate
+	srv.TLSConfig = tc
+
+	if cfg.HTTP2Disabled {
+		srv.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+		return reloader, nil
+	}
+	if err := http2.ConfigureServer(srv, &http2.Server{MaxConcurrentStreams: cfg.HTTP2MaxConcurrentStreams}); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// SPIFFEID returns the single spiffe:// URI SAN of cert. Certificates with none
+// or several are rejected, as the SPIFFE X.509-SVID format requires.
+func SPIFFEID(cert *x509.Certificate) (string, bool) {
+	var id string
+	for _, u := range cert.URIs {
+		if u.Scheme != "spiffe" {
+			continue
+		}
+		if id != "" || u.Host == "" {
+			return "", false
+		}
+		id = u.String()
+	}
+	return id, id != ""
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSelfSigned writes a self-signed CA cert/key pair and returns the paths.
+func writeSelfSigned(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestConfigureMTLSServer_RequiresClientCert(t *testing.T) {
+	certFile, keyFile := writeSelfSigned(t)
+	srv := &http.Server{}
+	reloader, err := ConfigureMTLSServer(srv, TLSServerConfig{CertFile: certFile, KeyFile: keyFile}, certFile)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if reloader == nil || srv.TLSConfig.ClientAuth != tls.RequireAndVerifyClientCert || srv.TLSConfig.ClientCAs == nil {
+		t.Fatalf("expected verified client certs, got %+v", srv.TLSConfig)
+	}
+	if _, err := ConfigureMTLSServer(&http.Server{}, TLSServerConfig{CertFile: certFile, KeyFile: keyFile}, keyFile); err == nil {
+		t.Fatalf("expected error for a CA file without certificates")
+	}
+}
+
+func TestSPIFFEID(t *testing.T) {
+	parse := func(s string) *url.URL {
+		u, err := url.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u
+	}
+	cases := []struct {
+		name string
+		uris []*url.URL
+		want string
+	}{
+		{"single id", []*url.URL{parse("spiffe://telecom.internal/worker")}, "spiffe://telecom.internal/worker"},
+		{"other uris ignored", []*url.URL{parse("https://example.com"), parse("spiffe://telecom.internal/media")}, "spiffe://telecom.internal/media"},
+		{"none", []*url.URL{parse("https://example.com")}, ""},
+		{"several rejected", []*url.URL{parse("spiffe://a/x"), parse("spiffe://a/y")}, ""},
+		{"missing trust domain", []*url.URL{parse("spiffe:///worker")}, ""},
+	}
+	for _, tc := range cases {
+		got, ok := SPIFFEID(&x509.Certificate{URIs: tc.uris})
+		if got != tc.want || ok != (tc.want != "") {
+			t.Fatalf("%s: got %q %v", tc.name, got, ok)
+		}
+	}
+}
@@ -77,7 +77,16 @@ type TxFunc func(ctx context.Context, tx *sql.Tx) error
 // - If fn returns error: tx is rolled back and the error is returned.
 // - If fn panics: tx is rolled back and the panic is re-thrown.
 // - If commit fails: commit error is returned.
+// - If ctx is tagged by WithQuery: its statement timeout applies and slow runs are logged.
+// - For retries on serialization failures and deadlocks, use WithTxRetry.
+// - If ctx comes from an outer WithTx on db: fn runs under a savepoint (see savepoint.go).
 func WithTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn TxFunc) (err error) {
+	if st, ok := inTx(ctx, db); ok {
+		return withSavepoint(ctx, st, fn)
+	}
+	tag, tagged := queryTagFrom(ctx)
+	start := time.Now()
+
 	tx, err := db.BeginTx(ctx, opts)
 	if err != nil {
 		return err
@@ -90,11 +99,19 @@ func WithTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn TxFunc) (er
 		}
 		if err != nil {
 			_ = tx.Rollback()
-			return
+		} else {
+			err = tx.Commit()
+		}
+		if tagged {
+			logSlowQuery(ctx, tag, time.Since(start), err)
 		}
-		err = tx.Commit()
 	}()
 
-	err = fn(ctx, tx)
+	if tagged && tag.budget.StatementTimeout > 0 {
+		if _, err = tx.ExecContext(ctx, statementTimeoutSQL(tag.budget.StatementTimeout)); err != nil {
+			return err
+		}
+	}
+	err = fn(context.WithValue(ctx, txStateKey{}, txState{db: db, tx: tx}), tx)
 	return err
 }
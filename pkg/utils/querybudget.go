@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"telecom-platform/pkg/logger"
+)
+
+// Query budgets and slow-query logging.
+//
+// Repositories tag their context with WithQuery (module, operation, budget).
+// WithTx then applies the budget's statement timeout server-side with
+// SET LOCAL statement_timeout, and TrackQuery bounds standalone queries with a
+// context deadline. Either way, work slower than the budget's SlowThreshold is
+// logged as "slow query" with the module and operation, so regressions show up in
+// production logs. Untagged work keeps the database defaults and is not logged.
+
+// QueryBudget bounds the database work of one operation.
+type QueryBudget struct {
+	StatementTimeout time.Duration
+	SlowThreshold    time.Duration
+}
+
+var (
+	// MoneyBudget is for money paths. They hold wallet row locks, so a stuck
+	// statement must fail fast rather than block every other posting on the wallet.
+	MoneyBudget = QueryBudget{StatementTimeout: 3 * time.Second, SlowThreshold: 250 * time.Millisecond}
+	// ReportBudget is for read-only scans: ledger pages, chain verification and
+	// reports.
+	ReportBudget = QueryBudget{StatementTimeout: 60 * time.Second, SlowThreshold: 5 * time.Second}
+	// BatchBudget is for nightly jobs that scan everything in one statement
+	// (e.g. wallet reconciliation).
+	BatchBudget = QueryBudget{StatementTimeout: 30 * time.Minute, SlowThreshold: 5 * time.Minute}
+)
+
+type queryTag struct {
+	module string
+	op     string
+	budget QueryBudget
+}
+
+type queryTagKey struct{}
+
+// WithQuery tags ctx with the calling module and operation (e.g. "wallet",
+// "Debit") and the budget for its database work.
+func WithQuery(ctx context.Context, module, op string, b QueryBudget) context.Context {
+	return context.WithValue(ctx, queryTagKey{}, queryTag{module: module, op: op, budget: b})
+}
+
+func queryTagFrom(ctx context.Context) (queryTag, bool) {
+	t, ok := ctx.Value(queryTagKey{}).(queryTag)
+	return t, ok
+}
+
+// TrackQuery bounds standalone (non-transactional) work by the budget in ctx.
+// Call done with the outcome once the rows are consumed; it releases the deadline
+// and logs the work if it was slow.
+func TrackQuery(ctx context.Context) (context.Context, func(err error)) {
+	t, ok := queryTagFrom(ctx)
+	if !ok {
+		return ctx, func(error) {}
+	}
+	start := time.Now()
+	cancel := context.CancelFunc(func() {})
+	if t.budget.StatementTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.budget.StatementTimeout)
+	}
+	return ctx, func(err error) {
+		cancel()
+		logSlowQuery(ctx, t, time.Since(start), err)
+	}
+}
+
+// statementTimeoutSQL returns the SET LOCAL for d, rounded up to a millisecond.
+// SET does not take bind parameters; the value is always an integer.
+func statementTimeoutSQL(d time.Duration) string {
+	ms := (d + time.Millisecond - 1) / time.Millisecond
+	return fmt.Sprintf("SET LOCAL statement_timeout = %d", ms)
+}
+
+func logSlowQuery(ctx context.Context, t queryTag, d time.Duration, err error) {
+	if t.budget.SlowThreshold <= 0 || d < t.budget.SlowThreshold {
+		return
+	}
+	attrs := []any{
+		"module", t.module,
+		"op", t.op,
+		"duration_ms", d.Milliseconds(),
+		"threshold_ms", t.budget.SlowThreshold.Milliseconds(),
+	}
+	if err != nil {
+		attrs = append(attrs, "err", err)
+	}
+	logger.From(ctx).Warn("slow query", attrs...)
+}
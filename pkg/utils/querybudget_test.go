@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"telecom-platform/pkg/logger"
+)
+
+func TestStatementTimeoutSQL(t *testing.T) {
+	cases := map[time.Duration]string{
+		3 * time.Second:                    "SET LOCAL statement_timeout = 3000",
+		1500 * time.Microsecond:            "SET LOCAL statement_timeout = 2",
+		time.Minute + 250*time.Millisecond: "SET LOCAL statement_timeout = 60250",
+	}
+	for d, want := range cases {
+		if got := statementTimeoutSQL(d); got != want {
+			t.Fatalf("%s: got %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestTrackQuery_UntaggedIsPassThrough(t *testing.T) {
+	ctx, done := TrackQuery(context.Background())
+	if _, ok := ctx.Deadline(); ok {
+		t.Fatalf("expected no deadline without a query tag")
+	}
+	done(nil)
+}
+
+func TestTrackQuery_AppliesTimeoutAndLogsSlowWork(t *testing.T) {
+	var buf bytes.Buffer
+	base := logger.With(context.Background(), slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	ctx, done := TrackQuery(WithQuery(base, "wallet", "ListLedger", QueryBudget{StatementTimeout: time.Minute}))
+	if dl, ok := ctx.Deadline(); !ok || time.Until(dl) > time.Minute {
+		t.Fatalf("expected the statement timeout as deadline, got %v %v", dl, ok)
+	}
+	done(nil)
+	if buf.Len() != 0 {
+		t.Fatalf("expected no log without a slow threshold, got %s", buf.String())
+	}
+
+	_, done = TrackQuery(WithQuery(base, "wallet", "VerifyLedgerChain", QueryBudget{SlowThreshold: time.Nanosecond}))
+	time.Sleep(time.Millisecond)
+	done(errors.New("canceling statement due to statement timeout"))
+	out := buf.String()
+	for _, want := range []string{`"msg":"slow query"`, `"module":"wallet"`, `"op":"VerifyLedgerChain"`, `"err":"canceling statement`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %s in %s", want, out)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Nested transactions.
+//
+// WithTx called with the ctx an outer WithTx passed to its fn, on the same
+// *sql.DB, does not begin a new transaction: it runs fn under a SAVEPOINT of the
+// outer one. If fn fails, only fn's work is rolled back (ROLLBACK TO SAVEPOINT)
+// and its error is returned, so the caller can take a recoverable branch and
+// still commit the rest; returning the error aborts the whole transaction as
+// usual. WithSavepoint does the same for code that is handed the *sql.Tx.
+//
+// A nested call ignores its TxOptions and query budget (the outer transaction's
+// apply) and is not retried by WithTxRetry: serialization failures and deadlocks
+// abort the whole transaction, so only the outermost call can retry.
+
+type txState struct {
+	db    *sql.DB
+	tx    *sql.Tx
+	depth int
+}
+
+type txStateKey struct{}
+
+func txFrom(ctx context.Context) (txState, bool) {
+	st, ok := ctx.Value(txStateKey{}).(txState)
+	return st, ok
+}
+
+// inTx reports whether ctx belongs to a WithTx transaction on db.
+func inTx(ctx context.Context, db *sql.DB) (txState, bool) {
+	st, ok := txFrom(ctx)
+	return st, ok && st.db == db
+}
+
+// WithSavepoint runs fn under a savepoint of tx: fn's work is released into tx
+// on success and rolled back (leaving tx usable) when fn returns an error, which
+// is then returned. A panic rolls back to the savepoint and is re-thrown.
+func WithSavepoint(ctx context.Context, tx *sql.Tx, fn TxFunc) error {
+	st, ok := txFrom(ctx)
+	if !ok || st.tx != tx {
+		st = txState{tx: tx}
+	}
+	return withSavepoint(ctx, st, fn)
+}
+
+func withSavepoint(ctx context.Context, st txState, fn TxFunc) (err error) {
+	st.depth++
+	// Names only need to be unique among open savepoints; Postgres resolves a
+	// reused name to the most recent one.
+	name := fmt.Sprintf("sp_%d", st.depth)
+	if _, err := st.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = st.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+			panic(p)
+		}
+		if err != nil {
+			if _, rbErr := st.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+				err = errors.Join(err, rbErr)
+			}
+			return
+		}
+		_, err = st.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	}()
+
+	err = fn(context.WithValue(ctx, txStateKey{}, st), st.tx)
+	return err
+}
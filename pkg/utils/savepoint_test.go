@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recorder is a database/sql driver that logs statements and transaction
+// boundaries; statements listed in fail return that error.
+type recorder struct {
+	mu   sync.Mutex
+	log  []string
+	fail map[string]error
+}
+
+func (r *recorder) add(s string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.log = append(r.log, s)
+	return r.fail[s]
+}
+
+func (r *recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.log, "; ")
+}
+
+func (r *recorder) Connect(context.Context) (driver.Conn, error) { return recConn{r}, nil }
+func (r *recorder) Driver() driver.Driver                        { return recDriver{r} }
+
+type recDriver struct{ r *recorder }
+
+func (d recDriver) Open(string) (driver.Conn, error) { return recConn{d.r}, nil }
+
+type recConn struct{ r *recorder }
+
+func (c recConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c recConn) Close() error                        { return nil }
+func (c recConn) Begin() (driver.Tx, error)           { return recTx{c.r}, c.r.add("BEGIN") }
+
+func (c recConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(0), c.r.add(query)
+}
+
+type recTx struct{ r *recorder }
+
+func (t recTx) Commit() error   { return t.r.add("COMMIT") }
+func (t recTx) Rollback() error { return t.r.add("ROLLBACK") }
+
+func newRecorder() (*recorder, *sql.DB) {
+	r := &recorder{fail: map[string]error{}}
+	return r, sql.OpenDB(r)
+}
+
+func exec(q string) TxFunc {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, q)
+		return err
+	}
+}
+
+func TestWithTx_NestedFailureRollsBackToSavepoint(t *testing.T) {
+	r, db := newRecorder()
+	defer db.Close()
+	provider := errors.New("provider unavailable")
+	r.fail["CALL provider"] = provider
+
+	err := WithTx(context.Background(), db, &sql.TxOptions{}, func(ctx context.Context, tx *sql.Tx) error {
+		if err := exec("INSERT charge")(ctx, tx); err != nil {
+			return err
+		}
+		if err := WithTx(ctx, db, &sql.TxOptions{}, exec("CALL provider")); !errors.Is(err, provider) {
+			t.Fatalf("expected the inner error, got %v", err)
+		}
+		return exec("INSERT fallback")(ctx, tx)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "BEGIN; INSERT charge; SAVEPOINT sp_1; CALL provider; ROLLBACK TO SAVEPOINT sp_1; INSERT fallback; COMMIT"
+	if got := r.String(); got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestWithTx_NestedSuccessReleasesSavepoints(t *testing.T) {
+	r, db := newRecorder()
+	defer db.Close()
+
+	err := WithTx(context.Background(), db, &sql.TxOptions{}, func(ctx context.Context, tx *sql.Tx) error {
+		return WithSavepoint(ctx, tx, func(ctx context.Context, tx *sql.Tx) error {
+			return WithTxRetry(ctx, db, &sql.TxOptions{}, DefaultTxRetry, exec("INSERT inventory"))
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "BEGIN; SAVEPOINT sp_1; SAVEPOINT sp_2; INSERT inventory; RELEASE SAVEPOINT sp_2; RELEASE SAVEPOINT sp_1; COMMIT"
+	if got := r.String(); got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestWithTx_NestedErrorReturnedAbortsAll(t *testing.T) {
+	r, db := newRecorder()
+	defer db.Close()
+	r.fail["UPDATE wallet"] = sqlStateErr("40P01")
+
+	err := WithTx(context.Background(), db, &sql.TxOptions{}, func(ctx context.Context, tx *sql.Tx) error {
+		// Nested retries are left to the outermost transaction.
+		return WithTxRetry(ctx, db, &sql.TxOptions{}, DefaultTxRetry, exec("UPDATE wallet"))
+	})
+	if !IsRetryableTxError(err) {
+		t.Fatalf("expected the deadlock, got %v", err)
+	}
+	want := "BEGIN; SAVEPOINT sp_1; UPDATE wallet; ROLLBACK TO SAVEPOINT sp_1; ROLLBACK"
+	if got := r.String(); got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestWithTx_OtherDBStartsOwnTransaction(t *testing.T) {
+	r1, db1 := newRecorder()
+	defer db1.Close()
+	r2, db2 := newRecorder()
+	defer db2.Close()
+
+	err := WithTx(context.Background(), db1, &sql.TxOptions{}, func(ctx context.Context, tx *sql.Tx) error {
+		return WithTx(ctx, db2, &sql.TxOptions{}, exec("INSERT elsewhere"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r1.String(); got != "BEGIN; COMMIT" {
+		t.Fatalf("unexpected outer log %s", got)
+	}
+	if got := r2.String(); got != "BEGIN; INSERT elsewhere; COMMIT" {
+		t.Fatalf("unexpected inner log %s", got)
+	}
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"sync"
+
+	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/net/http2"
+)
+
+// TLSServerConfig controls native TLS on the API server. It is only used when the
+// service terminates TLS itself (no fronting proxy/load balancer).
+type TLSServerConfig struct {
+	// Files mode: PEM cert/key pair, re-read by CertReloader.Reload (SIGHUP).
+	CertFile string
+	KeyFile  string
+
+	// ACME mode: certificates are obtained and renewed automatically (TLS-ALPN-01).
+	ACMEDomains  []string
+	ACMECacheDir string
+	ACMEEmail    string
+
+	MinVersion   string // "1.2" (default) or "1.3"
+	CipherPolicy string // "modern" (default) or "intermediate"
+
+	HTTP2Disabled             bool
+	HTTP2MaxConcurrentStreams uint32
+}
+
+// CertReloader serves a cert/key pair that can be swapped at runtime without
+// dropping the listener.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// NewCertReloader loads the pair once; a bad pair at startup is fatal for the caller.
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload re-reads the pair. On error the previous certificate keeps serving.
+func (r *CertReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	return nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// modernCiphers are AEAD-only ECDHE suites; intermediateCiphers adds ECDHE CBC
+// suites for older TLS 1.2 clients without GCM. Both keep forward secrecy: static-RSA
+// key exchange (TLS_RSA_*) is never offered.
+// TLS 1.3 suites are not configurable in crypto/tls and are always enabled.
+var (
+	modernCiphers = []uint16{
+		tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+		tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+	}
+	intermediateCiphers = append(append([]uint16{}, modernCiphers...),
+		tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+		tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+		tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+	)
+)
+
+// BuildTLSConfig returns the base tls.Config for the configured version and cipher policy.
+// The certificate source (reloader or ACME manager) is attached by ConfigureTLSServer.
+func BuildTLSConfig(cfg TLSServerConfig) (*tls.Config, error) {
+	out := &tls.Config{
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
+	}
+	switch cfg.MinVersion {
+	case "", "1.2":
+		out.MinVersion = tls.VersionTLS12
+	case "1.3":
+		out.MinVersion = tls.VersionTLS13
+	default:
+		return nil, errors.New("tls: unsupported min version")
+	}
+	switch cfg.CipherPolicy {
+	case "", "modern":
+		out.CipherSuites = modernCiphers
+	case "intermediate":
+		out.CipherSuites = intermediateCiphers
+	default:
+		return nil, errors.New("tls: unsupported cipher policy")
+	}
+	return out, nil
+}
+
+// ConfigureTLSServer attaches TLS (and HTTP/2 unless disabled) to srv.
+// The returned reloader is nil in ACME mode, where autocert renews on its own.
+// Serve with srv.ListenAndServeTLS("", "").
+func ConfigureTLSServer(srv *http.Server, cfg TLSServerConfig) (*CertReloader, error) {
+	tc, err := BuildTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var reloader *CertReloader
+	if len(cfg.ACMEDomains) > 0 {
+		m := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.ACMEDomains...),
+			Cache:      autocert.DirCache(cfg.ACMECacheDir),
+			Email:      cfg.ACMEEmail,
+		}
+		tc.GetCertificate = m.GetCertificate
+		tc.NextProtos = append(tc.NextProtos, "acme-tls/1")
+	} else {
+		reloader, err = NewCertReloader(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tc.GetCertificate = reloader.GetCertificate
+	}
+	srv.TLSConfig = tc
+
+	if cfg.HTTP2Disabled {
+		// A non-nil empty map turns off the automatic h2 upgrade in net/http.
+		srv.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+		return reloader, nil
+	}
+	if err := http2.ConfigureServer(srv, &http2.Server{MaxConcurrentStreams: cfg.HTTP2MaxConcurrentStreams}); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBuildTLSConfig_Policies(t *testing.T) {
+	tc, err := BuildTLSConfig(TLSServerConfig{MinVersion: "1.3"})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if tc.MinVersion != tls.VersionTLS13 || len(tc.CipherSuites) != len(modernCiphers) {
+		t.Fatalf("unexpected config: min=%x ciphers=%d", tc.MinVersion, len(tc.CipherSuites))
+	}
+	tc, err = BuildTLSConfig(TLSServerConfig{CipherPolicy: "intermediate"})
+	if err != nil || len(tc.CipherSuites) <= len(modernCiphers) {
+		t.Fatalf("expected intermediate policy to add suites, err=%v", err)
+	}
+	for _, id := range tc.CipherSuites {
+		if name := tls.CipherSuiteName(id); !strings.HasPrefix(name, "TLS_ECDHE_") {
+			t.Fatalf("expected only forward-secret suites, got %s", name)
+		}
+	}
+	if _, err := BuildTLSConfig(TLSServerConfig{MinVersion: "1.0"}); err == nil {
+		t.Fatalf("expected error for TLS 1.0")
+	}
+}
+
+func TestConfigureTLSServer_ACMEEnablesHTTP2(t *testing.T) {
+	srv := &http.Server{}
+	reloader, err := ConfigureTLSServer(srv, TLSServerConfig{ACMEDomains: []string{"api.example.com"}, ACMECacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if reloader != nil {
+		t.Fatalf("acme mode should not return a file reloader")
+	}
+	if srv.TLSConfig == nil || srv.TLSConfig.GetCertificate == nil {
+		t.Fatalf("expected certificate source")
+	}
+	var h2 bool
+	for _, p := range srv.TLSConfig.NextProtos {
+		if p == "h2" {
+			h2 = true
+		}
+	}
+	if !h2 {
+		t.Fatalf("expected h2 in NextProtos, got %v", srv.TLSConfig.NextProtos)
+	}
+}
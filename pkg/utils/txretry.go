@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"telecom-platform/pkg/logger"
+)
+
+// Transaction retries.
+//
+// Postgres aborts one side of a deadlock (40P01) and, under SERIALIZABLE or
+// REPEATABLE READ, transactions that conflict with a concurrent commit (40001).
+// Both are safe to retry from the start: the aborted transaction left nothing
+// behind. WithTxRetry does that with exponential backoff and full jitter, so
+// colliding callers do not retry in lockstep.
+//
+// fn runs once per attempt and must not have side effects outside tx (calls to
+// other services, channel sends); results it assigns are overwritten by the
+// attempt that commits.
+
+// TxRetry bounds the retries of WithTxRetry.
+type TxRetry struct {
+	// MaxAttempts includes the first try; values below 1 mean 1.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultTxRetry suits short money transactions that contend on wallet rows.
+var DefaultTxRetry = TxRetry{MaxAttempts: 4, BaseDelay: 10 * time.Millisecond, MaxDelay: 200 * time.Millisecond}
+
+// Postgres SQLSTATEs that make a transaction safe to retry.
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// IsRetryableTxError reports whether err is a serialization failure or deadlock.
+// It matches any driver error exposing SQLState() (pgx's *pgconn.PgError does).
+func IsRetryableTxError(err error) bool {
+	var pgErr interface{ SQLState() string }
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	switch pgErr.SQLState() {
+	case sqlStateSerializationFailure, sqlStateDeadlockDetected:
+		return true
+	}
+	return false
+}
+
+// WithTxRetry runs fn in a transaction like WithTx, retrying the whole
+// transaction on serialization failures and deadlocks up to r.MaxAttempts.
+// Nested in another WithTx on db it runs once, under a savepoint.
+func WithTxRetry(ctx context.Context, db *sql.DB, opts *sql.TxOptions, r TxRetry, fn TxFunc) error {
+	if _, ok := inTx(ctx, db); ok {
+		return WithTx(ctx, db, opts, fn)
+	}
+	return retryTx(ctx, r, func() error { return WithTx(ctx, db, opts, fn) })
+}
+
+func retryTx(ctx context.Context, r TxRetry, run func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := run()
+		if err == nil || attempt >= r.MaxAttempts || !IsRetryableTxError(err) {
+			return err
+		}
+		delay := r.backoff(attempt)
+		attrs := []any{"attempt", attempt, "delay_ms", delay.Milliseconds(), "err", err}
+		if t, ok := queryTagFrom(ctx); ok {
+			attrs = append(attrs, "module", t.module, "op", t.op)
+		}
+		logger.From(ctx).Warn("retrying transaction", attrs...)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns a random delay in [0, min(MaxDelay, BaseDelay*2^(attempt-1))].
+func (r TxRetry) backoff(attempt int) time.Duration {
+	ceiling := r.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || (r.MaxDelay > 0 && ceiling > r.MaxDelay) {
+		ceiling = r.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type sqlStateErr string
+
+func (e sqlStateErr) Error() string    { return "pg error " + string(e) }
+func (e sqlStateErr) SQLState() string { return string(e) }
+
+func TestIsRetryableTxError(t *testing.T) {
+	cases := map[error]bool{
+		sqlStateErr("40001"):                          true,
+		sqlStateErr("40P01"):                          true,
+		fmt.Errorf("debit: %w", sqlStateErr("40P01")): true,
+		sqlStateErr("23505"):                          false,
+		errors.New("40001"):                           false,
+		nil:                                           false,
+	}
+	for err, want := range cases {
+		if got := IsRetryableTxError(err); got != want {
+			t.Fatalf("%v: got %v, want %v", err, got, want)
+		}
+	}
+}
+
+func TestRetryTx(t *testing.T) {
+	r := TxRetry{MaxAttempts: 3, BaseDelay: time.Microsecond, MaxDelay: time.Millisecond}
+	ctx := context.Background()
+
+	calls := 0
+	err := retryTx(ctx, r, func() error { calls++; return sqlStateErr("40P01") })
+	if calls != 3 || !IsRetryableTxError(err) {
+		t.Fatalf("expected 3 attempts ending in the deadlock, got %d, %v", calls, err)
+	}
+
+	calls = 0
+	err = retryTx(ctx, r, func() error {
+		calls++
+		if calls < 2 {
+			return sqlStateErr("40001")
+		}
+		return nil
+	})
+	if calls != 2 || err != nil {
+		t.Fatalf("expected success on the second attempt, got %d, %v", calls, err)
+	}
+
+	calls = 0
+	boom := errors.New("insufficient funds")
+	if err := retryTx(ctx, r, func() error { calls++; return boom }); calls != 1 || err != boom {
+		t.Fatalf("expected no retry for other errors, got %d, %v", calls, err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	calls = 0
+	slow := TxRetry{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	if err := retryTx(canceled, slow, func() error { calls++; return sqlStateErr("40001") }); calls != 1 || !IsRetryableTxError(err) {
+		t.Fatalf("expected the wait to stop on cancel, got %d, %v", calls, err)
+	}
+}
+
+func TestTxRetryBackoff(t *testing.T) {
+	r := TxRetry{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	for attempt := 1; attempt <= 70; attempt++ {
+		d := r.backoff(attempt)
+		if d < 0 || d > r.MaxDelay {
+			t.Fatalf("attempt %d: delay %s outside [0, %s]", attempt, d, r.MaxDelay)
+		}
+	}
+	if d := (TxRetry{}).backoff(1); d != 0 {
+		t.Fatalf("expected no delay without a base, got %s", d)
+	}
+}